@@ -7,6 +7,10 @@
  *    - AUTH_SECRET: JWT authentication secret
  *    - RATE_LIMIT_WINDOW: Rate limiting window in minutes
  *    - RATE_LIMIT_MAX_REQUESTS: Maximum requests per window
+ *    - OAUTH_ISSUER, OAUTH_AUDIENCE: Identity provider accepting OAuth2 access tokens (optional)
+ *    - OAUTH_JWKS_URI: Signing key set, when the provider has no OpenID discovery metadata (optional)
+ *    - BOOKING_SERVICE_URL, TRACKING_SERVICE_URL: Services the GraphQL endpoint reads from
+ *    - TRACKING_SERVICE_API_KEY: Key the GraphQL endpoint reads walker positions with
  * 2. Ensure proper security measures for storing sensitive configuration
  * 3. Review and adjust rate limiting settings based on load testing results
  */
@@ -41,6 +45,15 @@ class ConfigSchema {
   @IsNumber()
   rateLimitMaxRequests: number;
 
+  @IsString()
+  bookingServiceUrl: string;
+
+  @IsString()
+  trackingServiceUrl: string;
+
+  @IsString()
+  trackingServiceApiKey: string;
+
   constructor() {
     this.port = parseInt(process.env.PORT || '3000', 10);
     this.nodeEnv = process.env.NODE_ENV || 'development';
@@ -48,6 +61,9 @@ class ConfigSchema {
     this.logLevel = process.env.LOG_LEVEL || 'info';
     this.rateLimitWindow = parseInt(process.env.RATE_LIMIT_WINDOW || '15', 10);
     this.rateLimitMaxRequests = parseInt(process.env.RATE_LIMIT_MAX_REQUESTS || '100', 10);
+    this.bookingServiceUrl = process.env.BOOKING_SERVICE_URL || 'http://booking-service:8081';
+    this.trackingServiceUrl = process.env.TRACKING_SERVICE_URL || 'http://tracking-service:8084';
+    this.trackingServiceApiKey = process.env.TRACKING_SERVICE_API_KEY || '';
   }
 }
 
@@ -0,0 +1,183 @@
+/**
+ * Human Tasks:
+ * 1. Set BOOKING_SERVICE_URL and TRACKING_SERVICE_URL to the services' base URLs
+ * 2. Set TRACKING_SERVICE_API_KEY to the tracking-service's key for reading walker positions
+ * 3. Configure network policies allowing the API Gateway to reach both services
+ */
+
+import logger from '../../../shared/utils/logger';
+
+/**
+ * @description Timeout for each downstream request; a GraphQL query waits on its slowest field
+ */
+const DOWNSTREAM_TIMEOUT_MS = 5000;
+
+/**
+ * @description A booking as the booking-service publishes it
+ */
+export interface Booking {
+  id: string;
+  owner_id: string;
+  walker_id: string;
+  dog_id: string;
+  scheduled_at: string;
+  status: string;
+  amount: number;
+  duration_minutes: number;
+  region?: string;
+}
+
+/**
+ * @description A change recorded to a booking by the booking-service
+ */
+export interface BookingEvent {
+  booking_id: string;
+  version: number;
+  type: string;
+  changes: unknown;
+  recorded_at: string;
+}
+
+/**
+ * @description Where the tracking-service last saw a walker during a walk
+ */
+export interface WalkerPosition {
+  walker_id: string;
+  session_id: string;
+  latitude: number;
+  longitude: number;
+  updated_at: string;
+}
+
+/**
+ * @description What the tracking-service tracked of a booking's walks
+ */
+export interface WalkEvidence {
+  booking_id: string;
+  sessions: number;
+  active: boolean;
+  duration_seconds: number;
+  distance_meters: number;
+  photos: number;
+}
+
+/**
+ * @description The booking-service's response wrapper
+ */
+interface Envelope<T> {
+  success: boolean;
+  data: T;
+}
+
+/**
+ * @description A downstream service answered with an error status
+ */
+export class ServiceError extends Error {
+  constructor(readonly service: string, readonly status: number) {
+    super(`${service} responded with status ${status}`);
+    this.name = 'ServiceError';
+  }
+}
+
+/**
+ * @description Calls a backend service with the given credentials: usually the caller's, so the
+ * service applies its own access checks, or the gateway's own API key for service-only routes
+ * Addresses requirement: Technical Specification/8.3 API Design/8.3.3 Integration Patterns
+ */
+export class ServiceClient {
+  constructor(
+    private readonly service: string,
+    private readonly baseUrl: string,
+    private readonly credentials: Record<string, string>
+  ) {}
+
+  /**
+   * @description Fetches path as JSON, resolving to null when the service reports it not found
+   */
+  async get<T>(path: string): Promise<T | null> {
+    const controller = new AbortController();
+    const timer = setTimeout(() => controller.abort(), DOWNSTREAM_TIMEOUT_MS);
+    try {
+      const response = await fetch(this.baseUrl.replace(/\/+$/, '') + path, {
+        headers: {
+          Accept: 'application/json',
+          ...this.credentials
+        },
+        signal: controller.signal
+      });
+      if (response.status === 404) {
+        return null;
+      }
+      if (!response.ok) {
+        logger.logError('Downstream request failed', {
+          service: this.service,
+          path,
+          status: response.status
+        });
+        throw new ServiceError(this.service, response.status);
+      }
+      return (await response.json()) as T;
+    } finally {
+      clearTimeout(timer);
+    }
+  }
+}
+
+/**
+ * @description Reads bookings and their history from the booking-service
+ */
+export class BookingServiceClient {
+  private readonly client: ServiceClient;
+
+  constructor(baseUrl: string, authorization: string) {
+    this.client = new ServiceClient('booking-service', baseUrl, { Authorization: authorization });
+  }
+
+  async getBooking(id: string): Promise<Booking | null> {
+    const envelope = await this.client.get<Envelope<Booking>>(`/api/v1/bookings/${encodeURIComponent(id)}`);
+    return envelope ? envelope.data : null;
+  }
+
+  /**
+   * @description Fetches the bookings with the given IDs in one request, in no particular order;
+   * any not found or not visible to the caller are left out
+   */
+  async getBookings(ids: readonly string[]): Promise<Booking[]> {
+    const query = new URLSearchParams({ ids: ids.join(','), limit: String(ids.length) });
+    const envelope = await this.client.get<Envelope<{ bookings: Booking[] }>>(`/api/v1/bookings?${query}`);
+    return envelope ? envelope.data.bookings : [];
+  }
+
+  /**
+   * @description Lists every change recorded to a booking, oldest first; only admins may read it
+   */
+  async getBookingHistory(id: string): Promise<BookingEvent[]> {
+    const envelope = await this.client.get<Envelope<BookingEvent[]>>(
+      `/api/v1/admin/bookings/${encodeURIComponent(id)}/history`
+    );
+    return envelope ? envelope.data : [];
+  }
+}
+
+/**
+ * @description Reads walker positions and walk evidence from the tracking-service. Positions are
+ * only served to other services, so they are read with the gateway's API key and callers must be
+ * checked before asking for one.
+ */
+export class TrackingServiceClient {
+  private readonly client: ServiceClient;
+  private readonly positions: ServiceClient;
+
+  constructor(baseUrl: string, authorization: string, apiKey: string) {
+    this.client = new ServiceClient('tracking-service', baseUrl, { Authorization: authorization });
+    this.positions = new ServiceClient('tracking-service', baseUrl, { 'X-API-Key': apiKey });
+  }
+
+  async getWalkerPosition(walkerId: string): Promise<WalkerPosition | null> {
+    return this.positions.get<WalkerPosition>(`/api/v1/walkers/${encodeURIComponent(walkerId)}/position`);
+  }
+
+  async getWalkEvidence(bookingId: string): Promise<WalkEvidence | null> {
+    return this.client.get<WalkEvidence>(`/api/v1/bookings/${encodeURIComponent(bookingId)}/walk-evidence`);
+  }
+}
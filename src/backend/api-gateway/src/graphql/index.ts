@@ -0,0 +1,79 @@
+// express v4.18.2
+import { NextFunction, Request, Response, Router } from 'express';
+// graphql v16.8.1
+import { graphql } from 'graphql';
+import { createHttpError } from '../../../shared/utils/error';
+import logger from '../../../shared/utils/logger';
+import { authenticateRequest, requireScopes } from '../middleware/auth';
+import { BookingServiceClient, TrackingServiceClient } from './clients';
+import { createLoaders } from './loaders';
+import { schema } from './schema';
+
+/**
+ * @description Where the GraphQL resolvers find the backend services
+ */
+export interface GraphQLOptions {
+  bookingServiceUrl: string;
+  trackingServiceUrl: string;
+
+  /** API key the tracking-service serves walker positions to other services with */
+  trackingServiceApiKey: string;
+}
+
+/**
+ * @description Registers POST /graphql, which answers a query across the booking and tracking
+ * services in one request. Downstream calls carry the caller's credentials, so each service
+ * still decides what the caller may read; walker positions, which only services may read, are
+ * checked against the caller's bookings by the resolvers.
+ * Addresses requirement: Technical Specification/8.3 API Design/8.3.1 API Architecture
+ *
+ * @param app - Express Router instance
+ * @param options - Base URLs of the backend services and the tracking-service API key
+ */
+export const registerGraphQLRoute = (app: Router, options: GraphQLOptions): void => {
+  app.post(
+    '/graphql',
+    authenticateRequest,
+    requireScopes(['bookings:read']),
+    async (req: Request, res: Response, next: NextFunction) => {
+      try {
+        const { query, variables, operationName } = req.body || {};
+        if (typeof query !== 'string' || query.trim() === '') {
+          throw createHttpError(400, 'A GraphQL query is required');
+        }
+
+        const authorization = req.headers['authorization'] || '';
+        const loaders = createLoaders(
+          new BookingServiceClient(options.bookingServiceUrl, authorization),
+          new TrackingServiceClient(options.trackingServiceUrl, authorization, options.trackingServiceApiKey)
+        );
+
+        const result = await graphql({
+          schema,
+          source: query,
+          variableValues: variables,
+          operationName,
+          contextValue: { loaders, userId: req.user?.id || '', role: req.user?.role || '', bookingsRequested: 0 }
+        });
+
+        if (result.errors) {
+          logger.logError('GraphQL query returned errors', {
+            userId: req.user?.id,
+            operationName,
+            errors: result.errors.map((error) => error.message),
+            requestId: req.headers['x-request-id']
+          });
+        }
+        res.status(200).json(result);
+      } catch (error) {
+        logger.logError('Error in GraphQL route', {
+          error,
+          path: req.path,
+          method: req.method,
+          userId: req.user?.id
+        });
+        next(error);
+      }
+    }
+  );
+};
@@ -0,0 +1,65 @@
+// dataloader v2.2.2
+import DataLoader from 'dataloader';
+import {
+  Booking,
+  BookingEvent,
+  BookingServiceClient,
+  TrackingServiceClient,
+  WalkEvidence,
+  WalkerPosition
+} from './clients';
+
+/**
+ * @description Most bookings fetched in one request to the booking-service, which lists at most
+ * 100 bookings a page
+ */
+const BOOKING_BATCH_SIZE = 100;
+
+/**
+ * @description Per-request loaders. Each key is fetched once per request however many fields
+ * ask for it, and the fetches a query needs are started together rather than one field at a
+ * time. Bookings are fetched in batches with one request each; the other services have no batch
+ * endpoints, so their batches are sent as concurrent requests.
+ */
+export interface Loaders {
+  bookings: DataLoader<string, Booking | null>;
+  bookingEvents: DataLoader<string, BookingEvent[]>;
+  walkerPositions: DataLoader<string, WalkerPosition | null>;
+  walkEvidence: DataLoader<string, WalkEvidence | null>;
+}
+
+/**
+ * @description Resolves each key with load, turning a failed load into that key's error so
+ * one failure does not fail the whole batch
+ */
+const fetchEach = <T>(load: (key: string) => Promise<T>) => {
+  return (keys: readonly string[]): Promise<Array<T | Error>> =>
+    Promise.all(
+      keys.map((key) =>
+        load(key).catch((error: unknown) => (error instanceof Error ? error : new Error(String(error))))
+      )
+    );
+};
+
+/**
+ * @description Fetches a batch of bookings in one request, resolving each key to its booking or
+ * to null when the booking-service did not return it
+ */
+const fetchBookings = (client: BookingServiceClient) => {
+  return async (ids: readonly string[]): Promise<Array<Booking | null>> => {
+    const found = new Map((await client.getBookings(ids)).map((booking): [string, Booking] => [booking.id, booking]));
+    return ids.map((id) => found.get(id) ?? null);
+  };
+};
+
+/**
+ * @description Creates the loaders for one GraphQL request, reading from the services with the
+ * caller's credentials. Loaders cache for the request only, so they must not be shared.
+ * Addresses requirement: Technical Specification/8.3 API Design/8.3.3 Integration Patterns
+ */
+export const createLoaders = (bookings: BookingServiceClient, tracking: TrackingServiceClient): Loaders => ({
+  bookings: new DataLoader(fetchBookings(bookings), { maxBatchSize: BOOKING_BATCH_SIZE }),
+  bookingEvents: new DataLoader(fetchEach((id) => bookings.getBookingHistory(id))),
+  walkerPositions: new DataLoader(fetchEach((walkerId) => tracking.getWalkerPosition(walkerId))),
+  walkEvidence: new DataLoader(fetchEach((bookingId) => tracking.getWalkEvidence(bookingId)))
+});
@@ -0,0 +1,213 @@
+// graphql v16.8.1
+import {
+  GraphQLBoolean,
+  GraphQLFloat,
+  GraphQLID,
+  GraphQLInt,
+  GraphQLList,
+  GraphQLNonNull,
+  GraphQLObjectType,
+  GraphQLSchema,
+  GraphQLString
+} from 'graphql';
+import { Booking, BookingEvent, WalkEvidence, WalkerPosition } from './clients';
+import { Loaders } from './loaders';
+
+/**
+ * @description Most bookings one query may request by ID, across all its fields and aliases
+ */
+export const MAX_BOOKINGS_PER_QUERY = 50;
+
+/**
+ * @description Events returned for a booking when no limit is given
+ */
+const DEFAULT_EVENT_LIMIT = 10;
+
+/**
+ * @description Context of a GraphQL request
+ */
+export interface GraphQLContext {
+  loaders: Loaders;
+
+  /** ID of the caller, deciding which walkers' positions they may read */
+  userId: string;
+
+  /** Role of the caller, deciding which fields they may read */
+  role: string;
+
+  /** Bookings the query has requested so far */
+  bookingsRequested: number;
+}
+
+/**
+ * @description Counts count more bookings against the query's MAX_BOOKINGS_PER_QUERY, so that
+ * aliasing the booking fields cannot request more
+ */
+const requestBookings = (context: GraphQLContext, count: number): void => {
+  if (context.bookingsRequested + count > MAX_BOOKINGS_PER_QUERY) {
+    throw new Error(`At most ${MAX_BOOKINGS_PER_QUERY} bookings may be requested at once`);
+  }
+  context.bookingsRequested += count;
+};
+
+/**
+ * @description A walker or dog, known by ID; their profiles live outside these services
+ */
+interface Ref {
+  id: string;
+}
+
+/**
+ * @description A booking's walker, with the booking deciding who may see where they are
+ */
+interface WalkerRef extends Ref {
+  booking: Booking;
+}
+
+/**
+ * @description Whether the caller may see where a booking's walker is: only its owner, its walker
+ * and admins may. Positions are read with the gateway's API key, so the tracking-service cannot
+ * check the caller itself.
+ */
+const canTrack = (context: GraphQLContext, booking: Booking): boolean =>
+  context.role === 'admin' ||
+  (context.userId !== '' && (context.userId === booking.owner_id || context.userId === booking.walker_id));
+
+const PositionType = new GraphQLObjectType<WalkerPosition, GraphQLContext>({
+  name: 'Position',
+  description: 'Where a walker was last seen during a walk, to a cell about 150m across',
+  fields: {
+    latitude: { type: new GraphQLNonNull(GraphQLFloat) },
+    longitude: { type: new GraphQLNonNull(GraphQLFloat) },
+    sessionId: { type: new GraphQLNonNull(GraphQLID), resolve: (position) => position.session_id },
+    updatedAt: { type: new GraphQLNonNull(GraphQLString), resolve: (position) => position.updated_at }
+  }
+});
+
+const WalkerType = new GraphQLObjectType<WalkerRef, GraphQLContext>({
+  name: 'Walker',
+  fields: {
+    id: { type: new GraphQLNonNull(GraphQLID) },
+    lastLocation: {
+      type: PositionType,
+      description: "Null when the walker has not been seen during any walk, or the caller is not on the walker's booking",
+      resolve: (walker, _args, context) =>
+        canTrack(context, walker.booking) ? context.loaders.walkerPositions.load(walker.id) : null
+    }
+  }
+});
+
+const DogType = new GraphQLObjectType<Ref, GraphQLContext>({
+  name: 'Dog',
+  fields: {
+    id: { type: new GraphQLNonNull(GraphQLID) }
+  }
+});
+
+const WalkEvidenceType = new GraphQLObjectType<WalkEvidence, GraphQLContext>({
+  name: 'WalkEvidence',
+  description: "What was tracked of a booking's walks",
+  fields: {
+    sessions: { type: new GraphQLNonNull(GraphQLInt) },
+    active: { type: new GraphQLNonNull(GraphQLBoolean) },
+    durationSeconds: { type: new GraphQLNonNull(GraphQLFloat), resolve: (evidence) => evidence.duration_seconds },
+    distanceMeters: { type: new GraphQLNonNull(GraphQLFloat), resolve: (evidence) => evidence.distance_meters },
+    photos: { type: new GraphQLNonNull(GraphQLInt) }
+  }
+});
+
+const BookingEventType = new GraphQLObjectType<BookingEvent, GraphQLContext>({
+  name: 'BookingEvent',
+  fields: {
+    version: { type: new GraphQLNonNull(GraphQLInt) },
+    type: { type: new GraphQLNonNull(GraphQLString) },
+    changes: {
+      type: new GraphQLNonNull(GraphQLString),
+      description: 'The booking fields the event set, as a JSON object',
+      resolve: (event) => JSON.stringify(event.changes)
+    },
+    recordedAt: { type: new GraphQLNonNull(GraphQLString), resolve: (event) => event.recorded_at }
+  }
+});
+
+const BookingType = new GraphQLObjectType<Booking, GraphQLContext>({
+  name: 'Booking',
+  fields: {
+    id: { type: new GraphQLNonNull(GraphQLID) },
+    ownerId: { type: new GraphQLNonNull(GraphQLID), resolve: (booking) => booking.owner_id },
+    status: { type: new GraphQLNonNull(GraphQLString) },
+    scheduledAt: { type: new GraphQLNonNull(GraphQLString), resolve: (booking) => booking.scheduled_at },
+    durationMinutes: { type: new GraphQLNonNull(GraphQLInt), resolve: (booking) => booking.duration_minutes },
+    amount: { type: new GraphQLNonNull(GraphQLFloat) },
+    region: { type: GraphQLString },
+    walker: {
+      type: WalkerType,
+      description: 'Null until a walker is assigned',
+      resolve: (booking): WalkerRef | null => (booking.walker_id ? { id: booking.walker_id, booking } : null)
+    },
+    dog: {
+      type: new GraphQLNonNull(DogType),
+      resolve: (booking): Ref => ({ id: booking.dog_id })
+    },
+    lastLocation: {
+      type: PositionType,
+      description: "The assigned walker's last tracked position; null unless the caller is on the booking or an admin",
+      resolve: (booking, _args, context) =>
+        booking.walker_id && canTrack(context, booking) ? context.loaders.walkerPositions.load(booking.walker_id) : null
+    },
+    walkEvidence: {
+      type: WalkEvidenceType,
+      resolve: (booking, _args, context) => context.loaders.walkEvidence.load(booking.id)
+    },
+    events: {
+      type: new GraphQLList(new GraphQLNonNull(BookingEventType)),
+      description: 'The most recent changes to the booking, newest first; null unless the caller is an admin',
+      args: {
+        limit: { type: GraphQLInt, defaultValue: DEFAULT_EVENT_LIMIT }
+      },
+      resolve: async (booking, args: { limit: number }, context) => {
+        if (context.role !== 'admin') {
+          return null;
+        }
+        const events = await context.loaders.bookingEvents.load(booking.id);
+        const limit = Math.max(0, args.limit);
+        return events.slice(Math.max(0, events.length - limit)).reverse();
+      }
+    }
+  }
+});
+
+const QueryType = new GraphQLObjectType<unknown, GraphQLContext>({
+  name: 'Query',
+  fields: {
+    booking: {
+      type: BookingType,
+      args: {
+        id: { type: new GraphQLNonNull(GraphQLID) }
+      },
+      resolve: (_source, args: { id: string }, context) => {
+        requestBookings(context, 1);
+        return context.loaders.bookings.load(args.id);
+      }
+    },
+    bookings: {
+      type: new GraphQLNonNull(new GraphQLList(BookingType)),
+      description: `Bookings by ID, in the order requested, with null for any not found; at most ${MAX_BOOKINGS_PER_QUERY}`,
+      args: {
+        ids: { type: new GraphQLNonNull(new GraphQLList(new GraphQLNonNull(GraphQLID))) }
+      },
+      resolve: (_source, args: { ids: string[] }, context) => {
+        requestBookings(context, args.ids.length);
+        return context.loaders.bookings.loadMany(args.ids);
+      }
+    }
+  }
+});
+
+/**
+ * @description Read-only schema resolving a booking together with its walker, dog, the walker's
+ * last location, the walk's tracking and the booking's recent events across the booking and
+ * tracking services
+ * Addresses requirement: Technical Specification/8.3 API Design/8.3.2 API Specifications
+ */
+export const schema = new GraphQLSchema({ query: QueryType });
@@ -25,6 +25,7 @@ import { authenticateRequest, authorizeRequest } from './middleware/auth';
 import { rateLimitMiddleware } from './middleware/rateLimit';
 import { validateRequest } from './middleware/validation';
 import { registerAllRoutes } from './routes';
+import { registerGraphQLRoute } from './graphql';
 import { createHttpError } from '../../shared/utils/error';
 import logger from '../../shared/utils/logger';
 
@@ -50,6 +51,7 @@ export const initializeServer = async (): Promise<void> => {
         : '*',
       methods: ['GET', 'POST', 'PUT', 'DELETE', 'OPTIONS'],
       allowedHeaders: ['Content-Type', 'Authorization'],
+      exposedHeaders: ['X-Total-Count'],
       credentials: true,
       maxAge: 86400 // 24 hours
     }));
@@ -71,6 +73,13 @@ export const initializeServer = async (): Promise<void> => {
     // Register all API routes
     registerAllRoutes(app);
 
+    // Register the GraphQL endpoint resolving across the booking and tracking services
+    registerGraphQLRoute(app, {
+      bookingServiceUrl: config.bookingServiceUrl,
+      trackingServiceUrl: config.trackingServiceUrl,
+      trackingServiceApiKey: config.trackingServiceApiKey
+    });
+
     // Global error handling middleware
     app.use((err: Error, req: Request, res: Response, next: NextFunction) => {
       logger.logError('Unhandled error in API Gateway', {
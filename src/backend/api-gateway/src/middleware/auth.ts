@@ -10,6 +10,9 @@
 // jsonwebtoken v9.0.0
 import { Request, Response, NextFunction } from 'express';
 import { authenticateToken, authorizeRole } from '../../../shared/middleware/auth';
+
+// Scope checks for identity provider tokens need no gateway-specific handling
+export { requireScopes } from '../../../shared/middleware/auth';
 import logger from '../../../shared/utils/logger';
 import { createHttpError } from '../../../shared/utils/error';
 import { validateModel } from '../../../shared/utils/validation';
@@ -13,7 +13,7 @@ import { Booking, validate } from '../../../shared/models/booking';
 import { createHttpError } from '../../../shared/utils/error';
 import logger from '../../../shared/utils/logger';
 import { validateRequest } from '../middleware/validation';
-import { authenticateRequest } from '../middleware/auth';
+import { authenticateRequest, requireScopes } from '../middleware/auth';
 
 /**
  * @description Handles the creation of a new booking via the API.
@@ -149,6 +149,7 @@ const router = Router();
 router.post(
   '/bookings',
   authenticateRequest,
+  requireScopes(['bookings:write']),
   validateRequest(Booking),
   createBookingRoute
 );
@@ -156,6 +157,7 @@ router.post(
 router.get(
   '/bookings/:id',
   authenticateRequest,
+  requireScopes(['bookings:read']),
   getBookingRoute
 );
 
@@ -4,7 +4,7 @@ import { Location } from '../../../shared/models/location';
 import { Location as TrackingProto } from '../../../shared/proto/tracking.proto';
 import logger from '../../../shared/utils/logger';
 import { validateModel } from '../../../shared/utils/validation';
-import { authenticateRequest } from '../middleware/auth';
+import { authenticateRequest, requireScopes } from '../middleware/auth';
 import { validateRequest } from '../middleware/validation';
 
 /**
@@ -34,6 +34,7 @@ export const registerTrackingRoutes = (app: Router): void => {
   trackingRouter.post(
     '/location',
     authenticateRequest,
+    requireScopes(['tracking:write']),
     validateRequest(Location),
     async (req, res, next) => {
       try {
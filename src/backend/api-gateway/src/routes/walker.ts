@@ -12,7 +12,7 @@ import { User } from '../../../shared/models/user';
 import { Booking } from '../../../shared/models/booking';
 import logger from '../../../shared/utils/logger';
 import { validateModel } from '../../../shared/utils/validation';
-import { authenticateRequest } from '../middleware/auth';
+import { authenticateRequest, requireScopes } from '../middleware/auth';
 import { validateRequest } from '../middleware/validation';
 import { createHttpError } from '../../../shared/utils/error';
 
@@ -109,6 +109,7 @@ export const registerWalkerRoutes = (router: Router): void => {
   router.get(
     '/walkers/:walkerId/bookings',
     authenticateRequest,
+    requireScopes(['bookings:read']),
     async (req: Request, res: Response, next: NextFunction) => {
       try {
         const { walkerId } = req.params;
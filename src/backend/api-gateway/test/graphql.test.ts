@@ -0,0 +1,230 @@
+// jest v29.0.0
+// graphql v16.8.1
+import { graphql } from 'graphql';
+import { BookingServiceClient, TrackingServiceClient } from '../src/graphql/clients';
+import { createLoaders } from '../src/graphql/loaders';
+import { MAX_BOOKINGS_PER_QUERY, schema } from '../src/graphql/schema';
+
+const bookings: Record<string, object> = {
+  'booking-1': {
+    id: 'booking-1',
+    owner_id: 'owner-1',
+    walker_id: 'walker-1',
+    dog_id: 'dog-1',
+    scheduled_at: '2030-03-04T09:00:00Z',
+    status: 'confirmed',
+    amount: 25.5,
+    duration_minutes: 30
+  },
+  'booking-2': {
+    id: 'booking-2',
+    owner_id: 'owner-1',
+    walker_id: 'walker-1',
+    dog_id: 'dog-2',
+    scheduled_at: '2030-03-04T10:00:00Z',
+    status: 'confirmed',
+    amount: 25.5,
+    duration_minutes: 30
+  }
+};
+
+const history: Record<string, object[]> = {
+  'booking-1': [
+    { booking_id: 'booking-1', version: 1, type: 'booking.created', changes: {}, recorded_at: '2030-03-01T09:00:00Z' },
+    { booking_id: 'booking-1', version: 2, type: 'booking.confirmed', changes: {}, recorded_at: '2030-03-01T10:00:00Z' },
+    { booking_id: 'booking-1', version: 3, type: 'booking.updated', changes: {}, recorded_at: '2030-03-02T09:00:00Z' }
+  ]
+};
+
+/**
+ * The tracking-service API key walker positions are served with
+ */
+const TRACKING_API_KEY = 'tracking-key';
+
+const jsonResponse = (status: number, body: unknown) =>
+  ({ status, ok: status < 400, json: async () => body } as Response);
+
+/**
+ * Answers the downstream requests the resolvers make, recording each path requested. Walker
+ * positions require the tracking-service API key, as the tracking-service does.
+ */
+const mockServices = (): string[] => {
+  const requested: string[] = [];
+  global.fetch = jest.fn(async (input: RequestInfo | URL, init?: RequestInit) => {
+    const url = new URL(String(input));
+    const headers = (init?.headers || {}) as Record<string, string>;
+    requested.push(url.host + url.pathname + url.search);
+    if (url.pathname === '/api/v1/bookings') {
+      const ids = (url.searchParams.get('ids') || '').split(',');
+      const found = ids.filter((id) => bookings[id]).map((id) => bookings[id]);
+      return jsonResponse(200, { success: true, data: { bookings: found } });
+    }
+    if (url.pathname.endsWith('/position') && headers['X-API-Key'] !== TRACKING_API_KEY) {
+      return jsonResponse(401, {});
+    }
+    if (url.pathname === '/api/v1/walkers/walker-1/position') {
+      return jsonResponse(200, {
+        walker_id: 'walker-1',
+        session_id: 'session-1',
+        latitude: 51.5,
+        longitude: -0.12,
+        updated_at: '2030-03-04T09:10:00Z'
+      });
+    }
+    const events = url.pathname.match(/^\/api\/v1\/admin\/bookings\/([^/]+)\/history$/);
+    if (events && events[1]) {
+      const data = history[events[1]];
+      return data ? jsonResponse(200, { success: true, data }) : jsonResponse(403, {});
+    }
+    return jsonResponse(404, {});
+  }) as typeof fetch;
+  return requested;
+};
+
+const execute = (source: string, role = 'owner', userId = 'owner-1', apiKey = TRACKING_API_KEY) =>
+  graphql({
+    schema,
+    source,
+    contextValue: {
+      loaders: createLoaders(
+        new BookingServiceClient('http://booking-service:8081', 'Bearer token'),
+        new TrackingServiceClient('http://tracking-service:8084', 'Bearer token', apiKey)
+      ),
+      userId,
+      role,
+      bookingsRequested: 0
+    }
+  });
+
+/**
+ * GraphQL Tests
+ * Addresses requirement: Technical Specification/8.3 API Design/8.3.2 API Specifications
+ */
+describe('GraphQL', () => {
+  test('resolves a booking with its walker, dog and last location', async () => {
+    mockServices();
+
+    const result = await execute(`{
+      booking(id: "booking-1") {
+        id status durationMinutes
+        walker { id lastLocation { latitude sessionId } }
+        dog { id }
+        lastLocation { updatedAt }
+      }
+    }`);
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data).toEqual({
+      booking: {
+        id: 'booking-1',
+        status: 'confirmed',
+        durationMinutes: 30,
+        walker: { id: 'walker-1', lastLocation: { latitude: 51.5, sessionId: 'session-1' } },
+        dog: { id: 'dog-1' },
+        lastLocation: { updatedAt: '2030-03-04T09:10:00Z' }
+      }
+    });
+  });
+
+  test('fetches the bookings of a query in one request and each walker position once', async () => {
+    const requested = mockServices();
+
+    const result = await execute(`{
+      first: booking(id: "booking-1") { walker { lastLocation { latitude } } }
+      all: bookings(ids: ["booking-1", "booking-2", "missing"]) { id lastLocation { latitude } }
+    }`);
+
+    expect(result.errors).toBeUndefined();
+    expect(result.data?.all).toEqual([
+      { id: 'booking-1', lastLocation: { latitude: 51.5 } },
+      { id: 'booking-2', lastLocation: { latitude: 51.5 } },
+      null
+    ]);
+    expect(requested.sort()).toEqual([
+      'booking-service:8081/api/v1/bookings?ids=booking-1%2Cbooking-2%2Cmissing&limit=3',
+      'tracking-service:8084/api/v1/walkers/walker-1/position'
+    ]);
+  });
+
+  test('reads walker positions with the tracking-service API key', async () => {
+    mockServices();
+
+    const query = `{ booking(id: "booking-1") { lastLocation { latitude } walker { lastLocation { latitude } } } }`;
+    const walker = await execute(query, 'walker', 'walker-1');
+    expect(walker.errors).toBeUndefined();
+    expect(walker.data).toEqual({
+      booking: { lastLocation: { latitude: 51.5 }, walker: { lastLocation: { latitude: 51.5 } } }
+    });
+
+    const withoutKey = await execute(query, 'owner', 'owner-1', '');
+    expect(withoutKey.errors?.[0]?.message).toBe('tracking-service responded with status 401');
+  });
+
+  test('hides walker positions from callers not on the booking, without asking for them', async () => {
+    const requested = mockServices();
+
+    const query = `{ booking(id: "booking-1") { id lastLocation { latitude } walker { id lastLocation { latitude } } } }`;
+    const stranger = await execute(query, 'owner', 'owner-2');
+    expect(stranger.errors).toBeUndefined();
+    expect(stranger.data).toEqual({
+      booking: { id: 'booking-1', lastLocation: null, walker: { id: 'walker-1', lastLocation: null } }
+    });
+    expect(requested.some((path) => path.endsWith('/position'))).toBe(false);
+
+    const admin = await execute(query, 'admin', 'admin-1');
+    expect(admin.errors).toBeUndefined();
+    expect(admin.data?.booking).toMatchObject({ lastLocation: { latitude: 51.5 } });
+  });
+
+  test('answers events to admins only, without asking for them otherwise', async () => {
+    const requested = mockServices();
+
+    const owner = await execute(`{ booking(id: "booking-1") { id events { version } } }`);
+    expect(owner.errors).toBeUndefined();
+    expect(owner.data).toEqual({ booking: { id: 'booking-1', events: null } });
+    expect(requested.some((path) => path.includes('/history'))).toBe(false);
+
+    const admin = await execute(`{ booking(id: "booking-1") { events(limit: 2) { version type } } }`, 'admin');
+    expect(admin.errors).toBeUndefined();
+    expect(admin.data).toEqual({
+      booking: {
+        events: [
+          { version: 3, type: 'booking.updated' },
+          { version: 2, type: 'booking.confirmed' }
+        ]
+      }
+    });
+  });
+
+  test('reports a field that failed downstream as an error beside the rest', async () => {
+    mockServices();
+
+    const result = await execute(
+      `{
+        first: booking(id: "booking-1") { id }
+        second: booking(id: "booking-2") { id events { version } }
+      }`,
+      'admin'
+    );
+
+    expect(result.data).toEqual({
+      first: { id: 'booking-1' },
+      second: { id: 'booking-2', events: null }
+    });
+    expect(result.errors?.[0]?.message).toBe('booking-service responded with status 403');
+  });
+
+  test('limits the bookings of a query however its fields are aliased', async () => {
+    mockServices();
+
+    const aliased = Array.from({ length: MAX_BOOKINGS_PER_QUERY + 1 }, (_, i) => `b${i}: booking(id: "booking-${i}") { id }`);
+    const single = await execute(`{ ${aliased.join('\n')} }`);
+    expect(single.errors).toHaveLength(1);
+    expect(single.errors?.[0]?.message).toBe(`At most ${MAX_BOOKINGS_PER_QUERY} bookings may be requested at once`);
+
+    const ids = JSON.stringify(Array.from({ length: 30 }, (_, i) => `booking-${i}`));
+    const lists = await execute(`{ first: bookings(ids: ${ids}) { id } second: bookings(ids: ${ids}) { id } }`);
+    expect(lists.errors).toHaveLength(1);
+    expect(lists.errors?.[0]?.path).toEqual(['second']);
+  });
+});
@@ -0,0 +1,153 @@
+// jest v29.0.0
+import http from 'http';
+import { AddressInfo } from 'net';
+import { generateKeyPairSync } from 'crypto';
+// jsonwebtoken v9.0.0
+import jwt from 'jsonwebtoken';
+import { loadOAuthConfig, OAuthVerifier } from '../../shared/middleware/oauth';
+import { authenticateToken, requireScopes } from '../../shared/middleware/auth';
+
+const AUDIENCE = 'https://api.dogwalking.test';
+
+// The identity provider's signing key, published through its JWKS
+const { privateKey, publicKey } = generateKeyPairSync('rsa', { modulusLength: 2048 });
+const signingKey = privateKey.export({ type: 'pkcs8', format: 'pem' }).toString();
+
+let provider: http.Server;
+let issuer: string;
+
+// Serves OpenID discovery metadata and the key set, as the identity provider does
+beforeAll(async () => {
+  provider = http.createServer((req, res) => {
+    res.setHeader('Content-Type', 'application/json');
+    if (req.url === '/.well-known/openid-configuration') {
+      res.end(JSON.stringify({ issuer, jwks_uri: `${issuer}/jwks` }));
+    } else if (req.url === '/jwks') {
+      res.end(JSON.stringify({ keys: [{ ...publicKey.export({ format: 'jwk' }), kid: 'key-1', use: 'sig', alg: 'RS256' }] }));
+    } else {
+      res.statusCode = 404;
+      res.end('{}');
+    }
+  });
+  await new Promise<void>(resolve => provider.listen(0, '127.0.0.1', resolve));
+  issuer = `http://127.0.0.1:${(provider.address() as AddressInfo).port}`;
+});
+
+afterAll(done => {
+  provider.close(done);
+});
+
+/** Signs an access token as the identity provider would, with claims overriding the defaults */
+const providerToken = (claims: Record<string, unknown> = {}, options: jwt.SignOptions = {}): string =>
+  jwt.sign({ sub: 'client-1', scope: 'bookings:read bookings:write', ...claims }, signingKey, {
+    algorithm: 'RS256',
+    keyid: 'key-1',
+    issuer,
+    audience: AUDIENCE,
+    expiresIn: '5m',
+    ...options
+  });
+
+/** Runs a middleware against a request and resolves with what it passed to next */
+const run = async (middleware: (req: any, res: any, next: any) => Promise<void>, req: any): Promise<any> => {
+  let passed: any = 'next not called';
+  await middleware(req, {}, (error?: any) => {
+    passed = error;
+  });
+  return passed;
+};
+
+describe('loadOAuthConfig', () => {
+  test('disables OAuth2 tokens while no issuer is configured', () => {
+    expect(loadOAuthConfig({})).toBeUndefined();
+    expect(loadOAuthConfig({ OAUTH_ISSUER: '  ' })).toBeUndefined();
+  });
+
+  test('reads the identity provider settings', () => {
+    expect(
+      loadOAuthConfig({ OAUTH_ISSUER: 'https://id.example.com', OAUTH_AUDIENCE: AUDIENCE, OAUTH_JWKS_URI: 'https://id.example.com/keys' })
+    ).toEqual({ issuer: 'https://id.example.com', audience: AUDIENCE, jwksUri: 'https://id.example.com/keys', clockToleranceSeconds: 30 });
+  });
+
+  test('rejects incomplete or invalid settings', () => {
+    expect(() => loadOAuthConfig({ OAUTH_ISSUER: 'https://id.example.com' })).toThrow('OAUTH_AUDIENCE');
+    expect(() =>
+      loadOAuthConfig({ OAUTH_ISSUER: 'https://id.example.com', OAUTH_AUDIENCE: AUDIENCE, OAUTH_CLOCK_TOLERANCE_SECONDS: '-1' })
+    ).toThrow('OAUTH_CLOCK_TOLERANCE_SECONDS');
+  });
+});
+
+describe('OAuthVerifier', () => {
+  const verifier = () => new OAuthVerifier({ issuer, audience: AUDIENCE, clockToleranceSeconds: 0 });
+
+  test('verifies tokens against keys found through discovery', async () => {
+    const token = providerToken({ client_id: 'partner-app' });
+    expect(verifier().isIssuedBy(token)).toBe(true);
+    await expect(verifier().verify(token)).resolves.toEqual({
+      subject: 'client-1',
+      clientId: 'partner-app',
+      email: undefined,
+      role: undefined,
+      scopes: ['bookings:read', 'bookings:write']
+    });
+  });
+
+  test('reads scopes from an scp array', async () => {
+    const token = providerToken({ scope: undefined, scp: ['tracking:read', 42] });
+    await expect(verifier().verify(token)).resolves.toMatchObject({ scopes: ['tracking:read'] });
+  });
+
+  test('rejects tokens for another audience, expired or not signed by the provider', async () => {
+    await expect(verifier().verify(providerToken({}, { audience: 'another-api' }))).rejects.toThrow();
+    await expect(verifier().verify(providerToken({}, { expiresIn: -60 }))).rejects.toThrow();
+
+    const forged = jwt.sign({ sub: 'client-1', scope: 'bookings:write' }, 'shared-secret', { issuer, audience: AUDIENCE });
+    expect(verifier().isIssuedBy(forged)).toBe(true);
+    await expect(verifier().verify(forged)).rejects.toThrow();
+  });
+
+  test('only claims tokens naming its issuer', () => {
+    expect(verifier().isIssuedBy(jwt.sign({ sub: 'owner-1' }, 'session-secret'))).toBe(false);
+    expect(verifier().isIssuedBy('not-a-token')).toBe(false);
+  });
+});
+
+describe('authenticateToken with identity provider tokens', () => {
+  beforeAll(() => {
+    process.env.OAUTH_ISSUER = issuer;
+    process.env.OAUTH_AUDIENCE = AUDIENCE;
+  });
+
+  test('exposes the token subject and granted scopes', async () => {
+    const req: any = { headers: { authorization: `Bearer ${providerToken({ client_id: 'partner-app' })}` } };
+    expect(await run(authenticateToken, req)).toBeUndefined();
+    expect(req.user).toEqual({ id: 'client-1', email: '', role: 'client' });
+    expect(req.auth).toEqual({ clientId: 'partner-app', scopes: ['bookings:read', 'bookings:write'] });
+  });
+
+  test('rejects tokens the provider did not sign', async () => {
+    const forged = jwt.sign({ sub: 'client-1', scope: 'bookings:write' }, 'shared-secret', { issuer, audience: AUDIENCE });
+    const error = await run(authenticateToken, { headers: { authorization: `Bearer ${forged}` } });
+    expect(error.status).toBe(403);
+  });
+});
+
+describe('requireScopes', () => {
+  test('requires every scope of identity provider tokens', async () => {
+    const user = { id: 'client-1', email: '', role: 'client' };
+    expect(await run(requireScopes(['bookings:write']), { user, auth: { scopes: ['bookings:read', 'bookings:write'] } })).toBeUndefined();
+
+    const error = await run(requireScopes(['bookings:write', 'tracking:read']), { user, auth: { scopes: ['bookings:write'] } });
+    expect(error.status).toBe(403);
+  });
+
+  test('leaves session tokens to role checks', async () => {
+    const user = { id: 'owner-1', email: 'owner@example.com', role: 'owner' };
+    expect(await run(requireScopes(['bookings:write']), { user })).toBeUndefined();
+  });
+
+  test('requires an authenticated request', async () => {
+    const error = await run(requireScopes(['bookings:read']), {});
+    expect(error.status).toBe(401);
+  });
+});
@@ -0,0 +1,45 @@
+// Package main is a break-glass admin CLI for the Booking Service. It works directly against
+// the booking database, so it keeps working when the service itself is unavailable.
+package main
+
+import (
+    "fmt"
+    "os"
+
+    "github.com/spf13/cobra" // v1.6.1
+
+    "src/backend/booking-service/internal/cli"
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/repository"
+)
+
+// Human Tasks:
+// 1. Restrict who can run this tool; it bypasses the admin API's role checks
+// 2. Run it with the same BOOKING_DATABASE_URL as the service, from inside the cluster network
+
+func main() {
+    root := &cobra.Command{
+        Use:           "admin",
+        Short:         "Operational tasks against the booking database",
+        SilenceUsage:  true,
+        SilenceErrors: true,
+        PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+            if err := config.LoadConfig(); err != nil {
+                return err
+            }
+            if config.Config.Store == config.StoreMemory {
+                return fmt.Errorf("the admin tool needs a database; unset STORE=memory")
+            }
+            return repository.InitDB(config.Config)
+        },
+        PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+            return repository.Close()
+        },
+    }
+    root.AddCommand(cli.ListBookingsCommand(), cli.ForceStatusCommand())
+
+    if err := root.Execute(); err != nil {
+        fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+        os.Exit(1)
+    }
+}
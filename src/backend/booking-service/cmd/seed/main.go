@@ -0,0 +1,65 @@
+// Package main seeds the booking database with sample walkers, availability and bookings.
+// The same -seed and -start always produce the same records, so demos and manual tests are
+// reproducible.
+package main
+
+import (
+    "context"
+    "flag"
+    "log"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/seed"
+)
+
+// Human Tasks:
+// 1. Run only against development and demo databases; seeded records use predictable IDs
+// 2. Seed an empty database or pick a new -seed value, since IDs collide when a seed is reused
+
+func main() {
+    seedValue := flag.Int64("seed", 1, "random seed; IDs are prefixed with it so several seeds can coexist")
+    walkers := flag.Int("walkers", 10, "number of walkers publishing availability")
+    owners := flag.Int("owners", 25, "number of dog owners making bookings")
+    bookings := flag.Int("bookings", 200, "number of bookings to create; bookings that would overbook a walker are skipped")
+    days := flag.Int("days", 7, "bookings are spread over this many days before and after the start date")
+    startDate := flag.String("start", time.Now().UTC().Format("2006-01-02"), "date the schedule is centred on (YYYY-MM-DD); bookings before it are in the past")
+    migrate := flag.Bool("migrate", false, "apply schema migrations before seeding")
+    flag.Parse()
+
+    start, err := time.Parse("2006-01-02", *startDate)
+    if err != nil {
+        log.Fatalf("Invalid -start date: %v", err)
+    }
+
+    if err := config.LoadConfig(); err != nil {
+        log.Fatalf("Failed to load configuration: %v", err)
+    }
+    if err := repository.InitDB(config.Config); err != nil {
+        log.Fatalf("Failed to initialize database: %v", err)
+    }
+    defer repository.Close()
+
+    ctx := context.Background()
+    if *migrate {
+        if err := repository.Migrate(ctx); err != nil {
+            log.Fatalf("Failed to migrate database: %v", err)
+        }
+    }
+
+    report, err := seed.Run(ctx, seed.Options{
+        Seed:     *seedValue,
+        Walkers:  *walkers,
+        Owners:   *owners,
+        Bookings: *bookings,
+        Days:     *days,
+        Start:    start,
+    })
+    if err != nil {
+        log.Fatalf("Failed to seed database: %v", err)
+    }
+
+    log.Printf("Seeded %d walkers, %d availability windows and %d bookings (%d skipped as the walker was fully booked)",
+        report.Walkers, report.Windows, report.Bookings, report.Skipped)
+}
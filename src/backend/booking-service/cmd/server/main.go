@@ -2,23 +2,41 @@
 package main
 
 import (
-    "fmt"
+    "context"
     "log"
     "net/http"
-    "os"
-    "os/signal"
-    "syscall"
 
     "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/encryption"
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/exchange"
+    "src/backend/booking-service/internal/geocoding"
     "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/integrations"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/receipts"
     "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/booking-service/internal/tax"
+    "src/backend/booking-service/internal/tracking"
+    "src/backend/shared/bootstrap"
+    "src/backend/shared/clients"
+    "src/backend/shared/faults"
+    "src/backend/shared/featureflags"
+    "src/backend/shared/httpmetrics"
+    "src/backend/shared/moderation"
+    "src/backend/shared/policy"
 )
 
 // Human Tasks:
 // 1. Configure environment variables for service configuration
 // 2. Set up monitoring and metrics collection
 // 3. Configure logging aggregation
-// 4. Set up health check endpoint monitoring
+// 4. Set up health check endpoint monitoring (/health for liveness, /ready for readiness)
 // 5. Review and adjust server timeouts based on load testing
 // 6. Configure TLS/SSL certificates for HTTPS
 // 7. Set up rate limiting and request throttling
@@ -30,55 +48,344 @@ func main() {
         log.Fatalf("Failed to load configuration: %v", err)
     }
 
-    // Initialize database connection
+    // Initialize database connection, or the in-memory store when running without one
     // Addresses requirement 7.2.1: Booking System Initialization
-    if err := repository.InitDB(config.Config); err != nil {
-        log.Fatalf("Failed to initialize database: %v", err)
+    if config.Config.Store == config.StoreMemory {
+        repository.UseMemoryStore()
+        log.Println("Using the in-memory store; data will be lost on restart")
+    } else {
+        if err := repository.InitDB(config.Config); err != nil {
+            log.Fatalf("Failed to initialize database: %v", err)
+        }
+        if config.Config.Migrate {
+            if err := repository.Migrate(context.Background()); err != nil {
+                log.Fatalf("Failed to migrate database: %v", err)
+            }
+        }
+        // Requests that only read bookings can be served by replicas while they keep up
+        if err := repository.InitReplicas(context.Background(), config.Config); err != nil {
+            log.Fatalf("Failed to initialize read replicas: %v", err)
+        }
+        // Prepare the statements nearly every request runs, once the tables they name exist
+        if err := repository.PrepareStatements(context.Background()); err != nil {
+            log.Fatalf("Failed to prepare statements: %v", err)
+        }
+    }
+
+    // Record bookings' history as events, or stop recording it
+    if err := repository.ConfigureEventSourcing(context.Background(), config.Config.EventSourcing, config.Config.SnapshotInterval); err != nil {
+        log.Fatalf("Failed to configure event sourcing: %v", err)
+    }
+
+    // Select where domain events, user notifications and operational alerts are delivered
+    events.Init(config.Config.EventsURL)
+    service.SubscribeEvents()
+    notifier.Init(config.Config.NotificationURL)
+    notifier.InitAlerts(config.Config.AlertWebhookURL)
+
+    // Emails go straight out through SMTP or SES when a provider is configured
+    if err := notifier.InitEmail(context.Background(), config.Config.Email); err != nil {
+        log.Fatalf("Failed to initialize email: %v", err)
+    }
+
+    // Push notifications go straight to users' devices through FCM and APNs when configured
+    if err := notifier.InitPush(config.Config.Push, service.NotificationStore{}); err != nil {
+        log.Fatalf("Failed to initialize push notifications: %v", err)
+    }
+    notifier.InitSMS(config.Config.SMS)
+
+    // Every notification is also kept for the apps' notification center
+    notifier.InitInbox(service.NotificationStore{})
+
+    // Payments are read from the payment-service for the nightly reconciliation, and tips
+    // are charged through it
+    payments.Init(config.Config.PaymentsURL)
+
+    // Walkers' positions are read from the tracking-service to check them in at the pickup
+    tracking.Init(config.Config.TrackingURL, config.Config.TrackingAPIKey)
+
+    // Pickup addresses booked without coordinates are placed by the geocoder
+    geocoding.Init(config.Config.GeocoderURL)
+
+    // Sales tax is charged at flat rates until a tax provider is integrated
+    tax.Init(config.Config.TaxRates)
+
+    // Walkers can connect Google and Outlook calendars their confirmed walks are written to
+    integrations.Init(config.Config.Calendars)
+
+    // Exchange rates let quotes and reports be shown in the requester's currency
+    exchange.Init(config.Config.ExchangeRatesURL, config.Config.ExchangeRatesTTL)
+
+    // Select where receipts of completed bookings are rendered and stored
+    if err := receipts.Init(context.Background(), config.Config.Receipts); err != nil {
+        log.Fatalf("Failed to initialize receipts: %v", err)
+    }
+
+    // Owners' booking attachments are encrypted with the attachment keys, and unavailable without them
+    if config.Config.AttachmentKeys != "" {
+        keys, err := encryption.ParseKeyring(config.Config.AttachmentKeys, config.Config.AttachmentKeyID)
+        if err != nil {
+            log.Fatalf("Failed to load attachment encryption keys: %v", err)
+        }
+        repository.UseAttachmentEncryption(keys)
+    }
+
+    // Screen walk notes with the configured term lists
+    moderation.Init(config.Config.Moderation)
+
+    // Load feature flag rules so features can be rolled out per tenant or percentage
+    flags, err := featureflags.Init(config.Config.FeatureFlags)
+    if err != nil {
+        log.Fatalf("Failed to initialize feature flags: %v", err)
     }
-    defer repository.Close()
 
-    // Initialize router and register routes
+    // Load the authorization policy shared with the other services
+    if _, err := policy.Init(config.Config.Policy); err != nil {
+        log.Fatalf("Failed to initialize authorization policy: %v", err)
+    }
+
+    // Initialize server and register routes
     // Addresses requirement 7.2.1: Core Components/Booking Service
-    router := http.NewServeMux()
+    router := bootstrap.New("booking-service", config.Config.ServicePort).
+        Use(bootstrap.Recover, clients.Tracing, bootstrap.RequestLogger, i18n.Middleware, middleware.ReplicaReads).
+        WithAdminPort(config.Config.AdminPort)
+
+    // Inject latency and errors into routes when enabled in a test environment, managed by admins
+    // from the admin port
+    if config.Config.FaultInjection {
+        injector := faults.New()
+        faultRules := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceFaults, policy.ActionUpdate)(injector.Handler().ServeHTTP)
+        router.Use(injector.Middleware).
+            HandleAdmin(faults.APIPath, faultRules).
+            HandleAdmin(faults.APIPath+"/", faultRules)
+    }
 
-    // Register booking endpoints
+    // Register booking endpoints; partner backends call them with an API key instead of a user token
+    bookingReaders := middleware.RequireUserOrAPIKey(config.Config.JWTSecret, service.AuthenticateAPIKeyService,
+        models.ScopeBookingsRead, policy.ResourceBookings, policy.ActionRead)
+    bookingWriters := middleware.RequireUserOrAPIKey(config.Config.JWTSecret, service.AuthenticateAPIKeyService,
+        models.ScopeBookingsWrite, policy.ResourceBookings, policy.ActionCreate)
+    createBooking := bookingWriters(handlers.CreateBookingHandler)
+    listBookings := bookingReaders(handlers.ListBookingsHandler)
     router.HandleFunc("/api/v1/bookings", func(w http.ResponseWriter, r *http.Request) {
         switch r.Method {
         case http.MethodPost:
-            handlers.CreateBookingHandler(w, r)
+            createBooking(w, r)
         case http.MethodGet:
-            handlers.GetBookingHandler(w, r)
+            listBookings(w, r)
         default:
             http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
         }
     })
 
-    // Configure server
-    server := &http.Server{
-        Addr:    fmt.Sprintf(":%d", config.Config.ServicePort),
-        Handler: router,
-    }
+    // Register per-booking endpoints, including the change approval workflow; owners patch
+    // their bookings and manage their attachments with a user token, and every action on a
+    // booking, as well as reading its dispute or receipt, is taken as the user of the token
+    patchBooking := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookings, policy.ActionUpdate)(handlers.PatchBookingHandler)
+    readAttachments := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookings, policy.ActionRead)(handlers.BookingAttachmentHandler)
+    writeAttachments := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookings, policy.ActionUpdate)(handlers.BookingAttachmentHandler)
+    bookingActions := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookings, policy.ActionUpdate)(handlers.BookingHandler)
+    bookingDispute := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookings, policy.ActionRead)(handlers.BookingHandler)
+    bookingReceipt := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookings, policy.ActionRead)(handlers.BookingHandler)
+    router.HandleFunc("/api/v1/bookings/", func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == http.MethodPatch {
+            patchBooking(w, r)
+            return
+        }
+        if handlers.IsBookingAttachmentPath(r.URL.Path) {
+            if r.Method == http.MethodGet {
+                readAttachments(w, r)
+                return
+            }
+            writeAttachments(w, r)
+            return
+        }
+        if r.Method == http.MethodPost {
+            bookingActions(w, r)
+            return
+        }
+        if handlers.IsBookingDisputePath(r.URL.Path) {
+            bookingDispute(w, r)
+            return
+        }
+        if handlers.IsBookingReceiptPath(r.URL.Path) {
+            bookingReceipt(w, r)
+            return
+        }
+        handlers.BookingHandler(w, r)
+    })
+
+    // Register availability endpoints; walkers publish their own windows with a user token
+    createAvailability := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceAvailability, policy.ActionCreate)(handlers.CreateAvailabilityHandler)
+    router.HandleFunc("/api/v1/availability", func(w http.ResponseWriter, r *http.Request) {
+        switch r.Method {
+        case http.MethodPost:
+            createAvailability(w, r)
+        case http.MethodGet:
+            handlers.ListAvailabilityHandler(w, r)
+        default:
+            http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        }
+    })
 
-    // Start server in a goroutine
-    go func() {
-        log.Printf("Starting Booking Service on port %d", config.Config.ServicePort)
-        if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-            log.Fatalf("Failed to start server: %v", err)
+    // Register walker rate plans and quotes priced from them; walkers set their own plan with a
+    // user token
+    saveRatePlan := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceRatePlans, policy.ActionUpdate)(handlers.SaveRatePlanHandler)
+    router.HandleFunc("/api/v1/rates", func(w http.ResponseWriter, r *http.Request) {
+        switch r.Method {
+        case http.MethodPut:
+            saveRatePlan(w, r)
+        case http.MethodGet:
+            handlers.GetRatePlanHandler(w, r)
+        default:
+            http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
         }
-    }()
+    })
+    // Owners checking out hold the slot quoted at its price with a user token
+    requireOwner := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookings, policy.ActionCreate)
+    holdQuote := requireOwner(handlers.QuoteRateHandler)
+    router.HandleFunc("/api/v1/rates/quote", func(w http.ResponseWriter, r *http.Request) {
+        switch r.Method {
+        case http.MethodGet:
+            handlers.QuoteRateHandler(w, r)
+        case http.MethodPost:
+            holdQuote(w, r)
+        default:
+            http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        }
+    })
+    router.HandleFunc("/api/v1/rates/holds/", requireOwner(methodHandler(http.MethodDelete, handlers.ReleaseSlotHoldHandler)))
+
+    // Register walkers' calendar feeds; the feed URL is signed so calendar apps need no token
+    requireWalker := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookings, policy.ActionRead)
+    router.HandleFunc("/api/v1/calendars/url", requireWalker(methodHandler(http.MethodGet, handlers.CalendarURLHandler)))
+    router.HandleFunc("/api/v1/calendars/", methodHandler(http.MethodGet, handlers.CalendarFeedHandler))
+
+    // Register walkers' connected calendars; the provider redirects back to the callback with a signed state
+    router.HandleFunc("/api/v1/integrations/calendars", requireWalker(handlers.CalendarIntegrationHandler))
+    router.HandleFunc("/api/v1/integrations/calendars/", requireWalker(handlers.CalendarIntegrationHandler))
+    router.HandleFunc("/api/v1/integrations/calendars/callback", methodHandler(http.MethodGet, handlers.CalendarCallbackHandler))
+
+    // Register users' notification centers, the devices they receive push notifications on,
+    // the number they are texted at, and their notification preferences
+    requireNotifications := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceNotifications, policy.ActionUpdate)
+    router.HandleFunc("/api/v1/notifications", requireNotifications(handlers.InboxHandler))
+    router.HandleFunc("/api/v1/notifications/", requireNotifications(handlers.InboxHandler))
+    router.HandleFunc("/api/v1/notifications/devices", requireNotifications(handlers.DeviceHandler))
+    router.HandleFunc("/api/v1/notifications/devices/", requireNotifications(handlers.DeviceHandler))
+    router.HandleFunc("/api/v1/notifications/preferences", requireNotifications(handlers.NotificationPreferencesHandler))
+    router.HandleFunc("/api/v1/notifications/sms", requireNotifications(handlers.SMSSettingsHandler))
+
+    // Replies to texts are posted by the messaging service and authenticated by its signature
+    router.HandleFunc("/api/v1/notifications/sms/inbound", methodHandler(http.MethodPost, handlers.InboundSMSHandler))
+
+    // Register referral endpoints; users get their own code and attribute their own signup with a
+    // user token, and only admins see the report
+    readReferrals := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceReferrals, policy.ActionRead)
+    createReferrals := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceReferrals, policy.ActionCreate)
+    readReferralReport := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceReports, policy.ActionRead)
+    router.HandleFunc("/api/v1/referrals/code", readReferrals(methodHandler(http.MethodGet, handlers.GetReferralCodeHandler)))
+    router.HandleFunc("/api/v1/referrals/signups", createReferrals(methodHandler(http.MethodPost, handlers.ReferralSignupHandler)))
+    router.HandleFunc("/api/v1/referrals/report", readReferralReport(methodHandler(http.MethodGet, handlers.ReferralReportHandler)))
+
+    // Register the service regions other services locate walks in
+    router.HandleFunc("/api/v1/regions", methodHandler(http.MethodGet, handlers.ListRegionsHandler))
 
-    // Set up graceful shutdown
-    stop := make(chan os.Signal, 1)
-    signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+    // Register walker earnings reports, including tips; walkers see their own
+    requireEarnings := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceEarnings, policy.ActionRead)
+    router.HandleFunc("/api/v1/earnings/report", requireEarnings(methodHandler(http.MethodGet, handlers.EarningsReportHandler)))
 
-    // Wait for interrupt signal
-    <-stop
-    log.Println("Shutting down server...")
+    // Register admin override endpoints
+    requireOverride := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookingOverrides, policy.ActionUpdate)
+    router.HandleFunc("/api/v1/admin/bookings/", requireOverride(handlers.AdminBookingHandler))
+    // Takes precedence over the per-booking routes under /api/v1/bookings/
+    router.HandleFunc("/api/v1/bookings/bulk-status", requireOverride(methodHandler(http.MethodPost, handlers.BulkStatusHandler)))
 
-    // Close database connection
-    if err := repository.Close(); err != nil {
-        log.Printf("Error closing database connection: %v", err)
+    // Register walker verification endpoints; only eligible walkers can be booked or assigned
+    requireVerifier := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceWalkerVerifications, policy.ActionUpdate)
+    router.HandleFunc("/api/v1/admin/walkers/", requireVerifier(handlers.AdminWalkerHandler))
+
+    // Register API key management for server-to-server clients
+    requireKeyAdmin := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceAPIKeys, policy.ActionUpdate)
+    router.HandleFunc("/api/v1/admin/api-keys", requireKeyAdmin(handlers.AdminAPIKeyHandler))
+    router.HandleFunc("/api/v1/admin/api-keys/", requireKeyAdmin(handlers.AdminAPIKeyHandler))
+
+    // Register the dispute review queue; resolving a dispute can refund the owner
+    requireDisputes := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceDisputes, policy.ActionUpdate)
+    router.HandleFunc("/api/v1/admin/disputes", requireDisputes(handlers.AdminDisputeHandler))
+    router.HandleFunc("/api/v1/admin/disputes/", requireDisputes(handlers.AdminDisputeHandler))
+
+    // Register the capacity report operations use to see where more walkers are needed
+    requireOperations := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceCapacity, policy.ActionRead)
+    router.HandleFunc("/api/v1/admin/capacity/report", requireOperations(methodHandler(http.MethodGet, handlers.AdminCapacityReportHandler)))
+
+    // Register the admin reports emailed on a schedule, their run history and re-sending them
+    requireReports := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceReports, policy.ActionRead)
+    requireReportRuns := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceReports, policy.ActionCreate)
+    router.HandleFunc("/api/v1/admin/reports/schedules", requireReports(methodHandler(http.MethodGet, handlers.AdminReportSchedulesHandler)))
+    router.HandleFunc("/api/v1/admin/reports/schedules/", requireReportRuns(methodHandler(http.MethodPost, handlers.RunReportHandler)))
+    router.HandleFunc("/api/v1/admin/reports/runs", requireReports(methodHandler(http.MethodGet, handlers.AdminReportRunsHandler)))
+
+    // Register the definition of service regions
+    requireRegions := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceRegions, policy.ActionUpdate)
+    router.HandleFunc("/api/v1/admin/regions/", requireRegions(handlers.AdminRegionHandler))
+
+    // Register the rules operations tune new bookings against, per region
+    requireRules := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceBookingRules, policy.ActionUpdate)
+    router.HandleFunc("/api/v1/admin/booking-rules/", requireRules(handlers.AdminBookingRulesHandler))
+
+    // Register each region's blackout dates and holidays
+    requireHolidays := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceHolidays, policy.ActionUpdate)
+    router.HandleFunc("/api/v1/admin/holidays/", requireHolidays(handlers.AdminHolidaysHandler))
+
+    // Register push delivery receipts support uses to find out why a notification did not arrive
+    requireSupport := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceDeliveryReceipts, policy.ActionRead)
+    router.HandleFunc("/api/v1/admin/notifications/receipts", requireSupport(methodHandler(http.MethodGet, handlers.AdminDeliveryReceiptsHandler)))
+
+    // Register the payment reconciliation reports produced by the nightly job
+    requireFinance := middleware.RequirePermission(config.Config.JWTSecret, policy.ResourceReconciliation, policy.ActionRead)
+    router.HandleFunc("/api/v1/admin/reconciliation", requireFinance(handlers.AdminReconciliationHandler))
+    router.HandleFunc("/api/v1/admin/reconciliation/", requireFinance(handlers.AdminReconciliationHandler))
+
+    // Expose Prometheus metrics, including how long each kind of database statement takes
+    router.Handle("/metrics", httpmetrics.Handler())
+
+    // Keep flag rules from the flag service current
+    if poller, ok := flags.(featureflags.Poller); ok {
+        router.Go("feature flags", poller.Run)
     }
 
-    log.Println("Server shutdown complete")
-}
\ No newline at end of file
+    // Take replicas that stop answering or fall behind out of rotation until they catch up
+    if len(config.Config.ReplicaURLs) > 0 {
+        router.Go("replica health checks", repository.RunReplicaHealthChecks)
+    }
+
+    // Report ready only while the database is reachable, and close it once requests have drained
+    router.ReadinessCheck("postgres", repository.Ping)
+    router.OnStop("postgres", func(ctx context.Context) error {
+        return repository.Close()
+    })
+
+    // Run the periodic jobs expiring unanswered booking changes, walker assignments and slot
+    // holds, reconciling each day's bookings against their payments and emailing the capacity and
+    // scheduled admin reports. Stop hooks run in reverse, so the jobs stop before the database
+    // they use is closed.
+    jobs := &service.BackgroundJobs{}
+    router.OnStart("background jobs", jobs.Start)
+    router.OnStop("background jobs", jobs.Stop)
+
+    if err := router.Run(); err != nil {
+        log.Fatalf("Booking Service stopped: %v", err)
+    }
+}
+
+// methodHandler restricts a handler to a single HTTP method
+func methodHandler(method string, handler http.HandlerFunc) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if r.Method != method {
+            http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+            return
+        }
+        handler(w, r)
+    }
+}
@@ -0,0 +1,110 @@
+// Package calendar renders walkers' bookings as iCalendar (RFC 5545) feeds that calendar
+// apps can subscribe to, and signs the feed URLs so only the walker's link can read them
+package calendar
+
+import (
+    "bytes"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ContentType is the media type of a rendered feed
+const ContentType = "text/calendar; charset=utf-8"
+
+// refreshInterval is how often subscribed calendar apps are asked to fetch the feed again
+const refreshInterval = "PT1H"
+
+// maxLineLength is the longest content line, in octets, before it is folded
+const maxLineLength = 75
+
+// timestampFormat is the UTC date-time format used for every timestamp in a feed
+const timestampFormat = "20060102T150405Z"
+
+// Token returns the token signing walkerID's feed URL. Changing secret revokes every URL
+// handed out before.
+func Token(secret, walkerID string) string {
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte("calendar:" + walkerID))
+    return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether token signs walkerID's feed URL
+func Verify(secret, walkerID, token string) bool {
+    return secret != "" && hmac.Equal([]byte(Token(secret, walkerID)), []byte(token))
+}
+
+// Render writes the bookings as a calendar with one event per walk, in order of start time
+func Render(name string, bookings []models.Booking, now time.Time) []byte {
+    sorted := append([]models.Booking(nil), bookings...)
+    sort.Slice(sorted, func(i, j int) bool { return sorted[i].ScheduledAt.Before(sorted[j].ScheduledAt) })
+
+    var buf bytes.Buffer
+    writeLine(&buf, "BEGIN:VCALENDAR")
+    writeLine(&buf, "VERSION:2.0")
+    writeLine(&buf, "PRODID:-//Dog Walking//Booking Service//EN")
+    writeLine(&buf, "CALSCALE:GREGORIAN")
+    writeLine(&buf, "METHOD:PUBLISH")
+    writeLine(&buf, "X-WR-CALNAME:"+escapeText(name))
+    writeLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:"+refreshInterval)
+    writeLine(&buf, "X-PUBLISHED-TTL:"+refreshInterval)
+
+    stamp := now.UTC().Format(timestampFormat)
+    for _, booking := range sorted {
+        writeLine(&buf, "BEGIN:VEVENT")
+        writeLine(&buf, "UID:"+booking.ID+"@booking-service")
+        writeLine(&buf, "DTSTAMP:"+stamp)
+        writeLine(&buf, "DTSTART:"+booking.ScheduledAt.UTC().Format(timestampFormat))
+        writeLine(&buf, "DTEND:"+booking.EndsAt().UTC().Format(timestampFormat))
+        writeLine(&buf, "SUMMARY:"+escapeText("Dog walk"))
+        writeLine(&buf, "DESCRIPTION:"+escapeText(describe(booking)))
+        if booking.Region != "" {
+            writeLine(&buf, "LOCATION:"+escapeText(booking.Region))
+        }
+        writeLine(&buf, "STATUS:CONFIRMED")
+        writeLine(&buf, "END:VEVENT")
+    }
+
+    writeLine(&buf, "END:VCALENDAR")
+    return buf.Bytes()
+}
+
+// describe returns the event description of a booking
+func describe(booking models.Booking) string {
+    minutes := booking.DurationMinutes
+    if minutes <= 0 {
+        minutes = models.DefaultDurationMinutes
+    }
+    return fmt.Sprintf("Booking %s\nDog %s\n%d minute walk", booking.ID, booking.DogID, minutes)
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(value string) string {
+    return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// writeLine writes a content line ended by CRLF, folding it onto continuation lines starting
+// with a space when it is longer than maxLineLength octets. Lines are only split between
+// UTF-8 characters.
+func writeLine(buf *bytes.Buffer, line string) {
+    limit := maxLineLength
+    for len(line) > limit {
+        cut := limit
+        for cut > 0 && line[cut]&0xC0 == 0x80 {
+            cut--
+        }
+        buf.WriteString(line[:cut])
+        buf.WriteString("\r\n ")
+        line = line[cut:]
+        // The leading space of a continuation line counts towards its length
+        limit = maxLineLength - 1
+    }
+    buf.WriteString(line)
+    buf.WriteString("\r\n")
+}
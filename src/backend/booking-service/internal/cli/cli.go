@@ -0,0 +1,114 @@
+// Package cli holds the commands of the booking admin CLI. They work directly against the
+// configured repository, so they keep working when the service itself is unavailable.
+package cli
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "os/user"
+    "text/tabwriter"
+    "time"
+
+    "github.com/spf13/cobra" // v1.6.1
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// ListBookingsCommand prints bookings matching the filter flags, most recently scheduled first
+func ListBookingsCommand() *cobra.Command {
+    var (
+        status   string
+        walkerID string
+        ownerID  string
+        region   string
+        limit    int
+        asJSON   bool
+    )
+
+    cmd := &cobra.Command{
+        Use:   "list-bookings",
+        Short: "List bookings, most recently scheduled first",
+        Args:  cobra.NoArgs,
+        RunE: func(cmd *cobra.Command, args []string) error {
+            if status != "" && !models.BookingStatus(status).IsValid() {
+                return fmt.Errorf("unknown status %q", status)
+            }
+            if limit < 1 {
+                return fmt.Errorf("limit must be at least 1")
+            }
+
+            bookings, err := repository.ListBookings(cmd.Context(), models.BookingFilter{
+                Status:   models.BookingStatus(status),
+                WalkerID: walkerID,
+                OwnerID:  ownerID,
+                Region:   service.NormalizeRegion(region),
+            }, nil, limit)
+            if err != nil {
+                return err
+            }
+
+            if asJSON {
+                encoder := json.NewEncoder(cmd.OutOrStdout())
+                encoder.SetIndent("", "  ")
+                return encoder.Encode(bookings)
+            }
+
+            w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+            fmt.Fprintln(w, "ID\tSTATUS\tSCHEDULED\tMINUTES\tREGION\tOWNER\tWALKER\tAMOUNT")
+            for _, b := range bookings {
+                fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%.2f\n",
+                    b.ID, b.Status, b.ScheduledAt.Format(time.RFC3339), b.DurationMinutes, b.Region, b.OwnerID, b.WalkerID, b.Amount)
+            }
+            return w.Flush()
+        },
+    }
+
+    cmd.Flags().StringVar(&status, "status", "", "only bookings with this status")
+    cmd.Flags().StringVar(&walkerID, "walker", "", "only bookings assigned to this walker")
+    cmd.Flags().StringVar(&ownerID, "owner", "", "only bookings made by this owner")
+    cmd.Flags().StringVar(&region, "region", "", "only bookings in this service region")
+    cmd.Flags().IntVar(&limit, "limit", 50, "maximum number of bookings to list")
+    cmd.Flags().BoolVar(&asJSON, "json", false, "print bookings as JSON")
+    return cmd
+}
+
+// ForceStatusCommand overrides a booking's status, recording the change in the audit log
+func ForceStatusCommand() *cobra.Command {
+    var (
+        reason string
+        actor  string
+    )
+
+    cmd := &cobra.Command{
+        Use:   "force-status <booking-id> <status>",
+        Short: "Override a booking's status; the change is written to the audit log",
+        Args:  cobra.ExactArgs(2),
+        RunE: func(cmd *cobra.Command, args []string) error {
+            ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+            defer cancel()
+
+            booking, err := service.ForceStatusService(ctx, actor, args[0], models.BookingStatus(args[1]), reason)
+            if err != nil {
+                return err
+            }
+            fmt.Fprintf(cmd.OutOrStdout(), "Booking %s is now %s\n", booking.ID, booking.Status)
+            return nil
+        },
+    }
+
+    cmd.Flags().StringVar(&reason, "reason", "", "why the status is being overridden (required)")
+    cmd.Flags().StringVar(&actor, "actor", defaultActor(), "who is making the change, as recorded in the audit log")
+    cmd.MarkFlagRequired("reason")
+    return cmd
+}
+
+// defaultActor identifies the operator running the tool in audit entries
+func defaultActor() string {
+    if current, err := user.Current(); err == nil {
+        return "cli:" + current.Username
+    }
+    return "cli"
+}
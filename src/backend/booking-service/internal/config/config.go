@@ -5,18 +5,208 @@ package config
 
 import (
 	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/sirupsen/logrus" // v1.9.0
 	"github.com/spf13/viper"     // v1.10.1
+
+	"src/backend/booking-service/internal/integrations"
+	"src/backend/booking-service/internal/models"
+	"src/backend/booking-service/internal/notifier"
+	"src/backend/booking-service/internal/receipts"
+	"src/backend/booking-service/internal/surge"
+	"src/backend/booking-service/internal/tax"
+	"src/backend/shared/faults"
+	"src/backend/shared/featureflags"
+	"src/backend/shared/moderation"
+	"src/backend/shared/policy"
+)
+
+// Store backends selectable with STORE
+const (
+	// StorePostgres keeps data in PostgreSQL; the default
+	StorePostgres = "postgres"
+
+	// StoreMemory keeps data in process memory, for local development and CI without a database
+	StoreMemory = "memory"
 )
 
 // Config holds the configuration settings for the Booking Service
 // Addresses requirement 7.2.1: Booking System Initialization
 type Config struct {
+	// Store selects the persistence backend: StorePostgres or StoreMemory
+	Store string
+
 	// DatabaseURL is the connection string for the PostgreSQL database
 	DatabaseURL string
 
+	// Migrate applies pending schema migrations at startup
+	Migrate bool
+
+	// EventSourcing records every change to a booking as an event and reads bookings back by
+	// folding their events, keeping each booking's full history
+	EventSourcing bool
+
+	// SnapshotInterval is how many events a read folds before it snapshots the booking's state
+	SnapshotInterval int
+
+	// ReplicaURLs are connection strings for read replicas of the database; bookings are only
+	// read from the primary when empty
+	ReplicaURLs []string
+
+	// ReplicaMaxLag is how far behind the primary a replica may fall before reads stop going to it
+	ReplicaMaxLag time.Duration
+
+	// SlowQueryThreshold is how long a database statement may take before it is logged as slow;
+	// negative logs none
+	SlowQueryThreshold time.Duration
+
 	// ServicePort is the port number on which the service will listen
 	ServicePort int
+
+	// AdminPort serves pprof profiles and expvar variables apart from the API; zero serves none
+	AdminPort int
+
+	// FaultInjection enables injecting latency and errors into routes through the admin port;
+	// never enable it in production
+	FaultInjection bool
+
+	// ChangeRequestTTL is how long a walker has to answer a proposed booking change
+	ChangeRequestTTL time.Duration
+
+	// QuoteHoldTTL is how long a quoted walk's slot and price are held for the owner checking out
+	QuoteHoldTTL time.Duration
+
+	// ReferralCredit is the credit granted to a referrer when a referred user makes their first booking
+	ReferralCredit float64
+
+	// EventsURL is where domain events are posted; events are only logged when empty
+	EventsURL string
+
+	// JWTSecret verifies tokens issued by the auth-service; admin and booking endpoints reject every
+	// request without an API key when empty
+	JWTSecret string
+
+	// NotificationURL is the notification-service base URL; notifications are only logged when empty
+	NotificationURL string
+
+	// AssignmentAcceptWindow is the walker response SLA: how long an assigned walker has to accept
+	// before the booking is offered to another walker, or cancelled if none is available
+	AssignmentAcceptWindow time.Duration
+
+	// FeatureFlags selects where feature flag rules are read from
+	FeatureFlags featureflags.Options
+
+	// Policy selects where authorization rules are read from; the built-in matrix when empty
+	Policy policy.Options
+
+	// PaymentsURL is the payment-service base URL; payment reconciliation is skipped when empty
+	PaymentsURL string
+
+	// ReconciliationAlertThreshold is how many discrepancies a nightly reconciliation may find
+	// before an alert is raised
+	ReconciliationAlertThreshold int
+
+	// AlertWebhookURL receives operational alerts; alerts are only logged when empty
+	AlertWebhookURL string
+
+	// Receipts selects where receipts are stored and how they are rendered
+	Receipts receipts.Options
+
+	// ExchangeRatesURL is the exchange rates API used to show amounts in other currencies;
+	// conversion is unavailable when empty
+	ExchangeRatesURL string
+
+	// ExchangeRatesTTL is how long fetched exchange rates are reused
+	ExchangeRatesTTL time.Duration
+
+	// TipWindow is how long after a walk ends its owner can tip the walker
+	TipWindow time.Duration
+
+	// CancellationPolicy is the fee owners are charged for cancelling a walk, by how much notice
+	// they give
+	CancellationPolicy models.CancellationPolicy
+
+	// TrackingURL is the tracking-service base URL; walkers cannot check in to walks when empty
+	TrackingURL string
+
+	// TrackingAPIKey is the tracking-service's service API key walkers' positions and walks are
+	// read with
+	TrackingAPIKey string
+
+	// GeocoderURL is a Nominatim-compatible geocoding API pickup addresses are resolved to
+	// coordinates with; bookings made with only an address have no coordinates when empty
+	GeocoderURL string
+
+	// ServiceAreaEnforced rejects bookings whose pickup lies outside every service region. It
+	// has no effect until regions are defined, nor on bookings without pickup coordinates.
+	ServiceAreaEnforced bool
+
+	// Surge tunes how prices rise in region hours with more dogs booked than walkers available
+	Surge surge.Options
+
+	// CheckInRadius is how close, in meters, a walker's last tracked position must be to a
+	// booking's pickup for them to check in
+	CheckInRadius float64
+
+	// CheckInPositionMaxAge is how recently a walker's position must have been tracked for them
+	// to check in with it
+	CheckInPositionMaxAge time.Duration
+
+	// Completion is the proof of the walk that must have been tracked before a walker checking
+	// out completes the booking; nothing is required when all are zero
+	Completion models.CompletionRequirements
+
+	// TaxRates are the flat sales tax rates charged on every booking; no tax is charged when empty
+	TaxRates []tax.Rate
+
+	// CapacityReportRecipients are the addresses the daily capacity report is emailed to; the
+	// report is only available on request when empty
+	CapacityReportRecipients []string
+
+	// CapacityReportHorizon is how far ahead the daily capacity report looks
+	CapacityReportHorizon time.Duration
+
+	// ReportSchedules are the admin reports emailed whenever their cron expression fires
+	ReportSchedules []models.ReportSchedule
+
+	// CalendarSecret signs the URLs of walkers' calendar feeds and their requests to connect
+	// external calendars; both are unavailable when empty. Changing it revokes every feed URL
+	// handed out.
+	CalendarSecret string
+
+	// AttachmentKeys lists the id:base64 256-bit keys owners' booking attachments are encrypted
+	// with; attachments are unavailable when empty
+	AttachmentKeys string
+
+	// AttachmentKeyID selects which of AttachmentKeys new attachments are encrypted with
+	AttachmentKeyID string
+
+	// AttachmentRetention is how long after a finished booking's scheduled end its attachments
+	// are kept before they are purged
+	AttachmentRetention time.Duration
+
+	// Calendars configures the OAuth clients walkers connect Google and Outlook calendars with
+	Calendars integrations.Options
+
+	// Push configures sending push notifications directly through FCM and APNs; they go
+	// through the notification-service when no credentials are set
+	Push notifier.PushOptions
+
+	// SMS configures texting owners through a Twilio-compatible messaging API; texts are only
+	// logged when no account is set
+	SMS notifier.SMSOptions
+
+	// Email configures sending emails directly through SMTP or SES; they go through the
+	// notification-service when no provider is set
+	Email notifier.EmailOptions
+
+	// Moderation lists the terms that get walk notes rejected or flagged for review
+	Moderation moderation.Options
 }
 
 // Global configuration instance
@@ -33,8 +223,85 @@ func LoadConfig() error {
 	v := viper.New()
 
 	// Set configuration defaults
+	v.SetDefault("store", StorePostgres)
 	v.SetDefault("database.url", "postgres://localhost:5432/booking_service")
+	v.SetDefault("database.migrate", false)
+	v.SetDefault("database.event_sourcing", false)
+	v.SetDefault("database.snapshot_interval", 50)
+	v.SetDefault("database.replica_urls", "")
+	v.SetDefault("database.replica_max_lag", "5s")
+	v.SetDefault("database.slow_query_threshold", "500ms")
 	v.SetDefault("service.port", 8080)
+	v.SetDefault("service.admin_port", 0)
+	v.SetDefault("service.fault_injection", false)
+	v.SetDefault("booking.change_request_ttl", 24*time.Hour)
+	v.SetDefault("booking.quote_hold_ttl", 10*time.Minute)
+	v.SetDefault("referral.credit", 10.0)
+	v.SetDefault("events.url", "")
+	v.SetDefault("auth.jwt_secret", "")
+	v.SetDefault("notification.url", "")
+	v.SetDefault("booking.assignment_accept_window", 2*time.Hour)
+	v.SetDefault("features.file", "")
+	v.SetDefault("features.url", "")
+	v.SetDefault("features.sdk_key", "")
+	v.SetDefault("features.poll_interval", 30*time.Second)
+	v.SetDefault("policy.file", "")
+	v.SetDefault("policy.opa_url", "")
+	v.SetDefault("policy.opa_path", "")
+	v.SetDefault("payments.url", "")
+	v.SetDefault("reconciliation.alert_threshold", 0)
+	v.SetDefault("alerts.webhook_url", "")
+	v.SetDefault("receipts.bucket", "")
+	v.SetDefault("receipts.endpoint", "")
+	v.SetDefault("receipts.dir", filepath.Join(os.TempDir(), "booking-receipts"))
+	v.SetDefault("receipts.renderer_url", "")
+	v.SetDefault("tax.rates", "")
+	v.SetDefault("booking.tip_window", 72*time.Hour)
+	v.SetDefault("booking.cancellation_policy", "")
+	v.SetDefault("tracking.url", "")
+	v.SetDefault("tracking.api_key", "")
+	v.SetDefault("geocoder.url", "")
+	v.SetDefault("booking.service_area_enforced", true)
+	v.SetDefault("surge.threshold", 1.0)
+	v.SetDefault("surge.sensitivity", 0.5)
+	v.SetDefault("surge.max", 2.0)
+	v.SetDefault("booking.check_in_radius", 250.0)
+	v.SetDefault("booking.check_in_position_max_age", 10*time.Minute)
+	v.SetDefault("booking.completion_min_duration", time.Duration(0))
+	v.SetDefault("booking.completion_min_distance", 0.0)
+	v.SetDefault("booking.completion_min_photos", 0)
+	v.SetDefault("exchange.url", "")
+	v.SetDefault("exchange.ttl", time.Hour)
+	v.SetDefault("capacity.report_recipients", "")
+	v.SetDefault("capacity.report_horizon", 7*24*time.Hour)
+	v.SetDefault("reports.schedules", "")
+	v.SetDefault("calendar.secret", "")
+	v.SetDefault("calendar.redirect_url", "")
+	v.SetDefault("attachments.keys", "")
+	v.SetDefault("attachments.key_id", "")
+	v.SetDefault("attachments.retention", 7*24*time.Hour)
+	v.SetDefault("calendar.google_client_id", "")
+	v.SetDefault("calendar.google_client_secret", "")
+	v.SetDefault("calendar.microsoft_client_id", "")
+	v.SetDefault("calendar.microsoft_client_secret", "")
+	v.SetDefault("push.fcm_credentials_file", "")
+	v.SetDefault("push.apns_key_file", "")
+	v.SetDefault("push.apns_key_id", "")
+	v.SetDefault("push.apns_team_id", "")
+	v.SetDefault("push.apns_topic", "")
+	v.SetDefault("push.apns_sandbox", false)
+	v.SetDefault("sms.account_sid", "")
+	v.SetDefault("sms.auth_token", "")
+	v.SetDefault("sms.api_url", "")
+	v.SetDefault("sms.senders", "")
+	v.SetDefault("sms.webhook_url", "")
+	v.SetDefault("email.provider", "")
+	v.SetDefault("email.from", "")
+	v.SetDefault("email.smtp_host", "")
+	v.SetDefault("email.smtp_port", 587)
+	v.SetDefault("email.smtp_username", "")
+	v.SetDefault("email.smtp_password", "")
+	v.SetDefault("email.ses_region", "")
 
 	// Set configuration file settings
 	v.SetConfigName("config")        // config file name without extension
@@ -45,8 +312,87 @@ func LoadConfig() error {
 	// Enable environment variable support
 	v.AutomaticEnv()
 	v.SetEnvPrefix("BOOKING")
+	v.BindEnv("store", "BOOKING_STORE", "STORE")
+	v.BindEnv("database.event_sourcing", "BOOKING_EVENT_SOURCING")
+	v.BindEnv("database.snapshot_interval", "BOOKING_SNAPSHOT_INTERVAL")
 	v.BindEnv("database.url", "BOOKING_DATABASE_URL")
+	v.BindEnv("database.migrate", "BOOKING_DATABASE_MIGRATE")
+	v.BindEnv("database.replica_urls", "BOOKING_DATABASE_REPLICA_URLS")
+	v.BindEnv("database.replica_max_lag", "BOOKING_DATABASE_REPLICA_MAX_LAG")
+	v.BindEnv("database.slow_query_threshold", "BOOKING_SLOW_QUERY_THRESHOLD")
 	v.BindEnv("service.port", "BOOKING_SERVICE_PORT")
+	v.BindEnv("service.admin_port", "BOOKING_ADMIN_PORT")
+	v.BindEnv("service.fault_injection", "BOOKING_FAULT_INJECTION")
+	v.BindEnv("booking.change_request_ttl", "BOOKING_CHANGE_REQUEST_TTL")
+	v.BindEnv("booking.quote_hold_ttl", "BOOKING_QUOTE_HOLD_TTL")
+	v.BindEnv("referral.credit", "BOOKING_REFERRAL_CREDIT")
+	v.BindEnv("events.url", "BOOKING_EVENTS_URL")
+	v.BindEnv("auth.jwt_secret", "BOOKING_JWT_SECRET", "JWT_SECRET")
+	v.BindEnv("notification.url", "BOOKING_NOTIFICATION_URL")
+	v.BindEnv("booking.assignment_accept_window", "BOOKING_ASSIGNMENT_ACCEPT_WINDOW")
+	v.BindEnv("features.file", "BOOKING_FEATURE_FLAGS_FILE")
+	v.BindEnv("features.url", "BOOKING_FEATURE_FLAGS_URL")
+	v.BindEnv("features.sdk_key", "BOOKING_FEATURE_FLAGS_SDK_KEY")
+	v.BindEnv("features.poll_interval", "BOOKING_FEATURE_FLAGS_POLL_INTERVAL")
+	v.BindEnv("policy.file", "BOOKING_POLICY_FILE")
+	v.BindEnv("policy.opa_url", "BOOKING_POLICY_OPA_URL")
+	v.BindEnv("policy.opa_path", "BOOKING_POLICY_OPA_PATH")
+	v.BindEnv("payments.url", "BOOKING_PAYMENTS_URL")
+	v.BindEnv("reconciliation.alert_threshold", "BOOKING_RECONCILIATION_ALERT_THRESHOLD")
+	v.BindEnv("alerts.webhook_url", "BOOKING_ALERT_WEBHOOK_URL")
+	v.BindEnv("receipts.bucket", "BOOKING_RECEIPTS_BUCKET")
+	v.BindEnv("receipts.endpoint", "BOOKING_RECEIPTS_S3_ENDPOINT")
+	v.BindEnv("receipts.dir", "BOOKING_RECEIPTS_DIR")
+	v.BindEnv("receipts.renderer_url", "BOOKING_RECEIPT_RENDERER_URL")
+	v.BindEnv("tax.rates", "BOOKING_TAX_RATES")
+	v.BindEnv("booking.tip_window", "BOOKING_TIP_WINDOW")
+	v.BindEnv("booking.cancellation_policy", "BOOKING_CANCELLATION_POLICY")
+	v.BindEnv("tracking.url", "BOOKING_TRACKING_URL")
+	v.BindEnv("tracking.api_key", "BOOKING_TRACKING_API_KEY")
+	v.BindEnv("geocoder.url", "BOOKING_GEOCODER_URL")
+	v.BindEnv("booking.service_area_enforced", "BOOKING_SERVICE_AREA_ENFORCED")
+	v.BindEnv("surge.threshold", "BOOKING_SURGE_THRESHOLD")
+	v.BindEnv("surge.sensitivity", "BOOKING_SURGE_SENSITIVITY")
+	v.BindEnv("surge.max", "BOOKING_SURGE_MAX")
+	v.BindEnv("booking.check_in_radius", "BOOKING_CHECK_IN_RADIUS")
+	v.BindEnv("booking.check_in_position_max_age", "BOOKING_CHECK_IN_POSITION_MAX_AGE")
+	v.BindEnv("booking.completion_min_duration", "BOOKING_COMPLETION_MIN_DURATION")
+	v.BindEnv("booking.completion_min_distance", "BOOKING_COMPLETION_MIN_DISTANCE")
+	v.BindEnv("booking.completion_min_photos", "BOOKING_COMPLETION_MIN_PHOTOS")
+	v.BindEnv("exchange.url", "BOOKING_EXCHANGE_RATES_URL")
+	v.BindEnv("exchange.ttl", "BOOKING_EXCHANGE_RATES_TTL")
+	v.BindEnv("capacity.report_recipients", "BOOKING_CAPACITY_REPORT_RECIPIENTS")
+	v.BindEnv("capacity.report_horizon", "BOOKING_CAPACITY_REPORT_HORIZON")
+	v.BindEnv("reports.schedules", "BOOKING_REPORT_SCHEDULES")
+	v.BindEnv("calendar.secret", "BOOKING_CALENDAR_SECRET")
+	v.BindEnv("calendar.redirect_url", "BOOKING_CALENDAR_REDIRECT_URL")
+	v.BindEnv("attachments.keys", "BOOKING_ATTACHMENT_KEYS")
+	v.BindEnv("attachments.key_id", "BOOKING_ATTACHMENT_KEY_ID")
+	v.BindEnv("attachments.retention", "BOOKING_ATTACHMENT_RETENTION")
+	v.BindEnv("calendar.google_client_id", "BOOKING_GOOGLE_CLIENT_ID")
+	v.BindEnv("calendar.google_client_secret", "BOOKING_GOOGLE_CLIENT_SECRET")
+	v.BindEnv("calendar.microsoft_client_id", "BOOKING_MICROSOFT_CLIENT_ID")
+	v.BindEnv("calendar.microsoft_client_secret", "BOOKING_MICROSOFT_CLIENT_SECRET")
+	v.BindEnv("push.fcm_credentials_file", "BOOKING_FCM_CREDENTIALS_FILE")
+	v.BindEnv("push.apns_key_file", "BOOKING_APNS_KEY_FILE")
+	v.BindEnv("push.apns_key_id", "BOOKING_APNS_KEY_ID")
+	v.BindEnv("push.apns_team_id", "BOOKING_APNS_TEAM_ID")
+	v.BindEnv("push.apns_topic", "BOOKING_APNS_TOPIC")
+	v.BindEnv("push.apns_sandbox", "BOOKING_APNS_SANDBOX")
+	v.BindEnv("sms.account_sid", "BOOKING_SMS_ACCOUNT_SID")
+	v.BindEnv("sms.auth_token", "BOOKING_SMS_AUTH_TOKEN")
+	v.BindEnv("sms.api_url", "BOOKING_SMS_API_URL")
+	v.BindEnv("sms.senders", "BOOKING_SMS_SENDERS")
+	v.BindEnv("sms.webhook_url", "BOOKING_SMS_WEBHOOK_URL")
+	v.BindEnv("email.provider", "BOOKING_EMAIL_PROVIDER")
+	v.BindEnv("email.from", "BOOKING_EMAIL_FROM")
+	v.BindEnv("email.smtp_host", "BOOKING_SMTP_HOST")
+	v.BindEnv("email.smtp_port", "BOOKING_SMTP_PORT")
+	v.BindEnv("email.smtp_username", "BOOKING_SMTP_USERNAME")
+	v.BindEnv("email.smtp_password", "BOOKING_SMTP_PASSWORD")
+	v.BindEnv("email.ses_region", "BOOKING_SES_REGION")
+	v.BindEnv("moderation.blocked_terms", "BOOKING_MODERATION_BLOCKED_TERMS")
+	v.BindEnv("moderation.flagged_terms", "BOOKING_MODERATION_FLAGGED_TERMS")
 
 	// Read configuration file
 	if err = v.ReadInConfig(); err != nil {
@@ -58,10 +404,131 @@ func LoadConfig() error {
 		logger.Info("No config file found, using environment variables and defaults")
 	}
 
+	taxRates, err := tax.ParseRates(v.GetString("tax.rates"))
+	if err != nil {
+		logger.WithError(err).Error("Configuration validation failed")
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+	cancellationPolicy, err := models.ParseCancellationPolicy(v.GetString("booking.cancellation_policy"))
+	if err != nil {
+		logger.WithError(err).Error("Configuration validation failed")
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+	smsSenders, err := notifier.ParseSMSSenders(v.GetString("sms.senders"))
+	if err != nil {
+		logger.WithError(err).Error("Configuration validation failed")
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+	reportSchedules, err := models.ParseReportSchedules(v.GetString("reports.schedules"))
+	if err != nil {
+		logger.WithError(err).Error("Configuration validation failed")
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
 	// Create new Config instance
 	Config = &Config{
-		DatabaseURL: v.GetString("database.url"),
-		ServicePort: v.GetInt("service.port"),
+		Store:                  v.GetString("store"),
+		DatabaseURL:            v.GetString("database.url"),
+		Migrate:                v.GetBool("database.migrate"),
+		EventSourcing:          v.GetBool("database.event_sourcing"),
+		SnapshotInterval:       v.GetInt("database.snapshot_interval"),
+		ReplicaURLs:            splitList(v.GetString("database.replica_urls")),
+		ReplicaMaxLag:          v.GetDuration("database.replica_max_lag"),
+		SlowQueryThreshold:     v.GetDuration("database.slow_query_threshold"),
+		ServicePort:            v.GetInt("service.port"),
+		AdminPort:              v.GetInt("service.admin_port"),
+		FaultInjection:         v.GetBool("service.fault_injection"),
+		ChangeRequestTTL:       v.GetDuration("booking.change_request_ttl"),
+		QuoteHoldTTL:           v.GetDuration("booking.quote_hold_ttl"),
+		ReferralCredit:         v.GetFloat64("referral.credit"),
+		EventsURL:              v.GetString("events.url"),
+		JWTSecret:              v.GetString("auth.jwt_secret"),
+		NotificationURL:        v.GetString("notification.url"),
+		AssignmentAcceptWindow: v.GetDuration("booking.assignment_accept_window"),
+		FeatureFlags: featureflags.Options{
+			File:         v.GetString("features.file"),
+			URL:          v.GetString("features.url"),
+			SDKKey:       v.GetString("features.sdk_key"),
+			PollInterval: v.GetDuration("features.poll_interval"),
+		},
+		Policy: policy.Options{
+			File:    v.GetString("policy.file"),
+			OPAURL:  v.GetString("policy.opa_url"),
+			OPAPath: v.GetString("policy.opa_path"),
+		},
+		PaymentsURL:                  v.GetString("payments.url"),
+		ReconciliationAlertThreshold: v.GetInt("reconciliation.alert_threshold"),
+		AlertWebhookURL:              v.GetString("alerts.webhook_url"),
+		Receipts: receipts.Options{
+			Bucket:      v.GetString("receipts.bucket"),
+			Endpoint:    v.GetString("receipts.endpoint"),
+			Dir:         v.GetString("receipts.dir"),
+			RendererURL: v.GetString("receipts.renderer_url"),
+		},
+		Surge: surge.Options{
+			Threshold:   v.GetFloat64("surge.threshold"),
+			Sensitivity: v.GetFloat64("surge.sensitivity"),
+			Max:         v.GetFloat64("surge.max"),
+		},
+		TipWindow:             v.GetDuration("booking.tip_window"),
+		CancellationPolicy:    cancellationPolicy,
+		TrackingURL:           v.GetString("tracking.url"),
+		TrackingAPIKey:        v.GetString("tracking.api_key"),
+		GeocoderURL:           v.GetString("geocoder.url"),
+		ServiceAreaEnforced:   v.GetBool("booking.service_area_enforced"),
+		CheckInRadius:         v.GetFloat64("booking.check_in_radius"),
+		CheckInPositionMaxAge: v.GetDuration("booking.check_in_position_max_age"),
+		Completion: models.CompletionRequirements{
+			MinDuration: v.GetDuration("booking.completion_min_duration"),
+			MinDistance: v.GetFloat64("booking.completion_min_distance"),
+			MinPhotos:   v.GetInt("booking.completion_min_photos"),
+		},
+		TaxRates:              taxRates,
+		ExchangeRatesURL:      v.GetString("exchange.url"),
+		ExchangeRatesTTL:      v.GetDuration("exchange.ttl"),
+
+		CapacityReportRecipients: splitList(v.GetString("capacity.report_recipients")),
+		CapacityReportHorizon:    v.GetDuration("capacity.report_horizon"),
+		ReportSchedules:          reportSchedules,
+		CalendarSecret:           v.GetString("calendar.secret"),
+		AttachmentKeys:           v.GetString("attachments.keys"),
+		AttachmentKeyID:          v.GetString("attachments.key_id"),
+		AttachmentRetention:      v.GetDuration("attachments.retention"),
+		Calendars: integrations.Options{
+			GoogleClientID:        v.GetString("calendar.google_client_id"),
+			GoogleClientSecret:    v.GetString("calendar.google_client_secret"),
+			MicrosoftClientID:     v.GetString("calendar.microsoft_client_id"),
+			MicrosoftClientSecret: v.GetString("calendar.microsoft_client_secret"),
+			RedirectURL:           v.GetString("calendar.redirect_url"),
+		},
+		Push: notifier.PushOptions{
+			FCMCredentialsFile: v.GetString("push.fcm_credentials_file"),
+			APNsKeyFile:        v.GetString("push.apns_key_file"),
+			APNsKeyID:          v.GetString("push.apns_key_id"),
+			APNsTeamID:         v.GetString("push.apns_team_id"),
+			APNsTopic:          v.GetString("push.apns_topic"),
+			APNsSandbox:        v.GetBool("push.apns_sandbox"),
+		},
+		SMS: notifier.SMSOptions{
+			AccountSID: v.GetString("sms.account_sid"),
+			AuthToken:  v.GetString("sms.auth_token"),
+			APIURL:     v.GetString("sms.api_url"),
+			Senders:    smsSenders,
+			WebhookURL: v.GetString("sms.webhook_url"),
+		},
+		Email: notifier.EmailOptions{
+			Provider:     v.GetString("email.provider"),
+			From:         v.GetString("email.from"),
+			SMTPHost:     v.GetString("email.smtp_host"),
+			SMTPPort:     v.GetInt("email.smtp_port"),
+			SMTPUsername: v.GetString("email.smtp_username"),
+			SMTPPassword: v.GetString("email.smtp_password"),
+			SESRegion:    v.GetString("email.ses_region"),
+		},
+		Moderation: moderation.Options{
+			BlockedTerms: splitList(v.GetString("moderation.blocked_terms")),
+			FlaggedTerms: splitList(v.GetString("moderation.flagged_terms")),
+		},
 	}
 
 	// Validate configuration
@@ -72,8 +539,34 @@ func LoadConfig() error {
 
 	logger.WithFields(logrus.Fields{
 		"servicePort": Config.ServicePort,
+		"adminPort":   Config.AdminPort,
+		"store":       Config.Store,
 		// Mask sensitive database URL
 		"databaseConfigured": Config.DatabaseURL != "",
+		"eventSourcing":      Config.EventSourcing,
+		"readReplicas":       len(Config.ReplicaURLs),
+		"jwtConfigured":      Config.JWTSecret != "",
+		"featureFlagService": Config.FeatureFlags.URL != "",
+		"policyServer":       Config.Policy.OPAURL != "",
+		"reconciliation":     Config.PaymentsURL != "",
+		"checkIn":            Config.TrackingURL != "",
+		"geocoder":           Config.GeocoderURL != "",
+		"serviceArea":        Config.ServiceAreaEnforced,
+		"surgePricing":       Config.Surge.Enabled(),
+		"proofOfCompletion":  Config.Completion.Any(),
+		"receiptBucket":      Config.Receipts.Bucket != "",
+		"taxRates":           len(Config.TaxRates),
+		"exchangeRates":      Config.ExchangeRatesURL != "",
+		"capacityReports":    len(Config.CapacityReportRecipients),
+		"scheduledReports":   len(Config.ReportSchedules),
+		"calendarFeeds":      Config.CalendarSecret != "",
+		"attachments":        Config.AttachmentKeys != "",
+		"calendarSync":       Config.Calendars.GoogleClientID != "" || Config.Calendars.MicrosoftClientID != "",
+		"fcm":                Config.Push.FCMCredentialsFile != "",
+		"apns":               Config.Push.APNsKeyFile != "",
+		"sms":                Config.SMS.AccountSID != "",
+		"emailProvider":      Config.Email.Provider,
+		"faultInjection":     Config.FaultInjection,
 	}).Info("Configuration loaded successfully")
 
 	return nil
@@ -81,13 +574,148 @@ func LoadConfig() error {
 
 // validateConfig performs validation checks on the configuration values
 func validateConfig(cfg *Config) error {
-	if cfg.DatabaseURL == "" {
+	if cfg.Store != StorePostgres && cfg.Store != StoreMemory {
+		return fmt.Errorf("store must be %q or %q", StorePostgres, StoreMemory)
+	}
+
+	if cfg.Store == StorePostgres && cfg.DatabaseURL == "" {
 		return fmt.Errorf("database URL is required")
 	}
 
+	if cfg.EventSourcing && cfg.SnapshotInterval < 1 {
+		return fmt.Errorf("snapshot interval must be at least 1")
+	}
+
+	if len(cfg.ReplicaURLs) > 0 && cfg.ReplicaMaxLag <= 0 {
+		return fmt.Errorf("replica max lag must be positive")
+	}
+
 	if cfg.ServicePort < 1 || cfg.ServicePort > 65535 {
 		return fmt.Errorf("service port must be between 1 and 65535")
 	}
 
+	if cfg.AdminPort < 0 || cfg.AdminPort > 65535 || (cfg.AdminPort != 0 && cfg.AdminPort == cfg.ServicePort) {
+		return fmt.Errorf("admin port must be between 1 and 65535 and differ from the service port, or 0 to disable")
+	}
+
+	if cfg.FaultInjection {
+		if err := faults.CheckEnvironment(); err != nil {
+			return err
+		}
+		if cfg.AdminPort == 0 {
+			return fmt.Errorf("fault injection requires an admin port to be managed from")
+		}
+	}
+
+	if cfg.ChangeRequestTTL <= 0 {
+		return fmt.Errorf("change request TTL must be positive")
+	}
+
+	if cfg.QuoteHoldTTL <= 0 {
+		return fmt.Errorf("quote hold TTL must be positive")
+	}
+
+	if cfg.ReferralCredit < 0 {
+		return fmt.Errorf("referral credit must be non-negative")
+	}
+
+	if cfg.AssignmentAcceptWindow <= 0 {
+		return fmt.Errorf("assignment accept window must be positive")
+	}
+
+	if cfg.FeatureFlags.PollInterval <= 0 {
+		return fmt.Errorf("feature flag poll interval must be positive")
+	}
+
+	if cfg.ReconciliationAlertThreshold < 0 {
+		return fmt.Errorf("reconciliation alert threshold must be non-negative")
+	}
+
+	if cfg.TipWindow <= 0 {
+		return fmt.Errorf("tip window must be positive")
+	}
+
+	if cfg.CheckInRadius <= 0 {
+		return fmt.Errorf("check-in radius must be positive")
+	}
+
+	if cfg.CheckInPositionMaxAge <= 0 {
+		return fmt.Errorf("check-in position max age must be positive")
+	}
+
+	if cfg.Completion.MinDuration < 0 || cfg.Completion.MinDistance < 0 || cfg.Completion.MinPhotos < 0 {
+		return fmt.Errorf("completion requirements must be non-negative")
+	}
+
+	if cfg.Completion.Any() && cfg.TrackingURL == "" {
+		return fmt.Errorf("tracking URL is required when proof of completion is required")
+	}
+
+	if cfg.TrackingURL != "" && cfg.TrackingAPIKey == "" {
+		return fmt.Errorf("tracking API key is required when a tracking URL is set")
+	}
+
+	if err := cfg.Surge.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.AttachmentKeys != "" && cfg.AttachmentKeyID == "" {
+		return fmt.Errorf("attachment key ID is required when attachment keys are configured")
+	}
+
+	if cfg.AttachmentRetention <= 0 {
+		return fmt.Errorf("attachment retention must be positive")
+	}
+
+	if (cfg.Calendars.GoogleClientID != "" || cfg.Calendars.MicrosoftClientID != "") && cfg.Calendars.RedirectURL == "" {
+		return fmt.Errorf("calendar redirect URL is required when a calendar provider is configured")
+	}
+
+	if cfg.Push.APNsKeyFile != "" && (cfg.Push.APNsKeyID == "" || cfg.Push.APNsTeamID == "" || cfg.Push.APNsTopic == "") {
+		return fmt.Errorf("APNs key ID, team ID and topic are required when an APNs key is configured")
+	}
+
+	if cfg.SMS.AccountSID != "" && (cfg.SMS.AuthToken == "" || len(cfg.SMS.Senders) == 0) {
+		return fmt.Errorf("SMS auth token and at least one sender are required when an SMS account is configured")
+	}
+
+	switch cfg.Email.Provider {
+	case "":
+	case notifier.EmailProviderSMTP, notifier.EmailProviderSES:
+		if _, err := mail.ParseAddress(cfg.Email.From); err != nil {
+			return fmt.Errorf("a valid email sender address is required when an email provider is configured")
+		}
+		if cfg.Email.Provider == notifier.EmailProviderSMTP && (cfg.Email.SMTPHost == "" || cfg.Email.SMTPPort <= 0) {
+			return fmt.Errorf("SMTP host and port are required when sending email through SMTP")
+		}
+	default:
+		return fmt.Errorf("email provider must be %q or %q", notifier.EmailProviderSMTP, notifier.EmailProviderSES)
+	}
+
+	if cfg.ExchangeRatesTTL <= 0 {
+		return fmt.Errorf("exchange rates TTL must be positive")
+	}
+
+	if cfg.CapacityReportHorizon <= 0 || cfg.CapacityReportHorizon > 31*24*time.Hour {
+		return fmt.Errorf("capacity report horizon must be positive and at most 31 days")
+	}
+
+	for _, schedule := range cfg.ReportSchedules {
+		if schedule.Report == models.ReportTrackingAnomalies && cfg.TrackingURL == "" {
+			return fmt.Errorf("report schedule %s requires a tracking-service URL", schedule.Name)
+		}
+	}
+
 	return nil
+}
+
+// splitList splits a comma-separated setting, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
\ No newline at end of file
@@ -0,0 +1,216 @@
+// Package cron parses five-field cron expressions and finds the times they fire
+package cron
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// searchLimit is how far Next and Prev look for a fire time; an expression such as
+// "0 0 29 2 *" fires at least once in any five years
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// macros are the named schedules accepted in place of five fields
+var macros = map[string]string{
+    "@yearly":   "0 0 1 1 *",
+    "@annually": "0 0 1 1 *",
+    "@monthly":  "0 0 1 * *",
+    "@weekly":   "0 0 * * 0",
+    "@daily":    "0 0 * * *",
+    "@midnight": "0 0 * * *",
+    "@hourly":   "0 * * * *",
+}
+
+// bounds are the values each field accepts: minute, hour, day of month, month and day of
+// week, where both 0 and 7 are Sunday
+var bounds = [5]struct{ min, max int }{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// Schedule is a parsed cron expression. Times are matched in UTC.
+type Schedule struct {
+    expr string
+
+    // Bit n of a field is set when the field matches value n
+    minute, hour, dom, month, dow uint64
+
+    // A day field starting with "*" matches any day; when neither does, a day matching either
+    // field fires, as with cron(8)
+    anyDom, anyDow bool
+}
+
+// Parse parses a cron expression of five space-separated fields (minute, hour, day of month,
+// month, day of week), each a list of values, ranges and "*" with optional "/step", or one of
+// @hourly, @daily, @weekly, @monthly and @yearly
+func Parse(expr string) (*Schedule, error) {
+    expr = strings.TrimSpace(expr)
+    fields := strings.Fields(expr)
+    if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+        expanded, ok := macros[fields[0]]
+        if !ok {
+            return nil, fmt.Errorf("invalid cron expression %q: unknown schedule %s", expr, fields[0])
+        }
+        fields = strings.Fields(expanded)
+    }
+    if len(fields) != 5 {
+        return nil, fmt.Errorf("invalid cron expression %q: want 5 fields, got %d", expr, len(fields))
+    }
+
+    s := &Schedule{expr: expr}
+    targets := [5]*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+    for i, field := range fields {
+        bits, err := parseField(field, bounds[i].min, bounds[i].max)
+        if err != nil {
+            return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+        }
+        *targets[i] = bits
+    }
+    if s.dow&(1<<7) != 0 {
+        s.dow |= 1
+    }
+    s.anyDom = strings.HasPrefix(fields[2], "*")
+    s.anyDow = strings.HasPrefix(fields[4], "*")
+
+    if s.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+        return nil, fmt.Errorf("invalid cron expression %q: it never fires", expr)
+    }
+    return s, nil
+}
+
+// parseField parses one comma-separated field into a bit set of the values it matches
+func parseField(field string, min, max int) (uint64, error) {
+    var bits uint64
+    for _, part := range strings.Split(field, ",") {
+        span, stepText, stepped := strings.Cut(part, "/")
+        step := 1
+        if stepped {
+            n, err := strconv.Atoi(stepText)
+            if err != nil || n <= 0 {
+                return 0, fmt.Errorf("invalid step in %q", part)
+            }
+            step = n
+        }
+
+        lo, hi := min, max
+        switch {
+        case span == "*":
+        case strings.Contains(span, "-"):
+            from, to, _ := strings.Cut(span, "-")
+            var err error
+            if lo, err = parseValue(from, min, max); err != nil {
+                return 0, err
+            }
+            if hi, err = parseValue(to, min, max); err != nil {
+                return 0, err
+            }
+            if lo > hi {
+                return 0, fmt.Errorf("invalid range %q", span)
+            }
+        default:
+            value, err := parseValue(span, min, max)
+            if err != nil {
+                return 0, err
+            }
+            // A single value with a step runs to the end of the field, "5/15" meaning "5-59/15"
+            lo = value
+            if !stepped {
+                hi = value
+            }
+        }
+        for v := lo; v <= hi; v += step {
+            bits |= 1 << uint(v)
+        }
+    }
+    return bits, nil
+}
+
+// parseValue parses a number within [min, max]
+func parseValue(s string, min, max int) (int, error) {
+    n, err := strconv.Atoi(s)
+    if err != nil {
+        return 0, fmt.Errorf("invalid value %q", s)
+    }
+    if n < min || n > max {
+        return 0, fmt.Errorf("value %d out of range %d-%d", n, min, max)
+    }
+    return n, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+    return s.expr
+}
+
+// MarshalText encodes the schedule as its expression
+func (s *Schedule) MarshalText() ([]byte, error) {
+    return []byte(s.expr), nil
+}
+
+// Next returns the first time after t the schedule fires, or the zero time if it does not
+// fire within five years
+func (s *Schedule) Next(t time.Time) time.Time {
+    t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+    limit := t.Add(searchLimit)
+    for t.Before(limit) {
+        switch {
+        case !has(s.month, int(t.Month())):
+            t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+        case !s.matchesDay(t):
+            t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+        case !has(s.hour, t.Hour()):
+            t = t.Truncate(time.Hour).Add(time.Hour)
+        case !has(s.minute, t.Minute()):
+            t = t.Add(time.Minute)
+        default:
+            return t
+        }
+    }
+    return time.Time{}
+}
+
+// Prev returns the last time before t the schedule fired, or the zero time if it did not
+// fire within the five years before t
+func (s *Schedule) Prev(t time.Time) time.Time {
+    t = t.UTC()
+    if truncated := t.Truncate(time.Minute); truncated.Equal(t) {
+        t = t.Add(-time.Minute)
+    } else {
+        t = truncated
+    }
+    limit := t.Add(-searchLimit)
+    for t.After(limit) {
+        switch {
+        case !has(s.month, int(t.Month())):
+            t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+        case !s.matchesDay(t):
+            t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+        case !has(s.hour, t.Hour()):
+            t = t.Truncate(time.Hour).Add(-time.Minute)
+        case !has(s.minute, t.Minute()):
+            t = t.Add(-time.Minute)
+        default:
+            return t
+        }
+    }
+    return time.Time{}
+}
+
+// matchesDay reports whether the schedule fires on t's day
+func (s *Schedule) matchesDay(t time.Time) bool {
+    dom, dow := has(s.dom, t.Day()), has(s.dow, int(t.Weekday()))
+    switch {
+    case s.anyDom && s.anyDow:
+        return true
+    case s.anyDom:
+        return dow
+    case s.anyDow:
+        return dom
+    default:
+        return dom || dow
+    }
+}
+
+// has reports whether bit n of bits is set
+func has(bits uint64, n int) bool {
+    return bits&(1<<uint(n)) != 0
+}
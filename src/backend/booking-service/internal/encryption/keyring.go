@@ -0,0 +1,112 @@
+// Package encryption seals sensitive booking data, such as owners' attachments, before it is stored
+package encryption
+
+import (
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/rand"
+    "encoding/base64"
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// Human Tasks:
+// 1. Generate 256-bit data keys and store them in the secret manager
+// 2. To rotate, add a new key and make it current; keep the old key until every attachment
+//    sealed with it has been purged
+
+// keySize is the AES-256 key length in bytes
+const keySize = 32
+
+// ErrUnknownKey is returned when a value was sealed with a key that is not in the keyring
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Keyring seals values with AES-GCM under its current key and opens values sealed under any
+// of its keys, so data written before a rotation stays readable.
+type Keyring struct {
+    current string
+    aeads   map[string]cipher.AEAD
+}
+
+// NewKeyring creates a Keyring from raw 256-bit keys by ID, sealing with currentID
+func NewKeyring(currentID string, keys map[string][]byte) (*Keyring, error) {
+    if _, ok := keys[currentID]; !ok {
+        return nil, fmt.Errorf("current key %q is not in the keyring", currentID)
+    }
+
+    k := &Keyring{current: currentID, aeads: make(map[string]cipher.AEAD, len(keys))}
+    for id, key := range keys {
+        if len(key) != keySize {
+            return nil, fmt.Errorf("key %q must be %d bytes, got %d", id, keySize, len(key))
+        }
+        block, err := aes.NewCipher(key)
+        if err != nil {
+            return nil, fmt.Errorf("key %q: %w", id, err)
+        }
+        aead, err := cipher.NewGCM(block)
+        if err != nil {
+            return nil, fmt.Errorf("key %q: %w", id, err)
+        }
+        k.aeads[id] = aead
+    }
+    return k, nil
+}
+
+// ParseKeyring parses a comma-separated list of id:base64 key pairs into a Keyring sealing
+// with currentID
+func ParseKeyring(spec, currentID string) (*Keyring, error) {
+    keys := make(map[string][]byte)
+    for _, pair := range strings.Split(spec, ",") {
+        pair = strings.TrimSpace(pair)
+        if pair == "" {
+            continue
+        }
+        id, encoded, ok := strings.Cut(pair, ":")
+        if !ok || id == "" {
+            return nil, fmt.Errorf("invalid key entry %q: expected id:base64", pair)
+        }
+        key, err := base64.StdEncoding.DecodeString(encoded)
+        if err != nil {
+            return nil, fmt.Errorf("invalid key %q: %w", id, err)
+        }
+        keys[id] = key
+    }
+    if len(keys) == 0 {
+        return nil, fmt.Errorf("no keys given")
+    }
+    return NewKeyring(currentID, keys)
+}
+
+// CurrentID returns the ID of the key new values are sealed with
+func (k *Keyring) CurrentID() string {
+    return k.current
+}
+
+// Seal encrypts plaintext under the current key, binding it to aad, and returns the key ID
+// and the nonce-prefixed ciphertext
+func (k *Keyring) Seal(plaintext, aad []byte) (string, []byte, error) {
+    aead := k.aeads[k.current]
+    nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+    if _, err := rand.Read(nonce); err != nil {
+        return "", nil, fmt.Errorf("failed to generate nonce: %w", err)
+    }
+    return k.current, aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// Open decrypts a value sealed under keyID with the same aad
+func (k *Keyring) Open(keyID string, sealed, aad []byte) ([]byte, error) {
+    aead, ok := k.aeads[keyID]
+    if !ok {
+        return nil, fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+    }
+    if len(sealed) < aead.NonceSize() {
+        return nil, fmt.Errorf("sealed value is too short")
+    }
+    nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+    plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+    if err != nil {
+        return nil, fmt.Errorf("failed to decrypt value: %w", err)
+    }
+    return plaintext, nil
+}
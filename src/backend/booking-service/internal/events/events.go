@@ -0,0 +1,135 @@
+// Package events publishes booking-service domain events to downstream consumers
+package events
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "sync"
+    "time"
+)
+
+// Human Tasks:
+// 1. Set BOOKING_EVENTS_URL to the event ingestion endpoint in each environment
+// 2. Agree event type names and payload schemas with consuming teams before changing them
+// 3. Monitor event delivery failures from the booking-service
+
+// Event is a single domain event
+type Event struct {
+    Type       string      `json:"type"`
+    OccurredAt time.Time   `json:"occurred_at"`
+    Data       interface{} `json:"data"`
+}
+
+// Publisher delivers events to consumers
+// Addresses requirement: 7.3 Technical Decisions/Architecture Patterns/Microservices
+type Publisher interface {
+    Publish(ctx context.Context, event Event) error
+}
+
+// publisher is the process-wide publisher, set by Init
+var publisher Publisher = LogPublisher{}
+
+// Init selects the publisher: events are posted to url when set, otherwise logged
+func Init(url string) {
+    if url == "" {
+        publisher = LogPublisher{}
+        return
+    }
+    publisher = NewHTTPPublisher(url)
+}
+
+// Handler reacts to an event published by this process
+type Handler func(ctx context.Context, event Event)
+
+var (
+    handlersMu sync.RWMutex
+    handlers   = make(map[string][]Handler)
+)
+
+// Subscribe runs handler on every event of the given type this process publishes, so one part
+// of the booking-service can react to another's changes without calling it. Handlers run in
+// the publishing goroutine, in the order subscribed, whether or not delivery succeeded.
+func Subscribe(eventType string, handler Handler) {
+    handlersMu.Lock()
+    defer handlersMu.Unlock()
+    handlers[eventType] = append(handlers[eventType], handler)
+}
+
+// Publish sends an event of the given type through the configured publisher, then runs the
+// handlers subscribed to it. Delivery failures are logged and returned; callers decide
+// whether they matter.
+func Publish(ctx context.Context, eventType string, data interface{}) error {
+    event := Event{
+        Type:       eventType,
+        OccurredAt: time.Now().UTC(),
+        Data:       data,
+    }
+    err := publisher.Publish(ctx, event)
+    if err != nil {
+        log.Printf("Failed to publish %s event: %v", eventType, err)
+    }
+
+    handlersMu.RLock()
+    subscribed := handlers[eventType]
+    handlersMu.RUnlock()
+    for _, handler := range subscribed {
+        handler(ctx, event)
+    }
+    return err
+}
+
+// HTTPPublisher posts each event as JSON to a fixed URL
+type HTTPPublisher struct {
+    url    string
+    client *http.Client
+}
+
+// NewHTTPPublisher creates a publisher posting to url
+func NewHTTPPublisher(url string) *HTTPPublisher {
+    return &HTTPPublisher{
+        url:    url,
+        client: &http.Client{Timeout: 5 * time.Second},
+    }
+}
+
+// Publish posts the event
+func (p *HTTPPublisher) Publish(ctx context.Context, event Event) error {
+    payload, err := json.Marshal(event)
+    if err != nil {
+        return fmt.Errorf("failed to encode event: %w", err)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+    if err != nil {
+        return fmt.Errorf("failed to create event request: %w", err)
+    }
+    req.Header.Set("Content-Type", "application/json")
+
+    resp, err := p.client.Do(req)
+    if err != nil {
+        return fmt.Errorf("failed to send event: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return fmt.Errorf("event endpoint returned status %d", resp.StatusCode)
+    }
+    return nil
+}
+
+// LogPublisher logs events instead of delivering them, for environments without a consumer
+type LogPublisher struct{}
+
+// Publish logs the event
+func (LogPublisher) Publish(ctx context.Context, event Event) error {
+    payload, err := json.Marshal(event.Data)
+    if err != nil {
+        return fmt.Errorf("failed to encode event: %w", err)
+    }
+    log.Printf("Event %s: %s", event.Type, payload)
+    return nil
+}
@@ -0,0 +1,174 @@
+// Package exchange provides currency exchange rates for showing prices in other currencies
+package exchange
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+    "net/http"
+    "net/url"
+    "strings"
+    "sync"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// Human Tasks:
+// 1. Set BOOKING_EXCHANGE_RATES_URL to a rates API with a Frankfurter-compatible /latest endpoint
+// 2. Check the provider's terms allow displaying its rates to customers
+
+// ErrUnsupportedCurrency is returned when the provider publishes no rate for a currency
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// Rates are the exchange rates from Base published at AsOf
+type Rates struct {
+    Base string
+    AsOf time.Time
+
+    // Rates maps a currency to the units of it bought by one unit of Base
+    Rates map[string]float64
+}
+
+// Provider publishes exchange rates
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type Provider interface {
+    // Latest returns the current rates from base
+    Latest(ctx context.Context, base string) (*Rates, error)
+}
+
+// Default is the process-wide provider, set by Init; nil when no rates service is configured
+var Default Provider
+
+// Init selects the provider: rates are fetched from baseURL and cached for ttl when set,
+// otherwise Default is nil and amounts cannot be converted
+func Init(baseURL string, ttl time.Duration) {
+    if baseURL == "" {
+        Default = nil
+        return
+    }
+    Default = NewCache(NewHTTPProvider(baseURL), ttl)
+}
+
+// Rate returns the rate converting from into to using provider. Converting a currency into
+// itself needs no provider.
+func Rate(ctx context.Context, provider Provider, from, to string) (models.ExchangeRate, error) {
+    from, to = strings.ToUpper(from), strings.ToUpper(to)
+    if from == to {
+        return models.ExchangeRate{From: from, To: to, Rate: 1, AsOf: time.Now().UTC()}, nil
+    }
+
+    rates, err := provider.Latest(ctx, from)
+    if err != nil {
+        return models.ExchangeRate{}, err
+    }
+    rate, ok := rates.Rates[to]
+    if !ok || rate <= 0 {
+        return models.ExchangeRate{}, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, to)
+    }
+    return models.ExchangeRate{From: from, To: to, Rate: rate, AsOf: rates.AsOf}, nil
+}
+
+// HTTPProvider reads rates from a Frankfurter-compatible API
+type HTTPProvider struct {
+    baseURL string
+    client  *http.Client
+}
+
+// NewHTTPProvider creates a provider for the rates API at baseURL
+func NewHTTPProvider(baseURL string) *HTTPProvider {
+    return &HTTPProvider{
+        baseURL: strings.TrimRight(baseURL, "/"),
+        client:  &http.Client{Timeout: 5 * time.Second},
+    }
+}
+
+// latestResponse mirrors the rates API /latest response
+type latestResponse struct {
+    Base  string             `json:"base"`
+    Date  string             `json:"date"`
+    Rates map[string]float64 `json:"rates"`
+}
+
+// Latest implements Provider
+func (p *HTTPProvider) Latest(ctx context.Context, base string) (*Rates, error) {
+    query := url.Values{}
+    query.Set("from", strings.ToUpper(base))
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/latest?"+query.Encode(), nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to create exchange rates request: %w", err)
+    }
+
+    resp, err := p.client.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("exchange rates service returned status %d", resp.StatusCode)
+    }
+
+    var body latestResponse
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+        return nil, fmt.Errorf("failed to decode exchange rates: %w", err)
+    }
+    asOf, err := time.Parse("2006-01-02", body.Date)
+    if err != nil {
+        return nil, fmt.Errorf("invalid exchange rates date %q: %w", body.Date, err)
+    }
+    return &Rates{Base: strings.ToUpper(body.Base), AsOf: asOf, Rates: body.Rates}, nil
+}
+
+// Cache keeps each base currency's rates for a TTL, so quotes do not call the provider on
+// every request. When a refresh fails, the last rates are served until a refresh succeeds.
+type Cache struct {
+    provider Provider
+    ttl      time.Duration
+
+    mu      sync.Mutex
+    entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+    rates     *Rates
+    fetchedAt time.Time
+}
+
+// NewCache wraps provider with a cache keeping rates for ttl
+func NewCache(provider Provider, ttl time.Duration) *Cache {
+    return &Cache{
+        provider: provider,
+        ttl:      ttl,
+        entries:  make(map[string]cacheEntry),
+    }
+}
+
+// Latest implements Provider
+func (c *Cache) Latest(ctx context.Context, base string) (*Rates, error) {
+    base = strings.ToUpper(base)
+
+    c.mu.Lock()
+    entry, ok := c.entries[base]
+    c.mu.Unlock()
+    if ok && time.Since(entry.fetchedAt) < c.ttl {
+        return entry.rates, nil
+    }
+
+    rates, err := c.provider.Latest(ctx, base)
+    if err != nil {
+        if ok {
+            log.Printf("Serving cached %s exchange rates from %s: %v", base, entry.rates.AsOf.Format("2006-01-02"), err)
+            return entry.rates, nil
+        }
+        return nil, err
+    }
+
+    c.mu.Lock()
+    c.entries[base] = cacheEntry{rates: rates, fetchedAt: time.Now()}
+    c.mu.Unlock()
+    return rates, nil
+}
@@ -0,0 +1,117 @@
+// Package geocoding resolves bookings' pickup addresses to coordinates
+package geocoding
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// Human Tasks:
+// 1. Set BOOKING_GEOCODER_URL to a Nominatim-compatible geocoding API; bookings made with only
+//    an address have no pickup coordinates while it is unset
+// 2. Check the provider's usage policy; the public Nominatim instance is not meant for production
+
+// ErrAddressNotFound is returned when the geocoder cannot place an address
+var ErrAddressNotFound = errors.New("address not found")
+
+// Geocoder resolves addresses to coordinates. A provider such as Google or Mapbox can replace
+// the Nominatim geocoder by implementing it.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type Geocoder interface {
+    // Geocode returns the latitude and longitude of address, or ErrAddressNotFound
+    Geocode(ctx context.Context, address models.Address) (float64, float64, error)
+}
+
+// Default is the process-wide geocoder, set by Init; nil when no geocoder is configured
+var Default Geocoder
+
+// Init selects the geocoder: addresses are resolved through the API at baseURL when set,
+// otherwise Default is nil and addresses are stored without coordinates
+func Init(baseURL string) {
+    if baseURL == "" {
+        Default = nil
+        return
+    }
+    Default = NewNominatim(baseURL)
+}
+
+// Nominatim resolves addresses through a Nominatim-compatible /search endpoint
+type Nominatim struct {
+    baseURL string
+    client  *http.Client
+}
+
+// NewNominatim creates a geocoder for the Nominatim API at baseURL
+func NewNominatim(baseURL string) *Nominatim {
+    return &Nominatim{
+        baseURL: strings.TrimRight(baseURL, "/"),
+        // Owners wait on the answer while booking, so a slow geocoder fails fast
+        client: &http.Client{Timeout: 5 * time.Second},
+    }
+}
+
+// searchResult mirrors an entry of the Nominatim /search response; coordinates are strings
+type searchResult struct {
+    Lat string `json:"lat"`
+    Lon string `json:"lon"`
+}
+
+// Geocode implements Geocoder with a structured query, so the street is never mistaken for
+// the city
+func (n *Nominatim) Geocode(ctx context.Context, address models.Address) (float64, float64, error) {
+    query := url.Values{}
+    query.Set("format", "jsonv2")
+    query.Set("limit", "1")
+    query.Set("street", address.Line1)
+    query.Set("city", address.City)
+    query.Set("postalcode", address.PostalCode)
+    if address.State != "" {
+        query.Set("state", address.State)
+    }
+    if address.Country != "" {
+        query.Set("countrycodes", strings.ToLower(address.Country))
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+"/search?"+query.Encode(), nil)
+    if err != nil {
+        return 0, 0, fmt.Errorf("failed to create geocoding request: %w", err)
+    }
+    // Nominatim's usage policy requires requests to identify the application
+    req.Header.Set("User-Agent", "booking-service")
+
+    resp, err := n.client.Do(req)
+    if err != nil {
+        return 0, 0, fmt.Errorf("failed to geocode address: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return 0, 0, fmt.Errorf("geocoder returned status %d", resp.StatusCode)
+    }
+
+    var results []searchResult
+    if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+        return 0, 0, fmt.Errorf("failed to decode geocoding response: %w", err)
+    }
+    if len(results) == 0 {
+        return 0, 0, ErrAddressNotFound
+    }
+    latitude, err := strconv.ParseFloat(results[0].Lat, 64)
+    if err != nil {
+        return 0, 0, fmt.Errorf("invalid latitude %q from geocoder", results[0].Lat)
+    }
+    longitude, err := strconv.ParseFloat(results[0].Lon, 64)
+    if err != nil {
+        return 0, 0, fmt.Errorf("invalid longitude %q from geocoder", results[0].Lon)
+    }
+    return latitude, longitude, nil
+}
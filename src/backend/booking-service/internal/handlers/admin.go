@@ -0,0 +1,282 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// overrideRequest is the body of an admin override request; only the fields relevant
+// to the action are read
+type overrideRequest struct {
+    Status   models.BookingStatus `json:"status"`
+    WalkerID string               `json:"walker_id"`
+    Amount   *float64             `json:"amount"`
+    Reason   string               `json:"reason"`
+}
+
+// AdminBookingHandler dispatches admin override requests:
+//   POST /api/v1/admin/bookings/{id}/status
+//   POST /api/v1/admin/bookings/{id}/walker
+//   POST /api/v1/admin/bookings/{id}/amount
+//   POST /api/v1/admin/bookings/{id}/assign
+//   POST /api/v1/admin/bookings/{id}/check-in
+//   GET  /api/v1/admin/bookings/{id}/history
+//   GET  /api/v1/admin/bookings/{id}/audit
+// It must be wrapped in middleware.RequirePermission.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func AdminBookingHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/bookings/"), "/"), "/")
+    if len(parts) != 2 || parts[0] == "" {
+        http.NotFound(w, r)
+        return
+    }
+    if parts[1] == "history" {
+        AdminBookingHistoryHandler(w, r, parts[0])
+        return
+    }
+    if parts[1] == "audit" {
+        AdminBookingAuditHandler(w, r, parts[0])
+        return
+    }
+    if r.Method != http.MethodPost {
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    bookingID, action := parts[0], parts[1]
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var req overrideRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    var (
+        booking *models.Booking
+        err     error
+    )
+    switch action {
+    case "status":
+        booking, err = service.ForceStatusService(r.Context(), claims.ID, bookingID, req.Status, req.Reason)
+    case "walker":
+        booking, err = service.ReassignWalkerService(r.Context(), claims.ID, bookingID, req.WalkerID, req.Reason)
+    case "amount":
+        if req.Amount == nil {
+            http.Error(w, "amount is required", http.StatusBadRequest)
+            return
+        }
+        booking, err = service.AdjustAmountService(r.Context(), claims.ID, bookingID, *req.Amount, req.Reason)
+    case "assign":
+        // Assignment is a normal workflow step rather than an override, so it is not
+        // audited; an empty walker_id asks the matching engine to pick one
+        booking, err = service.AssignWalkerService(r.Context(), bookingID, req.WalkerID)
+    case "check-in":
+        // Checks the walker in without verifying their position, for when it cannot be tracked
+        booking, err = service.CheckInOverrideService(r.Context(), claims.ID, bookingID, req.Reason)
+    default:
+        http.NotFound(w, r)
+        return
+    }
+
+    if err != nil {
+        logger.LogError("Admin override failed", map[string]interface{}{
+            "error":     err.Error(),
+            "action":    action,
+            "bookingId": bookingID,
+            "actorId":   claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, err.Error(), http.StatusNotFound)
+        case strings.Contains(err.Error(), "invalid override"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking conflict"), strings.Contains(err.Error(), "no walker available"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        case strings.Contains(err.Error(), "walker not eligible"):
+            http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Admin override applied", map[string]interface{}{
+        "action":    action,
+        "bookingId": bookingID,
+        "actorId":   claims.ID,
+        "reason":    req.Reason,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Booking override applied",
+        "data":    booking,
+    })
+}
+
+// bulkStatusRequest is the body of a bulk status request
+type bulkStatusRequest struct {
+    models.BulkStatusFilter
+    Status models.BookingStatus `json:"status"`
+    Reason string               `json:"reason"`
+}
+
+// BulkStatusHandler handles POST /api/v1/bookings/bulk-status, setting the status of every
+// booking of a walker scheduled between from and to, and responds with what happened to each.
+// It must be wrapped in middleware.RequirePermission.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func BulkStatusHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var req bulkStatusRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    report, err := service.BulkStatusService(r.Context(), claims.ID, req.BulkStatusFilter, req.Status, req.Reason)
+    if err != nil {
+        logger.LogError("Bulk status change failed", map[string]interface{}{
+            "error":    err.Error(),
+            "walkerId": req.WalkerID,
+            "status":   req.Status,
+            "actorId":  claims.ID,
+        })
+
+        if strings.Contains(err.Error(), "invalid override") {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        } else {
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Bulk status change applied", map[string]interface{}{
+        "walkerId": req.WalkerID,
+        "status":   req.Status,
+        "updated":  report.Updated,
+        "skipped":  report.Skipped,
+        "actorId":  claims.ID,
+        "reason":   req.Reason,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": fmt.Sprintf("Updated %d of %d bookings", report.Updated, len(report.Results)),
+        "data":    report,
+    })
+}
+
+// AdminBookingHistoryHandler lists every change recorded to a booking, or with ?version=N
+// returns the booking as it was after change N
+func AdminBookingHistoryHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    if r.Method != http.MethodGet {
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    var (
+        data interface{}
+        err  error
+    )
+    if raw := r.URL.Query().Get("version"); raw != "" {
+        version, convErr := strconv.Atoi(raw)
+        if convErr != nil {
+            http.Error(w, "version must be a number", http.StatusBadRequest)
+            return
+        }
+        data, err = service.BookingAtVersionService(r.Context(), bookingID, version)
+    } else {
+        data, err = service.BookingHistoryService(r.Context(), bookingID)
+    }
+
+    if err != nil {
+        logger.LogError("Failed to read booking history", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, err.Error(), http.StatusNotFound)
+        case strings.Contains(err.Error(), "invalid version"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    data,
+    })
+}
+
+// AdminBookingAuditHandler lists the administrative overrides recorded to a booking, with
+// who made each one, why, and the booking before and after it
+func AdminBookingAuditHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    if r.Method != http.MethodGet {
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    entries, err := service.BookingAuditService(r.Context(), bookingID)
+    if err != nil {
+        logger.LogError("Failed to read booking audit log", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+        })
+
+        if strings.Contains(err.Error(), "booking not found") {
+            http.Error(w, err.Error(), http.StatusNotFound)
+        } else {
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    entries,
+    })
+}
@@ -0,0 +1,145 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// issueAPIKeyRequest is the body of an API key issuance request
+type issueAPIKeyRequest struct {
+    Name   string            `json:"name"`
+    Scopes []string          `json:"scopes"`
+    Tier   models.APIKeyTier `json:"tier"`
+}
+
+// AdminAPIKeyHandler dispatches API key management requests:
+//   GET    /api/v1/admin/api-keys
+//   POST   /api/v1/admin/api-keys
+//   DELETE /api/v1/admin/api-keys/{id}
+// It must be wrapped in middleware.RequirePermission.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func AdminAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/api-keys"), "/")
+    switch {
+    case id == "" && r.Method == http.MethodGet:
+        listAPIKeys(w, r)
+    case id == "" && r.Method == http.MethodPost:
+        issueAPIKey(w, r, claims.ID)
+    case id != "" && !strings.Contains(id, "/") && r.Method == http.MethodDelete:
+        revokeAPIKey(w, r, claims.ID, id)
+    case strings.Contains(id, "/"):
+        http.NotFound(w, r)
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+    }
+}
+
+// listAPIKeys writes every issued key; secrets are never included
+func listAPIKeys(w http.ResponseWriter, r *http.Request) {
+    keys, err := service.ListAPIKeysService(r.Context())
+    if err != nil {
+        logger.LogError("Failed to list api keys", map[string]interface{}{
+            "error": err.Error(),
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    keys,
+    })
+}
+
+// issueAPIKey creates a key and writes its secret, which is not retrievable afterwards
+func issueAPIKey(w http.ResponseWriter, r *http.Request, actorID string) {
+    var req issueAPIKeyRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    key, secret, err := service.IssueAPIKeyService(r.Context(), actorID, req.Name, req.Scopes, req.Tier)
+    if err != nil {
+        logger.LogError("Failed to issue api key", map[string]interface{}{
+            "error":   err.Error(),
+            "name":    req.Name,
+            "actorId": actorID,
+        })
+        if strings.Contains(err.Error(), "invalid api key") {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    logger.LogInfo("API key issued", map[string]interface{}{
+        "apiKeyId": key.ID,
+        "name":     key.Name,
+        "scopes":   key.Scopes,
+        "tier":     key.Tier,
+        "actorId":  actorID,
+    })
+
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "API key issued; store it now, it cannot be shown again",
+        "data": map[string]interface{}{
+            "key":     secret,
+            "api_key": key,
+        },
+    })
+}
+
+// revokeAPIKey revokes a key by ID
+func revokeAPIKey(w http.ResponseWriter, r *http.Request, actorID, id string) {
+    key, err := service.RevokeAPIKeyService(r.Context(), id)
+    if err != nil {
+        logger.LogError("Failed to revoke api key", map[string]interface{}{
+            "error":    err.Error(),
+            "apiKeyId": id,
+            "actorId":  actorID,
+        })
+        if strings.Contains(err.Error(), "api key not found") {
+            http.Error(w, err.Error(), http.StatusNotFound)
+            return
+        }
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    logger.LogInfo("API key revoked", map[string]interface{}{
+        "apiKeyId": key.ID,
+        "name":     key.Name,
+        "actorId":  actorID,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "API key revoked",
+        "data":    key,
+    })
+}
@@ -0,0 +1,81 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+    "src/backend/shared/utils/logger"
+)
+
+// RespondAssignmentHandler handles a walker accepting or declining the booking assigned to them.
+// The walker is the one whose token middleware.RequirePermission authenticated.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func RespondAssignmentHandler(w http.ResponseWriter, r *http.Request, bookingID string, accept bool) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+    if claims.Role != policy.RoleWalker {
+        http.Error(w, "Only walkers can answer an assignment", http.StatusForbidden)
+        return
+    }
+
+    var (
+        booking *models.Booking
+        err     error
+        message = "Booking declined"
+    )
+    if accept {
+        booking, err = service.AcceptAssignmentService(r.Context(), bookingID, claims.ID)
+        message = "Booking accepted"
+    } else {
+        err = service.DeclineAssignmentService(r.Context(), bookingID, claims.ID)
+    }
+
+    if err != nil {
+        logger.LogError("Failed to respond to booking assignment", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+            "walkerId":  claims.ID,
+            "accept":    accept,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, err.Error(), http.StatusNotFound)
+        case strings.Contains(err.Error(), "invalid assignment response"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        case strings.Contains(err.Error(), "payment not authorized"):
+            http.Error(w, err.Error(), http.StatusPaymentRequired)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo(message, map[string]interface{}{
+        "bookingId": bookingID,
+        "walkerId":  claims.ID,
+    })
+
+    response := map[string]interface{}{
+        "success": true,
+        "message": message,
+    }
+    if booking != nil {
+        response["data"] = booking
+    }
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(response)
+}
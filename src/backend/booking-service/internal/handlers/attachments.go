@@ -0,0 +1,137 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// maxAttachmentRequestBytes bounds the body of a new attachment; a file's content is base64
+// encoded, so it takes a third more than models.MaxAttachmentBytes
+const maxAttachmentRequestBytes = models.MaxAttachmentBytes*4/3 + 64<<10
+
+// IsBookingAttachmentPath reports whether path is under /api/v1/bookings/{id}/attachments
+func IsBookingAttachmentPath(path string) bool {
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/v1/bookings/"), "/"), "/")
+    return len(parts) >= 2 && parts[1] == "attachments"
+}
+
+// BookingAttachmentHandler handles the notes and small files owners attach to a booking for
+// its walker, such as access instructions and the vet's contact. Owners POST attachments to
+// /api/v1/bookings/{id}/attachments and DELETE them at .../attachments/{aid}; GET lists them
+// without file content, or returns one with its file. The caller is identified by their user
+// token, and walkers can only read a booking's attachments while it is confirmed or in progress.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func BookingAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/bookings/"), "/"), "/")
+    if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] != "attachments" {
+        http.NotFound(w, r)
+        return
+    }
+    bookingID := parts[0]
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    switch {
+    case len(parts) == 2 && r.Method == http.MethodPost:
+        addBookingAttachment(w, r, bookingID, claims.ID)
+    case len(parts) == 2 && r.Method == http.MethodGet:
+        attachments, err := service.ListBookingAttachmentsService(r.Context(), bookingID, claims.ID)
+        if err != nil {
+            writeAttachmentError(w, err, "Failed to list attachments", bookingID, claims.ID)
+            return
+        }
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "success": true,
+            "data":    attachments,
+        })
+    case len(parts) == 3 && r.Method == http.MethodGet:
+        attachment, err := service.GetBookingAttachmentService(r.Context(), bookingID, parts[2], claims.ID)
+        if err != nil {
+            writeAttachmentError(w, err, "Failed to get attachment", bookingID, claims.ID)
+            return
+        }
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "success": true,
+            "data":    attachment,
+        })
+    case len(parts) == 3 && r.Method == http.MethodDelete:
+        if err := service.DeleteBookingAttachmentService(r.Context(), bookingID, parts[2], claims.ID); err != nil {
+            writeAttachmentError(w, err, "Failed to delete attachment", bookingID, claims.ID)
+            return
+        }
+        w.WriteHeader(http.StatusNoContent)
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+    }
+}
+
+// addBookingAttachment handles POST /api/v1/bookings/{id}/attachments
+func addBookingAttachment(w http.ResponseWriter, r *http.Request, bookingID, ownerID string) {
+    var attachment models.BookingAttachment
+    if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAttachmentRequestBytes)).Decode(&attachment); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    created, err := service.AddBookingAttachmentService(r.Context(), bookingID, ownerID, attachment)
+    if err != nil {
+        writeAttachmentError(w, err, "Failed to add attachment", bookingID, ownerID)
+        return
+    }
+
+    logger.LogInfo("Attachment added", map[string]interface{}{
+        "bookingId":    bookingID,
+        "attachmentId": created.ID,
+        "kind":         created.Kind,
+        "size":         created.Size,
+    })
+
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    created.Summary(),
+    })
+}
+
+// writeAttachmentError logs an attachment request's failure and maps it to a status code
+func writeAttachmentError(w http.ResponseWriter, err error, message, bookingID, userID string) {
+    logger.LogError(message, map[string]interface{}{
+        "error":     err.Error(),
+        "bookingId": bookingID,
+        "userId":    userID,
+    })
+
+    switch {
+    case strings.Contains(err.Error(), "invalid attachment"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "booking not found"):
+        http.Error(w, "Booking not found with id: "+bookingID, http.StatusNotFound)
+    case strings.Contains(err.Error(), "attachment not found"):
+        http.Error(w, "Attachment not found", http.StatusNotFound)
+    case strings.Contains(err.Error(), "attachment forbidden"):
+        http.Error(w, err.Error(), http.StatusForbidden)
+    case strings.Contains(err.Error(), "attachment not allowed"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    case strings.Contains(err.Error(), "attachments unavailable"):
+        http.Error(w, "Attachments are not available", http.StatusServiceUnavailable)
+    default:
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+    }
+}
@@ -0,0 +1,101 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+    "src/backend/shared/utils/logger"
+)
+
+// CreateAvailabilityHandler handles HTTP POST requests to publish a walker availability window.
+// Walkers authenticated by middleware.RequirePermission publish their own windows; admins can
+// publish any walker's.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+// Handles real-time availability search and schedule coordination, including group walk capacity
+func CreateAvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var availability models.Availability
+    if err := json.NewDecoder(r.Body).Decode(&availability); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+    if claims.Role != policy.RoleAdmin {
+        if availability.WalkerID != "" && availability.WalkerID != claims.ID {
+            http.Error(w, "Walkers can only publish their own availability", http.StatusForbidden)
+            return
+        }
+        availability.WalkerID = claims.ID
+    }
+
+    if err := service.CreateAvailabilityService(r.Context(), &availability); err != nil {
+        logger.LogError("Failed to create availability", map[string]interface{}{
+            "error":    err.Error(),
+            "walkerId": availability.WalkerID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid availability data"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Availability created successfully", map[string]interface{}{
+        "availabilityId": availability.ID,
+        "walkerId":       availability.WalkerID,
+        "capacity":       availability.Capacity,
+    })
+
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Availability created successfully",
+        "data":    availability,
+    })
+}
+
+// ListAvailabilityHandler handles HTTP GET requests for a walker's upcoming availability windows
+func ListAvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    walkerID := r.URL.Query().Get("walker_id")
+    if walkerID == "" {
+        http.Error(w, "walker_id query parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    windows, err := service.ListAvailabilityService(r.Context(), walkerID)
+    if err != nil {
+        logger.LogError("Failed to retrieve availability", map[string]interface{}{
+            "error":    err.Error(),
+            "walkerId": walkerID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    windows,
+    })
+}
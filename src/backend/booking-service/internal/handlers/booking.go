@@ -2,14 +2,17 @@
 package handlers
 
 import (
-    "context"
     "encoding/json"
-    "fmt"
+    "errors"
     "net/http"
+    "strconv"
     "strings"
 
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/middleware"
     "src/backend/booking-service/internal/models"
     "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
     "src/backend/shared/utils/logger"
 )
 
@@ -20,12 +23,14 @@ import (
 // 4. Configure CORS settings if needed
 // 5. Set up API documentation using Swagger/OpenAPI
 
-// CreateBookingHandler handles HTTP POST requests to create a new booking
+// CreateBookingHandler handles HTTP POST requests to create a new booking. Errors are given in
+// the language negotiated by i18n.Middleware.
 // Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
 // Handles real-time availability search, booking management, and schedule coordination
 func CreateBookingHandler(w http.ResponseWriter, r *http.Request) {
     // Set response content type
     w.Header().Set("Content-Type", "application/json")
+    locale := i18n.FromContext(r.Context())
 
     // Parse request body
     var booking models.Booking
@@ -34,12 +39,20 @@ func CreateBookingHandler(w http.ResponseWriter, r *http.Request) {
             "error": err.Error(),
             "path":  r.URL.Path,
         })
-        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        http.Error(w, i18n.T(locale, "error.invalid_body"), http.StatusBadRequest)
         return
     }
 
-    // Create context for the request
-    ctx := context.Background()
+    // Owners book for themselves; admins and partner backends name the owner
+    if claims, ok := middleware.UserFromContext(r.Context()); ok && claims.Role == policy.RoleOwner {
+        if booking.OwnerID != "" && booking.OwnerID != claims.ID {
+            http.Error(w, i18n.T(locale, "error.owner_mismatch"), http.StatusForbidden)
+            return
+        }
+        booking.OwnerID = claims.ID
+    }
+
+    ctx := r.Context()
 
     // Call service layer to create booking
     err := service.CreateBookingService(ctx, &booking)
@@ -52,17 +65,37 @@ func CreateBookingHandler(w http.ResponseWriter, r *http.Request) {
         })
 
         // Handle different types of errors
+        var ruleErr *service.RuleError
+        var areaErr *service.ServiceAreaError
         switch {
+        case errors.As(err, &ruleErr):
+            writeRuleError(w, r, ruleErr)
+        case errors.As(err, &areaErr):
+            writeServiceAreaError(w, r, areaErr)
         case strings.Contains(err.Error(), "invalid booking data"):
-            http.Error(w, err.Error(), http.StatusBadRequest)
+            http.Error(w, i18n.Localize(locale, err), http.StatusBadRequest)
         case strings.Contains(err.Error(), "booking must be scheduled"):
-            http.Error(w, err.Error(), http.StatusBadRequest)
+            http.Error(w, i18n.Localize(locale, err), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking conflict"):
+            http.Error(w, i18n.Localize(locale, err), http.StatusConflict)
+        case strings.Contains(err.Error(), "walker not eligible"):
+            http.Error(w, i18n.Localize(locale, err), http.StatusUnprocessableEntity)
         default:
-            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            http.Error(w, i18n.T(locale, "error.internal"), http.StatusInternalServerError)
         }
         return
     }
 
+    // Receipts and updates on walks are emailed to the address the owner booked with
+    if claims, ok := middleware.UserFromContext(r.Context()); ok && claims.ID == booking.OwnerID {
+        if err := service.RecordUserEmailService(r.Context(), claims.ID, claims.Email); err != nil {
+            logger.LogError("Failed to record owner email", map[string]interface{}{
+                "error":   err.Error(),
+                "ownerId": booking.OwnerID,
+            })
+        }
+    }
+
     // Log successful booking creation
     logger.LogInfo("Booking created successfully", map[string]interface{}{
         "bookingId": booking.ID,
@@ -70,39 +103,56 @@ func CreateBookingHandler(w http.ResponseWriter, r *http.Request) {
         "walkerId":  booking.WalkerID,
     })
 
-    // Return success response
-    w.WriteHeader(http.StatusCreated)
-    json.NewEncoder(w).Encode(map[string]interface{}{
+    response := map[string]interface{}{
         "success": true,
         "message": "Booking created successfully",
         "data":    booking,
-    })
+    }
+
+    // The booking already exists, so a failed conversion only leaves out the quote
+    if currency := r.URL.Query().Get(currencyParam); currency != "" {
+        quote, err := service.QuoteBookingService(r.Context(), &booking, currency)
+        if err != nil {
+            logger.LogError("Failed to quote booking", map[string]interface{}{
+                "error":     err.Error(),
+                "bookingId": booking.ID,
+                "currency":  currency,
+            })
+        } else {
+            response["quote"] = quote
+        }
+    }
+
+    // Return success response
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(response)
 }
 
-// GetBookingHandler handles HTTP GET requests to retrieve a booking by ID
+// GetBookingHandler handles HTTP GET requests to retrieve a booking by ID. With ?currency=EUR
+// the response also quotes the booking's price in that currency.
 // Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
 // Handles booking management and retrieval
 func GetBookingHandler(w http.ResponseWriter, r *http.Request) {
     // Set response content type
     w.Header().Set("Content-Type", "application/json")
+    locale := i18n.FromContext(r.Context())
 
     // Extract booking ID from URL path
     // Expected format: /bookings/{id}
     pathParts := strings.Split(r.URL.Path, "/")
     if len(pathParts) < 3 {
-        http.Error(w, "Invalid request path", http.StatusBadRequest)
+        http.Error(w, i18n.T(locale, "error.invalid_path"), http.StatusBadRequest)
         return
     }
     bookingID := pathParts[len(pathParts)-1]
 
     // Validate booking ID
     if bookingID == "" {
-        http.Error(w, "Booking ID is required", http.StatusBadRequest)
+        http.Error(w, i18n.T(locale, "error.booking_id_required"), http.StatusBadRequest)
         return
     }
 
-    // Create context for the request
-    ctx := context.Background()
+    ctx := r.Context()
 
     // Call service layer to retrieve booking
     booking, err := service.GetBookingService(ctx, bookingID)
@@ -115,9 +165,9 @@ func GetBookingHandler(w http.ResponseWriter, r *http.Request) {
         // Handle different types of errors
         switch {
         case strings.Contains(err.Error(), "booking not found"):
-            http.Error(w, fmt.Sprintf("Booking not found with id: %s", bookingID), http.StatusNotFound)
+            http.Error(w, i18n.T(locale, "error.booking_not_found", bookingID), http.StatusNotFound)
         default:
-            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            http.Error(w, i18n.T(locale, "error.internal"), http.StatusInternalServerError)
         }
         return
     }
@@ -129,10 +179,99 @@ func GetBookingHandler(w http.ResponseWriter, r *http.Request) {
         "walkerId":  booking.WalkerID,
     })
 
+    response := map[string]interface{}{
+        "success": true,
+        "data":    booking,
+    }
+
+    // The booking stays in its own currency; the quote shows it in the requested one
+    if currency := r.URL.Query().Get(currencyParam); currency != "" {
+        quote, err := service.QuoteBookingService(r.Context(), booking, currency)
+        if err != nil {
+            logger.LogError("Failed to quote booking", map[string]interface{}{
+                "error":     err.Error(),
+                "bookingId": bookingID,
+                "currency":  currency,
+            })
+            writeCurrencyError(w, err)
+            return
+        }
+        response["quote"] = quote
+    }
+
     // Return success response
     w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(response)
+}
+// ListBookingsHandler handles GET /api/v1/bookings, listing bookings latest scheduled first:
+//   GET /api/v1/bookings?status=confirmed&walker_id=&owner_id=&region=&ids=&limit=20&cursor={next_cursor}
+// Bookings are ordered by scheduled time and then ID, both descending, and paged by seeking
+// past the last booking returned rather than by offset. Passing a page's next_cursor fetches
+// the following page; bookings created meanwhile never shift or repeat entries across pages,
+// and next_cursor is omitted on the last page. Every page carries a summary counting all the
+// matching bookings by status, and the X-Total-Count header gives their total, so dashboards
+// can size pagination controls. ids, a comma-separated list, fetches those bookings in one
+// request; any not found or not visible to the caller are left out. Owners and walkers see
+// only their own bookings.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ListBookingsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    query := r.URL.Query()
+    filter := models.BookingFilter{
+        Status:   models.BookingStatus(query.Get("status")),
+        WalkerID: query.Get("walker_id"),
+        OwnerID:  query.Get("owner_id"),
+        Region:   query.Get("region"),
+    }
+    if ids := query.Get("ids"); ids != "" {
+        filter.IDs = strings.Split(ids, ",")
+    }
+    limit := 0
+    if raw := query.Get("limit"); raw != "" {
+        var err error
+        if limit, err = strconv.Atoi(raw); err != nil {
+            http.Error(w, "Invalid limit", http.StatusBadRequest)
+            return
+        }
+    }
+
+    // API keys belong to partner backends, which may list any booking
+    if claims, ok := middleware.UserFromContext(r.Context()); ok {
+        switch claims.Role {
+        case policy.RoleOwner:
+            if filter.OwnerID != "" && filter.OwnerID != claims.ID {
+                http.Error(w, "Insufficient permissions", http.StatusForbidden)
+                return
+            }
+            filter.OwnerID = claims.ID
+        case policy.RoleWalker:
+            if filter.WalkerID != "" && filter.WalkerID != claims.ID {
+                http.Error(w, "Insufficient permissions", http.StatusForbidden)
+                return
+            }
+            filter.WalkerID = claims.ID
+        }
+    }
+
+    page, err := service.ListBookingsService(r.Context(), filter, query.Get("cursor"), limit)
+    if err != nil {
+        if strings.Contains(err.Error(), "invalid booking listing") {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        logger.LogError("Failed to list bookings", map[string]interface{}{
+            "error":  err.Error(),
+            "filter": filter,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("X-Total-Count", strconv.Itoa(page.Summary.Total))
+    w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(map[string]interface{}{
         "success": true,
-        "data":    booking,
+        "data":    page,
     })
-}
\ No newline at end of file
+}
@@ -0,0 +1,196 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// BookingHandler dispatches requests under /api/v1/bookings/; PATCH /api/v1/bookings/{id} is
+// routed to PatchBookingHandler behind its own authentication:
+//   GET  /api/v1/bookings/{id}
+//   GET  /api/v1/bookings/{id}/receipt
+//   POST /api/v1/bookings/{id}/tip
+//   GET  /api/v1/bookings/{id}/dispute
+//   POST /api/v1/bookings/{id}/dispute
+//   POST /api/v1/bookings/{id}/rebook
+//   POST /api/v1/bookings/{id}/cancel
+//   POST /api/v1/bookings/{id}/changes
+//   POST /api/v1/bookings/{id}/changes/{changeId}/accept
+//   POST /api/v1/bookings/{id}/changes/{changeId}/reject
+//   POST /api/v1/bookings/{id}/accept
+//   POST /api/v1/bookings/{id}/decline
+//   POST /api/v1/bookings/{id}/en-route
+//   POST /api/v1/bookings/{id}/check-in
+//   POST /api/v1/bookings/{id}/check-out
+//   POST /api/v1/bookings/{id}/summary
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func BookingHandler(w http.ResponseWriter, r *http.Request) {
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/bookings/"), "/"), "/")
+    if parts[0] == "" {
+        http.Error(w, "Booking ID is required", http.StatusBadRequest)
+        return
+    }
+
+    switch {
+    case len(parts) == 1 && r.Method == http.MethodGet:
+        GetBookingHandler(w, r)
+    case len(parts) == 2 && parts[1] == "receipt" && r.Method == http.MethodGet:
+        GetReceiptHandler(w, r, parts[0])
+    case len(parts) == 2 && parts[1] == "tip" && r.Method == http.MethodPost:
+        AddTipHandler(w, r, parts[0])
+    case len(parts) == 2 && parts[1] == "dispute":
+        BookingDisputeHandler(w, r, parts[0])
+    case len(parts) == 2 && parts[1] == "rebook" && r.Method == http.MethodPost:
+        RebookHandler(w, r, parts[0])
+    case len(parts) == 2 && parts[1] == "cancel" && r.Method == http.MethodPost:
+        CancelBookingHandler(w, r, parts[0])
+    case len(parts) == 2 && parts[1] == "accept" && r.Method == http.MethodPost:
+        RespondAssignmentHandler(w, r, parts[0], true)
+    case len(parts) == 2 && parts[1] == "decline" && r.Method == http.MethodPost:
+        RespondAssignmentHandler(w, r, parts[0], false)
+    case len(parts) == 2 && parts[1] == "en-route" && r.Method == http.MethodPost:
+        WalkerEnRouteHandler(w, r, parts[0])
+    case len(parts) == 2 && parts[1] == "check-in" && r.Method == http.MethodPost:
+        WalkerCheckInHandler(w, r, parts[0])
+    case len(parts) == 2 && parts[1] == "check-out" && r.Method == http.MethodPost:
+        WalkerCheckOutHandler(w, r, parts[0])
+    case len(parts) == 2 && parts[1] == "summary" && r.Method == http.MethodPost:
+        WalkSummaryHandler(w, r, parts[0])
+    case len(parts) == 2 && parts[1] == "changes" && r.Method == http.MethodPost:
+        ProposeBookingChangeHandler(w, r, parts[0])
+    case len(parts) == 4 && parts[1] == "changes" && r.Method == http.MethodPost:
+        switch parts[3] {
+        case "accept":
+            RespondBookingChangeHandler(w, r, parts[0], parts[2], true)
+        case "reject":
+            RespondBookingChangeHandler(w, r, parts[0], parts[2], false)
+        default:
+            http.NotFound(w, r)
+        }
+    case len(parts) <= 4:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+    default:
+        http.NotFound(w, r)
+    }
+}
+
+// ProposeBookingChangeHandler handles HTTP POST requests proposing a change to a booking, on
+// behalf of the owner authenticated by middleware.RequirePermission
+func ProposeBookingChangeHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var change models.BookingChange
+    if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+    change.BookingID = bookingID
+    change.ProposedBy = claims.ID
+
+    if err := service.ProposeBookingChangeService(r.Context(), &change); err != nil {
+        logger.LogError("Failed to propose booking change", map[string]interface{}{
+            "error":      err.Error(),
+            "bookingId":  bookingID,
+            "proposedBy": change.ProposedBy,
+        })
+        writeChangeError(w, err)
+        return
+    }
+
+    logger.LogInfo("Booking change proposed", map[string]interface{}{
+        "bookingId": bookingID,
+        "changeId":  change.ID,
+        "expiresAt": change.ExpiresAt,
+    })
+
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Booking change proposed; awaiting walker approval",
+        "data":    change,
+    })
+}
+
+// RespondBookingChangeHandler handles the walker's acceptance or rejection of a proposed change,
+// answered as the walker authenticated by middleware.RequirePermission
+func RespondBookingChangeHandler(w http.ResponseWriter, r *http.Request, bookingID, changeID string, accept bool) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var (
+        booking *models.Booking
+        err     error
+        message = "Booking change rejected"
+    )
+    if accept {
+        booking, err = service.AcceptBookingChangeService(r.Context(), bookingID, changeID, claims.ID)
+        message = "Booking change accepted"
+    } else {
+        err = service.RejectBookingChangeService(r.Context(), bookingID, changeID, claims.ID)
+    }
+
+    if err != nil {
+        logger.LogError("Failed to respond to booking change", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+            "changeId":  changeID,
+            "accept":    accept,
+        })
+        writeChangeError(w, err)
+        return
+    }
+
+    logger.LogInfo(message, map[string]interface{}{
+        "bookingId": bookingID,
+        "changeId":  changeID,
+        "walkerId":  claims.ID,
+    })
+
+    response := map[string]interface{}{
+        "success": true,
+        "message": message,
+    }
+    if booking != nil {
+        response["data"] = booking
+    }
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(response)
+}
+
+// writeChangeError maps booking change service errors to HTTP responses
+func writeChangeError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    case strings.Contains(err.Error(), "only the"):
+        http.Error(w, err.Error(), http.StatusForbidden)
+    case strings.Contains(err.Error(), "invalid change"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "booking conflict"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    default:
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+    }
+}
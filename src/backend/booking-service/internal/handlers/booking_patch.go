@@ -0,0 +1,107 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "errors"
+    "io"
+    "mime"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// maxPatchBytes bounds the body of a booking patch
+const maxPatchBytes = 64 << 10
+
+// PatchBookingHandler handles PATCH /api/v1/bookings/{id} with an application/merge-patch+json
+// body, changing only the fields the patch names and clearing those it sets to null. Owners may
+// change the dog, schedule and pickup point of a pending booking no walker has been assigned
+// to, and only the dog once one has; patching a field the booking's status does not allow is a
+// 409, and a field owners never change a 422. Rescheduling an assigned booking goes through
+// POST /api/v1/bookings/{id}/changes instead.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func PatchBookingHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+    w.Header().Set("Accept-Patch", models.MergePatchContentType)
+    locale := i18n.FromContext(r.Context())
+
+    path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/bookings/"), "/")
+    if path == "" {
+        http.Error(w, "Booking ID is required", http.StatusBadRequest)
+        return
+    }
+    if strings.Contains(path, "/") {
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+    bookingID := path
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != models.MergePatchContentType {
+        http.Error(w, "Content-Type must be "+models.MergePatchContentType, http.StatusUnsupportedMediaType)
+        return
+    }
+    patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchBytes))
+    if err != nil {
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    booking, err := service.PatchBookingService(r.Context(), bookingID, claims.ID, patch)
+    if err != nil {
+        logger.LogError("Failed to patch booking", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+            "ownerId":   claims.ID,
+        })
+
+        var patchErr *models.PatchError
+        var ruleErr *service.RuleError
+        var areaErr *service.ServiceAreaError
+        switch {
+        case errors.As(err, &patchErr) && patchErr.Status != "":
+            http.Error(w, err.Error(), http.StatusConflict)
+        case errors.As(err, &patchErr):
+            http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+        case errors.As(err, &ruleErr):
+            writeRuleError(w, r, ruleErr)
+        case errors.As(err, &areaErr):
+            writeServiceAreaError(w, r, areaErr)
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, "Booking not found with id: "+bookingID, http.StatusNotFound)
+        case strings.Contains(err.Error(), "only the booking's owner"):
+            http.Error(w, err.Error(), http.StatusForbidden)
+        case strings.Contains(err.Error(), "invalid patch"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "invalid booking data"), strings.Contains(err.Error(), "booking must be scheduled"):
+            http.Error(w, i18n.Localize(locale, err), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        default:
+            http.Error(w, i18n.T(locale, "error.internal"), http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Booking patched", map[string]interface{}{
+        "bookingId": booking.ID,
+        "ownerId":   booking.OwnerID,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    booking,
+    })
+}
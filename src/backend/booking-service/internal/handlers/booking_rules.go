@@ -0,0 +1,113 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// AdminBookingRulesHandler handles the rules new bookings are checked against, per region:
+//   GET    /api/v1/admin/booking-rules/{region} returns the rules the region's bookings are checked against
+//   PUT    /api/v1/admin/booking-rules/{region} replaces the region's rules
+//   DELETE /api/v1/admin/booking-rules/{region} returns the region to the default rules
+// The region "default" holds the rules of regions without their own. It must be wrapped in
+// middleware.RequirePermission.
+func AdminBookingRulesHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    region := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/booking-rules"), "/")
+    if region == "" || strings.Contains(region, "/") {
+        http.Error(w, "Not found", http.StatusNotFound)
+        return
+    }
+
+    var (
+        set *models.BookingRuleSet
+        err error
+    )
+    switch r.Method {
+    case http.MethodGet:
+        set, err = service.GetBookingRulesService(r.Context(), region)
+    case http.MethodPut:
+        set = &models.BookingRuleSet{}
+        if err := json.NewDecoder(r.Body).Decode(set); err != nil {
+            logger.LogError("Failed to decode request body", map[string]interface{}{
+                "error": err.Error(),
+                "path":  r.URL.Path,
+            })
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        err = service.SaveBookingRulesService(r.Context(), region, set)
+    case http.MethodDelete:
+        err = service.DeleteBookingRulesService(r.Context(), region)
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    if err != nil {
+        logger.LogError("Booking rules request failed", map[string]interface{}{
+            "error":   err.Error(),
+            "region":  region,
+            "actorId": claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid booking rules"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking rules not found"):
+            http.Error(w, err.Error(), http.StatusNotFound)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    if r.Method != http.MethodGet {
+        logger.LogInfo("Booking rules updated", map[string]interface{}{
+            "region":  region,
+            "method":  r.Method,
+            "actorId": claims.ID,
+        })
+    }
+
+    if r.Method == http.MethodDelete {
+        w.WriteHeader(http.StatusNoContent)
+        return
+    }
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    set,
+    })
+}
+
+// writeRuleError refuses a walk outside the booking window with 422 Unprocessable Entity and a
+// body naming the rule broken, so clients can tell it from other invalid bookings:
+//   {"success": false, "error": {"code": "booking_lead_time", "message": "...", "details": {"kind": "min_lead_time", "minutes": 120}}}
+func writeRuleError(w http.ResponseWriter, r *http.Request, err *service.RuleError) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusUnprocessableEntity)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": false,
+        "error": map[string]interface{}{
+            "code":    err.Code,
+            "message": i18n.Localize(i18n.FromContext(r.Context()), err),
+            "details": err.Rule,
+        },
+    })
+}
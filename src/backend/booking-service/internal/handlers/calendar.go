@@ -0,0 +1,102 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/url"
+    "strings"
+
+    "src/backend/booking-service/internal/calendar"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+    "src/backend/shared/utils/logger"
+)
+
+// CalendarURLHandler handles HTTP GET requests from a walker for the URL of their calendar
+// feed, which calendar apps such as Google Calendar can subscribe to
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CalendarURLHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    walkerID, ok := authenticatedWalker(w, r)
+    if !ok {
+        return
+    }
+
+    path, err := service.WalkerCalendarPathService(walkerID)
+    if err != nil {
+        logger.LogError("Failed to create calendar URL", map[string]interface{}{
+            "error":    err.Error(),
+            "walkerId": walkerID,
+        })
+        if strings.Contains(err.Error(), "calendar feeds unavailable") {
+            http.Error(w, "Calendar feeds are not available", http.StatusServiceUnavailable)
+            return
+        }
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    scheme := "https"
+    if r.TLS == nil && r.Header.Get("X-Forwarded-Proto") != "https" {
+        scheme = "http"
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "url": scheme + "://" + r.Host + path,
+        },
+    })
+}
+
+// CalendarFeedHandler handles HTTP GET requests for a walker's calendar feed at
+// /api/v1/calendars/{walkerId}.ics?token=..., serving their confirmed bookings as events
+func CalendarFeedHandler(w http.ResponseWriter, r *http.Request) {
+    name := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v1/calendars/")
+    if !strings.HasSuffix(name, ".ics") || strings.Contains(name, "/") {
+        http.NotFound(w, r)
+        return
+    }
+    walkerID, err := url.PathUnescape(strings.TrimSuffix(name, ".ics"))
+    if err != nil || walkerID == "" {
+        http.NotFound(w, r)
+        return
+    }
+
+    feed, err := service.WalkerCalendarService(r.Context(), walkerID, r.URL.Query().Get("token"))
+    if err != nil {
+        switch {
+        case strings.Contains(err.Error(), "calendar not found"):
+            http.NotFound(w, r)
+        case strings.Contains(err.Error(), "calendar feeds unavailable"):
+            http.Error(w, "Calendar feeds are not available", http.StatusServiceUnavailable)
+        default:
+            logger.LogError("Failed to build calendar feed", map[string]interface{}{
+                "error":    err.Error(),
+                "walkerId": walkerID,
+            })
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    w.Header().Set("Content-Type", calendar.ContentType)
+    w.Header().Set("Cache-Control", "private, no-cache")
+    w.WriteHeader(http.StatusOK)
+    w.Write(feed)
+}
+
+// authenticatedWalker returns the ID of the walker making the request. Other users are
+// refused, as calendars are only kept for walkers.
+func authenticatedWalker(w http.ResponseWriter, r *http.Request) (string, bool) {
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok || claims.Role != policy.RoleWalker {
+        http.Error(w, "Calendars are only available to walkers", http.StatusForbidden)
+        return "", false
+    }
+    return claims.ID, true
+}
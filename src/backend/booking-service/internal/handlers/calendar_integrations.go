@@ -0,0 +1,161 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/integrations"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// CalendarIntegrationHandler dispatches a walker's requests to manage connected calendars:
+//   GET    /api/v1/integrations/calendars
+//   GET    /api/v1/integrations/calendars/{provider}/connect
+//   DELETE /api/v1/integrations/calendars/{provider}
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CalendarIntegrationHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    walkerID, ok := authenticatedWalker(w, r)
+    if !ok {
+        return
+    }
+
+    path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/integrations/calendars"), "/")
+    parts := strings.Split(path, "/")
+    switch {
+    case path == "" && r.Method == http.MethodGet:
+        listCalendarConnections(w, r, walkerID)
+    case len(parts) == 2 && parts[1] == "connect" && r.Method == http.MethodGet:
+        connectCalendar(w, r, walkerID, parts[0])
+    case len(parts) == 1 && path != "" && r.Method == http.MethodDelete:
+        disconnectCalendar(w, r, walkerID, parts[0])
+    case len(parts) <= 2:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+    default:
+        http.NotFound(w, r)
+    }
+}
+
+// listCalendarConnections responds with the walker's connected calendars and the providers
+// they can connect
+func listCalendarConnections(w http.ResponseWriter, r *http.Request, walkerID string) {
+    connections, err := service.ListCalendarConnectionsService(r.Context(), walkerID)
+    if err != nil {
+        logger.LogError("Failed to list calendar connections", map[string]interface{}{
+            "error":    err.Error(),
+            "walkerId": walkerID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "connections": connections,
+            "providers":   integrations.Names(),
+        },
+    })
+}
+
+// connectCalendar responds with the provider page where the walker grants access
+func connectCalendar(w http.ResponseWriter, r *http.Request, walkerID, provider string) {
+    authURL, err := service.CalendarConnectURLService(r.Context(), walkerID, provider)
+    if err != nil {
+        switch {
+        case strings.Contains(err.Error(), "invalid calendar provider"):
+            http.Error(w, err.Error(), http.StatusNotFound)
+        case strings.Contains(err.Error(), "calendar sync unavailable"):
+            http.Error(w, "Calendar sync is not available", http.StatusServiceUnavailable)
+        default:
+            logger.LogError("Failed to start calendar connection", map[string]interface{}{
+                "error":    err.Error(),
+                "walkerId": walkerID,
+                "provider": provider,
+            })
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "url": authURL,
+        },
+    })
+}
+
+// disconnectCalendar stops writing walks to one of the walker's calendars
+func disconnectCalendar(w http.ResponseWriter, r *http.Request, walkerID, provider string) {
+    if err := service.DisconnectCalendarService(r.Context(), walkerID, provider); err != nil {
+        if strings.Contains(err.Error(), "calendar not found") {
+            http.Error(w, err.Error(), http.StatusNotFound)
+            return
+        }
+        logger.LogError("Failed to disconnect calendar", map[string]interface{}{
+            "error":    err.Error(),
+            "walkerId": walkerID,
+            "provider": provider,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    logger.LogInfo("Calendar disconnected", map[string]interface{}{
+        "walkerId": walkerID,
+        "provider": provider,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Calendar disconnected",
+    })
+}
+
+// CalendarCallbackHandler handles the redirect back from a calendar provider once a walker
+// has granted or refused access. The signed state identifies the walker, so no token is needed.
+func CalendarCallbackHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    query := r.URL.Query()
+    if reason := query.Get("error"); reason != "" {
+        http.Error(w, "Calendar access was not granted: "+reason, http.StatusBadRequest)
+        return
+    }
+
+    connection, err := service.CompleteCalendarConnectionService(r.Context(), query.Get("state"), query.Get("code"))
+    if err != nil {
+        logger.LogError("Failed to connect calendar", map[string]interface{}{
+            "error": err.Error(),
+        })
+        switch {
+        case strings.Contains(err.Error(), "invalid calendar"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "failed to connect calendar"):
+            http.Error(w, "Calendar could not be connected", http.StatusBadGateway)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Calendar connected", map[string]interface{}{
+        "walkerId": connection.WalkerID,
+        "provider": connection.Provider,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Calendar connected",
+        "data":    connection,
+    })
+}
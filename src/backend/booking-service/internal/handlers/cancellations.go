@@ -0,0 +1,62 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// CancelBookingHandler handles HTTP POST requests from owners cancelling their booking, as the
+// owner authenticated by middleware.RequirePermission. The booking in the response carries the
+// cancellation fee charged and the amount refunded.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CancelBookingHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    booking, err := service.CancelBookingService(r.Context(), bookingID, claims.ID)
+    if err != nil {
+        logger.LogError("Failed to cancel booking", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+            "ownerId":   claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid cancellation"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, "Booking not found with id: "+bookingID, http.StatusNotFound)
+        case strings.Contains(err.Error(), "only the booking's owner"):
+            http.Error(w, err.Error(), http.StatusForbidden)
+        case strings.Contains(err.Error(), "cancellation not allowed"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Booking cancelled by owner", map[string]interface{}{
+        "bookingId": booking.ID,
+        "fee":       booking.Cancellation.Fee,
+        "refund":    booking.Cancellation.Refund,
+        "refundId":  booking.Cancellation.RefundID,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    booking,
+    })
+}
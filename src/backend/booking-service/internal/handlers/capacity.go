@@ -0,0 +1,51 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/clock"
+    "src/backend/shared/utils/logger"
+)
+
+// defaultCapacityRange is how far ahead the capacity report looks when no range is given
+const defaultCapacityRange = 7 * 24 * time.Hour
+
+// AdminCapacityReportHandler handles HTTP GET requests for the upcoming supply and demand of
+// walks per region and hour. from and to (RFC 3339) default to the next 7 days and region
+// limits the report to one region. It must be wrapped in middleware.RequirePermission.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func AdminCapacityReportHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    now := clock.FromContext(r.Context()).Now()
+    from, to, ok := queryRange(w, r, now, now.Add(defaultCapacityRange))
+    if !ok {
+        return
+    }
+
+    report, err := service.CapacityReportService(r.Context(), from, to, r.URL.Query().Get("region"))
+    if err != nil {
+        logger.LogError("Failed to build capacity report", map[string]interface{}{
+            "error": err.Error(),
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid report range"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    report,
+    })
+}
@@ -0,0 +1,202 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// disputeRequest is the body of an owner's dispute
+type disputeRequest struct {
+    Reason string `json:"reason"`
+}
+
+// resolveDisputeRequest is the body of an admin's resolution of a dispute. Amount is the
+// refund, in the booking currency; zero refunds the booking's full total.
+type resolveDisputeRequest struct {
+    Refund     bool    `json:"refund"`
+    Amount     float64 `json:"amount"`
+    Resolution string  `json:"resolution"`
+}
+
+// IsBookingDisputePath reports whether path addresses the dispute of a booking
+func IsBookingDisputePath(path string) bool {
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/v1/bookings/"), "/"), "/")
+    return len(parts) == 2 && parts[1] == "dispute"
+}
+
+// BookingDisputeHandler handles an owner's dispute of a booking: POST raises it and GET returns
+// its progress. The owner is the user authenticated by middleware.RequirePermission.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func BookingDisputeHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var (
+        dispute *models.Dispute
+        err     error
+        ownerID = claims.ID
+        status  = http.StatusOK
+    )
+    switch r.Method {
+    case http.MethodGet:
+        dispute, err = service.GetBookingDisputeService(r.Context(), bookingID, ownerID)
+    case http.MethodPost:
+        var req disputeRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            logger.LogError("Failed to decode request body", map[string]interface{}{
+                "error": err.Error(),
+                "path":  r.URL.Path,
+            })
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        dispute, err = service.OpenDisputeService(r.Context(), bookingID, ownerID, req.Reason)
+        status = http.StatusCreated
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    if err != nil {
+        logger.LogError("Booking dispute request failed", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+            "ownerId":   ownerID,
+        })
+        writeDisputeError(w, err)
+        return
+    }
+
+    if r.Method == http.MethodPost {
+        logger.LogInfo("Booking disputed", map[string]interface{}{
+            "bookingId": bookingID,
+            "disputeId": dispute.ID,
+            "ownerId":   ownerID,
+        })
+    }
+
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    dispute,
+    })
+}
+
+// AdminDisputeHandler dispatches dispute review requests:
+//   GET  /api/v1/admin/disputes?status=open&limit=50
+//   GET  /api/v1/admin/disputes/{id}
+//   POST /api/v1/admin/disputes/{id}/review
+//   POST /api/v1/admin/disputes/{id}/resolve
+// It must be wrapped in middleware.RequirePermission.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func AdminDisputeHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/disputes"), "/"), "/")
+
+    var (
+        data interface{}
+        err  error
+    )
+    switch {
+    case parts[0] == "" && r.Method == http.MethodGet:
+        query := r.URL.Query()
+        limit := 0
+        if raw := query.Get("limit"); raw != "" {
+            if limit, err = strconv.Atoi(raw); err != nil {
+                http.Error(w, "Invalid limit", http.StatusBadRequest)
+                return
+            }
+        }
+        var disputes []models.Dispute
+        disputes, err = service.ListDisputesService(r.Context(), models.DisputeStatus(query.Get("status")), limit)
+        if disputes == nil {
+            disputes = []models.Dispute{}
+        }
+        data = disputes
+    case parts[0] != "" && len(parts) == 1 && r.Method == http.MethodGet:
+        data, err = service.GetDisputeService(r.Context(), parts[0])
+    case len(parts) == 2 && parts[1] == "review" && r.Method == http.MethodPost:
+        data, err = service.ReviewDisputeService(r.Context(), claims.ID, parts[0])
+    case len(parts) == 2 && parts[1] == "resolve" && r.Method == http.MethodPost:
+        var req resolveDisputeRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            logger.LogError("Failed to decode request body", map[string]interface{}{
+                "error": err.Error(),
+                "path":  r.URL.Path,
+            })
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        data, err = service.ResolveDisputeService(r.Context(), claims.ID, parts[0], req.Refund, req.Amount, req.Resolution)
+    case len(parts) <= 2:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    default:
+        http.NotFound(w, r)
+        return
+    }
+
+    if err != nil {
+        logger.LogError("Dispute request failed", map[string]interface{}{
+            "error":   err.Error(),
+            "path":    r.URL.Path,
+            "actorId": claims.ID,
+        })
+        writeDisputeError(w, err)
+        return
+    }
+
+    if r.Method == http.MethodPost {
+        logger.LogInfo("Dispute updated", map[string]interface{}{
+            "disputeId": parts[0],
+            "action":    parts[1],
+            "actorId":   claims.ID,
+        })
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    data,
+    })
+}
+
+// writeDisputeError maps dispute service errors to HTTP responses
+func writeDisputeError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "invalid dispute"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "not found"):
+        http.Error(w, err.Error(), http.StatusNotFound)
+    case strings.Contains(err.Error(), "only the booking's owner"):
+        http.Error(w, err.Error(), http.StatusForbidden)
+    case strings.Contains(err.Error(), "dispute not allowed"), strings.Contains(err.Error(), "dispute conflict"):
+        http.Error(w, err.Error(), http.StatusConflict)
+    case strings.Contains(err.Error(), "failed to refund dispute"):
+        http.Error(w, "Refund could not be issued; the dispute is still open", http.StatusBadGateway)
+    case strings.Contains(err.Error(), "refunds unavailable"):
+        http.Error(w, "Refunds are not available", http.StatusServiceUnavailable)
+    default:
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+    }
+}
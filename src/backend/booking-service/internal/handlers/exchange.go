@@ -0,0 +1,22 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "net/http"
+    "strings"
+)
+
+// currencyParam is the query parameter naming the currency a requester wants amounts shown in
+const currencyParam = "currency"
+
+// writeCurrencyError maps a failed currency conversion to a response
+func writeCurrencyError(w http.ResponseWriter, err error) {
+    switch {
+    case strings.Contains(err.Error(), "invalid currency"):
+        http.Error(w, err.Error(), http.StatusBadRequest)
+    case strings.Contains(err.Error(), "currency conversion unavailable"):
+        http.Error(w, "Currency conversion is not available", http.StatusServiceUnavailable)
+    default:
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+    }
+}
@@ -0,0 +1,104 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// AdminHolidaysHandler handles each region's holiday calendar:
+//   GET    /api/v1/admin/holidays/{region} lists the region's blackout dates and holidays
+//   PUT    /api/v1/admin/holidays/{region}/{date} adds or replaces the entry for a date
+//   DELETE /api/v1/admin/holidays/{region}/{date} removes the entry for a date
+// Dates of the region "default" apply in every region without an entry of its own for them.
+// It must be wrapped in middleware.RequirePermission.
+func AdminHolidaysHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/holidays"), "/"), "/")
+    if parts[0] == "" || len(parts) > 2 {
+        http.Error(w, "Not found", http.StatusNotFound)
+        return
+    }
+    region := parts[0]
+
+    var (
+        data interface{}
+        err  error
+    )
+    switch {
+    case len(parts) == 1 && r.Method == http.MethodGet:
+        var holidays []models.Holiday
+        holidays, err = service.ListHolidaysService(r.Context(), region)
+        if holidays == nil {
+            holidays = []models.Holiday{}
+        }
+        data = holidays
+    case len(parts) == 2 && r.Method == http.MethodPut:
+        holiday := &models.Holiday{}
+        if err := json.NewDecoder(r.Body).Decode(holiday); err != nil {
+            logger.LogError("Failed to decode request body", map[string]interface{}{
+                "error": err.Error(),
+                "path":  r.URL.Path,
+            })
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        err = service.SaveHolidayService(r.Context(), region, parts[1], holiday)
+        data = holiday
+    case len(parts) == 2 && r.Method == http.MethodDelete:
+        err = service.DeleteHolidayService(r.Context(), region, parts[1])
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    if err != nil {
+        logger.LogError("Holiday calendar request failed", map[string]interface{}{
+            "error":   err.Error(),
+            "region":  region,
+            "actorId": claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid holiday"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "holiday not found"):
+            http.Error(w, err.Error(), http.StatusNotFound)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    if r.Method != http.MethodGet {
+        logger.LogInfo("Holiday calendar updated", map[string]interface{}{
+            "region":  region,
+            "date":    parts[1],
+            "method":  r.Method,
+            "actorId": claims.ID,
+        })
+    }
+
+    if r.Method == http.MethodDelete {
+        w.WriteHeader(http.StatusNoContent)
+        return
+    }
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    data,
+    })
+}
@@ -0,0 +1,139 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// markReadRequest is the body marking notifications read: either their IDs, or all of them
+type markReadRequest struct {
+    IDs []string `json:"ids"`
+    All bool     `json:"all"`
+}
+
+// InboxHandler dispatches the signed-in user's requests to their notification center:
+//   GET  /api/v1/notifications?limit=20&before={id}&unread=true
+//   POST /api/v1/notifications/read
+//   POST /api/v1/notifications/{id}/read
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func InboxHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/notifications"), "/")
+    parts := strings.Split(path, "/")
+    switch {
+    case path == "" && r.Method == http.MethodGet:
+        listInbox(w, r, claims.ID)
+    case path == "read" && r.Method == http.MethodPost:
+        markInboxRead(w, r, claims.ID)
+    case len(parts) == 2 && parts[1] == "read" && r.Method == http.MethodPost:
+        markInboxNotificationRead(w, r, claims.ID, parts[0])
+    case path == "" || path == "read" || (len(parts) == 2 && parts[1] == "read"):
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+    default:
+        http.NotFound(w, r)
+    }
+}
+
+// listInbox responds with a page of the user's notifications
+func listInbox(w http.ResponseWriter, r *http.Request, userID string) {
+    query := r.URL.Query()
+    limit := 0
+    if raw := query.Get("limit"); raw != "" {
+        var err error
+        if limit, err = strconv.Atoi(raw); err != nil {
+            http.Error(w, "Invalid limit", http.StatusBadRequest)
+            return
+        }
+    }
+
+    page, err := service.ListInboxService(r.Context(), userID, query.Get("before"), query.Get("unread") == "true", limit)
+    if err != nil {
+        if strings.Contains(err.Error(), "invalid inbox request") {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        logger.LogError("Failed to list notifications", map[string]interface{}{
+            "error":  err.Error(),
+            "userId": userID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    page,
+    })
+}
+
+// markInboxRead marks the notifications in the request body read
+func markInboxRead(w http.ResponseWriter, r *http.Request, userID string) {
+    var req markReadRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    marked, err := service.MarkInboxReadService(r.Context(), userID, req.IDs, req.All)
+    if err != nil {
+        if strings.Contains(err.Error(), "invalid mark read request") {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        logger.LogError("Failed to mark notifications read", map[string]interface{}{
+            "error":  err.Error(),
+            "userId": userID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    map[string]int64{"marked": marked},
+    })
+}
+
+// markInboxNotificationRead marks one of the user's notifications read
+func markInboxNotificationRead(w http.ResponseWriter, r *http.Request, userID, id string) {
+    notification, err := service.MarkInboxNotificationReadService(r.Context(), userID, id)
+    if err != nil {
+        if strings.Contains(err.Error(), "notification not found") {
+            http.Error(w, "Notification not found", http.StatusNotFound)
+            return
+        }
+        logger.LogError("Failed to mark notification read", map[string]interface{}{
+            "error":          err.Error(),
+            "userId":         userID,
+            "notificationId": id,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    notification,
+    })
+}
@@ -0,0 +1,248 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// registerDeviceRequest is the body of a device registration
+type registerDeviceRequest struct {
+    Token    string `json:"token"`
+    Platform string `json:"platform"`
+}
+
+// notificationPreferencesRequest is the body replacing a user's notification preferences
+type notificationPreferencesRequest struct {
+    PushEnabled     bool     `json:"push_enabled"`
+    MutedCategories []string `json:"muted_categories"`
+    Locale          string   `json:"locale"`
+}
+
+// DeviceHandler dispatches the signed-in user's requests to manage the devices receiving
+// their push notifications:
+//   GET    /api/v1/notifications/devices
+//   POST   /api/v1/notifications/devices
+//   DELETE /api/v1/notifications/devices/{token}
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func DeviceHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    token := strings.Trim(strings.TrimPrefix(r.URL.EscapedPath(), "/api/v1/notifications/devices"), "/")
+    switch {
+    case token == "" && r.Method == http.MethodGet:
+        listDevices(w, r, claims.ID)
+    case token == "" && r.Method == http.MethodPost:
+        registerDevice(w, r, claims.ID)
+    case token != "" && r.Method == http.MethodDelete:
+        token, err := url.PathUnescape(token)
+        if err != nil {
+            http.NotFound(w, r)
+            return
+        }
+        unregisterDevice(w, r, claims.ID, token)
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+    }
+}
+
+// listDevices responds with the user's registered devices
+func listDevices(w http.ResponseWriter, r *http.Request, userID string) {
+    devices, err := service.ListDevicesService(r.Context(), userID)
+    if err != nil {
+        logger.LogError("Failed to list devices", map[string]interface{}{
+            "error":  err.Error(),
+            "userId": userID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+    if devices == nil {
+        devices = []models.Device{}
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    devices,
+    })
+}
+
+// registerDevice registers the device in the request body for the user's push notifications
+func registerDevice(w http.ResponseWriter, r *http.Request, userID string) {
+    var req registerDeviceRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    device, err := service.RegisterDeviceService(r.Context(), userID, req.Token, req.Platform)
+    if err != nil {
+        if strings.Contains(err.Error(), "invalid device") {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        logger.LogError("Failed to register device", map[string]interface{}{
+            "error":  err.Error(),
+            "userId": userID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    logger.LogInfo("Device registered", map[string]interface{}{
+        "userId":   userID,
+        "platform": device.Platform,
+    })
+
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    device,
+    })
+}
+
+// unregisterDevice stops push notifications to one of the user's devices
+func unregisterDevice(w http.ResponseWriter, r *http.Request, userID, token string) {
+    if err := service.UnregisterDeviceService(r.Context(), userID, token); err != nil {
+        if strings.Contains(err.Error(), "device not found") {
+            http.Error(w, "Device not found", http.StatusNotFound)
+            return
+        }
+        logger.LogError("Failed to unregister device", map[string]interface{}{
+            "error":  err.Error(),
+            "userId": userID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Device unregistered",
+    })
+}
+
+// NotificationPreferencesHandler handles the signed-in user's notification preferences: GET
+// returns them and PUT replaces them. A PUT without a locale keeps notifications in the
+// language negotiated for the request.
+func NotificationPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var (
+        prefs *models.NotificationPreferences
+        err   error
+    )
+    switch r.Method {
+    case http.MethodGet:
+        prefs, err = service.GetNotificationPreferencesService(r.Context(), claims.ID)
+    case http.MethodPut:
+        var req notificationPreferencesRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            logger.LogError("Failed to decode request body", map[string]interface{}{
+                "error": err.Error(),
+                "path":  r.URL.Path,
+            })
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        if req.Locale == "" {
+            req.Locale = i18n.FromContext(r.Context())
+        }
+        prefs, err = service.SaveNotificationPreferencesService(r.Context(), &models.NotificationPreferences{
+            UserID:          claims.ID,
+            PushEnabled:     req.PushEnabled,
+            MutedCategories: req.MutedCategories,
+            Locale:          req.Locale,
+        })
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    if err != nil {
+        if strings.Contains(err.Error(), "invalid notification preferences") {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        logger.LogError("Notification preferences request failed", map[string]interface{}{
+            "error":  err.Error(),
+            "userId": claims.ID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    prefs,
+    })
+}
+
+// AdminDeliveryReceiptsHandler handles HTTP GET requests for a user's recent push deliveries:
+//   GET /api/v1/admin/notifications/receipts?user_id=...&limit=50
+// It must be wrapped in middleware.RequirePermission.
+func AdminDeliveryReceiptsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    query := r.URL.Query()
+    userID := query.Get("user_id")
+    if userID == "" {
+        http.Error(w, "user_id is required", http.StatusBadRequest)
+        return
+    }
+    limit := 0
+    if raw := query.Get("limit"); raw != "" {
+        var err error
+        if limit, err = strconv.Atoi(raw); err != nil {
+            http.Error(w, "Invalid limit", http.StatusBadRequest)
+            return
+        }
+    }
+
+    receipts, err := service.ListDeliveryReceiptsService(r.Context(), userID, limit)
+    if err != nil {
+        logger.LogError("Failed to list delivery receipts", map[string]interface{}{
+            "error":  err.Error(),
+            "userId": userID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+    if receipts == nil {
+        receipts = []models.DeliveryReceipt{}
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    receipts,
+    })
+}
@@ -0,0 +1,222 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "errors"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+    "src/backend/shared/utils/logger"
+)
+
+// SaveRatePlanHandler handles HTTP PUT requests setting a walker's base rate and surcharges.
+// Walkers authenticated by middleware.RequirePermission set their own plan; admins set any.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func SaveRatePlanHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var plan models.RatePlan
+    if err := json.NewDecoder(r.Body).Decode(&plan); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+    if claims.Role != policy.RoleAdmin {
+        if plan.WalkerID != "" && plan.WalkerID != claims.ID {
+            http.Error(w, "Walkers can only set their own rate plan", http.StatusForbidden)
+            return
+        }
+        plan.WalkerID = claims.ID
+    }
+
+    if err := service.SaveRatePlanService(r.Context(), &plan); err != nil {
+        logger.LogError("Failed to save rate plan", map[string]interface{}{
+            "error":    err.Error(),
+            "walkerId": plan.WalkerID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid rate plan"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Rate plan saved successfully", map[string]interface{}{
+        "walkerId": plan.WalkerID,
+        "baseRate": plan.BaseRate,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Rate plan saved successfully",
+        "data":    plan,
+    })
+}
+
+// GetRatePlanHandler handles HTTP GET requests for a walker's rate plan
+func GetRatePlanHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    walkerID := r.URL.Query().Get("walker_id")
+    if walkerID == "" {
+        http.Error(w, "walker_id query parameter is required", http.StatusBadRequest)
+        return
+    }
+
+    plan, err := service.GetRatePlanService(r.Context(), walkerID)
+    if err != nil {
+        if strings.Contains(err.Error(), "rate plan not found") {
+            http.Error(w, err.Error(), http.StatusNotFound)
+            return
+        }
+        logger.LogError("Failed to retrieve rate plan", map[string]interface{}{
+            "error":    err.Error(),
+            "walkerId": walkerID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    plan,
+    })
+}
+
+// QuoteRateHandler handles HTTP GET requests pricing a walk from a walker's rate plan. The
+// walk is given by walker_id, scheduled_at (RFC3339) and the optional region, duration_minutes,
+// large_dog and extra_dogs query parameters. Walks outside the region's booking window are
+// refused with the rule's error code. A POST with the same parameters also holds the walker's
+// slot at the price quoted for the calling owner while they check out, answering with the hold,
+// whose ID the booking is then made with.
+func QuoteRateHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    query := r.URL.Query()
+    walkerID := query.Get("walker_id")
+    if walkerID == "" {
+        http.Error(w, "walker_id query parameter is required", http.StatusBadRequest)
+        return
+    }
+    scheduledAt, err := time.Parse(time.RFC3339, query.Get("scheduled_at"))
+    if err != nil {
+        http.Error(w, "Invalid scheduled_at format. Expected RFC3339", http.StatusBadRequest)
+        return
+    }
+    var (
+        durationMinutes int
+        largeDog        bool
+        extraDogs       int
+    )
+    if raw := query.Get("duration_minutes"); raw != "" {
+        if durationMinutes, err = strconv.Atoi(raw); err != nil {
+            http.Error(w, "Invalid duration_minutes", http.StatusBadRequest)
+            return
+        }
+    }
+    if raw := query.Get("large_dog"); raw != "" {
+        if largeDog, err = strconv.ParseBool(raw); err != nil {
+            http.Error(w, "Invalid large_dog", http.StatusBadRequest)
+            return
+        }
+    }
+    if raw := query.Get("extra_dogs"); raw != "" {
+        if extraDogs, err = strconv.Atoi(raw); err != nil {
+            http.Error(w, "Invalid extra_dogs", http.StatusBadRequest)
+            return
+        }
+    }
+
+    var quote interface{}
+    status := http.StatusOK
+    if r.Method == http.MethodPost {
+        claims, ok := middleware.UserFromContext(r.Context())
+        if !ok {
+            http.Error(w, "Authentication required", http.StatusUnauthorized)
+            return
+        }
+        quote, err = service.HoldQuoteService(r.Context(), claims.ID, walkerID, query.Get("region"), scheduledAt, durationMinutes, largeDog, extraDogs)
+        status = http.StatusCreated
+    } else {
+        quote, err = service.QuoteRateService(r.Context(), walkerID, query.Get("region"), scheduledAt, durationMinutes, largeDog, extraDogs)
+    }
+    if err != nil {
+        var ruleErr *service.RuleError
+        switch {
+        case errors.As(err, &ruleErr):
+            writeRuleError(w, r, ruleErr)
+        case strings.Contains(err.Error(), "invalid quote"), strings.Contains(err.Error(), "invalid booking data"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "rate plan not found"):
+            http.Error(w, err.Error(), http.StatusNotFound)
+        case strings.Contains(err.Error(), "booking conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        case strings.Contains(err.Error(), "walker not eligible"):
+            http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+        default:
+            logger.LogError("Failed to quote rate", map[string]interface{}{
+                "error":    err.Error(),
+                "walkerId": walkerID,
+            })
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    quote,
+    })
+}
+
+// ReleaseSlotHoldHandler handles HTTP DELETE requests to /api/v1/rates/holds/{id}, releasing
+// the calling owner's hold on a walker's slot before it expires
+func ReleaseSlotHoldHandler(w http.ResponseWriter, r *http.Request) {
+    holdID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/rates/holds/"), "/")
+    if holdID == "" || strings.Contains(holdID, "/") {
+        http.NotFound(w, r)
+        return
+    }
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    if err := service.ReleaseSlotHoldService(r.Context(), holdID, claims.ID); err != nil {
+        if strings.Contains(err.Error(), "slot hold not found") {
+            http.Error(w, err.Error(), http.StatusNotFound)
+            return
+        }
+        logger.LogError("Failed to release slot hold", map[string]interface{}{
+            "error":   err.Error(),
+            "holdId":  holdID,
+            "ownerId": claims.ID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+    w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,86 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+    "src/backend/shared/utils/logger"
+)
+
+// rebookRequest is the body of an owner's request to book a past walk again
+type rebookRequest struct {
+    ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// RebookHandler handles HTTP POST requests booking the walk of an earlier booking again,
+// with the same walker, dog and length, at a new time. Only the booking's owner can rebook
+// it, with an owner token checked by middleware.RequirePermission.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func RebookHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+    if claims.Role != policy.RoleOwner {
+        http.Error(w, "Only owners can rebook their walks", http.StatusForbidden)
+        return
+    }
+
+    var req rebookRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    booking, err := service.RebookService(r.Context(), bookingID, claims.ID, req.ScheduledAt)
+    if err != nil {
+        logger.LogError("Failed to rebook booking", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+            "ownerId":   claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, "Booking not found with id: "+bookingID, http.StatusNotFound)
+        case strings.Contains(err.Error(), "rebook not allowed"):
+            http.Error(w, err.Error(), http.StatusForbidden)
+        case strings.Contains(err.Error(), "invalid booking data"),
+            strings.Contains(err.Error(), "booking must be scheduled"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        case strings.Contains(err.Error(), "walker not eligible"):
+            http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Booking rebooked successfully", map[string]interface{}{
+        "bookingId":         booking.ID,
+        "originalBookingId": bookingID,
+        "walkerId":          booking.WalkerID,
+    })
+
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Booking created successfully",
+        "data":    booking,
+    })
+}
@@ -0,0 +1,85 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/receipts"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+    "src/backend/shared/utils/logger"
+)
+
+// IsBookingReceiptPath reports whether path addresses the receipt of a booking
+func IsBookingReceiptPath(path string) bool {
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/v1/bookings/"), "/"), "/")
+    return len(parts) == 2 && parts[1] == "receipt"
+}
+
+// GetReceiptHandler handles HTTP GET requests for a completed booking's receipt, from the
+// booking's owner or an admin authenticated by middleware.RequirePermission. The receipt is
+// returned as JSON, or as its rendered document with ?format=pdf or Accept: application/pdf.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func GetReceiptHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+    ownerID := claims.ID
+    if claims.Role == policy.RoleAdmin {
+        ownerID = ""
+    }
+
+    asDocument := r.URL.Query().Get("format") == "pdf" || strings.Contains(r.Header.Get("Accept"), receipts.ContentTypePDF)
+
+    var (
+        receipt  interface{}
+        document []byte
+        err      error
+    )
+    if asDocument {
+        document, err = service.GetReceiptDocumentService(r.Context(), bookingID, ownerID)
+    } else {
+        receipt, err = service.GetReceiptService(r.Context(), bookingID, ownerID)
+    }
+
+    if err != nil {
+        logger.LogError("Failed to retrieve receipt", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, "Booking not found with id: "+bookingID, http.StatusNotFound)
+        case strings.Contains(err.Error(), "receipt forbidden"):
+            http.Error(w, err.Error(), http.StatusForbidden)
+        case strings.Contains(err.Error(), "receipt not available"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        case strings.Contains(err.Error(), "receipts unavailable"):
+            http.Error(w, "Receipts are not available", http.StatusServiceUnavailable)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    if asDocument {
+        w.Header().Set("Content-Type", receipts.ContentTypePDF)
+        w.Header().Set("Content-Disposition", `inline; filename="receipt.pdf"`)
+        w.WriteHeader(http.StatusOK)
+        w.Write(document)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    receipt,
+    })
+}
@@ -0,0 +1,80 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// AdminReconciliationHandler serves the payment reconciliation reports:
+//   GET /api/v1/admin/reconciliation?limit=30  lists recent runs
+//   GET /api/v1/admin/reconciliation/{id}      returns a run with its discrepancies
+// It must be wrapped in middleware.RequirePermission.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func AdminReconciliationHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    if r.Method != http.MethodGet {
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/reconciliation"), "/")
+    if strings.Contains(id, "/") {
+        http.NotFound(w, r)
+        return
+    }
+
+    var data interface{}
+    if id == "" {
+        limit := 0
+        if raw := r.URL.Query().Get("limit"); raw != "" {
+            parsed, err := strconv.Atoi(raw)
+            if err != nil {
+                http.Error(w, "Invalid limit", http.StatusBadRequest)
+                return
+            }
+            limit = parsed
+        }
+
+        runs, err := service.ListReconciliationRunsService(r.Context(), limit)
+        if err != nil {
+            logger.LogError("Failed to list reconciliation runs", map[string]interface{}{
+                "error": err.Error(),
+            })
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+        if runs == nil {
+            runs = []models.ReconciliationRun{}
+        }
+        data = runs
+    } else {
+        run, err := service.GetReconciliationRunService(r.Context(), id)
+        if err != nil {
+            if strings.Contains(err.Error(), "not found") {
+                http.Error(w, err.Error(), http.StatusNotFound)
+                return
+            }
+            logger.LogError("Failed to retrieve reconciliation run", map[string]interface{}{
+                "error": err.Error(),
+                "runId": id,
+            })
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+        data = run
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    data,
+    })
+}
@@ -0,0 +1,210 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/clock"
+    "src/backend/shared/policy"
+    "src/backend/shared/utils/logger"
+)
+
+// referralSignupRequest is the body of a signup attribution request. UserID is only read from
+// admins attributing a signup on a user's behalf.
+type referralSignupRequest struct {
+    Code   string `json:"code"`
+    UserID string `json:"user_id"`
+}
+
+// GetReferralCodeHandler handles HTTP GET requests for the referral code of the user
+// authenticated by middleware.RequirePermission; admins can name another with user_id
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func GetReferralCodeHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+    userID := claims.ID
+    if requested := r.URL.Query().Get("user_id"); requested != "" && requested != userID {
+        if claims.Role != policy.RoleAdmin {
+            http.Error(w, "Users can only see their own referral code", http.StatusForbidden)
+            return
+        }
+        userID = requested
+    }
+
+    code, err := service.GetReferralCodeService(r.Context(), userID)
+    if err != nil {
+        logger.LogError("Failed to retrieve referral code", map[string]interface{}{
+            "error":  err.Error(),
+            "userId": userID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    code,
+    })
+}
+
+// ReferralSignupHandler handles HTTP POST requests attributing the new user authenticated by
+// middleware.RequirePermission to a referral code; admins can attribute another with user_id
+func ReferralSignupHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var req referralSignupRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+    if req.UserID == "" || claims.Role != policy.RoleAdmin {
+        if req.UserID != "" && req.UserID != claims.ID {
+            http.Error(w, "Users can only attribute their own signup", http.StatusForbidden)
+            return
+        }
+        req.UserID = claims.ID
+    }
+
+    referral, err := service.AttributeReferralService(r.Context(), req.Code, req.UserID)
+    if err != nil {
+        logger.LogError("Failed to attribute referral", map[string]interface{}{
+            "error":  err.Error(),
+            "code":   req.Code,
+            "userId": req.UserID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid referral"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "referral conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Referral attributed", map[string]interface{}{
+        "referralId": referral.ID,
+        "referrerId": referral.ReferrerID,
+        "refereeId":  referral.RefereeID,
+    })
+
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Referral attributed successfully",
+        "data":    referral,
+    })
+}
+
+// ReferralReportHandler handles HTTP GET requests for referral conversions and credits granted.
+// It must be wrapped in middleware.RequirePermission for the admin reports. The range is given by from and to (RFC 3339) and defaults to the last 30 days. Credits are
+// reported in the booking currency, or in the currency named by ?currency=.
+func ReferralReportHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    from, to, ok := reportRange(w, r)
+    if !ok {
+        return
+    }
+
+    query := r.URL.Query()
+
+    currency := query.Get(currencyParam)
+    if currency == "" {
+        currency = models.BookingCurrency
+    }
+    rate, err := service.ExchangeRateService(r.Context(), currency)
+    if err != nil {
+        logger.LogError("Failed to convert referral report", map[string]interface{}{
+            "error":    err.Error(),
+            "currency": currency,
+        })
+        writeCurrencyError(w, err)
+        return
+    }
+
+    report, err := service.ReferralReportService(r.Context(), from, to)
+    if err != nil {
+        logger.LogError("Failed to build referral report", map[string]interface{}{
+            "error": err.Error(),
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid report range"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    for i := range report {
+        report[i].CreditsGranted = rate.Convert(report[i].CreditsGranted)
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "from":          from,
+            "to":            to,
+            "currency":      rate.To,
+            "exchange_rate": rate,
+            "referrers":     report,
+        },
+    })
+}
+
+// reportRange reads a report's range from the from and to query parameters (RFC 3339),
+// defaulting to the last 30 days. It writes the error response when either is invalid.
+func reportRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+    to := clock.FromContext(r.Context()).Now()
+    return queryRange(w, r, to.AddDate(0, 0, -30), to)
+}
+
+// queryRange reads a range from the from and to query parameters (RFC 3339), defaulting to
+// from and to. It writes the error response when either is invalid.
+func queryRange(w http.ResponseWriter, r *http.Request, from, to time.Time) (time.Time, time.Time, bool) {
+    query := r.URL.Query()
+    if v := query.Get("from"); v != "" {
+        parsed, err := time.Parse(time.RFC3339, v)
+        if err != nil {
+            http.Error(w, "from must be an RFC 3339 timestamp", http.StatusBadRequest)
+            return time.Time{}, time.Time{}, false
+        }
+        from = parsed
+    }
+    if v := query.Get("to"); v != "" {
+        parsed, err := time.Parse(time.RFC3339, v)
+        if err != nil {
+            http.Error(w, "to must be an RFC 3339 timestamp", http.StatusBadRequest)
+            return time.Time{}, time.Time{}, false
+        }
+        to = parsed
+    }
+    return from, to, true
+}
@@ -0,0 +1,129 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/regions"
+    "src/backend/shared/utils/logger"
+)
+
+// ListRegionsHandler handles HTTP GET requests for every service region and its boundary. The
+// tracking service polls it to tag walks with their region.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ListRegionsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    list, err := service.ListRegionsService(r.Context())
+    if err != nil {
+        logger.LogError("Failed to list regions", map[string]interface{}{
+            "error": err.Error(),
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+    if list == nil {
+        list = []regions.Region{}
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    list,
+    })
+}
+
+// AdminRegionHandler handles the definition of service regions: PUT /api/v1/admin/regions/{id}
+// with a name and boundary creates or replaces the region and DELETE removes it. It must be
+// wrapped in middleware.RequirePermission.
+func AdminRegionHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/regions"), "/")
+    if id == "" || strings.Contains(id, "/") {
+        http.Error(w, "Not found", http.StatusNotFound)
+        return
+    }
+
+    var (
+        region regions.Region
+        err    error
+    )
+    switch r.Method {
+    case http.MethodPut:
+        if err := json.NewDecoder(r.Body).Decode(&region); err != nil {
+            logger.LogError("Failed to decode request body", map[string]interface{}{
+                "error": err.Error(),
+                "path":  r.URL.Path,
+            })
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        err = service.SaveRegionService(r.Context(), id, &region)
+    case http.MethodDelete:
+        err = service.DeleteRegionService(r.Context(), id)
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    if err != nil {
+        logger.LogError("Region request failed", map[string]interface{}{
+            "error":    err.Error(),
+            "regionId": id,
+            "actorId":  claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid region"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "region not found"):
+            http.Error(w, err.Error(), http.StatusNotFound)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Region updated", map[string]interface{}{
+        "regionId": id,
+        "method":   r.Method,
+        "actorId":  claims.ID,
+    })
+
+    if r.Method == http.MethodDelete {
+        w.WriteHeader(http.StatusNoContent)
+        return
+    }
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    region,
+    })
+}
+
+// writeServiceAreaError refuses a booking whose pickup lies outside every service region with
+// 422 Unprocessable Entity and service.CodeOutsideServiceArea, in the body writeRuleError uses
+func writeServiceAreaError(w http.ResponseWriter, r *http.Request, err *service.ServiceAreaError) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusUnprocessableEntity)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": false,
+        "error": map[string]interface{}{
+            "code":    service.CodeOutsideServiceArea,
+            "message": i18n.Localize(i18n.FromContext(r.Context()), err),
+            "details": err,
+        },
+    })
+}
@@ -0,0 +1,125 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/clock"
+    "src/backend/shared/utils/logger"
+)
+
+// AdminReportSchedulesHandler handles HTTP GET requests listing the admin reports emailed on
+// a schedule, with when each next runs and how its last run went. It must be wrapped in
+// middleware.RequirePermission.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func AdminReportSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    schedules, err := service.ListReportSchedulesService(r.Context(), clock.FromContext(r.Context()).Now())
+    if err != nil {
+        logger.LogError("Failed to list report schedules", map[string]interface{}{
+            "error": err.Error(),
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    schedules,
+    })
+}
+
+// AdminReportRunsHandler handles HTTP GET requests for the run history of the scheduled
+// reports, most recent first. schedule limits the runs to one schedule and limit (default 30)
+// caps how many are returned. It must be wrapped in middleware.RequirePermission.
+func AdminReportRunsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    limit := 0
+    if raw := r.URL.Query().Get("limit"); raw != "" {
+        parsed, err := strconv.Atoi(raw)
+        if err != nil {
+            http.Error(w, "Invalid limit", http.StatusBadRequest)
+            return
+        }
+        limit = parsed
+    }
+
+    runs, err := service.ListReportRunsService(r.Context(), r.URL.Query().Get("schedule"), limit)
+    if err != nil {
+        logger.LogError("Failed to list report runs", map[string]interface{}{
+            "error": err.Error(),
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+    if runs == nil {
+        runs = []models.ReportRun{}
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    runs,
+    })
+}
+
+// RunReportHandler handles HTTP POST requests to /api/v1/admin/reports/schedules/{name}/run,
+// emailing a scheduled report now to its recipients. It must be wrapped in
+// middleware.RequirePermission.
+func RunReportHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/reports/schedules"), "/"), "/")
+    if len(parts) != 2 || parts[0] == "" || parts[1] != "run" {
+        http.NotFound(w, r)
+        return
+    }
+
+    run, err := service.RunReportService(r.Context(), parts[0], clock.FromContext(r.Context()).Now())
+    if err != nil {
+        logger.LogError("Failed to run scheduled report", map[string]interface{}{
+            "error":    err.Error(),
+            "schedule": parts[0],
+            "actorId":  claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "not found"):
+            http.Error(w, err.Error(), http.StatusNotFound)
+        case strings.Contains(err.Error(), "already sent"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        case strings.Contains(err.Error(), "failed to send report"):
+            http.Error(w, "Report could not be sent; operations have been alerted", http.StatusBadGateway)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Scheduled report run", map[string]interface{}{
+        "schedule": run.Schedule,
+        "runId":    run.ID,
+        "actorId":  claims.ID,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    run,
+    })
+}
@@ -0,0 +1,113 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// WalkerCheckInHandler handles HTTP POST requests from the assigned walker checking in at the
+// pickup of a confirmed booking, which starts the walk once their tracked position is verified.
+// The walker is the user authenticated by middleware.RequirePermission.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func WalkerCheckInHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    booking, err := service.CheckInService(r.Context(), bookingID, claims.ID)
+    if err != nil {
+        logger.LogError("Failed to check walker in", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+            "walkerId":  claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, "Booking not found", http.StatusNotFound)
+        case strings.Contains(err.Error(), "invalid check-in"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        case strings.Contains(err.Error(), "check-in not allowed"):
+            http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+        case strings.Contains(err.Error(), "check-in unavailable"):
+            http.Error(w, "Check-in is temporarily unavailable", http.StatusServiceUnavailable)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Walker checked in", map[string]interface{}{
+        "bookingId": bookingID,
+        "walkerId":  claims.ID,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Walker checked in",
+        "data":    booking,
+    })
+}
+
+// WalkerCheckOutHandler handles HTTP POST requests from the walker of an in-progress booking
+// checking out as the walk ends, which completes the booking once any proof of the walk
+// required has been tracked. The walker is the user authenticated by middleware.RequirePermission.
+func WalkerCheckOutHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    booking, err := service.CheckOutService(r.Context(), bookingID, claims.ID)
+    if err != nil {
+        logger.LogError("Failed to check walker out", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+            "walkerId":  claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, "Booking not found", http.StatusNotFound)
+        case strings.Contains(err.Error(), "invalid check-out"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        case strings.Contains(err.Error(), "completion not allowed"):
+            http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+        case strings.Contains(err.Error(), "completion unavailable"):
+            http.Error(w, "Check-out is temporarily unavailable", http.StatusServiceUnavailable)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Walker checked out", map[string]interface{}{
+        "bookingId": bookingID,
+        "walkerId":  claims.ID,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Walker checked out",
+        "data":    booking,
+    })
+}
@@ -0,0 +1,163 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// enRouteRequest is the body of a walker's notice that they are on the way
+type enRouteRequest struct {
+    ETAMinutes int `json:"eta_minutes"`
+}
+
+// SMSSettingsHandler handles the phone number the signed-in user is texted at: GET returns it
+// with whether it has opted out and PUT replaces both
+func SMSSettingsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var (
+        settings *models.SMSSettings
+        err      error
+    )
+    switch r.Method {
+    case http.MethodGet:
+        settings, err = service.GetSMSSettingsService(r.Context(), claims.ID)
+    case http.MethodPut:
+        var req models.SMSSettings
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            logger.LogError("Failed to decode request body", map[string]interface{}{
+                "error": err.Error(),
+                "path":  r.URL.Path,
+            })
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        settings, err = service.SaveSMSSettingsService(r.Context(), claims.ID, &req)
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    if err != nil {
+        if strings.Contains(err.Error(), "invalid SMS settings") {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        logger.LogError("SMS settings request failed", map[string]interface{}{
+            "error":  err.Error(),
+            "userId": claims.ID,
+        })
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    settings,
+    })
+}
+
+// InboundSMSHandler handles replies to texts posted by the messaging service, opting senders
+// out on STOP and back in on START. It responds with an empty TwiML document so no reply is sent.
+func InboundSMSHandler(w http.ResponseWriter, r *http.Request) {
+    if err := r.ParseForm(); err != nil {
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    changed, err := service.ReceiveSMSService(r.Context(), r.PostForm, r.Header.Get("X-Twilio-Signature"))
+    if err != nil {
+        switch {
+        case strings.Contains(err.Error(), "unavailable"):
+            http.Error(w, "SMS replies are not configured", http.StatusServiceUnavailable)
+        case strings.Contains(err.Error(), "signature"):
+            http.Error(w, "Invalid signature", http.StatusForbidden)
+        case strings.Contains(err.Error(), "invalid SMS reply"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        default:
+            logger.LogError("Failed to receive SMS reply", map[string]interface{}{
+                "error": err.Error(),
+            })
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+    if changed {
+        logger.LogInfo("SMS opt-out updated by reply", map[string]interface{}{
+            "messageSid": r.PostForm.Get("MessageSid"),
+        })
+    }
+
+    w.Header().Set("Content-Type", "text/xml")
+    w.WriteHeader(http.StatusOK)
+    w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Response></Response>`))
+}
+
+// WalkerEnRouteHandler handles HTTP POST requests from the assigned walker saying they are on
+// the way to a confirmed booking; the owner is texted unless they have opted out. The walker is
+// the user authenticated by middleware.RequirePermission.
+func WalkerEnRouteHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var req enRouteRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    booking, err := service.WalkerEnRouteService(r.Context(), bookingID, claims.ID, req.ETAMinutes)
+    if err != nil {
+        switch {
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, "Booking not found", http.StatusNotFound)
+        case strings.Contains(err.Error(), "invalid en route update"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        default:
+            logger.LogError("Failed to record walker en route", map[string]interface{}{
+                "error":     err.Error(),
+                "bookingId": bookingID,
+            })
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Walker en route", map[string]interface{}{
+        "bookingId":  bookingID,
+        "walkerId":   claims.ID,
+        "etaMinutes": req.ETAMinutes,
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Owner notified that the walker is on the way",
+        "data":    booking,
+    })
+}
@@ -0,0 +1,165 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "math"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+    "src/backend/shared/utils/logger"
+)
+
+// tipRequest is the body of an owner's tip
+type tipRequest struct {
+    Amount float64 `json:"amount"`
+}
+
+// AddTipHandler handles HTTP POST requests adding a tip to a completed booking. The tip is
+// charged to the owner authenticated by middleware.RequirePermission.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func AddTipHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var req tipRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+
+    tip, charge, err := service.AddTipService(r.Context(), bookingID, claims.ID, req.Amount)
+    if err != nil {
+        logger.LogError("Failed to add tip", map[string]interface{}{
+            "error":     err.Error(),
+            "bookingId": bookingID,
+            "ownerId":   claims.ID,
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid tip"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, "Booking not found with id: "+bookingID, http.StatusNotFound)
+        case strings.Contains(err.Error(), "only the booking's owner"):
+            http.Error(w, err.Error(), http.StatusForbidden)
+        case strings.Contains(err.Error(), "tip not allowed"), strings.Contains(err.Error(), "tip conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        case strings.Contains(err.Error(), "failed to charge tip"):
+            http.Error(w, "Tip payment could not be requested", http.StatusBadGateway)
+        case strings.Contains(err.Error(), "tips unavailable"):
+            http.Error(w, "Tips are not available", http.StatusServiceUnavailable)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Tip added", map[string]interface{}{
+        "bookingId": bookingID,
+        "tipId":     tip.ID,
+        "walkerId":  tip.WalkerID,
+        "paymentId": tip.PaymentID,
+    })
+
+    w.WriteHeader(http.StatusCreated)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "tip":     tip,
+            "payment": charge,
+        },
+    })
+}
+
+// EarningsReportHandler handles HTTP GET requests for walker earnings: completed bookings and
+// tips over a range given by from and to (RFC 3339), by default the last 30 days. walker_id
+// and region limit the report to one walker or region, and currency converts it as for the
+// referral report. Walkers authenticated by middleware.RequirePermission only see their own
+// earnings; admins see every walker's.
+func EarningsReportHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+    walkerID := r.URL.Query().Get("walker_id")
+    if claims.Role != policy.RoleAdmin {
+        if walkerID != "" && walkerID != claims.ID {
+            http.Error(w, "Walkers can only see their own earnings", http.StatusForbidden)
+            return
+        }
+        walkerID = claims.ID
+    }
+
+    from, to, ok := reportRange(w, r)
+    if !ok {
+        return
+    }
+
+    query := r.URL.Query()
+    currency := query.Get(currencyParam)
+    if currency == "" {
+        currency = models.BookingCurrency
+    }
+    rate, err := service.ExchangeRateService(r.Context(), currency)
+    if err != nil {
+        logger.LogError("Failed to convert earnings report", map[string]interface{}{
+            "error":    err.Error(),
+            "currency": currency,
+        })
+        writeCurrencyError(w, err)
+        return
+    }
+
+    report, err := service.WalkerEarningsReportService(r.Context(), from, to, walkerID, query.Get("region"))
+    if err != nil {
+        logger.LogError("Failed to build earnings report", map[string]interface{}{
+            "error": err.Error(),
+        })
+
+        switch {
+        case strings.Contains(err.Error(), "invalid report range"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        default:
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    for i := range report {
+        report[i].BookingAmount = rate.Convert(report[i].BookingAmount)
+        report[i].Tips = rate.Convert(report[i].Tips)
+        report[i].Total = math.Round((report[i].BookingAmount+report[i].Tips)*100) / 100
+    }
+    if report == nil {
+        report = []models.WalkerEarnings{}
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data": map[string]interface{}{
+            "from":          from,
+            "to":            to,
+            "currency":      rate.To,
+            "exchange_rate": rate,
+            "walkers":       report,
+        },
+    })
+}
@@ -0,0 +1,74 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// WalkSummaryHandler handles HTTP POST requests from the assigned walker summarising a walk;
+// the owner is emailed the summary with a map of the route. The walker is the user
+// authenticated by middleware.RequirePermission.
+func WalkSummaryHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+    w.Header().Set("Content-Type", "application/json")
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var summary models.WalkSummary
+    if err := json.NewDecoder(r.Body).Decode(&summary); err != nil {
+        logger.LogError("Failed to decode request body", map[string]interface{}{
+            "error": err.Error(),
+            "path":  r.URL.Path,
+        })
+        http.Error(w, "Invalid request body", http.StatusBadRequest)
+        return
+    }
+    summary.BookingID = bookingID
+    summary.WalkerID = claims.ID
+
+    sent, err := service.SendWalkSummaryService(r.Context(), &summary)
+    if err != nil {
+        switch {
+        case strings.Contains(err.Error(), "booking not found"):
+            http.Error(w, "Booking not found", http.StatusNotFound)
+        case strings.Contains(err.Error(), "invalid walk summary"):
+            http.Error(w, err.Error(), http.StatusBadRequest)
+        case strings.Contains(err.Error(), "booking conflict"):
+            http.Error(w, err.Error(), http.StatusConflict)
+        default:
+            logger.LogError("Failed to send walk summary", map[string]interface{}{
+                "error":     err.Error(),
+                "bookingId": bookingID,
+            })
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+        }
+        return
+    }
+
+    logger.LogInfo("Walk summary sent", map[string]interface{}{
+        "bookingId":   bookingID,
+        "walkerId":    sent.WalkerID,
+        "routePoints": len(sent.Route),
+    })
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "message": "Walk summary sent to the owner",
+        "data": map[string]interface{}{
+            "distance_km":      sent.DistanceKm,
+            "duration_minutes": sent.DurationMinutes,
+            "notes_withheld":   sent.NotesWithheld,
+        },
+    })
+}
@@ -0,0 +1,92 @@
+// Package handlers implements HTTP handlers for the Booking Service
+package handlers
+
+import (
+    "encoding/json"
+    "net/http"
+    "strings"
+
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/utils/logger"
+)
+
+// verificationRequest is the body of a walker verification status change
+type verificationRequest struct {
+    Status models.WalkerVerificationStatus `json:"status"`
+    Reason string                          `json:"reason"`
+}
+
+// AdminWalkerHandler dispatches walker verification requests:
+//   GET  /api/v1/admin/walkers/{id}/verification
+//   POST /api/v1/admin/walkers/{id}/verification
+// It must be wrapped in middleware.RequirePermission.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func AdminWalkerHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/admin/walkers/"), "/"), "/")
+    if len(parts) != 2 || parts[0] == "" || parts[1] != "verification" {
+        http.NotFound(w, r)
+        return
+    }
+    walkerID := parts[0]
+
+    claims, ok := middleware.UserFromContext(r.Context())
+    if !ok {
+        http.Error(w, "Authentication required", http.StatusUnauthorized)
+        return
+    }
+
+    var (
+        verification *models.WalkerVerification
+        err          error
+    )
+    switch r.Method {
+    case http.MethodGet:
+        verification, err = service.GetWalkerVerificationService(r.Context(), walkerID)
+    case http.MethodPost:
+        var req verificationRequest
+        if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+            logger.LogError("Failed to decode request body", map[string]interface{}{
+                "error": err.Error(),
+                "path":  r.URL.Path,
+            })
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        verification, err = service.SetWalkerVerificationService(r.Context(), claims.ID, walkerID, req.Status, req.Reason)
+    default:
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    if err != nil {
+        logger.LogError("Walker verification request failed", map[string]interface{}{
+            "error":    err.Error(),
+            "walkerId": walkerID,
+            "actorId":  claims.ID,
+        })
+        if strings.Contains(err.Error(), "invalid verification") {
+            http.Error(w, err.Error(), http.StatusBadRequest)
+            return
+        }
+        http.Error(w, "Internal server error", http.StatusInternalServerError)
+        return
+    }
+
+    if r.Method == http.MethodPost {
+        logger.LogInfo("Walker verification status changed", map[string]interface{}{
+            "walkerId": walkerID,
+            "status":   verification.Status,
+            "actorId":  claims.ID,
+        })
+    }
+
+    w.WriteHeader(http.StatusOK)
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "success": true,
+        "data":    verification,
+    })
+}
@@ -0,0 +1,224 @@
+// Package i18n translates the messages the booking service shows to users
+package i18n
+
+import (
+    "context"
+    "embed"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "path"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Human Tasks:
+// 1. Have every new catalog entry translated before release; missing entries fall back to English
+// 2. Add a catalogs/<language>.json file to support another language
+
+// DefaultLocale is the language used when a user's language is unknown or not supported, and
+// for messages missing from a catalog
+const DefaultLocale = "en"
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+// catalogs holds the messages of each supported language, keyed by message key. Messages are
+// fmt format strings.
+var catalogs = loadCatalogs()
+
+// loadCatalogs parses the embedded catalogs, one file per base language
+func loadCatalogs() map[string]map[string]string {
+    files, err := catalogFiles.ReadDir("catalogs")
+    if err != nil {
+        panic(fmt.Sprintf("i18n: failed to read catalogs: %v", err))
+    }
+    loaded := make(map[string]map[string]string, len(files))
+    for _, file := range files {
+        data, err := catalogFiles.ReadFile(path.Join("catalogs", file.Name()))
+        if err != nil {
+            panic(fmt.Sprintf("i18n: failed to read catalog %s: %v", file.Name(), err))
+        }
+        messages := make(map[string]string)
+        if err := json.Unmarshal(data, &messages); err != nil {
+            panic(fmt.Sprintf("i18n: invalid catalog %s: %v", file.Name(), err))
+        }
+        loaded[strings.TrimSuffix(file.Name(), ".json")] = messages
+    }
+    if _, ok := loaded[DefaultLocale]; !ok {
+        panic("i18n: missing catalog for " + DefaultLocale)
+    }
+    return loaded
+}
+
+// Supported returns the supported languages in alphabetical order
+func Supported() []string {
+    locales := make([]string, 0, len(catalogs))
+    for locale := range catalogs {
+        locales = append(locales, locale)
+    }
+    sort.Strings(locales)
+    return locales
+}
+
+// Keys returns the message keys of a supported language in alphabetical order
+func Keys(locale string) []string {
+    keys := make([]string, 0, len(catalogs[locale]))
+    for key := range catalogs[locale] {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    return keys
+}
+
+// Normalize returns the supported language of a language tag such as "fr-CA", and false when
+// the language is not supported
+func Normalize(tag string) (string, bool) {
+    base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+    base, _, _ = strings.Cut(base, "_")
+    if _, ok := catalogs[base]; !ok {
+        return "", false
+    }
+    return base, true
+}
+
+// Negotiate picks the supported language a client prefers from an Accept-Language header,
+// or DefaultLocale when it accepts none of them
+func Negotiate(acceptLanguage string) string {
+    type preference struct {
+        locale  string
+        quality float64
+    }
+    var preferences []preference
+    for _, part := range strings.Split(acceptLanguage, ",") {
+        tag, params, _ := strings.Cut(part, ";")
+        quality := 1.0
+        if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+            parsed, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+            if err != nil {
+                continue
+            }
+            quality = parsed
+        }
+        locale, ok := Normalize(tag)
+        if !ok || quality <= 0 {
+            continue
+        }
+        preferences = append(preferences, preference{locale: locale, quality: quality})
+    }
+    if len(preferences) == 0 {
+        return DefaultLocale
+    }
+    sort.SliceStable(preferences, func(i, j int) bool { return preferences[i].quality > preferences[j].quality })
+    return preferences[0].locale
+}
+
+// T returns the message key in locale, formatted with args. Messages missing from the locale's
+// catalog are given in DefaultLocale, and unknown keys are returned as they are.
+func T(locale, key string, args ...interface{}) string {
+    message, ok := catalogs[locale][key]
+    if !ok {
+        if message, ok = catalogs[DefaultLocale][key]; !ok {
+            return key
+        }
+    }
+    if len(args) == 0 {
+        return message
+    }
+    localized := make([]interface{}, len(args))
+    for i, arg := range args {
+        if err, ok := arg.(error); ok {
+            arg = Localize(locale, err)
+        }
+        localized[i] = arg
+    }
+    return fmt.Sprintf(message, localized...)
+}
+
+// FormatTime formats t in locale as a day and time, such as "Tuesday 5 March at 09:30 UTC"
+func FormatTime(locale string, t time.Time) string {
+    return formatTime(locale, t, "long", "time.weekday.", "time.month.")
+}
+
+// FormatTimeShort formats t in locale as an abbreviated day and time, such as "Tue 5 Mar 09:30 UTC"
+func FormatTimeShort(locale string, t time.Time) string {
+    return formatTime(locale, t, "short", "time.weekday_short.", "time.month_short.")
+}
+
+// formatTime formats t with the named time format and day and month names of locale
+func formatTime(locale string, t time.Time, format, weekdayPrefix, monthPrefix string) string {
+    return T(locale, "time.format."+format,
+        T(locale, weekdayPrefix+strings.ToLower(t.Weekday().String())),
+        t.Day(),
+        T(locale, monthPrefix+strings.ToLower(t.Month().String())),
+        t.Format("15:04 MST"),
+    )
+}
+
+// Message is an error whose text is a catalog message, so it can be shown to each user in their
+// own language. Its Error text is the DefaultLocale message.
+type Message struct {
+    Key  string
+    Args []interface{}
+}
+
+// Errorf returns an error with the message key formatted with args. An error among args is
+// localized with the message and can be unwrapped.
+func Errorf(key string, args ...interface{}) error {
+    return &Message{Key: key, Args: args}
+}
+
+// Error returns the message in DefaultLocale
+func (m *Message) Error() string {
+    return T(DefaultLocale, m.Key, m.Args...)
+}
+
+// Unwrap returns the first error among the message's args
+func (m *Message) Unwrap() error {
+    for _, arg := range m.Args {
+        if err, ok := arg.(error); ok {
+            return err
+        }
+    }
+    return nil
+}
+
+// Localize returns err's text in locale when it is a Message, and its usual text otherwise
+func Localize(locale string, err error) string {
+    var message *Message
+    if !errors.As(err, &message) || message.Error() != err.Error() {
+        // Errors wrapping a message add text of their own that has no translation
+        return err.Error()
+    }
+    return T(locale, message.Key, message.Args...)
+}
+
+// contextKey is the type of context keys set by this package
+type contextKey struct{}
+
+// WithLocale returns a copy of ctx carrying locale
+func WithLocale(ctx context.Context, locale string) context.Context {
+    return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale stored by WithLocale or Middleware, or DefaultLocale
+func FromContext(ctx context.Context) string {
+    if locale, ok := ctx.Value(contextKey{}).(string); ok {
+        return locale
+    }
+    return DefaultLocale
+}
+
+// Middleware stores the language negotiated from each request's Accept-Language header in its
+// context, and names it in the response's Content-Language header
+func Middleware(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        locale := Negotiate(r.Header.Get("Accept-Language"))
+        w.Header().Set("Content-Language", locale)
+        w.Header().Add("Vary", "Accept-Language")
+        next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), locale)))
+    })
+}
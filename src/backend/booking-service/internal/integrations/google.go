@@ -0,0 +1,83 @@
+// Package integrations connects walkers' external calendars, such as Google Calendar and
+// Outlook, over OAuth so their walks can be written to them
+package integrations
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/url"
+    "time"
+)
+
+// googleEventsURL is the Google Calendar API collection of events in the walker's main calendar
+const googleEventsURL = "https://www.googleapis.com/calendar/v3/calendars/primary/events"
+
+// Google writes walks to the walker's primary Google Calendar
+type Google struct {
+    oauthClient
+    eventsURL string
+}
+
+// NewGoogle creates a Google Calendar provider for an OAuth client
+func NewGoogle(clientID, clientSecret, redirectURL string) *Google {
+    return &Google{
+        oauthClient: oauthClient{
+            authURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+            tokenURL:     "https://oauth2.googleapis.com/token",
+            clientID:     clientID,
+            clientSecret: clientSecret,
+            redirectURL:  redirectURL,
+            scopes:       []string{"https://www.googleapis.com/auth/calendar.events"},
+            // A refresh token is only issued for offline access granted on the consent screen
+            authParams: url.Values{"access_type": {"offline"}, "prompt": {"consent"}},
+            client:     &http.Client{Timeout: 10 * time.Second},
+        },
+        eventsURL: googleEventsURL,
+    }
+}
+
+// googleTime is a point in time in a Google Calendar event
+type googleTime struct {
+    DateTime string `json:"dateTime"`
+}
+
+// googleEvent is the Google Calendar API representation of an event
+type googleEvent struct {
+    ID          string     `json:"id,omitempty"`
+    Summary     string     `json:"summary"`
+    Description string     `json:"description,omitempty"`
+    Location    string     `json:"location,omitempty"`
+    Start       googleTime `json:"start"`
+    End         googleTime `json:"end"`
+}
+
+// SaveEvent implements Provider
+func (g *Google) SaveEvent(ctx context.Context, accessToken, eventID string, event Event) (string, error) {
+    body := googleEvent{
+        Summary:     event.Summary,
+        Description: event.Description,
+        Location:    event.Location,
+        Start:       googleTime{DateTime: event.Start.UTC().Format(time.RFC3339)},
+        End:         googleTime{DateTime: event.End.UTC().Format(time.RFC3339)},
+    }
+
+    method, endpoint := http.MethodPost, g.eventsURL
+    if eventID != "" {
+        method, endpoint = http.MethodPut, g.eventsURL+"/"+url.PathEscape(eventID)
+    }
+    var saved googleEvent
+    if err := g.sendJSON(ctx, method, endpoint, accessToken, body, &saved); err != nil {
+        return "", err
+    }
+    return saved.ID, nil
+}
+
+// DeleteEvent implements Provider
+func (g *Google) DeleteEvent(ctx context.Context, accessToken, eventID string) error {
+    err := g.sendJSON(ctx, http.MethodDelete, g.eventsURL+"/"+url.PathEscape(eventID), accessToken, nil, nil)
+    if errors.Is(err, ErrEventNotFound) {
+        return nil
+    }
+    return err
+}
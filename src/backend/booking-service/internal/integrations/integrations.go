@@ -0,0 +1,290 @@
+// Package integrations connects walkers' external calendars, such as Google Calendar and
+// Outlook, over OAuth so their walks can be written to them
+package integrations
+
+import (
+    "bytes"
+    "context"
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Human Tasks:
+// 1. Register OAuth clients with Google and Microsoft whose redirect URL is BOOKING_CALENDAR_REDIRECT_URL
+// 2. Restrict access to the calendar_connections table; it holds walkers' OAuth refresh tokens
+
+// Provider names
+const (
+    ProviderGoogle    = "google"
+    ProviderMicrosoft = "microsoft"
+)
+
+// ErrEventNotFound is returned when an event no longer exists in the external calendar,
+// usually because the walker deleted it
+var ErrEventNotFound = errors.New("calendar event not found")
+
+// maxResponseSize bounds the responses read from providers
+const maxResponseSize = 1 << 20
+
+// Options configures the calendar providers; a provider without a client ID is unavailable
+type Options struct {
+    GoogleClientID        string
+    GoogleClientSecret    string
+    MicrosoftClientID     string
+    MicrosoftClientSecret string
+
+    // RedirectURL is where providers send walkers back to after they grant access
+    RedirectURL string
+}
+
+// Token is the access granted by a walker to their calendar
+type Token struct {
+    AccessToken  string
+    RefreshToken string
+    ExpiresAt    time.Time
+}
+
+// Event is a walk as written to an external calendar
+type Event struct {
+    Summary     string
+    Description string
+    Location    string
+    Start       time.Time
+    End         time.Time
+}
+
+// Provider is an external calendar service
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type Provider interface {
+    // AuthCodeURL returns the page a walker grants access on; state is returned to the redirect URL
+    AuthCodeURL(state string) string
+
+    // Exchange trades the code returned to the redirect URL for a token
+    Exchange(ctx context.Context, code string) (*Token, error)
+
+    // Refresh obtains a new access token; the refresh token is kept when none is returned
+    Refresh(ctx context.Context, refreshToken string) (*Token, error)
+
+    // SaveEvent creates the event, or updates it when eventID is set, returning its ID.
+    // ErrEventNotFound is returned when the event to update no longer exists.
+    SaveEvent(ctx context.Context, accessToken, eventID string, event Event) (string, error)
+
+    // DeleteEvent removes an event; events already removed are not an error
+    DeleteEvent(ctx context.Context, accessToken, eventID string) error
+}
+
+// providers are the configured providers by name, set by Init
+var providers = map[string]Provider{}
+
+// Init configures the providers that have a client ID
+func Init(opts Options) {
+    providers = map[string]Provider{}
+    if opts.GoogleClientID != "" {
+        providers[ProviderGoogle] = NewGoogle(opts.GoogleClientID, opts.GoogleClientSecret, opts.RedirectURL)
+    }
+    if opts.MicrosoftClientID != "" {
+        providers[ProviderMicrosoft] = NewMicrosoft(opts.MicrosoftClientID, opts.MicrosoftClientSecret, opts.RedirectURL)
+    }
+}
+
+// Register makes a provider available under name, replacing any configured one
+func Register(name string, provider Provider) {
+    providers[name] = provider
+}
+
+// Get returns the provider with the given name
+func Get(name string) (Provider, bool) {
+    provider, ok := providers[name]
+    return provider, ok
+}
+
+// Names returns the names of the configured providers, sorted
+func Names() []string {
+    names := make([]string, 0, len(providers))
+    for name := range providers {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// SignState returns the OAuth state tying a connection attempt to the walker and provider,
+// valid until expires
+func SignState(secret, walkerID, provider string, expires time.Time) string {
+    payload := base64.RawURLEncoding.EncodeToString([]byte(walkerID + "\n" + provider + "\n" + strconv.FormatInt(expires.Unix(), 10)))
+    return payload + "." + stateMAC(secret, payload)
+}
+
+// ParseState verifies an OAuth state and returns the walker and provider it was signed for
+func ParseState(secret, state string, now time.Time) (walkerID, provider string, err error) {
+    payload, mac, ok := strings.Cut(state, ".")
+    if !ok || secret == "" || !hmac.Equal([]byte(mac), []byte(stateMAC(secret, payload))) {
+        return "", "", fmt.Errorf("state signature is invalid")
+    }
+    decoded, err := base64.RawURLEncoding.DecodeString(payload)
+    if err != nil {
+        return "", "", fmt.Errorf("state is malformed")
+    }
+    fields := strings.Split(string(decoded), "\n")
+    if len(fields) != 3 {
+        return "", "", fmt.Errorf("state is malformed")
+    }
+    expires, err := strconv.ParseInt(fields[2], 10, 64)
+    if err != nil {
+        return "", "", fmt.Errorf("state is malformed")
+    }
+    if now.After(time.Unix(expires, 0)) {
+        return "", "", fmt.Errorf("state has expired")
+    }
+    return fields[0], fields[1], nil
+}
+
+// stateMAC signs an encoded state payload
+func stateMAC(secret, payload string) string {
+    mac := hmac.New(sha256.New, []byte(secret))
+    mac.Write([]byte("calendar-connect:" + payload))
+    return hex.EncodeToString(mac.Sum(nil))
+}
+
+// oauthClient implements the authorization code flow shared by the providers
+type oauthClient struct {
+    authURL      string
+    tokenURL     string
+    clientID     string
+    clientSecret string
+    redirectURL  string
+    scopes       []string
+    authParams   url.Values
+    client       *http.Client
+}
+
+// AuthCodeURL implements Provider
+func (c *oauthClient) AuthCodeURL(state string) string {
+    params := url.Values{
+        "client_id":     {c.clientID},
+        "redirect_uri":  {c.redirectURL},
+        "response_type": {"code"},
+        "scope":         {strings.Join(c.scopes, " ")},
+        "state":         {state},
+    }
+    for key, values := range c.authParams {
+        params[key] = values
+    }
+    return c.authURL + "?" + params.Encode()
+}
+
+// Exchange implements Provider
+func (c *oauthClient) Exchange(ctx context.Context, code string) (*Token, error) {
+    return c.requestToken(ctx, url.Values{
+        "grant_type":   {"authorization_code"},
+        "code":         {code},
+        "redirect_uri": {c.redirectURL},
+    })
+}
+
+// Refresh implements Provider
+func (c *oauthClient) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+    token, err := c.requestToken(ctx, url.Values{
+        "grant_type":    {"refresh_token"},
+        "refresh_token": {refreshToken},
+    })
+    if err != nil {
+        return nil, err
+    }
+    if token.RefreshToken == "" {
+        token.RefreshToken = refreshToken
+    }
+    return token, nil
+}
+
+// requestToken posts a grant to the token endpoint
+func (c *oauthClient) requestToken(ctx context.Context, form url.Values) (*Token, error) {
+    form.Set("client_id", c.clientID)
+    form.Set("client_secret", c.clientSecret)
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+    if err != nil {
+        return nil, fmt.Errorf("failed to create token request: %w", err)
+    }
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    req.Header.Set("Accept", "application/json")
+
+    var body struct {
+        AccessToken  string `json:"access_token"`
+        RefreshToken string `json:"refresh_token"`
+        ExpiresIn    int64  `json:"expires_in"`
+    }
+    if err := c.do(req, &body); err != nil {
+        return nil, fmt.Errorf("failed to obtain token: %w", err)
+    }
+    if body.AccessToken == "" {
+        return nil, fmt.Errorf("failed to obtain token: no access token returned")
+    }
+    return &Token{
+        AccessToken:  body.AccessToken,
+        RefreshToken: body.RefreshToken,
+        ExpiresAt:    time.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+    }, nil
+}
+
+// sendJSON sends an authorized API request with an optional JSON body, decoding any JSON
+// response into out
+func (c *oauthClient) sendJSON(ctx context.Context, method, endpoint, accessToken string, in, out interface{}) error {
+    var body io.Reader
+    if in != nil {
+        payload, err := json.Marshal(in)
+        if err != nil {
+            return fmt.Errorf("failed to encode request: %w", err)
+        }
+        body = bytes.NewReader(payload)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+    if err != nil {
+        return fmt.Errorf("failed to create request: %w", err)
+    }
+    req.Header.Set("Authorization", "Bearer "+accessToken)
+    if in != nil {
+        req.Header.Set("Content-Type", "application/json")
+    }
+    return c.do(req, out)
+}
+
+// do sends a request, decoding any JSON response into out. Non-2xx responses are errors;
+// 404 and 410 are returned as ErrEventNotFound.
+func (c *oauthClient) do(req *http.Request, out interface{}) error {
+    resp, err := c.client.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+    if err != nil {
+        return fmt.Errorf("failed to read response: %w", err)
+    }
+    switch {
+    case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+        return ErrEventNotFound
+    case resp.StatusCode < 200 || resp.StatusCode >= 300:
+        return fmt.Errorf("provider returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+    }
+    if out != nil && len(data) > 0 {
+        if err := json.Unmarshal(data, out); err != nil {
+            return fmt.Errorf("failed to decode response: %w", err)
+        }
+    }
+    return nil
+}
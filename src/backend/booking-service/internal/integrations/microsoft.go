@@ -0,0 +1,98 @@
+// Package integrations connects walkers' external calendars, such as Google Calendar and
+// Outlook, over OAuth so their walks can be written to them
+package integrations
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/url"
+    "time"
+)
+
+// microsoftEventsURL is the Microsoft Graph collection of events in the walker's default calendar
+const microsoftEventsURL = "https://graph.microsoft.com/v1.0/me/events"
+
+// microsoftTimeFormat is the local date-time format Graph pairs with a time zone
+const microsoftTimeFormat = "2006-01-02T15:04:05"
+
+// Microsoft writes walks to the walker's default Outlook calendar through Microsoft Graph
+type Microsoft struct {
+    oauthClient
+    eventsURL string
+}
+
+// NewMicrosoft creates an Outlook calendar provider for an OAuth client registered with
+// the Microsoft identity platform
+func NewMicrosoft(clientID, clientSecret, redirectURL string) *Microsoft {
+    return &Microsoft{
+        oauthClient: oauthClient{
+            authURL:      "https://login.microsoftonline.com/common/oauth2/v2.0/authorize",
+            tokenURL:     "https://login.microsoftonline.com/common/oauth2/v2.0/token",
+            clientID:     clientID,
+            clientSecret: clientSecret,
+            redirectURL:  redirectURL,
+            // offline_access is needed for a refresh token
+            scopes: []string{"offline_access", "Calendars.ReadWrite"},
+            client: &http.Client{Timeout: 10 * time.Second},
+        },
+        eventsURL: microsoftEventsURL,
+    }
+}
+
+// microsoftTime is a point in time in a Graph event
+type microsoftTime struct {
+    DateTime string `json:"dateTime"`
+    TimeZone string `json:"timeZone"`
+}
+
+// microsoftBody is the body of a Graph event
+type microsoftBody struct {
+    ContentType string `json:"contentType"`
+    Content     string `json:"content"`
+}
+
+// microsoftLocation is where a Graph event takes place
+type microsoftLocation struct {
+    DisplayName string `json:"displayName"`
+}
+
+// microsoftEvent is the Microsoft Graph representation of an event
+type microsoftEvent struct {
+    ID       string            `json:"id,omitempty"`
+    Subject  string            `json:"subject"`
+    Body     microsoftBody     `json:"body"`
+    Location microsoftLocation `json:"location"`
+    Start    microsoftTime     `json:"start"`
+    End      microsoftTime     `json:"end"`
+}
+
+// SaveEvent implements Provider
+func (m *Microsoft) SaveEvent(ctx context.Context, accessToken, eventID string, event Event) (string, error) {
+    body := microsoftEvent{
+        Subject:  event.Summary,
+        Body:     microsoftBody{ContentType: "text", Content: event.Description},
+        Location: microsoftLocation{DisplayName: event.Location},
+        Start:    microsoftTime{DateTime: event.Start.UTC().Format(microsoftTimeFormat), TimeZone: "UTC"},
+        End:      microsoftTime{DateTime: event.End.UTC().Format(microsoftTimeFormat), TimeZone: "UTC"},
+    }
+
+    method, endpoint := http.MethodPost, m.eventsURL
+    if eventID != "" {
+        method, endpoint = http.MethodPatch, m.eventsURL+"/"+url.PathEscape(eventID)
+    }
+    var saved microsoftEvent
+    if err := m.sendJSON(ctx, method, endpoint, accessToken, body, &saved); err != nil {
+        return "", err
+    }
+    return saved.ID, nil
+}
+
+// DeleteEvent implements Provider
+func (m *Microsoft) DeleteEvent(ctx context.Context, accessToken, eventID string) error {
+    err := m.sendJSON(ctx, http.MethodDelete, m.eventsURL+"/"+url.PathEscape(eventID), accessToken, nil, nil)
+    if errors.Is(err, ErrEventNotFound) {
+        return nil
+    }
+    return err
+}
@@ -0,0 +1,46 @@
+// Package metrics defines the Prometheus metrics exported by the booking-service
+// Version: 1.0.0
+
+package metrics
+
+import (
+    "math"
+    "strconv"
+
+    "github.com/prometheus/client_golang/prometheus" // v1.14.0
+)
+
+// Human Tasks:
+// 1. Chart out_of_area_bookings_total by cell on a map to find where demand outside the
+//    service regions justifies expanding them
+
+// outOfAreaCellDegrees is the size of the grid cells out-of-area demand is counted in; a tenth
+// of a degree is roughly 11 km, coarse enough to bound the label's cardinality and to keep
+// owners' addresses out of the metrics
+const outOfAreaCellDegrees = 0.1
+
+var (
+    // OutOfAreaBookings counts bookings refused because their pickup lies outside every
+    // service region, by the grid cell of the pickup
+    // Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+    OutOfAreaBookings = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Namespace: "booking",
+        Name:      "out_of_area_bookings_total",
+        Help:      "Bookings refused because their pickup lies outside every service region.",
+    }, []string{"cell"})
+)
+
+func init() {
+    prometheus.MustRegister(
+        OutOfAreaBookings,
+    )
+}
+
+// RecordOutOfArea counts a refused booking in the cell of its pickup, labelled by the cell's
+// south-west corner, e.g. "40.7,-74.1"
+func RecordOutOfArea(latitude, longitude float64) {
+    corner := func(degrees float64) string {
+        return strconv.FormatFloat(math.Floor(degrees/outOfAreaCellDegrees)*outOfAreaCellDegrees, 'f', 1, 64)
+    }
+    OutOfAreaBookings.WithLabelValues(corner(latitude) + "," + corner(longitude)).Inc()
+}
@@ -0,0 +1,149 @@
+// Package middleware provides HTTP middleware for the Booking Service
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/shared/policy"
+    "src/backend/shared/utils/logger"
+)
+
+// APIKeyHeader carries the API key of a server-to-server client
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator resolves a presented API key to its active record, returning an
+// error when the key is unknown or revoked
+type APIKeyAuthenticator func(ctx context.Context, key string) (*models.APIKey, error)
+
+// apiKeyContextKey is the context key under which the authenticated API key is stored
+type apiKeyContextKey struct{}
+
+// APIKeyFromContext returns the API key stored by RequireUserOrAPIKey, if the request used one
+func APIKeyFromContext(ctx context.Context) (*models.APIKey, bool) {
+    key, ok := ctx.Value(apiKeyContextKey{}).(*models.APIKey)
+    return key, ok
+}
+
+// RequireUserOrAPIKey returns middleware that admits requests carrying either a valid bearer
+// token signed with secret or an API key granting scope, provided the policy allows the
+// caller's role to perform action on resource. API keys act with the client role and are
+// rate limited per key according to the key's tier.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func RequireUserOrAPIKey(secret string, authenticate APIKeyAuthenticator, scope, resource, action string) func(http.HandlerFunc) http.HandlerFunc {
+    limiter := newKeyRateLimiter()
+
+    return func(next http.HandlerFunc) http.HandlerFunc {
+        return func(w http.ResponseWriter, r *http.Request) {
+            raw := r.Header.Get(APIKeyHeader)
+            if raw == "" {
+                claims, err := parseBearer(r, secret)
+                if err != nil {
+                    logger.LogError("Authentication failed", map[string]interface{}{
+                        "error":  err.Error(),
+                        "path":   r.URL.Path,
+                        "method": r.Method,
+                    })
+                    http.Error(w, "Authentication required", http.StatusUnauthorized)
+                    return
+                }
+                if !policy.Allowed(r.Context(), claims.Role, resource, action) {
+                    logger.LogError("Authorization failed - Denied by policy", map[string]interface{}{
+                        "userId":   claims.ID,
+                        "userRole": claims.Role,
+                        "resource": resource,
+                        "action":   action,
+                        "path":     r.URL.Path,
+                        "method":   r.Method,
+                    })
+                    http.Error(w, "Insufficient permissions", http.StatusForbidden)
+                    return
+                }
+                next(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, claims)))
+                return
+            }
+
+            key, err := authenticate(r.Context(), raw)
+            if err != nil {
+                logger.LogError("API key authentication failed", map[string]interface{}{
+                    "error":  err.Error(),
+                    "path":   r.URL.Path,
+                    "method": r.Method,
+                })
+                http.Error(w, "Invalid API key", http.StatusUnauthorized)
+                return
+            }
+
+            if !key.HasScope(scope) || !policy.Allowed(r.Context(), policy.RoleClient, resource, action) {
+                logger.LogError("Authorization failed - API key not permitted", map[string]interface{}{
+                    "apiKeyId":      key.ID,
+                    "requiredScope": scope,
+                    "path":          r.URL.Path,
+                    "method":        r.Method,
+                })
+                http.Error(w, "Insufficient permissions", http.StatusForbidden)
+                return
+            }
+
+            if retryAfter, ok := limiter.allow(key, time.Now()); !ok {
+                w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds()+0.5)))
+                http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+                return
+            }
+
+            next(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+        }
+    }
+}
+
+// keyRateLimiter counts API key requests in fixed one-minute windows. Counts are kept per
+// instance, so a key's effective limit scales with the number of replicas.
+type keyRateLimiter struct {
+    mu      sync.Mutex
+    windows map[string]*rateWindow
+}
+
+// rateWindow is the request count of one key in the window starting at start
+type rateWindow struct {
+    start time.Time
+    count int
+}
+
+// newKeyRateLimiter creates an empty keyRateLimiter
+func newKeyRateLimiter() *keyRateLimiter {
+    return &keyRateLimiter{windows: make(map[string]*rateWindow)}
+}
+
+// allow counts a request by key at now, reporting whether it is within the tier's limit and,
+// if not, how long until the window resets
+func (l *keyRateLimiter) allow(key *models.APIKey, now time.Time) (time.Duration, bool) {
+    limit := key.Tier.RequestsPerMinute()
+    if limit <= 0 {
+        return 0, true
+    }
+
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    window, ok := l.windows[key.ID]
+    if !ok || now.Sub(window.start) >= time.Minute {
+        // Drop idle windows while the lock is held anyway, so revoked keys do not linger
+        for id, w := range l.windows {
+            if now.Sub(w.start) >= time.Minute {
+                delete(l.windows, id)
+            }
+        }
+        window = &rateWindow{start: now}
+        l.windows[key.ID] = window
+    }
+
+    if window.count >= limit {
+        return window.start.Add(time.Minute).Sub(now), false
+    }
+    window.count++
+    return 0, true
+}
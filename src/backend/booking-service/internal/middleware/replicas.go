@@ -0,0 +1,20 @@
+// Package middleware provides HTTP middleware for the Booking Service
+package middleware
+
+import (
+    "net/http"
+
+    "src/backend/booking-service/internal/repository"
+)
+
+// ReplicaReads lets GET and HEAD requests read bookings from a read replica. Requests that
+// change bookings read from the primary, so what they return reflects their own writes.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ReplicaReads(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == http.MethodGet || r.Method == http.MethodHead {
+            r = r.WithContext(repository.ReplicaReads(r.Context()))
+        }
+        next.ServeHTTP(w, r)
+    })
+}
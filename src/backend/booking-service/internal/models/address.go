@@ -0,0 +1,94 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "database/sql/driver"
+    "encoding/json"
+    "fmt"
+    "strings"
+
+    "src/backend/booking-service/internal/i18n"
+)
+
+// Address is a booking's pickup address. Its coordinates are resolved by the geocoder when the
+// booking is made, unless the owner's app supplied them, and are the same as the booking's
+// Latitude and Longitude.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type Address struct {
+    // Street address, e.g. "221B Baker Street"
+    Line1 string `json:"line1"`
+
+    // Flat, suite or building, if any
+    Line2 string `json:"line2,omitempty"`
+
+    City string `json:"city"`
+
+    // State, province or county, if any
+    State string `json:"state,omitempty"`
+
+    PostalCode string `json:"postal_code"`
+
+    // ISO 3166-1 alpha-2 country code, e.g. "US"; empty leaves the country to the geocoder
+    Country string `json:"country,omitempty"`
+
+    // Coordinates of the address; nil until resolved
+    Latitude  *float64 `json:"latitude,omitempty"`
+    Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// Validate checks the address has its street, city and postal code, worded for the user
+// through i18n
+func (a *Address) Validate() error {
+    if strings.TrimSpace(a.Line1) == "" || strings.TrimSpace(a.City) == "" || strings.TrimSpace(a.PostalCode) == "" {
+        return i18n.Errorf("booking.address_incomplete")
+    }
+    if a.Country != "" && len(a.Country) != 2 {
+        return i18n.Errorf("booking.address_country_invalid")
+    }
+    if (a.Latitude == nil) != (a.Longitude == nil) {
+        return i18n.Errorf("booking.coordinates_incomplete")
+    }
+    if a.Latitude != nil && (*a.Latitude < -90 || *a.Latitude > 90 || *a.Longitude < -180 || *a.Longitude > 180) {
+        return i18n.Errorf("booking.coordinates_out_of_range")
+    }
+    return nil
+}
+
+// SamePlace reports whether two optional addresses name the same place, ignoring their
+// coordinates, case and surrounding spaces
+func (a *Address) SamePlace(b *Address) bool {
+    if a == nil || b == nil {
+        return a == b
+    }
+    same := func(x, y string) bool { return strings.EqualFold(strings.TrimSpace(x), strings.TrimSpace(y)) }
+    return same(a.Line1, b.Line1) && same(a.Line2, b.Line2) && same(a.City, b.City) &&
+        same(a.State, b.State) && same(a.PostalCode, b.PostalCode) && same(a.Country, b.Country)
+}
+
+// String formats the address on one line, e.g. "221B Baker Street, London, NW1 6XE, GB"
+func (a Address) String() string {
+    var parts []string
+    for _, part := range []string{a.Line1, a.Line2, strings.TrimSpace(a.City + " " + a.State), a.PostalCode, a.Country} {
+        if part = strings.TrimSpace(part); part != "" {
+            parts = append(parts, part)
+        }
+    }
+    return strings.Join(parts, ", ")
+}
+
+// Value stores the address as JSON
+func (a Address) Value() (driver.Value, error) {
+    return json.Marshal(a)
+}
+
+// Scan reads an address stored as JSON
+func (a *Address) Scan(src interface{}) error {
+    switch v := src.(type) {
+    case []byte:
+        return json.Unmarshal(v, a)
+    case string:
+        return json.Unmarshal([]byte(v), a)
+    default:
+        return fmt.Errorf("cannot scan %T into address", src)
+    }
+}
@@ -0,0 +1,96 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "time"
+)
+
+// API key scopes granted to server-to-server clients
+const (
+    // ScopeBookingsRead allows listing and reading bookings
+    ScopeBookingsRead = "bookings:read"
+
+    // ScopeBookingsWrite allows creating bookings
+    ScopeBookingsWrite = "bookings:write"
+)
+
+// APIKeyScopes lists every scope an API key may be granted
+var APIKeyScopes = []string{ScopeBookingsRead, ScopeBookingsWrite}
+
+// APIKeyTier selects how many requests per minute a key may make
+type APIKeyTier string
+
+// API key tier constants
+const (
+    // APIKeyTierStandard is the default tier for partner integrations
+    APIKeyTierStandard APIKeyTier = "standard"
+
+    // APIKeyTierPremium is for high-volume partners
+    APIKeyTierPremium APIKeyTier = "premium"
+
+    // APIKeyTierInternal is for first-party backends and is not rate limited
+    APIKeyTierInternal APIKeyTier = "internal"
+)
+
+// apiKeyTierLimits is the requests per minute allowed by each tier; zero means unlimited
+var apiKeyTierLimits = map[APIKeyTier]int{
+    APIKeyTierStandard: 60,
+    APIKeyTierPremium:  600,
+    APIKeyTierInternal: 0,
+}
+
+// RequestsPerMinute returns the tier's rate limit, zero meaning unlimited
+func (t APIKeyTier) RequestsPerMinute() int {
+    return apiKeyTierLimits[t]
+}
+
+// IsValid reports whether t is a known tier
+func (t APIKeyTier) IsValid() bool {
+    _, ok := apiKeyTierLimits[t]
+    return ok
+}
+
+// APIKey is a credential issued to a partner backend. Only a hash of the key is stored;
+// the key itself is shown once, when it is issued.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+type APIKey struct {
+    // Unique identifier for the key
+    ID string `json:"id" db:"id"`
+
+    // Name of the client the key was issued to
+    Name string `json:"name" db:"name"`
+
+    // First characters of the key, so a key can be recognised without revealing it
+    Prefix string `json:"prefix" db:"prefix"`
+
+    // SHA-256 hash of the key, hex encoded
+    KeyHash string `json:"-" db:"key_hash"`
+
+    // Scopes the key grants
+    Scopes []string `json:"scopes" db:"scopes"`
+
+    // Rate limit tier of the key
+    Tier APIKeyTier `json:"tier" db:"tier"`
+
+    // ID of the admin who issued the key
+    CreatedBy string `json:"created_by" db:"created_by"`
+
+    // Time the key was issued
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+    // Time the key was last used, updated at most once a minute
+    LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+
+    // Time the key was revoked, if it has been
+    RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+    for _, s := range k.Scopes {
+        if s == scope {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,142 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "fmt"
+    "strings"
+    "time"
+)
+
+// AttachmentKind is what an owner's booking attachment holds
+type AttachmentKind string
+
+// Attachment kind constants
+const (
+    // AttachmentKindAccessInstructions tells the walker how to get in, such as a gate code or
+    // where the key is kept
+    AttachmentKindAccessInstructions AttachmentKind = "access_instructions"
+
+    // AttachmentKindVetContact is the dog's vet, to be called in an emergency
+    AttachmentKindVetContact AttachmentKind = "vet_contact"
+
+    // AttachmentKindNote is any other note for the walker
+    AttachmentKindNote AttachmentKind = "note"
+
+    // AttachmentKindFile is a small file, such as a photo of the lockbox or a vet record
+    AttachmentKindFile AttachmentKind = "file"
+)
+
+// Attachment limits
+const (
+    // MaxAttachmentBytes bounds the content of a file attachment
+    MaxAttachmentBytes = 1 << 20
+
+    // MaxAttachmentTextLength bounds an attachment's text, in characters
+    MaxAttachmentTextLength = 4000
+
+    // MaxAttachmentFields bounds the number of structured fields of an attachment
+    MaxAttachmentFields = 20
+
+    // MaxBookingAttachments bounds the number of attachments of one booking
+    MaxBookingAttachments = 10
+)
+
+// BookingAttachment is a note or small file an owner attaches to a booking for its walker.
+// Only its booking, owner, kind, size and creation time are stored in the clear; the title,
+// text, fields and file are encrypted. The walker can read it only while the booking is
+// confirmed or in progress, and it is purged once the booking has been finished for the
+// retention period.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type BookingAttachment struct {
+    // Unique identifier for the attachment
+    ID string `json:"id"`
+
+    // Booking the attachment is for
+    BookingID string `json:"booking_id"`
+
+    // Owner who attached it
+    OwnerID string `json:"owner_id"`
+
+    // What the attachment holds
+    Kind AttachmentKind `json:"kind"`
+
+    // Short title shown in the walker's app, e.g. "Side gate"
+    Title string `json:"title,omitempty"`
+
+    // Free text, such as the instructions themselves
+    Text string `json:"text,omitempty"`
+
+    // Structured values, e.g. name and phone of a vet contact or code of an access instruction
+    Fields map[string]string `json:"fields,omitempty"`
+
+    // Name of an attached file
+    FileName string `json:"file_name,omitempty"`
+
+    // Media type of an attached file
+    ContentType string `json:"content_type,omitempty"`
+
+    // File content, base64 encoded in JSON; left out when attachments are listed
+    Content []byte `json:"content,omitempty"`
+
+    // Size of the file content in bytes
+    Size int `json:"size"`
+
+    // Time the attachment was added
+    CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks the attachment's kind and content against the attachment limits
+func (a *BookingAttachment) Validate() error {
+    switch a.Kind {
+    case AttachmentKindAccessInstructions, AttachmentKindVetContact, AttachmentKindNote:
+        if strings.TrimSpace(a.Text) == "" && len(a.Fields) == 0 {
+            return fmt.Errorf("text or fields are required")
+        }
+        if len(a.Content) > 0 || a.FileName != "" {
+            return fmt.Errorf("only file attachments can carry a file")
+        }
+    case AttachmentKindFile:
+        if a.FileName == "" || a.ContentType == "" {
+            return fmt.Errorf("file name and content type are required")
+        }
+        if len(a.Content) == 0 {
+            return fmt.Errorf("file content is required")
+        }
+        if len(a.Content) > MaxAttachmentBytes {
+            return fmt.Errorf("file must be at most %d bytes", MaxAttachmentBytes)
+        }
+    default:
+        return fmt.Errorf("unknown attachment kind %q", a.Kind)
+    }
+
+    if len([]rune(a.Title)) > 200 {
+        return fmt.Errorf("title must be at most 200 characters")
+    }
+    if len([]rune(a.Text)) > MaxAttachmentTextLength {
+        return fmt.Errorf("text must be at most %d characters", MaxAttachmentTextLength)
+    }
+    if len(a.Fields) > MaxAttachmentFields {
+        return fmt.Errorf("at most %d fields are allowed", MaxAttachmentFields)
+    }
+    for name, value := range a.Fields {
+        if strings.TrimSpace(name) == "" {
+            return fmt.Errorf("field names must not be empty")
+        }
+        if len([]rune(value)) > MaxAttachmentTextLength {
+            return fmt.Errorf("field %s must be at most %d characters", name, MaxAttachmentTextLength)
+        }
+    }
+    return nil
+}
+
+// Summary returns a copy of the attachment without its file content, for listings
+func (a BookingAttachment) Summary() BookingAttachment {
+    a.Content = nil
+    return a
+}
+
+// AttachmentsVisibleToWalker reports whether the booking's walker may read its attachments,
+// which they may only while the walk is coming up or under way
+func (b *Booking) AttachmentsVisibleToWalker() bool {
+    return b.IsAssigned() && (b.Status == BookingStatusConfirmed || b.Status == BookingStatusInProgress)
+}
@@ -0,0 +1,44 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "encoding/json"
+    "time"
+)
+
+// Audit actions recorded for administrative overrides
+const (
+    AuditActionForceStatus    = "force_status"
+    AuditActionReassignWalker = "reassign_walker"
+    AuditActionAdjustAmount   = "adjust_amount"
+    AuditActionCheckIn        = "check_in"
+    AuditActionBulkStatus     = "bulk_status"
+)
+
+// AuditEntry records a privileged change to a booking, with the booking before and after it.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+type AuditEntry struct {
+    // Unique identifier for the entry
+    ID string `json:"id" db:"id"`
+
+    // ID of the user who made the change
+    ActorID string `json:"actor_id" db:"actor_id"`
+
+    // Kind of change made
+    Action string `json:"action" db:"action"`
+
+    // ID of the booking changed
+    BookingID string `json:"booking_id" db:"booking_id"`
+
+    // Justification given for the change
+    Reason string `json:"reason" db:"reason"`
+
+    // Booking as it was before the change
+    Before json.RawMessage `json:"before" db:"before"`
+
+    // Booking as it was after the change
+    After json.RawMessage `json:"after" db:"after"`
+
+    // Time the change was made
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
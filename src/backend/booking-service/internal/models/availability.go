@@ -0,0 +1,73 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "fmt"
+    "time"
+)
+
+// Availability is a window in which a walker accepts bookings. Capacity is the number
+// of dogs the walker will take at the same time, allowing group walks.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+// Handles real-time availability search and schedule coordination.
+type Availability struct {
+    // Unique identifier for the availability window
+    ID string `json:"id" db:"id"`
+
+    // ID of the walker offering the window
+    WalkerID string `json:"walker_id" db:"walker_id"`
+
+    // Start of the window
+    StartsAt time.Time `json:"starts_at" db:"starts_at"`
+
+    // End of the window
+    EndsAt time.Time `json:"ends_at" db:"ends_at"`
+
+    // Maximum number of dogs walked at the same time within the window
+    Capacity int `json:"capacity" db:"capacity"`
+
+    // Discount, in percent, applied to each additional dog joining a group walk
+    GroupDiscountPercent float64 `json:"group_discount_percent" db:"group_discount_percent"`
+
+    // Service region the walker covers in the window; empty when unknown
+    Region string `json:"region,omitempty" db:"region"`
+}
+
+// Validate performs basic validation on the availability window.
+func (a *Availability) Validate() error {
+    if a.ID == "" {
+        return fmt.Errorf("availability ID is required")
+    }
+    if a.WalkerID == "" {
+        return fmt.Errorf("walker ID is required")
+    }
+    if a.StartsAt.IsZero() || a.EndsAt.IsZero() {
+        return fmt.Errorf("start and end times are required")
+    }
+    if !a.EndsAt.After(a.StartsAt) {
+        return fmt.Errorf("end time must be after start time")
+    }
+    if a.Capacity < 1 {
+        return fmt.Errorf("capacity must be at least 1")
+    }
+    if a.GroupDiscountPercent < 0 || a.GroupDiscountPercent > 100 {
+        return fmt.Errorf("group discount must be between 0 and 100 percent")
+    }
+    return nil
+}
+
+// Covers reports whether the window contains the interval [start, end).
+func (a *Availability) Covers(start, end time.Time) bool {
+    return !start.Before(a.StartsAt) && !end.After(a.EndsAt)
+}
+
+// PriceForDog returns the amount for a dog joining the slot, given how many dogs are
+// already booked in it. The first dog pays the full amount.
+func (a *Availability) PriceForDog(amount float64, existingDogs int) float64 {
+    if existingDogs == 0 || a.GroupDiscountPercent == 0 {
+        return amount
+    }
+    discounted := amount * (1 - a.GroupDiscountPercent/100)
+    // Round to cents
+    return float64(int64(discounted*100+0.5)) / 100
+}
@@ -2,7 +2,12 @@
 package models
 
 import (
+    "encoding/base64"
+    "fmt"
+    "strings"
     "time"
+
+    "src/backend/booking-service/internal/i18n"
 )
 
 // Human Tasks:
@@ -11,6 +16,9 @@ import (
 // 3. Set up monitoring for booking-related metrics
 // 4. Review and adjust booking status enum values based on business requirements
 
+// DefaultDurationMinutes is the walk length assumed when a booking does not specify one
+const DefaultDurationMinutes = 30
+
 // BookingStatus represents the current state of a booking
 type BookingStatus string
 
@@ -22,6 +30,10 @@ const (
     BookingStatusCompleted  BookingStatus = "completed"
     BookingStatusCancelled  BookingStatus = "cancelled"
     BookingStatusFailed     BookingStatus = "failed"
+
+    // BookingStatusNeedsReassignment means the booking's walker can no longer take it, such as
+    // when they were suspended after it was confirmed, and it is waiting for another walker
+    BookingStatusNeedsReassignment BookingStatus = "needs_reassignment"
 )
 
 // Booking represents a dog walking appointment with details about the user, walker, and schedule.
@@ -34,7 +46,7 @@ type Booking struct {
     // ID of the dog owner who created the booking
     OwnerID string `json:"owner_id" db:"owner_id"`
 
-    // ID of the assigned dog walker
+    // ID of the assigned dog walker; empty until a walker is assigned
     WalkerID string `json:"walker_id" db:"walker_id"`
 
     // ID of the dog to be walked
@@ -48,6 +60,145 @@ type Booking struct {
 
     // Cost of the booking in the system's default currency (USD)
     Amount float64 `json:"amount" db:"amount"`
+
+    // Length of the walk in minutes
+    DurationMinutes int `json:"duration_minutes" db:"duration_minutes"`
+
+    // Deadline for the assigned walker to accept; the booking is cancelled if it passes unanswered
+    AcceptBy *time.Time `json:"accept_by,omitempty" db:"accept_by"`
+
+    // Tax charged on top of Amount; nil when no tax applies
+    Tax *TaxBreakdown `json:"tax,omitempty" db:"tax"`
+
+    // Service region the walk takes place in, such as "brooklyn"; empty when unknown
+    Region string `json:"region,omitempty" db:"region"`
+
+    // How Amount was worked out from the walker's rate plan; nil when the walker has none
+    Rate *RateSnapshot `json:"rate,omitempty" db:"rate"`
+
+    // Fee charged and refund owed when the owner cancelled; nil unless they did
+    Cancellation *Cancellation `json:"cancellation,omitempty" db:"cancellation"`
+
+    // Referral code supplied with the owner's first booking; used for attribution only, not stored
+    ReferralCode string `json:"referral_code,omitempty" db:"-"`
+
+    // Slot hold made with the quote the owner is booking at, which the booking takes the place
+    // and price of; not stored
+    HoldID string `json:"hold_id,omitempty" db:"-"`
+
+    // Pickup coordinates supplied when booking; used to derive Region and to check the walker
+    // is at the pickup when they check in
+    Latitude  *float64 `json:"latitude,omitempty" db:"latitude"`
+    Longitude *float64 `json:"longitude,omitempty" db:"longitude"`
+
+    // Pickup address; when given without coordinates they are resolved from it by the geocoder
+    Address *Address `json:"address,omitempty" db:"address"`
+
+    // Dog details supplied when booking; used to price the walk from the walker's rate plan, not stored
+    LargeDog  bool `json:"large_dog,omitempty" db:"-"`
+    ExtraDogs int  `json:"extra_dogs,omitempty" db:"-"`
+}
+
+// BookingFilter narrows a listing of bookings; empty fields match every booking
+type BookingFilter struct {
+    Status   BookingStatus
+    WalkerID string
+    OwnerID  string
+    Region   string
+
+    // IDs restricts the bookings to those listed; empty matches any
+    IDs []string
+}
+
+// Matches reports whether b passes the filter.
+func (f BookingFilter) Matches(b *Booking) bool {
+    return (f.Status == "" || b.Status == f.Status) && (f.WalkerID == "" || b.WalkerID == f.WalkerID) &&
+        (f.OwnerID == "" || b.OwnerID == f.OwnerID) && (f.Region == "" || b.Region == f.Region) &&
+        (len(f.IDs) == 0 || hasID(f.IDs, b.ID))
+}
+
+// hasID reports whether id is one of ids
+func hasID(ids []string, id string) bool {
+    for _, candidate := range ids {
+        if candidate == id {
+            return true
+        }
+    }
+    return false
+}
+
+// BookingCursor marks where a page of bookings ended. Bookings are listed by scheduled time,
+// latest first, with ties broken by ID, so the cursor holds both: a page resumes exactly after
+// the last booking returned even if that booking has since been rescheduled or removed.
+type BookingCursor struct {
+    ScheduledAt time.Time
+    ID          string
+}
+
+// CursorAfter returns the cursor for the page following b.
+func CursorAfter(b *Booking) *BookingCursor {
+    return &BookingCursor{ScheduledAt: b.ScheduledAt, ID: b.ID}
+}
+
+// Precedes reports whether b is listed before the cursor, that is on an earlier page.
+func (c *BookingCursor) Precedes(b *Booking) bool {
+    return b.ScheduledAt.After(c.ScheduledAt) || (b.ScheduledAt.Equal(c.ScheduledAt) && b.ID >= c.ID)
+}
+
+// String encodes the cursor as an opaque token for clients to pass back.
+func (c *BookingCursor) String() string {
+    return base64.RawURLEncoding.EncodeToString([]byte(c.ScheduledAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+// ParseBookingCursor decodes a token made by BookingCursor.String.
+func ParseBookingCursor(token string) (*BookingCursor, error) {
+    raw, err := base64.RawURLEncoding.DecodeString(token)
+    if err != nil {
+        return nil, fmt.Errorf("malformed cursor")
+    }
+    at, id, found := strings.Cut(string(raw), "|")
+    if !found || id == "" {
+        return nil, fmt.Errorf("malformed cursor")
+    }
+    scheduledAt, err := time.Parse(time.RFC3339Nano, at)
+    if err != nil {
+        return nil, fmt.Errorf("malformed cursor")
+    }
+    return &BookingCursor{ScheduledAt: scheduledAt, ID: id}, nil
+}
+
+// BookingSummary counts every booking matching a listing's filter, not just those on one page
+type BookingSummary struct {
+    Total int `json:"total"`
+
+    // ByStatus holds a count for every status, zero when no booking has it
+    ByStatus map[BookingStatus]int `json:"by_status"`
+}
+
+// NewBookingSummary returns a summary of no bookings, with every status counted as zero
+func NewBookingSummary() *BookingSummary {
+    summary := &BookingSummary{ByStatus: make(map[BookingStatus]int, len(BookingStatuses))}
+    for _, status := range BookingStatuses {
+        summary.ByStatus[status] = 0
+    }
+    return summary
+}
+
+// Add counts n bookings with status.
+func (s *BookingSummary) Add(status BookingStatus, n int) {
+    s.ByStatus[status] += n
+    s.Total += n
+}
+
+// BookingPage is one page of a booking listing, latest scheduled first
+type BookingPage struct {
+    Bookings []Booking `json:"bookings"`
+
+    // Summary counts all the bookings the listing's filter matches, across every page
+    Summary *BookingSummary `json:"summary"`
+
+    // NextCursor is passed as cursor to fetch the following page; empty on the last page
+    NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // NewBooking creates a new instance of the Booking struct with the provided parameters.
@@ -73,40 +224,99 @@ func NewBooking(
 }
 
 // Validate performs basic validation on the booking data.
-// Returns an error if any required fields are missing or invalid.
+// Returns an error if any required fields are missing or invalid, worded for the user through i18n.
 func (b *Booking) Validate() error {
     if b.ID == "" {
-        return fmt.Errorf("booking ID is required")
+        return i18n.Errorf("booking.id_required")
     }
     if b.OwnerID == "" {
-        return fmt.Errorf("owner ID is required")
-    }
-    if b.WalkerID == "" {
-        return fmt.Errorf("walker ID is required")
+        return i18n.Errorf("booking.owner_required")
     }
     if b.DogID == "" {
-        return fmt.Errorf("dog ID is required")
+        return i18n.Errorf("booking.dog_required")
     }
     if b.ScheduledAt.IsZero() {
-        return fmt.Errorf("scheduled time is required")
+        return i18n.Errorf("booking.scheduled_at_required")
     }
     if b.Status == "" {
-        return fmt.Errorf("status is required")
+        return i18n.Errorf("booking.status_required")
     }
     if b.Amount < 0 {
-        return fmt.Errorf("amount must be non-negative")
+        return i18n.Errorf("booking.amount_negative")
+    }
+    if b.DurationMinutes < 0 {
+        return i18n.Errorf("booking.duration_negative")
+    }
+    if b.ExtraDogs < 0 {
+        return i18n.Errorf("booking.extra_dogs_negative")
+    }
+    if (b.Latitude == nil) != (b.Longitude == nil) {
+        return i18n.Errorf("booking.coordinates_incomplete")
+    }
+    if b.Latitude != nil && (*b.Latitude < -90 || *b.Latitude > 90 || *b.Longitude < -180 || *b.Longitude > 180) {
+        return i18n.Errorf("booking.coordinates_out_of_range")
+    }
+    if b.Address != nil {
+        if err := b.Address.Validate(); err != nil {
+            return err
+        }
     }
     return nil
 }
 
-// IsScheduledInFuture checks if the booking is scheduled for a future time.
-func (b *Booking) IsScheduledInFuture() bool {
-    return b.ScheduledAt.After(time.Now())
+// TotalAmount returns what the owner is charged: the amount plus any tax.
+func (b *Booking) TotalAmount() float64 {
+    if b.Tax == nil {
+        return b.Amount
+    }
+    return b.Amount + b.Tax.Total
+}
+
+// EndsAt returns the scheduled end of the walk.
+func (b *Booking) EndsAt() time.Time {
+    duration := b.DurationMinutes
+    if duration == 0 {
+        duration = DefaultDurationMinutes
+    }
+    return b.ScheduledAt.Add(time.Duration(duration) * time.Minute)
+}
+
+// BookingStatuses lists every booking status
+var BookingStatuses = []BookingStatus{
+    BookingStatusPending, BookingStatusConfirmed, BookingStatusInProgress,
+    BookingStatusCompleted, BookingStatusCancelled, BookingStatusFailed,
+    BookingStatusNeedsReassignment,
+}
+
+// IsValid reports whether s is one of the known booking statuses.
+func (s BookingStatus) IsValid() bool {
+    for _, status := range BookingStatuses {
+        if s == status {
+            return true
+        }
+    }
+    return false
+}
+
+// IsAssigned reports whether a walker has been assigned to the booking.
+func (b *Booking) IsAssigned() bool {
+    return b.WalkerID != ""
+}
+
+// IsScheduledInFuture checks if the booking is scheduled after now.
+func (b *Booking) IsScheduledInFuture(now time.Time) bool {
+    return b.ScheduledAt.After(now)
 }
 
 // IsCancellable determines if the booking can be cancelled based on its current status.
 func (b *Booking) IsCancellable() bool {
-    return b.Status == BookingStatusPending || b.Status == BookingStatusConfirmed
+    return b.Status == BookingStatusPending || b.Status == BookingStatusConfirmed ||
+        b.Status == BookingStatusNeedsReassignment
+}
+
+// NeedsWalker reports whether the booking is waiting for the matching engine to assign a walker.
+func (b *Booking) NeedsWalker() bool {
+    return (b.Status == BookingStatusPending && !b.IsAssigned()) || b.Status == BookingStatusNeedsReassignment
 }
 
 // IsModifiable determines if the booking details can be modified based on its current status.
@@ -128,6 +338,9 @@ func (b *Booking) UpdateStatus(newStatus BookingStatus) error {
     case BookingStatusInProgress:
         validTransition = newStatus == BookingStatusCompleted || 
                          newStatus == BookingStatusFailed
+    case BookingStatusNeedsReassignment:
+        validTransition = newStatus == BookingStatusPending ||
+                         newStatus == BookingStatusCancelled
     case BookingStatusCompleted, BookingStatusCancelled, BookingStatusFailed:
         validTransition = false
     }
@@ -140,14 +353,14 @@ func (b *Booking) UpdateStatus(newStatus BookingStatus) error {
     return nil
 }
 
-// TimeUntilScheduled returns the duration until the scheduled time.
-func (b *Booking) TimeUntilScheduled() time.Duration {
-    return time.Until(b.ScheduledAt)
+// TimeUntilScheduled returns the duration from now until the scheduled time.
+func (b *Booking) TimeUntilScheduled(now time.Time) time.Duration {
+    return b.ScheduledAt.Sub(now)
 }
 
-// IsOverdue checks if the booking is past its scheduled time without being started.
-func (b *Booking) IsOverdue() bool {
-    return time.Now().After(b.ScheduledAt) && 
+// IsOverdue checks if the booking is past its scheduled time at now without being started.
+func (b *Booking) IsOverdue(now time.Time) bool {
+    return now.After(b.ScheduledAt) && 
            b.Status != BookingStatusInProgress && 
            b.Status != BookingStatusCompleted && 
            b.Status != BookingStatusCancelled && 
@@ -0,0 +1,90 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "fmt"
+    "time"
+)
+
+// BookingChangeStatus represents the state of a proposed booking change
+type BookingChangeStatus string
+
+// Booking change status constants
+const (
+    BookingChangeStatusPending  BookingChangeStatus = "pending"
+    BookingChangeStatusAccepted BookingChangeStatus = "accepted"
+    BookingChangeStatusRejected BookingChangeStatus = "rejected"
+    BookingChangeStatusExpired  BookingChangeStatus = "expired"
+)
+
+// BookingChange is a proposed modification to a booking that takes effect only once the
+// walker accepts it. Unanswered proposals expire automatically.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+// Handles booking management and schedule coordination.
+type BookingChange struct {
+    // Unique identifier for the change request
+    ID string `json:"id" db:"id"`
+
+    // ID of the booking to modify
+    BookingID string `json:"booking_id" db:"booking_id"`
+
+    // ID of the user proposing the change
+    ProposedBy string `json:"proposed_by" db:"proposed_by"`
+
+    // Proposed new scheduled time, if changing
+    ScheduledAt *time.Time `json:"scheduled_at,omitempty" db:"scheduled_at"`
+
+    // Proposed new walk length in minutes, if changing
+    DurationMinutes *int `json:"duration_minutes,omitempty" db:"duration_minutes"`
+
+    // Current state of the proposal
+    Status BookingChangeStatus `json:"status" db:"status"`
+
+    // Time after which an unanswered proposal expires
+    ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+
+    // Time the proposal was created
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+    // Time the walker responded or the proposal expired
+    ResolvedAt *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+// Validate performs basic validation on the proposed change as of now.
+func (c *BookingChange) Validate(now time.Time) error {
+    if c.ID == "" {
+        return fmt.Errorf("change ID is required")
+    }
+    if c.BookingID == "" {
+        return fmt.Errorf("booking ID is required")
+    }
+    if c.ProposedBy == "" {
+        return fmt.Errorf("proposer ID is required")
+    }
+    if c.ScheduledAt == nil && c.DurationMinutes == nil {
+        return fmt.Errorf("at least one field must change")
+    }
+    if c.ScheduledAt != nil && !c.ScheduledAt.After(now) {
+        return fmt.Errorf("new scheduled time must be in the future")
+    }
+    if c.DurationMinutes != nil && *c.DurationMinutes <= 0 {
+        return fmt.Errorf("duration must be positive")
+    }
+    return nil
+}
+
+// ApplyTo returns a copy of the booking with the proposed changes applied.
+func (c *BookingChange) ApplyTo(booking Booking) Booking {
+    if c.ScheduledAt != nil {
+        booking.ScheduledAt = *c.ScheduledAt
+    }
+    if c.DurationMinutes != nil {
+        booking.DurationMinutes = *c.DurationMinutes
+    }
+    return booking
+}
+
+// IsExpired reports whether a pending proposal has passed its expiry at now.
+func (c *BookingChange) IsExpired(now time.Time) bool {
+    return c.Status == BookingChangeStatusPending && now.After(c.ExpiresAt)
+}
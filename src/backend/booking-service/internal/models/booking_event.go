@@ -0,0 +1,39 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "encoding/json"
+    "time"
+)
+
+// Booking event types
+const (
+    // BookingEventCreated carries every field of a new booking
+    BookingEventCreated = "created"
+
+    // BookingEventImported carries every field of a booking that existed before event
+    // sourcing was enabled, or while it was disabled
+    BookingEventImported = "imported"
+
+    // BookingEventStatusChanged carries a change that includes the booking's status
+    BookingEventStatusChanged = "status_changed"
+
+    // BookingEventUpdated carries any other change
+    BookingEventUpdated = "updated"
+)
+
+// BookingEvent is one change to a booking. Folding a booking's events in version order
+// rebuilds its state as of any version.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type BookingEvent struct {
+    BookingID string `json:"booking_id" db:"booking_id"`
+
+    // Version numbers a booking's events from 1, in the order they happened
+    Version int    `json:"version" db:"version"`
+    Type    string `json:"type" db:"type"`
+
+    // Changes holds the booking's JSON fields the event set, null for fields it cleared
+    Changes json.RawMessage `json:"changes" db:"changes"`
+
+    RecordedAt time.Time `json:"recorded_at" db:"recorded_at"`
+}
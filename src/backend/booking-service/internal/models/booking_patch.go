@@ -0,0 +1,128 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "encoding/json"
+    "fmt"
+    "reflect"
+    "sort"
+    "strings"
+)
+
+// MergePatchContentType is the media type of a JSON Merge Patch (RFC 7396)
+const MergePatchContentType = "application/merge-patch+json"
+
+// Booking fields an owner may patch, by JSON name. Schedule and pickup changes are only
+// patched while no walker is assigned; once one is, they go through the change approval
+// workflow so the walker can agree to them.
+var (
+    detailFields   = []string{"dog_id"}
+    scheduleFields = []string{"scheduled_at", "duration_minutes", "latitude", "longitude", "address"}
+)
+
+// PatchError reports the fields a merge patch tried to change but may not
+type PatchError struct {
+    Fields []string
+
+    // Status is set when the fields can be patched, just not while the booking is in it
+    Status BookingStatus
+}
+
+func (e *PatchError) Error() string {
+    if e.Status != "" {
+        return fmt.Sprintf("%s cannot be changed while the booking is %s", strings.Join(e.Fields, ", "), e.Status)
+    }
+    return fmt.Sprintf("%s cannot be changed", strings.Join(e.Fields, ", "))
+}
+
+// PatchableFields returns the JSON names of the fields an owner may patch in the booking's
+// current state; none once the walk has started or the booking is closed.
+func (b *Booking) PatchableFields() []string {
+    switch {
+    case b.Status == BookingStatusPending && !b.IsAssigned():
+        return append(append([]string{}, detailFields...), scheduleFields...)
+    case b.Status == BookingStatusPending, b.Status == BookingStatusConfirmed:
+        return detailFields
+    }
+    return nil
+}
+
+// ApplyMergePatch returns a copy of the booking with a JSON Merge Patch applied: each member
+// of the patch replaces that field, and null clears it. Every patchable field is a scalar
+// except the address, which is always replaced whole, so replacing a field is the whole merge. Fields that may not be patched are accepted only with
+// their current value, letting clients send back a booking as they read it.
+func (b *Booking) ApplyMergePatch(patch []byte) (Booking, error) {
+    var members map[string]json.RawMessage
+    if err := json.Unmarshal(patch, &members); err != nil || members == nil {
+        return Booking{}, fmt.Errorf("merge patch must be a JSON object")
+    }
+
+    encoded, err := json.Marshal(b)
+    if err != nil {
+        return Booking{}, fmt.Errorf("failed to encode booking: %w", err)
+    }
+    var fields map[string]json.RawMessage
+    if err := json.Unmarshal(encoded, &fields); err != nil {
+        return Booking{}, fmt.Errorf("failed to encode booking: %w", err)
+    }
+
+    patchable := make(map[string]bool)
+    for _, field := range b.PatchableFields() {
+        patchable[field] = true
+    }
+    var denied []string
+    var later bool
+    for field, value := range members {
+        switch {
+        case patchable[field] && string(value) == "null":
+            delete(fields, field)
+        case patchable[field]:
+            fields[field] = value
+        case sameJSON(fields[field], value):
+        default:
+            denied = append(denied, field)
+            later = later || hasField(detailFields, field) || hasField(scheduleFields, field)
+        }
+    }
+    if len(denied) > 0 {
+        sort.Strings(denied)
+        patchErr := &PatchError{Fields: denied}
+        if later {
+            patchErr.Status = b.Status
+        }
+        return Booking{}, patchErr
+    }
+
+    encoded, err = json.Marshal(fields)
+    if err != nil {
+        return Booking{}, fmt.Errorf("failed to apply merge patch: %w", err)
+    }
+    var patched Booking
+    if err := json.Unmarshal(encoded, &patched); err != nil {
+        return Booking{}, fmt.Errorf("merge patch has an invalid value: %w", err)
+    }
+    return patched, nil
+}
+
+// sameJSON reports whether two JSON values are equal, ignoring formatting. A missing value
+// equals only null.
+func sameJSON(a, b json.RawMessage) bool {
+    if a == nil {
+        a = json.RawMessage("null")
+    }
+    var x, y interface{}
+    if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+        return false
+    }
+    return reflect.DeepEqual(x, y)
+}
+
+// hasField reports whether fields holds field
+func hasField(fields []string, field string) bool {
+    for _, f := range fields {
+        if f == field {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,88 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "fmt"
+    "time"
+)
+
+// DefaultRuleSetRegion is the region of the rule set applied in regions without one of their own
+const DefaultRuleSetRegion = "default"
+
+// BookingRuleKind names a check new bookings must pass
+type BookingRuleKind string
+
+// Booking rule kinds
+const (
+    // BookingRuleMinLeadTime rejects walks starting less than Minutes from now
+    BookingRuleMinLeadTime BookingRuleKind = "min_lead_time"
+
+    // BookingRuleMaxDuration rejects walks longer than Minutes
+    BookingRuleMaxDuration BookingRuleKind = "max_duration"
+
+    // BookingRuleMaxAdvance rejects walks starting more than Days from now
+    BookingRuleMaxAdvance BookingRuleKind = "max_advance"
+)
+
+// bookingRuleInDays records whether each known rule kind is limited in Days rather than Minutes
+var bookingRuleInDays = map[BookingRuleKind]bool{
+    BookingRuleMinLeadTime: false,
+    BookingRuleMaxDuration: false,
+    BookingRuleMaxAdvance:  true,
+}
+
+// BookingRule is one check of a BookingRuleSet
+type BookingRule struct {
+    Kind BookingRuleKind `json:"kind"`
+
+    // Minutes is the limit of min_lead_time and max_duration rules
+    Minutes int `json:"minutes,omitempty"`
+
+    // Days is the limit of max_advance rules
+    Days int `json:"days,omitempty"`
+}
+
+// BookingRuleSet is the rules new bookings in a region are checked against, so operations can
+// tune them without a deploy. Walks in the past are always refused; rules only add to that.
+// Regions without a set use the DefaultRuleSetRegion set, or DefaultBookingRules when that has
+// not been saved either.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type BookingRuleSet struct {
+    Region    string        `json:"region" db:"region"`
+    Rules     []BookingRule `json:"rules" db:"rules"`
+    UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultBookingRules returns the rules applied where none have been saved: none beyond
+// refusing walks in the past
+func DefaultBookingRules() *BookingRuleSet {
+    return &BookingRuleSet{
+        Region: DefaultRuleSetRegion,
+        Rules:  []BookingRule{},
+    }
+}
+
+// Validate checks every rule is of a known kind, appears once and has a positive limit
+func (s *BookingRuleSet) Validate() error {
+    if s.Region == "" {
+        return fmt.Errorf("region is required")
+    }
+    seen := make(map[BookingRuleKind]bool, len(s.Rules))
+    for _, rule := range s.Rules {
+        inDays, ok := bookingRuleInDays[rule.Kind]
+        if !ok {
+            return fmt.Errorf("unknown rule %q", rule.Kind)
+        }
+        if seen[rule.Kind] {
+            return fmt.Errorf("rule %s is given more than once", rule.Kind)
+        }
+        seen[rule.Kind] = true
+        if inDays && (rule.Days <= 0 || rule.Minutes != 0) {
+            return fmt.Errorf("rule %s needs a positive number of days and no minutes", rule.Kind)
+        }
+        if !inDays && (rule.Minutes <= 0 || rule.Days != 0) {
+            return fmt.Errorf("rule %s needs a positive number of minutes and no days", rule.Kind)
+        }
+    }
+    return nil
+}
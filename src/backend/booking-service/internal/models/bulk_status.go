@@ -0,0 +1,53 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// Bulk status outcomes for a single booking
+const (
+    BulkStatusUpdated = "updated"
+    BulkStatusSkipped = "skipped"
+)
+
+// BulkStatusFilter selects the bookings a bulk status change applies to
+type BulkStatusFilter struct {
+    // Walker whose bookings are changed
+    WalkerID string `json:"walker_id"`
+
+    // Bookings scheduled in [From, To) are changed
+    From time.Time `json:"from"`
+    To   time.Time `json:"to"`
+
+    // Statuses a booking must currently have to be changed; matched bookings with any other
+    // status are reported as skipped
+    Statuses []BookingStatus `json:"statuses,omitempty"`
+}
+
+// BulkStatusResult reports what a bulk status change did to one booking
+type BulkStatusResult struct {
+    BookingID   string    `json:"booking_id"`
+    ScheduledAt time.Time `json:"scheduled_at"`
+
+    // Status of the booking before the change
+    PreviousStatus BookingStatus `json:"previous_status"`
+
+    // Status of the booking after the change; unchanged when skipped
+    Status BookingStatus `json:"status"`
+
+    // Outcome is BulkStatusUpdated or BulkStatusSkipped
+    Outcome string `json:"outcome"`
+
+    // Why the booking was skipped
+    Reason string `json:"reason,omitempty"`
+}
+
+// BulkStatusReport is the outcome of a bulk status change, such as cancelling every walk of a
+// walker who has fallen ill. Either every booking listed as updated was changed or none was.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+type BulkStatusReport struct {
+    Updated int `json:"updated"`
+    Skipped int `json:"skipped"`
+
+    // Results lists every matched booking, by scheduled time
+    Results []BulkStatusResult `json:"results"`
+}
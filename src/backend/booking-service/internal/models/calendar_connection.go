@@ -0,0 +1,32 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// CalendarConnection is a walker's external calendar, such as Google Calendar, that their
+// confirmed walks are written to. The OAuth tokens are never returned by the API.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type CalendarConnection struct {
+    WalkerID string `json:"walker_id" db:"walker_id"`
+
+    // Provider names the calendar service, e.g. "google" or "microsoft"
+    Provider string `json:"provider" db:"provider"`
+
+    AccessToken  string    `json:"-" db:"access_token"`
+    RefreshToken string    `json:"-" db:"refresh_token"`
+    ExpiresAt    time.Time `json:"-" db:"expires_at"`
+
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+    UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CalendarEvent links a booking to the event written for it in a walker's external calendar,
+// so the event can be updated or removed when the booking changes
+type CalendarEvent struct {
+    BookingID string `json:"booking_id" db:"booking_id"`
+    WalkerID  string `json:"walker_id" db:"walker_id"`
+    Provider  string `json:"provider" db:"provider"`
+
+    // EventID is the provider's ID of the event
+    EventID string `json:"event_id" db:"event_id"`
+}
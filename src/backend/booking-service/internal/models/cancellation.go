@@ -0,0 +1,131 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "database/sql/driver"
+    "encoding/json"
+    "fmt"
+    "math"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// CancellationTier is the fee charged for cancelling a walk at least Notice before it starts
+type CancellationTier struct {
+    Notice     time.Duration `json:"notice"`
+    FeePercent float64       `json:"fee_percent"`
+}
+
+// CancellationPolicy is the fee owners are charged for cancelling a walk, by how much notice
+// they give. Tiers are ordered from the longest notice to the shortest; cancelling with less
+// notice than every tier, or after the walk has started, costs the full total.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type CancellationPolicy []CancellationTier
+
+// DefaultCancellationPolicy returns the policy applied when none is configured: free until 24
+// hours before the walk, half the total until 2 hours before, and the full total after that
+func DefaultCancellationPolicy() CancellationPolicy {
+    return CancellationPolicy{
+        {Notice: 24 * time.Hour, FeePercent: 0},
+        {Notice: 2 * time.Hour, FeePercent: 50},
+        {Notice: 0, FeePercent: 100},
+    }
+}
+
+// ParseCancellationPolicy parses a comma-separated list of notice=percent pairs, such as
+// "24h=0,2h=50,0s=100". An empty string gives DefaultCancellationPolicy.
+func ParseCancellationPolicy(s string) (CancellationPolicy, error) {
+    var policy CancellationPolicy
+    for _, pair := range strings.Split(s, ",") {
+        if strings.TrimSpace(pair) == "" {
+            continue
+        }
+        notice, percent, ok := strings.Cut(pair, "=")
+        if !ok {
+            return nil, fmt.Errorf("invalid cancellation tier %q: expected notice=percent", pair)
+        }
+        d, err := time.ParseDuration(strings.TrimSpace(notice))
+        if err != nil || d < 0 {
+            return nil, fmt.Errorf("invalid cancellation tier %q: notice must be a non-negative duration such as 24h", pair)
+        }
+        value, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+        if err != nil || value < 0 || value > 100 {
+            return nil, fmt.Errorf("invalid cancellation tier %q: percent must be between 0 and 100", pair)
+        }
+        policy = append(policy, CancellationTier{Notice: d, FeePercent: value})
+    }
+    if len(policy) == 0 {
+        return DefaultCancellationPolicy(), nil
+    }
+
+    sort.SliceStable(policy, func(i, j int) bool { return policy[i].Notice > policy[j].Notice })
+    for i := 1; i < len(policy); i++ {
+        if policy[i].Notice == policy[i-1].Notice {
+            return nil, fmt.Errorf("invalid cancellation policy: notice %s is given more than once", policy[i].Notice)
+        }
+    }
+    return policy, nil
+}
+
+// FeePercent returns the share of the total charged for cancelling a walk starting at
+// scheduledAt at the time at
+func (p CancellationPolicy) FeePercent(scheduledAt, at time.Time) float64 {
+    notice := scheduledAt.Sub(at)
+    for _, tier := range p {
+        if notice >= tier.Notice {
+            return tier.FeePercent
+        }
+    }
+    return 100
+}
+
+// Cancel works out the fee for cancelling booking at the time at, rounded to cents. The rest
+// of the booking's total is to be refunded.
+func (p CancellationPolicy) Cancel(booking *Booking, at time.Time, cancelledBy string) *Cancellation {
+    total := booking.TotalAmount()
+    percent := p.FeePercent(booking.ScheduledAt, at)
+    fee := math.Round(total*percent) / 100
+    return &Cancellation{
+        CancelledAt: at,
+        CancelledBy: cancelledBy,
+        FeePercent:  percent,
+        Fee:         fee,
+        Refund:      math.Round((total-fee)*100) / 100,
+    }
+}
+
+// Cancellation records an owner's cancellation of a booking and the fee it cost them. It is
+// stored with the booking so the fee can be explained after the policy changes.
+type Cancellation struct {
+    CancelledAt time.Time `json:"cancelled_at"`
+    CancelledBy string    `json:"cancelled_by"`
+
+    // FeePercent is the share of the booking's total the policy charged when it was cancelled
+    FeePercent float64 `json:"fee_percent"`
+
+    // Fee is kept from the owner's payment and Refund returned to them
+    Fee    float64 `json:"fee"`
+    Refund float64 `json:"refund"`
+
+    // RefundID is the payment-service refund of Refund; empty until it has been made
+    RefundID string `json:"refund_id,omitempty"`
+}
+
+// Value stores the cancellation as JSON
+func (c Cancellation) Value() (driver.Value, error) {
+    return json.Marshal(c)
+}
+
+// Scan reads a cancellation stored as JSON
+func (c *Cancellation) Scan(src interface{}) error {
+    switch v := src.(type) {
+    case []byte:
+        return json.Unmarshal(v, c)
+    case string:
+        return json.Unmarshal([]byte(v), c)
+    default:
+        return fmt.Errorf("cannot scan %T into cancellation", src)
+    }
+}
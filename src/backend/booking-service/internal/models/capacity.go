@@ -0,0 +1,43 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// CapacitySlot compares the dogs booked in one region during one hour with the dogs walkers
+// have offered to take then
+type CapacitySlot struct {
+    // Region the slot covers; empty for bookings and windows without a region
+    Region string `json:"region"`
+
+    // Start of the hour, in UTC
+    Hour time.Time `json:"hour"`
+
+    // Demand is the number of active bookings whose walks overlap the hour
+    Demand int `json:"demand"`
+
+    // Unassigned is how many of those bookings have no walker yet
+    Unassigned int `json:"unassigned"`
+
+    // Supply is the number of dogs walkers' availability windows accept during the hour
+    Supply int `json:"supply"`
+
+    // Walkers is the number of walkers available during the hour
+    Walkers int `json:"walkers"`
+
+    // Shortfall is how many booked dogs exceed the supply; zero when the hour is covered
+    Shortfall int `json:"shortfall"`
+}
+
+// CapacityReport is the upcoming supply and demand of walks per region and hour, so
+// operations can recruit walkers where coverage is thin.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type CapacityReport struct {
+    From time.Time `json:"from"`
+    To   time.Time `json:"to"`
+
+    // Slots lists every region and hour with demand or supply, by region then hour
+    Slots []CapacitySlot `json:"slots"`
+
+    // ThinSlots counts the slots with a shortfall
+    ThinSlots int `json:"thin_slots"`
+}
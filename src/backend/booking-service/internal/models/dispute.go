@@ -0,0 +1,65 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// DisputeStatus is where a dispute is in its review
+type DisputeStatus string
+
+// Dispute status constants
+const (
+    // DisputeOpen is a dispute raised by the owner and not yet picked up by support
+    DisputeOpen DisputeStatus = "open"
+
+    // DisputeUnderReview is a dispute support is investigating
+    DisputeUnderReview DisputeStatus = "under_review"
+
+    // DisputeResolvedRefund is a dispute upheld with a refund to the owner
+    DisputeResolvedRefund DisputeStatus = "resolved_refund"
+
+    // DisputeResolvedDenied is a dispute closed without a refund
+    DisputeResolvedDenied DisputeStatus = "resolved_denied"
+)
+
+// IsResolved reports whether the dispute has reached a final status
+func (s DisputeStatus) IsResolved() bool {
+    return s == DisputeResolvedRefund || s == DisputeResolvedDenied
+}
+
+// Dispute is an owner's complaint about a completed or failed booking, asking for their money
+// back. Support reviews it and either refunds the owner or denies it.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type Dispute struct {
+    // Unique identifier for the dispute
+    ID string `json:"id" db:"id"`
+
+    // Booking being disputed; a booking can be disputed once
+    BookingID string `json:"booking_id" db:"booking_id"`
+
+    // Owner who raised the dispute
+    OwnerID string `json:"owner_id" db:"owner_id"`
+
+    // Owner's account of what went wrong
+    Reason string `json:"reason" db:"reason"`
+
+    // Current status of the dispute
+    Status DisputeStatus `json:"status" db:"status"`
+
+    // Support's explanation of the outcome, once resolved
+    Resolution string `json:"resolution,omitempty" db:"resolution"`
+
+    // Admin who last moved the dispute along
+    ReviewedBy string `json:"reviewed_by,omitempty" db:"reviewed_by"`
+
+    // Amount refunded in BookingCurrency, for disputes resolved with a refund
+    RefundAmount float64 `json:"refund_amount,omitempty" db:"refund_amount"`
+
+    // Payment processor ID of the refund
+    RefundID string `json:"refund_id,omitempty" db:"refund_id"`
+
+    // Time the dispute was raised
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+    // Time the dispute was resolved
+    ResolvedAt *time.Time `json:"resolved_at,omitempty" db:"resolved_at"`
+}
@@ -0,0 +1,34 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "math"
+    "time"
+)
+
+// ExchangeRate converts amounts between two currencies at a published rate. Currencies are
+// ISO 4217 codes in upper case.
+type ExchangeRate struct {
+    From string `json:"from"`
+    To   string `json:"to"`
+
+    // Rate is the number of units of To bought by one unit of From
+    Rate float64 `json:"rate"`
+
+    // AsOf is when the provider published the rate
+    AsOf time.Time `json:"as_of"`
+}
+
+// Convert converts an amount in From to To, rounded to cents
+func (r ExchangeRate) Convert(amount float64) float64 {
+    return math.Round(amount*r.Rate*100) / 100
+}
+
+// BookingQuote is a booking's price shown in a currency other than the one it is charged in.
+// It is informational: bookings are stored and charged in BookingCurrency.
+type BookingQuote struct {
+    ExchangeRate
+    Amount float64 `json:"amount"`
+    Tax    float64 `json:"tax"`
+    Total  float64 `json:"total"`
+}
@@ -0,0 +1,93 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "fmt"
+    "strings"
+    "time"
+)
+
+// HolidayDateLayout is the layout of a Holiday's Date
+const HolidayDateLayout = "2006-01-02"
+
+// HolidayKind says what a date in a region's holiday calendar does to walks on it
+type HolidayKind string
+
+// Holiday kinds
+const (
+    // HolidayBlackout is a date no walks can be booked on
+    HolidayBlackout HolidayKind = "blackout"
+
+    // HolidaySurcharge is a date walks can be booked on at SurchargePercent over the walker's rates
+    HolidaySurcharge HolidayKind = "holiday"
+)
+
+// Holiday is a date in a region's holiday calendar. Dates in the DefaultRuleSetRegion calendar
+// apply in every region, unless a region has an entry of its own for the same date.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type Holiday struct {
+    Region string `json:"region" db:"region"`
+
+    // Date is the day in TimeZone, such as "2026-12-25"
+    Date string `json:"date" db:"date"`
+
+    // Name is shown to owners refused a walk on the date, such as "Christmas Day"
+    Name string      `json:"name" db:"name"`
+    Kind HolidayKind `json:"kind" db:"kind"`
+
+    // SurchargePercent is added to the base price of walks on a HolidaySurcharge date
+    SurchargePercent float64 `json:"surcharge_percent,omitempty" db:"surcharge_percent"`
+
+    // TimeZone is the IANA zone the date is reckoned in; UTC when empty
+    TimeZone string `json:"time_zone,omitempty" db:"time_zone"`
+
+    UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks the date exists and the surcharge suits the kind of day
+func (h *Holiday) Validate() error {
+    if h.Region == "" {
+        return fmt.Errorf("region is required")
+    }
+    if _, err := time.Parse(HolidayDateLayout, h.Date); err != nil {
+        return fmt.Errorf("date must be given as YYYY-MM-DD")
+    }
+    if strings.TrimSpace(h.Name) == "" {
+        return fmt.Errorf("name is required")
+    }
+    if _, err := time.LoadLocation(h.TimeZone); err != nil {
+        return fmt.Errorf("unknown time zone %q", h.TimeZone)
+    }
+    switch h.Kind {
+    case HolidayBlackout:
+        if h.SurchargePercent != 0 {
+            return fmt.Errorf("a blackout date cannot have a surcharge")
+        }
+    case HolidaySurcharge:
+        if h.SurchargePercent <= 0 || h.SurchargePercent > 100 {
+            return fmt.Errorf("holiday surcharge must be above 0 and at most 100 percent")
+        }
+    default:
+        return fmt.Errorf("kind must be %q or %q", HolidayBlackout, HolidaySurcharge)
+    }
+    return nil
+}
+
+// Period returns the start and end of the date in its time zone
+func (h *Holiday) Period() (time.Time, time.Time) {
+    location, err := time.LoadLocation(h.TimeZone)
+    if err != nil {
+        location = time.UTC
+    }
+    start, err := time.ParseInLocation(HolidayDateLayout, h.Date, location)
+    if err != nil {
+        return time.Time{}, time.Time{}
+    }
+    return start, start.AddDate(0, 0, 1)
+}
+
+// Overlaps reports whether any of [start, end) falls on the date
+func (h *Holiday) Overlaps(start, end time.Time) bool {
+    from, to := h.Period()
+    return start.Before(to) && end.After(from)
+}
@@ -0,0 +1,110 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// Device is a phone registered to receive a user's push notifications. A token belongs to
+// one user at a time; registering it again moves it to whoever is signed in on the device.
+type Device struct {
+    // Token is the FCM registration token or APNs device token
+    Token  string `json:"token" db:"token"`
+    UserID string `json:"user_id" db:"user_id"`
+
+    // Platform is "android" or "ios"
+    Platform string `json:"platform" db:"platform"`
+
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+    UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NotificationPreferences are the push notifications a user wants, and the language they are
+// written in. Users without saved preferences receive every notification in the default language.
+type NotificationPreferences struct {
+    UserID string `json:"user_id" db:"user_id"`
+
+    // PushEnabled turns every push notification on or off
+    PushEnabled bool `json:"push_enabled" db:"push_enabled"`
+
+    // MutedCategories are the notification categories the user does not want pushed
+    MutedCategories []string `json:"muted_categories" db:"muted_categories"`
+
+    // Locale is the language of the user's notifications, texts and emails; empty for the default
+    Locale string `json:"locale,omitempty" db:"locale"`
+
+    UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultNotificationPreferences returns the preferences of a user who has saved none
+func DefaultNotificationPreferences(userID string) *NotificationPreferences {
+    return &NotificationPreferences{
+        UserID:          userID,
+        PushEnabled:     true,
+        MutedCategories: []string{},
+    }
+}
+
+// Allows reports whether a notification in category should be pushed to the user
+func (p *NotificationPreferences) Allows(category string) bool {
+    if !p.PushEnabled {
+        return false
+    }
+    for _, muted := range p.MutedCategories {
+        if muted == category {
+            return false
+        }
+    }
+    return true
+}
+
+// DeliveryReceipt records what happened to a push notification sent to one of a user's
+// devices, so support can find out why a notification did not arrive
+type DeliveryReceipt struct {
+    ID     string `json:"id" db:"id"`
+    UserID string `json:"user_id" db:"user_id"`
+
+    // Platform and DeviceToken are empty when the notification was not sent to any device
+    Platform    string `json:"platform,omitempty" db:"platform"`
+    DeviceToken string `json:"device_token,omitempty" db:"device_token"`
+
+    Category string `json:"category,omitempty" db:"category"`
+    Subject  string `json:"subject" db:"subject"`
+
+    // Status is one of the notifier delivery statuses, e.g. "sent" or "muted"
+    Status string `json:"status" db:"status"`
+
+    // MessageID is the platform's ID of a sent message
+    MessageID string `json:"message_id,omitempty" db:"message_id"`
+
+    // Error explains a failed delivery
+    Error string `json:"error,omitempty" db:"error"`
+
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// InboxNotification is a notification kept in a user's in-app notification center, whether or
+// not it was pushed to their devices
+type InboxNotification struct {
+    ID       string `json:"id" db:"id"`
+    UserID   string `json:"user_id" db:"user_id"`
+    Category string `json:"category,omitempty" db:"category"`
+    Subject  string `json:"subject" db:"subject"`
+    Body     string `json:"body" db:"body"`
+
+    // Data carries the same fields as the push notification, such as booking_id, so the apps
+    // can open the same screen from either
+    Data map[string]string `json:"data,omitempty" db:"data"`
+
+    // ReadAt is when the user read the notification; nil while unread
+    ReadAt *time.Time `json:"read_at,omitempty" db:"read_at"`
+
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// InboxPage is one page of a user's notification center, newest first
+type InboxPage struct {
+    Notifications []InboxNotification `json:"notifications"`
+    UnreadCount   int                 `json:"unread_count"`
+
+    // NextBefore is passed as before to fetch the following page; empty on the last page
+    NextBefore string `json:"next_before,omitempty"`
+}
@@ -0,0 +1,180 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "database/sql/driver"
+    "encoding/json"
+    "fmt"
+    "math"
+    "time"
+)
+
+// RatePlan is a walker's own pricing: a base rate for a standard walk and the surcharges
+// added to it. Bookings with a walker who has a plan are priced from it instead of the
+// amount the client sends.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type RatePlan struct {
+    WalkerID string `json:"walker_id" db:"walker_id"`
+
+    // BaseRate is the price of a DefaultDurationMinutes walk for one dog; other lengths are
+    // priced pro rata
+    BaseRate float64 `json:"base_rate" db:"base_rate"`
+
+    // WeekendSurchargePercent is added to the base price of walks on a Saturday or Sunday,
+    // e.g. 20 for 20%
+    WeekendSurchargePercent float64 `json:"weekend_surcharge_percent" db:"weekend_surcharge_percent"`
+
+    // LargeDogSurcharge is the flat amount added when the dog is large
+    LargeDogSurcharge float64 `json:"large_dog_surcharge" db:"large_dog_surcharge"`
+
+    // ExtraDogSurcharge is the flat amount added for each dog beyond the first
+    ExtraDogSurcharge float64 `json:"extra_dog_surcharge" db:"extra_dog_surcharge"`
+
+    // TimeZone is the IANA zone the walker works in, deciding which days are weekends; UTC when empty
+    TimeZone string `json:"time_zone,omitempty" db:"time_zone"`
+
+    UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Rate surcharge names recorded in a RateSnapshot
+const (
+    SurchargeWeekend  = "weekend"
+    SurchargeLargeDog = "large_dog"
+    SurchargeExtraDog = "extra_dogs"
+    SurchargeHoliday  = "holiday"
+    SurchargeSurge    = "surge"
+)
+
+// Validate checks the rate plan is complete and its rates are sensible
+func (p *RatePlan) Validate() error {
+    if p.WalkerID == "" {
+        return fmt.Errorf("walker ID is required")
+    }
+    if p.BaseRate <= 0 {
+        return fmt.Errorf("base rate must be positive")
+    }
+    if p.WeekendSurchargePercent < 0 || p.WeekendSurchargePercent > 100 {
+        return fmt.Errorf("weekend surcharge must be between 0 and 100 percent")
+    }
+    if p.LargeDogSurcharge < 0 || p.ExtraDogSurcharge < 0 {
+        return fmt.Errorf("surcharges must be non-negative")
+    }
+    if _, err := time.LoadLocation(p.TimeZone); err != nil {
+        return fmt.Errorf("unknown time zone %q", p.TimeZone)
+    }
+    return nil
+}
+
+// Price works out what a walk costs under the plan. extraDogs counts the dogs walked on
+// the booking beyond the first.
+func (p *RatePlan) Price(scheduledAt time.Time, durationMinutes int, largeDog bool, extraDogs int) RateSnapshot {
+    if durationMinutes <= 0 {
+        durationMinutes = DefaultDurationMinutes
+    }
+    snapshot := RateSnapshot{
+        BaseRate:        p.BaseRate,
+        DurationMinutes: durationMinutes,
+        LargeDog:        largeDog,
+        ExtraDogs:       extraDogs,
+        Base:            roundCents(p.BaseRate * float64(durationMinutes) / DefaultDurationMinutes),
+    }
+
+    location, err := time.LoadLocation(p.TimeZone)
+    if err != nil {
+        location = time.UTC
+    }
+    if day := scheduledAt.In(location).Weekday(); p.WeekendSurchargePercent > 0 && (day == time.Saturday || day == time.Sunday) {
+        snapshot.addSurcharge(SurchargeWeekend, snapshot.Base*p.WeekendSurchargePercent/100)
+    }
+    if largeDog && p.LargeDogSurcharge > 0 {
+        snapshot.addSurcharge(SurchargeLargeDog, p.LargeDogSurcharge)
+    }
+    if extraDogs > 0 && p.ExtraDogSurcharge > 0 {
+        snapshot.addSurcharge(SurchargeExtraDog, p.ExtraDogSurcharge*float64(extraDogs))
+    }
+
+    snapshot.Amount = snapshot.Base
+    for _, surcharge := range snapshot.Surcharges {
+        snapshot.Amount += surcharge.Amount
+    }
+    snapshot.Amount = roundCents(snapshot.Amount)
+    return snapshot
+}
+
+// Surcharge is one addition to the base price of a walk
+type Surcharge struct {
+    Name   string  `json:"name"`
+    Amount float64 `json:"amount"`
+}
+
+// RateSnapshot is how a booking was priced from its walker's rate plan. It is recorded with
+// the booking so the price can be explained after the walker changes their rates.
+type RateSnapshot struct {
+    // BaseRate is the plan's rate for a standard walk when the booking was priced
+    BaseRate        float64 `json:"base_rate"`
+    DurationMinutes int     `json:"duration_minutes"`
+
+    // The dog details the walk was priced for, so it can be priced the same way again
+    LargeDog  bool `json:"large_dog,omitempty"`
+    ExtraDogs int  `json:"extra_dogs,omitempty"`
+
+    // Base is the price of the walk's length before surcharges
+    Base       float64     `json:"base"`
+    Surcharges []Surcharge `json:"surcharges,omitempty"`
+
+    // SurgeMultiplier is what the price was multiplied by for demand in the walk's region and
+    // hour, disclosed to the owner; omitted when prices were not raised
+    SurgeMultiplier float64 `json:"surge_multiplier,omitempty"`
+
+    // Amount is the price before any group walk discount and tax
+    Amount float64 `json:"amount"`
+}
+
+// addSurcharge records a surcharge, rounded to cents
+func (s *RateSnapshot) addSurcharge(name string, amount float64) {
+    s.Surcharges = append(s.Surcharges, Surcharge{Name: name, Amount: roundCents(amount)})
+}
+
+// ApplyHoliday adds the surcharge of a HolidaySurcharge date to a priced walk
+func (s *RateSnapshot) ApplyHoliday(holiday *Holiday) {
+    if holiday == nil || holiday.Kind != HolidaySurcharge {
+        return
+    }
+    surcharge := roundCents(s.Base * holiday.SurchargePercent / 100)
+    s.addSurcharge(SurchargeHoliday, surcharge)
+    s.Amount = roundCents(s.Amount + surcharge)
+}
+
+// ApplySurge multiplies a priced walk by the surge multiplier of its region and hour, adding
+// the difference as a surcharge
+func (s *RateSnapshot) ApplySurge(multiplier float64) {
+    if multiplier <= 1 {
+        return
+    }
+    surcharge := roundCents(s.Amount * (multiplier - 1))
+    s.SurgeMultiplier = multiplier
+    s.addSurcharge(SurchargeSurge, surcharge)
+    s.Amount = roundCents(s.Amount + surcharge)
+}
+
+// Value stores the snapshot as JSON
+func (s RateSnapshot) Value() (driver.Value, error) {
+    return json.Marshal(s)
+}
+
+// Scan reads a snapshot stored as JSON
+func (s *RateSnapshot) Scan(src interface{}) error {
+    switch v := src.(type) {
+    case []byte:
+        return json.Unmarshal(v, s)
+    case string:
+        return json.Unmarshal([]byte(v), s)
+    default:
+        return fmt.Errorf("cannot scan %T into rate snapshot", src)
+    }
+}
+
+// roundCents rounds an amount to whole cents
+func roundCents(amount float64) float64 {
+    return math.Round(amount*100) / 100
+}
@@ -0,0 +1,73 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// ReceiptLineItem is a single charge on a receipt
+type ReceiptLineItem struct {
+    Description string `json:"description"`
+    AmountCents int64  `json:"amount_cents"`
+}
+
+// Receipt is the record of what an owner paid for a completed booking. It is issued once,
+// stored alongside its rendered document and never changed afterwards.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type Receipt struct {
+    // Number identifies the receipt to the owner and to support
+    Number string `json:"number"`
+
+    BookingID       string    `json:"booking_id"`
+    OwnerID         string    `json:"owner_id"`
+    WalkerID        string    `json:"walker_id"`
+    DogID           string    `json:"dog_id"`
+    ScheduledAt     time.Time `json:"scheduled_at"`
+    DurationMinutes int       `json:"duration_minutes"`
+    IssuedAt        time.Time `json:"issued_at"`
+
+    // Currency of every amount on the receipt; amounts are in its smallest unit
+    Currency   string            `json:"currency"`
+    LineItems  []ReceiptLineItem `json:"line_items"`
+    TotalCents int64             `json:"total_cents"`
+}
+
+// NewReceipt builds the receipt of a completed booking issued at issuedAt, itemizing any tax
+func NewReceipt(booking *Booking, issuedAt time.Time) *Receipt {
+    amount := AmountCents(booking.Amount)
+    items := []ReceiptLineItem{{Description: "Dog walk", AmountCents: amount}}
+    total := amount
+    if booking.Tax != nil {
+        for _, line := range booking.Tax.Lines {
+            cents := AmountCents(line.Amount)
+            items = append(items, ReceiptLineItem{
+                Description: fmt.Sprintf("%s (%s%%)", line.Name, strconv.FormatFloat(line.RatePercent, 'f', -1, 64)),
+                AmountCents: cents,
+            })
+            total += cents
+        }
+    }
+
+    // Booking IDs are opaque; the first characters are enough for a readable number
+    ref := strings.ToUpper(booking.ID)
+    if len(ref) > 8 {
+        ref = ref[:8]
+    }
+
+    return &Receipt{
+        Number:          "PW-" + issuedAt.UTC().Format("20060102") + "-" + ref,
+        BookingID:       booking.ID,
+        OwnerID:         booking.OwnerID,
+        WalkerID:        booking.WalkerID,
+        DogID:           booking.DogID,
+        ScheduledAt:     booking.ScheduledAt,
+        DurationMinutes: booking.DurationMinutes,
+        IssuedAt:        issuedAt,
+        Currency:        BookingCurrency,
+        LineItems:       items,
+        TotalCents:      total,
+    }
+}
@@ -0,0 +1,91 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "math"
+    "time"
+)
+
+// ReconciliationStatus is the progress of a reconciliation run
+type ReconciliationStatus string
+
+// Reconciliation run status constants
+const (
+    ReconciliationRunning   ReconciliationStatus = "running"
+    ReconciliationCompleted ReconciliationStatus = "completed"
+    ReconciliationFailed    ReconciliationStatus = "failed"
+)
+
+// DiscrepancyKind classifies a mismatch between a booking and the payments recorded for it
+type DiscrepancyKind string
+
+// Discrepancy kind constants
+const (
+    // DiscrepancyMissingPayment is a completed booking, or one cancelled with a fee, with
+    // nothing captured
+    DiscrepancyMissingPayment DiscrepancyKind = "missing_payment"
+
+    // DiscrepancyAmountMismatch is a completed booking whose net payment differs from its amount plus
+    // tax, or a cancelled one refunded more than all but its cancellation fee
+    DiscrepancyAmountMismatch DiscrepancyKind = "amount_mismatch"
+
+    // DiscrepancyUnrefundedCancellation is a cancelled or failed booking that still holds money
+    DiscrepancyUnrefundedCancellation DiscrepancyKind = "unrefunded_cancellation"
+
+    // DiscrepancyOverRefund is a booking refunded more than was captured for it
+    DiscrepancyOverRefund DiscrepancyKind = "over_refund"
+
+    // DiscrepancyCurrencyMismatch is a payment taken in a currency other than the booking's
+    DiscrepancyCurrencyMismatch DiscrepancyKind = "currency_mismatch"
+
+    // DiscrepancyUnknownBooking is a payment referencing a booking that does not exist
+    DiscrepancyUnknownBooking DiscrepancyKind = "unknown_booking"
+)
+
+// BookingCurrency is the currency booking amounts are priced in
+const BookingCurrency = "usd"
+
+// ReconciliationRun is one comparison of booking amounts against payment captures and refunds
+// for the bookings scheduled in [PeriodStart, PeriodEnd)
+type ReconciliationRun struct {
+    ID          string               `json:"id" db:"id"`
+    PeriodStart time.Time            `json:"period_start" db:"period_start"`
+    PeriodEnd   time.Time            `json:"period_end" db:"period_end"`
+    Status      ReconciliationStatus `json:"status" db:"status"`
+    StartedAt   time.Time            `json:"started_at" db:"started_at"`
+    CompletedAt *time.Time           `json:"completed_at,omitempty" db:"completed_at"`
+
+    // BookingsChecked is how many bookings were compared against their payments
+    BookingsChecked int `json:"bookings_checked" db:"bookings_checked"`
+
+    // DiscrepancyCount is how many discrepancies the run found
+    DiscrepancyCount int `json:"discrepancy_count" db:"discrepancy_count"`
+
+    // DiscrepancyCents is the sum of the absolute differences, in cents
+    DiscrepancyCents int64 `json:"discrepancy_cents" db:"discrepancy_cents"`
+
+    // Error is why a failed run stopped
+    Error string `json:"error,omitempty" db:"error"`
+
+    // Discrepancies are only loaded when a single run is requested
+    Discrepancies []ReconciliationDiscrepancy `json:"discrepancies,omitempty" db:"-"`
+}
+
+// ReconciliationDiscrepancy is a booking whose payments do not match its amount. Amounts are
+// in cents; DifferenceCents is the net payment minus the expected amount.
+type ReconciliationDiscrepancy struct {
+    RunID           string          `json:"run_id" db:"run_id"`
+    BookingID       string          `json:"booking_id" db:"booking_id"`
+    Kind            DiscrepancyKind `json:"kind" db:"kind"`
+    BookingStatus   BookingStatus   `json:"booking_status,omitempty" db:"booking_status"`
+    ExpectedCents   int64           `json:"expected_cents" db:"expected_cents"`
+    CapturedCents   int64           `json:"captured_cents" db:"captured_cents"`
+    RefundedCents   int64           `json:"refunded_cents" db:"refunded_cents"`
+    DifferenceCents int64           `json:"difference_cents" db:"difference_cents"`
+    Currency        string          `json:"currency" db:"currency"`
+}
+
+// AmountCents converts a booking amount to cents, the unit payments are recorded in
+func AmountCents(amount float64) int64 {
+    return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,87 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "fmt"
+    "time"
+)
+
+// ReferralStatus represents how far a referred user has progressed
+type ReferralStatus string
+
+// Referral status constants
+const (
+    // ReferralStatusSignedUp means the referred user has been attributed but has not booked yet
+    ReferralStatusSignedUp ReferralStatus = "signed_up"
+
+    // ReferralStatusConverted means the referred user made their first booking and credit was granted
+    ReferralStatusConverted ReferralStatus = "converted"
+)
+
+// ReferralCode is a user's personal code for inviting others
+type ReferralCode struct {
+    // The shareable code
+    Code string `json:"code" db:"code"`
+
+    // ID of the user who owns the code
+    UserID string `json:"user_id" db:"user_id"`
+
+    // Time the code was issued
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Referral attributes a referred user to the user whose code they signed up with.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type Referral struct {
+    // Unique identifier for the referral
+    ID string `json:"id" db:"id"`
+
+    // Referral code used
+    Code string `json:"code" db:"code"`
+
+    // ID of the user who owns the code
+    ReferrerID string `json:"referrer_id" db:"referrer_id"`
+
+    // ID of the referred user
+    RefereeID string `json:"referee_id" db:"referee_id"`
+
+    // Current state of the referral
+    Status ReferralStatus `json:"status" db:"status"`
+
+    // Time the referred user was attributed
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+    // First booking made by the referred user, once converted
+    BookingID *string `json:"booking_id,omitempty" db:"booking_id"`
+
+    // Time of the first booking, once converted
+    ConvertedAt *time.Time `json:"converted_at,omitempty" db:"converted_at"`
+
+    // Credit granted to the referrer on conversion
+    CreditAmount float64 `json:"credit_amount" db:"credit_amount"`
+}
+
+// Validate performs basic validation on the referral data.
+func (r *Referral) Validate() error {
+    if r.ID == "" {
+        return fmt.Errorf("referral ID is required")
+    }
+    if r.Code == "" {
+        return fmt.Errorf("referral code is required")
+    }
+    if r.RefereeID == "" {
+        return fmt.Errorf("referred user ID is required")
+    }
+    if r.ReferrerID == r.RefereeID {
+        return fmt.Errorf("users cannot refer themselves")
+    }
+    return nil
+}
+
+// ReferralSummary aggregates one referrer's referrals over a reporting period
+type ReferralSummary struct {
+    ReferrerID     string  `json:"referrer_id" db:"referrer_id"`
+    Signups        int     `json:"signups" db:"signups"`
+    Conversions    int     `json:"conversions" db:"conversions"`
+    CreditsGranted float64 `json:"credits_granted" db:"credits_granted"`
+}
@@ -0,0 +1,149 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "fmt"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/cron"
+)
+
+// ReportKind is an admin report that can be emailed on a schedule
+type ReportKind string
+
+// Report kind constants
+const (
+    // ReportBookingsSummary counts the bookings scheduled in the period by region and status
+    ReportBookingsSummary ReportKind = "bookings_summary"
+
+    // ReportEarnings lists each walker's booking amounts and tips for the walks completed in
+    // the period
+    ReportEarnings ReportKind = "earnings"
+
+    // ReportTrackingAnomalies lists the walks completed in the period whose tracking looks
+    // wrong, such as walks never tracked or cut short
+    ReportTrackingAnomalies ReportKind = "tracking_anomalies"
+)
+
+// IsValid checks if the report kind is supported
+func (k ReportKind) IsValid() bool {
+    switch k {
+    case ReportBookingsSummary, ReportEarnings, ReportTrackingAnomalies:
+        return true
+    }
+    return false
+}
+
+// ReportSchedule emails a report to its recipients each time its cron expression fires. Each
+// email covers the period since the expression last fired.
+type ReportSchedule struct {
+    Name       string         `json:"name"`
+    Report     ReportKind     `json:"report"`
+    Cron       *cron.Schedule `json:"cron"`
+    Recipients []string       `json:"recipients"`
+}
+
+// ParseReportSchedules parses schedules configured as
+// "NAME|REPORT|CRON|RECIPIENT,RECIPIENT;NAME|REPORT|CRON|RECIPIENT", such as
+// "daily-bookings|bookings_summary|0 6 * * *|ops@example.com". Cron expressions are in UTC.
+func ParseReportSchedules(s string) ([]ReportSchedule, error) {
+    var schedules []ReportSchedule
+    names := make(map[string]bool)
+    for _, entry := range strings.Split(s, ";") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        parts := strings.Split(entry, "|")
+        if len(parts) != 4 {
+            return nil, fmt.Errorf("invalid report schedule %q: want NAME|REPORT|CRON|RECIPIENTS", entry)
+        }
+
+        name := strings.TrimSpace(parts[0])
+        if name == "" || strings.Trim(name, "abcdefghijklmnopqrstuvwxyz0123456789-_") != "" {
+            return nil, fmt.Errorf("invalid report schedule %q: name must be lowercase letters, digits, - and _", entry)
+        }
+        if names[name] {
+            return nil, fmt.Errorf("invalid report schedule %q: %s is scheduled twice", entry, name)
+        }
+        names[name] = true
+
+        report := ReportKind(strings.TrimSpace(parts[1]))
+        if !report.IsValid() {
+            return nil, fmt.Errorf("invalid report schedule %q: unknown report %q", entry, report)
+        }
+        schedule, err := cron.Parse(parts[2])
+        if err != nil {
+            return nil, fmt.Errorf("invalid report schedule %q: %w", entry, err)
+        }
+
+        var recipients []string
+        for _, address := range strings.Split(parts[3], ",") {
+            if address = strings.TrimSpace(address); address != "" {
+                if !strings.Contains(address, "@") {
+                    return nil, fmt.Errorf("invalid report schedule %q: %q is not an email address", entry, address)
+                }
+                recipients = append(recipients, address)
+            }
+        }
+        if len(recipients) == 0 {
+            return nil, fmt.Errorf("invalid report schedule %q: no recipients", entry)
+        }
+
+        schedules = append(schedules, ReportSchedule{
+            Name:       name,
+            Report:     report,
+            Cron:       schedule,
+            Recipients: recipients,
+        })
+    }
+    return schedules, nil
+}
+
+// ScheduledReport is a report schedule with when it next fires and its most recent run
+type ScheduledReport struct {
+    ReportSchedule
+    NextRunAt time.Time  `json:"next_run_at"`
+    LastRun   *ReportRun `json:"last_run,omitempty"`
+}
+
+// ReportRunStatus is the progress of a scheduled report run
+type ReportRunStatus string
+
+// Report run status constants
+const (
+    ReportRunRunning   ReportRunStatus = "running"
+    ReportRunCompleted ReportRunStatus = "completed"
+    ReportRunFailed    ReportRunStatus = "failed"
+)
+
+// ReportRun is one rendering and emailing of a scheduled report, covering the bookings
+// scheduled in [PeriodStart, PeriodEnd)
+type ReportRun struct {
+    ID       string     `json:"id" db:"id"`
+    Schedule string     `json:"schedule" db:"schedule"`
+    Report   ReportKind `json:"report" db:"report"`
+
+    // ScheduledFor is when the schedule fired, or when an admin ran it; a schedule runs once
+    // for each
+    ScheduledFor time.Time `json:"scheduled_for" db:"scheduled_for"`
+
+    PeriodStart time.Time       `json:"period_start" db:"period_start"`
+    PeriodEnd   time.Time       `json:"period_end" db:"period_end"`
+    Status      ReportRunStatus `json:"status" db:"status"`
+
+    // Attempts counts the tries at the run, including retries after failures
+    Attempts int `json:"attempts" db:"attempts"`
+
+    // Recipients is how many addresses the report was sent to, and Delivered how many of
+    // them the email reached
+    Recipients int `json:"recipients" db:"recipients"`
+    Delivered  int `json:"delivered" db:"delivered"`
+
+    StartedAt   time.Time  `json:"started_at" db:"started_at"`
+    CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+
+    // Error is why a failed run stopped
+    Error string `json:"error,omitempty" db:"error"`
+}
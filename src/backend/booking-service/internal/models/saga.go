@@ -0,0 +1,119 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "database/sql/driver"
+    "encoding/json"
+    "fmt"
+    "time"
+)
+
+// Saga kinds
+const (
+    // SagaKindBookingConfirmation confirms a booking once its walker accepts it
+    SagaKindBookingConfirmation = "booking_confirmation"
+
+    // SagaKindWalkerSuspension moves a confirmed booking of a suspended walker to another walker
+    SagaKindWalkerSuspension = "walker_suspension"
+)
+
+// SagaStatus is how far a saga has got
+type SagaStatus string
+
+// Saga status constants
+const (
+    // SagaStatusRunning means the saga's steps are being run
+    SagaStatusRunning SagaStatus = "running"
+
+    // SagaStatusCompensating means a step failed and the steps before it are being undone
+    SagaStatusCompensating SagaStatus = "compensating"
+
+    // SagaStatusCompleted means every step ran; best-effort steps may have failed
+    SagaStatusCompleted SagaStatus = "completed"
+
+    // SagaStatusCompensated means a step failed and every step before it was undone
+    SagaStatusCompensated SagaStatus = "compensated"
+)
+
+// Finished reports whether the saga has nothing left to run or undo
+func (s SagaStatus) Finished() bool {
+    return s == SagaStatusCompleted || s == SagaStatusCompensated
+}
+
+// SagaStepStatus is the outcome of one step of a saga
+type SagaStepStatus string
+
+// Saga step status constants
+const (
+    SagaStepPending     SagaStepStatus = "pending"
+    SagaStepDone        SagaStepStatus = "done"
+    SagaStepSkipped     SagaStepStatus = "skipped"
+    SagaStepFailed      SagaStepStatus = "failed"
+    SagaStepCompensated SagaStepStatus = "compensated"
+)
+
+// SagaStep records one step of a saga
+type SagaStep struct {
+    Name     string         `json:"name"`
+    Status   SagaStepStatus `json:"status"`
+    Attempts int            `json:"attempts"`
+
+    // Error is the step's last failure, or of its compensation
+    Error string `json:"error,omitempty"`
+
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SagaSteps is stored as JSON
+type SagaSteps []SagaStep
+
+// Value stores the steps as JSON
+func (s SagaSteps) Value() (driver.Value, error) {
+    return json.Marshal(s)
+}
+
+// Scan reads steps stored as JSON
+func (s *SagaSteps) Scan(src interface{}) error {
+    switch v := src.(type) {
+    case []byte:
+        return json.Unmarshal(v, s)
+    case string:
+        return json.Unmarshal([]byte(v), s)
+    default:
+        return fmt.Errorf("cannot scan %T into saga steps", src)
+    }
+}
+
+// Saga is a change spanning the booking-service and the services it calls, run as a series
+// of steps each of which can be undone. Its state is saved after every step, so a saga
+// interrupted by a crash or a restart is finished or undone by the background jobs instead of
+// leaving the booking half changed.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type Saga struct {
+    ID        string     `json:"id" db:"id"`
+    Kind      string     `json:"kind" db:"kind"`
+    BookingID string     `json:"booking_id" db:"booking_id"`
+    WalkerID  string     `json:"walker_id" db:"walker_id"`
+    Status    SagaStatus `json:"status" db:"status"`
+    Steps     SagaSteps  `json:"steps" db:"steps"`
+
+    // PaymentID is the payment authorized for the booking; empty until one has been
+    PaymentID string `json:"payment_id,omitempty" db:"payment_id"`
+
+    // AcceptBy is the walker's acceptance deadline before the saga started, restored if the
+    // booking goes back to awaiting them
+    AcceptBy *time.Time `json:"accept_by,omitempty" db:"accept_by"`
+
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+    UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Step returns the named step, or nil when the saga has no such step
+func (s *Saga) Step(name string) *SagaStep {
+    for i := range s.Steps {
+        if s.Steps[i].Name == name {
+            return &s.Steps[i]
+        }
+    }
+    return nil
+}
@@ -0,0 +1,48 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// WalkerShift is a walker's time on a walk, from checking in at the pickup to checking out once
+// the dog is home. A walker checks in with their last tracked position, unless support checks
+// them in instead.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type WalkerShift struct {
+    // Booking walked during the shift
+    BookingID string `json:"booking_id" db:"booking_id"`
+
+    // Walker who checked in
+    WalkerID string `json:"walker_id" db:"walker_id"`
+
+    // Time the walker checked in at the pickup
+    CheckedInAt time.Time `json:"checked_in_at" db:"checked_in_at"`
+
+    // How far, in meters, the walker's last tracked position was from the pickup when they
+    // checked in; nil when support checked them in
+    CheckInDistance *float64 `json:"check_in_distance_m,omitempty" db:"check_in_distance_m"`
+
+    // Support user who checked the walker in without verifying their position; empty unless
+    // the check-in was overridden
+    OverriddenBy string `json:"overridden_by,omitempty" db:"overridden_by"`
+
+    // Time the walker checked out; nil while the walk is in progress
+    CheckedOutAt *time.Time `json:"checked_out_at,omitempty" db:"checked_out_at"`
+}
+
+// CompletionRequirements is the proof a walk happened that a walker must have tracked before
+// checking out completes the booking. Zero values require nothing.
+type CompletionRequirements struct {
+    // MinDuration is the shortest the tracked walk may be
+    MinDuration time.Duration
+
+    // MinDistance is the shortest distance, in meters, the tracked route may cover
+    MinDistance float64
+
+    // MinPhotos is the fewest photos the walker may have taken during the walk
+    MinPhotos int
+}
+
+// Any reports whether any proof of completion is required
+func (r CompletionRequirements) Any() bool {
+    return r.MinDuration > 0 || r.MinDistance > 0 || r.MinPhotos > 0
+}
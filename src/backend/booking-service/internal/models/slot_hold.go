@@ -0,0 +1,49 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// SlotHold reserves a place in a walker's schedule at the price quoted, for an owner who is
+// checking out, so neither can be taken from them before they pay. Holds count against the
+// walker's capacity like bookings until they are booked, released or expire.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type SlotHold struct {
+    // Unique identifier for the hold, sent back as the booking's hold_id
+    ID string `json:"id" db:"id"`
+
+    // ID of the owner the slot is held for
+    OwnerID string `json:"owner_id" db:"owner_id"`
+
+    // ID of the walker whose slot is held
+    WalkerID string `json:"walker_id" db:"walker_id"`
+
+    // Region the walk was quoted in
+    Region string `json:"region,omitempty" db:"region"`
+
+    // Start and length of the walk held; the length is never zero
+    ScheduledAt     time.Time `json:"scheduled_at" db:"scheduled_at"`
+    DurationMinutes int       `json:"duration_minutes" db:"duration_minutes"`
+
+    // Quote is the price the walk is booked at while the hold lasts
+    Quote RateSnapshot `json:"quote" db:"quote"`
+
+    // Time the hold lapses and the slot is released
+    ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+
+    // Time the hold was made
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// EndsAt returns when the walk held ends
+func (h *SlotHold) EndsAt() time.Time {
+    return h.ScheduledAt.Add(time.Duration(h.DurationMinutes) * time.Minute)
+}
+
+// Covers reports whether booking is the walk held: the same walker, time, length and dogs
+func (h *SlotHold) Covers(booking *Booking) bool {
+    return booking.WalkerID == h.WalkerID &&
+        booking.ScheduledAt.Equal(h.ScheduledAt) &&
+        booking.EndsAt().Equal(h.EndsAt()) &&
+        booking.LargeDog == h.Quote.LargeDog &&
+        booking.ExtraDogs == h.Quote.ExtraDogs
+}
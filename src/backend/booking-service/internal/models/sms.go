@@ -0,0 +1,35 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// Sources of SMS opt-outs
+const (
+    // SMSOptOutUser is an opt-out the user made in the app
+    SMSOptOutUser = "user"
+
+    // SMSOptOutReply is an opt-out the user made by replying STOP
+    SMSOptOutReply = "reply"
+
+    // SMSOptOutCarrier is an opt-out reported by the messaging provider when a text was refused
+    SMSOptOutCarrier = "carrier"
+)
+
+// SMSOptOut records that a phone number must not be texted. Opt-outs belong to the number,
+// not the user, as that is how carriers apply them.
+type SMSOptOut struct {
+    Phone string `json:"phone" db:"phone"`
+
+    // Source is how the opt-out was made: SMSOptOutUser, SMSOptOutReply or SMSOptOutCarrier
+    Source string `json:"source" db:"source"`
+
+    OptedOutAt time.Time `json:"opted_out_at" db:"opted_out_at"`
+}
+
+// SMSSettings are the phone number a user is texted at and whether they have opted out
+type SMSSettings struct {
+    // Phone is in E.164 format, e.g. +447700900123; empty when the user has not given one
+    Phone string `json:"phone"`
+
+    OptedOut bool `json:"opted_out"`
+}
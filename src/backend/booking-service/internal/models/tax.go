@@ -0,0 +1,46 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "database/sql/driver"
+    "encoding/json"
+    "fmt"
+)
+
+// TaxLine is one tax charged on a booking, such as a state or city sales tax
+type TaxLine struct {
+    Name string `json:"name"`
+
+    // RatePercent is the rate applied to the booking amount, e.g. 6.25 for 6.25%
+    RatePercent float64 `json:"rate_percent"`
+
+    // Amount is the tax charged, in the booking's currency and rounded to cents
+    Amount float64 `json:"amount"`
+}
+
+// TaxBreakdown is the tax charged on a booking on top of its amount. It is recorded when the
+// booking is priced so receipts and reports show the tax actually charged, even after rates change.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type TaxBreakdown struct {
+    // Provider names the calculator that produced the breakdown, e.g. "flat_rate"
+    Provider string    `json:"provider"`
+    Lines    []TaxLine `json:"lines"`
+    Total    float64   `json:"total"`
+}
+
+// Value stores the breakdown as JSON
+func (t TaxBreakdown) Value() (driver.Value, error) {
+    return json.Marshal(t)
+}
+
+// Scan reads a breakdown stored as JSON
+func (t *TaxBreakdown) Scan(src interface{}) error {
+    switch v := src.(type) {
+    case []byte:
+        return json.Unmarshal(v, t)
+    case string:
+        return json.Unmarshal([]byte(v), t)
+    default:
+        return fmt.Errorf("cannot scan %T into tax breakdown", src)
+    }
+}
@@ -0,0 +1,66 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// TipStatus is the state of the payment taken for a tip
+type TipStatus string
+
+// Tip status constants
+const (
+    // TipStatusPending means the tip is recorded and its payment is being requested
+    TipStatusPending TipStatus = "pending"
+
+    // TipStatusCharged means the payment-service accepted the payment for the tip
+    TipStatusCharged TipStatus = "charged"
+
+    // TipStatusFailed means the payment could not be requested; the owner may tip again
+    TipStatusFailed TipStatus = "failed"
+)
+
+// Tip is an owner's gratuity for the walker of a completed booking. It is kept apart from
+// the booking's amount: it is paid separately, carries no tax and goes to the walker in full.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type Tip struct {
+    // Unique identifier for the tip, also used as the payment ID
+    ID string `json:"id" db:"id"`
+
+    // Booking the tip is for; a booking has at most one tip
+    BookingID string `json:"booking_id" db:"booking_id"`
+
+    // Owner giving the tip
+    OwnerID string `json:"owner_id" db:"owner_id"`
+
+    // Walker receiving the tip
+    WalkerID string `json:"walker_id" db:"walker_id"`
+
+    // Tip amount in BookingCurrency
+    Amount float64 `json:"amount" db:"amount"`
+
+    // State of the tip's payment
+    Status TipStatus `json:"status" db:"status"`
+
+    // Payment processor ID of the tip's payment, once requested
+    PaymentID string `json:"payment_id,omitempty" db:"payment_id"`
+
+    // Time the tip was given
+    CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// WalkerEarnings sums what a walker earned from completed bookings over a period. Tax is
+// excluded: it is collected for the tax authority, not the walker.
+type WalkerEarnings struct {
+    WalkerID string `json:"walker_id" db:"walker_id"`
+
+    // Number of completed bookings
+    Bookings int `json:"bookings" db:"bookings"`
+
+    // Sum of the bookings' amounts
+    BookingAmount float64 `json:"booking_amount" db:"booking_amount"`
+
+    // Sum of the tips given for the bookings, excluding tips whose payment failed
+    Tips float64 `json:"tips" db:"tips"`
+
+    // Booking amounts plus tips
+    Total float64 `json:"total" db:"total"`
+}
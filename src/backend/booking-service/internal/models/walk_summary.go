@@ -0,0 +1,27 @@
+// Package models defines the core data models for the booking service
+package models
+
+import "time"
+
+// RoutePoint is a position the walker's app recorded during a walk
+type RoutePoint struct {
+    Latitude  float64   `json:"latitude"`
+    Longitude float64   `json:"longitude"`
+    Timestamp time.Time `json:"timestamp"`
+}
+
+// WalkSummary is the walker's account of a finished walk, emailed to the owner. It is not
+// stored; the tracking-service keeps the route.
+type WalkSummary struct {
+    BookingID string       `json:"booking_id"`
+    WalkerID  string       `json:"walker_id"`
+    Notes     string       `json:"notes,omitempty"`
+    Route     []RoutePoint `json:"route"`
+
+    // DistanceKm and DurationMinutes are worked out from the route
+    DistanceKm      float64 `json:"distance_km"`
+    DurationMinutes int     `json:"duration_minutes"`
+
+    // NotesWithheld is set when moderation kept the notes out of the owner's email
+    NotesWithheld bool `json:"notes_withheld,omitempty"`
+}
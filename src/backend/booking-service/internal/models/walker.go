@@ -0,0 +1,65 @@
+// Package models defines the core data models for the booking service
+package models
+
+import (
+    "time"
+)
+
+// WalkerVerificationStatus is how far a walker has progressed through identity verification
+type WalkerVerificationStatus string
+
+// Walker verification status constants
+const (
+    // WalkerUnverified means the walker has not started verification; walkers without a record are unverified
+    WalkerUnverified WalkerVerificationStatus = "unverified"
+
+    // WalkerPending means the walker's documents are being checked
+    WalkerPending WalkerVerificationStatus = "pending"
+
+    // WalkerVerified means the walker's identity has been confirmed
+    WalkerVerified WalkerVerificationStatus = "verified"
+
+    // WalkerSuspended means the walker has been barred from taking bookings
+    WalkerSuspended WalkerVerificationStatus = "suspended"
+)
+
+// EligibleWalkerStatuses lists the statuses that allow a walker to be booked or assigned
+var EligibleWalkerStatuses = []WalkerVerificationStatus{WalkerPending, WalkerVerified}
+
+// IsValid reports whether s is a known verification status
+func (s WalkerVerificationStatus) IsValid() bool {
+    switch s {
+    case WalkerUnverified, WalkerPending, WalkerVerified, WalkerSuspended:
+        return true
+    }
+    return false
+}
+
+// CanTakeBookings reports whether a walker with status s may be booked or assigned
+func (s WalkerVerificationStatus) CanTakeBookings() bool {
+    for _, eligible := range EligibleWalkerStatuses {
+        if s == eligible {
+            return true
+        }
+    }
+    return false
+}
+
+// WalkerVerification is the current identity verification status of a walker.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type WalkerVerification struct {
+    // ID of the walker
+    WalkerID string `json:"walker_id" db:"walker_id"`
+
+    // Current verification status
+    Status WalkerVerificationStatus `json:"status" db:"status"`
+
+    // Reason given for the last change, such as why a walker was suspended
+    Reason string `json:"reason,omitempty" db:"reason"`
+
+    // ID of the admin who made the last change
+    UpdatedBy string `json:"updated_by,omitempty" db:"updated_by"`
+
+    // Time of the last change; zero for walkers who were never reviewed
+    UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,181 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "bytes"
+    "context"
+    "crypto/ecdsa"
+    "crypto/rand"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/json"
+    "encoding/pem"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "os"
+    "strings"
+    "sync"
+    "time"
+)
+
+const (
+    // apnsProductionURL and apnsSandboxURL are the APNs hosts for release and debug builds
+    apnsProductionURL = "https://api.push.apple.com"
+    apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+
+    // apnsTokenLifetime is how long a provider token is reused; APNs rejects tokens older
+    // than an hour and refreshing more often than every 20 minutes
+    apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsSender sends notifications through the Apple Push Notification service, authenticating
+// with a token signed by the team's auth key. Requests use HTTP/2, as APNs requires.
+type APNsSender struct {
+    baseURL string
+    keyID   string
+    teamID  string
+    topic   string
+    key     *ecdsa.PrivateKey
+    client  *http.Client
+
+    mu       sync.Mutex
+    token    string
+    issuedAt time.Time
+}
+
+// NewAPNsSender creates a sender from an APNs auth key file. The sandbox environment
+// delivers to debug builds of the app.
+func NewAPNsSender(keyFile, keyID, teamID, topic string, sandbox bool) (*APNsSender, error) {
+    if keyID == "" || teamID == "" || topic == "" {
+        return nil, fmt.Errorf("APNs key ID, team ID and topic are required")
+    }
+    data, err := os.ReadFile(keyFile)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read APNs key: %w", err)
+    }
+    block, _ := pem.Decode(data)
+    if block == nil {
+        return nil, fmt.Errorf("APNs key file holds no private key")
+    }
+    parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+    if err != nil {
+        return nil, fmt.Errorf("failed to parse APNs key: %w", err)
+    }
+    key, ok := parsed.(*ecdsa.PrivateKey)
+    if !ok {
+        return nil, fmt.Errorf("APNs key is not an EC key")
+    }
+
+    baseURL := apnsProductionURL
+    if sandbox {
+        baseURL = apnsSandboxURL
+    }
+    return &APNsSender{
+        baseURL: baseURL,
+        keyID:   keyID,
+        teamID:  teamID,
+        topic:   topic,
+        key:     key,
+        client:  &http.Client{Timeout: 10 * time.Second},
+    }, nil
+}
+
+// Send implements PushSender. Notification data is sent as custom keys beside "aps".
+func (s *APNsSender) Send(ctx context.Context, token string, notification Notification) (string, error) {
+    providerToken, err := s.providerToken()
+    if err != nil {
+        return "", err
+    }
+
+    message := make(map[string]interface{}, len(notification.Data)+1)
+    for key, value := range notification.Data {
+        message[key] = value
+    }
+    message["aps"] = map[string]interface{}{
+        "alert": map[string]string{
+            "title": notification.Subject,
+            "body":  notification.Body,
+        },
+        "sound": "default",
+    }
+    payload, err := json.Marshal(message)
+    if err != nil {
+        return "", fmt.Errorf("failed to encode APNs payload: %w", err)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/3/device/"+url.PathEscape(token), bytes.NewReader(payload))
+    if err != nil {
+        return "", fmt.Errorf("failed to create APNs request: %w", err)
+    }
+    priority := "5"
+    if notification.Priority == "high" {
+        priority = "10"
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "bearer "+providerToken)
+    req.Header.Set("apns-topic", s.topic)
+    req.Header.Set("apns-push-type", "alert")
+    req.Header.Set("apns-priority", priority)
+
+    resp, err := s.client.Do(req)
+    if err != nil {
+        return "", fmt.Errorf("failed to send APNs notification: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode == http.StatusOK {
+        return resp.Header.Get("apns-id"), nil
+    }
+
+    var failure struct {
+        Reason string `json:"reason"`
+    }
+    body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<12))
+    json.Unmarshal(body, &failure)
+    switch {
+    case resp.StatusCode == http.StatusGone, failure.Reason == "BadDeviceToken", failure.Reason == "Unregistered":
+        return "", ErrUnregistered
+    case failure.Reason != "":
+        return "", fmt.Errorf("APNs returned status %d: %s", resp.StatusCode, failure.Reason)
+    default:
+        return "", fmt.Errorf("APNs returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+    }
+}
+
+// providerToken returns the signed token authenticating requests, signing a new one when the
+// current one is due to expire
+func (s *APNsSender) providerToken() (string, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    now := time.Now()
+    if s.token != "" && now.Sub(s.issuedAt) < apnsTokenLifetime {
+        return s.token, nil
+    }
+
+    token, err := signJWT(
+        map[string]string{"alg": "ES256", "kid": s.keyID},
+        map[string]interface{}{"iss": s.teamID, "iat": now.Unix()},
+        func(signingInput []byte) ([]byte, error) {
+            digest := sha256.Sum256(signingInput)
+            r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+            if err != nil {
+                return nil, err
+            }
+            // ES256 signatures are the two 32 byte integers concatenated
+            signature := make([]byte, 64)
+            r.FillBytes(signature[:32])
+            sig.FillBytes(signature[32:])
+            return signature, nil
+        },
+    )
+    if err != nil {
+        return "", err
+    }
+
+    s.token = token
+    s.issuedAt = now
+    return token, nil
+}
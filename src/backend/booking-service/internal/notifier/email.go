@@ -0,0 +1,333 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "bytes"
+    "context"
+    "embed"
+    "encoding/base64"
+    "fmt"
+    "html"
+    "html/template"
+    "io"
+    "mime"
+    "mime/multipart"
+    "mime/quotedprintable"
+    "net/mail"
+    "net/textproto"
+    "regexp"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/i18n"
+)
+
+// Human Tasks:
+// 1. Verify the sender address or its domain with SES, or create SMTP credentials, in each environment
+// 2. Set BOOKING_EMAIL_PROVIDER and BOOKING_EMAIL_FROM where emails should bypass the notification-service
+
+// Email providers emails can be sent through directly
+const (
+    // EmailProviderSMTP sends emails through an SMTP relay
+    EmailProviderSMTP = "smtp"
+
+    // EmailProviderSES sends emails through Amazon SES
+    EmailProviderSES = "ses"
+)
+
+// Email templates
+const (
+    // EmailBookingConfirmed tells the owner a walker has accepted their booking
+    EmailBookingConfirmed = "booking_confirmed"
+
+    // EmailBookingCancelled tells the owner their booking has been cancelled
+    EmailBookingCancelled = "booking_cancelled"
+
+    // EmailWalkSummary tells the owner how their dog's walk went, with a map of the route
+    EmailWalkSummary = "walk_summary"
+)
+
+// RouteMapContentID is the content ID the walk summary refers to its route map image by
+const RouteMapContentID = "route-map"
+
+//go:embed templates/*.html
+var emailTemplateFiles embed.FS
+
+// emailTemplates renders the bodies of the emails sent to owners. Their text comes from the
+// i18n catalogs through the t function.
+var emailTemplates = template.Must(template.New("email").
+    Funcs(template.FuncMap{"t": translateHTML}).
+    ParseFS(emailTemplateFiles, "templates/*.html"))
+
+// emailSubjects are the catalog keys of the subjects of the email templates
+var emailSubjects = map[string]string{
+    EmailBookingConfirmed: "email.booking_confirmed.subject",
+    EmailBookingCancelled: "email.booking_cancelled.subject",
+    EmailWalkSummary:      "email.walk_summary.subject",
+}
+
+// translateHTML returns a catalog message as HTML. Catalog messages may contain markup, so
+// only the arguments are escaped.
+func translateHTML(locale, key string, args ...interface{}) template.HTML {
+    for i, arg := range args {
+        if s, ok := arg.(string); ok {
+            args[i] = html.EscapeString(s)
+        }
+    }
+    return template.HTML(i18n.T(locale, key, args...))
+}
+
+// EmailData fills in an email template
+type EmailData struct {
+    // Locale is the language the email is written in; i18n.DefaultLocale when empty
+    Locale string
+
+    // Time is when the walk is scheduled, already formatted for the owner
+    Time string
+
+    // Reason explains why a booking was cancelled
+    Reason string
+
+    // DurationMinutes and DistanceKm describe a finished walk; omitted when zero
+    DurationMinutes int
+    DistanceKm      float64
+
+    // Notes are the walker's notes on the walk
+    Notes string
+
+    // RouteMap is set when the email carries a route map attached as RouteMapContentID
+    RouteMap bool
+}
+
+// RenderEmail renders the named email template
+func RenderEmail(name string, data EmailData) (Email, error) {
+    subject, ok := emailSubjects[name]
+    if !ok || emailTemplates.Lookup(name) == nil {
+        return Email{}, fmt.Errorf("unknown email template %q", name)
+    }
+    if data.Locale == "" {
+        data.Locale = i18n.DefaultLocale
+    }
+    var body bytes.Buffer
+    if err := emailTemplates.ExecuteTemplate(&body, name, data); err != nil {
+        return Email{}, fmt.Errorf("failed to render email template %s: %w", name, err)
+    }
+    return Email{Subject: i18n.T(data.Locale, subject), Body: body.String()}, nil
+}
+
+// EmailOptions configures sending emails directly through SMTP or SES; they go through the
+// notification-service when no provider is set
+type EmailOptions struct {
+    // Provider is EmailProviderSMTP or EmailProviderSES
+    Provider string
+
+    // From is the address emails are sent from, e.g. "DogWalker <walks@example.com>"
+    From string
+
+    // SMTPHost and SMTPPort locate the relay, which must support STARTTLS when credentials are set
+    SMTPHost     string
+    SMTPPort     int
+    SMTPUsername string
+    SMTPPassword string
+
+    // SESRegion is the AWS region emails are sent from; the default AWS region when empty
+    SESRegion string
+}
+
+// Mailer delivers encoded MIME messages
+type Mailer interface {
+    Send(ctx context.Context, from, address string, message []byte) error
+}
+
+// InitEmail sends emails through the configured provider, leaving other notifications with
+// the current notifier
+func InitEmail(ctx context.Context, opts EmailOptions) error {
+    var mailer Mailer
+    switch opts.Provider {
+    case "":
+        return nil
+    case EmailProviderSMTP:
+        mailer = NewSMTPMailer(opts.SMTPHost, opts.SMTPPort, opts.SMTPUsername, opts.SMTPPassword)
+    case EmailProviderSES:
+        ses, err := NewSESMailer(ctx, opts.SESRegion)
+        if err != nil {
+            return err
+        }
+        mailer = ses
+    default:
+        return fmt.Errorf("unknown email provider %q", opts.Provider)
+    }
+    Default = NewEmailNotifier(mailer, opts.From, Default)
+    return nil
+}
+
+// EmailNotifier sends emails through a Mailer and hands every other notification to the
+// notifier it wraps
+type EmailNotifier struct {
+    mailer Mailer
+    from   string
+    next   Notifier
+}
+
+// NewEmailNotifier creates a notifier sending emails from the from address through mailer
+func NewEmailNotifier(mailer Mailer, from string, next Notifier) *EmailNotifier {
+    return &EmailNotifier{mailer: mailer, from: from, next: next}
+}
+
+// Notify hands the notification to the wrapped notifier
+func (n *EmailNotifier) Notify(ctx context.Context, userID string, notification Notification) error {
+    return n.next.Notify(ctx, userID, notification)
+}
+
+// Email sends an email to address
+func (n *EmailNotifier) Email(ctx context.Context, address string, email Email) error {
+    to, err := mail.ParseAddress(address)
+    if err != nil {
+        return fmt.Errorf("invalid email address: %w", err)
+    }
+    from, err := mail.ParseAddress(n.from)
+    if err != nil {
+        return fmt.Errorf("invalid sender address: %w", err)
+    }
+
+    message, err := buildMessage(from, to, email)
+    if err != nil {
+        return err
+    }
+    if err := n.mailer.Send(ctx, from.Address, to.Address, message); err != nil {
+        return fmt.Errorf("failed to send email: %w", err)
+    }
+    return nil
+}
+
+// buildMessage encodes email as a MIME message: the HTML body with a plain-text alternative,
+// inline images beside the body and other attachments after it
+func buildMessage(from, to *mail.Address, email Email) ([]byte, error) {
+    var related bytes.Buffer
+    relatedWriter := multipart.NewWriter(&related)
+    var alternative bytes.Buffer
+    alternativeWriter := multipart.NewWriter(&alternative)
+    if err := writeTextPart(alternativeWriter, "text/plain", plainText(email.Body)); err != nil {
+        return nil, err
+    }
+    if err := writeTextPart(alternativeWriter, "text/html", email.Body); err != nil {
+        return nil, err
+    }
+    if err := alternativeWriter.Close(); err != nil {
+        return nil, fmt.Errorf("failed to encode email: %w", err)
+    }
+    if err := writeNestedPart(relatedWriter, "alternative", alternativeWriter.Boundary(), alternative.Bytes()); err != nil {
+        return nil, err
+    }
+
+    var attachments []Attachment
+    for _, attachment := range email.Attachments {
+        if attachment.ContentID == "" {
+            attachments = append(attachments, attachment)
+            continue
+        }
+        if err := writeAttachmentPart(relatedWriter, attachment, "inline"); err != nil {
+            return nil, err
+        }
+    }
+    if err := relatedWriter.Close(); err != nil {
+        return nil, fmt.Errorf("failed to encode email: %w", err)
+    }
+
+    var body bytes.Buffer
+    mixedWriter := multipart.NewWriter(&body)
+    if err := writeNestedPart(mixedWriter, "related", relatedWriter.Boundary(), related.Bytes()); err != nil {
+        return nil, err
+    }
+    for _, attachment := range attachments {
+        if err := writeAttachmentPart(mixedWriter, attachment, "attachment"); err != nil {
+            return nil, err
+        }
+    }
+    if err := mixedWriter.Close(); err != nil {
+        return nil, fmt.Errorf("failed to encode email: %w", err)
+    }
+
+    var message bytes.Buffer
+    fmt.Fprintf(&message, "From: %s\r\n", from.String())
+    fmt.Fprintf(&message, "To: %s\r\n", to.String())
+    fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+    fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+    message.WriteString("MIME-Version: 1.0\r\n")
+    fmt.Fprintf(&message, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixedWriter.Boundary())
+    message.Write(body.Bytes())
+    return message.Bytes(), nil
+}
+
+// writeNestedPart writes a multipart body encoded with boundary as a part of w
+func writeNestedPart(w *multipart.Writer, subtype, boundary string, content []byte) error {
+    part, err := w.CreatePart(textproto.MIMEHeader{
+        "Content-Type": {fmt.Sprintf("multipart/%s; boundary=%s", subtype, boundary)},
+    })
+    if err != nil {
+        return fmt.Errorf("failed to encode email: %w", err)
+    }
+    _, err = part.Write(content)
+    return err
+}
+
+// writeTextPart writes UTF-8 text as a quoted-printable part of w
+func writeTextPart(w *multipart.Writer, contentType, text string) error {
+    part, err := w.CreatePart(textproto.MIMEHeader{
+        "Content-Type":              {contentType + "; charset=utf-8"},
+        "Content-Transfer-Encoding": {"quoted-printable"},
+    })
+    if err != nil {
+        return fmt.Errorf("failed to encode email: %w", err)
+    }
+    encoder := quotedprintable.NewWriter(part)
+    if _, err := encoder.Write([]byte(text)); err != nil {
+        return fmt.Errorf("failed to encode email: %w", err)
+    }
+    return encoder.Close()
+}
+
+// writeAttachmentPart writes an attachment as a base64 part of w with the given disposition
+func writeAttachmentPart(w *multipart.Writer, attachment Attachment, disposition string) error {
+    header := textproto.MIMEHeader{
+        "Content-Type":              {attachment.ContentType},
+        "Content-Transfer-Encoding": {"base64"},
+        "Content-Disposition":       {mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename})},
+    }
+    if attachment.ContentID != "" {
+        header.Set("Content-ID", "<"+attachment.ContentID+">")
+    }
+    part, err := w.CreatePart(header)
+    if err != nil {
+        return fmt.Errorf("failed to encode email: %w", err)
+    }
+    return writeBase64Lines(part, attachment.Content)
+}
+
+// writeBase64Lines writes content base64 encoded in lines of 76 characters, as MIME requires
+func writeBase64Lines(w io.Writer, content []byte) error {
+    encoded := base64.StdEncoding.EncodeToString(content)
+    for len(encoded) > 76 {
+        if _, err := io.WriteString(w, encoded[:76]+"\r\n"); err != nil {
+            return err
+        }
+        encoded = encoded[76:]
+    }
+    _, err := io.WriteString(w, encoded)
+    return err
+}
+
+// plainTextTags matches the HTML tags removed from bodies to make their plain-text alternative
+var plainTextTags = regexp.MustCompile(`<[^>]*>`)
+
+// plainText converts an HTML email body to plain text
+func plainText(body string) string {
+    text := plainTextTags.ReplaceAllString(strings.ReplaceAll(body, "<br>", "\n"), "")
+    var lines []string
+    for _, line := range strings.Split(html.UnescapeString(text), "\n") {
+        if line = strings.TrimSpace(line); line != "" {
+            lines = append(lines, line)
+        }
+    }
+    return strings.Join(lines, "\r\n")
+}
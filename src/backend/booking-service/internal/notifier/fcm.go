@@ -0,0 +1,226 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "bytes"
+    "context"
+    "crypto"
+    "crypto/rand"
+    "crypto/rsa"
+    "crypto/sha256"
+    "crypto/x509"
+    "encoding/json"
+    "encoding/pem"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "os"
+    "strings"
+    "sync"
+    "time"
+)
+
+const (
+    // fcmScope is the OAuth scope needed to send messages
+    fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+    // fcmSendURL is the FCM HTTP v1 send endpoint of a Firebase project
+    fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+
+    // fcmTokenLifetime is how long the access tokens requested for the service account last
+    fcmTokenLifetime = time.Hour
+)
+
+// FCMSender sends notifications through the Firebase Cloud Messaging HTTP v1 API,
+// authenticating as a service account
+type FCMSender struct {
+    projectID   string
+    clientEmail string
+    tokenURL    string
+    key         *rsa.PrivateKey
+    client      *http.Client
+
+    mu          sync.Mutex
+    accessToken string
+    expiresAt   time.Time
+}
+
+// NewFCMSender creates a sender from a Firebase service account key file
+func NewFCMSender(credentialsFile string) (*FCMSender, error) {
+    data, err := os.ReadFile(credentialsFile)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+    }
+    var credentials struct {
+        ProjectID   string `json:"project_id"`
+        ClientEmail string `json:"client_email"`
+        PrivateKey  string `json:"private_key"`
+        TokenURI    string `json:"token_uri"`
+    }
+    if err := json.Unmarshal(data, &credentials); err != nil {
+        return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+    }
+    if credentials.ProjectID == "" || credentials.ClientEmail == "" || credentials.TokenURI == "" {
+        return nil, fmt.Errorf("FCM credentials must be a service account key")
+    }
+
+    block, _ := pem.Decode([]byte(credentials.PrivateKey))
+    if block == nil {
+        return nil, fmt.Errorf("FCM credentials hold no private key")
+    }
+    parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+    if err != nil {
+        return nil, fmt.Errorf("failed to parse FCM private key: %w", err)
+    }
+    key, ok := parsed.(*rsa.PrivateKey)
+    if !ok {
+        return nil, fmt.Errorf("FCM private key is not an RSA key")
+    }
+
+    return &FCMSender{
+        projectID:   credentials.ProjectID,
+        clientEmail: credentials.ClientEmail,
+        tokenURL:    credentials.TokenURI,
+        key:         key,
+        client:      &http.Client{Timeout: 10 * time.Second},
+    }, nil
+}
+
+// fcmMessage is the message payload of the send endpoint
+type fcmMessage struct {
+    Token        string            `json:"token"`
+    Notification fcmNotification   `json:"notification"`
+    Data         map[string]string `json:"data,omitempty"`
+    Android      fcmAndroidConfig  `json:"android"`
+}
+
+type fcmNotification struct {
+    Title string `json:"title"`
+    Body  string `json:"body"`
+}
+
+type fcmAndroidConfig struct {
+    Priority string `json:"priority"`
+}
+
+// Send implements PushSender
+func (s *FCMSender) Send(ctx context.Context, token string, notification Notification) (string, error) {
+    accessToken, err := s.token(ctx)
+    if err != nil {
+        return "", err
+    }
+
+    priority := "NORMAL"
+    if notification.Priority == "high" {
+        priority = "HIGH"
+    }
+    payload, err := json.Marshal(map[string]fcmMessage{
+        "message": {
+            Token:        token,
+            Notification: fcmNotification{Title: notification.Subject, Body: notification.Body},
+            Data:         notification.Data,
+            Android:      fcmAndroidConfig{Priority: priority},
+        },
+    })
+    if err != nil {
+        return "", fmt.Errorf("failed to encode FCM message: %w", err)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(fcmSendURL, url.PathEscape(s.projectID)), bytes.NewReader(payload))
+    if err != nil {
+        return "", fmt.Errorf("failed to create FCM request: %w", err)
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer "+accessToken)
+
+    resp, err := s.client.Do(req)
+    if err != nil {
+        return "", fmt.Errorf("failed to send FCM message: %w", err)
+    }
+    defer resp.Body.Close()
+
+    body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+    if err != nil {
+        return "", fmt.Errorf("failed to read FCM response: %w", err)
+    }
+    switch {
+    case resp.StatusCode == http.StatusNotFound:
+        // FCM answers UNREGISTERED tokens with 404
+        return "", ErrUnregistered
+    case resp.StatusCode != http.StatusOK:
+        return "", fmt.Errorf("FCM returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+    }
+
+    var sent struct {
+        Name string `json:"name"`
+    }
+    if err := json.Unmarshal(body, &sent); err != nil {
+        return "", fmt.Errorf("failed to decode FCM response: %w", err)
+    }
+    return sent.Name, nil
+}
+
+// token returns an access token for the service account, requesting a new one shortly
+// before the current one expires
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    now := time.Now()
+    if s.accessToken != "" && now.Before(s.expiresAt.Add(-time.Minute)) {
+        return s.accessToken, nil
+    }
+
+    assertion, err := signJWT(
+        map[string]string{"alg": "RS256", "typ": "JWT"},
+        map[string]interface{}{
+            "iss":   s.clientEmail,
+            "scope": fcmScope,
+            "aud":   s.tokenURL,
+            "iat":   now.Unix(),
+            "exp":   now.Add(fcmTokenLifetime).Unix(),
+        },
+        func(signingInput []byte) ([]byte, error) {
+            digest := sha256.Sum256(signingInput)
+            return rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+        },
+    )
+    if err != nil {
+        return "", err
+    }
+
+    form := url.Values{
+        "grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+        "assertion":  {assertion},
+    }
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+    if err != nil {
+        return "", fmt.Errorf("failed to create FCM token request: %w", err)
+    }
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+    resp, err := s.client.Do(req)
+    if err != nil {
+        return "", fmt.Errorf("failed to obtain FCM access token: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return "", fmt.Errorf("FCM token endpoint returned status %d", resp.StatusCode)
+    }
+    var body struct {
+        AccessToken string `json:"access_token"`
+        ExpiresIn   int64  `json:"expires_in"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+        return "", fmt.Errorf("failed to decode FCM access token: %w", err)
+    }
+    if body.AccessToken == "" {
+        return "", fmt.Errorf("FCM token endpoint returned no access token")
+    }
+
+    s.accessToken = body.AccessToken
+    s.expiresAt = now.Add(time.Duration(body.ExpiresIn) * time.Second)
+    return s.accessToken, nil
+}
@@ -0,0 +1,42 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "context"
+    "log"
+)
+
+// InboxStore keeps the notifications shown in users' in-app notification centers
+type InboxStore interface {
+    Save(ctx context.Context, userID string, notification Notification) error
+}
+
+// InitInbox keeps every notification sent through Default in store, so the apps' notification
+// center shows what was pushed, and what was muted or could not be pushed
+func InitInbox(store InboxStore) {
+    Default = NewInboxNotifier(store, Default)
+}
+
+// InboxNotifier keeps notifications in an InboxStore before handing them to the notifier it wraps
+type InboxNotifier struct {
+    store InboxStore
+    next  Notifier
+}
+
+// NewInboxNotifier creates a notifier keeping notifications in store
+func NewInboxNotifier(store InboxStore, next Notifier) *InboxNotifier {
+    return &InboxNotifier{store: store, next: next}
+}
+
+// Notify keeps the notification and hands it on; it is still delivered when it cannot be kept
+func (n *InboxNotifier) Notify(ctx context.Context, userID string, notification Notification) error {
+    if err := n.store.Save(ctx, userID, notification); err != nil {
+        log.Printf("Failed to keep notification for user %s: %v", userID, err)
+    }
+    return n.next.Notify(ctx, userID, notification)
+}
+
+// Email hands the email to the wrapped notifier; emails are not kept
+func (n *InboxNotifier) Email(ctx context.Context, address string, email Email) error {
+    return n.next.Email(ctx, address, email)
+}
@@ -0,0 +1,184 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "bytes"
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "time"
+)
+
+// Human Tasks:
+// 1. Set BOOKING_NOTIFICATION_URL to the notification-service base URL in each environment
+// 2. Configure network policies allowing booking-service to reach notification-service
+
+// Notification categories users can mute in their notification preferences
+const (
+    // CategoryBookings covers walk requests, cancellations and disputes
+    CategoryBookings = "bookings"
+
+    // CategoryPayments covers receipts and tips
+    CategoryPayments = "payments"
+)
+
+// Categories lists every notification category
+var Categories = []string{CategoryBookings, CategoryPayments}
+
+// Notification is a push notification addressed to a single user
+type Notification struct {
+    Subject  string
+    Body     string
+    Data     map[string]string
+    Priority string
+
+    // Category lets users mute the notification; notifications without one are only muted
+    // along with every other push notification
+    Category string
+}
+
+// Email is an email message, optionally with attached documents
+type Email struct {
+    Subject     string
+    Body        string
+    Attachments []Attachment
+}
+
+// Attachment is a document attached to an email
+type Attachment struct {
+    Filename    string
+    ContentType string
+    Content     []byte
+
+    // ContentID makes the attachment an inline image, which the body refers to as cid:ContentID
+    ContentID string
+}
+
+// Notifier delivers notifications to users
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+type Notifier interface {
+    Notify(ctx context.Context, userID string, notification Notification) error
+
+    // Email sends an email to address
+    Email(ctx context.Context, address string, email Email) error
+}
+
+// Default is the process-wide notifier, set by Init
+var Default Notifier = LogNotifier{}
+
+// Init selects the notifier: notifications go to the notification-service at baseURL
+// when set, otherwise they are logged
+func Init(baseURL string) {
+    if baseURL == "" {
+        Default = LogNotifier{}
+        return
+    }
+    Default = NewHTTPNotifier(baseURL)
+}
+
+// HTTPNotifier posts notifications to the notification-service send endpoint
+type HTTPNotifier struct {
+    baseURL string
+    client  *http.Client
+}
+
+// NewHTTPNotifier creates a notifier for the notification-service at baseURL
+func NewHTTPNotifier(baseURL string) *HTTPNotifier {
+    return &HTTPNotifier{
+        baseURL: baseURL,
+        client:  &http.Client{Timeout: 5 * time.Second},
+    }
+}
+
+// sendRequest mirrors the notification-service NotificationRequest payload
+type sendRequest struct {
+    Type        string            `json:"type"`
+    Recipient   string            `json:"recipient"`
+    Subject     string            `json:"subject,omitempty"`
+    Body        string            `json:"body"`
+    Data        map[string]string `json:"data,omitempty"`
+    Priority    string            `json:"priority,omitempty"`
+    Attachments []sendAttachment  `json:"attachments,omitempty"`
+}
+
+// sendAttachment mirrors the notification-service attachment payload; content is base64 encoded
+type sendAttachment struct {
+    Filename    string `json:"filename"`
+    ContentType string `json:"contentType"`
+    Content     string `json:"content"`
+    ContentID   string `json:"contentId,omitempty"`
+}
+
+// Notify sends a push notification to userID
+func (n *HTTPNotifier) Notify(ctx context.Context, userID string, notification Notification) error {
+    return n.send(ctx, sendRequest{
+        Type:      "push",
+        Recipient: userID,
+        Subject:   notification.Subject,
+        Body:      notification.Body,
+        Data:      notification.Data,
+        Priority:  notification.Priority,
+    })
+}
+
+// Email sends an email to address
+func (n *HTTPNotifier) Email(ctx context.Context, address string, email Email) error {
+    req := sendRequest{
+        Type:      "email",
+        Recipient: address,
+        Subject:   email.Subject,
+        Body:      email.Body,
+    }
+    for _, attachment := range email.Attachments {
+        req.Attachments = append(req.Attachments, sendAttachment{
+            Filename:    attachment.Filename,
+            ContentType: attachment.ContentType,
+            Content:     base64.StdEncoding.EncodeToString(attachment.Content),
+            ContentID:   attachment.ContentID,
+        })
+    }
+    return n.send(ctx, req)
+}
+
+// send posts a request to the notification-service send endpoint
+func (n *HTTPNotifier) send(ctx context.Context, request sendRequest) error {
+    payload, err := json.Marshal(request)
+    if err != nil {
+        return fmt.Errorf("failed to encode notification: %w", err)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.baseURL+"/api/notifications/send", bytes.NewReader(payload))
+    if err != nil {
+        return fmt.Errorf("failed to create notification request: %w", err)
+    }
+    req.Header.Set("Content-Type", "application/json")
+
+    resp, err := n.client.Do(req)
+    if err != nil {
+        return fmt.Errorf("failed to send notification: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return fmt.Errorf("notification-service returned status %d", resp.StatusCode)
+    }
+    return nil
+}
+
+// LogNotifier logs notifications instead of sending them
+type LogNotifier struct{}
+
+// Notify logs the notification
+func (LogNotifier) Notify(ctx context.Context, userID string, notification Notification) error {
+    log.Printf("Notification for user %s: %s - %s", userID, notification.Subject, notification.Body)
+    return nil
+}
+
+// Email logs the email subject; bodies and attachments may hold personal data
+func (LogNotifier) Email(ctx context.Context, address string, email Email) error {
+    log.Printf("Email: %s (%d attachments)", email.Subject, len(email.Attachments))
+    return nil
+}
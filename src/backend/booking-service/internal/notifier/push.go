@@ -0,0 +1,253 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log"
+)
+
+// Human Tasks:
+// 1. Create a Firebase service account allowed to send messages and set BOOKING_FCM_CREDENTIALS_FILE to its key file
+// 2. Create an APNs auth key and set BOOKING_APNS_KEY_FILE, BOOKING_APNS_KEY_ID, BOOKING_APNS_TEAM_ID and BOOKING_APNS_TOPIC
+
+// Device platforms push notifications are delivered to
+const (
+    // PlatformAndroid devices receive notifications through Firebase Cloud Messaging
+    PlatformAndroid = "android"
+
+    // PlatformIOS devices receive notifications through the Apple Push Notification service
+    PlatformIOS = "ios"
+)
+
+// Delivery statuses recorded for each push notification
+const (
+    // DeliverySent means the platform accepted the notification for the device
+    DeliverySent = "sent"
+
+    // DeliveryFailed means the platform could not be reached or refused the notification
+    DeliveryFailed = "failed"
+
+    // DeliveryUnregistered means the device token is no longer valid and was forgotten
+    DeliveryUnregistered = "unregistered"
+
+    // DeliveryMuted means the user muted the notification's category or push notifications
+    DeliveryMuted = "muted"
+
+    // DeliveryNoDevice means the user has no registered devices
+    DeliveryNoDevice = "no_device"
+)
+
+// ErrUnregistered is returned by a PushSender when the device token is no longer valid,
+// usually because the app was uninstalled
+var ErrUnregistered = errors.New("device token is not registered")
+
+// PushOptions configures delivering push notifications directly through FCM and APNs; a
+// platform without credentials is unavailable
+type PushOptions struct {
+    // FCMCredentialsFile is the JSON key of a Firebase service account
+    FCMCredentialsFile string
+
+    // APNsKeyFile is the .p8 auth key issued by Apple, with its key ID and team ID
+    APNsKeyFile string
+    APNsKeyID   string
+    APNsTeamID  string
+
+    // APNsTopic is the bundle ID of the iOS app
+    APNsTopic string
+
+    // APNsSandbox sends to the development environment, for debug builds of the app
+    APNsSandbox bool
+}
+
+// Device is a device registered to receive a user's push notifications
+type Device struct {
+    Token    string
+    Platform string
+}
+
+// Delivery is the outcome of sending a notification to one of a user's devices, kept so
+// missing notifications can be investigated
+type Delivery struct {
+    UserID    string
+    Platform  string
+    Token     string
+    Category  string
+    Subject   string
+    Status    string
+    MessageID string
+    Error     string
+}
+
+// PushStore holds users' devices and notification preferences and records deliveries
+type PushStore interface {
+    // Devices returns the devices registered to userID
+    Devices(ctx context.Context, userID string) ([]Device, error)
+
+    // Allows reports whether userID accepts push notifications in category
+    Allows(ctx context.Context, userID, category string) (bool, error)
+
+    // Unregister forgets a device token the platform no longer accepts
+    Unregister(ctx context.Context, token string) error
+
+    // Record stores the outcome of a delivery
+    Record(ctx context.Context, delivery Delivery) error
+}
+
+// PushSender delivers notifications to device tokens of one platform
+type PushSender interface {
+    // Send delivers notification to token, returning the platform's message ID.
+    // ErrUnregistered is returned when the token is no longer valid.
+    Send(ctx context.Context, token string, notification Notification) (string, error)
+}
+
+// InitPush delivers push notifications directly to users' devices when FCM or APNs
+// credentials are configured. Emails are still sent by the notifier selected by Init, so
+// InitPush must be called after it.
+func InitPush(opts PushOptions, store PushStore) error {
+    senders := make(map[string]PushSender)
+    if opts.FCMCredentialsFile != "" {
+        fcm, err := NewFCMSender(opts.FCMCredentialsFile)
+        if err != nil {
+            return err
+        }
+        senders[PlatformAndroid] = fcm
+    }
+    if opts.APNsKeyFile != "" {
+        apns, err := NewAPNsSender(opts.APNsKeyFile, opts.APNsKeyID, opts.APNsTeamID, opts.APNsTopic, opts.APNsSandbox)
+        if err != nil {
+            return err
+        }
+        senders[PlatformIOS] = apns
+    }
+    if len(senders) == 0 {
+        return nil
+    }
+    Default = NewPushNotifier(senders, store, Default)
+    return nil
+}
+
+// PushNotifier sends notifications to every device a user has registered, honouring their
+// preferences, and records the outcome for each device
+type PushNotifier struct {
+    senders map[string]PushSender
+    store   PushStore
+    email   Notifier
+}
+
+// NewPushNotifier creates a notifier sending to devices through senders, keyed by platform.
+// Emails are sent through email.
+func NewPushNotifier(senders map[string]PushSender, store PushStore, email Notifier) *PushNotifier {
+    return &PushNotifier{
+        senders: senders,
+        store:   store,
+        email:   email,
+    }
+}
+
+// Notify sends the notification to each of the user's devices. It fails only when no
+// device could be reached.
+func (n *PushNotifier) Notify(ctx context.Context, userID string, notification Notification) error {
+    allowed, err := n.store.Allows(ctx, userID, notification.Category)
+    if err != nil {
+        return fmt.Errorf("failed to read notification preferences: %w", err)
+    }
+    delivery := Delivery{
+        UserID:   userID,
+        Category: notification.Category,
+        Subject:  notification.Subject,
+    }
+    if !allowed {
+        delivery.Status = DeliveryMuted
+        n.record(ctx, delivery)
+        return nil
+    }
+
+    devices, err := n.store.Devices(ctx, userID)
+    if err != nil {
+        return fmt.Errorf("failed to list devices: %w", err)
+    }
+    if len(devices) == 0 {
+        delivery.Status = DeliveryNoDevice
+        n.record(ctx, delivery)
+        return nil
+    }
+
+    var sent bool
+    var lastErr error
+    for _, device := range devices {
+        delivery.Platform = device.Platform
+        delivery.Token = device.Token
+        delivery.MessageID, delivery.Error = "", ""
+
+        err := n.send(ctx, device, notification, &delivery)
+        switch {
+        case errors.Is(err, ErrUnregistered):
+            delivery.Status = DeliveryUnregistered
+            if err := n.store.Unregister(ctx, device.Token); err != nil {
+                log.Printf("Failed to unregister device of user %s: %v", userID, err)
+            }
+        case err != nil:
+            delivery.Status = DeliveryFailed
+            delivery.Error = err.Error()
+            lastErr = err
+        default:
+            delivery.Status = DeliverySent
+            sent = true
+        }
+        n.record(ctx, delivery)
+    }
+
+    if !sent && lastErr != nil {
+        return fmt.Errorf("failed to send push notification: %w", lastErr)
+    }
+    return nil
+}
+
+// send delivers a notification to one device, setting the message ID on delivery
+func (n *PushNotifier) send(ctx context.Context, device Device, notification Notification, delivery *Delivery) error {
+    sender, ok := n.senders[device.Platform]
+    if !ok {
+        return fmt.Errorf("push notifications to %s devices are not configured", device.Platform)
+    }
+    messageID, err := sender.Send(ctx, device.Token, notification)
+    if err != nil {
+        return err
+    }
+    delivery.MessageID = messageID
+    return nil
+}
+
+// record stores a delivery; failing to do so does not fail the notification
+func (n *PushNotifier) record(ctx context.Context, delivery Delivery) {
+    if err := n.store.Record(ctx, delivery); err != nil {
+        log.Printf("Failed to record push delivery to user %s: %v", delivery.UserID, err)
+    }
+}
+
+// Email sends the email through the notifier emails were sent with before push was configured
+func (n *PushNotifier) Email(ctx context.Context, address string, email Email) error {
+    return n.email.Email(ctx, address, email)
+}
+
+// signJWT encodes header and claims as a JSON Web Token signed by sign
+func signJWT(header, claims interface{}, sign func(signingInput []byte) ([]byte, error)) (string, error) {
+    encodedHeader, err := json.Marshal(header)
+    if err != nil {
+        return "", fmt.Errorf("failed to encode token header: %w", err)
+    }
+    encodedClaims, err := json.Marshal(claims)
+    if err != nil {
+        return "", fmt.Errorf("failed to encode token claims: %w", err)
+    }
+
+    signingInput := base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(encodedClaims)
+    signature, err := sign([]byte(signingInput))
+    if err != nil {
+        return "", fmt.Errorf("failed to sign token: %w", err)
+    }
+    return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,113 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "bytes"
+    "fmt"
+    "image"
+    "image/color"
+    "image/png"
+    "math"
+)
+
+// Route map dimensions and styling, sized to fit the width of an email
+const (
+    routeMapWidth   = 600
+    routeMapHeight  = 360
+    routeMapPadding = 30
+    routeLineRadius = 2
+    routeDotRadius  = 7
+)
+
+var (
+    routeMapBackground = color.RGBA{R: 0xf4, G: 0xf1, B: 0xea, A: 0xff}
+    routeMapGrid       = color.RGBA{R: 0xe2, G: 0xdd, B: 0xd2, A: 0xff}
+    routeLineColor     = color.RGBA{R: 0x2b, G: 0x6c, B: 0xb0, A: 0xff}
+    routeStartColor    = color.RGBA{R: 0x2f, G: 0x85, B: 0x5a, A: 0xff}
+    routeEndColor      = color.RGBA{R: 0xc5, G: 0x30, B: 0x30, A: 0xff}
+)
+
+// RoutePoint is a position along a walk
+type RoutePoint struct {
+    Latitude  float64
+    Longitude float64
+}
+
+// RenderRouteMap draws a walk's route as a PNG image, from a green start marker to a red end
+// marker, scaled to fill the image. The image has no base map so no map provider sees the route.
+func RenderRouteMap(route []RoutePoint) ([]byte, error) {
+    if len(route) == 0 {
+        return nil, fmt.Errorf("route has no points")
+    }
+
+    // Project onto Web Mercator so the route keeps the shape owners know from map apps
+    xs := make([]float64, len(route))
+    ys := make([]float64, len(route))
+    minX, minY := math.Inf(1), math.Inf(1)
+    maxX, maxY := math.Inf(-1), math.Inf(-1)
+    for i, point := range route {
+        xs[i] = point.Longitude * math.Pi / 180
+        ys[i] = -math.Log(math.Tan(math.Pi/4 + point.Latitude*math.Pi/360))
+        minX, maxX = math.Min(minX, xs[i]), math.Max(maxX, xs[i])
+        minY, maxY = math.Min(minY, ys[i]), math.Max(maxY, ys[i])
+    }
+
+    // Scale both axes alike, centring the route; a route that never moved is drawn mid-image
+    innerWidth := float64(routeMapWidth - 2*routeMapPadding)
+    innerHeight := float64(routeMapHeight - 2*routeMapPadding)
+    scale := 0.0
+    if spanX, spanY := maxX-minX, maxY-minY; spanX > 0 || spanY > 0 {
+        scale = math.Min(innerWidth/math.Max(spanX, 1e-12), innerHeight/math.Max(spanY, 1e-12))
+    }
+    offsetX := routeMapPadding + (innerWidth-(maxX-minX)*scale)/2
+    offsetY := routeMapPadding + (innerHeight-(maxY-minY)*scale)/2
+    pixel := func(i int) (float64, float64) {
+        return offsetX + (xs[i]-minX)*scale, offsetY + (ys[i]-minY)*scale
+    }
+
+    img := image.NewRGBA(image.Rect(0, 0, routeMapWidth, routeMapHeight))
+    for y := 0; y < routeMapHeight; y++ {
+        for x := 0; x < routeMapWidth; x++ {
+            if x%40 == 0 || y%40 == 0 {
+                img.SetRGBA(x, y, routeMapGrid)
+            } else {
+                img.SetRGBA(x, y, routeMapBackground)
+            }
+        }
+    }
+
+    for i := 1; i < len(route); i++ {
+        x0, y0 := pixel(i - 1)
+        x1, y1 := pixel(i)
+        steps := int(math.Ceil(math.Hypot(x1-x0, y1-y0)))
+        for step := 0; step <= steps; step++ {
+            t := 0.0
+            if steps > 0 {
+                t = float64(step) / float64(steps)
+            }
+            fillDisc(img, x0+(x1-x0)*t, y0+(y1-y0)*t, routeLineRadius, routeLineColor)
+        }
+    }
+
+    startX, startY := pixel(0)
+    endX, endY := pixel(len(route) - 1)
+    fillDisc(img, endX, endY, routeDotRadius, routeEndColor)
+    fillDisc(img, startX, startY, routeDotRadius, routeStartColor)
+
+    var buf bytes.Buffer
+    if err := png.Encode(&buf, img); err != nil {
+        return nil, fmt.Errorf("failed to encode route map: %w", err)
+    }
+    return buf.Bytes(), nil
+}
+
+// fillDisc paints a disc of radius r centred on (cx, cy)
+func fillDisc(img *image.RGBA, cx, cy float64, r int, c color.RGBA) {
+    for dy := -r; dy <= r; dy++ {
+        for dx := -r; dx <= r; dx++ {
+            if dx*dx+dy*dy <= r*r {
+                img.SetRGBA(int(math.Round(cx))+dx, int(math.Round(cy))+dy, c)
+            }
+        }
+    }
+}
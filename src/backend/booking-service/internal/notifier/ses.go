@@ -0,0 +1,44 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/aws/aws-sdk-go-v2/aws"                 // v1.21.0
+    awsconfig "github.com/aws/aws-sdk-go-v2/config"    // v1.18.42
+    "github.com/aws/aws-sdk-go-v2/service/sesv2"       // v1.20.1
+    "github.com/aws/aws-sdk-go-v2/service/sesv2/types" // v1.20.1
+)
+
+// SESMailer sends emails through Amazon SES
+type SESMailer struct {
+    client *sesv2.Client
+}
+
+// NewSESMailer creates an SESMailer for region using the default AWS credential chain
+func NewSESMailer(ctx context.Context, region string) (*SESMailer, error) {
+    cfg, err := awsconfig.LoadDefaultConfig(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("failed to load AWS config: %w", err)
+    }
+    client := sesv2.NewFromConfig(cfg, func(o *sesv2.Options) {
+        if region != "" {
+            o.Region = region
+        }
+    })
+    return &SESMailer{client: client}, nil
+}
+
+// Send implements Mailer
+func (m *SESMailer) Send(ctx context.Context, from, address string, message []byte) error {
+    _, err := m.client.SendEmail(ctx, &sesv2.SendEmailInput{
+        FromEmailAddress: aws.String(from),
+        Destination:      &types.Destination{ToAddresses: []string{address}},
+        Content:          &types.EmailContent{Raw: &types.RawMessage{Data: message}},
+    })
+    if err != nil {
+        return fmt.Errorf("SES rejected email: %w", err)
+    }
+    return nil
+}
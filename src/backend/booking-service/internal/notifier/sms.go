@@ -0,0 +1,160 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "sort"
+    "strings"
+    "text/template"
+
+    "src/backend/booking-service/internal/i18n"
+)
+
+// Human Tasks:
+// 1. Buy a sender number, or register an alphanumeric sender ID, for each country owners are texted in
+// 2. Point the messaging service's inbound webhook at /api/v1/notifications/sms/inbound and set BOOKING_SMS_WEBHOOK_URL to that URL
+
+// DefaultSender is the SMSOptions.Senders key used for countries without a sender of their own
+const DefaultSender = "default"
+
+// SMS templates
+const (
+    // SMSBookingConfirmed tells the owner a walker has accepted their booking
+    SMSBookingConfirmed = "booking_confirmed"
+
+    // SMSWalkerEnRoute tells the owner their walker is on the way
+    SMSWalkerEnRoute = "walker_en_route"
+
+    // SMSWalkComplete tells the owner the walk has finished
+    SMSWalkComplete = "walk_complete"
+)
+
+// smsTemplates renders the texts sent to owners from the i18n catalogs. Every text says how to
+// opt out, as carriers require.
+var smsTemplates = template.Must(template.New("sms").Funcs(template.FuncMap{"t": i18n.T}).Parse(`
+{{- define "booking_confirmed" -}}
+{{t .Locale "sms.booking_confirmed" .Time}}
+{{- end -}}
+{{- define "walker_en_route" -}}
+{{if .ETAMinutes}}{{t .Locale "sms.walker_en_route_eta" .ETAMinutes}}{{else}}{{t .Locale "sms.walker_en_route"}}{{end}}
+{{- end -}}
+{{- define "walk_complete" -}}
+{{t .Locale "sms.walk_complete"}}
+{{- end -}}
+`))
+
+// SMSData fills in an SMS template
+type SMSData struct {
+    // Locale is the language the text is written in; i18n.DefaultLocale when empty
+    Locale string
+
+    // Time is when the walk is scheduled, already formatted for the owner
+    Time string
+
+    // ETAMinutes is how far away the walker is; omitted when zero
+    ETAMinutes int
+}
+
+// RenderSMS renders the named SMS template
+func RenderSMS(name string, data SMSData) (string, error) {
+    if smsTemplates.Lookup(name) == nil {
+        return "", fmt.Errorf("unknown SMS template %q", name)
+    }
+    var body bytes.Buffer
+    if err := smsTemplates.ExecuteTemplate(&body, name, data); err != nil {
+        return "", fmt.Errorf("failed to render SMS template %s: %w", name, err)
+    }
+    return body.String(), nil
+}
+
+// ErrOptedOut is returned by a Texter when the recipient has opted out of texts with the carrier
+var ErrOptedOut = errors.New("recipient has opted out of text messages")
+
+// SMSOptions configures sending texts through a Twilio-compatible messaging API; texts are only
+// logged when no account is set
+type SMSOptions struct {
+    AccountSID string
+    AuthToken  string
+
+    // APIURL is the base URL of the messaging API
+    APIURL string
+
+    // Senders are the numbers or alphanumeric IDs texts are sent from, keyed by country
+    // calling code without the "+", or DefaultSender
+    Senders map[string]string
+
+    // WebhookURL is the public URL of the inbound message webhook, which replies are signed for
+    WebhookURL string
+}
+
+// Texter sends text messages to phone numbers in E.164 format
+type Texter interface {
+    Text(ctx context.Context, phone, body string) error
+}
+
+// Texts is the process-wide texter, set by InitSMS
+var Texts Texter = LogTexter{}
+
+// InitSMS selects the texter: texts are sent through the messaging API when an account is
+// configured, otherwise they are logged
+func InitSMS(opts SMSOptions) {
+    if opts.AccountSID == "" {
+        Texts = LogTexter{}
+        return
+    }
+    Texts = NewTwilioTexter(opts.APIURL, opts.AccountSID, opts.AuthToken, opts.Senders)
+}
+
+// ParseSMSSenders parses senders configured as "44=+447700900123,1=+15550100,default=DogWalk"
+func ParseSMSSenders(s string) (map[string]string, error) {
+    senders := make(map[string]string)
+    for _, entry := range strings.Split(s, ",") {
+        entry = strings.TrimSpace(entry)
+        if entry == "" {
+            continue
+        }
+        code, sender, ok := strings.Cut(entry, "=")
+        code, sender = strings.TrimPrefix(strings.TrimSpace(code), "+"), strings.TrimSpace(sender)
+        if !ok || code == "" || sender == "" {
+            return nil, fmt.Errorf("invalid SMS sender %q: want CALLING_CODE=SENDER", entry)
+        }
+        if code != DefaultSender && strings.Trim(code, "0123456789") != "" {
+            return nil, fmt.Errorf("invalid SMS sender %q: %q is not a country calling code", entry, code)
+        }
+        senders[code] = sender
+    }
+    return senders, nil
+}
+
+// senderFor returns the sender for phone: the one configured for the longest calling code the
+// number starts with, or the default sender
+func senderFor(senders map[string]string, phone string) (string, bool) {
+    digits := strings.TrimPrefix(phone, "+")
+    codes := make([]string, 0, len(senders))
+    for code := range senders {
+        if code != DefaultSender {
+            codes = append(codes, code)
+        }
+    }
+    sort.Slice(codes, func(i, j int) bool { return len(codes[i]) > len(codes[j]) })
+    for _, code := range codes {
+        if strings.HasPrefix(digits, code) {
+            return senders[code], true
+        }
+    }
+    sender, ok := senders[DefaultSender]
+    return sender, ok
+}
+
+// LogTexter logs texts instead of sending them
+type LogTexter struct{}
+
+// Text logs the length of the text; phone numbers and message bodies are personal data
+func (LogTexter) Text(ctx context.Context, phone, body string) error {
+    log.Printf("Text message: %d characters", len(body))
+    return nil
+}
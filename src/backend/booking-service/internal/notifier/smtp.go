@@ -0,0 +1,82 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "context"
+    "crypto/tls"
+    "fmt"
+    "net"
+    "net/smtp"
+    "strconv"
+    "time"
+)
+
+// SMTPMailer sends emails through an SMTP relay, upgrading the connection with STARTTLS when
+// the relay offers it
+type SMTPMailer struct {
+    host string
+    addr string
+    auth smtp.Auth
+}
+
+// NewSMTPMailer creates a mailer for the relay at host:port; it does not authenticate when
+// username is empty
+func NewSMTPMailer(host string, port int, username, password string) *SMTPMailer {
+    mailer := &SMTPMailer{
+        host: host,
+        addr: net.JoinHostPort(host, strconv.Itoa(port)),
+    }
+    if username != "" {
+        mailer.auth = smtp.PlainAuth("", username, password, host)
+    }
+    return mailer
+}
+
+// Send implements Mailer
+func (m *SMTPMailer) Send(ctx context.Context, from, address string, message []byte) error {
+    dialer := net.Dialer{Timeout: 10 * time.Second}
+    conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+    if err != nil {
+        return fmt.Errorf("failed to connect to SMTP relay: %w", err)
+    }
+    deadline, ok := ctx.Deadline()
+    if !ok {
+        deadline = time.Now().Add(30 * time.Second)
+    }
+    conn.SetDeadline(deadline)
+
+    client, err := smtp.NewClient(conn, m.host)
+    if err != nil {
+        conn.Close()
+        return fmt.Errorf("failed to greet SMTP relay: %w", err)
+    }
+    defer client.Close()
+
+    if ok, _ := client.Extension("STARTTLS"); ok {
+        if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+            return fmt.Errorf("failed to start TLS with SMTP relay: %w", err)
+        }
+    }
+    if m.auth != nil {
+        if err := client.Auth(m.auth); err != nil {
+            return fmt.Errorf("failed to authenticate with SMTP relay: %w", err)
+        }
+    }
+    if err := client.Mail(from); err != nil {
+        return err
+    }
+    if err := client.Rcpt(address); err != nil {
+        return err
+    }
+    data, err := client.Data()
+    if err != nil {
+        return err
+    }
+    if _, err := data.Write(message); err != nil {
+        return err
+    }
+    if err := data.Close(); err != nil {
+        return err
+    }
+    return client.Quit()
+}
@@ -0,0 +1,126 @@
+// Package notifier sends user notifications through the notification-service
+package notifier
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/sha1"
+    "encoding/base64"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "sort"
+    "strings"
+    "time"
+)
+
+const (
+    // twilioAPIURL is the messaging API used when no other is configured
+    twilioAPIURL = "https://api.twilio.com"
+
+    // twilioOptedOutCode is the error code returned for recipients who replied STOP
+    twilioOptedOutCode = 21610
+)
+
+// TwilioTexter sends texts through the Twilio Messages API, or any API compatible with it,
+// choosing the sender by the recipient's country
+type TwilioTexter struct {
+    apiURL     string
+    accountSID string
+    authToken  string
+    senders    map[string]string
+    client     *http.Client
+}
+
+// NewTwilioTexter creates a texter for the account; apiURL defaults to Twilio's
+func NewTwilioTexter(apiURL, accountSID, authToken string, senders map[string]string) *TwilioTexter {
+    if apiURL == "" {
+        apiURL = twilioAPIURL
+    }
+    return &TwilioTexter{
+        apiURL:     strings.TrimSuffix(apiURL, "/"),
+        accountSID: accountSID,
+        authToken:  authToken,
+        senders:    senders,
+        client:     &http.Client{Timeout: 10 * time.Second},
+    }
+}
+
+// Text implements Texter
+func (t *TwilioTexter) Text(ctx context.Context, phone, body string) error {
+    sender, ok := senderFor(t.senders, phone)
+    if !ok {
+        return fmt.Errorf("no SMS sender configured for %s", callingCodeHint(phone))
+    }
+
+    form := url.Values{
+        "To":   {phone},
+        "From": {sender},
+        "Body": {body},
+    }
+    endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", t.apiURL, url.PathEscape(t.accountSID))
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+    if err != nil {
+        return fmt.Errorf("failed to create SMS request: %w", err)
+    }
+    req.SetBasicAuth(t.accountSID, t.authToken)
+    req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+    resp, err := t.client.Do(req)
+    if err != nil {
+        return fmt.Errorf("failed to send SMS: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+        return nil
+    }
+    var failure struct {
+        Code    int    `json:"code"`
+        Message string `json:"message"`
+    }
+    data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<12))
+    json.Unmarshal(data, &failure)
+    if failure.Code == twilioOptedOutCode {
+        return ErrOptedOut
+    }
+    return fmt.Errorf("messaging API returned status %d: %d %s", resp.StatusCode, failure.Code, failure.Message)
+}
+
+// callingCodeHint returns the first digits of a phone number, enough to tell which country's
+// sender is missing without logging the number
+func callingCodeHint(phone string) string {
+    if len(phone) > 4 {
+        return phone[:4] + "..."
+    }
+    return phone
+}
+
+// VerifyTwilioSignature reports whether signature, the X-Twilio-Signature header, was made
+// with authToken for a request posted to webhookURL with params
+func VerifyTwilioSignature(authToken, webhookURL string, params url.Values, signature string) bool {
+    if authToken == "" || signature == "" {
+        return false
+    }
+    keys := make([]string, 0, len(params))
+    for key := range params {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+
+    var payload strings.Builder
+    payload.WriteString(webhookURL)
+    for _, key := range keys {
+        for _, value := range params[key] {
+            payload.WriteString(key)
+            payload.WriteString(value)
+        }
+    }
+
+    mac := hmac.New(sha1.New, []byte(authToken))
+    mac.Write([]byte(payload.String()))
+    expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+    return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,283 @@
+// Package payments requests booking payments from the payment-service and reads back their
+// captures and refunds
+package payments
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/url"
+    "time"
+)
+
+// Human Tasks:
+// 1. Set BOOKING_PAYMENTS_URL to the payment-service base URL; payment reconciliation and cancellation
+//    refunds are skipped, and tips and dispute refunds are refused, while it is unset
+// 2. Configure network policies allowing booking-service to reach payment-service
+// 3. Set Holds once the payment-service can authorize and void payments; until then bookings
+//    are confirmed without holding their payment
+
+// Payment kinds recorded with the payment processor
+const (
+    // KindBooking is the payment for the booking itself; payments recorded before kinds
+    // were introduced have no kind and are booking payments
+    KindBooking = "booking"
+
+    // KindTip is a tip for the walker, paid separately after the walk
+    KindTip = "tip"
+)
+
+// Settlement is the money captured and refunded on one payment for a booking, in the
+// currency's smallest unit
+type Settlement struct {
+    PaymentID     string    `json:"paymentIntentId"`
+    BookingID     string    `json:"bookingId"`
+    Kind          string    `json:"kind"`
+    Currency      string    `json:"currency"`
+    CapturedCents int64     `json:"captured"`
+    RefundedCents int64     `json:"refunded"`
+    CreatedAt     time.Time `json:"created"`
+}
+
+// Ledger lists the payments taken for bookings
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type Ledger interface {
+    // Settlements returns every booking payment created in [from, to) with its current
+    // captured and refunded amounts
+    Settlements(ctx context.Context, from, to time.Time) ([]Settlement, error)
+}
+
+// Charge is a payment requested from a user for a booking
+type Charge struct {
+    ID          string
+    UserID      string
+    BookingID   string
+    Kind        string
+    AmountCents int64
+    Currency    string
+}
+
+// ChargeResult is the payment created for a charge. Payments the user still has to confirm
+// carry a client secret for the apps to complete them with.
+type ChargeResult struct {
+    PaymentID    string `json:"stripePaymentIntentId"`
+    Status       string `json:"status"`
+    ClientSecret string `json:"clientSecret,omitempty"`
+}
+
+// Charger takes payments for bookings
+type Charger interface {
+    Charge(ctx context.Context, charge Charge) (*ChargeResult, error)
+}
+
+// Refund returns money taken for a booking to its owner
+type Refund struct {
+    BookingID   string
+    AmountCents int64
+
+    // Reference identifies why the refund was made, such as a dispute ID. Repeating a refund
+    // with the same reference does not refund twice.
+    Reference string
+}
+
+// RefundResult is the refund created by the payment processor
+type RefundResult struct {
+    RefundID    string `json:"refundId"`
+    Status      string `json:"status"`
+    AmountCents int64  `json:"amount"`
+}
+
+// Refunder refunds booking payments
+type Refunder interface {
+    Refund(ctx context.Context, refund Refund) (*RefundResult, error)
+}
+
+// Authorizer holds payments for bookings, to be captured once the walk has been paid for or
+// voided to release the owner's money
+type Authorizer interface {
+    // Authorize places a hold for the charge; repeating it with the same charge ID returns
+    // the original hold
+    Authorize(ctx context.Context, charge Charge) (*ChargeResult, error)
+
+    // Void releases a hold; voiding a released hold succeeds
+    Void(ctx context.Context, paymentID string) error
+}
+
+// Process-wide ledger, charger and refunder, set by Init; nil when no payment-service is configured
+var (
+    Default Ledger
+    Charges Charger
+    Refunds Refunder
+)
+
+// Holds authorizes booking payments when confirming bookings. Init leaves it alone, as the
+// payment-service captures payments when they are created; nil means no payment is held.
+var Holds Authorizer
+
+// Init selects the ledger, charger and refunder: payments go through the payment-service at
+// baseURL when set, otherwise all are nil and nothing can be reconciled, charged or refunded
+func Init(baseURL string) {
+    if baseURL == "" {
+        Default = nil
+        Charges = nil
+        Refunds = nil
+        return
+    }
+    client := NewHTTPLedger(baseURL)
+    Default = client
+    Charges = client
+    Refunds = client
+}
+
+// HTTPLedger reads settlements from, and requests charges and refunds through, the payment-service
+type HTTPLedger struct {
+    baseURL string
+    client  *http.Client
+}
+
+// NewHTTPLedger creates a ledger for the payment-service at baseURL
+func NewHTTPLedger(baseURL string) *HTTPLedger {
+    return &HTTPLedger{
+        baseURL: baseURL,
+        // The payment-service pages through the processor's API, so a long range takes a while
+        client: &http.Client{Timeout: 2 * time.Minute},
+    }
+}
+
+// settlementsResponse mirrors the payment-service settlements response
+type settlementsResponse struct {
+    Success bool         `json:"success"`
+    Data    []Settlement `json:"data"`
+}
+
+// Settlements implements Ledger
+func (l *HTTPLedger) Settlements(ctx context.Context, from, to time.Time) ([]Settlement, error) {
+    query := url.Values{}
+    query.Set("from", from.UTC().Format(time.RFC3339))
+    query.Set("to", to.UTC().Format(time.RFC3339))
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.baseURL+"/payments/settlements?"+query.Encode(), nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to create settlements request: %w", err)
+    }
+
+    resp, err := l.client.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch settlements: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("payment-service returned status %d", resp.StatusCode)
+    }
+
+    var body settlementsResponse
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+        return nil, fmt.Errorf("failed to decode settlements: %w", err)
+    }
+    return body.Data, nil
+}
+
+// chargeRequest mirrors the payment-service payment creation request
+type chargeRequest struct {
+    ID                      string `json:"id"`
+    UserID                  string `json:"userId"`
+    Amount                  int64  `json:"amount"`
+    Currency                string `json:"currency"`
+    ServiceSpecificProperty string `json:"serviceSpecificProperty"`
+    BookingID               string `json:"bookingId"`
+    Kind                    string `json:"kind"`
+}
+
+// chargeResponse mirrors the payment-service payment creation response
+type chargeResponse struct {
+    Success bool         `json:"success"`
+    Data    ChargeResult `json:"data"`
+}
+
+// Charge implements Charger
+func (l *HTTPLedger) Charge(ctx context.Context, charge Charge) (*ChargeResult, error) {
+    payload, err := json.Marshal(chargeRequest{
+        ID:                      charge.ID,
+        UserID:                  charge.UserID,
+        Amount:                  charge.AmountCents,
+        Currency:                charge.Currency,
+        ServiceSpecificProperty: "booking-service",
+        BookingID:               charge.BookingID,
+        Kind:                    charge.Kind,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode charge: %w", err)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.baseURL+"/payments", bytes.NewReader(payload))
+    if err != nil {
+        return nil, fmt.Errorf("failed to create charge request: %w", err)
+    }
+    req.Header.Set("Content-Type", "application/json")
+
+    resp, err := l.client.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("failed to request charge: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("payment-service returned status %d", resp.StatusCode)
+    }
+
+    var body chargeResponse
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+        return nil, fmt.Errorf("failed to decode charge: %w", err)
+    }
+    return &body.Data, nil
+}
+
+// refundRequest mirrors the payment-service refund request
+type refundRequest struct {
+    BookingID string `json:"bookingId"`
+    Amount    int64  `json:"amount"`
+    Reference string `json:"reference,omitempty"`
+}
+
+// refundResponse mirrors the payment-service refund response
+type refundResponse struct {
+    Success bool         `json:"success"`
+    Data    RefundResult `json:"data"`
+}
+
+// Refund implements Refunder
+func (l *HTTPLedger) Refund(ctx context.Context, refund Refund) (*RefundResult, error) {
+    payload, err := json.Marshal(refundRequest{
+        BookingID: refund.BookingID,
+        Amount:    refund.AmountCents,
+        Reference: refund.Reference,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode refund: %w", err)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.baseURL+"/payments/refund", bytes.NewReader(payload))
+    if err != nil {
+        return nil, fmt.Errorf("failed to create refund request: %w", err)
+    }
+    req.Header.Set("Content-Type", "application/json")
+
+    resp, err := l.client.Do(req)
+    if err != nil {
+        return nil, fmt.Errorf("failed to request refund: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("payment-service returned status %d", resp.StatusCode)
+    }
+
+    var body refundResponse
+    if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+        return nil, fmt.Errorf("failed to decode refund: %w", err)
+    }
+    return &body.Data, nil
+}
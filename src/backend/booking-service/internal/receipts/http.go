@@ -0,0 +1,67 @@
+// Package receipts renders booking receipts and keeps them in object storage
+package receipts
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// maxDocumentSize bounds the response read from a rendering service
+const maxDocumentSize = 10 << 20
+
+// HTTPRenderer posts the receipt as JSON to a document rendering service, which returns the
+// rendered document with its content type
+type HTTPRenderer struct {
+    url    string
+    client *http.Client
+}
+
+// NewHTTPRenderer creates a renderer posting to url
+func NewHTTPRenderer(url string) *HTTPRenderer {
+    return &HTTPRenderer{
+        url:    url,
+        client: &http.Client{Timeout: 30 * time.Second},
+    }
+}
+
+// Render implements Renderer
+func (r *HTTPRenderer) Render(ctx context.Context, receipt *models.Receipt) ([]byte, string, error) {
+    payload, err := json.Marshal(receipt)
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to encode receipt: %w", err)
+    }
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(payload))
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to create render request: %w", err)
+    }
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Accept", ContentTypePDF)
+
+    resp, err := r.client.Do(req)
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to render receipt: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return nil, "", fmt.Errorf("receipt renderer returned status %d", resp.StatusCode)
+    }
+
+    document, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize))
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to read rendered receipt: %w", err)
+    }
+    contentType := resp.Header.Get("Content-Type")
+    if contentType == "" {
+        contentType = ContentTypePDF
+    }
+    return document, contentType, nil
+}
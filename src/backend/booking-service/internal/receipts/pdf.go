@@ -0,0 +1,101 @@
+// Package receipts renders booking receipts and keeps them in object storage
+package receipts
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "strings"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ContentTypePDF is the content type of rendered receipts
+const ContentTypePDF = "application/pdf"
+
+// PDFRenderer renders a single-page, text-only PDF without any external dependency. It is
+// the default; a document service with branded templates can replace it through RendererURL.
+type PDFRenderer struct{}
+
+// Render implements Renderer
+func (PDFRenderer) Render(ctx context.Context, receipt *models.Receipt) ([]byte, string, error) {
+    var content bytes.Buffer
+    content.WriteString("BT\n/F1 11 Tf\n16 TL\n72 740 Td\n")
+    for _, line := range ReceiptLines(receipt) {
+        fmt.Fprintf(&content, "(%s) Tj T*\n", escapePDFText(line))
+    }
+    content.WriteString("ET\n")
+
+    objects := []string{
+        "<< /Type /Catalog /Pages 2 0 R >>",
+        "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+        "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+        "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+        fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+    }
+
+    // Every object's byte offset goes in the cross-reference table readers seek with
+    var out bytes.Buffer
+    out.WriteString("%PDF-1.4\n")
+    offsets := make([]int, len(objects))
+    for i, object := range objects {
+        offsets[i] = out.Len()
+        fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+    }
+    xref := out.Len()
+    fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+    for _, offset := range offsets {
+        fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+    }
+    fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+    return out.Bytes(), ContentTypePDF, nil
+}
+
+// ReceiptLines lays a receipt out as lines of text, for documents and plain-text emails
+func ReceiptLines(receipt *models.Receipt) []string {
+    lines := []string{
+        "Dog Walking Receipt",
+        "",
+        "Receipt number: " + receipt.Number,
+        "Issued: " + receipt.IssuedAt.UTC().Format("January 2, 2006 15:04 MST"),
+        "Booking: " + receipt.BookingID,
+        fmt.Sprintf("Walk: %s, %d minutes", receipt.ScheduledAt.UTC().Format("January 2, 2006 15:04 MST"), receipt.DurationMinutes),
+        "Walker: " + receipt.WalkerID,
+        "Dog: " + receipt.DogID,
+        "",
+    }
+    for _, item := range receipt.LineItems {
+        lines = append(lines, fmt.Sprintf("%s: %s", item.Description, FormatAmount(item.AmountCents, receipt.Currency)))
+    }
+    lines = append(lines, "", "Total: "+FormatAmount(receipt.TotalCents, receipt.Currency))
+    return lines
+}
+
+// FormatAmount formats an amount in the currency's smallest unit, such as "USD 25.50"
+func FormatAmount(cents int64, currency string) string {
+    sign := ""
+    if cents < 0 {
+        sign = "-"
+        cents = -cents
+    }
+    return fmt.Sprintf("%s%s %d.%02d", sign, strings.ToUpper(currency), cents/100, cents%100)
+}
+
+// escapePDFText escapes a line for a PDF string literal. The standard fonts only cover
+// Latin-1, so other characters are replaced.
+func escapePDFText(s string) string {
+    var b strings.Builder
+    for _, r := range s {
+        switch {
+        case r == '\\' || r == '(' || r == ')':
+            b.WriteByte('\\')
+            b.WriteRune(r)
+        case r < 0x20 || r > 0x7e:
+            b.WriteByte('?')
+        default:
+            b.WriteRune(r)
+        }
+    }
+    return b.String()
+}
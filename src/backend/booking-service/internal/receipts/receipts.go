@@ -0,0 +1,80 @@
+// Package receipts renders booking receipts and keeps them in object storage
+package receipts
+
+import (
+    "context"
+    "errors"
+    "fmt"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// Human Tasks:
+// 1. Create the receipts bucket and grant the service role s3:PutObject and s3:GetObject on it
+// 2. Receipts are financial records; set the bucket's retention to match the finance team's policy
+// 3. Without a bucket, receipts are kept on local disk; use that only for development
+
+// ErrNotFound is returned when no document is stored under a key
+var ErrNotFound = errors.New("receipt document not found")
+
+// Renderer turns a receipt into a printable document
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type Renderer interface {
+    // Render returns the document and its content type
+    Render(ctx context.Context, receipt *models.Receipt) ([]byte, string, error)
+}
+
+// Store keeps receipt documents
+type Store interface {
+    Put(ctx context.Context, key, contentType string, body []byte) error
+
+    // Get returns the document stored under key, or ErrNotFound
+    Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// Options selects the renderer and the store
+type Options struct {
+    // Bucket is the S3 bucket receipts are written to; empty keeps them in Dir
+    Bucket string
+
+    // Endpoint overrides the S3 endpoint, for S3-compatible stores such as MinIO
+    Endpoint string
+
+    // Dir is the local directory used when no bucket is configured
+    Dir string
+
+    // RendererURL is a document rendering service receipts are posted to; the built-in PDF
+    // renderer is used when empty
+    RendererURL string
+}
+
+// Process-wide renderer and store, set by Init
+var (
+    DefaultRenderer Renderer = PDFRenderer{}
+    DefaultStore    Store
+)
+
+// Init selects the renderer and the store from opts
+func Init(ctx context.Context, opts Options) error {
+    if opts.RendererURL != "" {
+        DefaultRenderer = NewHTTPRenderer(opts.RendererURL)
+    } else {
+        DefaultRenderer = PDFRenderer{}
+    }
+
+    if opts.Bucket != "" {
+        store, err := NewS3Store(ctx, opts.Bucket, opts.Endpoint)
+        if err != nil {
+            return err
+        }
+        DefaultStore = store
+        return nil
+    }
+
+    store, err := NewFileStore(opts.Dir)
+    if err != nil {
+        return fmt.Errorf("failed to create receipt store: %w", err)
+    }
+    DefaultStore = store
+    return nil
+}
@@ -0,0 +1,140 @@
+// Package receipts renders booking receipts and keeps them in object storage
+package receipts
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/aws/aws-sdk-go-v2/aws"               // v1.21.0
+    awsconfig "github.com/aws/aws-sdk-go-v2/config"  // v1.18.42
+    "github.com/aws/aws-sdk-go-v2/service/s3"        // v1.40.0
+    "github.com/aws/aws-sdk-go-v2/service/s3/types" // v1.40.0
+)
+
+// S3Store keeps receipts in an S3 bucket
+type S3Store struct {
+    bucket string
+    client *s3.Client
+}
+
+// NewS3Store creates an S3Store for bucket using the default AWS credential chain.
+// A non-empty endpoint selects an S3-compatible service with path-style addressing.
+func NewS3Store(ctx context.Context, bucket, endpoint string) (*S3Store, error) {
+    cfg, err := awsconfig.LoadDefaultConfig(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+    }
+
+    client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+        if endpoint != "" {
+            o.BaseEndpoint = aws.String(endpoint)
+            o.UsePathStyle = true
+        }
+    })
+    return &S3Store{bucket: bucket, client: client}, nil
+}
+
+// Put implements Store
+func (s *S3Store) Put(ctx context.Context, key, contentType string, body []byte) error {
+    _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+        Bucket:      aws.String(s.bucket),
+        Key:         aws.String(key),
+        Body:        bytes.NewReader(body),
+        ContentType: aws.String(contentType),
+    })
+    if err != nil {
+        return fmt.Errorf("failed to upload receipt: %w", err)
+    }
+    return nil
+}
+
+// Get implements Store
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+    out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+        Bucket: aws.String(s.bucket),
+        Key:    aws.String(key),
+    })
+    var missing *types.NoSuchKey
+    if errors.As(err, &missing) {
+        return nil, ErrNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to download receipt: %w", err)
+    }
+    defer out.Body.Close()
+
+    body, err := io.ReadAll(out.Body)
+    if err != nil {
+        return nil, fmt.Errorf("failed to download receipt: %w", err)
+    }
+    return body, nil
+}
+
+// FileStore keeps receipts on local disk. It is not shared between instances, so it is only
+// suitable for development and tests.
+type FileStore struct {
+    dir string
+}
+
+// NewFileStore creates a FileStore writing under dir
+func NewFileStore(dir string) (*FileStore, error) {
+    if err := os.MkdirAll(dir, 0o750); err != nil {
+        return nil, err
+    }
+    return &FileStore{dir: dir}, nil
+}
+
+// Put writes body to a temporary file and renames it into place, so a partially written
+// receipt is never read
+func (s *FileStore) Put(ctx context.Context, key, contentType string, body []byte) error {
+    path, err := s.path(key)
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+        return err
+    }
+
+    tmp, err := os.CreateTemp(filepath.Dir(path), ".receipt-*")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(tmp.Name())
+
+    if _, err := tmp.Write(body); err != nil {
+        tmp.Close()
+        return err
+    }
+    if err := tmp.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp.Name(), path)
+}
+
+// Get implements Store
+func (s *FileStore) Get(ctx context.Context, key string) ([]byte, error) {
+    path, err := s.path(key)
+    if err != nil {
+        return nil, err
+    }
+    body, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil, ErrNotFound
+    }
+    return body, err
+}
+
+// path maps key to a file under the store's directory, rejecting keys that would escape it
+func (s *FileStore) path(key string) (string, error) {
+    clean := filepath.Clean(filepath.FromSlash(key))
+    if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+        return "", fmt.Errorf("invalid receipt key %q", key)
+    }
+    return filepath.Join(s.dir, clean), nil
+}
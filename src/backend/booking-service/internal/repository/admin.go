@@ -0,0 +1,300 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrTooManyBookings is returned when a bulk change matches more bookings than it may change at once
+var ErrTooManyBookings = errors.New("too many bookings match")
+
+// OverrideBooking applies an administrative change to a booking and records it in the audit
+// log in the same transaction. mutate receives the locked booking and edits it in place;
+// if it returns an error nothing is written. When the walker changes and capacity is
+// positive, the new walker's overlapping bookings are checked against it.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func OverrideBooking(ctx context.Context, bookingID string, entry *models.AuditEntry, capacity int, mutate func(booking *models.Booking) error) (*models.Booking, error) {
+    if memory != nil {
+        return memory.overrideBooking(bookingID, entry, capacity, mutate)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    booking, err := getBookingForUpdate(ctx, tx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+
+    before, err := json.Marshal(booking)
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode booking: %w", err)
+    }
+
+    previousWalker := booking.WalkerID
+    if err := mutate(booking); err != nil {
+        return nil, err
+    }
+
+    if capacity > 0 && booking.WalkerID != previousWalker {
+        if err := lockWalker(ctx, tx, booking.WalkerID); err != nil {
+            return nil, err
+        }
+        overlapping, err := countOverlapping(ctx, tx, booking.WalkerID, booking.ScheduledAt, booking.EndsAt(), booking.ID)
+        if err != nil {
+            return nil, err
+        }
+        if overlapping >= capacity {
+            return nil, ErrSlotFull
+        }
+    }
+
+    after, err := json.Marshal(booking)
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode booking: %w", err)
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        UPDATE bookings SET walker_id = $2, status = $3, amount = $4, tax = $5 WHERE id = $1`,
+        booking.ID,
+        booking.WalkerID,
+        booking.Status,
+        booking.Amount,
+        booking.Tax,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to update booking: %w", err)
+    }
+
+    entry.BookingID = booking.ID
+    entry.Before = before
+    entry.After = after
+    if err := insertAuditEntry(ctx, tx, entry); err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, fmt.Errorf("failed to commit booking override: %w", err)
+    }
+    return booking, nil
+}
+
+// ListAuditEntries returns the audit entries recorded for a booking, oldest first
+func ListAuditEntries(ctx context.Context, bookingID string) ([]models.AuditEntry, error) {
+    if memory != nil {
+        return memory.listAuditEntries(bookingID), nil
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT id, actor_id, action, booking_id, reason, before, after, created_at
+        FROM audit_log
+        WHERE booking_id = $1
+        ORDER BY created_at, id`,
+        bookingID,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to query audit log: %w", err)
+    }
+    defer rows.Close()
+
+    var entries []models.AuditEntry
+    for rows.Next() {
+        var entry models.AuditEntry
+        var before, after []byte
+        if err := rows.Scan(&entry.ID, &entry.ActorID, &entry.Action, &entry.BookingID, &entry.Reason, &before, &after, &entry.CreatedAt); err != nil {
+            return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+        }
+        entry.Before = before
+        entry.After = after
+        entries = append(entries, entry)
+    }
+    return entries, rows.Err()
+}
+
+// BulkSetStatus sets the status of every booking of filter.WalkerID scheduled in the filter's
+// window in one transaction, recording an audit entry from newEntry for each booking changed.
+// Matched bookings already at status, or whose status is not one of filter.Statuses, are left
+// unchanged and reported as skipped. When more than limit bookings match, nothing is written
+// and ErrTooManyBookings is returned. The bookings changed are returned as they are now.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func BulkSetStatus(ctx context.Context, filter models.BulkStatusFilter, status models.BookingStatus, limit int, newEntry func() (*models.AuditEntry, error)) (*models.BulkStatusReport, []models.Booking, error) {
+    if memory != nil {
+        return memory.bulkSetStatus(filter, status, limit, newEntry)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    // Rows are locked in a fixed order so concurrent bulk changes cannot deadlock
+    rows, err := tx.QueryContext(ctx, `
+        SELECT id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
+        FROM bookings
+        WHERE walker_id = $1 AND scheduled_at >= $2 AND scheduled_at < $3
+        ORDER BY scheduled_at, id
+        LIMIT $4
+        FOR UPDATE`,
+        filter.WalkerID,
+        filter.From,
+        filter.To,
+        limit+1,
+    )
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to list bookings: %w", err)
+    }
+    var matched []models.Booking
+    for rows.Next() {
+        var b models.Booking
+        if err := rows.Scan(
+            &b.ID,
+            &b.OwnerID,
+            &b.WalkerID,
+            &b.DogID,
+            &b.ScheduledAt,
+            &b.Status,
+            &b.Amount,
+            &b.DurationMinutes,
+            &b.AcceptBy,
+            &b.Tax,
+            &b.Region,
+            &b.Rate,
+            &b.Cancellation,
+            &b.Latitude,
+            &b.Longitude,
+            &b.Address,
+        ); err != nil {
+            rows.Close()
+            return nil, nil, fmt.Errorf("failed to scan booking: %w", err)
+        }
+        matched = append(matched, b)
+    }
+    rows.Close()
+    if err := rows.Err(); err != nil {
+        return nil, nil, fmt.Errorf("failed to list bookings: %w", err)
+    }
+    if len(matched) > limit {
+        return nil, nil, ErrTooManyBookings
+    }
+
+    report := &models.BulkStatusReport{Results: make([]models.BulkStatusResult, 0, len(matched))}
+    var changed []models.Booking
+    for i := range matched {
+        booking := &matched[i]
+        result := planBulkStatus(booking, filter.Statuses, status)
+        report.Results = append(report.Results, result)
+        if result.Outcome == models.BulkStatusSkipped {
+            report.Skipped++
+            continue
+        }
+        report.Updated++
+
+        entry, err := newEntry()
+        if err != nil {
+            return nil, nil, err
+        }
+        if err := auditStatusChange(booking, status, entry); err != nil {
+            return nil, nil, err
+        }
+
+        if _, err := tx.ExecContext(ctx, `UPDATE bookings SET status = $2 WHERE id = $1`, booking.ID, booking.Status); err != nil {
+            return nil, nil, fmt.Errorf("failed to update booking: %w", err)
+        }
+        if err := insertAuditEntry(ctx, tx, entry); err != nil {
+            return nil, nil, err
+        }
+        changed = append(changed, *booking)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, nil, fmt.Errorf("failed to commit bulk status change: %w", err)
+    }
+    return report, changed, nil
+}
+
+// planBulkStatus reports whether a bulk change to status applies to booking, which it does
+// unless the booking already has that status or a status outside statuses
+func planBulkStatus(booking *models.Booking, statuses []models.BookingStatus, status models.BookingStatus) models.BulkStatusResult {
+    result := models.BulkStatusResult{
+        BookingID:      booking.ID,
+        ScheduledAt:    booking.ScheduledAt,
+        PreviousStatus: booking.Status,
+        Status:         booking.Status,
+        Outcome:        models.BulkStatusSkipped,
+    }
+
+    if booking.Status == status {
+        result.Reason = fmt.Sprintf("booking is already %s", status)
+        return result
+    }
+    selected := false
+    for _, s := range statuses {
+        selected = selected || booking.Status == s
+    }
+    if !selected {
+        result.Reason = fmt.Sprintf("booking is %s, which was not selected", booking.Status)
+        return result
+    }
+
+    result.Status = status
+    result.Outcome = models.BulkStatusUpdated
+    return result
+}
+
+// auditStatusChange sets booking's status, filling entry with the booking before and after
+func auditStatusChange(booking *models.Booking, status models.BookingStatus, entry *models.AuditEntry) error {
+    before, err := json.Marshal(booking)
+    if err != nil {
+        return fmt.Errorf("failed to encode booking: %w", err)
+    }
+    booking.Status = status
+    after, err := json.Marshal(booking)
+    if err != nil {
+        return fmt.Errorf("failed to encode booking: %w", err)
+    }
+
+    entry.BookingID = booking.ID
+    entry.Before = before
+    entry.After = after
+    return nil
+}
+
+// insertAuditEntry writes an audit entry in the transaction of the change it records
+func insertAuditEntry(ctx context.Context, tx *sql.Tx, entry *models.AuditEntry) error {
+    _, err := tx.ExecContext(ctx, `
+        INSERT INTO audit_log (id, actor_id, action, booking_id, reason, before, after, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+        entry.ID,
+        entry.ActorID,
+        entry.Action,
+        entry.BookingID,
+        entry.Reason,
+        []byte(entry.Before),
+        []byte(entry.After),
+        entry.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to write audit log: %w", err)
+    }
+    return nil
+}
@@ -0,0 +1,160 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrAPIKeyNotFound is returned when no active API key matches
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// apiKeyColumns is the column list scanned by scanAPIKey
+const apiKeyColumns = `id, name, prefix, key_hash, scopes, tier, created_by, created_at, last_used_at, revoked_at`
+
+// scanAPIKey scans a row selected with apiKeyColumns
+func scanAPIKey(row interface{ Scan(...interface{}) error }) (*models.APIKey, error) {
+    key := &models.APIKey{}
+    err := row.Scan(
+        &key.ID,
+        &key.Name,
+        &key.Prefix,
+        &key.KeyHash,
+        pq.Array(&key.Scopes),
+        &key.Tier,
+        &key.CreatedBy,
+        &key.CreatedAt,
+        &key.LastUsedAt,
+        &key.RevokedAt,
+    )
+    if err != nil {
+        return nil, err
+    }
+    return key, nil
+}
+
+// CreateAPIKey stores a newly issued API key
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+    if memory != nil {
+        return memory.createAPIKey(key)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO api_keys (id, name, prefix, key_hash, scopes, tier, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+        key.ID,
+        key.Name,
+        key.Prefix,
+        key.KeyHash,
+        pq.Array(key.Scopes),
+        key.Tier,
+        key.CreatedBy,
+        key.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create api key: %w", err)
+    }
+    return nil
+}
+
+// GetActiveAPIKeyByHash retrieves the unrevoked API key with the given hash
+func GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+    if memory != nil {
+        return memory.getActiveAPIKeyByHash(keyHash)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    key, err := scanAPIKey(DB.QueryRowContext(ctx, `
+        SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`,
+        keyHash,
+    ))
+    if err == sql.ErrNoRows {
+        return nil, ErrAPIKeyNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get api key: %w", err)
+    }
+    return key, nil
+}
+
+// ListAPIKeys retrieves every API key, newest first, including revoked keys
+func ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+    if memory != nil {
+        return memory.listAPIKeys()
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at DESC`)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list api keys: %w", err)
+    }
+    defer rows.Close()
+
+    var keys []models.APIKey
+    for rows.Next() {
+        key, err := scanAPIKey(rows)
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan api key: %w", err)
+        }
+        keys = append(keys, *key)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list api keys: %w", err)
+    }
+    return keys, nil
+}
+
+// RevokeAPIKey revokes an active API key; ErrAPIKeyNotFound means it does not exist or is already revoked
+func RevokeAPIKey(ctx context.Context, id string, at time.Time) (*models.APIKey, error) {
+    if memory != nil {
+        return memory.revokeAPIKey(id, at)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    key, err := scanAPIKey(DB.QueryRowContext(ctx, `
+        UPDATE api_keys SET revoked_at = $2
+        WHERE id = $1 AND revoked_at IS NULL
+        RETURNING `+apiKeyColumns,
+        id,
+        at,
+    ))
+    if err == sql.ErrNoRows {
+        return nil, ErrAPIKeyNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to revoke api key: %w", err)
+    }
+    return key, nil
+}
+
+// TouchAPIKey records that an API key was used at the given time
+func TouchAPIKey(ctx context.Context, id string, at time.Time) error {
+    if memory != nil {
+        return memory.touchAPIKey(id, at)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    if _, err := DB.ExecContext(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, id, at); err != nil {
+        return fmt.Errorf("failed to update api key usage: %w", err)
+    }
+    return nil
+}
@@ -0,0 +1,312 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
+
+    "src/backend/booking-service/internal/models"
+)
+
+var (
+    // ErrNotAssignable is returned when a booking is no longer waiting for a walker
+    ErrNotAssignable = errors.New("booking is not awaiting a walker")
+
+    // ErrNoPendingAssignment is returned when a walker answers a booking they are not
+    // currently assigned to, or after their deadline has passed
+    ErrNoPendingAssignment = errors.New("no pending assignment for this walker")
+)
+
+// FindAvailableWalkers returns up to limit walkers for a booking whose published availability
+// covers [start, end) and who still have capacity in it, counting slots held for owners
+// checking out, least busy first. Walkers who already declined or let the booking lapse are
+// skipped.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func FindAvailableWalkers(ctx context.Context, bookingID string, start, end time.Time, limit int) ([]string, error) {
+    if memory != nil {
+        return memory.findAvailableWalkers(bookingID, start, end, limit)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT a.walker_id
+        FROM walker_availability a
+        CROSS JOIN LATERAL (
+            SELECT (
+                SELECT COUNT(*)
+                FROM bookings b
+                WHERE b.walker_id = a.walker_id
+                  AND b.status = ANY($3)
+                  AND b.scheduled_at < $2
+                  AND b.scheduled_at + make_interval(mins => b.duration_minutes) > $1
+            ) + (
+                SELECT COUNT(*)
+                FROM slot_holds h
+                WHERE h.walker_id = a.walker_id
+                  AND h.expires_at > NOW()
+                  AND h.scheduled_at < $2
+                  AND h.scheduled_at + make_interval(mins => h.duration_minutes) > $1
+            ) AS overlapping
+        ) load
+        WHERE a.starts_at <= $1 AND a.ends_at >= $2 AND load.overlapping < a.capacity
+          AND a.walker_id NOT IN (SELECT walker_id FROM assignment_declines WHERE booking_id = $5)
+          AND a.walker_id IN (SELECT walker_id FROM walker_verifications WHERE status = ANY($6))
+        ORDER BY load.overlapping, a.walker_id
+        LIMIT $4`,
+        start,
+        end,
+        pq.Array(activeBookingStatuses),
+        limit,
+        bookingID,
+        pq.Array(models.EligibleWalkerStatuses),
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to find available walkers: %w", err)
+    }
+    defer rows.Close()
+
+    var walkers []string
+    for rows.Next() {
+        var walkerID string
+        if err := rows.Scan(&walkerID); err != nil {
+            return nil, fmt.Errorf("failed to scan walker: %w", err)
+        }
+        walkers = append(walkers, walkerID)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to read available walkers: %w", err)
+    }
+    return walkers, nil
+}
+
+// AssignWalker sets the walker of a pending booking, provided the walker has fewer than
+// capacity overlapping bookings, and starts the walker's acceptance deadline
+func AssignWalker(ctx context.Context, bookingID, walkerID string, capacity int, acceptBy time.Time) (*models.Booking, error) {
+    if memory != nil {
+        return memory.assignWalker(bookingID, walkerID, capacity, acceptBy)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    booking, err := getBookingForUpdate(ctx, tx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.Status != models.BookingStatusPending && booking.Status != models.BookingStatusNeedsReassignment {
+        return nil, ErrNotAssignable
+    }
+
+    if err := lockWalker(ctx, tx, walkerID); err != nil {
+        return nil, err
+    }
+    overlapping, err := countOverlapping(ctx, tx, walkerID, booking.ScheduledAt, booking.EndsAt(), booking.ID)
+    if err != nil {
+        return nil, err
+    }
+    if overlapping >= capacity {
+        return nil, ErrSlotFull
+    }
+
+    // A booking waiting for reassignment goes back to awaiting its new walker's acceptance
+    _, err = tx.ExecContext(ctx, `
+        UPDATE bookings SET walker_id = $2, accept_by = $3, status = $4 WHERE id = $1`,
+        booking.ID,
+        walkerID,
+        acceptBy,
+        models.BookingStatusPending,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to assign walker: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, fmt.Errorf("failed to commit walker assignment: %w", err)
+    }
+
+    booking.WalkerID = walkerID
+    booking.AcceptBy = &acceptBy
+    booking.Status = models.BookingStatusPending
+    return booking, nil
+}
+
+// AcceptAssignment confirms a pending booking on behalf of its assigned walker, provided the
+// acceptance deadline has not passed
+func AcceptAssignment(ctx context.Context, bookingID, walkerID string) error {
+    if memory != nil {
+        return memory.acceptAssignment(bookingID, walkerID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `
+        UPDATE bookings SET status = $3, accept_by = NULL
+        WHERE id = $1 AND walker_id = $2 AND status = $4
+          AND (accept_by IS NULL OR accept_by > NOW())`,
+        bookingID,
+        walkerID,
+        models.BookingStatusConfirmed,
+        models.BookingStatusPending,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to accept booking: %w", err)
+    }
+
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to accept booking: %w", err)
+    }
+    if rows == 0 {
+        return ErrNoPendingAssignment
+    }
+    return nil
+}
+
+// ReleaseAssignment removes walkerID from a pending booking and records why, so the matching
+// engine does not offer the booking to them again. It returns ErrNoPendingAssignment if the
+// walker is no longer assigned, which lets concurrent releases of the same booking detect
+// that another one won.
+func ReleaseAssignment(ctx context.Context, bookingID, walkerID, reason string) error {
+    if memory != nil {
+        return memory.releaseAssignment(bookingID, walkerID, reason)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    result, err := tx.ExecContext(ctx, `
+        UPDATE bookings SET walker_id = '', accept_by = NULL
+        WHERE id = $1 AND walker_id = $2 AND status = $3`,
+        bookingID,
+        walkerID,
+        models.BookingStatusPending,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to release assignment: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to release assignment: %w", err)
+    }
+    if rows == 0 {
+        return ErrNoPendingAssignment
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        INSERT INTO assignment_declines (booking_id, walker_id, reason, created_at)
+        VALUES ($1, $2, $3, NOW())`,
+        bookingID,
+        walkerID,
+        reason,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to record declined assignment: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit released assignment: %w", err)
+    }
+    return nil
+}
+
+// ListLapsedAssignments retrieves pending bookings whose walker did not answer before now
+func ListLapsedAssignments(ctx context.Context, now time.Time) ([]models.Booking, error) {
+    if memory != nil {
+        return memory.listLapsedAssignments(now)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
+        FROM bookings
+        WHERE status = $1 AND walker_id <> '' AND accept_by <= $2
+        ORDER BY accept_by`,
+        models.BookingStatusPending,
+        now,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to query lapsed assignments: %w", err)
+    }
+    defer rows.Close()
+
+    var lapsed []models.Booking
+    for rows.Next() {
+        var b models.Booking
+        err := rows.Scan(
+            &b.ID,
+            &b.OwnerID,
+            &b.WalkerID,
+            &b.DogID,
+            &b.ScheduledAt,
+            &b.Status,
+            &b.Amount,
+            &b.DurationMinutes,
+            &b.AcceptBy,
+            &b.Tax,
+            &b.Region,
+            &b.Rate,
+            &b.Cancellation,
+            &b.Latitude,
+            &b.Longitude,
+            &b.Address,
+        )
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan lapsed assignment: %w", err)
+        }
+        lapsed = append(lapsed, b)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to read lapsed assignments: %w", err)
+    }
+    return lapsed, nil
+}
+
+// CancelUnassignedBooking cancels a pending booking that has no walker
+func CancelUnassignedBooking(ctx context.Context, bookingID string) error {
+    if memory != nil {
+        return memory.cancelUnassignedBooking(bookingID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `
+        UPDATE bookings SET status = $2
+        WHERE id = $1 AND status = $3 AND walker_id = ''`,
+        bookingID,
+        models.BookingStatusCancelled,
+        models.BookingStatusPending,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to cancel booking: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to cancel booking: %w", err)
+    }
+    if rows == 0 {
+        return ErrNotAssignable
+    }
+    return nil
+}
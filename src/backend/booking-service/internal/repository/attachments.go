@@ -0,0 +1,293 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/encryption"
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrAttachmentNotFound is returned when a booking has no attachment with the requested ID
+var ErrAttachmentNotFound = errors.New("attachment not found")
+
+// ErrAttachmentsUnavailable is returned when no attachment encryption keys are configured;
+// attachments are never stored in the clear
+var ErrAttachmentsUnavailable = errors.New("attachments unavailable: no encryption keys configured")
+
+// attachmentKeys seals the content of booking attachments; nil disables attachments
+var attachmentKeys *encryption.Keyring
+
+// UseAttachmentEncryption seals booking attachments with keys
+func UseAttachmentEncryption(keys *encryption.Keyring) {
+    attachmentKeys = keys
+}
+
+// sealedAttachment is a booking attachment as it is stored: the fields needed to find, count
+// and purge it in the clear, and the rest sealed
+type sealedAttachment struct {
+    ID        string
+    BookingID string
+    OwnerID   string
+    Kind      models.AttachmentKind
+    Size      int
+    CreatedAt time.Time
+    KeyID     string
+    Sealed    []byte
+}
+
+// attachmentContent is the part of a booking attachment that is sealed
+type attachmentContent struct {
+    Title       string            `json:"title,omitempty"`
+    Text        string            `json:"text,omitempty"`
+    Fields      map[string]string `json:"fields,omitempty"`
+    FileName    string            `json:"file_name,omitempty"`
+    ContentType string            `json:"content_type,omitempty"`
+    Content     []byte            `json:"content,omitempty"`
+}
+
+// attachmentAAD binds sealed content to its booking and attachment, so it cannot be moved
+// to another row
+func attachmentAAD(bookingID, id string) []byte {
+    return []byte(bookingID + "/" + id)
+}
+
+// sealAttachment encrypts a's content for storage
+func sealAttachment(a *models.BookingAttachment) (*sealedAttachment, error) {
+    if attachmentKeys == nil {
+        return nil, ErrAttachmentsUnavailable
+    }
+    plaintext, err := json.Marshal(attachmentContent{
+        Title:       a.Title,
+        Text:        a.Text,
+        Fields:      a.Fields,
+        FileName:    a.FileName,
+        ContentType: a.ContentType,
+        Content:     a.Content,
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode attachment: %w", err)
+    }
+    keyID, sealed, err := attachmentKeys.Seal(plaintext, attachmentAAD(a.BookingID, a.ID))
+    if err != nil {
+        return nil, fmt.Errorf("failed to encrypt attachment: %w", err)
+    }
+    return &sealedAttachment{
+        ID:        a.ID,
+        BookingID: a.BookingID,
+        OwnerID:   a.OwnerID,
+        Kind:      a.Kind,
+        Size:      a.Size,
+        CreatedAt: a.CreatedAt,
+        KeyID:     keyID,
+        Sealed:    sealed,
+    }, nil
+}
+
+// open decrypts a stored attachment
+func (s *sealedAttachment) open() (*models.BookingAttachment, error) {
+    if attachmentKeys == nil {
+        return nil, ErrAttachmentsUnavailable
+    }
+    plaintext, err := attachmentKeys.Open(s.KeyID, s.Sealed, attachmentAAD(s.BookingID, s.ID))
+    if err != nil {
+        return nil, fmt.Errorf("failed to open attachment %s: %w", s.ID, err)
+    }
+    var content attachmentContent
+    if err := json.Unmarshal(plaintext, &content); err != nil {
+        return nil, fmt.Errorf("failed to decode attachment %s: %w", s.ID, err)
+    }
+    return &models.BookingAttachment{
+        ID:          s.ID,
+        BookingID:   s.BookingID,
+        OwnerID:     s.OwnerID,
+        Kind:        s.Kind,
+        Title:       content.Title,
+        Text:        content.Text,
+        Fields:      content.Fields,
+        FileName:    content.FileName,
+        ContentType: content.ContentType,
+        Content:     content.Content,
+        Size:        s.Size,
+        CreatedAt:   s.CreatedAt,
+    }, nil
+}
+
+// CreateBookingAttachment encrypts and stores an attachment
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CreateBookingAttachment(ctx context.Context, a *models.BookingAttachment) error {
+    sealed, err := sealAttachment(a)
+    if err != nil {
+        return err
+    }
+    if memory != nil {
+        return memory.createBookingAttachment(*sealed)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err = DB.ExecContext(ctx, `
+        INSERT INTO booking_attachments (id, booking_id, owner_id, kind, size, created_at, key_id, sealed)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+        sealed.ID,
+        sealed.BookingID,
+        sealed.OwnerID,
+        sealed.Kind,
+        sealed.Size,
+        sealed.CreatedAt,
+        sealed.KeyID,
+        sealed.Sealed,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create attachment: %w", err)
+    }
+    return nil
+}
+
+// ListBookingAttachments returns a booking's attachments, oldest first
+func ListBookingAttachments(ctx context.Context, bookingID string) ([]models.BookingAttachment, error) {
+    var stored []sealedAttachment
+    if memory != nil {
+        stored = memory.listBookingAttachments(bookingID)
+    } else {
+        ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+        defer cancel()
+
+        rows, err := DB.QueryContext(ctx, `
+            SELECT id, booking_id, owner_id, kind, size, created_at, key_id, sealed
+            FROM booking_attachments
+            WHERE booking_id = $1
+            ORDER BY created_at, id`,
+            bookingID,
+        )
+        if err != nil {
+            return nil, fmt.Errorf("failed to query attachments: %w", err)
+        }
+        defer rows.Close()
+
+        for rows.Next() {
+            s, err := scanAttachment(rows)
+            if err != nil {
+                return nil, err
+            }
+            stored = append(stored, *s)
+        }
+        if err := rows.Err(); err != nil {
+            return nil, fmt.Errorf("failed to read attachments: %w", err)
+        }
+    }
+
+    attachments := make([]models.BookingAttachment, 0, len(stored))
+    for _, s := range stored {
+        a, err := s.open()
+        if err != nil {
+            return nil, err
+        }
+        attachments = append(attachments, *a)
+    }
+    return attachments, nil
+}
+
+// GetBookingAttachment returns one of a booking's attachments
+func GetBookingAttachment(ctx context.Context, bookingID, id string) (*models.BookingAttachment, error) {
+    var stored *sealedAttachment
+    if memory != nil {
+        s, ok := memory.getBookingAttachment(bookingID, id)
+        if !ok {
+            return nil, ErrAttachmentNotFound
+        }
+        stored = &s
+    } else {
+        ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+        defer cancel()
+
+        s, err := scanAttachment(DB.QueryRowContext(ctx, `
+            SELECT id, booking_id, owner_id, kind, size, created_at, key_id, sealed
+            FROM booking_attachments
+            WHERE booking_id = $1 AND id = $2`,
+            bookingID,
+            id,
+        ))
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, ErrAttachmentNotFound
+        }
+        if err != nil {
+            return nil, err
+        }
+        stored = s
+    }
+    return stored.open()
+}
+
+// DeleteBookingAttachment removes one of a booking's attachments
+func DeleteBookingAttachment(ctx context.Context, bookingID, id string) error {
+    if memory != nil {
+        return memory.deleteBookingAttachment(bookingID, id)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `DELETE FROM booking_attachments WHERE booking_id = $1 AND id = $2`, bookingID, id)
+    if err != nil {
+        return fmt.Errorf("failed to delete attachment: %w", err)
+    }
+    deleted, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to delete attachment: %w", err)
+    }
+    if deleted == 0 {
+        return ErrAttachmentNotFound
+    }
+    return nil
+}
+
+// DeleteFinishedBookingAttachmentsBefore removes the attachments of completed, cancelled and
+// failed bookings scheduled to end before cutoff, returning how many were removed
+func DeleteFinishedBookingAttachmentsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+    if memory != nil {
+        return memory.deleteFinishedBookingAttachmentsBefore(cutoff)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `
+        DELETE FROM booking_attachments a
+        USING bookings b
+        WHERE a.booking_id = b.id
+          AND b.status IN ($2, $3, $4)
+          AND b.scheduled_at + COALESCE(NULLIF(b.duration_minutes, 0), $5) * INTERVAL '1 minute' < $1`,
+        cutoff,
+        models.BookingStatusCompleted,
+        models.BookingStatusCancelled,
+        models.BookingStatusFailed,
+        models.DefaultDurationMinutes,
+    )
+    if err != nil {
+        return 0, fmt.Errorf("failed to delete attachments: %w", err)
+    }
+    deleted, err := result.RowsAffected()
+    if err != nil {
+        return 0, fmt.Errorf("failed to delete attachments: %w", err)
+    }
+    return deleted, nil
+}
+
+// scanAttachment reads a booking_attachments row
+func scanAttachment(row interface{ Scan(...interface{}) error }) (*sealedAttachment, error) {
+    var s sealedAttachment
+    if err := row.Scan(&s.ID, &s.BookingID, &s.OwnerID, &s.Kind, &s.Size, &s.CreatedAt, &s.KeyID, &s.Sealed); err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, err
+        }
+        return nil, fmt.Errorf("failed to scan attachment: %w", err)
+    }
+    return &s, nil
+}
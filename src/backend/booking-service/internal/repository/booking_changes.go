@@ -0,0 +1,225 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+var (
+    // ErrChangeNotFound is returned when no booking change exists with the requested ID
+    ErrChangeNotFound = errors.New("booking change not found")
+
+    // ErrChangeAlreadyPending is returned when a booking already has an unanswered change
+    ErrChangeAlreadyPending = errors.New("booking already has a pending change")
+
+    // ErrChangeNotPending is returned when responding to a change that was already resolved
+    ErrChangeNotPending = errors.New("booking change is no longer pending")
+)
+
+// bookingChangeColumns lists the booking_changes columns in scan order
+const bookingChangeColumns = `id, booking_id, proposed_by, scheduled_at, duration_minutes, status, expires_at, created_at, resolved_at`
+
+// CreateBookingChange inserts a proposed change unless the booking already has a pending one
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CreateBookingChange(ctx context.Context, change *models.BookingChange) error {
+    if memory != nil {
+        return memory.createBookingChange(change)
+    }
+
+    query := `
+        INSERT INTO booking_changes (
+            id, booking_id, proposed_by, scheduled_at, duration_minutes, status, expires_at, created_at
+        )
+        SELECT $1, $2, $3, $4::timestamptz, $5::integer, $6, $7::timestamptz, $8::timestamptz
+        WHERE NOT EXISTS (
+            SELECT 1 FROM booking_changes WHERE booking_id = $2 AND status = $6
+        )`
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, query,
+        change.ID,
+        change.BookingID,
+        change.ProposedBy,
+        change.ScheduledAt,
+        change.DurationMinutes,
+        change.Status,
+        change.ExpiresAt,
+        change.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create booking change: %w", err)
+    }
+
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to create booking change: %w", err)
+    }
+    if rows == 0 {
+        return ErrChangeAlreadyPending
+    }
+
+    return nil
+}
+
+// GetBookingChange retrieves a booking change by its ID
+func GetBookingChange(ctx context.Context, id string) (*models.BookingChange, error) {
+    if memory != nil {
+        return memory.getBookingChange(id)
+    }
+
+    query := `SELECT ` + bookingChangeColumns + ` FROM booking_changes WHERE id = $1`
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    change := &models.BookingChange{}
+    err := DB.QueryRowContext(ctx, query, id).Scan(
+        &change.ID,
+        &change.BookingID,
+        &change.ProposedBy,
+        &change.ScheduledAt,
+        &change.DurationMinutes,
+        &change.Status,
+        &change.ExpiresAt,
+        &change.CreatedAt,
+        &change.ResolvedAt,
+    )
+
+    if err == sql.ErrNoRows {
+        return nil, ErrChangeNotFound
+    }
+
+    if err != nil {
+        return nil, fmt.Errorf("failed to get booking change: %w", err)
+    }
+
+    return change, nil
+}
+
+// AcceptBookingChange applies an accepted change to its booking. The updated booking must
+// still fit the walker's capacity, checked under the same per-walker lock used at creation.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func AcceptBookingChange(ctx context.Context, change *models.BookingChange, updated *models.Booking, capacity int) error {
+    if memory != nil {
+        return memory.acceptBookingChange(change, updated, capacity)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    if err := lockWalker(ctx, tx, updated.WalkerID); err != nil {
+        return err
+    }
+
+    overlapping, err := countOverlapping(ctx, tx, updated.WalkerID, updated.ScheduledAt, updated.EndsAt(), updated.ID)
+    if err != nil {
+        return err
+    }
+    if overlapping >= capacity {
+        return ErrSlotFull
+    }
+
+    if err := resolveChange(ctx, tx, change.ID, models.BookingChangeStatusAccepted); err != nil {
+        return err
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        UPDATE bookings SET scheduled_at = $2, duration_minutes = $3 WHERE id = $1`,
+        updated.ID,
+        updated.ScheduledAt,
+        updated.DurationMinutes,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to update booking: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit booking change: %w", err)
+    }
+    return nil
+}
+
+// RejectBookingChange marks a pending change as rejected
+func RejectBookingChange(ctx context.Context, id string) error {
+    if memory != nil {
+        return memory.rejectBookingChange(id)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    if err := resolveChange(ctx, tx, id, models.BookingChangeStatusRejected); err != nil {
+        return err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit booking change: %w", err)
+    }
+    return nil
+}
+
+// ExpireBookingChanges marks every pending change past its expiry as expired
+func ExpireBookingChanges(ctx context.Context, now time.Time) (int64, error) {
+    if memory != nil {
+        return memory.expireBookingChanges(now)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `
+        UPDATE booking_changes SET status = $1, resolved_at = $2
+        WHERE status = $3 AND expires_at <= $2`,
+        models.BookingChangeStatusExpired,
+        now,
+        models.BookingChangeStatusPending,
+    )
+    if err != nil {
+        return 0, fmt.Errorf("failed to expire booking changes: %w", err)
+    }
+
+    return result.RowsAffected()
+}
+
+// resolveChange moves a pending, unexpired change to status
+func resolveChange(ctx context.Context, tx *sql.Tx, id string, status models.BookingChangeStatus) error {
+    result, err := tx.ExecContext(ctx, `
+        UPDATE booking_changes SET status = $2, resolved_at = NOW()
+        WHERE id = $1 AND status = $3 AND expires_at > NOW()`,
+        id,
+        status,
+        models.BookingChangeStatusPending,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to resolve booking change: %w", err)
+    }
+
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to resolve booking change: %w", err)
+    }
+    if rows == 0 {
+        return ErrChangeNotPending
+    }
+    return nil
+}
@@ -0,0 +1,305 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "fmt"
+    "log"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// bookingEventTrigger records changes to bookings while event sourcing is enabled
+const bookingEventTrigger = "bookings_record_events"
+
+// snapshotInterval is how many events a read folds before saving a snapshot; 0 means event
+// sourcing is disabled and bookings are read from their rows
+var snapshotInterval int
+
+// ConfigureEventSourcing turns event sourcing of bookings on or off. While it is on, every
+// change to a booking is recorded as an event in the same transaction, and bookings are read
+// by folding their events onto their latest snapshot. Booking rows are still kept up to date,
+// as the projection every listing and capacity query reads. Turning it on records the current
+// state of every booking as an imported event, so history starts from a known state.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ConfigureEventSourcing(ctx context.Context, enabled bool, interval int) error {
+    if !enabled {
+        snapshotInterval = 0
+    } else if interval < 1 {
+        return fmt.Errorf("snapshot interval must be at least 1")
+    }
+
+    if memory != nil {
+        memory.configureEventSourcing(enabled)
+        if enabled {
+            snapshotInterval = interval
+        }
+        return nil
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, time.Minute)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    // Replicas starting together agree on whether the trigger was already attached
+    if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, migrationLockKey); err != nil {
+        return fmt.Errorf("failed to lock bookings: %w", err)
+    }
+    var attached bool
+    if err := tx.QueryRowContext(ctx,
+        `SELECT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = $1)`, bookingEventTrigger,
+    ).Scan(&attached); err != nil {
+        return fmt.Errorf("failed to check booking event trigger: %w", err)
+    }
+
+    switch {
+    case enabled && !attached:
+        // No booking may change between importing it and the trigger taking over
+        if _, err := tx.ExecContext(ctx, `LOCK TABLE bookings IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+            return fmt.Errorf("failed to lock bookings: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `
+            INSERT INTO booking_events (booking_id, version, type, changes)
+            SELECT b.id, COALESCE((SELECT MAX(e.version) FROM booking_events e WHERE e.booking_id = b.id), 0) + 1, $1, to_jsonb(b)
+            FROM bookings b`,
+            models.BookingEventImported,
+        ); err != nil {
+            return fmt.Errorf("failed to import bookings: %w", err)
+        }
+        // Snapshots taken before event sourcing was last turned off predate the imported state
+        if _, err := tx.ExecContext(ctx, `DELETE FROM booking_snapshots`); err != nil {
+            return fmt.Errorf("failed to clear booking snapshots: %w", err)
+        }
+        if _, err := tx.ExecContext(ctx, `
+            CREATE TRIGGER `+bookingEventTrigger+`
+            AFTER INSERT OR UPDATE ON bookings
+            FOR EACH ROW EXECUTE FUNCTION record_booking_event()`); err != nil {
+            return fmt.Errorf("failed to attach booking event trigger: %w", err)
+        }
+    case !enabled && attached:
+        if _, err := tx.ExecContext(ctx, `DROP TRIGGER IF EXISTS `+bookingEventTrigger+` ON bookings`); err != nil {
+            return fmt.Errorf("failed to detach booking event trigger: %w", err)
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit event sourcing change: %w", err)
+    }
+    if enabled {
+        snapshotInterval = interval
+    }
+    return nil
+}
+
+// ListBookingEvents returns every event recorded for a booking, oldest first
+func ListBookingEvents(ctx context.Context, bookingID string) ([]models.BookingEvent, error) {
+    if memory != nil {
+        return memory.listBookingEvents(bookingID, 0, 0)
+    }
+    return queryBookingEvents(ctx, bookingID, 0, 0)
+}
+
+// GetBookingAtVersion rebuilds a booking as it was after the given event version
+func GetBookingAtVersion(ctx context.Context, bookingID string, version int) (*models.Booking, error) {
+    if version < 1 {
+        return nil, fmt.Errorf("invalid version: must be at least 1")
+    }
+    if memory != nil {
+        return memory.foldBooking(bookingID, version)
+    }
+    return foldBooking(ctx, bookingID, version)
+}
+
+// queryBookingEvents returns a booking's events after version from, up to version to when
+// to is positive
+func queryBookingEvents(ctx context.Context, bookingID string, from, to int) ([]models.BookingEvent, error) {
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT booking_id, version, type, changes, recorded_at
+        FROM booking_events
+        WHERE booking_id = $1 AND version > $2 AND ($3 = 0 OR version <= $3)
+        ORDER BY version`,
+        bookingID,
+        from,
+        to,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to query booking events: %w", err)
+    }
+    defer rows.Close()
+
+    var events []models.BookingEvent
+    for rows.Next() {
+        var event models.BookingEvent
+        var changes []byte
+        if err := rows.Scan(&event.BookingID, &event.Version, &event.Type, &changes, &event.RecordedAt); err != nil {
+            return nil, fmt.Errorf("failed to scan booking event: %w", err)
+        }
+        event.Changes = changes
+        events = append(events, event)
+    }
+    return events, rows.Err()
+}
+
+// foldBooking rebuilds a booking from its latest snapshot at or before version and the events
+// after it; version 0 means the current state. Reading the current state after folding
+// snapshotInterval or more events saves a new snapshot.
+func foldBooking(ctx context.Context, bookingID string, version int) (*models.Booking, error) {
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    state := map[string]json.RawMessage{}
+    from := 0
+
+    var snapshot []byte
+    err := DB.QueryRowContext(ctx, `
+        SELECT version, state FROM booking_snapshots
+        WHERE booking_id = $1 AND ($2 = 0 OR version <= $2)`,
+        bookingID,
+        version,
+    ).Scan(&from, &snapshot)
+    switch {
+    case err == sql.ErrNoRows:
+        from = 0
+    case err != nil:
+        return nil, fmt.Errorf("failed to get booking snapshot: %w", err)
+    default:
+        if err := json.Unmarshal(snapshot, &state); err != nil {
+            return nil, fmt.Errorf("failed to decode booking snapshot: %w", err)
+        }
+    }
+
+    events, err := queryBookingEvents(ctx, bookingID, from, version)
+    if err != nil {
+        return nil, err
+    }
+    if from == 0 && len(events) == 0 {
+        return nil, fmt.Errorf("booking not found with id: %s", bookingID)
+    }
+    latest := from
+    if len(events) > 0 {
+        latest = events[len(events)-1].Version
+    }
+    if latest < version {
+        return nil, fmt.Errorf("invalid version: booking %s has %d changes", bookingID, latest)
+    }
+    if err := applyBookingEvents(state, events); err != nil {
+        return nil, err
+    }
+
+    if version == 0 && len(events) >= snapshotInterval && snapshotInterval > 0 {
+        if err := saveBookingSnapshot(ctx, bookingID, latest, state); err != nil {
+            // The booking was rebuilt all the same; the next read tries again
+            log.Printf("Failed to snapshot booking %s: %v", bookingID, err)
+        }
+    }
+    return decodeBookingState(state)
+}
+
+// saveBookingSnapshot records a booking's state at version, unless a later one is recorded
+func saveBookingSnapshot(ctx context.Context, bookingID string, version int, state map[string]json.RawMessage) error {
+    encoded, err := json.Marshal(state)
+    if err != nil {
+        return fmt.Errorf("failed to encode booking snapshot: %w", err)
+    }
+    _, err = DB.ExecContext(ctx, `
+        INSERT INTO booking_snapshots (booking_id, version, state, taken_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (booking_id) DO UPDATE
+        SET version = EXCLUDED.version, state = EXCLUDED.state, taken_at = EXCLUDED.taken_at
+        WHERE booking_snapshots.version < EXCLUDED.version`,
+        bookingID,
+        version,
+        encoded,
+        time.Now().UTC(),
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save booking snapshot: %w", err)
+    }
+    return nil
+}
+
+// applyBookingEvents folds events onto a booking's state, field by field. Created and
+// imported events carry the whole booking, so they replace the state rather than change it.
+func applyBookingEvents(state map[string]json.RawMessage, events []models.BookingEvent) error {
+    for _, event := range events {
+        var changes map[string]json.RawMessage
+        if err := json.Unmarshal(event.Changes, &changes); err != nil {
+            return fmt.Errorf("failed to decode booking event %d: %w", event.Version, err)
+        }
+        if event.Type == models.BookingEventCreated || event.Type == models.BookingEventImported {
+            for field := range state {
+                delete(state, field)
+            }
+        }
+        for field, value := range changes {
+            state[field] = value
+        }
+    }
+    return nil
+}
+
+// decodeBookingState turns folded fields into a booking
+func decodeBookingState(state map[string]json.RawMessage) (*models.Booking, error) {
+    encoded, err := json.Marshal(state)
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode booking state: %w", err)
+    }
+    var booking models.Booking
+    if err := json.Unmarshal(encoded, &booking); err != nil {
+        return nil, fmt.Errorf("failed to decode booking state: %w", err)
+    }
+    return &booking, nil
+}
+
+// bookingChanges returns the JSON fields of after that differ from before, with null for
+// fields after no longer has. A nil before returns every field of after.
+func bookingChanges(before, after *models.Booking) (map[string]json.RawMessage, error) {
+    newFields, err := bookingFields(after)
+    if err != nil {
+        return nil, err
+    }
+    if before == nil {
+        return newFields, nil
+    }
+    oldFields, err := bookingFields(before)
+    if err != nil {
+        return nil, err
+    }
+
+    changes := map[string]json.RawMessage{}
+    for field, value := range newFields {
+        if string(oldFields[field]) != string(value) {
+            changes[field] = value
+        }
+    }
+    for field := range oldFields {
+        if _, ok := newFields[field]; !ok {
+            changes[field] = json.RawMessage("null")
+        }
+    }
+    return changes, nil
+}
+
+// bookingFields returns a booking's JSON fields
+func bookingFields(booking *models.Booking) (map[string]json.RawMessage, error) {
+    encoded, err := json.Marshal(booking)
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode booking: %w", err)
+    }
+    var fields map[string]json.RawMessage
+    if err := json.Unmarshal(encoded, &fields); err != nil {
+        return nil, fmt.Errorf("failed to decode booking: %w", err)
+    }
+    return fields, nil
+}
@@ -0,0 +1,97 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrBookingRulesNotFound is returned when a region has no rule set of its own
+var ErrBookingRulesNotFound = errors.New("booking rules not found")
+
+// GetBookingRuleSet retrieves the booking rules saved for a region
+func GetBookingRuleSet(ctx context.Context, region string) (*models.BookingRuleSet, error) {
+    if memory != nil {
+        return memory.getBookingRuleSet(region)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    var (
+        set   models.BookingRuleSet
+        rules []byte
+    )
+    err := DB.QueryRowContext(ctx, `
+        SELECT region, rules, updated_at FROM booking_rules WHERE region = $1`,
+        region,
+    ).Scan(&set.Region, &rules, &set.UpdatedAt)
+    if err == sql.ErrNoRows {
+        return nil, ErrBookingRulesNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get booking rules: %w", err)
+    }
+    if err := json.Unmarshal(rules, &set.Rules); err != nil {
+        return nil, fmt.Errorf("failed to decode booking rules of region %s: %w", region, err)
+    }
+    return &set, nil
+}
+
+// SaveBookingRuleSet creates or replaces a region's booking rules
+func SaveBookingRuleSet(ctx context.Context, set *models.BookingRuleSet) error {
+    if memory != nil {
+        return memory.saveBookingRuleSet(set)
+    }
+
+    rules, err := json.Marshal(set.Rules)
+    if err != nil {
+        return fmt.Errorf("failed to encode booking rules: %w", err)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err = DB.ExecContext(ctx, `
+        INSERT INTO booking_rules (region, rules, updated_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (region) DO UPDATE
+        SET rules = EXCLUDED.rules, updated_at = EXCLUDED.updated_at`,
+        set.Region,
+        rules,
+        set.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save booking rules: %w", err)
+    }
+    return nil
+}
+
+// DeleteBookingRuleSet removes a region's booking rules, so the default rules apply to it again
+func DeleteBookingRuleSet(ctx context.Context, region string) error {
+    if memory != nil {
+        return memory.deleteBookingRuleSet(region)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `DELETE FROM booking_rules WHERE region = $1`, region)
+    if err != nil {
+        return fmt.Errorf("failed to delete booking rules: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to delete booking rules: %w", err)
+    }
+    if rows == 0 {
+        return ErrBookingRulesNotFound
+    }
+    return nil
+}
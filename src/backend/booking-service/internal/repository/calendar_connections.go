@@ -0,0 +1,202 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrCalendarNotConnected is returned when a walker has not connected the calendar
+var ErrCalendarNotConnected = errors.New("calendar not connected")
+
+// SaveCalendarConnection connects a walker's calendar, or stores new tokens for one already connected
+func SaveCalendarConnection(ctx context.Context, connection *models.CalendarConnection) error {
+    if memory != nil {
+        return memory.saveCalendarConnection(connection)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO calendar_connections (walker_id, provider, access_token, refresh_token, expires_at, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (walker_id, provider) DO UPDATE
+        SET access_token = EXCLUDED.access_token, refresh_token = EXCLUDED.refresh_token,
+            expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at`,
+        connection.WalkerID,
+        connection.Provider,
+        connection.AccessToken,
+        connection.RefreshToken,
+        connection.ExpiresAt,
+        connection.CreatedAt,
+        connection.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save calendar connection: %w", err)
+    }
+    return nil
+}
+
+// ListCalendarConnections retrieves the calendars a walker has connected, by provider
+func ListCalendarConnections(ctx context.Context, walkerID string) ([]models.CalendarConnection, error) {
+    if memory != nil {
+        return memory.listCalendarConnections(walkerID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT walker_id, provider, access_token, refresh_token, expires_at, created_at, updated_at
+        FROM calendar_connections
+        WHERE walker_id = $1
+        ORDER BY provider`,
+        walkerID,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list calendar connections: %w", err)
+    }
+    defer rows.Close()
+
+    var connections []models.CalendarConnection
+    for rows.Next() {
+        var c models.CalendarConnection
+        if err := rows.Scan(
+            &c.WalkerID,
+            &c.Provider,
+            &c.AccessToken,
+            &c.RefreshToken,
+            &c.ExpiresAt,
+            &c.CreatedAt,
+            &c.UpdatedAt,
+        ); err != nil {
+            return nil, fmt.Errorf("failed to scan calendar connection: %w", err)
+        }
+        connections = append(connections, c)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list calendar connections: %w", err)
+    }
+    return connections, nil
+}
+
+// DeleteCalendarConnection disconnects a walker's calendar and forgets the events written to
+// it; the events themselves are left in the calendar
+func DeleteCalendarConnection(ctx context.Context, walkerID, provider string) error {
+    if memory != nil {
+        return memory.deleteCalendarConnection(walkerID, provider)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    result, err := tx.ExecContext(ctx, `DELETE FROM calendar_connections WHERE walker_id = $1 AND provider = $2`, walkerID, provider)
+    if err != nil {
+        return fmt.Errorf("failed to delete calendar connection: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to delete calendar connection: %w", err)
+    }
+    if rows == 0 {
+        return ErrCalendarNotConnected
+    }
+
+    if _, err := tx.ExecContext(ctx, `DELETE FROM calendar_events WHERE walker_id = $1 AND provider = $2`, walkerID, provider); err != nil {
+        return fmt.Errorf("failed to delete calendar events: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit transaction: %w", err)
+    }
+    return nil
+}
+
+// ListCalendarEvents retrieves the events written to external calendars for a booking
+func ListCalendarEvents(ctx context.Context, bookingID string) ([]models.CalendarEvent, error) {
+    if memory != nil {
+        return memory.listCalendarEvents(bookingID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT booking_id, walker_id, provider, event_id
+        FROM calendar_events
+        WHERE booking_id = $1`,
+        bookingID,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list calendar events: %w", err)
+    }
+    defer rows.Close()
+
+    var calendarEvents []models.CalendarEvent
+    for rows.Next() {
+        var e models.CalendarEvent
+        if err := rows.Scan(&e.BookingID, &e.WalkerID, &e.Provider, &e.EventID); err != nil {
+            return nil, fmt.Errorf("failed to scan calendar event: %w", err)
+        }
+        calendarEvents = append(calendarEvents, e)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list calendar events: %w", err)
+    }
+    return calendarEvents, nil
+}
+
+// SaveCalendarEvent records the event written for a booking in a walker's calendar
+func SaveCalendarEvent(ctx context.Context, event *models.CalendarEvent) error {
+    if memory != nil {
+        return memory.saveCalendarEvent(event)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO calendar_events (booking_id, walker_id, provider, event_id)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (booking_id, walker_id, provider) DO UPDATE
+        SET event_id = EXCLUDED.event_id`,
+        event.BookingID,
+        event.WalkerID,
+        event.Provider,
+        event.EventID,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save calendar event: %w", err)
+    }
+    return nil
+}
+
+// DeleteCalendarEvent forgets the event written for a booking in a walker's calendar
+func DeleteCalendarEvent(ctx context.Context, bookingID, walkerID, provider string) error {
+    if memory != nil {
+        return memory.deleteCalendarEvent(bookingID, walkerID, provider)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        DELETE FROM calendar_events WHERE booking_id = $1 AND walker_id = $2 AND provider = $3`,
+        bookingID, walkerID, provider,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to delete calendar event: %w", err)
+    }
+    return nil
+}
@@ -0,0 +1,84 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrNotCancellable is returned when a booking has already started, finished or been cancelled
+var ErrNotCancellable = errors.New("booking can no longer be cancelled")
+
+// CancelBooking cancels a pending or confirmed booking and records its cancellation, which fee
+// works out from the locked booking, in the same transaction
+func CancelBooking(ctx context.Context, bookingID string, fee func(booking *models.Booking) *models.Cancellation) (*models.Booking, error) {
+    if memory != nil {
+        return memory.cancelBooking(bookingID, fee)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    booking, err := getBookingForUpdate(ctx, tx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if !booking.IsCancellable() {
+        return nil, ErrNotCancellable
+    }
+
+    booking.Status = models.BookingStatusCancelled
+    booking.Cancellation = fee(booking)
+    _, err = tx.ExecContext(ctx, `
+        UPDATE bookings SET status = $2, cancellation = $3 WHERE id = $1`,
+        booking.ID,
+        booking.Status,
+        booking.Cancellation,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to cancel booking: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, fmt.Errorf("failed to commit cancellation: %w", err)
+    }
+    return booking, nil
+}
+
+// RecordCancellationRefund records the payment-service refund made for a cancelled booking
+func RecordCancellationRefund(ctx context.Context, bookingID, refundID string) error {
+    if memory != nil {
+        return memory.recordCancellationRefund(bookingID, refundID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `
+        UPDATE bookings SET cancellation = jsonb_set(cancellation, '{refund_id}', to_jsonb($2::text))
+        WHERE id = $1 AND cancellation IS NOT NULL`,
+        bookingID,
+        refundID,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to record cancellation refund: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to record cancellation refund: %w", err)
+    }
+    if rows == 0 {
+        return fmt.Errorf("booking not found with id: %s", bookingID)
+    }
+    return nil
+}
@@ -0,0 +1,158 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ListAvailabilityBetween retrieves every walker's availability windows overlapping [from, to)
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ListAvailabilityBetween(ctx context.Context, from, to time.Time) ([]models.Availability, error) {
+    if memory != nil {
+        return memory.listAvailabilityBetween(from, to)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT id, walker_id, starts_at, ends_at, capacity, group_discount_percent, region
+        FROM walker_availability
+        WHERE starts_at < $2 AND ends_at > $1
+        ORDER BY starts_at`,
+        from,
+        to,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list availability: %w", err)
+    }
+    defer rows.Close()
+
+    var windows []models.Availability
+    for rows.Next() {
+        var a models.Availability
+        if err := rows.Scan(
+            &a.ID,
+            &a.WalkerID,
+            &a.StartsAt,
+            &a.EndsAt,
+            &a.Capacity,
+            &a.GroupDiscountPercent,
+            &a.Region,
+        ); err != nil {
+            return nil, fmt.Errorf("failed to scan availability: %w", err)
+        }
+        windows = append(windows, a)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list availability: %w", err)
+    }
+    return windows, nil
+}
+
+// ListActiveBookingsBetween retrieves the active bookings whose walks overlap [from, to),
+// assigned to a walker or not
+func ListActiveBookingsBetween(ctx context.Context, from, to time.Time) ([]models.Booking, error) {
+    if memory != nil {
+        return memory.listActiveBookingsBetween(from, to)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
+        FROM bookings
+        WHERE status = ANY($3)
+          AND scheduled_at < $2
+          AND scheduled_at + make_interval(mins => duration_minutes) > $1
+        ORDER BY scheduled_at`,
+        from,
+        to,
+        pq.Array(activeBookingStatuses),
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list bookings: %w", err)
+    }
+    defer rows.Close()
+
+    var bookings []models.Booking
+    for rows.Next() {
+        var b models.Booking
+        if err := rows.Scan(
+            &b.ID,
+            &b.OwnerID,
+            &b.WalkerID,
+            &b.DogID,
+            &b.ScheduledAt,
+            &b.Status,
+            &b.Amount,
+            &b.DurationMinutes,
+            &b.AcceptBy,
+            &b.Tax,
+            &b.Region,
+            &b.Rate,
+            &b.Cancellation,
+            &b.Latitude,
+            &b.Longitude,
+            &b.Address,
+        ); err != nil {
+            return nil, fmt.Errorf("failed to scan booking: %w", err)
+        }
+        bookings = append(bookings, b)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list bookings: %w", err)
+    }
+    return bookings, nil
+}
+
+// ClaimScheduledReport records that the named report for the period starting at periodStart
+// is being sent. It returns false when another instance has already claimed it.
+func ClaimScheduledReport(ctx context.Context, name string, periodStart, now time.Time) (bool, error) {
+    if memory != nil {
+        return memory.claimScheduledReport(name, periodStart, now)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `
+        INSERT INTO scheduled_reports (name, period_start, sent_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (name, period_start) DO NOTHING`,
+        name,
+        periodStart,
+        now,
+    )
+    if err != nil {
+        return false, fmt.Errorf("failed to claim scheduled report: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return false, fmt.Errorf("failed to claim scheduled report: %w", err)
+    }
+    return rows == 1, nil
+}
+
+// ReleaseScheduledReport removes a claim whose report could not be sent, so it is retried
+func ReleaseScheduledReport(ctx context.Context, name string, periodStart time.Time) error {
+    if memory != nil {
+        return memory.releaseScheduledReport(name, periodStart)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `DELETE FROM scheduled_reports WHERE name = $1 AND period_start = $2`, name, periodStart)
+    if err != nil {
+        return fmt.Errorf("failed to release scheduled report: %w", err)
+    }
+    return nil
+}
@@ -0,0 +1,99 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+)
+
+// SaveUserEmail records the email address a user last authenticated with, so documents such
+// as receipts can be emailed to them later
+func SaveUserEmail(ctx context.Context, userID, email string, at time.Time) error {
+    if memory != nil {
+        return memory.saveUserEmail(userID, email)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO user_emails (user_id, email, updated_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id) DO UPDATE
+        SET email = EXCLUDED.email, updated_at = EXCLUDED.updated_at
+        WHERE user_emails.email <> EXCLUDED.email`,
+        userID,
+        email,
+        at,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save user email: %w", err)
+    }
+    return nil
+}
+
+// GetUserEmail retrieves a user's email address; empty when none has been recorded
+func GetUserEmail(ctx context.Context, userID string) (string, error) {
+    if memory != nil {
+        return memory.getUserEmail(userID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    var email string
+    err := DB.QueryRowContext(ctx, `SELECT email FROM user_emails WHERE user_id = $1`, userID).Scan(&email)
+    if err == sql.ErrNoRows {
+        return "", nil
+    }
+    if err != nil {
+        return "", fmt.Errorf("failed to get user email: %w", err)
+    }
+    return email, nil
+}
+
+// SaveUserPhone records the phone number a user wants texts sent to
+func SaveUserPhone(ctx context.Context, userID, phone string, at time.Time) error {
+    if memory != nil {
+        return memory.saveUserPhone(userID, phone)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO user_phones (user_id, phone, updated_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id) DO UPDATE
+        SET phone = EXCLUDED.phone, updated_at = EXCLUDED.updated_at`,
+        userID,
+        phone,
+        at,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save user phone: %w", err)
+    }
+    return nil
+}
+
+// GetUserPhone retrieves a user's phone number; empty when none has been given
+func GetUserPhone(ctx context.Context, userID string) (string, error) {
+    if memory != nil {
+        return memory.getUserPhone(userID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    var phone string
+    err := DB.QueryRowContext(ctx, `SELECT phone FROM user_phones WHERE user_id = $1`, userID).Scan(&phone)
+    if err == sql.ErrNoRows {
+        return "", nil
+    }
+    if err != nil {
+        return "", fmt.Errorf("failed to get user phone: %w", err)
+    }
+    return phone, nil
+}
@@ -0,0 +1,199 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
+
+    "src/backend/booking-service/internal/models"
+)
+
+// Dispute errors
+var (
+    // ErrDisputeNotFound is returned when no dispute matches
+    ErrDisputeNotFound = errors.New("dispute not found")
+
+    // ErrDisputeExists is returned when the booking has already been disputed
+    ErrDisputeExists = errors.New("booking has already been disputed")
+
+    // ErrDisputeStatusChanged is returned when a dispute moved on before an update was applied
+    ErrDisputeStatusChanged = errors.New("dispute status has changed")
+)
+
+// disputeColumns is the column list scanned by scanDispute
+const disputeColumns = `id, booking_id, owner_id, reason, status, resolution, reviewed_by, refund_amount, refund_id, created_at, resolved_at`
+
+// scanDispute scans a row selected with disputeColumns
+func scanDispute(row interface{ Scan(...interface{}) error }) (*models.Dispute, error) {
+    dispute := &models.Dispute{}
+    err := row.Scan(
+        &dispute.ID,
+        &dispute.BookingID,
+        &dispute.OwnerID,
+        &dispute.Reason,
+        &dispute.Status,
+        &dispute.Resolution,
+        &dispute.ReviewedBy,
+        &dispute.RefundAmount,
+        &dispute.RefundID,
+        &dispute.CreatedAt,
+        &dispute.ResolvedAt,
+    )
+    if err != nil {
+        return nil, err
+    }
+    return dispute, nil
+}
+
+// CreateDispute stores a newly raised dispute; ErrDisputeExists means the booking already has one
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func CreateDispute(ctx context.Context, dispute *models.Dispute) error {
+    if memory != nil {
+        return memory.createDispute(dispute)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO disputes (id, booking_id, owner_id, reason, status, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+        dispute.ID,
+        dispute.BookingID,
+        dispute.OwnerID,
+        dispute.Reason,
+        dispute.Status,
+        dispute.CreatedAt,
+    )
+    var pqErr *pq.Error
+    if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+        return ErrDisputeExists
+    }
+    if err != nil {
+        return fmt.Errorf("failed to create dispute: %w", err)
+    }
+    return nil
+}
+
+// GetDispute retrieves a dispute by ID
+func GetDispute(ctx context.Context, id string) (*models.Dispute, error) {
+    if memory != nil {
+        return memory.getDispute(id)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    dispute, err := scanDispute(DB.QueryRowContext(ctx, `SELECT `+disputeColumns+` FROM disputes WHERE id = $1`, id))
+    if err == sql.ErrNoRows {
+        return nil, ErrDisputeNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get dispute: %w", err)
+    }
+    return dispute, nil
+}
+
+// GetDisputeForBooking retrieves the dispute raised for a booking
+func GetDisputeForBooking(ctx context.Context, bookingID string) (*models.Dispute, error) {
+    if memory != nil {
+        return memory.getDisputeForBooking(bookingID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    dispute, err := scanDispute(DB.QueryRowContext(ctx, `SELECT `+disputeColumns+` FROM disputes WHERE booking_id = $1`, bookingID))
+    if err == sql.ErrNoRows {
+        return nil, ErrDisputeNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get dispute: %w", err)
+    }
+    return dispute, nil
+}
+
+// ListDisputes retrieves up to limit disputes, oldest first so the review queue is worked in
+// order. An empty status matches every dispute.
+func ListDisputes(ctx context.Context, status models.DisputeStatus, limit int) ([]models.Dispute, error) {
+    if memory != nil {
+        return memory.listDisputes(status, limit)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT `+disputeColumns+`
+        FROM disputes
+        WHERE ($1 = '' OR status = $1)
+        ORDER BY created_at
+        LIMIT $2`,
+        status,
+        limit,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list disputes: %w", err)
+    }
+    defer rows.Close()
+
+    var disputes []models.Dispute
+    for rows.Next() {
+        dispute, err := scanDispute(rows)
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan dispute: %w", err)
+        }
+        disputes = append(disputes, *dispute)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list disputes: %w", err)
+    }
+    return disputes, nil
+}
+
+// UpdateDispute writes a dispute's review fields, provided it is still in one of the from
+// statuses; otherwise ErrDisputeStatusChanged is returned and nothing is written. This keeps
+// two admins from resolving, and refunding, the same dispute.
+func UpdateDispute(ctx context.Context, dispute *models.Dispute, from ...models.DisputeStatus) error {
+    if memory != nil {
+        return memory.updateDispute(dispute, from)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    statuses := make([]string, len(from))
+    for i, status := range from {
+        statuses[i] = string(status)
+    }
+
+    result, err := DB.ExecContext(ctx, `
+        UPDATE disputes
+        SET status = $2, resolution = $3, reviewed_by = $4, refund_amount = $5, refund_id = $6, resolved_at = $7
+        WHERE id = $1 AND status = ANY($8)`,
+        dispute.ID,
+        dispute.Status,
+        dispute.Resolution,
+        dispute.ReviewedBy,
+        dispute.RefundAmount,
+        dispute.RefundID,
+        dispute.ResolvedAt,
+        pq.Array(statuses),
+    )
+    if err != nil {
+        return fmt.Errorf("failed to update dispute: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to update dispute: %w", err)
+    }
+    if rows == 0 {
+        return ErrDisputeStatusChanged
+    }
+    return nil
+}
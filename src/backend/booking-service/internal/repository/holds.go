@@ -0,0 +1,145 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrHoldNotFound is returned when no slot hold exists with the requested ID for the owner
+var ErrHoldNotFound = errors.New("slot hold not found")
+
+// CreateSlotHold holds a place in the walker's schedule unless they already have capacity
+// bookings and holds overlapping it, under the same per-walker lock bookings are made with
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CreateSlotHold(ctx context.Context, hold *models.SlotHold, capacity int) error {
+    if memory != nil {
+        return memory.createSlotHold(hold, capacity)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    if err := lockWalker(ctx, tx, hold.WalkerID); err != nil {
+        return err
+    }
+
+    overlapping, err := countOverlapping(ctx, tx, hold.WalkerID, hold.ScheduledAt, hold.EndsAt(), "")
+    if err != nil {
+        return err
+    }
+    if overlapping >= capacity {
+        return ErrSlotFull
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        INSERT INTO slot_holds (
+            id, owner_id, walker_id, region, scheduled_at, duration_minutes, quote, expires_at, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9
+        )`,
+        hold.ID,
+        hold.OwnerID,
+        hold.WalkerID,
+        hold.Region,
+        hold.ScheduledAt,
+        hold.DurationMinutes,
+        hold.Quote,
+        hold.ExpiresAt,
+        hold.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create slot hold: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit slot hold: %w", err)
+    }
+    return nil
+}
+
+// GetSlotHold retrieves a slot hold by its ID, whether or not it has expired
+func GetSlotHold(ctx context.Context, id string) (*models.SlotHold, error) {
+    if memory != nil {
+        return memory.getSlotHold(id)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    hold := &models.SlotHold{}
+    err := DB.QueryRowContext(ctx, `
+        SELECT id, owner_id, walker_id, region, scheduled_at, duration_minutes, quote, expires_at, created_at
+        FROM slot_holds
+        WHERE id = $1`,
+        id,
+    ).Scan(
+        &hold.ID,
+        &hold.OwnerID,
+        &hold.WalkerID,
+        &hold.Region,
+        &hold.ScheduledAt,
+        &hold.DurationMinutes,
+        &hold.Quote,
+        &hold.ExpiresAt,
+        &hold.CreatedAt,
+    )
+    if err == sql.ErrNoRows {
+        return nil, ErrHoldNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get slot hold: %w", err)
+    }
+    return hold, nil
+}
+
+// DeleteSlotHold releases the owner's hold on a slot
+func DeleteSlotHold(ctx context.Context, id, ownerID string) error {
+    if memory != nil {
+        return memory.deleteSlotHold(id, ownerID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `DELETE FROM slot_holds WHERE id = $1 AND owner_id = $2`, id, ownerID)
+    if err != nil {
+        return fmt.Errorf("failed to delete slot hold: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to delete slot hold: %w", err)
+    }
+    if rows == 0 {
+        return ErrHoldNotFound
+    }
+    return nil
+}
+
+// DeleteExpiredSlotHolds removes the holds that lapsed by now. Expired holds already stop
+// counting against capacity; this only keeps the table small.
+func DeleteExpiredSlotHolds(ctx context.Context, now time.Time) (int64, error) {
+    if memory != nil {
+        return memory.deleteExpiredSlotHolds(now), nil
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `DELETE FROM slot_holds WHERE expires_at <= $1`, now)
+    if err != nil {
+        return 0, fmt.Errorf("failed to delete expired slot holds: %w", err)
+    }
+    return result.RowsAffected()
+}
@@ -0,0 +1,101 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrHolidayNotFound is returned when a region's holiday calendar has no entry for a date
+var ErrHolidayNotFound = errors.New("holiday not found")
+
+// ListHolidays retrieves a region's holiday calendar in date order
+func ListHolidays(ctx context.Context, region string) ([]models.Holiday, error) {
+    if memory != nil {
+        return memory.listHolidays(region)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT region, date::text, name, kind, surcharge_percent, time_zone, updated_at
+        FROM holidays
+        WHERE region = $1
+        ORDER BY date`,
+        region,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list holidays: %w", err)
+    }
+    defer rows.Close()
+
+    var holidays []models.Holiday
+    for rows.Next() {
+        var h models.Holiday
+        if err := rows.Scan(&h.Region, &h.Date, &h.Name, &h.Kind, &h.SurchargePercent, &h.TimeZone, &h.UpdatedAt); err != nil {
+            return nil, fmt.Errorf("failed to scan holiday: %w", err)
+        }
+        holidays = append(holidays, h)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to read holidays: %w", err)
+    }
+    return holidays, nil
+}
+
+// SaveHoliday creates or replaces the entry for a date in a region's holiday calendar
+func SaveHoliday(ctx context.Context, holiday *models.Holiday) error {
+    if memory != nil {
+        return memory.saveHoliday(holiday)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO holidays (region, date, name, kind, surcharge_percent, time_zone, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (region, date) DO UPDATE
+        SET name = EXCLUDED.name, kind = EXCLUDED.kind, surcharge_percent = EXCLUDED.surcharge_percent,
+            time_zone = EXCLUDED.time_zone, updated_at = EXCLUDED.updated_at`,
+        holiday.Region,
+        holiday.Date,
+        holiday.Name,
+        holiday.Kind,
+        holiday.SurchargePercent,
+        holiday.TimeZone,
+        holiday.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save holiday: %w", err)
+    }
+    return nil
+}
+
+// DeleteHoliday removes a date from a region's holiday calendar
+func DeleteHoliday(ctx context.Context, region, date string) error {
+    if memory != nil {
+        return memory.deleteHoliday(region, date)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `DELETE FROM holidays WHERE region = $1 AND date = $2`, region, date)
+    if err != nil {
+        return fmt.Errorf("failed to delete holiday: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to delete holiday: %w", err)
+    }
+    if rows == 0 {
+        return ErrHolidayNotFound
+    }
+    return nil
+}
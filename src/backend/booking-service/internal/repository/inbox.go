@@ -0,0 +1,212 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrInboxNotificationNotFound is returned when the user has no notification with the ID
+var ErrInboxNotificationNotFound = errors.New("notification not found")
+
+// inboxColumns are the inbox_notifications columns read by scanInboxNotification
+const inboxColumns = `id, user_id, category, subject, body, data, read_at, created_at`
+
+// CreateInboxNotification adds a notification to a user's notification center
+func CreateInboxNotification(ctx context.Context, n *models.InboxNotification) error {
+    if memory != nil {
+        return memory.createInboxNotification(n)
+    }
+
+    var data []byte
+    if len(n.Data) > 0 {
+        var err error
+        if data, err = json.Marshal(n.Data); err != nil {
+            return fmt.Errorf("failed to encode notification data: %w", err)
+        }
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO inbox_notifications (id, user_id, category, subject, body, data, read_at, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+        n.ID,
+        n.UserID,
+        n.Category,
+        n.Subject,
+        n.Body,
+        data,
+        n.ReadAt,
+        n.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create inbox notification: %w", err)
+    }
+    return nil
+}
+
+// GetInboxNotification retrieves one of a user's notifications
+func GetInboxNotification(ctx context.Context, userID, id string) (*models.InboxNotification, error) {
+    if memory != nil {
+        return memory.getInboxNotification(userID, id)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    n, err := scanInboxNotification(DB.QueryRowContext(ctx, `
+        SELECT `+inboxColumns+` FROM inbox_notifications WHERE user_id = $1 AND id = $2`,
+        userID, id,
+    ))
+    if err == sql.ErrNoRows {
+        return nil, ErrInboxNotificationNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get inbox notification: %w", err)
+    }
+    return n, nil
+}
+
+// ListInboxNotifications returns up to limit of a user's notifications, newest first, starting
+// after the notification with ID before when set; only unread ones when unreadOnly is set
+func ListInboxNotifications(ctx context.Context, userID, before string, unreadOnly bool, limit int) ([]models.InboxNotification, error) {
+    if memory != nil {
+        return memory.listInboxNotifications(userID, before, unreadOnly, limit)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT `+inboxColumns+`
+        FROM inbox_notifications
+        WHERE user_id = $1
+          AND ($2 = '' OR (created_at, id) < (
+              SELECT created_at, id FROM inbox_notifications WHERE user_id = $1 AND id = $2))
+          AND (NOT $3 OR read_at IS NULL)
+        ORDER BY created_at DESC, id DESC
+        LIMIT $4`,
+        userID, before, unreadOnly, limit,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list inbox notifications: %w", err)
+    }
+    defer rows.Close()
+
+    var notifications []models.InboxNotification
+    for rows.Next() {
+        n, err := scanInboxNotification(rows)
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan inbox notification: %w", err)
+        }
+        notifications = append(notifications, *n)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list inbox notifications: %w", err)
+    }
+    return notifications, nil
+}
+
+// CountUnreadInboxNotifications counts a user's unread notifications
+func CountUnreadInboxNotifications(ctx context.Context, userID string) (int, error) {
+    if memory != nil {
+        return memory.countUnreadInboxNotifications(userID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    var count int
+    err := DB.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM inbox_notifications WHERE user_id = $1 AND read_at IS NULL`,
+        userID,
+    ).Scan(&count)
+    if err != nil {
+        return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+    }
+    return count, nil
+}
+
+// MarkInboxNotificationsRead marks a user's unread notifications with the given IDs read at
+// the given time, or all of them when ids is nil, returning how many were marked
+func MarkInboxNotificationsRead(ctx context.Context, userID string, ids []string, at time.Time) (int64, error) {
+    if memory != nil {
+        return memory.markInboxNotificationsRead(userID, ids, at)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    var (
+        result sql.Result
+        err    error
+    )
+    if ids == nil {
+        result, err = DB.ExecContext(ctx, `
+            UPDATE inbox_notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL`,
+            userID, at,
+        )
+    } else {
+        result, err = DB.ExecContext(ctx, `
+            UPDATE inbox_notifications SET read_at = $3
+            WHERE user_id = $1 AND id = ANY($2) AND read_at IS NULL`,
+            userID, pq.Array(ids), at,
+        )
+    }
+    if err != nil {
+        return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+    }
+    marked, err := result.RowsAffected()
+    if err != nil {
+        return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+    }
+    return marked, nil
+}
+
+// DeleteInboxNotificationsBefore removes notifications created before cutoff, returning how
+// many were removed
+func DeleteInboxNotificationsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+    if memory != nil {
+        return memory.deleteInboxNotificationsBefore(cutoff)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `DELETE FROM inbox_notifications WHERE created_at < $1`, cutoff)
+    if err != nil {
+        return 0, fmt.Errorf("failed to delete inbox notifications: %w", err)
+    }
+    deleted, err := result.RowsAffected()
+    if err != nil {
+        return 0, fmt.Errorf("failed to delete inbox notifications: %w", err)
+    }
+    return deleted, nil
+}
+
+// scanInboxNotification reads a row of inboxColumns
+func scanInboxNotification(row interface{ Scan(...interface{}) error }) (*models.InboxNotification, error) {
+    var (
+        n    models.InboxNotification
+        data []byte
+    )
+    if err := row.Scan(&n.ID, &n.UserID, &n.Category, &n.Subject, &n.Body, &data, &n.ReadAt, &n.CreatedAt); err != nil {
+        return nil, err
+    }
+    if len(data) > 0 {
+        if err := json.Unmarshal(data, &n.Data); err != nil {
+            return nil, fmt.Errorf("failed to decode notification data: %w", err)
+        }
+    }
+    return &n, nil
+}
@@ -0,0 +1,221 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "database/sql/driver"
+    "errors"
+    "fmt"
+    "reflect"
+    "strings"
+    "time"
+
+    "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
+
+    "src/backend/shared/dbmetrics"
+)
+
+// statements records the duration of every statement sent to the database; nil until InitDB
+var statements *dbmetrics.Recorder
+
+// openDB opens a connection pool whose statements, including those run in transactions, are
+// recorded by statements
+func openDB(dsn string) (*sql.DB, error) {
+    connector, err := pq.NewConnector(dsn)
+    if err != nil {
+        return nil, err
+    }
+    return sql.OpenDB(instrumentedConnector{connector}), nil
+}
+
+// observeStatement records a statement run with args that started at start
+func observeStatement(query string, args []driver.NamedValue, start time.Time, err error) {
+    if statements == nil {
+        return
+    }
+    if errors.Is(err, driver.ErrSkip) {
+        // database/sql retries the statement another way, which is recorded instead
+        return
+    }
+    statements.Observe(statementKind(query), time.Since(start), err, func() string {
+        return fmt.Sprintf("%s %s", strings.Join(strings.Fields(query), " "), sanitizeArgs(args))
+    })
+}
+
+// statementKeywords are the keywords after which a statement names the table it works on
+var statementKeywords = map[string]bool{"FROM": true, "INTO": true, "UPDATE": true, "TABLE": true}
+
+// statementKind labels a statement by its verb and the first table it names, such as
+// "SELECT bookings", so statements can be told apart without a series per query text
+func statementKind(query string) string {
+    words := strings.Fields(query)
+    if len(words) == 0 {
+        return "EMPTY"
+    }
+    verb := strings.ToUpper(words[0])
+    for i, word := range words[:len(words)-1] {
+        if statementKeywords[strings.ToUpper(word)] {
+            table := strings.Trim(words[i+1], `"(),;`)
+            if table != "" && !strings.HasPrefix(table, "$") {
+                return verb + " " + strings.ToLower(table)
+            }
+        }
+    }
+    return verb
+}
+
+// sanitizeArgs describes a statement's arguments by type and size only, as they hold owners'
+// and walkers' details
+func sanitizeArgs(args []driver.NamedValue) string {
+    described := make([]string, len(args))
+    for i, arg := range args {
+        switch v := arg.Value.(type) {
+        case nil:
+            described[i] = fmt.Sprintf("$%d=NULL", arg.Ordinal)
+        case string:
+            described[i] = fmt.Sprintf("$%d=string(%d)", arg.Ordinal, len(v))
+        case []byte:
+            described[i] = fmt.Sprintf("$%d=bytes(%d)", arg.Ordinal, len(v))
+        default:
+            described[i] = fmt.Sprintf("$%d=%s", arg.Ordinal, reflect.TypeOf(v))
+        }
+    }
+    return "[" + strings.Join(described, " ") + "]"
+}
+
+// instrumentedConnector opens connections whose statements are recorded
+type instrumentedConnector struct {
+    driver.Connector
+}
+
+// Connect opens an instrumented connection
+func (c instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+    conn, err := c.Connector.Connect(ctx)
+    if err != nil {
+        return nil, err
+    }
+    return &instrumentedConn{conn}, nil
+}
+
+// instrumentedConn records the statements run on a connection, passing everything else through
+type instrumentedConn struct {
+    driver.Conn
+}
+
+// QueryContext records a query run without preparing it
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+    queryer, ok := c.Conn.(driver.QueryerContext)
+    if !ok {
+        return nil, driver.ErrSkip
+    }
+    start := time.Now()
+    rows, err := queryer.QueryContext(ctx, query, args)
+    observeStatement(query, args, start, err)
+    return rows, err
+}
+
+// ExecContext records a statement run without preparing it
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+    execer, ok := c.Conn.(driver.ExecerContext)
+    if !ok {
+        return nil, driver.ErrSkip
+    }
+    start := time.Now()
+    result, err := execer.ExecContext(ctx, query, args)
+    observeStatement(query, args, start, err)
+    return result, err
+}
+
+// PrepareContext prepares a statement whose executions are recorded
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+    var stmt driver.Stmt
+    var err error
+    if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+        stmt, err = preparer.PrepareContext(ctx, query)
+    } else {
+        if err := ctx.Err(); err != nil {
+            return nil, err
+        }
+        stmt, err = c.Conn.Prepare(query)
+    }
+    if err != nil {
+        return nil, err
+    }
+    return &instrumentedStmt{Stmt: stmt, query: query}, nil
+}
+
+// BeginTx starts a transaction, whose statements are run on this connection
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+    if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+        return beginner.BeginTx(ctx, opts)
+    }
+    if opts.Isolation != 0 || opts.ReadOnly {
+        return nil, errors.New("driver does not support transaction options")
+    }
+    return c.Conn.Begin()
+}
+
+// Ping checks the connection is alive
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+    if pinger, ok := c.Conn.(driver.Pinger); ok {
+        return pinger.Ping(ctx)
+    }
+    return nil
+}
+
+// instrumentedStmt records the executions of a prepared statement
+type instrumentedStmt struct {
+    driver.Stmt
+    query string
+}
+
+// ExecContext records an execution of the statement
+func (s *instrumentedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+    start := time.Now()
+    var result driver.Result
+    var err error
+    if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+        result, err = execer.ExecContext(ctx, args)
+    } else {
+        var values []driver.Value
+        if values, err = namedValues(ctx, args); err == nil {
+            result, err = s.Stmt.Exec(values)
+        }
+    }
+    observeStatement(s.query, args, start, err)
+    return result, err
+}
+
+// QueryContext records an execution of the statement
+func (s *instrumentedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+    start := time.Now()
+    var rows driver.Rows
+    var err error
+    if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+        rows, err = queryer.QueryContext(ctx, args)
+    } else {
+        var values []driver.Value
+        if values, err = namedValues(ctx, args); err == nil {
+            rows, err = s.Stmt.Query(values)
+        }
+    }
+    observeStatement(s.query, args, start, err)
+    return rows, err
+}
+
+// namedValues converts arguments for a statement that predates contexts, which only takes
+// positional ones
+func namedValues(ctx context.Context, args []driver.NamedValue) ([]driver.Value, error) {
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+    values := make([]driver.Value, len(args))
+    for i, arg := range args {
+        if arg.Name != "" {
+            return nil, fmt.Errorf("driver does not support named parameter %s", arg.Name)
+        }
+        values[i] = arg.Value
+    }
+    return values, nil
+}
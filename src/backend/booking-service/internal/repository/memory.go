@@ -0,0 +1,2033 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "encoding/json"
+    "fmt"
+    "sort"
+    "sync"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/shared/regions"
+)
+
+// memory replaces PostgreSQL when the service runs with STORE=memory; nil means PostgreSQL
+var memory *memoryStore
+
+// UseMemoryStore serves every repository function from an empty in-process store instead of
+// PostgreSQL. Nothing survives a restart, so it is meant for local development and CI only.
+func UseMemoryStore() {
+    memory = newMemoryStore()
+}
+
+// memoryStore holds the booking tables in maps. A single mutex stands in for the row locks
+// and per-walker advisory locks used with PostgreSQL, so capacity checks stay race free.
+type memoryStore struct {
+    mu            sync.Mutex
+    bookings      map[string]models.Booking
+    availability  []models.Availability
+    changes       map[string]models.BookingChange
+    referralCodes map[string]models.ReferralCode // keyed by code
+    referrals     map[string]models.Referral     // keyed by referee ID
+    declines      map[string]map[string]string   // booking ID -> walker ID -> reason
+    audit         []models.AuditEntry
+    apiKeys       map[string]models.APIKey // keyed by ID
+    walkers       map[string]models.WalkerVerification
+    runs          map[string]models.ReconciliationRun           // keyed by ID
+    discrepancies map[string][]models.ReconciliationDiscrepancy // keyed by run ID
+    emails        map[string]string                             // keyed by user ID
+    tips          map[string]models.Tip                         // keyed by booking ID
+    disputes      map[string]models.Dispute                     // keyed by ID
+    reports       map[string]time.Time                          // sent time keyed by report name and period
+    reportRuns    map[string]models.ReportRun                   // keyed by ID
+    regions       map[string]regions.Region                     // keyed by ID
+    ratePlans     map[string]models.RatePlan                    // keyed by walker ID
+    bookingRules  map[string]models.BookingRuleSet              // keyed by region
+    holidays      map[string]models.Holiday                     // keyed by region and date
+    shifts        map[string]models.WalkerShift                 // keyed by booking ID
+    calendars     map[string]models.CalendarConnection          // keyed by walker ID and provider
+    calendarItems map[string]models.CalendarEvent               // keyed by booking ID, walker ID and provider
+    devices       map[string]models.Device                      // keyed by token
+    preferences   map[string]models.NotificationPreferences     // keyed by user ID
+    deliveries    []models.DeliveryReceipt
+    phones        map[string]string           // keyed by user ID
+    smsOptOuts    map[string]models.SMSOptOut // keyed by phone
+    inbox         []models.InboxNotification  // oldest first
+    sagas         map[string]models.Saga      // keyed by ID
+    eventSourced  bool
+    bookingEvents map[string][]models.BookingEvent // keyed by booking ID, oldest first
+    attachments   map[string][]sealedAttachment    // keyed by booking ID, oldest first
+    holds         map[string]models.SlotHold       // keyed by ID
+}
+
+// newMemoryStore creates an empty memoryStore
+func newMemoryStore() *memoryStore {
+    return &memoryStore{
+        bookings:      make(map[string]models.Booking),
+        changes:       make(map[string]models.BookingChange),
+        referralCodes: make(map[string]models.ReferralCode),
+        referrals:     make(map[string]models.Referral),
+        declines:      make(map[string]map[string]string),
+        apiKeys:       make(map[string]models.APIKey),
+        walkers:       make(map[string]models.WalkerVerification),
+        runs:          make(map[string]models.ReconciliationRun),
+        discrepancies: make(map[string][]models.ReconciliationDiscrepancy),
+        emails:        make(map[string]string),
+        tips:          make(map[string]models.Tip),
+        disputes:      make(map[string]models.Dispute),
+        reports:       make(map[string]time.Time),
+        reportRuns:    make(map[string]models.ReportRun),
+        regions:       make(map[string]regions.Region),
+        ratePlans:     make(map[string]models.RatePlan),
+        bookingRules:  make(map[string]models.BookingRuleSet),
+        holidays:      make(map[string]models.Holiday),
+        shifts:        make(map[string]models.WalkerShift),
+        calendars:     make(map[string]models.CalendarConnection),
+        calendarItems: make(map[string]models.CalendarEvent),
+        devices:       make(map[string]models.Device),
+        preferences:   make(map[string]models.NotificationPreferences),
+        phones:        make(map[string]string),
+        smsOptOuts:    make(map[string]models.SMSOptOut),
+        sagas:         make(map[string]models.Saga),
+        bookingEvents: make(map[string][]models.BookingEvent),
+        attachments:   make(map[string][]sealedAttachment),
+        holds:         make(map[string]models.SlotHold),
+    }
+}
+
+// putBooking stores a booking, recording what changed as an event while event sourcing is
+// enabled. The caller must hold m.mu.
+func (m *memoryStore) putBooking(booking models.Booking) {
+    if m.eventSourced {
+        var before *models.Booking
+        if stored, ok := m.bookings[booking.ID]; ok {
+            before = &stored
+        }
+        m.recordBookingEvent(before, &booking)
+    }
+    m.bookings[booking.ID] = booking
+}
+
+// recordBookingEvent appends the change from before to after to the booking's events, the
+// same way the PostgreSQL trigger does. The caller must hold m.mu.
+func (m *memoryStore) recordBookingEvent(before, after *models.Booking) {
+    changes, err := bookingChanges(before, after)
+    if err != nil || len(changes) == 0 {
+        return
+    }
+    eventType := models.BookingEventUpdated
+    switch {
+    case before == nil:
+        eventType = models.BookingEventCreated
+    case changes["status"] != nil:
+        eventType = models.BookingEventStatusChanged
+    }
+    m.appendBookingEvent(after.ID, eventType, changes)
+}
+
+// appendBookingEvent adds the next event of a booking. The caller must hold m.mu.
+func (m *memoryStore) appendBookingEvent(bookingID, eventType string, changes map[string]json.RawMessage) {
+    encoded, _ := json.Marshal(changes)
+    events := m.bookingEvents[bookingID]
+    m.bookingEvents[bookingID] = append(events, models.BookingEvent{
+        BookingID:  bookingID,
+        Version:    len(events) + 1,
+        Type:       eventType,
+        Changes:    encoded,
+        RecordedAt: time.Now().UTC(),
+    })
+}
+
+// isActive reports whether a booking status occupies a walker's capacity
+func isActive(status models.BookingStatus) bool {
+    for _, active := range activeBookingStatuses {
+        if status == active {
+            return true
+        }
+    }
+    return false
+}
+
+// countOverlapping counts the walker's active bookings overlapping [start, end), ignoring excludeID.
+// The caller must hold m.mu.
+func (m *memoryStore) countOverlapping(walkerID string, start, end time.Time, excludeID string) int {
+    overlapping := 0
+    for _, b := range m.bookings {
+        if b.WalkerID == walkerID && b.ID != excludeID && isActive(b.Status) &&
+            b.ScheduledAt.Before(end) && b.EndsAt().After(start) {
+            overlapping++
+        }
+    }
+    now := time.Now()
+    for _, h := range m.holds {
+        if h.WalkerID == walkerID && h.ID != excludeID && h.ExpiresAt.After(now) &&
+            h.ScheduledAt.Before(end) && h.EndsAt().After(start) {
+            overlapping++
+        }
+    }
+    return overlapping
+}
+
+func (m *memoryStore) createBooking(booking *models.Booking) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, exists := m.bookings[booking.ID]; exists {
+        return fmt.Errorf("failed to create booking: duplicate id %s", booking.ID)
+    }
+    m.putBooking(*booking)
+    return nil
+}
+
+func (m *memoryStore) getBookingByID(id string) (*models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if m.eventSourced {
+        return m.foldBookingLocked(id, 0)
+    }
+    booking, ok := m.bookings[id]
+    if !ok {
+        return nil, fmt.Errorf("booking not found with id: %s", id)
+    }
+    return &booking, nil
+}
+
+func (m *memoryStore) listBookings(filter models.BookingFilter, after *models.BookingCursor, limit int) ([]models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var bookings []models.Booking
+    for _, b := range m.bookings {
+        if filter.Matches(&b) && (after == nil || !after.Precedes(&b)) {
+            bookings = append(bookings, b)
+        }
+    }
+
+    sort.Slice(bookings, func(i, j int) bool {
+        if !bookings[i].ScheduledAt.Equal(bookings[j].ScheduledAt) {
+            return bookings[i].ScheduledAt.After(bookings[j].ScheduledAt)
+        }
+        return bookings[i].ID > bookings[j].ID
+    })
+    if len(bookings) > limit {
+        bookings = bookings[:limit]
+    }
+    return bookings, nil
+}
+
+func (m *memoryStore) summarizeBookings(filter models.BookingFilter) (*models.BookingSummary, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    summary := models.NewBookingSummary()
+    for _, b := range m.bookings {
+        if filter.Matches(&b) {
+            summary.Add(b.Status, 1)
+        }
+    }
+    return summary, nil
+}
+
+func (m *memoryStore) updateBookingDetails(original, updated *models.Booking) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    stored, ok := m.bookings[original.ID]
+    if !ok {
+        return fmt.Errorf("booking not found with id: %s", original.ID)
+    }
+    if detailsChanged(original, &stored) {
+        return ErrBookingModified
+    }
+
+    stored.DogID = updated.DogID
+    stored.ScheduledAt = updated.ScheduledAt
+    stored.DurationMinutes = updated.DurationMinutes
+    stored.Latitude, stored.Longitude = updated.Latitude, updated.Longitude
+    stored.Address = updated.Address
+    stored.Region = updated.Region
+    stored.Amount = updated.Amount
+    stored.Tax = updated.Tax
+    m.putBooking(stored)
+    return nil
+}
+
+func (m *memoryStore) createBookingWithinCapacity(booking *models.Booking, capacity int, adjust func(booking *models.Booking, overlapping int) error) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    overlapping := m.countOverlapping(booking.WalkerID, booking.ScheduledAt, booking.EndsAt(), booking.HoldID)
+    if overlapping >= capacity {
+        return ErrSlotFull
+    }
+    if adjust != nil {
+        if err := adjust(booking, overlapping); err != nil {
+            return err
+        }
+    }
+
+    if _, exists := m.bookings[booking.ID]; exists {
+        return fmt.Errorf("failed to create booking: duplicate id %s", booking.ID)
+    }
+    m.putBooking(*booking)
+    delete(m.holds, booking.HoldID)
+    return nil
+}
+
+func (m *memoryStore) createAvailability(availability *models.Availability) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.availability = append(m.availability, *availability)
+    return nil
+}
+
+func (m *memoryStore) getAvailabilityCovering(walkerID string, start, end time.Time) (*models.Availability, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var covering *models.Availability
+    for i := range m.availability {
+        a := m.availability[i]
+        if a.WalkerID != walkerID || a.StartsAt.After(start) || a.EndsAt.Before(end) {
+            continue
+        }
+        if covering == nil || a.StartsAt.After(covering.StartsAt) {
+            covering = &a
+        }
+    }
+    return covering, nil
+}
+
+func (m *memoryStore) listAvailability(walkerID string, from time.Time) ([]models.Availability, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var windows []models.Availability
+    for _, a := range m.availability {
+        if a.WalkerID == walkerID && a.EndsAt.After(from) {
+            windows = append(windows, a)
+        }
+    }
+    sort.Slice(windows, func(i, j int) bool { return windows[i].StartsAt.Before(windows[j].StartsAt) })
+    return windows, nil
+}
+
+func (m *memoryStore) overrideBooking(bookingID string, entry *models.AuditEntry, capacity int, mutate func(booking *models.Booking) error) (*models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    stored, ok := m.bookings[bookingID]
+    if !ok {
+        return nil, fmt.Errorf("booking not found with id: %s", bookingID)
+    }
+    booking := stored
+
+    before, err := json.Marshal(booking)
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode booking: %w", err)
+    }
+
+    if err := mutate(&booking); err != nil {
+        return nil, err
+    }
+
+    if capacity > 0 && booking.WalkerID != stored.WalkerID {
+        if m.countOverlapping(booking.WalkerID, booking.ScheduledAt, booking.EndsAt(), booking.ID) >= capacity {
+            return nil, ErrSlotFull
+        }
+    }
+
+    after, err := json.Marshal(booking)
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode booking: %w", err)
+    }
+
+    // Only the columns an override may change are written, as with PostgreSQL
+    stored.WalkerID = booking.WalkerID
+    stored.Status = booking.Status
+    stored.Amount = booking.Amount
+    stored.Tax = booking.Tax
+    m.putBooking(stored)
+
+    entry.BookingID = booking.ID
+    entry.Before = before
+    entry.After = after
+    m.audit = append(m.audit, *entry)
+    return &booking, nil
+}
+
+func (m *memoryStore) bulkSetStatus(filter models.BulkStatusFilter, status models.BookingStatus, limit int, newEntry func() (*models.AuditEntry, error)) (*models.BulkStatusReport, []models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var matched []models.Booking
+    for _, b := range m.bookings {
+        if b.WalkerID == filter.WalkerID && !b.ScheduledAt.Before(filter.From) && b.ScheduledAt.Before(filter.To) {
+            matched = append(matched, b)
+        }
+    }
+    if len(matched) > limit {
+        return nil, nil, ErrTooManyBookings
+    }
+    sort.Slice(matched, func(i, j int) bool {
+        if !matched[i].ScheduledAt.Equal(matched[j].ScheduledAt) {
+            return matched[i].ScheduledAt.Before(matched[j].ScheduledAt)
+        }
+        return matched[i].ID < matched[j].ID
+    })
+
+    // Changes are staged so that a failure part way leaves every booking as it was
+    report := &models.BulkStatusReport{Results: make([]models.BulkStatusResult, 0, len(matched))}
+    var changed []models.Booking
+    var entries []models.AuditEntry
+    for i := range matched {
+        booking := &matched[i]
+        result := planBulkStatus(booking, filter.Statuses, status)
+        report.Results = append(report.Results, result)
+        if result.Outcome == models.BulkStatusSkipped {
+            report.Skipped++
+            continue
+        }
+        report.Updated++
+
+        entry, err := newEntry()
+        if err != nil {
+            return nil, nil, err
+        }
+        if err := auditStatusChange(booking, status, entry); err != nil {
+            return nil, nil, err
+        }
+        changed = append(changed, *booking)
+        entries = append(entries, *entry)
+    }
+
+    for _, booking := range changed {
+        stored := m.bookings[booking.ID]
+        stored.Status = booking.Status
+        m.putBooking(stored)
+    }
+    m.audit = append(m.audit, entries...)
+    return report, changed, nil
+}
+
+func (m *memoryStore) listAuditEntries(bookingID string) []models.AuditEntry {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var entries []models.AuditEntry
+    for _, entry := range m.audit {
+        if entry.BookingID == bookingID {
+            entries = append(entries, entry)
+        }
+    }
+    return entries
+}
+
+func (m *memoryStore) findAvailableWalkers(bookingID string, start, end time.Time, limit int) ([]string, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    type candidate struct {
+        walkerID    string
+        overlapping int
+    }
+
+    seen := make(map[string]bool)
+    var candidates []candidate
+    for _, a := range m.availability {
+        if a.StartsAt.After(start) || a.EndsAt.Before(end) || seen[a.WalkerID] {
+            continue
+        }
+        if _, declined := m.declines[bookingID][a.WalkerID]; declined {
+            continue
+        }
+        if !m.walkers[a.WalkerID].Status.CanTakeBookings() {
+            continue
+        }
+        overlapping := m.countOverlapping(a.WalkerID, start, end, "")
+        if overlapping >= a.Capacity {
+            continue
+        }
+        seen[a.WalkerID] = true
+        candidates = append(candidates, candidate{walkerID: a.WalkerID, overlapping: overlapping})
+    }
+
+    sort.Slice(candidates, func(i, j int) bool {
+        if candidates[i].overlapping != candidates[j].overlapping {
+            return candidates[i].overlapping < candidates[j].overlapping
+        }
+        return candidates[i].walkerID < candidates[j].walkerID
+    })
+
+    var walkers []string
+    for _, c := range candidates {
+        if len(walkers) == limit {
+            break
+        }
+        walkers = append(walkers, c.walkerID)
+    }
+    return walkers, nil
+}
+
+func (m *memoryStore) assignWalker(bookingID, walkerID string, capacity int, acceptBy time.Time) (*models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[bookingID]
+    if !ok {
+        return nil, fmt.Errorf("booking not found with id: %s", bookingID)
+    }
+    if booking.Status != models.BookingStatusPending && booking.Status != models.BookingStatusNeedsReassignment {
+        return nil, ErrNotAssignable
+    }
+    if m.countOverlapping(walkerID, booking.ScheduledAt, booking.EndsAt(), booking.ID) >= capacity {
+        return nil, ErrSlotFull
+    }
+
+    booking.WalkerID = walkerID
+    booking.AcceptBy = &acceptBy
+    booking.Status = models.BookingStatusPending
+    m.putBooking(booking)
+    return &booking, nil
+}
+
+func (m *memoryStore) acceptAssignment(bookingID, walkerID string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[bookingID]
+    if !ok || booking.WalkerID != walkerID || booking.Status != models.BookingStatusPending ||
+        (booking.AcceptBy != nil && !booking.AcceptBy.After(time.Now())) {
+        return ErrNoPendingAssignment
+    }
+
+    booking.Status = models.BookingStatusConfirmed
+    booking.AcceptBy = nil
+    m.putBooking(booking)
+    return nil
+}
+
+func (m *memoryStore) releaseAssignment(bookingID, walkerID, reason string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[bookingID]
+    if !ok || booking.WalkerID != walkerID || booking.Status != models.BookingStatusPending {
+        return ErrNoPendingAssignment
+    }
+
+    booking.WalkerID = ""
+    booking.AcceptBy = nil
+    m.putBooking(booking)
+
+    if m.declines[bookingID] == nil {
+        m.declines[bookingID] = make(map[string]string)
+    }
+    m.declines[bookingID][walkerID] = reason
+    return nil
+}
+
+func (m *memoryStore) listUpcomingWalkerBookings(walkerID string, status models.BookingStatus, from time.Time) ([]models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var upcoming []models.Booking
+    for _, b := range m.bookings {
+        if b.WalkerID == walkerID && b.Status == status && b.ScheduledAt.After(from) {
+            upcoming = append(upcoming, b)
+        }
+    }
+    sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].ScheduledAt.Before(upcoming[j].ScheduledAt) })
+    return upcoming, nil
+}
+
+func (m *memoryStore) releaseConfirmedWalker(bookingID, walkerID, reason string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[bookingID]
+    if !ok || booking.WalkerID != walkerID || booking.Status != models.BookingStatusConfirmed {
+        return ErrNotConfirmed
+    }
+
+    booking.Status = models.BookingStatusNeedsReassignment
+    booking.WalkerID = ""
+    booking.AcceptBy = nil
+    m.putBooking(booking)
+
+    if m.declines[bookingID] == nil {
+        m.declines[bookingID] = make(map[string]string)
+    }
+    m.declines[bookingID][walkerID] = reason
+    return nil
+}
+
+func (m *memoryStore) listLapsedAssignments(now time.Time) ([]models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var lapsed []models.Booking
+    for _, b := range m.bookings {
+        if b.Status == models.BookingStatusPending && b.WalkerID != "" && b.AcceptBy != nil && !b.AcceptBy.After(now) {
+            lapsed = append(lapsed, b)
+        }
+    }
+    sort.Slice(lapsed, func(i, j int) bool { return lapsed[i].AcceptBy.Before(*lapsed[j].AcceptBy) })
+    return lapsed, nil
+}
+
+func (m *memoryStore) cancelUnassignedBooking(bookingID string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[bookingID]
+    if !ok || booking.Status != models.BookingStatusPending || booking.WalkerID != "" {
+        return ErrNotAssignable
+    }
+
+    booking.Status = models.BookingStatusCancelled
+    m.putBooking(booking)
+    return nil
+}
+
+func (m *memoryStore) cancelBooking(bookingID string, fee func(booking *models.Booking) *models.Cancellation) (*models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[bookingID]
+    if !ok {
+        return nil, fmt.Errorf("booking not found with id: %s", bookingID)
+    }
+    if !booking.IsCancellable() {
+        return nil, ErrNotCancellable
+    }
+
+    booking.Status = models.BookingStatusCancelled
+    booking.Cancellation = fee(&booking)
+    m.putBooking(booking)
+    return &booking, nil
+}
+
+func (m *memoryStore) startShift(shift *models.WalkerShift) (*models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[shift.BookingID]
+    if !ok {
+        return nil, fmt.Errorf("booking not found with id: %s", shift.BookingID)
+    }
+    if booking.Status != models.BookingStatusConfirmed || booking.WalkerID != shift.WalkerID {
+        return nil, ErrNotAwaitingCheckIn
+    }
+
+    booking.Status = models.BookingStatusInProgress
+    m.putBooking(booking)
+    m.shifts[booking.ID] = *shift
+    return &booking, nil
+}
+
+func (m *memoryStore) saveShift(shift *models.WalkerShift) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, ok := m.bookings[shift.BookingID]; !ok {
+        return fmt.Errorf("booking not found with id: %s", shift.BookingID)
+    }
+    m.shifts[shift.BookingID] = *shift
+    return nil
+}
+
+func (m *memoryStore) endShift(bookingID, walkerID string, at time.Time) (*models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[bookingID]
+    if !ok {
+        return nil, fmt.Errorf("booking not found with id: %s", bookingID)
+    }
+    if booking.Status != models.BookingStatusInProgress || booking.WalkerID != walkerID {
+        return nil, ErrNotAwaitingCheckOut
+    }
+
+    booking.Status = models.BookingStatusCompleted
+    m.putBooking(booking)
+    if shift, ok := m.shifts[bookingID]; ok && shift.CheckedOutAt == nil {
+        shift.CheckedOutAt = &at
+        m.shifts[bookingID] = shift
+    }
+    return &booking, nil
+}
+
+func (m *memoryStore) getShift(bookingID string) (*models.WalkerShift, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    shift, ok := m.shifts[bookingID]
+    if !ok {
+        return nil, nil
+    }
+    return &shift, nil
+}
+
+func (m *memoryStore) recordCancellationRefund(bookingID, refundID string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[bookingID]
+    if !ok || booking.Cancellation == nil {
+        return fmt.Errorf("booking not found with id: %s", bookingID)
+    }
+    // Bookings handed out share the stored cancellation, so it is replaced rather than edited
+    cancellation := *booking.Cancellation
+    cancellation.RefundID = refundID
+    booking.Cancellation = &cancellation
+    m.putBooking(booking)
+    return nil
+}
+
+func (m *memoryStore) createBookingChange(change *models.BookingChange) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, existing := range m.changes {
+        if existing.BookingID == change.BookingID && existing.Status == change.Status {
+            return ErrChangeAlreadyPending
+        }
+    }
+    m.changes[change.ID] = *change
+    return nil
+}
+
+func (m *memoryStore) getBookingChange(id string) (*models.BookingChange, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    change, ok := m.changes[id]
+    if !ok {
+        return nil, ErrChangeNotFound
+    }
+    return &change, nil
+}
+
+// resolveChange moves a pending, unexpired change to status. The caller must hold m.mu.
+func (m *memoryStore) resolveChange(id string, status models.BookingChangeStatus) error {
+    now := time.Now()
+    change, ok := m.changes[id]
+    if !ok || change.Status != models.BookingChangeStatusPending || !change.ExpiresAt.After(now) {
+        return ErrChangeNotPending
+    }
+
+    change.Status = status
+    change.ResolvedAt = &now
+    m.changes[id] = change
+    return nil
+}
+
+func (m *memoryStore) acceptBookingChange(change *models.BookingChange, updated *models.Booking, capacity int) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if m.countOverlapping(updated.WalkerID, updated.ScheduledAt, updated.EndsAt(), updated.ID) >= capacity {
+        return ErrSlotFull
+    }
+    if err := m.resolveChange(change.ID, models.BookingChangeStatusAccepted); err != nil {
+        return err
+    }
+
+    if booking, ok := m.bookings[updated.ID]; ok {
+        booking.ScheduledAt = updated.ScheduledAt
+        booking.DurationMinutes = updated.DurationMinutes
+        m.putBooking(booking)
+    }
+    return nil
+}
+
+func (m *memoryStore) rejectBookingChange(id string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return m.resolveChange(id, models.BookingChangeStatusRejected)
+}
+
+func (m *memoryStore) expireBookingChanges(now time.Time) (int64, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var expired int64
+    for id, change := range m.changes {
+        if change.Status == models.BookingChangeStatusPending && !change.ExpiresAt.After(now) {
+            resolvedAt := now
+            change.Status = models.BookingChangeStatusExpired
+            change.ResolvedAt = &resolvedAt
+            m.changes[id] = change
+            expired++
+        }
+    }
+    return expired, nil
+}
+
+func (m *memoryStore) getReferralCodeForUser(userID string) (*models.ReferralCode, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return m.referralCodeForUser(userID), nil
+}
+
+// referralCodeForUser returns a copy of the user's code, or nil. The caller must hold m.mu.
+func (m *memoryStore) referralCodeForUser(userID string) *models.ReferralCode {
+    for _, code := range m.referralCodes {
+        if code.UserID == userID {
+            return &code
+        }
+    }
+    return nil
+}
+
+func (m *memoryStore) createReferralCode(code *models.ReferralCode) (*models.ReferralCode, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if existing := m.referralCodeForUser(code.UserID); existing != nil {
+        return existing, nil
+    }
+    if _, taken := m.referralCodes[code.Code]; taken {
+        return nil, ErrReferralCodeTaken
+    }
+
+    m.referralCodes[code.Code] = *code
+    stored := *code
+    return &stored, nil
+}
+
+func (m *memoryStore) getReferralCode(code string) (*models.ReferralCode, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    result, ok := m.referralCodes[code]
+    if !ok {
+        return nil, ErrReferralCodeNotFound
+    }
+    return &result, nil
+}
+
+func (m *memoryStore) createReferral(referral *models.Referral) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, exists := m.referrals[referral.RefereeID]; exists {
+        return ErrAlreadyReferred
+    }
+
+    stored := *referral
+    stored.CreditAmount = 0
+    m.referrals[referral.RefereeID] = stored
+    return nil
+}
+
+func (m *memoryStore) convertReferral(refereeID, bookingID string, credit float64, at time.Time) (*models.Referral, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    referral, ok := m.referrals[refereeID]
+    if !ok || referral.Status != models.ReferralStatusSignedUp {
+        return nil, nil
+    }
+    for _, b := range m.bookings {
+        if b.OwnerID == refereeID && b.ID != bookingID {
+            return nil, nil
+        }
+    }
+
+    referral.Status = models.ReferralStatusConverted
+    referral.BookingID = &bookingID
+    referral.ConvertedAt = &at
+    referral.CreditAmount = credit
+    m.referrals[refereeID] = referral
+    return &referral, nil
+}
+
+func (m *memoryStore) countBookingsByOwner(ownerID string) (int, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    count := 0
+    for _, b := range m.bookings {
+        if b.OwnerID == ownerID {
+            count++
+        }
+    }
+    return count, nil
+}
+
+func (m *memoryStore) getReferralReport(from, to time.Time) ([]models.ReferralSummary, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    byReferrer := make(map[string]*models.ReferralSummary)
+    for _, r := range m.referrals {
+        if r.CreatedAt.Before(from) || !r.CreatedAt.Before(to) {
+            continue
+        }
+        summary, ok := byReferrer[r.ReferrerID]
+        if !ok {
+            summary = &models.ReferralSummary{ReferrerID: r.ReferrerID}
+            byReferrer[r.ReferrerID] = summary
+        }
+        summary.Signups++
+        if r.Status == models.ReferralStatusConverted {
+            summary.Conversions++
+        }
+        summary.CreditsGranted += r.CreditAmount
+    }
+
+    var report []models.ReferralSummary
+    for _, summary := range byReferrer {
+        report = append(report, *summary)
+    }
+    sort.Slice(report, func(i, j int) bool {
+        if report[i].Conversions != report[j].Conversions {
+            return report[i].Conversions > report[j].Conversions
+        }
+        return report[i].ReferrerID < report[j].ReferrerID
+    })
+    return report, nil
+}
+
+func (m *memoryStore) createAPIKey(key *models.APIKey) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, existing := range m.apiKeys {
+        if existing.KeyHash == key.KeyHash {
+            return fmt.Errorf("failed to create api key: duplicate key hash")
+        }
+    }
+    stored := *key
+    stored.Scopes = append([]string(nil), key.Scopes...)
+    m.apiKeys[key.ID] = stored
+    return nil
+}
+
+func (m *memoryStore) getActiveAPIKeyByHash(keyHash string) (*models.APIKey, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, key := range m.apiKeys {
+        if key.KeyHash == keyHash && key.RevokedAt == nil {
+            return &key, nil
+        }
+    }
+    return nil, ErrAPIKeyNotFound
+}
+
+func (m *memoryStore) listAPIKeys() ([]models.APIKey, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    keys := make([]models.APIKey, 0, len(m.apiKeys))
+    for _, key := range m.apiKeys {
+        keys = append(keys, key)
+    }
+    sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+    return keys, nil
+}
+
+func (m *memoryStore) revokeAPIKey(id string, at time.Time) (*models.APIKey, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    key, ok := m.apiKeys[id]
+    if !ok || key.RevokedAt != nil {
+        return nil, ErrAPIKeyNotFound
+    }
+    key.RevokedAt = &at
+    m.apiKeys[id] = key
+    return &key, nil
+}
+
+func (m *memoryStore) touchAPIKey(id string, at time.Time) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if key, ok := m.apiKeys[id]; ok {
+        key.LastUsedAt = &at
+        m.apiKeys[id] = key
+    }
+    return nil
+}
+
+func (m *memoryStore) getWalkerVerification(walkerID string) (*models.WalkerVerification, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    verification, ok := m.walkers[walkerID]
+    if !ok {
+        return &models.WalkerVerification{WalkerID: walkerID, Status: models.WalkerUnverified}, nil
+    }
+    return &verification, nil
+}
+
+func (m *memoryStore) setWalkerVerification(verification *models.WalkerVerification) (models.WalkerVerificationStatus, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    previous := models.WalkerUnverified
+    if existing, ok := m.walkers[verification.WalkerID]; ok {
+        previous = existing.Status
+    }
+    m.walkers[verification.WalkerID] = *verification
+    return previous, nil
+}
+
+func (m *memoryStore) listBookingsScheduledBetween(from, to time.Time) ([]models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var bookings []models.Booking
+    for _, b := range m.bookings {
+        if !b.ScheduledAt.Before(from) && b.ScheduledAt.Before(to) {
+            bookings = append(bookings, b)
+        }
+    }
+    sort.Slice(bookings, func(i, j int) bool { return bookings[i].ScheduledAt.Before(bookings[j].ScheduledAt) })
+    return bookings, nil
+}
+
+func (m *memoryStore) claimReconciliationRun(run *models.ReconciliationRun, retryBefore time.Time) (bool, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for id, existing := range m.runs {
+        if !existing.PeriodStart.Equal(run.PeriodStart) {
+            continue
+        }
+        if existing.Status != models.ReconciliationFailed || !existing.StartedAt.Before(retryBefore) {
+            return false, nil
+        }
+        run.ID = id
+        break
+    }
+    run.Status = models.ReconciliationRunning
+    run.CompletedAt = nil
+    run.Error = ""
+    m.runs[run.ID] = *run
+    return true, nil
+}
+
+func (m *memoryStore) completeReconciliationRun(run *models.ReconciliationRun, discrepancies []models.ReconciliationDiscrepancy) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    stored, ok := m.runs[run.ID]
+    if !ok {
+        return ErrReconciliationRunNotFound
+    }
+    stored.Status = models.ReconciliationCompleted
+    stored.CompletedAt = run.CompletedAt
+    stored.BookingsChecked = run.BookingsChecked
+    stored.DiscrepancyCount = run.DiscrepancyCount
+    stored.DiscrepancyCents = run.DiscrepancyCents
+    m.runs[run.ID] = stored
+
+    kept := make([]models.ReconciliationDiscrepancy, len(discrepancies))
+    for i, d := range discrepancies {
+        d.RunID = run.ID
+        kept[i] = d
+    }
+    m.discrepancies[run.ID] = kept
+    run.Status = models.ReconciliationCompleted
+    return nil
+}
+
+func (m *memoryStore) failReconciliationRun(id, reason string, at time.Time) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if run, ok := m.runs[id]; ok {
+        run.Status = models.ReconciliationFailed
+        run.CompletedAt = &at
+        run.Error = reason
+        m.runs[id] = run
+    }
+    return nil
+}
+
+func (m *memoryStore) listReconciliationRuns(limit int) ([]models.ReconciliationRun, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    runs := make([]models.ReconciliationRun, 0, len(m.runs))
+    for _, run := range m.runs {
+        runs = append(runs, run)
+    }
+    sort.Slice(runs, func(i, j int) bool { return runs[i].PeriodStart.After(runs[j].PeriodStart) })
+    if len(runs) > limit {
+        runs = runs[:limit]
+    }
+    return runs, nil
+}
+
+func (m *memoryStore) getReconciliationRun(id string) (*models.ReconciliationRun, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    run, ok := m.runs[id]
+    if !ok {
+        return nil, ErrReconciliationRunNotFound
+    }
+    run.Discrepancies = append([]models.ReconciliationDiscrepancy(nil), m.discrepancies[id]...)
+    sort.Slice(run.Discrepancies, func(i, j int) bool {
+        if run.Discrepancies[i].Kind != run.Discrepancies[j].Kind {
+            return run.Discrepancies[i].Kind < run.Discrepancies[j].Kind
+        }
+        return run.Discrepancies[i].BookingID < run.Discrepancies[j].BookingID
+    })
+    return &run, nil
+}
+
+func (m *memoryStore) saveUserEmail(userID, email string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.emails[userID] = email
+    return nil
+}
+
+func (m *memoryStore) getUserEmail(userID string) (string, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return m.emails[userID], nil
+}
+
+func (m *memoryStore) createTip(tip *models.Tip) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if existing, ok := m.tips[tip.BookingID]; ok && existing.Status != models.TipStatusFailed {
+        return ErrAlreadyTipped
+    }
+    m.tips[tip.BookingID] = *tip
+    return nil
+}
+
+func (m *memoryStore) updateTipPayment(id string, status models.TipStatus, paymentID string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for bookingID, tip := range m.tips {
+        if tip.ID == id {
+            tip.Status = status
+            tip.PaymentID = paymentID
+            m.tips[bookingID] = tip
+            return nil
+        }
+    }
+    return nil
+}
+
+func (m *memoryStore) getWalkerEarnings(from, to time.Time, walkerID, region string) ([]models.WalkerEarnings, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    byWalker := make(map[string]*models.WalkerEarnings)
+    for _, b := range m.bookings {
+        if b.Status != models.BookingStatusCompleted || b.WalkerID == "" {
+            continue
+        }
+        if b.ScheduledAt.Before(from) || !b.ScheduledAt.Before(to) {
+            continue
+        }
+        if walkerID != "" && b.WalkerID != walkerID {
+            continue
+        }
+        if region != "" && b.Region != region {
+            continue
+        }
+        e, ok := byWalker[b.WalkerID]
+        if !ok {
+            e = &models.WalkerEarnings{WalkerID: b.WalkerID}
+            byWalker[b.WalkerID] = e
+        }
+        e.Bookings++
+        e.BookingAmount += b.Amount
+        if tip, ok := m.tips[b.ID]; ok && tip.Status != models.TipStatusFailed {
+            e.Tips += tip.Amount
+        }
+    }
+
+    earnings := make([]models.WalkerEarnings, 0, len(byWalker))
+    for _, e := range byWalker {
+        e.Total = e.BookingAmount + e.Tips
+        earnings = append(earnings, *e)
+    }
+    sort.Slice(earnings, func(i, j int) bool { return earnings[i].WalkerID < earnings[j].WalkerID })
+    return earnings, nil
+}
+
+func (m *memoryStore) createDispute(dispute *models.Dispute) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, existing := range m.disputes {
+        if existing.BookingID == dispute.BookingID {
+            return ErrDisputeExists
+        }
+    }
+    m.disputes[dispute.ID] = *dispute
+    return nil
+}
+
+func (m *memoryStore) getDispute(id string) (*models.Dispute, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    dispute, ok := m.disputes[id]
+    if !ok {
+        return nil, ErrDisputeNotFound
+    }
+    return &dispute, nil
+}
+
+func (m *memoryStore) getDisputeForBooking(bookingID string) (*models.Dispute, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, dispute := range m.disputes {
+        if dispute.BookingID == bookingID {
+            return &dispute, nil
+        }
+    }
+    return nil, ErrDisputeNotFound
+}
+
+func (m *memoryStore) listDisputes(status models.DisputeStatus, limit int) ([]models.Dispute, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var disputes []models.Dispute
+    for _, dispute := range m.disputes {
+        if status == "" || dispute.Status == status {
+            disputes = append(disputes, dispute)
+        }
+    }
+    sort.Slice(disputes, func(i, j int) bool { return disputes[i].CreatedAt.Before(disputes[j].CreatedAt) })
+    if len(disputes) > limit {
+        disputes = disputes[:limit]
+    }
+    return disputes, nil
+}
+
+func (m *memoryStore) updateDispute(dispute *models.Dispute, from []models.DisputeStatus) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    stored, ok := m.disputes[dispute.ID]
+    if !ok {
+        return ErrDisputeStatusChanged
+    }
+    for _, status := range from {
+        if stored.Status == status {
+            stored.Status = dispute.Status
+            stored.Resolution = dispute.Resolution
+            stored.ReviewedBy = dispute.ReviewedBy
+            stored.RefundAmount = dispute.RefundAmount
+            stored.RefundID = dispute.RefundID
+            stored.ResolvedAt = dispute.ResolvedAt
+            m.disputes[dispute.ID] = stored
+            return nil
+        }
+    }
+    return ErrDisputeStatusChanged
+}
+
+func (m *memoryStore) listAvailabilityBetween(from, to time.Time) ([]models.Availability, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var windows []models.Availability
+    for _, a := range m.availability {
+        if a.StartsAt.Before(to) && a.EndsAt.After(from) {
+            windows = append(windows, a)
+        }
+    }
+    sort.Slice(windows, func(i, j int) bool { return windows[i].StartsAt.Before(windows[j].StartsAt) })
+    return windows, nil
+}
+
+func (m *memoryStore) listActiveBookingsBetween(from, to time.Time) ([]models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var bookings []models.Booking
+    for _, b := range m.bookings {
+        if isActive(b.Status) && b.ScheduledAt.Before(to) && b.EndsAt().After(from) {
+            bookings = append(bookings, b)
+        }
+    }
+    sort.Slice(bookings, func(i, j int) bool { return bookings[i].ScheduledAt.Before(bookings[j].ScheduledAt) })
+    return bookings, nil
+}
+
+func (m *memoryStore) claimScheduledReport(name string, periodStart, now time.Time) (bool, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    key := name + "/" + periodStart.UTC().Format(time.RFC3339)
+    if _, ok := m.reports[key]; ok {
+        return false, nil
+    }
+    m.reports[key] = now
+    return true, nil
+}
+
+func (m *memoryStore) releaseScheduledReport(name string, periodStart time.Time) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    delete(m.reports, name+"/"+periodStart.UTC().Format(time.RFC3339))
+    return nil
+}
+
+func (m *memoryStore) claimReportRun(run *models.ReportRun, retryBefore time.Time, maxAttempts int) (bool, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    run.Attempts = 1
+    for id, existing := range m.reportRuns {
+        if existing.Schedule != run.Schedule || !existing.ScheduledFor.Equal(run.ScheduledFor) {
+            continue
+        }
+        if existing.Status != models.ReportRunFailed || !existing.StartedAt.Before(retryBefore) || existing.Attempts >= maxAttempts {
+            return false, nil
+        }
+        run.ID = id
+        run.Attempts = existing.Attempts + 1
+        break
+    }
+    run.Status = models.ReportRunRunning
+    run.Delivered = 0
+    run.CompletedAt = nil
+    run.Error = ""
+    m.reportRuns[run.ID] = *run
+    return true, nil
+}
+
+func (m *memoryStore) completeReportRun(run *models.ReportRun) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if stored, ok := m.reportRuns[run.ID]; ok {
+        stored.Status = models.ReportRunCompleted
+        stored.Delivered = run.Delivered
+        stored.CompletedAt = run.CompletedAt
+        m.reportRuns[run.ID] = stored
+    }
+    run.Status = models.ReportRunCompleted
+    return nil
+}
+
+func (m *memoryStore) failReportRun(id, reason string, at time.Time) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if run, ok := m.reportRuns[id]; ok {
+        run.Status = models.ReportRunFailed
+        run.CompletedAt = &at
+        run.Error = reason
+        m.reportRuns[id] = run
+    }
+    return nil
+}
+
+func (m *memoryStore) listReportRuns(schedule string, limit int) ([]models.ReportRun, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var runs []models.ReportRun
+    for _, run := range m.reportRuns {
+        if schedule == "" || run.Schedule == schedule {
+            runs = append(runs, run)
+        }
+    }
+    sort.Slice(runs, func(i, j int) bool { return runs[i].StartedAt.After(runs[j].StartedAt) })
+    if len(runs) > limit {
+        runs = runs[:limit]
+    }
+    return runs, nil
+}
+
+func (m *memoryStore) listRegions() ([]regions.Region, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var list []regions.Region
+    for _, region := range m.regions {
+        region.Boundary = append([]regions.Point(nil), region.Boundary...)
+        list = append(list, region)
+    }
+    sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+    return list, nil
+}
+
+func (m *memoryStore) saveRegion(region *regions.Region) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    saved := *region
+    saved.Boundary = append([]regions.Point(nil), region.Boundary...)
+    m.regions[region.ID] = saved
+    return nil
+}
+
+func (m *memoryStore) deleteRegion(id string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, ok := m.regions[id]; !ok {
+        return ErrRegionNotFound
+    }
+    delete(m.regions, id)
+    return nil
+}
+
+func (m *memoryStore) getBookingRuleSet(region string) (*models.BookingRuleSet, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    set, ok := m.bookingRules[region]
+    if !ok {
+        return nil, ErrBookingRulesNotFound
+    }
+    set.Rules = append([]models.BookingRule(nil), set.Rules...)
+    return &set, nil
+}
+
+func (m *memoryStore) saveBookingRuleSet(set *models.BookingRuleSet) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    saved := *set
+    saved.Rules = append([]models.BookingRule(nil), set.Rules...)
+    m.bookingRules[set.Region] = saved
+    return nil
+}
+
+func (m *memoryStore) deleteBookingRuleSet(region string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, ok := m.bookingRules[region]; !ok {
+        return ErrBookingRulesNotFound
+    }
+    delete(m.bookingRules, region)
+    return nil
+}
+
+func (m *memoryStore) listHolidays(region string) ([]models.Holiday, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var holidays []models.Holiday
+    for _, h := range m.holidays {
+        if h.Region == region {
+            holidays = append(holidays, h)
+        }
+    }
+    sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date < holidays[j].Date })
+    return holidays, nil
+}
+
+func (m *memoryStore) saveHoliday(holiday *models.Holiday) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.holidays[holiday.Region+"/"+holiday.Date] = *holiday
+    return nil
+}
+
+func (m *memoryStore) deleteHoliday(region, date string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    key := region + "/" + date
+    if _, ok := m.holidays[key]; !ok {
+        return ErrHolidayNotFound
+    }
+    delete(m.holidays, key)
+    return nil
+}
+
+func (m *memoryStore) getRatePlan(walkerID string) (*models.RatePlan, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    plan, ok := m.ratePlans[walkerID]
+    if !ok {
+        return nil, nil
+    }
+    return &plan, nil
+}
+
+func (m *memoryStore) saveRatePlan(plan *models.RatePlan) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.ratePlans[plan.WalkerID] = *plan
+    return nil
+}
+
+func (m *memoryStore) saveCalendarConnection(connection *models.CalendarConnection) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    key := connection.WalkerID + "/" + connection.Provider
+    saved := *connection
+    if existing, ok := m.calendars[key]; ok {
+        saved.CreatedAt = existing.CreatedAt
+    }
+    m.calendars[key] = saved
+    return nil
+}
+
+func (m *memoryStore) listCalendarConnections(walkerID string) ([]models.CalendarConnection, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var connections []models.CalendarConnection
+    for _, c := range m.calendars {
+        if c.WalkerID == walkerID {
+            connections = append(connections, c)
+        }
+    }
+    sort.Slice(connections, func(i, j int) bool { return connections[i].Provider < connections[j].Provider })
+    return connections, nil
+}
+
+func (m *memoryStore) deleteCalendarConnection(walkerID, provider string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    key := walkerID + "/" + provider
+    if _, ok := m.calendars[key]; !ok {
+        return ErrCalendarNotConnected
+    }
+    delete(m.calendars, key)
+    for itemKey, e := range m.calendarItems {
+        if e.WalkerID == walkerID && e.Provider == provider {
+            delete(m.calendarItems, itemKey)
+        }
+    }
+    return nil
+}
+
+func (m *memoryStore) listCalendarEvents(bookingID string) ([]models.CalendarEvent, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var calendarEvents []models.CalendarEvent
+    for _, e := range m.calendarItems {
+        if e.BookingID == bookingID {
+            calendarEvents = append(calendarEvents, e)
+        }
+    }
+    return calendarEvents, nil
+}
+
+func (m *memoryStore) saveCalendarEvent(event *models.CalendarEvent) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.calendarItems[event.BookingID+"/"+event.WalkerID+"/"+event.Provider] = *event
+    return nil
+}
+
+func (m *memoryStore) deleteCalendarEvent(bookingID, walkerID, provider string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    delete(m.calendarItems, bookingID+"/"+walkerID+"/"+provider)
+    return nil
+}
+
+func (m *memoryStore) saveDevice(device *models.Device) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    stored := *device
+    if existing, ok := m.devices[device.Token]; ok {
+        stored.CreatedAt = existing.CreatedAt
+    }
+    m.devices[device.Token] = stored
+    return nil
+}
+
+func (m *memoryStore) listDevices(userID string) ([]models.Device, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var devices []models.Device
+    for _, d := range m.devices {
+        if d.UserID == userID {
+            devices = append(devices, d)
+        }
+    }
+    sort.Slice(devices, func(i, j int) bool { return devices[i].UpdatedAt.After(devices[j].UpdatedAt) })
+    return devices, nil
+}
+
+func (m *memoryStore) deleteDevice(userID, token string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if d, ok := m.devices[token]; !ok || d.UserID != userID {
+        return ErrDeviceNotFound
+    }
+    delete(m.devices, token)
+    return nil
+}
+
+func (m *memoryStore) deleteDeviceToken(token string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    delete(m.devices, token)
+    return nil
+}
+
+func (m *memoryStore) getNotificationPreferences(userID string) (*models.NotificationPreferences, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    prefs, ok := m.preferences[userID]
+    if !ok {
+        return models.DefaultNotificationPreferences(userID), nil
+    }
+    prefs.MutedCategories = append([]string{}, prefs.MutedCategories...)
+    return &prefs, nil
+}
+
+func (m *memoryStore) saveNotificationPreferences(prefs *models.NotificationPreferences) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    stored := *prefs
+    stored.MutedCategories = append([]string{}, prefs.MutedCategories...)
+    m.preferences[prefs.UserID] = stored
+    return nil
+}
+
+func (m *memoryStore) createDeliveryReceipt(receipt *models.DeliveryReceipt) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.deliveries = append(m.deliveries, *receipt)
+    return nil
+}
+
+func (m *memoryStore) listDeliveryReceipts(userID string, limit int) ([]models.DeliveryReceipt, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var receipts []models.DeliveryReceipt
+    for i := len(m.deliveries) - 1; i >= 0 && len(receipts) < limit; i-- {
+        if m.deliveries[i].UserID == userID {
+            receipts = append(receipts, m.deliveries[i])
+        }
+    }
+    return receipts, nil
+}
+
+func (m *memoryStore) deleteDeliveryReceiptsBefore(cutoff time.Time) (int64, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    kept := m.deliveries[:0]
+    for _, r := range m.deliveries {
+        if !r.CreatedAt.Before(cutoff) {
+            kept = append(kept, r)
+        }
+    }
+    deleted := int64(len(m.deliveries) - len(kept))
+    m.deliveries = kept
+    return deleted, nil
+}
+
+func (m *memoryStore) saveUserPhone(userID, phone string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.phones[userID] = phone
+    return nil
+}
+
+func (m *memoryStore) getUserPhone(userID string) (string, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return m.phones[userID], nil
+}
+
+func (m *memoryStore) saveSMSOptOut(optOut *models.SMSOptOut) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, ok := m.smsOptOuts[optOut.Phone]; !ok {
+        m.smsOptOuts[optOut.Phone] = *optOut
+    }
+    return nil
+}
+
+func (m *memoryStore) getSMSOptOut(phone string) (*models.SMSOptOut, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    optOut, ok := m.smsOptOuts[phone]
+    if !ok {
+        return nil, nil
+    }
+    return &optOut, nil
+}
+
+func (m *memoryStore) deleteSMSOptOut(phone string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    delete(m.smsOptOuts, phone)
+    return nil
+}
+
+func (m *memoryStore) createInboxNotification(n *models.InboxNotification) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.inbox = append(m.inbox, *n)
+    return nil
+}
+
+func (m *memoryStore) getInboxNotification(userID, id string) (*models.InboxNotification, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, n := range m.inbox {
+        if n.UserID == userID && n.ID == id {
+            return &n, nil
+        }
+    }
+    return nil, ErrInboxNotificationNotFound
+}
+
+func (m *memoryStore) listInboxNotifications(userID, before string, unreadOnly bool, limit int) ([]models.InboxNotification, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    start := len(m.inbox) - 1
+    if before != "" {
+        start = -1
+        for i, n := range m.inbox {
+            if n.UserID == userID && n.ID == before {
+                start = i - 1
+                break
+            }
+        }
+    }
+
+    var notifications []models.InboxNotification
+    for i := start; i >= 0 && len(notifications) < limit; i-- {
+        n := m.inbox[i]
+        if n.UserID == userID && (!unreadOnly || n.ReadAt == nil) {
+            notifications = append(notifications, n)
+        }
+    }
+    return notifications, nil
+}
+
+func (m *memoryStore) countUnreadInboxNotifications(userID string) (int, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    unread := 0
+    for _, n := range m.inbox {
+        if n.UserID == userID && n.ReadAt == nil {
+            unread++
+        }
+    }
+    return unread, nil
+}
+
+func (m *memoryStore) markInboxNotificationsRead(userID string, ids []string, at time.Time) (int64, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    wanted := make(map[string]bool, len(ids))
+    for _, id := range ids {
+        wanted[id] = true
+    }
+    var marked int64
+    for i := range m.inbox {
+        n := &m.inbox[i]
+        if n.UserID != userID || n.ReadAt != nil || (ids != nil && !wanted[n.ID]) {
+            continue
+        }
+        readAt := at
+        n.ReadAt = &readAt
+        marked++
+    }
+    return marked, nil
+}
+
+func (m *memoryStore) deleteInboxNotificationsBefore(cutoff time.Time) (int64, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    kept := m.inbox[:0]
+    for _, n := range m.inbox {
+        if !n.CreatedAt.Before(cutoff) {
+            kept = append(kept, n)
+        }
+    }
+    deleted := int64(len(m.inbox) - len(kept))
+    m.inbox = kept
+    return deleted, nil
+}
+
+func (m *memoryStore) createBookingAttachment(a sealedAttachment) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.attachments[a.BookingID] = append(m.attachments[a.BookingID], a)
+    return nil
+}
+
+func (m *memoryStore) listBookingAttachments(bookingID string) []sealedAttachment {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return append([]sealedAttachment(nil), m.attachments[bookingID]...)
+}
+
+func (m *memoryStore) getBookingAttachment(bookingID, id string) (sealedAttachment, bool) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, a := range m.attachments[bookingID] {
+        if a.ID == id {
+            return a, true
+        }
+    }
+    return sealedAttachment{}, false
+}
+
+func (m *memoryStore) deleteBookingAttachment(bookingID, id string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    attachments := m.attachments[bookingID]
+    for i, a := range attachments {
+        if a.ID == id {
+            m.attachments[bookingID] = append(attachments[:i:i], attachments[i+1:]...)
+            return nil
+        }
+    }
+    return ErrAttachmentNotFound
+}
+
+func (m *memoryStore) deleteFinishedBookingAttachmentsBefore(cutoff time.Time) (int64, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var deleted int64
+    for bookingID, attachments := range m.attachments {
+        booking, ok := m.bookings[bookingID]
+        if !ok || !booking.EndsAt().Before(cutoff) {
+            continue
+        }
+        switch booking.Status {
+        case models.BookingStatusCompleted, models.BookingStatusCancelled, models.BookingStatusFailed:
+            deleted += int64(len(attachments))
+            delete(m.attachments, bookingID)
+        }
+    }
+    return deleted, nil
+}
+
+// copySaga returns saga with steps of its own, so callers cannot change stored sagas
+func copySaga(saga models.Saga) models.Saga {
+    saga.Steps = append(models.SagaSteps(nil), saga.Steps...)
+    return saga
+}
+
+func (m *memoryStore) saveSaga(saga *models.Saga) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.sagas[saga.ID] = copySaga(*saga)
+    return nil
+}
+
+func (m *memoryStore) getSaga(id string) (*models.Saga, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    saga, ok := m.sagas[id]
+    if !ok {
+        return nil, nil
+    }
+    saga = copySaga(saga)
+    return &saga, nil
+}
+
+func (m *memoryStore) listStalledSagas(before time.Time, limit int) ([]models.Saga, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var sagas []models.Saga
+    for _, saga := range m.sagas {
+        if !saga.Status.Finished() && saga.UpdatedAt.Before(before) {
+            sagas = append(sagas, copySaga(saga))
+        }
+    }
+    sort.Slice(sagas, func(i, j int) bool { return sagas[i].UpdatedAt.Before(sagas[j].UpdatedAt) })
+    if len(sagas) > limit {
+        sagas = sagas[:limit]
+    }
+    return sagas, nil
+}
+
+func (m *memoryStore) claimSaga(id string, seen, now time.Time) (bool, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    saga, ok := m.sagas[id]
+    if !ok || !saga.UpdatedAt.Equal(seen) {
+        return false, nil
+    }
+    saga.UpdatedAt = now
+    m.sagas[id] = saga
+    return true, nil
+}
+
+func (m *memoryStore) revertAcceptance(bookingID, walkerID string, acceptBy *time.Time) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    booking, ok := m.bookings[bookingID]
+    if !ok || booking.WalkerID != walkerID || booking.Status != models.BookingStatusConfirmed {
+        return nil
+    }
+    booking.Status = models.BookingStatusPending
+    booking.AcceptBy = acceptBy
+    m.putBooking(booking)
+    return nil
+}
+
+func (m *memoryStore) configureEventSourcing(enabled bool) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if enabled && !m.eventSourced {
+        for id := range m.bookings {
+            booking := m.bookings[id]
+            changes, err := bookingChanges(nil, &booking)
+            if err == nil {
+                m.appendBookingEvent(id, models.BookingEventImported, changes)
+            }
+        }
+    }
+    m.eventSourced = enabled
+}
+
+func (m *memoryStore) listBookingEvents(bookingID string, from, to int) ([]models.BookingEvent, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var events []models.BookingEvent
+    for _, event := range m.bookingEvents[bookingID] {
+        if event.Version > from && (to == 0 || event.Version <= to) {
+            events = append(events, event)
+        }
+    }
+    return events, nil
+}
+
+func (m *memoryStore) foldBooking(bookingID string, version int) (*models.Booking, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return m.foldBookingLocked(bookingID, version)
+}
+
+// foldBookingLocked rebuilds a booking from its events up to version, or all of them when
+// version is 0. Every read folds from the first event, as snapshots only save PostgreSQL
+// round trips. The caller must hold m.mu.
+func (m *memoryStore) foldBookingLocked(bookingID string, version int) (*models.Booking, error) {
+    var events []models.BookingEvent
+    for _, event := range m.bookingEvents[bookingID] {
+        if version == 0 || event.Version <= version {
+            events = append(events, event)
+        }
+    }
+    if len(events) == 0 {
+        return nil, fmt.Errorf("booking not found with id: %s", bookingID)
+    }
+    if latest := events[len(events)-1].Version; latest < version {
+        return nil, fmt.Errorf("invalid version: booking %s has %d changes", bookingID, latest)
+    }
+
+    state := map[string]json.RawMessage{}
+    if err := applyBookingEvents(state, events); err != nil {
+        return nil, err
+    }
+    return decodeBookingState(state)
+}
+
+func (m *memoryStore) createSlotHold(hold *models.SlotHold, capacity int) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if m.countOverlapping(hold.WalkerID, hold.ScheduledAt, hold.EndsAt(), "") >= capacity {
+        return ErrSlotFull
+    }
+    m.holds[hold.ID] = *hold
+    return nil
+}
+
+func (m *memoryStore) getSlotHold(id string) (*models.SlotHold, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    hold, ok := m.holds[id]
+    if !ok {
+        return nil, ErrHoldNotFound
+    }
+    return &hold, nil
+}
+
+func (m *memoryStore) deleteSlotHold(id, ownerID string) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    hold, ok := m.holds[id]
+    if !ok || hold.OwnerID != ownerID {
+        return ErrHoldNotFound
+    }
+    delete(m.holds, id)
+    return nil
+}
+
+func (m *memoryStore) deleteExpiredSlotHolds(now time.Time) int64 {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    var deleted int64
+    for id, hold := range m.holds {
+        if !hold.ExpiresAt.After(now) {
+            delete(m.holds, id)
+            deleted++
+        }
+    }
+    return deleted
+}
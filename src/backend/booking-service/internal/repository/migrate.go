@@ -0,0 +1,98 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "embed"
+    "fmt"
+    "io/fs"
+    "log"
+    "sort"
+    "strings"
+    "time"
+)
+
+// migrations holds the schema changes applied by Migrate, in file name order
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLockKey serializes Migrate across replicas starting at the same time
+const migrationLockKey = "booking-service:migrations"
+
+// Migrate applies every migration in migrations/ that has not yet been applied, each in its
+// own transaction, recording applied versions in schema_migrations
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func Migrate(ctx context.Context) error {
+    if memory != nil {
+        return nil
+    }
+    ctx, cancel := context.WithTimeout(ctx, time.Minute)
+    defer cancel()
+
+    if _, err := DB.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version    TEXT PRIMARY KEY,
+            applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        )`); err != nil {
+        return fmt.Errorf("failed to create schema_migrations: %w", err)
+    }
+
+    files, err := fs.Glob(migrations, "migrations/*.sql")
+    if err != nil {
+        return fmt.Errorf("failed to list migrations: %w", err)
+    }
+    sort.Strings(files)
+
+    for _, file := range files {
+        version := strings.TrimSuffix(strings.TrimPrefix(file, "migrations/"), ".sql")
+        applied, err := applyMigration(ctx, file, version)
+        if err != nil {
+            return err
+        }
+        if applied {
+            log.Printf("Applied migration %s", version)
+        }
+    }
+
+    return nil
+}
+
+// applyMigration runs a single migration unless it has already been applied
+func applyMigration(ctx context.Context, file, version string) (bool, error) {
+    script, err := migrations.ReadFile(file)
+    if err != nil {
+        return false, fmt.Errorf("failed to read migration %s: %w", version, err)
+    }
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return false, fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, migrationLockKey); err != nil {
+        return false, fmt.Errorf("failed to lock migrations: %w", err)
+    }
+
+    var exists bool
+    if err := tx.QueryRowContext(ctx,
+        `SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, version,
+    ).Scan(&exists); err != nil {
+        return false, fmt.Errorf("failed to check migration %s: %w", version, err)
+    }
+    if exists {
+        return false, nil
+    }
+
+    if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+        return false, fmt.Errorf("failed to apply migration %s: %w", version, err)
+    }
+    if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+        return false, fmt.Errorf("failed to record migration %s: %w", version, err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return false, fmt.Errorf("failed to commit migration %s: %w", version, err)
+    }
+    return true, nil
+}
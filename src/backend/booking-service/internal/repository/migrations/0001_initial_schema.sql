@@ -0,0 +1,86 @@
+-- Initial booking-service schema: bookings, walker availability, booking changes,
+-- referrals, the admin audit log and walker assignment declines.
+
+CREATE TABLE IF NOT EXISTS bookings (
+    id               TEXT PRIMARY KEY,
+    owner_id         TEXT NOT NULL,
+    walker_id        TEXT NOT NULL DEFAULT '',
+    dog_id           TEXT NOT NULL,
+    scheduled_at     TIMESTAMPTZ NOT NULL,
+    status           TEXT NOT NULL,
+    amount           NUMERIC(10, 2) NOT NULL,
+    duration_minutes INTEGER NOT NULL DEFAULT 30,
+    accept_by        TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS bookings_owner_id_idx ON bookings (owner_id);
+CREATE INDEX IF NOT EXISTS bookings_walker_schedule_idx ON bookings (walker_id, scheduled_at);
+CREATE INDEX IF NOT EXISTS bookings_accept_by_idx ON bookings (accept_by) WHERE accept_by IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS walker_availability (
+    id                     TEXT PRIMARY KEY,
+    walker_id              TEXT NOT NULL,
+    starts_at              TIMESTAMPTZ NOT NULL,
+    ends_at                TIMESTAMPTZ NOT NULL,
+    capacity               INTEGER NOT NULL,
+    group_discount_percent NUMERIC(5, 2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS walker_availability_window_idx ON walker_availability (walker_id, starts_at, ends_at);
+
+CREATE TABLE IF NOT EXISTS booking_changes (
+    id               TEXT PRIMARY KEY,
+    booking_id       TEXT NOT NULL REFERENCES bookings (id),
+    proposed_by      TEXT NOT NULL,
+    scheduled_at     TIMESTAMPTZ,
+    duration_minutes INTEGER,
+    status           TEXT NOT NULL,
+    expires_at       TIMESTAMPTZ NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL,
+    resolved_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS booking_changes_booking_id_idx ON booking_changes (booking_id);
+CREATE INDEX IF NOT EXISTS booking_changes_expiry_idx ON booking_changes (status, expires_at);
+
+CREATE TABLE IF NOT EXISTS referral_codes (
+    code       TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS referrals (
+    id            TEXT PRIMARY KEY,
+    code          TEXT NOT NULL REFERENCES referral_codes (code),
+    referrer_id   TEXT NOT NULL,
+    referee_id    TEXT NOT NULL UNIQUE,
+    status        TEXT NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL,
+    booking_id    TEXT,
+    converted_at  TIMESTAMPTZ,
+    credit_amount NUMERIC(10, 2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS referrals_referrer_id_idx ON referrals (referrer_id, created_at);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id         TEXT PRIMARY KEY,
+    actor_id   TEXT NOT NULL,
+    action     TEXT NOT NULL,
+    booking_id TEXT NOT NULL,
+    reason     TEXT NOT NULL,
+    before     JSONB,
+    after      JSONB,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_booking_idx ON audit_log (booking_id, created_at);
+
+CREATE TABLE IF NOT EXISTS assignment_declines (
+    booking_id TEXT NOT NULL,
+    walker_id  TEXT NOT NULL,
+    reason     TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS assignment_declines_booking_id_idx ON assignment_declines (booking_id);
@@ -0,0 +1,8 @@
+-- Walkers' verification status; only eligible walkers can be booked or assigned
+CREATE TABLE IF NOT EXISTS walker_verifications (
+    walker_id  TEXT PRIMARY KEY,
+    status     TEXT NOT NULL,
+    reason     TEXT NOT NULL DEFAULT '',
+    updated_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
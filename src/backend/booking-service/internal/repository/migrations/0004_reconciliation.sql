@@ -0,0 +1,27 @@
+-- Nightly reconciliation runs of booking payments and the discrepancies each found
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id                TEXT PRIMARY KEY,
+    period_start      TIMESTAMPTZ NOT NULL UNIQUE,
+    period_end        TIMESTAMPTZ NOT NULL,
+    status            TEXT NOT NULL,
+    started_at        TIMESTAMPTZ NOT NULL,
+    completed_at      TIMESTAMPTZ,
+    bookings_checked  INTEGER NOT NULL DEFAULT 0,
+    discrepancy_count INTEGER NOT NULL DEFAULT 0,
+    discrepancy_cents BIGINT NOT NULL DEFAULT 0,
+    error             TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS reconciliation_discrepancies (
+    run_id           TEXT NOT NULL REFERENCES reconciliation_runs (id) ON DELETE CASCADE,
+    booking_id       TEXT NOT NULL,
+    kind             TEXT NOT NULL,
+    booking_status   TEXT NOT NULL DEFAULT '',
+    expected_cents   BIGINT NOT NULL,
+    captured_cents   BIGINT NOT NULL,
+    refunded_cents   BIGINT NOT NULL,
+    difference_cents BIGINT NOT NULL,
+    currency         TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS reconciliation_discrepancies_run_id_idx ON reconciliation_discrepancies (run_id);
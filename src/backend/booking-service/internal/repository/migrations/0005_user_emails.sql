@@ -0,0 +1,6 @@
+-- Email addresses booking receipts are sent to
+CREATE TABLE IF NOT EXISTS user_emails (
+    user_id    TEXT PRIMARY KEY,
+    email      TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,2 @@
+-- Tax charged on each booking, recorded when it is priced
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS tax JSONB;
@@ -0,0 +1,11 @@
+-- Tips owners add to completed bookings
+CREATE TABLE IF NOT EXISTS booking_tips (
+    id         TEXT PRIMARY KEY,
+    booking_id TEXT NOT NULL UNIQUE REFERENCES bookings (id),
+    owner_id   TEXT NOT NULL,
+    walker_id  TEXT NOT NULL,
+    amount     NUMERIC(10, 2) NOT NULL,
+    status     TEXT NOT NULL,
+    payment_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,16 @@
+-- Disputes owners open about bookings, and how they were resolved
+CREATE TABLE IF NOT EXISTS disputes (
+    id            TEXT PRIMARY KEY,
+    booking_id    TEXT NOT NULL UNIQUE REFERENCES bookings (id),
+    owner_id      TEXT NOT NULL,
+    reason        TEXT NOT NULL,
+    status        TEXT NOT NULL,
+    resolution    TEXT NOT NULL DEFAULT '',
+    reviewed_by   TEXT NOT NULL DEFAULT '',
+    refund_amount NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    refund_id     TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL,
+    resolved_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS disputes_status_idx ON disputes (status, created_at);
@@ -0,0 +1,13 @@
+-- Service region of each walk and of the windows walkers cover, for capacity planning
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';
+ALTER TABLE walker_availability ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS walker_availability_period_idx ON walker_availability (starts_at, ends_at);
+
+-- Scheduled reports sent for each period, so only one instance sends each report
+CREATE TABLE IF NOT EXISTS scheduled_reports (
+    name         TEXT NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    sent_at      TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (name, period_start)
+);
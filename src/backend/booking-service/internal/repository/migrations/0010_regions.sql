@@ -0,0 +1,9 @@
+-- Service regions: one polygon per city zone, published to the other services
+CREATE TABLE IF NOT EXISTS regions (
+    id         TEXT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    boundary   JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS bookings_region_idx ON bookings (region, scheduled_at);
@@ -0,0 +1,12 @@
+-- Walkers' own rates, and the rate each booking was priced at
+CREATE TABLE IF NOT EXISTS rate_plans (
+    walker_id                 TEXT PRIMARY KEY,
+    base_rate                 NUMERIC(10, 2) NOT NULL,
+    weekend_surcharge_percent NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    large_dog_surcharge       NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    extra_dog_surcharge       NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    time_zone                 TEXT NOT NULL DEFAULT '',
+    updated_at                TIMESTAMPTZ NOT NULL
+);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS rate JSONB;
@@ -0,0 +1,21 @@
+-- Walkers' connected external calendars, and the events written to them for each booking
+CREATE TABLE IF NOT EXISTS calendar_connections (
+    walker_id     TEXT NOT NULL,
+    provider      TEXT NOT NULL,
+    access_token  TEXT NOT NULL,
+    refresh_token TEXT NOT NULL,
+    expires_at    TIMESTAMPTZ NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL,
+    updated_at    TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (walker_id, provider)
+);
+
+CREATE TABLE IF NOT EXISTS calendar_events (
+    booking_id TEXT NOT NULL,
+    walker_id  TEXT NOT NULL,
+    provider   TEXT NOT NULL,
+    event_id   TEXT NOT NULL,
+    PRIMARY KEY (booking_id, walker_id, provider)
+);
+
+CREATE INDEX IF NOT EXISTS calendar_events_walker_idx ON calendar_events (walker_id, provider);
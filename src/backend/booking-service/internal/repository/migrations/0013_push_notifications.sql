@@ -0,0 +1,34 @@
+-- Devices receiving push notifications, users' notification preferences, and the outcome of
+-- each push notification sent
+CREATE TABLE IF NOT EXISTS devices (
+    token      TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    platform   TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS devices_user_idx ON devices (user_id);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id          TEXT PRIMARY KEY,
+    push_enabled     BOOLEAN NOT NULL DEFAULT TRUE,
+    muted_categories TEXT[] NOT NULL DEFAULT '{}',
+    updated_at       TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS delivery_receipts (
+    id           TEXT PRIMARY KEY,
+    user_id      TEXT NOT NULL,
+    platform     TEXT NOT NULL DEFAULT '',
+    device_token TEXT NOT NULL DEFAULT '',
+    category     TEXT NOT NULL DEFAULT '',
+    subject      TEXT NOT NULL,
+    status       TEXT NOT NULL,
+    message_id   TEXT NOT NULL DEFAULT '',
+    error        TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS delivery_receipts_user_idx ON delivery_receipts (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS delivery_receipts_created_idx ON delivery_receipts (created_at);
@@ -0,0 +1,12 @@
+-- Phone numbers owners are texted at, and numbers that must not be texted
+CREATE TABLE IF NOT EXISTS user_phones (
+    user_id    TEXT PRIMARY KEY,
+    phone      TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS sms_opt_outs (
+    phone        TEXT PRIMARY KEY,
+    source       TEXT NOT NULL,
+    opted_out_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,15 @@
+-- Notifications shown in users' in-app notification centers
+CREATE TABLE IF NOT EXISTS inbox_notifications (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    category   TEXT NOT NULL DEFAULT '',
+    subject    TEXT NOT NULL,
+    body       TEXT NOT NULL DEFAULT '',
+    data       JSONB,
+    read_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS inbox_notifications_user_idx ON inbox_notifications (user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS inbox_notifications_unread_idx ON inbox_notifications (user_id) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS inbox_notifications_created_idx ON inbox_notifications (created_at);
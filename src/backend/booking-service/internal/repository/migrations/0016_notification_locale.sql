@@ -0,0 +1,2 @@
+-- Language owners' notifications are sent in; empty for the default
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- Rules new bookings are checked against, per region, with a "default" set for the others
+CREATE TABLE IF NOT EXISTS booking_rules (
+    region     TEXT PRIMARY KEY,
+    rules      JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
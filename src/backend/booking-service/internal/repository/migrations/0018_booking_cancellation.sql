@@ -0,0 +1,2 @@
+-- Fee charged and refund owed when an owner cancels a booking
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS cancellation JSONB;
@@ -0,0 +1,11 @@
+-- Blackout dates and holidays of each region's calendar, with "default" dates for every region
+CREATE TABLE IF NOT EXISTS holidays (
+    region            TEXT NOT NULL,
+    date              DATE NOT NULL,
+    name              TEXT NOT NULL,
+    kind              TEXT NOT NULL,
+    surcharge_percent NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    time_zone         TEXT NOT NULL DEFAULT '',
+    updated_at        TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (region, date)
+);
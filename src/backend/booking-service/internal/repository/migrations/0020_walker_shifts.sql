@@ -0,0 +1,13 @@
+-- Pickup coordinates walkers are checked in against
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+-- Walkers' check-in at the pickup and check-out of each walk
+CREATE TABLE IF NOT EXISTS walker_shifts (
+    booking_id          TEXT PRIMARY KEY REFERENCES bookings (id),
+    walker_id           TEXT NOT NULL,
+    checked_in_at       TIMESTAMPTZ NOT NULL,
+    check_in_distance_m DOUBLE PRECISION,
+    overridden_by       TEXT NOT NULL DEFAULT '',
+    checked_out_at      TIMESTAMPTZ
+);
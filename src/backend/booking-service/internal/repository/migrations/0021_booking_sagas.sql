@@ -0,0 +1,17 @@
+-- Sagas confirming bookings across the payment-service and walkers' calendars, kept so an
+-- interrupted one can be finished or undone
+CREATE TABLE IF NOT EXISTS booking_sagas (
+    id         TEXT PRIMARY KEY,
+    kind       TEXT NOT NULL,
+    booking_id TEXT NOT NULL REFERENCES bookings (id),
+    walker_id  TEXT NOT NULL DEFAULT '',
+    status     TEXT NOT NULL,
+    steps      JSONB NOT NULL,
+    payment_id TEXT NOT NULL DEFAULT '',
+    accept_by  TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS booking_sagas_unfinished_idx ON booking_sagas (updated_at) WHERE status IN ('running', 'compensating');
+CREATE INDEX IF NOT EXISTS booking_sagas_booking_idx ON booking_sagas (booking_id);
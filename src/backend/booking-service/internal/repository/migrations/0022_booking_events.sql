@@ -0,0 +1,45 @@
+-- Every change to a booking while event sourcing is enabled, as the columns it changed; the
+-- trigger recording them is attached by repository.ConfigureEventSourcing
+CREATE TABLE IF NOT EXISTS booking_events (
+    booking_id  TEXT NOT NULL,
+    version     INTEGER NOT NULL,
+    type        TEXT NOT NULL,
+    changes     JSONB NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (booking_id, version)
+);
+
+-- Bookings' state folded from their events up to a version, so reads replay only later events
+CREATE TABLE IF NOT EXISTS booking_snapshots (
+    booking_id TEXT PRIMARY KEY,
+    version    INTEGER NOT NULL,
+    state      JSONB NOT NULL,
+    taken_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE OR REPLACE FUNCTION record_booking_event() RETURNS TRIGGER AS $$
+DECLARE
+    changes    JSONB;
+    event_type TEXT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        changes := to_jsonb(NEW);
+        event_type := 'created';
+    ELSE
+        SELECT COALESCE(jsonb_object_agg(n.key, n.value), '{}'::jsonb) INTO changes
+        FROM jsonb_each(to_jsonb(NEW)) n
+        WHERE to_jsonb(OLD) -> n.key IS DISTINCT FROM n.value;
+        IF changes = '{}'::jsonb THEN
+            RETURN NEW;
+        END IF;
+        event_type := CASE WHEN changes ? 'status' THEN 'status_changed' ELSE 'updated' END;
+    END IF;
+
+    -- Updates to a booking hold its row lock, so versions cannot be taken twice
+    INSERT INTO booking_events (booking_id, version, type, changes)
+    SELECT NEW.id, COALESCE(MAX(version), 0) + 1, event_type, changes
+    FROM booking_events
+    WHERE booking_id = NEW.id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
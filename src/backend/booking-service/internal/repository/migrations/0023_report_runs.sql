@@ -0,0 +1,19 @@
+-- Runs of the admin reports emailed on a schedule, one per schedule and time it fired
+CREATE TABLE IF NOT EXISTS report_runs (
+    id            TEXT PRIMARY KEY,
+    schedule      TEXT NOT NULL,
+    report        TEXT NOT NULL,
+    scheduled_for TIMESTAMPTZ NOT NULL,
+    period_start  TIMESTAMPTZ NOT NULL,
+    period_end    TIMESTAMPTZ NOT NULL,
+    status        TEXT NOT NULL,
+    attempts      INTEGER NOT NULL DEFAULT 1,
+    recipients    INTEGER NOT NULL DEFAULT 0,
+    delivered     INTEGER NOT NULL DEFAULT 0,
+    started_at    TIMESTAMPTZ NOT NULL,
+    completed_at  TIMESTAMPTZ,
+    error         TEXT NOT NULL DEFAULT '',
+    UNIQUE (schedule, scheduled_for)
+);
+
+CREATE INDEX IF NOT EXISTS report_runs_started_at_idx ON report_runs (started_at DESC);
@@ -0,0 +1,2 @@
+-- Booking listings page by seeking on (scheduled_at, id), latest first
+CREATE INDEX IF NOT EXISTS bookings_schedule_idx ON bookings (scheduled_at DESC, id DESC);
@@ -0,0 +1,14 @@
+-- Notes and small files owners attach to bookings for their walkers. Everything but what is
+-- needed to find and purge them is sealed with the attachment keys.
+CREATE TABLE IF NOT EXISTS booking_attachments (
+    id         TEXT PRIMARY KEY,
+    booking_id TEXT NOT NULL REFERENCES bookings (id),
+    owner_id   TEXT NOT NULL,
+    kind       TEXT NOT NULL,
+    size       INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+    key_id     TEXT NOT NULL,
+    sealed     BYTEA NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS booking_attachments_booking_idx ON booking_attachments (booking_id, created_at);
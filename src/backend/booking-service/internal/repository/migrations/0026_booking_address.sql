@@ -0,0 +1,2 @@
+-- Pickup addresses, resolved to the pickup coordinates when bookings are made
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS address JSONB;
@@ -0,0 +1,16 @@
+-- Places in walkers' schedules held at a quoted price while owners check out; they count
+-- against capacity like bookings until booked, released or expired
+CREATE TABLE IF NOT EXISTS slot_holds (
+    id               TEXT PRIMARY KEY,
+    owner_id         TEXT NOT NULL,
+    walker_id        TEXT NOT NULL,
+    region           TEXT NOT NULL DEFAULT '',
+    scheduled_at     TIMESTAMPTZ NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    quote            JSONB NOT NULL,
+    expires_at       TIMESTAMPTZ NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS slot_holds_walker_idx ON slot_holds (walker_id, scheduled_at);
+CREATE INDEX IF NOT EXISTS slot_holds_expiry_idx ON slot_holds (expires_at);
@@ -0,0 +1,268 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrDeviceNotFound is returned when the user has no device with the token
+var ErrDeviceNotFound = errors.New("device not found")
+
+// SaveDevice registers a device for a user's push notifications. A token already registered
+// moves to the user, as only the account signed in on a device should receive its notifications.
+func SaveDevice(ctx context.Context, device *models.Device) error {
+    if memory != nil {
+        return memory.saveDevice(device)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO devices (token, user_id, platform, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (token) DO UPDATE
+        SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform, updated_at = EXCLUDED.updated_at`,
+        device.Token,
+        device.UserID,
+        device.Platform,
+        device.CreatedAt,
+        device.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save device: %w", err)
+    }
+    return nil
+}
+
+// ListDevices retrieves the devices registered to a user, most recently registered first
+func ListDevices(ctx context.Context, userID string) ([]models.Device, error) {
+    if memory != nil {
+        return memory.listDevices(userID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT token, user_id, platform, created_at, updated_at
+        FROM devices
+        WHERE user_id = $1
+        ORDER BY updated_at DESC`,
+        userID,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list devices: %w", err)
+    }
+    defer rows.Close()
+
+    var devices []models.Device
+    for rows.Next() {
+        var d models.Device
+        if err := rows.Scan(&d.Token, &d.UserID, &d.Platform, &d.CreatedAt, &d.UpdatedAt); err != nil {
+            return nil, fmt.Errorf("failed to scan device: %w", err)
+        }
+        devices = append(devices, d)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list devices: %w", err)
+    }
+    return devices, nil
+}
+
+// DeleteDevice removes one of a user's devices
+func DeleteDevice(ctx context.Context, userID, token string) error {
+    if memory != nil {
+        return memory.deleteDevice(userID, token)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `DELETE FROM devices WHERE user_id = $1 AND token = $2`, userID, token)
+    if err != nil {
+        return fmt.Errorf("failed to delete device: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to delete device: %w", err)
+    }
+    if rows == 0 {
+        return ErrDeviceNotFound
+    }
+    return nil
+}
+
+// DeleteDeviceToken removes a device token whoever it is registered to, once the platform
+// reports it is no longer valid
+func DeleteDeviceToken(ctx context.Context, token string) error {
+    if memory != nil {
+        return memory.deleteDeviceToken(token)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    if _, err := DB.ExecContext(ctx, `DELETE FROM devices WHERE token = $1`, token); err != nil {
+        return fmt.Errorf("failed to delete device: %w", err)
+    }
+    return nil
+}
+
+// GetNotificationPreferences retrieves a user's notification preferences, or the defaults
+// when they have saved none
+func GetNotificationPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+    if memory != nil {
+        return memory.getNotificationPreferences(userID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    prefs := &models.NotificationPreferences{}
+    err := DB.QueryRowContext(ctx, `
+        SELECT user_id, push_enabled, muted_categories, locale, updated_at
+        FROM notification_preferences
+        WHERE user_id = $1`,
+        userID,
+    ).Scan(&prefs.UserID, &prefs.PushEnabled, pq.Array(&prefs.MutedCategories), &prefs.Locale, &prefs.UpdatedAt)
+    if err == sql.ErrNoRows {
+        return models.DefaultNotificationPreferences(userID), nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+    }
+    return prefs, nil
+}
+
+// SaveNotificationPreferences creates or replaces a user's notification preferences
+func SaveNotificationPreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+    if memory != nil {
+        return memory.saveNotificationPreferences(prefs)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO notification_preferences (user_id, push_enabled, muted_categories, locale, updated_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (user_id) DO UPDATE
+        SET push_enabled = EXCLUDED.push_enabled, muted_categories = EXCLUDED.muted_categories,
+            locale = EXCLUDED.locale, updated_at = EXCLUDED.updated_at`,
+        prefs.UserID,
+        prefs.PushEnabled,
+        pq.Array(prefs.MutedCategories),
+        prefs.Locale,
+        prefs.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save notification preferences: %w", err)
+    }
+    return nil
+}
+
+// CreateDeliveryReceipt records the outcome of a push notification
+func CreateDeliveryReceipt(ctx context.Context, receipt *models.DeliveryReceipt) error {
+    if memory != nil {
+        return memory.createDeliveryReceipt(receipt)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO delivery_receipts (id, user_id, platform, device_token, category, subject, status, message_id, error, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+        receipt.ID,
+        receipt.UserID,
+        receipt.Platform,
+        receipt.DeviceToken,
+        receipt.Category,
+        receipt.Subject,
+        receipt.Status,
+        receipt.MessageID,
+        receipt.Error,
+        receipt.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create delivery receipt: %w", err)
+    }
+    return nil
+}
+
+// ListDeliveryReceipts retrieves up to limit of a user's delivery receipts, newest first
+func ListDeliveryReceipts(ctx context.Context, userID string, limit int) ([]models.DeliveryReceipt, error) {
+    if memory != nil {
+        return memory.listDeliveryReceipts(userID, limit)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT id, user_id, platform, device_token, category, subject, status, message_id, error, created_at
+        FROM delivery_receipts
+        WHERE user_id = $1
+        ORDER BY created_at DESC
+        LIMIT $2`,
+        userID, limit,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list delivery receipts: %w", err)
+    }
+    defer rows.Close()
+
+    var receipts []models.DeliveryReceipt
+    for rows.Next() {
+        var r models.DeliveryReceipt
+        if err := rows.Scan(
+            &r.ID,
+            &r.UserID,
+            &r.Platform,
+            &r.DeviceToken,
+            &r.Category,
+            &r.Subject,
+            &r.Status,
+            &r.MessageID,
+            &r.Error,
+            &r.CreatedAt,
+        ); err != nil {
+            return nil, fmt.Errorf("failed to scan delivery receipt: %w", err)
+        }
+        receipts = append(receipts, r)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list delivery receipts: %w", err)
+    }
+    return receipts, nil
+}
+
+// DeleteDeliveryReceiptsBefore removes delivery receipts created before cutoff, returning how
+// many were removed
+func DeleteDeliveryReceiptsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+    if memory != nil {
+        return memory.deleteDeliveryReceiptsBefore(cutoff)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `DELETE FROM delivery_receipts WHERE created_at < $1`, cutoff)
+    if err != nil {
+        return 0, fmt.Errorf("failed to delete delivery receipts: %w", err)
+    }
+    deleted, err := result.RowsAffected()
+    if err != nil {
+        return 0, fmt.Errorf("failed to delete delivery receipts: %w", err)
+    }
+    return deleted, nil
+}
@@ -4,31 +4,47 @@ package repository
 import (
     "context"
     "database/sql"
+    "errors"
     "fmt"
-    _ "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
+    "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
     "time"
 
     "src/backend/booking-service/internal/models"
     "src/backend/booking-service/internal/config"
+    "src/backend/shared/dbmetrics"
 )
 
 // Human Tasks:
 // 1. Ensure PostgreSQL is installed and running
-// 2. Create the bookings table with appropriate schema
-// 3. Set up database indexes for frequently queried fields (id, owner_id, walker_id, scheduled_at)
-// 4. Configure connection pool settings based on load testing results
-// 5. Implement database monitoring and alerting
-// 6. Set up regular database backups
-// 7. Review and adjust query timeout settings based on performance requirements
+// 2. Apply the schema in migrations/ with Migrate (or BOOKING_DATABASE_MIGRATE=true) before serving traffic
+// 3. Configure connection pool settings based on load testing results
+// 4. Implement database monitoring and alerting
+// 5. Set up regular database backups
+// 6. Review and adjust query timeout settings based on performance requirements
 
 // DB is a global variable holding the database connection pool
 var DB *sql.DB
 
+// ErrSlotFull is returned when a walker has no remaining capacity in the requested time slot
+var ErrSlotFull = errors.New("walker has no remaining capacity for the requested time")
+
+// ErrBookingModified is returned when a booking changed after it was read to be updated
+var ErrBookingModified = errors.New("booking was changed by another request")
+
+// activeBookingStatuses are the statuses that occupy a walker's capacity
+var activeBookingStatuses = []models.BookingStatus{
+    models.BookingStatusPending,
+    models.BookingStatusConfirmed,
+    models.BookingStatusInProgress,
+}
+
 // InitDB initializes the database connection pool using the provided configuration
 // Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
 func InitDB(cfg *config.Config) error {
+    statements = dbmetrics.New("booking-service", cfg.SlowQueryThreshold)
+
     var err error
-    DB, err = sql.Open("postgres", cfg.DatabaseURL)
+    DB, err = openDB(cfg.DatabaseURL)
     if err != nil {
         return fmt.Errorf("failed to open database connection: %w", err)
     }
@@ -46,22 +62,26 @@ func InitDB(cfg *config.Config) error {
     return nil
 }
 
+// UseDB serves every repository function from db, a connection pool the caller opened, in
+// place of the in-memory store
+func UseDB(db *sql.DB) {
+    DB = db
+    memory = nil
+}
+
 // CreateBooking inserts a new booking record into the PostgreSQL database
 // Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
 func CreateBooking(ctx context.Context, booking *models.Booking) error {
-    query := `
-        INSERT INTO bookings (
-            id, owner_id, walker_id, dog_id, scheduled_at, status, amount
-        ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7
-        )`
+    if memory != nil {
+        return memory.createBooking(booking)
+    }
 
     // Create context with timeout for the database operation
     ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
     defer cancel()
 
     // Execute the insert query
-    _, err := DB.ExecContext(ctx, query,
+    _, err := execPrepared(ctx, DB, createBookingQuery,
         booking.ID,
         booking.OwnerID,
         booking.WalkerID,
@@ -69,6 +89,15 @@ func CreateBooking(ctx context.Context, booking *models.Booking) error {
         booking.ScheduledAt,
         booking.Status,
         booking.Amount,
+        booking.DurationMinutes,
+        booking.AcceptBy,
+        booking.Tax,
+        booking.Region,
+        booking.Rate,
+        booking.Cancellation,
+        booking.Latitude,
+        booking.Longitude,
+        booking.Address,
     )
 
     if err != nil {
@@ -78,20 +107,327 @@ func CreateBooking(ctx context.Context, booking *models.Booking) error {
     return nil
 }
 
-// GetBookingByID retrieves a booking record from the PostgreSQL database by its ID
+// GetBookingByID retrieves a booking record from the PostgreSQL database by its ID. With event
+// sourcing enabled the booking is rebuilt from its events instead of read from its row, always
+// on the primary; otherwise a context marked with ReplicaReads may be served by a replica.
 // Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
 func GetBookingByID(ctx context.Context, id string) (*models.Booking, error) {
+    if memory != nil {
+        return memory.getBookingByID(id)
+    }
+    if snapshotInterval > 0 {
+        return foldBooking(ctx, id, 0)
+    }
+
+    // Create context with timeout for the database operation
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    booking := &models.Booking{}
+    err := readFromReplica(ctx, func(db *sql.DB) error {
+        return queryRowPrepared(ctx, db, getBookingQuery, id).Scan(
+            &booking.ID,
+            &booking.OwnerID,
+            &booking.WalkerID,
+            &booking.DogID,
+            &booking.ScheduledAt,
+            &booking.Status,
+            &booking.Amount,
+            &booking.DurationMinutes,
+            &booking.AcceptBy,
+            &booking.Tax,
+            &booking.Region,
+            &booking.Rate,
+            &booking.Cancellation,
+            &booking.Latitude,
+            &booking.Longitude,
+            &booking.Address,
+        )
+    })
+
+    if err == sql.ErrNoRows {
+        return nil, fmt.Errorf("booking not found with id: %s", id)
+    }
+
+    if err != nil {
+        return nil, fmt.Errorf("failed to get booking: %w", err)
+    }
+
+    return booking, nil
+}
+
+// ListBookings retrieves up to limit bookings matching filter, most recently scheduled first
+// with ties broken by descending ID, so the order is total and pages never overlap or skip.
+// When after is set, only bookings listed after it are returned. A context marked with
+// ReplicaReads may be served by a replica.
+func ListBookings(ctx context.Context, filter models.BookingFilter, after *models.BookingCursor, limit int) ([]models.Booking, error) {
+    if memory != nil {
+        return memory.listBookings(filter, after, limit)
+    }
+
+    // Seeking on (scheduled_at, id) rather than skipping OFFSET rows keeps pages stable while
+    // bookings are inserted, and uses bookings_schedule_idx however deep the page
     query := `
-        SELECT id, owner_id, walker_id, dog_id, scheduled_at, status, amount
+        SELECT id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
         FROM bookings
-        WHERE id = $1`
+        WHERE ($1 = '' OR status = $1)
+          AND ($2 = '' OR walker_id = $2)
+          AND ($3 = '' OR owner_id = $3)
+          AND ($5 = '' OR region = $5)
+          AND (NOT $6 OR (scheduled_at, id) < ($7, $8))
+          AND ($9::text[] IS NULL OR id = ANY($9))
+        ORDER BY scheduled_at DESC, id DESC
+        LIMIT $4`
+
+    var afterAt time.Time
+    var afterID string
+    if after != nil {
+        afterAt, afterID = after.ScheduledAt, after.ID
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    var bookings []models.Booking
+    err := readFromReplica(ctx, func(db *sql.DB) error {
+        bookings = nil
+        rows, err := db.QueryContext(ctx, query, filter.Status, filter.WalkerID, filter.OwnerID, limit, filter.Region,
+            after != nil, afterAt, afterID, pq.Array(filter.IDs))
+        if err != nil {
+            return err
+        }
+        defer rows.Close()
+
+        for rows.Next() {
+            var b models.Booking
+            if err := rows.Scan(
+                &b.ID,
+                &b.OwnerID,
+                &b.WalkerID,
+                &b.DogID,
+                &b.ScheduledAt,
+                &b.Status,
+                &b.Amount,
+                &b.DurationMinutes,
+                &b.AcceptBy,
+                &b.Tax,
+                &b.Region,
+                &b.Rate,
+                &b.Cancellation,
+                &b.Latitude,
+                &b.Longitude,
+                &b.Address,
+            ); err != nil {
+                return fmt.Errorf("failed to scan booking: %w", err)
+            }
+            bookings = append(bookings, b)
+        }
+        return rows.Err()
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to list bookings: %w", err)
+    }
+
+    return bookings, nil
+}
+
+// SummarizeBookings counts the bookings matching filter by status in one aggregate query,
+// served by a replica when the context is marked with ReplicaReads
+func SummarizeBookings(ctx context.Context, filter models.BookingFilter) (*models.BookingSummary, error) {
+    if memory != nil {
+        return memory.summarizeBookings(filter)
+    }
+
+    query := `
+        SELECT status, COUNT(*)
+        FROM bookings
+        WHERE ($1 = '' OR status = $1)
+          AND ($2 = '' OR walker_id = $2)
+          AND ($3 = '' OR owner_id = $3)
+          AND ($4 = '' OR region = $4)
+          AND ($5::text[] IS NULL OR id = ANY($5))
+        GROUP BY status`
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    var summary *models.BookingSummary
+    err := readFromReplica(ctx, func(db *sql.DB) error {
+        summary = models.NewBookingSummary()
+        rows, err := db.QueryContext(ctx, query, filter.Status, filter.WalkerID, filter.OwnerID, filter.Region, pq.Array(filter.IDs))
+        if err != nil {
+            return err
+        }
+        defer rows.Close()
+
+        for rows.Next() {
+            var status models.BookingStatus
+            var count int
+            if err := rows.Scan(&status, &count); err != nil {
+                return fmt.Errorf("failed to scan booking count: %w", err)
+            }
+            summary.Add(status, count)
+        }
+        return rows.Err()
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to summarize bookings: %w", err)
+    }
+    return summary, nil
+}
+
+// CreateBookingWithinCapacity inserts a booking only if the walker has fewer than capacity
+// active bookings overlapping it. The walker is locked for the duration of the transaction so
+// concurrent requests cannot both take the last place. adjust is called inside the transaction
+// with the number of overlapping bookings, before the insert, to apply per-dog pricing and
+// tax; an error from adjust aborts the booking.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CreateBookingWithinCapacity(ctx context.Context, booking *models.Booking, capacity int, adjust func(booking *models.Booking, overlapping int) error) error {
+    if memory != nil {
+        return memory.createBookingWithinCapacity(booking, capacity, adjust)
+    }
 
-    // Create context with timeout for the database operation
     ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
     defer cancel()
 
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    // Serialize bookings per walker until the transaction ends
+    if err := lockWalker(ctx, tx, booking.WalkerID); err != nil {
+        return err
+    }
+
+    // The owner's own hold on the slot gives its place to the booking
+    overlapping, err := countOverlapping(ctx, tx, booking.WalkerID, booking.ScheduledAt, booking.EndsAt(), booking.HoldID)
+    if err != nil {
+        return err
+    }
+
+    if overlapping >= capacity {
+        return ErrSlotFull
+    }
+
+    if adjust != nil {
+        if err := adjust(booking, overlapping); err != nil {
+            return err
+        }
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        INSERT INTO bookings (
+            id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+        )`,
+        booking.ID,
+        booking.OwnerID,
+        booking.WalkerID,
+        booking.DogID,
+        booking.ScheduledAt,
+        booking.Status,
+        booking.Amount,
+        booking.DurationMinutes,
+        booking.AcceptBy,
+        booking.Tax,
+        booking.Region,
+        booking.Rate,
+        booking.Cancellation,
+        booking.Latitude,
+        booking.Longitude,
+        booking.Address,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create booking: %w", err)
+    }
+
+    if booking.HoldID != "" {
+        if _, err := tx.ExecContext(ctx, `DELETE FROM slot_holds WHERE id = $1`, booking.HoldID); err != nil {
+            return fmt.Errorf("failed to release slot hold: %w", err)
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit booking: %w", err)
+    }
+    return nil
+}
+
+// UpdateBookingDetails saves the owner-editable details of updated: its dog, schedule, pickup
+// point and address, region, amount and tax. The booking must still be as it was when read as
+// original, so a change made meanwhile, such as a walker being assigned, is never overwritten;
+// otherwise ErrBookingModified is returned.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func UpdateBookingDetails(ctx context.Context, original, updated *models.Booking) error {
+    if memory != nil {
+        return memory.updateBookingDetails(original, updated)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    stored, err := getBookingForUpdate(ctx, tx, original.ID)
+    if err != nil {
+        return err
+    }
+    if detailsChanged(original, stored) {
+        return ErrBookingModified
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        UPDATE bookings
+        SET dog_id = $2, scheduled_at = $3, duration_minutes = $4, latitude = $5, longitude = $6, region = $7, tax = $8, address = $9, amount = $10
+        WHERE id = $1`,
+        updated.ID,
+        updated.DogID,
+        updated.ScheduledAt,
+        updated.DurationMinutes,
+        updated.Latitude,
+        updated.Longitude,
+        updated.Region,
+        updated.Tax,
+        updated.Address,
+        updated.Amount,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to update booking: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit booking update: %w", err)
+    }
+    return nil
+}
+
+// detailsChanged reports whether the booking's status, walker or any detail UpdateBookingDetails
+// saves differs between a and b
+func detailsChanged(a, b *models.Booking) bool {
+    sameFloat := func(x, y *float64) bool { return (x == nil && y == nil) || (x != nil && y != nil && *x == *y) }
+    return a.Status != b.Status || a.WalkerID != b.WalkerID || a.DogID != b.DogID || a.Amount != b.Amount ||
+        !a.ScheduledAt.Equal(b.ScheduledAt) || a.DurationMinutes != b.DurationMinutes ||
+        !sameFloat(a.Latitude, b.Latitude) || !sameFloat(a.Longitude, b.Longitude) || a.Region != b.Region ||
+        !a.Address.SamePlace(b.Address)
+}
+
+// getBookingForUpdate reads a booking and locks its row until tx ends
+func getBookingForUpdate(ctx context.Context, tx *sql.Tx, id string) (*models.Booking, error) {
     booking := &models.Booking{}
-    err := DB.QueryRowContext(ctx, query, id).Scan(
+    err := tx.QueryRowContext(ctx, `
+        SELECT id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
+        FROM bookings
+        WHERE id = $1
+        FOR UPDATE`,
+        id,
+    ).Scan(
         &booking.ID,
         &booking.OwnerID,
         &booking.WalkerID,
@@ -99,6 +435,15 @@ func GetBookingByID(ctx context.Context, id string) (*models.Booking, error) {
         &booking.ScheduledAt,
         &booking.Status,
         &booking.Amount,
+        &booking.DurationMinutes,
+        &booking.AcceptBy,
+        &booking.Tax,
+        &booking.Region,
+        &booking.Rate,
+        &booking.Cancellation,
+        &booking.Latitude,
+        &booking.Longitude,
+        &booking.Address,
     )
 
     if err == sql.ErrNoRows {
@@ -112,8 +457,183 @@ func GetBookingByID(ctx context.Context, id string) (*models.Booking, error) {
     return booking, nil
 }
 
-// Close closes the database connection pool
+// lockWalker serializes capacity-checked writes for a walker until tx ends
+func lockWalker(ctx context.Context, tx *sql.Tx, walkerID string) error {
+    if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, walkerID); err != nil {
+        return fmt.Errorf("failed to lock walker schedule: %w", err)
+    }
+    return nil
+}
+
+// countOverlapping counts the walker's active bookings and unexpired slot holds overlapping
+// [start, end), ignoring the booking or hold excludeID
+func countOverlapping(ctx context.Context, tx *sql.Tx, walkerID string, start, end time.Time, excludeID string) (int, error) {
+    var overlapping int
+    err := tx.QueryRowContext(ctx, `
+        SELECT (
+            SELECT COUNT(*)
+            FROM bookings
+            WHERE walker_id = $1
+              AND id <> $2
+              AND status = ANY($3)
+              AND scheduled_at < $4
+              AND scheduled_at + make_interval(mins => duration_minutes) > $5
+        ) + (
+            SELECT COUNT(*)
+            FROM slot_holds
+            WHERE walker_id = $1
+              AND id <> $2
+              AND expires_at > NOW()
+              AND scheduled_at < $4
+              AND scheduled_at + make_interval(mins => duration_minutes) > $5
+        )`,
+        walkerID,
+        excludeID,
+        pq.Array(activeBookingStatuses),
+        end,
+        start,
+    ).Scan(&overlapping)
+    if err != nil {
+        return 0, fmt.Errorf("failed to count overlapping bookings: %w", err)
+    }
+    return overlapping, nil
+}
+
+// CreateAvailability inserts a walker availability window
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CreateAvailability(ctx context.Context, availability *models.Availability) error {
+    if memory != nil {
+        return memory.createAvailability(availability)
+    }
+
+    query := `
+        INSERT INTO walker_availability (
+            id, walker_id, starts_at, ends_at, capacity, group_discount_percent, region
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7
+        )`
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, query,
+        availability.ID,
+        availability.WalkerID,
+        availability.StartsAt,
+        availability.EndsAt,
+        availability.Capacity,
+        availability.GroupDiscountPercent,
+        availability.Region,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create availability: %w", err)
+    }
+
+    return nil
+}
+
+// GetAvailabilityCovering retrieves the walker's availability window containing [start, end).
+// Returns nil without error if the walker has not published one.
+func GetAvailabilityCovering(ctx context.Context, walkerID string, start, end time.Time) (*models.Availability, error) {
+    if memory != nil {
+        return memory.getAvailabilityCovering(walkerID, start, end)
+    }
+
+    query := `
+        SELECT id, walker_id, starts_at, ends_at, capacity, group_discount_percent, region
+        FROM walker_availability
+        WHERE walker_id = $1 AND starts_at <= $2 AND ends_at >= $3
+        ORDER BY starts_at DESC
+        LIMIT 1`
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    availability := &models.Availability{}
+    err := DB.QueryRowContext(ctx, query, walkerID, start, end).Scan(
+        &availability.ID,
+        &availability.WalkerID,
+        &availability.StartsAt,
+        &availability.EndsAt,
+        &availability.Capacity,
+        &availability.GroupDiscountPercent,
+        &availability.Region,
+    )
+
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+
+    if err != nil {
+        return nil, fmt.Errorf("failed to get availability: %w", err)
+    }
+
+    return availability, nil
+}
+
+// ListAvailability retrieves a walker's availability windows ending after from
+func ListAvailability(ctx context.Context, walkerID string, from time.Time) ([]models.Availability, error) {
+    if memory != nil {
+        return memory.listAvailability(walkerID, from)
+    }
+
+    query := `
+        SELECT id, walker_id, starts_at, ends_at, capacity, group_discount_percent, region
+        FROM walker_availability
+        WHERE walker_id = $1 AND ends_at > $2
+        ORDER BY starts_at`
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, query, walkerID, from)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list availability: %w", err)
+    }
+    defer rows.Close()
+
+    var windows []models.Availability
+    for rows.Next() {
+        var availability models.Availability
+        if err := rows.Scan(
+            &availability.ID,
+            &availability.WalkerID,
+            &availability.StartsAt,
+            &availability.EndsAt,
+            &availability.Capacity,
+            &availability.GroupDiscountPercent,
+            &availability.Region,
+        ); err != nil {
+            return nil, fmt.Errorf("failed to scan availability: %w", err)
+        }
+        windows = append(windows, availability)
+    }
+
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list availability: %w", err)
+    }
+
+    return windows, nil
+}
+
+// Ping verifies the database is reachable; used by the readiness probe
+func Ping(ctx context.Context) error {
+    if memory != nil {
+        return nil
+    }
+    if DB == nil {
+        return errors.New("database not initialized")
+    }
+    return DB.PingContext(ctx)
+}
+
+// Close closes the database connection pool and those of any read replicas
 func Close() error {
+    CloseStatements()
+    if replicas != nil {
+        replicas.close()
+        replicas = nil
+    }
     if DB != nil {
         return DB.Close()
     }
@@ -0,0 +1,76 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// GetRatePlan retrieves a walker's rate plan, or nil when they have not set one
+func GetRatePlan(ctx context.Context, walkerID string) (*models.RatePlan, error) {
+    if memory != nil {
+        return memory.getRatePlan(walkerID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    plan := &models.RatePlan{}
+    err := DB.QueryRowContext(ctx, `
+        SELECT walker_id, base_rate, weekend_surcharge_percent, large_dog_surcharge, extra_dog_surcharge, time_zone, updated_at
+        FROM rate_plans
+        WHERE walker_id = $1`,
+        walkerID,
+    ).Scan(
+        &plan.WalkerID,
+        &plan.BaseRate,
+        &plan.WeekendSurchargePercent,
+        &plan.LargeDogSurcharge,
+        &plan.ExtraDogSurcharge,
+        &plan.TimeZone,
+        &plan.UpdatedAt,
+    )
+
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get rate plan: %w", err)
+    }
+    return plan, nil
+}
+
+// SaveRatePlan creates or replaces a walker's rate plan. Bookings already priced keep the
+// rate they were priced at.
+func SaveRatePlan(ctx context.Context, plan *models.RatePlan) error {
+    if memory != nil {
+        return memory.saveRatePlan(plan)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO rate_plans (walker_id, base_rate, weekend_surcharge_percent, large_dog_surcharge, extra_dog_surcharge, time_zone, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (walker_id) DO UPDATE
+        SET base_rate = EXCLUDED.base_rate, weekend_surcharge_percent = EXCLUDED.weekend_surcharge_percent,
+            large_dog_surcharge = EXCLUDED.large_dog_surcharge, extra_dog_surcharge = EXCLUDED.extra_dog_surcharge,
+            time_zone = EXCLUDED.time_zone, updated_at = EXCLUDED.updated_at`,
+        plan.WalkerID,
+        plan.BaseRate,
+        plan.WeekendSurchargePercent,
+        plan.LargeDogSurcharge,
+        plan.ExtraDogSurcharge,
+        plan.TimeZone,
+        plan.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save rate plan: %w", err)
+    }
+    return nil
+}
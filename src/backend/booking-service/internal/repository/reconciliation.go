@@ -0,0 +1,298 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrReconciliationRunNotFound is returned when no reconciliation run exists with the requested ID
+var ErrReconciliationRunNotFound = errors.New("reconciliation run not found")
+
+// reconciliationRunColumns is the column list scanned by scanReconciliationRun
+const reconciliationRunColumns = `id, period_start, period_end, status, started_at, completed_at,
+    bookings_checked, discrepancy_count, discrepancy_cents, error`
+
+// scanReconciliationRun scans a row selected with reconciliationRunColumns
+func scanReconciliationRun(row interface{ Scan(...interface{}) error }) (*models.ReconciliationRun, error) {
+    run := &models.ReconciliationRun{}
+    err := row.Scan(
+        &run.ID,
+        &run.PeriodStart,
+        &run.PeriodEnd,
+        &run.Status,
+        &run.StartedAt,
+        &run.CompletedAt,
+        &run.BookingsChecked,
+        &run.DiscrepancyCount,
+        &run.DiscrepancyCents,
+        &run.Error,
+    )
+    if err != nil {
+        return nil, err
+    }
+    return run, nil
+}
+
+// ListBookingsScheduledBetween retrieves every booking scheduled in [from, to)
+func ListBookingsScheduledBetween(ctx context.Context, from, to time.Time) ([]models.Booking, error) {
+    if memory != nil {
+        return memory.listBookingsScheduledBetween(from, to)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
+        FROM bookings
+        WHERE scheduled_at >= $1 AND scheduled_at < $2
+        ORDER BY scheduled_at`,
+        from,
+        to,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list bookings: %w", err)
+    }
+    defer rows.Close()
+
+    var bookings []models.Booking
+    for rows.Next() {
+        var b models.Booking
+        if err := rows.Scan(
+            &b.ID,
+            &b.OwnerID,
+            &b.WalkerID,
+            &b.DogID,
+            &b.ScheduledAt,
+            &b.Status,
+            &b.Amount,
+            &b.DurationMinutes,
+            &b.AcceptBy,
+            &b.Tax,
+            &b.Region,
+            &b.Rate,
+            &b.Cancellation,
+            &b.Latitude,
+            &b.Longitude,
+            &b.Address,
+        ); err != nil {
+            return nil, fmt.Errorf("failed to scan booking: %w", err)
+        }
+        bookings = append(bookings, b)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list bookings: %w", err)
+    }
+    return bookings, nil
+}
+
+// ClaimReconciliationRun records run as running for its period, so only one instance
+// reconciles a period. A period whose earlier run failed before retryBefore is claimed again,
+// keeping that run's ID. Reports false when the period is already done or being reconciled.
+func ClaimReconciliationRun(ctx context.Context, run *models.ReconciliationRun, retryBefore time.Time) (bool, error) {
+    if memory != nil {
+        return memory.claimReconciliationRun(run, retryBefore)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    err := DB.QueryRowContext(ctx, `
+        INSERT INTO reconciliation_runs (id, period_start, period_end, status, started_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (period_start) DO UPDATE
+        SET status = EXCLUDED.status, started_at = EXCLUDED.started_at, completed_at = NULL, error = ''
+        WHERE reconciliation_runs.status = $6 AND reconciliation_runs.started_at < $7
+        RETURNING id`,
+        run.ID,
+        run.PeriodStart,
+        run.PeriodEnd,
+        models.ReconciliationRunning,
+        run.StartedAt,
+        models.ReconciliationFailed,
+        retryBefore,
+    ).Scan(&run.ID)
+
+    if err == sql.ErrNoRows {
+        return false, nil
+    }
+    if err != nil {
+        return false, fmt.Errorf("failed to claim reconciliation run: %w", err)
+    }
+    run.Status = models.ReconciliationRunning
+    return true, nil
+}
+
+// CompleteReconciliationRun stores the discrepancies a run found and marks it completed
+func CompleteReconciliationRun(ctx context.Context, run *models.ReconciliationRun, discrepancies []models.ReconciliationDiscrepancy) error {
+    if memory != nil {
+        return memory.completeReconciliationRun(run, discrepancies)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    for _, d := range discrepancies {
+        _, err := tx.ExecContext(ctx, `
+            INSERT INTO reconciliation_discrepancies (run_id, booking_id, kind, booking_status,
+                expected_cents, captured_cents, refunded_cents, difference_cents, currency)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+            run.ID,
+            d.BookingID,
+            d.Kind,
+            d.BookingStatus,
+            d.ExpectedCents,
+            d.CapturedCents,
+            d.RefundedCents,
+            d.DifferenceCents,
+            d.Currency,
+        )
+        if err != nil {
+            return fmt.Errorf("failed to store reconciliation discrepancy: %w", err)
+        }
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        UPDATE reconciliation_runs
+        SET status = $2, completed_at = $3, bookings_checked = $4, discrepancy_count = $5, discrepancy_cents = $6
+        WHERE id = $1`,
+        run.ID,
+        models.ReconciliationCompleted,
+        run.CompletedAt,
+        run.BookingsChecked,
+        run.DiscrepancyCount,
+        run.DiscrepancyCents,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to complete reconciliation run: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit reconciliation run: %w", err)
+    }
+    run.Status = models.ReconciliationCompleted
+    return nil
+}
+
+// FailReconciliationRun marks a run failed with the reason it stopped
+func FailReconciliationRun(ctx context.Context, id, reason string, at time.Time) error {
+    if memory != nil {
+        return memory.failReconciliationRun(id, reason, at)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        UPDATE reconciliation_runs SET status = $2, completed_at = $3, error = $4 WHERE id = $1`,
+        id,
+        models.ReconciliationFailed,
+        at,
+        reason,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to mark reconciliation run failed: %w", err)
+    }
+    return nil
+}
+
+// ListReconciliationRuns retrieves up to limit runs, most recent period first, without their discrepancies
+func ListReconciliationRuns(ctx context.Context, limit int) ([]models.ReconciliationRun, error) {
+    if memory != nil {
+        return memory.listReconciliationRuns(limit)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT `+reconciliationRunColumns+`
+        FROM reconciliation_runs
+        ORDER BY period_start DESC
+        LIMIT $1`,
+        limit,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list reconciliation runs: %w", err)
+    }
+    defer rows.Close()
+
+    var runs []models.ReconciliationRun
+    for rows.Next() {
+        run, err := scanReconciliationRun(rows)
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan reconciliation run: %w", err)
+        }
+        runs = append(runs, *run)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list reconciliation runs: %w", err)
+    }
+    return runs, nil
+}
+
+// GetReconciliationRun retrieves a run with its discrepancies
+func GetReconciliationRun(ctx context.Context, id string) (*models.ReconciliationRun, error) {
+    if memory != nil {
+        return memory.getReconciliationRun(id)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    run, err := scanReconciliationRun(DB.QueryRowContext(ctx, `
+        SELECT `+reconciliationRunColumns+` FROM reconciliation_runs WHERE id = $1`, id))
+    if err == sql.ErrNoRows {
+        return nil, ErrReconciliationRunNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get reconciliation run: %w", err)
+    }
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT run_id, booking_id, kind, booking_status, expected_cents, captured_cents,
+               refunded_cents, difference_cents, currency
+        FROM reconciliation_discrepancies
+        WHERE run_id = $1
+        ORDER BY kind, booking_id`,
+        id,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list reconciliation discrepancies: %w", err)
+    }
+    defer rows.Close()
+
+    for rows.Next() {
+        var d models.ReconciliationDiscrepancy
+        if err := rows.Scan(
+            &d.RunID,
+            &d.BookingID,
+            &d.Kind,
+            &d.BookingStatus,
+            &d.ExpectedCents,
+            &d.CapturedCents,
+            &d.RefundedCents,
+            &d.DifferenceCents,
+            &d.Currency,
+        ); err != nil {
+            return nil, fmt.Errorf("failed to scan reconciliation discrepancy: %w", err)
+        }
+        run.Discrepancies = append(run.Discrepancies, d)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list reconciliation discrepancies: %w", err)
+    }
+    return run, nil
+}
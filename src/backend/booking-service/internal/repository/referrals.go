@@ -0,0 +1,244 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/lib/pq" // v1.10.0 - PostgreSQL driver
+
+    "src/backend/booking-service/internal/models"
+)
+
+var (
+    // ErrReferralCodeNotFound is returned when a referral code does not exist
+    ErrReferralCodeNotFound = errors.New("referral code not found")
+
+    // ErrReferralCodeTaken is returned when a newly generated code collides with an existing one
+    ErrReferralCodeTaken = errors.New("referral code already in use")
+
+    // ErrAlreadyReferred is returned when a user has already been attributed to a referrer
+    ErrAlreadyReferred = errors.New("user has already been referred")
+)
+
+// uniqueViolation is the PostgreSQL error code for unique constraint violations
+const uniqueViolation = "23505"
+
+// GetReferralCodeForUser retrieves the referral code owned by a user, or nil if they have none
+func GetReferralCodeForUser(ctx context.Context, userID string) (*models.ReferralCode, error) {
+    if memory != nil {
+        return memory.getReferralCodeForUser(userID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    code := &models.ReferralCode{}
+    err := DB.QueryRowContext(ctx, `
+        SELECT code, user_id, created_at FROM referral_codes WHERE user_id = $1`,
+        userID,
+    ).Scan(&code.Code, &code.UserID, &code.CreatedAt)
+
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get referral code: %w", err)
+    }
+    return code, nil
+}
+
+// CreateReferralCode stores a code for a user. If the user already has a code, that code is
+// returned instead; ErrReferralCodeTaken means the candidate code belongs to someone else.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CreateReferralCode(ctx context.Context, code *models.ReferralCode) (*models.ReferralCode, error) {
+    if memory != nil {
+        return memory.createReferralCode(code)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO referral_codes (code, user_id, created_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (user_id) DO NOTHING`,
+        code.Code,
+        code.UserID,
+        code.CreatedAt,
+    )
+    var pqErr *pq.Error
+    if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+        return nil, ErrReferralCodeTaken
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to create referral code: %w", err)
+    }
+
+    return GetReferralCodeForUser(ctx, code.UserID)
+}
+
+// GetReferralCode looks up a referral code
+func GetReferralCode(ctx context.Context, code string) (*models.ReferralCode, error) {
+    if memory != nil {
+        return memory.getReferralCode(code)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result := &models.ReferralCode{}
+    err := DB.QueryRowContext(ctx, `
+        SELECT code, user_id, created_at FROM referral_codes WHERE code = $1`,
+        code,
+    ).Scan(&result.Code, &result.UserID, &result.CreatedAt)
+
+    if err == sql.ErrNoRows {
+        return nil, ErrReferralCodeNotFound
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get referral code: %w", err)
+    }
+    return result, nil
+}
+
+// CreateReferral attributes a referred user to a referrer; each user can be referred only once
+func CreateReferral(ctx context.Context, referral *models.Referral) error {
+    if memory != nil {
+        return memory.createReferral(referral)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `
+        INSERT INTO referrals (id, code, referrer_id, referee_id, status, created_at, credit_amount)
+        VALUES ($1, $2, $3, $4, $5, $6, 0)
+        ON CONFLICT (referee_id) DO NOTHING`,
+        referral.ID,
+        referral.Code,
+        referral.ReferrerID,
+        referral.RefereeID,
+        referral.Status,
+        referral.CreatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create referral: %w", err)
+    }
+
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to create referral: %w", err)
+    }
+    if rows == 0 {
+        return ErrAlreadyReferred
+    }
+    return nil
+}
+
+// ConvertReferral marks a user's referral converted when bookingID is their first booking,
+// granting credit to the referrer. It returns nil when there is nothing to convert.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ConvertReferral(ctx context.Context, refereeID, bookingID string, credit float64, at time.Time) (*models.Referral, error) {
+    if memory != nil {
+        return memory.convertReferral(refereeID, bookingID, credit, at)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    referral := &models.Referral{}
+    err := DB.QueryRowContext(ctx, `
+        UPDATE referrals
+        SET status = $3, booking_id = $2, converted_at = $4, credit_amount = $5
+        WHERE referee_id = $1
+          AND status = $6
+          AND NOT EXISTS (SELECT 1 FROM bookings WHERE owner_id = $1 AND id <> $2)
+        RETURNING id, code, referrer_id, referee_id, status, created_at, booking_id, converted_at, credit_amount`,
+        refereeID,
+        bookingID,
+        models.ReferralStatusConverted,
+        at,
+        credit,
+        models.ReferralStatusSignedUp,
+    ).Scan(
+        &referral.ID,
+        &referral.Code,
+        &referral.ReferrerID,
+        &referral.RefereeID,
+        &referral.Status,
+        &referral.CreatedAt,
+        &referral.BookingID,
+        &referral.ConvertedAt,
+        &referral.CreditAmount,
+    )
+
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to convert referral: %w", err)
+    }
+    return referral, nil
+}
+
+// CountBookingsByOwner counts the bookings a user has made
+func CountBookingsByOwner(ctx context.Context, ownerID string) (int, error) {
+    if memory != nil {
+        return memory.countBookingsByOwner(ownerID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    var count int
+    if err := DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM bookings WHERE owner_id = $1`, ownerID).Scan(&count); err != nil {
+        return 0, fmt.Errorf("failed to count bookings: %w", err)
+    }
+    return count, nil
+}
+
+// GetReferralReport summarises signups, conversions and credits per referrer for referrals
+// created in [from, to)
+func GetReferralReport(ctx context.Context, from, to time.Time) ([]models.ReferralSummary, error) {
+    if memory != nil {
+        return memory.getReferralReport(from, to)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT referrer_id,
+               COUNT(*) AS signups,
+               COUNT(*) FILTER (WHERE status = $3) AS conversions,
+               COALESCE(SUM(credit_amount), 0) AS credits_granted
+        FROM referrals
+        WHERE created_at >= $1 AND created_at < $2
+        GROUP BY referrer_id
+        ORDER BY conversions DESC, referrer_id`,
+        from,
+        to,
+        models.ReferralStatusConverted,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to query referral report: %w", err)
+    }
+    defer rows.Close()
+
+    var report []models.ReferralSummary
+    for rows.Next() {
+        var s models.ReferralSummary
+        if err := rows.Scan(&s.ReferrerID, &s.Signups, &s.Conversions, &s.CreditsGranted); err != nil {
+            return nil, fmt.Errorf("failed to scan referral report: %w", err)
+        }
+        report = append(report, s)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to read referral report: %w", err)
+    }
+    return report, nil
+}
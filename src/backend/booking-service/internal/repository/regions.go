@@ -0,0 +1,103 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/shared/regions"
+)
+
+// ErrRegionNotFound is returned when no region matches
+var ErrRegionNotFound = errors.New("region not found")
+
+// ListRegions retrieves every service region, by ID
+func ListRegions(ctx context.Context) ([]regions.Region, error) {
+    if memory != nil {
+        return memory.listRegions()
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `SELECT id, name, boundary, updated_at FROM regions ORDER BY id`)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list regions: %w", err)
+    }
+    defer rows.Close()
+
+    var list []regions.Region
+    for rows.Next() {
+        var (
+            region   regions.Region
+            boundary []byte
+        )
+        if err := rows.Scan(&region.ID, &region.Name, &boundary, &region.UpdatedAt); err != nil {
+            return nil, fmt.Errorf("failed to scan region: %w", err)
+        }
+        if err := json.Unmarshal(boundary, &region.Boundary); err != nil {
+            return nil, fmt.Errorf("failed to decode boundary of region %s: %w", region.ID, err)
+        }
+        list = append(list, region)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list regions: %w", err)
+    }
+    return list, nil
+}
+
+// SaveRegion creates the region or replaces the name and boundary of the one with its ID
+func SaveRegion(ctx context.Context, region *regions.Region) error {
+    if memory != nil {
+        return memory.saveRegion(region)
+    }
+
+    boundary, err := json.Marshal(region.Boundary)
+    if err != nil {
+        return fmt.Errorf("failed to encode region boundary: %w", err)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err = DB.ExecContext(ctx, `
+        INSERT INTO regions (id, name, boundary, updated_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (id) DO UPDATE
+        SET name = EXCLUDED.name, boundary = EXCLUDED.boundary, updated_at = EXCLUDED.updated_at`,
+        region.ID,
+        region.Name,
+        boundary,
+        region.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save region: %w", err)
+    }
+    return nil
+}
+
+// DeleteRegion removes a region. Bookings already tagged with it keep the tag.
+func DeleteRegion(ctx context.Context, id string) error {
+    if memory != nil {
+        return memory.deleteRegion(id)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `DELETE FROM regions WHERE id = $1`, id)
+    if err != nil {
+        return fmt.Errorf("failed to delete region: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to delete region: %w", err)
+    }
+    if rows == 0 {
+        return ErrRegionNotFound
+    }
+    return nil
+}
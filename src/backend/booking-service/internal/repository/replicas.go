@@ -0,0 +1,203 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "log"
+    "sync"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+)
+
+// replicaCheckInterval is how often replicas are checked for health and lag
+const replicaCheckInterval = 5 * time.Second
+
+// replica is a read replica of the database
+type replica struct {
+    name string
+    db   *sql.DB
+
+    // healthy is set while the replica answers and is within the allowed lag of the primary
+    healthy bool
+}
+
+// replicaSet spreads reads across the healthy replicas
+type replicaSet struct {
+    mu       sync.Mutex
+    replicas []*replica
+    next     int
+    maxLag   time.Duration
+}
+
+// replicas are the read replicas bookings may be read from; nil when none are configured
+var replicas *replicaSet
+
+// replicaReadsKey marks a context whose booking reads may be served by a replica
+type replicaReadsKey struct{}
+
+// ReplicaReads marks reads made with the returned context as tolerating replica lag, up to
+// the configured maximum. Reads are only sent to replicas when marked, so a request reading
+// back what it just wrote always sees its write.
+func ReplicaReads(ctx context.Context) context.Context {
+    return context.WithValue(ctx, replicaReadsKey{}, true)
+}
+
+// InitReplicas opens connection pools to the configured read replicas. A replica that cannot
+// be reached is not an error: reads go to the primary until it passes a health check.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func InitReplicas(ctx context.Context, cfg *config.Config) error {
+    if len(cfg.ReplicaURLs) == 0 {
+        return nil
+    }
+
+    set := &replicaSet{maxLag: cfg.ReplicaMaxLag}
+    for i, url := range cfg.ReplicaURLs {
+        db, err := openDB(url)
+        if err != nil {
+            set.close()
+            return fmt.Errorf("failed to open replica %d: %w", i+1, err)
+        }
+        db.SetMaxOpenConns(25)
+        db.SetMaxIdleConns(5)
+        db.SetConnMaxLifetime(5 * time.Minute)
+        // Replica URLs carry credentials, so replicas are logged by position
+        set.replicas = append(set.replicas, &replica{name: fmt.Sprintf("replica %d", i+1), db: db})
+    }
+
+    replicas = set
+    replicas.check(ctx)
+    return nil
+}
+
+// RunReplicaHealthChecks checks the replicas' health and lag until ctx is cancelled
+func RunReplicaHealthChecks(ctx context.Context) {
+    if replicas == nil {
+        return
+    }
+
+    ticker := time.NewTicker(replicaCheckInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            replicas.check(ctx)
+        }
+    }
+}
+
+// check measures how far each replica is behind the primary, taking replicas that cannot
+// answer or have fallen too far behind out of rotation until they catch up
+func (s *replicaSet) check(ctx context.Context) {
+    for _, r := range s.replicas {
+        lag, err := replicaLag(ctx, r.db)
+        healthy := err == nil && lag <= s.maxLag
+
+        s.mu.Lock()
+        changed := r.healthy != healthy
+        r.healthy = healthy
+        s.mu.Unlock()
+
+        switch {
+        case !changed:
+        case err != nil:
+            log.Printf("Reading bookings from the primary instead of %s: %v", r.name, err)
+        case !healthy:
+            log.Printf("Reading bookings from the primary instead of %s: %s behind", r.name, lag)
+        default:
+            log.Printf("Reading bookings from %s again", r.name)
+        }
+    }
+}
+
+// replicaLag returns how long ago the replica last replayed a change from the primary. A
+// replica that has replayed everything it received is not behind, however quiet the primary.
+func replicaLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+    ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+    defer cancel()
+
+    var seconds sql.NullFloat64
+    err := db.QueryRowContext(ctx, `
+        SELECT CASE
+            WHEN NOT pg_is_in_recovery() THEN 0
+            WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+            ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+        END`).Scan(&seconds)
+    if err != nil {
+        return 0, fmt.Errorf("failed to check replication lag: %w", err)
+    }
+    if !seconds.Valid {
+        return 0, fmt.Errorf("replica has not replayed any changes")
+    }
+    return time.Duration(seconds.Float64 * float64(time.Second)), nil
+}
+
+// pick returns the next healthy replica in turn, or nil when none is healthy
+func (s *replicaSet) pick() *replica {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    for range s.replicas {
+        r := s.replicas[s.next%len(s.replicas)]
+        s.next++
+        if r.healthy {
+            return r
+        }
+    }
+    return nil
+}
+
+// markUnhealthy takes a replica whose query failed out of rotation until its next health check
+func (s *replicaSet) markUnhealthy(r *replica, err error) {
+    s.mu.Lock()
+    r.healthy = false
+    s.mu.Unlock()
+    log.Printf("Reading bookings from the primary instead of %s: %v", r.name, err)
+}
+
+// close closes every replica's connection pool
+func (s *replicaSet) close() {
+    for _, r := range s.replicas {
+        r.db.Close()
+    }
+}
+
+// readReplica returns the replica a read made with ctx should go to, or nil when it should
+// go to the primary
+func readReplica(ctx context.Context) *replica {
+    if replicas == nil {
+        return nil
+    }
+    if tolerates, _ := ctx.Value(replicaReadsKey{}).(bool); !tolerates {
+        return nil
+    }
+    return replicas.pick()
+}
+
+// readFromReplica runs read against a replica when ctx tolerates replica lag and one is
+// healthy, and against the primary otherwise. A replica that fails the read is taken out of
+// rotation and the read is retried on the primary. A replica reporting the row missing is not
+// trusted either, as it may not have replayed the row's creation yet.
+func readFromReplica(ctx context.Context, read func(db *sql.DB) error) error {
+    r := readReplica(ctx)
+    if r == nil {
+        return read(DB)
+    }
+
+    err := read(r.db)
+    switch {
+    case err == nil:
+        return nil
+    case err == sql.ErrNoRows:
+    case ctx.Err() != nil:
+        return err
+    default:
+        replicas.markUnhealthy(r, err)
+    }
+    return read(DB)
+}
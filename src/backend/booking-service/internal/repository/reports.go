@@ -0,0 +1,166 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// reportRunColumns is the column list scanned by scanReportRun
+const reportRunColumns = `id, schedule, report, scheduled_for, period_start, period_end, status, attempts,
+    recipients, delivered, started_at, completed_at, error`
+
+// scanReportRun scans a row selected with reportRunColumns
+func scanReportRun(row interface{ Scan(...interface{}) error }) (*models.ReportRun, error) {
+    run := &models.ReportRun{}
+    err := row.Scan(
+        &run.ID,
+        &run.Schedule,
+        &run.Report,
+        &run.ScheduledFor,
+        &run.PeriodStart,
+        &run.PeriodEnd,
+        &run.Status,
+        &run.Attempts,
+        &run.Recipients,
+        &run.Delivered,
+        &run.StartedAt,
+        &run.CompletedAt,
+        &run.Error,
+    )
+    if err != nil {
+        return nil, err
+    }
+    return run, nil
+}
+
+// ClaimReportRun records run as running for its schedule and fire time, so only one instance
+// sends each report. A run that failed before retryBefore, with fewer than maxAttempts tries,
+// is claimed again, keeping its ID and counting the attempt. Reports false when the run is
+// done, being sent by another instance, or out of retries.
+func ClaimReportRun(ctx context.Context, run *models.ReportRun, retryBefore time.Time, maxAttempts int) (bool, error) {
+    if memory != nil {
+        return memory.claimReportRun(run, retryBefore, maxAttempts)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    err := DB.QueryRowContext(ctx, `
+        INSERT INTO report_runs (id, schedule, report, scheduled_for, period_start, period_end, status, attempts, recipients, started_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, 1, $8, $9)
+        ON CONFLICT (schedule, scheduled_for) DO UPDATE
+        SET status = EXCLUDED.status, attempts = report_runs.attempts + 1, recipients = EXCLUDED.recipients,
+            delivered = 0, started_at = EXCLUDED.started_at, completed_at = NULL, error = ''
+        WHERE report_runs.status = $10 AND report_runs.started_at < $11 AND report_runs.attempts < $12
+        RETURNING id, attempts`,
+        run.ID,
+        run.Schedule,
+        run.Report,
+        run.ScheduledFor,
+        run.PeriodStart,
+        run.PeriodEnd,
+        models.ReportRunRunning,
+        run.Recipients,
+        run.StartedAt,
+        models.ReportRunFailed,
+        retryBefore,
+        maxAttempts,
+    ).Scan(&run.ID, &run.Attempts)
+
+    if err == sql.ErrNoRows {
+        return false, nil
+    }
+    if err != nil {
+        return false, fmt.Errorf("failed to claim report run: %w", err)
+    }
+    run.Status = models.ReportRunRunning
+    return true, nil
+}
+
+// CompleteReportRun marks a run completed with how many recipients the report reached
+func CompleteReportRun(ctx context.Context, run *models.ReportRun) error {
+    if memory != nil {
+        return memory.completeReportRun(run)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        UPDATE report_runs SET status = $2, delivered = $3, completed_at = $4 WHERE id = $1`,
+        run.ID,
+        models.ReportRunCompleted,
+        run.Delivered,
+        run.CompletedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to complete report run: %w", err)
+    }
+    run.Status = models.ReportRunCompleted
+    return nil
+}
+
+// FailReportRun marks a run failed with the reason it stopped
+func FailReportRun(ctx context.Context, id, reason string, at time.Time) error {
+    if memory != nil {
+        return memory.failReportRun(id, reason, at)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        UPDATE report_runs SET status = $2, completed_at = $3, error = $4 WHERE id = $1`,
+        id,
+        models.ReportRunFailed,
+        at,
+        reason,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to mark report run failed: %w", err)
+    }
+    return nil
+}
+
+// ListReportRuns retrieves up to limit runs, most recently started first. A non-empty
+// schedule limits them to that schedule's runs.
+func ListReportRuns(ctx context.Context, schedule string, limit int) ([]models.ReportRun, error) {
+    if memory != nil {
+        return memory.listReportRuns(schedule, limit)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT `+reportRunColumns+`
+        FROM report_runs
+        WHERE $1 = '' OR schedule = $1
+        ORDER BY started_at DESC
+        LIMIT $2`,
+        schedule,
+        limit,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to list report runs: %w", err)
+    }
+    defer rows.Close()
+
+    var runs []models.ReportRun
+    for rows.Next() {
+        run, err := scanReportRun(rows)
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan report run: %w", err)
+        }
+        runs = append(runs, *run)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to list report runs: %w", err)
+    }
+    return runs, nil
+}
@@ -0,0 +1,176 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// SaveSaga records a saga's state, replacing what was recorded before
+func SaveSaga(ctx context.Context, saga *models.Saga) error {
+    if memory != nil {
+        return memory.saveSaga(saga)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO booking_sagas (id, kind, booking_id, walker_id, status, steps, payment_id, accept_by, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+        ON CONFLICT (id) DO UPDATE
+        SET status = EXCLUDED.status, steps = EXCLUDED.steps, payment_id = EXCLUDED.payment_id,
+            updated_at = EXCLUDED.updated_at`,
+        saga.ID,
+        saga.Kind,
+        saga.BookingID,
+        saga.WalkerID,
+        saga.Status,
+        saga.Steps,
+        saga.PaymentID,
+        saga.AcceptBy,
+        saga.CreatedAt,
+        saga.UpdatedAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save saga: %w", err)
+    }
+    return nil
+}
+
+// GetSaga retrieves a saga, or nil when there is no such saga
+func GetSaga(ctx context.Context, id string) (*models.Saga, error) {
+    if memory != nil {
+        return memory.getSaga(id)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    saga, err := scanSaga(DB.QueryRowContext(ctx, `
+        SELECT id, kind, booking_id, walker_id, status, steps, payment_id, accept_by, created_at, updated_at
+        FROM booking_sagas
+        WHERE id = $1`,
+        id,
+    ))
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get saga: %w", err)
+    }
+    return saga, nil
+}
+
+// ListStalledSagas returns up to limit unfinished sagas not updated since before, oldest first
+func ListStalledSagas(ctx context.Context, before time.Time, limit int) ([]models.Saga, error) {
+    if memory != nil {
+        return memory.listStalledSagas(before, limit)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT id, kind, booking_id, walker_id, status, steps, payment_id, accept_by, created_at, updated_at
+        FROM booking_sagas
+        WHERE status IN ($1, $2) AND updated_at < $3
+        ORDER BY updated_at
+        LIMIT $4`,
+        models.SagaStatusRunning,
+        models.SagaStatusCompensating,
+        before,
+        limit,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to query stalled sagas: %w", err)
+    }
+    defer rows.Close()
+
+    var sagas []models.Saga
+    for rows.Next() {
+        saga, err := scanSaga(rows)
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan saga: %w", err)
+        }
+        sagas = append(sagas, *saga)
+    }
+    return sagas, rows.Err()
+}
+
+// ClaimSaga takes over a stalled saga by moving its update time from seen to now. It reports
+// false when the saga has been updated since it was read, meaning someone else is running it.
+func ClaimSaga(ctx context.Context, id string, seen, now time.Time) (bool, error) {
+    if memory != nil {
+        return memory.claimSaga(id, seen, now)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `
+        UPDATE booking_sagas SET updated_at = $3 WHERE id = $1 AND updated_at = $2`,
+        id,
+        seen,
+        now,
+    )
+    if err != nil {
+        return false, fmt.Errorf("failed to claim saga: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return false, fmt.Errorf("failed to claim saga: %w", err)
+    }
+    return rows == 1, nil
+}
+
+// RevertAcceptance returns a booking its walker accepted to awaiting their acceptance, with
+// the deadline it had before. A booking no longer confirmed with the walker is left alone, so
+// reverting twice is harmless.
+func RevertAcceptance(ctx context.Context, bookingID, walkerID string, acceptBy *time.Time) error {
+    if memory != nil {
+        return memory.revertAcceptance(bookingID, walkerID, acceptBy)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        UPDATE bookings SET status = $3, accept_by = $4
+        WHERE id = $1 AND walker_id = $2 AND status = $5`,
+        bookingID,
+        walkerID,
+        models.BookingStatusPending,
+        acceptBy,
+        models.BookingStatusConfirmed,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to revert acceptance: %w", err)
+    }
+    return nil
+}
+
+// scanSaga reads a saga from a row
+func scanSaga(row interface{ Scan(...interface{}) error }) (*models.Saga, error) {
+    var saga models.Saga
+    err := row.Scan(
+        &saga.ID,
+        &saga.Kind,
+        &saga.BookingID,
+        &saga.WalkerID,
+        &saga.Status,
+        &saga.Steps,
+        &saga.PaymentID,
+        &saga.AcceptBy,
+        &saga.CreatedAt,
+        &saga.UpdatedAt,
+    )
+    if err != nil {
+        return nil, err
+    }
+    return &saga, nil
+}
@@ -0,0 +1,178 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+var (
+    // ErrNotAwaitingCheckIn is returned when a walker checks in to a booking that is not
+    // confirmed with them
+    ErrNotAwaitingCheckIn = errors.New("booking is not confirmed with the walker checking in")
+
+    // ErrNotAwaitingCheckOut is returned when a walker checks out of a booking that is not in
+    // progress with them
+    ErrNotAwaitingCheckOut = errors.New("booking is not in progress with the walker checking out")
+)
+
+// StartShift checks a walker in, moving their confirmed booking to in progress and recording the
+// shift in the same transaction
+func StartShift(ctx context.Context, shift *models.WalkerShift) (*models.Booking, error) {
+    if memory != nil {
+        return memory.startShift(shift)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    booking, err := getBookingForUpdate(ctx, tx, shift.BookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.Status != models.BookingStatusConfirmed || booking.WalkerID != shift.WalkerID {
+        return nil, ErrNotAwaitingCheckIn
+    }
+
+    booking.Status = models.BookingStatusInProgress
+    if _, err := tx.ExecContext(ctx, `UPDATE bookings SET status = $2 WHERE id = $1`, booking.ID, booking.Status); err != nil {
+        return nil, fmt.Errorf("failed to start booking: %w", err)
+    }
+    if err := saveShift(ctx, tx, shift); err != nil {
+        return nil, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, fmt.Errorf("failed to commit check-in: %w", err)
+    }
+    return booking, nil
+}
+
+// SaveShift records a shift started outside StartShift, such as when support checks a walker in
+func SaveShift(ctx context.Context, shift *models.WalkerShift) error {
+    if memory != nil {
+        return memory.saveShift(shift)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    if err := saveShift(ctx, tx, shift); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+// saveShift writes a shift within tx, replacing any earlier check-in to the booking
+func saveShift(ctx context.Context, tx *sql.Tx, shift *models.WalkerShift) error {
+    _, err := tx.ExecContext(ctx, `
+        INSERT INTO walker_shifts (booking_id, walker_id, checked_in_at, check_in_distance_m, overridden_by, checked_out_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (booking_id) DO UPDATE
+        SET walker_id = EXCLUDED.walker_id, checked_in_at = EXCLUDED.checked_in_at,
+            check_in_distance_m = EXCLUDED.check_in_distance_m, overridden_by = EXCLUDED.overridden_by,
+            checked_out_at = EXCLUDED.checked_out_at`,
+        shift.BookingID,
+        shift.WalkerID,
+        shift.CheckedInAt,
+        shift.CheckInDistance,
+        shift.OverriddenBy,
+        shift.CheckedOutAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save shift: %w", err)
+    }
+    return nil
+}
+
+// EndShift checks a walker out, completing their in-progress booking and closing its shift in
+// the same transaction. Bookings moved to in progress by an admin status override have no
+// shift, and are completed all the same.
+func EndShift(ctx context.Context, bookingID, walkerID string, at time.Time) (*models.Booking, error) {
+    if memory != nil {
+        return memory.endShift(bookingID, walkerID, at)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    booking, err := getBookingForUpdate(ctx, tx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.Status != models.BookingStatusInProgress || booking.WalkerID != walkerID {
+        return nil, ErrNotAwaitingCheckOut
+    }
+
+    booking.Status = models.BookingStatusCompleted
+    if _, err := tx.ExecContext(ctx, `UPDATE bookings SET status = $2 WHERE id = $1`, booking.ID, booking.Status); err != nil {
+        return nil, fmt.Errorf("failed to complete booking: %w", err)
+    }
+    if _, err := tx.ExecContext(ctx, `
+        UPDATE walker_shifts SET checked_out_at = $2 WHERE booking_id = $1 AND checked_out_at IS NULL`,
+        booking.ID,
+        at,
+    ); err != nil {
+        return nil, fmt.Errorf("failed to end shift: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return nil, fmt.Errorf("failed to commit check-out: %w", err)
+    }
+    return booking, nil
+}
+
+// GetShift retrieves a booking's shift, or nil when its walker has not checked in
+func GetShift(ctx context.Context, bookingID string) (*models.WalkerShift, error) {
+    if memory != nil {
+        return memory.getShift(bookingID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    var shift models.WalkerShift
+    err := DB.QueryRowContext(ctx, `
+        SELECT booking_id, walker_id, checked_in_at, check_in_distance_m, overridden_by, checked_out_at
+        FROM walker_shifts
+        WHERE booking_id = $1`,
+        bookingID,
+    ).Scan(
+        &shift.BookingID,
+        &shift.WalkerID,
+        &shift.CheckedInAt,
+        &shift.CheckInDistance,
+        &shift.OverriddenBy,
+        &shift.CheckedOutAt,
+    )
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get shift: %w", err)
+    }
+    return &shift, nil
+}
@@ -0,0 +1,73 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// SaveSMSOptOut records that a phone number must not be texted; an existing opt-out is kept
+// as first made
+func SaveSMSOptOut(ctx context.Context, optOut *models.SMSOptOut) error {
+    if memory != nil {
+        return memory.saveSMSOptOut(optOut)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        INSERT INTO sms_opt_outs (phone, source, opted_out_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (phone) DO NOTHING`,
+        optOut.Phone,
+        optOut.Source,
+        optOut.OptedOutAt,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to save SMS opt-out: %w", err)
+    }
+    return nil
+}
+
+// GetSMSOptOut retrieves the opt-out of a phone number; nil when it may be texted
+func GetSMSOptOut(ctx context.Context, phone string) (*models.SMSOptOut, error) {
+    if memory != nil {
+        return memory.getSMSOptOut(phone)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    optOut := &models.SMSOptOut{}
+    err := DB.QueryRowContext(ctx, `
+        SELECT phone, source, opted_out_at FROM sms_opt_outs WHERE phone = $1`,
+        phone,
+    ).Scan(&optOut.Phone, &optOut.Source, &optOut.OptedOutAt)
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get SMS opt-out: %w", err)
+    }
+    return optOut, nil
+}
+
+// DeleteSMSOptOut lets a phone number be texted again
+func DeleteSMSOptOut(ctx context.Context, phone string) error {
+    if memory != nil {
+        return memory.deleteSMSOptOut(phone)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    if _, err := DB.ExecContext(ctx, `DELETE FROM sms_opt_outs WHERE phone = $1`, phone); err != nil {
+        return fmt.Errorf("failed to delete SMS opt-out: %w", err)
+    }
+    return nil
+}
@@ -0,0 +1,143 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "log"
+    "sync"
+)
+
+// Statements run on nearly every request, prepared once by PrepareStatements instead of being
+// parsed and planned by PostgreSQL on every call
+const (
+    createBookingQuery = `
+        INSERT INTO bookings (
+            id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+        )`
+
+    getBookingQuery = `
+        SELECT id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
+        FROM bookings
+        WHERE id = $1`
+)
+
+// preparedQueries are the statements PrepareStatements prepares
+var preparedQueries = []string{createBookingQuery, getBookingQuery}
+
+// statementCache holds the prepared statements of the primary and each replica. database/sql
+// prepares a statement again on each connection it is first run on, so one per pool suffices.
+type statementCache struct {
+    mu      sync.RWMutex
+    enabled bool
+    byDB    map[*sql.DB]map[string]*sql.Stmt
+}
+
+// statementsCache is empty, and every statement is sent unprepared, until PrepareStatements
+var statementsCache = &statementCache{}
+
+// PrepareStatements prepares the statements run on nearly every request on the primary and on
+// the read replicas. It must run after migrations, as preparing a statement checks the tables
+// it names. A replica that cannot prepare them is logged, not an error: they are prepared on
+// it when it is first read from.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func PrepareStatements(ctx context.Context) error {
+    if memory != nil {
+        return nil
+    }
+
+    statementsCache.mu.Lock()
+    statementsCache.enabled = true
+    statementsCache.mu.Unlock()
+
+    for _, query := range preparedQueries {
+        if _, err := statementsCache.prepare(ctx, DB, query); err != nil {
+            return err
+        }
+    }
+    if replicas != nil {
+        for _, r := range replicas.replicas {
+            for _, query := range preparedQueries {
+                if _, err := statementsCache.prepare(ctx, r.db, query); err != nil {
+                    log.Printf("Failed to prepare statements on %s: %v", r.name, err)
+                    break
+                }
+            }
+        }
+    }
+    return nil
+}
+
+// CloseStatements closes every prepared statement; statements are sent unprepared until
+// PrepareStatements is called again
+func CloseStatements() {
+    statementsCache.mu.Lock()
+    defer statementsCache.mu.Unlock()
+
+    for _, stmts := range statementsCache.byDB {
+        for _, stmt := range stmts {
+            stmt.Close()
+        }
+    }
+    statementsCache.byDB = nil
+    statementsCache.enabled = false
+}
+
+// prepare returns db's prepared statement for query, preparing it if it has not been yet
+func (c *statementCache) prepare(ctx context.Context, db *sql.DB, query string) (*sql.Stmt, error) {
+    c.mu.RLock()
+    stmt, enabled := c.byDB[db][query], c.enabled
+    c.mu.RUnlock()
+    if stmt != nil || !enabled {
+        return stmt, nil
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    // Another caller may have prepared it, or statements been closed, while unlocked
+    if !c.enabled {
+        return nil, nil
+    }
+    if stmt := c.byDB[db][query]; stmt != nil {
+        return stmt, nil
+    }
+
+    stmt, err := db.PrepareContext(ctx, query)
+    if err != nil {
+        return nil, fmt.Errorf("failed to prepare statement: %w", err)
+    }
+    if c.byDB == nil {
+        c.byDB = make(map[*sql.DB]map[string]*sql.Stmt)
+    }
+    if c.byDB[db] == nil {
+        c.byDB[db] = make(map[string]*sql.Stmt)
+    }
+    c.byDB[db][query] = stmt
+    return stmt, nil
+}
+
+// execPrepared runs query on db with its prepared statement, or unprepared when statements
+// are not prepared or preparing it failed
+func execPrepared(ctx context.Context, db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+    if stmt, err := statementsCache.prepare(ctx, db, query); stmt != nil {
+        return stmt.ExecContext(ctx, args...)
+    } else if err != nil {
+        log.Printf("Running statement unprepared: %v", err)
+    }
+    return db.ExecContext(ctx, query, args...)
+}
+
+// queryRowPrepared runs query on db with its prepared statement, or unprepared when statements
+// are not prepared or preparing it failed
+func queryRowPrepared(ctx context.Context, db *sql.DB, query string, args ...interface{}) *sql.Row {
+    if stmt, err := statementsCache.prepare(ctx, db, query); stmt != nil {
+        return stmt.QueryRowContext(ctx, args...)
+    } else if err != nil {
+        log.Printf("Running statement unprepared: %v", err)
+    }
+    return db.QueryRowContext(ctx, query, args...)
+}
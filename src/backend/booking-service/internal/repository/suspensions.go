@@ -0,0 +1,125 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrNotConfirmed is returned when a walker is released from a booking that is no longer
+// confirmed with them
+var ErrNotConfirmed = errors.New("booking is not confirmed with this walker")
+
+// ListUpcomingWalkerBookings retrieves the walker's bookings with the given status scheduled
+// after from, soonest first
+func ListUpcomingWalkerBookings(ctx context.Context, walkerID string, status models.BookingStatus, from time.Time) ([]models.Booking, error) {
+    if memory != nil {
+        return memory.listUpcomingWalkerBookings(walkerID, status, from)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT id, owner_id, walker_id, dog_id, scheduled_at, status, amount, duration_minutes, accept_by, tax, region, rate, cancellation, latitude, longitude, address
+        FROM bookings
+        WHERE walker_id = $1 AND status = $2 AND scheduled_at > $3
+        ORDER BY scheduled_at`,
+        walkerID,
+        status,
+        from,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to query walker bookings: %w", err)
+    }
+    defer rows.Close()
+
+    var bookings []models.Booking
+    for rows.Next() {
+        var b models.Booking
+        err := rows.Scan(
+            &b.ID,
+            &b.OwnerID,
+            &b.WalkerID,
+            &b.DogID,
+            &b.ScheduledAt,
+            &b.Status,
+            &b.Amount,
+            &b.DurationMinutes,
+            &b.AcceptBy,
+            &b.Tax,
+            &b.Region,
+            &b.Rate,
+            &b.Cancellation,
+            &b.Latitude,
+            &b.Longitude,
+            &b.Address,
+        )
+        if err != nil {
+            return nil, fmt.Errorf("failed to scan walker booking: %w", err)
+        }
+        bookings = append(bookings, b)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to read walker bookings: %w", err)
+    }
+    return bookings, nil
+}
+
+// ReleaseConfirmedWalker takes a confirmed booking away from its walker, leaving it waiting for
+// reassignment, and records why so the matching engine does not offer it to them again. It
+// returns ErrNotConfirmed when the booking is no longer confirmed with the walker.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ReleaseConfirmedWalker(ctx context.Context, bookingID, walkerID, reason string) error {
+    if memory != nil {
+        return memory.releaseConfirmedWalker(bookingID, walkerID, reason)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    result, err := tx.ExecContext(ctx, `
+        UPDATE bookings SET status = $3, walker_id = '', accept_by = NULL
+        WHERE id = $1 AND walker_id = $2 AND status = $4`,
+        bookingID,
+        walkerID,
+        models.BookingStatusNeedsReassignment,
+        models.BookingStatusConfirmed,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to release walker: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to release walker: %w", err)
+    }
+    if rows == 0 {
+        return ErrNotConfirmed
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        INSERT INTO assignment_declines (booking_id, walker_id, reason, created_at)
+        VALUES ($1, $2, $3, NOW())`,
+        bookingID,
+        walkerID,
+        reason,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to record released walker: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return fmt.Errorf("failed to commit released walker: %w", err)
+    }
+    return nil
+}
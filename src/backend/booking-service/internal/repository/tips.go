@@ -0,0 +1,128 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// ErrAlreadyTipped is returned when a booking already has a tip whose payment did not fail
+var ErrAlreadyTipped = errors.New("booking has already been tipped")
+
+// CreateTip records a tip. A booking has at most one tip; a tip whose payment failed is
+// replaced, so the owner can try again.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func CreateTip(ctx context.Context, tip *models.Tip) error {
+    if memory != nil {
+        return memory.createTip(tip)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    result, err := DB.ExecContext(ctx, `
+        INSERT INTO booking_tips (id, booking_id, owner_id, walker_id, amount, status, payment_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        ON CONFLICT (booking_id) DO UPDATE
+        SET id = EXCLUDED.id, owner_id = EXCLUDED.owner_id, walker_id = EXCLUDED.walker_id,
+            amount = EXCLUDED.amount, status = EXCLUDED.status, payment_id = EXCLUDED.payment_id,
+            created_at = EXCLUDED.created_at
+        WHERE booking_tips.status = $9`,
+        tip.ID,
+        tip.BookingID,
+        tip.OwnerID,
+        tip.WalkerID,
+        tip.Amount,
+        tip.Status,
+        tip.PaymentID,
+        tip.CreatedAt,
+        models.TipStatusFailed,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to create tip: %w", err)
+    }
+    rows, err := result.RowsAffected()
+    if err != nil {
+        return fmt.Errorf("failed to create tip: %w", err)
+    }
+    if rows == 0 {
+        return ErrAlreadyTipped
+    }
+    return nil
+}
+
+// UpdateTipPayment records the outcome of requesting a tip's payment
+func UpdateTipPayment(ctx context.Context, id string, status models.TipStatus, paymentID string) error {
+    if memory != nil {
+        return memory.updateTipPayment(id, status, paymentID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    _, err := DB.ExecContext(ctx, `
+        UPDATE booking_tips SET status = $2, payment_id = $3 WHERE id = $1`,
+        id,
+        status,
+        paymentID,
+    )
+    if err != nil {
+        return fmt.Errorf("failed to update tip: %w", err)
+    }
+    return nil
+}
+
+// GetWalkerEarnings sums each walker's completed bookings scheduled in [from, to) and the tips
+// given for them. An empty walkerID includes every walker and an empty region every region.
+func GetWalkerEarnings(ctx context.Context, from, to time.Time, walkerID, region string) ([]models.WalkerEarnings, error) {
+    if memory != nil {
+        return memory.getWalkerEarnings(from, to, walkerID, region)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    rows, err := DB.QueryContext(ctx, `
+        SELECT b.walker_id,
+               COUNT(*) AS bookings,
+               COALESCE(SUM(b.amount), 0) AS booking_amount,
+               COALESCE(SUM(t.amount), 0) AS tips
+        FROM bookings b
+        LEFT JOIN booking_tips t ON t.booking_id = b.id AND t.status <> $4
+        WHERE b.status = $3
+          AND b.walker_id <> ''
+          AND b.scheduled_at >= $1 AND b.scheduled_at < $2
+          AND ($5 = '' OR b.walker_id = $5)
+          AND ($6 = '' OR b.region = $6)
+        GROUP BY b.walker_id
+        ORDER BY b.walker_id`,
+        from,
+        to,
+        models.BookingStatusCompleted,
+        models.TipStatusFailed,
+        walkerID,
+        region,
+    )
+    if err != nil {
+        return nil, fmt.Errorf("failed to query walker earnings: %w", err)
+    }
+    defer rows.Close()
+
+    var earnings []models.WalkerEarnings
+    for rows.Next() {
+        var e models.WalkerEarnings
+        if err := rows.Scan(&e.WalkerID, &e.Bookings, &e.BookingAmount, &e.Tips); err != nil {
+            return nil, fmt.Errorf("failed to scan walker earnings: %w", err)
+        }
+        e.Total = e.BookingAmount + e.Tips
+        earnings = append(earnings, e)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("failed to read walker earnings: %w", err)
+    }
+    return earnings, nil
+}
@@ -0,0 +1,90 @@
+// Package repository implements the data access layer for the Booking Service
+package repository
+
+import (
+    "context"
+    "database/sql"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// GetWalkerVerification retrieves a walker's verification status. Walkers never reviewed
+// are reported as unverified.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func GetWalkerVerification(ctx context.Context, walkerID string) (*models.WalkerVerification, error) {
+    if memory != nil {
+        return memory.getWalkerVerification(walkerID)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    verification := &models.WalkerVerification{}
+    err := DB.QueryRowContext(ctx, `
+        SELECT walker_id, status, reason, updated_by, updated_at
+        FROM walker_verifications WHERE walker_id = $1`,
+        walkerID,
+    ).Scan(
+        &verification.WalkerID,
+        &verification.Status,
+        &verification.Reason,
+        &verification.UpdatedBy,
+        &verification.UpdatedAt,
+    )
+
+    if err == sql.ErrNoRows {
+        return &models.WalkerVerification{WalkerID: walkerID, Status: models.WalkerUnverified}, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to get walker verification: %w", err)
+    }
+    return verification, nil
+}
+
+// SetWalkerVerification records a walker's new verification status and returns the status it replaced
+func SetWalkerVerification(ctx context.Context, verification *models.WalkerVerification) (models.WalkerVerificationStatus, error) {
+    if memory != nil {
+        return memory.setWalkerVerification(verification)
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    tx, err := DB.BeginTx(ctx, nil)
+    if err != nil {
+        return "", fmt.Errorf("failed to begin transaction: %w", err)
+    }
+    defer tx.Rollback()
+
+    previous := models.WalkerUnverified
+    err = tx.QueryRowContext(ctx, `
+        SELECT status FROM walker_verifications WHERE walker_id = $1 FOR UPDATE`,
+        verification.WalkerID,
+    ).Scan(&previous)
+    if err != nil && err != sql.ErrNoRows {
+        return "", fmt.Errorf("failed to get walker verification: %w", err)
+    }
+
+    _, err = tx.ExecContext(ctx, `
+        INSERT INTO walker_verifications (walker_id, status, reason, updated_by, updated_at)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (walker_id) DO UPDATE
+        SET status = EXCLUDED.status, reason = EXCLUDED.reason,
+            updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`,
+        verification.WalkerID,
+        verification.Status,
+        verification.Reason,
+        verification.UpdatedBy,
+        verification.UpdatedAt,
+    )
+    if err != nil {
+        return "", fmt.Errorf("failed to set walker verification: %w", err)
+    }
+
+    if err := tx.Commit(); err != nil {
+        return "", fmt.Errorf("failed to commit walker verification: %w", err)
+    }
+    return previous, nil
+}
@@ -0,0 +1,152 @@
+// Package seed fills the booking database with sample walkers, availability and bookings.
+// The same seed and start always produce the same records, so demos and manual tests are
+// reproducible.
+package seed
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "math/rand"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+)
+
+// durations are the walk lengths seeded bookings choose from, in minutes
+var durations = []int{30, 45, 60}
+
+// groupDiscounts are the group discounts seeded walkers choose from, in percent
+var groupDiscounts = []float64{0, 10, 15}
+
+// ratePerMinute prices seeded walks
+const ratePerMinute = 0.5
+
+// Walkers publish availability from workdayStart to workdayEnd each day
+const (
+    WorkdayStart = 8 * time.Hour
+    WorkdayEnd   = 18 * time.Hour
+)
+
+// Options describe the records a run seeds
+type Options struct {
+    // Seed drives every random choice; IDs are prefixed with it so several seeds can coexist
+    Seed int64
+
+    // Walkers publish availability and Owners make bookings
+    Walkers int
+    Owners  int
+
+    // Bookings is how many bookings to try; bookings that would overbook a walker are skipped
+    Bookings int
+
+    // Bookings are spread over Days days before and after Start, a UTC date; bookings
+    // before it are in the past
+    Days  int
+    Start time.Time
+}
+
+// Report counts the records a run seeded
+type Report struct {
+    Walkers  int
+    Windows  int
+    Bookings int
+    Skipped  int
+}
+
+// Run seeds the records opts describe in the configured repository
+func Run(ctx context.Context, opts Options) (*Report, error) {
+    if opts.Walkers < 1 || opts.Owners < 1 || opts.Bookings < 0 || opts.Days < 1 {
+        return nil, fmt.Errorf("walkers, owners and days must be at least 1 and bookings must not be negative")
+    }
+
+    rng := rand.New(rand.NewSource(opts.Seed))
+    prefix := fmt.Sprintf("seed%d", opts.Seed)
+    report := &Report{Walkers: opts.Walkers}
+
+    // Every walker is available every day of the seeded period
+    walkerIDs := make([]string, opts.Walkers)
+    capacities := make(map[string]int, opts.Walkers)
+    for w := range walkerIDs {
+        walkerIDs[w] = fmt.Sprintf("%s-walker-%03d", prefix, w+1)
+        capacity := 1 + rng.Intn(3)
+        discount := groupDiscounts[rng.Intn(len(groupDiscounts))]
+
+        for d := -opts.Days; d < opts.Days; d++ {
+            day := opts.Start.AddDate(0, 0, d)
+            availability := &models.Availability{
+                ID:                   fmt.Sprintf("%s-availability-%03d-%03d", prefix, w+1, d+opts.Days),
+                WalkerID:             walkerIDs[w],
+                StartsAt:             day.Add(WorkdayStart),
+                EndsAt:               day.Add(WorkdayEnd),
+                Capacity:             capacity,
+                GroupDiscountPercent: discount,
+            }
+            if err := repository.CreateAvailability(ctx, availability); err != nil {
+                return nil, fmt.Errorf("failed to seed availability: %w", err)
+            }
+            report.Windows++
+        }
+
+        capacities[walkerIDs[w]] = capacity
+    }
+
+    for i := 0; i < opts.Bookings; i++ {
+        walkerID := walkerIDs[rng.Intn(len(walkerIDs))]
+        owner := rng.Intn(opts.Owners) + 1
+        duration := durations[rng.Intn(len(durations))]
+
+        // Start on a quarter hour late enough in the day that the walk ends before WorkdayEnd
+        day := opts.Start.AddDate(0, 0, rng.Intn(2*opts.Days)-opts.Days)
+        slots := int((WorkdayEnd-WorkdayStart)/(15*time.Minute)) - duration/15
+        scheduledAt := day.Add(WorkdayStart + time.Duration(rng.Intn(slots))*15*time.Minute)
+
+        booking := models.NewBooking(
+            fmt.Sprintf("%s-booking-%04d", prefix, i+1),
+            fmt.Sprintf("%s-owner-%03d", prefix, owner),
+            walkerID,
+            fmt.Sprintf("%s-dog-%03d-%d", prefix, owner, rng.Intn(2)+1),
+            scheduledAt,
+            seedStatus(rng, scheduledAt.Before(opts.Start)),
+            float64(duration)*ratePerMinute,
+        )
+        booking.DurationMinutes = duration
+
+        err := repository.CreateBookingWithinCapacity(ctx, booking, capacities[walkerID], nil)
+        if errors.Is(err, repository.ErrSlotFull) {
+            report.Skipped++
+            continue
+        }
+        if err != nil {
+            return nil, fmt.Errorf("failed to seed booking %s: %w", booking.ID, err)
+        }
+        report.Bookings++
+    }
+
+    return report, nil
+}
+
+// seedStatus picks a plausible status for a booking in the past or the future
+func seedStatus(rng *rand.Rand, past bool) models.BookingStatus {
+    roll := rng.Float64()
+    if past {
+        switch {
+        case roll < 0.80:
+            return models.BookingStatusCompleted
+        case roll < 0.95:
+            return models.BookingStatusCancelled
+        default:
+            return models.BookingStatusFailed
+        }
+    }
+
+    switch {
+    case roll < 0.60:
+        return models.BookingStatusConfirmed
+    case roll < 0.90:
+        return models.BookingStatusPending
+    default:
+        return models.BookingStatusCancelled
+    }
+}
@@ -0,0 +1,43 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "log"
+
+    "src/backend/booking-service/internal/geocoding"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+)
+
+// resolvePickup fills in a booking's pickup coordinates from its address when the owner's app
+// sent only the address, and records them on the address. An address the geocoder cannot
+// place is rejected; without a geocoder, or when it fails, the booking is kept without
+// coordinates, so a geocoding outage never blocks booking.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func resolvePickup(ctx context.Context, booking *models.Booking) error {
+    if booking.Address == nil {
+        return nil
+    }
+    address := *booking.Address
+
+    if booking.Latitude == nil && address.Latitude != nil {
+        booking.Latitude, booking.Longitude = address.Latitude, address.Longitude
+    }
+    if geocoder := providersFrom(ctx).Geocoder; booking.Latitude == nil && geocoder != nil {
+        latitude, longitude, err := geocoder.Geocode(ctx, address)
+        switch {
+        case errors.Is(err, geocoding.ErrAddressNotFound):
+            return i18n.Errorf("booking.invalid_data", i18n.Errorf("booking.address_not_found"))
+        case err != nil:
+            log.Printf("Failed to geocode the pickup address of booking %s: %v", booking.ID, err)
+        default:
+            booking.Latitude, booking.Longitude = &latitude, &longitude
+        }
+    }
+
+    address.Latitude, address.Longitude = booking.Latitude, booking.Longitude
+    booking.Address = &address
+    return nil
+}
@@ -0,0 +1,251 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "math"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// ForceStatusService sets a booking's status regardless of the normal status transitions
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func ForceStatusService(ctx context.Context, actorID, bookingID string, status models.BookingStatus, reason string) (*models.Booking, error) {
+    if !status.IsValid() {
+        return nil, fmt.Errorf("invalid override: unknown status %q", status)
+    }
+
+    booking, err := override(ctx, actorID, bookingID, models.AuditActionForceStatus, reason, 0, func(b *models.Booking) error {
+        if b.Status == status {
+            return fmt.Errorf("invalid override: booking is already %s", status)
+        }
+        b.Status = status
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    notifyForcedStatus(ctx, booking)
+    return booking, nil
+}
+
+// Limits on a bulk status change, so a mistyped filter cannot rewrite a walker's whole history
+const (
+    bulkStatusMaxBookings = 200
+    bulkStatusMaxWindow   = 31 * 24 * time.Hour
+)
+
+// bulkStatusDefaultStatuses are the statuses a bulk change applies to when none are given:
+// the walks that have not started yet
+var bulkStatusDefaultStatuses = []models.BookingStatus{models.BookingStatusPending, models.BookingStatusConfirmed}
+
+// BulkStatusService sets the status of every booking of a walker scheduled in a window, such
+// as cancelling a day of walks when the walker falls ill. The change is all or nothing, and
+// each booking changed gets its own audit entry.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func BulkStatusService(ctx context.Context, actorID string, filter models.BulkStatusFilter, status models.BookingStatus, reason string) (*models.BulkStatusReport, error) {
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    if !status.IsValid() {
+        return nil, fmt.Errorf("invalid override: unknown status %q", status)
+    }
+    reason = strings.TrimSpace(reason)
+    if reason == "" {
+        return nil, fmt.Errorf("invalid override: a reason is required")
+    }
+    if filter.WalkerID == "" {
+        return nil, fmt.Errorf("invalid override: walker ID is required")
+    }
+    if filter.From.IsZero() || filter.To.IsZero() || !filter.From.Before(filter.To) {
+        return nil, fmt.Errorf("invalid override: from must be before to")
+    }
+    if filter.To.Sub(filter.From) > bulkStatusMaxWindow {
+        return nil, fmt.Errorf("invalid override: window must not exceed %d days", int(bulkStatusMaxWindow.Hours()/24))
+    }
+    if len(filter.Statuses) == 0 {
+        filter.Statuses = bulkStatusDefaultStatuses
+    }
+    for _, s := range filter.Statuses {
+        if !s.IsValid() {
+            return nil, fmt.Errorf("invalid override: unknown status %q", s)
+        }
+    }
+
+    newEntry := func() (*models.AuditEntry, error) {
+        id, err := newID()
+        if err != nil {
+            return nil, fmt.Errorf("failed to generate audit entry ID: %w", err)
+        }
+        return &models.AuditEntry{
+            ID:        id,
+            ActorID:   actorID,
+            Action:    models.AuditActionBulkStatus,
+            Reason:    reason,
+            CreatedAt: clock.FromContext(ctx).Now(),
+        }, nil
+    }
+
+    report, changed, err := repository.BulkSetStatus(ctx, filter, status, bulkStatusMaxBookings, newEntry)
+    if errors.Is(err, repository.ErrTooManyBookings) {
+        return nil, fmt.Errorf("invalid override: more than %d bookings match; narrow the window", bulkStatusMaxBookings)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to change booking statuses: %w", err)
+    }
+
+    for i := range changed {
+        booking := &changed[i]
+        syncBookingCalendars(booking)
+        notifyForcedStatus(ctx, booking)
+    }
+    return report, nil
+}
+
+// notifyForcedStatus tells the owner about a status an admin set on their booking
+func notifyForcedStatus(ctx context.Context, booking *models.Booking) {
+    // The owner's receipt is issued as the booking completes; reading it never issues it
+    if booking.Status == models.BookingStatusCompleted {
+        if _, err := IssueReceiptService(ctx, booking.ID); err != nil {
+            log.Printf("Failed to issue receipt for booking %s: %v", booking.ID, err)
+        }
+        textOwner(ctx, booking, notifier.SMSWalkComplete, notifier.SMSData{})
+    }
+    if booking.Status == models.BookingStatusCancelled {
+        emailOwner(ctx, booking, notifier.EmailBookingCancelled, notifier.EmailData{})
+    }
+}
+
+// ReassignWalkerService moves a booking to another walker, respecting the new walker's capacity
+func ReassignWalkerService(ctx context.Context, actorID, bookingID, walkerID, reason string) (*models.Booking, error) {
+    if walkerID == "" {
+        return nil, fmt.Errorf("invalid override: walker ID is required")
+    }
+    if err := requireEligibleWalker(ctx, walkerID); err != nil {
+        return nil, err
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+
+    slot, err := repository.GetAvailabilityCovering(ctx, walkerID, booking.ScheduledAt, booking.EndsAt())
+    if err != nil {
+        return nil, fmt.Errorf("failed to check walker availability: %w", err)
+    }
+    capacity := 1
+    if slot != nil {
+        capacity = slot.Capacity
+    }
+
+    return override(ctx, actorID, bookingID, models.AuditActionReassignWalker, reason, capacity, func(b *models.Booking) error {
+        if b.WalkerID == walkerID {
+            return fmt.Errorf("invalid override: booking is already assigned to walker %s", walkerID)
+        }
+        b.WalkerID = walkerID
+        return nil
+    })
+}
+
+// AdjustAmountService sets a booking's amount, rounded to cents, and recalculates its tax
+func AdjustAmountService(ctx context.Context, actorID, bookingID string, amount float64, reason string) (*models.Booking, error) {
+    if amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+        return nil, fmt.Errorf("invalid override: amount must be non-negative")
+    }
+
+    return override(ctx, actorID, bookingID, models.AuditActionAdjustAmount, reason, 0, func(b *models.Booking) error {
+        b.Amount = math.Round(amount*100) / 100
+        return applyTax(ctx, b)
+    })
+}
+
+// override validates the common override inputs and applies mutate with an audit entry
+func override(ctx context.Context, actorID, bookingID, action, reason string, capacity int, mutate func(*models.Booking) error) (*models.Booking, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    reason = strings.TrimSpace(reason)
+    if reason == "" {
+        return nil, fmt.Errorf("invalid override: a reason is required")
+    }
+    if bookingID == "" {
+        return nil, fmt.Errorf("booking ID is required")
+    }
+
+    id, err := newID()
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate audit entry ID: %w", err)
+    }
+
+    entry := &models.AuditEntry{
+        ID:        id,
+        ActorID:   actorID,
+        Action:    action,
+        Reason:    reason,
+        CreatedAt: clock.FromContext(ctx).Now(),
+    }
+
+    booking, err := repository.OverrideBooking(ctx, bookingID, entry, capacity, mutate)
+    if errors.Is(err, repository.ErrSlotFull) {
+        return nil, fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    // Overrides can cancel, move or reassign a walk the walker has in their calendar
+    syncBookingCalendars(booking)
+    return booking, nil
+}
+
+// BookingHistoryService returns every change recorded to a booking, oldest first. Changes are
+// only recorded while event sourcing is enabled.
+func BookingHistoryService(ctx context.Context, bookingID string) ([]models.BookingEvent, error) {
+    if bookingID == "" {
+        return nil, fmt.Errorf("booking ID is required")
+    }
+    history, err := repository.ListBookingEvents(ctx, bookingID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list booking history: %w", err)
+    }
+    if len(history) == 0 {
+        return nil, fmt.Errorf("booking not found with id: %s", bookingID)
+    }
+    return history, nil
+}
+
+// BookingAuditService returns the administrative overrides recorded to a booking, oldest first
+func BookingAuditService(ctx context.Context, bookingID string) ([]models.AuditEntry, error) {
+    if bookingID == "" {
+        return nil, fmt.Errorf("booking ID is required")
+    }
+    if _, err := repository.GetBookingByID(ctx, bookingID); err != nil {
+        return nil, err
+    }
+    entries, err := repository.ListAuditEntries(ctx, bookingID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list audit entries: %w", err)
+    }
+    if entries == nil {
+        entries = []models.AuditEntry{}
+    }
+    return entries, nil
+}
+
+// BookingAtVersionService rebuilds a booking as it was after one of its recorded changes
+func BookingAtVersionService(ctx context.Context, bookingID string, version int) (*models.Booking, error) {
+    if version < 1 {
+        return nil, fmt.Errorf("invalid version: must be at least 1")
+    }
+    return repository.GetBookingAtVersion(ctx, bookingID, version)
+}
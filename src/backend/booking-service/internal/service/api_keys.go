@@ -0,0 +1,141 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "log"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+const (
+    // apiKeyPrefix starts every issued key, so leaked keys are easy to recognise in scanners and logs
+    apiKeyPrefix = "pwk_"
+
+    // apiKeyDisplayLength is how much of a key is kept in clear to identify it in listings
+    apiKeyDisplayLength = len(apiKeyPrefix) + 8
+
+    // apiKeyTouchInterval limits how often last_used_at is written for a busy key
+    apiKeyTouchInterval = time.Minute
+)
+
+// IssueAPIKeyService creates an API key for a partner backend. The returned secret is the
+// only copy of the key; just its hash is stored.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func IssueAPIKeyService(ctx context.Context, actorID, name string, scopes []string, tier models.APIKeyTier) (*models.APIKey, string, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    name = strings.TrimSpace(name)
+    if name == "" {
+        return nil, "", fmt.Errorf("invalid api key: name is required")
+    }
+    if len(scopes) == 0 {
+        return nil, "", fmt.Errorf("invalid api key: at least one scope is required")
+    }
+    for _, scope := range scopes {
+        if !isAPIKeyScope(scope) {
+            return nil, "", fmt.Errorf("invalid api key: unknown scope %q", scope)
+        }
+    }
+    if tier == "" {
+        tier = models.APIKeyTierStandard
+    }
+    if !tier.IsValid() {
+        return nil, "", fmt.Errorf("invalid api key: unknown tier %q", tier)
+    }
+
+    id, err := newID()
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to generate api key ID: %w", err)
+    }
+    b := make([]byte, 32)
+    if _, err := rand.Read(b); err != nil {
+        return nil, "", fmt.Errorf("failed to generate api key: %w", err)
+    }
+    secret := apiKeyPrefix + hex.EncodeToString(b)
+
+    key := &models.APIKey{
+        ID:        id,
+        Name:      name,
+        Prefix:    secret[:apiKeyDisplayLength],
+        KeyHash:   hashAPIKey(secret),
+        Scopes:    scopes,
+        Tier:      tier,
+        CreatedBy: actorID,
+        CreatedAt: clock.FromContext(ctx).Now(),
+    }
+    if err := repository.CreateAPIKey(ctx, key); err != nil {
+        return nil, "", fmt.Errorf("failed to create api key: %w", err)
+    }
+    return key, secret, nil
+}
+
+// ListAPIKeysService lists every issued API key, including revoked keys
+func ListAPIKeysService(ctx context.Context) ([]models.APIKey, error) {
+    keys, err := repository.ListAPIKeys(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list api keys: %w", err)
+    }
+    return keys, nil
+}
+
+// RevokeAPIKeyService revokes an API key; requests using it are rejected from then on
+func RevokeAPIKeyService(ctx context.Context, id string) (*models.APIKey, error) {
+    key, err := repository.RevokeAPIKey(ctx, id, clock.FromContext(ctx).Now())
+    if errors.Is(err, repository.ErrAPIKeyNotFound) {
+        return nil, fmt.Errorf("api key not found: %s", id)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to revoke api key: %w", err)
+    }
+    return key, nil
+}
+
+// AuthenticateAPIKeyService resolves a presented key to its active record, or returns
+// repository.ErrAPIKeyNotFound when the key is unknown or revoked
+func AuthenticateAPIKeyService(ctx context.Context, secret string) (*models.APIKey, error) {
+    if !strings.HasPrefix(secret, apiKeyPrefix) {
+        return nil, repository.ErrAPIKeyNotFound
+    }
+
+    key, err := repository.GetActiveAPIKeyByHash(ctx, hashAPIKey(secret))
+    if err != nil {
+        return nil, err
+    }
+
+    // Usage tracking is best effort and never fails the request
+    now := clock.FromContext(ctx).Now()
+    if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+        if err := repository.TouchAPIKey(ctx, key.ID, now); err != nil {
+            log.Printf("Failed to record api key %s usage: %v", key.ID, err)
+        }
+    }
+    return key, nil
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys carry 256 random bits, so a fast
+// unsalted hash is enough and lets keys be looked up by hash.
+func hashAPIKey(secret string) string {
+    sum := sha256.Sum256([]byte(secret))
+    return hex.EncodeToString(sum[:])
+}
+
+// isAPIKeyScope reports whether scope may be granted to an API key
+func isAPIKeyScope(scope string) bool {
+    for _, s := range models.APIKeyScopes {
+        if scope == s {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,285 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// Assignment event types
+const (
+    EventWalkerAssigned    = "booking.walker_assigned"
+    EventWalkerAccepted    = "booking.walker_accepted"
+    EventWalkerDeclined    = "booking.walker_declined"
+    EventAssignmentExpired = "booking.assignment_expired"
+    EventBookingUnmatched  = "booking.unmatched"
+)
+
+// Reasons recorded when a walker is released from a booking
+const (
+    releaseDeclined  = "declined"
+    releaseTimeout   = "timeout"
+    releaseSuspended = "suspended"
+)
+
+// ErrNoWalkerAvailable is returned when the matching engine finds no walker for a booking
+var ErrNoWalkerAvailable = errors.New("no walker available")
+
+// matchCandidates is how many walkers the matching engine proposes per assignment attempt
+const matchCandidates = 5
+
+// AssignWalkerService assigns a walker to a pending booking, or one waiting for reassignment,
+// and notifies them. When walkerID
+// is empty the matching engine picks the least busy walker available for the slot.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+// Handles schedule coordination between owners and walkers
+func AssignWalkerService(ctx context.Context, bookingID, walkerID string) (*models.Booking, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.Status != models.BookingStatusPending && booking.Status != models.BookingStatusNeedsReassignment {
+        return nil, fmt.Errorf("booking conflict: %w", repository.ErrNotAssignable)
+    }
+
+    // The matching engine only proposes eligible walkers; a walker named by an admin is checked here
+    candidates := []string{walkerID}
+    if walkerID != "" {
+        if err := requireEligibleWalker(ctx, walkerID); err != nil {
+            return nil, err
+        }
+    } else {
+        candidates, err = repository.FindAvailableWalkers(ctx, booking.ID, booking.ScheduledAt, booking.EndsAt(), matchCandidates)
+        if err != nil {
+            return nil, fmt.Errorf("failed to match walkers: %w", err)
+        }
+        if len(candidates) == 0 {
+            return nil, fmt.Errorf("%w for booking %s", ErrNoWalkerAvailable, bookingID)
+        }
+    }
+
+    acceptBy := acceptDeadline(booking, clock.FromContext(ctx).Now())
+
+    // Candidates can fill up between matching and assignment; fall through to the next one
+    for _, candidate := range candidates {
+        capacity, err := walkerCapacity(ctx, candidate, booking)
+        if err != nil {
+            return nil, err
+        }
+
+        assigned, err := repository.AssignWalker(ctx, bookingID, candidate, capacity, acceptBy)
+        if errors.Is(err, repository.ErrSlotFull) {
+            continue
+        }
+        if errors.Is(err, repository.ErrNotAssignable) {
+            return nil, fmt.Errorf("booking conflict: %w", err)
+        }
+        if err != nil {
+            return nil, fmt.Errorf("failed to assign walker: %w", err)
+        }
+
+        notifyAssignedWalker(ctx, assigned)
+        events.Publish(ctx, EventWalkerAssigned, assigned)
+        return assigned, nil
+    }
+
+    if walkerID != "" {
+        return nil, fmt.Errorf("booking conflict: %w", repository.ErrSlotFull)
+    }
+    return nil, fmt.Errorf("%w for booking %s", ErrNoWalkerAvailable, bookingID)
+}
+
+// AcceptAssignmentService confirms a booking on behalf of its assigned walker. Confirmation
+// runs as a saga: the booking is confirmed, its payment held, and it is written to the walker's
+// calendars. When the payment cannot be held the booking goes back to awaiting the walker.
+// Accepting a booking the walker already confirmed returns it without running the saga again.
+func AcceptAssignmentService(ctx context.Context, bookingID, walkerID string) (*models.Booking, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    if walkerID == "" {
+        return nil, fmt.Errorf("invalid assignment response: walker ID is required")
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if alreadyTransitioned(booking, models.BookingStatusConfirmed, walkerID) {
+        return booking, nil
+    }
+    if booking.Status != models.BookingStatusPending || booking.WalkerID != walkerID {
+        return nil, fmt.Errorf("booking conflict: %w", repository.ErrNoPendingAssignment)
+    }
+
+    saga, err := newSaga(ctx, models.SagaKindBookingConfirmation, booking, walkerID)
+    if err != nil {
+        return nil, err
+    }
+    if err := repository.SaveSaga(ctx, saga); err != nil {
+        return nil, fmt.Errorf("failed to accept booking: %w", err)
+    }
+    if err := runSaga(ctx, saga); err != nil {
+        return nil, err
+    }
+
+    return GetBookingService(ctx, bookingID)
+}
+
+// DeclineAssignmentService releases the walker from a booking and offers it to another walker,
+// cancelling the booking if nobody else is available
+func DeclineAssignmentService(ctx context.Context, bookingID, walkerID string) error {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    if walkerID == "" {
+        return fmt.Errorf("invalid assignment response: walker ID is required")
+    }
+
+    err := repository.ReleaseAssignment(ctx, bookingID, walkerID, releaseDeclined)
+    if errors.Is(err, repository.ErrNoPendingAssignment) {
+        return fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to decline booking: %w", err)
+    }
+
+    events.Publish(ctx, EventWalkerDeclined, map[string]string{
+        "booking_id": bookingID,
+        "walker_id":  walkerID,
+    })
+
+    reassignOrCancel(ctx, bookingID)
+    return nil
+}
+
+// acceptDeadline is when a walker assigned now must answer by: the configured response SLA,
+// but never later than the start of the walk
+func acceptDeadline(booking *models.Booking, now time.Time) time.Time {
+    acceptBy := now.Add(config.Config.AssignmentAcceptWindow)
+    if booking.ScheduledAt.Before(acceptBy) {
+        acceptBy = booking.ScheduledAt
+    }
+    return acceptBy
+}
+
+// walkerCapacity resolves how many dogs the walker takes in the booking's slot;
+// without a published availability window the walker takes one dog at a time
+func walkerCapacity(ctx context.Context, walkerID string, booking *models.Booking) (int, error) {
+    slot, err := repository.GetAvailabilityCovering(ctx, walkerID, booking.ScheduledAt, booking.EndsAt())
+    if err != nil {
+        return 0, fmt.Errorf("failed to check walker availability: %w", err)
+    }
+    if slot == nil {
+        return 1, nil
+    }
+    return slot.Capacity, nil
+}
+
+// notifyAssignedWalker asks the walker to accept the booking before its deadline
+func notifyAssignedWalker(ctx context.Context, booking *models.Booking) {
+    locale := userLocale(ctx, booking.WalkerID)
+    body := i18n.T(locale, "notify.walk_request.body",
+        i18n.FormatTime(locale, booking.ScheduledAt.UTC()), i18n.FormatTime(locale, booking.AcceptBy.UTC()))
+    err := providersFrom(ctx).Notifier.Notify(ctx, booking.WalkerID, notifier.Notification{
+        Subject:  i18n.T(locale, "notify.walk_request.subject"),
+        Body:     body,
+        Priority: "high",
+        Category: notifier.CategoryBookings,
+        Data: map[string]string{
+            "event":      EventWalkerAssigned,
+            "booking_id": booking.ID,
+            "accept_by":  booking.AcceptBy.Format(time.RFC3339),
+        },
+    })
+    if err != nil {
+        log.Printf("Failed to notify walker %s of booking %s: %v", booking.WalkerID, booking.ID, err)
+    }
+}
+
+// releaseLapsedAssignments takes bookings away from walkers who did not answer in time and
+// reassigns or cancels them. Every instance runs this job; the conditional release lets
+// exactly one of them handle each booking.
+func releaseLapsedAssignments(ctx context.Context, now time.Time) {
+    lapsed, err := repository.ListLapsedAssignments(ctx, now)
+    if err != nil {
+        log.Printf("Failed to list lapsed assignments: %v", err)
+        return
+    }
+
+    for i := range lapsed {
+        booking := &lapsed[i]
+
+        err := repository.ReleaseAssignment(ctx, booking.ID, booking.WalkerID, releaseTimeout)
+        if errors.Is(err, repository.ErrNoPendingAssignment) {
+            continue
+        }
+        if err != nil {
+            log.Printf("Failed to release lapsed assignment of booking %s: %v", booking.ID, err)
+            continue
+        }
+
+        events.Publish(ctx, EventAssignmentExpired, map[string]string{
+            "booking_id": booking.ID,
+            "walker_id":  booking.WalkerID,
+        })
+        reassignOrCancel(ctx, booking.ID)
+    }
+}
+
+// reassignOrCancel offers a released booking to the next available walker, or cancels it
+// and tells the owner when there is none
+func reassignOrCancel(ctx context.Context, bookingID string) {
+    _, err := AssignWalkerService(ctx, bookingID, "")
+    if err == nil {
+        return
+    }
+    if !errors.Is(err, ErrNoWalkerAvailable) {
+        log.Printf("Failed to reassign booking %s: %v", bookingID, err)
+        return
+    }
+
+    if err := repository.CancelUnassignedBooking(ctx, bookingID); err != nil {
+        log.Printf("Failed to cancel unmatched booking %s: %v", bookingID, err)
+        return
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        log.Printf("Failed to load cancelled booking %s: %v", bookingID, err)
+        return
+    }
+    events.Publish(ctx, EventBookingUnmatched, booking)
+
+    locale := userLocale(ctx, booking.OwnerID)
+    err = providersFrom(ctx).Notifier.Notify(ctx, booking.OwnerID, notifier.Notification{
+        Subject:  i18n.T(locale, "notify.booking_unmatched.subject"),
+        Body:     i18n.T(locale, "notify.booking_unmatched.body"),
+        Category: notifier.CategoryBookings,
+        Data: map[string]string{
+            "event":      EventBookingUnmatched,
+            "booking_id": booking.ID,
+        },
+    })
+    if err != nil {
+        log.Printf("Failed to notify owner of cancelled booking %s: %v", booking.ID, err)
+    }
+    emailOwner(ctx, booking, notifier.EmailBookingCancelled, notifier.EmailData{
+        Locale: locale,
+        Reason: i18n.T(locale, "notify.booking_unmatched.reason"),
+    })
+}
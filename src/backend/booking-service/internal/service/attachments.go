@@ -0,0 +1,153 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// attachmentPurgeInterval is how often attachments of finished bookings are purged
+const attachmentPurgeInterval = time.Hour
+
+// lastAttachmentPurge is when this instance last purged attachments of finished bookings
+var lastAttachmentPurge time.Time
+
+// AddBookingAttachmentService encrypts and stores a note or small file the booking's owner
+// attaches for its walker. Attachments can be added until the booking is finished.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func AddBookingAttachmentService(ctx context.Context, bookingID, ownerID string, attachment models.BookingAttachment) (*models.BookingAttachment, error) {
+    attachment.Title = strings.TrimSpace(attachment.Title)
+    if err := attachment.Validate(); err != nil {
+        return nil, fmt.Errorf("invalid attachment: %w", err)
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.OwnerID != ownerID {
+        return nil, fmt.Errorf("attachment forbidden: only the booking's owner can add attachments")
+    }
+    switch booking.Status {
+    case models.BookingStatusCompleted, models.BookingStatusCancelled, models.BookingStatusFailed:
+        return nil, fmt.Errorf("attachment not allowed: booking is %s", booking.Status)
+    }
+
+    existing, err := repository.ListBookingAttachments(ctx, booking.ID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list attachments: %w", err)
+    }
+    if len(existing) >= models.MaxBookingAttachments {
+        return nil, fmt.Errorf("attachment not allowed: a booking can have at most %d attachments", models.MaxBookingAttachments)
+    }
+
+    id, err := newID()
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate attachment ID: %w", err)
+    }
+    attachment.ID = id
+    attachment.BookingID = booking.ID
+    attachment.OwnerID = ownerID
+    attachment.Size = len(attachment.Content)
+    attachment.CreatedAt = clock.FromContext(ctx).Now().UTC()
+
+    if err := repository.CreateBookingAttachment(ctx, &attachment); err != nil {
+        return nil, fmt.Errorf("failed to store attachment: %w", err)
+    }
+    return &attachment, nil
+}
+
+// ListBookingAttachmentsService returns a booking's attachments without their file content to
+// its owner, or to its walker while the booking is confirmed or in progress
+func ListBookingAttachmentsService(ctx context.Context, bookingID, userID string) ([]models.BookingAttachment, error) {
+    booking, err := attachmentBooking(ctx, bookingID, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    attachments, err := repository.ListBookingAttachments(ctx, booking.ID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list attachments: %w", err)
+    }
+    for i := range attachments {
+        attachments[i] = attachments[i].Summary()
+    }
+    return attachments, nil
+}
+
+// GetBookingAttachmentService returns one of a booking's attachments, including its file, to
+// the same users ListBookingAttachmentsService lists them to
+func GetBookingAttachmentService(ctx context.Context, bookingID, id, userID string) (*models.BookingAttachment, error) {
+    booking, err := attachmentBooking(ctx, bookingID, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    attachment, err := repository.GetBookingAttachment(ctx, booking.ID, id)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get attachment: %w", err)
+    }
+    return attachment, nil
+}
+
+// DeleteBookingAttachmentService removes an attachment at the request of the booking's owner
+func DeleteBookingAttachmentService(ctx context.Context, bookingID, id, ownerID string) error {
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return err
+    }
+    if booking.OwnerID != ownerID {
+        return fmt.Errorf("attachment forbidden: only the booking's owner can remove attachments")
+    }
+
+    if err := repository.DeleteBookingAttachment(ctx, booking.ID, id); err != nil {
+        return fmt.Errorf("failed to delete attachment: %w", err)
+    }
+    return nil
+}
+
+// attachmentBooking returns the booking whose attachments userID wants to read, checking
+// they are its owner or its walker while the walk is coming up or under way
+func attachmentBooking(ctx context.Context, bookingID, userID string) (*models.Booking, error) {
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    switch {
+    case userID != "" && booking.OwnerID == userID:
+        return booking, nil
+    case userID != "" && booking.WalkerID == userID:
+        if !booking.AttachmentsVisibleToWalker() {
+            return nil, fmt.Errorf("attachment forbidden: the walker can only read attachments while the booking is confirmed or in progress, not %s", booking.Status)
+        }
+        return booking, nil
+    default:
+        return nil, fmt.Errorf("attachment forbidden: only the booking's owner and walker can read attachments")
+    }
+}
+
+// purgeBookingAttachments removes the attachments of bookings finished more than
+// config.Config.AttachmentRetention ago, at most once per attachmentPurgeInterval
+func purgeBookingAttachments(ctx context.Context, now time.Time) {
+    if now.Sub(lastAttachmentPurge) < attachmentPurgeInterval {
+        return
+    }
+    lastAttachmentPurge = now
+
+    deleted, err := repository.DeleteFinishedBookingAttachmentsBefore(ctx, now.Add(-config.Config.AttachmentRetention))
+    if err != nil {
+        log.Printf("Failed to purge booking attachments: %v", err)
+        return
+    }
+    if deleted > 0 {
+        log.Printf("Purged %d attachments of finished bookings", deleted)
+    }
+}
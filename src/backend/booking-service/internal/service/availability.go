@@ -0,0 +1,73 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// CreateAvailabilityService handles the business logic for publishing a walker availability window
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+// Handles real-time availability search and schedule coordination
+func CreateAvailabilityService(ctx context.Context, availability *models.Availability) error {
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    // Default to a single dog per walk when no capacity is given
+    if availability.Capacity == 0 {
+        availability.Capacity = 1
+    }
+    availability.Region = NormalizeRegion(availability.Region)
+
+    if err := availability.Validate(); err != nil {
+        return fmt.Errorf("invalid availability data: %w", err)
+    }
+
+    if !availability.EndsAt.After(clock.FromContext(ctx).Now()) {
+        return fmt.Errorf("invalid availability data: window must end in the future")
+    }
+
+    if err := repository.CreateAvailability(ctx, availability); err != nil {
+        return fmt.Errorf("failed to create availability: %w", err)
+    }
+
+    return nil
+}
+
+// ListAvailabilityService handles the business logic for retrieving a walker's upcoming availability.
+// Windows touching a blackout date of their region are left out, as they cannot be booked.
+func ListAvailabilityService(ctx context.Context, walkerID string) ([]models.Availability, error) {
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    if walkerID == "" {
+        return nil, fmt.Errorf("walker ID is required")
+    }
+
+    windows, err := repository.ListAvailability(ctx, walkerID, clock.FromContext(ctx).Now())
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve availability: %w", err)
+    }
+
+    calendars := make(map[string][]models.Holiday)
+    open := windows[:0]
+    for _, window := range windows {
+        calendar, ok := calendars[window.Region]
+        if !ok {
+            if calendar, err = holidayCalendar(ctx, window.Region); err != nil {
+                return nil, err
+            }
+            calendars[window.Region] = calendar
+        }
+        if checkBlackout(holidayDuring(calendar, window.StartsAt, window.EndsAt)) == nil {
+            open = append(open, window)
+        }
+    }
+
+    return open, nil
+}
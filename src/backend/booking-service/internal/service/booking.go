@@ -3,12 +3,17 @@ package service
 
 import (
     "context"
+    "errors"
     "fmt"
+    "log"
     "time"
 
+    "src/backend/booking-service/internal/i18n"
     "src/backend/booking-service/internal/models"
     "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/tax"
     "src/backend/booking-service/internal/config"
+    "src/backend/shared/clock"
 )
 
 // Human Tasks:
@@ -28,24 +33,112 @@ func CreateBookingService(ctx context.Context, booking *models.Booking) error {
 
     // Validate booking data
     if err := booking.Validate(); err != nil {
-        return fmt.Errorf("invalid booking data: %w", err)
+        return i18n.Errorf("booking.invalid_data", err)
     }
 
     // Validate that the booking is scheduled in the future
-    if !booking.IsScheduledInFuture() {
-        return fmt.Errorf("booking must be scheduled for a future time")
+    now := clock.FromContext(ctx).Now()
+    if !booking.IsScheduledInFuture(now) {
+        return i18n.Errorf("booking.not_in_future")
     }
 
     // Validate that the booking is in a valid initial state
     if booking.Status != models.BookingStatusPending {
-        return fmt.Errorf("new bookings must have 'pending' status")
+        return i18n.Errorf("booking.not_pending")
+    }
+    // A pickup address given without coordinates is placed by the geocoder
+    if err := resolvePickup(ctx, booking); err != nil {
+        return err
+    }
+    booking.Region = NormalizeRegion(booking.Region)
+    if booking.Latitude != nil {
+        // The pickup point decides the region over any region the client named
+        region, err := locateRegion(ctx, *booking.Latitude, *booking.Longitude)
+        if err != nil {
+            return err
+        }
+        if region != "" {
+            booking.Region = region
+        }
     }
 
-    // Create the booking in the database
-    if err := repository.CreateBooking(ctx, booking); err != nil {
+    // Check the booking against the rules operations set for its region, such as how far
+    // ahead walks must be booked
+    if err := checkBookingRules(ctx, booking, now); err != nil {
+        return err
+    }
+
+    // Walks cannot be booked on the blackout dates of the region's holiday calendar, and
+    // cost more on its holidays
+    holiday, err := walkHoliday(ctx, booking.Region, booking.ScheduledAt, booking.DurationMinutes)
+    if err != nil {
+        return err
+    }
+    if err := checkBlackout(holiday); err != nil {
+        return err
+    }
+
+    // Price the walk at the quote the owner held the slot with, or else from the walker's own
+    // rates when they have set them
+    if booking.HoldID != "" {
+        err = applySlotHold(ctx, booking, now)
+    } else {
+        err = priceBooking(ctx, booking, holiday)
+    }
+    if err != nil {
+        return err
+    }
+
+    // Bookings without a walker are stored unassigned and offered to the matching engine
+    if !booking.IsAssigned() {
+        return createUnassignedBooking(ctx, booking)
+    }
+
+    // Unverified and suspended walkers cannot be booked
+    if err := requireEligibleWalker(ctx, booking.WalkerID); err != nil {
+        return err
+    }
+
+    // The walker must accept the booking within the response SLA
+    acceptBy := acceptDeadline(booking, now)
+    booking.AcceptBy = &acceptBy
+
+    // Resolve the walker's capacity for the slot; without a published
+    // availability window the walker takes one dog at a time
+    slot, err := repository.GetAvailabilityCovering(ctx, booking.WalkerID, booking.ScheduledAt, booking.EndsAt())
+    if err != nil {
+        return fmt.Errorf("failed to check walker availability: %w", err)
+    }
+    capacity := 1
+    if slot != nil {
+        capacity = slot.Capacity
+        // Owners need not know the region; the walk takes place where the walker works
+        if booking.Region == "" {
+            booking.Region = slot.Region
+        }
+    }
+
+    // Create the booking in the database, counting overlapping bookings against capacity
+    // and applying the group walk discount for each additional dog before tax. Only amounts
+    // the service priced are discounted, never one the client sent for a walker without rates.
+    err = repository.CreateBookingWithinCapacity(ctx, booking, capacity, func(b *models.Booking, overlapping int) error {
+        if slot != nil && b.Rate != nil {
+            b.Amount = slot.PriceForDog(b.Amount, overlapping)
+        }
+        return applyTax(ctx, b)
+    })
+    if errors.Is(err, repository.ErrSlotFull) {
+        return fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
         return fmt.Errorf("failed to create booking: %w", err)
     }
 
+    notifyAssignedWalker(ctx, booking)
+
+    // Credit the referrer if this is a referred owner's first booking
+    convertReferral(ctx, booking)
+
     return nil
 }
 
@@ -74,4 +167,111 @@ func GetBookingService(ctx context.Context, id string) (*models.Booking, error)
     }
 
     return booking, nil
-}
\ No newline at end of file
+}
+
+// alreadyTransitioned reports whether a status transition requested by walkerID finds the
+// booking already at status with them assigned. Walkers double-tap buttons such as "Start
+// walk" on slow connections, so a repeat is answered with the booking as it is, without
+// acting twice, rather than failing as an invalid transition.
+func alreadyTransitioned(booking *models.Booking, status models.BookingStatus, walkerID string) bool {
+    return booking.Status == status && booking.WalkerID == walkerID
+}
+
+// repeatedTransition re-reads a booking whose transition lost a race, returning it when a
+// concurrent repeat of the same request already made the transition; nil otherwise
+func repeatedTransition(ctx context.Context, bookingID string, status models.BookingStatus, walkerID string) *models.Booking {
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil || !alreadyTransitioned(booking, status, walkerID) {
+        return nil
+    }
+    return booking
+}
+
+// defaultBookingPage and maxBookingPage are the bookings returned per page of a listing when
+// the client asks for none and at most
+const (
+    defaultBookingPage = 20
+    maxBookingPage     = 100
+)
+
+// ListBookingsService returns a page of the bookings matching filter, latest scheduled first,
+// resuming after cursor when one from an earlier page is given, with counts of every booking
+// the filter matches
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ListBookingsService(ctx context.Context, filter models.BookingFilter, cursor string, limit int) (*models.BookingPage, error) {
+    if limit <= 0 {
+        limit = defaultBookingPage
+    }
+    if limit > maxBookingPage {
+        limit = maxBookingPage
+    }
+    if filter.Status != "" && !filter.Status.IsValid() {
+        return nil, fmt.Errorf("invalid booking listing: unknown status %q", filter.Status)
+    }
+    if len(filter.IDs) > maxBookingPage {
+        return nil, fmt.Errorf("invalid booking listing: at most %d IDs may be listed", maxBookingPage)
+    }
+    filter.Region = NormalizeRegion(filter.Region)
+
+    var after *models.BookingCursor
+    if cursor != "" {
+        var err error
+        if after, err = models.ParseBookingCursor(cursor); err != nil {
+            return nil, fmt.Errorf("invalid booking listing: %w", err)
+        }
+    }
+
+    // One extra booking tells whether there is another page
+    bookings, err := repository.ListBookings(ctx, filter, after, limit+1)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list bookings: %w", err)
+    }
+
+    summary, err := repository.SummarizeBookings(ctx, filter)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list bookings: %w", err)
+    }
+
+    page := &models.BookingPage{Bookings: bookings, Summary: summary}
+    if len(bookings) > limit {
+        page.Bookings = bookings[:limit]
+        page.NextCursor = models.CursorAfter(&bookings[limit-1]).String()
+    }
+    if page.Bookings == nil {
+        page.Bookings = []models.Booking{}
+    }
+    return page, nil
+}
+
+// applyTax records the tax due on the booking's amount. Any tax sent by the client is
+// discarded, so the owner is only ever charged what the calculator works out.
+func applyTax(ctx context.Context, booking *models.Booking) error {
+    breakdown, err := tax.Default.Calculate(ctx, booking)
+    if err != nil {
+        return fmt.Errorf("failed to calculate tax: %w", err)
+    }
+    booking.Tax = breakdown
+    return nil
+}
+
+// createUnassignedBooking stores a booking without a walker and tries to assign one.
+// The booking is kept even when no walker is available yet, so it can be assigned later.
+func createUnassignedBooking(ctx context.Context, booking *models.Booking) error {
+    if err := applyTax(ctx, booking); err != nil {
+        return err
+    }
+    if err := repository.CreateBooking(ctx, booking); err != nil {
+        return fmt.Errorf("failed to create booking: %w", err)
+    }
+
+    convertReferral(ctx, booking)
+
+    assigned, err := AssignWalkerService(ctx, booking.ID, "")
+    if err != nil {
+        log.Printf("Booking %s created without a walker: %v", booking.ID, err)
+        return nil
+    }
+
+    *booking = *assigned
+    return nil
+}
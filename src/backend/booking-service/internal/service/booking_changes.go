@@ -0,0 +1,171 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// ProposeBookingChangeService records a proposed change to a booking. The booking itself is
+// left untouched until the walker accepts the change.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+// Handles booking management and schedule coordination
+func ProposeBookingChangeService(ctx context.Context, change *models.BookingChange) error {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    booking, err := GetBookingService(ctx, change.BookingID)
+    if err != nil {
+        return err
+    }
+
+    if booking.Status != models.BookingStatusPending && booking.Status != models.BookingStatusConfirmed {
+        return fmt.Errorf("invalid change request: booking is %s", booking.Status)
+    }
+
+    if change.ProposedBy != booking.OwnerID {
+        return fmt.Errorf("invalid change request: only the booking owner can propose changes")
+    }
+
+    id, err := newID()
+    if err != nil {
+        return fmt.Errorf("failed to generate change ID: %w", err)
+    }
+
+    now := clock.FromContext(ctx).Now()
+    change.ID = id
+    change.Status = models.BookingChangeStatusPending
+    change.CreatedAt = now
+    change.ResolvedAt = nil
+
+    // A proposal cannot outlive the walk it would change
+    change.ExpiresAt = now.Add(config.Config.ChangeRequestTTL)
+    if booking.ScheduledAt.Before(change.ExpiresAt) {
+        change.ExpiresAt = booking.ScheduledAt
+    }
+
+    if err := change.Validate(now); err != nil {
+        return fmt.Errorf("invalid change request: %w", err)
+    }
+
+    err = repository.CreateBookingChange(ctx, change)
+    if errors.Is(err, repository.ErrChangeAlreadyPending) {
+        return fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to create booking change: %w", err)
+    }
+
+    return nil
+}
+
+// AcceptBookingChangeService applies a pending change on the walker's acceptance
+func AcceptBookingChangeService(ctx context.Context, bookingID, changeID, walkerID string) (*models.Booking, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    booking, change, err := getChangeForWalker(ctx, bookingID, changeID, walkerID)
+    if err != nil {
+        return nil, err
+    }
+
+    updated := change.ApplyTo(*booking)
+
+    // The new time may fall in a different availability window than the original
+    slot, err := repository.GetAvailabilityCovering(ctx, updated.WalkerID, updated.ScheduledAt, updated.EndsAt())
+    if err != nil {
+        return nil, fmt.Errorf("failed to check walker availability: %w", err)
+    }
+    capacity := 1
+    if slot != nil {
+        capacity = slot.Capacity
+    }
+
+    err = repository.AcceptBookingChange(ctx, change, &updated, capacity)
+    switch {
+    case errors.Is(err, repository.ErrSlotFull), errors.Is(err, repository.ErrChangeNotPending):
+        return nil, fmt.Errorf("booking conflict: %w", err)
+    case err != nil:
+        return nil, fmt.Errorf("failed to accept booking change: %w", err)
+    }
+
+    syncBookingCalendars(&updated)
+    return &updated, nil
+}
+
+// RejectBookingChangeService discards a pending change on the walker's refusal
+func RejectBookingChangeService(ctx context.Context, bookingID, changeID, walkerID string) error {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    if _, _, err := getChangeForWalker(ctx, bookingID, changeID, walkerID); err != nil {
+        return err
+    }
+
+    err := repository.RejectBookingChange(ctx, changeID)
+    if errors.Is(err, repository.ErrChangeNotPending) {
+        return fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to reject booking change: %w", err)
+    }
+
+    return nil
+}
+
+// expireBookingChanges expires booking changes the walker has not answered by now
+func expireBookingChanges(ctx context.Context, now time.Time) {
+    expired, err := repository.ExpireBookingChanges(ctx, now)
+    if err != nil {
+        log.Printf("Failed to expire booking changes: %v", err)
+        return
+    }
+    if expired > 0 {
+        log.Printf("Expired %d unanswered booking changes", expired)
+    }
+}
+
+// getChangeForWalker loads a change and its booking, checking the walker is the one assigned
+func getChangeForWalker(ctx context.Context, bookingID, changeID, walkerID string) (*models.Booking, *models.BookingChange, error) {
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    change, err := repository.GetBookingChange(ctx, changeID)
+    if errors.Is(err, repository.ErrChangeNotFound) || (err == nil && change.BookingID != bookingID) {
+        return nil, nil, fmt.Errorf("booking change not found with id: %s", changeID)
+    }
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to retrieve booking change: %w", err)
+    }
+
+    if walkerID != booking.WalkerID {
+        return nil, nil, fmt.Errorf("invalid change response: only the assigned walker can respond")
+    }
+
+    if change.Status != models.BookingChangeStatusPending || change.IsExpired(clock.FromContext(ctx).Now()) {
+        return nil, nil, fmt.Errorf("booking conflict: %w", repository.ErrChangeNotPending)
+    }
+
+    return booking, change, nil
+}
+
+// newID generates a random 128-bit identifier
+func newID() (string, error) {
+    b := make([]byte, 16)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,126 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "math"
+    "time"
+
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// EventBookingUpdated is published when an owner patches their booking
+const EventBookingUpdated = "booking.updated"
+
+// PatchBookingService applies an owner's JSON Merge Patch to their booking. A rescheduled or
+// relocated booking is checked against its region's rules and holidays as a new booking
+// would be, and its region, price and tax are worked out again.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func PatchBookingService(ctx context.Context, bookingID, ownerID string, patch []byte) (*models.Booking, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.OwnerID != ownerID {
+        return nil, fmt.Errorf("patch not allowed: only the booking's owner can change it")
+    }
+
+    patched, err := booking.ApplyMergePatch(patch)
+    var patchErr *models.PatchError
+    if errors.As(err, &patchErr) {
+        return nil, err
+    }
+    if err != nil {
+        return nil, fmt.Errorf("invalid patch: %w", err)
+    }
+
+    if err := patched.Validate(); err != nil {
+        return nil, i18n.Errorf("booking.invalid_data", err)
+    }
+    now := clock.FromContext(ctx).Now()
+    rescheduled := !patched.ScheduledAt.Equal(booking.ScheduledAt) || !patched.EndsAt().Equal(booking.EndsAt())
+    if rescheduled && !patched.IsScheduledInFuture(now) {
+        return nil, i18n.Errorf("booking.not_in_future")
+    }
+
+    // A new address without new coordinates is placed by the geocoder again
+    if patched.Address != nil && !patched.Address.SamePlace(booking.Address) &&
+        sameCoordinate(patched.Latitude, booking.Latitude) && sameCoordinate(patched.Longitude, booking.Longitude) {
+        patched.Latitude, patched.Longitude = nil, nil
+        if sameCoordinate(patched.Address.Latitude, booking.Latitude) && sameCoordinate(patched.Address.Longitude, booking.Longitude) {
+            patched.Address.Latitude, patched.Address.Longitude = nil, nil
+        }
+    }
+    if err := resolvePickup(ctx, &patched); err != nil {
+        return nil, err
+    }
+
+    relocated := !sameCoordinate(patched.Latitude, booking.Latitude) || !sameCoordinate(patched.Longitude, booking.Longitude)
+    if relocated && patched.Latitude != nil {
+        // As when booking, the pickup point decides the region, and must lie in the service area
+        region, err := locateRegion(ctx, *patched.Latitude, *patched.Longitude)
+        if err != nil {
+            return nil, err
+        }
+        if region != "" {
+            patched.Region = region
+        }
+    }
+
+    if rescheduled || patched.Region != booking.Region {
+        if err := checkBookingRules(ctx, &patched, now); err != nil {
+            return nil, err
+        }
+        holiday, err := walkHoliday(ctx, patched.Region, patched.ScheduledAt, patched.DurationMinutes)
+        if err != nil {
+            return nil, err
+        }
+        if err := checkBlackout(holiday); err != nil {
+            return nil, err
+        }
+        repriceDuration(booking, &patched)
+        if err := applyTax(ctx, &patched); err != nil {
+            return nil, err
+        }
+    }
+
+    err = repository.UpdateBookingDetails(ctx, booking, &patched)
+    if errors.Is(err, repository.ErrBookingModified) {
+        return nil, fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to update booking: %w", err)
+    }
+
+    log.Printf("Booking %s patched by its owner", booking.ID)
+
+    syncBookingCalendars(&patched)
+    events.Publish(ctx, EventBookingUpdated, &patched)
+    return &patched, nil
+}
+
+// repriceDuration scales the amount of a booking whose length was patched to the new length,
+// as rate plans price walks. Only unassigned bookings are rescheduled by patch, and they carry
+// the amount the owner booked with rather than a walker's rates.
+func repriceDuration(booking, patched *models.Booking) {
+    before, after := booking.EndsAt().Sub(booking.ScheduledAt), patched.EndsAt().Sub(patched.ScheduledAt)
+    if before == after || before <= 0 {
+        return
+    }
+    patched.Amount = math.Round(patched.Amount*float64(after)/float64(before)*100) / 100
+}
+
+// sameCoordinate reports whether two optional coordinates are both unset or equal
+func sameCoordinate(a, b *float64) bool {
+    return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
@@ -0,0 +1,141 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// Codes of the booking rules clients are told they broke, so they can tell a walk booked
+// outside the booking window from other invalid bookings
+const (
+    // RuleCodeLeadTime is the code of walks booked too close to their start
+    RuleCodeLeadTime = "booking_lead_time"
+
+    // RuleCodeAdvanceWindow is the code of walks booked too far ahead
+    RuleCodeAdvanceWindow = "booking_advance_window"
+)
+
+// RuleError is returned when a walk breaks a booking rule that has a code of its own
+type RuleError struct {
+    // Code is RuleCodeLeadTime or RuleCodeAdvanceWindow
+    Code string
+
+    // Rule is the rule broken, whose limit tells the client when the walk may be booked
+    Rule models.BookingRule
+
+    // err is the message for the owner
+    err error
+}
+
+// Error returns the message for the owner in the default language
+func (e *RuleError) Error() string {
+    return e.err.Error()
+}
+
+// Unwrap returns the message for the owner, which i18n.Localize translates
+func (e *RuleError) Unwrap() error {
+    return e.err
+}
+
+// bookingRuleCheck reports why booking breaks rule at now, or nil when it passes
+type bookingRuleCheck func(rule models.BookingRule, booking *models.Booking, now time.Time) error
+
+// bookingRuleChecks evaluates each kind of booking rule. Errors are worded for the owner, and
+// the handlers map their prefixes to status codes.
+var bookingRuleChecks = map[models.BookingRuleKind]bookingRuleCheck{
+    models.BookingRuleMinLeadTime: func(rule models.BookingRule, booking *models.Booking, now time.Time) error {
+        if booking.ScheduledAt.Before(now.Add(time.Duration(rule.Minutes) * time.Minute)) {
+            return &RuleError{Code: RuleCodeLeadTime, Rule: rule, err: i18n.Errorf("booking.lead_time", rule.Minutes)}
+        }
+        return nil
+    },
+    models.BookingRuleMaxAdvance: func(rule models.BookingRule, booking *models.Booking, now time.Time) error {
+        if booking.ScheduledAt.After(now.AddDate(0, 0, rule.Days)) {
+            return &RuleError{Code: RuleCodeAdvanceWindow, Rule: rule, err: i18n.Errorf("booking.advance_window", rule.Days)}
+        }
+        return nil
+    },
+    models.BookingRuleMaxDuration: func(rule models.BookingRule, booking *models.Booking, now time.Time) error {
+        if booking.EndsAt().Sub(booking.ScheduledAt) > time.Duration(rule.Minutes)*time.Minute {
+            return i18n.Errorf("booking.invalid_data", i18n.Errorf("booking.duration_too_long", rule.Minutes))
+        }
+        return nil
+    },
+}
+
+// checkBookingRules checks a new booking against the rules of its region, stopping at the
+// first rule it breaks
+func checkBookingRules(ctx context.Context, booking *models.Booking, now time.Time) error {
+    set, err := GetBookingRulesService(ctx, booking.Region)
+    if err != nil {
+        return err
+    }
+    for _, rule := range set.Rules {
+        check, ok := bookingRuleChecks[rule.Kind]
+        if !ok {
+            // Saved sets are validated, so this is a rule from a newer version of the service
+            continue
+        }
+        if err := check(rule, booking, now); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// GetBookingRulesService returns the rules new bookings in region are checked against: the
+// region's own, else the default set, else DefaultBookingRules
+func GetBookingRulesService(ctx context.Context, region string) (*models.BookingRuleSet, error) {
+    for _, candidate := range []string{NormalizeRegion(region), models.DefaultRuleSetRegion} {
+        if candidate == "" {
+            continue
+        }
+        set, err := repository.GetBookingRuleSet(ctx, candidate)
+        if err == nil {
+            return set, nil
+        }
+        if !errors.Is(err, repository.ErrBookingRulesNotFound) {
+            return nil, fmt.Errorf("failed to load booking rules: %w", err)
+        }
+    }
+    return models.DefaultBookingRules(), nil
+}
+
+// SaveBookingRulesService replaces the rules of a region, or of every region without its own
+// when region is models.DefaultRuleSetRegion. Bookings already made are not checked again.
+func SaveBookingRulesService(ctx context.Context, region string, set *models.BookingRuleSet) error {
+    set.Region = NormalizeRegion(region)
+    if set.Rules == nil {
+        set.Rules = []models.BookingRule{}
+    }
+    if err := set.Validate(); err != nil {
+        return fmt.Errorf("invalid booking rules: %w", err)
+    }
+    set.UpdatedAt = clock.FromContext(ctx).Now().UTC()
+
+    if err := repository.SaveBookingRuleSet(ctx, set); err != nil {
+        return fmt.Errorf("failed to save booking rules: %w", err)
+    }
+    return nil
+}
+
+// DeleteBookingRulesService removes a region's own rules, so the default rules apply to it
+func DeleteBookingRulesService(ctx context.Context, region string) error {
+    region = NormalizeRegion(region)
+    err := repository.DeleteBookingRuleSet(ctx, region)
+    if errors.Is(err, repository.ErrBookingRulesNotFound) {
+        return fmt.Errorf("booking rules not found: %s", region)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to delete booking rules: %w", err)
+    }
+    return nil
+}
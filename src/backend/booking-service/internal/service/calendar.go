@@ -0,0 +1,54 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "fmt"
+    "net/url"
+    "time"
+
+    "src/backend/booking-service/internal/calendar"
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// maxCalendarEvents caps the walks in one calendar feed, most recently scheduled first
+const maxCalendarEvents = 500
+
+// WalkerCalendarPathService returns the signed path of a walker's calendar feed. Anyone with
+// the path can read the feed, so it is only given to the walker.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func WalkerCalendarPathService(walkerID string) (string, error) {
+    if walkerID == "" {
+        return "", fmt.Errorf("walker ID is required")
+    }
+    secret := config.Config.CalendarSecret
+    if secret == "" {
+        return "", fmt.Errorf("calendar feeds unavailable: no calendar secret configured")
+    }
+    return "/api/v1/calendars/" + url.PathEscape(walkerID) + ".ics?token=" + calendar.Token(secret, walkerID), nil
+}
+
+// WalkerCalendarService renders a walker's confirmed bookings as a calendar feed. The feed is
+// built from the bookings on every request, so subscribed calendars pick up new, moved and
+// cancelled walks the next time they refresh.
+func WalkerCalendarService(ctx context.Context, walkerID, token string) ([]byte, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    secret := config.Config.CalendarSecret
+    if secret == "" {
+        return nil, fmt.Errorf("calendar feeds unavailable: no calendar secret configured")
+    }
+    if !calendar.Verify(secret, walkerID, token) {
+        return nil, fmt.Errorf("calendar not found for walker: %s", walkerID)
+    }
+
+    bookings, err := repository.ListBookings(ctx, models.BookingFilter{Status: models.BookingStatusConfirmed, WalkerID: walkerID}, nil, maxCalendarEvents)
+    if err != nil {
+        return nil, fmt.Errorf("failed to build calendar: %w", err)
+    }
+    return calendar.Render("Dog walks", bookings, clock.FromContext(ctx).Now()), nil
+}
@@ -0,0 +1,289 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/integrations"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// Calendar connection events
+const (
+    EventCalendarConnected    = "calendar.connected"
+    EventCalendarDisconnected = "calendar.disconnected"
+)
+
+const (
+    // calendarStateTTL is how long a walker has to grant access once they start connecting
+    calendarStateTTL = 15 * time.Minute
+
+    // calendarSyncTimeout bounds writing one booking to its walker's calendars
+    calendarSyncTimeout = 30 * time.Second
+
+    // tokenRefreshMargin is how long before expiry an access token is refreshed
+    tokenRefreshMargin = time.Minute
+)
+
+// calendarStatuses are the booking statuses whose walks appear in walkers' calendars
+var calendarStatuses = map[models.BookingStatus]bool{
+    models.BookingStatusConfirmed:  true,
+    models.BookingStatusInProgress: true,
+    models.BookingStatusCompleted:  true,
+}
+
+// CalendarConnectURLService returns the page where a walker grants access to their calendar
+// with the given provider. The walker is sent back to the redirect URL to finish connecting.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CalendarConnectURLService(ctx context.Context, walkerID, provider string) (string, error) {
+    if walkerID == "" {
+        return "", fmt.Errorf("walker ID is required")
+    }
+    calendar, ok := integrations.Get(provider)
+    if !ok {
+        return "", fmt.Errorf("invalid calendar provider: %q is not available", provider)
+    }
+    secret := config.Config.CalendarSecret
+    if secret == "" {
+        return "", fmt.Errorf("calendar sync unavailable: no calendar secret configured")
+    }
+
+    state := integrations.SignState(secret, walkerID, provider, clock.FromContext(ctx).Now().Add(calendarStateTTL))
+    return calendar.AuthCodeURL(state), nil
+}
+
+// CompleteCalendarConnectionService finishes connecting a calendar once the provider sends
+// the walker back with a code, then writes the walker's upcoming confirmed walks to it
+func CompleteCalendarConnectionService(ctx context.Context, state, code string) (*models.CalendarConnection, error) {
+    walkerID, provider, err := integrations.ParseState(config.Config.CalendarSecret, state, clock.FromContext(ctx).Now())
+    if err != nil {
+        return nil, fmt.Errorf("invalid calendar connection: %w", err)
+    }
+    if code == "" {
+        return nil, fmt.Errorf("invalid calendar connection: no authorization code")
+    }
+    calendar, ok := integrations.Get(provider)
+    if !ok {
+        return nil, fmt.Errorf("invalid calendar provider: %q is not available", provider)
+    }
+
+    token, err := calendar.Exchange(ctx, code)
+    if err != nil {
+        return nil, fmt.Errorf("failed to connect calendar: %w", err)
+    }
+
+    now := clock.FromContext(ctx).Now().UTC()
+    connection := &models.CalendarConnection{
+        WalkerID:     walkerID,
+        Provider:     provider,
+        AccessToken:  token.AccessToken,
+        RefreshToken: token.RefreshToken,
+        ExpiresAt:    token.ExpiresAt,
+        CreatedAt:    now,
+        UpdatedAt:    now,
+    }
+    if err := repository.SaveCalendarConnection(ctx, connection); err != nil {
+        return nil, fmt.Errorf("failed to save calendar connection: %w", err)
+    }
+    events.Publish(ctx, EventCalendarConnected, connection)
+
+    backfillCalendar(ctx, walkerID)
+    return connection, nil
+}
+
+// ListCalendarConnectionsService returns the calendars a walker has connected
+func ListCalendarConnectionsService(ctx context.Context, walkerID string) ([]models.CalendarConnection, error) {
+    if walkerID == "" {
+        return nil, fmt.Errorf("walker ID is required")
+    }
+    connections, err := repository.ListCalendarConnections(ctx, walkerID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list calendar connections: %w", err)
+    }
+    return connections, nil
+}
+
+// DisconnectCalendarService stops writing walks to a walker's calendar. Events already
+// written are left for the walker to keep or remove.
+func DisconnectCalendarService(ctx context.Context, walkerID, provider string) error {
+    err := repository.DeleteCalendarConnection(ctx, walkerID, provider)
+    if errors.Is(err, repository.ErrCalendarNotConnected) {
+        return fmt.Errorf("calendar not found: walker %s has not connected %s", walkerID, provider)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to disconnect calendar: %w", err)
+    }
+    events.Publish(ctx, EventCalendarDisconnected, map[string]string{"walker_id": walkerID, "provider": provider})
+    return nil
+}
+
+// backfillCalendar writes a newly connected walker's upcoming confirmed walks to their calendars
+func backfillCalendar(ctx context.Context, walkerID string) {
+    bookings, err := repository.ListBookings(ctx, models.BookingFilter{Status: models.BookingStatusConfirmed, WalkerID: walkerID}, nil, maxCalendarEvents)
+    if err != nil {
+        log.Printf("Failed to list bookings of walker %s to write to their calendar: %v", walkerID, err)
+        return
+    }
+    now := clock.FromContext(ctx).Now()
+    for i := range bookings {
+        if bookings[i].EndsAt().After(now) {
+            syncBookingCalendars(&bookings[i])
+        }
+    }
+}
+
+// syncBookingCalendars brings the events for a booking in walkers' connected calendars in
+// line with the booking: its walker's calendars show it while it is confirmed, and events in
+// other walkers' calendars, or for a cancelled booking, are removed. Failures are logged, and
+// reported only so a booking confirmation can retry; a calendar provider outage never
+// affects bookings.
+func syncBookingCalendars(booking *models.Booking) error {
+    // Calendar APIs may be slower than the request that changed the booking allows
+    ctx, cancel := context.WithTimeout(context.Background(), calendarSyncTimeout)
+    defer cancel()
+
+    written, err := repository.ListCalendarEvents(ctx, booking.ID)
+    if err != nil {
+        log.Printf("Failed to list calendar events of booking %s: %v", booking.ID, err)
+        return err
+    }
+
+    var connections []models.CalendarConnection
+    if booking.IsAssigned() {
+        if connections, err = repository.ListCalendarConnections(ctx, booking.WalkerID); err != nil {
+            log.Printf("Failed to list calendars of walker %s: %v", booking.WalkerID, err)
+            return err
+        }
+    }
+    byProvider := make(map[string]*models.CalendarConnection, len(connections))
+    for i := range connections {
+        byProvider[connections[i].Provider] = &connections[i]
+    }
+
+    show := calendarStatuses[booking.Status]
+    eventIDs := make(map[string]string)
+    failed := 0
+    for _, event := range written {
+        if show && event.WalkerID == booking.WalkerID && byProvider[event.Provider] != nil {
+            eventIDs[event.Provider] = event.EventID
+            continue
+        }
+        if !removeCalendarEvent(ctx, event) {
+            failed++
+        }
+    }
+    if show {
+        for _, connection := range byProvider {
+            if !writeCalendarEvent(ctx, connection, booking, eventIDs[connection.Provider]) {
+                failed++
+            }
+        }
+    }
+
+    if failed > 0 {
+        return fmt.Errorf("%d calendar updates of booking %s failed", failed, booking.ID)
+    }
+    return nil
+}
+
+// writeCalendarEvent creates or updates the event for a booking in one connected calendar,
+// reporting whether the calendar now shows it
+func writeCalendarEvent(ctx context.Context, connection *models.CalendarConnection, booking *models.Booking, eventID string) bool {
+    calendar, accessToken, err := calendarAccess(ctx, connection)
+    if err != nil {
+        log.Printf("Failed to access %s calendar of walker %s: %v", connection.Provider, connection.WalkerID, err)
+        return false
+    }
+
+    event := integrations.Event{
+        Summary:     "Dog walk",
+        Description: fmt.Sprintf("Booking %s\nDog %s", booking.ID, booking.DogID),
+        Location:    booking.Region,
+        Start:       booking.ScheduledAt,
+        End:         booking.EndsAt(),
+    }
+    savedID, err := calendar.SaveEvent(ctx, accessToken, eventID, event)
+    if errors.Is(err, integrations.ErrEventNotFound) && eventID != "" {
+        // The walker deleted the event; write it again
+        savedID, err = calendar.SaveEvent(ctx, accessToken, "", event)
+    }
+    if err != nil {
+        log.Printf("Failed to write booking %s to %s calendar of walker %s: %v", booking.ID, connection.Provider, connection.WalkerID, err)
+        return false
+    }
+
+    if savedID != eventID {
+        err = repository.SaveCalendarEvent(ctx, &models.CalendarEvent{
+            BookingID: booking.ID,
+            WalkerID:  connection.WalkerID,
+            Provider:  connection.Provider,
+            EventID:   savedID,
+        })
+        if err != nil {
+            log.Printf("Failed to record calendar event of booking %s: %v", booking.ID, err)
+        }
+    }
+    return true
+}
+
+// removeCalendarEvent deletes an event written for a booking and forgets it. Events in
+// calendars that have since been disconnected are only forgotten. It reports whether the event
+// is gone from the calendar.
+func removeCalendarEvent(ctx context.Context, event models.CalendarEvent) bool {
+    connections, err := repository.ListCalendarConnections(ctx, event.WalkerID)
+    if err != nil {
+        log.Printf("Failed to list calendars of walker %s: %v", event.WalkerID, err)
+        return false
+    }
+    for i := range connections {
+        if connections[i].Provider != event.Provider {
+            continue
+        }
+        calendar, accessToken, err := calendarAccess(ctx, &connections[i])
+        if err == nil {
+            err = calendar.DeleteEvent(ctx, accessToken, event.EventID)
+        }
+        if err != nil {
+            log.Printf("Failed to remove booking %s from %s calendar of walker %s: %v", event.BookingID, event.Provider, event.WalkerID, err)
+            return false
+        }
+    }
+
+    if err := repository.DeleteCalendarEvent(ctx, event.BookingID, event.WalkerID, event.Provider); err != nil {
+        log.Printf("Failed to forget calendar event of booking %s: %v", event.BookingID, err)
+    }
+    return true
+}
+
+// calendarAccess returns the provider of a connected calendar and a current access token,
+// refreshing and storing the token when it is about to expire
+func calendarAccess(ctx context.Context, connection *models.CalendarConnection) (integrations.Provider, string, error) {
+    calendar, ok := integrations.Get(connection.Provider)
+    if !ok {
+        return nil, "", fmt.Errorf("calendar provider %q is not available", connection.Provider)
+    }
+    if time.Until(connection.ExpiresAt) > tokenRefreshMargin {
+        return calendar, connection.AccessToken, nil
+    }
+
+    token, err := calendar.Refresh(ctx, connection.RefreshToken)
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to refresh calendar token: %w", err)
+    }
+    connection.AccessToken = token.AccessToken
+    connection.RefreshToken = token.RefreshToken
+    connection.ExpiresAt = token.ExpiresAt
+    connection.UpdatedAt = clock.FromContext(ctx).Now().UTC()
+    if err := repository.SaveCalendarConnection(ctx, connection); err != nil {
+        return nil, "", fmt.Errorf("failed to store refreshed calendar token: %w", err)
+    }
+    return calendar, connection.AccessToken, nil
+}
@@ -0,0 +1,133 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "strings"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// EventBookingCancelled is published when an owner cancels their booking
+const EventBookingCancelled = "booking.cancelled"
+
+// freeCancellation charges nothing at any notice. It applies to bookings that lost their walker
+// through no fault of the owner.
+var freeCancellation = models.CancellationPolicy{{Notice: 0, FeePercent: 0}}
+
+// cancellationPolicy returns the configured cancellation policy, or the default one
+func cancellationPolicy() models.CancellationPolicy {
+    if config.Config == nil || len(config.Config.CancellationPolicy) == 0 {
+        return models.DefaultCancellationPolicy()
+    }
+    return config.Config.CancellationPolicy
+}
+
+// CancelBookingService cancels an owner's pending or confirmed booking. The fee for the notice
+// given is worked out from config.Config.CancellationPolicy and stored with the booking, and
+// the rest of its total is refunded through the payment-service.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CancelBookingService(ctx context.Context, bookingID, ownerID string) (*models.Booking, error) {
+    if ownerID == "" {
+        return nil, fmt.Errorf("invalid cancellation: owner ID is required")
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.OwnerID != ownerID {
+        return nil, fmt.Errorf("cancellation not allowed: only the booking's owner can cancel it")
+    }
+    if cancelledBy(booking, ownerID) {
+        return booking, nil
+    }
+
+    policy := cancellationPolicy()
+    booking, err = repository.CancelBooking(ctx, bookingID, func(b *models.Booking) *models.Cancellation {
+        // The fee is worked out under the booking's lock, from its total at the moment it is cancelled
+        if b.Status == models.BookingStatusNeedsReassignment {
+            return freeCancellation.Cancel(b, clock.FromContext(ctx).Now(), ownerID)
+        }
+        return policy.Cancel(b, clock.FromContext(ctx).Now(), ownerID)
+    })
+    if errors.Is(err, repository.ErrNotCancellable) {
+        // A concurrent repeat of this request may have cancelled it first
+        if current, getErr := GetBookingService(ctx, bookingID); getErr == nil && cancelledBy(current, ownerID) {
+            return current, nil
+        }
+        return nil, fmt.Errorf("cancellation not allowed: %w", err)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    log.Printf("Booking %s cancelled by its owner with a fee of %.2f", booking.ID, booking.Cancellation.Fee)
+
+    refundCancellation(ctx, booking)
+    syncBookingCalendars(booking)
+    events.Publish(ctx, EventBookingCancelled, booking)
+
+    locale := userLocale(ctx, booking.OwnerID)
+    currency := strings.ToUpper(models.BookingCurrency)
+    reason := i18n.T(locale, "email.booking_cancelled.free", booking.Cancellation.Refund, currency)
+    if booking.Cancellation.Fee > 0 {
+        reason = i18n.T(locale, "email.booking_cancelled.fee",
+            booking.Cancellation.Fee, currency, booking.Cancellation.Refund, currency)
+    }
+    emailOwner(ctx, booking, notifier.EmailBookingCancelled, notifier.EmailData{
+        Locale: locale,
+        Reason: reason,
+    })
+    return booking, nil
+}
+
+// cancelledBy reports whether the booking was already cancelled by ownerID, so a repeated
+// cancellation is answered with it as it is, without charging or refunding again
+func cancelledBy(booking *models.Booking, ownerID string) bool {
+    return booking.Status == models.BookingStatusCancelled && booking.Cancellation != nil &&
+        booking.Cancellation.CancelledBy == ownerID
+}
+
+// refundCancellation refunds the owner of a cancelled booking everything but the cancellation
+// fee. The booking stays cancelled when the refund fails, and nightly reconciliation reports it
+// as an unrefunded cancellation.
+func refundCancellation(ctx context.Context, booking *models.Booking) {
+    cancellation := booking.Cancellation
+    if cancellation == nil || models.AmountCents(cancellation.Refund) <= 0 {
+        return
+    }
+    refunder := providersFrom(ctx).Refunds
+    if refunder == nil {
+        log.Printf("Refund of cancelled booking %s skipped: no payment service configured", booking.ID)
+        return
+    }
+
+    result, err := refunder.Refund(ctx, payments.Refund{
+        BookingID:   booking.ID,
+        AmountCents: models.AmountCents(cancellation.Refund),
+        Reference:   "cancellation-" + booking.ID,
+    })
+    if err != nil {
+        log.Printf("Failed to refund cancelled booking %s: %v", booking.ID, err)
+        return
+    }
+
+    refunded := *cancellation
+    refunded.RefundID = result.RefundID
+    booking.Cancellation = &refunded
+    if err := repository.RecordCancellationRefund(ctx, booking.ID, result.RefundID); err != nil {
+        // The owner has been refunded, so the cancellation stands without the refund ID
+        log.Printf("Failed to record refund %s for cancelled booking %s: %v", result.RefundID, booking.ID, err)
+    }
+}
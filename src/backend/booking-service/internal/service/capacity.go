@@ -0,0 +1,248 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "bytes"
+    "context"
+    "encoding/csv"
+    "fmt"
+    "log"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+)
+
+const (
+    // maxCapacityRange is the longest period one capacity report may cover
+    maxCapacityRange = 31 * 24 * time.Hour
+
+    // capacityReportName identifies the daily capacity email among the scheduled reports
+    capacityReportName = "capacity"
+
+    // capacityReportSendHour is the UTC hour from which the daily capacity email is sent
+    capacityReportSendHour = 6
+
+    // maxEmailedThinSlots caps the thin slots listed in the email body; the attachment has them all
+    maxEmailedThinSlots = 50
+)
+
+// lastCapacityReportDay is the most recent day this instance sent, or saw sent, the capacity
+// email, so the background job does not try to claim it again every minute
+var lastCapacityReportDay time.Time
+
+// NormalizeRegion returns region in the form it is stored and compared in
+func NormalizeRegion(region string) string {
+    return strings.ToLower(strings.TrimSpace(region))
+}
+
+// CapacityReportService compares the walks booked in each region and hour of [from, to) with
+// the dogs walkers are available to take, so operations can see where coverage is thin. The
+// range is widened to whole hours. A non-empty region limits the report to that region.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CapacityReportService(ctx context.Context, from, to time.Time, region string) (*models.CapacityReport, error) {
+    from = from.UTC().Truncate(time.Hour)
+    if rounded := to.UTC().Truncate(time.Hour); rounded.Before(to) {
+        to = rounded.Add(time.Hour)
+    } else {
+        to = rounded
+    }
+    if !from.Before(to) {
+        return nil, fmt.Errorf("invalid report range: from must be before to")
+    }
+    if to.Sub(from) > maxCapacityRange {
+        return nil, fmt.Errorf("invalid report range: at most %d days can be reported", int(maxCapacityRange/(24*time.Hour)))
+    }
+    region = NormalizeRegion(region)
+
+    windows, err := repository.ListAvailabilityBetween(ctx, from, to)
+    if err != nil {
+        return nil, fmt.Errorf("failed to build capacity report: %w", err)
+    }
+    bookings, err := repository.ListActiveBookingsBetween(ctx, from, to)
+    if err != nil {
+        return nil, fmt.Errorf("failed to build capacity report: %w", err)
+    }
+
+    type slotKey struct {
+        region string
+        hour   time.Time
+    }
+    slots := make(map[slotKey]*models.CapacitySlot)
+    walkers := make(map[slotKey]map[string]bool)
+    slotAt := func(region string, hour time.Time) (slotKey, *models.CapacitySlot) {
+        key := slotKey{region: region, hour: hour}
+        slot, ok := slots[key]
+        if !ok {
+            slot = &models.CapacitySlot{Region: region, Hour: hour}
+            slots[key] = slot
+            walkers[key] = make(map[string]bool)
+        }
+        return key, slot
+    }
+
+    // A window or walk counts towards every hour it overlaps, even in part
+    for _, window := range windows {
+        if region != "" && window.Region != region {
+            continue
+        }
+        for hour := window.StartsAt.UTC().Truncate(time.Hour); hour.Before(window.EndsAt) && hour.Before(to); hour = hour.Add(time.Hour) {
+            if hour.Before(from) {
+                continue
+            }
+            key, slot := slotAt(window.Region, hour)
+            slot.Supply += window.Capacity
+            walkers[key][window.WalkerID] = true
+        }
+    }
+    for _, booking := range bookings {
+        if region != "" && booking.Region != region {
+            continue
+        }
+        for hour := booking.ScheduledAt.UTC().Truncate(time.Hour); hour.Before(booking.EndsAt()) && hour.Before(to); hour = hour.Add(time.Hour) {
+            if hour.Before(from) {
+                continue
+            }
+            _, slot := slotAt(booking.Region, hour)
+            slot.Demand++
+            if !booking.IsAssigned() {
+                slot.Unassigned++
+            }
+        }
+    }
+
+    report := &models.CapacityReport{From: from, To: to, Slots: make([]models.CapacitySlot, 0, len(slots))}
+    for key, slot := range slots {
+        slot.Walkers = len(walkers[key])
+        if slot.Demand > slot.Supply {
+            slot.Shortfall = slot.Demand - slot.Supply
+            report.ThinSlots++
+        }
+        report.Slots = append(report.Slots, *slot)
+    }
+    sort.Slice(report.Slots, func(i, j int) bool {
+        a, b := report.Slots[i], report.Slots[j]
+        if a.Region != b.Region {
+            return a.Region < b.Region
+        }
+        return a.Hour.Before(b.Hour)
+    })
+    return report, nil
+}
+
+// sendCapacityReport emails the upcoming capacity report to config.Config.CapacityReportRecipients
+// once a day, from capacityReportSendHour UTC. Only one instance sends each day's report.
+func sendCapacityReport(ctx context.Context, now time.Time) {
+    recipients := config.Config.CapacityReportRecipients
+    if len(recipients) == 0 {
+        return
+    }
+
+    now = now.UTC()
+    day := now.Truncate(24 * time.Hour)
+    if now.Hour() < capacityReportSendHour || !day.After(lastCapacityReportDay) {
+        return
+    }
+
+    claimed, err := repository.ClaimScheduledReport(ctx, capacityReportName, day, now)
+    if err != nil {
+        log.Printf("Failed to claim capacity report for %s: %v", day.Format("2006-01-02"), err)
+        return
+    }
+    lastCapacityReportDay = day
+    if !claimed {
+        return
+    }
+
+    if err := emailCapacityReport(ctx, recipients, now); err != nil {
+        log.Printf("Failed to send capacity report for %s: %v", day.Format("2006-01-02"), err)
+        // Release the day so the next tick, on this or another instance, tries again
+        if err := repository.ReleaseScheduledReport(ctx, capacityReportName, day); err != nil {
+            log.Printf("Failed to release capacity report for %s: %v", day.Format("2006-01-02"), err)
+        }
+        lastCapacityReportDay = time.Time{}
+    }
+}
+
+// emailCapacityReport builds the report for the configured horizon from now and emails it,
+// listing the thin slots in the body and attaching every slot as CSV
+func emailCapacityReport(ctx context.Context, recipients []string, now time.Time) error {
+    report, err := CapacityReportService(ctx, now, now.Add(config.Config.CapacityReportHorizon), "")
+    if err != nil {
+        return err
+    }
+
+    var body strings.Builder
+    fmt.Fprintf(&body, "Walk capacity from %s to %s (UTC).\n\n", report.From.Format("Mon 2 Jan 15:04"), report.To.Format("Mon 2 Jan 15:04"))
+    if report.ThinSlots == 0 {
+        body.WriteString("Every booked hour has enough available walkers.\n")
+    } else {
+        fmt.Fprintf(&body, "%d region hours have more dogs booked than walkers can take:\n\n", report.ThinSlots)
+        listed := 0
+        for _, slot := range report.Slots {
+            if slot.Shortfall == 0 {
+                continue
+            }
+            if listed == maxEmailedThinSlots {
+                fmt.Fprintf(&body, "...and %d more in the attached report.\n", report.ThinSlots-listed)
+                break
+            }
+            fmt.Fprintf(&body, "%s  %s: %d booked, %d available, short by %d\n",
+                slot.Hour.Format("Mon 2 Jan 15:04"), regionLabel(slot.Region), slot.Demand, slot.Supply, slot.Shortfall)
+            listed++
+        }
+    }
+
+    attachment, err := capacityReportCSV(report)
+    if err != nil {
+        return err
+    }
+    email := notifier.Email{
+        Subject: fmt.Sprintf("Walk capacity report for %s", now.Format("2 Jan 2006")),
+        Body:    body.String(),
+        Attachments: []notifier.Attachment{{
+            Filename:    "capacity-" + now.Format("2006-01-02") + ".csv",
+            ContentType: "text/csv",
+            Content:     attachment,
+        }},
+    }
+
+    _, err = emailRecipients(ctx, recipients, email, "capacity report")
+    return err
+}
+
+// capacityReportCSV renders every slot of the report as CSV
+func capacityReportCSV(report *models.CapacityReport) ([]byte, error) {
+    var buf bytes.Buffer
+    w := csv.NewWriter(&buf)
+    w.Write([]string{"region", "hour", "demand", "unassigned", "supply", "walkers", "shortfall"})
+    for _, slot := range report.Slots {
+        w.Write([]string{
+            slot.Region,
+            slot.Hour.Format(time.RFC3339),
+            strconv.Itoa(slot.Demand),
+            strconv.Itoa(slot.Unassigned),
+            strconv.Itoa(slot.Supply),
+            strconv.Itoa(slot.Walkers),
+            strconv.Itoa(slot.Shortfall),
+        })
+    }
+    w.Flush()
+    if err := w.Error(); err != nil {
+        return nil, fmt.Errorf("failed to write capacity report: %w", err)
+    }
+    return buf.Bytes(), nil
+}
+
+// regionLabel names a region in messages; bookings and windows without one are grouped together
+func regionLabel(region string) string {
+    if region == "" {
+        return "(no region)"
+    }
+    return region
+}
@@ -0,0 +1,284 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "math"
+    "strings"
+
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// Dispute events
+const (
+    EventDisputeOpened   = "booking.dispute_opened"
+    EventDisputeResolved = "booking.dispute_resolved"
+)
+
+// maxDisputeList caps the number of disputes returned by one list request
+const maxDisputeList = 200
+
+// OpenDisputeService records an owner's dispute of a completed or failed booking. Each booking
+// can be disputed once; the dispute waits in the queue until support reviews it.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func OpenDisputeService(ctx context.Context, bookingID, ownerID, reason string) (*models.Dispute, error) {
+    reason = strings.TrimSpace(reason)
+    if ownerID == "" {
+        return nil, fmt.Errorf("invalid dispute: owner ID is required")
+    }
+    if reason == "" {
+        return nil, fmt.Errorf("invalid dispute: reason is required")
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.OwnerID != ownerID {
+        return nil, fmt.Errorf("dispute not allowed: only the booking's owner can dispute it")
+    }
+    if booking.Status != models.BookingStatusCompleted && booking.Status != models.BookingStatusFailed {
+        return nil, fmt.Errorf("dispute not allowed: booking is %s", booking.Status)
+    }
+
+    id, err := newID()
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate dispute ID: %w", err)
+    }
+    dispute := &models.Dispute{
+        ID:        id,
+        BookingID: booking.ID,
+        OwnerID:   ownerID,
+        Reason:    reason,
+        Status:    models.DisputeOpen,
+        CreatedAt: clock.FromContext(ctx).Now(),
+    }
+    err = repository.CreateDispute(ctx, dispute)
+    if errors.Is(err, repository.ErrDisputeExists) {
+        return nil, fmt.Errorf("dispute conflict: %w", err)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to record dispute: %w", err)
+    }
+
+    events.Publish(ctx, EventDisputeOpened, dispute)
+    return dispute, nil
+}
+
+// GetBookingDisputeService retrieves the dispute of a booking for its owner
+func GetBookingDisputeService(ctx context.Context, bookingID, ownerID string) (*models.Dispute, error) {
+    dispute, err := repository.GetDisputeForBooking(ctx, bookingID)
+    if errors.Is(err, repository.ErrDisputeNotFound) {
+        return nil, fmt.Errorf("dispute not found for booking: %s", bookingID)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve dispute: %w", err)
+    }
+    if dispute.OwnerID != ownerID {
+        // Other users are not told whether the booking was disputed
+        return nil, fmt.Errorf("dispute not found for booking: %s", bookingID)
+    }
+    return dispute, nil
+}
+
+// GetDisputeService retrieves a dispute by ID
+func GetDisputeService(ctx context.Context, id string) (*models.Dispute, error) {
+    dispute, err := repository.GetDispute(ctx, id)
+    if errors.Is(err, repository.ErrDisputeNotFound) {
+        return nil, fmt.Errorf("dispute not found with id: %s", id)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve dispute: %w", err)
+    }
+    return dispute, nil
+}
+
+// ListDisputesService lists disputes in status, oldest first, for the support queue. An empty
+// status lists every dispute.
+func ListDisputesService(ctx context.Context, status models.DisputeStatus, limit int) ([]models.Dispute, error) {
+    switch status {
+    case "", models.DisputeOpen, models.DisputeUnderReview, models.DisputeResolvedRefund, models.DisputeResolvedDenied:
+    default:
+        return nil, fmt.Errorf("invalid dispute status: %s", status)
+    }
+    if limit <= 0 || limit > maxDisputeList {
+        limit = maxDisputeList
+    }
+
+    disputes, err := repository.ListDisputes(ctx, status, limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list disputes: %w", err)
+    }
+    return disputes, nil
+}
+
+// ReviewDisputeService marks an open dispute as being investigated by actorID
+func ReviewDisputeService(ctx context.Context, actorID, id string) (*models.Dispute, error) {
+    dispute, err := GetDisputeService(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    if dispute.Status != models.DisputeOpen {
+        return nil, fmt.Errorf("dispute conflict: dispute is %s", dispute.Status)
+    }
+
+    dispute.Status = models.DisputeUnderReview
+    dispute.ReviewedBy = actorID
+    err = repository.UpdateDispute(ctx, dispute, models.DisputeOpen)
+    if errors.Is(err, repository.ErrDisputeStatusChanged) {
+        return nil, fmt.Errorf("dispute conflict: %w", err)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to update dispute: %w", err)
+    }
+
+    log.Printf("Dispute %s for booking %s taken under review by %s", dispute.ID, dispute.BookingID, actorID)
+    return dispute, nil
+}
+
+// ResolveDisputeService closes a dispute on behalf of actorID. With refund set, amount is
+// refunded to the owner through the payment-service, the booking's full total when amount is
+// zero; otherwise the dispute is denied. The resolution explains the outcome to the owner.
+func ResolveDisputeService(ctx context.Context, actorID, id string, refund bool, amount float64, resolution string) (*models.Dispute, error) {
+    resolution = strings.TrimSpace(resolution)
+    if resolution == "" {
+        return nil, fmt.Errorf("invalid dispute resolution: resolution is required")
+    }
+    amount = math.Round(amount*100) / 100
+    if math.IsNaN(amount) || math.IsInf(amount, 0) || amount < 0 {
+        return nil, fmt.Errorf("invalid dispute resolution: refund amount must not be negative")
+    }
+    if !refund && amount > 0 {
+        return nil, fmt.Errorf("invalid dispute resolution: a denied dispute cannot have a refund amount")
+    }
+
+    dispute, err := GetDisputeService(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+    if dispute.Status.IsResolved() {
+        return nil, fmt.Errorf("dispute conflict: dispute is already %s", dispute.Status)
+    }
+
+    refunder := providersFrom(ctx).Refunds
+    if refund {
+        if refunder == nil {
+            return nil, fmt.Errorf("refunds unavailable: no payment service configured")
+        }
+        booking, err := GetBookingService(ctx, dispute.BookingID)
+        if err != nil {
+            return nil, err
+        }
+        total := booking.TotalAmount()
+        if amount == 0 {
+            amount = total
+        }
+        if models.AmountCents(amount) > models.AmountCents(total) {
+            return nil, fmt.Errorf("invalid dispute resolution: refund of %.2f exceeds the booking total of %.2f", amount, total)
+        }
+    }
+
+    previous := dispute.Status
+    now := clock.FromContext(ctx).Now()
+    dispute.Resolution = resolution
+    dispute.ReviewedBy = actorID
+    dispute.ResolvedAt = &now
+    dispute.Status = models.DisputeResolvedDenied
+    if refund {
+        dispute.Status = models.DisputeResolvedRefund
+        dispute.RefundAmount = amount
+    }
+
+    // The dispute is resolved before the refund is requested, so an admin resolving it at the
+    // same time gets a conflict instead of refunding the owner a second time
+    err = repository.UpdateDispute(ctx, dispute, models.DisputeOpen, models.DisputeUnderReview)
+    if errors.Is(err, repository.ErrDisputeStatusChanged) {
+        return nil, fmt.Errorf("dispute conflict: %w", err)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to update dispute: %w", err)
+    }
+
+    if refund {
+        result, err := refunder.Refund(ctx, payments.Refund{
+            BookingID:   dispute.BookingID,
+            AmountCents: models.AmountCents(amount),
+            Reference:   dispute.ID,
+        })
+        if err != nil {
+            reopenDispute(ctx, dispute, previous)
+            return nil, fmt.Errorf("failed to refund dispute: %w", err)
+        }
+
+        dispute.RefundID = result.RefundID
+        if err := repository.UpdateDispute(ctx, dispute, models.DisputeResolvedRefund); err != nil {
+            // The owner has been refunded, so the resolution stands without the refund ID
+            log.Printf("Failed to record refund %s for dispute %s: %v", result.RefundID, dispute.ID, err)
+        }
+    }
+
+    events.Publish(ctx, EventDisputeResolved, dispute)
+    notifyDisputeOwner(ctx, dispute)
+    return dispute, nil
+}
+
+// reopenDispute returns a dispute whose refund failed to the status it was resolved from, so
+// it can be resolved again; failures are only logged
+func reopenDispute(ctx context.Context, dispute *models.Dispute, status models.DisputeStatus) {
+    dispute.Status = status
+    dispute.Resolution = ""
+    dispute.RefundAmount = 0
+    dispute.ResolvedAt = nil
+    if err := repository.UpdateDispute(ctx, dispute, models.DisputeResolvedRefund); err != nil {
+        log.Printf("Failed to reopen dispute %s after its refund failed: %v", dispute.ID, err)
+    }
+}
+
+// disputeRefundCents returns the amount refunded on a booking's dispute, or zero when the
+// booking has no dispute resolved with a refund
+func disputeRefundCents(ctx context.Context, bookingID string) (int64, error) {
+    dispute, err := repository.GetDisputeForBooking(ctx, bookingID)
+    if errors.Is(err, repository.ErrDisputeNotFound) {
+        return 0, nil
+    }
+    if err != nil {
+        return 0, err
+    }
+    if dispute.Status != models.DisputeResolvedRefund {
+        return 0, nil
+    }
+    return models.AmountCents(dispute.RefundAmount), nil
+}
+
+// notifyDisputeOwner tells the owner how their dispute was resolved; failures are only logged
+func notifyDisputeOwner(ctx context.Context, dispute *models.Dispute) {
+    locale := userLocale(ctx, dispute.OwnerID)
+    body := i18n.T(locale, "notify.dispute_rejected.body", dispute.Resolution)
+    if dispute.Status == models.DisputeResolvedRefund {
+        body = i18n.T(locale, "notify.dispute_refunded.body",
+            dispute.RefundAmount, strings.ToUpper(models.BookingCurrency), dispute.Resolution)
+    }
+
+    err := providersFrom(ctx).Notifier.Notify(ctx, dispute.OwnerID, notifier.Notification{
+        Subject:  i18n.T(locale, "notify.dispute_resolved.subject"),
+        Body:     body,
+        Category: notifier.CategoryBookings,
+        Data: map[string]string{
+            "event":      EventDisputeResolved,
+            "booking_id": dispute.BookingID,
+            "dispute_id": dispute.ID,
+        },
+    })
+    if err != nil {
+        log.Printf("Failed to notify owner %s of dispute %s: %v", dispute.OwnerID, dispute.ID, err)
+    }
+}
@@ -0,0 +1,186 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "math"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/moderation"
+)
+
+const (
+    // maxRoutePoints is the most positions a walk summary may carry, about three hours at one
+    // position a second
+    maxRoutePoints = 10000
+
+    // maxSummaryNotes is the longest the walker's notes on a walk may be, in characters
+    maxSummaryNotes = 2000
+
+    // earthRadiusKm is the mean radius of the Earth used to measure walked distances
+    earthRadiusKm = 6371.0
+
+    // moderationTimeout bounds how long walk notes wait on the moderation filter
+    moderationTimeout = 2 * time.Second
+)
+
+// SendWalkSummaryService emails the owner of a booking the walker's summary of its walk, with
+// a map of the route. The walk's distance and duration are worked out from the route. Notes the
+// moderation filter blocks are rejected; notes it flags are left out of the email and sent to
+// the operations channel for review, as the email cannot be taken back once sent.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func SendWalkSummaryService(ctx context.Context, summary *models.WalkSummary) (*models.WalkSummary, error) {
+    if summary.WalkerID == "" {
+        return nil, fmt.Errorf("invalid walk summary: walker ID is required")
+    }
+    if len(summary.Route) == 0 || len(summary.Route) > maxRoutePoints {
+        return nil, fmt.Errorf("invalid walk summary: route must have between 1 and %d points", maxRoutePoints)
+    }
+    for _, point := range summary.Route {
+        if point.Latitude < -90 || point.Latitude > 90 || point.Longitude < -180 || point.Longitude > 180 {
+            return nil, fmt.Errorf("invalid walk summary: route coordinates are out of range")
+        }
+    }
+    summary.Notes = strings.TrimSpace(summary.Notes)
+    if len([]rune(summary.Notes)) > maxSummaryNotes {
+        return nil, fmt.Errorf("invalid walk summary: notes must be at most %d characters", maxSummaryNotes)
+    }
+
+    booking, err := GetBookingService(ctx, summary.BookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.WalkerID != summary.WalkerID {
+        return nil, fmt.Errorf("invalid walk summary: walker %s is not assigned to booking %s", summary.WalkerID, booking.ID)
+    }
+    if booking.Status != models.BookingStatusConfirmed && booking.Status != models.BookingStatusCompleted {
+        return nil, fmt.Errorf("booking conflict: booking is %s, not confirmed or completed", booking.Status)
+    }
+
+    notes := summary.Notes
+    if notes != "" {
+        verdict := moderateNotes(ctx, notes)
+        switch verdict.Action {
+        case moderation.ActionBlock:
+            return nil, fmt.Errorf("invalid walk summary: notes contain blocked content")
+        case moderation.ActionFlag:
+            notes = ""
+            flagWalkNotes(ctx, booking, summary.Notes, verdict)
+        }
+    }
+
+    points := make([]notifier.RoutePoint, len(summary.Route))
+    summary.DistanceKm = 0
+    for i, point := range summary.Route {
+        points[i] = notifier.RoutePoint{Latitude: point.Latitude, Longitude: point.Longitude}
+        if i > 0 {
+            summary.DistanceKm += distanceKm(summary.Route[i-1], point)
+        }
+    }
+    summary.DistanceKm = math.Round(summary.DistanceKm*100) / 100
+    summary.DurationMinutes = 0
+    summary.NotesWithheld = false
+    if first, last := summary.Route[0].Timestamp, summary.Route[len(summary.Route)-1].Timestamp; !first.IsZero() && last.After(first) {
+        summary.DurationMinutes = int(math.Round(last.Sub(first).Minutes()))
+    }
+
+    data := notifier.EmailData{
+        DurationMinutes: summary.DurationMinutes,
+        DistanceKm:      summary.DistanceKm,
+        Notes:           notes,
+    }
+    var attachments []notifier.Attachment
+    routeMap, err := notifier.RenderRouteMap(points)
+    if err != nil {
+        // The summary is still worth sending without its map
+        log.Printf("Failed to draw route map for booking %s: %v", booking.ID, err)
+    } else {
+        data.RouteMap = true
+        attachments = append(attachments, notifier.Attachment{
+            Filename:    "route.png",
+            ContentType: "image/png",
+            Content:     routeMap,
+            ContentID:   notifier.RouteMapContentID,
+        })
+    }
+
+    emailOwner(ctx, booking, notifier.EmailWalkSummary, data, attachments...)
+    summary.NotesWithheld = summary.Notes != "" && notes == ""
+    return summary, nil
+}
+
+// moderateNotes reviews a walker's notes with the moderation filter. A filter error allows
+// them, so an unavailable provider does not hold up the summary.
+func moderateNotes(ctx context.Context, notes string) moderation.Verdict {
+    ctx, cancel := context.WithTimeout(ctx, moderationTimeout)
+    defer cancel()
+
+    verdict, err := moderation.Review(ctx, notes)
+    if err != nil {
+        log.Printf("Failed to moderate walk notes, allowing them: %v", err)
+        return moderation.Verdict{Action: moderation.ActionAllow}
+    }
+    return verdict
+}
+
+// flagWalkNotes sends notes withheld from a walk summary to the operations channel for review
+func flagWalkNotes(ctx context.Context, booking *models.Booking, notes string, verdict moderation.Verdict) {
+    err := providersFrom(ctx).Alerts.Alert(ctx, notifier.Alert{
+        Title: "Walk notes flagged for review",
+        Text:  notes,
+        Fields: map[string]string{
+            "booking_id": booking.ID,
+            "walker_id":  booking.WalkerID,
+            "matches":    strings.Join(verdict.Matches, ", "),
+        },
+    })
+    if err != nil {
+        log.Printf("Failed to send flagged walk notes for booking %s for review: %v", booking.ID, err)
+    }
+}
+
+// emailOwner emails the owner of a booking from an email template, unless their address is
+// unknown. Failures are logged, as emails only supplement push notifications.
+func emailOwner(ctx context.Context, booking *models.Booking, template string, data notifier.EmailData, attachments ...notifier.Attachment) {
+    address, err := repository.GetUserEmail(ctx, booking.OwnerID)
+    if err != nil {
+        log.Printf("Failed to look up email of owner of booking %s: %v", booking.ID, err)
+        return
+    }
+    if address == "" {
+        return
+    }
+
+    if data.Locale == "" {
+        data.Locale = userLocale(ctx, booking.OwnerID)
+    }
+    if data.Time == "" {
+        data.Time = i18n.FormatTime(data.Locale, booking.ScheduledAt.UTC())
+    }
+    email, err := notifier.RenderEmail(template, data)
+    if err != nil {
+        log.Printf("Failed to render %s email for booking %s: %v", template, booking.ID, err)
+        return
+    }
+    email.Attachments = attachments
+
+    if err := providersFrom(ctx).Notifier.Email(ctx, address, email); err != nil {
+        log.Printf("Failed to email owner of booking %s: %v", booking.ID, err)
+    }
+}
+
+// distanceKm returns the great-circle distance between two route points
+func distanceKm(from, to models.RoutePoint) float64 {
+    lat1, lat2 := from.Latitude*math.Pi/180, to.Latitude*math.Pi/180
+    dLat := lat2 - lat1
+    dLng := (to.Longitude - from.Longitude) * math.Pi / 180
+    h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+    return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
@@ -0,0 +1,57 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "math"
+    "strings"
+
+    "src/backend/booking-service/internal/exchange"
+    "src/backend/booking-service/internal/models"
+)
+
+// ExchangeRateService returns the rate converting booking amounts into currency, an ISO 4217
+// code such as "EUR"
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func ExchangeRateService(ctx context.Context, currency string) (models.ExchangeRate, error) {
+    currency = strings.ToUpper(strings.TrimSpace(currency))
+    if len(currency) != 3 || strings.Trim(currency, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+        return models.ExchangeRate{}, fmt.Errorf("invalid currency: %q is not an ISO 4217 code", currency)
+    }
+
+    provider := exchange.Default
+    if provider == nil && !strings.EqualFold(currency, models.BookingCurrency) {
+        return models.ExchangeRate{}, fmt.Errorf("currency conversion unavailable: no exchange rates service configured")
+    }
+
+    rate, err := exchange.Rate(ctx, provider, models.BookingCurrency, currency)
+    if errors.Is(err, exchange.ErrUnsupportedCurrency) {
+        return models.ExchangeRate{}, fmt.Errorf("invalid currency: %w", err)
+    }
+    if err != nil {
+        return models.ExchangeRate{}, fmt.Errorf("currency conversion unavailable: %w", err)
+    }
+    return rate, nil
+}
+
+// QuoteBookingService prices a booking in currency. The booking itself is not changed: it
+// is still stored and charged in models.BookingCurrency.
+func QuoteBookingService(ctx context.Context, booking *models.Booking, currency string) (*models.BookingQuote, error) {
+    rate, err := ExchangeRateService(ctx, currency)
+    if err != nil {
+        return nil, err
+    }
+
+    quote := &models.BookingQuote{
+        ExchangeRate: rate,
+        Amount:       rate.Convert(booking.Amount),
+    }
+    if booking.Tax != nil {
+        quote.Tax = rate.Convert(booking.Tax.Total)
+    }
+    // The total is the sum of the converted parts, so the quote adds up when displayed
+    quote.Total = math.Round((quote.Amount+quote.Tax)*100) / 100
+    return quote, nil
+}
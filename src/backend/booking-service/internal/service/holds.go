@@ -0,0 +1,122 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// HoldQuoteService quotes a walk as QuoteRateService does and holds the walker's slot at that
+// price for the owner for config.Config.QuoteHoldTTL, so the price and place shown at checkout
+// cannot be taken before they pay. The owner books the walk held by sending the hold's ID with
+// the booking.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func HoldQuoteService(ctx context.Context, ownerID, walkerID, region string, scheduledAt time.Time, durationMinutes int, largeDog bool, extraDogs int) (*models.SlotHold, error) {
+    if ownerID == "" {
+        return nil, fmt.Errorf("invalid quote: only an owner can hold a slot")
+    }
+    now := clock.FromContext(ctx).Now().UTC()
+    if !scheduledAt.After(now) {
+        return nil, fmt.Errorf("invalid quote: only future walks can be held")
+    }
+
+    quote, err := QuoteRateService(ctx, walkerID, region, scheduledAt, durationMinutes, largeDog, extraDogs)
+    if err != nil {
+        return nil, err
+    }
+    if err := requireEligibleWalker(ctx, walkerID); err != nil {
+        return nil, err
+    }
+
+    id, err := newID()
+    if err != nil {
+        return nil, err
+    }
+    hold := &models.SlotHold{
+        ID:              id,
+        OwnerID:         ownerID,
+        WalkerID:        walkerID,
+        Region:          NormalizeRegion(region),
+        ScheduledAt:     scheduledAt.UTC(),
+        DurationMinutes: quote.DurationMinutes,
+        Quote:           *quote,
+        ExpiresAt:       now.Add(config.Config.QuoteHoldTTL),
+        CreatedAt:       now,
+    }
+
+    // As when booking, a walker without a published window takes one dog at a time
+    slot, err := repository.GetAvailabilityCovering(ctx, walkerID, hold.ScheduledAt, hold.EndsAt())
+    if err != nil {
+        return nil, fmt.Errorf("failed to check walker availability: %w", err)
+    }
+    capacity := 1
+    if slot != nil {
+        capacity = slot.Capacity
+    }
+
+    err = repository.CreateSlotHold(ctx, hold, capacity)
+    if errors.Is(err, repository.ErrSlotFull) {
+        return nil, fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to hold slot: %w", err)
+    }
+    return hold, nil
+}
+
+// ReleaseSlotHoldService releases an owner's hold on a slot before it expires, such as when
+// they leave checkout
+func ReleaseSlotHoldService(ctx context.Context, holdID, ownerID string) error {
+    err := repository.DeleteSlotHold(ctx, holdID, ownerID)
+    if errors.Is(err, repository.ErrHoldNotFound) {
+        return fmt.Errorf("slot hold not found with id: %s", holdID)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to release slot hold: %w", err)
+    }
+    return nil
+}
+
+// applySlotHold prices a booking at the quote of the slot hold it was made from, once the hold
+// is checked to be the owner's, unexpired at now and for the walk booked. The booking takes
+// the hold's place in the walker's schedule when it is stored.
+func applySlotHold(ctx context.Context, booking *models.Booking, now time.Time) error {
+    hold, err := repository.GetSlotHold(ctx, booking.HoldID)
+    if errors.Is(err, repository.ErrHoldNotFound) || (err == nil && hold.OwnerID != booking.OwnerID) {
+        return fmt.Errorf("booking conflict: slot hold %s not found; get a new quote", booking.HoldID)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to retrieve slot hold: %w", err)
+    }
+    if !hold.ExpiresAt.After(now) {
+        return fmt.Errorf("booking conflict: slot hold %s expired at %s; get a new quote", hold.ID, hold.ExpiresAt.Format(time.RFC3339))
+    }
+    if !hold.Covers(booking) {
+        return fmt.Errorf("invalid booking data: the booking is not the walk slot hold %s was made for", hold.ID)
+    }
+
+    quote := hold.Quote
+    booking.Amount = quote.Amount
+    booking.Rate = &quote
+    return nil
+}
+
+// purgeSlotHolds removes the slot holds that expired by now
+func purgeSlotHolds(ctx context.Context, now time.Time) {
+    deleted, err := repository.DeleteExpiredSlotHolds(ctx, now)
+    if err != nil {
+        log.Printf("Failed to purge expired slot holds: %v", err)
+        return
+    }
+    if deleted > 0 {
+        log.Printf("Released %d expired slot holds", deleted)
+    }
+}
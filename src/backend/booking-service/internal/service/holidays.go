@@ -0,0 +1,124 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// ListHolidaysService returns the dates of a region's own holiday calendar, or of the calendar
+// every region shares when region is models.DefaultRuleSetRegion
+func ListHolidaysService(ctx context.Context, region string) ([]models.Holiday, error) {
+    holidays, err := repository.ListHolidays(ctx, NormalizeRegion(region))
+    if err != nil {
+        return nil, fmt.Errorf("failed to list holidays: %w", err)
+    }
+    return holidays, nil
+}
+
+// SaveHolidayService adds a date to a region's holiday calendar, or replaces its entry.
+// Bookings already made on the date keep their price.
+func SaveHolidayService(ctx context.Context, region, date string, holiday *models.Holiday) error {
+    holiday.Region = NormalizeRegion(region)
+    holiday.Date = date
+    holiday.Name = strings.TrimSpace(holiday.Name)
+    holiday.TimeZone = strings.TrimSpace(holiday.TimeZone)
+    if err := holiday.Validate(); err != nil {
+        return fmt.Errorf("invalid holiday: %w", err)
+    }
+    holiday.UpdatedAt = clock.FromContext(ctx).Now().UTC()
+
+    if err := repository.SaveHoliday(ctx, holiday); err != nil {
+        return fmt.Errorf("failed to save holiday: %w", err)
+    }
+    return nil
+}
+
+// DeleteHolidayService removes a date from a region's holiday calendar
+func DeleteHolidayService(ctx context.Context, region, date string) error {
+    region = NormalizeRegion(region)
+    if _, err := time.Parse(models.HolidayDateLayout, date); err != nil {
+        return fmt.Errorf("holiday not found: %s on %s", region, date)
+    }
+    err := repository.DeleteHoliday(ctx, region, date)
+    if errors.Is(err, repository.ErrHolidayNotFound) {
+        return fmt.Errorf("holiday not found: %s on %s", region, date)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to delete holiday: %w", err)
+    }
+    return nil
+}
+
+// holidayCalendar returns the dates that apply in region: its own, and those of the shared
+// calendar it has no entry of its own for
+func holidayCalendar(ctx context.Context, region string) ([]models.Holiday, error) {
+    shared, err := repository.ListHolidays(ctx, models.DefaultRuleSetRegion)
+    if err != nil {
+        return nil, fmt.Errorf("failed to load holidays: %w", err)
+    }
+    region = NormalizeRegion(region)
+    if region == "" || region == models.DefaultRuleSetRegion {
+        return shared, nil
+    }
+
+    own, err := repository.ListHolidays(ctx, region)
+    if err != nil {
+        return nil, fmt.Errorf("failed to load holidays: %w", err)
+    }
+    dates := make(map[string]bool, len(own))
+    for _, h := range own {
+        dates[h.Date] = true
+    }
+    for _, h := range shared {
+        if !dates[h.Date] {
+            own = append(own, h)
+        }
+    }
+    return own, nil
+}
+
+// holidayDuring returns the date of calendar [start, end) falls on, preferring a blackout
+// when it spans two; nil when it falls on none
+func holidayDuring(calendar []models.Holiday, start, end time.Time) *models.Holiday {
+    var found *models.Holiday
+    for i := range calendar {
+        h := &calendar[i]
+        if !h.Overlaps(start, end) {
+            continue
+        }
+        if h.Kind == models.HolidayBlackout {
+            return h
+        }
+        if found == nil {
+            found = h
+        }
+    }
+    return found
+}
+
+// walkHoliday returns the date of the holiday calendar of region a walk falls on, if any
+func walkHoliday(ctx context.Context, region string, scheduledAt time.Time, durationMinutes int) (*models.Holiday, error) {
+    calendar, err := holidayCalendar(ctx, region)
+    if err != nil {
+        return nil, err
+    }
+    walk := &models.Booking{ScheduledAt: scheduledAt, DurationMinutes: durationMinutes}
+    return holidayDuring(calendar, walk.ScheduledAt, walk.EndsAt()), nil
+}
+
+// checkBlackout refuses walks on a blackout date of their region
+func checkBlackout(holiday *models.Holiday) error {
+    if holiday != nil && holiday.Kind == models.HolidayBlackout {
+        return i18n.Errorf("booking.invalid_data", i18n.Errorf("booking.blackout", holiday.Date, holiday.Name))
+    }
+    return nil
+}
@@ -0,0 +1,91 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "time"
+
+    "src/backend/shared/clock"
+)
+
+// backgroundJobInterval is how often the time-based booking jobs run; jobs needed less often
+// check on each tick whether they are due
+const backgroundJobInterval = time.Minute
+
+// RunBackgroundJobs runs the periodic booking jobs until ctx is cancelled, as of the time of
+// the clock ctx carries
+func RunBackgroundJobs(ctx context.Context) {
+    ticker := time.NewTicker(backgroundJobInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+            RunDueJobs(ctx, clock.FromContext(ctx).Now())
+        }
+    }
+}
+
+// BackgroundJobs runs the periodic booking jobs in the background between Start and Stop,
+// whose signatures fit the server's start and stop hooks
+type BackgroundJobs struct {
+    mu      sync.Mutex
+    cancel  context.CancelFunc
+    stopped chan struct{}
+}
+
+// Start runs the periodic booking jobs in the background until Stop is called or ctx is
+// cancelled, as of the time of the clock ctx carries
+func (j *BackgroundJobs) Start(ctx context.Context) error {
+    j.mu.Lock()
+    defer j.mu.Unlock()
+    if j.cancel != nil {
+        return errors.New("background jobs are already started")
+    }
+
+    ctx, j.cancel = context.WithCancel(ctx)
+    j.stopped = make(chan struct{})
+    go func(stopped chan struct{}) {
+        defer close(stopped)
+        RunBackgroundJobs(ctx)
+    }(j.stopped)
+    return nil
+}
+
+// Stop ends the background jobs, waiting for a round in progress to return until ctx is done.
+// Stopping jobs that never started, or stopping them twice, is harmless.
+func (j *BackgroundJobs) Stop(ctx context.Context) error {
+    j.mu.Lock()
+    cancel, stopped := j.cancel, j.stopped
+    j.mu.Unlock()
+    if cancel == nil {
+        return nil
+    }
+
+    cancel()
+    select {
+    case <-stopped:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
+
+// RunDueJobs runs one round of the periodic booking jobs as of now, such as releasing the
+// assignments walkers left unanswered past their response SLA
+func RunDueJobs(ctx context.Context, now time.Time) {
+    expireBookingChanges(ctx, now)
+    releaseLapsedAssignments(ctx, now)
+    purgeSlotHolds(ctx, now)
+    reconcilePayments(ctx, now)
+    sendCapacityReport(ctx, now)
+    sendScheduledReports(ctx, now)
+    purgeDeliveryReceipts(ctx, now)
+    purgeInboxNotifications(ctx, now)
+    purgeBookingAttachments(ctx, now)
+    resumeStalledSagas(ctx, now)
+}
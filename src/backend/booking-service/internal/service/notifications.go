@@ -0,0 +1,368 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+const (
+    // maxDeviceTokenLength bounds registered device tokens; FCM tokens are the longest, at a
+    // few hundred characters
+    maxDeviceTokenLength = 4096
+
+    // maxDeliveryReceiptList caps the delivery receipts returned at once
+    maxDeliveryReceiptList = 200
+
+    // deliveryReceiptRetention is how long delivery receipts are kept for debugging
+    deliveryReceiptRetention = 30 * 24 * time.Hour
+
+    // deliveryReceiptPurgeInterval is how often expired delivery receipts are removed
+    deliveryReceiptPurgeInterval = time.Hour
+
+    // defaultInboxPage and maxInboxPage are the notifications returned per page of a user's
+    // notification center when no limit, or too high a limit, is asked for
+    defaultInboxPage = 20
+    maxInboxPage     = 100
+
+    // maxMarkRead caps the notifications marked read by ID at once
+    maxMarkRead = 100
+
+    // inboxRetention is how long notifications stay in users' notification centers
+    inboxRetention = 90 * 24 * time.Hour
+)
+
+var (
+    // lastDeliveryReceiptPurge is when this instance last removed expired delivery receipts
+    lastDeliveryReceiptPurge time.Time
+
+    // lastInboxPurge is when this instance last removed expired inbox notifications
+    lastInboxPurge time.Time
+)
+
+// RegisterDeviceService registers a device to receive userID's push notifications
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func RegisterDeviceService(ctx context.Context, userID, token, platform string) (*models.Device, error) {
+    if userID == "" {
+        return nil, fmt.Errorf("invalid device: user ID is required")
+    }
+    if token == "" || len(token) > maxDeviceTokenLength {
+        return nil, fmt.Errorf("invalid device: token must be between 1 and %d characters", maxDeviceTokenLength)
+    }
+    if platform != notifier.PlatformAndroid && platform != notifier.PlatformIOS {
+        return nil, fmt.Errorf("invalid device: platform must be %q or %q", notifier.PlatformAndroid, notifier.PlatformIOS)
+    }
+
+    now := clock.FromContext(ctx).Now().UTC()
+    device := &models.Device{
+        Token:     token,
+        UserID:    userID,
+        Platform:  platform,
+        CreatedAt: now,
+        UpdatedAt: now,
+    }
+    if err := repository.SaveDevice(ctx, device); err != nil {
+        return nil, fmt.Errorf("failed to register device: %w", err)
+    }
+    return device, nil
+}
+
+// ListDevicesService returns the devices registered to userID
+func ListDevicesService(ctx context.Context, userID string) ([]models.Device, error) {
+    devices, err := repository.ListDevices(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list devices: %w", err)
+    }
+    return devices, nil
+}
+
+// UnregisterDeviceService stops push notifications to one of userID's devices, for example
+// when they sign out of the app
+func UnregisterDeviceService(ctx context.Context, userID, token string) error {
+    err := repository.DeleteDevice(ctx, userID, token)
+    if errors.Is(err, repository.ErrDeviceNotFound) {
+        return fmt.Errorf("device not found: %w", err)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to unregister device: %w", err)
+    }
+    return nil
+}
+
+// GetNotificationPreferencesService returns userID's notification preferences
+func GetNotificationPreferencesService(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+    prefs, err := repository.GetNotificationPreferences(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+    }
+    return prefs, nil
+}
+
+// SaveNotificationPreferencesService replaces a user's notification preferences
+func SaveNotificationPreferencesService(ctx context.Context, prefs *models.NotificationPreferences) (*models.NotificationPreferences, error) {
+    if prefs.UserID == "" {
+        return nil, fmt.Errorf("invalid notification preferences: user ID is required")
+    }
+    muted := make([]string, 0, len(prefs.MutedCategories))
+    seen := make(map[string]bool, len(prefs.MutedCategories))
+    for _, category := range prefs.MutedCategories {
+        if !isNotificationCategory(category) {
+            return nil, fmt.Errorf("invalid notification preferences: unknown category %q", category)
+        }
+        if !seen[category] {
+            seen[category] = true
+            muted = append(muted, category)
+        }
+    }
+    prefs.MutedCategories = muted
+    if prefs.Locale != "" {
+        locale, ok := i18n.Normalize(prefs.Locale)
+        if !ok {
+            return nil, fmt.Errorf("invalid notification preferences: unsupported locale %q", prefs.Locale)
+        }
+        prefs.Locale = locale
+    }
+    prefs.UpdatedAt = clock.FromContext(ctx).Now().UTC()
+
+    if err := repository.SaveNotificationPreferences(ctx, prefs); err != nil {
+        return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+    }
+    return prefs, nil
+}
+
+// userLocale returns the language userID's notifications are written in. A failed lookup is
+// logged and falls back to the default language, as the notification is still worth sending.
+func userLocale(ctx context.Context, userID string) string {
+    prefs, err := repository.GetNotificationPreferences(ctx, userID)
+    if err != nil {
+        log.Printf("Failed to get locale of user %s: %v", userID, err)
+        return i18n.DefaultLocale
+    }
+    if prefs.Locale == "" {
+        return i18n.DefaultLocale
+    }
+    return prefs.Locale
+}
+
+// ListDeliveryReceiptsService returns up to limit of userID's most recent push deliveries,
+// for support investigating notifications that did not arrive
+func ListDeliveryReceiptsService(ctx context.Context, userID string, limit int) ([]models.DeliveryReceipt, error) {
+    if userID == "" {
+        return nil, fmt.Errorf("user ID is required")
+    }
+    if limit <= 0 || limit > maxDeliveryReceiptList {
+        limit = maxDeliveryReceiptList
+    }
+
+    receipts, err := repository.ListDeliveryReceipts(ctx, userID, limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list delivery receipts: %w", err)
+    }
+    return receipts, nil
+}
+
+// isNotificationCategory reports whether category is one users can mute
+func isNotificationCategory(category string) bool {
+    for _, c := range notifier.Categories {
+        if c == category {
+            return true
+        }
+    }
+    return false
+}
+
+// NotificationStore gives the push notifier the devices, preferences and delivery receipts
+// kept in the repository, and keeps the notifications shown in users' notification centers
+type NotificationStore struct{}
+
+// Devices implements notifier.PushStore
+func (NotificationStore) Devices(ctx context.Context, userID string) ([]notifier.Device, error) {
+    devices, err := repository.ListDevices(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+    targets := make([]notifier.Device, len(devices))
+    for i, d := range devices {
+        targets[i] = notifier.Device{Token: d.Token, Platform: d.Platform}
+    }
+    return targets, nil
+}
+
+// Allows implements notifier.PushStore
+func (NotificationStore) Allows(ctx context.Context, userID, category string) (bool, error) {
+    prefs, err := repository.GetNotificationPreferences(ctx, userID)
+    if err != nil {
+        return false, err
+    }
+    return prefs.Allows(category), nil
+}
+
+// Unregister implements notifier.PushStore
+func (NotificationStore) Unregister(ctx context.Context, token string) error {
+    return repository.DeleteDeviceToken(ctx, token)
+}
+
+// Record implements notifier.PushStore
+func (NotificationStore) Record(ctx context.Context, delivery notifier.Delivery) error {
+    id, err := newID()
+    if err != nil {
+        return fmt.Errorf("failed to generate delivery receipt ID: %w", err)
+    }
+    return repository.CreateDeliveryReceipt(ctx, &models.DeliveryReceipt{
+        ID:          id,
+        UserID:      delivery.UserID,
+        Platform:    delivery.Platform,
+        DeviceToken: delivery.Token,
+        Category:    delivery.Category,
+        Subject:     delivery.Subject,
+        Status:      delivery.Status,
+        MessageID:   delivery.MessageID,
+        Error:       delivery.Error,
+        CreatedAt:   clock.FromContext(ctx).Now().UTC(),
+    })
+}
+
+// Save implements notifier.InboxStore
+func (NotificationStore) Save(ctx context.Context, userID string, notification notifier.Notification) error {
+    id, err := newID()
+    if err != nil {
+        return fmt.Errorf("failed to generate notification ID: %w", err)
+    }
+    return repository.CreateInboxNotification(ctx, &models.InboxNotification{
+        ID:        id,
+        UserID:    userID,
+        Category:  notification.Category,
+        Subject:   notification.Subject,
+        Body:      notification.Body,
+        Data:      notification.Data,
+        CreatedAt: clock.FromContext(ctx).Now().UTC(),
+    })
+}
+
+// ListInboxService returns a page of userID's notification center, newest first, starting after
+// the notification with ID before when set; only unread notifications when unreadOnly is set
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func ListInboxService(ctx context.Context, userID, before string, unreadOnly bool, limit int) (*models.InboxPage, error) {
+    if limit <= 0 {
+        limit = defaultInboxPage
+    }
+    if limit > maxInboxPage {
+        limit = maxInboxPage
+    }
+    if before != "" {
+        if _, err := repository.GetInboxNotification(ctx, userID, before); err != nil {
+            if errors.Is(err, repository.ErrInboxNotificationNotFound) {
+                return nil, fmt.Errorf("invalid inbox request: unknown notification %s", before)
+            }
+            return nil, fmt.Errorf("failed to list notifications: %w", err)
+        }
+    }
+
+    // One extra notification tells whether there is another page
+    notifications, err := repository.ListInboxNotifications(ctx, userID, before, unreadOnly, limit+1)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list notifications: %w", err)
+    }
+    unread, err := repository.CountUnreadInboxNotifications(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list notifications: %w", err)
+    }
+
+    page := &models.InboxPage{Notifications: notifications, UnreadCount: unread}
+    if len(notifications) > limit {
+        page.Notifications = notifications[:limit]
+        page.NextBefore = notifications[limit-1].ID
+    }
+    if page.Notifications == nil {
+        page.Notifications = []models.InboxNotification{}
+    }
+    return page, nil
+}
+
+// MarkInboxReadService marks the given notifications of userID read, or all of them when all is
+// set, returning how many had been unread
+func MarkInboxReadService(ctx context.Context, userID string, ids []string, all bool) (int64, error) {
+    switch {
+    case all && len(ids) > 0:
+        return 0, fmt.Errorf("invalid mark read request: give either ids or all, not both")
+    case all:
+        ids = nil
+    case len(ids) == 0:
+        return 0, fmt.Errorf("invalid mark read request: ids or all is required")
+    case len(ids) > maxMarkRead:
+        return 0, fmt.Errorf("invalid mark read request: at most %d ids may be marked at once", maxMarkRead)
+    }
+
+    marked, err := repository.MarkInboxNotificationsRead(ctx, userID, ids, clock.FromContext(ctx).Now().UTC())
+    if err != nil {
+        return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+    }
+    return marked, nil
+}
+
+// MarkInboxNotificationReadService marks one of userID's notifications read; marking a read
+// notification again keeps when it was first read
+func MarkInboxNotificationReadService(ctx context.Context, userID, id string) (*models.InboxNotification, error) {
+    notification, err := repository.GetInboxNotification(ctx, userID, id)
+    if errors.Is(err, repository.ErrInboxNotificationNotFound) {
+        return nil, fmt.Errorf("notification not found: %s", id)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to mark notification read: %w", err)
+    }
+    if notification.ReadAt != nil {
+        return notification, nil
+    }
+
+    now := clock.FromContext(ctx).Now().UTC()
+    if _, err := repository.MarkInboxNotificationsRead(ctx, userID, []string{id}, now); err != nil {
+        return nil, fmt.Errorf("failed to mark notification read: %w", err)
+    }
+    notification.ReadAt = &now
+    return notification, nil
+}
+
+// purgeInboxNotifications removes notifications older than inboxRetention, at most once per
+// deliveryReceiptPurgeInterval
+func purgeInboxNotifications(ctx context.Context, now time.Time) {
+    if now.Sub(lastInboxPurge) < deliveryReceiptPurgeInterval {
+        return
+    }
+    lastInboxPurge = now
+
+    deleted, err := repository.DeleteInboxNotificationsBefore(ctx, now.Add(-inboxRetention))
+    if err != nil {
+        log.Printf("Failed to purge inbox notifications: %v", err)
+        return
+    }
+    if deleted > 0 {
+        log.Printf("Purged %d expired inbox notifications", deleted)
+    }
+}
+
+// purgeDeliveryReceipts removes delivery receipts older than deliveryReceiptRetention, at most
+// once per deliveryReceiptPurgeInterval
+func purgeDeliveryReceipts(ctx context.Context, now time.Time) {
+    if now.Sub(lastDeliveryReceiptPurge) < deliveryReceiptPurgeInterval {
+        return
+    }
+    lastDeliveryReceiptPurge = now
+
+    deleted, err := repository.DeleteDeliveryReceiptsBefore(ctx, now.Add(-deliveryReceiptRetention))
+    if err != nil {
+        log.Printf("Failed to purge delivery receipts: %v", err)
+        return
+    }
+    if deleted > 0 {
+        log.Printf("Purged %d expired delivery receipts", deleted)
+    }
+}
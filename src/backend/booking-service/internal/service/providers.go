@@ -0,0 +1,77 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+
+    "src/backend/booking-service/internal/geocoding"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/receipts"
+    "src/backend/booking-service/internal/tracking"
+)
+
+// Providers are the services outside the booking-service that its business logic calls.
+// A nil field means the process-wide provider the server configures at startup, so only the
+// providers a caller replaces need to be set.
+type Providers struct {
+    Ledger   payments.Ledger
+    Charges  payments.Charger
+    Refunds  payments.Refunder
+    Holds    payments.Authorizer
+    Receipts receipts.Store
+    Locator  tracking.Locator
+    Walks    tracking.Walks
+    Notifier notifier.Notifier
+    Texts    notifier.Texter
+    Alerts   notifier.Alerter
+    Geocoder geocoding.Geocoder
+}
+
+type providersKey struct{}
+
+// WithProviders returns a copy of ctx whose service calls use the given providers in place
+// of the process-wide ones
+func WithProviders(ctx context.Context, p Providers) context.Context {
+    return context.WithValue(ctx, providersKey{}, p)
+}
+
+// providersFrom returns the providers ctx carries, with the process-wide provider for each
+// one it leaves unset
+func providersFrom(ctx context.Context) Providers {
+    p, _ := ctx.Value(providersKey{}).(Providers)
+    if p.Ledger == nil {
+        p.Ledger = payments.Default
+    }
+    if p.Charges == nil {
+        p.Charges = payments.Charges
+    }
+    if p.Refunds == nil {
+        p.Refunds = payments.Refunds
+    }
+    if p.Holds == nil {
+        p.Holds = payments.Holds
+    }
+    if p.Receipts == nil {
+        p.Receipts = receipts.DefaultStore
+    }
+    if p.Locator == nil {
+        p.Locator = tracking.Default
+    }
+    if p.Walks == nil {
+        p.Walks = tracking.Evidence
+    }
+    if p.Notifier == nil {
+        p.Notifier = notifier.Default
+    }
+    if p.Texts == nil {
+        p.Texts = notifier.Texts
+    }
+    if p.Alerts == nil {
+        p.Alerts = notifier.Alerts
+    }
+    if p.Geocoder == nil {
+        p.Geocoder = geocoding.Default
+    }
+    return p
+}
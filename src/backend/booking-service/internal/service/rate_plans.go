@@ -0,0 +1,115 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// SaveRatePlanService sets a walker's own rates. Only bookings made afterwards are priced
+// from the new rates.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func SaveRatePlanService(ctx context.Context, plan *models.RatePlan) error {
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    plan.TimeZone = strings.TrimSpace(plan.TimeZone)
+    if err := plan.Validate(); err != nil {
+        return fmt.Errorf("invalid rate plan: %w", err)
+    }
+    plan.UpdatedAt = clock.FromContext(ctx).Now().UTC()
+
+    if err := repository.SaveRatePlan(ctx, plan); err != nil {
+        return fmt.Errorf("failed to save rate plan: %w", err)
+    }
+    return nil
+}
+
+// GetRatePlanService returns a walker's rate plan
+func GetRatePlanService(ctx context.Context, walkerID string) (*models.RatePlan, error) {
+    ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    if walkerID == "" {
+        return nil, fmt.Errorf("walker ID is required")
+    }
+
+    plan, err := repository.GetRatePlan(ctx, walkerID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve rate plan: %w", err)
+    }
+    if plan == nil {
+        return nil, fmt.Errorf("rate plan not found for walker: %s", walkerID)
+    }
+    return plan, nil
+}
+
+// QuoteRateService prices a walk from the walker's rate plan as a booking made now would be,
+// before any group walk discount and tax, with the surcharge of a holiday in region and the
+// surge multiplier of its hour there. Walks the booking rules or blackout dates of region
+// would refuse are not quoted.
+func QuoteRateService(ctx context.Context, walkerID, region string, scheduledAt time.Time, durationMinutes int, largeDog bool, extraDogs int) (*models.RateSnapshot, error) {
+    if durationMinutes < 0 || extraDogs < 0 {
+        return nil, fmt.Errorf("invalid quote: duration and extra dogs must be non-negative")
+    }
+    walk := &models.Booking{Region: region, ScheduledAt: scheduledAt, DurationMinutes: durationMinutes}
+    if err := checkBookingRules(ctx, walk, clock.FromContext(ctx).Now()); err != nil {
+        return nil, err
+    }
+    holiday, err := walkHoliday(ctx, region, scheduledAt, durationMinutes)
+    if err != nil {
+        return nil, err
+    }
+    if err := checkBlackout(holiday); err != nil {
+        return nil, err
+    }
+
+    plan, err := GetRatePlanService(ctx, walkerID)
+    if err != nil {
+        return nil, err
+    }
+    multiplier, err := surgeMultiplier(ctx, NormalizeRegion(region), scheduledAt, clock.FromContext(ctx).Now())
+    if err != nil {
+        return nil, err
+    }
+    snapshot := plan.Price(scheduledAt, durationMinutes, largeDog, extraDogs)
+    snapshot.ApplyHoliday(holiday)
+    snapshot.ApplySurge(multiplier)
+    return &snapshot, nil
+}
+
+// priceBooking sets the amount of a booking with a walker from the walker's rate plan, plus
+// the surcharge of the holiday it falls on and any surge, and records the rate used. Bookings
+// whose walker has no plan keep the amount they were made with; any rate sent by the client is
+// discarded either way.
+func priceBooking(ctx context.Context, booking *models.Booking, holiday *models.Holiday) error {
+    booking.Rate = nil
+    if !booking.IsAssigned() {
+        return nil
+    }
+
+    plan, err := repository.GetRatePlan(ctx, booking.WalkerID)
+    if err != nil {
+        return fmt.Errorf("failed to resolve walker rates: %w", err)
+    }
+    if plan == nil {
+        return nil
+    }
+
+    multiplier, err := surgeMultiplier(ctx, booking.Region, booking.ScheduledAt, clock.FromContext(ctx).Now())
+    if err != nil {
+        return err
+    }
+    snapshot := plan.Price(booking.ScheduledAt, booking.DurationMinutes, booking.LargeDog, booking.ExtraDogs)
+    snapshot.ApplyHoliday(holiday)
+    snapshot.ApplySurge(multiplier)
+    booking.Amount = snapshot.Amount
+    booking.Rate = &snapshot
+    return nil
+}
@@ -0,0 +1,55 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// RebookService books the walk of an earlier booking again at a new time: the same walker,
+// dog, length and region. The new booking goes through the same availability, conflict and
+// pricing checks as any other, so it is priced from the walker's current rates, or at the
+// earlier booking's amount when the walker has none.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func RebookService(ctx context.Context, bookingID, ownerID string, scheduledAt time.Time) (*models.Booking, error) {
+    if ownerID == "" {
+        return nil, fmt.Errorf("invalid booking data: owner ID is required")
+    }
+
+    original, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if original.OwnerID != ownerID {
+        return nil, fmt.Errorf("rebook not allowed: only the booking's owner can rebook it")
+    }
+
+    id, err := newID()
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate booking ID: %w", err)
+    }
+    booking := &models.Booking{
+        ID:              id,
+        OwnerID:         original.OwnerID,
+        WalkerID:        original.WalkerID,
+        DogID:           original.DogID,
+        ScheduledAt:     scheduledAt,
+        Status:          models.BookingStatusPending,
+        Amount:          original.Amount,
+        DurationMinutes: original.DurationMinutes,
+        Region:          original.Region,
+    }
+    // Walks priced from a rate plan are priced for the same dogs again
+    if original.Rate != nil {
+        booking.LargeDog = original.Rate.LargeDog
+        booking.ExtraDogs = original.Rate.ExtraDogs
+    }
+
+    if err := CreateBookingService(ctx, booking); err != nil {
+        return nil, err
+    }
+    return booking, nil
+}
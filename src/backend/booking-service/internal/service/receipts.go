@@ -0,0 +1,189 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "html"
+    "log"
+    "net/url"
+    "strings"
+
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/receipts"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// EventReceiptIssued is published once per completed booking, when its receipt is issued
+const EventReceiptIssued = "booking.receipt_issued"
+
+// receiptKey is where a booking's receipt is stored; ext is "json" or "pdf"
+func receiptKey(bookingID, ext string) string {
+    return "receipts/" + url.PathEscape(bookingID) + "." + ext
+}
+
+// IssueReceiptService issues the receipt of a booking as it completes: the receipt is rendered,
+// stored with its document and emailed to the owner. Receipts are never regenerated, so
+// issuing one again returns it unchanged and later changes to the booking do not alter it.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func IssueReceiptService(ctx context.Context, bookingID string) (*models.Receipt, error) {
+    store := providersFrom(ctx).Receipts
+    if store == nil {
+        return nil, fmt.Errorf("receipts unavailable: no receipt store configured")
+    }
+
+    receipt, err := loadReceipt(ctx, store, bookingID)
+    if err == nil {
+        return receipt, nil
+    }
+    if !errors.Is(err, receipts.ErrNotFound) {
+        return nil, fmt.Errorf("failed to load receipt: %w", err)
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.Status != models.BookingStatusCompleted {
+        return nil, fmt.Errorf("receipt not available: booking is %s", booking.Status)
+    }
+
+    receipt = models.NewReceipt(booking, clock.FromContext(ctx).Now().UTC())
+    document, contentType, err := receipts.DefaultRenderer.Render(ctx, receipt)
+    if err != nil {
+        return nil, fmt.Errorf("failed to render receipt: %w", err)
+    }
+
+    // The document is stored first, so a stored receipt always has its document
+    if err := store.Put(ctx, receiptKey(bookingID, "pdf"), contentType, document); err != nil {
+        return nil, fmt.Errorf("failed to store receipt document: %w", err)
+    }
+    data, err := json.Marshal(receipt)
+    if err != nil {
+        return nil, fmt.Errorf("failed to encode receipt: %w", err)
+    }
+    if err := store.Put(ctx, receiptKey(bookingID, "json"), "application/json", data); err != nil {
+        return nil, fmt.Errorf("failed to store receipt: %w", err)
+    }
+
+    events.Publish(ctx, EventReceiptIssued, receipt)
+    emailReceipt(ctx, receipt, document, contentType)
+    return receipt, nil
+}
+
+// GetReceiptService returns the receipt issued when a booking completed, to the booking's
+// owner, or to anyone when ownerID is empty. Reading a receipt never issues one.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func GetReceiptService(ctx context.Context, bookingID, ownerID string) (*models.Receipt, error) {
+    store := providersFrom(ctx).Receipts
+    if store == nil {
+        return nil, fmt.Errorf("receipts unavailable: no receipt store configured")
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if ownerID != "" && booking.OwnerID != ownerID {
+        return nil, fmt.Errorf("receipt forbidden: only the booking's owner can read its receipt")
+    }
+
+    receipt, err := loadReceipt(ctx, store, bookingID)
+    switch {
+    case err == nil:
+        return receipt, nil
+    case !errors.Is(err, receipts.ErrNotFound):
+        return nil, fmt.Errorf("failed to load receipt: %w", err)
+    case booking.Status != models.BookingStatusCompleted:
+        return nil, fmt.Errorf("receipt not available: booking is %s", booking.Status)
+    default:
+        return nil, fmt.Errorf("receipt not available: receipt has not been issued")
+    }
+}
+
+// GetReceiptDocumentService returns the rendered document of a completed booking's receipt,
+// to the booking's owner, or to anyone when ownerID is empty
+func GetReceiptDocumentService(ctx context.Context, bookingID, ownerID string) ([]byte, error) {
+    if _, err := GetReceiptService(ctx, bookingID, ownerID); err != nil {
+        return nil, err
+    }
+
+    document, err := providersFrom(ctx).Receipts.Get(ctx, receiptKey(bookingID, "pdf"))
+    if err != nil {
+        return nil, fmt.Errorf("failed to load receipt document: %w", err)
+    }
+    return document, nil
+}
+
+// RecordUserEmailService remembers the address a user booked with, for emailing receipts
+func RecordUserEmailService(ctx context.Context, userID, email string) error {
+    email = strings.TrimSpace(email)
+    if userID == "" || email == "" {
+        return nil
+    }
+    if err := repository.SaveUserEmail(ctx, userID, email, clock.FromContext(ctx).Now()); err != nil {
+        return fmt.Errorf("failed to record user email: %w", err)
+    }
+    return nil
+}
+
+// loadReceipt reads a stored receipt
+func loadReceipt(ctx context.Context, store receipts.Store, bookingID string) (*models.Receipt, error) {
+    data, err := store.Get(ctx, receiptKey(bookingID, "json"))
+    if err != nil {
+        return nil, err
+    }
+    var receipt models.Receipt
+    if err := json.Unmarshal(data, &receipt); err != nil {
+        return nil, err
+    }
+    return &receipt, nil
+}
+
+// emailReceipt sends the receipt to the owner with its document attached. Owners whose
+// address is unknown get a push notification instead. Failures are logged; the receipt
+// remains available from the API.
+func emailReceipt(ctx context.Context, receipt *models.Receipt, document []byte, contentType string) {
+    address, err := repository.GetUserEmail(ctx, receipt.OwnerID)
+    if err != nil {
+        log.Printf("Failed to look up email for receipt %s: %v", receipt.Number, err)
+        return
+    }
+
+    locale := userLocale(ctx, receipt.OwnerID)
+    if address == "" {
+        err = providersFrom(ctx).Notifier.Notify(ctx, receipt.OwnerID, notifier.Notification{
+            Subject:  i18n.T(locale, "notify.receipt_ready.subject"),
+            Body:     i18n.T(locale, "notify.receipt_ready.body", receipt.Number, receipts.FormatAmount(receipt.TotalCents, receipt.Currency)),
+            Data:     map[string]string{"booking_id": receipt.BookingID, "receipt_number": receipt.Number},
+            Category: notifier.CategoryPayments,
+        })
+        if err != nil {
+            log.Printf("Failed to notify owner of receipt %s: %v", receipt.Number, err)
+        }
+        return
+    }
+
+    lines := receipts.ReceiptLines(receipt)
+    for i, line := range lines {
+        lines[i] = html.EscapeString(line)
+    }
+    err = providersFrom(ctx).Notifier.Email(ctx, address, notifier.Email{
+        Subject: i18n.T(locale, "email.receipt.subject", receipt.Number),
+        Body:    strings.Join(lines, "<br>\n"),
+        Attachments: []notifier.Attachment{{
+            Filename:    "receipt-" + receipt.Number + ".pdf",
+            ContentType: contentType,
+            Content:     document,
+        }},
+    })
+    if err != nil {
+        log.Printf("Failed to email receipt %s: %v", receipt.Number, err)
+    }
+}
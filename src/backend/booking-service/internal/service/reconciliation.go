@@ -0,0 +1,322 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+const (
+    // reconciliationPeriod is the span of scheduled bookings each run covers: one UTC day
+    reconciliationPeriod = 24 * time.Hour
+
+    // reconciliationDelay is how long after a period ends it is reconciled, leaving time for
+    // the day's captures and refunds to settle
+    reconciliationDelay = 2 * time.Hour
+
+    // reconciliationRetryAfter is how long a failed run waits before it is attempted again
+    reconciliationRetryAfter = time.Hour
+
+    // settlementLookback is how far before a period payments for its bookings are looked for;
+    // owners pay when they book, which may be weeks before the walk
+    settlementLookback = 60 * 24 * time.Hour
+)
+
+var (
+    // ErrReconciliationUnavailable is returned when no payment-service is configured
+    ErrReconciliationUnavailable = errors.New("reconciliation unavailable: no payment-service configured")
+
+    // ErrPeriodReconciled is returned when the period has been reconciled, or is being reconciled by another instance
+    ErrPeriodReconciled = errors.New("period already reconciled")
+)
+
+// lastReconciledPeriod is the most recent period this instance reconciled, so the background
+// job does not try to claim it again every minute
+var lastReconciledPeriod time.Time
+
+// ReconcileService compares the amounts of the bookings scheduled on the UTC day starting at
+// periodStart against the payments captured and refunded for them, stores the discrepancies
+// found and alerts when there are more than alertThreshold.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func ReconcileService(ctx context.Context, periodStart time.Time, alertThreshold int) (*models.ReconciliationRun, error) {
+    ledger := providersFrom(ctx).Ledger
+    if ledger == nil {
+        return nil, ErrReconciliationUnavailable
+    }
+
+    id, err := newID()
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate reconciliation run ID: %w", err)
+    }
+    now := clock.FromContext(ctx).Now()
+    periodStart = periodStart.UTC().Truncate(reconciliationPeriod)
+    run := &models.ReconciliationRun{
+        ID:          id,
+        PeriodStart: periodStart,
+        PeriodEnd:   periodStart.Add(reconciliationPeriod),
+        StartedAt:   now,
+    }
+
+    claimed, err := repository.ClaimReconciliationRun(ctx, run, now.Add(-reconciliationRetryAfter))
+    if err != nil {
+        return nil, fmt.Errorf("failed to start reconciliation: %w", err)
+    }
+    if !claimed {
+        return nil, ErrPeriodReconciled
+    }
+
+    discrepancies, checked, err := reconcilePeriod(ctx, ledger, run, now)
+    if err != nil {
+        if failErr := repository.FailReconciliationRun(ctx, run.ID, err.Error(), clock.FromContext(ctx).Now()); failErr != nil {
+            log.Printf("Failed to record failed reconciliation run %s: %v", run.ID, failErr)
+        }
+        return nil, fmt.Errorf("failed to reconcile payments: %w", err)
+    }
+
+    completedAt := clock.FromContext(ctx).Now()
+    run.CompletedAt = &completedAt
+    run.BookingsChecked = checked
+    run.DiscrepancyCount = len(discrepancies)
+    for _, d := range discrepancies {
+        if d.DifferenceCents < 0 {
+            run.DiscrepancyCents -= d.DifferenceCents
+        } else {
+            run.DiscrepancyCents += d.DifferenceCents
+        }
+    }
+    if err := repository.CompleteReconciliationRun(ctx, run, discrepancies); err != nil {
+        return nil, fmt.Errorf("failed to store reconciliation run: %w", err)
+    }
+    run.Discrepancies = discrepancies
+
+    if run.DiscrepancyCount > alertThreshold {
+        alertDiscrepancies(ctx, run, alertThreshold)
+    }
+    return run, nil
+}
+
+// ListReconciliationRunsService lists the most recent reconciliation runs
+func ListReconciliationRunsService(ctx context.Context, limit int) ([]models.ReconciliationRun, error) {
+    if limit <= 0 || limit > 100 {
+        limit = 30
+    }
+    runs, err := repository.ListReconciliationRuns(ctx, limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list reconciliation runs: %w", err)
+    }
+    return runs, nil
+}
+
+// GetReconciliationRunService retrieves a reconciliation run with its discrepancies
+func GetReconciliationRunService(ctx context.Context, id string) (*models.ReconciliationRun, error) {
+    run, err := repository.GetReconciliationRun(ctx, id)
+    if errors.Is(err, repository.ErrReconciliationRunNotFound) {
+        return nil, fmt.Errorf("reconciliation run not found with id: %s", id)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve reconciliation run: %w", err)
+    }
+    return run, nil
+}
+
+// reconcilePeriod compares the run's bookings with their payments and returns the
+// discrepancies and how many bookings were checked
+func reconcilePeriod(ctx context.Context, ledger payments.Ledger, run *models.ReconciliationRun, now time.Time) ([]models.ReconciliationDiscrepancy, int, error) {
+    bookings, err := repository.ListBookingsScheduledBetween(ctx, run.PeriodStart, run.PeriodEnd)
+    if err != nil {
+        return nil, 0, err
+    }
+    settlements, err := ledger.Settlements(ctx, run.PeriodStart.Add(-settlementLookback), now)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    // Tips are optional and paid separately, so they are not part of what a booking owes
+    byBooking := make(map[string][]payments.Settlement)
+    for _, s := range settlements {
+        if s.Kind == payments.KindTip {
+            continue
+        }
+        byBooking[s.BookingID] = append(byBooking[s.BookingID], s)
+    }
+
+    var discrepancies []models.ReconciliationDiscrepancy
+    checked := make(map[string]bool, len(bookings))
+    for _, booking := range bookings {
+        checked[booking.ID] = true
+        if d, ok := compareBookingPayments(booking, byBooking[booking.ID]); ok {
+            // Refunds granted on a dispute reduce what a completed booking should have kept
+            if d.Kind == models.DiscrepancyAmountMismatch && d.RefundedCents > 0 {
+                refunded, err := disputeRefundCents(ctx, booking.ID)
+                if err != nil {
+                    return nil, 0, err
+                }
+                d.ExpectedCents -= refunded
+                d.DifferenceCents += refunded
+                if d.DifferenceCents == 0 {
+                    continue
+                }
+            }
+            d.RunID = run.ID
+            discrepancies = append(discrepancies, d)
+        }
+    }
+
+    // Payments taken during the period are also checked against bookings scheduled outside it,
+    // so money paid for a booking that does not exist is reported exactly once
+    for bookingID, paid := range byBooking {
+        if checked[bookingID] || !paidDuring(paid, run.PeriodStart, run.PeriodEnd) {
+            continue
+        }
+        _, err := repository.GetBookingByID(ctx, bookingID)
+        if err == nil {
+            continue
+        }
+        if !strings.Contains(err.Error(), "booking not found") {
+            return nil, 0, err
+        }
+        d := models.ReconciliationDiscrepancy{
+            RunID:     run.ID,
+            BookingID: bookingID,
+            Kind:      models.DiscrepancyUnknownBooking,
+            Currency:  strings.ToLower(paid[0].Currency),
+        }
+        for _, s := range paid {
+            d.CapturedCents += s.CapturedCents
+            d.RefundedCents += s.RefundedCents
+        }
+        d.DifferenceCents = d.CapturedCents - d.RefundedCents
+        discrepancies = append(discrepancies, d)
+    }
+
+    sort.Slice(discrepancies, func(i, j int) bool { return discrepancies[i].BookingID < discrepancies[j].BookingID })
+    return discrepancies, len(bookings), nil
+}
+
+// compareBookingPayments checks a booking's payments against its amount. Completed bookings
+// must have been paid in full, bookings their owner cancelled must have been refunded all but
+// the cancellation fee, and other cancelled or failed ones fully refunded; bookings in any
+// other status are not settled yet and are skipped.
+func compareBookingPayments(booking models.Booking, paid []payments.Settlement) (models.ReconciliationDiscrepancy, bool) {
+    d := models.ReconciliationDiscrepancy{
+        BookingID:     booking.ID,
+        BookingStatus: booking.Status,
+        Currency:      models.BookingCurrency,
+    }
+
+    switch booking.Status {
+    case models.BookingStatusCompleted:
+        d.ExpectedCents = models.AmountCents(booking.TotalAmount())
+    case models.BookingStatusCancelled, models.BookingStatusFailed:
+        d.ExpectedCents = 0
+        if booking.Cancellation != nil {
+            d.ExpectedCents = models.AmountCents(booking.Cancellation.Fee)
+        }
+    default:
+        return d, false
+    }
+
+    for _, s := range paid {
+        if !strings.EqualFold(s.Currency, models.BookingCurrency) {
+            d.Kind = models.DiscrepancyCurrencyMismatch
+            d.Currency = strings.ToLower(s.Currency)
+        }
+        d.CapturedCents += s.CapturedCents
+        d.RefundedCents += s.RefundedCents
+    }
+    d.DifferenceCents = d.CapturedCents - d.RefundedCents - d.ExpectedCents
+
+    switch {
+    case d.Kind != "":
+    case d.RefundedCents > d.CapturedCents:
+        d.Kind = models.DiscrepancyOverRefund
+    case d.DifferenceCents == 0:
+        return d, false
+    case d.CapturedCents == 0:
+        // Only a completed walk or a cancellation fee can be owed without anything captured
+        d.Kind = models.DiscrepancyMissingPayment
+    case booking.Status != models.BookingStatusCompleted && d.DifferenceCents > 0:
+        d.Kind = models.DiscrepancyUnrefundedCancellation
+    default:
+        d.Kind = models.DiscrepancyAmountMismatch
+    }
+    return d, true
+}
+
+// paidDuring reports whether any of the payments was created in [from, to)
+func paidDuring(paid []payments.Settlement, from, to time.Time) bool {
+    for _, s := range paid {
+        if !s.CreatedAt.Before(from) && s.CreatedAt.Before(to) {
+            return true
+        }
+    }
+    return false
+}
+
+// alertDiscrepancies raises an operations alert for a run with too many discrepancies
+func alertDiscrepancies(ctx context.Context, run *models.ReconciliationRun, threshold int) {
+    byKind := make(map[string]int)
+    for _, d := range run.Discrepancies {
+        byKind[string(d.Kind)]++
+    }
+
+    fields := map[string]string{
+        "run_id":            run.ID,
+        "period":            run.PeriodStart.Format("2006-01-02"),
+        "bookings_checked":  strconv.Itoa(run.BookingsChecked),
+        "discrepancies":     strconv.Itoa(run.DiscrepancyCount),
+        "discrepancy_total": fmt.Sprintf("%.2f", float64(run.DiscrepancyCents)/100),
+    }
+    for kind, count := range byKind {
+        fields[kind] = strconv.Itoa(count)
+    }
+
+    err := providersFrom(ctx).Alerts.Alert(ctx, notifier.Alert{
+        Title:  "Payment reconciliation mismatches",
+        Text:   fmt.Sprintf("%d bookings scheduled on %s do not match their payments (threshold %d)", run.DiscrepancyCount, run.PeriodStart.Format("2006-01-02"), threshold),
+        Fields: fields,
+    })
+    if err != nil {
+        log.Printf("Failed to send reconciliation alert for run %s: %v", run.ID, err)
+    }
+}
+
+// reconcilePayments reconciles the previous UTC day once it has had time to settle. Every
+// instance runs the job; the run claimed in the store ensures a day is reconciled once.
+func reconcilePayments(ctx context.Context, now time.Time) {
+    if providersFrom(ctx).Ledger == nil {
+        return
+    }
+
+    periodStart := now.UTC().Add(-reconciliationDelay).Truncate(reconciliationPeriod).Add(-reconciliationPeriod)
+    if !periodStart.After(lastReconciledPeriod) {
+        return
+    }
+
+    // A period another instance holds may still fail there, so it is only remembered once done here
+    run, err := ReconcileService(ctx, periodStart, config.Config.ReconciliationAlertThreshold)
+    if errors.Is(err, ErrPeriodReconciled) {
+        return
+    }
+    if err != nil {
+        log.Printf("Payment reconciliation for %s failed: %v", periodStart.Format("2006-01-02"), err)
+        return
+    }
+
+    lastReconciledPeriod = periodStart
+    log.Printf("Reconciled %d bookings scheduled on %s: %d discrepancies",
+        run.BookingsChecked, periodStart.Format("2006-01-02"), run.DiscrepancyCount)
+}
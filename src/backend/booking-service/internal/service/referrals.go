@@ -0,0 +1,164 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/base32"
+    "errors"
+    "fmt"
+    "log"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// Referral event types consumed by the rewards team
+const (
+    EventReferralSignedUp  = "referral.signed_up"
+    EventReferralConverted = "referral.converted"
+)
+
+// referralCodeAttempts bounds retries when a generated code collides with an existing one
+const referralCodeAttempts = 5
+
+// GetReferralCodeService returns the user's referral code, issuing one on first request
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func GetReferralCodeService(ctx context.Context, userID string) (*models.ReferralCode, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    if userID == "" {
+        return nil, fmt.Errorf("user ID is required")
+    }
+
+    existing, err := repository.GetReferralCodeForUser(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve referral code: %w", err)
+    }
+    if existing != nil {
+        return existing, nil
+    }
+
+    for attempt := 0; attempt < referralCodeAttempts; attempt++ {
+        candidate, err := newReferralCode()
+        if err != nil {
+            return nil, fmt.Errorf("failed to generate referral code: %w", err)
+        }
+
+        code, err := repository.CreateReferralCode(ctx, &models.ReferralCode{
+            Code:      candidate,
+            UserID:    userID,
+            CreatedAt: clock.FromContext(ctx).Now(),
+        })
+        if errors.Is(err, repository.ErrReferralCodeTaken) {
+            continue
+        }
+        if err != nil {
+            return nil, fmt.Errorf("failed to create referral code: %w", err)
+        }
+        return code, nil
+    }
+
+    return nil, fmt.Errorf("failed to create referral code: no unique code after %d attempts", referralCodeAttempts)
+}
+
+// AttributeReferralService records that a user signed up with a referral code
+func AttributeReferralService(ctx context.Context, code, refereeID string) (*models.Referral, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    referralCode, err := repository.GetReferralCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+    if errors.Is(err, repository.ErrReferralCodeNotFound) {
+        return nil, fmt.Errorf("invalid referral: %w", err)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to look up referral code: %w", err)
+    }
+
+    id, err := newID()
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate referral ID: %w", err)
+    }
+
+    referral := &models.Referral{
+        ID:         id,
+        Code:       referralCode.Code,
+        ReferrerID: referralCode.UserID,
+        RefereeID:  refereeID,
+        Status:     models.ReferralStatusSignedUp,
+        CreatedAt:  clock.FromContext(ctx).Now(),
+    }
+    if err := referral.Validate(); err != nil {
+        return nil, fmt.Errorf("invalid referral: %w", err)
+    }
+
+    err = repository.CreateReferral(ctx, referral)
+    if errors.Is(err, repository.ErrAlreadyReferred) {
+        return nil, fmt.Errorf("referral conflict: %w", err)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to create referral: %w", err)
+    }
+
+    events.Publish(ctx, EventReferralSignedUp, referral)
+    return referral, nil
+}
+
+// ReferralReportService summarises referral conversions and credits granted in [from, to)
+func ReferralReportService(ctx context.Context, from, to time.Time) ([]models.ReferralSummary, error) {
+    if !from.Before(to) {
+        return nil, fmt.Errorf("invalid report range: from must be before to")
+    }
+
+    report, err := repository.GetReferralReport(ctx, from, to)
+    if err != nil {
+        return nil, fmt.Errorf("failed to build referral report: %w", err)
+    }
+    return report, nil
+}
+
+// convertReferral attributes and converts a referral when booking is its owner's first.
+// Failures are logged rather than returned so they never fail the booking itself.
+func convertReferral(ctx context.Context, booking *models.Booking) {
+    // A code supplied with the first booking counts as attribution for users who
+    // were not referred at signup
+    if booking.ReferralCode != "" {
+        count, err := repository.CountBookingsByOwner(ctx, booking.OwnerID)
+        if err != nil {
+            log.Printf("Failed to check first booking for referral: %v", err)
+            return
+        }
+        if count == 1 {
+            _, err := AttributeReferralService(ctx, booking.ReferralCode, booking.OwnerID)
+            if err != nil && !errors.Is(err, repository.ErrAlreadyReferred) {
+                log.Printf("Failed to attribute referral code %s on booking %s: %v", booking.ReferralCode, booking.ID, err)
+            }
+        }
+    }
+
+    referral, err := repository.ConvertReferral(ctx, booking.OwnerID, booking.ID, config.Config.ReferralCredit, clock.FromContext(ctx).Now())
+    if err != nil {
+        log.Printf("Failed to convert referral for booking %s: %v", booking.ID, err)
+        return
+    }
+    if referral == nil {
+        return
+    }
+
+    events.Publish(ctx, EventReferralConverted, referral)
+}
+
+// newReferralCode generates an 8-character code that is easy to read aloud and type
+func newReferralCode() (string, error) {
+    b := make([]byte, 5)
+    if _, err := rand.Read(b); err != nil {
+        return "", err
+    }
+    return base32.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,108 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "strings"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/metrics"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+    "src/backend/shared/regions"
+)
+
+// Region events
+const (
+    EventRegionSaved   = "region.saved"
+    EventRegionDeleted = "region.deleted"
+)
+
+// ListRegionsService returns every service region, by ID. Other services poll this list to tag
+// walks with the region their coordinates fall in.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ListRegionsService(ctx context.Context) ([]regions.Region, error) {
+    list, err := repository.ListRegions(ctx)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list regions: %w", err)
+    }
+    return list, nil
+}
+
+// SaveRegionService defines the region with the given ID, replacing its name and boundary if
+// it already exists. Bookings already tagged keep their region; only new ones use the change.
+func SaveRegionService(ctx context.Context, id string, region *regions.Region) error {
+    region.ID = NormalizeRegion(id)
+    region.Name = strings.TrimSpace(region.Name)
+    if err := region.Validate(); err != nil {
+        return fmt.Errorf("invalid region: %w", err)
+    }
+    region.UpdatedAt = clock.FromContext(ctx).Now().UTC()
+
+    if err := repository.SaveRegion(ctx, region); err != nil {
+        return fmt.Errorf("failed to save region: %w", err)
+    }
+    events.Publish(ctx, EventRegionSaved, region)
+    return nil
+}
+
+// DeleteRegionService removes a service region
+func DeleteRegionService(ctx context.Context, id string) error {
+    id = NormalizeRegion(id)
+    err := repository.DeleteRegion(ctx, id)
+    if errors.Is(err, repository.ErrRegionNotFound) {
+        return fmt.Errorf("region not found: %s", id)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to delete region: %w", err)
+    }
+    events.Publish(ctx, EventRegionDeleted, map[string]string{"id": id})
+    return nil
+}
+
+// CodeOutsideServiceArea is the code clients are told bookings outside the service area by, so
+// they can tell them from other invalid bookings
+const CodeOutsideServiceArea = "booking_outside_service_area"
+
+// ServiceAreaError is returned when a booking's pickup lies outside every service region
+type ServiceAreaError struct {
+    // Latitude and Longitude are the pickup refused
+    Latitude  float64 `json:"latitude"`
+    Longitude float64 `json:"longitude"`
+
+    // err is the message for the owner
+    err error
+}
+
+// Error returns the message for the owner in the default language
+func (e *ServiceAreaError) Error() string {
+    return e.err.Error()
+}
+
+// Unwrap returns the message for the owner, which i18n.Localize translates
+func (e *ServiceAreaError) Unwrap() error {
+    return e.err
+}
+
+// locateRegion returns the ID of the region containing the coordinate, or "" when none does.
+// While the service area is enforced and regions are defined, a coordinate outside all of them
+// is refused with a ServiceAreaError and counted as out-of-area demand. A failure to load the
+// regions is logged rather than returned, so it never blocks a booking.
+func locateRegion(ctx context.Context, latitude, longitude float64) (string, error) {
+    list, err := repository.ListRegions(ctx)
+    if err != nil {
+        log.Printf("Failed to load regions to locate a booking: %v", err)
+        return "", nil
+    }
+    region := regions.NewSet(list).Locate(latitude, longitude)
+    if region == "" && len(list) > 0 && config.Config.ServiceAreaEnforced {
+        metrics.RecordOutOfArea(latitude, longitude)
+        return "", &ServiceAreaError{Latitude: latitude, Longitude: longitude, err: i18n.Errorf("booking.outside_service_area")}
+    }
+    return region, nil
+}
@@ -0,0 +1,448 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "bytes"
+    "context"
+    "encoding/csv"
+    "errors"
+    "fmt"
+    "log"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/tracking"
+    "src/backend/shared/clock"
+)
+
+const (
+    // reportRunGrace is how long after a schedule fires its report may still be sent; a report
+    // missed for longer, while every instance was down, is skipped rather than sent late
+    reportRunGrace = 6 * time.Hour
+
+    // reportRetryAfter is how long a failed report run waits before it is attempted again
+    reportRetryAfter = 15 * time.Minute
+
+    // reportMaxAttempts is how many times a scheduled report is tried before it is given up
+    reportMaxAttempts = 3
+
+    // maxReportPeriod is the longest period one scheduled report covers; a schedule firing
+    // less often reports on the period just before it fires
+    maxReportPeriod = 31 * 24 * time.Hour
+
+    // maxEmailedReportRows caps the rows listed in a report's email body; the attachment has them all
+    maxEmailedReportRows = 50
+
+    // maxAnomalyChecks caps the walks whose tracking one anomalies report checks
+    maxAnomalyChecks = 500
+)
+
+// ErrReportRunClaimed is returned when the report has been sent for that time, is being sent
+// by another instance, or is waiting to be retried
+var ErrReportRunClaimed = errors.New("report already sent")
+
+// lastReportRuns is the fire time each schedule was last sent for by this instance, so the
+// background job does not try to claim it again every minute
+var lastReportRuns = make(map[string]time.Time)
+
+// reportRenderers build the email of each kind of report for a run's period
+var reportRenderers = map[models.ReportKind]func(ctx context.Context, run *models.ReportRun) (*notifier.Email, error){
+    models.ReportBookingsSummary:   renderBookingsSummary,
+    models.ReportEarnings:          renderEarningsReport,
+    models.ReportTrackingAnomalies: renderTrackingAnomalies,
+}
+
+// ListReportSchedulesService lists the configured report schedules with when each next fires
+// and its most recent run
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func ListReportSchedulesService(ctx context.Context, now time.Time) ([]models.ScheduledReport, error) {
+    scheduled := make([]models.ScheduledReport, 0, len(config.Config.ReportSchedules))
+    for _, schedule := range config.Config.ReportSchedules {
+        report := models.ScheduledReport{ReportSchedule: schedule, NextRunAt: schedule.Cron.Next(now)}
+        runs, err := repository.ListReportRuns(ctx, schedule.Name, 1)
+        if err != nil {
+            return nil, fmt.Errorf("failed to list report runs: %w", err)
+        }
+        if len(runs) > 0 {
+            report.LastRun = &runs[0]
+        }
+        scheduled = append(scheduled, report)
+    }
+    return scheduled, nil
+}
+
+// ListReportRunsService lists the most recent report runs, of every schedule or only of the
+// named one
+func ListReportRunsService(ctx context.Context, schedule string, limit int) ([]models.ReportRun, error) {
+    if limit <= 0 || limit > 100 {
+        limit = 30
+    }
+    runs, err := repository.ListReportRuns(ctx, schedule, limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list report runs: %w", err)
+    }
+    return runs, nil
+}
+
+// RunReportService sends the named schedule's report now, covering the period since its cron
+// expression last fired, as an admin re-sending a report that failed or was missed
+func RunReportService(ctx context.Context, name string, now time.Time) (*models.ReportRun, error) {
+    for _, schedule := range config.Config.ReportSchedules {
+        if schedule.Name == name {
+            return runReport(ctx, schedule, now.UTC().Truncate(time.Minute))
+        }
+    }
+    return nil, fmt.Errorf("report schedule not found: %s", name)
+}
+
+// runReport renders the schedule's report for the period ending at scheduledFor and emails it
+// to the schedule's recipients, recording the run. A failed run raises an operations alert.
+func runReport(ctx context.Context, schedule models.ReportSchedule, scheduledFor time.Time) (*models.ReportRun, error) {
+    id, err := newID()
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate report run ID: %w", err)
+    }
+    periodStart := schedule.Cron.Prev(scheduledFor)
+    if periodStart.IsZero() || scheduledFor.Sub(periodStart) > maxReportPeriod {
+        periodStart = scheduledFor.Add(-maxReportPeriod)
+    }
+    now := clock.FromContext(ctx).Now()
+    run := &models.ReportRun{
+        ID:           id,
+        Schedule:     schedule.Name,
+        Report:       schedule.Report,
+        ScheduledFor: scheduledFor,
+        PeriodStart:  periodStart,
+        PeriodEnd:    scheduledFor,
+        Recipients:   len(schedule.Recipients),
+        StartedAt:    now,
+    }
+
+    claimed, err := repository.ClaimReportRun(ctx, run, now.Add(-reportRetryAfter), reportMaxAttempts)
+    if err != nil {
+        return nil, fmt.Errorf("failed to start report: %w", err)
+    }
+    if !claimed {
+        return nil, ErrReportRunClaimed
+    }
+
+    email, err := reportRenderers[schedule.Report](ctx, run)
+    if err == nil {
+        run.Delivered, err = emailRecipients(ctx, schedule.Recipients, *email, "report "+schedule.Name)
+    }
+    if err != nil {
+        if failErr := repository.FailReportRun(ctx, run.ID, err.Error(), clock.FromContext(ctx).Now()); failErr != nil {
+            log.Printf("Failed to record failed report run %s: %v", run.ID, failErr)
+        }
+        alertReportFailure(ctx, run, err)
+        return nil, fmt.Errorf("failed to send report: %w", err)
+    }
+
+    completedAt := clock.FromContext(ctx).Now()
+    run.CompletedAt = &completedAt
+    if err := repository.CompleteReportRun(ctx, run); err != nil {
+        return nil, fmt.Errorf("failed to store report run: %w", err)
+    }
+    return run, nil
+}
+
+// sendScheduledReports sends each schedule's report once its cron expression has fired. Every
+// instance runs the job; the run claimed in the store ensures a report is sent once, and a
+// failed run is retried until it runs out of attempts or its grace period.
+func sendScheduledReports(ctx context.Context, now time.Time) {
+    for _, schedule := range config.Config.ReportSchedules {
+        due := schedule.Cron.Prev(now.UTC().Truncate(time.Minute).Add(time.Minute))
+        if due.IsZero() || now.Sub(due) > reportRunGrace || !due.After(lastReportRuns[schedule.Name]) {
+            continue
+        }
+
+        // A run another instance holds may still fail there, so it is only remembered once sent here
+        run, err := runReport(ctx, schedule, due)
+        if errors.Is(err, ErrReportRunClaimed) {
+            continue
+        }
+        if err != nil {
+            log.Printf("Scheduled report %s for %s failed: %v", schedule.Name, due.Format(time.RFC3339), err)
+            continue
+        }
+
+        lastReportRuns[schedule.Name] = due
+        log.Printf("Sent scheduled report %s for %s to %d of %d recipients",
+            schedule.Name, due.Format(time.RFC3339), run.Delivered, run.Recipients)
+    }
+}
+
+// alertReportFailure raises an operations alert for a report run that could not be sent
+func alertReportFailure(ctx context.Context, run *models.ReportRun, cause error) {
+    text := fmt.Sprintf("The %s report of schedule %s due %s could not be sent: %v",
+        run.Report, run.Schedule, run.ScheduledFor.Format(time.RFC3339), cause)
+    if run.Attempts < reportMaxAttempts {
+        text += fmt.Sprintf(". It will be retried in %s.", reportRetryAfter)
+    } else {
+        text += ". It will not be retried."
+    }
+
+    err := providersFrom(ctx).Alerts.Alert(ctx, notifier.Alert{
+        Title: "Scheduled report failed",
+        Text:  text,
+        Fields: map[string]string{
+            "run_id":        run.ID,
+            "schedule":      run.Schedule,
+            "report":        string(run.Report),
+            "scheduled_for": run.ScheduledFor.Format(time.RFC3339),
+            "attempt":       fmt.Sprintf("%d of %d", run.Attempts, reportMaxAttempts),
+        },
+    })
+    if err != nil {
+        log.Printf("Failed to send report alert for run %s: %v", run.ID, err)
+    }
+}
+
+// emailRecipients emails every recipient, returning how many it reached. A recipient it
+// could not reach is only logged once anyone has the email, so a retry does not send the
+// others a second copy.
+func emailRecipients(ctx context.Context, recipients []string, email notifier.Email, what string) (int, error) {
+    sent := 0
+    var lastErr error
+    for _, address := range recipients {
+        if err := providersFrom(ctx).Notifier.Email(ctx, address, email); err != nil {
+            log.Printf("Failed to email %s to a recipient: %v", what, err)
+            lastErr = err
+            continue
+        }
+        sent++
+    }
+    if sent == 0 {
+        return 0, fmt.Errorf("failed to email %s: %w", what, lastErr)
+    }
+    return sent, nil
+}
+
+// reportEmail builds a report's email with body and the rows attached as CSV
+func reportEmail(run *models.ReportRun, title, body string, header []string, rows [][]string) (*notifier.Email, error) {
+    var buf bytes.Buffer
+    w := csv.NewWriter(&buf)
+    w.Write(header)
+    w.WriteAll(rows)
+    if err := w.Error(); err != nil {
+        return nil, fmt.Errorf("failed to write %s report: %w", run.Report, err)
+    }
+
+    return &notifier.Email{
+        Subject: fmt.Sprintf("%s for %s", title, run.PeriodEnd.Format("2 Jan 2006 15:04 MST")),
+        Body: fmt.Sprintf("%s from %s to %s (UTC).\n\n%s", title,
+            run.PeriodStart.Format("Mon 2 Jan 15:04"), run.PeriodEnd.Format("Mon 2 Jan 15:04"), body),
+        Attachments: []notifier.Attachment{{
+            Filename:    fmt.Sprintf("%s-%s.csv", run.Schedule, run.ScheduledFor.Format("20060102-1504")),
+            ContentType: "text/csv",
+            Content:     buf.Bytes(),
+        }},
+    }, nil
+}
+
+// renderBookingsSummary counts the bookings scheduled in the period by status, attaching the
+// count and amount of each region and status
+func renderBookingsSummary(ctx context.Context, run *models.ReportRun) (*notifier.Email, error) {
+    bookings, err := repository.ListBookingsScheduledBetween(ctx, run.PeriodStart, run.PeriodEnd)
+    if err != nil {
+        return nil, fmt.Errorf("failed to build bookings summary: %w", err)
+    }
+
+    type groupKey struct {
+        region string
+        status models.BookingStatus
+    }
+    type group struct {
+        bookings int
+        amount   float64
+    }
+    groups := make(map[groupKey]*group)
+    byStatus := make(map[models.BookingStatus]int)
+    var completedAmount float64
+    for _, b := range bookings {
+        key := groupKey{region: b.Region, status: b.Status}
+        g, ok := groups[key]
+        if !ok {
+            g = &group{}
+            groups[key] = g
+        }
+        g.bookings++
+        g.amount += b.Amount
+        byStatus[b.Status]++
+        if b.Status == models.BookingStatusCompleted {
+            completedAmount += b.Amount
+        }
+    }
+
+    var body strings.Builder
+    fmt.Fprintf(&body, "%d bookings were scheduled.\n", len(bookings))
+    statuses := make([]string, 0, len(byStatus))
+    for status := range byStatus {
+        statuses = append(statuses, string(status))
+    }
+    sort.Strings(statuses)
+    for _, status := range statuses {
+        fmt.Fprintf(&body, "  %s: %d\n", status, byStatus[models.BookingStatus(status)])
+    }
+    fmt.Fprintf(&body, "\nCompleted walks were booked for %.2f %s.\n", completedAmount, strings.ToUpper(models.BookingCurrency))
+
+    keys := make([]groupKey, 0, len(groups))
+    for key := range groups {
+        keys = append(keys, key)
+    }
+    sort.Slice(keys, func(i, j int) bool {
+        if keys[i].region != keys[j].region {
+            return keys[i].region < keys[j].region
+        }
+        return keys[i].status < keys[j].status
+    })
+    rows := make([][]string, 0, len(keys))
+    for _, key := range keys {
+        g := groups[key]
+        rows = append(rows, []string{key.region, string(key.status), strconv.Itoa(g.bookings), fmt.Sprintf("%.2f", g.amount)})
+    }
+    return reportEmail(run, "Bookings summary", body.String(), []string{"region", "status", "bookings", "amount"}, rows)
+}
+
+// renderEarningsReport lists what each walker earned from the walks completed in the period,
+// highest earner first
+func renderEarningsReport(ctx context.Context, run *models.ReportRun) (*notifier.Email, error) {
+    earnings, err := WalkerEarningsReportService(ctx, run.PeriodStart, run.PeriodEnd, "", "")
+    if err != nil {
+        return nil, err
+    }
+    sort.SliceStable(earnings, func(i, j int) bool { return earnings[i].Total > earnings[j].Total })
+
+    var walks int
+    var amount, tips float64
+    for _, e := range earnings {
+        walks += e.Bookings
+        amount += e.BookingAmount
+        tips += e.Tips
+    }
+    currency := strings.ToUpper(models.BookingCurrency)
+
+    var body strings.Builder
+    fmt.Fprintf(&body, "%d walkers completed %d walks, earning %.2f %s in bookings and %.2f %s in tips.\n",
+        len(earnings), walks, amount, currency, tips, currency)
+    rows := make([][]string, 0, len(earnings))
+    for i, e := range earnings {
+        if i == 0 {
+            body.WriteString("\n")
+        }
+        if i < maxEmailedReportRows {
+            fmt.Fprintf(&body, "%s: %d walks, %.2f %s\n", e.WalkerID, e.Bookings, e.Total, currency)
+        } else if i == maxEmailedReportRows {
+            fmt.Fprintf(&body, "...and %d more walkers in the attached report.\n", len(earnings)-i)
+        }
+        rows = append(rows, []string{
+            e.WalkerID,
+            strconv.Itoa(e.Bookings),
+            fmt.Sprintf("%.2f", e.BookingAmount),
+            fmt.Sprintf("%.2f", e.Tips),
+            fmt.Sprintf("%.2f", e.Total),
+        })
+    }
+    return reportEmail(run, "Walker earnings", body.String(), []string{"walker_id", "bookings", "booking_amount", "tips", "total"}, rows)
+}
+
+// renderTrackingAnomalies checks what the tracking-service tracked of each walk completed in
+// the period, listing the walks never tracked, still tracked, cut short or covering no ground
+func renderTrackingAnomalies(ctx context.Context, run *models.ReportRun) (*notifier.Email, error) {
+    walks := providersFrom(ctx).Walks
+    if walks == nil {
+        return nil, fmt.Errorf("tracking anomalies unavailable: no tracking service configured")
+    }
+    bookings, err := repository.ListBookingsScheduledBetween(ctx, run.PeriodStart, run.PeriodEnd)
+    if err != nil {
+        return nil, fmt.Errorf("failed to build tracking anomalies report: %w", err)
+    }
+
+    var completed []models.Booking
+    for _, b := range bookings {
+        if b.Status == models.BookingStatusCompleted {
+            completed = append(completed, b)
+        }
+    }
+    unchecked := 0
+    if len(completed) > maxAnomalyChecks {
+        unchecked = len(completed) - maxAnomalyChecks
+        completed = completed[:maxAnomalyChecks]
+    }
+
+    var rows [][]string
+    var listed strings.Builder
+    failed := 0
+    var lastErr error
+    for _, b := range completed {
+        evidence, err := walks.WalkEvidence(ctx, b.ID)
+        if err != nil {
+            failed++
+            lastErr = err
+            continue
+        }
+        anomalies := walkAnomalies(&b, evidence)
+        if len(anomalies) == 0 {
+            continue
+        }
+        if len(rows) < maxEmailedReportRows {
+            fmt.Fprintf(&listed, "%s  booking %s, walker %s: %s\n",
+                b.ScheduledAt.UTC().Format("Mon 2 Jan 15:04"), b.ID, b.WalkerID, strings.Join(anomalies, "; "))
+        }
+        rows = append(rows, []string{
+            b.ID,
+            b.WalkerID,
+            b.ScheduledAt.UTC().Format(time.RFC3339),
+            strconv.Itoa(b.DurationMinutes),
+            strconv.Itoa(evidence.Sessions),
+            strconv.Itoa(int(evidence.Duration() / time.Minute)),
+            fmt.Sprintf("%.0f", evidence.DistanceMeters),
+            strings.Join(anomalies, "; "),
+        })
+    }
+    if failed > 0 && failed == len(completed) {
+        return nil, fmt.Errorf("failed to check walk tracking: %w", lastErr)
+    }
+
+    var body strings.Builder
+    fmt.Fprintf(&body, "%d of %d completed walks checked look wrong.\n", len(rows), len(completed)-failed)
+    if failed > 0 {
+        fmt.Fprintf(&body, "The tracking of %d walks could not be read.\n", failed)
+    }
+    if unchecked > 0 {
+        fmt.Fprintf(&body, "%d later walks were not checked.\n", unchecked)
+    }
+    if len(rows) > 0 {
+        body.WriteString("\n")
+        body.WriteString(listed.String())
+        if len(rows) > maxEmailedReportRows {
+            fmt.Fprintf(&body, "...and %d more in the attached report.\n", len(rows)-maxEmailedReportRows)
+        }
+    }
+    header := []string{"booking_id", "walker_id", "scheduled_at", "booked_minutes", "sessions", "tracked_minutes", "distance_meters", "anomalies"}
+    return reportEmail(run, "Walk tracking anomalies", body.String(), header, rows)
+}
+
+// walkAnomalies lists what looks wrong with the tracking of a completed walk
+func walkAnomalies(booking *models.Booking, evidence *tracking.WalkEvidence) []string {
+    if evidence.Sessions == 0 {
+        return []string{"never tracked"}
+    }
+    var anomalies []string
+    if evidence.Active {
+        anomalies = append(anomalies, "still being tracked")
+    }
+    if tracked := evidence.Duration(); tracked < time.Duration(booking.DurationMinutes)*time.Minute/2 {
+        anomalies = append(anomalies, fmt.Sprintf("tracked for %d of %d minutes booked", int(tracked/time.Minute), booking.DurationMinutes))
+    }
+    if evidence.DistanceMeters == 0 {
+        anomalies = append(anomalies, "covered no distance")
+    }
+    return anomalies
+}
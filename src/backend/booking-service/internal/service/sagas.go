@@ -0,0 +1,313 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// EventConfirmationReverted is published when a booking's confirmation failed part way and
+// was undone
+const EventConfirmationReverted = "booking.confirmation_reverted"
+
+const (
+    // sagaStallTimeout is how long an unfinished saga goes without an update before the
+    // background jobs take it over, well beyond how long any request runs one
+    sagaStallTimeout = 2 * time.Minute
+
+    // sagaMaxAttempts is how many times a step that is retried rather than undone is tried
+    // before the saga completes without it
+    sagaMaxAttempts = 5
+
+    // sagaResumeBatch bounds how many stalled sagas one background run takes over
+    sagaResumeBatch = 50
+)
+
+// Confirmation saga steps
+const (
+    stepAcceptWalker     = "accept_walker"
+    stepAuthorizePayment = "authorize_payment"
+    stepSyncCalendar     = "sync_calendar"
+    stepNotifyOwner      = "notify_owner"
+)
+
+// errStepSkipped is returned by a step with nothing to do
+var errStepSkipped = errors.New("step skipped")
+
+// sagaStep is one step of a saga. run must be safe to repeat, as a saga taken over after a
+// crash reruns the step it was interrupted in.
+type sagaStep struct {
+    name string
+    run  func(ctx context.Context, saga *models.Saga, step *models.SagaStep) error
+
+    // compensate undoes the step when a later one fails. Steps without it come after the
+    // point of no return: they are retried, up to sagaMaxAttempts, instead of undoing the saga.
+    compensate func(ctx context.Context, saga *models.Saga) error
+}
+
+// sagaSteps are the steps of each kind of saga, in order
+var sagaSteps = map[string][]sagaStep{
+    // The walker accepts before the payment is held, so a repeated acceptance fails before
+    // anything needs undoing
+    models.SagaKindBookingConfirmation: {
+        {name: stepAcceptWalker, run: acceptWalkerStep, compensate: revertAcceptanceStep},
+        {name: stepAuthorizePayment, run: authorizePaymentStep, compensate: voidPaymentStep},
+        {name: stepSyncCalendar, run: syncCalendarStep},
+        {name: stepNotifyOwner, run: notifyOwnerStep},
+    },
+    // Nothing is undone once the walker is released: a suspended walker cannot have the
+    // booking back, so every step is retried instead
+    models.SagaKindWalkerSuspension: {
+        {name: stepReleaseWalker, run: releaseWalkerStep},
+        {name: stepNotifyOwner, run: notifyReassignmentStep},
+        {name: stepReassignWalker, run: reassignWalkerStep},
+    },
+}
+
+// newSaga creates a saga of the given kind with every step pending
+func newSaga(ctx context.Context, kind string, booking *models.Booking, walkerID string) (*models.Saga, error) {
+    id, err := newID()
+    if err != nil {
+        return nil, fmt.Errorf("failed to generate saga ID: %w", err)
+    }
+    now := clock.FromContext(ctx).Now().UTC()
+    saga := &models.Saga{
+        ID:        id,
+        Kind:      kind,
+        BookingID: booking.ID,
+        WalkerID:  walkerID,
+        Status:    models.SagaStatusRunning,
+        AcceptBy:  booking.AcceptBy,
+        CreatedAt: now,
+        UpdatedAt: now,
+    }
+    for _, step := range sagaSteps[kind] {
+        saga.Steps = append(saga.Steps, models.SagaStep{Name: step.name, Status: models.SagaStepPending, UpdatedAt: now})
+    }
+    return saga, nil
+}
+
+// runSaga runs a saga's pending steps in order. When a step that can be undone fails, the
+// steps done before it are undone in reverse and its error is returned. The saga is saved
+// after every step, so it can be taken over if this process stops part way.
+func runSaga(ctx context.Context, saga *models.Saga) error {
+    steps := sagaSteps[saga.Kind]
+    if steps == nil {
+        return fmt.Errorf("unknown saga kind %q", saga.Kind)
+    }
+
+    var failure error
+    for _, def := range steps {
+        if saga.Status != models.SagaStatusRunning {
+            break
+        }
+        step := saga.Step(def.name)
+        if step == nil || step.Status != models.SagaStepPending {
+            continue
+        }
+
+        // The attempt is recorded before the step runs, so a step interrupted part way is
+        // known to be a retry when the saga is taken over
+        step.Attempts++
+        saveSaga(ctx, saga)
+
+        err := def.run(ctx, saga, step)
+        step.UpdatedAt = clock.FromContext(ctx).Now().UTC()
+        switch {
+        case err == nil:
+            step.Status, step.Error = models.SagaStepDone, ""
+        case errors.Is(err, errStepSkipped):
+            step.Status, step.Error = models.SagaStepSkipped, ""
+        case def.compensate == nil:
+            step.Error = err.Error()
+            if step.Attempts >= sagaMaxAttempts {
+                step.Status = models.SagaStepFailed
+                log.Printf("Saga %s gave up on step %s of booking %s: %v", saga.ID, step.Name, saga.BookingID, err)
+            }
+        default:
+            step.Status, step.Error = models.SagaStepFailed, err.Error()
+            saga.Status = models.SagaStatusCompensating
+            failure = err
+        }
+        saveSaga(ctx, saga)
+    }
+
+    if saga.Status == models.SagaStatusCompensating {
+        return compensateSaga(ctx, saga, failure)
+    }
+    for _, step := range saga.Steps {
+        if step.Status == models.SagaStepPending {
+            // Left running for the background jobs to retry
+            return nil
+        }
+    }
+    saga.Status = models.SagaStatusCompleted
+    saveSaga(ctx, saga)
+    return nil
+}
+
+// compensateSaga undoes a failed saga's completed steps in reverse, returning the error of the
+// step that failed, or the one recorded for it when the saga was taken over. A compensation
+// that fails leaves the saga compensating, for the background jobs to try again.
+func compensateSaga(ctx context.Context, saga *models.Saga, failure error) error {
+    steps := sagaSteps[saga.Kind]
+
+    for _, step := range saga.Steps {
+        if failure == nil && step.Status == models.SagaStepFailed {
+            failure = errors.New(step.Error)
+        }
+    }
+
+    for i := len(steps) - 1; i >= 0; i-- {
+        def := steps[i]
+        step := saga.Step(def.name)
+        if step == nil || step.Status != models.SagaStepDone || def.compensate == nil {
+            continue
+        }
+
+        err := def.compensate(ctx, saga)
+        step.UpdatedAt = clock.FromContext(ctx).Now().UTC()
+        if err != nil {
+            step.Error = fmt.Sprintf("compensation failed: %v", err)
+            saveSaga(ctx, saga)
+            log.Printf("Failed to undo step %s of saga %s for booking %s: %v", step.Name, saga.ID, saga.BookingID, err)
+            return failure
+        }
+        step.Status = models.SagaStepCompensated
+        saveSaga(ctx, saga)
+    }
+
+    saga.Status = models.SagaStatusCompensated
+    saveSaga(ctx, saga)
+    events.Publish(ctx, EventConfirmationReverted, saga)
+    return failure
+}
+
+// saveSaga records a saga's progress. A failed save is logged, not returned: the steps have
+// already happened, and the saga carries on from the state held in memory.
+func saveSaga(ctx context.Context, saga *models.Saga) {
+    saga.UpdatedAt = clock.FromContext(ctx).Now().UTC()
+    if err := repository.SaveSaga(ctx, saga); err != nil {
+        log.Printf("Failed to save saga %s of booking %s: %v", saga.ID, saga.BookingID, err)
+    }
+}
+
+// resumeStalledSagas takes over sagas interrupted part way, finishing those still running and
+// undoing those that were compensating
+func resumeStalledSagas(ctx context.Context, now time.Time) {
+    stalled, err := repository.ListStalledSagas(ctx, now.Add(-sagaStallTimeout), sagaResumeBatch)
+    if err != nil {
+        log.Printf("Failed to list stalled sagas: %v", err)
+        return
+    }
+
+    for i := range stalled {
+        saga := &stalled[i]
+        claimed, err := repository.ClaimSaga(ctx, saga.ID, saga.UpdatedAt, now)
+        if err != nil {
+            log.Printf("Failed to claim saga %s: %v", saga.ID, err)
+            continue
+        }
+        if !claimed {
+            continue
+        }
+
+        if err := runSaga(ctx, saga); err != nil {
+            log.Printf("Resumed saga %s of booking %s was undone: %v", saga.ID, saga.BookingID, err)
+        }
+    }
+}
+
+// acceptWalkerStep confirms the booking with the walker. A retried step accepts a booking
+// already confirmed with the walker, as the interrupted attempt may have confirmed it.
+func acceptWalkerStep(ctx context.Context, saga *models.Saga, step *models.SagaStep) error {
+    err := repository.AcceptAssignment(ctx, saga.BookingID, saga.WalkerID)
+    if errors.Is(err, repository.ErrNoPendingAssignment) && step.Attempts > 1 {
+        booking, getErr := GetBookingService(ctx, saga.BookingID)
+        if getErr == nil && booking.Status == models.BookingStatusConfirmed && booking.WalkerID == saga.WalkerID {
+            return nil
+        }
+    }
+    if errors.Is(err, repository.ErrNoPendingAssignment) {
+        return fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to accept booking: %w", err)
+    }
+    return nil
+}
+
+// revertAcceptanceStep returns the booking to awaiting the walker's acceptance
+func revertAcceptanceStep(ctx context.Context, saga *models.Saga) error {
+    return repository.RevertAcceptance(ctx, saga.BookingID, saga.WalkerID, saga.AcceptBy)
+}
+
+// authorizePaymentStep holds the booking's total on the owner's payment method. The saga's ID
+// is the charge ID, so a retried authorization returns the original hold.
+func authorizePaymentStep(ctx context.Context, saga *models.Saga, step *models.SagaStep) error {
+    holds := providersFrom(ctx).Holds
+    if holds == nil {
+        return errStepSkipped
+    }
+    booking, err := GetBookingService(ctx, saga.BookingID)
+    if err != nil {
+        return err
+    }
+
+    result, err := holds.Authorize(ctx, payments.Charge{
+        ID:          saga.ID,
+        UserID:      booking.OwnerID,
+        BookingID:   booking.ID,
+        Kind:        payments.KindBooking,
+        AmountCents: models.AmountCents(booking.TotalAmount()),
+        Currency:    models.BookingCurrency,
+    })
+    if err != nil {
+        return fmt.Errorf("payment not authorized: %w", err)
+    }
+    saga.PaymentID = result.PaymentID
+    return nil
+}
+
+// voidPaymentStep releases the hold placed on the owner's payment method
+func voidPaymentStep(ctx context.Context, saga *models.Saga) error {
+    if saga.PaymentID == "" {
+        return nil
+    }
+    holds := providersFrom(ctx).Holds
+    if holds == nil {
+        return fmt.Errorf("cannot void payment %s: no payment service configured", saga.PaymentID)
+    }
+    return holds.Void(ctx, saga.PaymentID)
+}
+
+// syncCalendarStep writes the confirmed walk to the walker's connected calendars
+func syncCalendarStep(ctx context.Context, saga *models.Saga, step *models.SagaStep) error {
+    booking, err := GetBookingService(ctx, saga.BookingID)
+    if err != nil {
+        return err
+    }
+    return syncBookingCalendars(booking)
+}
+
+// notifyOwnerStep announces the confirmation and tells the owner their walk is confirmed
+func notifyOwnerStep(ctx context.Context, saga *models.Saga, step *models.SagaStep) error {
+    booking, err := GetBookingService(ctx, saga.BookingID)
+    if err != nil {
+        return err
+    }
+    events.Publish(ctx, EventWalkerAccepted, booking)
+    textOwner(ctx, booking, notifier.SMSBookingConfirmed, notifier.SMSData{})
+    emailOwner(ctx, booking, notifier.EmailBookingConfirmed, notifier.EmailData{})
+    return nil
+}
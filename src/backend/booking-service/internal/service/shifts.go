@@ -0,0 +1,243 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/tracking"
+    "src/backend/shared/clock"
+)
+
+// Events published as walkers check in to and out of walks
+const (
+    EventWalkerCheckedIn  = "booking.walker_checked_in"
+    EventWalkerCheckedOut = "booking.walker_checked_out"
+)
+
+// Check-in limits used when the configuration leaves them unset
+const (
+    defaultCheckInRadius         = 250.0
+    defaultCheckInPositionMaxAge = 10 * time.Minute
+)
+
+// checkInLimits returns how close to the pickup, in meters, and how recently tracked a walker's
+// position must be for them to check in
+func checkInLimits() (float64, time.Duration) {
+    radius, maxAge := defaultCheckInRadius, defaultCheckInPositionMaxAge
+    if config.Config != nil && config.Config.CheckInRadius > 0 {
+        radius = config.Config.CheckInRadius
+    }
+    if config.Config != nil && config.Config.CheckInPositionMaxAge > 0 {
+        maxAge = config.Config.CheckInPositionMaxAge
+    }
+    return radius, maxAge
+}
+
+// CheckInService starts a confirmed walk once its walker is verified to be at the pickup: their
+// last position tracked by the tracking-service must be recent and within
+// config.Config.CheckInRadius of the booking's pickup coordinates. Walkers who cannot be
+// verified are checked in by support with CheckInOverrideService.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func CheckInService(ctx context.Context, bookingID, walkerID string) (*models.Booking, error) {
+    if walkerID == "" {
+        return nil, fmt.Errorf("invalid check-in: walker ID is required")
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.WalkerID != walkerID {
+        return nil, fmt.Errorf("invalid check-in: walker %s is not assigned to booking %s", walkerID, bookingID)
+    }
+    if alreadyTransitioned(booking, models.BookingStatusInProgress, walkerID) {
+        return booking, nil
+    }
+    if booking.Status != models.BookingStatusConfirmed {
+        return nil, fmt.Errorf("booking conflict: booking is %s, not confirmed", booking.Status)
+    }
+    if booking.Latitude == nil || booking.Longitude == nil {
+        return nil, fmt.Errorf("check-in not allowed: booking %s has no pickup location to verify the walker against", bookingID)
+    }
+
+    locator := providersFrom(ctx).Locator
+    if locator == nil {
+        return nil, fmt.Errorf("check-in unavailable: no tracking service configured")
+    }
+    position, err := locator.LastPosition(ctx, walkerID)
+    if errors.Is(err, tracking.ErrNoPosition) {
+        return nil, fmt.Errorf("check-in not allowed: walker %s has no tracked position; start tracking the walk first", walkerID)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("check-in unavailable: %w", err)
+    }
+
+    radius, maxAge := checkInLimits()
+    if age := time.Since(position.UpdatedAt); age > maxAge {
+        return nil, fmt.Errorf("check-in not allowed: walker %s was last tracked %s ago, longer than the %s allowed",
+            walkerID, age.Round(time.Second), maxAge)
+    }
+    distance := distanceKm(
+        models.RoutePoint{Latitude: position.Latitude, Longitude: position.Longitude},
+        models.RoutePoint{Latitude: *booking.Latitude, Longitude: *booking.Longitude},
+    ) * 1000
+    if distance > radius {
+        return nil, fmt.Errorf("check-in not allowed: walker %s is %.0fm from the pickup, further than the %.0fm allowed",
+            walkerID, distance, radius)
+    }
+
+    booking, err = repository.StartShift(ctx, &models.WalkerShift{
+        BookingID:       bookingID,
+        WalkerID:        walkerID,
+        CheckedInAt:     clock.FromContext(ctx).Now(),
+        CheckInDistance: &distance,
+    })
+    if errors.Is(err, repository.ErrNotAwaitingCheckIn) {
+        if started := repeatedTransition(ctx, bookingID, models.BookingStatusInProgress, walkerID); started != nil {
+            return started, nil
+        }
+        return nil, fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    log.Printf("Walker %s checked in to booking %s %.0fm from the pickup", walkerID, bookingID, distance)
+    events.Publish(ctx, EventWalkerCheckedIn, map[string]interface{}{
+        "booking_id":          booking.ID,
+        "walker_id":           walkerID,
+        "check_in_distance_m": distance,
+    })
+    return booking, nil
+}
+
+// CheckInOverrideService checks a confirmed booking's walker in without verifying their
+// position, such as when their phone has lost its GPS fix. The override is written to the
+// audit log, and the shift records who made it.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func CheckInOverrideService(ctx context.Context, actorID, bookingID, reason string) (*models.Booking, error) {
+    booking, err := override(ctx, actorID, bookingID, models.AuditActionCheckIn, reason, 0, func(b *models.Booking) error {
+        if b.Status != models.BookingStatusConfirmed {
+            return fmt.Errorf("booking conflict: booking is %s, not confirmed", b.Status)
+        }
+        b.Status = models.BookingStatusInProgress
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    shift := &models.WalkerShift{
+        BookingID:    booking.ID,
+        WalkerID:     booking.WalkerID,
+        CheckedInAt:  clock.FromContext(ctx).Now(),
+        OverriddenBy: actorID,
+    }
+    if err := repository.SaveShift(ctx, shift); err != nil {
+        // The walk has started and the audit log holds the override, so only the shift is missing
+        log.Printf("Failed to record overridden check-in to booking %s: %v", booking.ID, err)
+    }
+
+    events.Publish(ctx, EventWalkerCheckedIn, map[string]interface{}{
+        "booking_id":    booking.ID,
+        "walker_id":     booking.WalkerID,
+        "overridden_by": actorID,
+    })
+    return booking, nil
+}
+
+// CheckOutService completes an in-progress walk as its walker checks out, issuing the owner's
+// receipt and letting them know the walk is over. When config.Config.Completion requires proof
+// of the walk, the tracking-service must have tracked enough of it first. Admins forcing a
+// booking to completed are not held to the requirements. Checking out of a completed walk again
+// returns the booking unchanged.
+func CheckOutService(ctx context.Context, bookingID, walkerID string) (*models.Booking, error) {
+    if walkerID == "" {
+        return nil, fmt.Errorf("invalid check-out: walker ID is required")
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if alreadyTransitioned(booking, models.BookingStatusCompleted, walkerID) {
+        return booking, nil
+    }
+    if booking.Status != models.BookingStatusInProgress || booking.WalkerID != walkerID {
+        return nil, fmt.Errorf("booking conflict: %w", repository.ErrNotAwaitingCheckOut)
+    }
+    if err := verifyCompletion(ctx, bookingID); err != nil {
+        return nil, err
+    }
+
+    booking, err = repository.EndShift(ctx, bookingID, walkerID, clock.FromContext(ctx).Now())
+    if errors.Is(err, repository.ErrNotAwaitingCheckOut) {
+        if completed := repeatedTransition(ctx, bookingID, models.BookingStatusCompleted, walkerID); completed != nil {
+            return completed, nil
+        }
+        return nil, fmt.Errorf("booking conflict: %w", err)
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    events.Publish(ctx, EventWalkerCheckedOut, map[string]interface{}{
+        "booking_id": booking.ID,
+        "walker_id":  walkerID,
+    })
+    // The owner's receipt is issued as the booking completes; reading it never issues it
+    if _, err := IssueReceiptService(ctx, booking.ID); err != nil {
+        log.Printf("Failed to issue receipt for booking %s: %v", booking.ID, err)
+    }
+    textOwner(ctx, booking, notifier.SMSWalkComplete, notifier.SMSData{})
+    return booking, nil
+}
+
+// verifyCompletion checks the tracking-service tracked the proof of a booking's walk that
+// config.Config.Completion requires, listing everything that falls short
+func verifyCompletion(ctx context.Context, bookingID string) error {
+    var required models.CompletionRequirements
+    if config.Config != nil {
+        required = config.Config.Completion
+    }
+    if !required.Any() {
+        return nil
+    }
+
+    walks := providersFrom(ctx).Walks
+    if walks == nil {
+        return fmt.Errorf("completion unavailable: no tracking service configured")
+    }
+    evidence, err := walks.WalkEvidence(ctx, bookingID)
+    if err != nil {
+        return fmt.Errorf("completion unavailable: %w", err)
+    }
+
+    var missing []string
+    if duration := evidence.Duration(); duration < required.MinDuration {
+        missing = append(missing, fmt.Sprintf("the walk lasted %s of the %s required",
+            duration.Round(time.Minute), required.MinDuration))
+    }
+    if evidence.DistanceMeters < required.MinDistance {
+        missing = append(missing, fmt.Sprintf("the walk covered %.0fm of the %.0fm required",
+            evidence.DistanceMeters, required.MinDistance))
+    }
+    if evidence.Photos < required.MinPhotos {
+        missing = append(missing, fmt.Sprintf("%d of the %d photos required were taken",
+            evidence.Photos, required.MinPhotos))
+    }
+    if len(missing) > 0 {
+        return fmt.Errorf("completion not allowed: %s", strings.Join(missing, "; "))
+    }
+    return nil
+}
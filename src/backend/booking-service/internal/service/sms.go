@@ -0,0 +1,200 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "net/url"
+    "regexp"
+    "strings"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// EventWalkerEnRoute is published when a walker sets off for a confirmed walk
+const EventWalkerEnRoute = "booking.walker_en_route"
+
+// maxEnRouteETA is the furthest away, in minutes, a walker can say they are
+const maxEnRouteETA = 180
+
+// e164 matches phone numbers in E.164 format
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// SMS reply keywords carriers require to opt a number out of, and back into, texts
+var (
+    smsStopKeywords  = map[string]bool{"STOP": true, "STOPALL": true, "UNSUBSCRIBE": true, "CANCEL": true, "END": true, "QUIT": true}
+    smsStartKeywords = map[string]bool{"START": true, "UNSTOP": true, "YES": true}
+)
+
+// GetSMSSettingsService returns the phone number userID is texted at and whether it has opted out
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func GetSMSSettingsService(ctx context.Context, userID string) (*models.SMSSettings, error) {
+    phone, err := repository.GetUserPhone(ctx, userID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get SMS settings: %w", err)
+    }
+    settings := &models.SMSSettings{Phone: phone}
+    if phone == "" {
+        return settings, nil
+    }
+
+    optOut, err := repository.GetSMSOptOut(ctx, phone)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get SMS settings: %w", err)
+    }
+    settings.OptedOut = optOut != nil
+    return settings, nil
+}
+
+// SaveSMSSettingsService sets the phone number userID is texted at and opts it out of, or
+// back into, texts
+func SaveSMSSettingsService(ctx context.Context, userID string, settings *models.SMSSettings) (*models.SMSSettings, error) {
+    if userID == "" {
+        return nil, fmt.Errorf("invalid SMS settings: user ID is required")
+    }
+    if !e164.MatchString(settings.Phone) {
+        return nil, fmt.Errorf("invalid SMS settings: phone must be in E.164 format, e.g. +447700900123")
+    }
+
+    now := clock.FromContext(ctx).Now().UTC()
+    if err := repository.SaveUserPhone(ctx, userID, settings.Phone, now); err != nil {
+        return nil, fmt.Errorf("failed to save SMS settings: %w", err)
+    }
+
+    var err error
+    if settings.OptedOut {
+        err = repository.SaveSMSOptOut(ctx, &models.SMSOptOut{
+            Phone:      settings.Phone,
+            Source:     models.SMSOptOutUser,
+            OptedOutAt: now,
+        })
+    } else {
+        err = repository.DeleteSMSOptOut(ctx, settings.Phone)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to save SMS settings: %w", err)
+    }
+    return settings, nil
+}
+
+// ReceiveSMSService applies a reply posted to the inbound message webhook with its form params
+// and signature: STOP and its synonyms opt the sender out, START opts them back in, and
+// anything else is ignored. It reports whether the reply changed the sender's opt-out.
+func ReceiveSMSService(ctx context.Context, params url.Values, signature string) (bool, error) {
+    opts := config.Config.SMS
+    if opts.WebhookURL == "" {
+        return false, fmt.Errorf("SMS replies unavailable: no webhook URL configured")
+    }
+    if !notifier.VerifyTwilioSignature(opts.AuthToken, opts.WebhookURL, params, signature) {
+        return false, fmt.Errorf("invalid SMS reply: signature does not match")
+    }
+    phone := params.Get("From")
+    if phone == "" {
+        return false, fmt.Errorf("invalid SMS reply: sender is required")
+    }
+
+    keyword := strings.ToUpper(strings.TrimSpace(params.Get("Body")))
+    switch {
+    case smsStopKeywords[keyword]:
+        err := repository.SaveSMSOptOut(ctx, &models.SMSOptOut{
+            Phone:      phone,
+            Source:     models.SMSOptOutReply,
+            OptedOutAt: clock.FromContext(ctx).Now().UTC(),
+        })
+        if err != nil {
+            return false, fmt.Errorf("failed to opt out of texts: %w", err)
+        }
+        return true, nil
+    case smsStartKeywords[keyword]:
+        if err := repository.DeleteSMSOptOut(ctx, phone); err != nil {
+            return false, fmt.Errorf("failed to opt in to texts: %w", err)
+        }
+        return true, nil
+    default:
+        return false, nil
+    }
+}
+
+// WalkerEnRouteService lets the owner of a confirmed booking know its walker is on the way,
+// arriving in about etaMinutes when that is known
+func WalkerEnRouteService(ctx context.Context, bookingID, walkerID string, etaMinutes int) (*models.Booking, error) {
+    if walkerID == "" {
+        return nil, fmt.Errorf("invalid en route update: walker ID is required")
+    }
+    if etaMinutes < 0 || etaMinutes > maxEnRouteETA {
+        return nil, fmt.Errorf("invalid en route update: ETA must be between 0 and %d minutes", maxEnRouteETA)
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.WalkerID != walkerID {
+        return nil, fmt.Errorf("invalid en route update: walker %s is not assigned to booking %s", walkerID, bookingID)
+    }
+    if booking.Status != models.BookingStatusConfirmed {
+        return nil, fmt.Errorf("booking conflict: booking is %s, not confirmed", booking.Status)
+    }
+
+    events.Publish(ctx, EventWalkerEnRoute, map[string]interface{}{
+        "booking_id":  booking.ID,
+        "walker_id":   walkerID,
+        "eta_minutes": etaMinutes,
+    })
+    textOwner(ctx, booking, notifier.SMSWalkerEnRoute, notifier.SMSData{ETAMinutes: etaMinutes})
+    return booking, nil
+}
+
+// textOwner texts the owner of a booking from an SMS template, unless they have given no phone
+// number or have opted out. Failures are logged, as texts only supplement push notifications.
+func textOwner(ctx context.Context, booking *models.Booking, template string, data notifier.SMSData) {
+    phone, err := repository.GetUserPhone(ctx, booking.OwnerID)
+    if err != nil {
+        log.Printf("Failed to get phone of owner of booking %s: %v", booking.ID, err)
+        return
+    }
+    if phone == "" {
+        return
+    }
+    optOut, err := repository.GetSMSOptOut(ctx, phone)
+    if err != nil {
+        log.Printf("Failed to check SMS opt-out of owner of booking %s: %v", booking.ID, err)
+        return
+    }
+    if optOut != nil {
+        return
+    }
+
+    if data.Locale == "" {
+        data.Locale = userLocale(ctx, booking.OwnerID)
+    }
+    if data.Time == "" {
+        data.Time = i18n.FormatTimeShort(data.Locale, booking.ScheduledAt.UTC())
+    }
+    body, err := notifier.RenderSMS(template, data)
+    if err != nil {
+        log.Printf("Failed to render %s text for booking %s: %v", template, booking.ID, err)
+        return
+    }
+
+    err = providersFrom(ctx).Texts.Text(ctx, phone, body)
+    if errors.Is(err, notifier.ErrOptedOut) {
+        // The owner replied STOP to the provider directly; remember it so they are not texted again
+        err = repository.SaveSMSOptOut(ctx, &models.SMSOptOut{
+            Phone:      phone,
+            Source:     models.SMSOptOutCarrier,
+            OptedOutAt: clock.FromContext(ctx).Now().UTC(),
+        })
+    }
+    if err != nil {
+        log.Printf("Failed to text owner of booking %s: %v", booking.ID, err)
+    }
+}
@@ -0,0 +1,91 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "fmt"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/regions"
+)
+
+const (
+    // surgePresenceHorizon is how soon a walk must start for walkers' tracked positions to
+    // count; later walks leave walkers time to get to the region
+    surgePresenceHorizon = time.Hour
+
+    // surgePresenceMaxAge is how recently a walker must have been tracked for their position
+    // to tell where they are
+    surgePresenceMaxAge = 15 * time.Minute
+)
+
+// surgeMultiplier works out the price multiplier of a walk in region starting at scheduledAt,
+// from the dogs booked in the region during that hour, counting the walk being priced, against
+// the dogs walkers' availability windows there accept. For walks starting within
+// surgePresenceHorizon of now, walkers the tracking-service has just seen in another region
+// are not counted as available.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func surgeMultiplier(ctx context.Context, region string, scheduledAt, now time.Time) (float64, error) {
+    options := config.Config.Surge
+    if !options.Enabled() {
+        return 1, nil
+    }
+
+    hour := scheduledAt.UTC().Truncate(time.Hour)
+    bookings, err := repository.ListActiveBookingsBetween(ctx, hour, hour.Add(time.Hour))
+    if err != nil {
+        return 0, fmt.Errorf("failed to price surge: %w", err)
+    }
+    demand := 1
+    for _, booking := range bookings {
+        if booking.Region == region {
+            demand++
+        }
+    }
+
+    windows, err := repository.ListAvailabilityBetween(ctx, hour, hour.Add(time.Hour))
+    if err != nil {
+        return 0, fmt.Errorf("failed to price surge: %w", err)
+    }
+    capacity := make(map[string]int)
+    for _, window := range windows {
+        if window.Region == region {
+            capacity[window.WalkerID] += window.Capacity
+        }
+    }
+    if scheduledAt.Before(now.Add(surgePresenceHorizon)) {
+        dropWalkersElsewhere(ctx, region, capacity, now)
+    }
+    supply := 0
+    for _, dogs := range capacity {
+        supply += dogs
+    }
+
+    return options.Multiplier(demand, supply), nil
+}
+
+// dropWalkersElsewhere removes from capacity the walkers the tracking-service saw in a region
+// other than region within surgePresenceMaxAge of now. Walkers it has not seen, or cannot say
+// where they are, stay counted.
+func dropWalkersElsewhere(ctx context.Context, region string, capacity map[string]int, now time.Time) {
+    locator := providersFrom(ctx).Locator
+    if locator == nil || len(capacity) == 0 {
+        return
+    }
+    list, err := repository.ListRegions(ctx)
+    if err != nil || len(list) == 0 {
+        return
+    }
+    set := regions.NewSet(list)
+    for walkerID := range capacity {
+        position, err := locator.LastPosition(ctx, walkerID)
+        if err != nil || now.Sub(position.UpdatedAt) > surgePresenceMaxAge {
+            continue
+        }
+        if seen := set.Locate(position.Latitude, position.Longitude); seen != "" && seen != region {
+            delete(capacity, walkerID)
+        }
+    }
+}
@@ -0,0 +1,151 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "sync"
+
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// EventBookingNeedsReassignment is published when a confirmed booking loses its walker and
+// waits for the matching engine to find another
+const EventBookingNeedsReassignment = "booking.needs_reassignment"
+
+// Walker suspension saga steps; the owner is told with stepNotifyOwner
+const (
+    stepReleaseWalker  = "release_walker"
+    stepReassignWalker = "reassign_walker"
+)
+
+var subscribeOnce sync.Once
+
+// SubscribeEvents registers the booking-service's reactions to its own events. It is safe to
+// call more than once; handlers are only registered the first time.
+// Addresses requirement: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func SubscribeEvents() {
+    subscribeOnce.Do(func() {
+        events.Subscribe(EventWalkerVerificationChanged, onWalkerVerificationChanged)
+    })
+}
+
+// onWalkerVerificationChanged starts the suspension cascade when a walker is suspended
+func onWalkerVerificationChanged(ctx context.Context, event events.Event) {
+    change, ok := event.Data.(walkerVerificationChange)
+    if !ok || change.Status != models.WalkerSuspended {
+        return
+    }
+    cascadeWalkerSuspension(ctx, change.WalkerID)
+}
+
+// cascadeWalkerSuspension moves each of a suspended walker's upcoming confirmed bookings to
+// another walker, one saga per booking. Every saga is saved before any runs, so bookings the
+// request does not get through are picked up by the background jobs.
+func cascadeWalkerSuspension(ctx context.Context, walkerID string) {
+    bookings, err := repository.ListUpcomingWalkerBookings(ctx, walkerID, models.BookingStatusConfirmed, clock.FromContext(ctx).Now())
+    if err != nil {
+        log.Printf("Failed to list bookings of suspended walker %s: %v", walkerID, err)
+        return
+    }
+
+    var sagas []*models.Saga
+    for i := range bookings {
+        saga, err := newSaga(ctx, models.SagaKindWalkerSuspension, &bookings[i], walkerID)
+        if err != nil {
+            log.Printf("Failed to start reassignment of booking %s: %v", bookings[i].ID, err)
+            continue
+        }
+        if err := repository.SaveSaga(ctx, saga); err != nil {
+            log.Printf("Failed to start reassignment of booking %s: %v", bookings[i].ID, err)
+            continue
+        }
+        sagas = append(sagas, saga)
+    }
+    log.Printf("Reassigning %d bookings of suspended walker %s", len(sagas), walkerID)
+
+    for _, saga := range sagas {
+        if err := runSaga(ctx, saga); err != nil {
+            log.Printf("Failed to reassign booking %s of suspended walker %s: %v", saga.BookingID, walkerID, err)
+        }
+    }
+}
+
+// releaseWalkerStep takes the booking from the suspended walker, leaving it waiting for
+// reassignment. A booking no longer confirmed with them, such as one the owner cancelled,
+// is left alone, unless a retried step finds the interrupted attempt released it.
+func releaseWalkerStep(ctx context.Context, saga *models.Saga, step *models.SagaStep) error {
+    err := repository.ReleaseConfirmedWalker(ctx, saga.BookingID, saga.WalkerID, releaseSuspended)
+    if errors.Is(err, repository.ErrNotConfirmed) {
+        if step.Attempts > 1 {
+            booking, getErr := GetBookingService(ctx, saga.BookingID)
+            if getErr == nil && booking.Status == models.BookingStatusNeedsReassignment {
+                return nil
+            }
+        }
+        return errStepSkipped
+    }
+    if err != nil {
+        return fmt.Errorf("failed to release walker: %w", err)
+    }
+    return nil
+}
+
+// notifyReassignmentStep announces the booking needs a walker, removes it from the suspended
+// walker's calendars and tells the owner another walker is being found
+func notifyReassignmentStep(ctx context.Context, saga *models.Saga, step *models.SagaStep) error {
+    booking, err := reassignableBooking(ctx, saga)
+    if err != nil {
+        return err
+    }
+
+    events.Publish(ctx, EventBookingNeedsReassignment, booking)
+    syncBookingCalendars(booking)
+
+    locale := userLocale(ctx, booking.OwnerID)
+    err = providersFrom(ctx).Notifier.Notify(ctx, booking.OwnerID, notifier.Notification{
+        Subject:  i18n.T(locale, "notify.walker_unavailable.subject"),
+        Body:     i18n.T(locale, "notify.walker_unavailable.body", i18n.FormatTime(locale, booking.ScheduledAt.UTC())),
+        Category: notifier.CategoryBookings,
+        Data: map[string]string{
+            "event":      EventBookingNeedsReassignment,
+            "booking_id": booking.ID,
+        },
+    })
+    if err != nil {
+        log.Printf("Failed to notify owner of booking %s needing reassignment: %v", booking.ID, err)
+    }
+    return nil
+}
+
+// reassignWalkerStep offers the booking to the matching engine. When no walker is available
+// the step is retried by the background jobs, and after its last attempt the booking is left
+// waiting for an admin to assign it.
+func reassignWalkerStep(ctx context.Context, saga *models.Saga, step *models.SagaStep) error {
+    booking, err := reassignableBooking(ctx, saga)
+    if err != nil {
+        return err
+    }
+    _, err = AssignWalkerService(ctx, booking.ID, "")
+    return err
+}
+
+// reassignableBooking returns the saga's booking while it is still waiting for reassignment,
+// and errStepSkipped once it is not
+func reassignableBooking(ctx context.Context, saga *models.Saga) (*models.Booking, error) {
+    booking, err := GetBookingService(ctx, saga.BookingID)
+    if err != nil {
+        return nil, err
+    }
+    if booking.Status != models.BookingStatusNeedsReassignment {
+        return nil, errStepSkipped
+    }
+    return booking, nil
+}
@@ -0,0 +1,138 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "math"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// EventTipAdded is published when an owner's tip has been charged
+const EventTipAdded = "booking.tip_added"
+
+// AddTipService records an owner's tip for the walker of a completed booking and requests
+// its payment. Tips are accepted until config.Config.TipWindow after the walk ends. The
+// returned charge carries the client secret the owner's app confirms the payment with.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func AddTipService(ctx context.Context, bookingID, ownerID string, amount float64) (*models.Tip, *payments.ChargeResult, error) {
+    amount = math.Round(amount*100) / 100
+    if math.IsNaN(amount) || math.IsInf(amount, 0) || amount <= 0 {
+        return nil, nil, fmt.Errorf("invalid tip: amount must be positive")
+    }
+    if ownerID == "" {
+        return nil, nil, fmt.Errorf("invalid tip: owner ID is required")
+    }
+
+    charger := providersFrom(ctx).Charges
+    if charger == nil {
+        return nil, nil, fmt.Errorf("tips unavailable: no payment service configured")
+    }
+
+    booking, err := GetBookingService(ctx, bookingID)
+    if err != nil {
+        return nil, nil, err
+    }
+    if booking.OwnerID != ownerID {
+        return nil, nil, fmt.Errorf("tip not allowed: only the booking's owner can tip")
+    }
+    if booking.Status != models.BookingStatusCompleted {
+        return nil, nil, fmt.Errorf("tip not allowed: booking is %s", booking.Status)
+    }
+    now := clock.FromContext(ctx).Now()
+    if closes := booking.EndsAt().Add(config.Config.TipWindow); now.After(closes) {
+        return nil, nil, fmt.Errorf("tip not allowed: tipping closed at %s", closes.UTC().Format(time.RFC3339))
+    }
+
+    id, err := newID()
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to generate tip ID: %w", err)
+    }
+    tip := &models.Tip{
+        ID:        id,
+        BookingID: booking.ID,
+        OwnerID:   ownerID,
+        WalkerID:  booking.WalkerID,
+        Amount:    amount,
+        Status:    models.TipStatusPending,
+        CreatedAt: now,
+    }
+    err = repository.CreateTip(ctx, tip)
+    if errors.Is(err, repository.ErrAlreadyTipped) {
+        return nil, nil, fmt.Errorf("tip conflict: %w", err)
+    }
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to record tip: %w", err)
+    }
+
+    // The tip is recorded before it is charged, so a payment never exists without its tip
+    charge, err := charger.Charge(ctx, payments.Charge{
+        ID:          tip.ID,
+        UserID:      ownerID,
+        BookingID:   booking.ID,
+        Kind:        payments.KindTip,
+        AmountCents: models.AmountCents(amount),
+        Currency:    models.BookingCurrency,
+    })
+    if err != nil {
+        tip.Status = models.TipStatusFailed
+        if updateErr := repository.UpdateTipPayment(ctx, tip.ID, tip.Status, ""); updateErr != nil {
+            log.Printf("Failed to mark tip %s as failed: %v", tip.ID, updateErr)
+        }
+        return nil, nil, fmt.Errorf("failed to charge tip: %w", err)
+    }
+
+    tip.Status = models.TipStatusCharged
+    tip.PaymentID = charge.PaymentID
+    if err := repository.UpdateTipPayment(ctx, tip.ID, tip.Status, tip.PaymentID); err != nil {
+        // The payment exists, so the tip is still returned; it stays pending until support resolves it
+        log.Printf("Failed to record payment %s for tip %s: %v", charge.PaymentID, tip.ID, err)
+    }
+
+    events.Publish(ctx, EventTipAdded, tip)
+    notifyTippedWalker(ctx, tip)
+    return tip, charge, nil
+}
+
+// WalkerEarningsReportService sums walkers' completed bookings and tips over [from, to). An
+// empty walkerID reports every walker; a non-empty region limits the report to walks there.
+func WalkerEarningsReportService(ctx context.Context, from, to time.Time, walkerID, region string) ([]models.WalkerEarnings, error) {
+    if !from.Before(to) {
+        return nil, fmt.Errorf("invalid report range: from must be before to")
+    }
+
+    earnings, err := repository.GetWalkerEarnings(ctx, from, to, walkerID, NormalizeRegion(region))
+    if err != nil {
+        return nil, fmt.Errorf("failed to build earnings report: %w", err)
+    }
+    return earnings, nil
+}
+
+// notifyTippedWalker tells the walker about a tip; failures are only logged
+func notifyTippedWalker(ctx context.Context, tip *models.Tip) {
+    locale := userLocale(ctx, tip.WalkerID)
+    err := providersFrom(ctx).Notifier.Notify(ctx, tip.WalkerID, notifier.Notification{
+        Subject:  i18n.T(locale, "notify.tip_received.subject"),
+        Body:     i18n.T(locale, "notify.tip_received.body", tip.Amount, strings.ToUpper(models.BookingCurrency)),
+        Category: notifier.CategoryPayments,
+        Data: map[string]string{
+            "event":      EventTipAdded,
+            "booking_id": tip.BookingID,
+        },
+    })
+    if err != nil {
+        log.Printf("Failed to notify walker %s of tip %s: %v", tip.WalkerID, tip.ID, err)
+    }
+}
@@ -0,0 +1,88 @@
+// Package service implements the business logic for the Booking Service
+package service
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "time"
+
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/clock"
+)
+
+// EventWalkerVerificationChanged is published whenever a walker's verification status changes
+const EventWalkerVerificationChanged = "walker.verification_changed"
+
+// walkerVerificationChange is the payload of EventWalkerVerificationChanged
+type walkerVerificationChange struct {
+    models.WalkerVerification
+    PreviousStatus models.WalkerVerificationStatus `json:"previous_status"`
+}
+
+// GetWalkerVerificationService returns a walker's verification status
+func GetWalkerVerificationService(ctx context.Context, walkerID string) (*models.WalkerVerification, error) {
+    if walkerID == "" {
+        return nil, fmt.Errorf("invalid verification: walker ID is required")
+    }
+
+    verification, err := repository.GetWalkerVerification(ctx, walkerID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to retrieve walker verification: %w", err)
+    }
+    return verification, nil
+}
+
+// SetWalkerVerificationService changes a walker's verification status on behalf of an admin
+// and publishes the change. Suspending a walker requires a reason.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func SetWalkerVerificationService(ctx context.Context, actorID, walkerID string, status models.WalkerVerificationStatus, reason string) (*models.WalkerVerification, error) {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+
+    reason = strings.TrimSpace(reason)
+    if walkerID == "" {
+        return nil, fmt.Errorf("invalid verification: walker ID is required")
+    }
+    if !status.IsValid() {
+        return nil, fmt.Errorf("invalid verification: unknown status %q", status)
+    }
+    if status == models.WalkerSuspended && reason == "" {
+        return nil, fmt.Errorf("invalid verification: a reason is required to suspend a walker")
+    }
+
+    verification := &models.WalkerVerification{
+        WalkerID:  walkerID,
+        Status:    status,
+        Reason:    reason,
+        UpdatedBy: actorID,
+        UpdatedAt: clock.FromContext(ctx).Now(),
+    }
+    previous, err := repository.SetWalkerVerification(ctx, verification)
+    if err != nil {
+        return nil, fmt.Errorf("failed to update walker verification: %w", err)
+    }
+
+    if previous != status {
+        events.Publish(ctx, EventWalkerVerificationChanged, walkerVerificationChange{
+            WalkerVerification: *verification,
+            PreviousStatus:     previous,
+        })
+    }
+    return verification, nil
+}
+
+// requireEligibleWalker returns an error unless the walker's verification status allows them
+// to be booked or assigned
+func requireEligibleWalker(ctx context.Context, walkerID string) error {
+    verification, err := repository.GetWalkerVerification(ctx, walkerID)
+    if err != nil {
+        return fmt.Errorf("failed to check walker verification: %w", err)
+    }
+    if !verification.Status.CanTakeBookings() {
+        return fmt.Errorf("walker not eligible: walker %s is %s", walkerID, verification.Status)
+    }
+    return nil
+}
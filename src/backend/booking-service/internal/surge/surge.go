@@ -0,0 +1,62 @@
+// Package surge raises the price of walks in the region hours where more dogs are booked than
+// walkers are available to take
+package surge
+
+import (
+    "fmt"
+    "math"
+)
+
+// Human Tasks:
+// 1. Tune BOOKING_SURGE_THRESHOLD and BOOKING_SURGE_SENSITIVITY against the capacity report, so
+//    prices only rise in the hours that are actually short of walkers
+// 2. Set BOOKING_SURGE_MAX to 1 to turn surge pricing off
+
+// step is the granularity of multipliers, so prices move in steps owners can make sense of
+const step = 0.1
+
+// Options tune how demand raises prices
+type Options struct {
+    // Threshold is the ratio of booked to available dogs above which prices rise, e.g. 1 for
+    // once every available walker's capacity is booked
+    Threshold float64
+
+    // Sensitivity is how much the multiplier rises for each unit the ratio exceeds the threshold
+    Sensitivity float64
+
+    // Max caps the multiplier; surge pricing is off when it is 1 or less
+    Max float64
+}
+
+// Enabled reports whether surge pricing is on
+func (o Options) Enabled() bool {
+    return o.Max > 1
+}
+
+// Validate checks the options are sensible
+func (o Options) Validate() error {
+    if o.Threshold < 0 || o.Sensitivity < 0 {
+        return fmt.Errorf("surge threshold and sensitivity must be non-negative")
+    }
+    return nil
+}
+
+// Multiplier returns the price multiplier of a region hour in which demand dogs are booked and
+// walkers are available to take supply dogs. It is 1 while the hour is covered, rises in steps
+// of a tenth with the shortfall, and is Max when nobody is available.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func (o Options) Multiplier(demand, supply int) float64 {
+    if !o.Enabled() || demand <= 0 {
+        return 1
+    }
+    if supply <= 0 {
+        return o.Max
+    }
+    ratio := float64(demand) / float64(supply)
+    if ratio <= o.Threshold {
+        return 1
+    }
+    // Round down to the step, allowing for floating point error on exact steps
+    multiplier := math.Floor((1+(ratio-o.Threshold)*o.Sensitivity)/step+1e-9) * step
+    return math.Round(math.Min(multiplier, o.Max)*100) / 100
+}
@@ -0,0 +1,87 @@
+// Package tax calculates the sales tax charged on bookings
+package tax
+
+import (
+    "context"
+    "fmt"
+    "math"
+    "strconv"
+    "strings"
+
+    "src/backend/booking-service/internal/models"
+)
+
+// Human Tasks:
+// 1. Set BOOKING_TAX_RATES to the rates the finance team has confirmed for each jurisdiction
+// 2. Rates apply to bookings priced after a restart; existing bookings keep the tax they were charged
+
+// Calculator works out the tax due on a priced booking. A provider such as Avalara, which
+// resolves rates from the walk's location, can replace the flat-rate calculator.
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+type Calculator interface {
+    // Calculate returns the tax due on booking.Amount, or nil when none is due
+    Calculate(ctx context.Context, booking *models.Booking) (*models.TaxBreakdown, error)
+}
+
+// Default is the process-wide calculator, set by Init
+var Default Calculator = FlatRate{}
+
+// Init selects the calculator; rates are applied as flat rates to every booking
+func Init(rates []Rate) {
+    Default = FlatRate{Rates: rates}
+}
+
+// Rate is a named flat tax rate
+type Rate struct {
+    Name    string
+    Percent float64
+}
+
+// FlatRate applies the same rates to every booking, whatever its location. Without rates no
+// tax is charged.
+type FlatRate struct {
+    Rates []Rate
+}
+
+// Calculate implements Calculator
+func (f FlatRate) Calculate(ctx context.Context, booking *models.Booking) (*models.TaxBreakdown, error) {
+    if len(f.Rates) == 0 {
+        return nil, nil
+    }
+
+    breakdown := &models.TaxBreakdown{Provider: "flat_rate"}
+    for _, rate := range f.Rates {
+        // Each line is rounded to cents on its own, as it would be on an invoice
+        amount := math.Round(booking.Amount*rate.Percent) / 100
+        breakdown.Lines = append(breakdown.Lines, models.TaxLine{
+            Name:        rate.Name,
+            RatePercent: rate.Percent,
+            Amount:      amount,
+        })
+        breakdown.Total += amount
+    }
+    breakdown.Total = math.Round(breakdown.Total*100) / 100
+    return breakdown, nil
+}
+
+// ParseRates parses a comma-separated list of name=percent pairs, such as
+// "State sales tax=6.25,City tax=1.5"
+func ParseRates(s string) ([]Rate, error) {
+    var rates []Rate
+    for _, pair := range strings.Split(s, ",") {
+        if strings.TrimSpace(pair) == "" {
+            continue
+        }
+        name, percent, ok := strings.Cut(pair, "=")
+        name = strings.TrimSpace(name)
+        if !ok || name == "" {
+            return nil, fmt.Errorf("invalid tax rate %q: expected name=percent", pair)
+        }
+        value, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+        if err != nil || value < 0 || value > 100 || math.IsNaN(value) {
+            return nil, fmt.Errorf("invalid tax rate %q: percent must be between 0 and 100", pair)
+        }
+        rates = append(rates, Rate{Name: name, Percent: value})
+    }
+    return rates, nil
+}
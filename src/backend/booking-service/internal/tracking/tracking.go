@@ -0,0 +1,93 @@
+// Package tracking reads where walkers were last seen, and what was tracked of their walks,
+// from the tracking-service
+package tracking
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "src/backend/shared/clients"
+)
+
+// Human Tasks:
+// 1. Set BOOKING_TRACKING_URL to the tracking-service base URL; walkers cannot check in to walks
+//    while it is unset, and support has to check them in instead, and walks cannot be completed
+//    while any proof of completion is required. Set BOOKING_TRACKING_API_KEY to the
+//    tracking-service's TRACKING_SERVICE_API_KEY.
+// 2. Configure network policies allowing booking-service to reach tracking-service
+
+// ErrNoPosition is returned when a walker has not been seen during any walk
+var ErrNoPosition = errors.New("walker has no tracked position")
+
+// Position is where a walker was last seen during a walk
+type Position = clients.WalkerPosition
+
+// Locator finds walkers' last tracked positions
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type Locator interface {
+    LastPosition(ctx context.Context, walkerID string) (*Position, error)
+}
+
+// WalkEvidence is what was tracked of a booking's walks
+type WalkEvidence = clients.WalkEvidence
+
+// Walks reads what was tracked of bookings' walks
+type Walks interface {
+    WalkEvidence(ctx context.Context, bookingID string) (*WalkEvidence, error)
+}
+
+// Process-wide locator and walk reader, set by Init; nil when no tracking-service is configured
+var (
+    Default  Locator
+    Evidence Walks
+)
+
+// Init selects the locator and walk reader: both read from the tracking-service at baseURL,
+// authenticated with its service API key, when set, otherwise both are nil and no walker can be
+// located nor walk checked
+func Init(baseURL, apiKey string) {
+    if baseURL == "" {
+        Default = nil
+        Evidence = nil
+        return
+    }
+    client := NewHTTPLocator(baseURL, apiKey)
+    Default = client
+    Evidence = client
+}
+
+// HTTPLocator reads positions and walk evidence from the tracking-service
+type HTTPLocator struct {
+    client *clients.TrackingClient
+}
+
+// NewHTTPLocator creates a locator for the tracking-service at baseURL
+func NewHTTPLocator(baseURL, apiKey string) *HTTPLocator {
+    return &HTTPLocator{
+        // Walkers wait on the answer at the door, so a slow tracking-service fails fast
+        client: clients.NewTrackingClient(baseURL, clients.Options{Timeout: 5 * time.Second, APIKey: apiKey}),
+    }
+}
+
+// LastPosition implements Locator
+func (l *HTTPLocator) LastPosition(ctx context.Context, walkerID string) (*Position, error) {
+    position, err := l.client.WalkerPosition(ctx, walkerID)
+    if errors.Is(err, clients.ErrNotFound) {
+        return nil, ErrNoPosition
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch walker position: %w", err)
+    }
+    return position, nil
+}
+
+// WalkEvidence implements Walks
+func (l *HTTPLocator) WalkEvidence(ctx context.Context, bookingID string) (*WalkEvidence, error) {
+    evidence, err := l.client.WalkEvidence(ctx, bookingID)
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch walk evidence: %w", err)
+    }
+    return evidence, nil
+}
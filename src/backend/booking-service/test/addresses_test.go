@@ -0,0 +1,107 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/geocoding"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// fakeGeocoder places the addresses it knows by their first line, and fails on any other
+type fakeGeocoder struct {
+    places map[string][2]float64
+    calls  int
+}
+
+func (f *fakeGeocoder) Geocode(ctx context.Context, address models.Address) (float64, float64, error) {
+    f.calls++
+    if address.Line1 == "unreachable" {
+        return 0, 0, errors.New("geocoder unavailable")
+    }
+    place, ok := f.places[address.Line1]
+    if !ok {
+        return 0, 0, geocoding.ErrAddressNotFound
+    }
+    return place[0], place[1], nil
+}
+
+// TestMemoryStoreBookingAddress verifies pickup addresses are placed by the geocoder when
+// booked without coordinates, and placed again when patched
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func TestMemoryStoreBookingAddress(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    geocoder := &fakeGeocoder{places: map[string][2]float64{
+        "221B Baker Street": {51.5237, -0.1585},
+        "10 Downing Street": {51.5034, -0.1276},
+    }}
+    ctx = service.WithProviders(ctx, service.Providers{Geocoder: geocoder})
+
+    start := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+    address := func(line1 string) *models.Address {
+        return &models.Address{Line1: line1, City: "London", PostalCode: "NW1 6XE", Country: "GB"}
+    }
+
+    booking := memoryBooking("address-geocoded", "", start)
+    booking.Address = address("221B Baker Street")
+    require.NoError(t, service.CreateBookingService(ctx, booking))
+    require.NotNil(t, booking.Latitude)
+    assert.Equal(t, 51.5237, *booking.Latitude)
+    assert.Equal(t, -0.1585, *booking.Longitude)
+    stored, err := repository.GetBookingByID(ctx, booking.ID)
+    require.NoError(t, err)
+    require.NotNil(t, stored.Address)
+    assert.Equal(t, 51.5237, *stored.Address.Latitude)
+
+    // Coordinates from the owner's app are kept without asking the geocoder
+    calls := geocoder.calls
+    supplied := memoryBooking("address-supplied", "", start)
+    latitude, longitude := 51.5, -0.12
+    supplied.Latitude, supplied.Longitude = &latitude, &longitude
+    supplied.Address = address("221B Baker Street")
+    require.NoError(t, service.CreateBookingService(ctx, supplied))
+    assert.Equal(t, calls, geocoder.calls)
+    assert.Equal(t, 51.5, *supplied.Address.Latitude)
+
+    incomplete := memoryBooking("address-incomplete", "", start)
+    incomplete.Address = &models.Address{Line1: "221B Baker Street"}
+    err = service.CreateBookingService(ctx, incomplete)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid booking data")
+
+    unknown := memoryBooking("address-unknown", "", start)
+    unknown.Address = address("1 Nowhere Lane")
+    err = service.CreateBookingService(ctx, unknown)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "pickup address could not be found")
+
+    // A geocoder outage does not stop the booking; it is only left without coordinates
+    unplaced := memoryBooking("address-unplaced", "", start)
+    unplaced.Address = address("unreachable")
+    require.NoError(t, service.CreateBookingService(ctx, unplaced))
+    assert.Nil(t, unplaced.Latitude)
+
+    // Moving the pickup places the new address
+    patched, err := service.PatchBookingService(ctx, booking.ID, booking.OwnerID,
+        []byte(`{"address": {"line1": "10 Downing Street", "city": "London", "postal_code": "SW1A 2AA"}}`))
+    require.NoError(t, err)
+    require.NotNil(t, patched.Latitude)
+    assert.Equal(t, 51.5034, *patched.Latitude)
+    assert.Equal(t, 51.5034, *patched.Address.Latitude)
+}
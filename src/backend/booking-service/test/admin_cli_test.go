@@ -0,0 +1,101 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/spf13/cobra"              // v1.6.1
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/cli"
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// runCommand runs an admin CLI command with args and returns what it printed
+func runCommand(cmd *cobra.Command, args ...string) (string, error) {
+    var out bytes.Buffer
+    cmd.SetArgs(args)
+    cmd.SetOut(&out)
+    cmd.SetErr(&out)
+    cmd.SilenceUsage = true
+    err := cmd.ExecuteContext(context.Background())
+    return out.String(), err
+}
+
+// TestAdminCLIListBookings verifies list-bookings filters bookings, lists the latest first and
+// prints them as a table or as JSON
+func TestAdminCLIListBookings(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    start := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+    for i, id := range []string{"cli-early", "cli-late", "cli-other"} {
+        booking := memoryBooking(id, "walker-cli", start.Add(time.Duration(i)*time.Hour))
+        if id == "cli-other" {
+            booking.WalkerID = "walker-cli-other"
+        }
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+
+    out, err := runCommand(cli.ListBookingsCommand(), "--walker", "walker-cli")
+    require.NoError(t, err)
+    lines := strings.Split(strings.TrimSpace(out), "\n")
+    require.Len(t, lines, 3, out)
+    assert.True(t, strings.HasPrefix(lines[0], "ID"))
+    assert.True(t, strings.HasPrefix(lines[1], "cli-late"), "the latest booking comes first")
+    assert.True(t, strings.HasPrefix(lines[2], "cli-early"))
+
+    out, err = runCommand(cli.ListBookingsCommand(), "--json", "--limit", "1")
+    require.NoError(t, err)
+    var listed []models.Booking
+    require.NoError(t, json.Unmarshal([]byte(out), &listed))
+    require.Len(t, listed, 1)
+    assert.Equal(t, "cli-other", listed[0].ID)
+
+    _, err = runCommand(cli.ListBookingsCommand(), "--status", "lost")
+    assert.Error(t, err)
+    _, err = runCommand(cli.ListBookingsCommand(), "--limit", "0")
+    assert.Error(t, err)
+}
+
+// TestAdminCLIForceStatus verifies force-status needs a reason, changes the booking's status
+// and records the operator in the audit log
+func TestAdminCLIForceStatus(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    useConfig(t, &config.Config{})
+
+    booking := memoryBooking("cli-force", "walker-cli", time.Now().Add(24*time.Hour))
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    _, err := runCommand(cli.ForceStatusCommand(), booking.ID, "cancelled")
+    assert.Error(t, err, "a reason is required")
+    _, err = runCommand(cli.ForceStatusCommand(), booking.ID, "lost", "--reason", "typo")
+    assert.Error(t, err)
+
+    out, err := runCommand(cli.ForceStatusCommand(), booking.ID, "cancelled", "--reason", "owner called", "--actor", "cli:ops")
+    require.NoError(t, err)
+    assert.Contains(t, out, "Booking cli-force is now cancelled")
+
+    stored, err := repository.GetBookingByID(ctx, booking.ID)
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCancelled, stored.Status)
+
+    entries, err := service.BookingAuditService(ctx, booking.ID)
+    require.NoError(t, err)
+    require.Len(t, entries, 1)
+    assert.Equal(t, "cli:ops", entries[0].ActorID)
+    assert.Equal(t, "owner called", entries[0].Reason)
+}
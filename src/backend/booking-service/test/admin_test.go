@@ -0,0 +1,111 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+)
+
+// adminOverrides serves the admin override endpoints as cmd/server does, behind a user token
+func adminOverrides() http.HandlerFunc {
+    return middleware.RequirePermission(actionsSecret, policy.ResourceBookingOverrides, policy.ActionUpdate)(handlers.AdminBookingHandler)
+}
+
+// TestAdminOverridesAudited checks that each admin override changes the booking and records
+// who made it, why, and the booking before and after it, and that overrides without a
+// reason or from anyone but an admin change nothing
+func TestAdminOverridesAudited(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+        WalkerID:  "walker-audit-new",
+        Status:    models.WalkerVerified,
+        UpdatedBy: "admin-1",
+        UpdatedAt: time.Now(),
+    })
+    require.NoError(t, err)
+    booking := memoryBooking("audit-booking", "walker-audit", time.Now().Add(24*time.Hour))
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    admin := adminOverrides()
+    path := "/api/v1/admin/bookings/audit-booking/"
+
+    // Refused overrides are not applied, so they leave no entry
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, admin, http.MethodPost, path+"status", "", "", `{"status": "cancelled", "reason": "owner asked"}`).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, admin, http.MethodPost, path+"status", "owner-audit-booking", policy.RoleOwner, `{"status": "cancelled", "reason": "owner asked"}`).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, admin, http.MethodPost, path+"amount", "walker-audit", policy.RoleWalker, `{"amount": 100, "reason": "extra dog"}`).Code)
+    assert.Equal(t, http.StatusBadRequest, callAs(t, admin, http.MethodPost, path+"status", "admin-1", policy.RoleAdmin, `{"status": "cancelled", "reason": "  "}`).Code,
+        "a reason is required")
+    assert.Equal(t, http.StatusUnprocessableEntity, callAs(t, admin, http.MethodPost, path+"walker", "admin-1", policy.RoleAdmin, `{"walker_id": "walker-unverified", "reason": "cover"}`).Code)
+    entries, err := service.BookingAuditService(ctx, booking.ID)
+    require.NoError(t, err)
+    assert.Empty(t, entries)
+
+    overrides := []struct {
+        action, body string
+        want         string
+    }{
+        {"amount", `{"amount": 30.005, "reason": "extra dog"}`, models.AuditActionAdjustAmount},
+        {"walker", `{"walker_id": "walker-audit-new", "reason": "walker ill"}`, models.AuditActionReassignWalker},
+        {"status", `{"status": "cancelled", "reason": "owner asked by phone"}`, models.AuditActionForceStatus},
+    }
+    for _, o := range overrides {
+        response := callAs(t, admin, http.MethodPost, path+o.action, "admin-1", policy.RoleAdmin, o.body)
+        require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    }
+
+    stored, err := repository.GetBookingByID(ctx, booking.ID)
+    require.NoError(t, err)
+    assert.Equal(t, 30.01, stored.Amount)
+    assert.Equal(t, "walker-audit-new", stored.WalkerID)
+    assert.Equal(t, models.BookingStatusCancelled, stored.Status)
+
+    response := callAs(t, admin, http.MethodGet, path+"audit", "admin-1", policy.RoleAdmin, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    var body struct {
+        Data []models.AuditEntry `json:"data"`
+    }
+    require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+    require.Len(t, body.Data, 3)
+
+    reasons := []string{"extra dog", "walker ill", "owner asked by phone"}
+    for i, entry := range body.Data {
+        assert.NotEmpty(t, entry.ID)
+        assert.Equal(t, overrides[i].want, entry.Action)
+        assert.Equal(t, "admin-1", entry.ActorID)
+        assert.Equal(t, booking.ID, entry.BookingID)
+        assert.Equal(t, reasons[i], entry.Reason)
+        assert.False(t, entry.CreatedAt.IsZero())
+    }
+
+    // Each entry holds the booking on both sides of its own change
+    var before, after models.Booking
+    require.NoError(t, json.Unmarshal(body.Data[1].Before, &before))
+    require.NoError(t, json.Unmarshal(body.Data[1].After, &after))
+    assert.Equal(t, "walker-audit", before.WalkerID)
+    assert.Equal(t, "walker-audit-new", after.WalkerID)
+    assert.Equal(t, 30.01, before.Amount)
+    assert.Equal(t, models.BookingStatusPending, after.Status)
+
+    assert.Equal(t, http.StatusNotFound, callAs(t, admin, http.MethodGet, "/api/v1/admin/bookings/no-such-booking/audit", "admin-1", policy.RoleAdmin, "").Code)
+}
@@ -0,0 +1,263 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// TestMemoryStoreAssignmentLifecycle verifies assign, decline and re-matching in memory
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreAssignmentLifecycle(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    start := time.Now().Add(24 * time.Hour)
+
+    // walker-3 is suspended, so the matching engine never proposes them
+    statuses := map[string]models.WalkerVerificationStatus{
+        "walker-1": models.WalkerVerified,
+        "walker-2": models.WalkerPending,
+        "walker-3": models.WalkerSuspended,
+    }
+    for walkerID, status := range statuses {
+        require.NoError(t, repository.CreateAvailability(ctx, &models.Availability{
+            ID:       "availability-" + walkerID,
+            WalkerID: walkerID,
+            StartsAt: start.Add(-time.Hour),
+            EndsAt:   start.Add(2 * time.Hour),
+            Capacity: 1,
+        }))
+        _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+            WalkerID:  walkerID,
+            Status:    status,
+            UpdatedBy: "admin-1",
+            UpdatedAt: time.Now(),
+        })
+        require.NoError(t, err)
+    }
+
+    booking := memoryBooking("booking-1", "", start)
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    walkers, err := repository.FindAvailableWalkers(ctx, booking.ID, start, booking.EndsAt(), 5)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"walker-1", "walker-2"}, walkers)
+
+    _, err = repository.AssignWalker(ctx, booking.ID, "walker-1", 1, time.Now().Add(time.Hour))
+    require.NoError(t, err)
+    require.NoError(t, repository.ReleaseAssignment(ctx, booking.ID, "walker-1", "declined"))
+    assert.ErrorIs(t, repository.ReleaseAssignment(ctx, booking.ID, "walker-1", "declined"), repository.ErrNoPendingAssignment)
+
+    walkers, err = repository.FindAvailableWalkers(ctx, booking.ID, start, booking.EndsAt(), 5)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"walker-2"}, walkers)
+
+    _, err = repository.AssignWalker(ctx, booking.ID, "walker-2", 1, time.Now().Add(time.Hour))
+    require.NoError(t, err)
+    require.NoError(t, repository.AcceptAssignment(ctx, booking.ID, "walker-2"))
+
+    stored, err := repository.GetBookingByID(ctx, booking.ID)
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusConfirmed, stored.Status)
+    assert.Nil(t, stored.AcceptBy)
+}
+
+// TestMatchingLeastBusyWalker verifies the matching engine assigns an unassigned booking to
+// the walker with the fewest dogs in its slot, giving them until the walk starts at the latest
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMatchingLeastBusyWalker(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{AssignmentAcceptWindow: time.Hour})
+
+    start := time.Now().Add(20 * time.Minute).Truncate(time.Minute)
+    for _, walkerID := range []string{"walker-busy", "walker-free"} {
+        require.NoError(t, repository.CreateAvailability(ctx, &models.Availability{
+            ID:       "availability-" + walkerID,
+            WalkerID: walkerID,
+            StartsAt: start.Add(-time.Hour),
+            EndsAt:   start.Add(2 * time.Hour),
+            Capacity: 2,
+        }))
+        _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+            WalkerID:  walkerID,
+            Status:    models.WalkerVerified,
+            UpdatedBy: "admin-1",
+            UpdatedAt: time.Now(),
+        })
+        require.NoError(t, err)
+    }
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("match-booked", "walker-busy", start)))
+
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("match-unassigned", "", start)))
+    assigned, err := service.AssignWalkerService(ctx, "match-unassigned", "")
+    require.NoError(t, err)
+    assert.Equal(t, "walker-free", assigned.WalkerID)
+    assert.Equal(t, models.BookingStatusPending, assigned.Status)
+    require.NotNil(t, assigned.AcceptBy)
+    assert.True(t, assigned.AcceptBy.Equal(start), "the walker must answer before the walk starts")
+
+    // Once both walkers are full nobody is left to match
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("match-busy-second", "walker-busy", start)))
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("match-free-second", "walker-free", start)))
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("match-late", "", start)))
+    _, err = service.AssignWalkerService(ctx, "match-late", "")
+    assert.ErrorIs(t, err, service.ErrNoWalkerAvailable)
+}
+
+// TestMemoryStoreWalkerVerificationGating verifies unverified and suspended walkers cannot be booked
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreWalkerVerificationGating(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    start := time.Now().Add(24 * time.Hour)
+
+    err := service.CreateBookingService(ctx, memoryBooking("booking-1", "walker-1", start))
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "walker not eligible")
+
+    _, err = service.SetWalkerVerificationService(ctx, "admin-1", "walker-1", models.WalkerSuspended, "")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid verification")
+
+    _, err = service.SetWalkerVerificationService(ctx, "admin-1", "walker-1", models.WalkerSuspended, "failed background check")
+    require.NoError(t, err)
+    verification, err := service.GetWalkerVerificationService(ctx, "walker-1")
+    require.NoError(t, err)
+    assert.Equal(t, models.WalkerSuspended, verification.Status)
+
+    err = service.CreateBookingService(ctx, memoryBooking("booking-2", "walker-1", start))
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "walker not eligible")
+
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("booking-3", "", start)))
+    _, err = service.AssignWalkerService(ctx, "booking-3", "walker-1")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "walker not eligible")
+}
+
+// TestMemoryStoreBulkStatus verifies a bulk status change cancels a walker's day and reports
+// each booking it matched
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreBulkStatus(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    day := time.Now().Add(48 * time.Hour).Truncate(24 * time.Hour)
+
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("bulk-pending", "walker-ill", day.Add(9*time.Hour))))
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("bulk-confirmed", "", day.Add(11*time.Hour))))
+    _, err := repository.AssignWalker(ctx, "bulk-confirmed", "walker-ill", 1, time.Now().Add(time.Hour))
+    require.NoError(t, err)
+    require.NoError(t, repository.AcceptAssignment(ctx, "bulk-confirmed", "walker-ill"))
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("bulk-completed", "walker-ill", day.Add(13*time.Hour))))
+    _, err = service.ForceStatusService(ctx, "admin-1", "bulk-completed", models.BookingStatusCompleted, "walked early")
+    require.NoError(t, err)
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("bulk-next-day", "walker-ill", day.Add(33*time.Hour))))
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("bulk-other-walker", "walker-well", day.Add(9*time.Hour))))
+
+    filter := models.BulkStatusFilter{WalkerID: "walker-ill", From: day, To: day.Add(24 * time.Hour)}
+    _, err = service.BulkStatusService(ctx, "admin-1", filter, models.BookingStatusCancelled, " ")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid override")
+
+    report, err := service.BulkStatusService(ctx, "admin-1", filter, models.BookingStatusCancelled, "walker is ill")
+    require.NoError(t, err)
+    assert.Equal(t, 2, report.Updated)
+    assert.Equal(t, 1, report.Skipped)
+    require.Len(t, report.Results, 3)
+    assert.Equal(t, "bulk-pending", report.Results[0].BookingID)
+    assert.Equal(t, models.BookingStatusPending, report.Results[0].PreviousStatus)
+    assert.Equal(t, models.BulkStatusUpdated, report.Results[0].Outcome)
+    assert.Equal(t, models.BookingStatusConfirmed, report.Results[1].PreviousStatus)
+    assert.Equal(t, models.BulkStatusSkipped, report.Results[2].Outcome)
+    assert.Equal(t, models.BookingStatusCompleted, report.Results[2].Status)
+
+    for id, want := range map[string]models.BookingStatus{
+        "bulk-pending":      models.BookingStatusCancelled,
+        "bulk-confirmed":    models.BookingStatusCancelled,
+        "bulk-completed":    models.BookingStatusCompleted,
+        "bulk-next-day":     models.BookingStatusPending,
+        "bulk-other-walker": models.BookingStatusPending,
+    } {
+        booking, err := service.GetBookingService(ctx, id)
+        require.NoError(t, err)
+        assert.Equal(t, want, booking.Status, id)
+    }
+
+    // Repeating the change is harmless: every booking is already cancelled or not selected
+    report, err = service.BulkStatusService(ctx, "admin-1", filter, models.BookingStatusCancelled, "walker is ill")
+    require.NoError(t, err)
+    assert.Equal(t, 0, report.Updated)
+    assert.Equal(t, 3, report.Skipped)
+}
+
+// TestMemoryStoreWalkerSuspensionCascade verifies suspending a walker moves their upcoming
+// confirmed bookings to other walkers, or leaves them waiting for reassignment
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreWalkerSuspensionCascade(t *testing.T) {
+    repository.UseMemoryStore()
+    service.SubscribeEvents()
+    ctx := context.Background()
+    start := time.Now().Add(24 * time.Hour)
+
+    useConfig(t, &config.Config{AssignmentAcceptWindow: time.Hour})
+
+    for _, walkerID := range []string{"walker-suspended", "walker-backup"} {
+        _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+            WalkerID:  walkerID,
+            Status:    models.WalkerVerified,
+            UpdatedBy: "admin-1",
+            UpdatedAt: time.Now(),
+        })
+        require.NoError(t, err)
+    }
+    // Only the first walk is covered by the backup walker's availability
+    require.NoError(t, repository.CreateAvailability(ctx, &models.Availability{
+        ID:       "availability-backup",
+        WalkerID: "walker-backup",
+        StartsAt: start.Add(-time.Hour),
+        EndsAt:   start.Add(time.Hour),
+        Capacity: 1,
+    }))
+
+    for i, id := range []string{"suspend-covered", "suspend-uncovered"} {
+        require.NoError(t, repository.CreateBooking(ctx, memoryBooking(id, "", start.Add(time.Duration(i)*3*time.Hour))))
+        _, err := repository.AssignWalker(ctx, id, "walker-suspended", 1, time.Now().Add(time.Hour))
+        require.NoError(t, err)
+        require.NoError(t, repository.AcceptAssignment(ctx, id, "walker-suspended"))
+    }
+
+    _, err := service.SetWalkerVerificationService(ctx, "admin-1", "walker-suspended", models.WalkerSuspended, "failed background check")
+    require.NoError(t, err)
+
+    covered, err := service.GetBookingService(ctx, "suspend-covered")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusPending, covered.Status)
+    assert.Equal(t, "walker-backup", covered.WalkerID)
+    assert.NotNil(t, covered.AcceptBy)
+
+    uncovered, err := service.GetBookingService(ctx, "suspend-uncovered")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusNeedsReassignment, uncovered.Status)
+    assert.Empty(t, uncovered.WalkerID)
+
+    // The owner of a booking that lost its walker may cancel it without a fee
+    cancelled, err := service.CancelBookingService(ctx, "suspend-uncovered", uncovered.OwnerID)
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCancelled, cancelled.Status)
+    require.NotNil(t, cancelled.Cancellation)
+    assert.Zero(t, cancelled.Cancellation.Fee)
+}
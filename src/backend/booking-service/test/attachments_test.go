@@ -0,0 +1,162 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "bytes"
+    "context"
+    "encoding/base64"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/golang-jwt/jwt/v4"        // v4.5.0
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/encryption"
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+)
+
+// TestMemoryStoreBookingAttachments verifies owners' attachments are stored encrypted, shown
+// to the walker only while the booking is active and purged once it has been finished long enough
+func TestMemoryStoreBookingAttachments(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    // Attachments are read with permission to read bookings and changed with permission to update them
+    const secret = "attachment-secret"
+    read := middleware.RequirePermission(secret, policy.ResourceBookings, policy.ActionRead)(handlers.BookingAttachmentHandler)
+    write := middleware.RequirePermission(secret, policy.ResourceBookings, policy.ActionUpdate)(handlers.BookingAttachmentHandler)
+    handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == http.MethodGet {
+            read(w, r)
+            return
+        }
+        write(w, r)
+    })
+    request := func(method, path, userID, role, body string) *httptest.ResponseRecorder {
+        request := httptest.NewRequest(method, path, strings.NewReader(body))
+        signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{ID: userID, Role: role}).SignedString([]byte(secret))
+        require.NoError(t, err)
+        request.Header.Set("Authorization", "Bearer "+signed)
+        response := httptest.NewRecorder()
+        handler.ServeHTTP(response, request)
+        return response
+    }
+
+    useConfig(t, &config.Config{AttachmentRetention: 7 * 24 * time.Hour})
+
+    start := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+    booking := memoryBooking("attachments", "walker-attachments", start)
+    booking.Status = models.BookingStatusConfirmed
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+    pending := memoryBooking("attachments-pending", "walker-attachments", start.Add(2*time.Hour))
+    pending.OwnerID = booking.OwnerID
+    require.NoError(t, repository.CreateBooking(ctx, pending))
+    path := "/api/v1/bookings/" + booking.ID + "/attachments"
+    gate := `{"kind": "access_instructions", "title": "Side gate", "text": "Code 4921, lock it behind you"}`
+
+    // Attachments are never stored in the clear, so they are unavailable without keys
+    repository.UseAttachmentEncryption(nil)
+    assert.Equal(t, http.StatusServiceUnavailable, request(http.MethodPost, path, booking.OwnerID, policy.RoleOwner, gate).Code)
+
+    key := bytes.Repeat([]byte{7}, 32)
+    keys, err := encryption.ParseKeyring("k1:"+base64.StdEncoding.EncodeToString(key), "k1")
+    require.NoError(t, err)
+    repository.UseAttachmentEncryption(keys)
+    t.Cleanup(func() { repository.UseAttachmentEncryption(nil) })
+
+    assert.Equal(t, http.StatusForbidden, request(http.MethodPost, path, booking.WalkerID, policy.RoleWalker, gate).Code)
+    assert.Equal(t, http.StatusForbidden, request(http.MethodPost, path, booking.OwnerID, policy.RoleClient, gate).Code,
+        "reading bookings does not allow uploading attachments")
+    assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, path, booking.OwnerID, policy.RoleOwner, `{"kind": "note"}`).Code)
+    assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, path, booking.OwnerID, policy.RoleOwner, `{"kind": "file", "file_name": "a.txt", "content_type": "text/plain"}`).Code)
+
+    response := request(http.MethodPost, path, booking.OwnerID, policy.RoleOwner, gate)
+    require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+    vet, err := service.AddBookingAttachmentService(ctx, booking.ID, booking.OwnerID, models.BookingAttachment{
+        Kind:   models.AttachmentKindVetContact,
+        Fields: map[string]string{"name": "Dr Rivera", "phone": "+15550100"},
+    })
+    require.NoError(t, err)
+    file, err := service.AddBookingAttachmentService(ctx, booking.ID, booking.OwnerID, models.BookingAttachment{
+        Kind:        models.AttachmentKindFile,
+        FileName:    "lockbox.txt",
+        ContentType: "text/plain",
+        Content:     []byte("lockbox under the mat"),
+    })
+    require.NoError(t, err)
+    assert.Equal(t, 21, file.Size)
+
+    // Another key cannot open what was sealed
+    other, err := encryption.ParseKeyring("k1:"+base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32)), "k1")
+    require.NoError(t, err)
+    repository.UseAttachmentEncryption(other)
+    _, err = repository.GetBookingAttachment(ctx, booking.ID, vet.ID)
+    assert.Error(t, err)
+    repository.UseAttachmentEncryption(keys)
+
+    // The walker cannot read attachments before the booking is confirmed
+    pendingPath := "/api/v1/bookings/" + pending.ID + "/attachments"
+    require.Equal(t, http.StatusCreated, request(http.MethodPost, pendingPath, booking.OwnerID, policy.RoleOwner, gate).Code)
+    assert.Equal(t, http.StatusOK, request(http.MethodGet, pendingPath, booking.OwnerID, policy.RoleOwner, "").Code)
+    assert.Equal(t, http.StatusForbidden, request(http.MethodGet, pendingPath, booking.WalkerID, policy.RoleWalker, "").Code)
+    assert.Equal(t, http.StatusForbidden, request(http.MethodGet, path, "stranger", policy.RoleOwner, "").Code)
+
+    response = request(http.MethodGet, path, booking.WalkerID, policy.RoleWalker, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    var listed struct {
+        Data []models.BookingAttachment `json:"data"`
+    }
+    require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listed))
+    require.Len(t, listed.Data, 3)
+    assert.Equal(t, "Code 4921, lock it behind you", listed.Data[0].Text)
+    assert.Equal(t, "+15550100", listed.Data[1].Fields["phone"])
+    assert.Empty(t, listed.Data[2].Content)
+
+    response = request(http.MethodGet, path+"/"+file.ID, booking.WalkerID, policy.RoleWalker, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    var fetched struct {
+        Data models.BookingAttachment `json:"data"`
+    }
+    require.NoError(t, json.Unmarshal(response.Body.Bytes(), &fetched))
+    assert.Equal(t, "lockbox under the mat", string(fetched.Data.Content))
+    assert.Equal(t, http.StatusNotFound, request(http.MethodGet, path+"/missing", booking.WalkerID, policy.RoleWalker, "").Code)
+
+    // Only the owner removes attachments
+    assert.Equal(t, http.StatusForbidden, request(http.MethodDelete, path+"/"+vet.ID, booking.OwnerID, policy.RoleClient, "").Code)
+    assert.Equal(t, http.StatusForbidden, request(http.MethodDelete, path+"/"+vet.ID, booking.WalkerID, policy.RoleWalker, "").Code)
+    assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, path+"/"+vet.ID, booking.OwnerID, policy.RoleOwner, "").Code)
+    assert.Equal(t, http.StatusNotFound, request(http.MethodDelete, path+"/"+vet.ID, booking.OwnerID, policy.RoleOwner, "").Code)
+
+    // Once the booking is over the walker loses access, and the attachments are purged after the retention
+    _, err = service.CancelBookingService(ctx, booking.ID, booking.OwnerID)
+    require.NoError(t, err)
+    assert.Equal(t, http.StatusForbidden, request(http.MethodGet, path, booking.WalkerID, policy.RoleWalker, "").Code)
+    assert.Equal(t, http.StatusConflict, request(http.MethodPost, path, booking.OwnerID, policy.RoleOwner, gate).Code)
+
+    deleted, err := repository.DeleteFinishedBookingAttachmentsBefore(ctx, booking.EndsAt())
+    require.NoError(t, err)
+    assert.Zero(t, deleted)
+    deleted, err = repository.DeleteFinishedBookingAttachmentsBefore(ctx, booking.EndsAt().Add(time.Minute))
+    require.NoError(t, err)
+    assert.Equal(t, int64(2), deleted)
+    remaining, err := repository.ListBookingAttachments(ctx, booking.ID)
+    require.NoError(t, err)
+    assert.Empty(t, remaining)
+    remaining, err = repository.ListBookingAttachments(ctx, pending.ID)
+    require.NoError(t, err)
+    assert.Len(t, remaining, 1)
+}
@@ -0,0 +1,434 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/golang-jwt/jwt/v4"        // v4.5.0
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/booking-service/internal/tracking"
+    "src/backend/shared/clock"
+    "src/backend/shared/policy"
+)
+
+// actionsSecret signs the user tokens of the tests taking actions on bookings
+const actionsSecret = "actions-secret"
+
+// userToken signs a bearer token for a user with the given role
+func userToken(t *testing.T, userID, role string) string {
+    t.Helper()
+    signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{ID: userID, Role: role}).SignedString([]byte(actionsSecret))
+    require.NoError(t, err)
+    return signed
+}
+
+// callAs sends body to handler as userID with role, or anonymously when userID is empty
+func callAs(t *testing.T, handler http.HandlerFunc, method, path, userID, role, body string) *httptest.ResponseRecorder {
+    t.Helper()
+    request := httptest.NewRequest(method, path, strings.NewReader(body))
+    request.Header.Set("Content-Type", "application/json")
+    if userID != "" {
+        request.Header.Set("Authorization", "Bearer "+userToken(t, userID, role))
+    }
+    response := httptest.NewRecorder()
+    handler.ServeHTTP(response, request)
+    return response
+}
+
+// withClock serves handler with requests carrying c, so it tells the time by c
+func withClock(c clock.Clock, handler http.HandlerFunc) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        handler(w, r.WithContext(clock.WithContext(r.Context(), c)))
+    }
+}
+
+// withProviders serves handler with requests carrying p, so the services it calls use p
+func withProviders(p service.Providers, handler http.HandlerFunc) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        handler(w, r.WithContext(service.WithProviders(r.Context(), p)))
+    }
+}
+
+// bookingActions serves the actions on a booking as cmd/server does, behind a user token
+func bookingActions() http.HandlerFunc {
+    return middleware.RequirePermission(actionsSecret, policy.ResourceBookings, policy.ActionUpdate)(handlers.BookingHandler)
+}
+
+// TestCheckInAsWalker checks that walkers check in to and out of walks as the user of their
+// token, whoever the body names
+func TestCheckInAsWalker(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{CheckInRadius: 200, CheckInPositionMaxAge: 5 * time.Minute})
+
+    latitude, longitude := 51.5007, -0.1416
+    booking := memoryBooking("shift-token", "walker-shift-token", time.Now().Add(10*time.Minute))
+    booking.Status = models.BookingStatusConfirmed
+    booking.Latitude, booking.Longitude = &latitude, &longitude
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    actions := withProviders(service.Providers{Locator: &fakeLocator{positions: map[string]tracking.Position{
+        "walker-shift-token": {Latitude: latitude, Longitude: longitude, UpdatedAt: time.Now()},
+        "walker-intruder":    {Latitude: latitude, Longitude: longitude, UpdatedAt: time.Now()},
+    }}}, bookingActions())
+    body := `{"walker_id": "walker-shift-token"}`
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, "/api/v1/bookings/shift-token/check-in", "", "", body).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, "/api/v1/bookings/shift-token/check-in", "client-1", policy.RoleClient, body).Code)
+    assert.Equal(t, http.StatusBadRequest, callAs(t, actions, http.MethodPost, "/api/v1/bookings/shift-token/check-in", "walker-intruder", policy.RoleWalker, body).Code,
+        "the body cannot name another walker")
+
+    response := callAs(t, actions, http.MethodPost, "/api/v1/bookings/shift-token/check-in", "walker-shift-token", policy.RoleWalker, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    stored, err := repository.GetBookingByID(ctx, "shift-token")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusInProgress, stored.Status)
+
+    assert.Equal(t, http.StatusConflict, callAs(t, actions, http.MethodPost, "/api/v1/bookings/shift-token/check-out", "walker-intruder", policy.RoleWalker, body).Code)
+    response = callAs(t, actions, http.MethodPost, "/api/v1/bookings/shift-token/check-out", "walker-shift-token", policy.RoleWalker, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+}
+
+// TestTipAsOwner checks that tips are charged to the owner of the token, whoever the body
+// names, and that walkers only see their own earnings
+func TestTipAsOwner(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{TipWindow: 72 * time.Hour})
+    charger := &fakeCharger{}
+
+    booking := memoryBooking("tip-token", "walker-tip-token", time.Now().Add(-2*time.Hour))
+    booking.Status = models.BookingStatusCompleted
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    actions := withProviders(service.Providers{Charges: charger}, bookingActions())
+    body := `{"owner_id": "owner-tip-token", "amount": 5}`
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, "/api/v1/bookings/tip-token/tip", "", "", body).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, "/api/v1/bookings/tip-token/tip", "owner-intruder", policy.RoleOwner, body).Code,
+        "the body cannot name another owner")
+    assert.Empty(t, charger.charges)
+
+    response := callAs(t, actions, http.MethodPost, "/api/v1/bookings/tip-token/tip", "owner-tip-token", policy.RoleOwner, `{"amount": 5}`)
+    require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+    require.Len(t, charger.charges, 1)
+
+    earnings := middleware.RequirePermission(actionsSecret, policy.ResourceEarnings, policy.ActionRead)(handlers.EarningsReportHandler)
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, earnings, http.MethodGet, "/api/v1/earnings/report", "", "", "").Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, earnings, http.MethodGet, "/api/v1/earnings/report", "owner-tip-token", policy.RoleOwner, "").Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, earnings, http.MethodGet, "/api/v1/earnings/report?walker_id=walker-tip-token", "walker-other", policy.RoleWalker, "").Code)
+
+    response = callAs(t, earnings, http.MethodGet, "/api/v1/earnings/report", "walker-tip-token", policy.RoleWalker, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    assert.Contains(t, response.Body.String(), "walker-tip-token")
+    response = callAs(t, earnings, http.MethodGet, "/api/v1/earnings/report?walker_id=walker-tip-token", "support", policy.RoleAdmin, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    assert.Contains(t, response.Body.String(), "walker-tip-token")
+}
+
+// TestCreateBookingAsOwner checks that owners book as the user of their token, refusing bodies
+// naming another owner, while admins name the owner themselves
+func TestCreateBookingAsOwner(t *testing.T) {
+    repository.UseMemoryStore()
+    useConfig(t, &config.Config{})
+
+    create := middleware.RequirePermission(actionsSecret, policy.ResourceBookings, policy.ActionCreate)(handlers.CreateBookingHandler)
+    body := func(id, ownerID string) string {
+        booking := memoryBooking(id, "", time.Now().Add(48*time.Hour))
+        booking.OwnerID = ownerID
+        data, err := json.Marshal(booking)
+        require.NoError(t, err)
+        return string(data)
+    }
+    owner := func(id string) string {
+        stored, err := repository.GetBookingByID(context.Background(), id)
+        require.NoError(t, err)
+        return stored.OwnerID
+    }
+
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, create, http.MethodPost, "/api/v1/bookings", "", "", body("create-anonymous", "owner-create")).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, create, http.MethodPost, "/api/v1/bookings", "owner-create", policy.RoleOwner, body("create-forged", "owner-victim")).Code,
+        "the body cannot name another owner")
+    _, err := repository.GetBookingByID(context.Background(), "create-forged")
+    assert.Error(t, err)
+
+    for id, ownerID := range map[string]string{"create-own": "owner-create", "create-unnamed": ""} {
+        response := callAs(t, create, http.MethodPost, "/api/v1/bookings", "owner-create", policy.RoleOwner, body(id, ownerID))
+        require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+        assert.Equal(t, "owner-create", owner(id), id)
+    }
+
+    response := callAs(t, create, http.MethodPost, "/api/v1/bookings", "support", policy.RoleAdmin, body("create-by-admin", "owner-named"))
+    require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+    assert.Equal(t, "owner-named", owner("create-by-admin"))
+}
+
+// TestCancelAsOwner checks that bookings are cancelled as the owner of the token, whoever the
+// body names, at the fee for the notice given by the service's clock
+func TestCancelAsOwner(t *testing.T) {
+    repository.UseMemoryStore()
+
+    useConfig(t, &config.Config{CancellationPolicy: models.DefaultCancellationPolicy()})
+
+    start := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+    fake := clock.NewFake(start.Add(-2 * time.Hour))
+    ctx := clock.WithContext(context.Background(), fake)
+
+    for _, id := range []string{"cancel-token", "cancel-token-late"} {
+        booking := memoryBooking(id, "walker-cancel-token", start)
+        booking.Status = models.BookingStatusConfirmed
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+
+    actions := withClock(fake, bookingActions())
+    body := `{"owner_id": "owner-cancel-token"}`
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, "/api/v1/bookings/cancel-token/cancel", "", "", body).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, "/api/v1/bookings/cancel-token/cancel", "owner-intruder", policy.RoleOwner, body).Code,
+        "the body cannot name another owner")
+
+    // Exactly two hours of notice still costs half of the total
+    response := callAs(t, actions, http.MethodPost, "/api/v1/bookings/cancel-token/cancel", "owner-cancel-token", policy.RoleOwner, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    stored, err := repository.GetBookingByID(ctx, "cancel-token")
+    require.NoError(t, err)
+    require.NotNil(t, stored.Cancellation)
+    assert.Equal(t, 50.0, stored.Cancellation.FeePercent)
+    assert.Equal(t, start.Add(-2*time.Hour), stored.Cancellation.CancelledAt)
+
+    fake.Advance(time.Second)
+    late, err := service.CancelBookingService(ctx, "cancel-token-late", "owner-cancel-token-late")
+    require.NoError(t, err)
+    assert.Equal(t, 100.0, late.Cancellation.FeePercent)
+}
+
+// TestDisputeAsOwner checks that disputes are opened and followed as the owner of the token,
+// whoever the body or query names
+func TestDisputeAsOwner(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    booking := memoryBooking("dispute-token", "walker-dispute-token", time.Now().Add(-2*time.Hour))
+    booking.Status = models.BookingStatusCompleted
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    actions := bookingActions()
+    readDispute := middleware.RequirePermission(actionsSecret, policy.ResourceBookings, policy.ActionRead)(handlers.BookingHandler)
+    path := "/api/v1/bookings/dispute-token/dispute"
+    body := `{"owner_id": "owner-dispute-token", "reason": "Walk was cut short"}`
+
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, path, "", "", body).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, path, "owner-intruder", policy.RoleOwner, body).Code,
+        "the body cannot name another owner")
+
+    response := callAs(t, actions, http.MethodPost, path, "owner-dispute-token", policy.RoleOwner, `{"reason": "Walk was cut short"}`)
+    require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, readDispute, http.MethodGet, path+"?owner_id=owner-dispute-token", "", "", "").Code)
+    assert.Equal(t, http.StatusNotFound, callAs(t, readDispute, http.MethodGet, path+"?owner_id=owner-dispute-token", "owner-intruder", policy.RoleOwner, "").Code,
+        "other owners are not told whether the booking was disputed")
+    response = callAs(t, readDispute, http.MethodGet, path, "owner-dispute-token", policy.RoleOwner, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    assert.Contains(t, response.Body.String(), "Walk was cut short")
+}
+
+// TestRebookAsOwner checks that walks are rebooked as the owner of the token, whoever the body
+// names, and only with an owner token
+func TestRebookAsOwner(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+        WalkerID:  "walker-rebook-token",
+        Status:    models.WalkerVerified,
+        UpdatedBy: "admin-1",
+        UpdatedAt: time.Now(),
+    })
+    require.NoError(t, err)
+    start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+    require.NoError(t, service.CreateBookingService(ctx, memoryBooking("rebook-token", "walker-rebook-token", start)))
+
+    actions := bookingActions()
+    path := "/api/v1/bookings/rebook-token/rebook"
+    body := `{"owner_id": "owner-rebook-token", "scheduled_at": "` + start.Add(48*time.Hour).Format(time.RFC3339) + `"}`
+
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, path, "", "", body).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, path, "walker-rebook-token", policy.RoleWalker, body).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, path, "owner-intruder", policy.RoleOwner, body).Code,
+        "the body cannot name another owner")
+
+    response := callAs(t, actions, http.MethodPost, path, "owner-rebook-token", policy.RoleOwner, body)
+    require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+    assert.Contains(t, response.Body.String(), `"owner_id":"owner-rebook-token"`)
+}
+
+// TestSaveRatePlanAsWalker checks that walkers set only their own rate plan, whoever the body
+// names, while admins can set any walker's
+func TestSaveRatePlanAsWalker(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    save := middleware.RequirePermission(actionsSecret, policy.ResourceRatePlans, policy.ActionUpdate)(handlers.SaveRatePlanHandler)
+    plan := `{"walker_id": "walker-rated", "base_rate": 22}`
+
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, save, http.MethodPut, "/api/v1/rates", "", "", plan).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, save, http.MethodPut, "/api/v1/rates", "owner-rated", policy.RoleOwner, plan).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, save, http.MethodPut, "/api/v1/rates", "walker-undercut", policy.RoleWalker, plan).Code,
+        "walkers cannot set each other's rates")
+
+    response := callAs(t, save, http.MethodPut, "/api/v1/rates", "walker-rated", policy.RoleWalker, `{"base_rate": 22}`)
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    saved, err := service.GetRatePlanService(ctx, "walker-rated")
+    require.NoError(t, err)
+    assert.Equal(t, 22.0, saved.BaseRate)
+
+    response = callAs(t, save, http.MethodPut, "/api/v1/rates", "support", policy.RoleAdmin, `{"walker_id": "walker-rated", "base_rate": 24}`)
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    saved, err = service.GetRatePlanService(ctx, "walker-rated")
+    require.NoError(t, err)
+    assert.Equal(t, 24.0, saved.BaseRate)
+}
+
+// TestBookingChangeAsParticipants checks that changes are proposed as the owner of the token
+// and answered as the walker of the token, whoever the body names
+func TestBookingChangeAsParticipants(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{ChangeRequestTTL: time.Hour})
+
+    start := time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+    booking := memoryBooking("change-token", "walker-change-token", start)
+    booking.Status = models.BookingStatusConfirmed
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    actions := bookingActions()
+    path := "/api/v1/bookings/change-token/changes"
+    later := start.Add(2 * time.Hour).Format(time.RFC3339)
+    body := `{"proposed_by": "owner-change-token", "scheduled_at": "` + later + `"}`
+
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, path, "", "", body).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, path, "owner-intruder", policy.RoleOwner, body).Code,
+        "the body cannot name another proposer")
+
+    response := callAs(t, actions, http.MethodPost, path, "owner-change-token", policy.RoleOwner, `{"scheduled_at": "`+later+`"}`)
+    require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+    var proposed struct {
+        Data models.BookingChange `json:"data"`
+    }
+    require.NoError(t, json.Unmarshal(response.Body.Bytes(), &proposed))
+    assert.Equal(t, "owner-change-token", proposed.Data.ProposedBy)
+
+    accept := path + "/" + proposed.Data.ID + "/accept"
+    answer := `{"walker_id": "walker-change-token"}`
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, accept, "", "", answer).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, accept, "walker-intruder", policy.RoleWalker, answer).Code,
+        "the body cannot name another walker")
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, path+"/"+proposed.Data.ID+"/reject", "walker-intruder", policy.RoleWalker, answer).Code)
+
+    response = callAs(t, actions, http.MethodPost, accept, "walker-change-token", policy.RoleWalker, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    stored, err := repository.GetBookingByID(ctx, "change-token")
+    require.NoError(t, err)
+    assert.True(t, stored.ScheduledAt.Equal(start.Add(2*time.Hour)))
+}
+
+// TestAssignmentAsWalker checks that assignments are accepted and declined as the walker of the
+// token, whoever the body names, and only with a walker token
+func TestAssignmentAsWalker(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{AssignmentAcceptWindow: time.Hour})
+
+    for i, id := range []string{"assigned-token", "declined-token"} {
+        booking := memoryBooking(id, "", time.Now().Add(time.Duration(24+i)*time.Hour))
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+        _, err := repository.AssignWalker(ctx, id, "walker-assigned", 1, time.Now().Add(time.Hour))
+        require.NoError(t, err)
+    }
+
+    actions := bookingActions()
+    body := `{"walker_id": "walker-assigned"}`
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, "/api/v1/bookings/assigned-token/accept", "", "", body).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, actions, http.MethodPost, "/api/v1/bookings/assigned-token/accept", "owner-assigned-token", policy.RoleOwner, body).Code)
+    assert.Equal(t, http.StatusConflict, callAs(t, actions, http.MethodPost, "/api/v1/bookings/assigned-token/accept", "walker-intruder", policy.RoleWalker, body).Code,
+        "the body cannot name another walker")
+    assert.Equal(t, http.StatusConflict, callAs(t, actions, http.MethodPost, "/api/v1/bookings/declined-token/decline", "walker-intruder", policy.RoleWalker, body).Code)
+
+    response := callAs(t, actions, http.MethodPost, "/api/v1/bookings/declined-token/decline", "walker-assigned", policy.RoleWalker, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    declined, err := repository.GetBookingByID(ctx, "declined-token")
+    require.NoError(t, err)
+    assert.NotEqual(t, "walker-assigned", declined.WalkerID)
+}
+
+// TestAssignmentResponseSLA checks that the background jobs release walkers who leave an
+// assignment unanswered past the response SLA, offer the booking to the next walker and
+// cancel it once nobody is left
+func TestAssignmentResponseSLA(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{AssignmentAcceptWindow: 15 * time.Minute})
+
+    start := time.Now().Add(24 * time.Hour)
+    for _, walkerID := range []string{"walker-sla-1", "walker-sla-2"} {
+        require.NoError(t, repository.CreateAvailability(ctx, &models.Availability{
+            ID:       "availability-" + walkerID,
+            WalkerID: walkerID,
+            StartsAt: start.Add(-time.Hour),
+            EndsAt:   start.Add(2 * time.Hour),
+            Capacity: 1,
+        }))
+        _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+            WalkerID:  walkerID,
+            Status:    models.WalkerVerified,
+            UpdatedBy: "admin-1",
+            UpdatedAt: time.Now(),
+        })
+        require.NoError(t, err)
+    }
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("sla-booking", "", start)))
+
+    assigned, err := service.AssignWalkerService(ctx, "sla-booking", "walker-sla-1")
+    require.NoError(t, err)
+    require.NotNil(t, assigned.AcceptBy)
+    acceptBy := *assigned.AcceptBy
+
+    // Within the SLA the walker keeps the booking
+    service.RunDueJobs(ctx, acceptBy.Add(-time.Minute))
+    stored, err := repository.GetBookingByID(ctx, "sla-booking")
+    require.NoError(t, err)
+    assert.Equal(t, "walker-sla-1", stored.WalkerID)
+
+    service.RunDueJobs(ctx, acceptBy.Add(time.Minute))
+    stored, err = repository.GetBookingByID(ctx, "sla-booking")
+    require.NoError(t, err)
+    assert.Equal(t, "walker-sla-2", stored.WalkerID, "the booking goes to the next available walker")
+    assert.Equal(t, models.BookingStatusPending, stored.Status)
+    require.NotNil(t, stored.AcceptBy)
+
+    service.RunDueJobs(ctx, stored.AcceptBy.Add(time.Minute))
+    stored, err = repository.GetBookingByID(ctx, "sla-booking")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCancelled, stored.Status, "nobody is left to take the booking")
+}
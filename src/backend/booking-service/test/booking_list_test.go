@@ -0,0 +1,193 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// TestMemoryStoreListBookingsPaging verifies listings are ordered by scheduled time and then ID
+// and that paging with cursors neither repeats nor skips bookings while others are inserted
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreListBookingsPaging(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    // Three bookings share each start time, so only the ID orders them
+    base := time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+    listed := make(map[string]bool)
+    for i := 0; i < 30; i++ {
+        booking := memoryBooking(fmt.Sprintf("page-%02d", i), "walker-page", base.Add(time.Duration(i/3)*time.Hour))
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+        listed[booking.ID] = false
+    }
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("other-walker", "walker-other", base)))
+
+    // Insert more of the walker's bookings, before and after the page boundaries, while paging
+    var wg sync.WaitGroup
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+        for i := 0; i < 40; i++ {
+            booking := memoryBooking(fmt.Sprintf("late-%02d", i), "walker-page", base.Add(time.Duration(i%12)*time.Hour))
+            if err := repository.CreateBooking(ctx, booking); err != nil {
+                t.Errorf("failed to create booking: %v", err)
+            }
+        }
+    }()
+
+    filter := models.BookingFilter{WalkerID: "walker-page"}
+    var seen []models.Booking
+    cursor := ""
+    for pages := 0; pages < 20; pages++ {
+        page, err := service.ListBookingsService(ctx, filter, cursor, 7)
+        require.NoError(t, err)
+        assert.LessOrEqual(t, len(page.Bookings), 7)
+        seen = append(seen, page.Bookings...)
+        if page.NextCursor == "" {
+            break
+        }
+        cursor = page.NextCursor
+    }
+    wg.Wait()
+
+    ids := make(map[string]bool)
+    for i, b := range seen {
+        assert.False(t, ids[b.ID], "booking %s listed twice", b.ID)
+        ids[b.ID] = true
+        assert.Equal(t, "walker-page", b.WalkerID)
+        if i > 0 {
+            previous := seen[i-1]
+            assert.True(t, previous.ScheduledAt.After(b.ScheduledAt) ||
+                (previous.ScheduledAt.Equal(b.ScheduledAt) && previous.ID > b.ID),
+                "%s listed before %s", previous.ID, b.ID)
+        }
+    }
+    for id := range listed {
+        assert.True(t, ids[id], "booking %s was skipped", id)
+    }
+
+    // A page boundary inside a group of bookings sharing a start time resumes at the next ID
+    page, err := service.ListBookingsService(ctx, filter, "", 1)
+    require.NoError(t, err)
+    require.Len(t, page.Bookings, 1)
+    next, err := service.ListBookingsService(ctx, filter, page.NextCursor, 2)
+    require.NoError(t, err)
+    require.Len(t, next.Bookings, 2)
+    assert.True(t, next.Bookings[0].ScheduledAt.Equal(page.Bookings[0].ScheduledAt))
+    assert.Less(t, next.Bookings[0].ID, page.Bookings[0].ID)
+
+    _, err = service.ListBookingsService(ctx, filter, "not a cursor", 5)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid booking listing")
+    _, err = service.ListBookingsService(ctx, models.BookingFilter{Status: "lost"}, "", 5)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid booking listing")
+
+    request := httptest.NewRequest(http.MethodGet, "/api/v1/bookings?walker_id=walker-other&limit=5", nil)
+    response := httptest.NewRecorder()
+    handlers.ListBookingsHandler(response, request)
+    require.Equal(t, http.StatusOK, response.Code)
+    var body struct {
+        Data models.BookingPage `json:"data"`
+    }
+    require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+    require.Len(t, body.Data.Bookings, 1)
+    assert.Equal(t, "other-walker", body.Data.Bookings[0].ID)
+    assert.Empty(t, body.Data.NextCursor)
+
+    // Bookings fetched by ID come in one page, leaving out any not found
+    request = httptest.NewRequest(http.MethodGet, "/api/v1/bookings?ids=page-03,other-walker,missing&limit=3", nil)
+    response = httptest.NewRecorder()
+    handlers.ListBookingsHandler(response, request)
+    require.Equal(t, http.StatusOK, response.Code)
+    body.Data = models.BookingPage{}
+    require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+    require.Len(t, body.Data.Bookings, 2)
+    assert.ElementsMatch(t, []string{"page-03", "other-walker"}, []string{body.Data.Bookings[0].ID, body.Data.Bookings[1].ID})
+    assert.Equal(t, 2, body.Data.Summary.Total)
+    _, err = service.ListBookingsService(ctx, models.BookingFilter{IDs: make([]string, 101)}, "", 5)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid booking listing")
+
+    request = httptest.NewRequest(http.MethodGet, "/api/v1/bookings?cursor=%25%25", nil)
+    response = httptest.NewRecorder()
+    handlers.ListBookingsHandler(response, request)
+    assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+// TestMemoryStoreBookingSummary verifies listings count every matching booking by status,
+// whichever page is returned, and report the total in X-Total-Count
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreBookingSummary(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    start := time.Now().Add(48 * time.Hour)
+    statuses := []models.BookingStatus{
+        models.BookingStatusPending, models.BookingStatusPending, models.BookingStatusConfirmed,
+        models.BookingStatusCancelled, models.BookingStatusConfirmed, models.BookingStatusPending,
+    }
+    for i, status := range statuses {
+        booking := memoryBooking(fmt.Sprintf("summary-%d", i), "walker-summary", start.Add(time.Duration(i)*time.Hour))
+        booking.Status = status
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("summary-other", "walker-other", start)))
+
+    filter := models.BookingFilter{WalkerID: "walker-summary"}
+    page, err := service.ListBookingsService(ctx, filter, "", 2)
+    require.NoError(t, err)
+    require.Len(t, page.Bookings, 2)
+    next, err := service.ListBookingsService(ctx, filter, page.NextCursor, 2)
+    require.NoError(t, err)
+    assert.Equal(t, page.Summary, next.Summary)
+
+    assert.Equal(t, 6, page.Summary.Total)
+    assert.Equal(t, 3, page.Summary.ByStatus[models.BookingStatusPending])
+    assert.Equal(t, 2, page.Summary.ByStatus[models.BookingStatusConfirmed])
+    assert.Equal(t, 1, page.Summary.ByStatus[models.BookingStatusCancelled])
+    assert.Len(t, page.Summary.ByStatus, len(models.BookingStatuses))
+
+    confirmed, err := service.ListBookingsService(ctx, models.BookingFilter{WalkerID: "walker-summary", Status: models.BookingStatusConfirmed}, "", 10)
+    require.NoError(t, err)
+    assert.Equal(t, 2, confirmed.Summary.Total)
+    assert.Zero(t, confirmed.Summary.ByStatus[models.BookingStatusPending])
+
+    request := httptest.NewRequest(http.MethodGet, "/api/v1/bookings?walker_id=walker-summary&limit=1", nil)
+    response := httptest.NewRecorder()
+    handlers.ListBookingsHandler(response, request)
+    require.Equal(t, http.StatusOK, response.Code)
+    assert.Equal(t, "6", response.Header().Get("X-Total-Count"))
+    var body struct {
+        Data struct {
+            Bookings []models.Booking `json:"bookings"`
+            Summary  struct {
+                Total    int            `json:"total"`
+                ByStatus map[string]int `json:"by_status"`
+            } `json:"summary"`
+        } `json:"data"`
+    }
+    require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+    assert.Len(t, body.Data.Bookings, 1)
+    assert.Equal(t, 6, body.Data.Summary.Total)
+    assert.Equal(t, 3, body.Data.Summary.ByStatus["pending"])
+    assert.Equal(t, 0, body.Data.Summary.ByStatus["failed"])
+}
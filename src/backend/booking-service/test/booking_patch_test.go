@@ -0,0 +1,117 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/golang-jwt/jwt/v4"        // v4.5.0
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+)
+
+// TestMemoryStorePatchBooking verifies owners can merge-patch the fields their booking's
+// status allows, and that other fields, other users and stale updates are refused
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStorePatchBooking(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    const secret = "patch-secret"
+    handler := middleware.RequirePermission(secret, policy.ResourceBookings, policy.ActionUpdate)(handlers.PatchBookingHandler)
+    token := func(userID string) string {
+        signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &middleware.Claims{ID: userID, Role: policy.RoleOwner}).SignedString([]byte(secret))
+        require.NoError(t, err)
+        return signed
+    }
+    patch := func(bookingID, userID, contentType, body string) *httptest.ResponseRecorder {
+        request := httptest.NewRequest(http.MethodPatch, "/api/v1/bookings/"+bookingID, strings.NewReader(body))
+        request.Header.Set("Content-Type", contentType)
+        if userID != "" {
+            request.Header.Set("Authorization", "Bearer "+token(userID))
+        }
+        response := httptest.NewRecorder()
+        handler.ServeHTTP(response, request)
+        return response
+    }
+    require.NoError(t, service.SaveBookingRulesService(ctx, models.DefaultRuleSetRegion, &models.BookingRuleSet{
+        Rules: []models.BookingRule{{Kind: models.BookingRuleMaxDuration, Minutes: 60}},
+    }))
+
+    start := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+    open := memoryBooking("patch-open", "", start)
+    require.NoError(t, repository.CreateBooking(ctx, open))
+
+    assert.Equal(t, http.StatusUnsupportedMediaType, patch(open.ID, open.OwnerID, "application/json", `{"duration_minutes": 45}`).Code)
+    assert.Equal(t, http.StatusUnauthorized, patch(open.ID, "", models.MergePatchContentType, `{"duration_minutes": 45}`).Code)
+    assert.Equal(t, http.StatusForbidden, patch(open.ID, "someone-else", models.MergePatchContentType, `{"duration_minutes": 45}`).Code)
+    assert.Equal(t, http.StatusNotFound, patch("missing", open.OwnerID, models.MergePatchContentType, `{}`).Code)
+    assert.Equal(t, http.StatusBadRequest, patch(open.ID, open.OwnerID, models.MergePatchContentType, `[1]`).Code)
+    assert.Equal(t, http.StatusBadRequest, patch(open.ID, open.OwnerID, models.MergePatchContentType, `{"dog_id": null}`).Code)
+    assert.Equal(t, http.StatusBadRequest, patch(open.ID, open.OwnerID, models.MergePatchContentType, `{"latitude": 40.7}`).Code)
+    assert.Equal(t, http.StatusBadRequest, patch(open.ID, open.OwnerID, models.MergePatchContentType, `{"duration_minutes": 90}`).Code)
+
+    response := patch(open.ID, open.OwnerID, models.MergePatchContentType, `{"amount": 1, "owner_id": "thief"}`)
+    assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+    assert.Contains(t, response.Body.String(), "amount, owner_id cannot be changed")
+
+    // Fields sent back unchanged are accepted alongside the changes
+    moved := start.Add(3 * time.Hour)
+    body := fmt.Sprintf(`{"scheduled_at": %q, "duration_minutes": 45, "owner_id": %q, "status": "pending"}`,
+        moved.Format(time.RFC3339), open.OwnerID)
+    response = patch(open.ID, open.OwnerID, models.MergePatchContentType+"; charset=utf-8", body)
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    stored, err := repository.GetBookingByID(ctx, open.ID)
+    require.NoError(t, err)
+    assert.True(t, moved.Equal(stored.ScheduledAt))
+    assert.Equal(t, 45, stored.DurationMinutes)
+    assert.Equal(t, open.DogID, stored.DogID)
+    assert.Equal(t, 38.25, stored.Amount, "a longer walk costs proportionally more")
+
+    // Moving the walk without changing its length keeps its price
+    response = patch(open.ID, open.OwnerID, models.MergePatchContentType, fmt.Sprintf(`{"scheduled_at": %q}`, start.Format(time.RFC3339)))
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    stored, err = repository.GetBookingByID(ctx, open.ID)
+    require.NoError(t, err)
+    assert.Equal(t, 38.25, stored.Amount)
+
+    // Once a walker has confirmed, only the dog can be patched; rescheduling needs their approval
+    confirmed := memoryBooking("patch-confirmed", "walker-patch", start)
+    confirmed.Status = models.BookingStatusConfirmed
+    require.NoError(t, repository.CreateBooking(ctx, confirmed))
+    response = patch(confirmed.ID, confirmed.OwnerID, models.MergePatchContentType, `{"duration_minutes": 60}`)
+    assert.Equal(t, http.StatusConflict, response.Code)
+    assert.Contains(t, response.Body.String(), "duration_minutes cannot be changed while the booking is confirmed")
+    require.Equal(t, http.StatusOK, patch(confirmed.ID, confirmed.OwnerID, models.MergePatchContentType, `{"dog_id": "dog-other"}`).Code)
+    stored, err = repository.GetBookingByID(ctx, confirmed.ID)
+    require.NoError(t, err)
+    assert.Equal(t, "dog-other", stored.DogID)
+    assert.Equal(t, 30, stored.DurationMinutes)
+
+    cancelled := memoryBooking("patch-cancelled", "", start)
+    cancelled.Status = models.BookingStatusCancelled
+    require.NoError(t, repository.CreateBooking(ctx, cancelled))
+    assert.Equal(t, http.StatusConflict, patch(cancelled.ID, cancelled.OwnerID, models.MergePatchContentType, `{"dog_id": "dog-other"}`).Code)
+
+    // A booking changed since it was read is not overwritten
+    stale := *stored
+    stale.WalkerID = "walker-before"
+    updated := stale
+    updated.DogID = "dog-stale"
+    assert.ErrorIs(t, repository.UpdateBookingDetails(ctx, &stale, &updated), repository.ErrBookingModified)
+}
@@ -0,0 +1,192 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/clock"
+)
+
+// TestMemoryStoreBookingRules verifies new bookings are checked against the rules saved for
+// their region, falling back to the default rules
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func TestMemoryStoreBookingRules(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    rules, err := service.GetBookingRulesService(ctx, "london")
+    require.NoError(t, err)
+    assert.Empty(t, rules.Rules)
+
+    for _, invalid := range [][]models.BookingRule{
+        {{Kind: "weekdays_only"}},
+        {{Kind: models.BookingRuleMinLeadTime}},
+        {{Kind: models.BookingRuleMaxDuration, Minutes: 60}, {Kind: models.BookingRuleMaxDuration, Minutes: 90}},
+    } {
+        err := service.SaveBookingRulesService(ctx, "london", &models.BookingRuleSet{Rules: invalid})
+        require.Error(t, err)
+        assert.Contains(t, err.Error(), "invalid booking rules")
+    }
+
+    require.NoError(t, service.SaveBookingRulesService(ctx, models.DefaultRuleSetRegion, &models.BookingRuleSet{
+        Rules: []models.BookingRule{{Kind: models.BookingRuleMaxDuration, Minutes: 60}},
+    }))
+    require.NoError(t, service.SaveBookingRulesService(ctx, " London ", &models.BookingRuleSet{
+        Rules: []models.BookingRule{{Kind: models.BookingRuleMinLeadTime, Minutes: 120}},
+    }))
+
+    // London's own rules replace the default set rather than adding to it
+    soon := memoryBooking("booking-soon", "", time.Now().Add(time.Hour))
+    soon.Region = "london"
+    err = service.CreateBookingService(ctx, soon)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking must be scheduled at least 120 minutes ahead")
+    long := memoryBooking("booking-long", "", time.Now().Add(3*time.Hour))
+    long.Region, long.DurationMinutes = "london", 90
+    require.NoError(t, service.CreateBookingService(ctx, long))
+
+    elsewhere := memoryBooking("booking-elsewhere", "", time.Now().Add(time.Hour))
+    elsewhere.Region, elsewhere.DurationMinutes = "paris", 90
+    err = service.CreateBookingService(ctx, elsewhere)
+    require.Error(t, err)
+    assert.Equal(t, "invalid booking data: walks can be at most 60 minutes long", err.Error())
+    elsewhere.DurationMinutes = 60
+    require.NoError(t, service.CreateBookingService(ctx, elsewhere))
+
+    require.NoError(t, service.DeleteBookingRulesService(ctx, "london"))
+    rules, err = service.GetBookingRulesService(ctx, "london")
+    require.NoError(t, err)
+    assert.Equal(t, models.DefaultRuleSetRegion, rules.Region)
+    err = service.DeleteBookingRulesService(ctx, "london")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking rules not found")
+}
+
+// TestMemoryStoreBookingClock verifies bookings are checked against the service's clock, so
+// lead times count elapsed time across the night the clocks go forward, and a walk the clock
+// has passed can no longer be rescheduled into the past
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func TestMemoryStoreBookingClock(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    london, err := time.LoadLocation("Europe/London")
+    require.NoError(t, err)
+    // Half an hour before the clocks go forward from 01:00 GMT to 02:00 BST
+    fake := clock.NewFake(time.Date(2026, time.March, 29, 0, 30, 0, 0, london))
+    ctx = clock.WithContext(ctx, fake)
+
+    require.NoError(t, service.SaveBookingRulesService(ctx, models.DefaultRuleSetRegion, &models.BookingRuleSet{
+        Rules: []models.BookingRule{{Kind: models.BookingRuleMinLeadTime, Minutes: 120}},
+    }))
+
+    // 03:00 BST reads two and a half hours on, but is only ninety minutes away
+    early := memoryBooking("booking-dst-early", "", time.Date(2026, time.March, 29, 3, 0, 0, 0, london))
+    err = service.CreateBookingService(ctx, early)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking must be scheduled at least 120 minutes ahead")
+
+    booked := memoryBooking("booking-dst", "", time.Date(2026, time.March, 29, 3, 30, 0, 0, london))
+    require.NoError(t, service.CreateBookingService(ctx, booked))
+    assert.True(t, booked.IsScheduledInFuture(fake.Now()))
+    assert.False(t, booked.IsOverdue(fake.Now()))
+    assert.Equal(t, 2*time.Hour, booked.TimeUntilScheduled(fake.Now()))
+
+    fake.Advance(time.Hour + 30*time.Minute)
+    stored, err := repository.GetBookingByID(ctx, booked.ID)
+    require.NoError(t, err)
+    assert.True(t, stored.IsScheduledInFuture(fake.Now()))
+
+    fake.Advance(time.Hour)
+    assert.False(t, stored.IsScheduledInFuture(fake.Now()))
+    assert.True(t, stored.IsOverdue(fake.Now()))
+    body := fmt.Sprintf(`{"scheduled_at": %q}`, time.Date(2026, time.March, 29, 3, 45, 0, 0, london).Format(time.RFC3339))
+    _, err = service.PatchBookingService(ctx, booked.ID, booked.OwnerID, []byte(body))
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking must be scheduled for a future time")
+}
+
+// TestMemoryStoreBookingWindow verifies walks booked too soon or too far ahead are refused at
+// quote and creation time with codes telling them apart from other invalid bookings
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func TestMemoryStoreBookingWindow(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    err := service.SaveBookingRulesService(ctx, models.DefaultRuleSetRegion, &models.BookingRuleSet{
+        Rules: []models.BookingRule{{Kind: models.BookingRuleMaxAdvance, Minutes: 60}},
+    })
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid booking rules")
+    require.NoError(t, service.SaveBookingRulesService(ctx, models.DefaultRuleSetRegion, &models.BookingRuleSet{
+        Rules: []models.BookingRule{
+            {Kind: models.BookingRuleMinLeadTime, Minutes: 120},
+            {Kind: models.BookingRuleMaxAdvance, Days: 60},
+        },
+    }))
+
+    var ruleErr *service.RuleError
+    err = service.CreateBookingService(ctx, memoryBooking("booking-soon", "", time.Now().Add(time.Hour)))
+    require.ErrorAs(t, err, &ruleErr)
+    assert.Equal(t, service.RuleCodeLeadTime, ruleErr.Code)
+    assert.Equal(t, 120, ruleErr.Rule.Minutes)
+
+    err = service.CreateBookingService(ctx, memoryBooking("booking-far", "", time.Now().AddDate(0, 0, 61)))
+    require.ErrorAs(t, err, &ruleErr)
+    assert.Equal(t, service.RuleCodeAdvanceWindow, ruleErr.Code)
+    assert.Equal(t, "booking can be made at most 60 days ahead", err.Error())
+
+    require.NoError(t, service.CreateBookingService(ctx, memoryBooking("booking-ok", "", time.Now().AddDate(0, 0, 59))))
+
+    _, err = service.QuoteRateService(ctx, "walker-1", "", time.Now().Add(30*time.Minute), 30, false, 0)
+    require.ErrorAs(t, err, &ruleErr)
+    assert.Equal(t, service.RuleCodeLeadTime, ruleErr.Code)
+
+    // The API answers with the rule's code and the message in the client's language
+    body, err := json.Marshal(memoryBooking("booking-api", "", time.Now().AddDate(0, 0, 90)))
+    require.NoError(t, err)
+    request := httptest.NewRequest(http.MethodPost, "/api/v1/bookings", bytes.NewReader(body))
+    request.Header.Set("Accept-Language", "es")
+    response := httptest.NewRecorder()
+    i18n.Middleware(http.HandlerFunc(handlers.CreateBookingHandler)).ServeHTTP(response, request)
+    assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+    var envelope struct {
+        Success bool `json:"success"`
+        Error   struct {
+            Code    string             `json:"code"`
+            Message string             `json:"message"`
+            Details models.BookingRule `json:"details"`
+        } `json:"error"`
+    }
+    require.NoError(t, json.Unmarshal(response.Body.Bytes(), &envelope))
+    assert.False(t, envelope.Success)
+    assert.Equal(t, service.RuleCodeAdvanceWindow, envelope.Error.Code)
+    assert.Equal(t, "la reserva puede hacerse como máximo con 60 días de antelación", envelope.Error.Message)
+    assert.Equal(t, 60, envelope.Error.Details.Days)
+}
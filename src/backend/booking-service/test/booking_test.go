@@ -1,74 +1,62 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
 package test
 
 import (
     "context"
+    "database/sql"
     "testing"
     "time"
 
-    "github.com/stretchr/testify/assert" // v1.8.0
-    "github.com/stretchr/testify/mock"   // v1.8.0
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
 
+    "src/backend/booking-service/internal/config"
     "src/backend/booking-service/internal/models"
-    "src/backend/booking-service/internal/service"
     "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
 )
 
-// MockRepository is a mock implementation of the repository layer
-type MockRepository struct {
-    mock.Mock
-}
-
-// CreateBooking mocks the repository's CreateBooking method
-func (m *MockRepository) CreateBooking(ctx context.Context, booking *models.Booking) error {
-    args := m.Called(ctx, booking)
-    return args.Error(0)
-}
-
-// GetBookingByID mocks the repository's GetBookingByID method
-func (m *MockRepository) GetBookingByID(ctx context.Context, id string) (*models.Booking, error) {
-    args := m.Called(ctx, id)
-    if args.Get(0) == nil {
-        return nil, args.Error(1)
-    }
-    return args.Get(0).(*models.Booking), args.Error(1)
+// useUnreachableDB points the repository at a PostgreSQL server that refuses every
+// connection, restoring an empty in-memory store when the test ends
+func useUnreachableDB(t *testing.T) {
+    t.Helper()
+    db, err := sql.Open("postgres", "host=127.0.0.1 port=1 sslmode=disable connect_timeout=1")
+    require.NoError(t, err)
+    repository.UseDB(db)
+    t.Cleanup(func() {
+        db.Close()
+        repository.UseMemoryStore()
+    })
 }
 
 // TestCreateBookingService tests the CreateBookingService function
 // Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
 func TestCreateBookingService(t *testing.T) {
-    // Set up mock repository
-    mockRepo := new(MockRepository)
-    repository.DB = nil // Ensure we're not using real DB
-
-    // Create test booking data
-    testBooking := &models.Booking{
-        ID:          "test-booking-1",
-        OwnerID:     "owner-1",
-        WalkerID:    "walker-1",
-        DogID:       "dog-1",
-        ScheduledAt: time.Now().Add(24 * time.Hour), // Schedule for tomorrow
-        Status:      models.BookingStatusPending,
-        Amount:      50.00,
-    }
+    repository.UseMemoryStore()
+
+    useConfig(t, &config.Config{})
 
     // Test case 1: Successful booking creation
     t.Run("Successful booking creation", func(t *testing.T) {
-        // Set up mock expectations
-        mockRepo.On("CreateBooking", mock.Anything, testBooking).Return(nil)
+        booking := memoryBooking("test-booking-1", "", time.Now().Add(24*time.Hour))
 
-        // Call the service function
-        err := service.CreateBookingService(context.Background(), testBooking)
+        err := service.CreateBookingService(context.Background(), booking)
 
-        // Assert expectations
-        assert.NoError(t, err)
-        mockRepo.AssertExpectations(t)
+        require.NoError(t, err)
+        stored, err := repository.GetBookingByID(context.Background(), booking.ID)
+        require.NoError(t, err)
+        assert.Equal(t, booking.OwnerID, stored.OwnerID)
+        assert.Equal(t, models.BookingStatusPending, stored.Status)
     })
 
     // Test case 2: Invalid booking data
     t.Run("Invalid booking data", func(t *testing.T) {
         invalidBooking := &models.Booking{
             // Missing required fields
-            ID:       "",
+            ID:      "",
             OwnerID: "",
         }
 
@@ -95,48 +83,38 @@ func TestCreateBookingService(t *testing.T) {
         assert.Error(t, err)
         assert.Contains(t, err.Error(), "must be scheduled for a future time")
     })
+
+    // Test case 4: Database error
+    t.Run("Database error", func(t *testing.T) {
+        useUnreachableDB(t)
+
+        err := service.CreateBookingService(context.Background(), memoryBooking("test-booking-3", "", time.Now().Add(24*time.Hour)))
+
+        assert.Error(t, err)
+        assert.Contains(t, err.Error(), "connection refused")
+    })
 }
 
 // TestGetBookingService tests the GetBookingService function
 // Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
 func TestGetBookingService(t *testing.T) {
-    // Set up mock repository
-    mockRepo := new(MockRepository)
-    repository.DB = nil // Ensure we're not using real DB
-
-    // Create test booking data
-    testBooking := &models.Booking{
-        ID:          "test-booking-1",
-        OwnerID:     "owner-1",
-        WalkerID:    "walker-1",
-        DogID:       "dog-1",
-        ScheduledAt: time.Now().Add(24 * time.Hour),
-        Status:      models.BookingStatusPending,
-        Amount:      50.00,
-    }
+    repository.UseMemoryStore()
+    testBooking := memoryBooking("test-booking-1", "walker-1", time.Now().Add(24*time.Hour))
+    require.NoError(t, repository.CreateBooking(context.Background(), testBooking))
 
     // Test case 1: Successful booking retrieval
     t.Run("Successful booking retrieval", func(t *testing.T) {
-        // Set up mock expectations
-        mockRepo.On("GetBookingByID", mock.Anything, testBooking.ID).Return(testBooking, nil)
-
-        // Call the service function
         booking, err := service.GetBookingService(context.Background(), testBooking.ID)
 
-        // Assert expectations
         assert.NoError(t, err)
-        assert.NotNil(t, booking)
+        require.NotNil(t, booking)
         assert.Equal(t, testBooking.ID, booking.ID)
         assert.Equal(t, testBooking.OwnerID, booking.OwnerID)
-        mockRepo.AssertExpectations(t)
     })
 
     // Test case 2: Booking not found
     t.Run("Booking not found", func(t *testing.T) {
-        nonExistentID := "non-existent-id"
-        mockRepo.On("GetBookingByID", mock.Anything, nonExistentID).Return(nil, repository.ErrBookingNotFound)
-
-        booking, err := service.GetBookingService(context.Background(), nonExistentID)
+        booking, err := service.GetBookingService(context.Background(), "non-existent-id")
 
         assert.Error(t, err)
         assert.Nil(t, booking)
@@ -154,7 +132,7 @@ func TestGetBookingService(t *testing.T) {
 
     // Test case 4: Database error
     t.Run("Database error", func(t *testing.T) {
-        mockRepo.On("GetBookingByID", mock.Anything, testBooking.ID).Return(nil, repository.ErrDatabaseError)
+        useUnreachableDB(t)
 
         booking, err := service.GetBookingService(context.Background(), testBooking.ID)
 
@@ -162,4 +140,4 @@ func TestGetBookingService(t *testing.T) {
         assert.Nil(t, booking)
         assert.Contains(t, err.Error(), "failed to retrieve booking")
     })
-}
\ No newline at end of file
+}
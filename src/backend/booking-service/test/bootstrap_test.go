@@ -0,0 +1,178 @@
+package test
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net"
+    "net/http"
+    "sync"
+    "syscall"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/shared/bootstrap"
+)
+
+// lifecycle records the order in which a server's hooks and workers ran
+type lifecycle struct {
+    mu     sync.Mutex
+    events []string
+}
+
+func (l *lifecycle) record(event string) {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    l.events = append(l.events, event)
+}
+
+func (l *lifecycle) recorded() []string {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return append([]string(nil), l.events...)
+}
+
+// hook returns a hook recording event and returning err
+func (l *lifecycle) hook(event string, err error) bootstrap.Hook {
+    return func(ctx context.Context) error {
+        l.record(event)
+        return err
+    }
+}
+
+// TestServerShutdownOrder verifies a server asked to stop waits for its workers to return
+// before running its stop hooks, in the reverse order of their registration
+func TestServerShutdownOrder(t *testing.T) {
+    var l lifecycle
+    running := make(chan struct{})
+    server := bootstrap.New("bootstrap-test", 0).
+        WithShutdownTimeout(5*time.Second).
+        OnStart("database", l.hook("start database", nil)).
+        OnStop("database", l.hook("stop database", nil)).
+        OnStart("hub", l.hook("start hub", nil)).
+        OnStop("hub", l.hook("stop hub", errors.New("hub already closed"))).
+        OnStop("jobs", l.hook("stop jobs", nil)).
+        Go("worker", func(ctx context.Context) {
+            close(running)
+            <-ctx.Done()
+            // A worker still finishing its round holds the stop hooks back
+            time.Sleep(50 * time.Millisecond)
+            l.record("worker stopped")
+        })
+
+    done := make(chan error, 1)
+    go func() { done <- server.Run() }()
+
+    select {
+    case <-running:
+    case <-time.After(5 * time.Second):
+        t.Fatal("the worker never started")
+    }
+    require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGINT))
+
+    select {
+    case err := <-done:
+        assert.NoError(t, err)
+    case <-time.After(5 * time.Second):
+        t.Fatal("the server did not stop")
+    }
+    assert.Equal(t, []string{"start database", "start hub", "worker stopped", "stop jobs", "stop hub", "stop database"}, l.recorded(),
+        "a failing stop hook does not keep the rest from running")
+}
+
+// TestServerFailedStart verifies a server whose start hook fails never runs its workers and
+// still runs every stop hook, for the components started before it
+func TestServerFailedStart(t *testing.T) {
+    var l lifecycle
+    server := bootstrap.New("bootstrap-test", 0).
+        OnStart("database", l.hook("start database", nil)).
+        OnStop("database", l.hook("stop database", nil)).
+        OnStart("hub", l.hook("start hub", errors.New("port in use"))).
+        OnStop("hub", l.hook("stop hub", nil)).
+        OnStart("jobs", l.hook("start jobs", nil)).
+        Go("worker", func(ctx context.Context) { l.record("worker ran") })
+
+    err := server.Run()
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "failed to start hub: port in use")
+    assert.Equal(t, []string{"start database", "start hub", "stop hub", "stop database"}, l.recorded())
+}
+
+// publishAdminStatus publishes the admin_test expvar once, as expvar names cannot be reused
+var publishAdminStatus sync.Once
+
+// freePort returns a port nothing is listening on
+func freePort(t *testing.T) int {
+    t.Helper()
+    listener, err := net.Listen("tcp", "127.0.0.1:0")
+    require.NoError(t, err)
+    defer listener.Close()
+    return listener.Addr().(*net.TCPAddr).Port
+}
+
+// TestServerAdminPort verifies profiles, runtime and published variables and admin handlers
+// are served on the admin port only, never next to the service's own routes
+func TestServerAdminPort(t *testing.T) {
+    publishAdminStatus.Do(func() {
+        bootstrap.Publish("admin_test", func() interface{} { return map[string]int{"queued": 3} })
+    })
+
+    servicePort, adminPort := freePort(t), freePort(t)
+    server := bootstrap.New("bootstrap-test", servicePort).
+        WithAdminPort(adminPort).
+        HandleFunc("/api/v1/ping", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "pong") }).
+        HandleAdmin("/admin/reload", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "reloaded") }))
+
+    done := make(chan error, 1)
+    go func() { done <- server.Run() }()
+
+    get := func(port int, path string) (int, string) {
+        resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d%s", port, path))
+        if err != nil {
+            return 0, err.Error()
+        }
+        defer resp.Body.Close()
+        body, _ := io.ReadAll(resp.Body)
+        return resp.StatusCode, string(body)
+    }
+    require.Eventually(t, func() bool {
+        status, _ := get(adminPort, "/debug/pprof/")
+        serviceStatus, _ := get(servicePort, "/api/v1/ping")
+        return status == http.StatusOK && serviceStatus == http.StatusOK
+    }, 5*time.Second, 20*time.Millisecond)
+
+    status, body := get(adminPort, "/debug/vars")
+    require.Equal(t, http.StatusOK, status)
+    var vars map[string]json.RawMessage
+    require.NoError(t, json.Unmarshal([]byte(body), &vars))
+    for _, name := range []string{"memstats", "goroutines", "gc"} {
+        assert.Contains(t, vars, name)
+    }
+    assert.JSONEq(t, `{"queued": 3}`, string(vars["admin_test"]))
+
+    status, body = get(adminPort, "/admin/reload")
+    assert.Equal(t, http.StatusOK, status)
+    assert.Equal(t, "reloaded", body)
+
+    for _, path := range []string{"/debug/vars", "/debug/pprof/", "/admin/reload"} {
+        status, _ := get(servicePort, path)
+        assert.Equal(t, http.StatusNotFound, status, path)
+    }
+    status, _ = get(adminPort, "/api/v1/ping")
+    assert.Equal(t, http.StatusNotFound, status)
+
+    require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGINT))
+    select {
+    case err := <-done:
+        assert.NoError(t, err)
+    case <-time.After(5 * time.Second):
+        t.Fatal("the server did not stop")
+    }
+    status, _ = get(adminPort, "/debug/vars")
+    assert.Zero(t, status, "the admin port closes with the server")
+}
@@ -0,0 +1,165 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "net/url"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/integrations"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// TestMemoryStoreCalendar verifies walkers' calendar feeds list their confirmed walks and
+// are only served to signed URLs
+func TestMemoryStoreCalendar(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    _, err := service.WalkerCalendarPathService("walker-1")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "calendar feeds unavailable")
+
+    config.Config.CalendarSecret = "calendar-secret"
+    path, err := service.WalkerCalendarPathService("walker-1")
+    require.NoError(t, err)
+    require.True(t, strings.HasPrefix(path, "/api/v1/calendars/walker-1.ics?token="))
+    token := strings.TrimPrefix(path, "/api/v1/calendars/walker-1.ics?token=")
+
+    start := time.Date(2030, time.March, 4, 9, 30, 0, 0, time.UTC)
+    confirmed := memoryBooking("booking-confirmed", "walker-1", start)
+    confirmed.Status = models.BookingStatusConfirmed
+    confirmed.Region = "nyc-brooklyn, south"
+    require.NoError(t, repository.CreateBooking(ctx, confirmed))
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("booking-pending", "walker-1", start.Add(time.Hour))))
+    other := memoryBooking("booking-other", "walker-2", start)
+    other.Status = models.BookingStatusConfirmed
+    require.NoError(t, repository.CreateBooking(ctx, other))
+
+    _, err = service.WalkerCalendarService(ctx, "walker-2", token)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "calendar not found")
+
+    feed, err := service.WalkerCalendarService(ctx, "walker-1", token)
+    require.NoError(t, err)
+    ics := string(feed)
+    assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+    assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+    assert.Equal(t, 1, strings.Count(ics, "BEGIN:VEVENT"))
+    assert.Contains(t, ics, "UID:booking-confirmed@booking-service\r\n")
+    assert.Contains(t, ics, "DTSTART:20300304T093000Z\r\n")
+    assert.Contains(t, ics, "DTEND:20300304T100000Z\r\n")
+    assert.Contains(t, ics, `LOCATION:nyc-brooklyn\, south`)
+    assert.NotContains(t, ics, "booking-pending")
+    for _, line := range strings.Split(ics, "\r\n") {
+        assert.LessOrEqual(t, len(line), 75)
+    }
+}
+
+// fakeCalendar is a calendar provider holding events in memory
+type fakeCalendar struct {
+    events    map[string]integrations.Event
+    refreshed int
+}
+
+func (f *fakeCalendar) AuthCodeURL(state string) string {
+    return "https://calendar.example/auth?state=" + url.QueryEscape(state)
+}
+
+func (f *fakeCalendar) Exchange(ctx context.Context, code string) (*integrations.Token, error) {
+    // An expired token makes the first write refresh it
+    return &integrations.Token{AccessToken: "access-" + code, RefreshToken: "refresh", ExpiresAt: time.Now().Add(-time.Minute)}, nil
+}
+
+func (f *fakeCalendar) Refresh(ctx context.Context, refreshToken string) (*integrations.Token, error) {
+    f.refreshed++
+    return &integrations.Token{AccessToken: "refreshed", RefreshToken: refreshToken, ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func (f *fakeCalendar) SaveEvent(ctx context.Context, accessToken, eventID string, event integrations.Event) (string, error) {
+    if eventID == "" {
+        eventID = "event-" + event.Start.Format("150405")
+    } else if _, ok := f.events[eventID]; !ok {
+        return "", integrations.ErrEventNotFound
+    }
+    f.events[eventID] = event
+    return eventID, nil
+}
+
+func (f *fakeCalendar) DeleteEvent(ctx context.Context, accessToken, eventID string) error {
+    delete(f.events, eventID)
+    return nil
+}
+
+func TestMemoryStoreCalendarSync(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{CalendarSecret: "calendar-secret"})
+    fake := &fakeCalendar{events: map[string]integrations.Event{}}
+    integrations.Init(integrations.Options{})
+    integrations.Register("fake", fake)
+    t.Cleanup(func() { integrations.Init(integrations.Options{}) })
+
+    _, err := service.CalendarConnectURLService(ctx, "walker-1", "unknown")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid calendar provider")
+
+    start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+    booking := memoryBooking("booking-1", "walker-1", start)
+    booking.Status = models.BookingStatusConfirmed
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    authURL, err := service.CalendarConnectURLService(ctx, "walker-1", "fake")
+    require.NoError(t, err)
+    parsed, err := url.Parse(authURL)
+    require.NoError(t, err)
+    state := parsed.Query().Get("state")
+
+    _, err = service.CompleteCalendarConnectionService(ctx, state+"x", "code")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid calendar connection")
+
+    connection, err := service.CompleteCalendarConnectionService(ctx, state, "code")
+    require.NoError(t, err)
+    assert.Equal(t, "walker-1", connection.WalkerID)
+    assert.Equal(t, "fake", connection.Provider)
+
+    // Connecting writes the walker's upcoming confirmed walk, refreshing the expired token
+    require.Len(t, fake.events, 1)
+    assert.Equal(t, 1, fake.refreshed)
+    written, err := repository.ListCalendarEvents(ctx, "booking-1")
+    require.NoError(t, err)
+    require.Len(t, written, 1)
+    assert.Equal(t, start, fake.events[written[0].EventID].Start)
+    connections, err := service.ListCalendarConnectionsService(ctx, "walker-1")
+    require.NoError(t, err)
+    require.Len(t, connections, 1)
+    assert.Equal(t, "refreshed", connections[0].AccessToken)
+
+    // Cancelling the booking removes its event
+    _, err = service.ForceStatusService(ctx, "admin-1", "booking-1", models.BookingStatusCancelled, "owner called")
+    require.NoError(t, err)
+    assert.Empty(t, fake.events)
+    written, err = repository.ListCalendarEvents(ctx, "booking-1")
+    require.NoError(t, err)
+    assert.Empty(t, written)
+
+    require.NoError(t, service.DisconnectCalendarService(ctx, "walker-1", "fake"))
+    err = service.DisconnectCalendarService(ctx, "walker-1", "fake")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "calendar not found")
+}
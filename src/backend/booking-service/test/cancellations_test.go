@@ -0,0 +1,87 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// TestMemoryStoreCancellation verifies owners are charged the fee of the notice they give when
+// cancelling, and refunded the rest
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreCancellation(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    _, err := models.ParseCancellationPolicy("24h=0,2h=150")
+    require.Error(t, err)
+    policy, err := models.ParseCancellationPolicy("2h=50,0s=100,24h=0")
+    require.NoError(t, err)
+    assert.Equal(t, models.DefaultCancellationPolicy(), policy)
+
+    useConfig(t, &config.Config{CancellationPolicy: policy})
+
+    refunder := &fakeRefunder{}
+    ctx = service.WithProviders(ctx, service.Providers{Refunds: refunder})
+
+    for id, notice := range map[string]time.Duration{
+        "cancel-early": 48 * time.Hour,
+        "cancel-late":  5 * time.Hour,
+        "cancel-last":  time.Hour,
+    } {
+        booking := memoryBooking(id, "walker-1", time.Now().Add(notice))
+        booking.Status = models.BookingStatusConfirmed
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+
+    _, err = service.CancelBookingService(ctx, "cancel-early", "owner-cancel-late")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "only the booking's owner")
+
+    early, err := service.CancelBookingService(ctx, "cancel-early", "owner-cancel-early")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCancelled, early.Status)
+    assert.Zero(t, early.Cancellation.Fee)
+    assert.InDelta(t, 25.50, early.Cancellation.Refund, 0.001)
+    assert.Equal(t, "re_cancellation-cancel-early", early.Cancellation.RefundID)
+
+    late, err := service.CancelBookingService(ctx, "cancel-late", "owner-cancel-late")
+    require.NoError(t, err)
+    assert.Equal(t, 50.0, late.Cancellation.FeePercent)
+    assert.InDelta(t, 12.75, late.Cancellation.Fee, 0.001)
+    assert.InDelta(t, 12.75, late.Cancellation.Refund, 0.001)
+
+    // Cancelling at the last minute costs the full total, so nothing is refunded
+    last, err := service.CancelBookingService(ctx, "cancel-last", "owner-cancel-last")
+    require.NoError(t, err)
+    assert.InDelta(t, 25.50, last.Cancellation.Fee, 0.001)
+    assert.Zero(t, last.Cancellation.Refund)
+
+    require.Len(t, refunder.refunds, 2)
+    assert.Equal(t, int64(2550), refunder.refunds[0].AmountCents)
+    assert.Equal(t, int64(1275), refunder.refunds[1].AmountCents)
+
+    // Cancelling again returns the cancelled booking without refunding twice
+    again, err := service.CancelBookingService(ctx, "cancel-late", "owner-cancel-late")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCancelled, again.Status)
+    assert.Len(t, refunder.refunds, 2)
+
+    stored, err := service.GetBookingService(ctx, "cancel-late")
+    require.NoError(t, err)
+    require.NotNil(t, stored.Cancellation)
+    assert.InDelta(t, 12.75, stored.Cancellation.Fee, 0.001)
+    assert.Equal(t, "re_cancellation-cancel-late", stored.Cancellation.RefundID)
+}
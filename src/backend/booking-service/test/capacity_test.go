@@ -0,0 +1,187 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+)
+
+// TestCreateBookingWithinCapacity verifies bookings are priced for the dogs already in their
+// slot, that a failed adjustment stores nothing and that full slots are refused
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestCreateBookingWithinCapacity(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    start := time.Now().Add(24 * time.Hour)
+
+    var seen []int
+    adjust := func(b *models.Booking, overlapping int) error {
+        seen = append(seen, overlapping)
+        return nil
+    }
+    require.NoError(t, repository.CreateBookingWithinCapacity(ctx, memoryBooking("slot-first", "walker-slot", start), 2, adjust))
+
+    // An adjustment that fails leaves the slot as it was
+    failed := errors.New("tax provider unavailable")
+    err := repository.CreateBookingWithinCapacity(ctx, memoryBooking("slot-failed", "walker-slot", start), 2,
+        func(b *models.Booking, overlapping int) error { return failed })
+    assert.ErrorIs(t, err, failed)
+    _, err = repository.GetBookingByID(ctx, "slot-failed")
+    assert.Error(t, err)
+
+    require.NoError(t, repository.CreateBookingWithinCapacity(ctx, memoryBooking("slot-second", "walker-slot", start.Add(10*time.Minute)), 2, adjust))
+    assert.Equal(t, []int{0, 1}, seen)
+
+    err = repository.CreateBookingWithinCapacity(ctx, memoryBooking("slot-third", "walker-slot", start), 2, adjust)
+    assert.ErrorIs(t, err, repository.ErrSlotFull)
+    assert.Len(t, seen, 2, "full slots are refused before the booking is priced")
+
+    // Walks after the slot and cancelled walks in it take no place
+    require.NoError(t, repository.CreateBookingWithinCapacity(ctx, memoryBooking("slot-later", "walker-slot", start.Add(2*time.Hour)), 2, adjust))
+    cancelled := memoryBooking("slot-cancelled", "walker-slot", start)
+    cancelled.Status = models.BookingStatusCancelled
+    require.NoError(t, repository.CreateBooking(ctx, cancelled))
+    require.NoError(t, repository.CreateBookingWithinCapacity(ctx, memoryBooking("slot-other-walker", "walker-other", start), 1, adjust))
+    assert.Equal(t, []int{0, 1, 0, 0}, seen)
+}
+
+// TestGroupWalkDiscount verifies additional dogs in a group walk get the slot's discount on
+// the price the service worked out, but never on an amount the client sent
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestGroupWalkDiscount(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+    for _, walkerID := range []string{"walker-group-rated", "walker-group-unrated"} {
+        _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+            WalkerID:  walkerID,
+            Status:    models.WalkerVerified,
+            UpdatedBy: "admin-1",
+            UpdatedAt: time.Now(),
+        })
+        require.NoError(t, err)
+        require.NoError(t, service.CreateAvailabilityService(ctx, &models.Availability{
+            ID:                   "availability-" + walkerID,
+            WalkerID:             walkerID,
+            StartsAt:             start.Add(-time.Hour),
+            EndsAt:               start.Add(2 * time.Hour),
+            Capacity:             3,
+            GroupDiscountPercent: 20,
+        }))
+    }
+    require.NoError(t, service.SaveRatePlanService(ctx, &models.RatePlan{WalkerID: "walker-group-rated", BaseRate: 20}))
+
+    first := memoryBooking("group-first", "walker-group-rated", start)
+    require.NoError(t, service.CreateBookingService(ctx, first))
+    second := memoryBooking("group-second", "walker-group-rated", start)
+    require.NoError(t, service.CreateBookingService(ctx, second))
+    assert.Equal(t, 20.0, first.Amount)
+    assert.Equal(t, 16.0, second.Amount)
+
+    // Without rates the client's amount stands as sent, undiscounted
+    for _, id := range []string{"group-unrated-first", "group-unrated-second"} {
+        booking := memoryBooking(id, "walker-group-unrated", start)
+        require.NoError(t, service.CreateBookingService(ctx, booking))
+        assert.Equal(t, 25.50, booking.Amount)
+    }
+}
+
+// TestCreateAvailabilityAsWalker checks that walkers publish only their own availability,
+// whoever the body names
+func TestCreateAvailabilityAsWalker(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    create := middleware.RequirePermission(actionsSecret, policy.ResourceAvailability, policy.ActionCreate)(handlers.CreateAvailabilityHandler)
+    start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Hour)
+    window := `{"id": "window-1", "walker_id": "walker-window", "starts_at": "` + start.Format(time.RFC3339) +
+        `", "ends_at": "` + start.Add(3*time.Hour).Format(time.RFC3339) + `", "capacity": 2}`
+
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, create, http.MethodPost, "/api/v1/availability", "", "", window).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, create, http.MethodPost, "/api/v1/availability", "owner-window", policy.RoleOwner, window).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, create, http.MethodPost, "/api/v1/availability", "walker-other", policy.RoleWalker, window).Code,
+        "walkers cannot publish each other's availability")
+
+    response := callAs(t, create, http.MethodPost, "/api/v1/availability", "walker-window", policy.RoleWalker, window)
+    require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+    windows, err := service.ListAvailabilityService(ctx, "walker-window")
+    require.NoError(t, err)
+    require.Len(t, windows, 1)
+    assert.Equal(t, 2, windows[0].Capacity)
+}
+
+// TestMemoryStoreCapacity verifies the capacity report counts booked dogs and walker capacity
+// per region and hour, and flags hours with a shortfall
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreCapacity(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+    windows := []models.Availability{
+        {ID: "north-1", WalkerID: "walker-1", StartsAt: day.Add(9 * time.Hour), EndsAt: day.Add(11 * time.Hour), Capacity: 2, Region: "north"},
+        {ID: "north-2", WalkerID: "walker-2", StartsAt: day.Add(9 * time.Hour), EndsAt: day.Add(10 * time.Hour), Capacity: 1, Region: "north"},
+        {ID: "south-1", WalkerID: "walker-3", StartsAt: day.Add(9 * time.Hour), EndsAt: day.Add(10 * time.Hour), Capacity: 1, Region: "south"},
+    }
+    for i := range windows {
+        require.NoError(t, repository.CreateAvailability(ctx, &windows[i]))
+    }
+
+    bookings := []*models.Booking{
+        memoryBooking("north-a", "walker-1", day.Add(9*time.Hour)),
+        memoryBooking("north-b", "", day.Add(10*time.Hour+45*time.Minute)),
+        memoryBooking("north-c", "walker-1", day.Add(10*time.Hour)),
+        memoryBooking("south-a", "walker-3", day.Add(9*time.Hour)),
+        memoryBooking("south-b", "", day.Add(9*time.Hour+30*time.Minute)),
+        memoryBooking("south-done", "walker-3", day.Add(9*time.Hour)),
+    }
+    bookings[5].Status = models.BookingStatusCancelled
+    for _, booking := range bookings {
+        booking.Region = strings.SplitN(booking.ID, "-", 2)[0]
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+
+    report, err := service.CapacityReportService(ctx, day.Add(9*time.Hour), day.Add(11*time.Hour+30*time.Minute), "")
+    require.NoError(t, err)
+    assert.Equal(t, day.Add(12*time.Hour), report.To)
+
+    slots := make(map[string]models.CapacitySlot)
+    for _, slot := range report.Slots {
+        slots[slot.Region+"@"+slot.Hour.Format("15")] = slot
+    }
+    assert.Equal(t, models.CapacitySlot{Region: "north", Hour: day.Add(9 * time.Hour), Demand: 1, Supply: 3, Walkers: 2}, slots["north@09"])
+    assert.Equal(t, models.CapacitySlot{Region: "north", Hour: day.Add(10 * time.Hour), Demand: 2, Unassigned: 1, Supply: 2, Walkers: 1}, slots["north@10"])
+    assert.Equal(t, models.CapacitySlot{Region: "north", Hour: day.Add(11 * time.Hour), Demand: 1, Unassigned: 1, Shortfall: 1}, slots["north@11"])
+    assert.Equal(t, models.CapacitySlot{Region: "south", Hour: day.Add(9 * time.Hour), Demand: 2, Unassigned: 1, Supply: 1, Walkers: 1, Shortfall: 1}, slots["south@09"])
+    assert.Equal(t, 2, report.ThinSlots)
+
+    south, err := service.CapacityReportService(ctx, day, day.Add(24*time.Hour), " South ")
+    require.NoError(t, err)
+    require.Len(t, south.Slots, 1)
+    assert.Equal(t, "south", south.Slots[0].Region)
+
+    _, err = service.CapacityReportService(ctx, day, day.Add(40*24*time.Hour), "")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid report range")
+}
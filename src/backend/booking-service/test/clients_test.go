@@ -0,0 +1,132 @@
+package test
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/shared/clients"
+)
+
+// TestServiceClientRetries verifies reads are retried through transient failures but not
+// through responses that will not change
+// Addresses requirement: Technical Specification/7.2.1 Core Components
+func TestServiceClientRetries(t *testing.T) {
+    var calls int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        assert.Equal(t, "partner-key", r.Header.Get(clients.APIKeyHeader))
+        switch {
+        case strings.HasSuffix(r.URL.Path, "/missing"):
+            atomic.AddInt32(&calls, 1)
+            http.Error(w, "booking not found", http.StatusNotFound)
+        case atomic.AddInt32(&calls, 1) < 3:
+            http.Error(w, "unavailable", http.StatusServiceUnavailable)
+        default:
+            json.NewEncoder(w).Encode(map[string]interface{}{
+                "success": true,
+                "data":    map[string]interface{}{"id": "b1", "walker_id": "w1", "status": "confirmed"},
+            })
+        }
+    }))
+    defer server.Close()
+
+    client := clients.NewBookingClient(server.URL, clients.Options{APIKey: "partner-key", Backoff: time.Millisecond})
+
+    booking, err := client.GetBooking(context.Background(), "b1")
+    require.NoError(t, err)
+    assert.Equal(t, "w1", booking.WalkerID)
+    assert.Equal(t, "confirmed", booking.Status)
+    assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "two failures are retried")
+
+    atomic.StoreInt32(&calls, 0)
+    _, err = client.GetBooking(context.Background(), "missing")
+    assert.True(t, errors.Is(err, clients.ErrNotFound))
+    assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "a missing booking is not retried")
+}
+
+// TestServiceClientCircuitBreaker verifies a failing service is left alone until the cooldown
+// passes, then tried once
+func TestServiceClientCircuitBreaker(t *testing.T) {
+    var calls, healthy int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        atomic.AddInt32(&calls, 1)
+        if atomic.LoadInt32(&healthy) == 0 {
+            http.Error(w, "unavailable", http.StatusServiceUnavailable)
+            return
+        }
+        json.NewEncoder(w).Encode(map[string]interface{}{"walker_id": "w1", "latitude": 40.7})
+    }))
+    defer server.Close()
+
+    client := clients.NewTrackingClient(server.URL, clients.Options{
+        Retries:          -1,
+        BreakerThreshold: 2,
+        BreakerCooldown:  50 * time.Millisecond,
+    })
+    ctx := context.Background()
+
+    for i := 0; i < 2; i++ {
+        _, err := client.WalkerPosition(ctx, "w1")
+        var statusErr *clients.StatusError
+        require.True(t, errors.As(err, &statusErr))
+        assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+    }
+    _, err := client.WalkerPosition(ctx, "w1")
+    assert.True(t, errors.Is(err, clients.ErrCircuitOpen))
+    assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "an open circuit makes no call")
+
+    atomic.StoreInt32(&healthy, 1)
+    time.Sleep(60 * time.Millisecond)
+    position, err := client.WalkerPosition(ctx, "w1")
+    require.NoError(t, err)
+    assert.Equal(t, 40.7, position.Latitude)
+
+    _, err = client.WalkerPosition(ctx, "w1")
+    assert.NoError(t, err, "a successful trial call closes the circuit")
+}
+
+// TestServiceClientTracePropagation verifies calls made while serving a request carry its trace
+func TestServiceClientTracePropagation(t *testing.T) {
+    var received string
+    tracking := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        received = r.Header.Get(clients.TraceparentHeader)
+        json.NewEncoder(w).Encode(map[string]interface{}{"booking_id": "b1", "duration_seconds": 1800, "photos": 2})
+    }))
+    defer tracking.Close()
+    client := clients.NewTrackingClient(tracking.URL, clients.Options{})
+
+    const incoming = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+    var traceID string
+    handler := clients.Tracing(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        traceID = clients.TraceID(r.Context())
+        evidence, err := client.WalkEvidence(r.Context(), "b1")
+        require.NoError(t, err)
+        assert.Equal(t, 30*time.Minute, evidence.Duration())
+    }))
+
+    req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/b1", nil)
+    req.Header.Set(clients.TraceparentHeader, incoming)
+    handler.ServeHTTP(httptest.NewRecorder(), req)
+
+    assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+    parts := strings.Split(received, "-")
+    require.Len(t, parts, 4)
+    assert.Equal(t, traceID, parts[1], "the trace continues")
+    assert.NotEqual(t, "00f067aa0ba902b7", parts[2], "under a span of its own")
+
+    // A malformed header starts a new trace rather than being passed on
+    req = httptest.NewRequest(http.MethodGet, "/api/v1/bookings/b1", nil)
+    req.Header.Set(clients.TraceparentHeader, "00-00000000000000000000000000000000-00f067aa0ba902b7-01")
+    handler.ServeHTTP(httptest.NewRecorder(), req)
+    assert.Len(t, traceID, 32)
+    assert.NotEqual(t, strings.Repeat("0", 32), traceID)
+}
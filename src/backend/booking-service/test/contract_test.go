@@ -0,0 +1,44 @@
+package test
+
+import (
+    "encoding/json"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/events"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/contracts"
+)
+
+// TestWalkerAcceptedEventContract verifies the booking.walker_accepted event still carries
+// everything tracking-service relies on to start a walk session
+// Addresses requirement: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func TestWalkerAcceptedEventContract(t *testing.T) {
+    expected, err := contracts.Fixture(contracts.BookingWalkerAccepted)
+    require.NoError(t, err)
+
+    // Build the event exactly as AcceptAssignmentService publishes it
+    booking := models.NewBooking("booking-1", "owner-1", "walker-1", "dog-1",
+        time.Now().Add(24*time.Hour), models.BookingStatusConfirmed, 30)
+    booking.DurationMinutes = 45
+
+    actual, err := json.Marshal(events.Event{
+        Type:       service.EventWalkerAccepted,
+        OccurredAt: time.Now().UTC(),
+        Data:       booking,
+    })
+    require.NoError(t, err)
+
+    assert.NoError(t, contracts.Satisfies(expected, actual))
+
+    // The event type is part of the contract, not just the shape
+    var envelope struct {
+        Type string `json:"type"`
+    }
+    require.NoError(t, json.Unmarshal(expected, &envelope))
+    assert.Equal(t, envelope.Type, service.EventWalkerAccepted)
+}
@@ -0,0 +1,38 @@
+package test
+
+import (
+    "bytes"
+    "errors"
+    "log"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert" // v1.8.0
+
+    "src/backend/shared/dbmetrics"
+)
+
+// TestSlowStatementLogging verifies only statements slower than the threshold are logged, and
+// only with the description they were given
+// Addresses requirement: Technical Specification/7.2.1 Core Components
+func TestSlowStatementLogging(t *testing.T) {
+    var logged bytes.Buffer
+    log.SetOutput(&logged)
+    t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+    recorder := dbmetrics.New("test", 100*time.Millisecond)
+    described := false
+    recorder.Observe("SELECT bookings", 5*time.Millisecond, nil, func() string {
+        described = true
+        return "SELECT * FROM bookings WHERE id = $1 [$1=string(36)]"
+    })
+    assert.False(t, described, "fast statements are not described")
+    assert.Empty(t, logged.String())
+
+    recorder.Observe("UPDATE bookings", 250*time.Millisecond, errors.New("deadlock detected"), func() string {
+        return "UPDATE bookings SET status = $2 WHERE id = $1 [$1=string(36) $2=string(9)]"
+    })
+    assert.Contains(t, logged.String(), "Slow UPDATE bookings statement took 250ms (error)")
+    assert.Contains(t, logged.String(), "[$1=string(36) $2=string(9)]")
+}
@@ -0,0 +1,118 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// fakeRefunder accepts refunds in place of the payment-service, failing while fail is set
+type fakeRefunder struct {
+    refunds []payments.Refund
+    fail    bool
+}
+
+func (r *fakeRefunder) Refund(ctx context.Context, refund payments.Refund) (*payments.RefundResult, error) {
+    if r.fail {
+        return nil, errors.New("payment-service unavailable")
+    }
+    r.refunds = append(r.refunds, refund)
+    return &payments.RefundResult{RefundID: "re_" + refund.Reference, Status: "succeeded", AmountCents: refund.AmountCents}, nil
+}
+
+// TestMemoryStoreDisputes verifies owners can dispute finished bookings once and that a
+// dispute is refunded at most once, staying open when the refund fails
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreDisputes(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    refunder := &fakeRefunder{}
+    ctx = service.WithProviders(ctx, service.Providers{Refunds: refunder})
+
+    start := time.Now().Add(-24 * time.Hour).Truncate(time.Minute)
+    for id, status := range map[string]models.BookingStatus{
+        "dispute-walked":   models.BookingStatusCompleted,
+        "dispute-failed":   models.BookingStatusFailed,
+        "dispute-upcoming": models.BookingStatusConfirmed,
+    } {
+        booking := memoryBooking(id, "walker-1", start)
+        booking.Status = status
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+
+    _, err := service.OpenDisputeService(ctx, "dispute-upcoming", "owner-dispute-upcoming", "Walker never arrived")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "dispute not allowed")
+
+    _, err = service.OpenDisputeService(ctx, "dispute-walked", "owner-dispute-failed", "Walk was cut short")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "only the booking's owner")
+
+    walked, err := service.OpenDisputeService(ctx, "dispute-walked", "owner-dispute-walked", "Walk was cut short")
+    require.NoError(t, err)
+    assert.Equal(t, models.DisputeOpen, walked.Status)
+
+    _, err = service.OpenDisputeService(ctx, "dispute-walked", "owner-dispute-walked", "Again")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "dispute conflict")
+
+    failed, err := service.OpenDisputeService(ctx, "dispute-failed", "owner-dispute-failed", "Dog was not walked")
+    require.NoError(t, err)
+
+    open, err := service.ListDisputesService(ctx, models.DisputeOpen, 0)
+    require.NoError(t, err)
+    assert.Len(t, open, 2)
+
+    reviewed, err := service.ReviewDisputeService(ctx, "admin-1", walked.ID)
+    require.NoError(t, err)
+    assert.Equal(t, models.DisputeUnderReview, reviewed.Status)
+
+    _, err = service.ResolveDisputeService(ctx, "admin-1", walked.ID, true, 30, "Walk lasted ten minutes")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid dispute resolution")
+
+    // A failed refund leaves the dispute to be resolved again
+    refunder.fail = true
+    _, err = service.ResolveDisputeService(ctx, "admin-1", walked.ID, true, 10, "Walk lasted ten minutes")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "failed to refund dispute")
+    stored, err := service.GetDisputeService(ctx, walked.ID)
+    require.NoError(t, err)
+    assert.Equal(t, models.DisputeUnderReview, stored.Status)
+
+    refunder.fail = false
+    resolved, err := service.ResolveDisputeService(ctx, "admin-1", walked.ID, true, 10, "Walk lasted ten minutes")
+    require.NoError(t, err)
+    assert.Equal(t, models.DisputeResolvedRefund, resolved.Status)
+    assert.Equal(t, "re_"+walked.ID, resolved.RefundID)
+    require.Len(t, refunder.refunds, 1)
+    assert.Equal(t, int64(1000), refunder.refunds[0].AmountCents)
+
+    _, err = service.ResolveDisputeService(ctx, "admin-2", walked.ID, true, 0, "Full refund")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "dispute conflict")
+    assert.Len(t, refunder.refunds, 1)
+
+    denied, err := service.ResolveDisputeService(ctx, "admin-1", failed.ID, false, 0, "Booking was already refunded")
+    require.NoError(t, err)
+    assert.Equal(t, models.DisputeResolvedDenied, denied.Status)
+    assert.Len(t, refunder.refunds, 1)
+
+    owned, err := service.GetBookingDisputeService(ctx, "dispute-failed", "owner-dispute-failed")
+    require.NoError(t, err)
+    assert.Equal(t, "Booking was already refunded", owned.Resolution)
+}
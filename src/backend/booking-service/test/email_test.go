@@ -0,0 +1,230 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "bytes"
+    "context"
+    "image/png"
+    "mime"
+    "net/http"
+    "net/http/httptest"
+    "net/mail"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/i18n"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/moderation"
+    "src/backend/shared/policy"
+)
+
+// fakeMailer records the messages it is asked to send
+type fakeMailer struct {
+    sent map[string][]string
+}
+
+func (f *fakeMailer) Send(ctx context.Context, from, address string, message []byte) error {
+    f.sent[address] = append(f.sent[address], string(message))
+    return nil
+}
+
+// TestMemoryStoreEmail verifies owners are emailed as their booking is confirmed and sent a
+// summary of the walk with a map of its route
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func TestMemoryStoreEmail(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    mailer := &fakeMailer{sent: map[string][]string{}}
+    ctx = service.WithProviders(ctx, service.Providers{Notifier: notifier.NewEmailNotifier(mailer, "DogWalker <walks@example.com>", notifier.LogNotifier{})})
+
+    start := time.Date(2030, 3, 4, 9, 30, 0, 0, time.UTC)
+    booking := memoryBooking("booking-1", "walker-1", start)
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+    require.NoError(t, service.RecordUserEmailService(ctx, booking.OwnerID, "owner@example.com"))
+
+    _, err := repository.AssignWalker(ctx, booking.ID, "walker-1", 1, time.Now().Add(time.Hour))
+    require.NoError(t, err)
+    _, err = service.AcceptAssignmentService(ctx, booking.ID, "walker-1")
+    require.NoError(t, err)
+    require.Len(t, mailer.sent["owner@example.com"], 1)
+    confirmed, err := mail.ReadMessage(strings.NewReader(mailer.sent["owner@example.com"][0]))
+    require.NoError(t, err)
+    assert.Equal(t, "Your dog walk is confirmed", confirmed.Header.Get("Subject"))
+    from, err := confirmed.Header.AddressList("From")
+    require.NoError(t, err)
+    assert.Equal(t, "walks@example.com", from[0].Address)
+    assert.Contains(t, mailer.sent["owner@example.com"][0], "Monday 4 March at 09:30 UTC")
+
+    route := []models.RoutePoint{
+        {Latitude: 51.5000, Longitude: -0.1200, Timestamp: start},
+        {Latitude: 51.5090, Longitude: -0.1200, Timestamp: start.Add(15 * time.Minute)},
+        {Latitude: 51.5090, Longitude: -0.1050, Timestamp: start.Add(30 * time.Minute)},
+    }
+    _, err = service.SendWalkSummaryService(ctx, &models.WalkSummary{BookingID: booking.ID, WalkerID: "walker-2", Route: route})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid walk summary")
+    _, err = service.SendWalkSummaryService(ctx, &models.WalkSummary{BookingID: booking.ID, WalkerID: "walker-1"})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid walk summary")
+
+    summary, err := service.SendWalkSummaryService(ctx, &models.WalkSummary{
+        BookingID: booking.ID,
+        WalkerID:  "walker-1",
+        Notes:     "Met <three> other dogs",
+        Route:     route,
+    })
+    require.NoError(t, err)
+    assert.Equal(t, 30, summary.DurationMinutes)
+    assert.InDelta(t, 2.04, summary.DistanceKm, 0.01)
+
+    require.Len(t, mailer.sent["owner@example.com"], 2)
+    message := mailer.sent["owner@example.com"][1]
+    assert.Contains(t, message, "multipart/related")
+    assert.Contains(t, message, "Content-Id: <route-map>")
+    assert.Contains(t, message, "Content-Type: image/png")
+    assert.Contains(t, message, "Met &lt;three&gt; other dogs")
+
+    // Blocked notes are rejected; flagged notes are kept out of the email
+    moderation.Init(moderation.Options{BlockedTerms: []string{"idiot"}, FlaggedTerms: []string{"bit"}})
+    t.Cleanup(func() { moderation.Init(moderation.Options{}) })
+    _, err = service.SendWalkSummaryService(ctx, &models.WalkSummary{
+        BookingID: booking.ID, WalkerID: "walker-1", Notes: "Your dog is an IDIOT!", Route: route,
+    })
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid walk summary")
+    summary, err = service.SendWalkSummaryService(ctx, &models.WalkSummary{
+        BookingID: booking.ID, WalkerID: "walker-1", Notes: "Nearly bit a cyclist", Route: route,
+    })
+    require.NoError(t, err)
+    assert.True(t, summary.NotesWithheld)
+    require.Len(t, mailer.sent["owner@example.com"], 3)
+    assert.NotContains(t, mailer.sent["owner@example.com"][2], "cyclist")
+
+    routeMap, err := notifier.RenderRouteMap([]notifier.RoutePoint{{Latitude: 51.5, Longitude: -0.12}})
+    require.NoError(t, err)
+    bounds, err := png.DecodeConfig(bytes.NewReader(routeMap))
+    require.NoError(t, err)
+    assert.Equal(t, 600, bounds.Width)
+    _, err = notifier.RenderRouteMap(nil)
+    require.Error(t, err)
+
+    // Cancelled bookings can no longer be summarised, and their owner is told by email
+    _, err = service.ForceStatusService(ctx, "admin-1", booking.ID, models.BookingStatusCancelled, "owner called")
+    require.NoError(t, err)
+    require.Len(t, mailer.sent["owner@example.com"], 4)
+    assert.Contains(t, mailer.sent["owner@example.com"][3], "has been cancelled")
+    _, err = service.SendWalkSummaryService(ctx, &models.WalkSummary{BookingID: booking.ID, WalkerID: "walker-1", Route: route})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking conflict")
+
+    _, err = notifier.RenderEmail("unknown", notifier.EmailData{})
+    require.Error(t, err)
+}
+
+// TestWalkSummaryAsWalker checks that walkers summarise walks as the user of their token,
+// whoever the body names
+func TestWalkSummaryAsWalker(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    mailer := &fakeMailer{sent: map[string][]string{}}
+    booking := memoryBooking("summary-token", "walker-summary", time.Now().Add(-time.Hour))
+    booking.Status = models.BookingStatusCompleted
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+    require.NoError(t, service.RecordUserEmailService(ctx, booking.OwnerID, "summary-owner@example.com"))
+
+    actions := withProviders(service.Providers{Notifier: notifier.NewEmailNotifier(mailer, "walks@example.com", notifier.LogNotifier{})}, bookingActions())
+    route := `"route": [{"latitude": 51.5, "longitude": -0.12, "timestamp": "2030-03-04T09:30:00Z"}]`
+    body := `{"walker_id": "walker-summary", ` + route + `}`
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, "/api/v1/bookings/summary-token/summary", "", "", body).Code)
+    assert.Equal(t, http.StatusBadRequest, callAs(t, actions, http.MethodPost, "/api/v1/bookings/summary-token/summary", "walker-intruder", policy.RoleWalker, body).Code,
+        "the body cannot name another walker")
+    assert.Empty(t, mailer.sent)
+
+    response := callAs(t, actions, http.MethodPost, "/api/v1/bookings/summary-token/summary", "walker-summary", policy.RoleWalker, `{`+route+`}`)
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    assert.Len(t, mailer.sent["summary-owner@example.com"], 1)
+}
+
+// TestMemoryStoreLocalization verifies API errors follow the request's Accept-Language and
+// notifications follow the language the user saved, falling back to English
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func TestMemoryStoreLocalization(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    // Every language translates every English message
+    for _, locale := range i18n.Supported() {
+        assert.Equal(t, i18n.Keys(i18n.DefaultLocale), i18n.Keys(locale), locale)
+    }
+    assert.Equal(t, "fr", i18n.Negotiate("de-DE, fr-CH;q=0.9, en;q=0.8"))
+    assert.Equal(t, "es", i18n.Negotiate("en;q=0.5, es-MX"))
+    assert.Equal(t, i18n.DefaultLocale, i18n.Negotiate("de, fr;q=0"))
+    assert.Equal(t, "unknown.key", i18n.T("es", "unknown.key"))
+
+    // Booking errors keep their English text for callers matching on it
+    invalid := memoryBooking("booking-1", "", time.Now().Add(time.Hour))
+    invalid.OwnerID = ""
+    err := service.CreateBookingService(ctx, invalid)
+    require.Error(t, err)
+    assert.Equal(t, "invalid booking data: owner ID is required", err.Error())
+    assert.Equal(t, "datos de reserva no válidos: el ID del dueño es obligatorio", i18n.Localize("es", err))
+
+    handler := i18n.Middleware(http.HandlerFunc(handlers.CreateBookingHandler))
+    request := httptest.NewRequest(http.MethodPost, "/api/v1/bookings", strings.NewReader("{"))
+    request.Header.Set("Accept-Language", "fr-CA, en;q=0.5")
+    response := httptest.NewRecorder()
+    handler.ServeHTTP(response, request)
+    assert.Equal(t, http.StatusBadRequest, response.Code)
+    assert.Equal(t, "fr", response.Header().Get("Content-Language"))
+    assert.Equal(t, "Corps de la requête invalide", strings.TrimSpace(response.Body.String()))
+
+    _, err = service.SaveNotificationPreferencesService(ctx, &models.NotificationPreferences{UserID: "owner-booking-2", Locale: "de"})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid notification preferences")
+    prefs, err := service.SaveNotificationPreferencesService(ctx, &models.NotificationPreferences{
+        UserID: "owner-booking-2", PushEnabled: true, Locale: "fr-CA",
+    })
+    require.NoError(t, err)
+    assert.Equal(t, "fr", prefs.Locale)
+
+    // The owner's confirmation email is written in the language they saved
+    mailer := &fakeMailer{sent: map[string][]string{}}
+    ctx = service.WithProviders(ctx, service.Providers{Notifier: notifier.NewEmailNotifier(mailer, "DogWalker <walks@example.com>", notifier.LogNotifier{})})
+
+    booking := memoryBooking("booking-2", "walker-1", time.Date(2030, 3, 4, 9, 30, 0, 0, time.UTC))
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+    require.NoError(t, service.RecordUserEmailService(ctx, booking.OwnerID, "owner@example.com"))
+    _, err = repository.AssignWalker(ctx, booking.ID, "walker-1", 1, time.Now().Add(time.Hour))
+    require.NoError(t, err)
+    _, err = service.AcceptAssignmentService(ctx, booking.ID, "walker-1")
+    require.NoError(t, err)
+    require.Len(t, mailer.sent["owner@example.com"], 1)
+    confirmed, err := mail.ReadMessage(strings.NewReader(mailer.sent["owner@example.com"][0]))
+    require.NoError(t, err)
+    subject, err := new(mime.WordDecoder).DecodeHeader(confirmed.Header.Get("Subject"))
+    require.NoError(t, err)
+    assert.Equal(t, "Votre promenade est confirmée", subject)
+
+    email, err := notifier.RenderEmail(notifier.EmailBookingConfirmed, notifier.EmailData{Locale: "fr", Time: "lundi 4 mars à 09:30 UTC"})
+    require.NoError(t, err)
+    assert.Contains(t, email.Body, `<html lang="fr">`)
+    assert.Contains(t, email.Body, "Un promeneur a accepté votre réservation pour le <strong>lundi 4 mars à 09:30 UTC</strong>.")
+    assert.Equal(t, "lundi 4 mars à 09:30 UTC", i18n.FormatTime("fr", booking.ScheduledAt))
+
+    body, err := notifier.RenderSMS(notifier.SMSWalkerEnRoute, notifier.SMSData{Locale: "es", ETAMinutes: 10})
+    require.NoError(t, err)
+    assert.Equal(t, "Tu paseador va de camino y llegará en unos 10 min. Responde STOP para darte de baja.", body)
+}
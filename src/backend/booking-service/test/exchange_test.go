@@ -0,0 +1,92 @@
+package test
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/exchange"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/service"
+)
+
+// TestExchangeRatesCached verifies rates are fetched once per TTL and served stale when the
+// rates API fails
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func TestExchangeRatesCached(t *testing.T) {
+    calls := 0
+    failing := false
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        calls++
+        if failing {
+            http.Error(w, "unavailable", http.StatusBadGateway)
+            return
+        }
+        assert.Equal(t, "/latest", r.URL.Path)
+        assert.Equal(t, "USD", r.URL.Query().Get("from"))
+        w.Write([]byte(`{"amount": 1.0, "base": "USD", "date": "2026-03-10", "rates": {"EUR": 0.92, "GBP": 0.79}}`))
+    }))
+    defer server.Close()
+
+    cache := exchange.NewCache(exchange.NewHTTPProvider(server.URL), time.Hour)
+    ctx := context.Background()
+
+    rate, err := exchange.Rate(ctx, cache, "usd", "eur")
+    require.NoError(t, err)
+    assert.Equal(t, 0.92, rate.Rate)
+    assert.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), rate.AsOf)
+
+    _, err = exchange.Rate(ctx, cache, "USD", "GBP")
+    require.NoError(t, err)
+    assert.Equal(t, 1, calls)
+
+    _, err = exchange.Rate(ctx, cache, "USD", "XYZ")
+    assert.ErrorIs(t, err, exchange.ErrUnsupportedCurrency)
+
+    expired := exchange.NewCache(exchange.NewHTTPProvider(server.URL), time.Nanosecond)
+    _, err = exchange.Rate(ctx, expired, "USD", "EUR")
+    require.NoError(t, err)
+    failing = true
+    rate, err = exchange.Rate(ctx, expired, "USD", "EUR")
+    require.NoError(t, err)
+    assert.Equal(t, 0.92, rate.Rate)
+}
+
+// TestQuoteBooking verifies a booking is quoted in another currency without being changed
+func TestQuoteBooking(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(`{"base": "USD", "date": "2026-03-10", "rates": {"EUR": 0.9}}`))
+    }))
+    defer server.Close()
+    ctx := context.Background()
+
+    booking := &models.Booking{
+        Amount: 40,
+        Tax:    &models.TaxBreakdown{Total: 3.1},
+    }
+
+    exchange.Init("", time.Hour)
+    _, err := service.QuoteBookingService(ctx, booking, "EUR")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "currency conversion unavailable")
+
+    exchange.Init(server.URL, time.Hour)
+    defer exchange.Init("", time.Hour)
+
+    quote, err := service.QuoteBookingService(ctx, booking, "eur")
+    require.NoError(t, err)
+    assert.Equal(t, "EUR", quote.To)
+    assert.Equal(t, 36.0, quote.Amount)
+    assert.Equal(t, 2.79, quote.Tax)
+    assert.Equal(t, 38.79, quote.Total)
+    assert.Equal(t, 40.0, booking.Amount)
+
+    _, err = service.QuoteBookingService(ctx, booking, "euro")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid currency")
+}
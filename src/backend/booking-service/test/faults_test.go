@@ -0,0 +1,101 @@
+package test
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/shared/faults"
+)
+
+// TestFaultInjection verifies rules added through the admin API fail and delay only the
+// routes they name, drop frames only where asked, and stop applying once removed
+// Addresses requirement: Technical Specification/7.4 Cross-Cutting Concerns/7.4.1 Monitoring and Observability
+func TestFaultInjection(t *testing.T) {
+    injector := faults.New()
+    api := injector.Handler()
+    handler := injector.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte("ok"))
+    }))
+    call := func(path string) *httptest.ResponseRecorder {
+        recorder := httptest.NewRecorder()
+        handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+        return recorder
+    }
+    admin := func(method, path, body string) *httptest.ResponseRecorder {
+        recorder := httptest.NewRecorder()
+        api.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+        return recorder
+    }
+
+    // Rules that inject nothing, or into no traffic, are refused
+    assert.Equal(t, http.StatusBadRequest, admin(http.MethodPost, "/faults", `{"route":"/api/v1/bookings","percent":100}`).Code)
+    assert.Equal(t, http.StatusBadRequest, admin(http.MethodPost, "/faults", `{"route":"/api/v1/bookings","percent":0,"status":503}`).Code)
+    assert.Equal(t, http.StatusBadRequest, admin(http.MethodPost, "/faults", `{"route":"/api/v1/bookings","percent":100,"status":200}`).Code)
+
+    created := admin(http.MethodPost, "/faults", `{"route":"/api/v1/bookings","percent":100,"status":503}`)
+    require.Equal(t, http.StatusCreated, created.Code)
+    var rule faults.Rule
+    require.NoError(t, json.Unmarshal(created.Body.Bytes(), &rule))
+    assert.NotEmpty(t, rule.ID)
+
+    failed := call("/api/v1/bookings/b-1")
+    assert.Equal(t, http.StatusServiceUnavailable, failed.Code)
+    assert.Equal(t, "error", failed.Header().Get(faults.Header))
+    assert.Equal(t, "ok", call("/api/v1/rates").Body.String(), "other routes are untouched")
+
+    require.Equal(t, http.StatusCreated, admin(http.MethodPost, "/faults", `{"route":"/api/v1/rates","percent":100,"latency_ms":50}`).Code)
+    start := time.Now()
+    delayed := call("/api/v1/rates")
+    assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+    assert.Equal(t, "ok", delayed.Body.String())
+    assert.Equal(t, "latency", delayed.Header().Get(faults.Header))
+
+    require.Equal(t, http.StatusCreated, admin(http.MethodPost, "/faults", `{"route":"/ws","percent":100,"drop_frames":true}`).Code)
+    assert.True(t, injector.DropFrame("/ws"))
+    assert.False(t, injector.DropFrame("/api/v1/bookings"))
+    assert.Equal(t, "ok", call("/ws").Body.String(), "frame drops leave the upgrade alone")
+
+    var listed []faults.Rule
+    require.NoError(t, json.Unmarshal(admin(http.MethodGet, "/faults", "").Body.Bytes(), &listed))
+    assert.Len(t, listed, 3)
+
+    assert.Equal(t, http.StatusNoContent, admin(http.MethodDelete, "/faults/"+rule.ID, "").Code)
+    assert.Equal(t, http.StatusNotFound, admin(http.MethodDelete, "/faults/"+rule.ID, "").Code)
+    assert.Equal(t, "ok", call("/api/v1/bookings/b-1").Body.String())
+
+    assert.Equal(t, http.StatusNoContent, admin(http.MethodDelete, "/faults", "").Code)
+    assert.Empty(t, injector.Rules())
+    assert.False(t, injector.DropFrame("/ws"))
+
+    // A nil injector, as when fault injection is disabled, injects nothing
+    var disabled *faults.Injector
+    assert.False(t, disabled.DropFrame("/ws"))
+}
+
+// TestFaultInjectionEnvironment verifies fault injection is only allowed in the development,
+// staging and test environments, and never when APP_ENV is unset
+func TestFaultInjectionEnvironment(t *testing.T) {
+    for env, allowed := range map[string]bool{
+        "development": true,
+        "Staging":     true,
+        "test":        true,
+        "production":  false,
+        "prod":        false,
+        "":            false,
+    } {
+        t.Setenv("APP_ENV", env)
+        err := faults.CheckEnvironment()
+        if allowed {
+            assert.NoError(t, err, env)
+        } else {
+            assert.ErrorIs(t, err, faults.ErrEnvironment, env)
+        }
+    }
+}
@@ -0,0 +1,100 @@
+package test
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "sync/atomic"
+    "testing"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/shared/featureflags"
+)
+
+// TestEnvFeatureFlags verifies flags are read from an env file and the environment, which
+// wins, and are rolled out to everyone, listed tenants or a stable share of callers
+func TestEnvFeatureFlags(t *testing.T) {
+    file := filepath.Join(t.TempDir(), "flags.env")
+    require.NoError(t, os.WriteFile(file, []byte(`# rollouts
+export FEATURE_PRICING_ENGINE=true
+FEATURE_GROUP_WALKS="tenants=acme, globex"
+FEATURE_ROUTE_SNAPPING=25%
+FEATURE_SMART_MATCHING='tenants=acme;percent=0'
+FEATURE_KILLED=true
+UNRELATED=whatever
+`), 0o600))
+    t.Setenv("FEATURE_KILLED", "false")
+
+    provider, err := featureflags.NewEnvProvider(file)
+    require.NoError(t, err)
+
+    acme := featureflags.Target{Tenant: "acme", Key: "owner-1"}
+    initech := featureflags.Target{Tenant: "initech", Key: "owner-2"}
+    assert.True(t, provider.Enabled("pricing-engine", initech), "names ignore case and dashes")
+    assert.True(t, provider.Enabled("GROUP_WALKS", acme))
+    assert.True(t, provider.Enabled("group_walks", featureflags.Target{Tenant: "globex"}))
+    assert.False(t, provider.Enabled("group_walks", initech), "a tenant list alone is not a rollout to everyone")
+    assert.True(t, provider.Enabled("smart_matching", acme))
+    assert.False(t, provider.Enabled("smart_matching", initech))
+    assert.False(t, provider.Enabled("killed", acme), "the environment overrides the file")
+    assert.False(t, provider.Enabled("unrelated", acme))
+    assert.False(t, provider.Enabled("never_defined", acme))
+
+    // A percentage rollout reaches about that share of callers, each of them every time
+    enabled := 0
+    for i := 0; i < 2000; i++ {
+        target := featureflags.Target{Key: fmt.Sprintf("user-%d", i)}
+        first := provider.Enabled("route_snapping", target)
+        assert.Equal(t, first, provider.Enabled("route_snapping", target))
+        if first {
+            enabled++
+        }
+    }
+    assert.InDelta(t, 500, enabled, 100)
+    assert.False(t, provider.Enabled("route_snapping", featureflags.Target{}), "anonymous callers are never in a partial rollout")
+
+    for _, value := range []string{"maybe", "150%", "tenants", "regions=north"} {
+        t.Setenv("FEATURE_BROKEN", value)
+        _, err := featureflags.NewEnvProvider("")
+        assert.Error(t, err, value)
+    }
+}
+
+// TestRemoteFeatureFlags verifies rules are fetched from the flag service with the SDK key,
+// and that the last rules fetched stay in effect while it is failing
+func TestRemoteFeatureFlags(t *testing.T) {
+    var failing int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Header.Get("Authorization") != "sdk-key" {
+            http.Error(w, "unauthorized", http.StatusUnauthorized)
+            return
+        }
+        if atomic.LoadInt32(&failing) == 1 {
+            http.Error(w, "unavailable", http.StatusServiceUnavailable)
+            return
+        }
+        json.NewEncoder(w).Encode(map[string]interface{}{
+            "flags": map[string]featureflags.Rule{
+                "Pricing-Engine": {Enabled: true, Tenants: []string{"acme"}},
+            },
+        })
+    }))
+    t.Cleanup(server.Close)
+
+    assert.Error(t, featureflags.NewRemoteProvider(server.URL, "wrong-key", 0).Refresh())
+
+    provider := featureflags.NewRemoteProvider(server.URL, "sdk-key", 0)
+    assert.False(t, provider.Enabled("pricing_engine", featureflags.Target{Tenant: "acme"}), "flags are off until the first fetch")
+    require.NoError(t, provider.Refresh())
+    assert.True(t, provider.Enabled("pricing_engine", featureflags.Target{Tenant: "acme"}))
+    assert.False(t, provider.Enabled("pricing_engine", featureflags.Target{Tenant: "globex"}))
+
+    atomic.StoreInt32(&failing, 1)
+    assert.Error(t, provider.Refresh())
+    assert.True(t, provider.Enabled("pricing_engine", featureflags.Target{Tenant: "acme"}))
+}
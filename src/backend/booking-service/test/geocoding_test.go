@@ -0,0 +1,48 @@
+package test
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/geocoding"
+    "src/backend/booking-service/internal/models"
+)
+
+// TestNominatimGeocoder verifies addresses are sent as structured queries and the first
+// match's coordinates returned
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func TestNominatimGeocoder(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        assert.Equal(t, "/search", r.URL.Path)
+        query := r.URL.Query()
+        assert.Equal(t, "jsonv2", query.Get("format"))
+        assert.Equal(t, "London", query.Get("city"))
+        assert.Equal(t, "gb", query.Get("countrycodes"))
+        assert.NotEmpty(t, r.Header.Get("User-Agent"))
+        if query.Get("street") == "1 Nowhere Lane" {
+            w.Write([]byte(`[]`))
+            return
+        }
+        assert.Equal(t, "221B Baker Street", query.Get("street"))
+        assert.Equal(t, "NW1 6XE", query.Get("postalcode"))
+        w.Write([]byte(`[{"lat": "51.5237", "lon": "-0.1585", "display_name": "221B"}, {"lat": "0", "lon": "0"}]`))
+    }))
+    defer server.Close()
+
+    geocoder := geocoding.NewNominatim(server.URL + "/")
+    address := models.Address{Line1: "221B Baker Street", City: "London", PostalCode: "NW1 6XE", Country: "GB"}
+    latitude, longitude, err := geocoder.Geocode(context.Background(), address)
+    require.NoError(t, err)
+    assert.Equal(t, 51.5237, latitude)
+    assert.Equal(t, -0.1585, longitude)
+    assert.Equal(t, "221B Baker Street, London, NW1 6XE, GB", address.String())
+
+    address.Line1 = "1 Nowhere Lane"
+    _, _, err = geocoder.Geocode(context.Background(), address)
+    assert.ErrorIs(t, err, geocoding.ErrAddressNotFound)
+}
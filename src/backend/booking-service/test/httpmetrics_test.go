@@ -0,0 +1,38 @@
+package test
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/stretchr/testify/assert" // v1.8.0
+
+    "src/backend/shared/httpmetrics"
+)
+
+// TestInstrumentedHandlers verifies instrumenting a route leaves its responses untouched and
+// still lets handlers reach the underlying writer, as streaming and WebSocket upgrades need
+// Addresses requirement: Technical Specification/7.4 Cross-Cutting Concerns/7.4.1 Monitoring and Observability
+func TestInstrumentedHandlers(t *testing.T) {
+    handler := httpmetrics.Instrument("booking-service", "/api/v1/bookings/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == http.MethodDelete {
+            http.Error(w, "booking not found", http.StatusNotFound)
+            return
+        }
+        unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+        if assert.True(t, ok, "instrumented writers unwrap") {
+            assert.IsType(t, &httptest.ResponseRecorder{}, unwrapper.Unwrap())
+        }
+        w.Write([]byte("ok"))
+    }))
+
+    recorder := httptest.NewRecorder()
+    handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/bookings/b-1", nil))
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, "ok", recorder.Body.String())
+
+    recorder = httptest.NewRecorder()
+    handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/v1/bookings/b-1", nil))
+    assert.Equal(t, http.StatusNotFound, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "booking not found")
+}
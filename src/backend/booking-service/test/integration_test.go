@@ -0,0 +1,462 @@
+//go:build integration
+
+// Integration tests run the real repository against a throwaway PostgreSQL container.
+// Run with: go test -tags integration ./test/...
+package test
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "os"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/ory/dockertest/v3"        // v3.10.0
+    "github.com/ory/dockertest/v3/docker" // v3.10.0
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+)
+
+// Human Tasks:
+// 1. Ensure Docker is available to the test runner (DOCKER_HOST is honoured)
+// 2. Run the integration suite in CI on every change to internal/repository or migrations/
+
+// idCounter keeps record IDs unique across tests sharing the database
+var idCounter atomic.Int64
+
+// TestMain starts PostgreSQL, applies the migrations and tears the container down afterwards
+func TestMain(m *testing.M) {
+    pool, err := dockertest.NewPool("")
+    if err != nil {
+        log.Fatalf("Failed to connect to Docker: %v", err)
+    }
+    pool.MaxWait = 2 * time.Minute
+
+    resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+        Repository: "postgres",
+        Tag:        "15-alpine",
+        Env:        []string{"POSTGRES_PASSWORD=secret", "POSTGRES_DB=booking_test"},
+    }, func(hostConfig *docker.HostConfig) {
+        hostConfig.AutoRemove = true
+        hostConfig.RestartPolicy = docker.RestartPolicy{Name: "no"}
+    })
+    if err != nil {
+        log.Fatalf("Failed to start PostgreSQL: %v", err)
+    }
+    // Reap the container even if the test binary is killed
+    resource.Expire(600)
+
+    cfg := &config.Config{
+        DatabaseURL: fmt.Sprintf("postgres://postgres:secret@%s/booking_test?sslmode=disable", resource.GetHostPort("5432/tcp")),
+    }
+    err = pool.Retry(func() error {
+        if err := repository.InitDB(cfg); err != nil {
+            repository.Close()
+            return err
+        }
+        return nil
+    })
+    if err != nil {
+        pool.Purge(resource)
+        log.Fatalf("PostgreSQL did not become ready: %v", err)
+    }
+
+    if err := repository.Migrate(context.Background()); err != nil {
+        pool.Purge(resource)
+        log.Fatalf("Failed to migrate: %v", err)
+    }
+
+    code := m.Run()
+
+    repository.Close()
+    pool.Purge(resource)
+    os.Exit(code)
+}
+
+// newID returns an ID unique within the test run
+func newID(prefix string) string {
+    return fmt.Sprintf("%s-%d", prefix, idCounter.Add(1))
+}
+
+// newBooking builds a pending 30 minute booking for walkerID starting at start
+func newBooking(walkerID string, start time.Time) *models.Booking {
+    return &models.Booking{
+        ID:              newID("booking"),
+        OwnerID:         newID("owner"),
+        WalkerID:        walkerID,
+        DogID:           newID("dog"),
+        ScheduledAt:     start,
+        Status:          models.BookingStatusPending,
+        Amount:          25.50,
+        DurationMinutes: 30,
+    }
+}
+
+// futureSlot returns a start time a day ahead, truncated to what PostgreSQL stores
+func futureSlot() time.Time {
+    return time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+}
+
+// TestMigrateIsIdempotent verifies migrations can be re-run on every start
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMigrateIsIdempotent(t *testing.T) {
+    require.NoError(t, repository.Migrate(context.Background()))
+    require.NoError(t, repository.Ping(context.Background()))
+}
+
+// TestBookingRoundTrip verifies a stored booking reads back unchanged
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestBookingRoundTrip(t *testing.T) {
+    ctx := context.Background()
+    booking := newBooking(newID("walker"), futureSlot())
+    acceptBy := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+    booking.AcceptBy = &acceptBy
+
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    stored, err := repository.GetBookingByID(ctx, booking.ID)
+    require.NoError(t, err)
+    assert.Equal(t, booking.OwnerID, stored.OwnerID)
+    assert.Equal(t, booking.WalkerID, stored.WalkerID)
+    assert.Equal(t, booking.Status, stored.Status)
+    assert.Equal(t, booking.Amount, stored.Amount)
+    assert.Equal(t, booking.DurationMinutes, stored.DurationMinutes)
+    assert.True(t, booking.ScheduledAt.Equal(stored.ScheduledAt))
+    require.NotNil(t, stored.AcceptBy)
+    assert.True(t, acceptBy.Equal(*stored.AcceptBy))
+
+    _, err = repository.GetBookingByID(ctx, "missing")
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "booking not found")
+}
+
+// TestCreateBookingWithinCapacityConcurrent verifies concurrent bookings never overfill a walker
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestCreateBookingWithinCapacityConcurrent(t *testing.T) {
+    ctx := context.Background()
+    walkerID := newID("walker")
+    start := futureSlot()
+
+    const attempts, capacity = 10, 2
+    var created, full atomic.Int64
+    var wg sync.WaitGroup
+    for i := 0; i < attempts; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            err := repository.CreateBookingWithinCapacity(ctx, newBooking(walkerID, start), capacity, nil)
+            switch {
+            case err == nil:
+                created.Add(1)
+            case errors.Is(err, repository.ErrSlotFull):
+                full.Add(1)
+            default:
+                t.Errorf("unexpected error: %v", err)
+            }
+        }()
+    }
+    wg.Wait()
+
+    assert.Equal(t, int64(capacity), created.Load())
+    assert.Equal(t, int64(attempts-capacity), full.Load())
+
+    // A non-overlapping slot is unaffected
+    assert.NoError(t, repository.CreateBookingWithinCapacity(ctx, newBooking(walkerID, start.Add(time.Hour)), capacity, nil))
+}
+
+// TestListBookingsKeysetConcurrent verifies paging a walker's bookings by cursor returns each
+// booking once, in (scheduled_at, id) order, while other bookings are being inserted
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestListBookingsKeysetConcurrent(t *testing.T) {
+    ctx := context.Background()
+    walkerID := newID("walker")
+    start := futureSlot()
+
+    var existing []string
+    for i := 0; i < 12; i++ {
+        booking := newBooking(walkerID, start.Add(time.Duration(i/3)*time.Hour))
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+        existing = append(existing, booking.ID)
+    }
+
+    var wg sync.WaitGroup
+    wg.Add(1)
+    go func() {
+        defer wg.Done()
+        for i := 0; i < 12; i++ {
+            if err := repository.CreateBooking(ctx, newBooking(walkerID, start.Add(time.Duration(i%5)*time.Hour))); err != nil {
+                t.Errorf("failed to create booking: %v", err)
+            }
+        }
+    }()
+
+    filter := models.BookingFilter{WalkerID: walkerID}
+    var seen []models.Booking
+    var after *models.BookingCursor
+    for {
+        page, err := repository.ListBookings(ctx, filter, after, 5)
+        require.NoError(t, err)
+        seen = append(seen, page...)
+        if len(page) < 5 {
+            break
+        }
+        after = models.CursorAfter(&page[len(page)-1])
+    }
+    wg.Wait()
+
+    ids := make(map[string]bool)
+    for i, b := range seen {
+        assert.False(t, ids[b.ID], "booking %s listed twice", b.ID)
+        ids[b.ID] = true
+        if i > 0 {
+            previous := seen[i-1]
+            assert.True(t, previous.ScheduledAt.After(b.ScheduledAt) ||
+                (previous.ScheduledAt.Equal(b.ScheduledAt) && previous.ID > b.ID))
+        }
+    }
+    for _, id := range existing {
+        assert.True(t, ids[id], "booking %s was skipped", id)
+    }
+
+    summary, err := repository.SummarizeBookings(ctx, filter)
+    require.NoError(t, err)
+    assert.Equal(t, 24, summary.Total)
+    assert.Equal(t, 24, summary.ByStatus[models.BookingStatusPending])
+    assert.Zero(t, summary.ByStatus[models.BookingStatusConfirmed])
+}
+
+// TestBookingChangeLifecycle verifies a proposed change can be accepted once and is applied
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestBookingChangeLifecycle(t *testing.T) {
+    ctx := context.Background()
+    booking := newBooking(newID("walker"), futureSlot())
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    newStart := booking.ScheduledAt.Add(2 * time.Hour)
+    duration := 60
+    change := &models.BookingChange{
+        ID:              newID("change"),
+        BookingID:       booking.ID,
+        ProposedBy:      booking.OwnerID,
+        ScheduledAt:     &newStart,
+        DurationMinutes: &duration,
+        Status:          models.BookingChangeStatusPending,
+        ExpiresAt:       time.Now().Add(time.Hour),
+        CreatedAt:       time.Now(),
+    }
+    require.NoError(t, repository.CreateBookingChange(ctx, change))
+
+    duplicate := *change
+    duplicate.ID = newID("change")
+    assert.ErrorIs(t, repository.CreateBookingChange(ctx, &duplicate), repository.ErrChangeAlreadyPending)
+
+    stored, err := repository.GetBookingChange(ctx, change.ID)
+    require.NoError(t, err)
+    updated := stored.ApplyTo(*booking)
+    require.NoError(t, repository.AcceptBookingChange(ctx, stored, &updated, 1))
+
+    // The change can only be resolved once
+    assert.ErrorIs(t, repository.RejectBookingChange(ctx, change.ID), repository.ErrChangeNotPending)
+
+    result, err := repository.GetBookingByID(ctx, booking.ID)
+    require.NoError(t, err)
+    assert.True(t, newStart.Equal(result.ScheduledAt))
+    assert.Equal(t, duration, result.DurationMinutes)
+}
+
+// TestAssignmentLifecycle verifies matching, assignment, acceptance and release
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestAssignmentLifecycle(t *testing.T) {
+    ctx := context.Background()
+    start := futureSlot()
+    walkerID := newID("walker")
+    require.NoError(t, repository.CreateAvailability(ctx, &models.Availability{
+        ID:       newID("availability"),
+        WalkerID: walkerID,
+        StartsAt: start.Add(-time.Hour),
+        EndsAt:   start.Add(2 * time.Hour),
+        Capacity: 1,
+    }))
+
+    // Only verified walkers are offered bookings
+    walkers, err := repository.FindAvailableWalkers(ctx, newID("booking"), start, start.Add(30*time.Minute), 5)
+    require.NoError(t, err)
+    assert.NotContains(t, walkers, walkerID)
+    _, err = repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+        WalkerID:  walkerID,
+        Status:    models.WalkerVerified,
+        UpdatedBy: newID("admin"),
+        UpdatedAt: time.Now(),
+    })
+    require.NoError(t, err)
+
+    booking := newBooking("", start)
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    walkers, err = repository.FindAvailableWalkers(ctx, booking.ID, start, booking.EndsAt(), 5)
+    require.NoError(t, err)
+    assert.Contains(t, walkers, walkerID)
+
+    _, err = repository.AssignWalker(ctx, booking.ID, walkerID, 1, time.Now().Add(time.Hour))
+    require.NoError(t, err)
+
+    // Only the assigned walker can accept
+    assert.ErrorIs(t, repository.AcceptAssignment(ctx, booking.ID, newID("walker")), repository.ErrNoPendingAssignment)
+
+    // A declined walker is not offered the booking again
+    require.NoError(t, repository.ReleaseAssignment(ctx, booking.ID, walkerID, "declined"))
+    assert.ErrorIs(t, repository.ReleaseAssignment(ctx, booking.ID, walkerID, "declined"), repository.ErrNoPendingAssignment)
+    walkers, err = repository.FindAvailableWalkers(ctx, booking.ID, start, booking.EndsAt(), 5)
+    require.NoError(t, err)
+    assert.NotContains(t, walkers, walkerID)
+
+    // A fresh booking can be assigned and accepted
+    other := newBooking("", start)
+    require.NoError(t, repository.CreateBooking(ctx, other))
+    _, err = repository.AssignWalker(ctx, other.ID, walkerID, 1, time.Now().Add(time.Hour))
+    require.NoError(t, err)
+    require.NoError(t, repository.AcceptAssignment(ctx, other.ID, walkerID))
+
+    accepted, err := repository.GetBookingByID(ctx, other.ID)
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusConfirmed, accepted.Status)
+    assert.Nil(t, accepted.AcceptBy)
+}
+
+// TestReferralConversion verifies a referral converts once, on the referee's first booking
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestReferralConversion(t *testing.T) {
+    ctx := context.Background()
+    referrerID, refereeID := newID("referrer"), newID("referee")
+
+    code, err := repository.CreateReferralCode(ctx, &models.ReferralCode{Code: newID("CODE"), UserID: referrerID, CreatedAt: time.Now()})
+    require.NoError(t, err)
+
+    referral := &models.Referral{
+        ID:         newID("referral"),
+        Code:       code.Code,
+        ReferrerID: referrerID,
+        RefereeID:  refereeID,
+        Status:     models.ReferralStatusSignedUp,
+        CreatedAt:  time.Now(),
+    }
+    require.NoError(t, repository.CreateReferral(ctx, referral))
+    duplicate := *referral
+    duplicate.ID = newID("referral")
+    assert.ErrorIs(t, repository.CreateReferral(ctx, &duplicate), repository.ErrAlreadyReferred)
+
+    booking := newBooking(newID("walker"), futureSlot())
+    booking.OwnerID = refereeID
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    converted, err := repository.ConvertReferral(ctx, refereeID, booking.ID, 10, time.Now())
+    require.NoError(t, err)
+    require.NotNil(t, converted)
+    assert.Equal(t, models.ReferralStatusConverted, converted.Status)
+    assert.Equal(t, 10.0, converted.CreditAmount)
+
+    again, err := repository.ConvertReferral(ctx, refereeID, booking.ID, 10, time.Now())
+    require.NoError(t, err)
+    assert.Nil(t, again, "a referral converts only once")
+}
+
+// TestOverrideBookingWritesAudit verifies admin overrides are applied and audited atomically
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestOverrideBookingWritesAudit(t *testing.T) {
+    ctx := context.Background()
+    booking := newBooking(newID("walker"), futureSlot())
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    entry := &models.AuditEntry{
+        ID:        newID("audit"),
+        ActorID:   newID("admin"),
+        Action:    models.AuditActionForceStatus,
+        Reason:    "integration test",
+        CreatedAt: time.Now(),
+    }
+    updated, err := repository.OverrideBooking(ctx, booking.ID, entry, 0, func(b *models.Booking) error {
+        b.Status = models.BookingStatusCancelled
+        return nil
+    })
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCancelled, updated.Status)
+
+    entries, err := repository.ListAuditEntries(ctx, booking.ID)
+    require.NoError(t, err)
+    require.Len(t, entries, 1)
+    assert.Equal(t, entry.ID, entries[0].ID)
+    assert.Equal(t, "integration test", entries[0].Reason)
+    assert.Contains(t, string(entries[0].After), `"cancelled"`)
+}
+
+// TestPreparedStatements verifies bookings round-trip the same with statements prepared
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestPreparedStatements(t *testing.T) {
+    ctx := context.Background()
+    require.NoError(t, repository.PrepareStatements(ctx))
+    t.Cleanup(repository.CloseStatements)
+
+    booking := newBooking(newID("walker"), futureSlot())
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+    stored, err := repository.GetBookingByID(ctx, booking.ID)
+    require.NoError(t, err)
+    assert.Equal(t, booking.OwnerID, stored.OwnerID)
+
+    _, err = repository.GetBookingByID(ctx, "missing")
+    assert.Error(t, err)
+    assert.Contains(t, err.Error(), "booking not found")
+}
+
+// benchmarkPrepared runs fn from many goroutines at once, with statements prepared or not, to
+// compare throughput under load. Compare the variants with
+// go test -tags integration -run '^$' -bench . ./test/...
+func benchmarkPrepared(b *testing.B, fn func(ctx context.Context) error) {
+    for _, prepared := range []bool{false, true} {
+        b.Run(fmt.Sprintf("prepared=%t", prepared), func(b *testing.B) {
+            ctx := context.Background()
+            repository.CloseStatements()
+            if prepared {
+                require.NoError(b, repository.PrepareStatements(ctx))
+            }
+            b.Cleanup(repository.CloseStatements)
+
+            b.ReportAllocs()
+            b.ResetTimer()
+            b.RunParallel(func(pb *testing.PB) {
+                for pb.Next() {
+                    if err := fn(ctx); err != nil {
+                        b.Error(err)
+                        return
+                    }
+                }
+            })
+        })
+    }
+}
+
+// BenchmarkGetBookingByID measures booking reads
+func BenchmarkGetBookingByID(b *testing.B) {
+    booking := newBooking(newID("walker"), futureSlot())
+    require.NoError(b, repository.CreateBooking(context.Background(), booking))
+
+    benchmarkPrepared(b, func(ctx context.Context) error {
+        _, err := repository.GetBookingByID(ctx, booking.ID)
+        return err
+    })
+}
+
+// BenchmarkCreateBooking measures booking inserts
+func BenchmarkCreateBooking(b *testing.B) {
+    start := futureSlot()
+    benchmarkPrepared(b, func(ctx context.Context) error {
+        return repository.CreateBooking(ctx, newBooking(newID("walker"), start))
+    })
+}
@@ -0,0 +1,43 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+    "go.uber.org/goleak"                  // v1.2.1
+
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// TestBackgroundJobsStop checks that stopping the periodic booking jobs leaves none of their
+// goroutines running, and that stop hooks may run for jobs that never started or twice
+func TestBackgroundJobsStop(t *testing.T) {
+    defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+    repository.UseMemoryStore()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    jobs := &service.BackgroundJobs{}
+    assert.NoError(t, jobs.Stop(ctx), "jobs that never started stop at once")
+
+    require.NoError(t, jobs.Start(context.Background()))
+    assert.Error(t, jobs.Start(context.Background()), "jobs run once")
+    require.NoError(t, jobs.Stop(ctx))
+    assert.NoError(t, jobs.Stop(ctx), "stopping twice is harmless")
+
+    // Jobs also stop with the context they were started with
+    stopping, stop := context.WithCancel(context.Background())
+    cancelled := &service.BackgroundJobs{}
+    require.NoError(t, cancelled.Start(stopping))
+    stop()
+    require.NoError(t, cancelled.Stop(ctx))
+}
@@ -0,0 +1,107 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// memoryBooking builds a pending 30 minute booking for walkerID starting at start
+func memoryBooking(id, walkerID string, start time.Time) *models.Booking {
+    return &models.Booking{
+        ID:              id,
+        OwnerID:         "owner-" + id,
+        WalkerID:        walkerID,
+        DogID:           "dog-" + id,
+        ScheduledAt:     start,
+        Status:          models.BookingStatusPending,
+        Amount:          25.50,
+        DurationMinutes: 30,
+    }
+}
+
+// useConfig installs cfg as the service's configuration until the test ends
+func useConfig(t *testing.T, cfg *config.Config) {
+    t.Helper()
+    previous := config.Config
+    config.Config = cfg
+    t.Cleanup(func() { config.Config = previous })
+}
+
+// TestMemoryStoreCapacityConcurrent verifies the in-memory store never overbooks a walker
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreCapacityConcurrent(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    start := time.Now().Add(24 * time.Hour)
+
+    const capacity = 2
+    var wg sync.WaitGroup
+    errs := make([]error, 10)
+    for i := range errs {
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            booking := memoryBooking(string(rune('a'+i)), "walker-1", start)
+            errs[i] = repository.CreateBookingWithinCapacity(ctx, booking, capacity, nil)
+        }(i)
+    }
+    wg.Wait()
+
+    created := 0
+    for _, err := range errs {
+        if err == nil {
+            created++
+        } else {
+            assert.ErrorIs(t, err, repository.ErrSlotFull)
+        }
+    }
+    assert.Equal(t, capacity, created)
+
+    _, err := repository.GetBookingByID(ctx, "missing")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking not found")
+}
+
+// TestMemoryStoreAPIKeyLifecycle verifies API keys authenticate until they are revoked
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func TestMemoryStoreAPIKeyLifecycle(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    key, secret, err := service.IssueAPIKeyService(ctx, "admin-1", "partner", []string{models.ScopeBookingsWrite}, "")
+    require.NoError(t, err)
+    assert.Equal(t, models.APIKeyTierStandard, key.Tier)
+    assert.NotContains(t, key.KeyHash, secret)
+
+    authenticated, err := service.AuthenticateAPIKeyService(ctx, secret)
+    require.NoError(t, err)
+    assert.Equal(t, key.ID, authenticated.ID)
+    assert.True(t, authenticated.HasScope(models.ScopeBookingsWrite))
+    assert.False(t, authenticated.HasScope(models.ScopeBookingsRead))
+
+    _, err = service.AuthenticateAPIKeyService(ctx, secret+"0")
+    assert.ErrorIs(t, err, repository.ErrAPIKeyNotFound)
+
+    _, err = service.RevokeAPIKeyService(ctx, key.ID)
+    require.NoError(t, err)
+    _, err = service.AuthenticateAPIKeyService(ctx, secret)
+    assert.ErrorIs(t, err, repository.ErrAPIKeyNotFound)
+
+    _, _, err = service.IssueAPIKeyService(ctx, "admin-1", "partner", []string{"bookings:delete"}, "")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid api key")
+}
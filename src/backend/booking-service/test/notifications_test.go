@@ -0,0 +1,186 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "testing"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// fakePushSender accepts every token except those listed as unregistered
+type fakePushSender struct {
+    unregistered map[string]bool
+    sent         []string
+}
+
+func (f *fakePushSender) Send(ctx context.Context, token string, notification notifier.Notification) (string, error) {
+    if f.unregistered[token] {
+        return "", notifier.ErrUnregistered
+    }
+    f.sent = append(f.sent, token)
+    return "message-" + token, nil
+}
+
+func TestMemoryStoreNotifications(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    _, err := service.RegisterDeviceService(ctx, "user-1", "token-a", "windows")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid device")
+
+    _, err = service.RegisterDeviceService(ctx, "user-1", "token-a", notifier.PlatformAndroid)
+    require.NoError(t, err)
+    _, err = service.RegisterDeviceService(ctx, "user-1", "token-stale", notifier.PlatformAndroid)
+    require.NoError(t, err)
+    _, err = service.RegisterDeviceService(ctx, "user-1", "token-i", notifier.PlatformIOS)
+    require.NoError(t, err)
+
+    // Re-registering a token moves the device to whoever is now signed in
+    _, err = service.RegisterDeviceService(ctx, "user-2", "token-i", notifier.PlatformIOS)
+    require.NoError(t, err)
+    devices, err := service.ListDevicesService(ctx, "user-1")
+    require.NoError(t, err)
+    assert.Len(t, devices, 2)
+
+    android := &fakePushSender{unregistered: map[string]bool{"token-stale": true}}
+    ios := &fakePushSender{}
+    push := notifier.NewPushNotifier(map[string]notifier.PushSender{
+        notifier.PlatformAndroid: android,
+        notifier.PlatformIOS:     ios,
+    }, service.NotificationStore{}, notifier.LogNotifier{})
+
+    notification := notifier.Notification{Subject: "Your receipt is ready", Category: notifier.CategoryPayments}
+    require.NoError(t, push.Notify(ctx, "user-1", notification))
+    assert.Equal(t, []string{"token-a"}, android.sent)
+    assert.Empty(t, ios.sent)
+
+    // The token the platform no longer accepts is forgotten
+    devices, err = service.ListDevicesService(ctx, "user-1")
+    require.NoError(t, err)
+    require.Len(t, devices, 1)
+    assert.Equal(t, "token-a", devices[0].Token)
+
+    _, err = service.SaveNotificationPreferencesService(ctx, &models.NotificationPreferences{
+        UserID:          "user-1",
+        PushEnabled:     true,
+        MutedCategories: []string{"marketing"},
+    })
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid notification preferences")
+
+    _, err = service.SaveNotificationPreferencesService(ctx, &models.NotificationPreferences{
+        UserID:          "user-1",
+        PushEnabled:     true,
+        MutedCategories: []string{notifier.CategoryPayments, notifier.CategoryPayments},
+    })
+    require.NoError(t, err)
+    prefs, err := service.GetNotificationPreferencesService(ctx, "user-1")
+    require.NoError(t, err)
+    assert.Equal(t, []string{notifier.CategoryPayments}, prefs.MutedCategories)
+
+    require.NoError(t, push.Notify(ctx, "user-1", notification))
+    require.NoError(t, push.Notify(ctx, "user-1", notifier.Notification{Subject: "New walk request", Category: notifier.CategoryBookings}))
+    assert.Equal(t, []string{"token-a", "token-a"}, android.sent)
+    require.NoError(t, push.Notify(ctx, "user-3", notification))
+
+    receipts, err := service.ListDeliveryReceiptsService(ctx, "user-1", 0)
+    require.NoError(t, err)
+    statuses := make([]string, len(receipts))
+    for i, receipt := range receipts {
+        statuses[i] = receipt.Status
+    }
+    require.Len(t, statuses, 4)
+    assert.Equal(t, []string{notifier.DeliverySent, notifier.DeliveryMuted}, statuses[:2])
+    assert.ElementsMatch(t, []string{notifier.DeliverySent, notifier.DeliveryUnregistered}, statuses[2:])
+    assert.Equal(t, "message-token-a", receipts[0].MessageID)
+
+    receipts, err = service.ListDeliveryReceiptsService(ctx, "user-3", 0)
+    require.NoError(t, err)
+    require.Len(t, receipts, 1)
+    assert.Equal(t, notifier.DeliveryNoDevice, receipts[0].Status)
+
+    require.NoError(t, service.UnregisterDeviceService(ctx, "user-1", "token-a"))
+    err = service.UnregisterDeviceService(ctx, "user-1", "token-a")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "device not found")
+}
+
+// TestMemoryStoreInbox verifies every notification is kept in the user's notification center,
+// which pages newest first and tracks what has been read
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func TestMemoryStoreInbox(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    inbox := notifier.NewInboxNotifier(service.NotificationStore{}, notifier.LogNotifier{})
+    for _, subject := range []string{"New walk request", "Booking cancelled", "Your receipt is ready"} {
+        require.NoError(t, inbox.Notify(ctx, "user-1", notifier.Notification{
+            Subject:  subject,
+            Category: notifier.CategoryBookings,
+            Data:     map[string]string{"booking_id": "booking-1"},
+        }))
+    }
+    require.NoError(t, inbox.Notify(ctx, "user-2", notifier.Notification{Subject: "Someone else's"}))
+
+    page, err := service.ListInboxService(ctx, "user-1", "", false, 2)
+    require.NoError(t, err)
+    require.Len(t, page.Notifications, 2)
+    assert.Equal(t, "Your receipt is ready", page.Notifications[0].Subject)
+    assert.Equal(t, "booking-1", page.Notifications[0].Data["booking_id"])
+    assert.Equal(t, 3, page.UnreadCount)
+    require.NotEmpty(t, page.NextBefore)
+
+    next, err := service.ListInboxService(ctx, "user-1", page.NextBefore, false, 2)
+    require.NoError(t, err)
+    require.Len(t, next.Notifications, 1)
+    assert.Equal(t, "New walk request", next.Notifications[0].Subject)
+    assert.Empty(t, next.NextBefore)
+
+    _, err = service.ListInboxService(ctx, "user-2", page.NextBefore, false, 2)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid inbox request")
+
+    read, err := service.MarkInboxNotificationReadService(ctx, "user-1", page.Notifications[0].ID)
+    require.NoError(t, err)
+    require.NotNil(t, read.ReadAt)
+    _, err = service.MarkInboxNotificationReadService(ctx, "user-2", page.Notifications[0].ID)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "notification not found")
+
+    unread, err := service.ListInboxService(ctx, "user-1", "", true, 0)
+    require.NoError(t, err)
+    assert.Len(t, unread.Notifications, 2)
+    assert.Equal(t, 2, unread.UnreadCount)
+
+    _, err = service.MarkInboxReadService(ctx, "user-1", nil, false)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid mark read request")
+
+    marked, err := service.MarkInboxReadService(ctx, "user-1", []string{page.Notifications[0].ID, page.Notifications[1].ID}, false)
+    require.NoError(t, err)
+    assert.Equal(t, int64(1), marked)
+
+    marked, err = service.MarkInboxReadService(ctx, "user-1", nil, true)
+    require.NoError(t, err)
+    assert.Equal(t, int64(1), marked)
+    page, err = service.ListInboxService(ctx, "user-1", "", false, 0)
+    require.NoError(t, err)
+    assert.Len(t, page.Notifications, 3)
+    assert.Zero(t, page.UnreadCount)
+
+    // Other users' notifications are untouched
+    other, err := service.ListInboxService(ctx, "user-2", "", false, 0)
+    require.NoError(t, err)
+    assert.Equal(t, 1, other.UnreadCount)
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/shared/policy"
+)
+
+// TestDefaultPolicyMatrix verifies the built-in role, resource and action rules
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func TestDefaultPolicyMatrix(t *testing.T) {
+    ctx := context.Background()
+
+    cases := []struct {
+        role, resource, action string
+        allowed                bool
+    }{
+        {policy.RoleAdmin, policy.ResourceBookingOverrides, policy.ActionUpdate, true},
+        {policy.RoleAdmin, "anything", "anything", true},
+        {policy.RoleOwner, policy.ResourceBookings, policy.ActionCreate, true},
+        {policy.RoleOwner, policy.ResourceBookingOverrides, policy.ActionUpdate, false},
+        {policy.RoleWalker, policy.ResourceBookings, policy.ActionCreate, false},
+        {policy.RoleWalker, policy.ResourceLocations, policy.ActionCreate, true},
+        {policy.RoleWalker, policy.ResourceMessages, policy.ActionCreate, true},
+        {policy.RoleWalker, policy.ResourcePrivacyZones, policy.ActionDelete, true},
+        {policy.RoleOwner, policy.ResourcePrivacyZones, policy.ActionRead, false},
+        {policy.RoleWalker, policy.ResourceEarnings, policy.ActionRead, true},
+        {policy.RoleOwner, policy.ResourceEarnings, policy.ActionRead, false},
+        {policy.RoleOwner, policy.ResourceConsents, policy.ActionDelete, true},
+        {policy.RoleClient, policy.ResourceConsents, policy.ActionCreate, false},
+        {policy.RoleWalker, policy.ResourceFaults, policy.ActionUpdate, false},
+        {policy.RoleAdmin, policy.ResourceFaults, policy.ActionUpdate, true},
+        {policy.RoleWalker, policy.ResourceRatePlans, policy.ActionUpdate, true},
+        {policy.RoleOwner, policy.ResourceRatePlans, policy.ActionUpdate, false},
+        {policy.RoleWalker, policy.ResourceAvailability, policy.ActionCreate, true},
+        {policy.RoleClient, policy.ResourceAvailability, policy.ActionCreate, false},
+        {policy.RoleOwner, policy.ResourceReferrals, policy.ActionCreate, true},
+        {policy.RoleOwner, policy.ResourceReports, policy.ActionRead, false},
+        {policy.RoleClient, policy.ResourceBookings, policy.ActionCreate, true},
+        {policy.RoleClient, policy.ResourceMessages, policy.ActionRead, false},
+        {policy.RoleClient, policy.ResourceAPIKeys, policy.ActionUpdate, false},
+        {"", policy.ResourceBookings, policy.ActionRead, false},
+    }
+    for _, c := range cases {
+        allowed, err := policy.DefaultMatrix.Allowed(ctx, policy.Request{Role: c.role, Resource: c.resource, Action: c.action})
+        require.NoError(t, err)
+        assert.Equal(t, c.allowed, allowed, "%s %s %s", c.role, c.action, c.resource)
+    }
+}
+
+// TestPolicyFromFile verifies a matrix file replaces the built-in rules
+func TestPolicyFromFile(t *testing.T) {
+    file := filepath.Join(t.TempDir(), "policy.json")
+    require.NoError(t, os.WriteFile(file, []byte(`{"support": {"bookings": ["read"]}}`), 0o600))
+
+    _, err := policy.Init(policy.Options{File: file})
+    require.NoError(t, err)
+    defer policy.Init(policy.Options{})
+
+    ctx := context.Background()
+    assert.True(t, policy.Allowed(ctx, "support", policy.ResourceBookings, policy.ActionRead))
+    assert.False(t, policy.Allowed(ctx, "support", policy.ResourceBookings, policy.ActionCreate))
+    assert.False(t, policy.Allowed(ctx, policy.RoleAdmin, policy.ResourceBookings, policy.ActionRead))
+}
+
+// TestOPAPolicy verifies decisions are taken from OPA and that failures deny the request
+func TestOPAPolicy(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        var body struct {
+            Input policy.Request `json:"input"`
+        }
+        if r.URL.Path != "/v1/data/dogwalk/authz/allow" || json.NewDecoder(r.Body).Decode(&body) != nil {
+            http.Error(w, "bad request", http.StatusBadRequest)
+            return
+        }
+        json.NewEncoder(w).Encode(map[string]bool{"result": body.Input.Role == policy.RoleOwner})
+    }))
+    defer server.Close()
+
+    ctx := context.Background()
+    authorizer := policy.NewOPAAuthorizer(server.URL, "")
+
+    allowed, err := authorizer.Allowed(ctx, policy.Request{Role: policy.RoleOwner, Resource: policy.ResourceBookings, Action: policy.ActionRead})
+    require.NoError(t, err)
+    assert.True(t, allowed)
+
+    allowed, err = authorizer.Allowed(ctx, policy.Request{Role: policy.RoleWalker, Resource: policy.ResourceBookings, Action: policy.ActionRead})
+    require.NoError(t, err)
+    assert.False(t, allowed)
+
+    _, err = policy.NewOPAAuthorizer(server.URL, "other/decision").Allowed(ctx, policy.Request{Role: policy.RoleOwner})
+    assert.Error(t, err)
+}
@@ -0,0 +1,287 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/booking-service/internal/surge"
+    "src/backend/booking-service/internal/tracking"
+    "src/backend/shared/regions"
+)
+
+// TestMemoryStoreRatePlans verifies bookings are priced from the walker's own rates and keep
+// the rate they were priced at
+func TestMemoryStoreRatePlans(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    for _, walkerID := range []string{"walker-1", "walker-2"} {
+        _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+            WalkerID:  walkerID,
+            Status:    models.WalkerVerified,
+            UpdatedBy: "admin-1",
+            UpdatedAt: time.Now(),
+        })
+        require.NoError(t, err)
+    }
+
+    err := service.SaveRatePlanService(ctx, &models.RatePlan{WalkerID: "walker-1", BaseRate: 20, TimeZone: "Mars/Olympus"})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid rate plan")
+
+    plan := &models.RatePlan{
+        WalkerID:                "walker-1",
+        BaseRate:                20,
+        WeekendSurchargePercent: 25,
+        LargeDogSurcharge:       5,
+        ExtraDogSurcharge:       7.5,
+        TimeZone:                "America/New_York",
+    }
+    require.NoError(t, service.SaveRatePlanService(ctx, plan))
+
+    // Noon on a Saturday in New York, at least a day away
+    newYork, err := time.LoadLocation("America/New_York")
+    require.NoError(t, err)
+    saturday := time.Now().In(newYork).AddDate(0, 0, 2)
+    for saturday.Weekday() != time.Saturday {
+        saturday = saturday.AddDate(0, 0, 1)
+    }
+    saturday = time.Date(saturday.Year(), saturday.Month(), saturday.Day(), 12, 0, 0, 0, newYork)
+
+    quote, err := service.QuoteRateService(ctx, "walker-1", "", saturday, 60, true, 1)
+    require.NoError(t, err)
+    assert.Equal(t, 40.0, quote.Base)
+    assert.Equal(t, []models.Surcharge{
+        {Name: models.SurchargeWeekend, Amount: 10},
+        {Name: models.SurchargeLargeDog, Amount: 5},
+        {Name: models.SurchargeExtraDog, Amount: 7.5},
+    }, quote.Surcharges)
+    assert.Equal(t, 62.5, quote.Amount)
+
+    weekday, err := service.QuoteRateService(ctx, "walker-1", "", saturday.AddDate(0, 0, 2), 45, false, 0)
+    require.NoError(t, err)
+    assert.Empty(t, weekday.Surcharges)
+    assert.Equal(t, 30.0, weekday.Amount)
+
+    _, err = service.QuoteRateService(ctx, "walker-2", "", saturday, 30, false, 0)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "rate plan not found")
+
+    booking := memoryBooking("booking-rated", "walker-1", saturday)
+    booking.DurationMinutes = 60
+    booking.LargeDog = true
+    booking.ExtraDogs = 1
+    booking.Rate = &models.RateSnapshot{Amount: 1}
+    require.NoError(t, service.CreateBookingService(ctx, booking))
+    assert.Equal(t, 62.5, booking.Amount)
+
+    // Later rate changes leave the booking at the rate it was priced at
+    plan.BaseRate = 30
+    require.NoError(t, service.SaveRatePlanService(ctx, plan))
+    stored, err := service.GetBookingService(ctx, "booking-rated")
+    require.NoError(t, err)
+    require.NotNil(t, stored.Rate)
+    assert.Equal(t, 20.0, stored.Rate.BaseRate)
+    assert.Equal(t, 62.5, stored.Rate.Amount)
+    assert.Equal(t, 62.5, stored.Amount)
+
+    unrated := memoryBooking("booking-unrated", "walker-2", saturday)
+    unrated.Rate = &models.RateSnapshot{Amount: 1}
+    require.NoError(t, service.CreateBookingService(ctx, unrated))
+    assert.Equal(t, 25.50, unrated.Amount)
+    assert.Nil(t, unrated.Rate)
+}
+
+// TestMemoryStoreSurgePricing verifies quotes and bookings are multiplied up when more dogs are
+// booked in a region hour than walkers are available to take, disclosing the multiplier
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+func TestMemoryStoreSurgePricing(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{Surge: surge.Options{Threshold: 1, Sensitivity: 0.5, Max: 2}})
+
+    assert.Equal(t, 1.0, config.Config.Surge.Multiplier(0, 0))
+    assert.Equal(t, 2.0, config.Config.Surge.Multiplier(1, 0))
+    assert.Equal(t, 1.0, surge.Options{Max: 1}.Multiplier(10, 1))
+
+    require.NoError(t, service.SaveRatePlanService(ctx, &models.RatePlan{WalkerID: "walker-1", BaseRate: 20}))
+    hour := time.Now().UTC().Truncate(time.Hour).Add(72 * time.Hour)
+    for i, walkerID := range []string{"walker-1", "walker-2"} {
+        require.NoError(t, repository.CreateAvailability(ctx, &models.Availability{
+            ID:       fmt.Sprintf("surge-window-%d", i),
+            WalkerID: walkerID,
+            StartsAt: hour.Add(-time.Hour),
+            EndsAt:   hour.Add(2 * time.Hour),
+            Capacity: 1,
+            Region:   "nyc-brooklyn",
+        }))
+    }
+
+    // The walk quoted fits in the two walkers' capacity
+    quote, err := service.QuoteRateService(ctx, "walker-1", "NYC-Brooklyn", hour, 30, false, 0)
+    require.NoError(t, err)
+    assert.Zero(t, quote.SurgeMultiplier)
+    assert.Equal(t, 20.0, quote.Amount)
+
+    // Two more booked in the hour make three dogs for two places: 1 + (1.5 - 1) * 0.5, in tenths
+    for i := 0; i < 2; i++ {
+        booking := memoryBooking(fmt.Sprintf("surge-open-%d", i), "", hour.Add(15*time.Minute))
+        booking.Region = "nyc-brooklyn"
+        require.NoError(t, service.CreateBookingService(ctx, booking))
+    }
+    quote, err = service.QuoteRateService(ctx, "walker-1", "nyc-brooklyn", hour, 30, false, 0)
+    require.NoError(t, err)
+    assert.Equal(t, 1.2, quote.SurgeMultiplier)
+    assert.Equal(t, []models.Surcharge{{Name: models.SurchargeSurge, Amount: 4}}, quote.Surcharges)
+    assert.Equal(t, 24.0, quote.Amount)
+
+    // Other regions are priced from their own supply and demand
+    quote, err = service.QuoteRateService(ctx, "walker-1", "nyc-queens", hour, 30, false, 0)
+    require.NoError(t, err)
+    assert.Equal(t, 2.0, quote.SurgeMultiplier)
+
+    // Bookings are charged the surge they were quoted
+    _, err = repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+        WalkerID:  "walker-1",
+        Status:    models.WalkerVerified,
+        UpdatedBy: "admin-1",
+        UpdatedAt: time.Now(),
+    })
+    require.NoError(t, err)
+    booking := memoryBooking("surge-booked", "walker-1", hour)
+    booking.Region = "nyc-brooklyn"
+    require.NoError(t, service.CreateBookingService(ctx, booking))
+    require.NotNil(t, booking.Rate)
+    assert.Equal(t, 1.2, booking.Rate.SurgeMultiplier)
+    assert.Equal(t, 24.0, booking.Amount)
+
+    // Walkers just tracked in another region are not available for a walk starting soon
+    require.NoError(t, service.SaveRegionService(ctx, "nyc-queens", &regions.Region{Name: "Queens", Boundary: []regions.Point{
+        {Latitude: 40.70, Longitude: -73.86}, {Latitude: 40.80, Longitude: -73.86},
+        {Latitude: 40.80, Longitude: -73.70}, {Latitude: 40.70, Longitude: -73.70},
+    }}))
+    soon := time.Now().UTC().Add(20 * time.Minute)
+    require.NoError(t, repository.CreateAvailability(ctx, &models.Availability{
+        ID:       "surge-window-soon",
+        WalkerID: "walker-2",
+        StartsAt: soon.Add(-time.Hour),
+        EndsAt:   soon.Add(time.Hour),
+        Capacity: 1,
+        Region:   "nyc-brooklyn",
+    }))
+    quote, err = service.QuoteRateService(ctx, "walker-1", "nyc-brooklyn", soon, 30, false, 0)
+    require.NoError(t, err)
+    assert.Zero(t, quote.SurgeMultiplier)
+
+    ctx = service.WithProviders(ctx, service.Providers{Locator: &fakeLocator{positions: map[string]tracking.Position{
+        "walker-2": {Latitude: 40.75, Longitude: -73.80, UpdatedAt: time.Now().Add(-5 * time.Minute)},
+    }}})
+    quote, err = service.QuoteRateService(ctx, "walker-1", "nyc-brooklyn", soon, 30, false, 0)
+    require.NoError(t, err)
+    assert.Equal(t, 2.0, quote.SurgeMultiplier)
+    assert.Equal(t, 40.0, quote.Amount)
+}
+
+// TestMemoryStoreQuoteHolds verifies a held quote keeps the walker's slot and price for the
+// owner until it is booked, released or expires
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreQuoteHolds(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{QuoteHoldTTL: 10 * time.Minute})
+
+    _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+        WalkerID:  "walker-1",
+        Status:    models.WalkerVerified,
+        UpdatedBy: "admin-1",
+        UpdatedAt: time.Now(),
+    })
+    require.NoError(t, err)
+    plan := &models.RatePlan{WalkerID: "walker-1", BaseRate: 20}
+    require.NoError(t, service.SaveRatePlanService(ctx, plan))
+
+    // The walker has no published window, so takes one dog at a time
+    start := time.Now().Add(48 * time.Hour).Truncate(time.Second).UTC()
+    hold, err := service.HoldQuoteService(ctx, "owner-a", "walker-1", "", start, 30, false, 0)
+    require.NoError(t, err)
+    assert.Equal(t, 20.0, hold.Quote.Amount)
+    assert.WithinDuration(t, time.Now().Add(10*time.Minute), hold.ExpiresAt, time.Minute)
+
+    // Nobody else can hold or book the slot meanwhile
+    _, err = service.HoldQuoteService(ctx, "owner-b", "walker-1", "", start, 30, false, 0)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking conflict")
+    err = service.CreateBookingService(ctx, memoryBooking("hold-taken", "walker-1", start))
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking conflict")
+
+    // The owner books at the price held, even though the walker's rates went up
+    plan.BaseRate = 30
+    require.NoError(t, service.SaveRatePlanService(ctx, plan))
+    booking := memoryBooking("hold-booked", "walker-1", start)
+    booking.OwnerID = "owner-a"
+    booking.HoldID = hold.ID
+    require.NoError(t, service.CreateBookingService(ctx, booking))
+    assert.Equal(t, 20.0, booking.Amount)
+    _, err = repository.GetSlotHold(ctx, hold.ID)
+    assert.ErrorIs(t, err, repository.ErrHoldNotFound)
+
+    // A hold only books the walk it was made for, by the owner it was made for
+    later := start.Add(3 * time.Hour)
+    hold, err = service.HoldQuoteService(ctx, "owner-a", "walker-1", "", later, 30, false, 0)
+    require.NoError(t, err)
+    longer := memoryBooking("hold-longer", "walker-1", later)
+    longer.OwnerID, longer.HoldID, longer.DurationMinutes = "owner-a", hold.ID, 60
+    err = service.CreateBookingService(ctx, longer)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid booking data")
+    stranger := memoryBooking("hold-stranger", "walker-1", later)
+    stranger.HoldID = hold.ID
+    err = service.CreateBookingService(ctx, stranger)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "not found")
+
+    // Released holds free the slot at once
+    err = service.ReleaseSlotHoldService(ctx, hold.ID, "owner-b")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "slot hold not found")
+    require.NoError(t, service.ReleaseSlotHoldService(ctx, hold.ID, "owner-a"))
+    hold, err = service.HoldQuoteService(ctx, "owner-b", "walker-1", "", later, 30, false, 0)
+    require.NoError(t, err)
+
+    // Expired holds no longer count against capacity, and cannot be booked
+    config.Config.QuoteHoldTTL = time.Nanosecond
+    evening := start.Add(6 * time.Hour)
+    expired, err := service.HoldQuoteService(ctx, "owner-a", "walker-1", "", evening, 30, false, 0)
+    require.NoError(t, err)
+    time.Sleep(time.Millisecond)
+    late := memoryBooking("hold-late", "walker-1", evening)
+    late.OwnerID, late.HoldID = "owner-a", expired.ID
+    err = service.CreateBookingService(ctx, late)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "expired")
+    require.NoError(t, service.CreateBookingService(ctx, memoryBooking("hold-after-expiry", "walker-1", evening)))
+
+    deleted, err := repository.DeleteExpiredSlotHolds(ctx, time.Now())
+    require.NoError(t, err)
+    assert.Equal(t, int64(1), deleted)
+    _, err = repository.GetSlotHold(ctx, hold.ID)
+    require.NoError(t, err)
+}
@@ -0,0 +1,72 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// TestMemoryStoreRebook verifies a past walk is booked again through the usual checks
+func TestMemoryStoreRebook(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+        WalkerID:  "walker-1",
+        Status:    models.WalkerVerified,
+        UpdatedBy: "admin-1",
+        UpdatedAt: time.Now(),
+    })
+    require.NoError(t, err)
+    require.NoError(t, service.SaveRatePlanService(ctx, &models.RatePlan{
+        WalkerID:          "walker-1",
+        BaseRate:          20,
+        LargeDogSurcharge: 5,
+    }))
+
+    start := time.Now().Add(24 * time.Hour)
+    original := memoryBooking("booking-original", "walker-1", start)
+    original.DurationMinutes = 60
+    original.LargeDog = true
+    original.Region = "nyc-brooklyn"
+    require.NoError(t, service.CreateBookingService(ctx, original))
+
+    _, err = service.RebookService(ctx, "booking-original", "someone-else", start.Add(48*time.Hour))
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "rebook not allowed")
+
+    // The walker is already booked for the original slot
+    _, err = service.RebookService(ctx, "booking-original", original.OwnerID, start)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking conflict")
+
+    _, err = service.RebookService(ctx, "booking-missing", original.OwnerID, start.Add(48*time.Hour))
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking not found")
+
+    rebooked, err := service.RebookService(ctx, "booking-original", original.OwnerID, start.Add(48*time.Hour))
+    require.NoError(t, err)
+    assert.NotEqual(t, original.ID, rebooked.ID)
+    assert.Equal(t, "walker-1", rebooked.WalkerID)
+    assert.Equal(t, original.DogID, rebooked.DogID)
+    assert.Equal(t, 60, rebooked.DurationMinutes)
+    assert.Equal(t, "nyc-brooklyn", rebooked.Region)
+    assert.Equal(t, models.BookingStatusPending, rebooked.Status)
+    require.NotNil(t, rebooked.Rate)
+    assert.True(t, rebooked.Rate.LargeDog)
+    assert.Equal(t, 45.0, rebooked.Amount)
+}
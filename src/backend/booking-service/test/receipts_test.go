@@ -0,0 +1,141 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "bytes"
+    "context"
+    "net/http"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/receipts"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/booking-service/internal/tax"
+    "src/backend/shared/policy"
+)
+
+// TestMemoryStoreReceipts verifies receipts are issued once, only for completed bookings
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreReceipts(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    store, err := receipts.NewFileStore(t.TempDir())
+    require.NoError(t, err)
+    ctx = service.WithProviders(ctx, service.Providers{Receipts: store})
+
+    start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+    completed := memoryBooking("receipt-done", "walker-1", start)
+    completed.Status = models.BookingStatusCompleted
+    require.NoError(t, repository.CreateBooking(ctx, completed))
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("receipt-pending", "walker-1", start)))
+
+    receipt, err := service.IssueReceiptService(ctx, "receipt-done")
+    require.NoError(t, err)
+    assert.Equal(t, int64(2550), receipt.TotalCents)
+    assert.Equal(t, "owner-receipt-done", receipt.OwnerID)
+
+    again, err := service.IssueReceiptService(ctx, "receipt-done")
+    require.NoError(t, err)
+    assert.Equal(t, receipt.Number, again.Number)
+    assert.True(t, receipt.IssuedAt.Equal(again.IssuedAt))
+
+    document, err := service.GetReceiptDocumentService(ctx, "receipt-done", "owner-receipt-done")
+    require.NoError(t, err)
+    assert.True(t, bytes.HasPrefix(document, []byte("%PDF-")))
+
+    _, err = service.IssueReceiptService(ctx, "receipt-pending")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "receipt not available")
+}
+
+// TestReceiptAccess verifies receipts are read only by the booking's owner or an admin, and
+// that reading one never issues it
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestReceiptAccess(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    store, err := receipts.NewFileStore(t.TempDir())
+    require.NoError(t, err)
+    providers := service.Providers{Receipts: store}
+    ctx = service.WithProviders(ctx, providers)
+
+    start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+    for _, id := range []string{"receipt-issued", "receipt-unissued"} {
+        booking := memoryBooking(id, "walker-1", start)
+        booking.Status = models.BookingStatusCompleted
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+    issued, err := service.IssueReceiptService(ctx, "receipt-issued")
+    require.NoError(t, err)
+
+    receipt := withProviders(providers, middleware.RequirePermission(actionsSecret, policy.ResourceBookings, policy.ActionRead)(handlers.BookingHandler))
+    path := "/api/v1/bookings/receipt-issued/receipt"
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, receipt, http.MethodGet, path, "", "", "").Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, receipt, http.MethodGet, path, "owner-receipt-unissued", policy.RoleOwner, "").Code,
+        "owners cannot read others' receipts")
+    assert.Equal(t, http.StatusForbidden, callAs(t, receipt, http.MethodGet, path, "walker-1", policy.RoleWalker, "").Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, receipt, http.MethodGet, path+"?format=pdf", "walker-1", policy.RoleWalker, "").Code)
+    for _, user := range [][2]string{{"owner-receipt-issued", policy.RoleOwner}, {"admin-1", policy.RoleAdmin}} {
+        response := callAs(t, receipt, http.MethodGet, path, user[0], user[1], "")
+        require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+        assert.Contains(t, response.Body.String(), issued.Number)
+    }
+
+    unissued := "/api/v1/bookings/receipt-unissued/receipt"
+    for i := 0; i < 2; i++ {
+        response := callAs(t, receipt, http.MethodGet, unissued, "owner-receipt-unissued", policy.RoleOwner, "")
+        assert.Equal(t, http.StatusConflict, response.Code, "reading a receipt does not issue it")
+        assert.Contains(t, response.Body.String(), "has not been issued")
+    }
+    _, err = store.Get(ctx, "receipts/receipt-unissued.json")
+    assert.ErrorIs(t, err, receipts.ErrNotFound)
+}
+
+// TestMemoryStoreTax verifies tax is recalculated with the amount and itemized on receipts
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreTax(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    rates, err := tax.ParseRates("State sales tax=6.25, City tax=1.5")
+    require.NoError(t, err)
+    tax.Init(rates)
+    t.Cleanup(func() { tax.Init(nil) })
+
+    store, err := receipts.NewFileStore(t.TempDir())
+    require.NoError(t, err)
+    ctx = service.WithProviders(ctx, service.Providers{Receipts: store})
+
+    booking := memoryBooking("taxed", "walker-1", time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC))
+    booking.Status = models.BookingStatusCompleted
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    adjusted, err := service.AdjustAmountService(ctx, "admin-1", "taxed", 40, "price correction")
+    require.NoError(t, err)
+    require.NotNil(t, adjusted.Tax)
+    assert.Equal(t, 2.5, adjusted.Tax.Lines[0].Amount)
+    assert.Equal(t, 0.6, adjusted.Tax.Lines[1].Amount)
+    assert.Equal(t, 3.1, adjusted.Tax.Total)
+    assert.Equal(t, int64(4310), models.AmountCents(adjusted.TotalAmount()))
+
+    receipt, err := service.IssueReceiptService(ctx, "taxed")
+    require.NoError(t, err)
+    require.Len(t, receipt.LineItems, 3)
+    assert.Equal(t, "State sales tax (6.25%)", receipt.LineItems[1].Description)
+    assert.Equal(t, int64(4310), receipt.TotalCents)
+
+    _, err = tax.ParseRates("VAT=120")
+    assert.Error(t, err)
+}
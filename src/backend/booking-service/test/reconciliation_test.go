@@ -0,0 +1,86 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// fakeLedger serves fixed settlements in place of the payment-service
+type fakeLedger []payments.Settlement
+
+func (l fakeLedger) Settlements(ctx context.Context, from, to time.Time) ([]payments.Settlement, error) {
+    return l, nil
+}
+
+// TestMemoryStoreReconciliation verifies booking amounts are compared against captures and
+// refunds, and that a period is reconciled only once
+func TestMemoryStoreReconciliation(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+    paidAt := day.Add(-7 * 24 * time.Hour)
+
+    bookings := map[string]models.BookingStatus{
+        "paid":     models.BookingStatusCompleted,
+        "short":    models.BookingStatusCompleted,
+        "unpaid":   models.BookingStatusCompleted,
+        "refunded": models.BookingStatusCancelled,
+        "kept":     models.BookingStatusCancelled,
+        "upcoming": models.BookingStatusConfirmed,
+    }
+    for id, status := range bookings {
+        booking := memoryBooking(id, "walker-1", day.Add(10*time.Hour))
+        booking.Status = status
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+
+    ctx = service.WithProviders(ctx, service.Providers{Ledger: fakeLedger{
+        {BookingID: "paid", Currency: "usd", CapturedCents: 2550, CreatedAt: paidAt},
+        {BookingID: "paid", Kind: payments.KindTip, Currency: "usd", CapturedCents: 500, CreatedAt: day.Add(12 * time.Hour)},
+        {BookingID: "short", Currency: "usd", CapturedCents: 2000, CreatedAt: paidAt},
+        {BookingID: "refunded", Currency: "usd", CapturedCents: 2550, RefundedCents: 2550, CreatedAt: paidAt},
+        {BookingID: "kept", Currency: "usd", CapturedCents: 2550, RefundedCents: 550, CreatedAt: paidAt},
+        {BookingID: "ghost", Currency: "usd", CapturedCents: 1000, CreatedAt: day.Add(time.Hour)},
+    }})
+
+    run, err := service.ReconcileService(ctx, day.Add(3*time.Hour), 10)
+    require.NoError(t, err)
+    assert.Equal(t, day, run.PeriodStart)
+    assert.Equal(t, models.ReconciliationCompleted, run.Status)
+    assert.Equal(t, 6, run.BookingsChecked)
+
+    kinds := make(map[string]models.DiscrepancyKind)
+    for _, d := range run.Discrepancies {
+        kinds[d.BookingID] = d.Kind
+    }
+    assert.Equal(t, map[string]models.DiscrepancyKind{
+        "short":  models.DiscrepancyAmountMismatch,
+        "unpaid": models.DiscrepancyMissingPayment,
+        "kept":   models.DiscrepancyUnrefundedCancellation,
+        "ghost":  models.DiscrepancyUnknownBooking,
+    }, kinds)
+    assert.Equal(t, int64(550+2550+2000+1000), run.DiscrepancyCents)
+
+    _, err = service.ReconcileService(ctx, day, 10)
+    assert.ErrorIs(t, err, service.ErrPeriodReconciled)
+
+    runs, err := service.ListReconciliationRunsService(ctx, 0)
+    require.NoError(t, err)
+    require.Len(t, runs, 1)
+    stored, err := service.GetReconciliationRunService(ctx, runs[0].ID)
+    require.NoError(t, err)
+    assert.Len(t, stored.Discrepancies, 4)
+}
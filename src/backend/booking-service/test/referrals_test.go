@@ -0,0 +1,65 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "encoding/json"
+    "net/http"
+    "testing"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/middleware"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/shared/policy"
+)
+
+// TestReferralsAsUser checks that users get their own referral code and attribute their own
+// signup, whoever the request names, and that only admins see the referral report
+func TestReferralsAsUser(t *testing.T) {
+    repository.UseMemoryStore()
+
+    code := middleware.RequirePermission(actionsSecret, policy.ResourceReferrals, policy.ActionRead)(handlers.GetReferralCodeHandler)
+    signup := middleware.RequirePermission(actionsSecret, policy.ResourceReferrals, policy.ActionCreate)(handlers.ReferralSignupHandler)
+    report := middleware.RequirePermission(actionsSecret, policy.ResourceReports, policy.ActionRead)(handlers.ReferralReportHandler)
+
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, code, http.MethodGet, "/api/v1/referrals/code", "", "", "").Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, code, http.MethodGet, "/api/v1/referrals/code?user_id=owner-referrer", "owner-snoop", policy.RoleOwner, "").Code,
+        "users cannot see each other's codes")
+
+    response := callAs(t, code, http.MethodGet, "/api/v1/referrals/code", "owner-referrer", policy.RoleOwner, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    var issued struct {
+        Data models.ReferralCode `json:"data"`
+    }
+    require.NoError(t, json.Unmarshal(response.Body.Bytes(), &issued))
+    assert.Equal(t, "owner-referrer", issued.Data.UserID)
+
+    response = callAs(t, code, http.MethodGet, "/api/v1/referrals/code?user_id=owner-referrer", "support", policy.RoleAdmin, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    assert.Contains(t, response.Body.String(), issued.Data.Code)
+
+    body := `{"code": "` + issued.Data.Code + `", "user_id": "owner-someone-else"}`
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, signup, http.MethodPost, "/api/v1/referrals/signups", "", "", body).Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, signup, http.MethodPost, "/api/v1/referrals/signups", "owner-referee", policy.RoleOwner, body).Code,
+        "users cannot attribute others' signups")
+
+    response = callAs(t, signup, http.MethodPost, "/api/v1/referrals/signups", "owner-referee", policy.RoleOwner, `{"code": "`+issued.Data.Code+`"}`)
+    require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+    var referred struct {
+        Data models.Referral `json:"data"`
+    }
+    require.NoError(t, json.Unmarshal(response.Body.Bytes(), &referred))
+    assert.Equal(t, "owner-referee", referred.Data.RefereeID)
+    assert.Equal(t, "owner-referrer", referred.Data.ReferrerID)
+
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, report, http.MethodGet, "/api/v1/referrals/report", "", "", "").Code)
+    assert.Equal(t, http.StatusForbidden, callAs(t, report, http.MethodGet, "/api/v1/referrals/report", "owner-referrer", policy.RoleOwner, "").Code)
+    response = callAs(t, report, http.MethodGet, "/api/v1/referrals/report", "support", policy.RoleAdmin, "")
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+}
@@ -0,0 +1,227 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/handlers"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/regions"
+)
+
+// TestMemoryStoreRegions verifies bookings are tagged with the region their pickup point falls
+// in and that lists and reports can be limited to one region
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreRegions(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    brooklyn := &regions.Region{Name: "Brooklyn", Boundary: []regions.Point{
+        {Latitude: 40.57, Longitude: -74.04}, {Latitude: 40.74, Longitude: -74.04},
+        {Latitude: 40.74, Longitude: -73.86}, {Latitude: 40.57, Longitude: -73.86},
+    }}
+    require.NoError(t, service.SaveRegionService(ctx, " NYC-Brooklyn ", brooklyn))
+    assert.Equal(t, "nyc-brooklyn", brooklyn.ID)
+
+    err := service.SaveRegionService(ctx, "nyc-queens", &regions.Region{Name: "Queens", Boundary: brooklyn.Boundary[:2]})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid region")
+
+    start := time.Now().Add(24 * time.Hour)
+    inside := memoryBooking("booking-inside", "", start)
+    latitude, longitude := 40.68, -73.94
+    inside.Latitude, inside.Longitude = &latitude, &longitude
+    inside.Region = "somewhere-else"
+    require.NoError(t, service.CreateBookingService(ctx, inside))
+    assert.Equal(t, "nyc-brooklyn", inside.Region)
+
+    outside := memoryBooking("booking-outside", "", start)
+    latitude, longitude = 40.78, -73.97
+    outside.Latitude, outside.Longitude = &latitude, &longitude
+    require.NoError(t, service.CreateBookingService(ctx, outside))
+    assert.Empty(t, outside.Region)
+
+    bookings, err := repository.ListBookings(ctx, models.BookingFilter{Region: "nyc-brooklyn"}, nil, 10)
+    require.NoError(t, err)
+    require.Len(t, bookings, 1)
+    assert.Equal(t, "booking-inside", bookings[0].ID)
+
+    require.NoError(t, service.DeleteRegionService(ctx, "nyc-brooklyn"))
+    list, err := service.ListRegionsService(ctx)
+    require.NoError(t, err)
+    assert.Empty(t, list)
+
+    err = service.DeleteRegionService(ctx, "nyc-brooklyn")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "region not found")
+}
+
+// TestMemoryStoreServiceArea verifies bookings and moves whose pickup lies outside every
+// service region are refused with their own code once regions are defined
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreServiceArea(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{ServiceAreaEnforced: true})
+
+    start := time.Now().Add(24 * time.Hour)
+    at := func(id string, latitude, longitude float64) *models.Booking {
+        booking := memoryBooking(id, "", start)
+        booking.Latitude, booking.Longitude = &latitude, &longitude
+        return booking
+    }
+
+    // Without regions there is no service area to enforce
+    require.NoError(t, service.CreateBookingService(ctx, at("area-undefined", 40.78, -73.97)))
+
+    require.NoError(t, service.SaveRegionService(ctx, "nyc-brooklyn", &regions.Region{Name: "Brooklyn", Boundary: []regions.Point{
+        {Latitude: 40.57, Longitude: -74.04}, {Latitude: 40.74, Longitude: -74.04},
+        {Latitude: 40.74, Longitude: -73.86}, {Latitude: 40.57, Longitude: -73.86},
+    }}))
+
+    inside := at("area-inside", 40.68, -73.94)
+    require.NoError(t, service.CreateBookingService(ctx, inside))
+    assert.Equal(t, "nyc-brooklyn", inside.Region)
+
+    err := service.CreateBookingService(ctx, at("area-outside", 40.78, -73.97))
+    var areaErr *service.ServiceAreaError
+    require.ErrorAs(t, err, &areaErr)
+    assert.Equal(t, 40.78, areaErr.Latitude)
+    assert.Contains(t, err.Error(), "outside the areas we serve")
+    _, err = repository.GetBookingByID(ctx, "area-outside")
+    require.Error(t, err)
+
+    // Bookings without pickup coordinates cannot be placed, so they are kept
+    require.NoError(t, service.CreateBookingService(ctx, memoryBooking("area-unplaced", "", start)))
+
+    // Moving the pickup out of the service area is refused too
+    _, err = service.PatchBookingService(ctx, inside.ID, inside.OwnerID, []byte(`{"latitude": 40.78, "longitude": -73.97}`))
+    require.ErrorAs(t, err, &areaErr)
+
+    // The handler tells clients the code of the refusal
+    body, err := json.Marshal(at("area-handler", 40.78, -73.97))
+    require.NoError(t, err)
+    rec := httptest.NewRecorder()
+    handlers.CreateBookingHandler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/bookings", bytes.NewReader(body)))
+    assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+    var response struct {
+        Error struct {
+            Code string `json:"code"`
+        } `json:"error"`
+    }
+    require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+    assert.Equal(t, service.CodeOutsideServiceArea, response.Error.Code)
+
+    // Operations can turn enforcement off, leaving such bookings without a region
+    config.Config.ServiceAreaEnforced = false
+    outside := at("area-allowed", 40.78, -73.97)
+    require.NoError(t, service.CreateBookingService(ctx, outside))
+    assert.Empty(t, outside.Region)
+}
+
+// TestMemoryStoreHolidays verifies walks cannot be booked or offered on a region's blackout
+// dates, and are priced with the surcharge of its holidays
+// Addresses requirement: Technical Specification/1.3 Scope/Core Features/Booking System
+func TestMemoryStoreHolidays(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{})
+
+    _, err := repository.SetWalkerVerification(ctx, &models.WalkerVerification{
+        WalkerID:  "walker-1",
+        Status:    models.WalkerVerified,
+        UpdatedBy: "admin-1",
+        UpdatedAt: time.Now(),
+    })
+    require.NoError(t, err)
+    require.NoError(t, service.SaveRatePlanService(ctx, &models.RatePlan{WalkerID: "walker-1", BaseRate: 20}))
+
+    // Three weekdays at noon UTC, at least a few days away
+    first := time.Now().UTC().AddDate(0, 0, 3)
+    for first.Weekday() == time.Saturday || first.Weekday() == time.Sunday || first.Weekday() == time.Friday {
+        first = first.AddDate(0, 0, 1)
+    }
+    first = time.Date(first.Year(), first.Month(), first.Day(), 12, 0, 0, 0, time.UTC)
+    blackout, holiday, local := first, first.AddDate(0, 0, 1), first.AddDate(0, 0, 2)
+    date := func(t time.Time) string { return t.Format(models.HolidayDateLayout) }
+
+    err = service.SaveHolidayService(ctx, models.DefaultRuleSetRegion, date(blackout), &models.Holiday{
+        Name: "Founders Day", Kind: models.HolidayBlackout, SurchargePercent: 10,
+    })
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid holiday")
+    require.NoError(t, service.SaveHolidayService(ctx, models.DefaultRuleSetRegion, date(blackout), &models.Holiday{
+        Name: "Founders Day", Kind: models.HolidayBlackout,
+    }))
+    require.NoError(t, service.SaveHolidayService(ctx, models.DefaultRuleSetRegion, date(holiday), &models.Holiday{
+        Name: "Parade Day", Kind: models.HolidaySurcharge, SurchargePercent: 50,
+    }))
+    // Brooklyn walks on the shared blackout date, but not on a date of its own
+    require.NoError(t, service.SaveHolidayService(ctx, "Brooklyn", date(blackout), &models.Holiday{
+        Name: "Street Fair", Kind: models.HolidaySurcharge, SurchargePercent: 25,
+    }))
+    require.NoError(t, service.SaveHolidayService(ctx, "brooklyn", date(local), &models.Holiday{
+        Name: "Marathon", Kind: models.HolidayBlackout,
+    }))
+
+    err = service.CreateBookingService(ctx, memoryBooking("booking-blackout", "walker-1", blackout))
+    require.Error(t, err)
+    assert.Equal(t, "invalid booking data: walks cannot be booked on "+date(blackout)+" (Founders Day)", err.Error())
+
+    _, err = service.QuoteRateService(ctx, "walker-1", "brooklyn", local, 30, false, 0)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "(Marathon)")
+
+    quote, err := service.QuoteRateService(ctx, "walker-1", "brooklyn", blackout, 30, false, 0)
+    require.NoError(t, err)
+    assert.Equal(t, []models.Surcharge{{Name: models.SurchargeHoliday, Amount: 5}}, quote.Surcharges)
+    assert.Equal(t, 25.0, quote.Amount)
+
+    booking := memoryBooking("booking-holiday", "walker-1", holiday)
+    require.NoError(t, service.CreateBookingService(ctx, booking))
+    assert.Equal(t, 30.0, booking.Amount)
+    assert.Equal(t, []models.Surcharge{{Name: models.SurchargeHoliday, Amount: 10}}, booking.Rate.Surcharges)
+
+    for id, day := range map[string]time.Time{"window-blackout": blackout, "window-holiday": holiday} {
+        require.NoError(t, service.CreateAvailabilityService(ctx, &models.Availability{
+            ID:       id,
+            WalkerID: "walker-1",
+            StartsAt: day.Add(-3 * time.Hour),
+            EndsAt:   day.Add(3 * time.Hour),
+        }))
+    }
+    windows, err := service.ListAvailabilityService(ctx, "walker-1")
+    require.NoError(t, err)
+    require.Len(t, windows, 1)
+    assert.Equal(t, "window-holiday", windows[0].ID)
+
+    require.NoError(t, service.DeleteHolidayService(ctx, models.DefaultRuleSetRegion, date(blackout)))
+    err = service.DeleteHolidayService(ctx, models.DefaultRuleSetRegion, date(blackout))
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "holiday not found")
+    require.NoError(t, service.CreateBookingService(ctx, memoryBooking("booking-reopened", "walker-1", blackout)))
+
+    own, err := service.ListHolidaysService(ctx, "brooklyn")
+    require.NoError(t, err)
+    require.Len(t, own, 2)
+    assert.Equal(t, "Street Fair", own[0].Name)
+}
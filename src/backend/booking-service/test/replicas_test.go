@@ -0,0 +1,59 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+)
+
+// TestReplicaReadsFallBackToPrimary verifies an unreachable replica does not keep the service
+// from starting, and that reads tolerating replica lag go to the primary while no replica is
+// healthy. The primary listens on port 1 and the replica on port 2, so errors show which of
+// them a read went to.
+func TestReplicaReadsFallBackToPrimary(t *testing.T) {
+    useUnreachableDB(t)
+    ctx := context.Background()
+
+    require.NoError(t, repository.InitReplicas(ctx, &config.Config{
+        ReplicaURLs:   []string{"host=127.0.0.1 port=2 sslmode=disable connect_timeout=1"},
+        ReplicaMaxLag: time.Second,
+    }))
+    t.Cleanup(func() { repository.Close() })
+
+    checks, stop := context.WithCancel(ctx)
+    stopped := make(chan struct{})
+    go func() {
+        defer close(stopped)
+        repository.RunReplicaHealthChecks(checks)
+    }()
+
+    for _, reads := range []context.Context{ctx, repository.ReplicaReads(ctx)} {
+        _, err := repository.GetBookingByID(reads, "replica-booking")
+        require.Error(t, err)
+        assert.Contains(t, err.Error(), "127.0.0.1:1")
+        assert.NotContains(t, err.Error(), "127.0.0.1:2")
+
+        _, err = repository.ListBookings(reads, models.BookingFilter{WalkerID: "walker-1"}, nil, 10)
+        require.Error(t, err)
+        assert.Contains(t, err.Error(), "127.0.0.1:1")
+        assert.NotContains(t, err.Error(), "127.0.0.1:2")
+    }
+
+    stop()
+    select {
+    case <-stopped:
+    case <-time.After(5 * time.Second):
+        t.Fatal("health checks did not stop with their context")
+    }
+}
@@ -0,0 +1,174 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "errors"
+    "net/mail"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/booking-service/internal/tracking"
+)
+
+// TestReportScheduleParsing verifies report schedules and their cron expressions are parsed,
+// and that the expressions fire when cron(8) would
+func TestReportScheduleParsing(t *testing.T) {
+    schedules, err := models.ParseReportSchedules("daily-bookings|bookings_summary|0 6 * * 1-5|ops@example.com, finance@example.com; weekly|earnings|@weekly|finance@example.com")
+    require.NoError(t, err)
+    require.Len(t, schedules, 2)
+    assert.Equal(t, models.ReportBookingsSummary, schedules[0].Report)
+    assert.Equal(t, []string{"ops@example.com", "finance@example.com"}, schedules[0].Recipients)
+
+    // Saturday 7 March 2026: weekdays at 06:00 next fire on Monday and last fired on Friday
+    saturday := time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)
+    weekdays := schedules[0].Cron
+    assert.Equal(t, time.Date(2026, 3, 9, 6, 0, 0, 0, time.UTC), weekdays.Next(saturday))
+    assert.Equal(t, time.Date(2026, 3, 6, 6, 0, 0, 0, time.UTC), weekdays.Prev(saturday))
+    assert.Equal(t, time.Date(2026, 3, 6, 6, 0, 0, 0, time.UTC), weekdays.Prev(time.Date(2026, 3, 9, 6, 0, 0, 0, time.UTC)))
+    assert.Equal(t, time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), schedules[1].Cron.Next(saturday))
+
+    // Restricting both day fields fires on a day matching either
+    either, err := models.ParseReportSchedules("either|earnings|30 8 1,15 * 1|finance@example.com")
+    require.NoError(t, err)
+    assert.Equal(t, time.Date(2026, 3, 9, 8, 30, 0, 0, time.UTC), either[0].Cron.Next(saturday))
+    assert.Equal(t, time.Date(2026, 3, 16, 8, 30, 0, 0, time.UTC), either[0].Cron.Next(time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC)))
+
+    steps, err := models.ParseReportSchedules("quarter-hourly|bookings_summary|*/15 9-17 * * *|ops@example.com")
+    require.NoError(t, err)
+    assert.Equal(t, time.Date(2026, 3, 7, 12, 15, 0, 0, time.UTC), steps[0].Cron.Next(saturday))
+    assert.Equal(t, time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC), steps[0].Cron.Next(time.Date(2026, 3, 7, 17, 45, 0, 0, time.UTC)))
+
+    for _, invalid := range []string{
+        "daily|bookings_summary|0 6 * * *",
+        "Daily|bookings_summary|0 6 * * *|ops@example.com",
+        "daily|revenue|0 6 * * *|ops@example.com",
+        "daily|bookings_summary|0 6 * *|ops@example.com",
+        "daily|bookings_summary|61 6 * * *|ops@example.com",
+        "daily|bookings_summary|0 0 30 2 *|ops@example.com",
+        "daily|bookings_summary|0 6 * * *|ops",
+        "daily|bookings_summary|0 6 * * *|ops@example.com;daily|earnings|0 7 * * *|ops@example.com",
+    } {
+        _, err := models.ParseReportSchedules(invalid)
+        assert.Error(t, err, invalid)
+    }
+}
+
+// failingMailer fails every message it is asked to send
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, from, address string, message []byte) error {
+    return errors.New("mail server unavailable")
+}
+
+// recordingAlerter records the alerts raised
+type recordingAlerter struct {
+    alerts []notifier.Alert
+}
+
+func (r *recordingAlerter) Alert(ctx context.Context, alert notifier.Alert) error {
+    r.alerts = append(r.alerts, alert)
+    return nil
+}
+
+// TestMemoryStoreScheduledReports verifies a scheduled report is emailed once per fire time
+// covering the period since the schedule last fired, and that a failed run is recorded and
+// alerted on
+func TestMemoryStoreScheduledReports(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    schedules, err := models.ParseReportSchedules(
+        "anomalies|tracking_anomalies|0 6 * * *|ops@example.com,finance@example.com;summary|bookings_summary|0 6 * * *|ops@example.com")
+    require.NoError(t, err)
+    useConfig(t, &config.Config{ReportSchedules: schedules})
+
+    mailer := &fakeMailer{sent: map[string][]string{}}
+    alerter := &recordingAlerter{}
+    providers := service.Providers{
+        Notifier: notifier.NewEmailNotifier(mailer, "DogWalker <walks@example.com>", notifier.LogNotifier{}),
+        Alerts:   alerter,
+    }
+
+    day := time.Date(2030, 3, 4, 9, 0, 0, 0, time.UTC)
+    for id, start := range map[string]time.Time{
+        "walk-ok":        day,
+        "walk-untracked": day.Add(time.Hour),
+        "walk-short":     day.Add(2 * time.Hour),
+        "walk-later":     day.Add(24 * time.Hour),
+    } {
+        booking := memoryBooking(id, "walker-1", start)
+        booking.Status = models.BookingStatusCompleted
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+    providers.Walks = &fakeWalks{evidence: map[string]tracking.WalkEvidence{
+        "walk-ok":    {Sessions: 1, DurationSeconds: 31 * 60, DistanceMeters: 1800},
+        "walk-short": {Sessions: 1, DurationSeconds: 5 * 60, DistanceMeters: 300},
+        "walk-later": {},
+    }}
+    ctx = service.WithProviders(ctx, providers)
+
+    now := time.Date(2030, 3, 5, 6, 0, 20, 0, time.UTC)
+    _, err = service.RunReportService(ctx, "missing", now)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "not found")
+
+    run, err := service.RunReportService(ctx, "anomalies", now)
+    require.NoError(t, err)
+    assert.Equal(t, models.ReportRunCompleted, run.Status)
+    assert.Equal(t, time.Date(2030, 3, 4, 6, 0, 0, 0, time.UTC), run.PeriodStart)
+    assert.Equal(t, time.Date(2030, 3, 5, 6, 0, 0, 0, time.UTC), run.PeriodEnd)
+    assert.Equal(t, 2, run.Delivered)
+
+    require.Len(t, mailer.sent["ops@example.com"], 1)
+    require.Len(t, mailer.sent["finance@example.com"], 1)
+    message, err := mail.ReadMessage(strings.NewReader(mailer.sent["ops@example.com"][0]))
+    require.NoError(t, err)
+    assert.Contains(t, message.Header.Get("Subject"), "Walk tracking anomalies")
+    email := mailer.sent["ops@example.com"][0]
+    assert.Contains(t, email, "2 of 3 completed walks checked look wrong")
+    assert.Contains(t, email, "walk-untracked, walker walker-1: never tracked")
+    assert.Contains(t, email, "walk-short, walker walker-1: tracked for 5 of")
+    assert.Contains(t, email, "anomalies-20300305-0600.csv")
+
+    // Each fire time is sent once
+    _, err = service.RunReportService(ctx, "anomalies", now.Add(10*time.Second))
+    assert.ErrorIs(t, err, service.ErrReportRunClaimed)
+
+    // A run no recipient receives fails, alerting operations, and is not retried at once
+    providers.Notifier = notifier.NewEmailNotifier(failingMailer{}, "DogWalker <walks@example.com>", notifier.LogNotifier{})
+    ctx = service.WithProviders(ctx, providers)
+    _, err = service.RunReportService(ctx, "summary", now)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "failed to send report")
+    require.Len(t, alerter.alerts, 1)
+    assert.Equal(t, "summary", alerter.alerts[0].Fields["schedule"])
+    assert.Equal(t, "1 of 3", alerter.alerts[0].Fields["attempt"])
+    _, err = service.RunReportService(ctx, "summary", now)
+    assert.ErrorIs(t, err, service.ErrReportRunClaimed)
+
+    runs, err := service.ListReportRunsService(ctx, "summary", 0)
+    require.NoError(t, err)
+    require.Len(t, runs, 1)
+    assert.Equal(t, models.ReportRunFailed, runs[0].Status)
+    assert.Contains(t, runs[0].Error, "mail server unavailable")
+
+    scheduled, err := service.ListReportSchedulesService(ctx, now)
+    require.NoError(t, err)
+    require.Len(t, scheduled, 2)
+    assert.Equal(t, time.Date(2030, 3, 6, 6, 0, 0, 0, time.UTC), scheduled[0].NextRunAt)
+    require.NotNil(t, scheduled[0].LastRun)
+    assert.Equal(t, models.ReportRunCompleted, scheduled[0].LastRun.Status)
+}
@@ -0,0 +1,175 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// fakeHolds authorizes payments unless told to decline, recording holds until they are voided
+type fakeHolds struct {
+    decline bool
+    held    map[string]string // payment ID -> charge ID
+    voided  []string
+}
+
+func (f *fakeHolds) Authorize(ctx context.Context, charge payments.Charge) (*payments.ChargeResult, error) {
+    if f.decline {
+        return nil, errors.New("card declined")
+    }
+    paymentID := "pi_" + charge.ID
+    f.held[paymentID] = charge.ID
+    return &payments.ChargeResult{PaymentID: paymentID, Status: "requires_capture"}, nil
+}
+
+func (f *fakeHolds) Void(ctx context.Context, paymentID string) error {
+    delete(f.held, paymentID)
+    f.voided = append(f.voided, paymentID)
+    return nil
+}
+
+// TestMemoryStoreConfirmationSaga checks that accepting a booking holds its payment, and that a
+// declined payment puts the booking back to awaiting the walker
+func TestMemoryStoreConfirmationSaga(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    start := time.Now().Add(24 * time.Hour)
+
+    holds := &fakeHolds{held: make(map[string]string)}
+    withHolds := service.WithProviders(ctx, service.Providers{Holds: holds})
+
+    acceptBy := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+    for i, id := range []string{"saga-held", "saga-declined"} {
+        require.NoError(t, repository.CreateBooking(ctx, memoryBooking(id, "", start.Add(time.Duration(i)*time.Hour))))
+        _, err := repository.AssignWalker(ctx, id, "walker-1", 1, acceptBy)
+        require.NoError(t, err)
+    }
+
+    confirmed, err := service.AcceptAssignmentService(withHolds, "saga-held", "walker-1")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusConfirmed, confirmed.Status)
+    require.Len(t, holds.held, 1)
+    for paymentID, sagaID := range holds.held {
+        saga, err := repository.GetSaga(ctx, sagaID)
+        require.NoError(t, err)
+        require.NotNil(t, saga)
+        assert.Equal(t, models.SagaStatusCompleted, saga.Status)
+        assert.Equal(t, paymentID, saga.PaymentID)
+        assert.Equal(t, models.SagaStepDone, saga.Step("authorize_payment").Status)
+    }
+
+    // Accepting again returns the confirmed booking without holding the payment twice
+    again, err := service.AcceptAssignmentService(withHolds, "saga-held", "walker-1")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusConfirmed, again.Status)
+    assert.Len(t, holds.held, 1)
+
+    holds.decline = true
+    _, err = service.AcceptAssignmentService(withHolds, "saga-declined", "walker-1")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "payment not authorized")
+    reverted, err := service.GetBookingService(ctx, "saga-declined")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusPending, reverted.Status)
+    assert.Equal(t, "walker-1", reverted.WalkerID)
+    require.NotNil(t, reverted.AcceptBy)
+    assert.True(t, acceptBy.Equal(*reverted.AcceptBy), "the walker keeps their original deadline")
+
+    // Once the owner's payment goes through the walker can accept again
+    holds.decline = false
+    confirmed, err = service.AcceptAssignmentService(withHolds, "saga-declined", "walker-1")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusConfirmed, confirmed.Status)
+    assert.Len(t, holds.held, 2)
+
+    // Without a payment service bookings are confirmed without a hold
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("saga-unheld", "", start.Add(2*time.Hour))))
+    _, err = repository.AssignWalker(ctx, "saga-unheld", "walker-1", 1, acceptBy)
+    require.NoError(t, err)
+    confirmed, err = service.AcceptAssignmentService(ctx, "saga-unheld", "walker-1")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusConfirmed, confirmed.Status)
+
+    // A stalled saga is taken over by exactly one claimant
+    stalledAt := time.Now().Add(-time.Hour).UTC()
+    require.NoError(t, repository.SaveSaga(ctx, &models.Saga{
+        ID:        "saga-stalled",
+        Kind:      models.SagaKindBookingConfirmation,
+        BookingID: "saga-unheld",
+        Status:    models.SagaStatusCompensating,
+        UpdatedAt: stalledAt,
+    }))
+    stalled, err := repository.ListStalledSagas(ctx, time.Now().Add(-time.Minute), 10)
+    require.NoError(t, err)
+    require.Len(t, stalled, 1)
+    assert.Equal(t, "saga-stalled", stalled[0].ID)
+    claimed, err := repository.ClaimSaga(ctx, "saga-stalled", stalled[0].UpdatedAt, time.Now())
+    require.NoError(t, err)
+    assert.True(t, claimed)
+    claimed, err = repository.ClaimSaga(ctx, "saga-stalled", stalled[0].UpdatedAt, time.Now())
+    require.NoError(t, err)
+    assert.False(t, claimed)
+}
+
+// TestMemoryStoreEventSourcing checks that every change to a booking is recorded and that a
+// booking can be rebuilt as it was after any of them
+func TestMemoryStoreEventSourcing(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+    start := time.Now().Add(24 * time.Hour)
+
+    // Bookings made before event sourcing is turned on are imported as they stand
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("es-imported", "", start)))
+    require.NoError(t, repository.ConfigureEventSourcing(ctx, true, 10))
+    t.Cleanup(func() { repository.ConfigureEventSourcing(context.Background(), false, 0) })
+
+    require.NoError(t, repository.CreateBooking(ctx, memoryBooking("es-booking", "", start.Add(time.Hour))))
+    _, err := repository.AssignWalker(ctx, "es-booking", "walker-1", 1, time.Now().Add(time.Hour))
+    require.NoError(t, err)
+    require.NoError(t, repository.AcceptAssignment(ctx, "es-booking", "walker-1"))
+
+    history, err := service.BookingHistoryService(ctx, "es-booking")
+    require.NoError(t, err)
+    require.NotEmpty(t, history)
+    assert.Equal(t, models.BookingEventCreated, history[0].Type)
+    assert.Equal(t, models.BookingEventStatusChanged, history[len(history)-1].Type)
+    for i, event := range history {
+        assert.Equal(t, i+1, event.Version)
+    }
+
+    original, err := service.BookingAtVersionService(ctx, "es-booking", 1)
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusPending, original.Status)
+    assert.Empty(t, original.WalkerID)
+
+    current, err := service.GetBookingService(ctx, "es-booking")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusConfirmed, current.Status)
+    assert.Equal(t, "walker-1", current.WalkerID)
+
+    _, err = service.BookingAtVersionService(ctx, "es-booking", len(history)+1)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid version")
+
+    imported, err := service.BookingHistoryService(ctx, "es-imported")
+    require.NoError(t, err)
+    require.Len(t, imported, 1)
+    assert.Equal(t, models.BookingEventImported, imported[0].Type)
+
+    _, err = service.BookingHistoryService(ctx, "es-missing")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking not found")
+}
@@ -0,0 +1,81 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/seed"
+)
+
+// seedBookings seeds a fresh store with opts and returns its report and bookings
+func seedBookings(t *testing.T, opts seed.Options) (*seed.Report, []models.Booking) {
+    t.Helper()
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    report, err := seed.Run(ctx, opts)
+    require.NoError(t, err)
+    bookings, err := repository.ListBookings(ctx, models.BookingFilter{}, nil, 10000)
+    require.NoError(t, err)
+    return report, bookings
+}
+
+// seededSchedule describes each booking by the fields a seed chooses
+func seededSchedule(bookings []models.Booking) []string {
+    schedule := make([]string, len(bookings))
+    for i, b := range bookings {
+        schedule[i] = fmt.Sprintf("%s %s %s %s %s %v %d", b.ID, b.OwnerID, b.WalkerID, b.DogID, b.Status, b.ScheduledAt, b.DurationMinutes)
+    }
+    return schedule
+}
+
+// TestSeedReproducible verifies the same seed always seeds the same bookings, within the
+// walkers' working day and their capacity, with statuses that fit their date
+func TestSeedReproducible(t *testing.T) {
+    start := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+    opts := seed.Options{Seed: 7, Walkers: 3, Owners: 5, Bookings: 150, Days: 2, Start: start}
+
+    report, bookings := seedBookings(t, opts)
+    assert.Equal(t, 3, report.Walkers)
+    assert.Equal(t, 12, report.Windows, "every walker is available every day")
+    assert.Equal(t, 150, report.Bookings+report.Skipped)
+    assert.Positive(t, report.Skipped, "bookings that would overbook a walker are skipped")
+    require.Len(t, bookings, report.Bookings)
+
+    for _, booking := range bookings {
+        assert.True(t, strings.HasPrefix(booking.ID, "seed7-booking-"), booking.ID)
+        day := booking.ScheduledAt.Truncate(24 * time.Hour)
+        assert.GreaterOrEqual(t, booking.ScheduledAt.Sub(day), seed.WorkdayStart, booking.ID)
+        assert.LessOrEqual(t, booking.EndsAt().Sub(day), seed.WorkdayEnd, booking.ID)
+
+        if booking.ScheduledAt.Before(start) {
+            assert.Contains(t, []models.BookingStatus{models.BookingStatusCompleted, models.BookingStatusCancelled, models.BookingStatusFailed}, booking.Status)
+        } else {
+            assert.Contains(t, []models.BookingStatus{models.BookingStatusConfirmed, models.BookingStatusPending, models.BookingStatusCancelled}, booking.Status)
+        }
+    }
+
+    again, reseeded := seedBookings(t, opts)
+    assert.Equal(t, report, again)
+    assert.Equal(t, seededSchedule(bookings), seededSchedule(reseeded))
+
+    opts.Seed = 8
+    _, other := seedBookings(t, opts)
+    require.NotEmpty(t, other)
+    assert.True(t, strings.HasPrefix(other[0].ID, "seed8-"), "IDs are prefixed with their seed so seeds can coexist")
+
+    _, err := seed.Run(context.Background(), seed.Options{Seed: 1, Walkers: 0, Owners: 1, Days: 1, Start: start})
+    assert.Error(t, err)
+}
@@ -0,0 +1,225 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/booking-service/internal/tracking"
+)
+
+// fakeLocator reports fixed walker positions
+type fakeLocator struct {
+    positions map[string]tracking.Position
+}
+
+func (f *fakeLocator) LastPosition(ctx context.Context, walkerID string) (*tracking.Position, error) {
+    position, ok := f.positions[walkerID]
+    if !ok {
+        return nil, tracking.ErrNoPosition
+    }
+    return &position, nil
+}
+
+// TestMemoryStoreCheckIn checks that walkers only check in near the pickup with a recent
+// position, that support can check them in instead, and that checking out completes the walk
+func TestMemoryStoreCheckIn(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{CheckInRadius: 200, CheckInPositionMaxAge: 5 * time.Minute})
+
+    latitude, longitude := 51.5007, -0.1416
+    for _, id := range []string{"shift-near", "shift-far", "shift-stale", "shift-override"} {
+        booking := memoryBooking(id, "walker-"+id, time.Now().Add(10*time.Minute))
+        booking.Status = models.BookingStatusConfirmed
+        booking.Latitude, booking.Longitude = &latitude, &longitude
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+
+    _, err := service.CheckInService(ctx, "shift-near", "walker-shift-near")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "check-in unavailable")
+
+    ctx = service.WithProviders(ctx, service.Providers{Locator: &fakeLocator{positions: map[string]tracking.Position{
+        // About 100m and 1km from the pickup
+        "walker-shift-near":  {Latitude: 51.5016, Longitude: -0.1416, UpdatedAt: time.Now().Add(-time.Minute)},
+        "walker-shift-far":   {Latitude: 51.5097, Longitude: -0.1416, UpdatedAt: time.Now()},
+        "walker-shift-stale": {Latitude: latitude, Longitude: longitude, UpdatedAt: time.Now().Add(-time.Hour)},
+    }}})
+
+    _, err = service.CheckInService(ctx, "shift-near", "walker-shift-far")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid check-in")
+
+    _, err = service.CheckInService(ctx, "shift-far", "walker-shift-far")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "check-in not allowed")
+    assert.Contains(t, err.Error(), "from the pickup")
+
+    _, err = service.CheckInService(ctx, "shift-stale", "walker-shift-stale")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "last tracked")
+
+    _, err = service.CheckInService(ctx, "shift-override", "walker-shift-override")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "no tracked position")
+
+    started, err := service.CheckInService(ctx, "shift-near", "walker-shift-near")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusInProgress, started.Status)
+
+    shift, err := repository.GetShift(ctx, "shift-near")
+    require.NoError(t, err)
+    require.NotNil(t, shift.CheckInDistance)
+    assert.InDelta(t, 100, *shift.CheckInDistance, 10)
+    assert.Nil(t, shift.CheckedOutAt)
+
+    // A double-tapped check-in returns the started walk; another walker still conflicts
+    again, err := service.CheckInService(ctx, "shift-near", "walker-shift-near")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusInProgress, again.Status)
+    _, err = service.CheckInService(ctx, "shift-near", "walker-shift-far")
+    require.Error(t, err)
+
+    // Support checks in the walker whose position is unknown, giving a reason for the audit log
+    _, err = service.CheckInOverrideService(ctx, "admin-1", "shift-override", " ")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid override")
+
+    overridden, err := service.CheckInOverrideService(ctx, "admin-1", "shift-override", "walker's GPS is down")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusInProgress, overridden.Status)
+    shift, err = repository.GetShift(ctx, "shift-override")
+    require.NoError(t, err)
+    assert.Equal(t, "admin-1", shift.OverriddenBy)
+    assert.Nil(t, shift.CheckInDistance)
+
+    _, err = service.CheckOutService(ctx, "shift-near", "walker-shift-far")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking conflict")
+
+    completed, err := service.CheckOutService(ctx, "shift-near", "walker-shift-near")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCompleted, completed.Status)
+    shift, err = repository.GetShift(ctx, "shift-near")
+    require.NoError(t, err)
+    assert.NotNil(t, shift.CheckedOutAt)
+
+    again, err = service.CheckOutService(ctx, "shift-near", "walker-shift-near")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCompleted, again.Status)
+    _, err = service.CheckInService(ctx, "shift-near", "walker-shift-near")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "booking conflict")
+}
+
+// TestMemoryStoreDoubleTapCheckIn checks that check-ins racing for the same walk all return
+// the started walk rather than a conflict
+func TestMemoryStoreDoubleTapCheckIn(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{CheckInRadius: 200, CheckInPositionMaxAge: 5 * time.Minute})
+
+    latitude, longitude := 51.5007, -0.1416
+    booking := memoryBooking("double-tap", "walker-1", time.Now().Add(10*time.Minute))
+    booking.Status = models.BookingStatusConfirmed
+    booking.Latitude, booking.Longitude = &latitude, &longitude
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    ctx = service.WithProviders(ctx, service.Providers{Locator: &fakeLocator{positions: map[string]tracking.Position{
+        "walker-1": {Latitude: latitude, Longitude: longitude, UpdatedAt: time.Now()},
+    }}})
+
+    var wg sync.WaitGroup
+    errs := make(chan error, 5)
+    for i := 0; i < 5; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            started, err := service.CheckInService(ctx, "double-tap", "walker-1")
+            if err == nil && started.Status != models.BookingStatusInProgress {
+                err = fmt.Errorf("check-in returned a %s booking", started.Status)
+            }
+            errs <- err
+        }()
+    }
+    wg.Wait()
+    close(errs)
+    for err := range errs {
+        assert.NoError(t, err)
+    }
+}
+
+// fakeWalks reports fixed walk evidence per booking
+type fakeWalks struct {
+    evidence map[string]tracking.WalkEvidence
+}
+
+func (f *fakeWalks) WalkEvidence(ctx context.Context, bookingID string) (*tracking.WalkEvidence, error) {
+    evidence := f.evidence[bookingID]
+    evidence.BookingID = bookingID
+    return &evidence, nil
+}
+
+// TestMemoryStoreProofOfCompletion checks that checking out only completes a walk once the
+// required duration, distance and photos have been tracked
+func TestMemoryStoreProofOfCompletion(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{Completion: models.CompletionRequirements{
+        MinDuration: 20 * time.Minute,
+        MinDistance: 500,
+        MinPhotos:   1,
+    }})
+
+    for _, id := range []string{"proof-short", "proof-done"} {
+        booking := memoryBooking(id, "walker-proof", time.Now().Add(-time.Hour))
+        booking.Status = models.BookingStatusInProgress
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+
+    _, err := service.CheckOutService(ctx, "proof-done", "walker-proof")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "completion unavailable")
+
+    ctx = service.WithProviders(ctx, service.Providers{Walks: &fakeWalks{evidence: map[string]tracking.WalkEvidence{
+        "proof-short": {Sessions: 1, DurationSeconds: 5 * 60, DistanceMeters: 1200},
+        "proof-done":  {Sessions: 1, DurationSeconds: 31 * 60, DistanceMeters: 1800, Photos: 2},
+    }}})
+
+    _, err = service.CheckOutService(ctx, "proof-short", "walker-proof")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "completion not allowed")
+    assert.Contains(t, err.Error(), "the walk lasted 5m0s of the 20m0s required")
+    assert.Contains(t, err.Error(), "0 of the 1 photos required")
+    assert.NotContains(t, err.Error(), "covered")
+
+    stored, err := service.GetBookingService(ctx, "proof-short")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusInProgress, stored.Status)
+
+    completed, err := service.CheckOutService(ctx, "proof-done", "walker-proof")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCompleted, completed.Status)
+
+    // An admin can still force a walk without enough proof to completed
+    forced, err := service.ForceStatusService(ctx, "admin-1", "proof-short", models.BookingStatusCompleted, "walked, phone died")
+    require.NoError(t, err)
+    assert.Equal(t, models.BookingStatusCompleted, forced.Status)
+}
@@ -0,0 +1,175 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "crypto/hmac"
+    "crypto/sha1"
+    "encoding/base64"
+    "net/http"
+    "net/url"
+    "sort"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/notifier"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+    "src/backend/shared/policy"
+)
+
+// fakeTexter records texts, refusing numbers that have opted out with the carrier
+type fakeTexter struct {
+    texts    map[string][]string
+    optedOut map[string]bool
+}
+
+func (f *fakeTexter) Text(ctx context.Context, phone, body string) error {
+    if f.optedOut[phone] {
+        return notifier.ErrOptedOut
+    }
+    f.texts[phone] = append(f.texts[phone], body)
+    return nil
+}
+
+// signSMSReply signs an inbound SMS webhook request the way the messaging service does
+func signSMSReply(authToken, webhookURL string, params url.Values) string {
+    keys := make([]string, 0, len(params))
+    for key := range params {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    payload := webhookURL
+    for _, key := range keys {
+        payload += key + params.Get(key)
+    }
+    mac := hmac.New(sha1.New, []byte(authToken))
+    mac.Write([]byte(payload))
+    return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// TestMemoryStoreSMS verifies owners are texted as their walk progresses until they opt out
+// by setting, by reply or with the carrier
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Notification Service
+func TestMemoryStoreSMS(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    const webhookURL = "https://bookings.example.com/api/v1/notifications/sms/inbound"
+    useConfig(t, &config.Config{SMS: notifier.SMSOptions{AuthToken: "sms-token", WebhookURL: webhookURL}})
+    texter := &fakeTexter{texts: map[string][]string{}, optedOut: map[string]bool{}}
+    ctx = service.WithProviders(ctx, service.Providers{Texts: texter})
+
+    start := time.Date(2030, 3, 4, 9, 30, 0, 0, time.UTC)
+    booking := memoryBooking("booking-1", "walker-1", start)
+    booking.Status = models.BookingStatusConfirmed
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+
+    // Owners who have given no number are not texted
+    _, err := service.WalkerEnRouteService(ctx, "booking-1", "walker-1", 10)
+    require.NoError(t, err)
+    assert.Empty(t, texter.texts)
+
+    _, err = service.SaveSMSSettingsService(ctx, booking.OwnerID, &models.SMSSettings{Phone: "07700 900123"})
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid SMS settings")
+
+    const phone = "+447700900123"
+    _, err = service.SaveSMSSettingsService(ctx, booking.OwnerID, &models.SMSSettings{Phone: phone})
+    require.NoError(t, err)
+
+    _, err = service.WalkerEnRouteService(ctx, "booking-1", "walker-2", 10)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid en route update")
+    _, err = service.WalkerEnRouteService(ctx, "booking-1", "walker-1", 600)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "invalid en route update")
+
+    _, err = service.WalkerEnRouteService(ctx, "booking-1", "walker-1", 10)
+    require.NoError(t, err)
+    require.Len(t, texter.texts[phone], 1)
+    assert.Equal(t, "Your walker is on the way and should arrive in about 10 min. Reply STOP to opt out.", texter.texts[phone][0])
+
+    // Replying STOP opts the number out; the reply must be signed by the messaging service
+    reply := url.Values{"From": {phone}, "Body": {" stop "}, "MessageSid": {"SM1"}}
+    _, err = service.ReceiveSMSService(ctx, reply, "forged")
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "signature")
+
+    changed, err := service.ReceiveSMSService(ctx, reply, signSMSReply("sms-token", webhookURL, reply))
+    require.NoError(t, err)
+    assert.True(t, changed)
+    settings, err := service.GetSMSSettingsService(ctx, booking.OwnerID)
+    require.NoError(t, err)
+    assert.True(t, settings.OptedOut)
+
+    _, err = service.ForceStatusService(ctx, "admin-1", "booking-1", models.BookingStatusCompleted, "walker app offline")
+    require.NoError(t, err)
+    assert.Len(t, texter.texts[phone], 1)
+
+    reply = url.Values{"From": {phone}, "Body": {"START"}, "MessageSid": {"SM2"}}
+    changed, err = service.ReceiveSMSService(ctx, reply, signSMSReply("sms-token", webhookURL, reply))
+    require.NoError(t, err)
+    assert.True(t, changed)
+
+    // A number the carrier refuses is opted out so it is not tried again
+    confirmed := memoryBooking("booking-2", "walker-1", start.Add(24*time.Hour))
+    confirmed.OwnerID = booking.OwnerID
+    require.NoError(t, repository.CreateBooking(ctx, confirmed))
+    _, err = repository.AssignWalker(ctx, confirmed.ID, "walker-1", 1, time.Now().Add(time.Hour))
+    require.NoError(t, err)
+    texter.optedOut[phone] = true
+    _, err = service.AcceptAssignmentService(ctx, confirmed.ID, "walker-1")
+    require.NoError(t, err)
+    optOut, err := repository.GetSMSOptOut(ctx, phone)
+    require.NoError(t, err)
+    require.NotNil(t, optOut)
+    assert.Equal(t, models.SMSOptOutCarrier, optOut.Source)
+
+    body, err := notifier.RenderSMS(notifier.SMSBookingConfirmed, notifier.SMSData{Time: "Tue 5 Mar 09:30 UTC"})
+    require.NoError(t, err)
+    assert.Equal(t, "Your dog walk on Tue 5 Mar 09:30 UTC is confirmed. Reply STOP to opt out.", body)
+    _, err = notifier.RenderSMS("unknown", notifier.SMSData{})
+    require.Error(t, err)
+
+    senders, err := notifier.ParseSMSSenders("44=+447700900000, 1=+15550100, default=DogWalk")
+    require.NoError(t, err)
+    assert.Equal(t, "+447700900000", senders["44"])
+    _, err = notifier.ParseSMSSenders("uk=+447700900000")
+    require.Error(t, err)
+}
+
+// TestEnRouteAsWalker checks that walkers say they are on the way as the user of their token,
+// whoever the body names
+func TestEnRouteAsWalker(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    texter := &fakeTexter{texts: map[string][]string{}, optedOut: map[string]bool{}}
+    booking := memoryBooking("en-route-token", "walker-en-route", time.Now().Add(30*time.Minute))
+    booking.Status = models.BookingStatusConfirmed
+    require.NoError(t, repository.CreateBooking(ctx, booking))
+    _, err := service.SaveSMSSettingsService(ctx, booking.OwnerID, &models.SMSSettings{Phone: "+447700900456"})
+    require.NoError(t, err)
+
+    actions := withProviders(service.Providers{Texts: texter}, bookingActions())
+    body := `{"walker_id": "walker-en-route", "eta_minutes": 10}`
+    assert.Equal(t, http.StatusUnauthorized, callAs(t, actions, http.MethodPost, "/api/v1/bookings/en-route-token/en-route", "", "", body).Code)
+    assert.Equal(t, http.StatusBadRequest, callAs(t, actions, http.MethodPost, "/api/v1/bookings/en-route-token/en-route", booking.OwnerID, policy.RoleOwner, body).Code,
+        "owners are not the booking's walker")
+    assert.Equal(t, http.StatusBadRequest, callAs(t, actions, http.MethodPost, "/api/v1/bookings/en-route-token/en-route", "walker-intruder", policy.RoleWalker, body).Code,
+        "the body cannot name another walker")
+    assert.Empty(t, texter.texts)
+
+    response := callAs(t, actions, http.MethodPost, "/api/v1/bookings/en-route-token/en-route", "walker-en-route", policy.RoleWalker, `{"eta_minutes": 10}`)
+    require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+    assert.Len(t, texter.texts["+447700900456"], 1)
+}
@@ -0,0 +1,96 @@
+//go:build !integration
+
+// The in-memory store replaces PostgreSQL for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+    "context"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/stretchr/testify/assert"  // v1.8.0
+    "github.com/stretchr/testify/require" // v1.8.0
+
+    "src/backend/booking-service/internal/config"
+    "src/backend/booking-service/internal/models"
+    "src/backend/booking-service/internal/payments"
+    "src/backend/booking-service/internal/repository"
+    "src/backend/booking-service/internal/service"
+)
+
+// fakeCharger accepts charges in place of the payment-service, failing while fail is set
+type fakeCharger struct {
+    charges []payments.Charge
+    fail    bool
+}
+
+func (c *fakeCharger) Charge(ctx context.Context, charge payments.Charge) (*payments.ChargeResult, error) {
+    if c.fail {
+        return nil, errors.New("payment-service unavailable")
+    }
+    c.charges = append(c.charges, charge)
+    return &payments.ChargeResult{PaymentID: "pi_" + charge.ID, Status: "requires_confirmation"}, nil
+}
+
+// TestMemoryStoreTips verifies tips are limited to the owner within the tipping window, are
+// charged separately from the booking and count towards the walker's earnings
+// Addresses requirement: Booking System Testing/7.2.1 Core Components/Booking Service
+func TestMemoryStoreTips(t *testing.T) {
+    repository.UseMemoryStore()
+    ctx := context.Background()
+
+    useConfig(t, &config.Config{TipWindow: 72 * time.Hour})
+    charger := &fakeCharger{}
+    ctx = service.WithProviders(ctx, service.Providers{Charges: charger})
+
+    walked := time.Now().Add(-2 * time.Hour).Truncate(time.Minute)
+    for id, start := range map[string]time.Time{
+        "tip-recent": walked,
+        "tip-retry":  walked,
+        "tip-stale":  walked.Add(-5 * 24 * time.Hour),
+    } {
+        booking := memoryBooking(id, "walker-1", start)
+        booking.Status = models.BookingStatusCompleted
+        require.NoError(t, repository.CreateBooking(ctx, booking))
+    }
+
+    tip, charge, err := service.AddTipService(ctx, "tip-recent", "owner-tip-recent", 5.004)
+    require.NoError(t, err)
+    assert.Equal(t, 5.0, tip.Amount)
+    assert.Equal(t, models.TipStatusCharged, tip.Status)
+    assert.Equal(t, "pi_"+tip.ID, charge.PaymentID)
+    require.Len(t, charger.charges, 1)
+    assert.Equal(t, payments.KindTip, charger.charges[0].Kind)
+    assert.Equal(t, int64(500), charger.charges[0].AmountCents)
+
+    _, _, err = service.AddTipService(ctx, "tip-recent", "owner-tip-recent", 2)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "tip conflict")
+
+    _, _, err = service.AddTipService(ctx, "tip-stale", "owner-tip-stale", 2)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "tip not allowed")
+
+    _, _, err = service.AddTipService(ctx, "tip-retry", "owner-tip-recent", 2)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "only the booking's owner")
+
+    // A tip whose payment could not be requested can be given again
+    charger.fail = true
+    _, _, err = service.AddTipService(ctx, "tip-retry", "owner-tip-retry", 3)
+    require.Error(t, err)
+    assert.Contains(t, err.Error(), "failed to charge tip")
+    charger.fail = false
+    _, _, err = service.AddTipService(ctx, "tip-retry", "owner-tip-retry", 3)
+    require.NoError(t, err)
+
+    earnings, err := service.WalkerEarningsReportService(ctx, walked.Add(-6*24*time.Hour), time.Now(), "walker-1", "")
+    require.NoError(t, err)
+    require.Len(t, earnings, 1)
+    assert.Equal(t, 3, earnings[0].Bookings)
+    assert.InDelta(t, 76.5, earnings[0].BookingAmount, 0.001)
+    assert.InDelta(t, 8.0, earnings[0].Tips, 0.001)
+    assert.InDelta(t, 84.5, earnings[0].Total, 0.001)
+}
@@ -6,7 +6,7 @@
  * 4. Set up monitoring for notification delivery success rates
  */
 
-import { sendEmail } from '../services/email';
+import { EmailAttachment, sendEmail } from '../services/email';
 import { sendPushNotification } from '../services/push';
 import logger from '../../../shared/utils/logger';
 import { createHttpError } from '../../../shared/utils/error';
@@ -21,6 +21,7 @@ interface NotificationPayload {
   data?: Record<string, string>;
   priority?: 'high' | 'normal';
   imageUrl?: string;
+  attachments?: EmailAttachment[];
 }
 
 /**
@@ -66,7 +67,8 @@ export const sendNotification = async (
         await sendEmail(
           payload.recipient,
           payload.subject,
-          payload.body
+          payload.body,
+          payload.attachments
         );
         break;
 
@@ -9,6 +9,7 @@
 // express v4.18.2
 import { Router, Request, Response, NextFunction } from 'express';
 import { sendNotification } from '../controllers/notification';
+import { EmailAttachment } from '../services/email';
 import logger from '../../../shared/utils/logger';
 import { createHttpError } from '../../../shared/utils/error';
 
@@ -23,6 +24,7 @@ interface NotificationRequest {
   data?: Record<string, string>;
   priority?: 'high' | 'normal';
   imageUrl?: string;
+  attachments?: EmailAttachment[];
 }
 
 /**
@@ -63,7 +65,8 @@ export const initializeRoutes = (router: Router): void => {
           body: notificationRequest.body,
           data: notificationRequest.data,
           priority: notificationRequest.priority,
-          imageUrl: notificationRequest.imageUrl
+          imageUrl: notificationRequest.imageUrl,
+          attachments: notificationRequest.attachments
         }
       );
 
@@ -13,6 +13,17 @@ import { logInfo, logError } from '../../../shared/utils/logger';
 import { createHttpError } from '../../../shared/utils/error';
 import { loadConfig } from '../config';
 
+/**
+ * A document attached to an email; content is base64 encoded
+ */
+export interface EmailAttachment {
+  filename: string;
+  contentType: string;
+  content: string;
+  // Content-ID of an inline image, which the HTML body refers to as cid:<contentId>
+  contentId?: string;
+}
+
 // Global email transporter instance
 let emailTransporter: nodemailer.Transporter;
 
@@ -61,13 +72,15 @@ export const initializeEmailService = async (): Promise<void> => {
  * @param recipient - Email address of the recipient
  * @param subject - Subject line of the email
  * @param body - HTML or text content of the email
+ * @param attachments - Documents to attach, such as receipts
  * @returns Promise<boolean> - True if email was sent successfully
  * @throws {Error} If email sending fails
  */
 export const sendEmail = async (
   recipient: string,
   subject: string,
-  body: string
+  body: string,
+  attachments: EmailAttachment[] = []
 ): Promise<boolean> => {
   try {
     // Validate email format
@@ -84,6 +97,13 @@ export const sendEmail = async (
       throw createHttpError(400, 'Email body cannot be empty');
     }
 
+    const files = attachments.map(attachment => ({
+      filename: attachment.filename,
+      contentType: attachment.contentType,
+      content: Buffer.from(attachment.content, 'base64'),
+      cid: attachment.contentId
+    }));
+
     const config = loadConfig();
     
     // Construct email payload
@@ -96,6 +116,7 @@ export const sendEmail = async (
       subject: subject,
       html: body, // Assuming body is HTML content
       text: body.replace(/<[^>]*>/g, ''), // Strip HTML for plain text alternative
+      attachments: files,
       headers: {
         'X-Application': 'DogWalker',
         'X-Environment': process.env.NODE_ENV || 'development',
@@ -10,7 +10,12 @@
 // express v4.18.2
 import { Request, Response } from 'express';
 import { PaymentServiceModel } from '../models/payment';
-import { processPayment, refundPayment } from '../services/stripe';
+import {
+    findBookingPayment,
+    listBookingSettlements,
+    processPayment,
+    refundPayment as refundStripePayment
+} from '../services/stripe';
 import logger from '../../../shared/utils/logger';
 import { createHttpError } from '../../../shared/utils/error';
 
@@ -23,6 +28,8 @@ export const createPayment = async (req: Request, res: Response): Promise<void>
     try {
         logger.logInfo('Received payment creation request', {
             userId: req.body.userId,
+            bookingId: req.body.bookingId,
+            kind: req.body.kind,
             amount: req.body.amount,
             currency: req.body.currency
         });
@@ -36,7 +43,9 @@ export const createPayment = async (req: Request, res: Response): Promise<void>
             'pending',
             new Date(),
             new Date(),
-            req.body.serviceSpecificProperty
+            req.body.serviceSpecificProperty,
+            req.body.bookingId,
+            req.body.kind
         );
 
         // Validate service-specific logic
@@ -74,25 +83,32 @@ export const createPayment = async (req: Request, res: Response): Promise<void>
 };
 
 /**
- * @description Processes a refund for a given payment
+ * @description Processes a refund for a given payment. The payment is identified by paymentId, or by
+ * bookingId for refunds requested by the booking-service, such as upheld disputes.
  * Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
  * Implements secure refund processing.
  */
 export const refundPayment = async (req: Request, res: Response): Promise<void> => {
     try {
-        const { paymentId, amount } = req.body;
+        const { bookingId, amount, reference } = req.body;
+        let { paymentId } = req.body;
 
         logger.logInfo('Received refund request', {
             paymentId,
+            bookingId,
             amount
         });
 
-        if (!paymentId || !amount) {
-            throw createHttpError(400, 'Payment ID and amount are required for refund');
+        if ((!paymentId && !bookingId) || !amount) {
+            throw createHttpError(400, 'Payment ID or booking ID and amount are required for refund');
+        }
+
+        if (!paymentId) {
+            paymentId = await findBookingPayment(bookingId);
         }
 
         // Process refund through Stripe
-        const refundResult = await refundPayment(paymentId, amount);
+        const refundResult = await refundStripePayment(paymentId, amount, reference);
 
         logger.logInfo('Refund processed successfully', {
             paymentId,
@@ -121,6 +137,33 @@ export const refundPayment = async (req: Request, res: Response): Promise<void>
     }
 };
 
+/**
+ * @description Lists the captured and refunded amounts of booking payments created in a time range,
+ * for the booking-service's nightly reconciliation
+ * Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+ */
+export const listSettlements = async (req: Request, res: Response): Promise<void> => {
+    const from = new Date(String(req.query.from || ''));
+    const to = new Date(String(req.query.to || ''));
+
+    if (Number.isNaN(from.getTime()) || Number.isNaN(to.getTime()) || to <= from) {
+        throw createHttpError(400, 'from and to must be ISO 8601 timestamps with from before to');
+    }
+
+    const settlements = await listBookingSettlements(from, to);
+
+    logger.logInfo('Listed booking settlements', {
+        from: from.toISOString(),
+        to: to.toISOString(),
+        count: settlements.length
+    });
+
+    res.status(200).json({
+        success: true,
+        data: settlements
+    });
+};
+
 /**
  * @description Handles incoming Stripe webhook events
  * Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
@@ -7,7 +7,7 @@
  */
 
 // class-validator v0.13.2
-import { IsString, validateSync, ValidationError } from 'class-validator';
+import { IsIn, IsOptional, IsString, validateSync, ValidationError } from 'class-validator';
 import { Payment, validate } from '../../../shared/models/payment';
 import { validatePayment } from '../../../shared/utils/validation';
 import { createHttpError } from '../../../shared/utils/error';
@@ -21,6 +21,16 @@ export class PaymentServiceModel extends Payment {
     @IsString({ message: 'Service specific property must be a string' })
     serviceSpecificProperty: string;
 
+    /** Booking the payment is for; recorded with the processor so payments can be reconciled */
+    @IsOptional()
+    @IsString({ message: 'Booking ID must be a string' })
+    bookingId?: string;
+
+    /** What the payment is for: the booking itself or a tip for the walker */
+    @IsOptional()
+    @IsIn(['booking', 'tip'], { message: "Payment kind must be 'booking' or 'tip'" })
+    kind?: string;
+
     /**
      * @description Initializes a new PaymentServiceModel instance with default values.
      * Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
@@ -33,6 +43,8 @@ export class PaymentServiceModel extends Payment {
      * @param createdAt - Timestamp when the payment was created
      * @param updatedAt - Timestamp when the payment was last updated
      * @param serviceSpecificProperty - Additional property specific to the payment service
+     * @param bookingId - Booking the payment is for, if any
+     * @param kind - What the payment is for, 'booking' or 'tip'; booking payments when omitted
      */
     constructor(
         id: string,
@@ -42,11 +54,15 @@ export class PaymentServiceModel extends Payment {
         status: string,
         createdAt: Date = new Date(),
         updatedAt: Date = new Date(),
-        serviceSpecificProperty: string
+        serviceSpecificProperty: string,
+        bookingId?: string,
+        kind?: string
     ) {
         // Call the parent Payment class constructor
         super(id, userId, amount, currency, status, createdAt, updatedAt);
         this.serviceSpecificProperty = serviceSpecificProperty;
+        this.bookingId = bookingId;
+        this.kind = kind;
     }
 
     /**
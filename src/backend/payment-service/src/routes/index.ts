@@ -8,7 +8,7 @@
 
 // express v4.18.2
 import express, { Router } from 'express';
-import { createPayment, refundPayment, webhookHandler } from '../controllers/payment';
+import { createPayment, listSettlements, refundPayment, webhookHandler } from '../controllers/payment';
 
 /**
  * @description Sets up the HTTP routes for the Payment Service, mapping endpoints to their respective controller functions.
@@ -31,7 +31,7 @@ export const setupRoutes = (app: express.Application): void => {
 
     /**
      * POST /payments/refund
-     * Processes a refund for a payment
+     * Processes a refund for a payment, identified by paymentId or by the bookingId it was taken for
      * Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
      * Implements secure refund processing
      */
@@ -45,6 +45,14 @@ export const setupRoutes = (app: express.Application): void => {
      */
     router.post('/payments/webhook', webhookHandler);
 
+    /**
+     * GET /payments/settlements?from=&to=
+     * Lists captured and refunded amounts of booking payments created in a time range
+     * Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+     * Used by the booking-service's nightly reconciliation
+     */
+    router.get('/payments/settlements', listSettlements);
+
     // Mount the router on the app
     app.use(router);
 };
\ No newline at end of file
@@ -62,7 +62,9 @@ export const processPayment = async (paymentData: PaymentServiceModel): Promise<
       currency: paymentData.currency.toLowerCase(),
       metadata: {
         paymentId: paymentData.id,
-        userId: paymentData.userId
+        userId: paymentData.userId,
+        ...(paymentData.bookingId ? { bookingId: paymentData.bookingId } : {}),
+        ...(paymentData.kind ? { kind: paymentData.kind } : {})
       },
       description: `Payment ${paymentData.id} for user ${paymentData.userId}`,
       statement_descriptor: 'PAWSOME PAYMENT', // Max 22 characters
@@ -101,10 +103,16 @@ export const processPayment = async (paymentData: PaymentServiceModel): Promise<
  * 
  * @param paymentId - ID of the payment to refund
  * @param amount - Amount to refund in smallest currency unit
+ * @param reference - Caller's ID for the refund, such as a dispute ID; retries with the same
+ * reference return the original refund instead of refunding twice
  * @returns Promise resolving to Stripe refund response
  * @throws HttpError if refund processing fails
  */
-export const refundPayment = async (paymentId: string, amount: number): Promise<Stripe.Response<Stripe.Refund>> => {
+export const refundPayment = async (
+  paymentId: string,
+  amount: number,
+  reference?: string
+): Promise<Stripe.Response<Stripe.Refund>> => {
   try {
     logger.logInfo('Initiating payment refund', {
       paymentId,
@@ -112,11 +120,15 @@ export const refundPayment = async (paymentId: string, amount: number): Promise<
     });
 
     // Create a refund with Stripe
-    const refund = await stripeClient.refunds.create({
-      payment_intent: paymentId,
-      amount: amount,
-      reason: 'requested_by_customer'
-    });
+    const refund = await stripeClient.refunds.create(
+      {
+        payment_intent: paymentId,
+        amount: amount,
+        reason: 'requested_by_customer',
+        ...(reference ? { metadata: { reference } } : {})
+      },
+      reference ? { idempotencyKey: `refund-${reference}` } : undefined
+    );
 
     logger.logInfo('Refund processed successfully', {
       paymentId,
@@ -137,4 +149,96 @@ export const refundPayment = async (paymentId: string, amount: number): Promise<
     }
     throw error;
   }
-};
\ No newline at end of file
+};
+
+/**
+ * Finds the payment taken for a booking itself, ignoring tips, so it can be refunded by booking.
+ * Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+ *
+ * @param bookingId - Booking whose payment to find
+ * @returns Promise resolving to the payment intent ID
+ * @throws HttpError 404 if the booking has no successful payment
+ */
+export const findBookingPayment = async (bookingId: string): Promise<string> => {
+  // Stripe search query strings are single quoted
+  const quoted = bookingId.replace(/\\/g, '\\\\').replace(/'/g, "\\'");
+  const result = await stripeClient.paymentIntents.search({
+    query: `metadata['bookingId']:'${quoted}' AND status:'succeeded'`
+  });
+
+  const payment = result.data.find((paymentIntent: Stripe.PaymentIntent) => paymentIntent.metadata?.kind !== 'tip');
+  if (!payment) {
+    throw createHttpError(404, `No payment found for booking ${bookingId}`);
+  }
+  return payment.id;
+};
+
+/**
+ * Money captured and refunded on one booking payment, in the smallest currency unit
+ */
+export interface BookingSettlement {
+  paymentIntentId: string;
+  bookingId: string;
+  kind: string;
+  currency: string;
+  captured: number;
+  refunded: number;
+  created: string;
+}
+
+/**
+ * Lists the booking payments created in [from, to) with their current captured and refunded amounts.
+ * Addresses requirement: Technical Specification/1.3 Scope/Core Features/Payments
+ * Supports reconciling booking amounts against payments. Payments without a bookingId are skipped.
+ *
+ * @param from - Start of the creation range, inclusive
+ * @param to - End of the creation range, exclusive
+ * @returns Promise resolving to the settlements, oldest first
+ * @throws HttpError if Stripe cannot be read
+ */
+export const listBookingSettlements = async (from: Date, to: Date): Promise<BookingSettlement[]> => {
+  const settlements: BookingSettlement[] = [];
+
+  try {
+    // The latest charge carries the refunded total, so no per-payment refund lookups are needed
+    await stripeClient.paymentIntents
+      .list({
+        created: {
+          gte: Math.floor(from.getTime() / 1000),
+          lt: Math.floor(to.getTime() / 1000)
+        },
+        limit: 100,
+        expand: ['data.latest_charge']
+      })
+      .autoPagingEach((paymentIntent: Stripe.PaymentIntent) => {
+        const bookingId = paymentIntent.metadata?.bookingId;
+        if (!bookingId) {
+          return;
+        }
+
+        const charge = paymentIntent.latest_charge as Stripe.Charge | null;
+        settlements.push({
+          paymentIntentId: paymentIntent.id,
+          bookingId,
+          kind: paymentIntent.metadata?.kind || 'booking',
+          currency: paymentIntent.currency,
+          captured: paymentIntent.amount_received,
+          refunded: charge ? charge.amount_refunded : 0,
+          created: new Date(paymentIntent.created * 1000).toISOString()
+        });
+      });
+  } catch (error) {
+    logger.logError('Failed to list booking settlements', {
+      from: from.toISOString(),
+      to: to.toISOString(),
+      error: error instanceof Error ? error.message : 'Unknown error'
+    });
+
+    if (error instanceof Stripe.errors.StripeError) {
+      throw createHttpError(502, `Settlements unavailable: ${error.message}`);
+    }
+    throw error;
+  }
+
+  return settlements.reverse();
+};
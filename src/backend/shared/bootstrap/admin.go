@@ -0,0 +1,75 @@
+// Package bootstrap provides the process lifecycle shared by the Go backend services
+// Version: 1.0.0
+
+package bootstrap
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Human Tasks:
+// 1. Never expose the admin port outside the cluster; reach it with kubectl port-forward
+// 2. Keep the admin port out of the Service and Ingress definitions
+
+// gcRecentPauses is how many of the latest GC pauses the gc variable lists
+const gcRecentPauses = 16
+
+// gcStats is the gc expvar: a summary of garbage collection since the process started
+type gcStats struct {
+	NumGC        int64     `json:"num_gc"`
+	PauseTotalMs float64   `json:"pause_total_ms"`
+	LastGC       time.Time `json:"last_gc"`
+	RecentMs     []float64 `json:"recent_pauses_ms"`
+}
+
+// Runtime variables published next to expvar's own cmdline and memstats, which already
+// cover the heap
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() interface{} {
+		return runtime.NumGoroutine()
+	}))
+	expvar.Publish("gc", expvar.Func(func() interface{} {
+		var stats debug.GCStats
+		stats.Pause = make([]time.Duration, 0, gcRecentPauses)
+		debug.ReadGCStats(&stats)
+		summary := gcStats{
+			NumGC:        stats.NumGC,
+			PauseTotalMs: milliseconds(stats.PauseTotal),
+			LastGC:       stats.LastGC,
+			RecentMs:     make([]float64, 0, len(stats.Pause)),
+		}
+		for _, pause := range stats.Pause {
+			summary.RecentMs = append(summary.RecentMs, milliseconds(pause))
+		}
+		return summary
+	}))
+}
+
+// Publish exposes the value fn returns as the expvar name on the admin port, such as the
+// internals of a component worth watching while profiling. fn is called on every request
+// to /debug/vars and must be safe for concurrent use. Names must be unique in the process.
+func Publish(name string, fn func() interface{}) {
+	expvar.Publish(name, expvar.Func(fn))
+}
+
+// newAdminMux serves the pprof profiles under /debug/pprof/ and expvar under /debug/vars
+func newAdminMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,61 @@
+// Package bootstrap provides the process lifecycle shared by the Go backend services
+// Version: 1.0.0
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// checkTimeout bounds each readiness check so a hung dependency cannot hang the probe
+const checkTimeout = 2 * time.Second
+
+// healthResponse is the body served by /health and /ready
+type healthResponse struct {
+	Status  string            `json:"status"`
+	Service string            `json:"service"`
+	Checks  map[string]string `json:"checks,omitempty"`
+}
+
+// handleHealth is the liveness probe: the process is up and serving HTTP
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "ok", Service: s.name})
+}
+
+// handleReady is the readiness probe: the server is not shutting down and every
+// registered dependency check passes
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() {
+		writeHealth(w, http.StatusServiceUnavailable, healthResponse{Status: "unavailable", Service: s.name})
+		return
+	}
+
+	response := healthResponse{Status: "ok", Service: s.name, Checks: make(map[string]string, len(s.checks))}
+	status := http.StatusOK
+	for _, check := range s.checks {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		err := check.fn(ctx)
+		cancel()
+
+		if err != nil {
+			response.Checks[check.name] = err.Error()
+			response.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		response.Checks[check.name] = "ok"
+	}
+
+	writeHealth(w, status, response)
+}
+
+// writeHealth writes a probe response
+func writeHealth(w http.ResponseWriter, status int, response healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,314 @@
+// Package bootstrap provides the process lifecycle shared by the Go backend services:
+// an HTTP server with a middleware chain, health and readiness endpoints, background
+// workers, start/stop hooks and graceful shutdown on SIGINT/SIGTERM.
+// Version: 1.0.0
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"src/backend/shared/httpmetrics"
+)
+
+// Human Tasks:
+// 1. Point liveness probes at /health and readiness probes at /ready
+// 2. Keep terminationGracePeriodSeconds above the shutdown timeout
+// 3. Review the default server timeouts against production traffic
+
+// Default server settings
+const (
+	DefaultReadTimeout     = 30 * time.Second
+	DefaultWriteTimeout    = 30 * time.Second
+	DefaultIdleTimeout     = 120 * time.Second
+	DefaultShutdownTimeout = 20 * time.Second
+)
+
+// Hook is a lifecycle callback run when the server starts or stops
+type Hook func(ctx context.Context) error
+
+// Check reports whether a dependency is usable; a non-nil error marks the service not ready
+type Check func(ctx context.Context) error
+
+// namedHook pairs a hook with the name used in logs
+type namedHook struct {
+	name string
+	fn   Hook
+}
+
+// namedCheck pairs a readiness check with the name reported by /ready
+type namedCheck struct {
+	name string
+	fn   Check
+}
+
+// worker is a background function bound to the server's lifetime
+type worker struct {
+	name string
+	fn   func(ctx context.Context)
+}
+
+// Server is an HTTP service with a managed lifecycle. Configure it with the builder
+// methods, then call Run, which blocks until the process is asked to stop.
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+type Server struct {
+	name      string
+	addr      string
+	adminAddr string
+	mux       *http.ServeMux
+	adminMux  *http.ServeMux
+
+	middleware []Middleware
+	startHooks []namedHook
+	stopHooks  []namedHook
+	checks     []namedCheck
+	workers    []worker
+
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	shutdownTimeout time.Duration
+
+	ready atomic.Bool
+}
+
+// New creates a server named name listening on port, serving /health and /ready
+func New(name string, port int) *Server {
+	s := &Server{
+		name:            name,
+		addr:            fmt.Sprintf(":%d", port),
+		mux:             http.NewServeMux(),
+		adminMux:        newAdminMux(),
+		readTimeout:     DefaultReadTimeout,
+		writeTimeout:    DefaultWriteTimeout,
+		idleTimeout:     DefaultIdleTimeout,
+		shutdownTimeout: DefaultShutdownTimeout,
+	}
+	s.mux.HandleFunc("/health", s.handleHealth)
+	s.mux.HandleFunc("/ready", s.handleReady)
+	return s
+}
+
+// Use appends middleware to the chain wrapping every route; the first added runs outermost
+func (s *Server) Use(middleware ...Middleware) *Server {
+	s.middleware = append(s.middleware, middleware...)
+	return s
+}
+
+// Handle registers a handler for pattern. Its requests are recorded in the shared HTTP
+// metrics under the server's name and pattern.
+func (s *Server) Handle(pattern string, handler http.Handler) *Server {
+	s.mux.Handle(pattern, httpmetrics.Instrument(s.name, pattern, handler))
+	return s
+}
+
+// HandleFunc registers a handler function for pattern
+func (s *Server) HandleFunc(pattern string, handler http.HandlerFunc) *Server {
+	return s.Handle(pattern, handler)
+}
+
+// WithTimeouts overrides the server's read, write and idle timeouts; zero leaves a value unchanged
+func (s *Server) WithTimeouts(read, write, idle time.Duration) *Server {
+	if read > 0 {
+		s.readTimeout = read
+	}
+	if write > 0 {
+		s.writeTimeout = write
+	}
+	if idle > 0 {
+		s.idleTimeout = idle
+	}
+	return s
+}
+
+// WithAdminPort serves pprof profiles, expvar variables and the handlers registered with
+// HandleAdmin on port, apart from the service's routes so they are never reachable through
+// its ingress; zero or less serves none
+func (s *Server) WithAdminPort(port int) *Server {
+	s.adminAddr = ""
+	if port > 0 {
+		s.adminAddr = fmt.Sprintf(":%d", port)
+	}
+	return s
+}
+
+// HandleAdmin registers a handler for pattern on the admin port, for operational APIs that
+// must stay inside the cluster. They are unreachable unless WithAdminPort sets a port.
+func (s *Server) HandleAdmin(pattern string, handler http.Handler) *Server {
+	s.adminMux.Handle(pattern, handler)
+	return s
+}
+
+// WithShutdownTimeout bounds how long Run waits for in-flight requests, workers and stop hooks
+func (s *Server) WithShutdownTimeout(timeout time.Duration) *Server {
+	s.shutdownTimeout = timeout
+	return s
+}
+
+// OnStart registers a hook run before the server starts listening. Hooks run in
+// registration order; if one fails, Run runs every stop hook and returns the error,
+// so stop hooks must tolerate components that never started.
+func (s *Server) OnStart(name string, hook Hook) *Server {
+	s.startHooks = append(s.startHooks, namedHook{name: name, fn: hook})
+	return s
+}
+
+// OnStop registers a hook run after the server stops accepting requests and its workers
+// have exited. Hooks run in reverse registration order, so resources are released in the
+// opposite order to the one they were acquired in.
+func (s *Server) OnStop(name string, hook Hook) *Server {
+	s.stopHooks = append(s.stopHooks, namedHook{name: name, fn: hook})
+	return s
+}
+
+// ReadinessCheck registers a check run by /ready
+func (s *Server) ReadinessCheck(name string, check Check) *Server {
+	s.checks = append(s.checks, namedCheck{name: name, fn: check})
+	return s
+}
+
+// Go runs fn in the background for the lifetime of the server. Its context is cancelled
+// when shutdown begins and Run waits for it to return before running stop hooks.
+func (s *Server) Go(name string, fn func(ctx context.Context)) *Server {
+	s.workers = append(s.workers, worker{name: name, fn: fn})
+	return s
+}
+
+// Run starts the server and blocks until SIGINT or SIGTERM, or until the listener fails,
+// then shuts down gracefully. Stop hooks run even when startup fails part way.
+func (s *Server) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return s.run(ctx)
+}
+
+// run is Run with an explicit stop context
+func (s *Server) run(ctx context.Context) error {
+	log.Printf("Starting %s...", s.name)
+
+	if err := s.start(ctx); err != nil {
+		s.stop()
+		return err
+	}
+
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		s.stop()
+		return fmt.Errorf("failed to listen on %s: %w", s.addr, err)
+	}
+
+	server := &http.Server{
+		Handler:      Chain(s.mux, s.middleware...),
+		ReadTimeout:  s.readTimeout,
+		WriteTimeout: s.writeTimeout,
+		IdleTimeout:  s.idleTimeout,
+	}
+
+	// Profiles run for as long as they are asked to, so the admin server sets no write timeout
+	var admin *http.Server
+	if s.adminAddr != "" {
+		admin = &http.Server{
+			Addr:        s.adminAddr,
+			Handler:     s.adminMux,
+			ReadTimeout: s.readTimeout,
+			IdleTimeout: s.idleTimeout,
+		}
+		go func() {
+			if err := admin.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("%s: admin server failed: %v", s.name, err)
+			}
+		}()
+		log.Printf("%s admin endpoints listening on %s", s.name, s.adminAddr)
+	}
+
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	for _, w := range s.workers {
+		workers.Add(1)
+		go func(w worker) {
+			defer workers.Done()
+			w.fn(workerCtx)
+			log.Printf("%s: worker %s stopped", s.name, w.name)
+		}(w)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	s.ready.Store(true)
+	log.Printf("%s listening on %s", s.name, s.addr)
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		log.Printf("Shutting down %s...", s.name)
+	case err := <-serveErr:
+		runErr = fmt.Errorf("server failed: %w", err)
+		log.Printf("%s: %v", s.name, runErr)
+	}
+
+	// Fail readiness first so load balancers stop routing here while requests drain
+	s.ready.Store(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("%s: error draining requests: %v", s.name, err)
+	}
+	if admin != nil {
+		admin.Close()
+	}
+
+	cancelWorkers()
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		log.Printf("%s: timed out waiting for workers to stop", s.name)
+	}
+
+	s.stop()
+	log.Printf("%s shutdown complete", s.name)
+	return runErr
+}
+
+// start runs the start hooks in registration order, stopping at the first failure
+func (s *Server) start(ctx context.Context) error {
+	for _, hook := range s.startHooks {
+		if err := hook.fn(ctx); err != nil {
+			return fmt.Errorf("%s: failed to start %s: %w", s.name, hook.name, err)
+		}
+	}
+	return nil
+}
+
+// stop runs the stop hooks in reverse order, logging failures and carrying on with the rest
+func (s *Server) stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	for i := len(s.stopHooks) - 1; i >= 0; i-- {
+		hook := s.stopHooks[i]
+		if err := hook.fn(ctx); err != nil {
+			log.Printf("%s: error stopping %s: %v", s.name, hook.name, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package clients provides typed clients for calls between the Go backend services
+// Version: 1.0.0
+
+package clients
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"src/backend/shared/regions"
+)
+
+// envelope is the booking-service's response wrapper
+type envelope struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data"`
+}
+
+// Booking is a booking as the booking-service publishes it. Only the fields other services
+// read are kept.
+type Booking struct {
+	ID              string    `json:"id"`
+	OwnerID         string    `json:"owner_id"`
+	WalkerID        string    `json:"walker_id"`
+	DogID           string    `json:"dog_id"`
+	ScheduledAt     time.Time `json:"scheduled_at"`
+	Status          string    `json:"status"`
+	Amount          float64   `json:"amount"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Region          string    `json:"region,omitempty"`
+	Latitude        *float64  `json:"latitude,omitempty"`
+	Longitude       *float64  `json:"longitude,omitempty"`
+}
+
+// BookingClient calls the booking-service
+type BookingClient struct {
+	client *client
+}
+
+// NewBookingClient creates a client for the booking-service at baseURL. Booking reads need an
+// API key with the bookings:read scope.
+func NewBookingClient(baseURL string, opts Options) *BookingClient {
+	return &BookingClient{client: newClient("booking-service", baseURL, opts)}
+}
+
+// GetBooking fetches a booking, returning ErrNotFound when there is no such booking
+func (c *BookingClient) GetBooking(ctx context.Context, id string) (*Booking, error) {
+	var booking Booking
+	if err := c.client.get(ctx, "/api/v1/bookings/"+url.PathEscape(id), &envelope{Data: &booking}); err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// ListRegions fetches every service region and its boundary
+func (c *BookingClient) ListRegions(ctx context.Context) ([]regions.Region, error) {
+	var list []regions.Region
+	if err := c.client.get(ctx, "/api/v1/regions", &envelope{Data: &list}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
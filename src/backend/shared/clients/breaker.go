@@ -0,0 +1,64 @@
+// Package clients provides typed clients for calls between the Go backend services
+// Version: 1.0.0
+
+package clients
+
+import (
+	"sync"
+	"time"
+)
+
+// breaker opens after threshold consecutive failures, refusing calls until cooldown has passed,
+// then lets a single call through to learn whether the service has recovered
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	probing   bool
+	now       func() time.Time
+}
+
+// newBreaker creates a closed breaker; a threshold below 1 never opens
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow reports whether a call may be made now
+func (b *breaker) allow() bool {
+	if b.threshold < 1 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record notes the outcome of a call allow let through
+func (b *breaker) record(success bool) {
+	if b.threshold < 1 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		// A failed trial call restarts the cooldown
+		b.openedAt = b.now()
+	}
+}
@@ -0,0 +1,252 @@
+// Package clients provides typed clients for calls between the Go backend services, so
+// timeouts, retries, circuit breaking and trace propagation are handled once rather than by
+// every caller
+// Version: 1.0.0
+
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Human Tasks:
+// 1. Add Tracing to every service's middleware chain, so calls made while serving a request
+//    carry its trace on to the next service
+// 2. Review the default timeouts and retry counts against each caller's latency budget
+
+// Default client settings, used where Options leaves a value zero
+const (
+	// DefaultTimeout bounds each attempt at a call
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultRetries is how many times an idempotent call is retried after a transient failure
+	DefaultRetries = 2
+
+	// DefaultBackoff is the wait before the first retry; it doubles with each one after
+	DefaultBackoff = 100 * time.Millisecond
+
+	// DefaultBreakerThreshold is how many consecutive failures open the circuit
+	DefaultBreakerThreshold = 5
+
+	// DefaultBreakerCooldown is how long an open circuit refuses calls before letting one through
+	DefaultBreakerCooldown = 30 * time.Second
+)
+
+// APIKeyHeader carries the API key services authenticate to each other with
+const APIKeyHeader = "X-API-Key"
+
+var (
+	// ErrNotFound is returned when the called service has no such resource
+	ErrNotFound = errors.New("not found")
+
+	// ErrCircuitOpen is returned without calling a service that has been failing
+	ErrCircuitOpen = errors.New("circuit open")
+)
+
+// StatusError is a response with a status the client does not expect
+type StatusError struct {
+	Service    string
+	StatusCode int
+	Message    string
+}
+
+// Error implements error
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s returned status %d", e.Service, e.StatusCode)
+	}
+	return fmt.Sprintf("%s returned status %d: %s", e.Service, e.StatusCode, e.Message)
+}
+
+// Options configure a client; zero values take the defaults
+type Options struct {
+	// Timeout bounds each attempt at a call; the caller's context bounds the call as a whole
+	Timeout time.Duration
+
+	// Retries is how many times an idempotent call is retried after a network error or a 429,
+	// 502, 503 or 504 response; negative disables retries
+	Retries int
+
+	// Backoff is the wait before the first retry, doubling with each one after and jittered
+	Backoff time.Duration
+
+	// BreakerThreshold is how many consecutive failed attempts open the circuit; negative
+	// disables the breaker
+	BreakerThreshold int
+
+	// BreakerCooldown is how long an open circuit refuses calls before a single trial call
+	BreakerCooldown time.Duration
+
+	// APIKey is sent in APIKeyHeader with every call when set
+	APIKey string
+
+	// HTTPClient sends the requests; a plain http.Client when nil
+	HTTPClient *http.Client
+}
+
+// withDefaults returns the options with zero values replaced by the defaults
+func (o Options) withDefaults() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Retries == 0 {
+		o.Retries = DefaultRetries
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = DefaultBackoff
+	}
+	if o.BreakerThreshold == 0 {
+		o.BreakerThreshold = DefaultBreakerThreshold
+	}
+	if o.BreakerCooldown <= 0 {
+		o.BreakerCooldown = DefaultBreakerCooldown
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = &http.Client{}
+	}
+	return o
+}
+
+// client calls one service, shared by the typed clients
+type client struct {
+	service string
+	baseURL string
+	opts    Options
+	breaker *breaker
+}
+
+// newClient creates a client for the service named service at baseURL
+func newClient(service, baseURL string, opts Options) *client {
+	opts = opts.withDefaults()
+	return &client{
+		service: service,
+		baseURL: baseURL,
+		opts:    opts,
+		breaker: newBreaker(opts.BreakerThreshold, opts.BreakerCooldown),
+	}
+}
+
+// get calls path with GET, which is always safe to retry, decoding the JSON response into out
+func (c *client) get(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, nil, out, true)
+}
+
+// do sends body as JSON to path and decodes the JSON response into out. Only idempotent calls
+// are retried, as a call that timed out may still have taken effect.
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}, idempotent bool) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", c.service, err)
+		}
+	}
+
+	attempts := 1
+	if idempotent && c.opts.Retries > 0 {
+		attempts += c.opts.Retries
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if waitErr := sleep(ctx, c.backoff(attempt)); waitErr != nil {
+				return err
+			}
+		}
+		if !c.breaker.allow() {
+			return fmt.Errorf("%s: %w", c.service, ErrCircuitOpen)
+		}
+
+		var retry bool
+		retry, err = c.attempt(ctx, method, path, payload, out)
+		// Only failures that suggest the service is unwell count against the circuit
+		c.breaker.record(!retry)
+		if !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// attempt makes one call, reporting whether a failure was transient and worth retrying
+func (c *client) attempt(ctx context.Context, method, path string, payload []byte, out interface{}) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return false, fmt.Errorf("failed to create %s request: %w", c.service, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.opts.APIKey != "" {
+		req.Header.Set(APIKeyHeader, c.opts.APIKey)
+	}
+	injectTrace(req.Context(), req.Header)
+
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to call %s: %w", c.service, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, fmt.Errorf("%s: %w", c.service, ErrNotFound)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		statusErr := &StatusError{Service: c.service, StatusCode: resp.StatusCode, Message: string(bytes.TrimSpace(message))}
+		return retryableStatus(resp.StatusCode), statusErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return false, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode %s response: %w", c.service, err)
+	}
+	return false, nil
+}
+
+// retryableStatus reports whether a response status means the call may succeed if repeated
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the jittered wait before the given retry
+func (c *client) backoff(attempt int) time.Duration {
+	wait := c.opts.Backoff << (attempt - 1)
+	// Up to half the wait is jitter, so callers failing together do not retry together
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// sleep waits for d, returning early with the context's error if it is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+// Package clients provides typed clients for calls between the Go backend services
+// Version: 1.0.0
+
+package clients
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// W3C Trace Context headers carried between services
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// traceKey is the context key for the trace a request belongs to
+type traceKey struct{}
+
+// trace is the W3C trace context of the request being served
+type trace struct {
+	traceID string
+	spanID  string
+	flags   string
+	state   string
+}
+
+// Tracing continues the trace named in a request's traceparent header, or starts a new one, so
+// the clients' calls made while serving the request carry it on to the next service
+func Tracing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t, ok := parseTraceparent(r.Header.Get(TraceparentHeader))
+		if ok {
+			t.state = r.Header.Get(TracestateHeader)
+		} else {
+			t = trace{traceID: randomHex(16), flags: "01"}
+		}
+		// Each service is its own span within the trace
+		t.spanID = randomHex(8)
+
+		w.Header().Set(TraceparentHeader, t.header())
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), traceKey{}, t)))
+	})
+}
+
+// TraceID returns the ID of the trace ctx belongs to, or "" outside a traced request
+func TraceID(ctx context.Context) string {
+	t, _ := ctx.Value(traceKey{}).(trace)
+	return t.traceID
+}
+
+// injectTrace names the trace ctx belongs to in an outgoing call's headers; calls made outside
+// a traced request start no trace of their own
+func injectTrace(ctx context.Context, header http.Header) {
+	t, ok := ctx.Value(traceKey{}).(trace)
+	if !ok {
+		return
+	}
+	header.Set(TraceparentHeader, t.header())
+	if t.state != "" {
+		header.Set(TracestateHeader, t.state)
+	}
+}
+
+// header formats the trace as a traceparent header
+func (t trace) header() string {
+	return "00-" + t.traceID + "-" + t.spanID + "-" + t.flags
+}
+
+// parseTraceparent reads a version 00 traceparent header, rejecting malformed and all-zero IDs
+func parseTraceparent(value string) (trace, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return trace{}, false
+	}
+	if !validHex(parts[1], 32) || !validHex(parts[2], 16) || !validHex(parts[3], 2) {
+		return trace{}, false
+	}
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return trace{}, false
+	}
+	return trace{traceID: parts[1], spanID: parts[2], flags: parts[3]}, true
+}
+
+// validHex reports whether s is n lowercase hex digits
+func validHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// randomHex returns n random bytes as hex
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// A trace ID only needs to be unlikely to collide, not secret
+		b[0] = 1
+	}
+	return hex.EncodeToString(b)
+}
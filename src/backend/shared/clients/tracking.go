@@ -0,0 +1,65 @@
+// Package clients provides typed clients for calls between the Go backend services
+// Version: 1.0.0
+
+package clients
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+// WalkerPosition is where a walker was last seen during a walk. Positions are kept to a cell
+// about 150m across, so Latitude and Longitude are its centre.
+type WalkerPosition struct {
+	WalkerID  string    `json:"walker_id"`
+	SessionID string    `json:"session_id"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WalkEvidence is what was tracked of a booking's walks, summed over every walk session started
+// for it
+type WalkEvidence struct {
+	BookingID       string  `json:"booking_id"`
+	Sessions        int     `json:"sessions"`
+	Active          bool    `json:"active"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	DistanceMeters  float64 `json:"distance_meters"`
+	Photos          int     `json:"photos"`
+}
+
+// Duration returns the time spent walking
+func (e *WalkEvidence) Duration() time.Duration {
+	return time.Duration(e.DurationSeconds * float64(time.Second))
+}
+
+// TrackingClient calls the tracking-service
+type TrackingClient struct {
+	client *client
+}
+
+// NewTrackingClient creates a client for the tracking-service at baseURL
+func NewTrackingClient(baseURL string, opts Options) *TrackingClient {
+	return &TrackingClient{client: newClient("tracking-service", baseURL, opts)}
+}
+
+// WalkerPosition fetches where a walker was last seen, returning ErrNotFound when they have not
+// been seen during any walk
+func (c *TrackingClient) WalkerPosition(ctx context.Context, walkerID string) (*WalkerPosition, error) {
+	var position WalkerPosition
+	if err := c.client.get(ctx, "/api/v1/walkers/"+url.PathEscape(walkerID)+"/position", &position); err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+// WalkEvidence fetches what was tracked of a booking's walks
+func (c *TrackingClient) WalkEvidence(ctx context.Context, bookingID string) (*WalkEvidence, error) {
+	var evidence WalkEvidence
+	if err := c.client.get(ctx, "/api/v1/bookings/"+url.PathEscape(bookingID)+"/walk-evidence", &evidence); err != nil {
+		return nil, err
+	}
+	return &evidence, nil
+}
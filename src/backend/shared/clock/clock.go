@@ -0,0 +1,192 @@
+// Package clock abstracts the current time and timers, so logic that depends on the time of
+// day, such as whether a walk is in the future or a walker has gone quiet, can be tested at
+// any instant, including across daylight saving transitions
+// Version: 1.0.0
+
+package clock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and starts timers
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// NewTimer starts a Timer that fires once after d
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the part of time.Timer the services use, with its channel behind a method
+type Timer interface {
+	// C returns the channel the time is delivered on when the timer fires
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing, reporting whether it was still pending
+	Stop() bool
+
+	// Reset makes the timer fire after d, reporting whether it was still pending
+	Reset(d time.Duration) bool
+}
+
+// System is the wall clock of the time package
+var System Clock = systemClock{}
+
+// contextKey is the context key the Clock of a request or job is stored under
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying c, for the code handling the request or job to
+// tell the time by
+func WithContext(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the Clock carried by ctx, or System when it carries none
+func FromContext(ctx context.Context) Clock {
+	if c, ok := ctx.Value(contextKey{}).(Clock); ok {
+		return c
+	}
+	return System
+}
+
+// systemClock implements Clock with the time package
+type systemClock struct{}
+
+// Now returns time.Now()
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer returns a time.Timer
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+// systemTimer adapts a time.Timer to Timer
+type systemTimer struct {
+	*time.Timer
+}
+
+// C returns the timer's channel
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// Fake is a Clock that only moves when told to. Timers fire, in deadline order, once the clock
+// is set or advanced past their deadlines. It is safe for concurrent use.
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake creates a Fake stopped at now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the time the clock is stopped at
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the clock forward by d, firing the timers that come due
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the clock to now, firing the timers that come due. Moving it backwards fires
+// nothing, as when the wall clock is corrected.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = now
+	sort.SliceStable(f.timers, func(i, j int) bool {
+		return f.timers[i].deadline.Before(f.timers[j].deadline)
+	})
+	pending := f.timers[:0]
+	for _, timer := range f.timers {
+		if timer.deadline.After(now) {
+			pending = append(pending, timer)
+			continue
+		}
+		// As with time.Timer, a fire nobody has received yet is not queued twice
+		select {
+		case timer.c <- timer.deadline:
+		default:
+		}
+	}
+	f.timers = pending
+}
+
+// Timers returns how many timers are pending, so tests can wait for the code under test to
+// start its timer before advancing the clock
+func (f *Fake) Timers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.timers)
+}
+
+// NewTimer starts a timer that fires once the clock reaches d from now
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	timer := &fakeTimer{clock: f, c: make(chan time.Time, 1)}
+	timer.Reset(d)
+	return timer
+}
+
+// fakeTimer is a Timer driven by a Fake
+type fakeTimer struct {
+	clock    *Fake
+	c        chan time.Time
+	deadline time.Time
+}
+
+// C returns the timer's channel
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop removes the timer from the clock
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.remove()
+}
+
+// Reset schedules the timer d after the clock's current time, firing it at once when d is not
+// positive
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	pending := t.remove()
+	t.deadline = t.clock.now.Add(d)
+	if d <= 0 {
+		select {
+		case t.c <- t.deadline:
+		default:
+		}
+		return pending
+	}
+	t.clock.timers = append(t.clock.timers, t)
+	return pending
+}
+
+// remove takes the timer off the clock's pending list, reporting whether it was there. The
+// clock's lock must be held.
+func (t *fakeTimer) remove() bool {
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+// Package contracts holds consumer-driven contracts between the backend services. Each
+// fixture is an example message written by the consuming side; the producing side's tests
+// check that what it actually sends still satisfies every fixture it is named in.
+// Version: 1.0.0
+
+package contracts
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Human Tasks:
+// 1. Only the consuming team edits a fixture; a producer test failing means the change breaks them
+// 2. Run both services' contract tests in CI whenever either service or this package changes
+
+// BookingWalkerAccepted is the booking.walker_accepted event, whose booking tracking-service
+// needs in order to start a walk session
+const BookingWalkerAccepted = "booking.walker_accepted"
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Fixture returns the example message for a contract
+func Fixture(name string) ([]byte, error) {
+	data, err := fixtures.ReadFile(path.Join("fixtures", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown contract %q: %w", name, err)
+	}
+	return data, nil
+}
+
+// Satisfies reports whether actual provides every field of expected with the same JSON type.
+// Values are not compared, and fields the consumer does not use may be added freely; removing
+// or retyping a field a consumer relies on is a breaking change. Arrays are checked against the
+// shape of the fixture's first element.
+func Satisfies(expected, actual []byte) error {
+	var want, got interface{}
+	if err := json.Unmarshal(expected, &want); err != nil {
+		return fmt.Errorf("invalid contract fixture: %w", err)
+	}
+	if err := json.Unmarshal(actual, &got); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+
+	var problems []string
+	compare("$", want, got, &problems)
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("contract not satisfied:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// compare walks want and got in parallel, recording every mismatch under its JSON path
+func compare(at string, want, got interface{}, problems *[]string) {
+	if kind(want) != kind(got) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", at, kind(want), kind(got)))
+		return
+	}
+
+	switch want := want.(type) {
+	case map[string]interface{}:
+		got := got.(map[string]interface{})
+		for key, value := range want {
+			field, ok := got[key]
+			if !ok {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: missing", at, key))
+				continue
+			}
+			compare(at+"."+key, value, field, problems)
+		}
+	case []interface{}:
+		got := got.([]interface{})
+		if len(want) == 0 {
+			return
+		}
+		if len(got) == 0 {
+			*problems = append(*problems, fmt.Sprintf("%s: expected at least one element", at))
+			return
+		}
+		for i, element := range got {
+			compare(fmt.Sprintf("%s[%d]", at, i), want[0], element, problems)
+		}
+	}
+}
+
+// kind names the JSON type of a decoded value
+func kind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,74 @@
+// Package dbmetrics records how long each kind of database statement takes and logs the slow
+// ones, so every service's repository reports its queries the same way.
+// Version: 1.0.0
+
+package dbmetrics
+
+import (
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus" // v1.14.0
+)
+
+// DefaultSlowThreshold is used when no slow query threshold is configured
+const DefaultSlowThreshold = 500 * time.Millisecond
+
+// Outcome label values
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+)
+
+// Recorder exports statement durations to Prometheus and logs statements slower than its
+// threshold. Statements are labelled by kind, such as "SELECT bookings" or "find sessions",
+// never by their full text, so the number of series stays bounded.
+type Recorder struct {
+	duration  *prometheus.HistogramVec
+	slow      *prometheus.CounterVec
+	threshold time.Duration
+}
+
+// New creates a Recorder whose metrics carry a service label of service, matching the
+// shared HTTP metrics, so one dashboard covers every service's database. Statements taking
+// longer than threshold are logged; a threshold of zero uses DefaultSlowThreshold and a
+// negative one logs none.
+func New(service string, threshold time.Duration) *Recorder {
+	if threshold == 0 {
+		threshold = DefaultSlowThreshold
+	}
+
+	r := &Recorder{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "db_statement_duration_seconds",
+			Help:        "Duration of database statements, by kind of statement and outcome.",
+			ConstLabels: prometheus.Labels{"service": service},
+			Buckets:     []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"statement", "outcome"}),
+		slow: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "db_slow_statements_total",
+			Help:        "Number of database statements slower than the slow query threshold, by kind of statement.",
+			ConstLabels: prometheus.Labels{"service": service},
+		}, []string{"statement"}),
+		threshold: threshold,
+	}
+	prometheus.MustRegister(r.duration, r.slow)
+	return r
+}
+
+// Observe records a statement of the given kind that took elapsed and failed with err, or
+// succeeded when err is nil. When the statement was slow, detail is called for a description
+// of it to log; it must leave out the values the statement was run with.
+func (r *Recorder) Observe(statement string, elapsed time.Duration, err error, detail func() string) {
+	outcome := OutcomeOK
+	if err != nil {
+		outcome = OutcomeError
+	}
+	r.duration.WithLabelValues(statement, outcome).Observe(elapsed.Seconds())
+
+	if r.threshold < 0 || elapsed < r.threshold {
+		return
+	}
+	r.slow.WithLabelValues(statement).Inc()
+	log.Printf("Slow %s statement took %s (%s): %s", statement, elapsed.Round(time.Millisecond), outcome, detail())
+}
@@ -0,0 +1,65 @@
+// Package faults injects latency, errors and dropped WebSocket frames into a percentage of a
+// route's traffic
+// Version: 1.0.0
+
+package faults
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// APIPath is where Handler is mounted on the admin port
+const APIPath = "/faults"
+
+// Handler serves the admin API managing the rules, mounted at APIPath:
+//
+//	GET    /faults       lists the active rules
+//	POST   /faults       adds the rule in the body and returns it with its ID
+//	DELETE /faults       removes every rule
+//	DELETE /faults/{id}  removes one rule
+func (i *Injector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.Trim(strings.TrimPrefix(r.URL.Path, APIPath), "/")
+
+		switch {
+		case r.Method == http.MethodGet && id == "":
+			writeJSON(w, http.StatusOK, i.Rules())
+
+		case r.Method == http.MethodPost && id == "":
+			var rule Rule
+			if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid rule: " + err.Error()})
+				return
+			}
+			added, err := i.Add(rule)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusCreated, added)
+
+		case r.Method == http.MethodDelete && id == "":
+			i.Clear()
+			w.WriteHeader(http.StatusNoContent)
+
+		case r.Method == http.MethodDelete:
+			if !i.Remove(id) {
+				writeJSON(w, http.StatusNotFound, map[string]string{"error": "rule not found"})
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// writeJSON writes value as a JSON response with status
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
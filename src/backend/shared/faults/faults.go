@@ -0,0 +1,226 @@
+// Package faults injects latency, errors and dropped WebSocket frames into a percentage of a
+// route's traffic, so client retries and circuit breakers can be exercised against a real
+// deployment. Rules are managed at runtime through an admin API that only admins can call.
+// It can only be enabled in the development, staging and test environments.
+// Version: 1.0.0
+
+package faults
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Human Tasks:
+// 1. Enable fault injection in staging and load test environments only; services refuse to start
+//    with it unless APP_ENV is development, staging or test
+// 2. Clear the rules after each experiment: DELETE /faults on the service's admin port
+
+// Header is set on responses a fault was injected into, naming the kind of fault
+const Header = "X-Fault-Injected"
+
+// MaxLatency bounds the latency a rule may inject
+const MaxLatency = time.Minute
+
+// Environments lists the values of APP_ENV fault injection can be enabled in
+var Environments = []string{"development", "staging", "test"}
+
+// ErrEnvironment is returned when fault injection is enabled outside the Environments, including
+// when APP_ENV is unset
+var ErrEnvironment = errors.New("fault injection can only be enabled when APP_ENV is development, staging or test")
+
+// CheckEnvironment returns ErrEnvironment unless APP_ENV names one of the Environments
+func CheckEnvironment() error {
+	env := strings.TrimSpace(os.Getenv("APP_ENV"))
+	for _, allowed := range Environments {
+		if strings.EqualFold(env, allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w, not %q", ErrEnvironment, env)
+}
+
+// Rule describes a fault injected into a percentage of the requests to a route
+type Rule struct {
+	// ID identifies the rule for removal; assigned when the rule is added
+	ID string `json:"id"`
+
+	// Route is the path prefix of the requests affected, e.g. "/api/v1/bookings" or "/ws";
+	// empty affects every route
+	Route string `json:"route"`
+
+	// Percent of the route's requests, or of its WebSocket frames, affected, 0-100
+	Percent float64 `json:"percent"`
+
+	// LatencyMs delays affected requests before they are handled
+	LatencyMs int `json:"latency_ms,omitempty"`
+
+	// Status answers affected requests with this error status instead of handling them
+	Status int `json:"status,omitempty"`
+
+	// DropFrames discards affected outbound WebSocket frames of connections made to the route
+	DropFrames bool `json:"drop_frames,omitempty"`
+}
+
+// Validate checks the rule injects something into a sensible share of traffic
+func (r Rule) Validate() error {
+	if r.Percent <= 0 || r.Percent > 100 {
+		return fmt.Errorf("percent must be above 0 and at most 100")
+	}
+	if r.LatencyMs < 0 || time.Duration(r.LatencyMs)*time.Millisecond > MaxLatency {
+		return fmt.Errorf("latency_ms must be between 0 and %d", MaxLatency.Milliseconds())
+	}
+	if r.Status != 0 && (r.Status < 400 || r.Status > 599) {
+		return fmt.Errorf("status must be an error status between 400 and 599")
+	}
+	if r.LatencyMs == 0 && r.Status == 0 && !r.DropFrames {
+		return fmt.Errorf("one of latency_ms, status or drop_frames is required")
+	}
+	return nil
+}
+
+// matches reports whether the rule applies to path
+func (r Rule) matches(path string) bool {
+	return strings.HasPrefix(path, r.Route)
+}
+
+// Injector holds the active rules and applies them. A nil Injector injects nothing, so
+// callers need not check whether fault injection is enabled.
+type Injector struct {
+	mu     sync.Mutex
+	rules  []Rule
+	nextID int
+}
+
+// New creates an Injector with no rules
+func New() *Injector {
+	return &Injector{}
+}
+
+// roll returns a random percentage in [0, 100), so a rule of 100 percent always applies
+func roll() float64 {
+	return rand.Float64() * 100
+}
+
+// Add validates rule and starts applying it, returning it with its ID
+func (i *Injector) Add(rule Rule) (Rule, error) {
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.nextID++
+	rule.ID = fmt.Sprintf("fault-%d", i.nextID)
+	i.rules = append(i.rules, rule)
+	log.Printf("Fault injection rule %s added: %+v", rule.ID, rule)
+	return rule, nil
+}
+
+// Remove stops applying the rule with id, reporting whether it existed
+func (i *Injector) Remove(id string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for n, rule := range i.rules {
+		if rule.ID == id {
+			i.rules = append(i.rules[:n], i.rules[n+1:]...)
+			log.Printf("Fault injection rule %s removed", id)
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes every rule
+func (i *Injector) Clear() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = nil
+	log.Printf("Fault injection rules cleared")
+}
+
+// Rules returns the active rules in the order they were added
+func (i *Injector) Rules() []Rule {
+	if i == nil {
+		return nil
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]Rule{}, i.rules...)
+}
+
+// pick returns the latency and error status to inject into a request to path, rolling once
+// for each matching rule. The longest latency and first error status rolled apply.
+func (i *Injector) pick(path string) (latency time.Duration, status int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, rule := range i.rules {
+		if !rule.matches(path) || (rule.LatencyMs == 0 && rule.Status == 0) || roll() >= rule.Percent {
+			continue
+		}
+		if d := time.Duration(rule.LatencyMs) * time.Millisecond; d > latency {
+			latency = d
+		}
+		if status == 0 {
+			status = rule.Status
+		}
+	}
+	return latency, status
+}
+
+// Middleware delays or fails requests as the rules matching their path roll. An injected
+// error is answered as JSON with the Header set, so clients treat it like a real failure
+// while tests can still tell it apart.
+func (i *Injector) Middleware(next http.Handler) http.Handler {
+	if i == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		latency, status := i.pick(r.URL.Path)
+		if latency > 0 {
+			timer := time.NewTimer(latency)
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			}
+			w.Header().Set(Header, "latency")
+		}
+		if status != 0 {
+			w.Header().Set(Header, "error")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]string{"error": "injected fault"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// DropFrame reports whether an outbound WebSocket frame of a connection made to route should
+// be discarded, rolling once for each matching rule that drops frames
+func (i *Injector) DropFrame(route string) bool {
+	if i == nil {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, rule := range i.rules {
+		if rule.DropFrames && rule.matches(route) && roll() < rule.Percent {
+			return true
+		}
+	}
+	return false
+}
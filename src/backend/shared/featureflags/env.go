@@ -0,0 +1,162 @@
+// Package featureflags decides whether a feature is enabled for a caller
+// Version: 1.0.0
+
+package featureflags
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envPrefix marks environment variables that define flags, e.g. FEATURE_PRICING_ENGINE
+const envPrefix = "FEATURE_"
+
+// EnvProvider serves flags defined in environment variables and an optional env file.
+// Each FEATURE_<NAME> variable defines the flag <name>; its value is one of:
+//
+//	true | false           on or off for everyone
+//	25%                    on for 25% of callers
+//	tenants=acme,globex    on for the listed tenants only
+//	tenants=acme;percent=10
+//	                       on for the listed tenants and 10% of everyone else
+type EnvProvider struct {
+	rules map[string]Rule
+}
+
+// NewEnvProvider loads flags from file, if given, then from the process environment,
+// which takes precedence
+func NewEnvProvider(file string) (*EnvProvider, error) {
+	provider := &EnvProvider{rules: make(map[string]Rule)}
+
+	if file != "" {
+		values, err := readEnvFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range values {
+			if err := provider.set(name, value); err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+	}
+
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		if err := provider.set(name, value); err != nil {
+			return nil, fmt.Errorf("environment: %w", err)
+		}
+	}
+
+	return provider, nil
+}
+
+// Enabled implements Provider
+func (p *EnvProvider) Enabled(flag string, target Target) bool {
+	rule, ok := p.rules[normalizeKey(flag)]
+	return ok && rule.Evaluate(normalizeKey(flag), target)
+}
+
+// set records the rule for a FEATURE_* variable; other variables are ignored
+func (p *EnvProvider) set(name, value string) error {
+	if !strings.HasPrefix(name, envPrefix) || len(name) == len(envPrefix) {
+		return nil
+	}
+
+	rule, err := parseRule(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+	p.rules[normalizeKey(strings.TrimPrefix(name, envPrefix))] = rule
+	return nil
+}
+
+// parseRule parses the value of a FEATURE_* variable
+func parseRule(value string) (Rule, error) {
+	value = strings.TrimSpace(value)
+
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		return Rule{Enabled: enabled}, nil
+	}
+
+	if strings.HasSuffix(value, "%") {
+		percentage, err := parsePercentage(strings.TrimSuffix(value, "%"))
+		if err != nil {
+			return Rule{}, err
+		}
+		return Rule{Enabled: true, Percentage: &percentage}, nil
+	}
+
+	rule := Rule{Enabled: true}
+	for _, clause := range strings.Split(value, ";") {
+		key, arg, ok := strings.Cut(strings.TrimSpace(clause), "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("expected true, false, N%% or key=value clauses, got %q", value)
+		}
+
+		switch strings.TrimSpace(key) {
+		case "tenants":
+			for _, tenant := range strings.Split(arg, ",") {
+				if tenant = strings.TrimSpace(tenant); tenant != "" {
+					rule.Tenants = append(rule.Tenants, tenant)
+				}
+			}
+		case "percent":
+			percentage, err := parsePercentage(strings.TrimSuffix(strings.TrimSpace(arg), "%"))
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Percentage = &percentage
+		default:
+			return Rule{}, fmt.Errorf("unknown clause %q", key)
+		}
+	}
+
+	// A tenant list alone is a tenant-only rollout, not a rollout to everyone
+	if len(rule.Tenants) > 0 && rule.Percentage == nil {
+		zero := 0.0
+		rule.Percentage = &zero
+	}
+	return rule, nil
+}
+
+// parsePercentage parses a rollout percentage between 0 and 100
+func parsePercentage(value string) (float64, error) {
+	percentage, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || percentage < 0 || percentage > 100 {
+		return 0, fmt.Errorf("percentage must be between 0 and 100, got %q", value)
+	}
+	return percentage, nil
+}
+
+// readEnvFile reads KEY=VALUE lines, skipping blank lines and # comments and
+// accepting an optional "export " prefix and quoted values
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open feature flag file: %w", err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(strings.TrimPrefix(text, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, line)
+		}
+		values[strings.TrimSpace(name)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read feature flag file: %w", err)
+	}
+
+	return values, nil
+}
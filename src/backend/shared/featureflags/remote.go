@@ -0,0 +1,112 @@
+// Package featureflags decides whether a feature is enabled for a caller
+// Version: 1.0.0
+
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultPollInterval is used when no poll interval is configured
+const defaultPollInterval = 30 * time.Second
+
+// ruleSet is the document served by the flag service
+type ruleSet struct {
+	Flags map[string]Rule `json:"flags"`
+}
+
+// RemoteProvider evaluates rules fetched from a flag service, in the manner of server-side
+// LaunchDarkly SDKs: rules are polled in the background and evaluated locally, so a flag
+// check never waits on the network. The last rules fetched stay in effect if the service
+// becomes unreachable.
+type RemoteProvider struct {
+	url      string
+	sdkKey   string
+	interval time.Duration
+	client   *http.Client
+
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+// NewRemoteProvider creates a provider polling url every interval
+func NewRemoteProvider(url, sdkKey string, interval time.Duration) *RemoteProvider {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	return &RemoteProvider{
+		url:      url,
+		sdkKey:   sdkKey,
+		interval: interval,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		rules:    make(map[string]Rule),
+	}
+}
+
+// Enabled implements Provider
+func (p *RemoteProvider) Enabled(flag string, target Target) bool {
+	p.mu.RLock()
+	rule, ok := p.rules[normalizeKey(flag)]
+	p.mu.RUnlock()
+	return ok && rule.Evaluate(normalizeKey(flag), target)
+}
+
+// Run refreshes the rules every poll interval until ctx is cancelled
+func (p *RemoteProvider) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Refresh(); err != nil {
+				log.Printf("Failed to refresh feature flags, keeping previous rules: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh fetches the current rules and replaces the ones in effect
+func (p *RemoteProvider) Refresh() error {
+	req, err := http.NewRequest(http.MethodGet, p.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create feature flag request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.sdkKey != "" {
+		req.Header.Set("Authorization", p.sdkKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch feature flags: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("flag service returned status %d", resp.StatusCode)
+	}
+
+	var set ruleSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode feature flags: %w", err)
+	}
+
+	rules := make(map[string]Rule, len(set.Flags))
+	for flag, rule := range set.Flags {
+		rules[normalizeKey(flag)] = rule
+	}
+
+	p.mu.Lock()
+	p.rules = rules
+	p.mu.Unlock()
+	return nil
+}
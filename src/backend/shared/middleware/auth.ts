@@ -4,6 +4,7 @@
  * 2. Configure token expiration time in environment variables (JWT_EXPIRATION)
  * 3. Implement token refresh mechanism if required
  * 4. Set up monitoring for authentication failures and suspicious activities
+ * 5. Configure OAUTH_ISSUER and OAUTH_AUDIENCE per environment to accept identity provider tokens
  */
 
 // jsonwebtoken v9.0.0
@@ -13,6 +14,10 @@ import { createHttpError } from '../utils/error';
 import logger from '../utils/logger';
 import { validateModel } from '../utils/validation';
 import { User } from '../models/user';
+import { loadOAuthConfig, OAuthVerifier } from './oauth';
+
+/** Role given to identity provider tokens that carry no role claim, such as client credentials */
+const OAUTH_CLIENT_ROLE = 'client';
 
 /**
  * Extended Request interface to include user information
@@ -23,8 +28,27 @@ interface AuthenticatedRequest extends Request {
     email: string;
     role: string;
   };
+  // Set only for identity provider tokens; first-party session tokens carry no scopes
+  auth?: {
+    clientId?: string;
+    scopes: string[];
+  };
 }
 
+// Created on first use so the environment is read after dotenv has loaded it
+let oauthVerifier: OAuthVerifier | null | undefined;
+
+/**
+ * @description Returns the identity provider verifier, or null when OAuth2 tokens are not configured
+ */
+const getOAuthVerifier = (): OAuthVerifier | null => {
+  if (oauthVerifier === undefined) {
+    const config = loadOAuthConfig();
+    oauthVerifier = config ? new OAuthVerifier(config) : null;
+  }
+  return oauthVerifier;
+};
+
 /**
  * @description Middleware function to authenticate requests using JWT
  * Addresses requirement: Technical Specification/10.1 Authentication and Authorization
@@ -52,9 +76,35 @@ export const authenticateToken = async (
       throw createHttpError(401, 'Authentication required');
     }
 
+    // Tokens from the identity provider are verified against its published signing keys
+    const verifier = getOAuthVerifier();
+    if (verifier && verifier.isIssuedBy(token)) {
+      try {
+        const oauthToken = await verifier.verify(token);
+        req.user = {
+          id: oauthToken.subject,
+          email: oauthToken.email || '',
+          role: oauthToken.role || OAUTH_CLIENT_ROLE
+        };
+        req.auth = {
+          clientId: oauthToken.clientId,
+          scopes: oauthToken.scopes
+        };
+      } catch (error) {
+        logger.logError('OAuth token verification failed', {
+          error,
+          path: req.path,
+          method: req.method
+        });
+        throw createHttpError(403, 'Invalid or expired token');
+      }
+      next();
+      return;
+    }
+
     // Verify the JWT token
     try {
-      const decoded = jwt.verify(token, process.env.JWT_SECRET!) as {
+      const decoded = jwt.verify(token, process.env.JWT_SECRET!, { algorithms: ['HS256'] }) as {
         id: string;
         email: string;
         role: string;
@@ -130,4 +180,50 @@ export const authorizeRole = (allowedRoles: string[]) => {
       next(error);
     }
   };
-};
\ No newline at end of file
+};
+
+/**
+ * @description Middleware factory function for scope-based authorization of identity provider tokens
+ * Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+ * Identity provider tokens must grant every required scope. First-party session tokens carry no
+ * scopes and are authorized by role instead, so they pass this check.
+ *
+ * @param requiredScopes - Scopes the token must grant, e.g. bookings:write
+ * @returns Middleware function for scope-based authorization
+ */
+export const requireScopes = (requiredScopes: string[]) => {
+  return async (
+    req: AuthenticatedRequest,
+    res: Response,
+    next: NextFunction
+  ): Promise<void> => {
+    try {
+      if (!req.user) {
+        logger.logError('Authorization failed - No user context', {
+          path: req.path,
+          method: req.method
+        });
+        throw createHttpError(401, 'Authentication required');
+      }
+
+      if (req.auth) {
+        const granted = req.auth.scopes;
+        const missing = requiredScopes.filter(scope => !granted.includes(scope));
+        if (missing.length > 0) {
+          logger.logError('Authorization failed - Insufficient scope', {
+            userId: req.user.id,
+            clientId: req.auth.clientId,
+            missingScopes: missing,
+            path: req.path,
+            method: req.method
+          });
+          throw createHttpError(403, 'Insufficient scope');
+        }
+      }
+
+      next();
+    } catch (error) {
+      next(error);
+    }
+  };
+};
@@ -9,7 +9,7 @@
 
 // express v4.18.2
 import { Request, Response, NextFunction } from 'express';
-import { logInfo, logError } from '../utils/logger';
+import { logInfo, logError, logDebug } from '../utils/logger';
 import { createHttpError } from '../utils/error';
 
 /**
@@ -34,6 +34,7 @@ export const loggingMiddleware = (
     requestId,
     method: req.method,
     url: req.originalUrl,
+    // Credentials are removed here; contact details and exact coordinates are redacted by the logger
     headers: {
       ...req.headers,
       // Exclude sensitive headers
@@ -85,6 +86,11 @@ export const loggingMiddleware = (
       userAgent: req.get('user-agent')
     });
 
+    // Successful response bodies are only logged at debug level
+    if (statusCode < 400) {
+      logDebug('HTTP Response Body', { requestId, responseBody });
+    }
+
     // Call original end method
     return originalEnd.call(res, chunk, ...rest);
   };
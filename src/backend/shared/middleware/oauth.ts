@@ -0,0 +1,213 @@
+/**
+ * Human Tasks:
+ * 1. Set OAUTH_ISSUER to the identity provider's issuer URL in each environment; OAuth2 tokens
+ *    are rejected while it is unset
+ * 2. Set OAUTH_AUDIENCE to the API identifier registered with the identity provider
+ * 3. Set OAUTH_JWKS_URI only if the provider does not publish OpenID discovery metadata
+ * 4. Grant partner clients only the scopes they need (e.g. bookings:write, tracking:read)
+ */
+
+// jsonwebtoken v9.0.0
+import jwt, { JwtHeader, JwtPayload } from 'jsonwebtoken';
+// jwks-rsa v3.0.1
+import jwksClient, { JwksClient } from 'jwks-rsa';
+import http from 'http';
+import https from 'https';
+
+/** Algorithms accepted for identity provider tokens; symmetric algorithms are never trusted here */
+const OAUTH_ALGORITHMS: jwt.Algorithm[] = ['RS256', 'RS384', 'RS512', 'ES256', 'ES384', 'ES512'];
+
+/**
+ * @description Identity provider settings, read from the environment so each environment can
+ * point at its own tenant
+ */
+export interface OAuthConfig {
+  issuer: string;
+  audience: string;
+  jwksUri?: string;
+  clockToleranceSeconds: number;
+}
+
+/**
+ * @description Claims of a verified identity provider access token
+ */
+export interface OAuthToken {
+  subject: string;
+  clientId?: string;
+  email?: string;
+  role?: string;
+  scopes: string[];
+}
+
+/**
+ * @description Reads the identity provider settings from the environment
+ * @returns The settings, or undefined when OAUTH_ISSUER is unset and OAuth2 tokens are disabled
+ */
+export const loadOAuthConfig = (env: NodeJS.ProcessEnv = process.env): OAuthConfig | undefined => {
+  const issuer = env.OAUTH_ISSUER?.trim();
+  if (!issuer) {
+    return undefined;
+  }
+
+  const audience = env.OAUTH_AUDIENCE?.trim();
+  if (!audience) {
+    throw new Error('OAUTH_AUDIENCE is required when OAUTH_ISSUER is set');
+  }
+
+  const tolerance = parseInt(env.OAUTH_CLOCK_TOLERANCE_SECONDS || '30', 10);
+  if (Number.isNaN(tolerance) || tolerance < 0) {
+    throw new Error(`Invalid OAUTH_CLOCK_TOLERANCE_SECONDS value: ${env.OAUTH_CLOCK_TOLERANCE_SECONDS}`);
+  }
+
+  return {
+    issuer,
+    audience,
+    jwksUri: env.OAUTH_JWKS_URI?.trim() || undefined,
+    clockToleranceSeconds: tolerance
+  };
+};
+
+/**
+ * @description Fetches and parses a JSON document over HTTP(S)
+ */
+const fetchJson = (url: string): Promise<any> =>
+  new Promise((resolve, reject) => {
+    const client = url.startsWith('https:') ? https : http;
+    const req = client.get(url, { timeout: 5000 }, res => {
+      if (!res.statusCode || res.statusCode >= 300) {
+        res.resume();
+        reject(new Error(`${url} returned status ${res.statusCode}`));
+        return;
+      }
+      let body = '';
+      res.setEncoding('utf8');
+      res.on('data', chunk => (body += chunk));
+      res.on('end', () => {
+        try {
+          resolve(JSON.parse(body));
+        } catch (error) {
+          reject(error);
+        }
+      });
+    });
+    req.on('timeout', () => req.destroy(new Error(`${url} timed out`)));
+    req.on('error', reject);
+  });
+
+/**
+ * @description Verifies access tokens issued by the configured identity provider. Signing keys are
+ * found through OpenID discovery unless a JWKS URI is configured, and are cached, so key rotation
+ * at the provider is picked up without a restart.
+ */
+export class OAuthVerifier {
+  private client?: Promise<JwksClient>;
+
+  constructor(private readonly config: OAuthConfig) {}
+
+  /**
+   * @description Reports whether a token claims to come from the configured issuer. The claim is
+   * only trusted after verify succeeds.
+   */
+  isIssuedBy(token: string): boolean {
+    const decoded = jwt.decode(token);
+    return typeof decoded === 'object' && decoded !== null && decoded.iss === this.config.issuer;
+  }
+
+  /**
+   * @description Verifies a token's signature, issuer, audience and lifetime
+   * @throws Error if the token is not valid
+   */
+  async verify(token: string): Promise<OAuthToken> {
+    const client = await this.jwks();
+
+    const payload = await new Promise<JwtPayload>((resolve, reject) => {
+      const getKey = (header: JwtHeader, callback: jwt.SigningKeyCallback) => {
+        client
+          .getSigningKey(header.kid)
+          .then(key => callback(null, key.getPublicKey()))
+          .catch(error => callback(error));
+      };
+
+      jwt.verify(
+        token,
+        getKey,
+        {
+          algorithms: OAUTH_ALGORITHMS,
+          issuer: this.config.issuer,
+          audience: this.config.audience,
+          clockTolerance: this.config.clockToleranceSeconds
+        },
+        (error, decoded) => {
+          if (error || typeof decoded !== 'object' || decoded === null) {
+            reject(error || new Error('token has no claims'));
+            return;
+          }
+          resolve(decoded as JwtPayload);
+        }
+      );
+    });
+
+    if (!payload.sub) {
+      throw new Error('token has no subject');
+    }
+
+    return {
+      subject: payload.sub,
+      clientId: payload.client_id || payload.azp,
+      email: payload.email,
+      role: payload.role,
+      scopes: parseScopes(payload)
+    };
+  }
+
+  /**
+   * @description Returns the JWKS client, resolving the JWKS URI through discovery on first use.
+   * A failed discovery is retried on the next request.
+   */
+  private jwks(): Promise<JwksClient> {
+    if (!this.client) {
+      this.client = this.resolveJwksUri()
+        .then(jwksUri =>
+          jwksClient({
+            jwksUri,
+            cache: true,
+            cacheMaxAge: 10 * 60 * 1000,
+            rateLimit: true,
+            jwksRequestsPerMinute: 10
+          })
+        )
+        .catch(error => {
+          this.client = undefined;
+          throw error;
+        });
+    }
+    return this.client;
+  }
+
+  private async resolveJwksUri(): Promise<string> {
+    if (this.config.jwksUri) {
+      return this.config.jwksUri;
+    }
+
+    const discoveryUrl = `${this.config.issuer.replace(/\/+$/, '')}/.well-known/openid-configuration`;
+    const metadata = await fetchJson(discoveryUrl);
+    if (!metadata || typeof metadata.jwks_uri !== 'string') {
+      throw new Error(`${discoveryUrl} has no jwks_uri`);
+    }
+    return metadata.jwks_uri;
+  }
+}
+
+/**
+ * @description Reads granted scopes from the space-separated scope claim, or the scp array some
+ * providers use instead
+ */
+const parseScopes = (payload: JwtPayload): string[] => {
+  if (typeof payload.scope === 'string') {
+    return payload.scope.split(' ').filter(Boolean);
+  }
+  if (Array.isArray(payload.scp)) {
+    return payload.scp.filter((scope: unknown): scope is string => typeof scope === 'string');
+  }
+  return [];
+};
@@ -0,0 +1,151 @@
+// Package moderation screens text written by users, such as chat messages and walk notes,
+// before it is stored or shown to anyone else. Filters are pluggable: the keyword filter
+// serves until a moderation provider's API is wired in behind the same interface.
+// Version: 1.0.0
+
+package moderation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// Human Tasks:
+// 1. Maintain the blocked and flagged term lists per environment; they are read at startup
+// 2. Review flagged content regularly from the services' admin endpoints
+// 3. Evaluate a moderation provider to replace the keyword lists
+
+// Action is what a filter decides should happen to a piece of text
+type Action string
+
+// Filter actions
+const (
+	// ActionAllow passes the text through
+	ActionAllow Action = "allow"
+
+	// ActionFlag passes the text through but queues it for review by an admin
+	ActionFlag Action = "flag"
+
+	// ActionBlock rejects the text
+	ActionBlock Action = "block"
+)
+
+// Verdict is a filter's decision on a piece of text
+type Verdict struct {
+	Action Action `json:"action" bson:"action"`
+
+	// Matches are the terms, or provider categories, that led to the decision
+	Matches []string `json:"matches,omitempty" bson:"matches,omitempty"`
+}
+
+// Filter reviews text before it is stored or delivered. Implementations fail open: callers
+// treat an error as allowing the text, so an unavailable provider does not stop conversations.
+type Filter interface {
+	Review(ctx context.Context, text string) (Verdict, error)
+}
+
+// Options configures the filter built by Init
+type Options struct {
+	// BlockedTerms are words or phrases that get text rejected
+	BlockedTerms []string
+
+	// FlaggedTerms are words or phrases that get text queued for review
+	FlaggedTerms []string
+}
+
+// Default is the process-wide filter used by Review. It is set once by Init at startup and
+// allows everything until then.
+var Default Filter = NewKeywordFilter(nil, nil)
+
+// Init builds the filter described by opts and makes it the default
+func Init(opts Options) Filter {
+	Default = NewKeywordFilter(opts.BlockedTerms, opts.FlaggedTerms)
+	return Default
+}
+
+// Review reviews text with the default filter
+func Review(ctx context.Context, text string) (Verdict, error) {
+	return Default.Review(ctx, text)
+}
+
+// ParseTerms splits a comma-separated term list, dropping blanks
+func ParseTerms(list string) []string {
+	var terms []string
+	for _, term := range strings.Split(list, ",") {
+		if term = strings.TrimSpace(term); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// KeywordFilter blocks or flags text containing listed words or phrases. Terms match whole
+// words regardless of case and punctuation, so "ass" does not flag "class".
+type KeywordFilter struct {
+	blocked [][]string
+	flagged [][]string
+}
+
+// NewKeywordFilter creates a filter for the given blocked and flagged terms
+func NewKeywordFilter(blocked, flagged []string) *KeywordFilter {
+	return &KeywordFilter{blocked: tokenizeTerms(blocked), flagged: tokenizeTerms(flagged)}
+}
+
+// Review implements Filter. Blocked terms take precedence over flagged ones.
+func (f *KeywordFilter) Review(ctx context.Context, text string) (Verdict, error) {
+	words := tokenize(text)
+	if matches := matchTerms(words, f.blocked); len(matches) > 0 {
+		return Verdict{Action: ActionBlock, Matches: matches}, nil
+	}
+	if matches := matchTerms(words, f.flagged); len(matches) > 0 {
+		return Verdict{Action: ActionFlag, Matches: matches}, nil
+	}
+	return Verdict{Action: ActionAllow}, nil
+}
+
+// tokenizeTerms splits each term into its words, skipping terms without any
+func tokenizeTerms(terms []string) [][]string {
+	var tokenized [][]string
+	for _, term := range terms {
+		if words := tokenize(term); len(words) > 0 {
+			tokenized = append(tokenized, words)
+		}
+	}
+	return tokenized
+}
+
+// tokenize lowercases text and splits it into words of letters and digits
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchTerms returns each term whose words appear consecutively in words
+func matchTerms(words []string, terms [][]string) []string {
+	var matches []string
+	for _, term := range terms {
+		if containsPhrase(words, term) {
+			matches = append(matches, strings.Join(term, " "))
+		}
+	}
+	return matches
+}
+
+// containsPhrase reports whether phrase appears as a run of consecutive words
+func containsPhrase(words, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(words); i++ {
+		matched := true
+		for j, word := range phrase {
+			if words[i+j] != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+// Package policy decides whether a role may perform an action on a resource
+// Version: 1.0.0
+
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOPAPath is the decision queried when no path is configured
+const defaultOPAPath = "dogwalk/authz/allow"
+
+// OPAAuthorizer asks an Open Policy Agent server for each decision through its data API,
+// sending the Request as input. The decision document must be a boolean; an undefined
+// decision denies the request.
+type OPAAuthorizer struct {
+	url    string
+	client *http.Client
+}
+
+// NewOPAAuthorizer creates an authorizer querying the decision at path on the OPA server at baseURL
+func NewOPAAuthorizer(baseURL, path string) *OPAAuthorizer {
+	if path == "" {
+		path = defaultOPAPath
+	}
+	return &OPAAuthorizer{
+		url:    strings.TrimRight(baseURL, "/") + "/v1/data/" + strings.Trim(path, "/"),
+		client: &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+// Allowed implements Authorizer
+func (a *OPAAuthorizer) Allowed(ctx context.Context, req Request) (bool, error) {
+	body, err := json.Marshal(map[string]Request{"input": req})
+	if err != nil {
+		return false, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create policy request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return false, fmt.Errorf("failed to query policy server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("policy server returned status %d", resp.StatusCode)
+	}
+
+	var decision struct {
+		Result *bool `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return false, fmt.Errorf("failed to decode policy decision: %w", err)
+	}
+	return decision.Result != nil && *decision.Result, nil
+}
@@ -0,0 +1,202 @@
+// Package policy decides whether a role may perform an action on a resource, so the
+// authorization rules of every service are defined, reviewed and tested in one place.
+// Version: 1.0.0
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Human Tasks:
+// 1. Review changes to DefaultMatrix with security; it is the policy in effect unless overridden
+// 2. To manage rules outside the code, point the services at an OPA server or a matrix file
+// 3. Keep the role names in step with the roles issued by the auth-service
+
+// Roles known to the platform
+const (
+	RoleAdmin  = "admin"
+	RoleOwner  = "owner"
+	RoleWalker = "walker"
+
+	// RoleClient is a server-to-server client, such as a partner backend
+	RoleClient = "client"
+)
+
+// Resources protected by the services
+const (
+	ResourceBookings            = "bookings"
+	ResourceBookingOverrides    = "booking_overrides"
+	ResourceAPIKeys             = "api_keys"
+	ResourceWalkerVerifications = "walker_verifications"
+	ResourceLocations           = "locations"
+	ResourceIncidents           = "incidents"
+	ResourceIncidentQueue       = "incident_queue"
+	ResourceInstances           = "instances"
+	ResourceSubjectData         = "subject_data"
+	ResourceReconciliation      = "reconciliation"
+	ResourceDisputes            = "disputes"
+	ResourceCapacity            = "capacity"
+	ResourceRegions             = "regions"
+	ResourceLocationAnalytics   = "location_analytics"
+	ResourceNotifications       = "notifications"
+	ResourceDeliveryReceipts    = "delivery_receipts"
+	ResourceFlaggedContent      = "flagged_content"
+	ResourceBookingRules        = "booking_rules"
+	ResourceHolidays            = "holidays"
+	ResourceReports             = "reports"
+	ResourceDispatch            = "dispatch"
+	ResourceMessages            = "messages"
+	ResourcePrivacyZones        = "privacy_zones"
+	ResourceEarnings            = "earnings"
+	ResourceConsents            = "consents"
+	ResourceFaults              = "faults"
+	ResourceRatePlans           = "rate_plans"
+	ResourceAvailability        = "availability"
+	ResourceReferrals           = "referrals"
+)
+
+// Actions on resources
+const (
+	ActionRead   = "read"
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Any matches every resource or action in a Matrix
+const Any = "*"
+
+// Request is a single authorization question
+type Request struct {
+	// Role of the caller
+	Role string `json:"role"`
+
+	// Resource the caller wants to act on
+	Resource string `json:"resource"`
+
+	// Action the caller wants to perform
+	Action string `json:"action"`
+
+	// SubjectID identifies the caller, for policies that need it; the matrix ignores it
+	SubjectID string `json:"subject_id,omitempty"`
+}
+
+// Authorizer answers authorization requests. Implementations fail closed: an error means
+// the request must be denied.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+type Authorizer interface {
+	Allowed(ctx context.Context, req Request) (bool, error)
+}
+
+// Matrix maps a role to the actions it may perform on each resource. Any may be used as a
+// resource or an action.
+type Matrix map[string]map[string][]string
+
+// Allowed implements Authorizer
+func (m Matrix) Allowed(ctx context.Context, req Request) (bool, error) {
+	resources := m[req.Role]
+	for _, resource := range []string{req.Resource, Any} {
+		for _, action := range resources[resource] {
+			if action == req.Action || action == Any {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// DefaultMatrix is the platform's authorization policy. Ownership of individual records, such
+// as whether a booking belongs to the caller, is checked by the services themselves.
+var DefaultMatrix = Matrix{
+	RoleAdmin: {
+		Any: {Any},
+	},
+	RoleOwner: {
+		ResourceBookings:      {ActionRead, ActionCreate, ActionUpdate},
+		ResourceLocations:     {ActionRead},
+		ResourceIncidents:     {ActionRead, ActionCreate},
+		ResourceNotifications: {ActionRead, ActionUpdate},
+		ResourceMessages:      {ActionRead, ActionCreate},
+		ResourceConsents:      {ActionRead, ActionCreate, ActionDelete},
+		ResourceReferrals:     {ActionRead, ActionCreate},
+	},
+	RoleWalker: {
+		ResourceBookings:      {ActionRead, ActionUpdate},
+		ResourceLocations:     {ActionRead, ActionCreate},
+		ResourceIncidents:     {ActionRead, ActionCreate},
+		ResourceNotifications: {ActionRead, ActionUpdate},
+		ResourceMessages:      {ActionRead, ActionCreate},
+		ResourcePrivacyZones:  {ActionRead, ActionCreate, ActionDelete},
+		ResourceEarnings:      {ActionRead},
+		ResourceRatePlans:     {ActionUpdate},
+		ResourceAvailability:  {ActionCreate},
+		ResourceConsents:      {ActionRead, ActionCreate, ActionDelete},
+	},
+	RoleClient: {
+		ResourceBookings: {ActionRead, ActionCreate},
+	},
+}
+
+// Options selects where the policy comes from
+type Options struct {
+	// File is a JSON matrix replacing DefaultMatrix
+	File string
+
+	// OPAURL is the base URL of an OPA server; when set it takes precedence over File
+	OPAURL string
+
+	// OPAPath is the document path of the decision in OPA, e.g. dogwalk/authz/allow
+	OPAPath string
+}
+
+// Default is the process-wide authorizer used by Allowed. It is set once by Init at startup.
+var Default Authorizer = DefaultMatrix
+
+// Init builds the authorizer described by opts and makes it the default
+func Init(opts Options) (Authorizer, error) {
+	var authorizer Authorizer = DefaultMatrix
+	switch {
+	case opts.OPAURL != "":
+		authorizer = NewOPAAuthorizer(opts.OPAURL, opts.OPAPath)
+	case opts.File != "":
+		matrix, err := LoadMatrix(opts.File)
+		if err != nil {
+			return nil, err
+		}
+		authorizer = matrix
+	}
+
+	Default = authorizer
+	return authorizer, nil
+}
+
+// LoadMatrix reads a matrix from a JSON file of the form
+// {"owner": {"bookings": ["read", "create"]}}
+func LoadMatrix(file string) (Matrix, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var matrix Matrix
+	if err := json.Unmarshal(data, &matrix); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", file, err)
+	}
+	return matrix, nil
+}
+
+// Allowed reports whether role may perform action on resource using the default authorizer.
+// Errors are logged and deny the request.
+func Allowed(ctx context.Context, role, resource, action string) bool {
+	allowed, err := Default.Allowed(ctx, Request{Role: role, Resource: resource, Action: action})
+	if err != nil {
+		log.Printf("Authorization check failed, denying %s %s to %s: %v", action, resource, role, err)
+		return false
+	}
+	return allowed
+}
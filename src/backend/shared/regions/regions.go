@@ -0,0 +1,109 @@
+// Package regions defines the service regions walks take place in, such as the zones of a
+// city, so every service tags walks with the same region for the same coordinates.
+// Version: 1.0.0
+
+package regions
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// idPattern restricts region IDs to lower-case slugs such as "nyc-brooklyn"
+var idPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Point is a coordinate on a region boundary
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Region is a named area bounded by a polygon. The boundary is a single ring of at least three
+// points; it is closed implicitly, so the last point need not repeat the first.
+type Region struct {
+	// ID is the region's slug, stored on bookings and walks
+	ID string `json:"id"`
+
+	// Name is the region's display name, such as "Brooklyn"
+	Name string `json:"name"`
+
+	// Boundary is the polygon enclosing the region
+	Boundary []Point `json:"boundary"`
+
+	// UpdatedAt is when the region was last defined
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate performs validation checks on the Region instance.
+func (r *Region) Validate() error {
+	if !idPattern.MatchString(r.ID) {
+		return fmt.Errorf("region ID must be a lower-case slug such as \"nyc-brooklyn\"")
+	}
+	if r.Name == "" {
+		return fmt.Errorf("region name is required")
+	}
+	if len(r.Boundary) < 3 {
+		return fmt.Errorf("region boundary needs at least three points")
+	}
+	for _, p := range r.Boundary {
+		if p.Latitude < -90 || p.Latitude > 90 || p.Longitude < -180 || p.Longitude > 180 {
+			return fmt.Errorf("region boundary point (%g, %g) is out of range", p.Latitude, p.Longitude)
+		}
+	}
+	return nil
+}
+
+// Contains reports whether the coordinate lies inside the region's boundary. Boundaries are
+// treated as flat, which is accurate at the scale of a city zone; they must not cross the
+// antimeridian.
+func (r *Region) Contains(latitude, longitude float64) bool {
+	// Even-odd ray casting: count the edges a ray heading east from the point crosses
+	inside := false
+	for i, j := 0, len(r.Boundary)-1; i < len(r.Boundary); j, i = i, i+1 {
+		a, b := r.Boundary[i], r.Boundary[j]
+		if (a.Latitude > latitude) == (b.Latitude > latitude) {
+			continue
+		}
+		crossing := a.Longitude + (latitude-a.Latitude)*(b.Longitude-a.Longitude)/(b.Latitude-a.Latitude)
+		if longitude < crossing {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Set is a collection of regions a coordinate can be located in
+type Set struct {
+	regions []Region
+}
+
+// NewSet creates a set of regions. Where regions overlap, a coordinate is located in the one
+// whose ID sorts first, so every service resolves it the same way.
+func NewSet(regions []Region) *Set {
+	sorted := append([]Region(nil), regions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return &Set{regions: sorted}
+}
+
+// Locate returns the ID of the region containing the coordinate, or "" when no region does
+func (s *Set) Locate(latitude, longitude float64) string {
+	if s == nil {
+		return ""
+	}
+	for i := range s.regions {
+		if s.regions[i].Contains(latitude, longitude) {
+			return s.regions[i].ID
+		}
+	}
+	return ""
+}
+
+// Regions returns the regions in the set, by ID
+func (s *Set) Regions() []Region {
+	if s == nil {
+		return nil
+	}
+	return append([]Region(nil), s.regions...)
+}
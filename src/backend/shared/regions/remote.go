@@ -0,0 +1,105 @@
+// Package regions defines the service regions walks take place in
+// Version: 1.0.0
+
+package regions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultPollInterval is used when no poll interval is configured
+const defaultPollInterval = 5 * time.Minute
+
+// regionList is the booking-service region list response
+type regionList struct {
+	Success bool     `json:"success"`
+	Data    []Region `json:"data"`
+}
+
+// RemoteDirectory locates coordinates in the regions published by the booking-service, which
+// stores them. Regions are polled in the background and matched locally, so tagging a point
+// never waits on the network. The last regions fetched stay in effect if the booking-service
+// becomes unreachable.
+type RemoteDirectory struct {
+	url      string
+	interval time.Duration
+	client   *http.Client
+
+	mu  sync.RWMutex
+	set *Set
+}
+
+// NewRemoteDirectory creates a directory polling the region list at url every interval
+func NewRemoteDirectory(url string, interval time.Duration) *RemoteDirectory {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	return &RemoteDirectory{
+		url:      url,
+		interval: interval,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		set:      NewSet(nil),
+	}
+}
+
+// Locate returns the ID of the region containing the coordinate, or "" when no region does
+// or none have been fetched yet
+func (d *RemoteDirectory) Locate(latitude, longitude float64) string {
+	d.mu.RLock()
+	set := d.set
+	d.mu.RUnlock()
+	return set.Locate(latitude, longitude)
+}
+
+// Run refreshes the regions every poll interval until ctx is cancelled
+func (d *RemoteDirectory) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Refresh(); err != nil {
+				log.Printf("Failed to refresh regions, keeping previous ones: %v", err)
+			}
+		}
+	}
+}
+
+// Refresh fetches the current regions and replaces the ones in effect
+func (d *RemoteDirectory) Refresh() error {
+	req, err := http.NewRequest(http.MethodGet, d.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create regions request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch regions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("region service returned status %d", resp.StatusCode)
+	}
+
+	var list regionList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return fmt.Errorf("failed to decode regions: %w", err)
+	}
+
+	set := NewSet(list.Data)
+	d.mu.Lock()
+	d.set = set
+	d.mu.Unlock()
+	return nil
+}
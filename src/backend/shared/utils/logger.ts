@@ -5,11 +5,13 @@
  *    - NODE_ENV (development, production)
  * 2. Configure log storage/shipping solution (e.g., ELK Stack, CloudWatch)
  * 3. Set up log rotation policies if file transport is used
+ * 4. Set LOG_REDACT_FIELDS for any additional sensitive field names (see ./redaction.ts)
  */
 
 // winston v3.8.2
 import winston from 'winston';
 import { createHttpError } from './error';
+import { redactionFormat } from './redaction';
 
 /**
  * @description Configures Winston logger with standardized formatting and transports
@@ -23,6 +25,8 @@ const winstonLogger = winston.createLogger({
       format: 'YYYY-MM-DD HH:mm:ss'
     }),
     winston.format.errors({ stack: true }),
+    // Redact before anything is serialised, so every transport receives the redacted entry
+    redactionFormat(),
     winston.format.json()
   ),
   defaultMeta: {
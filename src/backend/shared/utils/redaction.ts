@@ -0,0 +1,156 @@
+/**
+ * Human Tasks:
+ * 1. Add service-specific sensitive field names to LOG_REDACT_FIELDS (comma separated)
+ * 2. Review LOG_COORDINATE_PRECISION with the privacy team; 2 decimals is roughly 1 km
+ * 3. Spot-check production logs after enabling debug payload logging for anything that slipped through
+ */
+
+// winston v3.8.2
+import winston from 'winston';
+
+const REDACTED = '[REDACTED]';
+
+/**
+ * Field names whose values are always removed. Names are compared case-insensitively with
+ * underscores and dashes ignored, so apiKey, api_key and X-API-Key all match.
+ */
+const DEFAULT_SENSITIVE_FIELDS = [
+  'password',
+  'passwordhash',
+  'token',
+  'accesstoken',
+  'refreshtoken',
+  'idtoken',
+  'authorization',
+  'cookie',
+  'setcookie',
+  'apikey',
+  'xapikey',
+  'secret',
+  'clientsecret',
+  'email',
+  'phone',
+  'phonenumber',
+  'cardnumber',
+  'cvv'
+];
+
+/** Field names holding a single coordinate, whose values are rounded rather than removed */
+const COORDINATE_FIELDS = ['latitude', 'longitude', 'lat', 'lng', 'lon'];
+
+/** Patterns redacted wherever they appear inside string values */
+const EMAIL_PATTERN = /[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}/g;
+const JWT_PATTERN = /\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+/g;
+const BEARER_PATTERN = /\b(Bearer|Basic)\s+[A-Za-z0-9._~+/=-]+/gi;
+const COORDINATE_PAIR_PATTERN = /(-?\d{1,2}\.\d{3,})(\s*,\s*)(-?\d{1,3}\.\d{3,})/g;
+
+/** Nesting depth beyond which values are replaced rather than walked */
+const MAX_DEPTH = 10;
+
+const normalizeField = (name: string): string => name.toLowerCase().replace(/[_-]/g, '');
+
+const sensitiveFields = new Set(
+  [...DEFAULT_SENSITIVE_FIELDS, ...(process.env.LOG_REDACT_FIELDS || '').split(',')]
+    .map(name => normalizeField(name.trim()))
+    .filter(name => name.length > 0)
+);
+const coordinateFields = new Set(COORDINATE_FIELDS);
+
+const parsedPrecision = parseInt(process.env.LOG_COORDINATE_PRECISION || '2', 10);
+const coordinatePrecision = Number.isNaN(parsedPrecision) ? 2 : Math.min(Math.max(parsedPrecision, 0), 6);
+
+/**
+ * @description Rounds a coordinate so logs show the area but not the exact position
+ * @param value - Latitude or longitude
+ * @returns The coordinate rounded to LOG_COORDINATE_PRECISION decimals
+ */
+const coarsenCoordinate = (value: number): number => {
+  const factor = Math.pow(10, coordinatePrecision);
+  return Math.round(value * factor) / factor;
+};
+
+/**
+ * @description Redacts emails, tokens and exact coordinate pairs embedded in free text
+ * @param text - String to scan
+ * @returns The string with sensitive substrings replaced
+ */
+export const redactString = (text: string): string =>
+  text
+    .replace(JWT_PATTERN, REDACTED)
+    .replace(BEARER_PATTERN, (_match, scheme) => `${scheme} ${REDACTED}`)
+    .replace(EMAIL_PATTERN, REDACTED)
+    .replace(COORDINATE_PAIR_PATTERN, (_match, lat, separator, lng) =>
+      `${coarsenCoordinate(parseFloat(lat))}${separator}${coarsenCoordinate(parseFloat(lng))}`
+    );
+
+/**
+ * @description Returns a redacted copy of a log value. Sensitive fields are removed, coordinate
+ * fields are rounded, and strings are scanned for embedded personal data. The input is not modified.
+ * Addresses requirement: Technical Specification/7.4.1 Monitoring and Observability
+ * @param value - Value to redact
+ * @param field - Name of the field holding the value, if any
+ * @param depth - Nesting depth of the value within the logged entry
+ * @param ancestors - Objects enclosing the value, so only a reference back to one of them is a
+ * cycle; an object referenced from several places elsewhere in the entry is logged each time
+ * @returns The redacted value
+ */
+export const redactValue = (value: unknown, field?: string, depth = 0, ancestors = new WeakSet<object>()): unknown => {
+  if (field !== undefined) {
+    const name = normalizeField(field);
+    if (sensitiveFields.has(name)) {
+      return value === undefined || value === null ? value : REDACTED;
+    }
+    if (coordinateFields.has(name) && typeof value === 'number') {
+      return coarsenCoordinate(value);
+    }
+  }
+
+  if (typeof value === 'string') {
+    return redactString(value);
+  }
+  if (value === null || typeof value !== 'object') {
+    return value;
+  }
+  if (value instanceof Date) {
+    return value;
+  }
+  if (depth >= MAX_DEPTH || ancestors.has(value)) {
+    return '[Truncated]';
+  }
+
+  if (value instanceof Error) {
+    return {
+      name: value.name,
+      message: redactString(value.message),
+      stack: value.stack ? redactString(value.stack) : undefined
+    };
+  }
+
+  ancestors.add(value);
+  let copy: unknown;
+  if (Array.isArray(value)) {
+    copy = value.map(item => redactValue(item, undefined, depth + 1, ancestors));
+  } else {
+    const fields: Record<string, unknown> = {};
+    for (const [key, item] of Object.entries(value as Record<string, unknown>)) {
+      fields[key] = redactValue(item, key, depth + 1, ancestors);
+    }
+    copy = fields;
+  }
+  ancestors.delete(value);
+  return copy;
+};
+
+/**
+ * @description Winston format applying redactValue to the message and every metadata field, so
+ * payloads can be logged at debug level in production without exposing personal data
+ */
+export const redactionFormat = winston.format(info => {
+  for (const key of Object.keys(info)) {
+    if (key === 'level' || key === 'timestamp') {
+      continue;
+    }
+    info[key] = redactValue(info[key], key);
+  }
+  return info;
+});
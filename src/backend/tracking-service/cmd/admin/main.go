@@ -0,0 +1,73 @@
+// Package main is an admin CLI for the tracking-service. Maintenance commands work directly
+// against MongoDB for break-glass use; replay-walk drives a running service through its API.
+// Version: 1.0.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra" // v1.6.1
+
+	"src/backend/tracking-service/internal/cli"
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/encryption"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// Human Tasks:
+// 1. Restrict who can run this tool; purge-locations permanently deletes location history
+// 2. Run it with the same TRACKING_DB_URI and TRACKING_LOCATION_KEYS* settings as the service,
+//    from inside the cluster network
+
+func main() {
+	root := &cobra.Command{
+		Use:           "admin",
+		Short:         "Operational tasks for the tracking-service",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return connect()
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			return repository.Close(cmd.Context())
+		},
+	}
+	root.AddCommand(cli.ReplayWalkCommand(), cli.PurgeLocationsCommand(), cli.ReindexCommand(), cli.RotateLocationKeysCommand())
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// connect opens MongoDB using only the database settings, so the tool does not need the
+// service's token secret or other runtime configuration
+func connect() error {
+	cfg := config.Config{
+		DatabaseURI:        os.Getenv("TRACKING_DB_URI"),
+		BatchSize:          100,
+		BatchFlushInterval: time.Second,
+	}
+	if cfg.DatabaseURI == "" {
+		return fmt.Errorf("TRACKING_DB_URI environment variable is required")
+	}
+	if err := repository.Initialize(cfg); err != nil {
+		return err
+	}
+
+	// Use the service's coordinate encryption keys, so encrypted points can be read
+	if spec := os.Getenv("TRACKING_LOCATION_KEYS"); spec != "" {
+		useKMS, _ := strconv.ParseBool(os.Getenv("TRACKING_LOCATION_KEYS_KMS"))
+		keys, err := encryption.LoadKeyring(context.Background(), spec, os.Getenv("TRACKING_LOCATION_KEY_ID"), useKMS)
+		if err != nil {
+			return fmt.Errorf("failed to load location encryption keys: %w", err)
+		}
+		repository.UseLocationEncryption(keys)
+	}
+	return nil
+}
@@ -0,0 +1,365 @@
+// Package main provides a load generator for the tracking-service. It simulates walkers
+// posting locations and owners following their walks over WebSocket, and reports how long
+// broadcasts take to reach subscribers.
+// Version: 1.0.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"           // v4.5.0
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+)
+
+// Human Tasks:
+// 1. Run against a dedicated environment; every run creates walk sessions and location history
+// 2. Run the generator close to the service so client-side network latency does not dominate
+// 3. Record the percentiles of a baseline run before comparing hub changes against it
+
+// options are the command-line settings of a run
+type options struct {
+	baseURL     string
+	walkers     int
+	subscribers int
+	interval    time.Duration
+	duration    time.Duration
+	rampUp      time.Duration
+	terms       string
+	jwtSecret   string
+}
+
+// walk is a session driven by one simulated walker
+type walk struct {
+	sessionID string
+	latitude  float64
+	longitude float64
+}
+
+// frame is the subset of a hub frame the generator reads
+type frame struct {
+	Payload struct {
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"payload"`
+	Control string `json:"control"`
+}
+
+// stats collects results across walker and subscriber goroutines
+type stats struct {
+	posted     atomic.Int64
+	postErrors atomic.Int64
+	received   atomic.Int64
+
+	mu        sync.Mutex
+	latencies []time.Duration
+}
+
+// bearerTransport authenticates every request with a bearer token
+type bearerTransport struct {
+	token string
+}
+
+// RoundTrip sends req with the token
+func (t bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// record adds a broadcast latency sample
+func (s *stats) record(latency time.Duration) {
+	s.mu.Lock()
+	s.latencies = append(s.latencies, latency)
+	s.mu.Unlock()
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.baseURL, "url", "http://localhost:8080", "tracking-service base URL")
+	flag.IntVar(&opts.walkers, "walkers", 50, "number of simulated walkers, one walk session each")
+	flag.IntVar(&opts.subscribers, "subscribers", 100, "number of WebSocket subscribers, spread across the walks")
+	flag.DurationVar(&opts.interval, "interval", time.Second, "time between location posts per walker")
+	flag.DurationVar(&opts.duration, "duration", time.Minute, "how long to generate load")
+	flag.DurationVar(&opts.rampUp, "ramp-up", 5*time.Second, "time over which walkers start posting")
+	flag.StringVar(&opts.terms, "terms-version", "loadgen", "tracking terms version to consent to; must match TRACKING_CONSENT_TERMS_VERSION if set")
+	flag.StringVar(&opts.jwtSecret, "jwt-secret", os.Getenv("JWT_SECRET"), "secret the service verifies user tokens with; the generator acts as an admin")
+	flag.Parse()
+
+	if opts.walkers < 1 || opts.subscribers < 0 || opts.interval <= 0 || opts.duration <= 0 {
+		log.Fatal("walkers must be at least 1; subscribers, interval and duration must be positive")
+	}
+	if opts.jwtSecret == "" {
+		log.Fatal("jwt-secret is required to authenticate with the service")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{
+		ID:   "loadgen",
+		Role: policy.RoleAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(opts.duration + time.Hour)),
+		},
+	}).SignedString([]byte(opts.jwtSecret))
+	if err != nil {
+		log.Fatalf("Failed to sign token: %v", err)
+	}
+	client := &http.Client{Timeout: 10 * time.Second, Transport: bearerTransport{token: token}}
+
+	log.Printf("Starting %d walk sessions...", opts.walkers)
+	walks := make([]*walk, opts.walkers)
+	for i := range walks {
+		w, err := startWalk(ctx, client, opts.baseURL, opts.terms, i)
+		if err != nil {
+			log.Fatalf("Failed to start walk %d: %v", i, err)
+		}
+		walks[i] = w
+	}
+
+	results := &stats{}
+	var wg sync.WaitGroup
+
+	log.Printf("Connecting %d subscribers...", opts.subscribers)
+	for i := 0; i < opts.subscribers; i++ {
+		w := walks[i%len(walks)]
+		conn, err := subscribe(ctx, client, opts.baseURL, w.sessionID)
+		if err != nil {
+			log.Fatalf("Failed to connect subscriber %d: %v", i, err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readFrames(conn, results)
+		}()
+		go func() {
+			<-ctx.Done()
+			conn.Close()
+		}()
+	}
+
+	log.Printf("Generating load for %v...", opts.duration)
+	runCtx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	var walkers sync.WaitGroup
+	for i, w := range walks {
+		walkers.Add(1)
+		delay := opts.rampUp * time.Duration(i) / time.Duration(len(walks))
+		go func(w *walk) {
+			defer walkers.Done()
+			runWalker(runCtx, client, opts, w, delay, results)
+		}(w)
+	}
+	walkers.Wait()
+
+	// Give the last broadcasts time to arrive before closing subscribers
+	time.Sleep(2 * time.Second)
+	stop()
+	wg.Wait()
+
+	for _, w := range walks {
+		endWalk(client, opts.baseURL, w.sessionID)
+	}
+
+	report(opts, results)
+}
+
+// startWalk records tracking consent and creates a walk session for simulated walker i,
+// starting near a fixed point
+func startWalk(ctx context.Context, client *http.Client, baseURL, termsVersion string, i int) (*walk, error) {
+	bookingID := fmt.Sprintf("loadgen-booking-%d", i)
+	walkerID := fmt.Sprintf("loadgen-walker-%d", i)
+	ownerID := fmt.Sprintf("loadgen-owner-%d", i)
+
+	// Walks only start once both people on the booking have consented to tracking
+	for role, subjectID := range map[string]string{"walker": walkerID, "owner": ownerID} {
+		consent, _ := json.Marshal(map[string]string{"subject_id": subjectID, "role": role, "terms_version": termsVersion})
+		if err := postJSON(ctx, client, baseURL+"/api/v1/bookings/"+bookingID+"/consent", consent, nil); err != nil {
+			return nil, fmt.Errorf("failed to record %s consent: %w", role, err)
+		}
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"booking_id": bookingID,
+		"walker_id":  walkerID,
+		"owner_id":   ownerID,
+	})
+
+	var session struct {
+		ID string `json:"id"`
+	}
+	if err := postJSON(ctx, client, baseURL+"/api/v1/walks", body, &session); err != nil {
+		return nil, err
+	}
+
+	return &walk{
+		sessionID: session.ID,
+		latitude:  40.7128 + rand.Float64()*0.05,
+		longitude: -74.0060 + rand.Float64()*0.05,
+	}, nil
+}
+
+// subscribe issues a connection token for a session and opens a WebSocket with it
+func subscribe(ctx context.Context, client *http.Client, baseURL, sessionID string) (*gorillaws.Conn, error) {
+	body, _ := json.Marshal(map[string]string{"session_id": sessionID})
+
+	var token struct {
+		Token string `json:"token"`
+	}
+	if err := postJSON(ctx, client, baseURL+"/api/v1/location/tokens", body, &token); err != nil {
+		return nil, err
+	}
+
+	wsURL := "ws" + strings.TrimPrefix(baseURL, "http") + "/ws?token=" + url.QueryEscape(token.Token)
+	conn, _, err := gorillaws.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial WebSocket: %w", err)
+	}
+	return conn, nil
+}
+
+// readFrames records the latency of every location frame until the connection closes.
+// Latency is measured from the timestamp the walker stamped on the point, so the generator
+// and the service must see the same clock; run both on hosts synchronised with NTP.
+func readFrames(conn *gorillaws.Conn, results *stats) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		received := time.Now()
+
+		var f frame
+		if err := json.Unmarshal(data, &f); err != nil || f.Control != "" || f.Payload.Timestamp.IsZero() {
+			continue
+		}
+		results.received.Add(1)
+		results.record(received.Sub(f.Payload.Timestamp))
+	}
+}
+
+// runWalker posts a location for w every interval until ctx is done, wandering a few meters each time
+func runWalker(ctx context.Context, client *http.Client, opts options, w *walk, delay time.Duration, results *stats) {
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return
+	}
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	for {
+		w.latitude += (rand.Float64() - 0.5) * 0.0002
+		w.longitude += (rand.Float64() - 0.5) * 0.0002
+		accuracy := 5 + rand.Float64()*10
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"session_id":      w.sessionID,
+			"latitude":        w.latitude,
+			"longitude":       w.longitude,
+			"timestamp":       time.Now().UTC(),
+			"accuracy_meters": accuracy,
+		})
+		if err := postJSON(ctx, client, opts.baseURL+"/api/v1/location/track", body, nil); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			results.postErrors.Add(1)
+		} else {
+			results.posted.Add(1)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// endWalk ends a session created by the run; failures are only logged
+func endWalk(client *http.Client, baseURL, sessionID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := postJSON(ctx, client, baseURL+"/api/v1/walks/"+sessionID+"/end", nil, nil); err != nil {
+		log.Printf("Failed to end walk %s: %v", sessionID, err)
+	}
+}
+
+// postJSON posts body and decodes the response into out, if given
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// report prints throughput and latency percentiles for the run
+func report(opts options, results *stats) {
+	sort.Slice(results.latencies, func(i, j int) bool { return results.latencies[i] < results.latencies[j] })
+
+	fmt.Printf("\nwalkers=%d subscribers=%d interval=%v duration=%v\n", opts.walkers, opts.subscribers, opts.interval, opts.duration)
+	fmt.Printf("locations posted:    %d (%.1f/s), errors: %d\n",
+		results.posted.Load(), float64(results.posted.Load())/opts.duration.Seconds(), results.postErrors.Load())
+	fmt.Printf("broadcasts received: %d (%.1f/s)\n",
+		results.received.Load(), float64(results.received.Load())/opts.duration.Seconds())
+
+	if len(results.latencies) == 0 {
+		fmt.Println("no broadcasts received")
+		return
+	}
+	fmt.Printf("broadcast latency:   p50=%v p90=%v p99=%v p99.9=%v max=%v\n",
+		percentile(results.latencies, 50),
+		percentile(results.latencies, 90),
+		percentile(results.latencies, 99),
+		percentile(results.latencies, 99.9),
+		results.latencies[len(results.latencies)-1])
+}
+
+// percentile returns the p-th percentile of sorted samples using the nearest-rank method
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Microsecond)
+}
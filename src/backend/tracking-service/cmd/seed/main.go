@@ -0,0 +1,67 @@
+// Package main seeds the tracking database with sample walk sessions and synthetic GPS
+// traces. Routes are generated from -seed, so the same seed always draws the same walks.
+// Version: 1.0.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/seed"
+)
+
+// Human Tasks:
+// 1. Run only against development and demo databases; seeded records use predictable IDs
+// 2. Seed an empty database or pick a new -seed value, since session IDs collide when a seed is reused
+
+func main() {
+	var opts seed.Options
+	var startDate string
+	flag.Int64Var(&opts.Seed, "seed", 1, "random seed; IDs are prefixed with it so several seeds can coexist")
+	flag.IntVar(&opts.Walks, "walks", 20, "number of completed walks, spread over the days before the start date")
+	flag.IntVar(&opts.Active, "active", 2, "number of walks left active, with a trace ending now")
+	flag.Float64Var(&opts.Latitude, "lat", 37.7749, "latitude walks start around")
+	flag.Float64Var(&opts.Longitude, "lng", -122.4194, "longitude walks start around")
+	flag.DurationVar(&opts.Interval, "interval", 5*time.Second, "time between GPS points")
+	flag.StringVar(&startDate, "start", time.Now().UTC().Format("2006-01-02"), "completed walks take place in the week before this date (YYYY-MM-DD)")
+	flag.Parse()
+
+	var err error
+	if opts.Start, err = time.Parse("2006-01-02", startDate); err != nil {
+		log.Fatalf("Invalid -start date: %v", err)
+	}
+
+	// Only the database settings are needed, so the full service configuration is not loaded
+	cfg := config.Config{
+		DatabaseURI:        os.Getenv("TRACKING_DB_URI"),
+		BatchSize:          500,
+		BatchFlushInterval: time.Second,
+	}
+	if cfg.DatabaseURI == "" {
+		log.Fatal("TRACKING_DB_URI environment variable is required")
+	}
+	if err := repository.Initialize(cfg); err != nil {
+		log.Fatalf("Failed to initialize MongoDB: %v", err)
+	}
+	if err := repository.EnsureIndexes(context.Background()); err != nil {
+		log.Fatalf("Failed to create MongoDB indexes: %v", err)
+	}
+
+	opts.Now = time.Now()
+	report, err := seed.Run(opts)
+	if err != nil {
+		log.Fatalf("Failed to seed database: %v", err)
+	}
+
+	// Close flushes the buffered points before disconnecting
+	if err := repository.Close(context.Background()); err != nil {
+		log.Fatalf("Failed to flush seeded locations: %v", err)
+	}
+	log.Printf("Seeded %d completed and %d active walk sessions with %d GPS points", report.Walks, report.Active, report.Points)
+}
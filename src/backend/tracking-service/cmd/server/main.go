@@ -4,23 +4,32 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 
+	"src/backend/shared/bootstrap"
+	"src/backend/shared/clients"
+	"src/backend/shared/faults"
+	"src/backend/shared/featureflags"
+	"src/backend/shared/httpmetrics"
+	"src/backend/shared/moderation"
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
 	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/encryption"
+	"src/backend/tracking-service/internal/export"
 	"src/backend/tracking-service/internal/handlers"
 	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
 	"src/backend/tracking-service/internal/websocket"
 )
 
 // Human Tasks:
 // 1. Ensure all required environment variables are set in deployment configuration:
-//    - TRACKING_DB_URI: MongoDB connection string
+//    - TRACKING_DB_URI: MongoDB connection string (not needed with TRACKING_STORE=memory)
 //    - TRACKING_WS_PORT: WebSocket server port
+//    - TRACKING_JWT_SECRET: Required for the admin and data subject endpoints
 // 2. Configure monitoring and alerting for service health metrics
 // 3. Set up proper logging infrastructure in production environment
 // 4. Review and adjust server timeouts based on production requirements
@@ -29,64 +38,244 @@ import (
 func main() {
 	// Initialize logging
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	log.Printf("Starting tracking-service...")
 
 	// Load configuration
 	// Addresses requirement: Scalable microservices architecture
 	// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
 	cfg := config.LoadConfig()
 
-	// Initialize MongoDB connection
-	if err := repository.Initialize(cfg); err != nil {
-		log.Fatalf("Failed to initialize MongoDB: %v", err)
+	// Initialize MongoDB connection, or the in-memory store when running without one
+	if cfg.Store == config.StoreMemory {
+		repository.UseMemoryStore()
+		log.Printf("Using the in-memory store; data will be lost on restart")
+	} else {
+		if err := repository.Initialize(cfg); err != nil {
+			log.Fatalf("Failed to initialize MongoDB: %v", err)
+		}
+		if err := repository.EnsureIndexes(context.Background()); err != nil {
+			log.Fatalf("Failed to create MongoDB indexes: %v", err)
+		}
+	}
+
+	// Encrypt stored coordinates when keys are configured
+	if cfg.LocationKeys != "" {
+		keys, err := encryption.LoadKeyring(context.Background(), cfg.LocationKeys, cfg.LocationKeyID, cfg.LocationKeysKMS)
+		if err != nil {
+			log.Fatalf("Failed to load location encryption keys: %v", err)
+		}
+		repository.UseLocationEncryption(keys)
 	}
-	defer repository.Close()
+
+	// Load feature flag rules so features can be rolled out per tenant or percentage
+	flags, err := featureflags.Init(cfg.FeatureFlags)
+	if err != nil {
+		log.Fatalf("Failed to initialize feature flags: %v", err)
+	}
+
+	// Load the authorization policy shared with the other services
+	if _, err := policy.Init(cfg.Policy); err != nil {
+		log.Fatalf("Failed to initialize authorization policy: %v", err)
+	}
+
+	// Screen chat messages with the configured term lists
+	moderation.Init(cfg.Moderation)
 
 	// Initialize WebSocket hub
 	// Addresses requirement: Real-time location tracking
 	// Location: 1.2 System Overview/High-Level Description/Backend Services
 	hub := websocket.NewHub()
+	hub.SetInstanceID(cfg.InstanceID)
+	hub.ConfigureReplay(cfg.ReplaySize, cfg.ReplayTTL)
+	hub.LimitRates(websocket.RateLimits{
+		SessionRate:    cfg.SessionRate,
+		TenantFanout:   cfg.TenantFanoutRate,
+		ConnectionRate: cfg.ConnectionRate,
+	})
+	hub.LimitConnections(cfg.MaxConnections, cfg.MaxUserConnections)
+	hub.Compress(websocket.Compression{
+		Enabled:   cfg.Compression,
+		Level:     cfg.CompressionLevel,
+		Threshold: cfg.CompressionThreshold,
+	})
+	hub.HandleSlowConsumers(websocket.SlowConsumerPolicy(cfg.SlowConsumerPolicy))
+
+	// Fan broadcasts out across instances so clients need no sticky sessions
+	// Addresses requirement: Scalable microservices architecture
+	// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+	if cfg.RedisURL != "" {
+		backplane, err := websocket.NewRedisBackplane(cfg.RedisURL)
+		if err != nil {
+			log.Fatalf("Failed to initialize Redis backplane: %v", err)
+		}
+		hub.UseBackplane(backplane)
+	}
+
+	// Drop WebSocket frames as fault injection rules ask, when enabled in a test environment
+	var injector *faults.Injector
+	if cfg.FaultInjection {
+		injector = faults.New()
+		hub.InjectFrameDrops(func() bool { return injector.DropFrame("/ws") })
+	}
+
+	// Initialize service layer before the hub starts delivering messages
+	service.Initialize(cfg, hub)
 	go hub.Run()
 
-	// Set up HTTP routes
-	mux := http.NewServeMux()
+	// Finished history exports go to object storage, or local disk in development
+	exportStore, err := export.NewStore(context.Background(), cfg.ExportBucket, cfg.ExportS3Endpoint, cfg.ExportDir, []byte(cfg.TokenSecret))
+	if err != nil {
+		log.Fatalf("Failed to initialize export storage: %v", err)
+	}
+	service.ConfigureExports(exportStore, cfg)
+
+	// Set up HTTP server and routes
+	mux := bootstrap.New("tracking-service", cfg.WebSocketPort).
+		Use(bootstrap.Recover, clients.Tracing, bootstrap.RequestLogger).
+		WithShutdownTimeout(cfg.DrainPeriod + bootstrap.DefaultShutdownTimeout).
+		WithAdminPort(cfg.AdminPort)
+
+	// Inject latency and errors into routes, managed by admins from the admin port
+	if injector != nil {
+		faultRules := auth.Require(cfg.JWTSecret, policy.ResourceFaults, policy.ActionUpdate)(injector.Handler().ServeHTTP)
+		mux.Use(injector.Middleware).
+			HandleAdmin(faults.APIPath, faultRules).
+			HandleAdmin(faults.APIPath+"/", faultRules)
+	}
+
+	// Publish the hub's and the ingest path's internals next to the runtime's on the admin port
+	bootstrap.Publish("hub", func() interface{} { return service.HubStatus() })
+	bootstrap.Publish("location_writer", func() interface{} { return repository.LocationWriterStatus() })
 
 	// Register tracking endpoints
 	mux.HandleFunc("/api/v1/location/track", handlers.TrackLocationHandler)
 	mux.HandleFunc("/api/v1/location/history", handlers.GetLocationHistoryHandler)
+	mux.HandleFunc("/api/v1/location/tokens",
+		auth.Require(cfg.JWTSecret, policy.ResourceLocations, policy.ActionRead)(handlers.IssueConnectionTokenHandler))
+	mux.HandleFunc("/ws", handlers.WebSocketHandler)
 
-	// Create server with configured timeouts
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.WebSocketPort),
-		Handler:      mux,
-		ReadTimeout:  30,  // Adjust based on requirements
-		WriteTimeout: 30,  // Adjust based on requirements
-		IdleTimeout:  120, // Adjust based on requirements
-	}
+	// Register walk session endpoints; walkers start and act on their walks, and the walk's
+	// owners follow it
+	mux.HandleFunc("/api/v1/walks",
+		auth.Require(cfg.JWTSecret, policy.ResourceLocations, policy.ActionCreate)(handlers.StartWalkHandler))
+	mux.HandleFunc("/api/v1/walks/", auth.RequireMethod(cfg.JWTSecret, policy.ResourceLocations)(handlers.WalkHandler))
 
-	// Start server in a goroutine
-	go func() {
-		log.Printf("Starting HTTP server on port %d", cfg.WebSocketPort)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+	// Register walker privacy zone, consent, booking chat, walk evidence and data subject
+	// endpoints; only a booking's owner and walker can consent, chat and read its walk evidence,
+	// as themselves, only walkers see their own zones, and only the other backend services see
+	// where walkers were last or read any booking's walk evidence
+	readMessages := auth.Require(cfg.JWTSecret, policy.ResourceMessages, policy.ActionRead)(handlers.BookingMessagesHandler)
+	sendMessages := auth.Require(cfg.JWTSecret, policy.ResourceMessages, policy.ActionCreate)(handlers.BookingMessagesHandler)
+	bookingConsent := auth.RequireMethod(cfg.JWTSecret, policy.ResourceConsents)(handlers.BookingConsentHandler)
+	walkerPosition := auth.RequireAPIKey(cfg.ServiceAPIKey)(handlers.WalkerPositionHandler)
+	walkerPrivacyZones := auth.RequireMethod(cfg.JWTSecret, policy.ResourcePrivacyZones)(handlers.WalkerPrivacyZonesHandler)
+	mux.HandleFunc("/api/v1/walkers/", func(w http.ResponseWriter, r *http.Request) {
+		if handlers.IsWalkerPositionPath(r.URL.Path) {
+			walkerPosition(w, r)
+			return
 		}
-	}()
+		walkerPrivacyZones(w, r)
+	})
+	walkEvidence := auth.Require(cfg.JWTSecret, policy.ResourceLocations, policy.ActionRead)(handlers.BookingHandler)
+	serviceWalkEvidence := auth.RequireAPIKey(cfg.ServiceAPIKey)(handlers.BookingHandler)
+	mux.HandleFunc("/api/v1/bookings/", func(w http.ResponseWriter, r *http.Request) {
+		if handlers.IsBookingConsentPath(r.URL.Path) {
+			bookingConsent(w, r)
+			return
+		}
+		if !handlers.IsBookingMessagesPath(r.URL.Path) {
+			if r.Header.Get(clients.APIKeyHeader) != "" {
+				serviceWalkEvidence(w, r)
+				return
+			}
+			walkEvidence(w, r)
+			return
+		}
+		if r.Method == http.MethodGet {
+			readMessages(w, r)
+			return
+		}
+		sendMessages(w, r)
+	})
+	mux.HandleFunc("/api/v1/privacy/subjects/",
+		auth.Require(cfg.JWTSecret, policy.ResourceSubjectData, policy.ActionRead)(handlers.SubjectExportHandler))
 
-	// Set up graceful shutdown
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	// Register incident endpoints; users report incidents as themselves and read only their own,
+	// and only admins, who alone may update incidents, move them through the status workflow
+	incidents := auth.RequireMethod(cfg.JWTSecret, policy.ResourceIncidents)
+	mux.HandleFunc("/api/v1/incidents", incidents(handlers.CreateIncidentHandler))
+	mux.HandleFunc("/api/v1/incidents/", incidents(handlers.IncidentHandler))
 
-	// Wait for shutdown signal
-	<-stop
-	log.Printf("Shutting down server...")
+	// Register history export endpoints; users only see the exports they requested, and files
+	// kept on local disk are served through signed, expiring links
+	exportAccess := auth.Require(cfg.JWTSecret, policy.ResourceLocations, policy.ActionRead)
+	mux.HandleFunc("/api/v1/exports/jobs", exportAccess(handlers.CreateExportHandler))
+	mux.HandleFunc("/api/v1/exports/jobs/", exportAccess(handlers.ExportJobHandler))
+	if files, ok := exportStore.(http.Handler); ok {
+		mux.HandleFunc(export.FilesPathPrefix, exportAccess(handlers.ExportFileHandler(files)))
+	}
 
-	// Close all WebSocket connections
-	hub.CloseAllConnections()
+	// Register admin endpoints
+	mux.HandleFunc("/api/v1/admin/instances",
+		auth.Require(cfg.JWTSecret, policy.ResourceInstances, policy.ActionRead)(handlers.InstanceConnectionsHandler))
+	mux.HandleFunc("/api/v1/admin/hub/status",
+		auth.Require(cfg.JWTSecret, policy.ResourceInstances, policy.ActionRead)(handlers.HubStatusHandler))
+	mux.HandleFunc("/api/v1/admin/dispatch/tokens",
+		auth.Require(cfg.JWTSecret, policy.ResourceDispatch, policy.ActionRead)(handlers.IssueDispatchTokenHandler))
+	mux.HandleFunc("/api/v1/admin/incidents",
+		auth.Require(cfg.JWTSecret, policy.ResourceIncidentQueue, policy.ActionRead)(handlers.IncidentQueueHandler))
+	mux.HandleFunc("/api/v1/admin/walkers/nearby",
+		auth.Require(cfg.JWTSecret, policy.ResourceLocationAnalytics, policy.ActionRead)(handlers.NearbyWalkersHandler))
+	mux.HandleFunc("/api/v1/admin/flagged-content",
+		auth.Require(cfg.JWTSecret, policy.ResourceFlaggedContent, policy.ActionRead)(handlers.FlaggedContentHandler))
+	mux.HandleFunc("/api/v1/admin/flagged-content/",
+		auth.Require(cfg.JWTSecret, policy.ResourceFlaggedContent, policy.ActionUpdate)(handlers.FlaggedContentHandler))
+	mux.HandleFunc("/api/v1/admin/heatmap",
+		auth.Require(cfg.JWTSecret, policy.ResourceLocationAnalytics, policy.ActionRead)(handlers.HeatmapHandler))
 
-	// Close MongoDB connection
-	if err := repository.Close(); err != nil {
-		log.Printf("Error closing MongoDB connection: %v", err)
+	// Register synthetic walk endpoints for development
+	if cfg.Simulation {
+		mux.HandleFunc("/api/v1/dev/simulations", handlers.SimulationsHandler)
+		mux.HandleFunc("/api/v1/dev/simulations/", handlers.SimulationsHandler)
+		mux.Go("walk simulations", service.RunSimulations)
 	}
 
-	log.Printf("Server shutdown complete")
-}
\ No newline at end of file
+	// Expose Prometheus metrics
+	mux.Handle("/metrics", httpmetrics.Handler())
+
+	// Keep flag rules from the flag service current
+	if poller, ok := flags.(featureflags.Poller); ok {
+		mux.Go("feature flags", poller.Run)
+	}
+
+	// Keep the service regions walks are tagged with current
+	if cfg.RegionsURL != "" {
+		mux.Go("regions", service.RunRegionRefresh)
+	}
+
+	// Process queued history exports in the background
+	mux.Go("export workers", service.RunExportWorkers)
+
+	// Stream the fleet digest to dispatchers connected to this instance
+	mux.Go("fleet digest", service.RunFleetDigest)
+
+	// Remove booking chat messages past their retention
+	mux.Go("chat retention", service.RunChatRetention)
+
+	// Stop hooks run in reverse: stop the session monitors, drain and close WebSocket connections and
+	// stop the hub, then flush locations and disconnect MongoDB
+	mux.ReadinessCheck("mongodb", repository.Ping)
+	mux.OnStop("mongodb", func(ctx context.Context) error {
+		return repository.Close(ctx)
+	})
+	mux.OnStop("websocket hub", func(ctx context.Context) error {
+		// Ask clients to move to another instance before closing what is left
+		hub.Drain(ctx, cfg.DrainPeriod)
+		hub.CloseAllConnections()
+		return hub.Stop(ctx)
+	})
+	mux.OnStop("session monitors", service.Stop)
+
+	if err := mux.Run(); err != nil {
+		log.Fatalf("tracking-service stopped: %v", err)
+	}
+}
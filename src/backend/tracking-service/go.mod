@@ -14,13 +14,41 @@ require (
 
 	// WebSocket support for real-time communication
 	github.com/gorilla/websocket v1.5.0
+
+	// Redis client for the hub backplane
+	github.com/redis/go-redis/v9 v9.0.2
+
+	// Prometheus client for service metrics
+	github.com/prometheus/client_golang v1.14.0
+
+	// Test assertions, goroutine leak checks and throwaway containers for integration tests
+	github.com/stretchr/testify v1.8.0
+	go.uber.org/goleak v1.2.1
+	github.com/ory/dockertest/v3 v3.10.0
+
+	// Command-line interface for the admin tool
+	github.com/spf13/cobra v1.6.1
+
+	// S3 storage for history exports
+	github.com/aws/aws-sdk-go-v2 v1.21.0
+	github.com/aws/aws-sdk-go-v2/config v1.18.42
+	github.com/aws/aws-sdk-go-v2/feature/s3/manager v1.11.87
+	github.com/aws/aws-sdk-go-v2/service/s3 v1.40.0
+
+	// KMS for unwrapping location encryption keys
+	github.com/aws/aws-sdk-go-v2/service/kms v1.24.6
+
+	// Verification of user tokens issued by the auth-service
+	github.com/golang-jwt/jwt/v4 v4.5.0
 )
 
 require (
 	github.com/golang/snappy v0.0.4 // indirect
+	github.com/inconshreveable/mousetrap v1.0.1 // indirect
 	github.com/klauspost/compress v1.15.12 // indirect
 	github.com/montanaflynn/stats v0.6.6 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
+	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
 	github.com/xdg-go/scram v1.1.1 // indirect
 	github.com/xdg-go/stringprep v1.0.3 // indirect
@@ -0,0 +1,132 @@
+// Package auth authenticates users of the tracking-service's privileged endpoints and checks
+// them against the shared authorization policy
+// Version: 1.0.0
+
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4" // v4.5.0
+
+	"src/backend/shared/clients"
+	"src/backend/shared/policy"
+)
+
+// Claims mirrors the user claims issued by the auth-service
+type Claims struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// contextKey is the type of context keys set by this package
+type contextKey struct{}
+
+// UserFromContext returns the claims of the user authenticated by Require
+func UserFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(contextKey{}).(*Claims)
+	return claims, ok
+}
+
+// Require returns middleware admitting only requests with a valid bearer token signed with
+// secret whose role the policy allows to perform action on resource. Every request is
+// rejected when secret is empty.
+// Addresses requirement: Technical Specification/10.1 Authentication and Authorization
+func Require(secret, resource, action string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			claims, err := parseBearer(r, secret)
+			if err != nil {
+				log.Printf("Authentication failed for %s %s: %v", r.Method, r.URL.Path, err)
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+
+			if !policy.Allowed(r.Context(), claims.Role, resource, action) {
+				log.Printf("Authorization denied for user %s (%s): %s %s", claims.ID, claims.Role, action, resource)
+				http.Error(w, "Insufficient permissions", http.StatusForbidden)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, claims)))
+		}
+	}
+}
+
+// RequireMethod is Require with the action taken from the request method: GET reads, POST
+// creates, PUT and PATCH update and DELETE deletes
+func RequireMethod(secret, resource string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		byAction := map[string]http.HandlerFunc{}
+		for _, action := range []string{policy.ActionRead, policy.ActionCreate, policy.ActionUpdate, policy.ActionDelete} {
+			byAction[action] = Require(secret, resource, action)(next)
+		}
+		return func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead:
+				byAction[policy.ActionRead](w, r)
+			case http.MethodPost:
+				byAction[policy.ActionCreate](w, r)
+			case http.MethodPut, http.MethodPatch:
+				byAction[policy.ActionUpdate](w, r)
+			case http.MethodDelete:
+				byAction[policy.ActionDelete](w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		}
+	}
+}
+
+// RequireAPIKey returns middleware admitting only requests from the other backend services,
+// carrying key in the X-API-Key header. Every request is rejected when key is empty.
+func RequireAPIKey(key string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			provided := r.Header.Get(clients.APIKeyHeader)
+			if key == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(key)) != 1 {
+				log.Printf("API key authentication failed for %s %s", r.Method, r.URL.Path)
+				http.Error(w, "Invalid API key", http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// parseBearer verifies the request's bearer token and returns its claims
+func parseBearer(r *http.Request, secret string) (*Claims, error) {
+	if secret == "" {
+		return nil, errors.New("token verification is not configured")
+	}
+
+	header := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(header, "Bearer ")
+	if header == "" || token == header {
+		return nil, errors.New("no bearer token provided")
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	if claims.ID == "" {
+		return nil, errors.New("token has no user ID")
+	}
+	return claims, nil
+}
@@ -0,0 +1,208 @@
+// Package cli holds the commands of the tracking admin CLI. Maintenance commands work
+// directly against the configured repository for break-glass use; replay-walk drives a running
+// service through its API.
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra" // v1.6.1
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// ReplayWalkCommand replays a stored walk as a new live session on a running service, keeping
+// the original spacing between points so map and notification behaviour can be reproduced
+func ReplayWalkCommand() *cobra.Command {
+	var (
+		baseURL string
+		speed   float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay-walk <session-id>",
+		Short: "Replay a stored walk as a new live session",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if speed <= 0 {
+				return fmt.Errorf("speed must be positive")
+			}
+
+			original, err := repository.FindSessionByID(args[0])
+			if err != nil {
+				return err
+			}
+			route, err := repository.FindLocationsBySession(original.ID)
+			if err != nil {
+				return err
+			}
+			if len(route) == 0 {
+				return fmt.Errorf("session %s has no stored locations", original.ID)
+			}
+
+			client := &http.Client{Timeout: 10 * time.Second}
+			ctx := cmd.Context()
+
+			body, _ := json.Marshal(map[string]string{
+				"booking_id": original.BookingID,
+				"walker_id":  original.WalkerID,
+				"owner_id":   original.OwnerID,
+			})
+			var replay models.Session
+			if err := postJSON(ctx, client, baseURL+"/api/v1/walks", body, &replay); err != nil {
+				return fmt.Errorf("failed to start replay session: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Replaying %d points of %s as session %s\n", len(route), original.ID, replay.ID)
+
+			for i, point := range route {
+				if i > 0 {
+					gap := time.Duration(float64(point.Timestamp.Sub(route[i-1].Timestamp)) / speed)
+					select {
+					case <-time.After(gap):
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+
+				point.SessionID = replay.ID
+				point.Timestamp = time.Now().UTC()
+				body, _ := json.Marshal(point)
+				if err := postJSON(ctx, client, baseURL+"/api/v1/location/track", body, nil); err != nil {
+					return fmt.Errorf("failed to post point %d: %w", i+1, err)
+				}
+			}
+
+			if err := postJSON(ctx, client, baseURL+"/api/v1/walks/"+replay.ID+"/end", nil, nil); err != nil {
+				return fmt.Errorf("failed to end replay session: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Replay session %s ended\n", replay.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&baseURL, "url", "http://localhost:8080", "tracking-service base URL")
+	cmd.Flags().Float64Var(&speed, "speed", 1, "playback speed; 10 replays a walk ten times faster")
+	return cmd
+}
+
+// PurgeLocationsCommand deletes location history older than a cutoff
+func PurgeLocationsCommand() *cobra.Command {
+	var (
+		before string
+		yes    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "purge-locations --before <time>",
+		Short: "Permanently delete location points recorded before a time",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cutoff, err := parseCutoff(before)
+			if err != nil {
+				return err
+			}
+
+			if !yes {
+				fmt.Fprintf(cmd.OutOrStdout(), "Delete every location point recorded before %s? [y/N] ", cutoff.Format(time.RFC3339))
+				answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+				if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+					return fmt.Errorf("aborted")
+				}
+			}
+
+			deleted, err := repository.DeleteLocationsBefore(cutoff)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d location points\n", deleted)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&before, "before", "", "cutoff as an RFC 3339 time or an age such as 2160h (required)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "skip the confirmation prompt")
+	cmd.MarkFlagRequired("before")
+	return cmd
+}
+
+// ReindexCommand creates any missing indexes
+func ReindexCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex",
+		Short: "Create any missing MongoDB indexes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := repository.EnsureIndexes(cmd.Context()); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Indexes are up to date")
+			return nil
+		},
+	}
+}
+
+// RotateLocationKeysCommand re-encrypts stored points under the current key
+func RotateLocationKeysCommand() *cobra.Command {
+	var batchSize int
+
+	cmd := &cobra.Command{
+		Use:   "rotate-location-keys",
+		Short: "Re-encrypt stored coordinates under the current key, encrypting any plain-text points",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if os.Getenv("TRACKING_LOCATION_KEYS") == "" {
+				return fmt.Errorf("TRACKING_LOCATION_KEYS and TRACKING_LOCATION_KEY_ID are required")
+			}
+			rotated, err := repository.RotateLocationKeys(cmd.Context(), batchSize)
+			fmt.Fprintf(cmd.OutOrStdout(), "Re-encrypted %d location points\n", rotated)
+			return err
+		},
+	}
+
+	cmd.Flags().IntVar(&batchSize, "batch-size", 1000, "points read per cursor batch")
+	return cmd
+}
+
+// parseCutoff accepts an absolute RFC 3339 time or a duration measured back from now
+func parseCutoff(value string) (time.Time, error) {
+	if cutoff, err := time.Parse(time.RFC3339, value); err == nil {
+		return cutoff, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age <= 0 {
+		return time.Time{}, fmt.Errorf("invalid --before value %q: use an RFC 3339 time or a positive duration", value)
+	}
+	return time.Now().Add(-age), nil
+}
+
+// postJSON posts body and decodes the response into out, if given
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
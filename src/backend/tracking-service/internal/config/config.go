@@ -7,24 +7,293 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
+
+	"src/backend/shared/clock"
+	"src/backend/shared/faults"
+	"src/backend/shared/featureflags"
+	"src/backend/shared/moderation"
+	"src/backend/shared/policy"
+)
+
+// Store backends selectable with TRACKING_STORE
+const (
+	// StoreMongoDB keeps data in MongoDB; the default
+	StoreMongoDB = "mongodb"
+
+	// StoreMemory keeps data in process memory, for local development and CI without a database
+	StoreMemory = "memory"
 )
 
 // Config holds the configuration settings for the tracking-service
 // Requirement: Scalable microservices architecture
 // Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
 type Config struct {
+	// Store selects the persistence backend: StoreMongoDB or StoreMemory
+	Store string
+
 	// DatabaseURI is the connection string for the MongoDB tracking database
 	DatabaseURI string
 
+	// ReadPreference is the MongoDB read preference mode for reads other than session lifecycle,
+	// which always read from the primary
+	ReadPreference string
+
+	// WriteConcern is the MongoDB write concern, "majority" or a node count, for every write but
+	// location points; walk sessions start and end with it
+	WriteConcern string
+
+	// LocationWriteConcern is the write concern for location points and walker positions, whose
+	// volume makes waiting on a majority costly and any single point cheap to lose
+	LocationWriteConcern string
+
+	// WriteConcernTimeout bounds how long a write waits for its write concern
+	WriteConcernTimeout time.Duration
+
+	// SlowQueryThreshold is how long a MongoDB command may take before it is logged as slow;
+	// negative logs none
+	SlowQueryThreshold time.Duration
+
 	// WebSocketPort is the port number for the WebSocket server
 	WebSocketPort int
+
+	// AdminPort serves pprof profiles and expvar variables, including the hub's internals,
+	// apart from the API; zero serves none
+	AdminPort int
+
+	// FaultInjection enables injecting latency, errors and dropped WebSocket frames into routes
+	// through the admin port; never enable it in production
+	FaultInjection bool
+
+	// BatchSize is the number of location points buffered before a flush to MongoDB
+	BatchSize int
+
+	// BatchFlushInterval is the maximum time a buffered location point waits before being flushed
+	BatchFlushInterval time.Duration
+
+	// TokenSecret signs WebSocket connection tokens; shared by every instance
+	TokenSecret string
+
+	// TokenTTL is how long an issued WebSocket connection token remains valid
+	TokenTTL time.Duration
+
+	// AllowedOrigins lists the browser origins, besides the service's own host, that may open
+	// WebSockets; clients sending no Origin, such as the mobile apps, are always allowed
+	AllowedOrigins []string
+
+	// RedisURL is the connection string for the Redis hub backplane; empty disables it
+	RedisURL string
+
+	// InstanceID identifies this instance in per-instance connection reports
+	InstanceID string
+
+	// ReplaySize is the number of messages kept per subscription for resuming clients
+	ReplaySize int
+
+	// ReplayTTL is how long a message remains available to resuming clients
+	ReplayTTL time.Duration
+
+	// SessionRate is how many messages per second may be published to one walk or chat; 0 is unlimited
+	SessionRate float64
+
+	// TenantFanoutRate is how many client deliveries per second one tenant's messages may fan out to; 0 is unlimited
+	TenantFanoutRate float64
+
+	// ConnectionRate is how many messages per second one WebSocket client may send; 0 is unlimited
+	ConnectionRate float64
+
+	// MaxConnections bounds the WebSocket clients connected to an instance; 0 is unlimited
+	MaxConnections int
+
+	// MaxUserConnections bounds the concurrent WebSocket connections of a single chat
+	// participant; 0 is unlimited
+	MaxUserConnections int
+
+	// Compression enables permessage-deflate for WebSocket clients that negotiate it
+	Compression bool
+
+	// CompressionLevel is the flate level WebSocket messages are compressed at, -2 to 9
+	CompressionLevel int
+
+	// CompressionThreshold is the smallest WebSocket message, in bytes, that is compressed
+	CompressionThreshold int
+
+	// Simulation enables the development endpoints that generate synthetic walks; never enable
+	// it in production
+	Simulation bool
+
+	// CaptureDir is where anonymized ingestion traffic is recorded as replay fixtures; empty
+	// records none
+	CaptureDir string
+
+	// SlowConsumerPolicy is what the hub does with clients whose send buffers are full:
+	// disconnect-with-code, drop-oldest or downgrade-to-digest
+	SlowConsumerPolicy string
+
+	// StaleAfter is how long an active walk may go without location updates before it is reported stale
+	StaleAfter time.Duration
+
+	// NotificationURL is the base URL of the notification-service; empty logs notifications instead
+	NotificationURL string
+
+	// MaxAccuracyMeters is the worst reported accuracy a point may have and still be broadcast live
+	MaxAccuracyMeters float64
+
+	// Smoothing filters the positions broadcast to live subscribers, weighting each point by its
+	// accuracy, to reduce map jitter; stored points keep the reported fix
+	Smoothing bool
+
+	// SmoothingSpeed is how fast, in meters per second, smoothing expects walkers to move; lower
+	// values smooth more but lag behind real movement
+	SmoothingSpeed float64
+
+	// MapMatchingURL is the OSRM base URL used to snap walk routes; empty disables snapping
+	MapMatchingURL string
+
+	// OnCallWebhookURL receives emergency alerts for the on-call admin channel; empty logs them instead
+	OnCallWebhookURL string
+
+	// DrainPeriod is how long WebSocket clients are given to reconnect elsewhere before shutdown
+	DrainPeriod time.Duration
+
+	// FeatureFlags selects where feature flag rules are read from
+	FeatureFlags featureflags.Options
+
+	// ConsentTermsVersion is the tracking terms version walkers and owners must consent to; empty accepts any
+	ConsentTermsVersion string
+
+	// LocationKeys lists the id:base64 keys used to encrypt stored coordinates; empty stores them in plain text
+	LocationKeys string
+
+	// LocationKeyID selects which of LocationKeys new points are encrypted with
+	LocationKeyID string
+
+	// LocationKeysKMS indicates LocationKeys are AWS KMS ciphertexts to be decrypted at startup
+	LocationKeysKMS bool
+
+	// ExportBucket is the S3 bucket finished exports are written to; empty keeps them in ExportDir
+	ExportBucket string
+
+	// ExportS3Endpoint overrides the S3 endpoint, for S3-compatible stores
+	ExportS3Endpoint string
+
+	// ExportDir is the local directory used for exports when no bucket is configured
+	ExportDir string
+
+	// ExportURLTTL is how long an export download link remains valid
+	ExportURLTTL time.Duration
+
+	// ExportWorkers is the number of exports this instance processes at once
+	ExportWorkers int
+
+	// ExportMaxPending is the number of queued or running exports beyond which new ones are refused
+	ExportMaxPending int64
+
+	// JWTSecret verifies user tokens issued by the auth-service; admin endpoints reject every request when empty
+	JWTSecret string
+
+	// ServiceAPIKey authenticates the other backend services reading walkers' positions and walk
+	// evidence; those endpoints reject every service request when empty
+	ServiceAPIKey string
+
+	// Policy selects where authorization rules are read from; the built-in matrix when empty
+	Policy policy.Options
+
+	// RegionsURL is the booking-service region list walks are located in; empty leaves walks untagged
+	RegionsURL string
+
+	// RegionsPollInterval is how often the region list is refreshed
+	RegionsPollInterval time.Duration
+
+	// BookingsURL is the booking-service base URL bookings' owners and walkers are looked up at;
+	// when empty they are known only from the walks started for the booking
+	BookingsURL string
+
+	// BookingsAPIKey is the booking-service API key, with the bookings:read scope, used for the lookups
+	BookingsAPIKey string
+
+	// ChatRetention is how long booking chat messages are kept
+	ChatRetention time.Duration
+
+	// ChatReportRetention is how long a message reported as abusive is kept after the report
+	ChatReportRetention time.Duration
+
+	// Moderation lists the terms that get chat messages blocked or flagged for review
+	Moderation moderation.Options
+
+	// Clock times staleness detection and the fleet's last-seen times; it is not loaded from the
+	// environment, and nil is the system clock
+	Clock clock.Clock
 }
 
 // Human Tasks:
 // 1. Ensure environment variables are set in deployment configuration:
+//    - TRACKING_STORE: Persistence backend, mongodb or memory (default: mongodb; STORE is also read)
 //    - TRACKING_DB_URI: MongoDB connection string with proper credentials
+//    - TRACKING_DB_READ_PREFERENCE: primary, primaryPreferred, secondary, secondaryPreferred or nearest (default: primary)
+//    - TRACKING_DB_WRITE_CONCERN: Write concern for sessions and other records, majority or a node count (default: majority)
+//    - TRACKING_DB_LOCATION_WRITE_CONCERN: Write concern for location points and walker positions (default: 1)
+//    - TRACKING_DB_WRITE_CONCERN_TIMEOUT: How long a write waits for its write concern (default: 5s)
+//    - TRACKING_DB_SLOW_QUERY_THRESHOLD: Duration beyond which commands are logged as slow, negative to log none (default: 500ms)
 //    - TRACKING_WS_PORT: WebSocket server port (default: 8080)
+//    - TRACKING_ADMIN_PORT: Port serving pprof and expvar, kept inside the cluster (default: none)
+//    - TRACKING_FAULT_INJECTION: Enable fault injection rules on the admin port; requires APP_ENV development, staging or test (default: false)
+//    - TRACKING_BATCH_SIZE: Location insert batch size (default: 100)
+//    - TRACKING_BATCH_FLUSH_INTERVAL: Location insert flush interval (default: 1s)
+//    - TRACKING_TOKEN_SECRET: Secret shared by all instances for signing connection tokens
+//    - TRACKING_TOKEN_TTL: Connection token lifetime (default: 15m)
+//    - TRACKING_ALLOWED_ORIGINS: Comma-separated browser origins allowed to open WebSockets, e.g. https://app.example.com (optional)
+//    - TRACKING_REDIS_URL: Redis URL for the hub backplane (required when running more than one replica)
+//    - TRACKING_INSTANCE_ID: Instance identifier (default: hostname)
+//    - TRACKING_REPLAY_SIZE: Messages kept per subscription for resumption (default: 500)
+//    - TRACKING_REPLAY_TTL: Resumption window (default: 2m)
+//    - TRACKING_SESSION_RATE: Messages per second one walk or chat may publish, 0 for unlimited (default: 20)
+//    - TRACKING_TENANT_FANOUT_RATE: Deliveries per second one walker's walks may fan out to, 0 for unlimited (default: 5000)
+//    - TRACKING_CONNECTION_RATE: Messages per second one WebSocket client may send, 0 for unlimited (default: 10)
+//    - TRACKING_MAX_CONNECTIONS: WebSocket clients one instance accepts, 0 for unlimited (default: 10000)
+//    - TRACKING_MAX_USER_CONNECTIONS: Concurrent WebSocket connections per chat participant, 0 for unlimited (default: 5)
+//    - TRACKING_WS_COMPRESSION: Compress WebSocket messages for clients that negotiate permessage-deflate (default: true)
+//    - TRACKING_WS_COMPRESSION_LEVEL: Flate level from -2 (Huffman only) to 9 (default: 1)
+//    - TRACKING_WS_COMPRESSION_THRESHOLD: Smallest WebSocket message compressed, in bytes (default: 256)
+//    - TRACKING_SIMULATION: Enable the synthetic walk endpoints under /api/v1/dev, development only (default: false)
+//    - TRACKING_CAPTURE_DIR: Directory anonymized ingestion traffic is recorded to as replay fixtures (optional)
+//    - TRACKING_SLOW_CONSUMER_POLICY: disconnect-with-code, drop-oldest or downgrade-to-digest (default: disconnect-with-code)
+//    - TRACKING_STALE_AFTER: Silence before an active walk is reported stale (default: 60s)
+//    - TRACKING_NOTIFICATION_URL: notification-service base URL for owner notifications
+//    - TRACKING_MAX_ACCURACY_METERS: Worst accuracy broadcast to subscribers (default: 100)
+//    - TRACKING_SMOOTHING: Smooth positions broadcast to live subscribers (default: false)
+//    - TRACKING_SMOOTHING_SPEED: Walker speed smoothing expects, in meters per second (default: 3)
+//    - TRACKING_MAP_MATCHING_URL: OSRM base URL for route snapping (optional)
+//    - TRACKING_ONCALL_WEBHOOK_URL: Webhook for the on-call admin channel receiving SOS alerts
+//    - TRACKING_DRAIN_PERIOD: Time WebSocket clients get to reconnect elsewhere on shutdown (default: 10s)
+//    - TRACKING_FEATURE_FLAGS_FILE: Env file of FEATURE_* flag rules (optional)
+//    - TRACKING_FEATURE_FLAGS_URL / TRACKING_FEATURE_FLAGS_SDK_KEY: Flag service endpoint and key (optional)
+//    - TRACKING_FEATURE_FLAGS_POLL_INTERVAL: Flag service refresh interval (default: 30s)
+//    - TRACKING_CONSENT_TERMS_VERSION: Current tracking terms version consent must match (optional)
+//    - TRACKING_LOCATION_KEYS: Coordinate encryption keys as id:base64 pairs, comma separated (optional)
+//    - TRACKING_LOCATION_KEY_ID: ID of the key new points are encrypted with (required with keys)
+//    - TRACKING_LOCATION_KEYS_KMS: Set to true when the keys are KMS-encrypted (default: false)
+//    - TRACKING_EXPORT_BUCKET: S3 bucket for history exports (required in production)
+//    - TRACKING_EXPORT_S3_ENDPOINT: S3-compatible endpoint such as MinIO (optional)
+//    - TRACKING_EXPORT_DIR: Local export directory used without a bucket (default: system temp dir)
+//    - TRACKING_EXPORT_URL_TTL: Export download link lifetime (default: 15m)
+//    - TRACKING_EXPORT_WORKERS: Exports processed at once per instance (default: 2; 0 leaves them to other instances)
+//    - TRACKING_EXPORT_MAX_PENDING: Queued exports beyond which new ones are refused (default: 50)
+//    - TRACKING_JWT_SECRET: Secret the auth-service signs user tokens with (JWT_SECRET is also read)
+//    - TRACKING_SERVICE_API_KEY: API key other services read walkers' positions and walk evidence with (optional)
+//    - TRACKING_POLICY_FILE: JSON authorization matrix replacing the built-in one (optional)
+//    - TRACKING_POLICY_OPA_URL / TRACKING_POLICY_OPA_PATH: OPA server and decision path (optional)
+//    - TRACKING_REGIONS_URL: booking-service region list, e.g. http://booking-service/api/v1/regions (optional)
+//    - TRACKING_REGIONS_POLL_INTERVAL: Region list refresh interval (default: 5m)
+//    - TRACKING_BOOKINGS_URL: booking-service base URL bookings' participants are looked up at, e.g. http://booking-service
+//    - TRACKING_BOOKINGS_API_KEY: booking-service API key with the bookings:read scope (required with the URL)
+//    - TRACKING_CHAT_RETENTION: How long booking chat messages are kept (default: 2160h)
+//    - TRACKING_CHAT_REPORT_RETENTION: How long reported chat messages are kept after the report (default: 8760h)
+//    - TRACKING_MODERATION_BLOCKED_TERMS: Comma-separated words or phrases chat messages are rejected for (optional)
+//    - TRACKING_MODERATION_FLAGGED_TERMS: Comma-separated words or phrases chat messages are flagged for review for (optional)
 // 2. Verify MongoDB instance is accessible from the service's network
 // 3. Configure firewall rules to allow WebSocket traffic on the specified port
 // 4. Set up monitoring for the WebSocket server port health
@@ -35,13 +304,69 @@ func LoadConfig() Config {
 	// Initialize config struct
 	config := Config{}
 
+	// Load the persistence backend; the in-memory store needs no database
+	config.Store = os.Getenv("TRACKING_STORE")
+	if config.Store == "" {
+		config.Store = os.Getenv("STORE")
+	}
+	if config.Store == "" {
+		config.Store = StoreMongoDB
+	}
+	if config.Store != StoreMongoDB && config.Store != StoreMemory {
+		log.Fatal(fmt.Sprintf("Invalid TRACKING_STORE value: %s", config.Store))
+	}
+
 	// Load DatabaseURI from environment variable
 	dbURI := os.Getenv("TRACKING_DB_URI")
-	if dbURI == "" {
+	if dbURI == "" && config.Store == StoreMongoDB {
 		log.Fatal("TRACKING_DB_URI environment variable is required")
 	}
 	config.DatabaseURI = dbURI
 
+	// Load read preference and write concerns; points trade durability for throughput
+	config.ReadPreference = "primary"
+	if readPreference := os.Getenv("TRACKING_DB_READ_PREFERENCE"); readPreference != "" {
+		if !validReadPreference(readPreference) {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_DB_READ_PREFERENCE value: %s", readPreference))
+		}
+		config.ReadPreference = readPreference
+	}
+
+	config.WriteConcern = "majority"
+	if writeConcern := os.Getenv("TRACKING_DB_WRITE_CONCERN"); writeConcern != "" {
+		if !validWriteConcern(writeConcern) {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_DB_WRITE_CONCERN value: %s", writeConcern))
+		}
+		config.WriteConcern = writeConcern
+	}
+
+	config.LocationWriteConcern = "1"
+	if writeConcern := os.Getenv("TRACKING_DB_LOCATION_WRITE_CONCERN"); writeConcern != "" {
+		if !validWriteConcern(writeConcern) {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_DB_LOCATION_WRITE_CONCERN value: %s", writeConcern))
+		}
+		config.LocationWriteConcern = writeConcern
+	}
+
+	config.WriteConcernTimeout = 5 * time.Second
+	if writeTimeout := os.Getenv("TRACKING_DB_WRITE_CONCERN_TIMEOUT"); writeTimeout != "" {
+		timeout, err := time.ParseDuration(writeTimeout)
+		if err != nil || timeout <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_DB_WRITE_CONCERN_TIMEOUT value: %s", writeTimeout))
+		}
+		config.WriteConcernTimeout = timeout
+	}
+
+	// Load slow query logging settings; every command's duration is exported regardless
+	config.SlowQueryThreshold = 500 * time.Millisecond
+	if slowThreshold := os.Getenv("TRACKING_DB_SLOW_QUERY_THRESHOLD"); slowThreshold != "" {
+		threshold, err := time.ParseDuration(slowThreshold)
+		if err != nil || threshold == 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_DB_SLOW_QUERY_THRESHOLD value: %s", slowThreshold))
+		}
+		config.SlowQueryThreshold = threshold
+	}
+
 	// Load WebSocketPort from environment variable with default fallback
 	wsPort := os.Getenv("TRACKING_WS_PORT")
 	if wsPort == "" {
@@ -63,9 +388,386 @@ func LoadConfig() Config {
 		config.WebSocketPort = port
 	}
 
+	// Load the admin port profiles and runtime variables are served on
+	if adminPort := os.Getenv("TRACKING_ADMIN_PORT"); adminPort != "" {
+		port, err := strconv.Atoi(adminPort)
+		if err != nil || port < 0 || port > 65535 || port == config.WebSocketPort {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_ADMIN_PORT value: %s", adminPort))
+		}
+		config.AdminPort = port
+	}
+
+	// Load write-behind batching settings for location inserts
+	config.BatchSize = 100
+	if batchSize := os.Getenv("TRACKING_BATCH_SIZE"); batchSize != "" {
+		size, err := strconv.Atoi(batchSize)
+		if err != nil || size < 1 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_BATCH_SIZE value: %s", batchSize))
+		}
+		config.BatchSize = size
+	}
+
+	config.BatchFlushInterval = time.Second
+	if flushInterval := os.Getenv("TRACKING_BATCH_FLUSH_INTERVAL"); flushInterval != "" {
+		interval, err := time.ParseDuration(flushInterval)
+		if err != nil || interval <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_BATCH_FLUSH_INTERVAL value: %s", flushInterval))
+		}
+		config.BatchFlushInterval = interval
+	}
+
+	// Load WebSocket connection token settings
+	config.TokenSecret = os.Getenv("TRACKING_TOKEN_SECRET")
+	if config.TokenSecret == "" {
+		log.Fatal("TRACKING_TOKEN_SECRET environment variable is required")
+	}
+
+	config.TokenTTL = 15 * time.Minute
+	if tokenTTL := os.Getenv("TRACKING_TOKEN_TTL"); tokenTTL != "" {
+		ttl, err := time.ParseDuration(tokenTTL)
+		if err != nil || ttl <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_TOKEN_TTL value: %s", tokenTTL))
+		}
+		config.TokenTTL = ttl
+	}
+
+	for _, origin := range strings.Split(os.Getenv("TRACKING_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			config.AllowedOrigins = append(config.AllowedOrigins, origin)
+		}
+	}
+
+	// Load horizontal scaling settings
+	config.RedisURL = os.Getenv("TRACKING_REDIS_URL")
+	config.InstanceID = os.Getenv("TRACKING_INSTANCE_ID")
+	if config.InstanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.Fatal(fmt.Sprintf("TRACKING_INSTANCE_ID not set and hostname unavailable: %v", err))
+		}
+		config.InstanceID = hostname
+	}
+
+	// Load session resumption settings
+	config.ReplaySize = 500
+	if replaySize := os.Getenv("TRACKING_REPLAY_SIZE"); replaySize != "" {
+		size, err := strconv.Atoi(replaySize)
+		if err != nil || size < 1 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_REPLAY_SIZE value: %s", replaySize))
+		}
+		config.ReplaySize = size
+	}
+
+	config.ReplayTTL = 2 * time.Minute
+	if replayTTL := os.Getenv("TRACKING_REPLAY_TTL"); replayTTL != "" {
+		ttl, err := time.ParseDuration(replayTTL)
+		if err != nil || ttl <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_REPLAY_TTL value: %s", replayTTL))
+		}
+		config.ReplayTTL = ttl
+	}
+
+	// Load hub rate limits
+	config.SessionRate = parseRate("TRACKING_SESSION_RATE", 20)
+	config.TenantFanoutRate = parseRate("TRACKING_TENANT_FANOUT_RATE", 5000)
+	config.ConnectionRate = parseRate("TRACKING_CONNECTION_RATE", 10)
+
+	// Load connection limits
+	config.MaxConnections = parseLimit("TRACKING_MAX_CONNECTIONS", 10000)
+	config.MaxUserConnections = parseLimit("TRACKING_MAX_USER_CONNECTIONS", 5)
+
+	// Load WebSocket compression settings; small messages are sent as they are
+	config.Compression = true
+	if compression := os.Getenv("TRACKING_WS_COMPRESSION"); compression != "" {
+		enabled, err := strconv.ParseBool(compression)
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_WS_COMPRESSION value: %s", compression))
+		}
+		config.Compression = enabled
+	}
+
+	config.CompressionLevel = 1
+	if level := os.Getenv("TRACKING_WS_COMPRESSION_LEVEL"); level != "" {
+		parsed, err := strconv.Atoi(level)
+		if err != nil || parsed < -2 || parsed > 9 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_WS_COMPRESSION_LEVEL value: %s", level))
+		}
+		config.CompressionLevel = parsed
+	}
+	config.CompressionThreshold = parseLimit("TRACKING_WS_COMPRESSION_THRESHOLD", 256)
+
+	// Load the development simulation switch
+	if simulation := os.Getenv("TRACKING_SIMULATION"); simulation != "" {
+		enabled, err := strconv.ParseBool(simulation)
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_SIMULATION value: %s", simulation))
+		}
+		config.Simulation = enabled
+	}
+	if config.Simulation {
+		log.Printf("WARNING: walk simulation is enabled; synthetic walks can be started without authentication")
+	}
+
+	// Load the fault injection switch, which is refused in production
+	if faultInjection := os.Getenv("TRACKING_FAULT_INJECTION"); faultInjection != "" {
+		enabled, err := strconv.ParseBool(faultInjection)
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_FAULT_INJECTION value: %s", faultInjection))
+		}
+		config.FaultInjection = enabled
+	}
+	if config.FaultInjection {
+		if err := faults.CheckEnvironment(); err != nil {
+			log.Fatal(err)
+		}
+		if config.AdminPort == 0 {
+			log.Fatal("TRACKING_FAULT_INJECTION requires TRACKING_ADMIN_PORT to be set")
+		}
+		log.Printf("WARNING: fault injection is enabled; rules on the admin port can fail and slow requests")
+	}
+
+	// Load where ingestion traffic is captured for replay fixtures
+	config.CaptureDir = os.Getenv("TRACKING_CAPTURE_DIR")
+	if config.CaptureDir != "" {
+		log.Printf("Capturing anonymized ingestion traffic to %s", config.CaptureDir)
+	}
+
+	// Load the policy for clients that cannot keep up with their stream
+	config.SlowConsumerPolicy = "disconnect-with-code"
+	if policy := os.Getenv("TRACKING_SLOW_CONSUMER_POLICY"); policy != "" {
+		if policy != "disconnect-with-code" && policy != "drop-oldest" && policy != "downgrade-to-digest" {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_SLOW_CONSUMER_POLICY value: %s", policy))
+		}
+		config.SlowConsumerPolicy = policy
+	}
+
+	// Load staleness monitoring settings
+	config.StaleAfter = 60 * time.Second
+	if staleAfter := os.Getenv("TRACKING_STALE_AFTER"); staleAfter != "" {
+		threshold, err := time.ParseDuration(staleAfter)
+		if err != nil || threshold <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_STALE_AFTER value: %s", staleAfter))
+		}
+		config.StaleAfter = threshold
+	}
+
+	config.NotificationURL = os.Getenv("TRACKING_NOTIFICATION_URL")
+
+	// Load location quality settings
+	config.MaxAccuracyMeters = 100
+	if maxAccuracy := os.Getenv("TRACKING_MAX_ACCURACY_METERS"); maxAccuracy != "" {
+		accuracy, err := strconv.ParseFloat(maxAccuracy, 64)
+		if err != nil || accuracy <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_MAX_ACCURACY_METERS value: %s", maxAccuracy))
+		}
+		config.MaxAccuracyMeters = accuracy
+	}
+	if smoothing := os.Getenv("TRACKING_SMOOTHING"); smoothing != "" {
+		enabled, err := strconv.ParseBool(smoothing)
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_SMOOTHING value: %s", smoothing))
+		}
+		config.Smoothing = enabled
+	}
+	config.SmoothingSpeed = parseRate("TRACKING_SMOOTHING_SPEED", 3)
+	if config.SmoothingSpeed == 0 {
+		log.Fatal("Invalid TRACKING_SMOOTHING_SPEED value: 0")
+	}
+
+	config.MapMatchingURL = os.Getenv("TRACKING_MAP_MATCHING_URL")
+
+	config.OnCallWebhookURL = os.Getenv("TRACKING_ONCALL_WEBHOOK_URL")
+	if config.OnCallWebhookURL == "" {
+		log.Printf("TRACKING_ONCALL_WEBHOOK_URL is not set; SOS alerts will only be logged")
+	}
+
+	// Load shutdown draining settings
+	config.DrainPeriod = 10 * time.Second
+	if drainPeriod := os.Getenv("TRACKING_DRAIN_PERIOD"); drainPeriod != "" {
+		period, err := time.ParseDuration(drainPeriod)
+		if err != nil || period < 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_DRAIN_PERIOD value: %s", drainPeriod))
+		}
+		config.DrainPeriod = period
+	}
+
+	// Load feature flag source settings
+	config.FeatureFlags = featureflags.Options{
+		File:         os.Getenv("TRACKING_FEATURE_FLAGS_FILE"),
+		URL:          os.Getenv("TRACKING_FEATURE_FLAGS_URL"),
+		SDKKey:       os.Getenv("TRACKING_FEATURE_FLAGS_SDK_KEY"),
+		PollInterval: 30 * time.Second,
+	}
+	if pollInterval := os.Getenv("TRACKING_FEATURE_FLAGS_POLL_INTERVAL"); pollInterval != "" {
+		interval, err := time.ParseDuration(pollInterval)
+		if err != nil || interval <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_FEATURE_FLAGS_POLL_INTERVAL value: %s", pollInterval))
+		}
+		config.FeatureFlags.PollInterval = interval
+	}
+
+	// Load consent settings; when set, consent to older terms no longer allows tracking
+	config.ConsentTermsVersion = os.Getenv("TRACKING_CONSENT_TERMS_VERSION")
+
+	// Load location encryption settings; the keys themselves are checked when the keyring is built
+	config.LocationKeys = os.Getenv("TRACKING_LOCATION_KEYS")
+	config.LocationKeyID = os.Getenv("TRACKING_LOCATION_KEY_ID")
+	if config.LocationKeys != "" && config.LocationKeyID == "" {
+		log.Fatal("TRACKING_LOCATION_KEY_ID is required when TRACKING_LOCATION_KEYS is set")
+	}
+	if useKMS := os.Getenv("TRACKING_LOCATION_KEYS_KMS"); useKMS != "" {
+		enabled, err := strconv.ParseBool(useKMS)
+		if err != nil {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_LOCATION_KEYS_KMS value: %s", useKMS))
+		}
+		config.LocationKeysKMS = enabled
+	}
+
+	// Load history export settings
+	config.ExportBucket = os.Getenv("TRACKING_EXPORT_BUCKET")
+	config.ExportS3Endpoint = os.Getenv("TRACKING_EXPORT_S3_ENDPOINT")
+	config.ExportDir = os.Getenv("TRACKING_EXPORT_DIR")
+	if config.ExportDir == "" {
+		config.ExportDir = filepath.Join(os.TempDir(), "tracking-exports")
+	}
+	if config.ExportBucket == "" {
+		log.Printf("TRACKING_EXPORT_BUCKET is not set; exports will be stored in %s", config.ExportDir)
+	}
+
+	config.ExportURLTTL = 15 * time.Minute
+	if urlTTL := os.Getenv("TRACKING_EXPORT_URL_TTL"); urlTTL != "" {
+		ttl, err := time.ParseDuration(urlTTL)
+		if err != nil || ttl <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_EXPORT_URL_TTL value: %s", urlTTL))
+		}
+		config.ExportURLTTL = ttl
+	}
+
+	config.ExportWorkers = 2
+	if workers := os.Getenv("TRACKING_EXPORT_WORKERS"); workers != "" {
+		count, err := strconv.Atoi(workers)
+		if err != nil || count < 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_EXPORT_WORKERS value: %s", workers))
+		}
+		config.ExportWorkers = count
+	}
+
+	config.ExportMaxPending = 50
+	if maxPending := os.Getenv("TRACKING_EXPORT_MAX_PENDING"); maxPending != "" {
+		limit, err := strconv.ParseInt(maxPending, 10, 64)
+		if err != nil || limit < 1 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_EXPORT_MAX_PENDING value: %s", maxPending))
+		}
+		config.ExportMaxPending = limit
+	}
+
+	// Load authentication and authorization settings
+	config.JWTSecret = os.Getenv("TRACKING_JWT_SECRET")
+	if config.JWTSecret == "" {
+		config.JWTSecret = os.Getenv("JWT_SECRET")
+	}
+	config.ServiceAPIKey = os.Getenv("TRACKING_SERVICE_API_KEY")
+	config.Policy = policy.Options{
+		File:    os.Getenv("TRACKING_POLICY_FILE"),
+		OPAURL:  os.Getenv("TRACKING_POLICY_OPA_URL"),
+		OPAPath: os.Getenv("TRACKING_POLICY_OPA_PATH"),
+	}
+
+	// Load service region settings; walks are tagged with the region their points fall in
+	config.RegionsURL = os.Getenv("TRACKING_REGIONS_URL")
+	config.RegionsPollInterval = 5 * time.Minute
+	if pollInterval := os.Getenv("TRACKING_REGIONS_POLL_INTERVAL"); pollInterval != "" {
+		interval, err := time.ParseDuration(pollInterval)
+		if err != nil || interval <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_REGIONS_POLL_INTERVAL value: %s", pollInterval))
+		}
+		config.RegionsPollInterval = interval
+	}
+
+	// Load the booking-service lookup of bookings' owners and walkers, who alone may chat about
+	// a booking
+	config.BookingsURL = os.Getenv("TRACKING_BOOKINGS_URL")
+	config.BookingsAPIKey = os.Getenv("TRACKING_BOOKINGS_API_KEY")
+	if config.BookingsURL != "" && config.BookingsAPIKey == "" {
+		log.Fatal("TRACKING_BOOKINGS_URL requires TRACKING_BOOKINGS_API_KEY to be set")
+	}
+
+	// Load chat retention settings; reported messages are kept longer as evidence
+	config.ChatRetention = 90 * 24 * time.Hour
+	if chatRetention := os.Getenv("TRACKING_CHAT_RETENTION"); chatRetention != "" {
+		retention, err := time.ParseDuration(chatRetention)
+		if err != nil || retention <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_CHAT_RETENTION value: %s", chatRetention))
+		}
+		config.ChatRetention = retention
+	}
+
+	config.ChatReportRetention = 365 * 24 * time.Hour
+	if reportRetention := os.Getenv("TRACKING_CHAT_REPORT_RETENTION"); reportRetention != "" {
+		retention, err := time.ParseDuration(reportRetention)
+		if err != nil || retention <= 0 {
+			log.Fatal(fmt.Sprintf("Invalid TRACKING_CHAT_REPORT_RETENTION value: %s", reportRetention))
+		}
+		config.ChatReportRetention = retention
+	}
+
+	// Load the moderation term lists; without them every message is allowed
+	config.Moderation = moderation.Options{
+		BlockedTerms: moderation.ParseTerms(os.Getenv("TRACKING_MODERATION_BLOCKED_TERMS")),
+		FlaggedTerms: moderation.ParseTerms(os.Getenv("TRACKING_MODERATION_FLAGGED_TERMS")),
+	}
+
 	// Log the loaded configuration (excluding sensitive information)
-	log.Printf("Configuration loaded - WebSocket Port: %d", config.WebSocketPort)
-	// Note: DatabaseURI is intentionally not logged to prevent credential exposure
+	log.Printf("Configuration loaded - Store: %s, WebSocket Port: %d, Batch Size: %d, Batch Flush Interval: %v, Instance: %s, Backplane: %t",
+		config.Store, config.WebSocketPort, config.BatchSize, config.BatchFlushInterval, config.InstanceID, config.RedisURL != "")
+	log.Printf("Database - Read Preference: %s, Write Concern: %s, Location Write Concern: %s",
+		config.ReadPreference, config.WriteConcern, config.LocationWriteConcern)
+	// Note: DatabaseURI, RedisURL, TokenSecret, JWTSecret, ServiceAPIKey, BookingsAPIKey and LocationKeys are intentionally not logged to prevent credential exposure
 
 	return config
-}
\ No newline at end of file
+}
+
+// parseRate reads a per-second rate from the named environment variable, falling back to def
+func parseRate(name string, def float64) float64 {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 {
+		log.Fatal(fmt.Sprintf("Invalid %s value: %s", name, value))
+	}
+	return rate
+}
+
+// parseLimit reads a count limit from the named environment variable, falling back to def
+func parseLimit(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		log.Fatal(fmt.Sprintf("Invalid %s value: %s", name, value))
+	}
+	return limit
+}
+
+// validReadPreference reports whether mode names a MongoDB read preference mode
+func validReadPreference(mode string) bool {
+	switch strings.ToLower(mode) {
+	case "primary", "primarypreferred", "secondary", "secondarypreferred", "nearest":
+		return true
+	}
+	return false
+}
+
+// validWriteConcern reports whether w is "majority" or a count of at least one node; writes
+// must be acknowledged so duplicate points are still detected
+func validWriteConcern(w string) bool {
+	if w == "majority" {
+		return true
+	}
+	nodes, err := strconv.Atoi(w)
+	return err == nil && nodes >= 1
+}
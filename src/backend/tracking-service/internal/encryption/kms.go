@@ -0,0 +1,48 @@
+// Package encryption provides field-level encryption for sensitive values stored by the
+// tracking-service
+// Version: 1.0.0
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config" // v1.18.42
+	"github.com/aws/aws-sdk-go-v2/service/kms"      // v1.24.6
+)
+
+// UnwrapWithKMS decrypts data keys that were encrypted with AWS KMS, so only ciphertext
+// copies of the keys need to be kept in configuration
+func UnwrapWithKMS(ctx context.Context, wrapped map[string][]byte) (map[string][]byte, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	client := kms.NewFromConfig(cfg)
+
+	keys := make(map[string][]byte, len(wrapped))
+	for id, blob := range wrapped {
+		out, err := client.Decrypt(ctx, &kms.DecryptInput{CiphertextBlob: blob})
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap key %q: %w", id, err)
+		}
+		keys[id] = out.Plaintext
+	}
+	return keys, nil
+}
+
+// LoadKeyring builds a Keyring from an id:base64 key list, unwrapping the keys with KMS first
+// when useKMS is set
+func LoadKeyring(ctx context.Context, spec, currentID string, useKMS bool) (*Keyring, error) {
+	keys, err := ParseKeys(spec)
+	if err != nil {
+		return nil, err
+	}
+	if useKMS {
+		if keys, err = UnwrapWithKMS(ctx, keys); err != nil {
+			return nil, err
+		}
+	}
+	return NewKeyring(currentID, keys)
+}
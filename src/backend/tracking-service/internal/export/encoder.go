@@ -0,0 +1,237 @@
+// Package export writes location history to files and stores them for download
+// Version: 1.0.0
+
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// Encoder writes location points to a file one at a time, in session then time order
+type Encoder interface {
+	// Write appends one point
+	Write(location models.Location) error
+
+	// Close writes anything the format needs after the last point. It does not close
+	// the underlying writer.
+	Close() error
+}
+
+// NewEncoder returns an Encoder writing format to w
+func NewEncoder(format models.ExportFormat, w io.Writer) (Encoder, error) {
+	switch format {
+	case models.ExportFormatCSV:
+		return newCSVEncoder(w)
+	case models.ExportFormatGeoJSON:
+		return newGeoJSONEncoder(w)
+	case models.ExportFormatGPX:
+		return newGPXEncoder(w)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// FileExtension returns the usual file name extension for format
+func FileExtension(format models.ExportFormat) string {
+	if format == models.ExportFormatGeoJSON {
+		return "geojson"
+	}
+	return string(format)
+}
+
+// csvEncoder writes one row per point; optional device fields are left empty when unknown, and
+// points recorded while the walk was paused are flagged in the last column
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) (*csvEncoder, error) {
+	e := &csvEncoder{w: csv.NewWriter(w)}
+	header := []string{"session_id", "timestamp", "latitude", "longitude", "accuracy_meters", "altitude", "speed", "heading", "battery_percent", "paused"}
+	if err := e.w.Write(header); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *csvEncoder) Write(l models.Location) error {
+	return e.w.Write([]string{
+		l.SessionID,
+		l.Timestamp.UTC().Format(time.RFC3339Nano),
+		formatFloat(l.Latitude),
+		formatFloat(l.Longitude),
+		formatOptional(l.AccuracyMeters),
+		formatOptional(l.Altitude),
+		formatOptional(l.Speed),
+		formatOptional(l.Heading),
+		formatOptional(l.BatteryPercent),
+		strconv.FormatBool(l.Paused),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// geoJSONEncoder streams a FeatureCollection with one Point feature per location
+type geoJSONEncoder struct {
+	w     io.Writer
+	count int
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   geoJSONPoint      `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	SessionID      string    `json:"session_id"`
+	Timestamp      time.Time `json:"timestamp"`
+	AccuracyMeters *float64  `json:"accuracy_meters,omitempty"`
+	Speed          *float64  `json:"speed,omitempty"`
+	Heading        *float64  `json:"heading,omitempty"`
+	BatteryPercent *float64  `json:"battery_percent,omitempty"`
+	Paused         bool      `json:"paused,omitempty"`
+}
+
+func newGeoJSONEncoder(w io.Writer) (*geoJSONEncoder, error) {
+	if _, err := io.WriteString(w, `{"type":"FeatureCollection","features":[`); err != nil {
+		return nil, err
+	}
+	return &geoJSONEncoder{w: w}, nil
+}
+
+func (e *geoJSONEncoder) Write(l models.Location) error {
+	// GeoJSON positions are longitude first, with altitude as an optional third element
+	coordinates := []float64{l.Longitude, l.Latitude}
+	if l.Altitude != nil {
+		coordinates = append(coordinates, *l.Altitude)
+	}
+
+	feature, err := json.Marshal(geoJSONFeature{
+		Type:     "Feature",
+		Geometry: geoJSONPoint{Type: "Point", Coordinates: coordinates},
+		Properties: geoJSONProperties{
+			SessionID:      l.SessionID,
+			Timestamp:      l.Timestamp.UTC(),
+			AccuracyMeters: l.AccuracyMeters,
+			Speed:          l.Speed,
+			Heading:        l.Heading,
+			BatteryPercent: l.BatteryPercent,
+			Paused:         l.Paused,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(feature)
+	return err
+}
+
+func (e *geoJSONEncoder) Close() error {
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+// gpxEncoder writes a GPX 1.1 document with one track per walk session. A new track segment
+// starts whenever the walker pauses or resumes, and points recorded while paused have the
+// type "paused", so apps measuring the track can leave the pauses out.
+type gpxEncoder struct {
+	w       io.Writer
+	session string
+	paused  bool
+	open    bool
+}
+
+func newGPXEncoder(w io.Writer) (*gpxEncoder, error) {
+	_, err := io.WriteString(w, xml.Header+`<gpx version="1.1" creator="tracking-service" xmlns="http://www.topografix.com/GPX/1/1">`+"\n")
+	if err != nil {
+		return nil, err
+	}
+	return &gpxEncoder{w: w}, nil
+}
+
+func (e *gpxEncoder) Write(l models.Location) error {
+	if !e.open || l.SessionID != e.session {
+		if err := e.closeTrack(); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.w, "<trk><name>"); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(e.w, []byte(l.SessionID)); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(e.w, "</name><trkseg>\n"); err != nil {
+			return err
+		}
+		e.session, e.paused, e.open = l.SessionID, l.Paused, true
+	} else if l.Paused != e.paused {
+		if _, err := io.WriteString(e.w, "</trkseg><trkseg>\n"); err != nil {
+			return err
+		}
+		e.paused = l.Paused
+	}
+
+	point := fmt.Sprintf(`<trkpt lat="%s" lon="%s">`, formatFloat(l.Latitude), formatFloat(l.Longitude))
+	if l.Altitude != nil {
+		point += "<ele>" + formatFloat(*l.Altitude) + "</ele>"
+	}
+	point += "<time>" + l.Timestamp.UTC().Format(time.RFC3339Nano) + "</time>"
+	if l.Paused {
+		point += "<type>paused</type>"
+	}
+	point += "</trkpt>\n"
+	_, err := io.WriteString(e.w, point)
+	return err
+}
+
+func (e *gpxEncoder) Close() error {
+	if err := e.closeTrack(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "</gpx>\n")
+	return err
+}
+
+// closeTrack ends the current track, if one is open
+func (e *gpxEncoder) closeTrack() error {
+	if !e.open {
+		return nil
+	}
+	e.open = false
+	_, err := io.WriteString(e.w, "</trkseg></trk>\n")
+	return err
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatOptional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v)
+}
@@ -0,0 +1,39 @@
+// Package export writes location history to files and stores them for download
+// Version: 1.0.0
+
+package export
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Human Tasks:
+// 1. Create the export bucket and grant the service role s3:PutObject and s3:GetObject on it
+// 2. Add a bucket lifecycle rule expiring objects under exports/ after a few days
+// 3. Without a bucket, exports are kept on local disk; use that only for development
+
+// FilesPathPrefix is where the local file store serves finished exports
+const FilesPathPrefix = "/api/v1/exports/files/"
+
+// Store keeps finished export files and hands out links to download them.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type Store interface {
+	// Put stores everything read from body under key. body is read as it is produced,
+	// so a slow store slows the export down rather than letting it buffer.
+	Put(ctx context.Context, key, contentType string, body io.Reader) error
+
+	// URL returns a link to download key, valid for at least ttl
+	URL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// NewStore returns an S3 store when bucket is set and a local file store under dir, signing
+// its links with secret, otherwise. endpoint overrides the S3 endpoint, for S3-compatible
+// stores such as MinIO.
+func NewStore(ctx context.Context, bucket, endpoint, dir string, secret []byte) (Store, error) {
+	if bucket != "" {
+		return NewS3Store(ctx, bucket, endpoint)
+	}
+	return NewFileStore(dir, secret)
+}
@@ -0,0 +1,113 @@
+// Package export writes location history to files and stores them for download
+// Version: 1.0.0
+
+package export
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileStore keeps exports on local disk and serves them itself, through links signed with a
+// secret that expire like S3's presigned URLs. It is not shared between instances, so it is
+// only suitable for development.
+type FileStore struct {
+	dir    string
+	secret []byte
+}
+
+// NewFileStore creates a FileStore writing under dir and signing its links with secret
+func NewFileStore(dir string, secret []byte) (*FileStore, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("a secret to sign export links is required")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return &FileStore{dir: dir, secret: secret}, nil
+}
+
+// Put writes body to a temporary file and renames it into place once complete, so a
+// partially written export is never served
+func (s *FileStore) Put(ctx context.Context, key, contentType string, body io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// URL returns the path FileStore serves key under, signed to stay valid for ttl
+func (s *FileStore) URL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	return FilesPathPrefix + key + "?expires=" + expires + "&signature=" + s.sign(key, expires), nil
+}
+
+// ServeHTTP serves stored exports under FilesPathPrefix to requests with an unexpired link
+// signed for the file
+func (s *FileStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, FilesPathPrefix)
+	expires := r.URL.Query().Get("expires")
+	deadline, err := strconv.ParseInt(expires, 10, 64)
+	signature := []byte(r.URL.Query().Get("signature"))
+	if err != nil || !hmac.Equal(signature, []byte(s.sign(key, expires))) || time.Now().Unix() > deadline {
+		http.Error(w, "Invalid or expired download link", http.StatusForbidden)
+		return
+	}
+
+	path, err := s.path(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, path)
+}
+
+// sign returns the signature of a link to key expiring at the Unix time expires
+func (s *FileStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps key to a file under the store's directory, rejecting keys that would escape it
+func (s *FileStore) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid export key %q", key)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
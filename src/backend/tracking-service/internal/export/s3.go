@@ -0,0 +1,71 @@
+// Package export writes location history to files and stores them for download
+// Version: 1.0.0
+
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"                // v1.21.0
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"   // v1.18.42
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager" // v1.11.87
+	"github.com/aws/aws-sdk-go-v2/service/s3"         // v1.40.0
+)
+
+// S3Store keeps exports in an S3 bucket and links to them with presigned URLs
+type S3Store struct {
+	bucket    string
+	uploader  *manager.Uploader
+	presigner *s3.PresignClient
+}
+
+// NewS3Store creates an S3Store for bucket using the default AWS credential chain.
+// A non-empty endpoint selects an S3-compatible service with path-style addressing.
+func NewS3Store(ctx context.Context, bucket, endpoint string) (*S3Store, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &S3Store{
+		bucket:    bucket,
+		uploader:  manager.NewUploader(client),
+		presigner: s3.NewPresignClient(client),
+	}, nil
+}
+
+// Put uploads body in parts as it is read, so exports of any size use bounded memory
+func (s *S3Store) Put(ctx context.Context, key, contentType string, body io.Reader) error {
+	_, err := s.uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload export: %w", err)
+	}
+	return nil
+}
+
+// URL presigns a GET request for key valid for ttl
+func (s *S3Store) URL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	req, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign export URL: %w", err)
+	}
+	return req.URL, nil
+}
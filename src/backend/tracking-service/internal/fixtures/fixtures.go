@@ -0,0 +1,187 @@
+// Package fixtures records anonymized ingestion traffic as walk fixtures and loads them back,
+// so tests and benchmarks can replay real walks through the ingestion pipeline
+// Version: 1.0.0
+
+package fixtures
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// Human Tasks:
+// 1. Capture only on instances whose walkers have agreed to diagnostics; fixtures keep the
+//    shape and timing of real walks even though they are moved and renamed
+// 2. Review captured fixtures before committing them to the repository
+
+// FileExtension is the extension of fixture files; each holds one JSON point per line
+const FileExtension = ".jsonl"
+
+// maxCapturedWalks bounds the walks a Recorder captures, so a forgotten capture cannot fill
+// the disk
+const maxCapturedWalks = 100
+
+// metersPerDegree is the length of one degree of latitude
+const metersPerDegree = 111320.0
+
+// Epoch is the time the first point of every fixture is moved to
+var Epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Recorder captures the points of walks as they are ingested, one fixture file per walk.
+// Points are written in arrival order, so replaying a fixture reproduces retries and points
+// arriving out of order. Each walk is anonymized as it is written:
+//   - the session ID is replaced by a salted hash, so it cannot be matched to the walk
+//   - the walk is moved to start at latitude and longitude zero, keeping the distances
+//     between its points
+//   - timestamps are moved so the walk starts at Epoch, keeping the time between points
+//   - altitude is dropped
+type Recorder struct {
+	dir  string
+	salt []byte
+
+	mu    sync.Mutex
+	walks map[string]*capturedWalk
+	full  bool
+}
+
+// capturedWalk is a walk's anonymous name, where it is being captured and the first point it
+// is anonymized against
+type capturedWalk struct {
+	name  string
+	path  string
+	first models.Location
+}
+
+// NewRecorder creates a Recorder writing fixtures under dir
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create capture directory: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate capture salt: %w", err)
+	}
+	return &Recorder{dir: dir, salt: salt, walks: make(map[string]*capturedWalk)}, nil
+}
+
+// Record appends an anonymized copy of an ingested point to its walk's fixture. Points
+// without a session are not captured. Capture is best effort: failures are logged, never
+// returned, so they cannot affect ingestion. A nil Recorder records nothing.
+func (r *Recorder) Record(location models.Location) {
+	if r == nil || location.SessionID == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	walk, ok := r.walks[location.SessionID]
+	if !ok {
+		if len(r.walks) >= maxCapturedWalks {
+			if !r.full {
+				log.Printf("Capture limit of %d walks reached; further walks are not captured", maxCapturedWalks)
+				r.full = true
+			}
+			return
+		}
+		name := r.name(location.SessionID)
+		walk = &capturedWalk{name: name, path: filepath.Join(r.dir, name+FileExtension), first: location}
+		r.walks[location.SessionID] = walk
+	}
+
+	line, err := json.Marshal(anonymize(location, walk.first, walk.name))
+	if err != nil {
+		log.Printf("Failed to encode captured point: %v", err)
+		return
+	}
+
+	// Files are opened per point, so walks that end hold no file open and every point
+	// reaches the file as soon as it is ingested
+	file, err := os.OpenFile(walk.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		log.Printf("Failed to open capture file: %v", err)
+		return
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		log.Printf("Failed to write captured point: %v", err)
+	}
+	file.Close()
+}
+
+// name returns the anonymous name of a session's fixture
+func (r *Recorder) name(sessionID string) string {
+	sum := sha256.Sum256(append(append([]byte{}, r.salt...), sessionID...))
+	return "walk-" + hex.EncodeToString(sum[:6])
+}
+
+// anonymize moves a point of a walk that started at first so the walk starts at latitude and
+// longitude zero at Epoch, and renames its session to name
+func anonymize(location, first models.Location, name string) models.Location {
+	north := (location.Latitude - first.Latitude) * metersPerDegree
+	east := (location.Longitude - first.Longitude) * metersPerDegree * math.Cos(first.Latitude*math.Pi/180)
+
+	location.SessionID = name
+	location.Latitude = round(north / metersPerDegree)
+	location.Longitude = round(east / metersPerDegree)
+	location.Timestamp = Epoch.Add(location.Timestamp.Sub(first.Timestamp))
+	location.Altitude = nil
+	location.Late = false
+	return location
+}
+
+// round keeps coordinates to about a centimetre, so fixtures do not carry float noise
+func round(degrees float64) float64 {
+	return math.Round(degrees*1e7) / 1e7
+}
+
+// Load reads a fixture's points in the order they arrived
+func Load(path string) ([]models.Location, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open fixture: %w", err)
+	}
+	defer file.Close()
+
+	var points []models.Location
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var point models.Location
+		if err := json.Unmarshal(scanner.Bytes(), &point); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s line %d: %w", filepath.Base(path), line, err)
+		}
+		points = append(points, point)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	return points, nil
+}
+
+// Rebase returns a fixture's points moved to start at latitude and longitude, at start, and
+// renamed to sessionID, ready to be replayed as a new walk
+func Rebase(points []models.Location, sessionID string, latitude, longitude float64, start time.Time) []models.Location {
+	rebased := make([]models.Location, len(points))
+	for i, point := range points {
+		point.SessionID = sessionID
+		point.Latitude = latitude + point.Latitude
+		point.Longitude = longitude + point.Longitude/math.Cos(latitude*math.Pi/180)
+		point.Timestamp = start.Add(point.Timestamp.Sub(Epoch))
+		rebased[i] = point
+	}
+	return rebased
+}
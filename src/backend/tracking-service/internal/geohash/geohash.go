@@ -0,0 +1,168 @@
+// Package geohash indexes coordinates by the geohash cell containing them, so nearby points
+// share a key prefix and can be grouped or looked up without geospatial queries
+// Version: 1.0.0
+
+package geohash
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// base32 is the geohash alphabet; each character encodes five bits
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// MaxPrecision is the longest geohash produced, about 4cm across
+const MaxPrecision = 12
+
+// metersPerDegree is the length of one degree of latitude
+const metersPerDegree = 111320.0
+
+// Box is the area covered by a geohash cell
+type Box struct {
+	MinLatitude  float64 `json:"min_latitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+}
+
+// Center returns the coordinate at the middle of the box
+func (b Box) Center() (latitude, longitude float64) {
+	return (b.MinLatitude + b.MaxLatitude) / 2, (b.MinLongitude + b.MaxLongitude) / 2
+}
+
+// Encode returns the geohash of the cell of the given precision, in characters, containing
+// the coordinate
+func Encode(latitude, longitude float64, precision int) string {
+	if precision < 1 {
+		precision = 1
+	}
+	if precision > MaxPrecision {
+		precision = MaxPrecision
+	}
+
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+
+	var hash strings.Builder
+	bit, ch, even := 0, 0, true
+	for hash.Len() < precision {
+		// Bits alternate between longitude and latitude, starting with longitude
+		if even {
+			mid := (minLng + maxLng) / 2
+			if longitude >= mid {
+				ch = ch<<1 | 1
+				minLng = mid
+			} else {
+				ch <<= 1
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if latitude >= mid {
+				ch = ch<<1 | 1
+				minLat = mid
+			} else {
+				ch <<= 1
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			hash.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return hash.String()
+}
+
+// Decode returns the area covered by a geohash
+func Decode(hash string) (Box, error) {
+	box := Box{MinLatitude: -90, MaxLatitude: 90, MinLongitude: -180, MaxLongitude: 180}
+	if hash == "" || len(hash) > MaxPrecision {
+		return box, fmt.Errorf("invalid geohash %q", hash)
+	}
+
+	even := true
+	for i := 0; i < len(hash); i++ {
+		value := strings.IndexByte(base32, hash[i])
+		if value < 0 {
+			return box, fmt.Errorf("invalid geohash %q", hash)
+		}
+		for mask := 16; mask > 0; mask >>= 1 {
+			if even {
+				mid := (box.MinLongitude + box.MaxLongitude) / 2
+				if value&mask != 0 {
+					box.MinLongitude = mid
+				} else {
+					box.MaxLongitude = mid
+				}
+			} else {
+				mid := (box.MinLatitude + box.MaxLatitude) / 2
+				if value&mask != 0 {
+					box.MinLatitude = mid
+				} else {
+					box.MaxLatitude = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return box, nil
+}
+
+// Neighbors returns the cells of the same precision surrounding hash, without hash itself.
+// Near the poles, cells beyond the edge of the map are left out.
+func Neighbors(hash string) ([]string, error) {
+	box, err := Decode(hash)
+	if err != nil {
+		return nil, err
+	}
+	latitude, longitude := box.Center()
+	height := box.MaxLatitude - box.MinLatitude
+	width := box.MaxLongitude - box.MinLongitude
+
+	seen := map[string]bool{hash: true}
+	var neighbors []string
+	for _, dLat := range []float64{-1, 0, 1} {
+		for _, dLng := range []float64{-1, 0, 1} {
+			lat := latitude + dLat*height
+			if lat < -90 || lat > 90 {
+				continue
+			}
+			// Wrap across the antimeridian
+			lng := math.Mod(longitude+dLng*width+540, 360) - 180
+			neighbor := Encode(lat, lng, len(hash))
+			if !seen[neighbor] {
+				seen[neighbor] = true
+				neighbors = append(neighbors, neighbor)
+			}
+		}
+	}
+	return neighbors, nil
+}
+
+// PrecisionFor returns the longest precision, at most maxPrecision, whose cells at the given
+// latitude are at least radiusMeters across in both directions. A cell of that precision and
+// its neighbours then cover every point within radiusMeters of a point in the cell.
+func PrecisionFor(latitude, radiusMeters float64, maxPrecision int) int {
+	for precision := maxPrecision; precision > 1; precision-- {
+		height, width := CellSize(latitude, precision)
+		if height >= radiusMeters && width >= radiusMeters {
+			return precision
+		}
+	}
+	return 1
+}
+
+// CellSize returns the height and width in meters of a cell of the given precision at latitude
+func CellSize(latitude float64, precision int) (height, width float64) {
+	bits := 5 * precision
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	heightDegrees := 180 / math.Pow(2, float64(latBits))
+	widthDegrees := 360 / math.Pow(2, float64(lngBits))
+	return heightDegrees * metersPerDegree, widthDegrees * metersPerDegree * math.Cos(latitude*math.Pi/180)
+}
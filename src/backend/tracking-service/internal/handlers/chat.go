@@ -0,0 +1,200 @@
+// Package handlers implements HTTP handlers for the tracking-service
+// Version: 1.0.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/service"
+)
+
+// readReceiptRequest represents the incoming JSON payload marking a booking's messages read
+type readReceiptRequest struct {
+	UpTo string `json:"up_to"`
+}
+
+// reportMessageRequest represents the incoming JSON payload reporting a message as abusive
+type reportMessageRequest struct {
+	Reason string `json:"reason"`
+}
+
+// IsBookingMessagesPath reports whether path is one of a booking's chat endpoints
+func IsBookingMessagesPath(path string) bool {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, bookingsPathPrefix), "/"), "/")
+	return len(parts) >= 2 && parts[1] == "messages"
+}
+
+// BookingMessagesHandler routes requests for the conversation between a booking's owner and
+// walker. New messages and read receipts are also pushed to WebSocket clients holding a
+// connection token for the booking. The caller must be authenticated by auth.Require and be
+// the booking's owner or walker; messages are sent, read and reported as them.
+//
+//	GET  /api/v1/bookings/{booking_id}/messages?before={message_id}&limit={n}
+//	POST /api/v1/bookings/{booking_id}/messages
+//	POST /api/v1/bookings/{booking_id}/messages/read
+//	POST /api/v1/bookings/{booking_id}/messages/{message_id}/report
+//
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func BookingMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, bookingsPathPrefix), "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] != "messages" || len(parts) > 4 {
+		http.NotFound(w, r)
+		return
+	}
+	bookingID := parts[0]
+
+	userID, role, ok := bookingParticipant(w, r, bookingID)
+	if !ok {
+		return
+	}
+
+	switch {
+	case len(parts) == 2:
+		handleMessages(w, r, bookingID, userID, role)
+	case len(parts) == 3 && parts[2] == "read":
+		handleReadReceipt(w, r, bookingID, userID)
+	case len(parts) == 4 && parts[3] == "report":
+		handleReportMessage(w, r, bookingID, parts[2], userID)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// bookingParticipant returns the authenticated user and the role they take in the booking,
+// writing the error response when they are not one of its participants
+func bookingParticipant(w http.ResponseWriter, r *http.Request, bookingID string) (string, models.ConsentRole, bool) {
+	claims, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return "", "", false
+	}
+
+	role, err := service.BookingParticipant(r.Context(), bookingID, claims.ID)
+	switch {
+	case err == nil:
+		return claims.ID, role, true
+	case errors.Is(err, service.ErrNotParticipant):
+		http.Error(w, "Not a participant of the booking", http.StatusForbidden)
+	case errors.Is(err, service.ErrBookingLookupUnavailable):
+		log.Printf("Failed to look up participants of booking %s: %v", bookingID, err)
+		http.Error(w, "Booking lookup unavailable", http.StatusServiceUnavailable)
+	default:
+		log.Printf("Failed to look up participants of booking %s: %v", bookingID, err)
+		http.Error(w, "Failed to look up booking", http.StatusInternalServerError)
+	}
+	return "", "", false
+}
+
+// handleMessages lists a page of a booking's messages or sends a new one as the participant
+func handleMessages(w http.ResponseWriter, r *http.Request, bookingID, userID string, role models.ConsentRole) {
+	switch r.Method {
+	case http.MethodGet:
+		limit := 0
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			var err error
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 {
+				http.Error(w, "Invalid limit value", http.StatusBadRequest)
+				return
+			}
+		}
+		page, err := service.ListChatMessages(bookingID, r.URL.Query().Get("before"), limit)
+		if err != nil {
+			writeChatError(w, err, "Failed to retrieve messages")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+
+	case http.MethodPost:
+		var message models.ChatMessage
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			log.Printf("Failed to decode request body: %v", err)
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+		message.SenderID, message.SenderRole = userID, role
+		sent, err := service.SendChatMessage(bookingID, message)
+		if err != nil {
+			writeChatError(w, err, "Failed to send message")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(sent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleReadReceipt marks a booking's messages read by the participant
+func handleReadReceipt(w http.ResponseWriter, r *http.Request, bookingID, readerID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req readReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := service.MarkChatRead(bookingID, readerID, req.UpTo)
+	if err != nil {
+		writeChatError(w, err, "Failed to mark messages read")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(receipt)
+}
+
+// handleReportMessage reports one of a booking's messages as abusive on behalf of the participant
+func handleReportMessage(w http.ResponseWriter, r *http.Request, bookingID, messageID, reporterID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req reportMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	incident, err := service.ReportChatMessage(bookingID, messageID, reporterID, req.Reason)
+	if err != nil {
+		writeChatError(w, err, "Failed to report message")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(incident)
+}
+
+// writeChatError maps chat service errors to HTTP responses
+func writeChatError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrChatMessageNotFound):
+		http.Error(w, "Message not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrChatMessageReported):
+		http.Error(w, "Message has already been reported", http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid chat"),
+		strings.Contains(err.Error(), "invalid read receipt"),
+		strings.Contains(err.Error(), "invalid report"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("%s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,198 @@
+// Package handlers implements HTTP handlers for the tracking-service
+// Version: 1.0.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/service"
+)
+
+const (
+	// bookingsPathPrefix is the path prefix of the per-booking consent and chat endpoints
+	bookingsPathPrefix = "/api/v1/bookings/"
+
+	// subjectsPathPrefix is the path prefix of the data subject endpoints
+	subjectsPathPrefix = "/api/v1/privacy/subjects/"
+)
+
+// BookingHandler routes requests under /api/v1/bookings/ to the walk evidence endpoint; the
+// consent and chat endpoints are served by BookingConsentHandler and BookingMessagesHandler.
+// The caller must be authenticated by auth.Require, as a user, or auth.RequireAPIKey, as
+// another backend service.
+func BookingHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, bookingsPathPrefix), "/"), "/")
+	if len(parts) == 2 && parts[0] != "" && parts[1] == "walk-evidence" {
+		bookingWalkEvidenceHandler(w, r, parts[0])
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// IsBookingConsentPath reports whether path is one of a booking's consent endpoints
+func IsBookingConsentPath(path string) bool {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, bookingsPathPrefix), "/"), "/")
+	return len(parts) >= 2 && parts[1] == "consent"
+}
+
+// BookingConsentHandler routes requests for a booking's tracking consent:
+//
+//	GET    /api/v1/bookings/{booking_id}/consent
+//	POST   /api/v1/bookings/{booking_id}/consent
+//	DELETE /api/v1/bookings/{booking_id}/consent/{subject_id}
+//
+// The caller must be authenticated by auth.RequireMethod. The booking's owner and walker give
+// consent as themselves, in the role they take in the booking, and can only withdraw their
+// own; admins may record and withdraw consent on behalf of the subject they name.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func BookingConsentHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, bookingsPathPrefix), "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] != "consent" || len(parts) > 3 {
+		http.NotFound(w, r)
+		return
+	}
+	bookingID := parts[0]
+
+	claims, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	admin := claims.Role == policy.RoleAdmin
+
+	var (
+		subjectID string
+		role      models.ConsentRole
+	)
+	if !admin {
+		if subjectID, role, ok = bookingParticipant(w, r, bookingID); !ok {
+			return
+		}
+	}
+
+	if len(parts) == 3 {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !admin && parts[2] != subjectID {
+			http.Error(w, "Consent can only be withdrawn by the person who gave it", http.StatusForbidden)
+			return
+		}
+		if err := service.RevokeConsent(bookingID, parts[2]); err != nil {
+			writeConsentError(w, err, "Failed to revoke consent")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		state, err := service.GetConsentState(bookingID)
+		if err != nil {
+			writeConsentError(w, err, "Failed to retrieve consent")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(state)
+
+	case http.MethodPost:
+		var consent models.Consent
+		if err := json.NewDecoder(r.Body).Decode(&consent); err != nil {
+			log.Printf("Failed to decode request body: %v", err)
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+		if !admin {
+			consent.SubjectID, consent.Role = subjectID, role
+		}
+		recorded, err := service.RecordConsent(bookingID, consent)
+		if err != nil {
+			writeConsentError(w, err, "Failed to record consent")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(recorded)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// SubjectExportHandler handles HTTP GET requests for everything held about one person:
+//
+//	GET /api/v1/privacy/subjects/{subject_id}/export
+func SubjectExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, subjectsPathPrefix), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "export" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := service.ExportSubjectData(parts[0])
+	if err != nil {
+		log.Printf("Failed to export subject data: %v", err)
+		http.Error(w, "Failed to export subject data", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="tracking-data.json"`)
+	json.NewEncoder(w).Encode(data)
+}
+
+// writeConsentError maps consent service errors to HTTP responses
+func writeConsentError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrConsentNotFound):
+		http.Error(w, "No active consent found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid consent"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("%s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
+
+// bookingWalkEvidenceHandler handles HTTP GET requests for the duration, distance and photos
+// tracked across a booking's walks. Users other than admins must be the booking's owner or
+// walker; other backend services read any booking's evidence.
+//
+//	GET /api/v1/bookings/{booking_id}/walk-evidence
+func bookingWalkEvidenceHandler(w http.ResponseWriter, r *http.Request, bookingID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if claims, ok := auth.UserFromContext(r.Context()); ok && claims.Role != policy.RoleAdmin {
+		if _, _, ok := bookingParticipant(w, r, bookingID); !ok {
+			return
+		}
+	}
+
+	evidence, err := service.BookingWalkEvidence(bookingID)
+	if err != nil {
+		log.Printf("Failed to retrieve walk evidence: %v", err)
+		http.Error(w, "Failed to retrieve walk evidence", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(evidence)
+}
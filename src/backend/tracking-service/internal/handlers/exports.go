@@ -0,0 +1,153 @@
+// Package handlers implements HTTP handlers for the tracking-service
+// Version: 1.0.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/export"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/service"
+)
+
+// exportJobsPathPrefix is the path prefix of the per-job export endpoints
+const exportJobsPathPrefix = "/api/v1/exports/jobs/"
+
+// exportRetryAfter is the Retry-After hint sent when the export backlog is full
+const exportRetryAfter = "60"
+
+// exportRequest is the body of an export request: a session, a time range, or both, and
+// whether a session's route is snapped
+type exportRequest struct {
+	Format    models.ExportFormat `json:"format"`
+	SessionID string              `json:"session_id"`
+	StartTime *time.Time          `json:"start_time"`
+	EndTime   *time.Time          `json:"end_time"`
+	Snapped   bool                `json:"snapped"`
+}
+
+// CreateExportHandler handles HTTP POST requests to export location history. The export
+// runs in the background; the response points at the job to poll for its download link.
+// Only admins may export a time range across every walker; other users export their own walks.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func CreateExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req exportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	job, err := service.CreateExport(claims.ID, claims.Role == policy.RoleAdmin, req.Format, req.SessionID, req.StartTime, req.EndTime, req.Snapped)
+	if err != nil {
+		writeExportError(w, err, "Failed to create export")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", exportJobsPathPrefix+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// ExportJobHandler handles HTTP GET requests for the status of an export, from the user who
+// requested it or an admin:
+//
+//	GET /api/v1/exports/jobs/{id}
+func ExportJobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, exportJobsPathPrefix), "/")
+	if id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	job, err := service.GetExport(r.Context(), id, claims.ID, claims.Role == policy.RoleAdmin)
+	if err != nil {
+		writeExportError(w, err, "Failed to retrieve export")
+		return
+	}
+
+	// Links are short-lived, so clients must not reuse a cached response
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// ExportFileHandler serves finished exports stored on local disk through files, to the user
+// who requested them or an admin:
+//
+//	GET /api/v1/exports/files/exports/{id}.{ext}
+func ExportFileHandler(files http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := auth.UserFromContext(r.Context())
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, export.FilesPathPrefix), "exports/")
+		id, _, _ := strings.Cut(name, ".")
+		if id == "" || strings.Contains(id, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		if _, err := service.AuthorizeExport(id, claims.ID, claims.Role == policy.RoleAdmin); err != nil {
+			writeExportError(w, err, "Failed to retrieve export")
+			return
+		}
+		files.ServeHTTP(w, r)
+	}
+}
+
+// writeExportError maps export service errors to HTTP responses
+func writeExportError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrExportNotFound):
+		http.Error(w, "Export not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrExportForbidden):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	case errors.Is(err, service.ErrSessionNotFound):
+		http.Error(w, "Walk session not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrExportBacklogFull):
+		w.Header().Set("Retry-After", exportRetryAfter)
+		http.Error(w, "Too many exports in progress; retry later", http.StatusTooManyRequests)
+	case strings.Contains(err.Error(), "invalid export"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "not configured"):
+		http.Error(w, "Exports are not available", http.StatusServiceUnavailable)
+	default:
+		log.Printf("%s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,171 @@
+// Package handlers implements HTTP handlers for the tracking-service
+// Version: 1.0.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/service"
+)
+
+// incidentsPathPrefix is the path prefix of the per-incident endpoints
+const incidentsPathPrefix = "/api/v1/incidents/"
+
+// CreateIncidentHandler handles HTTP POST requests to report an incident, as the user of the token
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func CreateIncidentHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var incident models.Incident
+	if err := json.NewDecoder(r.Body).Decode(&incident); err != nil {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	incident.ReportedBy = claims.ID
+
+	created, err := service.CreateIncident(r.Context(), incident)
+	if err != nil {
+		writeIncidentError(w, err, "Failed to report incident")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// IncidentHandler routes requests for a single incident, from its reporter or an admin:
+//
+//	GET   /api/v1/incidents/{id}
+//	PATCH /api/v1/incidents/{id}
+//	POST  /api/v1/incidents/{id}/attachments
+func IncidentHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.SplitN(strings.Trim(strings.TrimPrefix(r.URL.Path, incidentsPathPrefix), "/"), "/", 2)
+	id := parts[0]
+	if id == "" {
+		http.Error(w, "Incident ID is required", http.StatusBadRequest)
+		return
+	}
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+
+	incident, err := service.GetIncident(id)
+	if err != nil {
+		writeIncidentError(w, err, "Failed to retrieve incident")
+		return
+	}
+	if claims.Role != policy.RoleAdmin && incident.ReportedBy != claims.ID {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		// The incident was read above
+
+	case action == "" && r.Method == http.MethodPatch:
+		var update service.IncidentUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			log.Printf("Failed to decode request body: %v", err)
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+		incident, err = service.UpdateIncident(r.Context(), id, update)
+
+	case action == "attachments" && r.Method == http.MethodPost:
+		var attachment models.Attachment
+		if err := json.NewDecoder(r.Body).Decode(&attachment); err != nil {
+			log.Printf("Failed to decode request body: %v", err)
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+		attachment.AddedBy = claims.ID
+		incident, err = service.AddIncidentAttachment(r.Context(), id, attachment)
+
+	case action == "" || action == "attachments":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		writeIncidentError(w, err, "Failed to process incident")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(incident)
+}
+
+// IncidentQueueHandler handles HTTP GET requests for the admin incident queue,
+// optionally filtered by status, type and region query parameters
+func IncidentQueueHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	incidents, err := service.IncidentQueue(
+		models.IncidentStatus(query.Get("status")),
+		models.IncidentType(query.Get("type")),
+		query.Get("region"),
+	)
+	if err != nil {
+		log.Printf("Failed to retrieve incident queue: %v", err)
+		http.Error(w, "Failed to retrieve incident queue", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"incidents": incidents,
+		"count":     len(incidents),
+	})
+}
+
+// writeIncidentError maps incident service errors to HTTP responses
+func writeIncidentError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrIncidentNotFound):
+		http.Error(w, "Incident not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrSessionNotFound):
+		http.Error(w, "Walk session not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid incident"), strings.Contains(err.Error(), "invalid attachment"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case strings.Contains(err.Error(), "incident conflict"):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("%s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
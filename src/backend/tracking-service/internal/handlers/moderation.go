@@ -0,0 +1,106 @@
+// Package handlers implements HTTP handlers for the tracking-service
+// Version: 1.0.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/service"
+)
+
+// flaggedContentPath is the path of the admin moderation review queue
+const flaggedContentPath = "/api/v1/admin/flagged-content"
+
+// reviewRequest represents the incoming JSON payload deciding on flagged content
+type reviewRequest struct {
+	ReviewerID string               `json:"reviewer_id"`
+	Decision   models.FlaggedStatus `json:"decision"`
+}
+
+// FlaggedContentHandler routes requests for the content the moderation filter flagged for
+// admin review. A decision of removed takes the content down.
+//
+//	GET  /api/v1/admin/flagged-content?status={pending|dismissed|removed}&limit={n}
+//	POST /api/v1/admin/flagged-content/{id}/review
+func FlaggedContentHandler(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, flaggedContentPath), "/")
+	if path == "" {
+		listFlaggedContent(w, r)
+		return
+	}
+
+	id, action, _ := strings.Cut(path, "/")
+	if action != "review" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req reviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	flagged, err := service.ReviewFlaggedContent(id, req.ReviewerID, req.Decision)
+	if err != nil {
+		writeModerationError(w, err, "Failed to review flagged content")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flagged)
+}
+
+// listFlaggedContent returns a page of the review queue
+func listFlaggedContent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 {
+			http.Error(w, "Invalid limit value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	flagged, err := service.ListFlaggedContent(models.FlaggedStatus(r.URL.Query().Get("status")), limit)
+	if err != nil {
+		writeModerationError(w, err, "Failed to retrieve flagged content")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"flagged": flagged,
+		"count":   len(flagged),
+	})
+}
+
+// writeModerationError maps moderation service errors to HTTP responses
+func writeModerationError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrFlaggedContentNotFound):
+		http.Error(w, "Flagged content not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrFlaggedContentReviewed):
+		http.Error(w, "Flagged content has already been reviewed", http.StatusConflict)
+	case strings.Contains(err.Error(), "invalid flagged content"), strings.Contains(err.Error(), "invalid review"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("%s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,147 @@
+// Package handlers implements HTTP handlers for the tracking-service
+// Version: 1.0.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/service"
+)
+
+// walkersPathPrefix is the path prefix of the per-walker endpoints
+const walkersPathPrefix = "/api/v1/walkers/"
+
+// IsWalkerPositionPath reports whether path is a walker's last position endpoint
+func IsWalkerPositionPath(path string) bool {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, walkersPathPrefix), "/"), "/")
+	return len(parts) == 2 && parts[0] != "" && parts[1] == "position"
+}
+
+// WalkerPrivacyZonesHandler routes requests for a walker's privacy zones:
+//
+//	GET    /api/v1/walkers/{walker_id}/privacy-zones
+//	POST   /api/v1/walkers/{walker_id}/privacy-zones
+//	DELETE /api/v1/walkers/{walker_id}/privacy-zones/{zone_id}
+//
+// The caller must be authenticated by auth.RequireMethod as the walker, or as an admin; the zones
+// reveal where the walker lives.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func WalkerPrivacyZonesHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, walkersPathPrefix), "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] != "privacy-zones" || len(parts) > 3 {
+		http.NotFound(w, r)
+		return
+	}
+	walkerID := parts[0]
+
+	claims, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if claims.ID != walkerID && claims.Role != policy.RoleAdmin {
+		log.Printf("User %s denied access to privacy zones of walker %s", claims.ID, walkerID)
+		http.Error(w, "Insufficient permissions", http.StatusForbidden)
+		return
+	}
+
+	if len(parts) == 3 {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := service.DeletePrivacyZone(walkerID, parts[2]); err != nil {
+			writePrivacyZoneError(w, err, "Failed to delete privacy zone")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		zones, err := service.ListPrivacyZones(walkerID)
+		if err != nil {
+			writePrivacyZoneError(w, err, "Failed to retrieve privacy zones")
+			return
+		}
+		if zones == nil {
+			zones = []models.PrivacyZone{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"privacy_zones": zones})
+
+	case http.MethodPost:
+		var zone models.PrivacyZone
+		if err := json.NewDecoder(r.Body).Decode(&zone); err != nil {
+			log.Printf("Failed to decode request body: %v", err)
+			http.Error(w, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+		created, err := service.CreatePrivacyZone(walkerID, zone)
+		if err != nil {
+			writePrivacyZoneError(w, err, "Failed to create privacy zone")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// WalkerPositionHandler handles HTTP GET requests for the position a walker was last seen at,
+// the centre of their cell, and when they were seen there. Only the booking-service, verifying
+// check-ins, is answered, authenticated by auth.RequireAPIKey:
+//
+//	GET /api/v1/walkers/{walker_id}/position
+func WalkerPositionHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, walkersPathPrefix), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "position" {
+		http.NotFound(w, r)
+		return
+	}
+	walkerID := parts[0]
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	position, err := service.LastWalkerPosition(walkerID)
+	if errors.Is(err, service.ErrPositionNotFound) {
+		http.Error(w, "Walker position not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to retrieve walker position: %v", err)
+		http.Error(w, "Failed to retrieve walker position", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(position)
+}
+
+// writePrivacyZoneError maps privacy zone service errors to HTTP responses
+func writePrivacyZoneError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrPrivacyZoneNotFound):
+		http.Error(w, "Privacy zone not found", http.StatusNotFound)
+	case strings.Contains(err.Error(), "invalid privacy zone"):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("%s: %v", message, err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
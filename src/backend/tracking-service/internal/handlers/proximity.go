@@ -0,0 +1,134 @@
+// Package handlers implements HTTP handlers for the tracking-service
+// Version: 1.0.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"src/backend/tracking-service/internal/service"
+)
+
+const (
+	// defaultHeatmapRange is how far back the heatmap looks when no start_time is given
+	defaultHeatmapRange = 7 * 24 * time.Hour
+
+	// defaultHeatmapPrecision is the heatmap cell size used when none is given, about 1km
+	defaultHeatmapPrecision = 6
+)
+
+// NearbyWalkersHandler handles HTTP GET requests for the walkers seen near a point, given by
+// latitude, longitude and radius_m query parameters. max_age (a duration such as 5m) limits
+// how recently they must have been seen.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func NearbyWalkersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	latitude, err := strconv.ParseFloat(query.Get("latitude"), 64)
+	if err != nil {
+		http.Error(w, "Invalid latitude", http.StatusBadRequest)
+		return
+	}
+	longitude, err := strconv.ParseFloat(query.Get("longitude"), 64)
+	if err != nil {
+		http.Error(w, "Invalid longitude", http.StatusBadRequest)
+		return
+	}
+	radius, err := strconv.ParseFloat(query.Get("radius_m"), 64)
+	if err != nil {
+		http.Error(w, "Invalid radius_m", http.StatusBadRequest)
+		return
+	}
+	var maxAge time.Duration
+	if raw := query.Get("max_age"); raw != "" {
+		if maxAge, err = time.ParseDuration(raw); err != nil {
+			http.Error(w, "Invalid max_age format. Expected a duration such as 5m", http.StatusBadRequest)
+			return
+		}
+	}
+
+	walkers, err := service.NearbyWalkers(latitude, longitude, radius, maxAge)
+	if err != nil {
+		log.Printf("Failed to find nearby walkers: %v", err)
+		if strings.Contains(err.Error(), "invalid proximity query") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to find nearby walkers", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"walkers": walkers,
+		"count":   len(walkers),
+	})
+}
+
+// HeatmapHandler handles HTTP GET requests for the number of location points recorded per
+// cell, over start_time to end_time (RFC3339, by default the last 7 days). precision sets the
+// cell size as a geohash length, from 4 (about 40km) to 7 (about 150m).
+func HeatmapHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	endTime := time.Now()
+	if raw := query.Get("end_time"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			http.Error(w, "Invalid end_time format. Expected RFC3339", http.StatusBadRequest)
+			return
+		}
+		endTime = parsed
+	}
+	startTime := endTime.Add(-defaultHeatmapRange)
+	if raw := query.Get("start_time"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			http.Error(w, "Invalid start_time format. Expected RFC3339", http.StatusBadRequest)
+			return
+		}
+		startTime = parsed
+	}
+	precision := defaultHeatmapPrecision
+	if raw := query.Get("precision"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, "Invalid precision", http.StatusBadRequest)
+			return
+		}
+		precision = parsed
+	}
+
+	cells, err := service.LocationHeatmap(startTime, endTime, precision)
+	if err != nil {
+		log.Printf("Failed to build heatmap: %v", err)
+		if strings.Contains(err.Error(), "invalid heatmap query") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to build heatmap", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"start_time": startTime,
+		"end_time":   endTime,
+		"precision":  precision,
+		"cells":      cells,
+	})
+}
@@ -0,0 +1,83 @@
+// Package handlers implements HTTP handlers for the tracking-service
+// Version: 1.0.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"src/backend/tracking-service/internal/service"
+)
+
+// simulationsPath is the path of the development walk simulation endpoints
+const simulationsPath = "/api/v1/dev/simulations"
+
+// SimulationsHandler routes requests for simulated walks, which are only registered when
+// simulation is enabled for development:
+//
+//	POST   /api/v1/dev/simulations
+//	GET    /api/v1/dev/simulations
+//	DELETE /api/v1/dev/simulations/{id}
+func SimulationsHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, simulationsPath), "/")
+
+	switch {
+	case id == "" && r.Method == http.MethodPost:
+		startSimulation(w, r)
+	case id == "" && r.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"simulations": service.ListSimulations()})
+	case id != "" && r.Method == http.MethodDelete:
+		stopSimulation(w, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// startSimulation starts a simulated walk with the settings in the request body
+func startSimulation(w http.ResponseWriter, r *http.Request) {
+	var settings service.SimulationSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	simulation, err := service.StartSimulation(settings)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "invalid simulation"):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, service.ErrTooManySimulations), errors.Is(err, service.ErrSimulationUnavailable):
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			log.Printf("Failed to start simulated walk: %v", err)
+			http.Error(w, "Failed to start simulated walk", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", simulationsPath+"/"+simulation.ID)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(simulation)
+}
+
+// stopSimulation ends a simulated walk and its session
+func stopSimulation(w http.ResponseWriter, id string) {
+	if err := service.StopSimulation(id); err != nil {
+		if errors.Is(err, service.ErrSimulationNotFound) {
+			http.Error(w, "Simulation not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to stop simulated walk: %v", err)
+		http.Error(w, "Failed to stop simulated walk", http.StatusInternalServerError)
+		return
+	}
+
+	writeSuccess(w, "Simulated walk stopped")
+}
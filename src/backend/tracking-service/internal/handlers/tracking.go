@@ -5,11 +5,16 @@ package handlers
 
 import (
 	"encoding/json" // standard library
-	"log"          // standard library
-	"net/http"     // standard library
+	"errors"
+	"log"      // standard library
+	"net/http" // standard library
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
-	"src/backend/tracking-service/internal/config"
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+
 	"src/backend/tracking-service/internal/models"
 	"src/backend/tracking-service/internal/service"
 	"src/backend/tracking-service/internal/websocket"
@@ -24,9 +29,50 @@ import (
 
 // locationRequest represents the incoming JSON payload for location tracking
 type locationRequest struct {
+	SessionID string    `json:"session_id"`
 	Latitude  float64   `json:"latitude"`
 	Longitude float64   `json:"longitude"`
 	Timestamp time.Time `json:"timestamp"`
+
+	// Optional device metadata
+	AccuracyMeters *float64 `json:"accuracy_meters"`
+	Altitude       *float64 `json:"altitude"`
+	Speed          *float64 `json:"speed"`
+	Heading        *float64 `json:"heading"`
+	BatteryPercent *float64 `json:"battery_percent"`
+}
+
+// connectionTokenRequest represents the incoming JSON payload for issuing a WebSocket connection token
+type connectionTokenRequest struct {
+	SessionID string `json:"session_id"`
+
+	// BookingID subscribes the connection to a booking's chat instead of a walk session, as
+	// the authenticated participant
+	BookingID string `json:"booking_id"`
+}
+
+// upgrader upgrades HTTP connections to the WebSocket protocol
+var upgrader = gorillaws.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Clients that ask for permessage-deflate get it; the hub decides which messages to compress
+	EnableCompression: true,
+	CheckOrigin:       checkOrigin,
+}
+
+// checkOrigin admits WebSocket upgrades from the service's own host, from the configured
+// allowed origins and from clients sending no Origin, such as the mobile apps, so that other
+// sites cannot connect with a token lifted from a user's browser
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Host, r.Host) || service.AllowedOrigin(origin)
 }
 
 // locationHistoryRequest represents the query parameters for retrieving location history
@@ -55,9 +101,16 @@ func TrackLocationHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Create location model from request
 	location := models.Location{
+		SessionID: req.SessionID,
 		Latitude:  req.Latitude,
 		Longitude: req.Longitude,
 		Timestamp: req.Timestamp,
+
+		AccuracyMeters: req.AccuracyMeters,
+		Altitude:       req.Altitude,
+		Speed:          req.Speed,
+		Heading:        req.Heading,
+		BatteryPercent: req.BatteryPercent,
 	}
 
 	// Validate location data
@@ -133,13 +186,215 @@ func GetLocationHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// setupWebSocket configures and starts the WebSocket hub for real-time location updates
+// IssueConnectionTokenHandler handles HTTP POST requests for WebSocket connection tokens.
+// The returned token encodes the subscription so the client can connect to any instance.
+// The caller must be authenticated by auth.Require; walk tokens are only issued to the walk's
+// walker and owners, and chat tokens to the booking's owner or walker, in the role they take in it.
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func IssueConnectionTokenHandler(w http.ResponseWriter, r *http.Request) {
+	// Verify HTTP method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Parse JSON request body
+	var req connectionTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	var (
+		token string
+		ttl   time.Duration
+		err   error
+	)
+	if req.BookingID != "" && req.SessionID == "" {
+		userID, role, ok := bookingParticipant(w, r, req.BookingID)
+		if !ok {
+			return
+		}
+		token, ttl, err = service.IssueChatToken(req.BookingID, userID, role)
+	} else {
+		if req.SessionID != "" {
+			if _, ok := sessionParticipant(w, r, req.SessionID); !ok {
+				return
+			}
+		}
+		token, ttl, err = service.IssueConnectionToken(req.SessionID)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrSessionRequired) {
+			http.Error(w, "Missing required field: session_id or booking_id", http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid chat connection") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Failed to issue connection token: %v", err)
+		http.Error(w, "Failed to issue connection token", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token":      token,
+		"expires_in": int(ttl.Seconds()),
+	})
+}
+
+// IssueDispatchTokenHandler handles HTTP POST requests for dispatcher WebSocket connection
+// tokens. A dispatch connection subscribes to bookings' walks by sending
+// {"control":"subscribe","booking_id":"..."} and {"control":"unsubscribe","booking_id":"..."},
+// each answered by an ack frame; its messages carry the topic they were published to.
+// Subscribing to {"channel":"fleet"} instead streams a digest of every active walk each second.
 // Addresses requirement: Real-time location tracking
 // Location: 1.2 System Overview/High-Level Description/Backend Services
-func setupWebSocket(cfg config.Config) *websocket.Hub {
-	hub := websocket.NewHub()
-	go hub.Run()
-	return hub
+func IssueDispatchTokenHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ttl, err := service.IssueDispatchToken()
+	if err != nil {
+		log.Printf("Failed to issue dispatch token: %v", err)
+		http.Error(w, "Failed to issue connection token", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token":      token,
+		"expires_in": int(ttl.Seconds()),
+	})
+}
+
+// WebSocketHandler upgrades an HTTP request carrying a valid connection token to a
+// WebSocket subscribed to the token's walk session or booking chat, or a dispatch connection
+// subscribing to walks as it goes. Clients passing protocol=2 receive each message as a typed,
+// versioned envelope rather than a bare frame, and clients passing interval_ms receive at most
+// one location per interval, the latest. Connections over the instance's connection limit, or
+// their participant's quota, are closed on arrival with a close code naming the limit.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	// A draining instance sends new connections back to the load balancer
+	if service.Hub.Draining() {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "Server is shutting down, reconnect to another instance", http.StatusServiceUnavailable)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "Missing required query parameter: token", http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := service.AuthorizeConnection(token)
+	if err != nil {
+		log.Printf("Rejected WebSocket connection: %v", err)
+		http.Error(w, "Invalid or expired connection token", http.StatusUnauthorized)
+		return
+	}
+
+	// A reconnecting client passes the last sequence number it processed to resume the stream
+	var lastSeq uint64
+	if lastSeqStr := r.URL.Query().Get("last_seq"); lastSeqStr != "" {
+		lastSeq, err = strconv.ParseUint(lastSeqStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid last_seq value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Clients opt in to typed envelopes; those that do not ask keep receiving bare frames
+	protocol := websocket.ProtocolLegacy
+	if protocolStr := r.URL.Query().Get("protocol"); protocolStr != "" {
+		protocol, err = strconv.Atoi(protocolStr)
+		if err != nil || (protocol != websocket.ProtocolLegacy && protocol != websocket.ProtocolEnvelope) {
+			http.Error(w, "Unsupported protocol version", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Owners' apps rarely need every point; they may ask for the latest one per interval instead
+	var interval time.Duration
+	if intervalStr := r.URL.Query().Get("interval_ms"); intervalStr != "" {
+		intervalMs, err := strconv.ParseInt(intervalStr, 10, 64)
+		interval = time.Duration(intervalMs) * time.Millisecond
+		if err != nil || interval < 0 || interval > websocket.MaxUpdateInterval {
+			http.Error(w, "Invalid interval_ms value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response
+		log.Printf("Failed to upgrade WebSocket connection: %v", err)
+		return
+	}
+
+	client := websocket.NewClient(service.Hub, conn, claims.Topic)
+	client.Protocol = protocol
+	client.App = r.UserAgent()
+	client.Deflate = strings.Contains(r.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+	client.Dispatch = claims.Dispatch
+	client.UserID = claims.UserID
+	client.Role = claims.Role
+	client.LastSeq = lastSeq
+	client.Interval = interval
+	client.Serve()
+}
+
+// InstanceConnectionsHandler handles HTTP GET requests listing per-instance WebSocket
+// connection counts for load balancer tuning.
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func InstanceConnectionsHandler(w http.ResponseWriter, r *http.Request) {
+	// Verify HTTP method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	counts, err := service.InstanceConnections(r.Context())
+	if err != nil {
+		log.Printf("Failed to retrieve instance connections: %v", err)
+		http.Error(w, "Failed to retrieve instance connections", http.StatusInternalServerError)
+		return
+	}
+
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"instances": counts,
+		"total":     total,
+	})
+}
+
+// HubStatusHandler handles HTTP GET requests for a snapshot of this instance's WebSocket hub:
+// its clients, rooms, buffered messages and delivery statistics, for debugging stuck streams.
+// Each instance reports its own hub.
+func HubStatusHandler(w http.ResponseWriter, r *http.Request) {
+	// Verify HTTP method
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(service.HubStatus())
 }
 
 // init initializes the handlers package
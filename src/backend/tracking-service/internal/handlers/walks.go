@@ -0,0 +1,399 @@
+// Package handlers implements HTTP handlers for the tracking-service
+// Version: 1.0.0
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/service"
+)
+
+// walksPathPrefix is the path prefix of the per-session walk endpoints
+const walksPathPrefix = "/api/v1/walks/"
+
+// startWalkRequest represents the incoming JSON payload for starting a walk session. A group
+// walk lists its bookings in place of booking_id and owner_id.
+type startWalkRequest struct {
+	BookingID string                  `json:"booking_id"`
+	WalkerID  string                  `json:"walker_id"`
+	OwnerID   string                  `json:"owner_id"`
+	Bookings  []models.SessionBooking `json:"bookings"`
+}
+
+// StartWalkHandler handles HTTP POST requests to start a walk session, authenticated by
+// auth.Require. Walkers start walks as the user of their token, for bookings the booking-service
+// has assigned to them, and each booking's owner is the one the booking-service knows; only
+// admins name the walker and owners in the body.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func StartWalkHandler(w http.ResponseWriter, r *http.Request) {
+	// Verify HTTP method
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	// Parse JSON request body
+	var req startWalkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	bookings := req.Bookings
+	if len(bookings) > 0 {
+		if req.BookingID != "" || req.OwnerID != "" {
+			http.Error(w, "Give either booking_id and owner_id or bookings, not both", http.StatusBadRequest)
+			return
+		}
+	} else {
+		bookings = []models.SessionBooking{{BookingID: req.BookingID, OwnerID: req.OwnerID}}
+	}
+
+	if claims.Role != policy.RoleAdmin {
+		if req.WalkerID != "" && req.WalkerID != claims.ID {
+			http.Error(w, "Walks are started as the walker of the token", http.StatusForbidden)
+			return
+		}
+		req.WalkerID = claims.ID
+		if !assignedBookings(w, r, claims.ID, bookings) {
+			return
+		}
+	}
+
+	var session *models.Session
+	var err error
+	if len(req.Bookings) > 0 {
+		session, err = service.StartGroupSession(req.WalkerID, bookings)
+	} else {
+		session, err = service.StartSession(bookings[0].BookingID, req.WalkerID, bookings[0].OwnerID)
+	}
+	if err != nil {
+		log.Printf("Failed to start walk session: %v", err)
+		if errors.Is(err, service.ErrConsentRequired) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid session data") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to start walk session", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+// assignedBookings checks that the booking-service assigned every booking to walkerID, setting
+// each booking's owner to the booking's. It writes the error response and returns false otherwise.
+func assignedBookings(w http.ResponseWriter, r *http.Request, walkerID string, bookings []models.SessionBooking) bool {
+	for i := range bookings {
+		if bookings[i].BookingID == "" {
+			http.Error(w, "invalid session data: booking ID is required", http.StatusBadRequest)
+			return false
+		}
+		ownerID, assignedTo, err := service.BookingParties(r.Context(), bookings[i].BookingID)
+		switch {
+		case errors.Is(err, service.ErrNotParticipant) || (err == nil && assignedTo != walkerID):
+			http.Error(w, "Not the walker of booking "+bookings[i].BookingID, http.StatusForbidden)
+			return false
+		case errors.Is(err, service.ErrBookingLookupUnavailable):
+			log.Printf("Failed to look up participants of booking %s: %v", bookings[i].BookingID, err)
+			http.Error(w, "Booking lookup unavailable", http.StatusServiceUnavailable)
+			return false
+		case err != nil:
+			log.Printf("Failed to look up participants of booking %s: %v", bookings[i].BookingID, err)
+			http.Error(w, "Failed to look up booking", http.StatusInternalServerError)
+			return false
+		}
+		bookings[i].OwnerID = ownerID
+	}
+	return true
+}
+
+// WalkHandler routes requests for a single walk session, authenticated by auth.RequireMethod.
+// Anyone on the walk, or an admin, may follow it; only its walker, or an admin, acts on it:
+//
+//	GET  /api/v1/walks/{session_id}
+//	POST /api/v1/walks/{session_id}/end
+//	POST /api/v1/walks/{session_id}/heartbeat
+//	GET  /api/v1/walks/{session_id}/route[?snapped=true]
+//	POST /api/v1/walks/{session_id}/sos
+//	POST /api/v1/walks/{session_id}/photos
+//	POST /api/v1/walks/{session_id}/pause
+//	POST /api/v1/walks/{session_id}/resume
+func WalkHandler(w http.ResponseWriter, r *http.Request) {
+	sessionID, action := parseWalkPath(r.URL.Path)
+	if sessionID == "" {
+		http.Error(w, "Session ID is required", http.StatusBadRequest)
+		return
+	}
+
+	session, ok := sessionParticipant(w, r, sessionID)
+	if !ok {
+		return
+	}
+	if r.Method != http.MethodGet {
+		if claims, _ := auth.UserFromContext(r.Context()); claims.Role != policy.RoleAdmin && claims.ID != session.WalkerID {
+			log.Printf("User %s denied acting on walk session %s", claims.ID, sessionID)
+			http.Error(w, "Only the walker can act on the walk", http.StatusForbidden)
+			return
+		}
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		getWalk(w, sessionID)
+	case action == "end" && r.Method == http.MethodPost:
+		endWalk(w, r, sessionID)
+	case action == "heartbeat" && r.Method == http.MethodPost:
+		walkHeartbeat(w, sessionID)
+	case action == "route" && r.Method == http.MethodGet:
+		walkRoute(w, sessionID, r.URL.Query().Get("snapped") == "true")
+	case action == "sos" && r.Method == http.MethodPost:
+		walkSOS(w, r, sessionID)
+	case action == "photos" && r.Method == http.MethodPost:
+		walkPhoto(w, r, sessionID)
+	case (action == "pause" || action == "resume") && r.Method == http.MethodPost:
+		pauseWalk(w, r, sessionID, action == "pause")
+	case action == "" || action == "end" || action == "heartbeat" || action == "route" || action == "sos" || action == "photos" ||
+		action == "pause" || action == "resume":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// getWalk writes the walk session as JSON
+func getWalk(w http.ResponseWriter, sessionID string) {
+	session, err := service.GetSession(sessionID)
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			http.Error(w, "Walk session not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to retrieve walk session: %v", err)
+		http.Error(w, "Failed to retrieve walk session", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// endWalk finishes the walk session
+func endWalk(w http.ResponseWriter, r *http.Request, sessionID string) {
+	if err := service.EndSession(r.Context(), sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			http.Error(w, "Active walk session not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to end walk session: %v", err)
+		http.Error(w, "Failed to end walk session", http.StatusInternalServerError)
+		return
+	}
+
+	writeSuccess(w, "Walk session ended")
+}
+
+// pauseWalk pauses the walk session, or resumes it when pause is false, and writes the
+// session as JSON
+func pauseWalk(w http.ResponseWriter, r *http.Request, sessionID string, pause bool) {
+	var session *models.Session
+	var err error
+	if pause {
+		session, err = service.PauseSession(r.Context(), sessionID)
+	} else {
+		session, err = service.ResumeSession(r.Context(), sessionID)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			http.Error(w, "Active walk session not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, service.ErrSessionPaused) {
+			http.Error(w, "Walk session is already paused", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, service.ErrSessionNotPaused) {
+			http.Error(w, "Walk session is not paused", http.StatusConflict)
+			return
+		}
+		log.Printf("Failed to pause or resume walk session: %v", err)
+		http.Error(w, "Failed to update walk session", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// walkHeartbeat records a client heartbeat for the walk session
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func walkHeartbeat(w http.ResponseWriter, sessionID string) {
+	if err := service.Heartbeat(sessionID); err != nil {
+		log.Printf("Failed to record heartbeat: %v", err)
+		http.Error(w, "Failed to record heartbeat", http.StatusInternalServerError)
+		return
+	}
+
+	writeSuccess(w, "Heartbeat recorded")
+}
+
+// walkRoute writes the walk session's route, optionally snapped to the street/path network
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func walkRoute(w http.ResponseWriter, sessionID string, snapped bool) {
+	route, err := service.GetSessionRoute(sessionID, snapped)
+	if err != nil {
+		log.Printf("Failed to retrieve walk route: %v", err)
+		http.Error(w, "Failed to retrieve walk route", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(route)
+}
+
+// sosRequest represents the incoming JSON payload for raising an SOS; the position is
+// optional and falls back to the walk's last reported location
+type sosRequest struct {
+	Message   string   `json:"message"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// walkSOS raises an emergency for the walk session
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func walkSOS(w http.ResponseWriter, r *http.Request, sessionID string) {
+	var req sosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	var location *models.Location
+	if req.Latitude != nil && req.Longitude != nil {
+		location = models.NewLocation(*req.Latitude, *req.Longitude, time.Now())
+	}
+
+	incident, err := service.RaiseSOS(sessionID, req.Message, location)
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			http.Error(w, "Walk session not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid incident data") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Failed to raise SOS: %v", err)
+		http.Error(w, "Failed to record SOS", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(incident)
+}
+
+// sessionParticipant looks up a walk session for the user authenticated by auth.Require, who
+// must walk it, follow one of its bookings or be an admin. It writes the error response and
+// returns false otherwise.
+func sessionParticipant(w http.ResponseWriter, r *http.Request, sessionID string) (*models.Session, bool) {
+	claims, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	session, err := service.GetSession(sessionID)
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		http.Error(w, "Walk session not found", http.StatusNotFound)
+		return nil, false
+	case err != nil:
+		log.Printf("Failed to retrieve walk session: %v", err)
+		http.Error(w, "Failed to retrieve walk session", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	if claims.Role != policy.RoleAdmin && !session.HasParticipant(claims.ID) {
+		log.Printf("User %s denied access to walk session %s", claims.ID, sessionID)
+		http.Error(w, "Not a participant of the walk", http.StatusForbidden)
+		return nil, false
+	}
+	return session, true
+}
+
+// parseWalkPath splits /api/v1/walks/{session_id}/{action} into its session ID and action
+func parseWalkPath(path string) (sessionID, action string) {
+	parts := strings.SplitN(strings.Trim(strings.TrimPrefix(path, walksPathPrefix), "/"), "/", 2)
+	sessionID = parts[0]
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+	return sessionID, action
+}
+
+// writeSuccess sends the standard success response
+func writeSuccess(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{
+		"status":  "success",
+		"message": message,
+	})
+}
+
+// walkPhoto records a photo the walker took during the walk session
+func walkPhoto(w http.ResponseWriter, r *http.Request, sessionID string) {
+	var photo models.WalkPhoto
+	if err := json.NewDecoder(r.Body).Decode(&photo); err != nil {
+		log.Printf("Failed to decode request body: %v", err)
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	recorded, err := service.RecordWalkPhoto(sessionID, photo)
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			http.Error(w, "Active walk session not found", http.StatusNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid photo") {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Failed to record walk photo: %v", err)
+		http.Error(w, "Failed to record walk photo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(recorded)
+}
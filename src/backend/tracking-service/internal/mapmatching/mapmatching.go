@@ -0,0 +1,24 @@
+// Package mapmatching snaps raw GPS traces onto the street and path network
+// Version: 1.0.0
+
+package mapmatching
+
+import (
+	"context"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// Human Tasks:
+// 1. Deploy an OSRM instance built with the foot profile for each service region
+// 2. Set TRACKING_MAP_MATCHING_URL to the OSRM base URL
+// 3. Monitor OSRM latency and error rates
+
+// Provider snaps a walk's location points to the street/path network.
+// Implementations must return exactly one point per input point, in order,
+// falling back to the raw point where it cannot be matched.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type Provider interface {
+	Match(ctx context.Context, points []models.Location) ([]models.Location, error)
+}
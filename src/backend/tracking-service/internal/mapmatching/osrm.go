@@ -0,0 +1,134 @@
+// Package mapmatching snaps raw GPS traces onto the street and path network
+// Version: 1.0.0
+
+package mapmatching
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+const (
+	// osrmMaxCoordinates is the OSRM default limit on coordinates per match request
+	osrmMaxCoordinates = 100
+
+	// osrmDefaultRadius is the search radius used when a point has no reported accuracy
+	osrmDefaultRadius = 25.0
+)
+
+// OSRMProvider implements Provider using the OSRM match service
+type OSRMProvider struct {
+	baseURL string
+	profile string
+	client  *http.Client
+}
+
+// NewOSRMProvider creates a provider for the OSRM server at baseURL using the foot profile
+func NewOSRMProvider(baseURL string) *OSRMProvider {
+	return &OSRMProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		profile: "foot",
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// osrmMatchResponse is the subset of the OSRM match response used here
+type osrmMatchResponse struct {
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+	Tracepoints []*struct {
+		Location [2]float64 `json:"location"`
+	} `json:"tracepoints"`
+}
+
+// Match snaps points in chunks of the OSRM coordinate limit
+func (p *OSRMProvider) Match(ctx context.Context, points []models.Location) ([]models.Location, error) {
+	matched := make([]models.Location, 0, len(points))
+	for start := 0; start < len(points); start += osrmMaxCoordinates {
+		end := start + osrmMaxCoordinates
+		if end > len(points) {
+			end = len(points)
+		}
+
+		chunk, err := p.matchChunk(ctx, points[start:end])
+		if err != nil {
+			return nil, err
+		}
+		matched = append(matched, chunk...)
+	}
+	return matched, nil
+}
+
+// matchChunk sends a single match request
+func (p *OSRMProvider) matchChunk(ctx context.Context, points []models.Location) ([]models.Location, error) {
+	// OSRM needs at least two coordinates to match a trace
+	if len(points) < 2 {
+		return points, nil
+	}
+
+	coordinates := make([]string, len(points))
+	timestamps := make([]string, len(points))
+	radiuses := make([]string, len(points))
+	for i, point := range points {
+		coordinates[i] = fmt.Sprintf("%f,%f", point.Longitude, point.Latitude)
+		timestamps[i] = strconv.FormatInt(point.Timestamp.Unix(), 10)
+
+		radius := osrmDefaultRadius
+		if point.AccuracyMeters != nil {
+			radius = *point.AccuracyMeters
+		}
+		radiuses[i] = strconv.FormatFloat(radius, 'f', 1, 64)
+	}
+
+	url := fmt.Sprintf("%s/match/v1/%s/%s?timestamps=%s&radiuses=%s&overview=false&gaps=ignore",
+		p.baseURL, p.profile,
+		strings.Join(coordinates, ";"),
+		strings.Join(timestamps, ";"),
+		strings.Join(radiuses, ";"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OSRM request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call OSRM: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// OSRM reports failures such as NoMatch with a 400 and a JSON body, but a proxy in front of
+	// it may answer with anything, so the status is checked whatever the body holds
+	var body osrmMatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("OSRM match failed: status %d", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to decode OSRM response: %w", err)
+	}
+
+	// NoMatch means nothing could be snapped; keep the raw trace
+	if body.Code == "NoMatch" {
+		return points, nil
+	}
+	if resp.StatusCode != http.StatusOK || body.Code != "Ok" {
+		return nil, fmt.Errorf("OSRM match failed: status %d: %s: %s", resp.StatusCode, body.Code, body.Message)
+	}
+
+	matched := make([]models.Location, len(points))
+	for i, point := range points {
+		matched[i] = point
+		if i < len(body.Tracepoints) && body.Tracepoints[i] != nil {
+			matched[i].Longitude = body.Tracepoints[i].Location[0]
+			matched[i].Latitude = body.Tracepoints[i].Location[1]
+		}
+	}
+	return matched, nil
+}
@@ -0,0 +1,212 @@
+// Package metrics defines the Prometheus metrics exported by the tracking-service
+// Version: 1.0.0
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus" // v1.14.0
+)
+
+// Human Tasks:
+// 1. Add the tracking-service /metrics endpoint to the Prometheus scrape configuration
+// 2. Create Grafana panels for location flush latency and batch sizes
+// 3. Configure alerts on location flush failures and dropped points
+// 4. Watch location_duplicates_total for clients whose retry rate suggests a connectivity bug
+
+var (
+	// LocationFlushDuration records how long each InsertMany flush of buffered locations takes
+	// Addresses requirement: Real-time location tracking
+	// Location: 1.2 System Overview/High-Level Description/Backend Services
+	LocationFlushDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "tracking",
+		Name:      "location_flush_duration_seconds",
+		Help:      "Duration of batched location inserts into MongoDB.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// LocationFlushSize records the number of location points written per flush
+	LocationFlushSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "tracking",
+		Name:      "location_flush_batch_size",
+		Help:      "Number of location points written per batched insert.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+	})
+
+	// LocationFlushErrors counts failed batched inserts
+	LocationFlushErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "location_flush_errors_total",
+		Help:      "Number of batched location inserts that failed.",
+	})
+
+	// LocationsDropped counts buffered points discarded after repeated flush failures
+	LocationsDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "locations_dropped_total",
+		Help:      "Number of buffered location points dropped after flush failures.",
+	})
+
+	// LocationDuplicates counts retried points discarded instead of stored, by the layer that
+	// caught them: "cache" for this instance's recent points, "index" for the unique index
+	LocationDuplicates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "location_duplicates_total",
+		Help:      "Number of duplicate location points discarded, by detection layer.",
+	}, []string{"layer"})
+
+	// LocationsLate counts points stored but kept off the live stream because a newer point of
+	// their session had already been broadcast
+	LocationsLate = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "locations_late_total",
+		Help:      "Number of out-of-order location points stored without being broadcast.",
+	})
+
+	// LocationsMasked counts points withheld from owners because they lie in a walker privacy zone
+	LocationsMasked = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "locations_masked_total",
+		Help:      "Number of location points hidden from owners by walker privacy zones.",
+	})
+
+	// ExportJobs counts finished history exports by outcome
+	ExportJobs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "export_jobs_total",
+		Help:      "Number of location history exports finished, by status.",
+	}, []string{"status"})
+
+	// ExportDuration records how long successful history exports take to write
+	ExportDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "tracking",
+		Name:      "export_duration_seconds",
+		Help:      "Duration of successful location history exports.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+	})
+
+	// HubRateLimited counts messages the WebSocket hub dropped for exceeding a rate limit, by
+	// scope: "session" for publishes to one topic, "tenant" for fan-out, "connection" for a
+	// client's own messages
+	HubRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "hub_rate_limited_total",
+		Help:      "Number of messages dropped by the WebSocket hub for exceeding a rate limit, by scope.",
+	}, []string{"scope"})
+
+	// HubClients is the number of WebSocket clients connected to this instance
+	HubClients = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tracking",
+		Name:      "hub_clients",
+		Help:      "Number of WebSocket clients connected to this instance.",
+	})
+
+	// HubRooms is the number of topics with at least one subscriber on this instance
+	HubRooms = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tracking",
+		Name:      "hub_rooms",
+		Help:      "Number of topics with WebSocket subscribers on this instance.",
+	})
+
+	// HubSubscriptions is the number of client subscriptions across every room, counting a
+	// dispatch client once per walk it follows
+	HubSubscriptions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tracking",
+		Name:      "hub_subscriptions",
+		Help:      "Number of WebSocket client subscriptions across all topics on this instance.",
+	})
+
+	// HubSendQueueDepth samples how many messages wait in each client's send buffer
+	HubSendQueueDepth = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "tracking",
+		Name:      "hub_send_queue_depth",
+		Help:      "Messages waiting in WebSocket client send buffers, sampled per client.",
+		Buckets:   []float64{0, 1, 2, 4, 8, 16, 32, 64},
+	})
+
+	// HubDropped counts messages the hub dropped, by reason: "send_buffer_full" for each client
+	// disconnected for not keeping up, "drop_oldest" and "coalesced" for messages discarded for
+	// slow clients, "unregistered_kind" for messages of unknown kinds, "hub_stopped" for
+	// messages published during shutdown, "fault_injected" for frames discarded by fault injection
+	HubDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "hub_dropped_messages_total",
+		Help:      "Number of messages dropped by the WebSocket hub, by reason.",
+	}, []string{"reason"})
+
+	// HubBroadcastLatency records how long messages take from being published to reaching
+	// every subscriber's send buffer
+	HubBroadcastLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "tracking",
+		Name:      "hub_broadcast_latency_seconds",
+		Help:      "Time from publishing a message to queueing it for every subscriber.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12),
+	})
+
+	// HubSlowConsumers counts the times WebSocket clients started falling behind, by client app
+	// and the policy applied to them
+	HubSlowConsumers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "hub_slow_consumers_total",
+		Help:      "Number of times WebSocket clients fell behind, by client app and slow consumer policy.",
+	}, []string{"app", "policy"})
+
+	// HubConnectionsRefused counts WebSocket connections closed on arrival for exceeding a
+	// connection limit, by limit: "instance" or "user"
+	HubConnectionsRefused = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "hub_connections_refused_total",
+		Help:      "Number of WebSocket connections refused for exceeding a connection limit, by limit.",
+	}, []string{"limit"})
+
+	// WebSocketMessageBytes counts the bytes of messages written to WebSocket clients before
+	// compression, by whether they were compressed
+	WebSocketMessageBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tracking",
+		Name:      "websocket_message_bytes_total",
+		Help:      "Uncompressed bytes of messages written to WebSocket clients, by whether they were compressed.",
+	}, []string{"compressed"})
+
+	// WebSocketWriteDuration records how long writing a message to a WebSocket client takes,
+	// including compression, by whether it was compressed
+	WebSocketWriteDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tracking",
+		Name:      "websocket_write_duration_seconds",
+		Help:      "Duration of writing a message to a WebSocket client, by whether it was compressed.",
+		Buckets:   prometheus.ExponentialBuckets(0.00001, 4, 10),
+	}, []string{"compressed"})
+
+	// WebSocketCompressionRatio samples the compressed size of messages as a fraction of their
+	// original size
+	WebSocketCompressionRatio = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "tracking",
+		Name:      "websocket_compression_ratio",
+		Help:      "Compressed size of sampled WebSocket messages as a fraction of their original size.",
+		Buckets:   prometheus.LinearBuckets(0.1, 0.1, 10),
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		LocationFlushDuration,
+		LocationFlushSize,
+		LocationFlushErrors,
+		LocationsDropped,
+		LocationDuplicates,
+		LocationsLate,
+		LocationsMasked,
+		ExportJobs,
+		ExportDuration,
+		HubRateLimited,
+		HubClients,
+		HubRooms,
+		HubSubscriptions,
+		HubSendQueueDepth,
+		HubDropped,
+		HubBroadcastLatency,
+		HubSlowConsumers,
+		HubConnectionsRefused,
+		WebSocketMessageBytes,
+		WebSocketWriteDuration,
+		WebSocketCompressionRatio,
+	)
+}
@@ -0,0 +1,77 @@
+// Package models provides data models for the tracking service
+package models
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxChatMessageLength is the longest a chat message body may be, in characters
+const MaxChatMessageLength = 2000
+
+// ChatMessage is a message between the owner and the walker of a booking.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type ChatMessage struct {
+	// ID is the unique identifier of the message
+	ID string `json:"id" bson:"_id"`
+
+	// BookingID is the booking the conversation belongs to
+	BookingID string `json:"booking_id" bson:"booking_id"`
+
+	// SenderID is the user who sent the message
+	SenderID string `json:"sender_id" bson:"sender_id"`
+
+	// SenderRole is whether the sender is the booking's walker or owner
+	SenderRole ConsentRole `json:"sender_role" bson:"sender_role"`
+
+	// Body is the text of the message
+	Body string `json:"body" bson:"body"`
+
+	// CreatedAt is when the message was sent
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+
+	// ReadAt is when the other participant read the message; nil while unread
+	ReadAt *time.Time `json:"read_at,omitempty" bson:"read_at,omitempty"`
+
+	// Report is set once the recipient has reported the message as abusive
+	Report *ChatReport `json:"report,omitempty" bson:"report,omitempty"`
+}
+
+// ChatReport records a participant reporting a message as abusive
+type ChatReport struct {
+	// ReportedBy is the user who reported the message
+	ReportedBy string `json:"reported_by" bson:"reported_by"`
+
+	// Reason is the reporter's explanation
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+
+	// IncidentID is the incident opened for the report
+	IncidentID string `json:"incident_id" bson:"incident_id"`
+
+	// ReportedAt is when the message was reported
+	ReportedAt time.Time `json:"reported_at" bson:"reported_at"`
+}
+
+// Validate performs validation checks on the ChatMessage instance.
+func (m *ChatMessage) Validate() error {
+	if m.ID == "" {
+		return fmt.Errorf("message ID is required")
+	}
+	if m.BookingID == "" {
+		return fmt.Errorf("booking_id is required")
+	}
+	if m.SenderID == "" {
+		return fmt.Errorf("sender_id is required")
+	}
+	if m.SenderRole != ConsentRoleWalker && m.SenderRole != ConsentRoleOwner {
+		return fmt.Errorf("invalid sender_role %q: must be walker or owner", m.SenderRole)
+	}
+	if m.Body == "" {
+		return fmt.Errorf("body is required")
+	}
+	if utf8.RuneCountInString(m.Body) > MaxChatMessageLength {
+		return fmt.Errorf("body must be at most %d characters", MaxChatMessageLength)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Package models provides data models for the tracking service
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConsentRole is the part a consenting person plays in the booking
+type ConsentRole string
+
+// Consent role constants
+const (
+	ConsentRoleWalker ConsentRole = "walker"
+	ConsentRoleOwner  ConsentRole = "owner"
+)
+
+// Consent records one person's explicit agreement to location tracking for a booking. Records
+// are never changed except to note a revocation, so they double as an audit trail.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type Consent struct {
+	// ID is the unique identifier of the record
+	ID string `json:"id" bson:"_id"`
+
+	// BookingID is the booking the consent covers
+	BookingID string `json:"booking_id" bson:"booking_id"`
+
+	// SubjectID is the user who gave consent
+	SubjectID string `json:"subject_id" bson:"subject_id"`
+
+	// Role is whether the subject is the booking's walker or owner
+	Role ConsentRole `json:"role" bson:"role"`
+
+	// TermsVersion is the version of the tracking terms the subject agreed to
+	TermsVersion string `json:"terms_version" bson:"terms_version"`
+
+	// GrantedAt is when consent was given
+	GrantedAt time.Time `json:"granted_at" bson:"granted_at"`
+
+	// RevokedAt is when consent was withdrawn; nil while it stands
+	RevokedAt *time.Time `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+}
+
+// Validate performs validation checks on the Consent instance.
+func (c *Consent) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("consent ID is required")
+	}
+	if c.BookingID == "" {
+		return fmt.Errorf("booking_id is required")
+	}
+	if c.SubjectID == "" {
+		return fmt.Errorf("subject_id is required")
+	}
+	if c.Role != ConsentRoleWalker && c.Role != ConsentRoleOwner {
+		return fmt.Errorf("invalid role %q: must be walker or owner", c.Role)
+	}
+	if c.TermsVersion == "" {
+		return fmt.Errorf("terms_version is required")
+	}
+	return nil
+}
+
+// IsActive reports whether the consent has not been revoked
+func (c *Consent) IsActive() bool {
+	return c.RevokedAt == nil
+}
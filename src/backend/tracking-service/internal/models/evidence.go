@@ -0,0 +1,36 @@
+// Package models provides data models for the tracking service
+package models
+
+// WalkEvidence sums up what was tracked of a booking's walks, across every session started for
+// it, so the booking-service can check a walk happened before completing the booking.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type WalkEvidence struct {
+	// BookingID is the booking the walks were for
+	BookingID string `json:"booking_id"`
+
+	// Sessions is the number of walk sessions started for the booking
+	Sessions int `json:"sessions"`
+
+	// Group reports whether any of the sessions was a group walk shared with other bookings;
+	// their duration and distance are the whole walk's, and only photos showing this
+	// booking's dogs are counted
+	Group bool `json:"group"`
+
+	// Active reports whether a session is still in progress, in which case DurationSeconds
+	// counts up to now
+	Active bool `json:"active"`
+
+	// DurationSeconds is the time spent walking, summed over the sessions; paused time is
+	// left out
+	DurationSeconds float64 `json:"duration_seconds"`
+
+	// PausedSeconds is the time the walks were paused, summed over the sessions
+	PausedSeconds float64 `json:"paused_seconds"`
+
+	// DistanceMeters is the length of the tracked routes, summed over the sessions; stretches
+	// covered while paused are left out
+	DistanceMeters float64 `json:"distance_meters"`
+
+	// Photos is the number of photos the walker took
+	Photos int `json:"photos"`
+}
@@ -0,0 +1,149 @@
+// Package models provides data models for the tracking service
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExportFormat is the file format of a location history export
+type ExportFormat string
+
+// Export format constants
+const (
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatGeoJSON ExportFormat = "geojson"
+	ExportFormatGPX     ExportFormat = "gpx"
+)
+
+// IsValid reports whether f is a supported export format.
+func (f ExportFormat) IsValid() bool {
+	switch f {
+	case ExportFormatCSV, ExportFormatGeoJSON, ExportFormatGPX:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type of files in format f.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatCSV:
+		return "text/csv"
+	case ExportFormatGeoJSON:
+		return "application/geo+json"
+	case ExportFormatGPX:
+		return "application/gpx+xml"
+	}
+	return "application/octet-stream"
+}
+
+// ExportStatus represents the processing state of an export job
+type ExportStatus string
+
+// Export status constants
+const (
+	ExportStatusQueued    ExportStatus = "queued"
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// maxExportRange is the longest time range a single export may cover
+const maxExportRange = 31 * 24 * time.Hour
+
+// ExportJob is an asynchronous export of location history to a file in object storage.
+// Exports cover either one walk session or every point in a time range.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type ExportJob struct {
+	// ID is the unique identifier of the job
+	ID string `json:"id" bson:"_id"`
+
+	// Format is the file format being produced
+	Format ExportFormat `json:"format" bson:"format"`
+
+	// Status is the current processing state
+	Status ExportStatus `json:"status" bson:"status"`
+
+	// SessionID restricts the export to one walk session
+	SessionID string `json:"session_id,omitempty" bson:"session_id,omitempty"`
+
+	// StartTime and EndTime restrict the export to points recorded in [StartTime, EndTime]
+	StartTime *time.Time `json:"start_time,omitempty" bson:"start_time,omitempty"`
+	EndTime   *time.Time `json:"end_time,omitempty" bson:"end_time,omitempty"`
+
+	// Snapped exports the session's route snapped to the street/path network
+	Snapped bool `json:"snapped,omitempty" bson:"snapped,omitempty"`
+
+	// RequestedBy is the user who requested the export; only they and admins may read it
+	RequestedBy string `json:"requested_by" bson:"requested_by,omitempty"`
+
+	// PointCount is the number of points written, once the job has completed
+	PointCount int64 `json:"point_count" bson:"point_count"`
+
+	// ObjectKey locates the finished file in object storage
+	ObjectKey string `json:"-" bson:"object_key,omitempty"`
+
+	// Error describes why a failed job failed
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
+
+	// DownloadURL is a short-lived link to the finished file; it is issued on read, never stored
+	DownloadURL string `json:"download_url,omitempty" bson:"-"`
+
+	// CreatedAt is when the job was requested
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+
+	// UpdatedAt is when the job last changed; running jobs that stop updating are retried
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+
+	// CompletedAt is when the job finished, successfully or not
+	CompletedAt *time.Time `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// NewExportJob creates a queued ExportJob requested now.
+func NewExportJob(id string, format ExportFormat, sessionID string, startTime, endTime *time.Time) *ExportJob {
+	now := time.Now()
+	return &ExportJob{
+		ID:        id,
+		Format:    format,
+		Status:    ExportStatusQueued,
+		SessionID: sessionID,
+		StartTime: startTime,
+		EndTime:   endTime,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate performs validation checks on the ExportJob instance.
+func (j *ExportJob) Validate() error {
+	if j.ID == "" {
+		return fmt.Errorf("export ID is required")
+	}
+	if !j.Format.IsValid() {
+		return fmt.Errorf("unsupported format %q: must be csv, geojson or gpx", j.Format)
+	}
+	if (j.StartTime == nil) != (j.EndTime == nil) {
+		return fmt.Errorf("start_time and end_time must be given together")
+	}
+	if j.SessionID == "" && j.StartTime == nil {
+		return fmt.Errorf("a session_id or a time range is required")
+	}
+	if j.Snapped && j.SessionID == "" {
+		return fmt.Errorf("only a session's route can be snapped")
+	}
+	if j.StartTime != nil {
+		if j.EndTime.Before(*j.StartTime) {
+			return fmt.Errorf("end_time must be after start_time")
+		}
+		if j.SessionID == "" && j.EndTime.Sub(*j.StartTime) > maxExportRange {
+			return fmt.Errorf("time range exceeds the maximum of %v", maxExportRange)
+		}
+	}
+	return nil
+}
+
+// IsFinished reports whether the job has completed or failed.
+func (j *ExportJob) IsFinished() bool {
+	return j.Status == ExportStatusCompleted || j.Status == ExportStatusFailed
+}
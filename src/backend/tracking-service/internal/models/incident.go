@@ -0,0 +1,160 @@
+// Package models provides data models for the tracking service
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// IncidentType classifies an incident
+type IncidentType string
+
+// Incident type constants
+const (
+	// IncidentTypeSOS is an emergency raised by the walker from the app
+	IncidentTypeSOS         IncidentType = "sos"
+	IncidentTypeInjury      IncidentType = "injury"
+	IncidentTypeLostDog     IncidentType = "lost_dog"
+	IncidentTypeAltercation IncidentType = "altercation"
+	IncidentTypeOther       IncidentType = "other"
+
+	// IncidentTypeAbusiveMessage is a chat message reported by its recipient
+	IncidentTypeAbusiveMessage IncidentType = "abusive_message"
+)
+
+// IsValid reports whether t is a known incident type.
+func (t IncidentType) IsValid() bool {
+	switch t {
+	case IncidentTypeSOS, IncidentTypeInjury, IncidentTypeLostDog, IncidentTypeAltercation, IncidentTypeOther, IncidentTypeAbusiveMessage:
+		return true
+	}
+	return false
+}
+
+// IncidentStatus represents the handling state of an incident
+type IncidentStatus string
+
+// Incident status constants
+const (
+	IncidentStatusOpen          IncidentStatus = "open"
+	IncidentStatusInvestigating IncidentStatus = "investigating"
+	IncidentStatusResolved      IncidentStatus = "resolved"
+	IncidentStatusClosed        IncidentStatus = "closed"
+)
+
+// incidentTransitions lists the statuses each status may move to
+var incidentTransitions = map[IncidentStatus][]IncidentStatus{
+	IncidentStatusOpen:          {IncidentStatusInvestigating, IncidentStatusResolved},
+	IncidentStatusInvestigating: {IncidentStatusResolved},
+	IncidentStatusResolved:      {IncidentStatusClosed, IncidentStatusInvestigating},
+}
+
+// Attachment references a file held by the media subsystem
+type Attachment struct {
+	// MediaID identifies the file in the media subsystem
+	MediaID string `json:"media_id" bson:"media_id"`
+
+	// URL is where the file can be retrieved
+	URL string `json:"url" bson:"url"`
+
+	// ContentType is the file's MIME type
+	ContentType string `json:"content_type,omitempty" bson:"content_type,omitempty"`
+
+	// AddedBy is the user who attached the file
+	AddedBy string `json:"added_by,omitempty" bson:"added_by,omitempty"`
+
+	// AddedAt is when the file was attached
+	AddedAt time.Time `json:"added_at" bson:"added_at"`
+}
+
+// Validate performs validation checks on the Attachment instance.
+func (a *Attachment) Validate() error {
+	if a.MediaID == "" {
+		return fmt.Errorf("media ID is required")
+	}
+	if a.URL == "" {
+		return fmt.Errorf("attachment URL is required")
+	}
+	return nil
+}
+
+// Incident records something that went wrong during a walk, where it happened and who was involved.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type Incident struct {
+	// ID is the unique identifier of the incident
+	ID string `json:"id" bson:"_id"`
+
+	// Type classifies the incident
+	Type IncidentType `json:"type" bson:"type"`
+
+	// Status is the current handling state
+	Status IncidentStatus `json:"status" bson:"status"`
+
+	// SessionID is the walk session during which the incident occurred, if any
+	SessionID string `json:"session_id,omitempty" bson:"session_id,omitempty"`
+
+	// BookingID is the booking the incident relates to
+	BookingID string `json:"booking_id" bson:"booking_id"`
+
+	// WalkerID is the walker on the walk
+	WalkerID string `json:"walker_id,omitempty" bson:"walker_id,omitempty"`
+
+	// OwnerID is the dog owner
+	OwnerID string `json:"owner_id,omitempty" bson:"owner_id,omitempty"`
+
+	// ReportedBy is the user who reported the incident
+	ReportedBy string `json:"reported_by,omitempty" bson:"reported_by,omitempty"`
+
+	// Message is the free-text description supplied by the reporter
+	Message string `json:"message,omitempty" bson:"message,omitempty"`
+
+	// Location is where the incident was reported from, if known
+	Location *Location `json:"location,omitempty" bson:"location,omitempty"`
+
+	// Region is the service region the incident happened in, from its location or its walk
+	Region string `json:"region,omitempty" bson:"region,omitempty"`
+
+	// Attachments are photos and documents supporting the report
+	Attachments []Attachment `json:"attachments,omitempty" bson:"attachments,omitempty"`
+
+	// Resolution describes how the incident was resolved
+	Resolution string `json:"resolution,omitempty" bson:"resolution,omitempty"`
+
+	// CreatedAt is when the incident was reported
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+
+	// UpdatedAt is when the incident was last changed
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+
+	// ResolvedAt is when the incident was resolved; nil while it is unresolved
+	ResolvedAt *time.Time `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`
+}
+
+// Validate performs validation checks on the Incident instance.
+func (i *Incident) Validate() error {
+	if i.ID == "" {
+		return fmt.Errorf("incident ID is required")
+	}
+	if !i.Type.IsValid() {
+		return fmt.Errorf("unknown incident type %q", i.Type)
+	}
+	if i.SessionID == "" && i.BookingID == "" {
+		return fmt.Errorf("session ID or booking ID is required")
+	}
+	if i.Location != nil {
+		if err := i.Location.Validate(); err != nil {
+			return fmt.Errorf("invalid incident location: %w", err)
+		}
+	}
+	return nil
+}
+
+// CanTransition reports whether the incident may move from its current status to next.
+func (i *Incident) CanTransition(next IncidentStatus) bool {
+	for _, allowed := range incidentTransitions[i.Status] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
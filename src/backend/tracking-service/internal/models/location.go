@@ -2,6 +2,8 @@
 package models
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -15,14 +17,59 @@ import (
 // Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
 // The Location model is used for real-time location tracking and processing.
 type Location struct {
+	// SessionID identifies the walk session this point belongs to
+	SessionID string `json:"session_id,omitempty" bson:"session_id,omitempty"`
+
 	// Latitude represents the geographical latitude coordinate
-	Latitude float64 `json:"latitude" bson:"latitude"`
+	Latitude float64 `json:"latitude" bson:"latitude,omitempty"`
 
 	// Longitude represents the geographical longitude coordinate
-	Longitude float64 `json:"longitude" bson:"longitude"`
+	Longitude float64 `json:"longitude" bson:"longitude,omitempty"`
 
 	// Timestamp represents when this location was recorded
 	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+
+	// AccuracyMeters is the device-reported horizontal accuracy radius, if known
+	AccuracyMeters *float64 `json:"accuracy_meters,omitempty" bson:"accuracy_meters,omitempty"`
+
+	// Altitude is the device-reported altitude in meters, if known
+	Altitude *float64 `json:"altitude,omitempty" bson:"altitude,omitempty"`
+
+	// Speed is the device-reported ground speed in meters per second, if known
+	Speed *float64 `json:"speed,omitempty" bson:"speed,omitempty"`
+
+	// Heading is the device-reported direction of travel in degrees from true north, if known
+	Heading *float64 `json:"heading,omitempty" bson:"heading,omitempty"`
+
+	// BatteryPercent is the walker device's battery level, if reported
+	BatteryPercent *float64 `json:"battery_percent,omitempty" bson:"battery_percent,omitempty"`
+
+	// DeviceSeq is the device's own counter for the points it sends; together with the session
+	// and timestamp it identifies a point across client retries
+	DeviceSeq *int64 `json:"device_seq,omitempty" bson:"device_seq,omitempty"`
+
+	// Cell is the geohash cell of the point, stored for proximity lookups and heatmaps. It is
+	// kept in plain text even when the coordinates are sealed, so it is stored at a coarse
+	// precision, and never for points inside a privacy zone.
+	Cell string `json:"-" bson:"cell,omitempty"`
+
+	// Late marks a point that arrived after a newer point of its session had been broadcast.
+	// Late points are stored for history and summaries but never sent to live subscribers.
+	Late bool `json:"late,omitempty" bson:"late,omitempty"`
+
+	// Paused marks a point recorded while the walker had paused the walk. Paused points are
+	// kept in history, exports and the live stream but left out of distance summaries.
+	Paused bool `json:"paused,omitempty" bson:"paused,omitempty"`
+
+	// Smoothed marks a broadcast point whose coordinates are the walk's filtered position
+	// rather than the reported fix. It is never stored; stored points keep the reported fix.
+	Smoothed bool `json:"smoothed,omitempty" bson:"-"`
+
+	// SealedCoordinates holds Latitude and Longitude encrypted under CoordinatesKey when location
+	// encryption is enabled; the plain fields are then not stored. The repository seals and
+	// opens them, so callers only ever see plain coordinates.
+	SealedCoordinates []byte `json:"-" bson:"coords_enc,omitempty"`
+	CoordinatesKey    string `json:"-" bson:"coords_key,omitempty"`
 }
 
 // NewLocation creates a new Location instance with the provided coordinates and timestamp.
@@ -58,5 +105,44 @@ func (l *Location) Validate() error {
 		return fmt.Errorf("invalid timestamp: cannot be in the future")
 	}
 
+	// Validate optional device metadata when present
+	if l.AccuracyMeters != nil && *l.AccuracyMeters < 0 {
+		return fmt.Errorf("invalid accuracy_meters: cannot be negative")
+	}
+	if l.Speed != nil && *l.Speed < 0 {
+		return fmt.Errorf("invalid speed: cannot be negative")
+	}
+	if l.Heading != nil && (*l.Heading < 0 || *l.Heading >= 360) {
+		return fmt.Errorf("invalid heading: must be between 0 and 360")
+	}
+	if l.BatteryPercent != nil && (*l.BatteryPercent < 0 || *l.BatteryPercent > 100) {
+		return fmt.Errorf("invalid battery_percent: must be between 0 and 100")
+	}
+
 	return nil
+}
+
+// DedupeKey identifies the point for duplicate detection. A client retrying a point sends the
+// same session, timestamp and device sequence number, so the retry has the same key.
+func (l *Location) DedupeKey() string {
+	seq := "-"
+	if l.DeviceSeq != nil {
+		seq = strconv.FormatInt(*l.DeviceSeq, 10)
+	}
+	return l.SessionID + "|" + strconv.FormatInt(l.Timestamp.UnixNano(), 10) + "|" + seq
+}
+
+// Precedes reports whether l was recorded before other. Points with the same timestamp are
+// ordered by device sequence number when both have one.
+func (l *Location) Precedes(other *Location) bool {
+	if !l.Timestamp.Equal(other.Timestamp) {
+		return l.Timestamp.Before(other.Timestamp)
+	}
+	return l.DeviceSeq != nil && other.DeviceSeq != nil && *l.DeviceSeq < *other.DeviceSeq
+}
+
+// IsPrecise reports whether the point's reported accuracy is within maxAccuracyMeters.
+// Points without a reported accuracy are treated as precise.
+func (l *Location) IsPrecise(maxAccuracyMeters float64) bool {
+	return l.AccuracyMeters == nil || *l.AccuracyMeters <= maxAccuracyMeters
 }
\ No newline at end of file
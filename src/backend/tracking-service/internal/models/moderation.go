@@ -0,0 +1,71 @@
+// Package models provides data models for the tracking service
+package models
+
+import (
+	"time"
+)
+
+// FlaggedSource identifies what kind of content was flagged
+type FlaggedSource string
+
+// Flagged content sources
+const (
+	FlaggedSourceChatMessage FlaggedSource = "chat_message"
+)
+
+// FlaggedStatus is where flagged content is in admin review
+type FlaggedStatus string
+
+// Flagged content statuses
+const (
+	// FlaggedStatusPending awaits review
+	FlaggedStatusPending FlaggedStatus = "pending"
+
+	// FlaggedStatusDismissed was reviewed and left in place
+	FlaggedStatusDismissed FlaggedStatus = "dismissed"
+
+	// FlaggedStatusRemoved was reviewed and taken down
+	FlaggedStatusRemoved FlaggedStatus = "removed"
+)
+
+// IsValid checks if the flagged status is one of the defined constants
+func (s FlaggedStatus) IsValid() bool {
+	switch s {
+	case FlaggedStatusPending, FlaggedStatusDismissed, FlaggedStatusRemoved:
+		return true
+	}
+	return false
+}
+
+// FlaggedContent is content the moderation filter let through but queued for an admin to review.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type FlaggedContent struct {
+	// ID is the unique identifier of the flag
+	ID string `json:"id" bson:"_id"`
+
+	// Source and SourceID identify the flagged content, such as a chat message
+	Source   FlaggedSource `json:"source" bson:"source"`
+	SourceID string        `json:"source_id" bson:"source_id"`
+
+	// BookingID is the booking the content was written for
+	BookingID string `json:"booking_id" bson:"booking_id"`
+
+	// AuthorID is the user who wrote the content
+	AuthorID string `json:"author_id" bson:"author_id"`
+
+	// Text is the content as it was flagged, kept even if the content is later removed
+	Text string `json:"text" bson:"text"`
+
+	// Matches are what the filter matched in Text
+	Matches []string `json:"matches,omitempty" bson:"matches,omitempty"`
+
+	// Status is where the flag is in review
+	Status FlaggedStatus `json:"status" bson:"status"`
+
+	// CreatedAt is when the content was flagged
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+
+	// ReviewedBy and ReviewedAt record the admin decision; empty while pending
+	ReviewedBy string     `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+}
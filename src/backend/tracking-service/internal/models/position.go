@@ -0,0 +1,53 @@
+// Package models provides data models for the tracking service
+package models
+
+import "time"
+
+// WalkerPosition is the cell a walker was last seen in during a walk, kept so walkers near a
+// point can be found without reading their location history.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type WalkerPosition struct {
+	// WalkerID is the walker the position belongs to
+	WalkerID string `json:"walker_id" bson:"_id"`
+
+	// SessionID is the walk session the position was reported in
+	SessionID string `json:"session_id" bson:"session_id"`
+
+	// Cell is the geohash cell of the walker's last position
+	Cell string `json:"cell" bson:"cell"`
+
+	// UpdatedAt is when the walker was last seen in the cell
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// NearbyWalker is a walker found near a point, with the approximate distance to the centre
+// of the cell they were last seen in
+type NearbyWalker struct {
+	WalkerPosition `bson:",inline"`
+
+	// DistanceMeters is the distance from the searched point to the centre of the walker's cell
+	DistanceMeters float64 `json:"distance_meters"`
+}
+
+// LocatedWalker is a walker's last position with the centre of the cell they were seen in, for
+// services that need coordinates rather than a cell
+type LocatedWalker struct {
+	WalkerPosition `bson:",inline"`
+
+	// Latitude and Longitude are the centre of the walker's cell, accurate to about 100m
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// HeatmapCell counts the location points recorded in one geohash cell
+type HeatmapCell struct {
+	// Cell is the geohash of the cell
+	Cell string `json:"cell" bson:"_id"`
+
+	// Count is the number of points recorded in the cell
+	Count int64 `json:"count" bson:"count"`
+
+	// Latitude and Longitude are the centre of the cell
+	Latitude  float64 `json:"latitude" bson:"-"`
+	Longitude float64 `json:"longitude" bson:"-"`
+}
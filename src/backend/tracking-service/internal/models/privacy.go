@@ -0,0 +1,78 @@
+// Package models provides data models for the tracking service
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Privacy zone radius bounds in meters. The lower bound keeps a zone wider than typical GPS
+// error; the upper bound stops a zone from hiding a whole walk.
+const (
+	MinPrivacyZoneRadius = 50
+	MaxPrivacyZoneRadius = 1000
+)
+
+// earthRadiusMeters is the mean Earth radius used for distance calculations
+const earthRadiusMeters = 6371000
+
+// PrivacyZone is a circle, typically around a walker's home, inside which the walker's
+// location is stored but never shown to owners.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type PrivacyZone struct {
+	// ID is the unique identifier of the zone
+	ID string `json:"id" bson:"_id"`
+
+	// WalkerID is the walker the zone belongs to
+	WalkerID string `json:"walker_id" bson:"walker_id"`
+
+	// Name is the walker's label for the zone, such as "Home"
+	Name string `json:"name" bson:"name"`
+
+	// Latitude and Longitude are the centre of the zone
+	Latitude  float64 `json:"latitude" bson:"latitude"`
+	Longitude float64 `json:"longitude" bson:"longitude"`
+
+	// RadiusMeters is the radius of the zone
+	RadiusMeters float64 `json:"radius_meters" bson:"radius_meters"`
+
+	// CreatedAt is when the zone was defined
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// Validate performs validation checks on the PrivacyZone instance.
+func (z *PrivacyZone) Validate() error {
+	if z.ID == "" {
+		return fmt.Errorf("privacy zone ID is required")
+	}
+	if z.WalkerID == "" {
+		return fmt.Errorf("walker_id is required")
+	}
+	if z.Latitude < -90 || z.Latitude > 90 {
+		return fmt.Errorf("invalid latitude: must be between -90 and 90")
+	}
+	if z.Longitude < -180 || z.Longitude > 180 {
+		return fmt.Errorf("invalid longitude: must be between -180 and 180")
+	}
+	if z.RadiusMeters < MinPrivacyZoneRadius || z.RadiusMeters > MaxPrivacyZoneRadius {
+		return fmt.Errorf("invalid radius_meters: must be between %d and %d", MinPrivacyZoneRadius, MaxPrivacyZoneRadius)
+	}
+	return nil
+}
+
+// Contains reports whether location lies inside the zone
+func (z *PrivacyZone) Contains(location Location) bool {
+	return DistanceMeters(z.Latitude, z.Longitude, location.Latitude, location.Longitude) <= z.RadiusMeters
+}
+
+// DistanceMeters returns the great-circle distance between two coordinates
+func DistanceMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
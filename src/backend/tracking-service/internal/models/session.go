@@ -0,0 +1,262 @@
+// Package models provides data models for the tracking service
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// SessionStatus represents the lifecycle state of a walk session
+type SessionStatus string
+
+// Session status constants
+const (
+	SessionStatusActive SessionStatus = "active"
+	SessionStatusEnded  SessionStatus = "ended"
+)
+
+// Session represents a single tracked walk, linking location points to the booking
+// and the people who need to follow it.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+type Session struct {
+	// ID is the unique identifier of the walk session
+	ID string `json:"id" bson:"_id"`
+
+	// BookingID is the booking this walk fulfils
+	BookingID string `json:"booking_id" bson:"booking_id"`
+
+	// WalkerID is the walker reporting locations for the session
+	WalkerID string `json:"walker_id" bson:"walker_id"`
+
+	// OwnerID is the dog owner following the walk
+	OwnerID string `json:"owner_id" bson:"owner_id"`
+
+	// Bookings lists every booking walked together in a group walk, each with the owner
+	// following it; the first is BookingID and OwnerID. Empty for a walk of a single booking.
+	Bookings []SessionBooking `json:"bookings,omitempty" bson:"bookings,omitempty"`
+
+	// Status is the current lifecycle state of the session
+	Status SessionStatus `json:"status" bson:"status"`
+
+	// StartedAt is when the walk began
+	StartedAt time.Time `json:"started_at" bson:"started_at"`
+
+	// EndedAt is when the walk finished; nil while the session is active
+	EndedAt *time.Time `json:"ended_at,omitempty" bson:"ended_at,omitempty"`
+
+	// Region is the service region the walk started in, set from its first located point
+	Region string `json:"region,omitempty" bson:"region,omitempty"`
+
+	// Photos are the photos the walker took during the walk, oldest first
+	Photos []WalkPhoto `json:"photos,omitempty" bson:"photos,omitempty"`
+
+	// PausedAt is when the current pause began; nil while the walker is walking
+	PausedAt *time.Time `json:"paused_at,omitempty" bson:"paused_at,omitempty"`
+
+	// Pauses are the finished pauses of the walk, oldest first
+	Pauses []WalkPause `json:"pauses,omitempty" bson:"pauses,omitempty"`
+}
+
+// WalkPause is a stretch of a walk the walker paused, such as popping into a store. Paused
+// time and the distance covered during it do not count towards the walk.
+type WalkPause struct {
+	// PausedAt is when the walker paused
+	PausedAt time.Time `json:"paused_at" bson:"paused_at"`
+
+	// ResumedAt is when the walker carried on
+	ResumedAt time.Time `json:"resumed_at" bson:"resumed_at"`
+}
+
+// SessionBooking is one of the bookings walked in a session, with the owner following it
+type SessionBooking struct {
+	// BookingID is the booking walked
+	BookingID string `json:"booking_id" bson:"booking_id"`
+
+	// OwnerID is the dog owner who made the booking
+	OwnerID string `json:"owner_id" bson:"owner_id"`
+}
+
+// WalkPhoto is a photo the walker took during a walk, kept as proof the walk happened. The
+// image itself is uploaded by the app; only its URL is recorded.
+type WalkPhoto struct {
+	// URL is where the uploaded image can be fetched
+	URL string `json:"url" bson:"url"`
+
+	// TakenAt is when the photo was taken
+	TakenAt time.Time `json:"taken_at" bson:"taken_at"`
+
+	// BookingIDs are the bookings of a group walk whose dogs the photo shows; empty when it
+	// is for every booking of the walk
+	BookingIDs []string `json:"booking_ids,omitempty" bson:"booking_ids,omitempty"`
+}
+
+// Validate performs validation checks on the WalkPhoto instance.
+func (p *WalkPhoto) Validate() error {
+	parsed, err := url.Parse(p.URL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if p.TakenAt.IsZero() {
+		return fmt.Errorf("taken_at is required")
+	}
+	return nil
+}
+
+// NewSession creates an active Session starting now.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func NewSession(id, bookingID, walkerID, ownerID string) *Session {
+	return &Session{
+		ID:        id,
+		BookingID: bookingID,
+		WalkerID:  walkerID,
+		OwnerID:   ownerID,
+		Status:    SessionStatusActive,
+		StartedAt: time.Now(),
+	}
+}
+
+// NewGroupSession creates an active Session starting now in which the walker walks the dogs
+// of several bookings together. A single booking makes an ordinary walk.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func NewGroupSession(id, walkerID string, bookings []SessionBooking) *Session {
+	session := &Session{
+		ID:        id,
+		WalkerID:  walkerID,
+		Status:    SessionStatusActive,
+		StartedAt: time.Now(),
+	}
+	if len(bookings) > 0 {
+		session.BookingID, session.OwnerID = bookings[0].BookingID, bookings[0].OwnerID
+	}
+	if len(bookings) > 1 {
+		session.Bookings = append([]SessionBooking(nil), bookings...)
+	}
+	return session
+}
+
+// Validate performs validation checks on the Session instance.
+func (s *Session) Validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+	if s.BookingID == "" {
+		return fmt.Errorf("booking ID is required")
+	}
+	if s.WalkerID == "" {
+		return fmt.Errorf("walker ID is required")
+	}
+	if s.OwnerID == "" {
+		return fmt.Errorf("owner ID is required")
+	}
+
+	seen := make(map[string]bool, len(s.Bookings))
+	for _, booking := range s.Bookings {
+		if booking.BookingID == "" || booking.OwnerID == "" {
+			return fmt.Errorf("every booking of a group walk needs a booking ID and an owner ID")
+		}
+		if seen[booking.BookingID] {
+			return fmt.Errorf("booking %s is listed more than once", booking.BookingID)
+		}
+		seen[booking.BookingID] = true
+	}
+	if len(s.Bookings) > 0 && (s.Bookings[0].BookingID != s.BookingID || s.Bookings[0].OwnerID != s.OwnerID) {
+		return fmt.Errorf("the first booking of a group walk must be its booking ID")
+	}
+	return nil
+}
+
+// AllBookings returns every booking walked in the session, the session's own booking first.
+func (s *Session) AllBookings() []SessionBooking {
+	if len(s.Bookings) > 0 {
+		return s.Bookings
+	}
+	return []SessionBooking{{BookingID: s.BookingID, OwnerID: s.OwnerID}}
+}
+
+// HasBooking reports whether the booking is walked in the session.
+func (s *Session) HasBooking(bookingID string) bool {
+	for _, booking := range s.AllBookings() {
+		if booking.BookingID == bookingID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasParticipant reports whether userID walks the session or follows one of its bookings.
+func (s *Session) HasParticipant(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	if s.WalkerID == userID {
+		return true
+	}
+	for _, booking := range s.AllBookings() {
+		if booking.OwnerID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGroup reports whether the session walks the dogs of more than one booking.
+func (s *Session) IsGroup() bool {
+	return len(s.Bookings) > 1
+}
+
+// IsActive reports whether the walk is still in progress.
+func (s *Session) IsActive() bool {
+	return s.Status == SessionStatusActive
+}
+
+// ShowsBooking reports whether the photo is for the booking; photos naming no booking are for
+// every booking of the walk.
+func (p *WalkPhoto) ShowsBooking(bookingID string) bool {
+	if len(p.BookingIDs) == 0 {
+		return true
+	}
+	for _, id := range p.BookingIDs {
+		if id == bookingID {
+			return true
+		}
+	}
+	return false
+}
+
+// Paused reports whether the walker has paused the walk and not yet carried on.
+func (s *Session) Paused() bool {
+	return s.PausedAt != nil
+}
+
+// AllPauses returns every pause of the walk, including the current one. A pause still open
+// when the walk ended lasts until the end; one still open on an active walk lasts until now.
+func (s *Session) AllPauses(now time.Time) []WalkPause {
+	pauses := append([]WalkPause(nil), s.Pauses...)
+	if s.PausedAt != nil {
+		end := now
+		if s.EndedAt != nil {
+			end = *s.EndedAt
+		}
+		pauses = append(pauses, WalkPause{PausedAt: *s.PausedAt, ResumedAt: end})
+	}
+	return pauses
+}
+
+// PausedBetween returns how much of the time between from and to the walk was paused
+func (s *Session) PausedBetween(from, to, now time.Time) time.Duration {
+	var paused time.Duration
+	for _, pause := range s.AllPauses(now) {
+		start, end := pause.PausedAt, pause.ResumedAt
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			paused += end.Sub(start)
+		}
+	}
+	return paused
+}
@@ -0,0 +1,76 @@
+// Package notifier sends user notifications through the notification-service
+// Version: 1.0.0
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Alert is an urgent message for the on-call admin channel
+type Alert struct {
+	Title  string            `json:"title"`
+	Text   string            `json:"text"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Alerter delivers alerts to the on-call admin channel
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type Alerter interface {
+	Alert(ctx context.Context, alert Alert) error
+}
+
+// WebhookAlerter posts alerts as JSON to an incoming webhook
+type WebhookAlerter struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookAlerter creates an alerter posting to url
+func NewWebhookAlerter(url string) *WebhookAlerter {
+	return &WebhookAlerter{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Alert posts the alert to the webhook
+func (a *WebhookAlerter) Alert(ctx context.Context, alert Alert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// LogAlerter logs alerts instead of sending them, for environments without an on-call channel
+type LogAlerter struct{}
+
+// Alert logs the alert
+func (LogAlerter) Alert(ctx context.Context, alert Alert) error {
+	log.Printf("ALERT: %s - %s %v", alert.Title, alert.Text, alert.Fields)
+	return nil
+}
@@ -0,0 +1,100 @@
+// Package notifier sends user notifications through the notification-service
+// Version: 1.0.0
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Human Tasks:
+// 1. Set TRACKING_NOTIFICATION_URL to the notification-service base URL in each environment
+// 2. Configure network policies allowing tracking-service to reach notification-service
+// 3. Monitor notification delivery failures from the tracking-service
+
+// Notification is a push notification addressed to a single user
+type Notification struct {
+	Subject  string
+	Body     string
+	Data     map[string]string
+	Priority string
+}
+
+// Notifier delivers notifications to users
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type Notifier interface {
+	Notify(ctx context.Context, userID string, notification Notification) error
+}
+
+// HTTPNotifier posts notifications to the notification-service send endpoint
+type HTTPNotifier struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPNotifier creates a notifier for the notification-service at baseURL
+func NewHTTPNotifier(baseURL string) *HTTPNotifier {
+	return &HTTPNotifier{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// sendRequest mirrors the notification-service NotificationRequest payload
+type sendRequest struct {
+	Type      string            `json:"type"`
+	Recipient string            `json:"recipient"`
+	Subject   string            `json:"subject,omitempty"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data,omitempty"`
+	Priority  string            `json:"priority,omitempty"`
+}
+
+// Notify sends a push notification to userID
+func (n *HTTPNotifier) Notify(ctx context.Context, userID string, notification Notification) error {
+	payload, err := json.Marshal(sendRequest{
+		Type:      "push",
+		Recipient: userID,
+		Subject:   notification.Subject,
+		Body:      notification.Body,
+		Data:      notification.Data,
+		Priority:  notification.Priority,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.baseURL+"/api/notifications/send", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("notification-service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// LogNotifier logs notifications instead of sending them, for environments
+// without a notification-service
+type LogNotifier struct{}
+
+// Notify logs the notification
+func (LogNotifier) Notify(ctx context.Context, userID string, notification Notification) error {
+	log.Printf("Notification for user %s: %s - %s", userID, notification.Subject, notification.Body)
+	return nil
+}
@@ -0,0 +1,257 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/metrics"
+	"src/backend/tracking-service/internal/models"
+)
+
+// maxPendingBatches bounds how many batches worth of points are retained while MongoDB is failing
+const maxPendingBatches = 10
+
+// duplicateKeyCode is the MongoDB error code for a unique index violation
+const duplicateKeyCode = 11000
+
+// ErrWriterClosed is returned when a location is enqueued after the batch writer has been stopped
+var ErrWriterClosed = errors.New("location batch writer is closed")
+
+// BatchWriter accumulates validated location points and writes them to MongoDB
+// with InsertMany once the batch size or the flush interval is reached.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type BatchWriter struct {
+	queue         chan models.Location
+	batchSize     int
+	flushInterval time.Duration
+
+	// mu guards closed so Enqueue never sends on a closed queue
+	mu     sync.RWMutex
+	closed bool
+
+	// stopping is closed when Stop is called, releasing Enqueue calls blocked on a full queue
+	// so Stop can take mu
+	stopping chan struct{}
+	stopOnce sync.Once
+
+	done chan struct{}
+}
+
+// locationWriter is the batch writer used by EnqueueLocation
+var locationWriter *BatchWriter
+
+// BatchStatus is a snapshot of a batch writer, for watching the ingest path while profiling
+type BatchStatus struct {
+	// Queued is how many points are waiting in the queue, out of QueueCapacity; Enqueue blocks when full
+	Queued        int `json:"queued"`
+	QueueCapacity int `json:"queue_capacity"`
+
+	BatchSize       int     `json:"batch_size"`
+	FlushIntervalMs float64 `json:"flush_interval_ms"`
+}
+
+// LocationWriterStatus returns the status of the batch writer used by EnqueueLocation, or
+// the zero status when none is running
+func LocationWriterStatus() BatchStatus {
+	if locationWriter == nil {
+		return BatchStatus{}
+	}
+	return locationWriter.Status()
+}
+
+// NewBatchWriter creates a BatchWriter with the given size and time thresholds
+func NewBatchWriter(batchSize int, flushInterval time.Duration) *BatchWriter {
+	return &BatchWriter{
+		queue:         make(chan models.Location, batchSize*2),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		stopping:      make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Status returns a snapshot of the writer's queue and thresholds
+func (w *BatchWriter) Status() BatchStatus {
+	return BatchStatus{
+		Queued:          len(w.queue),
+		QueueCapacity:   cap(w.queue),
+		BatchSize:       w.batchSize,
+		FlushIntervalMs: float64(w.flushInterval) / float64(time.Millisecond),
+	}
+}
+
+// Start runs the writer's flush loop in its own goroutine
+func (w *BatchWriter) Start() {
+	go w.run()
+}
+
+// Enqueue buffers a location point for the next flush, blocking while the queue is full
+// until the point is queued or the writer is stopped
+func (w *BatchWriter) Enqueue(location models.Location) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return ErrWriterClosed
+	}
+	select {
+	case w.queue <- location:
+		return nil
+	case <-w.stopping:
+		return ErrWriterClosed
+	}
+}
+
+// Stop stops accepting new points and blocks until every buffered point has been flushed
+// and the flush loop has returned, or until ctx is done
+func (w *BatchWriter) Stop(ctx context.Context) error {
+	w.stopOnce.Do(func() { close(w.stopping) })
+
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run is the writer's main loop, flushing on size and time thresholds
+func (w *BatchWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	pending := make([]models.Location, 0, w.batchSize)
+	for {
+		select {
+		case location, ok := <-w.queue:
+			if !ok {
+				// Queue closed: drain everything that is left before returning
+				w.drain(pending)
+				return
+			}
+			pending = append(pending, location)
+			if len(pending) >= w.batchSize {
+				pending = w.flush(pending)
+			}
+
+		case <-ticker.C:
+			if len(pending) > 0 {
+				pending = w.flush(pending)
+			}
+		}
+	}
+}
+
+// flush writes the pending points and returns the points that still need to be written
+func (w *BatchWriter) flush(pending []models.Location) []models.Location {
+	if err := insertLocations(pending); err != nil {
+		log.Printf("Failed to flush %d buffered locations: %v", len(pending), err)
+
+		// Keep the points for the next attempt, discarding the oldest beyond the retention bound
+		if limit := w.batchSize * maxPendingBatches; len(pending) > limit {
+			dropped := len(pending) - limit
+			metrics.LocationsDropped.Add(float64(dropped))
+			log.Printf("Dropping %d buffered locations after repeated flush failures", dropped)
+			pending = pending[dropped:]
+		}
+		return pending
+	}
+	return pending[:0]
+}
+
+// drain flushes the remaining points on shutdown, retrying a bounded number of times
+func (w *BatchWriter) drain(pending []models.Location) {
+	const attempts = 3
+	for i := 0; i < attempts && len(pending) > 0; i++ {
+		pending = w.flush(pending)
+		if len(pending) > 0 {
+			time.Sleep(time.Duration(i+1) * 500 * time.Millisecond)
+		}
+	}
+	if len(pending) > 0 {
+		metrics.LocationsDropped.Add(float64(len(pending)))
+		log.Printf("Failed to flush %d buffered locations during shutdown", len(pending))
+	}
+}
+
+// insertLocations writes a batch of location points with a single InsertMany call
+func insertLocations(locations []models.Location) error {
+	if memory != nil {
+		return memory.insertLocations(locations)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(collectionName)
+
+	docs := make([]interface{}, 0, len(locations))
+	for _, location := range locations {
+		sealed, err := sealLocation(location)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, sealed)
+	}
+
+	// Unordered, so a duplicate does not stop the rest of the batch from being written
+	start := time.Now()
+	_, err := collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	metrics.LocationFlushDuration.Observe(time.Since(start).Seconds())
+	if duplicates, ok := duplicateWrites(err); ok {
+		metrics.LocationDuplicates.WithLabelValues("index").Add(float64(duplicates))
+		err = nil
+	}
+	if err != nil {
+		metrics.LocationFlushErrors.Inc()
+		return err
+	}
+
+	metrics.LocationFlushSize.Observe(float64(len(locations)))
+	return nil
+}
+
+// duplicateWrites reports whether every failure in an InsertMany error was a duplicate key,
+// and how many there were. Such a batch has otherwise been written in full, which also makes
+// retrying a partially written batch safe.
+func duplicateWrites(err error) (int, bool) {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return 0, false
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Code != duplicateKeyCode {
+			return 0, false
+		}
+	}
+	return len(bulkErr.WriteErrors), true
+}
+
+// EnqueueLocation buffers a location point for a batched insert into MongoDB
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func EnqueueLocation(location models.Location) error {
+	if locationWriter == nil {
+		return InsertLocation(location)
+	}
+	return locationWriter.Enqueue(location)
+}
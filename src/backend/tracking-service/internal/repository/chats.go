@@ -0,0 +1,248 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// chatMessagesCollectionName is the collection holding booking chat messages
+const chatMessagesCollectionName = "chat_messages"
+
+var (
+	// ErrChatMessageNotFound is returned when a booking has no message with the requested ID
+	ErrChatMessageNotFound = errors.New("message not found")
+
+	// ErrChatMessageReported is returned when reporting a message that has already been reported
+	ErrChatMessageReported = errors.New("message already reported")
+)
+
+// InsertChatMessage stores a new chat message
+func InsertChatMessage(message models.ChatMessage) error {
+	if memory != nil {
+		return memory.insertChatMessage(message)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(chatMessagesCollectionName)
+
+	if _, err := collection.InsertOne(ctx, message); err != nil {
+		log.Printf("Failed to insert chat message: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindChatMessageByID retrieves one of a booking's messages
+func FindChatMessageByID(bookingID, id string) (*models.ChatMessage, error) {
+	if memory != nil {
+		return memory.findChatMessageByID(bookingID, id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(chatMessagesCollectionName)
+
+	var message models.ChatMessage
+	err := collection.FindOne(ctx, bson.M{"_id": id, "booking_id": bookingID}).Decode(&message)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrChatMessageNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to find chat message: %v", err)
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// FindChatMessages retrieves up to limit of a booking's messages, newest first, starting after
+// before when it is not nil
+func FindChatMessages(bookingID string, before *models.ChatMessage, limit int64) ([]models.ChatMessage, error) {
+	if memory != nil {
+		return memory.findChatMessages(bookingID, before, limit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(chatMessagesCollectionName)
+
+	filter := bson.M{"booking_id": bookingID}
+	if before != nil {
+		// Messages sent in the same instant are ordered by ID so no page skips or repeats one
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": before.CreatedAt}},
+			bson.M{"created_at": before.CreatedAt, "_id": bson.M{"$lt": before.ID}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("Failed to query chat messages: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.ChatMessage
+	if err := cursor.All(ctx, &messages); err != nil {
+		log.Printf("Failed to decode chat messages: %v", err)
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// FindChatMessagesBySender retrieves every message a user has sent, oldest first
+func FindChatMessagesBySender(senderID string) ([]models.ChatMessage, error) {
+	if memory != nil {
+		return memory.findChatMessagesBySender(senderID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(chatMessagesCollectionName)
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"sender_id": senderID}, opts)
+	if err != nil {
+		log.Printf("Failed to query chat messages: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.ChatMessage
+	if err := cursor.All(ctx, &messages); err != nil {
+		log.Printf("Failed to decode chat messages: %v", err)
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// MarkChatMessagesRead marks the unread messages of a booking sent to readerID up to and
+// including upTo as read at readAt, returning how many were marked
+func MarkChatMessagesRead(bookingID, readerID string, upTo, readAt time.Time) (int64, error) {
+	if memory != nil {
+		return memory.markChatMessagesRead(bookingID, readerID, upTo, readAt)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(chatMessagesCollectionName)
+
+	result, err := collection.UpdateMany(ctx,
+		bson.M{
+			"booking_id": bookingID,
+			"sender_id":  bson.M{"$ne": readerID},
+			"created_at": bson.M{"$lte": upTo},
+			"read_at":    bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"read_at": readAt}},
+	)
+	if err != nil {
+		log.Printf("Failed to mark chat messages read: %v", err)
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// ReportChatMessage records an abuse report against a message, unless it has already been reported
+func ReportChatMessage(bookingID, id string, report models.ChatReport) error {
+	if memory != nil {
+		return memory.reportChatMessage(bookingID, id, report)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(chatMessagesCollectionName)
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "booking_id": bookingID, "report": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"report": report}},
+	)
+	if err != nil {
+		log.Printf("Failed to report chat message: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		if _, err := FindChatMessageByID(bookingID, id); err != nil {
+			return err
+		}
+		return ErrChatMessageReported
+	}
+
+	return nil
+}
+
+// DeleteChatMessage removes one of a booking's messages
+func DeleteChatMessage(bookingID, id string) error {
+	if memory != nil {
+		return memory.deleteChatMessage(bookingID, id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(chatMessagesCollectionName)
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id, "booking_id": bookingID})
+	if err != nil {
+		log.Printf("Failed to delete chat message: %v", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrChatMessageNotFound
+	}
+
+	return nil
+}
+
+// DeleteChatMessagesBefore removes messages sent before cutoff, keeping reported messages until
+// they were reported before reportedCutoff, and returns how many were removed
+func DeleteChatMessagesBefore(cutoff, reportedCutoff time.Time) (int64, error) {
+	if memory != nil {
+		return memory.deleteChatMessagesBefore(cutoff, reportedCutoff)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(chatMessagesCollectionName)
+
+	result, err := collection.DeleteMany(ctx, bson.M{
+		"created_at": bson.M{"$lt": cutoff},
+		"$or": bson.A{
+			bson.M{"report": bson.M{"$exists": false}},
+			bson.M{"report.reported_at": bson.M{"$lt": reportedCutoff}},
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to delete chat messages: %v", err)
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,104 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// consentsCollectionName is the collection holding tracking consent records
+const consentsCollectionName = "consents"
+
+// ErrConsentNotFound is returned when a subject has no active consent for a booking
+var ErrConsentNotFound = errors.New("consent not found")
+
+// InsertConsent stores a new consent record
+func InsertConsent(consent models.Consent) error {
+	if memory != nil {
+		return memory.insertConsent(consent)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(consentsCollectionName)
+
+	if _, err := collection.InsertOne(ctx, consent); err != nil {
+		log.Printf("Failed to insert consent: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindConsents retrieves consent records, oldest first, for a booking, a subject, or both;
+// an empty argument matches any value
+func FindConsents(bookingID, subjectID string) ([]models.Consent, error) {
+	if memory != nil {
+		return memory.findConsents(bookingID, subjectID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(consentsCollectionName)
+
+	filter := bson.M{}
+	if bookingID != "" {
+		filter["booking_id"] = bookingID
+	}
+	if subjectID != "" {
+		filter["subject_id"] = subjectID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "granted_at", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("Failed to query consents: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var consents []models.Consent
+	if err := cursor.All(ctx, &consents); err != nil {
+		log.Printf("Failed to decode consents: %v", err)
+		return nil, err
+	}
+
+	return consents, nil
+}
+
+// RevokeConsent marks a subject's active consents for a booking as revoked
+func RevokeConsent(bookingID, subjectID string, revokedAt time.Time) error {
+	if memory != nil {
+		return memory.revokeConsent(bookingID, subjectID, revokedAt)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(consentsCollectionName)
+
+	result, err := collection.UpdateMany(ctx,
+		bson.M{"booking_id": bookingID, "subject_id": subjectID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": revokedAt}},
+	)
+	if err != nil {
+		log.Printf("Failed to revoke consent: %v", err)
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return ErrConsentNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/encryption"
+	"src/backend/tracking-service/internal/models"
+)
+
+// coordinateKeys encrypts stored coordinates when set; nil stores them in plain text
+var coordinateKeys *encryption.Keyring
+
+// UseLocationEncryption encrypts the coordinates of location points written from now on and
+// decrypts encrypted points on read. Points stored in plain text remain readable.
+func UseLocationEncryption(keys *encryption.Keyring) {
+	coordinateKeys = keys
+}
+
+// coordinatesAAD binds sealed coordinates to their point, so they cannot be copied onto
+// another point. MongoDB stores timestamps to the millisecond, so that is the precision used.
+func coordinatesAAD(location *models.Location) []byte {
+	return []byte(location.SessionID + "|" + strconv.FormatInt(location.Timestamp.UnixMilli(), 10))
+}
+
+// sealLocation returns location with its coordinates encrypted, if encryption is enabled
+func sealLocation(location models.Location) (models.Location, error) {
+	if coordinateKeys == nil {
+		return location, nil
+	}
+
+	plaintext := make([]byte, 16)
+	binary.BigEndian.PutUint64(plaintext[:8], math.Float64bits(location.Latitude))
+	binary.BigEndian.PutUint64(plaintext[8:], math.Float64bits(location.Longitude))
+
+	keyID, sealed, err := coordinateKeys.Seal(plaintext, coordinatesAAD(&location))
+	if err != nil {
+		return location, err
+	}
+
+	location.SealedCoordinates = sealed
+	location.CoordinatesKey = keyID
+	location.Latitude = 0
+	location.Longitude = 0
+	return location, nil
+}
+
+// openLocation decrypts location's coordinates in place if they were stored encrypted
+func openLocation(location *models.Location) error {
+	if location.SealedCoordinates == nil {
+		return nil
+	}
+	if coordinateKeys == nil {
+		return fmt.Errorf("location is encrypted but no encryption keys are configured")
+	}
+
+	plaintext, err := coordinateKeys.Open(location.CoordinatesKey, location.SealedCoordinates, coordinatesAAD(location))
+	if err != nil {
+		return err
+	}
+	if len(plaintext) != 16 {
+		return fmt.Errorf("decrypted coordinates have unexpected length %d", len(plaintext))
+	}
+
+	location.Latitude = math.Float64frombits(binary.BigEndian.Uint64(plaintext[:8]))
+	location.Longitude = math.Float64frombits(binary.BigEndian.Uint64(plaintext[8:]))
+	location.SealedCoordinates = nil
+	location.CoordinatesKey = ""
+	return nil
+}
+
+// openLocations decrypts every point in locations
+func openLocations(locations []models.Location) error {
+	for i := range locations {
+		if err := openLocation(&locations[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RotateLocationKeys re-encrypts every point not sealed under the current key, including
+// points stored in plain text, and returns how many were rewritten. It works through the
+// collection in batches, so it can be stopped and run again.
+func RotateLocationKeys(ctx context.Context, batchSize int) (int64, error) {
+	if memory != nil {
+		return 0, nil
+	}
+	if coordinateKeys == nil {
+		return 0, fmt.Errorf("location encryption is not configured")
+	}
+
+	collection := mongoCollection(collectionName)
+	filter := bson.M{"coords_key": bson.M{"$ne": coordinateKeys.CurrentID()}}
+	opts := options.Find().SetBatchSize(int32(batchSize))
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("Failed to query locations for re-encryption: %v", err)
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var rotated int64
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID              primitive.ObjectID `bson:"_id"`
+			models.Location `bson:",inline"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return rotated, err
+		}
+		if err := openLocation(&doc.Location); err != nil {
+			return rotated, fmt.Errorf("failed to decrypt location %s: %w", doc.ID.Hex(), err)
+		}
+		sealed, err := sealLocation(doc.Location)
+		if err != nil {
+			return rotated, err
+		}
+
+		writeCtx, cancel := context.WithTimeout(ctx, defaultTimeout)
+		_, err = collection.UpdateOne(writeCtx, bson.M{"_id": doc.ID}, bson.M{
+			"$set":   bson.M{"coords_enc": sealed.SealedCoordinates, "coords_key": sealed.CoordinatesKey},
+			"$unset": bson.M{"latitude": "", "longitude": ""},
+		})
+		cancel()
+		if err != nil {
+			log.Printf("Failed to re-encrypt location %s: %v", doc.ID.Hex(), err)
+			return rotated, err
+		}
+
+		rotated++
+		if rotated%10000 == 0 {
+			log.Printf("Re-encrypted %d location points so far", rotated)
+		}
+	}
+
+	return rotated, cursor.Err()
+}
@@ -0,0 +1,193 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// exportsCollectionName is the collection holding export jobs
+const exportsCollectionName = "exports"
+
+// ErrExportNotFound is returned when no export job exists with the requested ID
+var ErrExportNotFound = errors.New("export job not found")
+
+// InsertExportJob stores a new export job
+func InsertExportJob(job models.ExportJob) error {
+	if memory != nil {
+		return memory.insertExportJob(job)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(exportsCollectionName)
+
+	if _, err := collection.InsertOne(ctx, job); err != nil {
+		log.Printf("Failed to insert export job: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindExportJobByID retrieves an export job by its ID
+func FindExportJobByID(id string) (*models.ExportJob, error) {
+	if memory != nil {
+		return memory.findExportJobByID(id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(exportsCollectionName)
+
+	var job models.ExportJob
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to find export job: %v", err)
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// CountPendingExportJobs counts export jobs that are queued or running
+func CountPendingExportJobs() (int64, error) {
+	if memory != nil {
+		return memory.countPendingExportJobs()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(exportsCollectionName)
+
+	count, err := collection.CountDocuments(ctx, bson.M{
+		"status": bson.M{"$in": []models.ExportStatus{models.ExportStatusQueued, models.ExportStatusRunning}},
+	})
+	if err != nil {
+		log.Printf("Failed to count pending export jobs: %v", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// ClaimExportJob atomically moves the oldest queued export job to running and returns it.
+// Running jobs not updated since staleBefore are claimed again, so a job whose instance
+// died is retried. Returns nil without error when there is nothing to do.
+func ClaimExportJob(now, staleBefore time.Time) (*models.ExportJob, error) {
+	if memory != nil {
+		return memory.claimExportJob(now, staleBefore)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(exportsCollectionName)
+
+	filter := bson.M{"$or": []bson.M{
+		{"status": models.ExportStatusQueued},
+		{"status": models.ExportStatusRunning, "updated_at": bson.M{"$lt": staleBefore}},
+	}}
+	update := bson.M{"$set": bson.M{"status": models.ExportStatusRunning, "updated_at": now}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job models.ExportJob
+	err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Failed to claim export job: %v", err)
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// UpdateExportJob applies fields to an export job
+func UpdateExportJob(id string, fields bson.M) error {
+	if memory != nil {
+		return memory.updateExportJob(id, fields)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(exportsCollectionName)
+
+	result, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields})
+	if err != nil {
+		log.Printf("Failed to update export job: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrExportNotFound
+	}
+
+	return nil
+}
+
+// StreamLocations calls fn for every location point of sessionID, or of every session when
+// sessionID is empty, recorded in [startTime, endTime] when a range is given. Points arrive
+// ordered by session and then time, one cursor batch at a time, so fn sets the pace and an
+// export never holds more than a batch in memory.
+func StreamLocations(ctx context.Context, sessionID string, startTime, endTime *time.Time, fn func(location models.Location) error) error {
+	if memory != nil {
+		return memory.streamLocations(sessionID, startTime, endTime, fn)
+	}
+
+	collection := mongoCollection(collectionName)
+
+	filter := bson.M{}
+	if sessionID != "" {
+		filter["session_id"] = sessionID
+	}
+	if startTime != nil && endTime != nil {
+		filter["timestamp"] = bson.M{"$gte": *startTime, "$lte": *endTime}
+	}
+
+	// The session_id/timestamp index serves this sort, so no in-memory sort is needed
+	opts := options.Find().
+		SetSort(bson.D{{Key: "session_id", Value: 1}, {Key: "timestamp", Value: 1}}).
+		SetBatchSize(1000)
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("Failed to query locations for export: %v", err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var location models.Location
+		if err := cursor.Decode(&location); err != nil {
+			return err
+		}
+		if err := openLocation(&location); err != nil {
+			return err
+		}
+		if err := fn(location); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
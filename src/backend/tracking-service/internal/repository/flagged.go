@@ -0,0 +1,131 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// flaggedContentCollectionName is the collection holding content queued for moderation review
+const flaggedContentCollectionName = "flagged_content"
+
+var (
+	// ErrFlaggedContentNotFound is returned when no flagged content has the requested ID
+	ErrFlaggedContentNotFound = errors.New("flagged content not found")
+
+	// ErrFlaggedContentReviewed is returned when reviewing flagged content that has already been reviewed
+	ErrFlaggedContentReviewed = errors.New("flagged content already reviewed")
+)
+
+// InsertFlaggedContent queues flagged content for review
+func InsertFlaggedContent(flagged models.FlaggedContent) error {
+	if memory != nil {
+		return memory.insertFlaggedContent(flagged)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(flaggedContentCollectionName)
+
+	if _, err := collection.InsertOne(ctx, flagged); err != nil {
+		log.Printf("Failed to insert flagged content: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindFlaggedContentByID retrieves flagged content by its ID
+func FindFlaggedContentByID(id string) (*models.FlaggedContent, error) {
+	if memory != nil {
+		return memory.findFlaggedContentByID(id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(flaggedContentCollectionName)
+
+	var flagged models.FlaggedContent
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&flagged)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrFlaggedContentNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to find flagged content: %v", err)
+		return nil, err
+	}
+
+	return &flagged, nil
+}
+
+// FindFlaggedContent retrieves up to limit flagged content with the given status, oldest first
+func FindFlaggedContent(status models.FlaggedStatus, limit int64) ([]models.FlaggedContent, error) {
+	if memory != nil {
+		return memory.findFlaggedContent(status, limit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(flaggedContentCollectionName)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := collection.Find(ctx, bson.M{"status": status}, opts)
+	if err != nil {
+		log.Printf("Failed to query flagged content: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var flagged []models.FlaggedContent
+	if err := cursor.All(ctx, &flagged); err != nil {
+		log.Printf("Failed to decode flagged content: %v", err)
+		return nil, err
+	}
+
+	return flagged, nil
+}
+
+// ReviewFlaggedContent records an admin's decision on pending flagged content
+func ReviewFlaggedContent(id string, status models.FlaggedStatus, reviewerID string, reviewedAt time.Time) error {
+	if memory != nil {
+		return memory.reviewFlaggedContent(id, status, reviewerID, reviewedAt)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(flaggedContentCollectionName)
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.FlaggedStatusPending},
+		bson.M{"$set": bson.M{"status": status, "reviewed_by": reviewerID, "reviewed_at": reviewedAt}},
+	)
+	if err != nil {
+		log.Printf("Failed to review flagged content: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		if _, err := FindFlaggedContentByID(id); err != nil {
+			return err
+		}
+		return ErrFlaggedContentReviewed
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// incidentsCollectionName is the collection holding incident records
+const incidentsCollectionName = "incidents"
+
+var (
+	// ErrIncidentNotFound is returned when no incident exists with the requested ID
+	ErrIncidentNotFound = errors.New("incident not found")
+
+	// ErrIncidentChanged is returned when an incident's status changed since it was read
+	ErrIncidentChanged = errors.New("incident was modified concurrently")
+)
+
+// InsertIncident stores a new incident record
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func InsertIncident(incident models.Incident) error {
+	if memory != nil {
+		return memory.insertIncident(incident)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(incidentsCollectionName)
+
+	if _, err := collection.InsertOne(ctx, incident); err != nil {
+		log.Printf("Failed to insert incident: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindLatestLocation retrieves the most recent stored location of a walk session, or nil if none
+func FindLatestLocation(sessionID string) (*models.Location, error) {
+	if memory != nil {
+		return memory.findLatestLocation(sessionID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(collectionName)
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+
+	var location models.Location
+	err := collection.FindOne(ctx, bson.M{"session_id": sessionID}, opts).Decode(&location)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Failed to find latest session location: %v", err)
+		return nil, err
+	}
+	if err := openLocation(&location); err != nil {
+		log.Printf("Failed to decrypt latest session location: %v", err)
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+// FindIncidentByID retrieves an incident by its ID
+func FindIncidentByID(id string) (*models.Incident, error) {
+	if memory != nil {
+		return memory.findIncidentByID(id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(incidentsCollectionName)
+
+	var incident models.Incident
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&incident)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrIncidentNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to find incident: %v", err)
+		return nil, err
+	}
+
+	return &incident, nil
+}
+
+// UpdateIncident applies fields to an incident, provided its status is still expectedStatus
+func UpdateIncident(id string, expectedStatus models.IncidentStatus, fields bson.M) error {
+	if memory != nil {
+		return memory.updateIncident(id, expectedStatus, fields)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(incidentsCollectionName)
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": expectedStatus},
+		bson.M{"$set": fields},
+	)
+	if err != nil {
+		log.Printf("Failed to update incident: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrIncidentChanged
+	}
+
+	return nil
+}
+
+// AddIncidentAttachment appends an attachment to an incident
+func AddIncidentAttachment(id string, attachment models.Attachment) error {
+	if memory != nil {
+		return memory.addIncidentAttachment(id, attachment)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(incidentsCollectionName)
+
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$push": bson.M{"attachments": attachment},
+			"$set":  bson.M{"updated_at": attachment.AddedAt},
+		},
+	)
+	if err != nil {
+		log.Printf("Failed to add incident attachment: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrIncidentNotFound
+	}
+
+	return nil
+}
+
+// FindIncidentQueue retrieves incidents in any of statuses, oldest first, optionally
+// restricted to one type and one region
+func FindIncidentQueue(statuses []models.IncidentStatus, incidentType models.IncidentType, region string, limit int64) ([]models.Incident, error) {
+	if memory != nil {
+		return memory.findIncidentQueue(statuses, incidentType, region, limit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(incidentsCollectionName)
+
+	filter := bson.M{"status": bson.M{"$in": statuses}}
+	if incidentType != "" {
+		filter["type"] = incidentType
+	}
+	if region != "" {
+		filter["region"] = region
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("Failed to query incident queue: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var incidents []models.Incident
+	if err := cursor.All(ctx, &incidents); err != nil {
+		log.Printf("Failed to decode incident queue: %v", err)
+		return nil, err
+	}
+
+	return incidents, nil
+}
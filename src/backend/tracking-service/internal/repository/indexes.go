@@ -0,0 +1,98 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// indexes lists the indexes each collection needs, matching the queries in this package
+var indexes = map[string][]mongo.IndexModel{
+	collectionName: {
+		// Location history by time range
+		{Keys: bson.D{{Key: "timestamp", Value: 1}}},
+		// Walk routes and the latest point of a session
+		{Keys: bson.D{{Key: "session_id", Value: 1}, {Key: "timestamp", Value: 1}}},
+		// Rejects retried points; sessionless points are not deduplicated
+		{
+			Keys: bson.D{{Key: "session_id", Value: 1}, {Key: "timestamp", Value: 1}, {Key: "device_seq", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"session_id": bson.M{"$exists": true}}),
+		},
+	},
+	sessionsCollectionName: {
+		// Active sessions restored at startup
+		{Keys: bson.D{{Key: "status", Value: 1}}},
+		// Walk evidence checked as a booking completes
+		{Keys: bson.D{{Key: "booking_id", Value: 1}, {Key: "started_at", Value: 1}}},
+		// The same, for the other bookings of group walks
+		{Keys: bson.D{{Key: "bookings.booking_id", Value: 1}, {Key: "started_at", Value: 1}}},
+	},
+	incidentsCollectionName: {
+		// Admin incident queue
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "type", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "session_id", Value: 1}}},
+		// Admin incident queue of one region
+		{Keys: bson.D{{Key: "region", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	},
+	consentsCollectionName: {
+		// Consent checks when a walk starts, and subject data exports
+		{Keys: bson.D{{Key: "booking_id", Value: 1}, {Key: "subject_id", Value: 1}}},
+		{Keys: bson.D{{Key: "subject_id", Value: 1}}},
+	},
+	privacyZonesCollectionName: {
+		// Zones applied to each of a walker's sessions
+		{Keys: bson.D{{Key: "walker_id", Value: 1}}},
+	},
+	positionsCollectionName: {
+		// Walkers near a point, by cell prefix
+		{Keys: bson.D{{Key: "cell", Value: 1}, {Key: "updated_at", Value: 1}}},
+	},
+	exportsCollectionName: {
+		// Export workers claiming the oldest queued job
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	},
+	chatMessagesCollectionName: {
+		// A booking's conversation, a page at a time, and its read receipts
+		{Keys: bson.D{{Key: "booking_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		// Subject data exports
+		{Keys: bson.D{{Key: "sender_id", Value: 1}}},
+		// Retention purges
+		{Keys: bson.D{{Key: "created_at", Value: 1}}},
+	},
+	flaggedContentCollectionName: {
+		// The moderation review queue, oldest first
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	},
+}
+
+// EnsureIndexes creates any missing indexes; existing indexes are left untouched, so it is
+// safe to run on every start
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func EnsureIndexes(ctx context.Context) error {
+	if memory != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	for name, models := range indexes {
+		collection := mongoCollection(name)
+		if _, err := collection.Indexes().CreateMany(ctx, models, options.CreateIndexes()); err != nil {
+			return fmt.Errorf("failed to create indexes on %s: %w", name, err)
+		}
+	}
+
+	log.Printf("MongoDB indexes are up to date")
+	return nil
+}
@@ -0,0 +1,143 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	// go.mongodb.org/mongo-driver/mongo v1.11.0
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/event"
+
+	"src/backend/shared/dbmetrics"
+)
+
+// startedCommand is a command sent to MongoDB that has not been answered yet
+type startedCommand struct {
+	kind    string
+	command bson.Raw
+}
+
+// commandMonitor records every command the client sends with a Recorder
+type commandMonitor struct {
+	recorder *dbmetrics.Recorder
+
+	// started holds the commands in flight by request ID, as only the start of a command
+	// carries the command itself
+	started sync.Map
+}
+
+// newCommandMonitor returns a monitor recording commands with recorder, for the client options
+func newCommandMonitor(recorder *dbmetrics.Recorder) *event.CommandMonitor {
+	m := &commandMonitor{recorder: recorder}
+	return &event.CommandMonitor{
+		Started: m.commandStarted,
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			m.commandFinished(evt.CommandFinishedEvent, nil)
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			m.commandFinished(evt.CommandFinishedEvent, errors.New(evt.Failure))
+		},
+	}
+}
+
+// commandStarted remembers a command until it is answered. The command is copied, as the
+// driver reuses its buffer once the event has been handled.
+func (m *commandMonitor) commandStarted(ctx context.Context, evt *event.CommandStartedEvent) {
+	m.started.Store(evt.RequestID, startedCommand{
+		kind:    commandKind(evt.CommandName, evt.Command),
+		command: append(bson.Raw(nil), evt.Command...),
+	})
+}
+
+// commandFinished records how long an answered command took
+func (m *commandMonitor) commandFinished(evt event.CommandFinishedEvent, err error) {
+	value, ok := m.started.LoadAndDelete(evt.RequestID)
+	if !ok {
+		return
+	}
+	started := value.(startedCommand)
+	m.recorder.Observe(started.kind, time.Duration(evt.DurationNanos), err, func() string {
+		return sanitizeCommand(started.command)
+	})
+}
+
+// commandKind labels a command by its name and the collection it works on, such as
+// "insert locations"
+func commandKind(name string, command bson.Raw) string {
+	elements, err := command.Elements()
+	if err != nil || len(elements) == 0 {
+		return name
+	}
+	// The first element of a command is its name, valued with the collection it targets
+	if collection, ok := elements[0].Value().StringValueOK(); ok {
+		return name + " " + collection
+	}
+	return name
+}
+
+// sanitizeCommand describes a command with its values left out, keeping its shape: the
+// fields filtered on, the operators used and how many documents it carries. Values hold
+// walkers' locations and owners' details, so none are kept but the collection's name.
+func sanitizeCommand(command bson.Raw) string {
+	elements, err := command.Elements()
+	if err != nil {
+		return "{?}"
+	}
+
+	var fields []string
+	for i, element := range elements {
+		key := element.Key()
+		// Session, cluster time and routing fields say nothing about the query
+		if key == "lsid" || key == "txnNumber" || strings.HasPrefix(key, "$") {
+			continue
+		}
+		if i == 0 {
+			if collection, ok := element.Value().StringValueOK(); ok {
+				fields = append(fields, fmt.Sprintf("%s: %q", key, collection))
+				continue
+			}
+		}
+		fields = append(fields, fmt.Sprintf("%s: %s", key, sanitizeValue(element.Value())))
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+// sanitizeValue describes a value inside a command with every scalar replaced by "?"
+func sanitizeValue(value bson.RawValue) string {
+	switch value.Type {
+	case bsontype.EmbeddedDocument:
+		elements, err := bson.Raw(value.Value).Elements()
+		if err != nil {
+			return "{?}"
+		}
+		fields := make([]string, len(elements))
+		for i, element := range elements {
+			fields[i] = fmt.Sprintf("%s: %s", element.Key(), sanitizeValue(element.Value()))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case bsontype.Array:
+		// Arrays are encoded as documents keyed by index; the first item stands for the rest
+		elements, err := bson.Raw(value.Value).Elements()
+		if err != nil {
+			return "[?]"
+		}
+		switch len(elements) {
+		case 0:
+			return "[]"
+		case 1:
+			return "[" + sanitizeValue(elements[0].Value()) + "]"
+		default:
+			return fmt.Sprintf("[%s, ... %d items]", sanitizeValue(elements[0].Value()), len(elements))
+		}
+	default:
+		return "?"
+	}
+}
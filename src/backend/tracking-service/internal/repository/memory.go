@@ -0,0 +1,775 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"src/backend/tracking-service/internal/metrics"
+	"src/backend/tracking-service/internal/models"
+)
+
+// memory replaces MongoDB when the service runs with TRACKING_STORE=memory; nil means MongoDB
+var memory *memoryStore
+
+// UseMemoryStore serves every repository function from an empty in-process store instead of
+// MongoDB. Nothing survives a restart, so it is meant for local development and CI only.
+func UseMemoryStore() {
+	memory = &memoryStore{
+		locationKeys: make(map[string]struct{}),
+		sessions:     make(map[string]models.Session),
+		incidents:    make(map[string]models.Incident),
+		exports:      make(map[string]models.ExportJob),
+		privacyZones: make(map[string]models.PrivacyZone),
+		positions:    make(map[string]models.WalkerPosition),
+	}
+}
+
+// memoryStore holds the tracking collections in process memory
+type memoryStore struct {
+	mu        sync.RWMutex
+	locations []models.Location
+	sessions  map[string]models.Session
+	incidents map[string]models.Incident
+	exports   map[string]models.ExportJob
+
+	privacyZones map[string]models.PrivacyZone
+	consents     []models.Consent
+	positions    map[string]models.WalkerPosition
+	chatMessages []models.ChatMessage
+	flagged      []models.FlaggedContent
+
+	// locationKeys stands in for the unique location index
+	locationKeys map[string]struct{}
+}
+
+func (m *memoryStore) insertLocations(locations []models.Location) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, location := range locations {
+		if location.SessionID != "" {
+			key := location.DedupeKey()
+			if _, ok := m.locationKeys[key]; ok {
+				metrics.LocationDuplicates.WithLabelValues("index").Inc()
+				continue
+			}
+			m.locationKeys[key] = struct{}{}
+		}
+		m.locations = append(m.locations, location)
+	}
+	return nil
+}
+
+// findLocations returns the points matching keep in recording order, at most limit of them
+// when limit is positive
+func (m *memoryStore) findLocations(keep func(location models.Location) bool, limit int) []models.Location {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var locations []models.Location
+	for _, location := range m.locations {
+		if keep(location) {
+			locations = append(locations, location)
+		}
+	}
+
+	sort.SliceStable(locations, func(i, j int) bool { return locations[i].Precedes(&locations[j]) })
+	if limit > 0 && len(locations) > limit {
+		locations = locations[:limit]
+	}
+	return locations
+}
+
+func (m *memoryStore) findLocationsByTimeRange(startTime, endTime time.Time) ([]models.Location, error) {
+	return m.findLocations(func(location models.Location) bool {
+		return !location.Timestamp.Before(startTime) && !location.Timestamp.After(endTime)
+	}, 1000), nil
+}
+
+func (m *memoryStore) findLocationsBySession(sessionID string) ([]models.Location, error) {
+	return m.findLocations(func(location models.Location) bool {
+		return location.SessionID == sessionID
+	}, 0), nil
+}
+
+func (m *memoryStore) deleteLocationsBefore(cutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.locations[:0]
+	for _, location := range m.locations {
+		if !location.Timestamp.Before(cutoff) {
+			kept = append(kept, location)
+		} else {
+			delete(m.locationKeys, location.DedupeKey())
+		}
+	}
+	deleted := int64(len(m.locations) - len(kept))
+	m.locations = kept
+	return deleted, nil
+}
+
+func (m *memoryStore) findLatestLocation(sessionID string) (*models.Location, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest *models.Location
+	for i := range m.locations {
+		location := m.locations[i]
+		if location.SessionID == sessionID && (latest == nil || location.Timestamp.After(latest.Timestamp)) {
+			latest = &location
+		}
+	}
+	return latest, nil
+}
+
+func (m *memoryStore) insertSession(session models.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[session.ID] = session
+	return nil
+}
+
+func (m *memoryStore) findSessionByID(id string) (*models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+func (m *memoryStore) endSession(id string, endedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok || session.Status != models.SessionStatusActive {
+		return ErrSessionNotFound
+	}
+
+	session.Status = models.SessionStatusEnded
+	session.EndedAt = &endedAt
+	m.sessions[id] = session
+	return nil
+}
+
+func (m *memoryStore) tagSessionRegion(id, region string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if session.Region == "" {
+		session.Region = region
+		m.sessions[id] = session
+	}
+	return nil
+}
+
+func (m *memoryStore) addSessionPhoto(id string, photo models.WalkPhoto) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok || session.Status != models.SessionStatusActive {
+		return ErrSessionNotFound
+	}
+
+	// Sessions handed out share the stored photos, so the slice is copied rather than appended to
+	photos := make([]models.WalkPhoto, 0, len(session.Photos)+1)
+	session.Photos = append(append(photos, session.Photos...), photo)
+	m.sessions[id] = session
+	return nil
+}
+
+func (m *memoryStore) pauseSession(id string, pausedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok || session.Status != models.SessionStatusActive {
+		return ErrSessionNotFound
+	}
+	if session.Paused() {
+		return ErrSessionPaused
+	}
+
+	session.PausedAt = &pausedAt
+	m.sessions[id] = session
+	return nil
+}
+
+func (m *memoryStore) resumeSession(id string, resumedAt time.Time) (*models.WalkPause, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok || session.Status != models.SessionStatusActive {
+		return nil, ErrSessionNotFound
+	}
+	if !session.Paused() {
+		return nil, ErrSessionNotPaused
+	}
+
+	// Sessions handed out share the stored pauses, so the slice is copied rather than appended to
+	pause := models.WalkPause{PausedAt: *session.PausedAt, ResumedAt: resumedAt}
+	pauses := make([]models.WalkPause, 0, len(session.Pauses)+1)
+	session.Pauses = append(append(pauses, session.Pauses...), pause)
+	session.PausedAt = nil
+	m.sessions[id] = session
+	return &pause, nil
+}
+
+func (m *memoryStore) findSessionsByBooking(bookingID string) ([]models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var sessions []models.Session
+	for _, session := range m.sessions {
+		if session.HasBooking(bookingID) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartedAt.Before(sessions[j].StartedAt) })
+	return sessions, nil
+}
+
+func (m *memoryStore) findActiveSessions() ([]models.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var sessions []models.Session
+	for _, session := range m.sessions {
+		if session.Status == models.SessionStatusActive {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *memoryStore) insertIncident(incident models.Incident) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.incidents[incident.ID] = incident
+	return nil
+}
+
+func (m *memoryStore) findIncidentByID(id string) (*models.Incident, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	incident, ok := m.incidents[id]
+	if !ok {
+		return nil, ErrIncidentNotFound
+	}
+	return &incident, nil
+}
+
+func (m *memoryStore) updateIncident(id string, expectedStatus models.IncidentStatus, fields bson.M) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	incident, ok := m.incidents[id]
+	if !ok || incident.Status != expectedStatus {
+		return ErrIncidentChanged
+	}
+
+	var updated models.Incident
+	if err := applySet(incident, fields, &updated); err != nil {
+		return err
+	}
+	m.incidents[id] = updated
+	return nil
+}
+
+func (m *memoryStore) addIncidentAttachment(id string, attachment models.Attachment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	incident, ok := m.incidents[id]
+	if !ok {
+		return ErrIncidentNotFound
+	}
+
+	incident.Attachments = append(incident.Attachments, attachment)
+	incident.UpdatedAt = attachment.AddedAt
+	m.incidents[id] = incident
+	return nil
+}
+
+func (m *memoryStore) findIncidentQueue(statuses []models.IncidentStatus, incidentType models.IncidentType, region string, limit int64) ([]models.Incident, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var incidents []models.Incident
+	for _, incident := range m.incidents {
+		if incidentType != "" && incident.Type != incidentType {
+			continue
+		}
+		if region != "" && incident.Region != region {
+			continue
+		}
+		for _, status := range statuses {
+			if incident.Status == status {
+				incidents = append(incidents, incident)
+				break
+			}
+		}
+	}
+
+	sort.Slice(incidents, func(i, j int) bool { return incidents[i].CreatedAt.Before(incidents[j].CreatedAt) })
+	if limit > 0 && int64(len(incidents)) > limit {
+		incidents = incidents[:limit]
+	}
+	return incidents, nil
+}
+
+func (m *memoryStore) streamLocations(sessionID string, startTime, endTime *time.Time, fn func(location models.Location) error) error {
+	locations := m.findLocations(func(location models.Location) bool {
+		if sessionID != "" && location.SessionID != sessionID {
+			return false
+		}
+		if startTime != nil && endTime != nil {
+			return !location.Timestamp.Before(*startTime) && !location.Timestamp.After(*endTime)
+		}
+		return true
+	}, math.MaxInt)
+
+	// findLocations orders by time; a stable sort by session keeps that order within each session
+	sort.SliceStable(locations, func(i, j int) bool { return locations[i].SessionID < locations[j].SessionID })
+	for _, location := range locations {
+		if err := fn(location); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) insertExportJob(job models.ExportJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.exports[job.ID] = job
+	return nil
+}
+
+func (m *memoryStore) findExportJobByID(id string) (*models.ExportJob, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	job, ok := m.exports[id]
+	if !ok {
+		return nil, ErrExportNotFound
+	}
+	return &job, nil
+}
+
+func (m *memoryStore) countPendingExportJobs() (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int64
+	for _, job := range m.exports {
+		if job.Status == models.ExportStatusQueued || job.Status == models.ExportStatusRunning {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryStore) claimExportJob(now, staleBefore time.Time) (*models.ExportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var claimed *models.ExportJob
+	for id := range m.exports {
+		job := m.exports[id]
+		claimable := job.Status == models.ExportStatusQueued ||
+			(job.Status == models.ExportStatusRunning && job.UpdatedAt.Before(staleBefore))
+		if claimable && (claimed == nil || job.CreatedAt.Before(claimed.CreatedAt)) {
+			claimed = &job
+		}
+	}
+	if claimed == nil {
+		return nil, nil
+	}
+
+	claimed.Status = models.ExportStatusRunning
+	claimed.UpdatedAt = now
+	m.exports[claimed.ID] = *claimed
+	return claimed, nil
+}
+
+func (m *memoryStore) updateExportJob(id string, fields bson.M) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.exports[id]
+	if !ok {
+		return ErrExportNotFound
+	}
+
+	var updated models.ExportJob
+	if err := applySet(job, fields, &updated); err != nil {
+		return err
+	}
+	m.exports[id] = updated
+	return nil
+}
+
+// applySet applies fields to doc the way $set would, by round-tripping it through BSON into out
+func applySet(doc interface{}, fields bson.M, out interface{}) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	var values bson.M
+	if err := bson.Unmarshal(raw, &values); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		values[key] = value
+	}
+	if raw, err = bson.Marshal(values); err != nil {
+		return err
+	}
+	return bson.Unmarshal(raw, out)
+}
+
+func (m *memoryStore) insertPrivacyZone(zone models.PrivacyZone) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.privacyZones[zone.ID] = zone
+	return nil
+}
+
+func (m *memoryStore) findPrivacyZonesByWalker(walkerID string) ([]models.PrivacyZone, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var zones []models.PrivacyZone
+	for _, zone := range m.privacyZones {
+		if zone.WalkerID == walkerID {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].CreatedAt.Before(zones[j].CreatedAt) })
+	return zones, nil
+}
+
+func (m *memoryStore) deletePrivacyZone(walkerID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	zone, ok := m.privacyZones[id]
+	if !ok || zone.WalkerID != walkerID {
+		return ErrPrivacyZoneNotFound
+	}
+	delete(m.privacyZones, id)
+	return nil
+}
+
+func (m *memoryStore) insertConsent(consent models.Consent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.consents = append(m.consents, consent)
+	return nil
+}
+
+func (m *memoryStore) findConsents(bookingID, subjectID string) ([]models.Consent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var consents []models.Consent
+	for _, consent := range m.consents {
+		if (bookingID == "" || consent.BookingID == bookingID) && (subjectID == "" || consent.SubjectID == subjectID) {
+			consents = append(consents, consent)
+		}
+	}
+	return consents, nil
+}
+
+func (m *memoryStore) revokeConsent(bookingID, subjectID string, revokedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	revoked := false
+	for i := range m.consents {
+		consent := &m.consents[i]
+		if consent.BookingID == bookingID && consent.SubjectID == subjectID && consent.IsActive() {
+			consent.RevokedAt = &revokedAt
+			revoked = true
+		}
+	}
+	if !revoked {
+		return ErrConsentNotFound
+	}
+	return nil
+}
+
+func (m *memoryStore) saveWalkerPosition(position models.WalkerPosition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.positions[position.WalkerID] = position
+	return nil
+}
+
+func (m *memoryStore) findWalkerPosition(walkerID string) (*models.WalkerPosition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	position, ok := m.positions[walkerID]
+	if !ok {
+		return nil, ErrPositionNotFound
+	}
+	return &position, nil
+}
+
+func (m *memoryStore) findWalkerPositions(cellPrefixes []string, since time.Time) ([]models.WalkerPosition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var positions []models.WalkerPosition
+	for _, position := range m.positions {
+		if position.UpdatedAt.Before(since) {
+			continue
+		}
+		for _, prefix := range cellPrefixes {
+			if strings.HasPrefix(position.Cell, prefix) {
+				positions = append(positions, position)
+				break
+			}
+		}
+	}
+	return positions, nil
+}
+
+func (m *memoryStore) countLocationCells(startTime, endTime time.Time, precision int, limit int64) ([]models.HeatmapCell, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, location := range m.locations {
+		if location.Cell == "" || location.Timestamp.Before(startTime) || !location.Timestamp.Before(endTime) {
+			continue
+		}
+		cell := location.Cell
+		if len(cell) > precision {
+			cell = cell[:precision]
+		}
+		counts[cell]++
+	}
+
+	cells := make([]models.HeatmapCell, 0, len(counts))
+	for cell, count := range counts {
+		cells = append(cells, models.HeatmapCell{Cell: cell, Count: count})
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Count != cells[j].Count {
+			return cells[i].Count > cells[j].Count
+		}
+		return cells[i].Cell < cells[j].Cell
+	})
+	if limit > 0 && int64(len(cells)) > limit {
+		cells = cells[:limit]
+	}
+	return cells, nil
+}
+
+func (m *memoryStore) insertChatMessage(message models.ChatMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.chatMessages = append(m.chatMessages, message)
+	return nil
+}
+
+func (m *memoryStore) findChatMessageByID(bookingID, id string) (*models.ChatMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, message := range m.chatMessages {
+		if message.ID == id && message.BookingID == bookingID {
+			return &message, nil
+		}
+	}
+	return nil, ErrChatMessageNotFound
+}
+
+func (m *memoryStore) findChatMessages(bookingID string, before *models.ChatMessage, limit int64) ([]models.ChatMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// newer reports whether a sorts after b, matching the created_at then _id index order
+	newer := func(a, b *models.ChatMessage) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	}
+
+	var messages []models.ChatMessage
+	for _, message := range m.chatMessages {
+		if message.BookingID == bookingID && (before == nil || newer(before, &message)) {
+			messages = append(messages, message)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool { return newer(&messages[i], &messages[j]) })
+	if int64(len(messages)) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+func (m *memoryStore) findChatMessagesBySender(senderID string) ([]models.ChatMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var messages []models.ChatMessage
+	for _, message := range m.chatMessages {
+		if message.SenderID == senderID {
+			messages = append(messages, message)
+		}
+	}
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].CreatedAt.Before(messages[j].CreatedAt) })
+	return messages, nil
+}
+
+func (m *memoryStore) markChatMessagesRead(bookingID, readerID string, upTo, readAt time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var marked int64
+	for i := range m.chatMessages {
+		message := &m.chatMessages[i]
+		if message.BookingID == bookingID && message.SenderID != readerID && !message.CreatedAt.After(upTo) && message.ReadAt == nil {
+			at := readAt
+			message.ReadAt = &at
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+func (m *memoryStore) reportChatMessage(bookingID, id string, report models.ChatReport) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.chatMessages {
+		message := &m.chatMessages[i]
+		if message.ID != id || message.BookingID != bookingID {
+			continue
+		}
+		if message.Report != nil {
+			return ErrChatMessageReported
+		}
+		message.Report = &report
+		return nil
+	}
+	return ErrChatMessageNotFound
+}
+
+func (m *memoryStore) deleteChatMessagesBefore(cutoff, reportedCutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.chatMessages[:0]
+	for _, message := range m.chatMessages {
+		expired := message.CreatedAt.Before(cutoff) &&
+			(message.Report == nil || message.Report.ReportedAt.Before(reportedCutoff))
+		if !expired {
+			kept = append(kept, message)
+		}
+	}
+	deleted := int64(len(m.chatMessages) - len(kept))
+	m.chatMessages = kept
+	return deleted, nil
+}
+
+func (m *memoryStore) deleteChatMessage(bookingID, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, message := range m.chatMessages {
+		if message.ID == id && message.BookingID == bookingID {
+			m.chatMessages = append(m.chatMessages[:i], m.chatMessages[i+1:]...)
+			return nil
+		}
+	}
+	return ErrChatMessageNotFound
+}
+
+func (m *memoryStore) insertFlaggedContent(flagged models.FlaggedContent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.flagged = append(m.flagged, flagged)
+	return nil
+}
+
+func (m *memoryStore) findFlaggedContentByID(id string) (*models.FlaggedContent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, flagged := range m.flagged {
+		if flagged.ID == id {
+			return &flagged, nil
+		}
+	}
+	return nil, ErrFlaggedContentNotFound
+}
+
+// findFlaggedContent relies on flags being appended in the order they were created
+func (m *memoryStore) findFlaggedContent(status models.FlaggedStatus, limit int64) ([]models.FlaggedContent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var found []models.FlaggedContent
+	for _, flagged := range m.flagged {
+		if flagged.Status == status {
+			found = append(found, flagged)
+			if int64(len(found)) == limit {
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryStore) reviewFlaggedContent(id string, status models.FlaggedStatus, reviewerID string, reviewedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.flagged {
+		flagged := &m.flagged[i]
+		if flagged.ID != id {
+			continue
+		}
+		if flagged.Status != models.FlaggedStatusPending {
+			return ErrFlaggedContentReviewed
+		}
+		flagged.Status = status
+		flagged.ReviewedBy = reviewerID
+		flagged.ReviewedAt = &reviewedAt
+		return nil
+	}
+	return ErrFlaggedContentNotFound
+}
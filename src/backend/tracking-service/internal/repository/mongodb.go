@@ -5,22 +5,29 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	// go.mongodb.org/mongo-driver/mongo v1.11.0
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"go.mongodb.org/mongo-driver/bson"
 
 	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/metrics"
 	"src/backend/tracking-service/internal/models"
+	"src/backend/shared/dbmetrics"
 )
 
 // Human Tasks:
-// 1. Ensure MongoDB indexes are created for optimal query performance:
-//    - Create compound index on (timestamp, latitude, longitude)
-//    - Create TTL index on timestamp field if data retention is needed
+// 1. Indexes are created by EnsureIndexes at startup; remove duplicate points from existing
+//    data before deploying the unique location index. Add a TTL index on timestamp
+//    if location data retention is needed
 // 2. Configure MongoDB connection pooling based on expected load
 // 3. Set up MongoDB monitoring and alerting for performance metrics
 // 4. Review and adjust MongoDB timeout settings based on production requirements
@@ -39,17 +46,46 @@ const (
 // Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
 var MongoClient *mongo.Client
 
+// collectionOptions override the client's read preference and write concern for collections
+// whose operations need a different trade-off; collections not listed use the client's
+var collectionOptions = map[string]*options.CollectionOptions{}
+
 // Initialize initializes the MongoDB connection using the provided configuration
 func Initialize(cfg config.Config) error {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
+	readPreference, err := newReadPreference(cfg.ReadPreference)
+	if err != nil {
+		return err
+	}
+	writeConcern, err := newWriteConcern(cfg.WriteConcern, cfg.WriteConcernTimeout)
+	if err != nil {
+		return err
+	}
+	locationWriteConcern, err := newWriteConcern(cfg.LocationWriteConcern, cfg.WriteConcernTimeout)
+	if err != nil {
+		return err
+	}
+
 	// Configure MongoDB client options
 	clientOptions := options.Client().
 		ApplyURI(cfg.DatabaseURI).
 		SetMaxPoolSize(100).  // Adjust based on load requirements
 		SetMinPoolSize(10).   // Maintain minimum connections
-		SetMaxConnIdleTime(5 * time.Minute)
+		SetMaxConnIdleTime(5 * time.Minute).
+		SetReadPreference(readPreference).
+		SetWriteConcern(writeConcern).
+		SetMonitor(newCommandMonitor(dbmetrics.New("tracking-service", cfg.SlowQueryThreshold)))
+
+	// Walks are started and ended on the primary, so a session is never read back from a
+	// secondary that has not seen it yet; points and positions are written as fast as the
+	// location write concern allows
+	collectionOptions = map[string]*options.CollectionOptions{
+		sessionsCollectionName:  options.Collection().SetReadPreference(readpref.Primary()),
+		collectionName:          options.Collection().SetWriteConcern(locationWriteConcern),
+		positionsCollectionName: options.Collection().SetWriteConcern(locationWriteConcern),
+	}
 
 	// Connect to MongoDB
 	client, err := mongo.Connect(ctx, clientOptions)
@@ -67,27 +103,64 @@ func Initialize(cfg config.Config) error {
 
 	MongoClient = client
 	log.Printf("Successfully connected to MongoDB")
+
+	// Start the write-behind batch writer for location inserts
+	locationWriter = NewBatchWriter(cfg.BatchSize, cfg.BatchFlushInterval)
+	locationWriter.Start()
+
 	return nil
 }
 
+// newReadPreference returns the read preference for a configured mode
+func newReadPreference(mode string) (*readpref.ReadPref, error) {
+	readMode, err := readpref.ModeFromString(mode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid read preference %q: %w", mode, err)
+	}
+	return readpref.New(readMode)
+}
+
+// newWriteConcern returns the write concern for a configured "majority" or node count
+func newWriteConcern(w string, timeout time.Duration) (*writeconcern.WriteConcern, error) {
+	if w == "majority" {
+		return writeconcern.New(writeconcern.WMajority(), writeconcern.WTimeout(timeout)), nil
+	}
+	nodes, err := strconv.Atoi(w)
+	if err != nil || nodes < 1 {
+		return nil, fmt.Errorf("invalid write concern %q", w)
+	}
+	return writeconcern.New(writeconcern.W(nodes), writeconcern.WTimeout(timeout)), nil
+}
+
+// mongoCollection returns the named collection with its read preference and write concern
+func mongoCollection(name string) *mongo.Collection {
+	return MongoClient.Database(databaseName).Collection(name, collectionOptions[name])
+}
+
 // InsertLocation inserts a new location record into MongoDB
 // Addresses requirement: Real-time location tracking
 // Location: 1.2 System Overview/High-Level Description/Backend Services
 func InsertLocation(location models.Location) error {
+	if memory != nil {
+		return memory.insertLocations([]models.Location{location})
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
-	collection := MongoClient.Database(databaseName).Collection(collectionName)
+	collection := mongoCollection(collectionName)
 
-	// Convert location to BSON document
-	doc := bson.M{
-		"latitude":  location.Latitude,
-		"longitude": location.Longitude,
-		"timestamp": location.Timestamp,
+	sealed, err := sealLocation(location)
+	if err != nil {
+		return err
 	}
 
-	// Insert the document
-	_, err := collection.InsertOne(ctx, doc)
+	// Insert the document; optional device metadata is omitted when not reported
+	_, err = collection.InsertOne(ctx, sealed)
+	if mongo.IsDuplicateKeyError(err) {
+		metrics.LocationDuplicates.WithLabelValues("index").Inc()
+		return nil
+	}
 	if err != nil {
 		log.Printf("Failed to insert location: %v", err)
 		return err
@@ -100,10 +173,14 @@ func InsertLocation(location models.Location) error {
 // Addresses requirement: Real-time location tracking
 // Location: 1.2 System Overview/High-Level Description/Backend Services
 func FindLocationsByTimeRange(startTime, endTime time.Time) ([]models.Location, error) {
+	if memory != nil {
+		return memory.findLocationsByTimeRange(startTime, endTime)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
-	collection := MongoClient.Database(databaseName).Collection(collectionName)
+	collection := mongoCollection(collectionName)
 
 	// Create query filter for time range
 	filter := bson.M{
@@ -134,6 +211,10 @@ func FindLocationsByTimeRange(startTime, endTime time.Time) ([]models.Location,
 			log.Printf("Failed to decode location: %v", err)
 			continue
 		}
+		if err := openLocation(&loc); err != nil {
+			log.Printf("Failed to decrypt location: %v", err)
+			return nil, err
+		}
 		locations = append(locations, loc)
 	}
 
@@ -145,8 +226,87 @@ func FindLocationsByTimeRange(startTime, endTime time.Time) ([]models.Location,
 	return locations, nil
 }
 
-// Close closes the MongoDB connection
-func Close() error {
+// FindLocationsBySession retrieves every location point of a walk session in time order
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func FindLocationsBySession(sessionID string) ([]models.Location, error) {
+	if memory != nil {
+		return memory.findLocationsBySession(sessionID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(collectionName)
+
+	// Late points are stored out of arrival order, so the route is always ordered by recording
+	// time, with the device sequence number breaking ties between points in the same instant.
+	// The route is never cut short: summaries and exports need all of it, however long the walk.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "device_seq", Value: 1}})
+
+	cursor, err := collection.Find(ctx, bson.M{"session_id": sessionID}, opts)
+	if err != nil {
+		log.Printf("Failed to query session locations: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var locations []models.Location
+	if err := cursor.All(ctx, &locations); err != nil {
+		log.Printf("Failed to decode session locations: %v", err)
+		return nil, err
+	}
+	if err := openLocations(locations); err != nil {
+		log.Printf("Failed to decrypt session locations: %v", err)
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+// DeleteLocationsBefore removes every location point recorded before cutoff and returns how many were removed
+func DeleteLocationsBefore(cutoff time.Time) (int64, error) {
+	if memory != nil {
+		return memory.deleteLocationsBefore(cutoff)
+	}
+
+	// Deleting a large history can take far longer than a single query
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	collection := mongoCollection(collectionName)
+
+	result, err := collection.DeleteMany(ctx, bson.M{"timestamp": bson.M{"$lt": cutoff}})
+	if err != nil {
+		log.Printf("Failed to delete locations: %v", err)
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// Ping verifies MongoDB is reachable; used by the readiness probe
+func Ping(ctx context.Context) error {
+	if memory != nil {
+		return nil
+	}
+	if MongoClient == nil {
+		return errors.New("mongodb not initialized")
+	}
+	return MongoClient.Ping(ctx, nil)
+}
+
+// Close flushes buffered locations, waiting for them until ctx is done, and closes the
+// MongoDB connection
+func Close(ctx context.Context) error {
+	// Flush buffered points before the client is disconnected
+	if locationWriter != nil {
+		if err := locationWriter.Stop(ctx); err != nil {
+			log.Printf("Stopped waiting for buffered locations to flush: %v", err)
+		}
+	}
+
 	if MongoClient != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 		defer cancel()
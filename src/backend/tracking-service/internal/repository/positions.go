@@ -0,0 +1,146 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// positionsCollectionName is the collection holding each walker's last position
+const positionsCollectionName = "walker_positions"
+
+// ErrPositionNotFound is returned when a walker has not been seen during any walk
+var ErrPositionNotFound = errors.New("walker position not found")
+
+// SaveWalkerPosition records the cell a walker was last seen in, replacing their previous one
+func SaveWalkerPosition(position models.WalkerPosition) error {
+	if memory != nil {
+		return memory.saveWalkerPosition(position)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(positionsCollectionName)
+
+	_, err := collection.ReplaceOne(ctx, bson.M{"_id": position.WalkerID}, position, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Printf("Failed to save walker position: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindWalkerPosition retrieves the cell a walker was last seen in
+func FindWalkerPosition(walkerID string) (*models.WalkerPosition, error) {
+	if memory != nil {
+		return memory.findWalkerPosition(walkerID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(positionsCollectionName)
+
+	var position models.WalkerPosition
+	err := collection.FindOne(ctx, bson.M{"_id": walkerID}).Decode(&position)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrPositionNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to find walker position: %v", err)
+		return nil, err
+	}
+
+	return &position, nil
+}
+
+// FindWalkerPositions retrieves the walkers last seen since the given time in a cell starting
+// with any of cellPrefixes
+func FindWalkerPositions(cellPrefixes []string, since time.Time) ([]models.WalkerPosition, error) {
+	if memory != nil {
+		return memory.findWalkerPositions(cellPrefixes, since)
+	}
+	if len(cellPrefixes) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(positionsCollectionName)
+
+	// Anchored prefix matches are served by the cell index; geohashes need no escaping
+	cells := make(bson.A, 0, len(cellPrefixes))
+	for _, prefix := range cellPrefixes {
+		cells = append(cells, bson.M{"cell": bson.M{"$regex": "^" + prefix}})
+	}
+	filter := bson.M{"$or": cells, "updated_at": bson.M{"$gte": since}}
+
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		log.Printf("Failed to query walker positions: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var positions []models.WalkerPosition
+	if err := cursor.All(ctx, &positions); err != nil {
+		log.Printf("Failed to decode walker positions: %v", err)
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+// CountLocationCells counts the location points recorded in [startTime, endTime) per cell,
+// with cells truncated to precision characters, returning up to limit of the busiest cells
+func CountLocationCells(startTime, endTime time.Time, precision int, limit int64) ([]models.HeatmapCell, error) {
+	if memory != nil {
+		return memory.countLocationCells(startTime, endTime, precision, limit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	collection := mongoCollection(collectionName)
+
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"timestamp": bson.M{"$gte": startTime, "$lt": endTime},
+			"cell":      bson.M{"$exists": true},
+		}},
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"$substrCP": bson.A{"$cell", 0, precision}},
+			"count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		bson.M{"$limit": limit},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Printf("Failed to aggregate location cells: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cells []models.HeatmapCell
+	if err := cursor.All(ctx, &cells); err != nil {
+		log.Printf("Failed to decode location cells: %v", err)
+		return nil, err
+	}
+
+	return cells, nil
+}
@@ -0,0 +1,91 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// privacyZonesCollectionName is the collection holding walker privacy zones
+const privacyZonesCollectionName = "privacy_zones"
+
+// ErrPrivacyZoneNotFound is returned when a walker has no privacy zone with the requested ID
+var ErrPrivacyZoneNotFound = errors.New("privacy zone not found")
+
+// InsertPrivacyZone stores a new privacy zone
+func InsertPrivacyZone(zone models.PrivacyZone) error {
+	if memory != nil {
+		return memory.insertPrivacyZone(zone)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(privacyZonesCollectionName)
+
+	if _, err := collection.InsertOne(ctx, zone); err != nil {
+		log.Printf("Failed to insert privacy zone: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindPrivacyZonesByWalker retrieves a walker's privacy zones, oldest first
+func FindPrivacyZonesByWalker(walkerID string) ([]models.PrivacyZone, error) {
+	if memory != nil {
+		return memory.findPrivacyZonesByWalker(walkerID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(privacyZonesCollectionName)
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"walker_id": walkerID}, opts)
+	if err != nil {
+		log.Printf("Failed to query privacy zones: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var zones []models.PrivacyZone
+	if err := cursor.All(ctx, &zones); err != nil {
+		log.Printf("Failed to decode privacy zones: %v", err)
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// DeletePrivacyZone removes one of a walker's privacy zones
+func DeletePrivacyZone(walkerID, id string) error {
+	if memory != nil {
+		return memory.deletePrivacyZone(walkerID, id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(privacyZonesCollectionName)
+
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": id, "walker_id": walkerID})
+	if err != nil {
+		log.Printf("Failed to delete privacy zone: %v", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrPrivacyZoneNotFound
+	}
+
+	return nil
+}
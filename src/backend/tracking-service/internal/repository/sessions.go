@@ -0,0 +1,281 @@
+// Package repository implements MongoDB data access layer for the tracking-service
+// Version: 1.0.0
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+// sessionsCollectionName is the collection holding walk sessions
+const sessionsCollectionName = "sessions"
+
+// ErrSessionNotFound is returned when no walk session exists with the requested ID
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrSessionPaused is returned when pausing a walk session that is already paused
+var ErrSessionPaused = errors.New("session is already paused")
+
+// ErrSessionNotPaused is returned when resuming a walk session that is not paused
+var ErrSessionNotPaused = errors.New("session is not paused")
+
+// InsertSession stores a new walk session
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func InsertSession(session models.Session) error {
+	if memory != nil {
+		return memory.insertSession(session)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(sessionsCollectionName)
+
+	if _, err := collection.InsertOne(ctx, session); err != nil {
+		log.Printf("Failed to insert session: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindSessionByID retrieves a walk session by its ID
+func FindSessionByID(id string) (*models.Session, error) {
+	if memory != nil {
+		return memory.findSessionByID(id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(sessionsCollectionName)
+
+	var session models.Session
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&session)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to find session: %v", err)
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// EndSession marks an active walk session as ended
+func EndSession(id string, endedAt time.Time) error {
+	if memory != nil {
+		return memory.endSession(id, endedAt)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(sessionsCollectionName)
+
+	filter := bson.M{"_id": id, "status": models.SessionStatusActive}
+	update := bson.M{"$set": bson.M{
+		"status":   models.SessionStatusEnded,
+		"ended_at": endedAt,
+	}}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Printf("Failed to end session: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// TagSessionRegion sets the region of a walk session that has none yet; a session already
+// tagged keeps its region
+func TagSessionRegion(id, region string) error {
+	if memory != nil {
+		return memory.tagSessionRegion(id, region)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(sessionsCollectionName)
+
+	filter := bson.M{"_id": id, "region": bson.M{"$exists": false}}
+	if _, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"region": region}}); err != nil {
+		log.Printf("Failed to tag session region: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// AddSessionPhoto records a photo taken during an active walk session
+func AddSessionPhoto(id string, photo models.WalkPhoto) error {
+	if memory != nil {
+		return memory.addSessionPhoto(id, photo)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(sessionsCollectionName)
+
+	filter := bson.M{"_id": id, "status": models.SessionStatusActive}
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"photos": photo}})
+	if err != nil {
+		log.Printf("Failed to add session photo: %v", err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// PauseSession pauses an active walk session that is not already paused
+func PauseSession(id string, pausedAt time.Time) error {
+	if memory != nil {
+		return memory.pauseSession(id, pausedAt)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(sessionsCollectionName)
+
+	filter := bson.M{"_id": id, "status": models.SessionStatusActive, "paused_at": bson.M{"$exists": false}}
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"paused_at": pausedAt}})
+	if err != nil {
+		log.Printf("Failed to pause session: %v", err)
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// Nothing matched: tell a walk that is already paused from one that is not active
+	session, err := FindSessionByID(id)
+	if err != nil {
+		return err
+	}
+	if !session.IsActive() {
+		return ErrSessionNotFound
+	}
+	return ErrSessionPaused
+}
+
+// ResumeSession ends the current pause of an active walk session and returns it
+func ResumeSession(id string, resumedAt time.Time) (*models.WalkPause, error) {
+	if memory != nil {
+		return memory.resumeSession(id, resumedAt)
+	}
+
+	session, err := FindSessionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsActive() {
+		return nil, ErrSessionNotFound
+	}
+	if !session.Paused() {
+		return nil, ErrSessionNotPaused
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(sessionsCollectionName)
+
+	// The filter on the pause start makes the move atomic against a concurrent resume
+	pause := models.WalkPause{PausedAt: *session.PausedAt, ResumedAt: resumedAt}
+	filter := bson.M{"_id": id, "status": models.SessionStatusActive, "paused_at": pause.PausedAt}
+	update := bson.M{
+		"$unset": bson.M{"paused_at": ""},
+		"$push":  bson.M{"pauses": pause},
+	}
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.Printf("Failed to resume session: %v", err)
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, ErrSessionNotPaused
+	}
+
+	return &pause, nil
+}
+
+// FindSessionsByBooking retrieves every walk session started for a booking, group walks it
+// was part of included, oldest first
+func FindSessionsByBooking(bookingID string) ([]models.Session, error) {
+	if memory != nil {
+		return memory.findSessionsByBooking(bookingID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(sessionsCollectionName)
+
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: 1}})
+	filter := bson.M{"$or": bson.A{
+		bson.M{"booking_id": bookingID},
+		bson.M{"bookings.booking_id": bookingID},
+	}}
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Printf("Failed to query booking sessions: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []models.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		log.Printf("Failed to decode booking sessions: %v", err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// FindActiveSessions retrieves every walk session that has not ended
+func FindActiveSessions() ([]models.Session, error) {
+	if memory != nil {
+		return memory.findActiveSessions()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	collection := mongoCollection(sessionsCollectionName)
+
+	cursor, err := collection.Find(ctx, bson.M{"status": models.SessionStatusActive})
+	if err != nil {
+		log.Printf("Failed to query active sessions: %v", err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []models.Session
+	if err := cursor.All(ctx, &sessions); err != nil {
+		log.Printf("Failed to decode active sessions: %v", err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,160 @@
+// Package seed fills the tracking database with sample walk sessions and synthetic GPS
+// traces. Routes are drawn from the seed, so the same seed always draws the same walks.
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// metersPerDegree is the length of one degree of latitude
+const metersPerDegree = 111320.0
+
+// Options describe the walks a run seeds
+type Options struct {
+	// Seed drives every random choice; IDs are prefixed with it so several seeds can coexist
+	Seed int64
+
+	// Walks completed walks are spread over the week before Start, a UTC date, and Active
+	// walks are left active with a trace ending at Now
+	Walks  int
+	Active int
+	Start  time.Time
+	Now    time.Time
+
+	// Walks start around Latitude and Longitude and report a point every Interval
+	Latitude  float64
+	Longitude float64
+	Interval  time.Duration
+}
+
+// Report counts the records a run seeded
+type Report struct {
+	Walks  int
+	Active int
+	Points int
+}
+
+// walker is the simulated state of a walker moving along a synthetic route
+type walker struct {
+	rng       *rand.Rand
+	latitude  float64
+	longitude float64
+	heading   float64
+	speed     float64
+	battery   float64
+}
+
+// Run seeds the walks opts describe in the configured repository. Points are enqueued, so
+// they are only all stored once the repository is closed.
+func Run(opts Options) (*Report, error) {
+	if opts.Walks < 0 || opts.Active < 0 || opts.Interval <= 0 {
+		return nil, fmt.Errorf("walks and active must not be negative and interval must be positive")
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	prefix := fmt.Sprintf("seed%d", opts.Seed)
+	report := &Report{Walks: opts.Walks, Active: opts.Active}
+
+	for i := 0; i < opts.Walks+opts.Active; i++ {
+		session := models.NewSession(
+			fmt.Sprintf("%s-session-%04d", prefix, i+1),
+			fmt.Sprintf("%s-booking-%04d", prefix, i+1),
+			fmt.Sprintf("%s-walker-%03d", prefix, rng.Intn(10)+1),
+			fmt.Sprintf("%s-owner-%03d", prefix, rng.Intn(25)+1),
+		)
+		duration := time.Duration(30+15*rng.Intn(3)) * time.Minute
+
+		if i < opts.Walks {
+			// Completed walks start on a quarter hour during the day, some time in the past week
+			session.StartedAt = opts.Start.AddDate(0, 0, -1-rng.Intn(7)).
+				Add(8*time.Hour + time.Duration(rng.Intn(36))*15*time.Minute)
+			endedAt := session.StartedAt.Add(duration)
+			session.Status = models.SessionStatusEnded
+			session.EndedAt = &endedAt
+		} else {
+			// Active walks are part way through, with their last point at Now
+			elapsed := time.Duration(rng.Int63n(int64(duration)))
+			session.StartedAt = opts.Now.Add(-elapsed).Truncate(time.Second)
+			duration = elapsed
+		}
+
+		// Every seeded walk has the consent a real walk needs before it can start
+		for role, subjectID := range map[models.ConsentRole]string{
+			models.ConsentRoleWalker: session.WalkerID,
+			models.ConsentRoleOwner:  session.OwnerID,
+		} {
+			consent := models.Consent{
+				ID:           fmt.Sprintf("%s-%s-consent", session.ID, role),
+				BookingID:    session.BookingID,
+				SubjectID:    subjectID,
+				Role:         role,
+				TermsVersion: "seed",
+				GrantedAt:    session.StartedAt.Add(-time.Hour),
+			}
+			if err := repository.InsertConsent(consent); err != nil {
+				return nil, fmt.Errorf("failed to seed consent for %s: %w", session.ID, err)
+			}
+		}
+
+		if err := repository.InsertSession(*session); err != nil {
+			return nil, fmt.Errorf("failed to seed session %s: %w", session.ID, err)
+		}
+
+		w := newWalker(rng, opts.Latitude, opts.Longitude)
+		for at := session.StartedAt; !at.After(session.StartedAt.Add(duration)); at = at.Add(opts.Interval) {
+			location := w.step(opts.Interval, at)
+			location.SessionID = session.ID
+			if err := repository.EnqueueLocation(location); err != nil {
+				return nil, fmt.Errorf("failed to seed location: %w", err)
+			}
+			report.Points++
+		}
+	}
+
+	return report, nil
+}
+
+// newWalker places a walker within about a kilometre of the given point
+func newWalker(rng *rand.Rand, latitude, longitude float64) *walker {
+	return &walker{
+		rng:       rng,
+		latitude:  latitude + (rng.Float64()-0.5)*0.02,
+		longitude: longitude + (rng.Float64()-0.5)*0.02,
+		heading:   rng.Float64() * 360,
+		battery:   60 + rng.Float64()*40,
+	}
+}
+
+// step advances the walker by interval and returns the point it reports at t. Walkers drift
+// in heading, vary their pace and now and then stop while the dog sniffs around.
+func (w *walker) step(interval time.Duration, t time.Time) models.Location {
+	if w.rng.Float64() < 0.05 {
+		w.speed = 0
+	} else {
+		w.speed = 1.0 + w.rng.Float64()*0.8
+		w.heading = math.Mod(w.heading+w.rng.NormFloat64()*20+360, 360)
+	}
+
+	distance := w.speed * interval.Seconds()
+	radians := w.heading * math.Pi / 180
+	w.latitude += distance * math.Cos(radians) / metersPerDegree
+	w.longitude += distance * math.Sin(radians) / (metersPerDegree * math.Cos(w.latitude*math.Pi/180))
+	w.battery = math.Max(5, w.battery-interval.Hours()*8)
+
+	location := models.NewLocation(w.latitude, w.longitude, t)
+	accuracy := 3 + w.rng.Float64()*12
+	speed := w.speed
+	heading := w.heading
+	battery := math.Round(w.battery)
+	location.AccuracyMeters = &accuracy
+	location.Speed = &speed
+	location.Heading = &heading
+	location.BatteryPercent = &battery
+	return *location
+}
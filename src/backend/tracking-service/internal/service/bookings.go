@@ -0,0 +1,84 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"src/backend/shared/clients"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+var (
+	// ErrNotParticipant is returned when a user is neither the owner nor the walker of a booking
+	ErrNotParticipant = errors.New("not a participant of the booking")
+
+	// ErrBookingLookupUnavailable is returned when the booking-service cannot say who a
+	// booking's participants are
+	ErrBookingLookupUnavailable = errors.New("booking lookup unavailable")
+)
+
+// bookingDirectory looks bookings up at the booking-service; nil when it is not configured, in
+// which case a booking's participants are known only from the walks started for it
+var bookingDirectory *clients.BookingClient
+
+// newBookingDirectory creates the booking-service client for cfg's URL and API key, or nil
+func newBookingDirectory(baseURL, apiKey string) *clients.BookingClient {
+	if baseURL == "" {
+		return nil
+	}
+	// Participants wait on the lookup before every chat request, so a slow booking-service fails fast
+	return clients.NewBookingClient(baseURL, clients.Options{Timeout: 3 * time.Second, APIKey: apiKey})
+}
+
+// BookingParties returns the owner and walker of a booking. The walker is empty while none is
+// assigned.
+func BookingParties(ctx context.Context, bookingID string) (ownerID, walkerID string, err error) {
+	if bookingDirectory != nil {
+		booking, err := bookingDirectory.GetBooking(ctx, bookingID)
+		if errors.Is(err, clients.ErrNotFound) {
+			return "", "", ErrNotParticipant
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %v", ErrBookingLookupUnavailable, err)
+		}
+		return booking.OwnerID, booking.WalkerID, nil
+	}
+
+	sessions, err := repository.FindSessionsByBooking(bookingID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find walks of booking: %w", err)
+	}
+	for _, session := range sessions {
+		for _, booking := range session.AllBookings() {
+			if booking.BookingID == bookingID {
+				return booking.OwnerID, session.WalkerID, nil
+			}
+		}
+	}
+	return "", "", ErrNotParticipant
+}
+
+// BookingParticipant returns the role userID takes in a booking, returning ErrNotParticipant when
+// they are neither its owner nor its walker
+func BookingParticipant(ctx context.Context, bookingID, userID string) (models.ConsentRole, error) {
+	ownerID, walkerID, err := BookingParties(ctx, bookingID)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case userID == "":
+		return "", ErrNotParticipant
+	case userID == ownerID:
+		return models.ConsentRoleOwner, nil
+	case userID == walkerID:
+		return models.ConsentRoleWalker, nil
+	default:
+		return "", ErrNotParticipant
+	}
+}
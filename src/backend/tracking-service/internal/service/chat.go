@@ -0,0 +1,343 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"src/backend/shared/moderation"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// Chat events pushed to the subscribers of a booking's conversation
+const (
+	// EventChatMessage carries a newly sent message
+	EventChatMessage = "chat_message"
+
+	// EventChatRead tells the sender their messages have been read
+	EventChatRead = "chat_read"
+
+	// EventChatRemoved tells subscribers an admin took down the message with MessageID
+	EventChatRemoved = "chat_message_removed"
+)
+
+const (
+	// defaultChatPage and maxChatPage bound the number of messages returned per page
+	defaultChatPage = 50
+	maxChatPage     = 100
+
+	// maxReportReason is the longest an abuse report's reason may be, in characters
+	maxReportReason = 1000
+
+	// chatPurgeInterval is how often expired chat messages are removed
+	chatPurgeInterval = time.Hour
+
+	// chatTopicPrefix distinguishes booking conversations from walk sessions in hub topics
+	chatTopicPrefix = "chat:"
+)
+
+var (
+	// ErrChatMessageNotFound is returned when a booking has no message with the requested ID
+	ErrChatMessageNotFound = repository.ErrChatMessageNotFound
+
+	// ErrChatMessageReported is returned when reporting a message that has already been reported
+	ErrChatMessageReported = repository.ErrChatMessageReported
+
+	// ErrBookingRequired is returned when a chat connection token is requested without a booking
+	ErrBookingRequired = errors.New("booking ID is required")
+)
+
+// chatRetention and chatReportRetention are how long messages, and reported messages after
+// their report, are kept
+var (
+	chatRetention       = 90 * 24 * time.Hour
+	chatReportRetention = 365 * 24 * time.Hour
+)
+
+// ChatPage is one page of a booking's conversation, newest message first
+type ChatPage struct {
+	Messages []models.ChatMessage `json:"messages"`
+
+	// NextBefore is passed as before to fetch the next, older page; empty on the last page
+	NextBefore string `json:"next_before,omitempty"`
+}
+
+// ChatReceipt records a participant reading a booking's messages
+type ChatReceipt struct {
+	BookingID string    `json:"booking_id"`
+	ReaderID  string    `json:"reader_id"`
+	UpTo      string    `json:"up_to,omitempty"`
+	ReadAt    time.Time `json:"read_at"`
+	Marked    int64     `json:"marked"`
+}
+
+// chatEvent is the payload broadcast to a conversation's subscribers
+type chatEvent struct {
+	Event     string              `json:"event"`
+	BookingID string              `json:"booking_id"`
+	Message   *models.ChatMessage `json:"message,omitempty"`
+	MessageID string              `json:"message_id,omitempty"`
+	Receipt   *ChatReceipt        `json:"receipt,omitempty"`
+}
+
+// IssueChatToken creates a WebSocket connection token subscribed to a booking's conversation
+// for one of its participants, whose presence and typing the other participant is shown
+func IssueChatToken(bookingID, userID string, role models.ConsentRole) (string, time.Duration, error) {
+	if bookingID == "" {
+		return "", 0, ErrBookingRequired
+	}
+	if userID == "" {
+		return "", 0, fmt.Errorf("invalid chat connection: user_id is required")
+	}
+	if role != models.ConsentRoleWalker && role != models.ConsentRoleOwner {
+		return "", 0, fmt.Errorf("invalid chat connection: role %q must be walker or owner", role)
+	}
+
+	token, err := websocket.IssueParticipantToken(tokenSecret, chatTopic(bookingID), userID, string(role), tokenTTL)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to issue connection token: %w", err)
+	}
+	return token, tokenTTL, nil
+}
+
+// SendChatMessage stores a message from one participant of a booking to the other and pushes
+// it to everyone connected to the conversation. Messages the moderation filter blocks are
+// rejected; those it flags are delivered and queued for admin review.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func SendChatMessage(bookingID string, message models.ChatMessage) (*models.ChatMessage, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate message ID: %w", err)
+	}
+
+	message.ID = id
+	message.BookingID = bookingID
+	message.Body = strings.TrimSpace(message.Body)
+	message.CreatedAt = time.Now().UTC()
+	message.ReadAt = nil
+	message.Report = nil
+	if err := message.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid chat message: %w", err)
+	}
+
+	verdict := moderate(message.Body)
+	if verdict.Action == moderation.ActionBlock {
+		return nil, fmt.Errorf("invalid chat message: message contains blocked content")
+	}
+
+	if err := repository.InsertChatMessage(message); err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+	if verdict.Action == moderation.ActionFlag {
+		flagContent(models.FlaggedContent{
+			Source:    models.FlaggedSourceChatMessage,
+			SourceID:  message.ID,
+			BookingID: bookingID,
+			AuthorID:  message.SenderID,
+			Text:      message.Body,
+		}, verdict)
+	}
+
+	publishChatEvent(chatEvent{Event: EventChatMessage, BookingID: bookingID, Message: &message})
+	return &message, nil
+}
+
+// ListChatMessages returns a page of a booking's conversation, newest first, starting after the
+// message with ID before when it is set
+func ListChatMessages(bookingID, before string, limit int) (*ChatPage, error) {
+	if limit <= 0 {
+		limit = defaultChatPage
+	}
+	if limit > maxChatPage {
+		return nil, fmt.Errorf("invalid chat request: limit must be at most %d", maxChatPage)
+	}
+
+	var cursor *models.ChatMessage
+	if before != "" {
+		var err error
+		cursor, err = repository.FindChatMessageByID(bookingID, before)
+		if errors.Is(err, ErrChatMessageNotFound) {
+			return nil, fmt.Errorf("invalid chat request: before %s is not a message of booking %s", before, bookingID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve messages: %w", err)
+		}
+	}
+
+	// Fetch one extra message to learn whether there is an older page
+	messages, err := repository.FindChatMessages(bookingID, cursor, int64(limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve messages: %w", err)
+	}
+
+	page := &ChatPage{Messages: messages}
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		page.NextBefore = page.Messages[limit-1].ID
+	}
+	if page.Messages == nil {
+		page.Messages = []models.ChatMessage{}
+	}
+	return page, nil
+}
+
+// MarkChatRead marks the messages of a booking sent to readerID as read, up to and including the
+// message with ID upTo, or every message when upTo is empty, and tells the sender they were read
+func MarkChatRead(bookingID, readerID, upTo string) (*ChatReceipt, error) {
+	if readerID == "" {
+		return nil, fmt.Errorf("invalid read receipt: reader_id is required")
+	}
+
+	receipt := &ChatReceipt{BookingID: bookingID, ReaderID: readerID, UpTo: upTo, ReadAt: time.Now().UTC()}
+	until := receipt.ReadAt
+	if upTo != "" {
+		last, err := repository.FindChatMessageByID(bookingID, upTo)
+		if errors.Is(err, ErrChatMessageNotFound) {
+			return nil, fmt.Errorf("invalid read receipt: up_to %s is not a message of booking %s", upTo, bookingID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to mark messages read: %w", err)
+		}
+		until = last.CreatedAt
+	}
+
+	marked, err := repository.MarkChatMessagesRead(bookingID, readerID, until, receipt.ReadAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark messages read: %w", err)
+	}
+	receipt.Marked = marked
+
+	// Nothing newly read means the sender already has a receipt for these messages
+	if marked > 0 {
+		publishChatEvent(chatEvent{Event: EventChatRead, BookingID: bookingID, Receipt: receipt})
+	}
+	return receipt, nil
+}
+
+// ReportChatMessage reports a message as abusive on behalf of its recipient. The message is kept
+// past the usual retention as evidence, and an incident is opened for the admin queue and the
+// on-call channel.
+func ReportChatMessage(bookingID, messageID, reporterID, reason string) (*models.Incident, error) {
+	reason = strings.TrimSpace(reason)
+	if reporterID == "" {
+		return nil, fmt.Errorf("invalid report: reporter_id is required")
+	}
+	if len([]rune(reason)) > maxReportReason {
+		return nil, fmt.Errorf("invalid report: reason must be at most %d characters", maxReportReason)
+	}
+
+	message, err := repository.FindChatMessageByID(bookingID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.SenderID == reporterID {
+		return nil, fmt.Errorf("invalid report: participants cannot report their own messages")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate incident ID: %w", err)
+	}
+
+	now := time.Now()
+	incident := models.Incident{
+		ID:         id,
+		Type:       models.IncidentTypeAbusiveMessage,
+		Status:     models.IncidentStatusOpen,
+		BookingID:  bookingID,
+		ReportedBy: reporterID,
+		Message:    fmt.Sprintf("Chat message %s reported: %s", message.ID, reason),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if message.SenderRole == models.ConsentRoleWalker {
+		incident.WalkerID, incident.OwnerID = message.SenderID, reporterID
+	} else {
+		incident.WalkerID, incident.OwnerID = reporterID, message.SenderID
+	}
+	if err := incident.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid report: %w", err)
+	}
+
+	// Claim the message before opening the incident so a repeated report opens nothing
+	err = repository.ReportChatMessage(bookingID, messageID, models.ChatReport{
+		ReportedBy: reporterID,
+		Reason:     reason,
+		IncidentID: incident.ID,
+		ReportedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := repository.InsertIncident(incident); err != nil {
+		return nil, fmt.Errorf("failed to record incident: %w", err)
+	}
+
+	go alertIncident(incident)
+
+	log.Printf("Chat message %s on booking %s reported by %s (incident %s)", messageID, bookingID, reporterID, incident.ID)
+	return &incident, nil
+}
+
+// RunChatRetention removes chat messages past their retention until ctx is cancelled. Every
+// instance runs it, but each purge is claimed by one.
+func RunChatRetention(ctx context.Context) {
+	ticker := time.NewTicker(chatPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purgeChatMessages(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeChatMessages removes expired chat messages, unless another instance has just done so
+func purgeChatMessages(ctx context.Context) {
+	now := time.Now()
+	claimed, err := Hub.Claim(ctx, "chat-retention:"+now.Truncate(chatPurgeInterval).Format(time.RFC3339), chatPurgeInterval)
+	if err != nil {
+		log.Printf("Failed to claim chat retention purge: %v", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	deleted, err := repository.DeleteChatMessagesBefore(now.Add(-chatRetention), now.Add(-chatReportRetention))
+	if err != nil {
+		log.Printf("Failed to purge chat messages: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Purged %d chat messages past retention", deleted)
+	}
+}
+
+// publishChatEvent pushes an event to the subscribers of a booking's conversation
+func publishChatEvent(event chatEvent) {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to marshal %s event for booking %s: %v", event.Event, event.BookingID, err)
+		return
+	}
+	Hub.Publish(chatTopic(event.BookingID), websocket.KindChat, string(eventJSON))
+}
+
+// chatTopic is the hub topic a booking's conversation is published to
+func chatTopic(bookingID string) string {
+	return chatTopicPrefix + bookingID
+}
@@ -0,0 +1,132 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+var (
+	// ErrConsentRequired is returned when a walk is started without tracking consent
+	ErrConsentRequired = errors.New("tracking consent is required")
+
+	// ErrConsentNotFound is returned when revoking consent that was never given or already revoked
+	ErrConsentNotFound = repository.ErrConsentNotFound
+)
+
+// consentTermsVersion is the tracking terms version consent must be given to; empty accepts any
+var consentTermsVersion string
+
+// ConsentState summarises who has agreed to tracking for a booking
+type ConsentState struct {
+	BookingID       string           `json:"booking_id"`
+	WalkerConsented bool             `json:"walker_consented"`
+	OwnerConsented  bool             `json:"owner_consented"`
+	Records         []models.Consent `json:"records"`
+}
+
+// RecordConsent stores a person's consent to tracking for a booking
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func RecordConsent(bookingID string, consent models.Consent) (*models.Consent, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate consent ID: %w", err)
+	}
+
+	consent.ID = id
+	consent.BookingID = bookingID
+	consent.GrantedAt = time.Now()
+	consent.RevokedAt = nil
+	if err := consent.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid consent: %w", err)
+	}
+	if consentTermsVersion != "" && consent.TermsVersion != consentTermsVersion {
+		return nil, fmt.Errorf("invalid consent: terms_version %q is not the current version %q", consent.TermsVersion, consentTermsVersion)
+	}
+
+	if err := repository.InsertConsent(consent); err != nil {
+		return nil, fmt.Errorf("failed to record consent: %w", err)
+	}
+
+	log.Printf("Tracking consent recorded for booking %s by %s %s", bookingID, consent.Role, consent.SubjectID)
+	return &consent, nil
+}
+
+// RevokeConsent withdraws a person's consent to tracking for a booking. Walks already in
+// progress are not stopped; new walks for the booking are refused.
+func RevokeConsent(bookingID, subjectID string) error {
+	if err := repository.RevokeConsent(bookingID, subjectID, time.Now()); err != nil {
+		return err
+	}
+
+	log.Printf("Tracking consent revoked for booking %s by %s", bookingID, subjectID)
+	return nil
+}
+
+// GetConsentState returns a booking's consent records and whether each role has consented
+func GetConsentState(bookingID string) (*ConsentState, error) {
+	records, err := repository.FindConsents(bookingID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve consent: %w", err)
+	}
+
+	state := &ConsentState{BookingID: bookingID, Records: records}
+	if state.Records == nil {
+		state.Records = []models.Consent{}
+	}
+	for i := range records {
+		if !counts(&records[i]) {
+			continue
+		}
+		switch records[i].Role {
+		case models.ConsentRoleWalker:
+			state.WalkerConsented = true
+		case models.ConsentRoleOwner:
+			state.OwnerConsented = true
+		}
+	}
+	return state, nil
+}
+
+// requireConsent checks that both the walker and the owner have consented to tracking for the booking
+func requireConsent(bookingID, walkerID, ownerID string) error {
+	records, err := repository.FindConsents(bookingID, "")
+	if err != nil {
+		return fmt.Errorf("failed to check consent: %w", err)
+	}
+
+	var walker, owner bool
+	for i := range records {
+		if !counts(&records[i]) {
+			continue
+		}
+		switch {
+		case records[i].Role == models.ConsentRoleWalker && records[i].SubjectID == walkerID:
+			walker = true
+		case records[i].Role == models.ConsentRoleOwner && records[i].SubjectID == ownerID:
+			owner = true
+		}
+	}
+
+	switch {
+	case !walker && !owner:
+		return fmt.Errorf("%w from the walker and the owner", ErrConsentRequired)
+	case !walker:
+		return fmt.Errorf("%w from the walker", ErrConsentRequired)
+	case !owner:
+		return fmt.Errorf("%w from the owner", ErrConsentRequired)
+	}
+	return nil
+}
+
+// counts reports whether a consent record is active and given to the current terms
+func counts(consent *models.Consent) bool {
+	return consent.IsActive() && (consentTermsVersion == "" || consent.TermsVersion == consentTermsVersion)
+}
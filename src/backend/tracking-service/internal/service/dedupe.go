@@ -0,0 +1,58 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// recentPointTTL is how long a point is remembered for duplicate detection; client retries
+// normally arrive well within it, and the unique location index catches any that do not
+const recentPointTTL = 2 * time.Minute
+
+// recentPoints remembers the dedupe keys of points this instance accepted recently, so a retried
+// point is dropped before it is stored or broadcast
+type recentPoints struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+// seenPoints is the duplicate filter used by TrackLocation
+var seenPoints = newRecentPoints(recentPointTTL)
+
+func newRecentPoints(ttl time.Duration) *recentPoints {
+	return &recentPoints{ttl: ttl, seen: make(map[string]time.Time)}
+}
+
+// add records key and reports whether it was not already seen within the TTL
+func (r *recentPoints) add(key string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Sweep expired keys at most once per TTL so the map stays bounded by the recent point rate
+	if now.Sub(r.lastSweep) >= r.ttl {
+		for k, seenAt := range r.seen {
+			if now.Sub(seenAt) >= r.ttl {
+				delete(r.seen, k)
+			}
+		}
+		r.lastSweep = now
+	}
+
+	if seenAt, ok := r.seen[key]; ok && now.Sub(seenAt) < r.ttl {
+		return false
+	}
+	r.seen[key] = now
+	return true
+}
+
+// forget removes key, so a point that could not be stored is accepted when retried
+func (r *recentPoints) forget(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.seen, key)
+}
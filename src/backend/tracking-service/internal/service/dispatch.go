@@ -0,0 +1,46 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// ErrNoActiveWalk is reported to a dispatcher subscribing to a booking that is not being walked
+var ErrNoActiveWalk = errors.New("booking has no active walk")
+
+// IssueDispatchToken creates a signed WebSocket connection token for a dispatcher, who
+// subscribes to bookings over the one connection rather than connecting once per walk
+func IssueDispatchToken() (string, time.Duration, error) {
+	token, err := websocket.IssueDispatchToken(tokenSecret, tokenTTL)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to issue connection token: %w", err)
+	}
+	return token, tokenTTL, nil
+}
+
+// walkTopic returns the topic a booking's active walk is published to, for dispatch
+// subscriptions. Its errors are shown to the dispatcher.
+func walkTopic(bookingID string) (string, error) {
+	sessions, err := repository.FindSessionsByBooking(bookingID)
+	if err != nil {
+		log.Printf("Failed to look up walks of booking %s: %v", bookingID, err)
+		return "", websocket.ErrSubscriptionsUnavailable
+	}
+
+	// Sessions come oldest first; a booking is only walked once at a time
+	for i := len(sessions) - 1; i >= 0; i-- {
+		if sessions[i].Status == models.SessionStatusActive {
+			return sessions[i].ID, nil
+		}
+	}
+	return "", ErrNoActiveWalk
+}
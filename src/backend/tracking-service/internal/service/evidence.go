@@ -0,0 +1,108 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// RecordWalkPhoto records a photo the walker took during an active walk, and shows it to the
+// owner following the walk. A photo without a time is taken to have been taken now. On a
+// group walk a photo may name the bookings whose dogs it shows, which must be bookings of
+// the walk.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func RecordWalkPhoto(sessionID string, photo models.WalkPhoto) (*models.WalkPhoto, error) {
+	if sessionID == "" {
+		return nil, ErrSessionRequired
+	}
+	if photo.TakenAt.IsZero() {
+		photo.TakenAt = time.Now()
+	}
+	if err := photo.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid photo: %w", err)
+	}
+
+	if len(photo.BookingIDs) > 0 {
+		session, err := repository.FindSessionByID(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		for _, bookingID := range photo.BookingIDs {
+			if !session.HasBooking(bookingID) {
+				return nil, fmt.Errorf("invalid photo: booking %s is not part of the walk", bookingID)
+			}
+		}
+	}
+
+	if err := repository.AddSessionPhoto(sessionID, photo); err != nil {
+		return nil, err
+	}
+
+	event := sessionEvent{Event: EventWalkPhoto, SessionID: sessionID, PhotoURL: photo.URL, BookingIDs: photo.BookingIDs}
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session event: %w", err)
+	}
+	Hub.Publish(sessionID, websocket.KindEvent, string(eventJSON))
+	return &photo, nil
+}
+
+// BookingWalkEvidence sums up the duration, distance and photos tracked across every walk
+// session of a booking. Distances are measured along the whole route, privacy zones included,
+// as only the total leaves the service, snapped to the street/path network when map matching
+// is configured so that GPS jitter does not inflate them. Time the walker paused the walk is counted apart, and
+// stretches of route that overlap a pause are left out of the distance. A group walk counts
+// in full towards each of its bookings, apart from photos of the other bookings' dogs.
+func BookingWalkEvidence(bookingID string) (*models.WalkEvidence, error) {
+	if bookingID == "" {
+		return nil, fmt.Errorf("invalid evidence query: booking ID is required")
+	}
+
+	sessions, err := repository.FindSessionsByBooking(bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find walk sessions: %w", err)
+	}
+
+	evidence := &models.WalkEvidence{BookingID: bookingID, Sessions: len(sessions)}
+	now := time.Now()
+	for _, session := range sessions {
+		end := now
+		if session.EndedAt != nil {
+			end = *session.EndedAt
+		} else {
+			evidence.Active = true
+		}
+		paused := session.PausedBetween(session.StartedAt, end, now)
+		evidence.DurationSeconds += (end.Sub(session.StartedAt) - paused).Seconds()
+		evidence.PausedSeconds += paused.Seconds()
+		if session.IsGroup() {
+			evidence.Group = true
+		}
+		for i := range session.Photos {
+			if session.Photos[i].ShowsBooking(bookingID) {
+				evidence.Photos++
+			}
+		}
+
+		points, err := repository.FindLocationsBySession(session.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve session route: %w", err)
+		}
+		points = snapRoute(evidenceRouteKey(session.ID), points)
+		for i := 1; i < len(points); i++ {
+			from, to := points[i-1], points[i]
+			if from.Paused || to.Paused || session.PausedBetween(from.Timestamp, to.Timestamp, now) > 0 {
+				continue
+			}
+			evidence.DistanceMeters += models.DistanceMeters(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+		}
+	}
+	return evidence, nil
+}
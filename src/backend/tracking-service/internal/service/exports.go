@@ -0,0 +1,323 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/export"
+	"src/backend/tracking-service/internal/metrics"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+const (
+	// exportPollInterval is how often idle export workers look for jobs queued by other instances
+	exportPollInterval = 5 * time.Second
+
+	// exportHeartbeat is how often a running job's updated_at is refreshed
+	exportHeartbeat = 30 * time.Second
+
+	// exportStaleAfter is how long a running job may go without a heartbeat before another
+	// worker takes it over
+	exportStaleAfter = 5 * time.Minute
+)
+
+// ErrExportNotFound is returned when an export job does not exist
+var ErrExportNotFound = repository.ErrExportNotFound
+
+// ErrExportBacklogFull is returned when too many exports are waiting to be processed
+var ErrExportBacklogFull = errors.New("export backlog is full")
+
+// ErrExportForbidden is returned when a user may not request or read an export
+var ErrExportForbidden = errors.New("export not permitted")
+
+var (
+	exportStore      export.Store
+	exportWorkers          = 2
+	exportMaxPending int64 = 50
+	exportURLTTL           = 15 * time.Minute
+	exportWake             = make(chan struct{}, 1)
+)
+
+// ConfigureExports sets where finished exports are stored and how many are processed at once
+func ConfigureExports(store export.Store, cfg config.Config) {
+	exportStore = store
+	exportWorkers = cfg.ExportWorkers
+	exportMaxPending = cfg.ExportMaxPending
+	exportURLTTL = cfg.ExportURLTTL
+}
+
+// CreateExport queues an export of one walk session or of every point in a time range,
+// requested by requesterID. Users other than admins may only export sessions they take part
+// in; a time range without a session spans every walker, so only admins may export one. A
+// session's route is exported snapped to the street/path network when snapped is true.
+// New exports are refused while the backlog is full rather than left to wait indefinitely.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func CreateExport(requesterID string, admin bool, format models.ExportFormat, sessionID string, startTime, endTime *time.Time, snapped bool) (*models.ExportJob, error) {
+	if exportStore == nil {
+		return nil, fmt.Errorf("exports are not configured")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate export ID: %w", err)
+	}
+
+	job := models.NewExportJob(id, format, sessionID, startTime, endTime)
+	job.Snapped = snapped
+	job.RequestedBy = requesterID
+	if err := job.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid export request: %w", err)
+	}
+
+	if sessionID == "" && !admin {
+		return nil, ErrExportForbidden
+	}
+	if sessionID != "" {
+		session, err := GetSession(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if !admin && !session.HasParticipant(requesterID) {
+			return nil, ErrExportForbidden
+		}
+	}
+
+	pending, err := repository.CountPendingExportJobs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check export backlog: %w", err)
+	}
+	if pending >= exportMaxPending {
+		return nil, ErrExportBacklogFull
+	}
+
+	if err := repository.InsertExportJob(*job); err != nil {
+		return nil, fmt.Errorf("failed to queue export: %w", err)
+	}
+
+	// Wake an idle worker on this instance instead of waiting for its next poll
+	select {
+	case exportWake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+// GetExport returns an export job requested by requesterID, or any job to admins, with a fresh
+// download link once it has completed
+func GetExport(ctx context.Context, id, requesterID string, admin bool) (*models.ExportJob, error) {
+	job, err := AuthorizeExport(id, requesterID, admin)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status == models.ExportStatusCompleted && exportStore != nil {
+		url, err := exportStore.URL(ctx, job.ObjectKey, exportURLTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue download URL: %w", err)
+		}
+		job.DownloadURL = url
+	}
+
+	return job, nil
+}
+
+// AuthorizeExport returns an export job, or ErrExportForbidden unless requesterID requested it
+// or is an admin
+func AuthorizeExport(id, requesterID string, admin bool) (*models.ExportJob, error) {
+	job, err := repository.FindExportJobByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !admin && job.RequestedBy != requesterID {
+		return nil, ErrExportForbidden
+	}
+	return job, nil
+}
+
+// RunExportWorkers processes queued exports until ctx is cancelled. Jobs are claimed through
+// the repository, so workers on every instance share one queue.
+func RunExportWorkers(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < exportWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runExportWorker(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// runExportWorker claims and processes jobs until the queue is empty, then waits to be woken
+func runExportWorker(ctx context.Context) {
+	ticker := time.NewTicker(exportPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			now := time.Now()
+			job, err := repository.ClaimExportJob(now, now.Add(-exportStaleAfter))
+			if err != nil {
+				log.Printf("Failed to claim export job: %v", err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			processExport(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-exportWake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// processExport writes one job's file and records the outcome
+func processExport(ctx context.Context, job *models.ExportJob) {
+	start := time.Now()
+	key := fmt.Sprintf("exports/%s.%s", job.ID, export.FileExtension(job.Format))
+
+	count, err := writeExport(ctx, job, key)
+	now := time.Now()
+
+	if err != nil && ctx.Err() != nil {
+		// Shutting down: hand the job back so it is picked up again rather than failed
+		if err := repository.UpdateExportJob(job.ID, bson.M{"status": models.ExportStatusQueued, "updated_at": now}); err != nil {
+			log.Printf("Failed to requeue export %s: %v", job.ID, err)
+		}
+		return
+	}
+
+	if err != nil {
+		log.Printf("Export %s failed: %v", job.ID, err)
+		metrics.ExportJobs.WithLabelValues(string(models.ExportStatusFailed)).Inc()
+		if err := repository.UpdateExportJob(job.ID, bson.M{
+			"status":       models.ExportStatusFailed,
+			"error":        err.Error(),
+			"updated_at":   now,
+			"completed_at": now,
+		}); err != nil {
+			log.Printf("Failed to record export %s failure: %v", job.ID, err)
+		}
+		return
+	}
+
+	metrics.ExportJobs.WithLabelValues(string(models.ExportStatusCompleted)).Inc()
+	metrics.ExportDuration.Observe(now.Sub(start).Seconds())
+	if err := repository.UpdateExportJob(job.ID, bson.M{
+		"status":       models.ExportStatusCompleted,
+		"object_key":   key,
+		"point_count":  count,
+		"updated_at":   now,
+		"completed_at": now,
+	}); err != nil {
+		log.Printf("Failed to record export %s completion: %v", job.ID, err)
+	}
+}
+
+// writeExport streams the job's points through an encoder straight into the store. The pipe
+// between them has no buffer beyond the encoder's, so reading from MongoDB only proceeds as
+// fast as the store accepts data and memory use stays flat regardless of export size.
+func writeExport(ctx context.Context, job *models.ExportJob, key string) (int64, error) {
+	reader, writer := io.Pipe()
+
+	var count int64
+	encoded := make(chan error, 1)
+	go func() {
+		err := encodeExport(ctx, job, writer, &count)
+		writer.CloseWithError(err)
+		encoded <- err
+	}()
+
+	err := exportStore.Put(ctx, key, job.Format.ContentType(), reader)
+	// Unblock the encoder if the store gave up before reading everything
+	reader.CloseWithError(errors.New("export upload stopped"))
+
+	encodeErr := <-encoded
+	if err != nil {
+		return 0, err
+	}
+	if encodeErr != nil {
+		return 0, encodeErr
+	}
+	return count, nil
+}
+
+// encodeExport writes every point of the job to w in the job's format
+func encodeExport(ctx context.Context, job *models.ExportJob, w io.Writer, count *int64) error {
+	buffered := bufio.NewWriterSize(w, 64*1024)
+	encoder, err := export.NewEncoder(job.Format, buffered)
+	if err != nil {
+		return err
+	}
+
+	lastHeartbeat := time.Now()
+	write := func(location models.Location) error {
+		if time.Since(lastHeartbeat) >= exportHeartbeat {
+			lastHeartbeat = time.Now()
+			if err := repository.UpdateExportJob(job.ID, bson.M{"updated_at": lastHeartbeat}); err != nil {
+				log.Printf("Failed to refresh export %s heartbeat: %v", job.ID, err)
+			}
+		}
+		if isPrivate(location) {
+			metrics.LocationsMasked.Inc()
+			return nil
+		}
+		*count++
+		return encoder.Write(location)
+	}
+
+	if job.Snapped {
+		err = writeSnappedRoute(ctx, job, write)
+	} else {
+		err = repository.StreamLocations(ctx, job.SessionID, job.StartTime, job.EndTime, write)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// writeSnappedRoute passes the job's session route, snapped to the street/path network, to
+// write point by point. Matching needs the whole route at once, so unlike raw exports it is
+// held in memory rather than streamed.
+func writeSnappedRoute(ctx context.Context, job *models.ExportJob, write func(models.Location) error) error {
+	route, err := GetSessionRoute(job.SessionID, true)
+	if err != nil {
+		return err
+	}
+	for _, location := range route {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if job.StartTime != nil && (location.Timestamp.Before(*job.StartTime) || location.Timestamp.After(*job.EndTime)) {
+			continue
+		}
+		if err := write(location); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+const (
+	// FleetChannel is the dispatch channel streaming the fleet digest
+	FleetChannel = "fleet"
+
+	// fleetTopic is the hub topic the fleet digest is delivered to
+	fleetTopic = "dispatch:fleet"
+
+	// fleetDigestInterval is how often dispatchers receive the fleet digest
+	fleetDigestInterval = time.Second
+)
+
+// FleetWalk summarizes one active walk for the dispatch map
+type FleetWalk struct {
+	SessionID string    `json:"session_id"`
+	BookingID string    `json:"booking_id"`
+	WalkerID  string    `json:"walker_id"`
+	StartedAt time.Time `json:"started_at"`
+
+	// BookingIDs lists every booking of a group walk, BookingID first; empty for other walks
+	BookingIDs []string `json:"booking_ids,omitempty"`
+
+	// Position is the walk's last broadcast point; nil until one arrives
+	Position *FleetPosition `json:"position,omitempty"`
+
+	// LastSeenAt is when the walker's app last reported, with or without a new point
+	LastSeenAt time.Time `json:"last_seen_at"`
+
+	// Stale is set once the walk has gone without reports for the staleness threshold
+	Stale bool `json:"stale"`
+
+	// Paused is set while the walker has paused the walk
+	Paused bool `json:"paused"`
+}
+
+// FleetPosition is a walk's last broadcast point
+type FleetPosition struct {
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// FleetDigest is the summary of every active walk sent to dispatchers each fleetDigestInterval,
+// in place of each walk's raw stream
+type FleetDigest struct {
+	Active int         `json:"active"`
+	Stale  int         `json:"stale"`
+	Walks  []FleetWalk `json:"walks"`
+}
+
+// fleetState keeps the summary of every active walk. Every instance observes every walk's
+// traffic through the hub, so each builds the digest for its own dispatchers without
+// publishing it to the others.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type fleetState struct {
+	staleAfter time.Duration
+
+	mu    sync.Mutex
+	walks map[string]*FleetWalk
+}
+
+// fleet is the fleet state of this instance, set up by Initialize
+var fleet = newFleetState(time.Minute)
+
+// newFleetState creates an empty fleet state flagging walks stale after staleAfter
+func newFleetState(staleAfter time.Duration) *fleetState {
+	return &fleetState{staleAfter: staleAfter, walks: make(map[string]*FleetWalk)}
+}
+
+// observe feeds hub traffic into the fleet state; it runs on the hub loop and must not block
+func (f *fleetState) observe(message websocket.Message) {
+	switch message.Kind {
+	case websocket.KindLocation:
+		var location models.Location
+		if err := json.Unmarshal([]byte(message.Data), &location); err != nil {
+			log.Printf("Failed to decode location for the fleet digest: %v", err)
+			return
+		}
+		f.touch(message.Topic, &FleetPosition{
+			Latitude:  location.Latitude,
+			Longitude: location.Longitude,
+			Timestamp: location.Timestamp,
+		})
+
+	case websocket.KindHeartbeat:
+		f.touch(message.Topic, nil)
+
+	case websocket.KindSessionStarted:
+		var session models.Session
+		if err := json.Unmarshal([]byte(message.Data), &session); err != nil {
+			log.Printf("Failed to decode started session for the fleet digest: %v", err)
+			return
+		}
+		f.track(session)
+
+	case websocket.KindSessionEnded:
+		f.mu.Lock()
+		delete(f.walks, message.Topic)
+		f.mu.Unlock()
+
+	case websocket.KindEvent:
+		var event sessionEvent
+		if err := json.Unmarshal([]byte(message.Data), &event); err != nil {
+			return
+		}
+		if event.Event == EventWalkPaused || event.Event == EventWalkResumed {
+			f.mu.Lock()
+			if walk, ok := f.walks[message.Topic]; ok {
+				walk.Paused = event.Event == EventWalkPaused
+			}
+			f.mu.Unlock()
+		}
+	}
+}
+
+// track adds an active session to the fleet
+func (f *fleetState) track(session models.Session) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.walks[session.ID]; ok {
+		return
+	}
+	walk := &FleetWalk{
+		SessionID:  session.ID,
+		BookingID:  session.BookingID,
+		WalkerID:   session.WalkerID,
+		StartedAt:  session.StartedAt,
+		LastSeenAt: serviceClock.Now().UTC(),
+		Paused:     session.Paused(),
+	}
+	if session.IsGroup() {
+		for _, booking := range session.Bookings {
+			walk.BookingIDs = append(walk.BookingIDs, booking.BookingID)
+		}
+	}
+	f.walks[session.ID] = walk
+}
+
+// touch records a report from a walk, moving it to position when one is given
+func (f *fleetState) touch(sessionID string, position *FleetPosition) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	walk, ok := f.walks[sessionID]
+	if !ok {
+		return
+	}
+	walk.LastSeenAt = serviceClock.Now().UTC()
+	if position != nil {
+		walk.Position = position
+	}
+}
+
+// tenant returns the walker whose walk is published to topic, for the hub's fan-out limits,
+// or "" for topics that are not active walks
+func (f *fleetState) tenant(topic string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if walk, ok := f.walks[topic]; ok {
+		return "walker:" + walk.WalkerID
+	}
+	return ""
+}
+
+// digest summarizes the fleet as of now, walks ordered by when they started
+func (f *fleetState) digest(now time.Time) FleetDigest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	digest := FleetDigest{Walks: make([]FleetWalk, 0, len(f.walks))}
+	for _, walk := range f.walks {
+		summary := *walk
+		summary.Stale = now.Sub(walk.LastSeenAt) > f.staleAfter
+		if summary.Stale {
+			digest.Stale++
+		}
+		digest.Walks = append(digest.Walks, summary)
+	}
+	digest.Active = len(digest.Walks)
+
+	sort.Slice(digest.Walks, func(i, j int) bool {
+		if !digest.Walks[i].StartedAt.Equal(digest.Walks[j].StartedAt) {
+			return digest.Walks[i].StartedAt.Before(digest.Walks[j].StartedAt)
+		}
+		return digest.Walks[i].SessionID < digest.Walks[j].SessionID
+	})
+	return digest
+}
+
+// RunFleetDigest delivers the fleet digest to this instance's dispatchers every
+// fleetDigestInterval until ctx is cancelled; nothing is built while none are subscribed
+func RunFleetDigest(ctx context.Context) {
+	ticker := time.NewTicker(fleetDigestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			publishFleetDigest(serviceClock.Now())
+		}
+	}
+}
+
+// publishFleetDigest delivers the current fleet digest to this instance's dispatchers
+func publishFleetDigest(now time.Time) {
+	if Hub.Subscribers(fleetTopic) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(fleet.digest(now))
+	if err != nil {
+		log.Printf("Failed to marshal fleet digest: %v", err)
+		return
+	}
+	Hub.PublishLocal(fleetTopic, websocket.KindFleet, string(payload))
+}
@@ -0,0 +1,196 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"src/backend/shared/clock"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/notifier"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// incidentQueueLimit caps the number of incidents returned by the admin queue
+const incidentQueueLimit = 200
+
+// ErrIncidentNotFound is returned when an incident does not exist
+var ErrIncidentNotFound = repository.ErrIncidentNotFound
+
+// IncidentUpdate holds the incident fields a caller may change; nil fields are left as they are
+type IncidentUpdate struct {
+	Status     *models.IncidentStatus `json:"status"`
+	Message    *string                `json:"message"`
+	Resolution *string                `json:"resolution"`
+}
+
+// CreateIncident records an incident reported during or after a walk. When it names a walk
+// session, the booking and participants are taken from the session.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func CreateIncident(ctx context.Context, incident models.Incident) (*models.Incident, error) {
+	if incident.Type == models.IncidentTypeSOS {
+		return nil, fmt.Errorf("invalid incident data: emergencies must be raised through the SOS endpoint")
+	}
+	if incident.Type == models.IncidentTypeAbusiveMessage {
+		return nil, fmt.Errorf("invalid incident data: abusive messages must be reported from the booking chat")
+	}
+
+	if incident.SessionID != "" {
+		session, err := GetSession(incident.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		incident.BookingID = session.BookingID
+		incident.WalkerID = session.WalkerID
+		incident.OwnerID = session.OwnerID
+		incident.Region = session.Region
+	}
+	incident.Region = incidentRegion(incident.Location, incident.Region)
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate incident ID: %w", err)
+	}
+
+	now := clock.FromContext(ctx).Now()
+	incident.ID = id
+	incident.Status = models.IncidentStatusOpen
+	incident.CreatedAt = now
+	incident.UpdatedAt = now
+	incident.ResolvedAt = nil
+	incident.Resolution = ""
+	for i := range incident.Attachments {
+		if err := incident.Attachments[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid incident data: %w", err)
+		}
+		incident.Attachments[i].AddedBy = incident.ReportedBy
+		incident.Attachments[i].AddedAt = now
+	}
+
+	if err := incident.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid incident data: %w", err)
+	}
+
+	if err := repository.InsertIncident(incident); err != nil {
+		return nil, fmt.Errorf("failed to record incident: %w", err)
+	}
+
+	go alertIncident(incident)
+
+	log.Printf("Incident %s (%s) reported for booking %s", incident.ID, incident.Type, incident.BookingID)
+	return &incident, nil
+}
+
+// GetIncident retrieves an incident by ID
+func GetIncident(id string) (*models.Incident, error) {
+	incident, err := repository.FindIncidentByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve incident: %w", err)
+	}
+	return incident, nil
+}
+
+// UpdateIncident applies an update to an incident, enforcing the status workflow.
+// Resolving an incident requires a resolution.
+func UpdateIncident(ctx context.Context, id string, update IncidentUpdate) (*models.Incident, error) {
+	incident, err := GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.FromContext(ctx).Now()
+	fields := bson.M{"updated_at": now}
+
+	if update.Message != nil {
+		fields["message"] = *update.Message
+	}
+	if update.Resolution != nil {
+		fields["resolution"] = strings.TrimSpace(*update.Resolution)
+		incident.Resolution = strings.TrimSpace(*update.Resolution)
+	}
+
+	if update.Status != nil && *update.Status != incident.Status {
+		next := *update.Status
+		if !incident.CanTransition(next) {
+			return nil, fmt.Errorf("invalid incident update: cannot move from %s to %s", incident.Status, next)
+		}
+		if next == models.IncidentStatusResolved {
+			if incident.Resolution == "" {
+				return nil, fmt.Errorf("invalid incident update: a resolution is required to resolve an incident")
+			}
+			fields["resolved_at"] = now
+		}
+		if next == models.IncidentStatusInvestigating && incident.ResolvedAt != nil {
+			// Reopened for further investigation
+			fields["resolved_at"] = nil
+		}
+		fields["status"] = next
+	}
+
+	err = repository.UpdateIncident(id, incident.Status, fields)
+	if errors.Is(err, repository.ErrIncidentChanged) {
+		return nil, fmt.Errorf("incident conflict: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update incident: %w", err)
+	}
+
+	return GetIncident(id)
+}
+
+// AddIncidentAttachment attaches a file from the media subsystem to an incident
+func AddIncidentAttachment(ctx context.Context, id string, attachment models.Attachment) (*models.Incident, error) {
+	if err := attachment.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid attachment: %w", err)
+	}
+	attachment.AddedAt = clock.FromContext(ctx).Now()
+
+	if err := repository.AddIncidentAttachment(id, attachment); err != nil {
+		return nil, fmt.Errorf("failed to add attachment: %w", err)
+	}
+
+	return GetIncident(id)
+}
+
+// IncidentQueue lists unresolved incidents, oldest first, for the admin queue. An empty status
+// selects every incident still needing attention; a non-empty region limits it to that region.
+func IncidentQueue(status models.IncidentStatus, incidentType models.IncidentType, region string) ([]models.Incident, error) {
+	statuses := []models.IncidentStatus{models.IncidentStatusOpen, models.IncidentStatusInvestigating}
+	if status != "" {
+		statuses = []models.IncidentStatus{status}
+	}
+
+	incidents, err := repository.FindIncidentQueue(statuses, incidentType, strings.ToLower(strings.TrimSpace(region)), incidentQueueLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve incident queue: %w", err)
+	}
+	return incidents, nil
+}
+
+// alertIncident tells the on-call admin channel about a newly reported incident
+func alertIncident(incident models.Incident) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := alerter.Alert(ctx, notifier.Alert{
+		Title: fmt.Sprintf("Incident reported: %s", incident.Type),
+		Text:  incident.Message,
+		Fields: map[string]string{
+			"incident_id": incident.ID,
+			"booking_id":  incident.BookingID,
+			"session_id":  incident.SessionID,
+			"reported_by": incident.ReportedBy,
+		},
+	})
+	if err != nil {
+		log.Printf("Failed to alert on-call channel of incident %s: %v", incident.ID, err)
+	}
+}
@@ -0,0 +1,135 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"src/backend/shared/moderation"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+const (
+	// defaultFlaggedPage and maxFlaggedPage bound the flagged content returned per request
+	defaultFlaggedPage = 50
+	maxFlaggedPage     = 200
+
+	// moderationTimeout bounds how long text waits on the moderation filter
+	moderationTimeout = 2 * time.Second
+)
+
+var (
+	// ErrFlaggedContentNotFound is returned when no flagged content has the requested ID
+	ErrFlaggedContentNotFound = repository.ErrFlaggedContentNotFound
+
+	// ErrFlaggedContentReviewed is returned when reviewing flagged content that has already been reviewed
+	ErrFlaggedContentReviewed = repository.ErrFlaggedContentReviewed
+)
+
+// moderate reviews text with the moderation filter. A filter error allows the text, so an
+// unavailable provider does not stop the conversation.
+func moderate(text string) moderation.Verdict {
+	ctx, cancel := context.WithTimeout(context.Background(), moderationTimeout)
+	defer cancel()
+
+	verdict, err := moderation.Review(ctx, text)
+	if err != nil {
+		log.Printf("Failed to moderate content, allowing it: %v", err)
+		return moderation.Verdict{Action: moderation.ActionAllow}
+	}
+	return verdict
+}
+
+// flagContent queues content the filter let through for admin review. Failures are logged:
+// the content has already been accepted.
+func flagContent(flagged models.FlaggedContent, verdict moderation.Verdict) {
+	id, err := newID()
+	if err != nil {
+		log.Printf("Failed to generate flag ID for %s %s: %v", flagged.Source, flagged.SourceID, err)
+		return
+	}
+
+	flagged.ID = id
+	flagged.Matches = verdict.Matches
+	flagged.Status = models.FlaggedStatusPending
+	flagged.CreatedAt = time.Now().UTC()
+	if err := repository.InsertFlaggedContent(flagged); err != nil {
+		log.Printf("Failed to flag %s %s for review: %v", flagged.Source, flagged.SourceID, err)
+		return
+	}
+	log.Printf("Flagged %s %s for review (flag %s)", flagged.Source, flagged.SourceID, flagged.ID)
+}
+
+// ListFlaggedContent returns flagged content with the given status, pending by default, oldest first
+func ListFlaggedContent(status models.FlaggedStatus, limit int) ([]models.FlaggedContent, error) {
+	if status == "" {
+		status = models.FlaggedStatusPending
+	}
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid flagged content request: unknown status %q", status)
+	}
+	if limit <= 0 {
+		limit = defaultFlaggedPage
+	}
+	if limit > maxFlaggedPage {
+		return nil, fmt.Errorf("invalid flagged content request: limit must be at most %d", maxFlaggedPage)
+	}
+
+	flagged, err := repository.FindFlaggedContent(status, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve flagged content: %w", err)
+	}
+	if flagged == nil {
+		flagged = []models.FlaggedContent{}
+	}
+	return flagged, nil
+}
+
+// ReviewFlaggedContent records an admin's decision on flagged content. Removing a chat message
+// deletes it and tells the conversation's subscribers to drop it; the flag keeps its text.
+func ReviewFlaggedContent(id, reviewerID string, decision models.FlaggedStatus) (*models.FlaggedContent, error) {
+	if reviewerID == "" {
+		return nil, fmt.Errorf("invalid review: reviewer_id is required")
+	}
+	if decision != models.FlaggedStatusDismissed && decision != models.FlaggedStatusRemoved {
+		return nil, fmt.Errorf("invalid review: decision %q must be dismissed or removed", decision)
+	}
+
+	flagged, err := repository.FindFlaggedContentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if flagged.Status != models.FlaggedStatusPending {
+		return nil, ErrFlaggedContentReviewed
+	}
+
+	// Take the content down first so a failure leaves the flag pending to retry
+	if decision == models.FlaggedStatusRemoved && flagged.Source == models.FlaggedSourceChatMessage {
+		err := repository.DeleteChatMessage(flagged.BookingID, flagged.SourceID)
+		if err != nil && !errors.Is(err, ErrChatMessageNotFound) {
+			return nil, fmt.Errorf("failed to remove message: %w", err)
+		}
+		publishChatEvent(chatEvent{
+			Event:     EventChatRemoved,
+			BookingID: flagged.BookingID,
+			MessageID: flagged.SourceID,
+		})
+	}
+
+	now := time.Now().UTC()
+	if err := repository.ReviewFlaggedContent(id, decision, reviewerID, now); err != nil {
+		return nil, err
+	}
+	flagged.Status = decision
+	flagged.ReviewedBy = reviewerID
+	flagged.ReviewedAt = &now
+
+	log.Printf("Flagged %s %s reviewed by %s: %s", flagged.Source, flagged.SourceID, reviewerID, decision)
+	return flagged, nil
+}
@@ -0,0 +1,90 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"log"
+	"sync"
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// maxOrderedSessions bounds the number of sessions whose last broadcast point is remembered
+const maxOrderedSessions = 10000
+
+// liveOrder keeps each session's live stream moving forward in time. Clients that buffer
+// points while offline deliver them late and out of order; those points are still stored,
+// but only points newer than the last one broadcast reach subscribers.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type liveOrder struct {
+	mu   sync.Mutex
+	last map[string]models.Location
+}
+
+// broadcastOrder is the ordering filter used by TrackLocation
+var broadcastOrder = &liveOrder{last: make(map[string]models.Location)}
+
+// observe reports whether location is late: recorded no later than the last point broadcast
+// for its session. When the point is not late and broadcast is true it becomes the new
+// last point.
+func (o *liveOrder) observe(location models.Location, broadcast bool) bool {
+	o.mu.Lock()
+	_, tracked := o.last[location.SessionID]
+	o.mu.Unlock()
+
+	// First point this instance has seen for the session, perhaps after a restart or a client
+	// moving between instances: start from the newest stored point, read outside the lock
+	if !tracked {
+		if stored := latestStoredLocation(location.SessionID); stored != nil {
+			o.mu.Lock()
+			if _, ok := o.last[location.SessionID]; !ok {
+				o.remember(*stored)
+			}
+			o.mu.Unlock()
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if last, ok := o.last[location.SessionID]; ok && !last.Precedes(&location) {
+		return true
+	}
+	if broadcast {
+		o.remember(location)
+	}
+	return false
+}
+
+// remember records location as its session's last broadcast point. Callers hold o.mu.
+func (o *liveOrder) remember(location models.Location) {
+	if _, ok := o.last[location.SessionID]; !ok && len(o.last) >= maxOrderedSessions {
+		// Evict an arbitrary session; it is reseeded from storage if it sends again
+		for id := range o.last {
+			delete(o.last, id)
+			break
+		}
+	}
+	o.last[location.SessionID] = location
+}
+
+// forget drops a session's ordering state once it has ended
+func (o *liveOrder) forget(sessionID string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.last, sessionID)
+}
+
+// latestStoredLocation returns the newest stored point of a session, or nil if there is none
+// or it cannot be read; ordering then starts from the next point received
+func latestStoredLocation(sessionID string) *models.Location {
+	location, err := repository.FindLatestLocation(sessionID)
+	if err != nil {
+		log.Printf("Failed to load latest location of session %s: %v", sessionID, err)
+		return nil
+	}
+	return location
+}
@@ -0,0 +1,191 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"src/backend/shared/clock"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// maxPausedSessions bounds the number of sessions whose pauses are kept for marking points
+const maxPausedSessions = 10000
+
+// ErrSessionPaused is returned when pausing a walk that is already paused
+var ErrSessionPaused = repository.ErrSessionPaused
+
+// ErrSessionNotPaused is returned when resuming a walk that is not paused
+var ErrSessionNotPaused = repository.ErrSessionNotPaused
+
+// PauseSession pauses an active walk, for instance while the walker pops into a store. Points
+// reported until the walk is resumed are marked paused, and the pause is shown to the owner.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func PauseSession(ctx context.Context, id string) (*models.Session, error) {
+	if id == "" {
+		return nil, ErrSessionRequired
+	}
+
+	pausedAt := clock.FromContext(ctx).Now().UTC()
+	if err := repository.PauseSession(id, pausedAt); err != nil {
+		return nil, fmt.Errorf("failed to pause session: %w", err)
+	}
+	walkPauses.pause(id, pausedAt)
+
+	if err := publishPauseEvent(sessionEvent{Event: EventWalkPaused, SessionID: id, PausedAt: &pausedAt}); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Walk session %s paused", id)
+	return GetSession(id)
+}
+
+// ResumeSession carries on a paused walk
+func ResumeSession(ctx context.Context, id string) (*models.Session, error) {
+	if id == "" {
+		return nil, ErrSessionRequired
+	}
+
+	pause, err := repository.ResumeSession(id, clock.FromContext(ctx).Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume session: %w", err)
+	}
+	walkPauses.resume(id, *pause)
+
+	event := sessionEvent{Event: EventWalkResumed, SessionID: id, PausedAt: &pause.PausedAt, ResumedAt: &pause.ResumedAt}
+	if err := publishPauseEvent(event); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Walk session %s resumed after %s", id, pause.ResumedAt.Sub(pause.PausedAt).Round(time.Second))
+	return GetSession(id)
+}
+
+// publishPauseEvent tells the walk's subscribers, and every instance, that it paused or resumed
+func publishPauseEvent(event sessionEvent) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session event: %w", err)
+	}
+	Hub.Publish(event.SessionID, websocket.KindEvent, string(eventJSON))
+	return nil
+}
+
+// pauseLog keeps the pauses of the walks that have paused, so points can be marked paused as
+// they arrive without reading the session. Every instance learns of pauses through the hub's
+// session events, and late points are marked by the pause their timestamp falls in.
+type pauseLog struct {
+	mu       sync.Mutex
+	sessions map[string]*models.Session
+}
+
+// walkPauses is the pause log of this instance
+var walkPauses = &pauseLog{sessions: make(map[string]*models.Session)}
+
+// observe feeds hub traffic into the log; it runs on the hub loop and must not block
+func (p *pauseLog) observe(message websocket.Message) {
+	switch message.Kind {
+	case websocket.KindEvent:
+		var event sessionEvent
+		if err := json.Unmarshal([]byte(message.Data), &event); err != nil {
+			return
+		}
+		switch {
+		case event.Event == EventWalkPaused && event.PausedAt != nil:
+			p.pause(message.Topic, *event.PausedAt)
+		case event.Event == EventWalkResumed && event.PausedAt != nil && event.ResumedAt != nil:
+			p.resume(message.Topic, models.WalkPause{PausedAt: *event.PausedAt, ResumedAt: *event.ResumedAt})
+		}
+
+	case websocket.KindSessionEnded:
+		p.forget(message.Topic)
+	}
+}
+
+// track loads the pauses of a session that was active before this instance started
+func (p *pauseLog) track(session models.Session) {
+	if !session.Paused() && len(session.Pauses) == 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sessions[session.ID] = &models.Session{ID: session.ID, PausedAt: session.PausedAt, Pauses: session.Pauses}
+}
+
+// pause records that the session paused at pausedAt; repeating it is a no-op
+func (p *pauseLog) pause(sessionID string, pausedAt time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	session := p.session(sessionID)
+	if session.PausedAt == nil {
+		session.PausedAt = &pausedAt
+	}
+}
+
+// resume records a finished pause of the session; repeating it is a no-op
+func (p *pauseLog) resume(sessionID string, pause models.WalkPause) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	session := p.session(sessionID)
+	session.PausedAt = nil
+	for _, known := range session.Pauses {
+		if known.PausedAt.Equal(pause.PausedAt) {
+			return
+		}
+	}
+	session.Pauses = append(session.Pauses, pause)
+}
+
+// session returns the logged pauses of a session, adding it when it has none.
+// Callers must hold p.mu.
+func (p *pauseLog) session(sessionID string) *models.Session {
+	session, ok := p.sessions[sessionID]
+	if ok {
+		return session
+	}
+	if len(p.sessions) >= maxPausedSessions {
+		// Evict an arbitrary session; its later points are no longer marked paused
+		for id := range p.sessions {
+			delete(p.sessions, id)
+			break
+		}
+	}
+	session = &models.Session{ID: sessionID}
+	p.sessions[sessionID] = session
+	return session
+}
+
+// pausedAt reports whether the session was paused at t
+func (p *pauseLog) pausedAt(sessionID string, t time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	session, ok := p.sessions[sessionID]
+	if !ok {
+		return false
+	}
+	if session.PausedAt != nil && !t.Before(*session.PausedAt) {
+		return true
+	}
+	for _, pause := range session.Pauses {
+		if !t.Before(pause.PausedAt) && t.Before(pause.ResumedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// forget drops a session's pauses once it has ended
+func (p *pauseLog) forget(sessionID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.sessions, sessionID)
+}
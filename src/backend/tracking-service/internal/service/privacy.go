@@ -0,0 +1,228 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"src/backend/tracking-service/internal/metrics"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// privacyCacheTTL is how long a walker's zones are reused before being read again. Changes made
+// on this instance apply at once; changes made on another instance apply within the TTL.
+const privacyCacheTTL = time.Minute
+
+// maxPrivacyCacheEntries bounds the number of walkers and sessions held in the privacy cache
+const maxPrivacyCacheEntries = 10000
+
+// ErrPrivacyZoneNotFound is returned when a walker has no such privacy zone
+var ErrPrivacyZoneNotFound = repository.ErrPrivacyZoneNotFound
+
+// cachedZones is a walker's privacy zones and when they were read
+type cachedZones struct {
+	zones    []models.PrivacyZone
+	loadedAt time.Time
+}
+
+// privacyCache holds walkers' zones and the walker of each session seen, since every point
+// received has to be checked against them
+var privacyCache = struct {
+	sync.Mutex
+	walkers  map[string]cachedZones
+	sessions map[string]string
+}{walkers: make(map[string]cachedZones), sessions: make(map[string]string)}
+
+// CreatePrivacyZone defines a zone in which walkerID's location is hidden from owners
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func CreatePrivacyZone(walkerID string, zone models.PrivacyZone) (*models.PrivacyZone, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate privacy zone ID: %w", err)
+	}
+
+	zone.ID = id
+	zone.WalkerID = walkerID
+	zone.CreatedAt = time.Now()
+	if err := zone.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid privacy zone: %w", err)
+	}
+
+	if err := repository.InsertPrivacyZone(zone); err != nil {
+		return nil, fmt.Errorf("failed to create privacy zone: %w", err)
+	}
+	forgetPrivacyZones(walkerID)
+
+	return &zone, nil
+}
+
+// ListPrivacyZones returns a walker's privacy zones
+func ListPrivacyZones(walkerID string) ([]models.PrivacyZone, error) {
+	zones, err := repository.FindPrivacyZonesByWalker(walkerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve privacy zones: %w", err)
+	}
+	return zones, nil
+}
+
+// DeletePrivacyZone removes one of a walker's privacy zones
+func DeletePrivacyZone(walkerID, id string) error {
+	if err := repository.DeletePrivacyZone(walkerID, id); err != nil {
+		return err
+	}
+	forgetPrivacyZones(walkerID)
+	return nil
+}
+
+// SubjectData is what the tracking-service holds that one person provided, for data subject
+// access requests
+type SubjectData struct {
+	SubjectID    string               `json:"subject_id"`
+	ExportedAt   time.Time            `json:"exported_at"`
+	Consents     []models.Consent     `json:"consents"`
+	PrivacyZones []models.PrivacyZone `json:"privacy_zones"`
+	ChatMessages []models.ChatMessage `json:"chat_messages"`
+}
+
+// ExportSubjectData collects a person's consent records, privacy zones and chat messages
+func ExportSubjectData(subjectID string) (*SubjectData, error) {
+	if subjectID == "" {
+		return nil, fmt.Errorf("invalid subject: subject ID is required")
+	}
+
+	consents, err := repository.FindConsents("", subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve consents: %w", err)
+	}
+	zones, err := repository.FindPrivacyZonesByWalker(subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve privacy zones: %w", err)
+	}
+	messages, err := repository.FindChatMessagesBySender(subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chat messages: %w", err)
+	}
+
+	data := &SubjectData{
+		SubjectID:    subjectID,
+		ExportedAt:   time.Now().UTC(),
+		Consents:     consents,
+		PrivacyZones: zones,
+		ChatMessages: messages,
+	}
+	if data.Consents == nil {
+		data.Consents = []models.Consent{}
+	}
+	if data.PrivacyZones == nil {
+		data.PrivacyZones = []models.PrivacyZone{}
+	}
+	if data.ChatMessages == nil {
+		data.ChatMessages = []models.ChatMessage{}
+	}
+	return data, nil
+}
+
+// isPrivate reports whether location lies in a privacy zone of its session's walker. When the
+// zones cannot be read the point is treated as private, so a database error never reveals a
+// walker's home. Points without a session have no walker and are never private.
+func isPrivate(location models.Location) bool {
+	if location.SessionID == "" {
+		return false
+	}
+
+	zones, err := sessionPrivacyZones(location.SessionID)
+	if errors.Is(err, ErrSessionNotFound) {
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to load privacy zones for session %s, hiding point: %v", location.SessionID, err)
+		return true
+	}
+
+	for i := range zones {
+		if zones[i].Contains(location) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskLocations removes the points that lie in their walker's privacy zones. It is applied to
+// every owner-visible path: live broadcast, history, routes and exports.
+func maskLocations(locations []models.Location) []models.Location {
+	visible := locations[:0:0]
+	for _, location := range locations {
+		if isPrivate(location) {
+			metrics.LocationsMasked.Inc()
+			continue
+		}
+		visible = append(visible, location)
+	}
+	return visible
+}
+
+// sessionPrivacyZones returns the zones of a session's walker, from the cache when fresh
+func sessionPrivacyZones(sessionID string) ([]models.PrivacyZone, error) {
+	walkerID, err := sessionWalker(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	privacyCache.Lock()
+	cached, ok := privacyCache.walkers[walkerID]
+	privacyCache.Unlock()
+	if ok && time.Since(cached.loadedAt) < privacyCacheTTL {
+		return cached.zones, nil
+	}
+
+	zones, err := repository.FindPrivacyZonesByWalker(walkerID)
+	if err != nil {
+		return nil, err
+	}
+
+	privacyCache.Lock()
+	if len(privacyCache.walkers) >= maxPrivacyCacheEntries {
+		privacyCache.walkers = make(map[string]cachedZones)
+	}
+	privacyCache.walkers[walkerID] = cachedZones{zones: zones, loadedAt: time.Now()}
+	privacyCache.Unlock()
+
+	return zones, nil
+}
+
+// sessionWalker returns the walker of a session, from the cache when seen before
+func sessionWalker(sessionID string) (string, error) {
+	privacyCache.Lock()
+	walkerID, ok := privacyCache.sessions[sessionID]
+	privacyCache.Unlock()
+	if ok {
+		return walkerID, nil
+	}
+
+	session, err := repository.FindSessionByID(sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	privacyCache.Lock()
+	if len(privacyCache.sessions) >= maxPrivacyCacheEntries {
+		privacyCache.sessions = make(map[string]string)
+	}
+	privacyCache.sessions[sessionID] = session.WalkerID
+	privacyCache.Unlock()
+
+	return session.WalkerID, nil
+}
+
+// forgetPrivacyZones drops a walker's cached zones after they change
+func forgetPrivacyZones(walkerID string) {
+	privacyCache.Lock()
+	defer privacyCache.Unlock()
+	delete(privacyCache.walkers, walkerID)
+}
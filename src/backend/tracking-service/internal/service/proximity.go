@@ -0,0 +1,202 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"src/backend/tracking-service/internal/geohash"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+const (
+	// cellPrecision is the geohash precision stored with points and positions, cells about
+	// 150m across: fine enough for proximity and heatmaps, coarse enough to store unsealed
+	cellPrecision = 7
+
+	// positionWriteInterval is how often a walker's position is rewritten while they stay in
+	// the same cell
+	positionWriteInterval = 30 * time.Second
+
+	// maxPositionEntries bounds the number of sessions whose last written position is remembered
+	maxPositionEntries = 10000
+
+	// defaultNearbyMaxAge is how recently a walker must have been seen to be found nearby
+	defaultNearbyMaxAge = 10 * time.Minute
+
+	// maxNearbyRadius is the widest radius, in meters, a proximity search may cover
+	maxNearbyRadius = 10000.0
+
+	// minHeatmapPrecision and maxHeatmapPrecision bound the heatmap cell size, from about
+	// 40km down to the stored 150m cells
+	minHeatmapPrecision = 4
+	maxHeatmapPrecision = cellPrecision
+
+	// maxHeatmapRange is the longest period one heatmap may cover
+	maxHeatmapRange = 31 * 24 * time.Hour
+
+	// maxHeatmapCells caps the cells returned by one heatmap, busiest first
+	maxHeatmapCells = 5000
+)
+
+// writtenPosition is the last position written for a session
+type writtenPosition struct {
+	cell      string
+	writtenAt time.Time
+}
+
+// lastPositions remembers each session's last written position, so a walker standing still
+// does not cause a write for every point
+var lastPositions = struct {
+	sync.Mutex
+	sessions map[string]writtenPosition
+}{sessions: make(map[string]writtenPosition)}
+
+// locationCell returns the cell stored with a point
+func locationCell(location models.Location) string {
+	return geohash.Encode(location.Latitude, location.Longitude, cellPrecision)
+}
+
+// recordWalkerPosition updates the position of the walker of a broadcast point when they have
+// moved to another cell or positionWriteInterval has passed. Failures are logged, never
+// returned, so they do not affect tracking.
+func recordWalkerPosition(location models.Location) {
+	now := time.Now()
+	lastPositions.Lock()
+	last, ok := lastPositions.sessions[location.SessionID]
+	lastPositions.Unlock()
+	if ok && last.cell == location.Cell && now.Sub(last.writtenAt) < positionWriteInterval {
+		return
+	}
+
+	walkerID, err := sessionWalker(location.SessionID)
+	if err != nil {
+		log.Printf("Failed to find walker of session %s to record position: %v", location.SessionID, err)
+		return
+	}
+
+	position := models.WalkerPosition{
+		WalkerID:  walkerID,
+		SessionID: location.SessionID,
+		Cell:      location.Cell,
+		UpdatedAt: location.Timestamp,
+	}
+	if err := repository.SaveWalkerPosition(position); err != nil {
+		log.Printf("Failed to record position of walker %s: %v", walkerID, err)
+		return
+	}
+
+	lastPositions.Lock()
+	if _, ok := lastPositions.sessions[location.SessionID]; !ok && len(lastPositions.sessions) >= maxPositionEntries {
+		lastPositions.sessions = make(map[string]writtenPosition)
+	}
+	lastPositions.sessions[location.SessionID] = writtenPosition{cell: location.Cell, writtenAt: now}
+	lastPositions.Unlock()
+}
+
+// forgetWalkerPosition drops a session's last written position once it has ended
+func forgetWalkerPosition(sessionID string) {
+	lastPositions.Lock()
+	defer lastPositions.Unlock()
+	delete(lastPositions.sessions, sessionID)
+}
+
+// NearbyWalkers finds the walkers seen within radiusMeters of a point during the last maxAge,
+// nearest first. Positions are known to the cell, so distances are accurate to about 100m.
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func NearbyWalkers(latitude, longitude, radiusMeters float64, maxAge time.Duration) ([]models.NearbyWalker, error) {
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, fmt.Errorf("invalid proximity query: coordinates are out of range")
+	}
+	if radiusMeters <= 0 || radiusMeters > maxNearbyRadius {
+		return nil, fmt.Errorf("invalid proximity query: radius must be between 0 and %.0f meters", maxNearbyRadius)
+	}
+	if maxAge <= 0 {
+		maxAge = defaultNearbyMaxAge
+	}
+
+	// The cell containing the point and its neighbours cover the whole radius
+	precision := geohash.PrecisionFor(latitude, radiusMeters, cellPrecision)
+	center := geohash.Encode(latitude, longitude, precision)
+	cells, err := geohash.Neighbors(center)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find nearby walkers: %w", err)
+	}
+	cells = append(cells, center)
+
+	positions, err := repository.FindWalkerPositions(cells, time.Now().Add(-maxAge))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find nearby walkers: %w", err)
+	}
+
+	nearby := make([]models.NearbyWalker, 0, len(positions))
+	for _, position := range positions {
+		box, err := geohash.Decode(position.Cell)
+		if err != nil {
+			log.Printf("Skipping walker %s with invalid cell %q", position.WalkerID, position.Cell)
+			continue
+		}
+		cellLat, cellLng := box.Center()
+		distance := models.DistanceMeters(latitude, longitude, cellLat, cellLng)
+		if distance > radiusMeters {
+			continue
+		}
+		nearby = append(nearby, models.NearbyWalker{WalkerPosition: position, DistanceMeters: distance})
+	}
+	sort.Slice(nearby, func(i, j int) bool { return nearby[i].DistanceMeters < nearby[j].DistanceMeters })
+	return nearby, nil
+}
+
+// ErrPositionNotFound is returned when a walker has not been seen during any walk
+var ErrPositionNotFound = repository.ErrPositionNotFound
+
+// LastWalkerPosition returns where a walker was last seen during a walk, to the centre of
+// their cell
+func LastWalkerPosition(walkerID string) (*models.LocatedWalker, error) {
+	position, err := repository.FindWalkerPosition(walkerID)
+	if err != nil {
+		return nil, err
+	}
+
+	box, err := geohash.Decode(position.Cell)
+	if err != nil {
+		return nil, fmt.Errorf("walker %s has an invalid cell %q: %w", walkerID, position.Cell, err)
+	}
+	located := &models.LocatedWalker{WalkerPosition: *position}
+	located.Latitude, located.Longitude = box.Center()
+	return located, nil
+}
+
+// LocationHeatmap counts the points recorded in [from, to) per cell of the given precision,
+// busiest cells first. Points inside privacy zones have no cell and are never counted.
+func LocationHeatmap(from, to time.Time, precision int) ([]models.HeatmapCell, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid heatmap query: from must be before to")
+	}
+	if to.Sub(from) > maxHeatmapRange {
+		return nil, fmt.Errorf("invalid heatmap query: at most %d days can be mapped", int(maxHeatmapRange/(24*time.Hour)))
+	}
+	if precision < minHeatmapPrecision || precision > maxHeatmapPrecision {
+		return nil, fmt.Errorf("invalid heatmap query: precision must be between %d and %d", minHeatmapPrecision, maxHeatmapPrecision)
+	}
+
+	cells, err := repository.CountLocationCells(from, to, precision, maxHeatmapCells)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build heatmap: %w", err)
+	}
+
+	for i := range cells {
+		box, err := geohash.Decode(cells[i].Cell)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build heatmap: %w", err)
+		}
+		cells[i].Latitude, cells[i].Longitude = box.Center()
+	}
+	return cells, nil
+}
@@ -0,0 +1,97 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"src/backend/shared/regions"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// maxTaggedSessions bounds the number of sessions remembered as already tagged with a region
+const maxTaggedSessions = 10000
+
+// serviceRegions locates points in the regions defined in the booking-service; nil leaves
+// walks and incidents untagged
+var serviceRegions *regions.RemoteDirectory
+
+// RunRegionRefresh keeps the service regions current until ctx is cancelled
+func RunRegionRefresh(ctx context.Context) {
+	if serviceRegions == nil {
+		<-ctx.Done()
+		return
+	}
+	serviceRegions.Run(ctx)
+}
+
+// sessionSet remembers the sessions this instance has tagged with a region, so later points
+// of the walk skip the lookup and the write
+type sessionSet struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+// taggedSessions is the set of sessions tagged by tagSessionRegion
+var taggedSessions = &sessionSet{ids: make(map[string]struct{})}
+
+// has reports whether the session is in the set
+func (s *sessionSet) has(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.ids[id]
+	return ok
+}
+
+// add puts the session in the set
+func (s *sessionSet) add(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ids[id]; !ok && len(s.ids) >= maxTaggedSessions {
+		// Evict an arbitrary session; tagging it again is a harmless no-op
+		for evicted := range s.ids {
+			delete(s.ids, evicted)
+			break
+		}
+	}
+	s.ids[id] = struct{}{}
+}
+
+// forget removes the session from the set once it has ended
+func (s *sessionSet) forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.ids, id)
+}
+
+// tagSessionRegion tags the point's walk session with the region the point falls in, unless
+// it already has one. Points outside every region leave the session for a later point to tag.
+func tagSessionRegion(location models.Location) {
+	if serviceRegions == nil || taggedSessions.has(location.SessionID) {
+		return
+	}
+	region := serviceRegions.Locate(location.Latitude, location.Longitude)
+	if region == "" {
+		return
+	}
+	if err := repository.TagSessionRegion(location.SessionID, region); err != nil {
+		log.Printf("Failed to tag session %s with region %s: %v", location.SessionID, region, err)
+		return
+	}
+	taggedSessions.add(location.SessionID)
+}
+
+// incidentRegion returns the region an incident happened in: the one containing its location,
+// or fallback, usually its walk's region, when the location is unknown or outside every region
+func incidentRegion(location *models.Location, fallback string) string {
+	if location != nil && serviceRegions != nil {
+		if region := serviceRegions.Locate(location.Latitude, location.Longitude); region != "" {
+			return region
+		}
+	}
+	return fallback
+}
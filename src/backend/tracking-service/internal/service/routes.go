@@ -0,0 +1,104 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"src/backend/tracking-service/internal/mapmatching"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// maxCachedRoutes bounds the number of snapped routes held in memory
+const maxCachedRoutes = 1000
+
+// routeMatcher snaps walk routes when a map-matching provider is configured; nil disables snapping
+var routeMatcher mapmatching.Provider
+
+// cachedRoute is a snapped route and the number of raw points it was computed from
+type cachedRoute struct {
+	pointCount int
+	route      []models.Location
+}
+
+// routeCache holds snapped routes per session, keyed by session ID for the masked route and by
+// evidenceRouteKey for the full one. A route is reused while the session's point count is
+// unchanged, so finished walks are matched only once.
+var routeCache = struct {
+	sync.Mutex
+	routes map[string]cachedRoute
+}{routes: make(map[string]cachedRoute)}
+
+// evidenceRouteKey is the cache key of a session's route with its privacy zones, which only
+// walk evidence measures
+func evidenceRouteKey(sessionID string) string {
+	return sessionID + "/evidence"
+}
+
+// GetSessionRoute returns a walk session's route in time order, snapped to the street/path
+// network when snapped is true and a map-matching provider is configured. Raw points are
+// returned unchanged if matching fails, so summaries and exports always have a route.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func GetSessionRoute(sessionID string, snapped bool) ([]models.Location, error) {
+	if sessionID == "" {
+		return nil, ErrSessionRequired
+	}
+
+	points, err := repository.FindLocationsBySession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve session route: %w", err)
+	}
+
+	// Hide privacy zones before matching, so a snapped route cannot reveal them either
+	points = maskLocations(points)
+
+	if !snapped {
+		return points, nil
+	}
+	return snapRoute(sessionID, points), nil
+}
+
+// snapRoute snaps a route to the street/path network, reusing the snapped route cached under
+// key while the route has as many points as when it was matched. The route is returned
+// unchanged when no provider is configured or matching fails.
+func snapRoute(key string, points []models.Location) []models.Location {
+	if routeMatcher == nil || len(points) < 2 {
+		return points
+	}
+
+	routeCache.Lock()
+	cached, ok := routeCache.routes[key]
+	routeCache.Unlock()
+	if ok && cached.pointCount == len(points) {
+		return cached.route
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	route, err := routeMatcher.Match(ctx, points)
+	if err != nil {
+		log.Printf("Map matching failed for %s, using raw route: %v", key, err)
+		return points
+	}
+
+	routeCache.Lock()
+	if len(routeCache.routes) >= maxCachedRoutes {
+		// Evict an arbitrary entry; active sessions will be recomputed on demand
+		for id := range routeCache.routes {
+			delete(routeCache.routes, id)
+			break
+		}
+	}
+	routeCache.routes[key] = cachedRoute{pointCount: len(points), route: route}
+	routeCache.Unlock()
+
+	return route
+}
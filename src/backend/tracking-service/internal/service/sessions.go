@@ -0,0 +1,148 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"src/backend/shared/clock"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// ErrSessionNotFound is returned when a walk session does not exist or has already ended
+var ErrSessionNotFound = repository.ErrSessionNotFound
+
+// StartSession begins a walk session for a booking and starts monitoring it on every instance
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func StartSession(bookingID, walkerID, ownerID string) (*models.Session, error) {
+	return StartGroupSession(walkerID, []models.SessionBooking{{BookingID: bookingID, OwnerID: ownerID}})
+}
+
+// StartGroupSession begins one walk session in which the walker walks the dogs of several
+// bookings together. Every booking's owner follows the same live walk, while events and
+// evidence are kept per booking.
+func StartGroupSession(walkerID string, bookings []models.SessionBooking) (*models.Session, error) {
+	if len(bookings) == 0 {
+		return nil, fmt.Errorf("invalid session data: at least one booking is required")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	session := models.NewGroupSession(id, walkerID, bookings)
+	if err := session.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid session data: %w", err)
+	}
+
+	// Tracking only starts once both people on every booking have agreed to it
+	for _, booking := range bookings {
+		if err := requireConsent(booking.BookingID, walkerID, booking.OwnerID); err != nil {
+			if len(bookings) > 1 {
+				return nil, fmt.Errorf("booking %s: %w", booking.BookingID, err)
+			}
+			return nil, err
+		}
+	}
+
+	if err := repository.InsertSession(*session); err != nil {
+		return nil, fmt.Errorf("failed to store session: %w", err)
+	}
+
+	sessionJSON, err := json.Marshal(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	Hub.Publish(session.ID, websocket.KindSessionStarted, string(sessionJSON))
+
+	if session.IsGroup() {
+		log.Printf("Group walk session %s started for %d bookings", session.ID, len(session.Bookings))
+	} else {
+		log.Printf("Walk session %s started for booking %s", session.ID, session.BookingID)
+	}
+	return session, nil
+}
+
+// GetSession retrieves a walk session by ID
+func GetSession(id string) (*models.Session, error) {
+	if id == "" {
+		return nil, ErrSessionRequired
+	}
+
+	session, err := repository.FindSessionByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve session: %w", err)
+	}
+	return session, nil
+}
+
+// EndSession finishes an active walk session and notifies its subscribers
+func EndSession(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrSessionRequired
+	}
+
+	if err := repository.EndSession(id, clock.FromContext(ctx).Now()); err != nil {
+		return fmt.Errorf("failed to end session: %w", err)
+	}
+
+	Hub.Publish(id, websocket.KindSessionEnded, "")
+	broadcastOrder.forget(id)
+	smoother.forget(id)
+	taggedSessions.forget(id)
+	forgetWalkerPosition(id)
+
+	eventJSON, err := json.Marshal(sessionEvent{Event: EventWalkEnded, SessionID: id})
+	if err != nil {
+		return fmt.Errorf("failed to marshal session event: %w", err)
+	}
+	Hub.Publish(id, websocket.KindEvent, string(eventJSON))
+
+	log.Printf("Walk session %s ended", id)
+	return nil
+}
+
+// Heartbeat records that the walker's app is alive even when it has no new location to report
+func Heartbeat(id string) error {
+	if id == "" {
+		return ErrSessionRequired
+	}
+
+	Hub.Publish(id, websocket.KindHeartbeat, "")
+	return nil
+}
+
+// restoreSessionMonitors starts monitoring every session that was active before this instance started
+func restoreSessionMonitors() {
+	sessions, err := repository.FindActiveSessions()
+	if err != nil {
+		log.Printf("Failed to restore session monitors: %v", err)
+		return
+	}
+
+	for _, session := range sessions {
+		monitor.watch(session)
+		fleet.track(session)
+		walkPauses.track(session)
+	}
+	log.Printf("Monitoring %d active walk sessions", len(sessions))
+}
+
+// newID generates a random 128-bit identifier
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,387 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+const (
+	// maxSimulations bounds the simulated walks running at once on an instance
+	maxSimulations = 20
+
+	// simulationBlockMeters is the side of a block of the simulated street grid
+	simulationBlockMeters = 80.0
+
+	// simulationRadiusBlocks bounds how far from the start, in blocks, waypoints are placed
+	simulationRadiusBlocks = 6
+
+	// metersPerDegreeLatitude converts north-south distances to degrees
+	metersPerDegreeLatitude = 111320.0
+
+	// simulationTermsVersion is the terms version synthetic participants consent to when no
+	// current version is configured
+	simulationTermsVersion = "simulation"
+)
+
+var (
+	// ErrSimulationUnavailable is returned when walk simulation is not running on this instance
+	ErrSimulationUnavailable = errors.New("walk simulation is not running")
+
+	// ErrSimulationNotFound is returned when stopping a simulation that is not running
+	ErrSimulationNotFound = errors.New("simulation not found")
+
+	// ErrTooManySimulations is returned when maxSimulations are already running
+	ErrTooManySimulations = errors.New("too many simulations running")
+)
+
+// SimulationSettings shapes a simulated walk; zero values take their defaults
+type SimulationSettings struct {
+	// Latitude and Longitude are where the walk starts and ends
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+
+	// SpeedMps is the walker's average pace, in meters per second
+	SpeedMps float64 `json:"speed_mps"`
+
+	// JitterMeters is the standard deviation of the GPS noise added to each point
+	JitterMeters float64 `json:"jitter_meters"`
+
+	// IntervalSeconds is how often the walker's app reports a point
+	IntervalSeconds float64 `json:"interval_seconds"`
+
+	// DurationSeconds bounds the walk; it ends sooner once the route is walked
+	DurationSeconds float64 `json:"duration_seconds"`
+
+	// Waypoints is how many intersections the route visits before heading back
+	Waypoints int `json:"waypoints"`
+}
+
+// withDefaults fills in the settings left unset
+func (s SimulationSettings) withDefaults() SimulationSettings {
+	if s.SpeedMps == 0 {
+		s.SpeedMps = 1.4
+	}
+	if s.IntervalSeconds == 0 {
+		s.IntervalSeconds = 2
+	}
+	if s.DurationSeconds == 0 {
+		s.DurationSeconds = 30 * 60
+	}
+	if s.Waypoints == 0 {
+		s.Waypoints = 6
+	}
+	return s
+}
+
+// validate checks that the settings describe a walk
+func (s SimulationSettings) validate() error {
+	switch {
+	case s.Latitude < -85 || s.Latitude > 85 || s.Longitude < -180 || s.Longitude > 180:
+		return errors.New("latitude must be within ±85 and longitude within ±180")
+	case s.Latitude == 0 && s.Longitude == 0:
+		return errors.New("latitude and longitude are required")
+	case s.SpeedMps < 0.2 || s.SpeedMps > 5:
+		return errors.New("speed_mps must be between 0.2 and 5")
+	case s.JitterMeters < 0 || s.JitterMeters > 50:
+		return errors.New("jitter_meters must be between 0 and 50")
+	case s.IntervalSeconds < 0.1 || s.IntervalSeconds > 60:
+		return errors.New("interval_seconds must be between 0.1 and 60")
+	case s.DurationSeconds < 0 || s.DurationSeconds > 4*60*60:
+		return errors.New("duration_seconds must be at most 4 hours")
+	case s.Waypoints < 1 || s.Waypoints > 20:
+		return errors.New("waypoints must be between 1 and 20")
+	}
+	return nil
+}
+
+// Simulation is a synthetic walk fed through the normal ingestion path, for building clients
+// against live-looking data
+type Simulation struct {
+	ID        string             `json:"id"`
+	SessionID string             `json:"session_id"`
+	BookingID string             `json:"booking_id"`
+	WalkerID  string             `json:"walker_id"`
+	OwnerID   string             `json:"owner_id"`
+	Settings  SimulationSettings `json:"settings"`
+	StartedAt time.Time          `json:"started_at"`
+
+	// RouteMeters is the length of the route the walker follows
+	RouteMeters float64 `json:"route_meters"`
+}
+
+// simulator runs the simulated walks of this instance; ctx is set while RunSimulations runs
+var simulator = struct {
+	mu    sync.Mutex
+	ctx   context.Context
+	wg    sync.WaitGroup
+	walks map[string]*simulatedWalk
+}{walks: make(map[string]*simulatedWalk)}
+
+// simulatedWalk is a running simulation and the route it follows
+type simulatedWalk struct {
+	Simulation
+	route  []point
+	rng    *rand.Rand
+	cancel context.CancelFunc
+}
+
+// point is a position in meters east and north of a simulation's start
+type point struct {
+	x, y float64
+}
+
+// RunSimulations lets simulated walks run on this instance until ctx is cancelled, then ends
+// them. Only started in development.
+func RunSimulations(ctx context.Context) {
+	simulator.mu.Lock()
+	simulator.ctx = ctx
+	simulator.mu.Unlock()
+
+	<-ctx.Done()
+
+	simulator.mu.Lock()
+	simulator.ctx = nil
+	simulator.mu.Unlock()
+	simulator.wg.Wait()
+}
+
+// StartSimulation starts a simulated walk: it records consent for synthetic participants,
+// starts a walk session for them and reports points along a route through a street grid
+// around the start, as the walker's app would
+func StartSimulation(settings SimulationSettings) (*Simulation, error) {
+	settings = settings.withDefaults()
+	if err := settings.validate(); err != nil {
+		return nil, fmt.Errorf("invalid simulation: %w", err)
+	}
+
+	simulator.mu.Lock()
+	available, running := simulator.ctx != nil, len(simulator.walks)
+	simulator.mu.Unlock()
+	if !available {
+		return nil, ErrSimulationUnavailable
+	}
+	if running >= maxSimulations {
+		return nil, ErrTooManySimulations
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate simulation ID: %w", err)
+	}
+	walk := &simulatedWalk{
+		Simulation: Simulation{
+			ID:        id,
+			BookingID: "sim-booking-" + id,
+			WalkerID:  "sim-walker-" + id,
+			OwnerID:   "sim-owner-" + id,
+			Settings:  settings,
+		},
+		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	walk.route = simulatedRoute(walk.rng, settings.Waypoints)
+	walk.RouteMeters = routeLength(walk.route)
+
+	terms := consentTermsVersion
+	if terms == "" {
+		terms = simulationTermsVersion
+	}
+	for _, consent := range []models.Consent{
+		{SubjectID: walk.WalkerID, Role: models.ConsentRoleWalker, TermsVersion: terms},
+		{SubjectID: walk.OwnerID, Role: models.ConsentRoleOwner, TermsVersion: terms},
+	} {
+		if _, err := RecordConsent(walk.BookingID, consent); err != nil {
+			return nil, err
+		}
+	}
+	session, err := StartSession(walk.BookingID, walk.WalkerID, walk.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	walk.SessionID = session.ID
+	walk.StartedAt = session.StartedAt
+
+	simulator.mu.Lock()
+	if simulator.ctx == nil {
+		// The instance began shutting down while the walk was being set up
+		simulator.mu.Unlock()
+		EndSession(context.Background(), walk.SessionID)
+		return nil, ErrSimulationUnavailable
+	}
+	walkCtx, cancel := context.WithCancel(simulator.ctx)
+	walk.cancel = cancel
+	simulator.walks[id] = walk
+	simulator.wg.Add(1)
+	simulator.mu.Unlock()
+
+	go walk.run(walkCtx)
+	log.Printf("Simulated walk %s started as session %s (%.0fm route)", id, walk.SessionID, walk.RouteMeters)
+	return &walk.Simulation, nil
+}
+
+// ListSimulations returns the simulated walks running on this instance, oldest first
+func ListSimulations() []Simulation {
+	simulator.mu.Lock()
+	defer simulator.mu.Unlock()
+
+	simulations := make([]Simulation, 0, len(simulator.walks))
+	for _, walk := range simulator.walks {
+		simulations = append(simulations, walk.Simulation)
+	}
+	sort.Slice(simulations, func(i, j int) bool {
+		return simulations[i].StartedAt.Before(simulations[j].StartedAt)
+	})
+	return simulations
+}
+
+// StopSimulation ends a simulated walk and its session
+func StopSimulation(id string) error {
+	simulator.mu.Lock()
+	walk, ok := simulator.walks[id]
+	simulator.mu.Unlock()
+	if !ok {
+		return ErrSimulationNotFound
+	}
+
+	walk.cancel()
+	return nil
+}
+
+// run reports the walker's position every interval until the route is walked, the duration
+// has passed or the simulation is stopped, then ends the session
+func (w *simulatedWalk) run(ctx context.Context) {
+	defer simulator.wg.Done()
+	defer func() {
+		simulator.mu.Lock()
+		delete(simulator.walks, w.ID)
+		simulator.mu.Unlock()
+
+		if err := EndSession(context.Background(), w.SessionID); err != nil {
+			log.Printf("Failed to end simulated walk %s: %v", w.ID, err)
+		}
+		log.Printf("Simulated walk %s ended", w.ID)
+	}()
+
+	interval := time.Duration(w.Settings.IntervalSeconds * float64(time.Second))
+	deadline := time.NewTimer(time.Duration(w.Settings.DurationSeconds * float64(time.Second)))
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var walked float64
+	var seq int64
+	battery := 60 + w.rng.Float64()*40
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			return
+		case now := <-ticker.C:
+			// The pace varies, and now and then the dog stops to sniff
+			speed := w.Settings.SpeedMps * (1 + 0.15*w.rng.NormFloat64())
+			if w.rng.Float64() < 0.1 || speed < 0 {
+				speed = 0
+			}
+			walked += speed * interval.Seconds()
+			if walked >= w.RouteMeters {
+				return
+			}
+
+			seq++
+			battery = math.Max(5, battery-interval.Seconds()/300)
+			if err := TrackLocation(w.location(walked, speed, battery, seq, now)); err != nil {
+				log.Printf("Simulated walk %s failed to report a point: %v", w.ID, err)
+			}
+		}
+	}
+}
+
+// location is the point the walker's app reports after walking distance along the route
+func (w *simulatedWalk) location(distance, speed, battery float64, seq int64, now time.Time) models.Location {
+	position, heading := along(w.route, distance)
+
+	jitter := w.Settings.JitterMeters
+	position.x += w.rng.NormFloat64() * jitter
+	position.y += w.rng.NormFloat64() * jitter
+	accuracy := math.Max(3, jitter*(1.5+w.rng.Float64()))
+
+	location := models.NewLocation(
+		w.Settings.Latitude+position.y/metersPerDegreeLatitude,
+		w.Settings.Longitude+position.x/(metersPerDegreeLatitude*math.Cos(w.Settings.Latitude*math.Pi/180)),
+		now.UTC(),
+	)
+	location.SessionID = w.SessionID
+	location.AccuracyMeters = &accuracy
+	location.Speed = &speed
+	location.Heading = &heading
+	location.BatteryPercent = &battery
+	location.DeviceSeq = &seq
+	return *location
+}
+
+// simulatedRoute plans a loop from the origin through waypoints random intersections of a
+// street grid and back, turning only at intersections
+func simulatedRoute(rng *rand.Rand, waypoints int) []point {
+	route := []point{{}}
+	current := point{}
+	for i := 0; i <= waypoints; i++ {
+		next := point{}
+		for i < waypoints && next == current {
+			next = point{
+				x: float64(rng.Intn(2*simulationRadiusBlocks+1)-simulationRadiusBlocks) * simulationBlockMeters,
+				y: float64(rng.Intn(2*simulationRadiusBlocks+1)-simulationRadiusBlocks) * simulationBlockMeters,
+			}
+		}
+
+		// Walk along one street, then turn onto the cross street
+		corner := point{x: next.x, y: current.y}
+		if rng.Intn(2) == 0 {
+			corner = point{x: current.x, y: next.y}
+		}
+		if corner != current && corner != next {
+			route = append(route, corner)
+		}
+		if next != current {
+			route = append(route, next)
+		}
+		current = next
+	}
+	return route
+}
+
+// routeLength returns the length of route in meters
+func routeLength(route []point) float64 {
+	var length float64
+	for i := 1; i < len(route); i++ {
+		length += math.Hypot(route[i].x-route[i-1].x, route[i].y-route[i-1].y)
+	}
+	return length
+}
+
+// along returns the position distance meters along route and the heading there, in degrees
+// from north
+func along(route []point, distance float64) (point, float64) {
+	for i := 1; i < len(route); i++ {
+		from, to := route[i-1], route[i]
+		leg := math.Hypot(to.x-from.x, to.y-from.y)
+		heading := math.Mod(math.Atan2(to.x-from.x, to.y-from.y)*180/math.Pi+360, 360)
+		if distance <= leg {
+			f := distance / leg
+			return point{x: from.x + f*(to.x-from.x), y: from.y + f*(to.y-from.y)}, heading
+		}
+		distance -= leg
+	}
+	return route[len(route)-1], 0
+}
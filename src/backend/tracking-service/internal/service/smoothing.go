@@ -0,0 +1,109 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"math"
+	"sync"
+
+	"src/backend/tracking-service/internal/models"
+)
+
+const (
+	// maxSmoothedSessions bounds the number of sessions whose filter state is kept
+	maxSmoothedSessions = 10000
+
+	// assumedAccuracyMeters stands in for the accuracy of points that do not report one
+	assumedAccuracyMeters = 10.0
+
+	// minAccuracyMeters keeps a point claiming perfect accuracy from pinning the filter to it
+	minAccuracyMeters = 1.0
+)
+
+// positionFilter smooths the positions broadcast for each session with a one-dimensional
+// Kalman filter over latitude and longitude. Each point is weighted by its reported accuracy,
+// so a poor fix moves the broadcast position only a little, while the filter's uncertainty
+// grows with the time since the last point at the walker's expected speed, so a walker who
+// really moves is followed closely. Only live positions are smoothed; stored points stay raw.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type positionFilter struct {
+	// speed is how fast, in meters per second, the walker is expected to move between points
+	speed float64
+
+	mu       sync.Mutex
+	sessions map[string]*filterState
+}
+
+// filterState is a session's smoothed position and its variance in square meters
+type filterState struct {
+	location models.Location
+	variance float64
+}
+
+// smoother smooths broadcast positions when enabled; nil broadcasts them as reported
+var smoother *positionFilter
+
+// newPositionFilter creates a filter for walkers expected to move at speed meters per second
+func newPositionFilter(speed float64) *positionFilter {
+	return &positionFilter{speed: speed, sessions: make(map[string]*filterState)}
+}
+
+// apply returns location with its coordinates replaced by the session's smoothed position.
+// Points must be applied in time order, as broadcast points are. A nil filter returns
+// location unchanged.
+func (f *positionFilter) apply(location models.Location) models.Location {
+	if f == nil || location.SessionID == "" {
+		return location
+	}
+
+	accuracy := assumedAccuracyMeters
+	if location.AccuracyMeters != nil {
+		accuracy = math.Max(*location.AccuracyMeters, minAccuracyMeters)
+	}
+	measurement := accuracy * accuracy
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state, ok := f.sessions[location.SessionID]
+	if !ok {
+		if len(f.sessions) >= maxSmoothedSessions {
+			// Evict an arbitrary session; it restarts from its next point
+			for id := range f.sessions {
+				delete(f.sessions, id)
+				break
+			}
+		}
+		f.sessions[location.SessionID] = &filterState{location: location, variance: measurement}
+		location.Smoothed = true
+		return location
+	}
+
+	// The walker may have moved since the last point, so the smoothed position is less certain
+	if elapsed := location.Timestamp.Sub(state.location.Timestamp).Seconds(); elapsed > 0 {
+		state.variance += elapsed * f.speed * f.speed
+	}
+
+	gain := state.variance / (state.variance + measurement)
+	state.location.Latitude += gain * (location.Latitude - state.location.Latitude)
+	state.location.Longitude += gain * (location.Longitude - state.location.Longitude)
+	state.location.Timestamp = location.Timestamp
+	state.variance *= 1 - gain
+
+	location.Latitude = state.location.Latitude
+	location.Longitude = state.location.Longitude
+	location.Smoothed = true
+	return location
+}
+
+// forget drops a session's filter state once it has ended
+func (f *positionFilter) forget(sessionID string) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.sessions, sessionID)
+}
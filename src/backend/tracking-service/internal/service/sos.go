@@ -0,0 +1,164 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/notifier"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// EventSOS is the session event pushed to subscribers when the walker raises an emergency
+const EventSOS = "sos"
+
+// alerter delivers emergency alerts to the on-call admin channel
+var alerter notifier.Alerter = notifier.LogAlerter{}
+
+// sosEvent is the payload broadcast to a session's subscribers for an SOS
+type sosEvent struct {
+	Event      string           `json:"event"`
+	SessionID  string           `json:"session_id"`
+	IncidentID string           `json:"incident_id"`
+	Message    string           `json:"message,omitempty"`
+	Location   *models.Location `json:"location,omitempty"`
+}
+
+// RaiseSOS records an emergency raised by the walker and alerts everyone who needs to know.
+// The event is pushed to subscribers ahead of queued location traffic, and the owner and the
+// on-call channel are notified without waiting for the incident to be stored. When location
+// is nil the session's last known location is used.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func RaiseSOS(sessionID, message string, location *models.Location) (*models.Incident, error) {
+	session, err := GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if location == nil {
+		location, err = repository.FindLatestLocation(sessionID)
+		if err != nil {
+			// An SOS must go out even when the last position cannot be looked up
+			log.Printf("Failed to look up last location for SOS on session %s: %v", sessionID, err)
+		}
+	} else {
+		location.SessionID = sessionID
+		if location.Timestamp.IsZero() {
+			location.Timestamp = time.Now()
+		}
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate incident ID: %w", err)
+	}
+
+	now := time.Now()
+	incident := models.Incident{
+		ID:         id,
+		Type:       models.IncidentTypeSOS,
+		Status:     models.IncidentStatusOpen,
+		SessionID:  session.ID,
+		BookingID:  session.BookingID,
+		WalkerID:   session.WalkerID,
+		OwnerID:    session.OwnerID,
+		ReportedBy: session.WalkerID,
+		Message:    message,
+		Location:   location,
+		Region:     incidentRegion(location, session.Region),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := incident.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid incident data: %w", err)
+	}
+
+	eventJSON, err := json.Marshal(sosEvent{
+		Event:      EventSOS,
+		SessionID:  session.ID,
+		IncidentID: incident.ID,
+		Message:    message,
+		Location:   location,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SOS event: %w", err)
+	}
+	Hub.Publish(session.ID, websocket.KindSOS, string(eventJSON))
+
+	go notifySOS(incident, session.AllBookings())
+
+	if err := repository.InsertIncident(incident); err != nil {
+		return nil, fmt.Errorf("failed to record incident: %w", err)
+	}
+
+	log.Printf("SOS raised on session %s (incident %s)", session.ID, incident.ID)
+	return &incident, nil
+}
+
+// notifySOS alerts the owners and the on-call admin channel about an SOS incident. Each owner
+// of a group walk is told about their own booking; the incident is recorded against the first.
+func notifySOS(incident models.Incident, bookings []models.SessionBooking) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	data := map[string]string{
+		"event":       EventSOS,
+		"incident_id": incident.ID,
+		"session_id":  incident.SessionID,
+		"booking_id":  incident.BookingID,
+	}
+	if incident.Location != nil {
+		data["latitude"] = fmt.Sprintf("%f", incident.Location.Latitude)
+		data["longitude"] = fmt.Sprintf("%f", incident.Location.Longitude)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, booking := range bookings {
+			ownerData := make(map[string]string, len(data))
+			for k, v := range data {
+				ownerData[k] = v
+			}
+			ownerData["booking_id"] = booking.BookingID
+
+			err := owners.Notify(ctx, booking.OwnerID, notifier.Notification{
+				Subject:  "Emergency on your dog's walk",
+				Body:     "Your walker has raised an emergency. Our team has been alerted and will be in touch.",
+				Priority: "high",
+				Data:     ownerData,
+			})
+			if err != nil {
+				log.Printf("Failed to notify owner of SOS incident %s: %v", incident.ID, err)
+			}
+		}
+	}()
+
+	booked := "booking " + incident.BookingID
+	if len(bookings) > 1 {
+		ids := make([]string, len(bookings))
+		for i, booking := range bookings {
+			ids[i] = booking.BookingID
+		}
+		booked = "group walk of bookings " + strings.Join(ids, ", ")
+	}
+	err := alerter.Alert(ctx, notifier.Alert{
+		Title:  "SOS raised during walk",
+		Text:   fmt.Sprintf("Walker %s raised an SOS on %s: %s", incident.WalkerID, booked, incident.Message),
+		Fields: data,
+	})
+	if err != nil {
+		log.Printf("Failed to alert on-call channel of SOS incident %s: %v", incident.ID, err)
+	}
+
+	<-done
+}
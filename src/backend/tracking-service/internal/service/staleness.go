@@ -0,0 +1,257 @@
+// Package service implements the core business logic for the tracking-service
+// Version: 1.0.0
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"src/backend/shared/clock"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/notifier"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// Session event names pushed to subscribers
+const (
+	EventTrackingStale   = "tracking_stale"
+	EventTrackingResumed = "tracking_resumed"
+	EventWalkEnded       = "walk_ended"
+	EventWalkPhoto       = "walk_photo"
+	EventWalkPaused      = "walk_paused"
+	EventWalkResumed     = "walk_resumed"
+)
+
+// sessionEvent is the payload of session events broadcast to subscribers
+type sessionEvent struct {
+	Event      string     `json:"event"`
+	SessionID  string     `json:"session_id"`
+	LastSeenAt *time.Time `json:"last_seen_at,omitempty"`
+	StaleAfter float64    `json:"stale_after_seconds,omitempty"`
+	PhotoURL   string     `json:"photo_url,omitempty"`
+	PausedAt   *time.Time `json:"paused_at,omitempty"`
+	ResumedAt  *time.Time `json:"resumed_at,omitempty"`
+	BookingIDs []string   `json:"booking_ids,omitempty"`
+}
+
+// stalenessMonitor runs one goroutine per active walk session and emits a
+// tracking_stale event when the walker stops reporting for longer than threshold.
+// Every instance observes every session's traffic through the hub, so each runs
+// the same monitors; a hub claim ensures each event is emitted only once.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type stalenessMonitor struct {
+	threshold time.Duration
+	notifier  notifier.Notifier
+	clock     clock.Clock
+
+	mu      sync.Mutex
+	watches map[string]*sessionWatch
+
+	// running counts the session goroutines so stop can wait for them
+	running sync.WaitGroup
+}
+
+// sessionWatch is the state of a single monitored session
+type sessionWatch struct {
+	session  models.Session
+	activity chan time.Time
+	stop     chan struct{}
+}
+
+// newStalenessMonitor creates a monitor with the given staleness threshold, timing sessions by c
+func newStalenessMonitor(threshold time.Duration, n notifier.Notifier, c clock.Clock) *stalenessMonitor {
+	return &stalenessMonitor{
+		threshold: threshold,
+		notifier:  n,
+		clock:     c,
+		watches:   make(map[string]*sessionWatch),
+	}
+}
+
+// observe feeds hub traffic into the monitor; it runs on the hub loop and must not block
+func (m *stalenessMonitor) observe(message websocket.Message) {
+	switch message.Kind {
+	case websocket.KindLocation, websocket.KindHeartbeat:
+		m.touch(message.Topic)
+
+	case websocket.KindSessionStarted:
+		var session models.Session
+		if err := json.Unmarshal([]byte(message.Data), &session); err != nil {
+			log.Printf("Failed to decode started session: %v", err)
+			return
+		}
+		m.watch(session)
+
+	case websocket.KindSessionEnded:
+		m.unwatch(message.Topic)
+	}
+}
+
+// watch starts monitoring an active session
+func (m *stalenessMonitor) watch(session models.Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.watches[session.ID]; ok {
+		return
+	}
+
+	w := &sessionWatch{
+		session:  session,
+		activity: make(chan time.Time, 1),
+		stop:     make(chan struct{}),
+	}
+	m.watches[session.ID] = w
+	m.running.Add(1)
+	go m.run(w)
+}
+
+// unwatch stops monitoring a session
+func (m *stalenessMonitor) unwatch(sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if w, ok := m.watches[sessionID]; ok {
+		close(w.stop)
+		delete(m.watches, sessionID)
+	}
+}
+
+// stop ends every session's goroutine and waits for them to return until ctx is done. Sessions
+// still active are watched again by the instance that restarts with them.
+func (m *stalenessMonitor) stop(ctx context.Context) error {
+	m.mu.Lock()
+	for sessionID, w := range m.watches {
+		close(w.stop)
+		delete(m.watches, sessionID)
+	}
+	m.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		m.running.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// touch records activity for a session
+func (m *stalenessMonitor) touch(sessionID string) {
+	m.mu.Lock()
+	w, ok := m.watches[sessionID]
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case w.activity <- m.clock.Now():
+	default:
+		// An activity signal is already pending; the watch will reset its timer anyway
+	}
+}
+
+// run is the per-session monitor goroutine
+func (m *stalenessMonitor) run(w *sessionWatch) {
+	defer m.running.Done()
+
+	timer := m.clock.NewTimer(m.threshold)
+	defer timer.Stop()
+
+	lastSeen := m.clock.Now()
+	stale := false
+
+	for {
+		select {
+		case at := <-w.activity:
+			lastSeen = at
+			if stale {
+				stale = false
+				m.emit(w.session, EventTrackingResumed, lastSeen)
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C():
+				default:
+				}
+			}
+			timer.Reset(m.threshold)
+
+		case <-timer.C():
+			stale = true
+			if m.emit(w.session, EventTrackingStale, lastSeen) {
+				m.notifyOwner(w.session, lastSeen)
+			}
+
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// emit broadcasts a session event if this instance wins the claim for it
+func (m *stalenessMonitor) emit(session models.Session, event string, lastSeen time.Time) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Every instance fires within moments of the others; half the threshold is
+	// long enough to dedupe them and short enough to expire before the next episode
+	claimed, err := Hub.Claim(ctx, fmt.Sprintf("%s:%s", event, session.ID), m.threshold/2)
+	if err != nil {
+		log.Printf("Failed to claim %s event for session %s: %v", event, session.ID, err)
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	payload, err := json.Marshal(sessionEvent{
+		Event:      event,
+		SessionID:  session.ID,
+		LastSeenAt: &lastSeen,
+		StaleAfter: m.threshold.Seconds(),
+	})
+	if err != nil {
+		log.Printf("Failed to marshal %s event: %v", event, err)
+		return false
+	}
+
+	Hub.Publish(session.ID, websocket.KindEvent, string(payload))
+	log.Printf("Session %s: %s (last seen %v)", session.ID, event, lastSeen)
+	return true
+}
+
+// notifyOwner tells the dog owners following the walk that live tracking has stopped updating;
+// each owner of a group walk is told about their own booking
+func (m *stalenessMonitor) notifyOwner(session models.Session, lastSeen time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, booking := range session.AllBookings() {
+		err := m.notifier.Notify(ctx, booking.OwnerID, notifier.Notification{
+			Subject:  "Walk tracking paused",
+			Body:     "We haven't received a location update from your walker recently.",
+			Priority: "high",
+			Data: map[string]string{
+				"event":        EventTrackingStale,
+				"session_id":   session.ID,
+				"booking_id":   booking.BookingID,
+				"last_seen_at": lastSeen.Format(time.RFC3339),
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to notify owner of stale session %s: %v", session.ID, err)
+		}
+	}
+}
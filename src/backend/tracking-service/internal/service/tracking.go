@@ -4,12 +4,22 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"src/backend/shared/clock"
+	"src/backend/shared/regions"
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/fixtures"
+	"src/backend/tracking-service/internal/mapmatching"
+	"src/backend/tracking-service/internal/metrics"
 	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/notifier"
 	"src/backend/tracking-service/internal/repository"
 	"src/backend/tracking-service/internal/websocket"
 )
@@ -21,6 +31,126 @@ import (
 // 4. Ensure proper error handling and logging configuration
 // 5. Verify WebSocket broadcast performance under load
 
+// Hub is the WebSocket hub location updates are broadcast through
+var Hub *websocket.Hub
+
+// tokenSecret and tokenTTL configure WebSocket connection tokens, and allowedOrigins the
+// browser origins other than the service's own that may open WebSockets
+var (
+	tokenSecret    []byte
+	tokenTTL       time.Duration
+	allowedOrigins []string
+)
+
+// serviceClock times staleness detection and the fleet's last-seen times
+var serviceClock clock.Clock = clock.System
+
+// monitor watches active walk sessions for stale tracking
+var monitor *stalenessMonitor
+
+// owners delivers notifications to dog owners
+var owners notifier.Notifier = notifier.LogNotifier{}
+
+// maxAccuracyMeters is the worst reported accuracy a point may have and still be broadcast
+var maxAccuracyMeters = 100.0
+
+// capture records anonymized ingestion traffic as replay fixtures when configured; nil records none
+var capture *fixtures.Recorder
+
+// ErrSessionRequired is returned when a connection token is requested without a session
+var ErrSessionRequired = errors.New("session ID is required")
+
+// Initialize wires the service layer to the configuration and the hub.
+// Must be called before the hub is started.
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func Initialize(cfg config.Config, hub *websocket.Hub) {
+	Hub = hub
+	tokenSecret = []byte(cfg.TokenSecret)
+	tokenTTL = cfg.TokenTTL
+	allowedOrigins = cfg.AllowedOrigins
+	maxAccuracyMeters = cfg.MaxAccuracyMeters
+	consentTermsVersion = cfg.ConsentTermsVersion
+	chatRetention = cfg.ChatRetention
+	chatReportRetention = cfg.ChatReportRetention
+	serviceClock = clock.System
+	if cfg.Clock != nil {
+		serviceClock = cfg.Clock
+	}
+
+	// Owner notifications go through the notification-service when configured
+	owners = notifier.LogNotifier{}
+	if cfg.NotificationURL != "" {
+		owners = notifier.NewHTTPNotifier(cfg.NotificationURL)
+	}
+
+	// Emergencies are escalated to the on-call admin channel when configured
+	alerter = notifier.LogAlerter{}
+	if cfg.OnCallWebhookURL != "" {
+		alerter = notifier.NewWebhookAlerter(cfg.OnCallWebhookURL)
+	}
+
+	// Route snapping is optional post-processing for summaries and exports
+	routeMatcher = nil
+	if cfg.MapMatchingURL != "" {
+		routeMatcher = mapmatching.NewOSRMProvider(cfg.MapMatchingURL)
+	}
+
+	// Walks are tagged with the booking-service's regions when configured
+	if cfg.RegionsURL != "" {
+		serviceRegions = regions.NewRemoteDirectory(cfg.RegionsURL, cfg.RegionsPollInterval)
+		if err := serviceRegions.Refresh(); err != nil {
+			log.Printf("Failed to load regions, walks stay untagged until they load: %v", err)
+		}
+	}
+
+	// Bookings' owners and walkers are looked up at the booking-service when configured
+	bookingDirectory = newBookingDirectory(cfg.BookingsURL, cfg.BookingsAPIKey)
+
+	// Live positions are smoothed when configured; stored points are always the reported fixes
+	smoother = nil
+	if cfg.Smoothing {
+		smoother = newPositionFilter(cfg.SmoothingSpeed)
+	}
+
+	// Ingestion traffic is captured as replay fixtures for tests when configured
+	capture = nil
+	if cfg.CaptureDir != "" {
+		recorder, err := fixtures.NewRecorder(cfg.CaptureDir)
+		if err != nil {
+			log.Printf("Failed to start ingestion capture, traffic is not captured: %v", err)
+		} else {
+			capture = recorder
+		}
+	}
+
+	// Dispatchers follow walks by booking, and the whole fleet through its digest
+	hub.ResolveBookings(walkTopic)
+	fleet = newFleetState(cfg.StaleAfter)
+	hub.Observe(fleet.observe)
+	hub.Channel(FleetChannel, fleetTopic)
+
+	// A walker's walks share one fan-out limit, however many viewers each has
+	hub.TenantOf(fleet.tenant)
+
+	// Points are marked paused from the pauses every instance learns of through the hub
+	hub.Observe(walkPauses.observe)
+
+	monitor = newStalenessMonitor(cfg.StaleAfter, owners, serviceClock)
+	hub.Observe(monitor.observe)
+	restoreSessionMonitors()
+}
+
+// Stop ends the goroutines Initialize started, the staleness monitors of active sessions,
+// waiting for them until ctx is done. Background workers are stopped by cancelling their
+// contexts instead.
+func Stop(ctx context.Context) error {
+	if monitor == nil {
+		return nil
+	}
+	return monitor.stop(ctx)
+}
+
 // TrackLocation processes and broadcasts incoming location data
 // Addresses requirement: Real-time location tracking
 // Location: 1.2 System Overview/High-Level Description/Backend Services
@@ -31,30 +161,91 @@ func TrackLocation(location models.Location) error {
 		return fmt.Errorf("invalid location data: %w", err)
 	}
 
-	// Store the location data in MongoDB
-	if err := repository.InsertLocation(location); err != nil {
+	// Capture the point as it arrived, retries and late points included, before it is processed
+	capture.Record(location)
+
+	// Drop retries of a point already accepted; the client gets the same success either way
+	key := location.DedupeKey()
+	if location.SessionID != "" && !seenPoints.add(key, time.Now()) {
+		metrics.LocationDuplicates.WithLabelValues("cache").Inc()
+		return nil
+	}
+
+	// Points older than the last one broadcast are marked late before they are stored
+	precise := location.IsPrecise(maxAccuracyMeters)
+	private := isPrivate(location)
+	if location.SessionID != "" {
+		location.Late = broadcastOrder.observe(location, precise && !private)
+	}
+
+	// Points recorded while the walker paused are kept, but marked so summaries leave them out
+	if location.SessionID != "" {
+		location.Paused = walkPauses.pausedAt(location.SessionID, location.Timestamp)
+	}
+
+	// Index the point by cell for proximity lookups and heatmaps, unless it is private
+	if !private {
+		location.Cell = locationCell(location)
+	}
+
+	// Buffer the location data for a batched write to MongoDB
+	if err := repository.EnqueueLocation(location); err != nil {
+		seenPoints.forget(key)
 		log.Printf("Failed to store location: %v", err)
 		return fmt.Errorf("failed to store location: %w", err)
 	}
 
-	// Prepare location data for broadcasting
-	locationJSON, err := json.Marshal(struct {
-		Latitude  float64   `json:"latitude"`
-		Longitude float64   `json:"longitude"`
-		Timestamp time.Time `json:"timestamp"`
-	}{
-		Latitude:  location.Latitude,
-		Longitude: location.Longitude,
-		Timestamp: location.Timestamp,
-	})
+	// The first point located in a region tags the walk with it
+	if location.SessionID != "" {
+		tagSessionRegion(location)
+	}
+
+	// Late points are kept for history and summaries only, so the live stream never moves
+	// backwards; they still count as activity for staleness monitoring
+	if location.Late {
+		metrics.LocationsLate.Inc()
+		if Hub != nil {
+			Hub.Publish(location.SessionID, websocket.KindHeartbeat, "")
+		}
+		return nil
+	}
+
+	// Points inside the walker's privacy zones are stored but never shown to the owner
+	if private {
+		metrics.LocationsMasked.Inc()
+		if Hub != nil {
+			Hub.Publish(location.SessionID, websocket.KindHeartbeat, "")
+		}
+		return nil
+	}
+
+	// Imprecise fixes are stored for analysis but kept off the live stream;
+	// they still count as activity for staleness monitoring
+	if !precise {
+		log.Printf("Location stored but not broadcast: accuracy %.1fm exceeds %.1fm",
+			*location.AccuracyMeters, maxAccuracyMeters)
+		if Hub != nil && location.SessionID != "" {
+			Hub.Publish(location.SessionID, websocket.KindHeartbeat, "")
+		}
+		return nil
+	}
+
+	// Prepare location data for broadcasting, smoothed when configured
+	locationJSON, err := json.Marshal(smoother.apply(location))
 	if err != nil {
 		log.Printf("Failed to marshal location data: %v", err)
 		return fmt.Errorf("failed to marshal location data: %w", err)
 	}
 
-	// Broadcast location update to connected clients
-	hub := websocket.NewHub()
-	hub.BroadcastMessage(string(locationJSON))
+	// Broadcast location update to the session's subscribers on every instance
+	if Hub != nil && location.SessionID != "" {
+		Hub.Publish(location.SessionID, websocket.KindLocation, string(locationJSON))
+	}
+
+	// Keep the walker's last position current for proximity lookups
+	if location.SessionID != "" {
+		recordWalkerPosition(location)
+	}
 
 	log.Printf("Location processed and broadcasted successfully: lat=%f, lon=%f, time=%v",
 		location.Latitude, location.Longitude, location.Timestamp)
@@ -88,8 +279,56 @@ func GetLocationHistory(startTime, endTime time.Time) ([]models.Location, error)
 		return nil, fmt.Errorf("failed to retrieve location history: %w", err)
 	}
 
+	locations = maskLocations(locations)
+
 	log.Printf("Retrieved %d location records between %v and %v",
 		len(locations), startTime, endTime)
 
 	return locations, nil
-}
\ No newline at end of file
+}
+
+// IssueConnectionToken creates a signed WebSocket connection token subscribing to a walk session.
+// The token carries the subscription, so the client may connect to any instance.
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func IssueConnectionToken(sessionID string) (string, time.Duration, error) {
+	if sessionID == "" {
+		return "", 0, ErrSessionRequired
+	}
+
+	token, err := websocket.IssueToken(tokenSecret, sessionID, tokenTTL)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to issue connection token: %w", err)
+	}
+	return token, tokenTTL, nil
+}
+
+// AllowedOrigin reports whether browsers at origin may open WebSockets besides the service's own host
+func AllowedOrigin(origin string) bool {
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizeConnection validates a connection token and returns the subscription and
+// participant it encodes
+func AuthorizeConnection(token string) (*websocket.ConnectionToken, error) {
+	return websocket.ParseToken(tokenSecret, token)
+}
+
+// HubStatus returns a snapshot of this instance's WebSocket hub
+func HubStatus() websocket.HubStatus {
+	return Hub.Status()
+}
+
+// InstanceConnections returns the WebSocket connection count of every live instance
+func InstanceConnections(ctx context.Context) (map[string]int, error) {
+	counts, err := Hub.InstanceConnections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve instance connections: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,167 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9" // v9.0.2
+)
+
+const (
+	// backplaneChannel is the Redis pub/sub channel carrying hub messages between instances
+	backplaneChannel = "tracking:hub"
+
+	// sequenceKeyPrefix prefixes the per-topic sequence counters
+	sequenceKeyPrefix = "tracking:seq:"
+
+	// sequenceKeyTTL expires the counter of a topic that has gone quiet
+	sequenceKeyTTL = 24 * time.Hour
+
+	// claimKeyPrefix prefixes keys used to elect a single instance for a piece of work
+	claimKeyPrefix = "tracking:claim:"
+
+	// instanceKeyPrefix prefixes the per-instance connection count keys
+	instanceKeyPrefix = "tracking:instances:"
+
+	// instanceKeyTTL expires the count of an instance that stopped reporting
+	instanceKeyTTL = 3 * connectionReportInterval
+)
+
+// Backplane fans hub messages out across service instances and tracks
+// per-instance connection counts.
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+type Backplane interface {
+	// Publish sends a message to every instance, including this one
+	Publish(ctx context.Context, message Message) error
+
+	// NextSequence allocates the next sequence number for a topic, shared by all instances
+	NextSequence(ctx context.Context, topic string) (uint64, error)
+
+	// Subscribe blocks, invoking handler for each message published by any instance, until
+	// ctx is cancelled
+	Subscribe(ctx context.Context, handler func(Message)) error
+
+	// Claim atomically claims key for ttl, returning false if another instance already holds it
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+	// ReportConnections records the connection count of an instance
+	ReportConnections(ctx context.Context, instanceID string, count int) error
+
+	// ConnectionCounts returns the last reported connection count of every live instance
+	ConnectionCounts(ctx context.Context) (map[string]int, error)
+
+	// Close releases the backplane's resources
+	Close() error
+}
+
+// RedisBackplane implements Backplane using Redis pub/sub
+type RedisBackplane struct {
+	client *redis.Client
+}
+
+// NewRedisBackplane connects to Redis at the given URL
+func NewRedisBackplane(redisURL string) (*RedisBackplane, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return &RedisBackplane{client: client}, nil
+}
+
+// Publish sends a message to every instance
+func (b *RedisBackplane) Publish(ctx context.Context, message Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode backplane message: %w", err)
+	}
+	return b.client.Publish(ctx, backplaneChannel, payload).Err()
+}
+
+// NextSequence increments the topic's shared counter
+func (b *RedisBackplane) NextSequence(ctx context.Context, topic string) (uint64, error) {
+	key := sequenceKeyPrefix + topic
+
+	pipe := b.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, sequenceKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return uint64(incr.Val()), nil
+}
+
+// Subscribe invokes handler for every message published to the backplane
+func (b *RedisBackplane) Subscribe(ctx context.Context, handler func(Message)) error {
+	pubsub := b.client.Subscribe(ctx, backplaneChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var message Message
+			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+				continue
+			}
+			handler(message)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Claim sets the claim key only if it does not exist yet
+func (b *RedisBackplane) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return b.client.SetNX(ctx, claimKeyPrefix+key, 1, ttl).Result()
+}
+
+// ReportConnections stores an instance's connection count with a TTL so dead instances age out
+func (b *RedisBackplane) ReportConnections(ctx context.Context, instanceID string, count int) error {
+	return b.client.Set(ctx, instanceKeyPrefix+instanceID, count, instanceKeyTTL).Err()
+}
+
+// ConnectionCounts returns the connection count of every instance that reported recently
+func (b *RedisBackplane) ConnectionCounts(ctx context.Context) (map[string]int, error) {
+	counts := make(map[string]int)
+
+	iter := b.client.Scan(ctx, 0, instanceKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		count, err := b.client.Get(ctx, key).Int()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read connection count: %w", err)
+		}
+		counts[strings.TrimPrefix(key, instanceKeyPrefix)] = count
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan instances: %w", err)
+	}
+
+	return counts, nil
+}
+
+// Close closes the Redis client
+func (b *RedisBackplane) Close() error {
+	return b.client.Close()
+}
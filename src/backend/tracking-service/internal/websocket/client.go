@@ -0,0 +1,293 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket" // v1.5.0
+)
+
+const (
+	// writeWait is the time allowed to write a message to the peer
+	writeWait = 10 * time.Second
+
+	// pongWait is the time allowed to read the next pong message from the peer
+	pongWait = 60 * time.Second
+
+	// pingPeriod sends pings to the peer with this period; must be less than pongWait
+	pingPeriod = (pongWait * 9) / 10
+
+	// sendBufferSize is the number of outbound messages buffered per client
+	sendBufferSize = 64
+
+	// priorityBufferSize is the number of urgent outbound messages buffered per client
+	priorityBufferSize = 8
+
+	// maxInboundSize is the largest message accepted from the peer, in bytes; clients only
+	// send small control frames
+	maxInboundSize = 512
+)
+
+// Client is a single WebSocket connection registered with the hub.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type Client struct {
+	hub  *Hub
+	conn *websocket.Conn
+
+	// Topic is the subscription this client receives messages for
+	Topic string
+
+	// UserID and Role identify a chat participant; their presence is announced to the topic
+	// and they may send typing signals. Empty for walk subscriptions.
+	UserID string
+	Role   string
+
+	// LastSeq is the last sequence number a resuming client received;
+	// buffered messages after it are replayed on registration
+	LastSeq uint64
+
+	// Interval is the update interval the client asked for on Topic; zero sends every message
+	Interval time.Duration
+
+	// App is the user agent of the client app, reported when the client falls behind
+	App string
+
+	// Deflate is set when the connection negotiated permessage-deflate
+	Deflate bool
+
+	// Protocol is the version of the frames the client reads, ProtocolLegacy unless it asks
+	// for envelopes
+	Protocol int
+
+	// Dispatch is set for dispatcher connections, which subscribe to bookings' walks with
+	// control messages instead of connecting once per walk
+	Dispatch bool
+
+	// subscriptions maps each booking or channel a dispatch client follows to its topic; only
+	// touched by the hub loop
+	subscriptions map[subscriptionKey]string
+
+	// throttles holds the update interval of each throttled topic; only touched by the hub loop
+	throttles map[string]*throttle
+
+	// connectedAt is when the connection was upgraded
+	connectedAt time.Time
+
+	// closeCode and closeReason are sent in the close frame when the hub disconnects the client;
+	// set before send is closed
+	closeCode   int
+	closeReason string
+
+	// slow is set while the client is falling behind; slowEpisodes counts the times it has.
+	// digest holds the latest frame of each topic while the client is downgraded. Only
+	// touched by the hub loop.
+	slow         bool
+	slowEpisodes int
+	digest       map[string][]byte
+
+	// send buffers outbound messages; closed by the hub when the client is removed
+	send chan []byte
+
+	// priority buffers urgent messages, which are written before anything waiting in send
+	priority chan []byte
+
+	// backlog holds replayed messages, written before anything from send
+	backlog [][]byte
+
+	// registered is closed by the hub once registration (and backlog collection) is complete
+	registered chan struct{}
+
+	// typingStatus and typingAt are the last typing signal the client published, for throttling
+	typingStatus string
+	typingAt     time.Time
+
+	// compressedWrites counts the compressed messages written, for sampling compression
+	// ratios; only touched by the write goroutine
+	compressedWrites int
+
+	// inbound limits the messages the client sends; only touched by the read goroutine
+	inbound tokenBucket
+}
+
+// NewClient creates a client for an upgraded connection subscribed to topic
+func NewClient(hub *Hub, conn *websocket.Conn, topic string) *Client {
+	return &Client{
+		hub:         hub,
+		conn:        conn,
+		Topic:       topic,
+		Protocol:    ProtocolLegacy,
+		connectedAt: time.Now().UTC(),
+		send:        make(chan []byte, sendBufferSize),
+		priority:    make(chan []byte, priorityBufferSize),
+		registered:  make(chan struct{}),
+	}
+}
+
+// Serve registers the client with the hub and starts its read and write pumps. The
+// connection is closed instead when the hub has stopped.
+func (c *Client) Serve() {
+	select {
+	case c.hub.Register <- c:
+	case <-c.hub.done:
+		c.conn.Close()
+		return
+	}
+	go c.writePump()
+	go c.readPump()
+}
+
+// readPump keeps the connection alive, handles the peer's control messages and unregisters
+// the client once the peer goes away
+func (c *Client) readPump() {
+	defer func() {
+		select {
+		case c.hub.Unregister <- c:
+		case <-c.hub.done:
+		}
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxInboundSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, message, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("Unexpected WebSocket close: %v", err)
+			}
+			return
+		}
+		c.handleInbound(message, time.Now())
+	}
+}
+
+// handleInbound handles a control message sent by the peer, as a bare frame or an envelope:
+// a dispatch client's subscribe or unsubscribe, or a chat participant's typing signal, which is
+// published to the rest of the topic. Anything else the peer sends is ignored, as are repeats of
+// the same typing status within typingThrottle, and anything over the hub's connection rate.
+func (c *Client) handleInbound(message []byte, now time.Time) {
+	if !c.hub.admitInbound(c, now) {
+		return
+	}
+	inbound, ok := decodeInbound(message)
+	if !ok {
+		return
+	}
+	if inbound.Control == controlSubscribe || inbound.Control == controlUnsubscribe {
+		c.hub.requestSubscription(c, inbound)
+		return
+	}
+
+	if c.UserID == "" || inbound.Control != controlTyping {
+		return
+	}
+	if inbound.Status != statusTyping && inbound.Status != statusIdle {
+		return
+	}
+	if inbound.Status == c.typingStatus && now.Sub(c.typingAt) < typingThrottle {
+		return
+	}
+	c.typingStatus, c.typingAt = inbound.Status, now
+
+	var expiresIn time.Duration
+	if inbound.Status == statusTyping {
+		expiresIn = typingTTL
+	}
+	c.hub.Publish(c.Topic, KindSignal, string(encodeSignal(controlTyping, c.UserID, c.Role, inbound.Status, expiresIn)))
+}
+
+// writePump writes hub messages and keepalive pings to the connection
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	// Flush replayed messages before live traffic so the stream never goes backwards
+	<-c.registered
+	if c.Deflate {
+		c.conn.SetCompressionLevel(c.hub.compression.Level)
+	}
+	for _, message := range c.backlog {
+		if err := c.writeText(message); err != nil {
+			log.Printf("Error replaying message to client: %v", err)
+			return
+		}
+	}
+	c.backlog = nil
+
+	for {
+		// Write any urgent message before taking the next one from send
+		select {
+		case message := <-c.priority:
+			if !c.write(message) {
+				return
+			}
+			continue
+		default:
+		}
+
+		select {
+		case message := <-c.priority:
+			if !c.write(message) {
+				return
+			}
+
+		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				// The hub closed the channel, with a reason when it disconnected the client
+				var reason []byte
+				if c.closeCode != 0 {
+					reason = websocket.FormatCloseMessage(c.closeCode, c.closeReason)
+				}
+				c.conn.WriteMessage(websocket.CloseMessage, reason)
+				return
+			}
+			if !c.write(message) {
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// write sends a single text message, reporting whether the connection is still usable
+func (c *Client) write(message []byte) bool {
+	if err := c.writeText(message); err != nil {
+		log.Printf("Error writing message to client: %v", err)
+		return false
+	}
+	return true
+}
+
+// writeText writes a text message, compressed when it is large enough to be worth it
+func (c *Client) writeText(message []byte) error {
+	if c.hub.dropFrame != nil && c.hub.dropFrame() {
+		c.hub.drop(dropFaultInjected)
+		return nil
+	}
+
+	compressed := c.hub.compresses(c, message)
+	c.conn.EnableWriteCompression(compressed)
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+
+	start := time.Now()
+	if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+		return err
+	}
+	c.observeWrite(message, compressed, time.Since(start))
+	return nil
+}
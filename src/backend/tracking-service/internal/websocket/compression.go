@@ -0,0 +1,82 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"compress/flate"
+	"strconv"
+	"time"
+
+	"src/backend/tracking-service/internal/metrics"
+)
+
+// compressionSampleEvery is how many compressed messages a client writes for each one whose
+// compression ratio is measured
+const compressionSampleEvery = 50
+
+// Compression configures permessage-deflate for clients that negotiate it. Small messages
+// gain little from compression while still costing CPU, so only messages of at least
+// Threshold bytes are compressed.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type Compression struct {
+	Enabled bool
+
+	// Level is the flate compression level, from flate.HuffmanOnly to flate.BestCompression
+	Level int
+
+	// Threshold is the smallest message, in bytes, that is compressed
+	Threshold int
+}
+
+// Compress sets how messages are compressed for clients that negotiated permessage-deflate.
+// Must be called before Run.
+func (h *Hub) Compress(compression Compression) {
+	h.compression = compression
+}
+
+// compresses reports whether message is written compressed to client
+func (h *Hub) compresses(client *Client, message []byte) bool {
+	return client.Deflate && h.compression.Enabled && len(message) >= h.compression.Threshold
+}
+
+// observeWrite records the size of a message written to a client and how long writing it took,
+// and, for a sample of compressed messages, how well they compressed
+func (c *Client) observeWrite(message []byte, compressed bool, took time.Duration) {
+	label := strconv.FormatBool(compressed)
+	metrics.WebSocketMessageBytes.WithLabelValues(label).Add(float64(len(message)))
+	metrics.WebSocketWriteDuration.WithLabelValues(label).Observe(took.Seconds())
+
+	if !compressed {
+		return
+	}
+	if c.compressedWrites++; c.compressedWrites%compressionSampleEvery != 1 {
+		return
+	}
+	if size, ok := deflatedSize(message, c.hub.compression.Level); ok {
+		metrics.WebSocketCompressionRatio.Observe(float64(size) / float64(len(message)))
+	}
+}
+
+// deflatedSize returns the size of message compressed at level
+func deflatedSize(message []byte, level int) (int, bool) {
+	var counter byteCounter
+	writer, err := flate.NewWriter(&counter, level)
+	if err != nil {
+		return 0, false
+	}
+	if _, err := writer.Write(message); err != nil {
+		return 0, false
+	}
+	if err := writer.Close(); err != nil {
+		return 0, false
+	}
+	return int(counter), true
+}
+
+// byteCounter is a writer that counts and discards what is written to it
+type byteCounter int
+
+func (b *byteCounter) Write(p []byte) (int, error) {
+	*b += byteCounter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,168 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Protocol versions a client can ask for when connecting. Legacy clients receive bare frames,
+// as before envelopes existed; envelope clients receive every message wrapped in an Envelope.
+const (
+	ProtocolLegacy   = 1
+	ProtocolEnvelope = 2
+)
+
+// Envelope is the frame written to ProtocolEnvelope clients, for hub traffic and control
+// signals alike
+type Envelope struct {
+	// Type names the message, one of the types registered in messageTypes
+	Type string `json:"type"`
+
+	// Version is the version of the type's payload schema
+	Version int `json:"version"`
+
+	// Topic is the subscription a hub message was published to
+	Topic string `json:"topic,omitempty"`
+
+	// Payload is the message body; for control signals, their fields
+	Payload json.RawMessage `json:"payload,omitempty"`
+
+	// Seq is the message's sequence number within its subscription, for sequenced types
+	Seq uint64 `json:"seq,omitempty"`
+
+	// TS is when the message was published
+	TS time.Time `json:"ts"`
+}
+
+// messageType describes how the hub handles a type of message and which version of its
+// payload envelope clients receive. A payload change existing clients cannot read takes a
+// new version; clients skip types they do not know and versions newer than they understand.
+type messageType struct {
+	version int
+
+	// internal messages are seen by hub observers on every instance but never delivered
+	internal bool
+
+	// sequenced messages are numbered per topic and replayed to resuming clients
+	sequenced bool
+
+	// urgent messages skip ahead of queued traffic in the hub and in each client's buffer
+	urgent bool
+
+	// unlimited messages are exempt from the hub's rate limits
+	unlimited bool
+
+	// coalesced messages are superseded by the next of their topic, so throttled
+	// subscriptions are sent only the latest
+	coalesced bool
+}
+
+// messageTypes registers every message kind the hub accepts and every control signal it
+// sends. Signals travel under KindSignal and reach clients as the control they carry.
+var messageTypes = map[string]messageType{
+	KindLocation: {version: 1, sequenced: true, coalesced: true},
+	KindEvent:    {version: 1, sequenced: true},
+	KindSOS:      {version: 1, sequenced: true, urgent: true, unlimited: true},
+	KindChat:     {version: 1, sequenced: true},
+	KindSignal:   {unlimited: true},
+	KindFleet:    {version: 1, unlimited: true, coalesced: true},
+
+	KindHeartbeat:      {internal: true, unlimited: true},
+	KindSessionStarted: {internal: true, unlimited: true},
+	KindSessionEnded:   {internal: true, unlimited: true},
+
+	controlResumeIncomplete: {version: 1},
+	controlServerDraining:   {version: 1},
+	controlPresence:         {version: 1},
+	controlTyping:           {version: 1},
+	controlAck:              {version: 1},
+}
+
+// outbound is a message on its way to clients, encoded at most once for each protocol
+// version among them. It is only used from the hub loop, with h.mu held.
+type outbound struct {
+	kind    string
+	topic   string
+	seq     uint64
+	ts      time.Time
+	payload json.RawMessage
+
+	// legacy is the message's bare frame; frames caches its encoding per protocol version
+	legacy func() []byte
+	frames map[int][]byte
+}
+
+// messageOutbound prepares a hub message for delivery
+func messageOutbound(message Message) *outbound {
+	payload := json.RawMessage(message.Data)
+	if !json.Valid(payload) {
+		// Non-JSON messages are sent as a JSON string
+		payload, _ = json.Marshal(message.Data)
+	}
+	return &outbound{
+		kind:    message.Kind,
+		topic:   message.Topic,
+		seq:     message.Seq,
+		ts:      publishedAt(message),
+		payload: payload,
+		legacy: func() []byte {
+			data, _ := json.Marshal(frame{Seq: message.Seq, Topic: message.Topic, Payload: payload})
+			return data
+		},
+	}
+}
+
+// controlOutbound prepares a control signal for delivery. Envelope clients receive the
+// signal's fields as the payload of an envelope typed by the control.
+func controlOutbound(signal frame, ts time.Time) *outbound {
+	control := signal.Control
+	signal.Control = ""
+	payload, _ := json.Marshal(signal)
+	return &outbound{
+		kind:    control,
+		ts:      ts,
+		payload: payload,
+		legacy: func() []byte {
+			signal.Control = control
+			data, _ := json.Marshal(signal)
+			return data
+		},
+	}
+}
+
+// frame returns the message encoded for a client speaking protocol
+func (o *outbound) frame(protocol int) []byte {
+	if data, ok := o.frames[protocol]; ok {
+		return data
+	}
+
+	var data []byte
+	if protocol == ProtocolEnvelope {
+		data, _ = json.Marshal(Envelope{
+			Type:    o.kind,
+			Version: messageTypes[o.kind].version,
+			Topic:   o.topic,
+			Payload: o.payload,
+			Seq:     o.seq,
+			TS:      o.ts,
+		})
+	} else {
+		data = o.legacy()
+	}
+
+	if o.frames == nil {
+		o.frames = make(map[int][]byte)
+	}
+	o.frames[protocol] = data
+	return data
+}
+
+// publishedAt returns when a message was published. Messages relayed by instances that
+// predate envelopes carry no time and are stamped on arrival.
+func publishedAt(message Message) time.Time {
+	if message.Time.IsZero() {
+		return time.Now().UTC()
+	}
+	return message.Time
+}
@@ -0,0 +1,128 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Control signals sent to clients
+const (
+	// controlResumeIncomplete tells a resuming client that some missed messages are no longer buffered
+	controlResumeIncomplete = "resume_incomplete"
+
+	// controlServerDraining tells a client this instance is shutting down and it should
+	// reconnect, after ReconnectAfterMs, to be routed to another instance
+	controlServerDraining = "server_draining"
+
+	// controlPresence announces a chat participant coming online or going offline
+	controlPresence = "presence"
+
+	// controlTyping announces a chat participant starting or stopping typing; clients send it
+	// to the hub too, with only Status set
+	controlTyping = "typing"
+
+	// controlSubscribe and controlUnsubscribe are sent by dispatch clients to start and stop
+	// receiving a booking's walk, with BookingID set, or a channel, with Channel set. A
+	// subscribe may set IntervalMs to receive at most one location or digest per interval.
+	controlSubscribe   = "subscribe"
+	controlUnsubscribe = "unsubscribe"
+
+	// controlAck answers a subscribe or unsubscribe, naming it in Action, with the walk's Topic
+	// on success and Error otherwise
+	controlAck = "ack"
+)
+
+// Presence and typing statuses
+const (
+	statusOnline  = "online"
+	statusOffline = "offline"
+	statusTyping  = "typing"
+	statusIdle    = "idle"
+)
+
+// frame is the JSON structure written to ProtocolLegacy clients. Signals travel between
+// instances in this form whatever protocol their recipients speak.
+type frame struct {
+	// Seq is the message's sequence number within its subscription; clients send the
+	// last one they processed as last_seq when reconnecting
+	Seq uint64 `json:"seq,omitempty"`
+
+	// Topic is the subscription the message was published to, letting clients subscribed to
+	// several tell them apart
+	Topic string `json:"topic,omitempty"`
+
+	// Payload is the message body
+	Payload json.RawMessage `json:"payload,omitempty"`
+
+	// Control carries hub-to-client protocol signals
+	Control string `json:"control,omitempty"`
+
+	// ReconnectAfterMs is how long a draining client should wait before reconnecting;
+	// each client gets a different delay so they do not all reconnect at once
+	ReconnectAfterMs int64 `json:"reconnect_after_ms,omitempty"`
+
+	// UserID and Role identify the participant a presence or typing frame is about
+	UserID string `json:"user_id,omitempty"`
+	Role   string `json:"role,omitempty"`
+
+	// Status is the participant's presence or typing status
+	Status string `json:"status,omitempty"`
+
+	// ExpiresInMs is how long a typing status holds unless it is repeated; clients clear it
+	// afterwards, so a participant who stops without saying so does not appear typing forever
+	ExpiresInMs int64 `json:"expires_in_ms,omitempty"`
+
+	// BookingID or Channel, Action and Error describe a subscription request and its
+	// acknowledgement
+	BookingID string `json:"booking_id,omitempty"`
+	Channel   string `json:"channel,omitempty"`
+	Action    string `json:"action,omitempty"`
+	Error     string `json:"error,omitempty"`
+
+	// IntervalMs is the update interval a subscribe asks for; zero sends every message
+	IntervalMs int64 `json:"interval_ms,omitempty"`
+}
+
+// encodeSignal builds a presence or typing control frame about a participant
+func encodeSignal(control, userID, role, status string, expiresIn time.Duration) []byte {
+	data, _ := json.Marshal(frame{
+		Control:     control,
+		UserID:      userID,
+		Role:        role,
+		Status:      status,
+		ExpiresInMs: expiresIn.Milliseconds(),
+	})
+	return data
+}
+
+// decodeSignal parses a presence or typing control frame
+func decodeSignal(data string) (frame, bool) {
+	var f frame
+	if err := json.Unmarshal([]byte(data), &f); err != nil || f.UserID == "" {
+		return frame{}, false
+	}
+	return f, true
+}
+
+// decodeInbound parses a control frame sent by a client, either bare or as an envelope typed
+// by the control with the frame's fields as its payload
+func decodeInbound(message []byte) (frame, bool) {
+	var inbound struct {
+		frame
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(message, &inbound); err != nil {
+		return frame{}, false
+	}
+	if inbound.Type == "" {
+		return inbound.frame, true
+	}
+
+	var f frame
+	if len(inbound.Payload) > 0 && json.Unmarshal(inbound.Payload, &f) != nil {
+		return frame{}, false
+	}
+	f.Control = inbound.Type
+	return f, true
+}
@@ -2,29 +2,153 @@
 package websocket
 
 import (
+	"context"
 	"log"
+	"math/rand"
 	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// connectionReportInterval is how often the hub reports its connection count to the backplane
+	connectionReportInterval = 10 * time.Second
 
-	"github.com/gorilla/websocket" // v1.5.0
+	// drainPollInterval is how often Drain checks whether every client has disconnected
+	drainPollInterval = 250 * time.Millisecond
 )
 
+// Message kinds, registered with how the hub handles each in messageTypes. Signals
+// are presence and typing control frames, delivered as they are and never
+// sequenced or replayed.
+const (
+	KindLocation = "location"
+	KindEvent    = "event"
+	KindSOS      = "sos"
+	KindChat     = "chat"
+	KindSignal   = "signal"
+	KindFleet    = "fleet"
+
+	KindHeartbeat      = "heartbeat"
+	KindSessionStarted = "session_started"
+	KindSessionEnded   = "session_ended"
+)
+
+// Message is a payload addressed to the subscribers of a topic.
+// An empty Topic addresses every connected client.
+type Message struct {
+	Topic string `json:"topic"`
+	Kind  string `json:"kind,omitempty"`
+	Data  string `json:"data"`
+
+	// Seq orders messages within a topic so clients can resume after reconnecting
+	Seq uint64 `json:"seq,omitempty"`
+
+	// Time is when the message was published
+	Time time.Time `json:"ts"`
+}
+
 // Hub manages WebSocket connections and broadcasts messages to connected clients.
 // Addresses requirement: Real-time location tracking
 // Location: 1.2 System Overview/High-Level Description/Backend Services
 type Hub struct {
-	// Broadcast channel for sending messages to all connected clients
-	Broadcast chan string
+	// Broadcast channel for sending messages to connected clients
+	Broadcast chan Message
+
+	// urgent carries urgent messages, which the hub loop always handles before Broadcast
+	urgent chan Message
 
 	// Register channel for new client connections
-	Register chan *websocket.Conn
+	Register chan *Client
 
 	// Unregister channel for client disconnections
-	Unregister chan *websocket.Conn
+	Unregister chan *Client
+
+	// subscriptions carries dispatch clients' subscribe and unsubscribe requests
+	subscriptions chan subscription
+
+	// resolveBooking finds the topic of a booking's walk for dispatch subscriptions
+	resolveBooking func(bookingID string) (string, error)
+
+	// channels maps the channels dispatch clients may subscribe to by name onto their topics
+	channels map[string]string
 
 	// Clients map stores all active WebSocket connections
-	Clients map[*websocket.Conn]bool
+	Clients map[*Client]bool
+
+	// rooms maps a subscription topic to the clients subscribed to it
+	rooms map[string]map[*Client]bool
+
+	// replay retains recent messages per topic for session resumption
+	replay *replayBuffer
+
+	// sequences holds the last sequence number per topic when running without a backplane
+	sequences map[string]uint64
+	seqMu     sync.Mutex
+
+	// presence tracks the participants of each topic whose connections carry a user
+	presence map[string]map[string]*presenceEntry
+
+	// sessionLimit and fanoutLimit bound what is published to each topic and delivered for
+	// each tenant; nil when unlimited
+	sessionLimit *rateLimiter
+	fanoutLimit  *rateLimiter
+
+	// maxConnections and maxUserConnections bound the clients of this instance and of each
+	// user; userConnections counts the clients of each user. 0 is unlimited.
+	maxConnections     int
+	maxUserConnections int
+	userConnections    map[string]int
+
+	// compression configures permessage-deflate for the clients that negotiate it
+	compression Compression
+
+	// connectionRate bounds the messages each client may send, per second; 0 is unlimited
+	connectionRate float64
+
+	// tenantOf maps a topic to the tenant its fan-out counts towards
+	tenantOf func(topic string) string
+
+	// observers are notified of every message this instance receives, including internal kinds
+	observers []func(Message)
+
+	// backplane fans messages out to every instance when the service runs horizontally scaled
+	backplane  Backplane
+	instanceID string
+
+	// dropped counts the messages dropped for each reason
+	dropped map[string]*atomic.Uint64
 
-	// mutex for thread-safe access to the Clients map
+	// slowPolicy is applied to clients whose send buffers are full; downgraded holds the
+	// clients receiving digests under SlowConsumerDigest
+	slowPolicy SlowConsumerPolicy
+	downgraded map[*Client]bool
+
+	// throttled holds the clients with messages held back for their update intervals
+	throttled map[*Client]bool
+
+	// slowConsumers holds the most recent slow consumer events, oldest first
+	slowConsumers []SlowConsumer
+
+	// broadcasts counts delivered messages; latency accumulates their broadcast latency over
+	// the current stats window, lastLatency over the previous one
+	broadcasts  uint64
+	latency     latencyWindow
+	lastLatency latencyWindow
+
+	// dropFrame reports whether fault injection discards an outbound frame; nil drops none
+	dropFrame func() bool
+
+	// draining is set once the instance starts shutting down; new connections are refused
+	draining atomic.Bool
+
+	// done is closed by Stop to end Run; stopped is closed once Run and the goroutines it
+	// started have returned
+	done     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+
+	// mutex for thread-safe access to the Clients and rooms maps
 	mu sync.RWMutex
 }
 
@@ -33,67 +157,469 @@ type Hub struct {
 // Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
 func NewHub() *Hub {
 	return &Hub{
-		Broadcast:  make(chan string),
-		Register:   make(chan *websocket.Conn),
-		Unregister: make(chan *websocket.Conn),
-		Clients:    make(map[*websocket.Conn]bool),
+		Broadcast:       make(chan Message, 256),
+		urgent:          make(chan Message, 16),
+		Register:        make(chan *Client),
+		Unregister:      make(chan *Client),
+		subscriptions:   make(chan subscription),
+		Clients:         make(map[*Client]bool),
+		rooms:           make(map[string]map[*Client]bool),
+		replay:          newReplayBuffer(defaultReplaySize, defaultReplayTTL),
+		sequences:       make(map[string]uint64),
+		presence:        make(map[string]map[string]*presenceEntry),
+		channels:        make(map[string]string),
+		dropped:         newDropCounters(),
+		slowPolicy:      SlowConsumerDisconnect,
+		downgraded:      make(map[*Client]bool),
+		throttled:       make(map[*Client]bool),
+		userConnections: make(map[string]int),
+		done:            make(chan struct{}),
+		stopped:         make(chan struct{}),
+	}
+}
+
+// ConfigureReplay sets how many messages per subscription, and for how long, are kept
+// for resuming clients. Must be called before Run.
+func (h *Hub) ConfigureReplay(size int, ttl time.Duration) {
+	h.replay = newReplayBuffer(size, ttl)
+}
+
+// SetInstanceID sets the identifier this hub reports its connection count under
+func (h *Hub) SetInstanceID(instanceID string) {
+	h.instanceID = instanceID
+}
+
+// UseBackplane routes published messages through the given backplane so that
+// clients connected to any instance receive them. Must be called before Run.
+func (h *Hub) UseBackplane(backplane Backplane) {
+	h.backplane = backplane
+}
+
+// Observe registers fn to be called from the hub loop for every message received,
+// whether published locally or via the backplane. fn must not block. Must be called before Run.
+func (h *Hub) Observe(fn func(Message)) {
+	h.observers = append(h.observers, fn)
+}
+
+// InjectFrameDrops has fn decide whether each outbound text frame is discarded instead of
+// written, to test how clients cope with lost messages. Must be called before Run.
+func (h *Hub) InjectFrameDrops(fn func() bool) {
+	h.dropFrame = fn
+}
+
+// Claim reports whether this instance is the first to claim key within ttl, so that
+// work triggered on every instance (such as staleness events) happens only once.
+// Without a backplane every claim succeeds.
+func (h *Hub) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if h.backplane == nil {
+		return true, nil
 	}
+	return h.backplane.Claim(ctx, key, ttl)
 }
 
 // Run starts the WebSocket hub and handles client connections and message broadcasting.
-// This method runs in its own goroutine and manages the hub's main event loop.
+// This method runs in its own goroutine and manages the hub's main event loop until Stop
+// is called.
 func (h *Hub) Run() {
+	defer close(h.stopped)
+
+	// Deferred calls run in reverse: cancel the backplane goroutines, then wait for them
+	ctx, cancel := context.WithCancel(context.Background())
+	var background sync.WaitGroup
+	defer background.Wait()
+	defer cancel()
+	if h.backplane != nil {
+		background.Add(2)
+		go func() {
+			defer background.Done()
+			h.consumeBackplane(ctx)
+		}()
+		go func() {
+			defer background.Done()
+			h.reportConnections(ctx)
+		}()
+	}
+
+	pruneTicker := time.NewTicker(h.replay.ttl)
+	defer pruneTicker.Stop()
+	presenceTicker := time.NewTicker(presenceInterval)
+	defer presenceTicker.Stop()
+	statsTicker := time.NewTicker(statsInterval)
+	defer statsTicker.Stop()
+	digestTicker := time.NewTicker(digestInterval)
+	defer digestTicker.Stop()
+	throttleTicker := time.NewTicker(throttleResolution)
+	defer throttleTicker.Stop()
+
 	for {
+		// Drain urgent messages first; select alone would pick among ready channels at random
 		select {
+		case message := <-h.urgent:
+			h.broadcastMessage(message)
+			continue
+		default:
+		}
+
+		select {
+		case message := <-h.urgent:
+			h.broadcastMessage(message)
+
 		case client := <-h.Register:
-			// Add new client connection
+			// Add new client connection, unless it is over a connection limit
 			h.mu.Lock()
+			if !h.admit(client) {
+				h.mu.Unlock()
+				close(client.registered)
+				continue
+			}
 			h.Clients[client] = true
+			var online []byte
+			if client.Topic != "" {
+				h.joinRoom(client, client.Topic)
+				h.throttle(client, client.Topic, client.Interval)
+
+				// Replay what the client missed before it starts receiving live messages
+				if client.LastSeq > 0 {
+					h.resume(client)
+				}
+				online = h.joinPresence(client, time.Now())
+			}
+			total := len(h.Clients)
 			h.mu.Unlock()
-			log.Printf("New client connected. Total clients: %d", len(h.Clients))
+			close(client.registered)
+			if online != nil {
+				h.publishPresence(presenceSignal{topic: client.Topic, data: online})
+			}
+			log.Printf("New client connected. Total clients: %d", total)
 
 		case client := <-h.Unregister:
 			// Remove disconnected client
 			h.mu.Lock()
-			if _, ok := h.Clients[client]; ok {
-				delete(h.Clients, client)
-				client.Close()
-			}
+			h.removeClient(client)
+			total := len(h.Clients)
+			h.mu.Unlock()
+			log.Printf("Client disconnected. Total clients: %d", total)
+
+		case change := <-h.subscriptions:
+			h.mu.Lock()
+			h.applySubscription(change, time.Now())
 			h.mu.Unlock()
-			log.Printf("Client disconnected. Total clients: %d", len(h.Clients))
 
 		case message := <-h.Broadcast:
-			// Broadcast message to all connected clients
+			// Deliver message to the addressed clients
 			h.broadcastMessage(message)
+
+		case now := <-pruneTicker.C:
+			h.mu.Lock()
+			h.replay.prune(now)
+			h.mu.Unlock()
+			h.sessionLimit.prune(now)
+			h.fanoutLimit.prune(now)
+
+		case now := <-presenceTicker.C:
+			h.mu.Lock()
+			announcements := h.refreshPresence(now)
+			h.mu.Unlock()
+			h.publishPresence(announcements...)
+
+		case <-statsTicker.C:
+			h.mu.Lock()
+			h.refreshStats()
+			h.mu.Unlock()
+
+		case <-digestTicker.C:
+			h.mu.Lock()
+			h.flushDigests()
+			h.mu.Unlock()
+
+		case now := <-throttleTicker.C:
+			h.mu.Lock()
+			h.flushThrottled(now)
+			h.mu.Unlock()
+
+		case <-h.done:
+			return
 		}
 	}
 }
 
+// Stop ends Run and the goroutines it started, waiting until they have returned or ctx is
+// done. Close the clients first with CloseAllConnections; once the hub is stopped, messages
+// published to it are dropped and new clients are turned away.
+func (h *Hub) Stop(ctx context.Context) error {
+	h.stopOnce.Do(func() { close(h.done) })
+	select {
+	case <-h.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // BroadcastMessage sends a message to all connected WebSocket clients.
 // If a client connection fails, it is removed from the Clients map.
 func (h *Hub) BroadcastMessage(message string) {
-	h.Broadcast <- message
+	h.Publish("", KindEvent, message)
+}
+
+// Publish sends a message of the given kind to the clients subscribed to topic on every instance.
+// Without a backplane the message is delivered to local clients only.
+func (h *Hub) Publish(topic, kind, message string) {
+	if _, ok := messageTypes[kind]; !ok {
+		log.Printf("Dropping message of unregistered kind %q", kind)
+		h.drop(dropUnregisteredKind)
+		return
+	}
+	if !h.admitPublish(topic, kind, time.Now()) {
+		return
+	}
+	msg := Message{Topic: topic, Kind: kind, Data: message, Time: time.Now().UTC()}
+
+	if h.backplane != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if topic != "" && isSequenced(kind) {
+			seq, err := h.backplane.NextSequence(ctx, topic)
+			if err != nil {
+				log.Printf("Failed to allocate sequence number from backplane: %v", err)
+			}
+			msg.Seq = seq
+		}
+
+		err := h.backplane.Publish(ctx, msg)
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to publish to backplane, delivering locally: %v", err)
+	}
+
+	if topic != "" && msg.Seq == 0 && isSequenced(kind) {
+		msg.Seq = h.nextSequence(topic)
+	}
+	h.enqueue(msg)
+}
+
+// PublishLocal sends a message of the given kind to the clients subscribed to topic on this
+// instance only, for traffic every instance produces for its own clients. Local messages are
+// not sequenced.
+func (h *Hub) PublishLocal(topic, kind, message string) {
+	if _, ok := messageTypes[kind]; !ok {
+		log.Printf("Dropping message of unregistered kind %q", kind)
+		h.drop(dropUnregisteredKind)
+		return
+	}
+	h.enqueue(Message{Topic: topic, Kind: kind, Data: message, Time: time.Now().UTC()})
+}
+
+// enqueue hands a message to the hub loop, on the urgent channel if its kind requires
+func (h *Hub) enqueue(message Message) {
+	queue := h.Broadcast
+	if isUrgent(message.Kind) {
+		queue = h.urgent
+	}
+
+	// A select picks at random between ready cases, so a stopped hub with room left in its
+	// queue would otherwise sometimes take a message nothing is left to deliver
+	select {
+	case <-h.done:
+		h.drop(dropHubStopped)
+		return
+	default:
+	}
+	select {
+	case queue <- message:
+	case <-h.done:
+		h.drop(dropHubStopped)
+	}
+}
+
+// isInternal reports whether messages of kind are kept from WebSocket clients
+func isInternal(kind string) bool {
+	return messageTypes[kind].internal
+}
+
+// isSequenced reports whether messages of kind are numbered for resuming clients
+func isSequenced(kind string) bool {
+	return messageTypes[kind].sequenced
+}
+
+// isUrgent reports whether messages of kind skip ahead of queued traffic
+func isUrgent(kind string) bool {
+	return messageTypes[kind].urgent
+}
+
+// isUnlimited reports whether messages of kind are exempt from rate limits
+func isUnlimited(kind string) bool {
+	return messageTypes[kind].unlimited
+}
+
+// isCoalesced reports whether messages of kind may be skipped for a later one of their topic
+func isCoalesced(kind string) bool {
+	return messageTypes[kind].coalesced
+}
+
+// nextSequence allocates the next local sequence number for topic
+func (h *Hub) nextSequence(topic string) uint64 {
+	h.seqMu.Lock()
+	defer h.seqMu.Unlock()
+
+	h.sequences[topic]++
+	return h.sequences[topic]
 }
 
 // broadcastMessage is an internal method that handles the actual message broadcasting
-// to all connected clients.
-func (h *Hub) broadcastMessage(message string) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+// to the addressed clients.
+func (h *Hub) broadcastMessage(message Message) {
+	for _, observe := range h.observers {
+		observe(message)
+	}
+	if isInternal(message.Kind) {
+		return
+	}
 
-	for client := range h.Clients {
-		err := client.WriteMessage(websocket.TextMessage, []byte(message))
-		if err != nil {
-			log.Printf("Error broadcasting message to client: %v", err)
-			
-			// Close and remove failed client connection
-			client.Close()
-			h.Unregister <- client
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if message.Kind == KindSignal {
+		if h.admitSignal(message, time.Now()) {
+			signal, _ := decodeSignal(message.Data)
+			h.deliver(h.rooms[message.Topic], controlOutbound(signal, publishedAt(message)))
+			h.observeBroadcast(message.Time, time.Now())
+		}
+		return
+	}
+
+	targets := h.Clients
+	if message.Topic != "" {
+		targets = h.rooms[message.Topic]
+		if !h.admitFanout(message, len(targets), time.Now()) {
+			return
+		}
+		if message.Seq > 0 {
+			h.replay.append(message, time.Now())
+		}
+	}
+	h.deliver(targets, messageOutbound(message))
+	h.observeBroadcast(message.Time, time.Now())
+}
+
+// deliver queues a message to each target client, encoded for the client's protocol, holding
+// it back from subscriptions throttled to an update interval and applying the slow consumer
+// policy to clients that are not keeping up.
+// Callers must hold h.mu.
+func (h *Hub) deliver(targets map[*Client]bool, message *outbound) {
+	urgent := isUrgent(message.kind)
+	coalesced := isCoalesced(message.kind) && message.topic != ""
+	now := time.Now()
+	for client := range targets {
+		queue := client.send
+		if urgent {
+			queue = client.priority
+		}
+
+		frame := message.frame(client.Protocol)
+		if client.digest != nil && !urgent && message.topic != "" {
+			h.queueDigest(client, message.topic, frame)
+			continue
+		}
+		if coalesced && h.holdBack(client, message.topic, frame, now) {
 			continue
 		}
+
+		select {
+		case queue <- frame:
+			h.caughtUp(client, queue)
+		default:
+			// Client is not keeping up
+			h.queueFull(client, queue, frame, message.topic)
+		}
+	}
+}
+
+// resume collects the buffered messages a reconnecting client missed into its backlog,
+// which the client's write pump flushes before any live message.
+// Callers must hold h.mu.
+func (h *Hub) resume(client *Client) {
+	now := time.Now()
+	missed, complete := h.replay.since(client.Topic, client.LastSeq, now)
+	if !complete {
+		// Tell the client to backfill from the history API before applying replayed messages
+		incomplete := controlOutbound(frame{Control: controlResumeIncomplete}, now.UTC())
+		client.backlog = append(client.backlog, incomplete.frame(client.Protocol))
+	}
+	for _, message := range missed {
+		client.backlog = append(client.backlog, messageOutbound(message).frame(client.Protocol))
 	}
 }
 
+// removeClient deletes the client from the hub and closes its send channel.
+// Callers must hold h.mu.
+func (h *Hub) removeClient(client *Client) {
+	if _, ok := h.Clients[client]; !ok {
+		return
+	}
+	delete(h.Clients, client)
+	delete(h.downgraded, client)
+	delete(h.throttled, client)
+	h.releaseUser(client)
+	h.leaveRoom(client, client.Topic)
+	for _, topic := range client.subscriptions {
+		h.leaveRoom(client, topic)
+	}
+	close(client.send)
+
+	if offline := h.leavePresence(client); offline != nil {
+		h.publishPresence(presenceSignal{topic: client.Topic, data: offline})
+	}
+}
+
+// consumeBackplane delivers messages received from other instances to local clients
+func (h *Hub) consumeBackplane(ctx context.Context) {
+	err := h.backplane.Subscribe(ctx, func(message Message) {
+		h.enqueue(message)
+	})
+	if err != nil {
+		log.Printf("Backplane subscription ended: %v", err)
+	}
+}
+
+// reportConnections periodically publishes this instance's connection count to the backplane
+// until ctx is cancelled
+func (h *Hub) reportConnections(ctx context.Context) {
+	ticker := time.NewTicker(connectionReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		reportCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		if err := h.backplane.ReportConnections(reportCtx, h.instanceID, h.GetConnectedClients()); err != nil {
+			log.Printf("Failed to report connection count: %v", err)
+		}
+		cancel()
+	}
+}
+
+// InstanceConnections returns the connection count of every live instance, keyed by instance ID.
+// Without a backplane only this instance is reported.
+func (h *Hub) InstanceConnections(ctx context.Context) (map[string]int, error) {
+	if h.backplane == nil {
+		return map[string]int{h.instanceID: h.GetConnectedClients()}, nil
+	}
+	return h.backplane.ConnectionCounts(ctx)
+}
+
+// Subscribers returns the number of clients on this instance subscribed to topic
+func (h *Hub) Subscribers(topic string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.rooms[topic])
+}
+
 // GetConnectedClients returns the current number of connected clients
 func (h *Hub) GetConnectedClients() int {
 	h.mu.RLock()
@@ -101,6 +627,61 @@ func (h *Hub) GetConnectedClients() int {
 	return len(h.Clients)
 }
 
+// Draining reports whether the hub is draining connections ahead of shutdown
+func (h *Hub) Draining() bool {
+	return h.draining.Load()
+}
+
+// Drain asks every client connected to this instance to reconnect elsewhere, then waits up to
+// period for them to go. Each client is told to wait a random delay within the first half of the
+// period, spreading reconnects out instead of moving every connection at once, and leaving the
+// rest of the period for them to complete. The announcement is local: other instances keep serving.
+func (h *Hub) Drain(ctx context.Context, period time.Duration) {
+	h.draining.Store(true)
+
+	h.mu.Lock()
+	total := len(h.Clients)
+	for client := range h.Clients {
+		var delay time.Duration
+		if spread := int64(period / 2); spread > 0 {
+			delay = time.Duration(rand.Int63n(spread))
+		}
+
+		draining := controlOutbound(frame{Control: controlServerDraining, ReconnectAfterMs: delay.Milliseconds()}, time.Now().UTC())
+		select {
+		case client.priority <- draining.frame(client.Protocol):
+		default:
+			// The client is not keeping up; it will be disconnected when the hub closes
+		}
+	}
+	h.mu.Unlock()
+
+	if total == 0 {
+		return
+	}
+	log.Printf("Draining %d WebSocket connections over %v", total, period)
+
+	deadline := time.NewTimer(period)
+	defer deadline.Stop()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if h.GetConnectedClients() == 0 {
+				log.Printf("All WebSocket clients reconnected elsewhere")
+				return
+			}
+		case <-deadline.C:
+			log.Printf("Drain period elapsed with %d WebSocket clients still connected", h.GetConnectedClients())
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // CloseAllConnections closes all active WebSocket connections
 // This is useful for graceful shutdown of the hub
 func (h *Hub) CloseAllConnections() {
@@ -108,8 +689,13 @@ func (h *Hub) CloseAllConnections() {
 	defer h.mu.Unlock()
 
 	for client := range h.Clients {
-		client.Close()
-		delete(h.Clients, client)
+		h.removeClient(client)
 	}
 	log.Printf("All WebSocket connections closed")
-}
\ No newline at end of file
+
+	if h.backplane != nil {
+		if err := h.backplane.Close(); err != nil {
+			log.Printf("Failed to close backplane: %v", err)
+		}
+	}
+}
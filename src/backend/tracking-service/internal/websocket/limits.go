@@ -0,0 +1,64 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"log"
+
+	"src/backend/tracking-service/internal/metrics"
+)
+
+// Close codes sent to connections refused for exceeding a connection limit. Clients refused
+// by a full instance may reconnect, landing on another instance; clients over their user
+// quota should close one of their other connections first.
+const (
+	CloseInstanceFull = 4013
+	CloseUserQuota    = 4029
+)
+
+// LimitConnections bounds the clients connected to this instance, and the clients of a single
+// user among them; zero is unlimited. Only participant connections carry a user, so walk
+// subscriptions count towards the total alone. Must be called before Run.
+func (h *Hub) LimitConnections(max, perUser int) {
+	h.maxConnections = max
+	h.maxUserConnections = perUser
+}
+
+// admit reports whether a registering client fits within the connection limits, refusing it
+// with the close code of the limit it exceeds otherwise.
+// Callers must hold h.mu.
+func (h *Hub) admit(client *Client) bool {
+	switch {
+	case h.maxConnections > 0 && len(h.Clients) >= h.maxConnections:
+		h.refuse(client, CloseInstanceFull, "instance connection limit reached", "instance")
+		return false
+
+	case h.maxUserConnections > 0 && client.UserID != "" && h.userConnections[client.UserID] >= h.maxUserConnections:
+		h.refuse(client, CloseUserQuota, "user connection limit reached", "user")
+		return false
+	}
+
+	if client.UserID != "" {
+		h.userConnections[client.UserID]++
+	}
+	return true
+}
+
+// refuse closes a client that was never added to the hub with code.
+// Callers must hold h.mu.
+func (h *Hub) refuse(client *Client, code int, reason, limit string) {
+	log.Printf("Refused WebSocket connection: %s (app=%q user=%q)", reason, client.App, client.UserID)
+	metrics.HubConnectionsRefused.WithLabelValues(limit).Inc()
+	client.closeCode, client.closeReason = code, reason
+	close(client.send)
+}
+
+// releaseUser gives back the connection a removed client held in its user's quota.
+// Callers must hold h.mu.
+func (h *Hub) releaseUser(client *Client) {
+	if client.UserID == "" {
+		return
+	}
+	if h.userConnections[client.UserID]--; h.userConnections[client.UserID] <= 0 {
+		delete(h.userConnections, client.UserID)
+	}
+}
@@ -0,0 +1,197 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"time"
+)
+
+const (
+	// presenceInterval is how often an instance re-announces the participants connected to it
+	// and expires those it has stopped hearing about
+	presenceInterval = 30 * time.Second
+
+	// presenceTTL is how long a participant connected to another instance is shown online
+	// without being re-announced, covering an instance that goes away without saying so
+	presenceTTL = 3 * presenceInterval
+
+	// typingTTL is how long a typing status holds unless the participant repeats it
+	typingTTL = 5 * time.Second
+
+	// typingThrottle is the shortest interval between two identical typing signals from a client
+	typingThrottle = time.Second
+)
+
+// presenceEntry is what an instance knows about one participant of a topic
+type presenceEntry struct {
+	role string
+
+	// local counts the participant's connections to this instance
+	local int
+
+	// seenAt is when the participant was last announced online, by any instance;
+	// zero until the first announcement is delivered
+	seenAt time.Time
+}
+
+// online reports whether the participant is connected, here or to an instance that
+// has announced them recently
+func (e *presenceEntry) online(now time.Time) bool {
+	return e.local > 0 || (!e.seenAt.IsZero() && now.Sub(e.seenAt) < presenceTTL)
+}
+
+// presenceSignal is a presence announcement waiting to be published
+type presenceSignal struct {
+	topic string
+	data  []byte
+}
+
+// joinPresence records a participant's new connection, queuing the presence of the topic's
+// other participants into its backlog. It returns the online announcement to publish when
+// this is the participant's first connection to the instance.
+// Callers must hold h.mu.
+func (h *Hub) joinPresence(client *Client, now time.Time) []byte {
+	entries := h.presence[client.Topic]
+	for userID, entry := range entries {
+		if userID != client.UserID && entry.online(now) {
+			online := controlOutbound(frame{Control: controlPresence, UserID: userID, Role: entry.role, Status: statusOnline}, now.UTC())
+			client.backlog = append(client.backlog, online.frame(client.Protocol))
+		}
+	}
+	if client.UserID == "" {
+		return nil
+	}
+
+	if entries == nil {
+		entries = make(map[string]*presenceEntry)
+		h.presence[client.Topic] = entries
+	}
+	entry := entries[client.UserID]
+	if entry == nil {
+		entry = &presenceEntry{}
+		entries[client.UserID] = entry
+	}
+	entry.role = client.Role
+	entry.local++
+	if entry.local > 1 {
+		return nil
+	}
+	return encodeSignal(controlPresence, client.UserID, client.Role, statusOnline, 0)
+}
+
+// leavePresence records a participant's connection closing. It returns the offline announcement
+// to publish once the participant has no connection left to the instance, unless the instance
+// is draining, in which case the participant is expected to reconnect to another one.
+// Callers must hold h.mu.
+func (h *Hub) leavePresence(client *Client) []byte {
+	if client.UserID == "" {
+		return nil
+	}
+	entry := h.presence[client.Topic][client.UserID]
+	if entry == nil {
+		return nil
+	}
+
+	entry.local--
+	if entry.local > 0 || h.Draining() {
+		return nil
+	}
+	return encodeSignal(controlPresence, client.UserID, client.Role, statusOffline, 0)
+}
+
+// admitSignal updates presence from a signal about to be delivered, reporting whether local
+// clients should receive it. Online announcements are only passed on when they change what the
+// topic's clients see, and an offline announcement is overruled while the participant is still
+// connected to this instance, which re-announces them instead.
+// Callers must hold h.mu.
+func (h *Hub) admitSignal(message Message, now time.Time) bool {
+	signal, ok := decodeSignal(message.Data)
+	if !ok {
+		return false
+	}
+	if signal.Control != controlPresence {
+		return true
+	}
+
+	entries := h.presence[message.Topic]
+	entry := entries[signal.UserID]
+	switch signal.Status {
+	case statusOnline:
+		if entries == nil {
+			entries = make(map[string]*presenceEntry)
+			h.presence[message.Topic] = entries
+		}
+		if entry == nil {
+			entry = &presenceEntry{role: signal.Role}
+			entries[signal.UserID] = entry
+		}
+		announced := !entry.seenAt.IsZero() && now.Sub(entry.seenAt) < presenceTTL
+		entry.seenAt = now
+		return !announced
+
+	case statusOffline:
+		if entry == nil {
+			return false
+		}
+		if entry.local > 0 {
+			h.publishPresence(presenceSignal{
+				topic: message.Topic,
+				data:  encodeSignal(controlPresence, signal.UserID, entry.role, statusOnline, 0),
+			})
+			return false
+		}
+		h.forgetPresence(message.Topic, signal.UserID)
+		return true
+	}
+	return false
+}
+
+// refreshPresence collects the re-announcements of the participants connected to this
+// instance and expires the rest once they have not been announced within presenceTTL,
+// telling local clients they went offline.
+// Callers must hold h.mu.
+func (h *Hub) refreshPresence(now time.Time) []presenceSignal {
+	var announcements []presenceSignal
+	for topic, entries := range h.presence {
+		for userID, entry := range entries {
+			if entry.local > 0 {
+				announcements = append(announcements, presenceSignal{
+					topic: topic,
+					data:  encodeSignal(controlPresence, userID, entry.role, statusOnline, 0),
+				})
+				continue
+			}
+			if entry.online(now) {
+				continue
+			}
+
+			h.forgetPresence(topic, userID)
+			if !entry.seenAt.IsZero() {
+				offline := frame{Control: controlPresence, UserID: userID, Role: entry.role, Status: statusOffline}
+				h.deliver(h.rooms[topic], controlOutbound(offline, now.UTC()))
+			}
+		}
+	}
+	return announcements
+}
+
+// forgetPresence removes a participant from a topic's presence.
+// Callers must hold h.mu.
+func (h *Hub) forgetPresence(topic, userID string) {
+	delete(h.presence[topic], userID)
+	if len(h.presence[topic]) == 0 {
+		delete(h.presence, topic)
+	}
+}
+
+// publishPresence publishes presence announcements from outside the hub loop, which
+// Publish would otherwise block on
+func (h *Hub) publishPresence(signals ...presenceSignal) {
+	if len(signals) == 0 {
+		return
+	}
+	go func() {
+		for _, signal := range signals {
+			h.Publish(signal.topic, KindSignal, string(signal.data))
+		}
+	}()
+}
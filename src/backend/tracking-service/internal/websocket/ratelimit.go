@@ -0,0 +1,188 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"src/backend/tracking-service/internal/metrics"
+)
+
+// Rate limit scopes, as labelled in the rate limited metric
+const (
+	scopeSession    = "session"
+	scopeTenant     = "tenant"
+	scopeConnection = "connection"
+)
+
+// rateLimitIdle is how long a rate limit bucket is kept after its last use
+const rateLimitIdle = time.Minute
+
+// RateLimits bounds the traffic the hub accepts, each in messages per second; zero is unlimited.
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+type RateLimits struct {
+	// SessionRate bounds the messages published to a single walk or chat topic
+	SessionRate float64
+
+	// TenantFanout bounds the client deliveries of messages published to a tenant's topics,
+	// counting one per subscriber
+	TenantFanout float64
+
+	// ConnectionRate bounds the control messages a single client may send
+	ConnectionRate float64
+}
+
+// tokenBucket admits traffic at a sustained rate, with bursts of up to twice that. Traffic
+// larger than a burst is admitted once the bucket is full and leaves it in debt, so a large
+// fan-out is delayed rather than refused outright.
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+
+	// refused counts the messages refused since the bucket last admitted one
+	refused int
+}
+
+// take reports whether n units of traffic are admitted at rate, spending them if so
+func (b *tokenBucket) take(rate, n float64, now time.Time) bool {
+	burst := 2 * rate
+	if b.updated.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	}
+	b.updated = now
+
+	if b.tokens < math.Min(n, burst) {
+		return false
+	}
+	b.tokens -= n
+	return true
+}
+
+// admit takes n units of traffic from the bucket of key, counting and logging breaches: once
+// when key starts being refused, and once when it is admitted again
+func (b *tokenBucket) admit(scope, key string, rate, n float64, now time.Time) bool {
+	if b.take(rate, n, now) {
+		if b.refused > 0 {
+			log.Printf("Rate limit breach ended: %s %s had %d messages refused", scope, key, b.refused)
+			b.refused = 0
+		}
+		return true
+	}
+
+	if b.refused == 0 {
+		log.Printf("Rate limit breached: %s %s exceeded %g messages per second", scope, key, rate)
+	}
+	b.refused++
+	metrics.HubRateLimited.WithLabelValues(scope).Inc()
+	return false
+}
+
+// rateLimiter keeps a token bucket per key of one scope. A nil rateLimiter admits everything.
+type rateLimiter struct {
+	scope string
+	rate  float64
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// newRateLimiter creates a limiter admitting rate messages per second per key, or nil when
+// rate is not positive
+func newRateLimiter(scope string, rate float64) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &rateLimiter{scope: scope, rate: rate, buckets: make(map[string]*tokenBucket)}
+}
+
+// allow reports whether n messages may pass for key
+func (l *rateLimiter) allow(key string, n int, now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{}
+		l.buckets[key] = bucket
+	}
+	return bucket.admit(l.scope, key, l.rate, float64(n), now)
+}
+
+// prune drops the buckets of keys idle for rateLimitIdle
+func (l *rateLimiter) prune(now time.Time) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) < rateLimitIdle {
+			continue
+		}
+		if bucket.refused > 0 {
+			log.Printf("Rate limit breach ended: %s %s had %d messages refused", l.scope, key, bucket.refused)
+		}
+		delete(l.buckets, key)
+	}
+}
+
+// LimitRates sets the rates the hub accepts traffic at. Messages over a limit are dropped,
+// except urgent and session lifecycle messages, which are never limited. Must be called
+// before Run.
+func (h *Hub) LimitRates(limits RateLimits) {
+	h.sessionLimit = newRateLimiter(scopeSession, limits.SessionRate)
+	h.fanoutLimit = newRateLimiter(scopeTenant, limits.TenantFanout)
+	h.connectionRate = limits.ConnectionRate
+}
+
+// TenantOf sets how the hub finds the tenant a topic belongs to for fan-out limits; topics it
+// maps to "" are their own tenant. tenant runs on the hub loop and must not block. Must be
+// called before Run.
+func (h *Hub) TenantOf(tenant func(topic string) string) {
+	h.tenantOf = tenant
+}
+
+// tenant returns the tenant whose fan-out a message to topic counts towards
+func (h *Hub) tenant(topic string) string {
+	if h.tenantOf != nil {
+		if tenant := h.tenantOf(topic); tenant != "" {
+			return tenant
+		}
+	}
+	return topic
+}
+
+// admitPublish reports whether a message of kind may be published to topic
+func (h *Hub) admitPublish(topic, kind string, now time.Time) bool {
+	if topic == "" || isUnlimited(kind) {
+		return true
+	}
+	return h.sessionLimit.allow(topic, 1, now)
+}
+
+// admitFanout reports whether message may be delivered to its targets.
+// Callers must hold h.mu.
+func (h *Hub) admitFanout(message Message, targets int, now time.Time) bool {
+	if message.Topic == "" || targets == 0 || isUnlimited(message.Kind) {
+		return true
+	}
+	return h.fanoutLimit.allow(h.tenant(message.Topic), targets, now)
+}
+
+// admitInbound reports whether a message sent by client may be handled. Only called from the
+// client's read goroutine, which owns its bucket.
+func (h *Hub) admitInbound(client *Client, now time.Time) bool {
+	if h.connectionRate <= 0 {
+		return true
+	}
+	return client.inbound.admit(scopeConnection, client.conn.RemoteAddr().String(), h.connectionRate, 1, now)
+}
@@ -0,0 +1,110 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"time"
+)
+
+const (
+	// defaultReplaySize is the number of messages retained per subscription for resumption
+	defaultReplaySize = 500
+
+	// defaultReplayTTL is how long a message remains available for resumption
+	defaultReplayTTL = 2 * time.Minute
+
+	// evictedMarkTTL is how long a topic remembers the messages it dropped; clients gone
+	// longer than that have long since backfilled from history
+	evictedMarkTTL = 24 * time.Hour
+)
+
+// replayEntry is a buffered message with the time it was delivered
+type replayEntry struct {
+	message     Message
+	deliveredAt time.Time
+}
+
+// replayBuffer retains recently delivered messages per subscription so that
+// reconnecting clients can resume from the last sequence number they saw.
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+type replayBuffer struct {
+	size   int
+	ttl    time.Duration
+	topics map[string][]replayEntry
+
+	// evicted marks the newest message dropped from each topic, so a client resuming from
+	// before it is told it missed messages no longer held
+	evicted map[string]evictedMark
+}
+
+// evictedMark is the sequence number of the newest message dropped from a topic, and when
+type evictedMark struct {
+	seq uint64
+	at  time.Time
+}
+
+// newReplayBuffer creates a replay buffer bounded by size per topic and ttl per message
+func newReplayBuffer(size int, ttl time.Duration) *replayBuffer {
+	return &replayBuffer{
+		size:    size,
+		ttl:     ttl,
+		topics:  make(map[string][]replayEntry),
+		evicted: make(map[string]evictedMark),
+	}
+}
+
+// append records a delivered message for its topic
+func (b *replayBuffer) append(message Message, now time.Time) {
+	entries := append(b.topics[message.Topic], replayEntry{message: message, deliveredAt: now})
+	if len(entries) > b.size {
+		dropped := len(entries) - b.size
+		b.evicted[message.Topic] = evictedMark{seq: entries[dropped-1].message.Seq, at: now}
+		entries = entries[dropped:]
+	}
+	b.topics[message.Topic] = entries
+}
+
+// since returns the buffered messages for topic with a sequence number greater than lastSeq.
+// complete is false when messages after lastSeq have already been evicted.
+func (b *replayBuffer) since(topic string, lastSeq uint64, now time.Time) (messages []Message, complete bool) {
+	complete = lastSeq >= b.evicted[topic].seq
+	for _, entry := range b.topics[topic] {
+		if entry.message.Seq <= lastSeq {
+			continue
+		}
+		if now.Sub(entry.deliveredAt) > b.ttl {
+			complete = false
+			continue
+		}
+		// Sequence numbers are contiguous per topic, so a hole before the first
+		// replayable message means the client missed points we no longer hold
+		if len(messages) == 0 && entry.message.Seq != lastSeq+1 {
+			complete = false
+		}
+		messages = append(messages, entry.message)
+	}
+	return messages, complete
+}
+
+// prune discards expired messages and topics with nothing left to replay
+func (b *replayBuffer) prune(now time.Time) {
+	for topic, mark := range b.evicted {
+		if now.Sub(mark.at) > evictedMarkTTL {
+			delete(b.evicted, topic)
+		}
+	}
+	for topic, entries := range b.topics {
+		i := 0
+		for i < len(entries) && now.Sub(entries[i].deliveredAt) > b.ttl {
+			i++
+		}
+		if i > 0 {
+			b.evicted[topic] = evictedMark{seq: entries[i-1].message.Seq, at: now}
+		}
+		if i == len(entries) {
+			delete(b.topics, topic)
+			continue
+		}
+		b.topics[topic] = entries[i:]
+	}
+}
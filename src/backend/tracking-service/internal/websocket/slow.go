@@ -0,0 +1,186 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"src/backend/tracking-service/internal/metrics"
+)
+
+// SlowConsumerPolicy is what the hub does with a client whose send buffer is full
+type SlowConsumerPolicy string
+
+// Slow consumer policies selectable with HandleSlowConsumers
+const (
+	// SlowConsumerDisconnect closes the connection with CloseSlowConsumer; the default
+	SlowConsumerDisconnect SlowConsumerPolicy = "disconnect-with-code"
+
+	// SlowConsumerDropOldest discards the oldest buffered message to make room for the newest
+	SlowConsumerDropOldest SlowConsumerPolicy = "drop-oldest"
+
+	// SlowConsumerDigest holds only the latest message of each topic for the client, flushing
+	// them every digestInterval until it catches up and receives the full stream again
+	SlowConsumerDigest SlowConsumerPolicy = "downgrade-to-digest"
+)
+
+// CloseSlowConsumer is the close code sent to clients disconnected for not keeping up; they
+// may reconnect and resume from their last sequence number
+const CloseSlowConsumer = 4008
+
+const (
+	// digestInterval is how often downgraded clients are sent the latest message of each topic
+	digestInterval = time.Second
+
+	// recentSlowConsumers bounds the slow consumer events kept for the hub status
+	recentSlowConsumers = 50
+)
+
+// SlowConsumer is the event emitted when a client starts falling behind, naming the app it
+// runs so that chronically slow clients can be found
+type SlowConsumer struct {
+	App    string             `json:"app"`
+	UserID string             `json:"user_id,omitempty"`
+	Topic  string             `json:"topic,omitempty"`
+	Policy SlowConsumerPolicy `json:"policy"`
+
+	// Episodes counts the times this connection has fallen behind, this one included
+	Episodes int `json:"episodes"`
+
+	// ConnectedFor is how long the connection had been open, in seconds
+	ConnectedFor float64 `json:"connected_for"`
+
+	At time.Time `json:"at"`
+}
+
+// HandleSlowConsumers sets what the hub does with clients whose send buffers are full.
+// Must be called before Run.
+func (h *Hub) HandleSlowConsumers(policy SlowConsumerPolicy) {
+	h.slowPolicy = policy
+}
+
+// queueFull applies the slow consumer policy to a client whose queue has no room for frame.
+// Callers must hold h.mu.
+func (h *Hub) queueFull(client *Client, queue chan []byte, frame []byte, topic string) {
+	h.slowConsumer(client, time.Now())
+
+	switch {
+	case h.slowPolicy == SlowConsumerDigest && queue == client.send && topic != "":
+		if client.digest == nil {
+			client.digest = make(map[string][]byte)
+			h.downgraded[client] = true
+			log.Printf("Downgraded slow client to digest: app=%q user=%q topic=%q", client.App, client.UserID, client.Topic)
+		}
+		h.queueDigest(client, topic, frame)
+
+	case h.slowPolicy == SlowConsumerDigest || h.slowPolicy == SlowConsumerDropOldest:
+		// Only the hub loop sends to the queue, so a taken slot stays free for the new frame
+		select {
+		case <-queue:
+			h.drop(dropOldest)
+		default:
+		}
+		select {
+		case queue <- frame:
+		default:
+			h.drop(dropOldest)
+		}
+
+	default:
+		// Client is not keeping up; drop the connection
+		log.Printf("Error broadcasting message to client: send buffer full")
+		h.drop(dropSendBufferFull)
+		h.disconnect(client, CloseSlowConsumer, "slow consumer")
+	}
+}
+
+// queueDigest replaces the frame a downgraded client holds for topic.
+// Callers must hold h.mu.
+func (h *Hub) queueDigest(client *Client, topic string, frame []byte) {
+	if _, ok := client.digest[topic]; ok {
+		h.drop(dropCoalesced)
+	}
+	client.digest[topic] = frame
+}
+
+// flushDigests sends each downgraded client the latest frame of every topic it holds, as far
+// as its buffer allows, and restores the full stream to clients that have caught up.
+// Callers must hold h.mu.
+func (h *Hub) flushDigests() {
+	for client := range h.downgraded {
+		for topic, frame := range client.digest {
+			select {
+			case client.send <- frame:
+				delete(client.digest, topic)
+			default:
+			}
+		}
+
+		if len(client.digest) == 0 && len(client.send) <= cap(client.send)/4 {
+			client.digest = nil
+			client.slow = false
+			delete(h.downgraded, client)
+			log.Printf("Restored full stream to client: app=%q user=%q topic=%q", client.App, client.UserID, client.Topic)
+		}
+	}
+}
+
+// slowConsumer emits the slow consumer event when a client starts falling behind, once per
+// episode.
+// Callers must hold h.mu.
+func (h *Hub) slowConsumer(client *Client, now time.Time) {
+	if client.slow {
+		return
+	}
+	client.slow = true
+	client.slowEpisodes++
+
+	event := SlowConsumer{
+		App:          client.App,
+		UserID:       client.UserID,
+		Topic:        client.Topic,
+		Policy:       h.slowPolicy,
+		Episodes:     client.slowEpisodes,
+		ConnectedFor: now.Sub(client.connectedAt).Seconds(),
+		At:           now.UTC(),
+	}
+	log.Printf("Slow consumer: app=%q user=%q topic=%q policy=%s episodes=%d connected_for=%.0fs",
+		event.App, event.UserID, event.Topic, event.Policy, event.Episodes, event.ConnectedFor)
+	metrics.HubSlowConsumers.WithLabelValues(appName(client.App), string(h.slowPolicy)).Inc()
+
+	h.slowConsumers = append(h.slowConsumers, event)
+	if len(h.slowConsumers) > recentSlowConsumers {
+		h.slowConsumers = h.slowConsumers[len(h.slowConsumers)-recentSlowConsumers:]
+	}
+}
+
+// caughtUp ends a client's slow episode once its queue has drained to half.
+// Callers must hold h.mu.
+func (h *Hub) caughtUp(client *Client, queue chan []byte) {
+	if client.slow && client.digest == nil && len(queue) <= cap(queue)/2 {
+		client.slow = false
+	}
+}
+
+// disconnect removes a client from the hub, closing its connection with code.
+// Callers must hold h.mu.
+func (h *Hub) disconnect(client *Client, code int, reason string) {
+	client.closeCode, client.closeReason = code, reason
+	h.removeClient(client)
+}
+
+// appName reduces a client's user agent to its product name, for metric labels
+func appName(userAgent string) string {
+	name := userAgent
+	if i := strings.IndexAny(name, "/ ;("); i >= 0 {
+		name = name[:i]
+	}
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
@@ -0,0 +1,238 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"src/backend/tracking-service/internal/metrics"
+)
+
+const (
+	// statsInterval is how often the hub refreshes its gauges and starts a new latency window
+	statsInterval = 5 * time.Second
+
+	// statusRoomLimit and statusClientLimit bound the rooms and backlogged clients listed in a
+	// status report, largest first
+	statusRoomLimit   = 100
+	statusClientLimit = 50
+)
+
+// Reasons the hub drops messages, as labelled in the dropped messages metric
+const (
+	dropSendBufferFull   = "send_buffer_full"
+	dropUnregisteredKind = "unregistered_kind"
+	dropOldest           = "drop_oldest"
+	dropCoalesced        = "coalesced"
+	dropThrottled        = "throttled"
+	dropHubStopped       = "hub_stopped"
+	dropFaultInjected    = "fault_injected"
+)
+
+// newDropCounters creates a counter for every reason the hub drops messages
+func newDropCounters() map[string]*atomic.Uint64 {
+	return map[string]*atomic.Uint64{
+		dropSendBufferFull:   new(atomic.Uint64),
+		dropUnregisteredKind: new(atomic.Uint64),
+		dropOldest:           new(atomic.Uint64),
+		dropCoalesced:        new(atomic.Uint64),
+		dropThrottled:        new(atomic.Uint64),
+		dropHubStopped:       new(atomic.Uint64),
+		dropFaultInjected:    new(atomic.Uint64),
+	}
+}
+
+// HubStatus is a snapshot of the hub's internals for debugging stuck streams
+type HubStatus struct {
+	InstanceID string `json:"instance_id"`
+	Draining   bool   `json:"draining"`
+	Clients    int    `json:"clients"`
+
+	// RoomCount is the number of topics with subscribers; Rooms lists the largest of them
+	RoomCount int          `json:"room_count"`
+	Rooms     []RoomStatus `json:"rooms"`
+
+	// QueuedMessages is the number of messages waiting in every client's buffers; Backlogged
+	// lists the clients with the most waiting
+	QueuedMessages int            `json:"queued_messages"`
+	Backlogged     []ClientStatus `json:"backlogged"`
+
+	// Dropped counts the messages dropped since the hub started, by reason
+	Dropped map[string]uint64 `json:"dropped"`
+
+	// SlowConsumers lists the most recent slow consumer events, newest first
+	SlowConsumers []SlowConsumer `json:"slow_consumers"`
+
+	Broadcast BroadcastStats `json:"broadcast"`
+}
+
+// RoomStatus describes the subscribers of one topic
+type RoomStatus struct {
+	Topic       string `json:"topic"`
+	Subscribers int    `json:"subscribers"`
+}
+
+// ClientStatus describes one connection and the messages waiting to be written to it
+type ClientStatus struct {
+	Topic         string    `json:"topic,omitempty"`
+	App           string    `json:"app,omitempty"`
+	UserID        string    `json:"user_id,omitempty"`
+	Role          string    `json:"role,omitempty"`
+	Dispatch      bool      `json:"dispatch,omitempty"`
+	Subscriptions int       `json:"subscriptions,omitempty"`
+	Protocol      int       `json:"protocol"`
+	ConnectedAt   time.Time `json:"connected_at"`
+
+	// Queued and Priority are the messages waiting in the client's send and urgent buffers
+	Queued   int `json:"queued"`
+	Priority int `json:"priority"`
+
+	// Digest is set while the client is downgraded to the latest message of each topic
+	Digest bool `json:"digest,omitempty"`
+}
+
+// BroadcastStats describes the messages the hub delivered: all of them since it started, and
+// how long those of the last statsInterval took from publication to reaching client buffers
+type BroadcastStats struct {
+	Messages      uint64  `json:"messages"`
+	WindowSeconds float64 `json:"window_seconds"`
+	WindowCount   int     `json:"window_messages"`
+	MeanLatencyMs float64 `json:"mean_latency_ms"`
+	MaxLatencyMs  float64 `json:"max_latency_ms"`
+}
+
+// latencyWindow accumulates broadcast latencies over one statsInterval
+type latencyWindow struct {
+	count int
+	total time.Duration
+	max   time.Duration
+}
+
+// observeBroadcast records the latency of a message published at published that has just
+// reached its clients' buffers.
+// Callers must hold h.mu.
+func (h *Hub) observeBroadcast(published time.Time, now time.Time) {
+	h.broadcasts++
+	if published.IsZero() {
+		// Relayed by an instance that predates publication times
+		return
+	}
+
+	latency := now.Sub(published)
+	if latency < 0 {
+		latency = 0
+	}
+	metrics.HubBroadcastLatency.Observe(latency.Seconds())
+	h.latency.count++
+	h.latency.total += latency
+	if latency > h.latency.max {
+		h.latency.max = latency
+	}
+}
+
+// drop counts a message dropped for reason
+func (h *Hub) drop(reason string) {
+	h.dropped[reason].Add(1)
+	metrics.HubDropped.WithLabelValues(reason).Inc()
+}
+
+// refreshStats updates the hub's gauges, samples every client's send queue depth and starts a
+// new latency window.
+// Callers must hold h.mu.
+func (h *Hub) refreshStats() {
+	subscriptions := 0
+	for _, room := range h.rooms {
+		subscriptions += len(room)
+	}
+	for client := range h.Clients {
+		metrics.HubSendQueueDepth.Observe(float64(len(client.send)))
+	}
+	metrics.HubClients.Set(float64(len(h.Clients)))
+	metrics.HubRooms.Set(float64(len(h.rooms)))
+	metrics.HubSubscriptions.Set(float64(subscriptions))
+
+	h.lastLatency, h.latency = h.latency, latencyWindow{}
+}
+
+// Status returns a snapshot of the hub's connections, rooms, buffers and delivery statistics
+func (h *Hub) Status() HubStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	status := HubStatus{
+		InstanceID:    h.instanceID,
+		Draining:      h.Draining(),
+		Clients:       len(h.Clients),
+		RoomCount:     len(h.rooms),
+		Rooms:         make([]RoomStatus, 0, len(h.rooms)),
+		Backlogged:    []ClientStatus{},
+		Dropped:       make(map[string]uint64, len(h.dropped)),
+		SlowConsumers: make([]SlowConsumer, 0, len(h.slowConsumers)),
+		Broadcast: BroadcastStats{
+			Messages:      h.broadcasts,
+			WindowSeconds: statsInterval.Seconds(),
+			WindowCount:   h.lastLatency.count,
+			MaxLatencyMs:  milliseconds(h.lastLatency.max),
+		},
+	}
+	for reason, count := range h.dropped {
+		status.Dropped[reason] = count.Load()
+	}
+	for i := len(h.slowConsumers) - 1; i >= 0; i-- {
+		status.SlowConsumers = append(status.SlowConsumers, h.slowConsumers[i])
+	}
+	if h.lastLatency.count > 0 {
+		status.Broadcast.MeanLatencyMs = milliseconds(h.lastLatency.total / time.Duration(h.lastLatency.count))
+	}
+
+	for topic, room := range h.rooms {
+		status.Rooms = append(status.Rooms, RoomStatus{Topic: topic, Subscribers: len(room)})
+	}
+	sort.Slice(status.Rooms, func(i, j int) bool {
+		if status.Rooms[i].Subscribers != status.Rooms[j].Subscribers {
+			return status.Rooms[i].Subscribers > status.Rooms[j].Subscribers
+		}
+		return status.Rooms[i].Topic < status.Rooms[j].Topic
+	})
+	if len(status.Rooms) > statusRoomLimit {
+		status.Rooms = status.Rooms[:statusRoomLimit]
+	}
+
+	for client := range h.Clients {
+		queued, priority := len(client.send), len(client.priority)
+		status.QueuedMessages += queued + priority
+		if queued+priority == 0 && client.digest == nil {
+			continue
+		}
+		status.Backlogged = append(status.Backlogged, ClientStatus{
+			Topic:         client.Topic,
+			App:           client.App,
+			UserID:        client.UserID,
+			Role:          client.Role,
+			Dispatch:      client.Dispatch,
+			Subscriptions: len(client.subscriptions),
+			Protocol:      client.Protocol,
+			ConnectedAt:   client.connectedAt,
+			Queued:        queued,
+			Priority:      priority,
+			Digest:        client.digest != nil,
+		})
+	}
+	sort.Slice(status.Backlogged, func(i, j int) bool {
+		a, b := status.Backlogged[i], status.Backlogged[j]
+		if a.Queued+a.Priority != b.Queued+b.Priority {
+			return a.Queued+a.Priority > b.Queued+b.Priority
+		}
+		return a.ConnectedAt.Before(b.ConnectedAt)
+	})
+	if len(status.Backlogged) > statusClientLimit {
+		status.Backlogged = status.Backlogged[:statusClientLimit]
+	}
+	return status
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,152 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxSubscriptions bounds the bookings and channels a single dispatch connection follows
+const maxSubscriptions = 200
+
+// ErrSubscriptionsUnavailable is reported to dispatch clients when the hub cannot resolve bookings
+var ErrSubscriptionsUnavailable = errors.New("subscriptions are unavailable")
+
+// subscriptionKey names what a dispatch client follows: a booking's walk or a channel
+type subscriptionKey struct {
+	bookingID string
+	channel   string
+}
+
+// subscription is a dispatch client's request to start or stop following a booking or channel,
+// resolved on the client's read goroutine and applied by the hub loop
+type subscription struct {
+	client *Client
+	action string
+	key    subscriptionKey
+
+	// topic is the booking's walk or the channel's topic, resolved for a subscribe
+	topic string
+
+	// interval is the update interval a subscribe asks for
+	interval time.Duration
+
+	// err is reported back instead of applying the request
+	err error
+}
+
+// ResolveBookings sets how the hub finds the topic a booking's walk is published to when a
+// dispatch client subscribes to it. resolve runs outside the hub loop and may block; the
+// errors it returns are reported to the client. Must be called before Run.
+func (h *Hub) ResolveBookings(resolve func(bookingID string) (string, error)) {
+	h.resolveBooking = resolve
+}
+
+// Channel registers a channel dispatch clients may subscribe to by name, delivering the
+// messages published to topic. Must be called before Run.
+func (h *Hub) Channel(name, topic string) {
+	h.channels[name] = topic
+}
+
+// requestSubscription resolves a subscribe or unsubscribe sent by a client and hands it to
+// the hub loop, which acknowledges it
+func (h *Hub) requestSubscription(client *Client, request frame) {
+	change := subscription{
+		client: client,
+		action: request.Control,
+		key:    subscriptionKey{bookingID: request.BookingID, channel: request.Channel},
+
+		interval: time.Duration(request.IntervalMs) * time.Millisecond,
+	}
+	switch {
+	case !client.Dispatch:
+		change.err = errors.New("subscriptions are only available to dispatch connections")
+	case (request.BookingID == "") == (request.Channel == ""):
+		change.err = errors.New("one of booking_id or channel is required")
+	case !validInterval(change.interval):
+		change.err = fmt.Errorf("interval_ms must be between 0 and %d", MaxUpdateInterval.Milliseconds())
+	case request.Channel != "":
+		var ok bool
+		if change.topic, ok = h.channels[request.Channel]; !ok {
+			change.err = fmt.Errorf("unknown channel %q", request.Channel)
+		}
+	case request.Control == controlSubscribe && h.resolveBooking == nil:
+		change.err = ErrSubscriptionsUnavailable
+	case request.Control == controlSubscribe:
+		change.topic, change.err = h.resolveBooking(request.BookingID)
+	}
+	select {
+	case h.subscriptions <- change:
+	case <-h.done:
+	}
+}
+
+// applySubscription joins or leaves the room of a subscription change and acknowledges it
+// to the client. The acknowledgement of a subscribe reaches the client before anything
+// published to the subscription afterwards.
+// Callers must hold h.mu.
+func (h *Hub) applySubscription(change subscription, now time.Time) {
+	client := change.client
+	if _, ok := h.Clients[client]; !ok {
+		// The client disconnected while the request was being resolved
+		return
+	}
+
+	ack := frame{Control: controlAck, Action: change.action, BookingID: change.key.bookingID, Channel: change.key.channel}
+	current, subscribed := client.subscriptions[change.key]
+	switch {
+	case change.err != nil:
+		ack.Error = change.err.Error()
+
+	case change.action == controlSubscribe && !subscribed && len(client.subscriptions) >= maxSubscriptions:
+		ack.Error = "too many subscriptions"
+
+	case change.action == controlSubscribe:
+		// A booking walked again since it was subscribed to moves to its new walk
+		if subscribed && current != change.topic {
+			h.leaveRoom(client, current)
+			h.throttle(client, current, 0)
+		}
+		if client.subscriptions == nil {
+			client.subscriptions = make(map[subscriptionKey]string)
+		}
+		client.subscriptions[change.key] = change.topic
+		h.joinRoom(client, change.topic)
+		h.throttle(client, change.topic, change.interval)
+		ack.Topic = change.topic
+		ack.IntervalMs = change.interval.Milliseconds()
+
+	case change.action == controlUnsubscribe && subscribed:
+		delete(client.subscriptions, change.key)
+		if current != client.Topic {
+			h.leaveRoom(client, current)
+			h.throttle(client, current, 0)
+		}
+		ack.Topic = current
+	}
+
+	h.deliver(map[*Client]bool{client: true}, controlOutbound(ack, now.UTC()))
+}
+
+// joinRoom adds a client to the room of topic.
+// Callers must hold h.mu.
+func (h *Hub) joinRoom(client *Client, topic string) {
+	if h.rooms[topic] == nil {
+		h.rooms[topic] = make(map[*Client]bool)
+	}
+	h.rooms[topic][client] = true
+}
+
+// leaveRoom removes a client from the room of topic, dropping the room once it is empty.
+// Callers must hold h.mu.
+func (h *Hub) leaveRoom(client *Client, topic string) {
+	room, ok := h.rooms[topic]
+	if !ok {
+		return
+	}
+	delete(room, client)
+	if len(room) == 0 {
+		delete(h.rooms, topic)
+	}
+}
@@ -0,0 +1,101 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"time"
+)
+
+const (
+	// MaxUpdateInterval is the longest update interval a subscriber may ask for
+	MaxUpdateInterval = time.Minute
+
+	// throttleResolution is how often held back messages are checked for being due
+	throttleResolution = 250 * time.Millisecond
+)
+
+// throttle limits a subscription to one message of coalesced kinds per interval. The latest
+// message published while the subscription waits is held back and sent once the interval
+// has passed; the ones it replaced are skipped, so a phone that only needs an update every
+// few seconds is not woken for each point.
+type throttle struct {
+	interval time.Duration
+
+	// next is when the subscription may be sent its next message
+	next time.Time
+
+	// pending is the latest frame held back until next; nil when there is none
+	pending []byte
+}
+
+// validInterval reports whether interval is an update interval subscribers may ask for;
+// zero asks for every message
+func validInterval(interval time.Duration) bool {
+	return interval >= 0 && interval <= MaxUpdateInterval
+}
+
+// throttle sets the update interval of a client's subscription to topic; zero removes it.
+// Callers must hold h.mu.
+func (h *Hub) throttle(client *Client, topic string, interval time.Duration) {
+	if interval <= 0 {
+		delete(client.throttles, topic)
+		return
+	}
+	if client.throttles == nil {
+		client.throttles = make(map[string]*throttle)
+	}
+	if t, ok := client.throttles[topic]; ok {
+		t.interval = interval
+		return
+	}
+	client.throttles[topic] = &throttle{interval: interval}
+}
+
+// holdBack reports whether a frame of a coalesced kind is held back from a throttled
+// subscription, replacing any frame already waiting. A subscription whose interval has
+// passed with nothing waiting is sent the frame straight away.
+// Callers must hold h.mu.
+func (h *Hub) holdBack(client *Client, topic string, frame []byte, now time.Time) bool {
+	t, ok := client.throttles[topic]
+	if !ok {
+		return false
+	}
+	if t.pending == nil && !now.Before(t.next) {
+		t.next = now.Add(t.interval)
+		return false
+	}
+
+	if t.pending != nil {
+		h.drop(dropThrottled)
+	}
+	t.pending = frame
+	h.throttled[client] = true
+	return true
+}
+
+// flushThrottled sends each throttled subscription the frame it holds back once its interval
+// has passed. A frame that does not fit in the client's buffer waits for the next flush.
+// Callers must hold h.mu.
+func (h *Hub) flushThrottled(now time.Time) {
+	for client := range h.throttled {
+		waiting := false
+		for _, t := range client.throttles {
+			if t.pending == nil {
+				continue
+			}
+			if now.Before(t.next) {
+				waiting = true
+				continue
+			}
+			select {
+			case client.send <- t.pending:
+				t.pending = nil
+				t.next = now.Add(t.interval)
+			default:
+				waiting = true
+			}
+		}
+		if !waiting {
+			delete(h.throttled, client)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package websocket implements the WebSocket hub for real-time communication
+package websocket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned when a connection token is malformed or its signature does not match
+	ErrInvalidToken = errors.New("invalid connection token")
+
+	// ErrTokenExpired is returned when a connection token is past its expiry
+	ErrTokenExpired = errors.New("connection token expired")
+)
+
+// ConnectionToken is the signed claim a client presents when opening a WebSocket.
+// Because the subscription is carried in the token itself, any instance can serve
+// any client without sticky sessions or shared connection state.
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+type ConnectionToken struct {
+	// Topic is the subscription the connection receives messages for
+	Topic string `json:"topic"`
+
+	// UserID and Role identify the participant holding a chat connection, whose presence and
+	// typing are shown to the others on the topic; empty for walk subscriptions
+	UserID string `json:"uid,omitempty"`
+	Role   string `json:"role,omitempty"`
+
+	// Dispatch marks a dispatcher connection, which starts with no topic and subscribes to
+	// bookings with control messages
+	Dispatch bool `json:"dispatch,omitempty"`
+
+	// ExpiresAt is the Unix time after which the token is rejected
+	ExpiresAt int64 `json:"exp"`
+}
+
+// IssueToken creates a signed connection token for topic, valid for ttl
+func IssueToken(secret []byte, topic string, ttl time.Duration) (string, error) {
+	return issueToken(secret, ConnectionToken{Topic: topic}, ttl)
+}
+
+// IssueParticipantToken creates a signed connection token for topic, valid for ttl, that
+// announces userID in role to the topic's other participants
+func IssueParticipantToken(secret []byte, topic, userID, role string, ttl time.Duration) (string, error) {
+	return issueToken(secret, ConnectionToken{Topic: topic, UserID: userID, Role: role}, ttl)
+}
+
+// IssueDispatchToken creates a signed connection token for a dispatcher connection, valid for ttl
+func IssueDispatchToken(secret []byte, ttl time.Duration) (string, error) {
+	return issueToken(secret, ConnectionToken{Dispatch: true}, ttl)
+}
+
+// issueToken signs claims, setting their expiry ttl from now
+func issueToken(secret []byte, claims ConnectionToken, ttl time.Duration) (string, error) {
+	claims.ExpiresAt = time.Now().Add(ttl).Unix()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode connection token: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(secret, encoded), nil
+}
+
+// ParseToken verifies a connection token's signature and expiry and returns its claims
+func ParseToken(secret []byte, token string) (*ConnectionToken, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(sign(secret, parts[0])), []byte(parts[1])) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims ConnectionToken
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
+
+// sign returns the base64url-encoded HMAC-SHA256 of data
+func sign(secret []byte, data string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,136 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"              // v1.6.1
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/cli"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// runCommand runs an admin CLI command with args, answering its prompts with input, and
+// returns what it printed
+func runCommand(cmd *cobra.Command, input string, args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SilenceUsage = true
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+// TestAdminCLIPurgeLocations verifies purge-locations asks before deleting, and then deletes
+// only the points recorded before the cutoff
+func TestAdminCLIPurgeLocations(t *testing.T) {
+	repository.UseMemoryStore()
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, age := range []time.Duration{72 * time.Hour, 48 * time.Hour, time.Hour} {
+		location := models.NewLocation(37.77, -122.42+float64(i)*0.001, now.Add(-age))
+		location.SessionID = "purge-walk"
+		require.NoError(t, repository.InsertLocation(*location))
+	}
+	remaining := func() int {
+		locations, err := repository.FindLocationsBySession("purge-walk")
+		require.NoError(t, err)
+		return len(locations)
+	}
+	cutoff := now.Add(-24 * time.Hour).Format(time.RFC3339)
+
+	out, err := runCommand(cli.PurgeLocationsCommand(), "n\n", "--before", cutoff)
+	assert.Error(t, err)
+	assert.Contains(t, out, "Delete every location point recorded before "+cutoff)
+	assert.Equal(t, 3, remaining(), "nothing is deleted without confirmation")
+
+	out, err = runCommand(cli.PurgeLocationsCommand(), "y\n", "--before", cutoff)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Deleted 2 location points")
+	assert.Equal(t, 1, remaining())
+
+	out, err = runCommand(cli.PurgeLocationsCommand(), "", "--before", "30m", "--yes")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Deleted 1 location points")
+	assert.Equal(t, 0, remaining())
+
+	for _, before := range []string{"", "yesterday", "-1h"} {
+		_, err := runCommand(cli.PurgeLocationsCommand(), "", "--before", before, "--yes")
+		assert.Error(t, err, before)
+	}
+}
+
+// TestAdminCLIReplayWalk verifies replay-walk starts a new session for the stored walk's
+// booking, posts each of its points to it in order and then ends it
+func TestAdminCLIReplayWalk(t *testing.T) {
+	repository.UseMemoryStore()
+	session := models.NewSession("replayed-walk", "replayed-booking", "walker-1", "owner-1")
+	require.NoError(t, repository.InsertSession(*session))
+	started := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		location := models.NewLocation(37.77+float64(i)*0.001, -122.42, started.Add(time.Duration(i)*5*time.Second))
+		location.SessionID = session.ID
+		require.NoError(t, repository.InsertLocation(*location))
+	}
+
+	var mu sync.Mutex
+	var calls []string
+	var points []models.Location
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/api/v1/walks":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "replayed-booking", body["booking_id"])
+			json.NewEncoder(w).Encode(models.NewSession("replay-session", body["booking_id"], body["walker_id"], body["owner_id"]))
+		case "/api/v1/location/track":
+			var point models.Location
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&point))
+			points = append(points, point)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	out, err := runCommand(cli.ReplayWalkCommand(), "", session.ID, "--url", server.URL, "--speed", "1000")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Replaying 3 points of replayed-walk as session replay-session")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"POST /api/v1/walks",
+		"POST /api/v1/location/track",
+		"POST /api/v1/location/track",
+		"POST /api/v1/location/track",
+		"POST /api/v1/walks/replay-session/end",
+	}, calls)
+	require.Len(t, points, 3)
+	for i, point := range points {
+		assert.Equal(t, "replay-session", point.SessionID)
+		assert.InDelta(t, 37.77+float64(i)*0.001, point.Latitude, 1e-9)
+		assert.True(t, point.Timestamp.After(started.Add(time.Minute)), "points are replayed as recorded now")
+	}
+
+	_, err = runCommand(cli.ReplayWalkCommand(), "", session.ID, "--url", server.URL, "--speed", "0")
+	assert.Error(t, err)
+	_, err = runCommand(cli.ReplayWalkCommand(), "", "missing-walk", "--url", server.URL)
+	assert.Error(t, err)
+}
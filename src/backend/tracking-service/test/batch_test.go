@@ -0,0 +1,108 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// batchPoint is the nth point of a walk, a second after the one before
+func batchPoint(sessionID string, n int) models.Location {
+	return models.Location{
+		SessionID: sessionID,
+		Latitude:  51.5,
+		Longitude: -0.12,
+		Timestamp: time.Date(2026, time.May, 4, 9, 0, n, 0, time.UTC),
+	}
+}
+
+// storedPoints is how many points of the walk have been written
+func storedPoints(t *testing.T, sessionID string) int {
+	t.Helper()
+	stored, err := repository.FindLocationsBySession(sessionID)
+	require.NoError(t, err)
+	return len(stored)
+}
+
+// TestBatchWriterFlushes checks that buffered points are written once a batch fills up, once
+// the flush interval passes with a partial batch, and in full when the writer is stopped
+func TestBatchWriterFlushes(t *testing.T) {
+	repository.UseMemoryStore()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// An interval too long to pass leaves only the batch size to flush on
+	bySize := repository.NewBatchWriter(3, time.Hour)
+	bySize.Start()
+	for n := 0; n < 2; n++ {
+		require.NoError(t, bySize.Enqueue(batchPoint("batch-size-walk", n)))
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, storedPoints(t, "batch-size-walk"), "a partial batch waits for the interval")
+	require.NoError(t, bySize.Enqueue(batchPoint("batch-size-walk", 2)))
+	require.Eventually(t, func() bool { return storedPoints(t, "batch-size-walk") == 3 }, 2*time.Second, 10*time.Millisecond)
+
+	// What is left on stop is written before Stop returns
+	for n := 3; n < 5; n++ {
+		require.NoError(t, bySize.Enqueue(batchPoint("batch-size-walk", n)))
+	}
+	require.NoError(t, bySize.Stop(ctx))
+	assert.Equal(t, 5, storedPoints(t, "batch-size-walk"))
+
+	byInterval := repository.NewBatchWriter(100, 20*time.Millisecond)
+	byInterval.Start()
+	require.NoError(t, byInterval.Enqueue(batchPoint("batch-interval-walk", 0)))
+	require.Eventually(t, func() bool { return storedPoints(t, "batch-interval-walk") == 1 }, 2*time.Second, 10*time.Millisecond)
+	require.NoError(t, byInterval.Stop(ctx))
+}
+
+// TestBatchWriterStopReleasesEnqueue checks that stopping a writer whose queue is full returns
+// by its deadline, turning away the point waiting for room, and that the queued points are
+// still drained once the flush loop runs
+func TestBatchWriterStopReleasesEnqueue(t *testing.T) {
+	repository.UseMemoryStore()
+
+	// Without its flush loop running the writer's queue, two batches deep, fills up
+	writer := repository.NewBatchWriter(1, time.Hour)
+	require.NoError(t, writer.Enqueue(batchPoint("batch-full-walk", 0)))
+	require.NoError(t, writer.Enqueue(batchPoint("batch-full-walk", 1)))
+	assert.Equal(t, repository.BatchStatus{Queued: 2, QueueCapacity: 2, BatchSize: 1, FlushIntervalMs: 3600000}, writer.Status(),
+		"the status published on the admin port shows the queue is full")
+	assert.Equal(t, repository.BatchStatus{}, repository.LocationWriterStatus(), "the memory store writes points directly")
+
+	blocked := make(chan error, 1)
+	go func() { blocked <- writer.Enqueue(batchPoint("batch-full-walk", 2)) }()
+	select {
+	case err := <-blocked:
+		t.Fatalf("Enqueue returned %v on a full queue", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, writer.Stop(ctx), context.DeadlineExceeded, "Stop gives up at its deadline")
+	select {
+	case err := <-blocked:
+		assert.ErrorIs(t, err, repository.ErrWriterClosed)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Enqueue stayed blocked after Stop")
+	}
+	assert.ErrorIs(t, writer.Enqueue(batchPoint("batch-full-walk", 3)), repository.ErrWriterClosed)
+
+	// The flush loop drains the closed queue and returns
+	writer.Start()
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer drainCancel()
+	require.NoError(t, writer.Stop(drainCtx))
+	assert.Equal(t, 2, storedPoints(t, "batch-full-walk"))
+}
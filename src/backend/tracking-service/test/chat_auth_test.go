@@ -0,0 +1,188 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"        // v4.5.0
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/shared/clients"
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/handlers"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// testJWTSecret signs the user tokens of the tests calling authenticated endpoints
+const testJWTSecret = "tracking-test-secret"
+
+// userToken signs a bearer token for a user with the given role
+func userToken(t *testing.T, id, role string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{ID: id, Role: role}).
+		SignedString([]byte(testJWTSecret))
+	require.NoError(t, err)
+	return token
+}
+
+// callAs sends a JSON request to handler as the holder of token, or anonymously when it is empty
+func callAs(handler http.HandlerFunc, token, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+// startBookingDirectory serves bookings from a fake booking-service, requiring apiKey, and
+// initializes the service to look participants up there
+func startBookingDirectory(t *testing.T, apiKey string, bookings ...clients.Booking) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(clients.APIKeyHeader) != apiKey {
+			http.Error(w, "invalid API key", http.StatusUnauthorized)
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/bookings/")
+		for _, booking := range bookings {
+			if booking.ID == id {
+				json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": booking})
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{
+		TokenSecret:         "participant-secret",
+		TokenTTL:            time.Minute,
+		MaxAccuracyMeters:   100,
+		ChatRetention:       90 * 24 * time.Hour,
+		ChatReportRetention: 365 * 24 * time.Hour,
+		BookingsURL:         server.URL,
+		BookingsAPIKey:      apiKey,
+	}, hub)
+	go hub.Run()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		assert.NoError(t, service.Stop(ctx))
+		assert.NoError(t, hub.Stop(ctx))
+	})
+}
+
+// TestBookingChatRequiresParticipant checks that only a booking's owner and walker can chat or
+// be issued a chat token, and that they always act as themselves whatever the body says
+func TestBookingChatRequiresParticipant(t *testing.T) {
+	startBookingDirectory(t, "chat-key", clients.Booking{ID: "auth-booking", OwnerID: "auth-owner", WalkerID: "auth-walker"})
+
+	send := auth.Require(testJWTSecret, policy.ResourceMessages, policy.ActionCreate)(handlers.BookingMessagesHandler)
+	read := auth.Require(testJWTSecret, policy.ResourceMessages, policy.ActionRead)(handlers.BookingMessagesHandler)
+	tokens := auth.Require(testJWTSecret, policy.ResourceLocations, policy.ActionRead)(handlers.IssueConnectionTokenHandler)
+	owner := userToken(t, "auth-owner", policy.RoleOwner)
+	walker := userToken(t, "auth-walker", policy.RoleWalker)
+	messages := "/api/v1/bookings/auth-booking/messages"
+
+	rec := callAs(send, "", http.MethodPost, messages, map[string]string{"body": "Hi"})
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = callAs(send, userToken(t, "auth-client", policy.RoleClient), http.MethodPost, messages, map[string]string{"body": "Hi"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = callAs(send, userToken(t, "auth-stranger", policy.RoleOwner), http.MethodPost, messages, map[string]string{"body": "Hi"})
+	assert.Equal(t, http.StatusForbidden, rec.Code, "only the booking's participants can chat")
+	rec = callAs(send, owner, http.MethodPost, "/api/v1/bookings/other-booking/messages", map[string]string{"body": "Hi"})
+	assert.Equal(t, http.StatusForbidden, rec.Code, "unknown bookings have no participants")
+
+	// The sender is the caller, not whoever the body names
+	rec = callAs(send, owner, http.MethodPost, messages, map[string]string{
+		"sender_id": "auth-walker", "sender_role": "walker", "body": "Please use the back gate",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var sent models.ChatMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &sent))
+	assert.Equal(t, "auth-owner", sent.SenderID)
+	assert.Equal(t, models.ConsentRoleOwner, sent.SenderRole)
+
+	rec = callAs(read, userToken(t, "auth-stranger", policy.RoleWalker), http.MethodGet, messages, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = callAs(read, walker, http.MethodGet, messages, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var page service.ChatPage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.Len(t, page.Messages, 1)
+
+	// Read receipts and reports are made as the caller; the owner cannot report their own message
+	rec = callAs(send, walker, http.MethodPost, messages+"/read", map[string]string{"reader_id": "auth-owner"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var receipt service.ChatReceipt
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &receipt))
+	assert.Equal(t, "auth-walker", receipt.ReaderID)
+	assert.Equal(t, int64(1), receipt.Marked)
+	rec = callAs(send, owner, http.MethodPost, messages+"/"+sent.ID+"/report", map[string]string{"reporter_id": "auth-walker", "reason": "Rude"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Chat tokens carry the caller and the role they take in the booking
+	rec = callAs(tokens, userToken(t, "auth-stranger", policy.RoleWalker), http.MethodPost, "/api/v1/location/tokens",
+		map[string]string{"booking_id": "auth-booking"})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = callAs(tokens, walker, http.MethodPost, "/api/v1/location/tokens",
+		map[string]string{"booking_id": "auth-booking", "user_id": "auth-owner", "role": "owner"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var issued struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &issued))
+	claims, err := service.AuthorizeConnection(issued.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "auth-walker", claims.UserID)
+	assert.Equal(t, string(models.ConsentRoleWalker), claims.Role)
+}
+
+// TestBookingParticipantFromWalks checks that without a booking-service the participants are
+// taken from the walks started for the booking, and that an unreachable booking-service fails
+// closed
+func TestBookingParticipantFromWalks(t *testing.T) {
+	ctx := context.Background()
+	repository.UseMemoryStore()
+	service.Initialize(config.Config{TokenSecret: "participant-secret", TokenTTL: time.Minute, BookingsURL: "http://127.0.0.1:1"}, websocket.NewHub())
+	_, err := service.BookingParticipant(ctx, "walked-booking", "walked-owner")
+	assert.ErrorIs(t, err, service.ErrBookingLookupUnavailable)
+
+	service.Initialize(config.Config{TokenSecret: "participant-secret", TokenTTL: time.Minute}, websocket.NewHub())
+	_, err = service.BookingParticipant(ctx, "walked-booking", "walked-owner")
+	assert.ErrorIs(t, err, service.ErrNotParticipant)
+
+	require.NoError(t, repository.InsertSession(*models.NewSession("walked-session", "walked-booking", "walked-walker", "walked-owner")))
+	role, err := service.BookingParticipant(ctx, "walked-booking", "walked-owner")
+	require.NoError(t, err)
+	assert.Equal(t, models.ConsentRoleOwner, role)
+	role, err = service.BookingParticipant(ctx, "walked-booking", "walked-walker")
+	require.NoError(t, err)
+	assert.Equal(t, models.ConsentRoleWalker, role)
+	_, err = service.BookingParticipant(ctx, "walked-booking", "someone-else")
+	assert.ErrorIs(t, err, service.ErrNotParticipant)
+}
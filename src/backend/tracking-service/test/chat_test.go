@@ -0,0 +1,118 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestBookingChat checks that messages are paged newest first and pushed to the booking's
+// subscribers, that read receipts only mark the other participant's messages, and that a
+// reported message opens an incident and outlives the usual retention
+func TestBookingChat(t *testing.T) {
+	repository.UseMemoryStore()
+
+	published := make(chan websocket.Message, 16)
+	hub := websocket.NewHub()
+	hub.Observe(func(message websocket.Message) {
+		if message.Kind == websocket.KindChat {
+			published <- message
+		}
+	})
+	service.Hub = hub
+	go hub.Run()
+
+	_, _, err := service.IssueChatToken("chat-booking", "chat-owner", "groomer")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid chat connection")
+	token, _, err := service.IssueChatToken("chat-booking", "chat-owner", models.ConsentRoleOwner)
+	require.NoError(t, err)
+	claims, err := service.AuthorizeConnection(token)
+	require.NoError(t, err)
+	assert.Equal(t, "chat-owner", claims.UserID)
+	topic := claims.Topic
+
+	_, err = service.SendChatMessage("chat-booking", models.ChatMessage{SenderID: "chat-owner", SenderRole: "groomer", Body: "Hi"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid chat message")
+
+	first, err := service.SendChatMessage("chat-booking", models.ChatMessage{
+		SenderID: "chat-owner", SenderRole: models.ConsentRoleOwner, Body: "  Please use the back gate  ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Please use the back gate", first.Body)
+	second, err := service.SendChatMessage("chat-booking", models.ChatMessage{
+		SenderID: "chat-walker", SenderRole: models.ConsentRoleWalker, Body: "Will do",
+	})
+	require.NoError(t, err)
+
+	select {
+	case message := <-published:
+		assert.Equal(t, topic, message.Topic)
+		var event map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(message.Data), &event))
+		assert.Equal(t, service.EventChatMessage, event["event"])
+	case <-time.After(time.Second):
+		t.Fatal("chat message was not published to the hub")
+	}
+
+	page, err := service.ListChatMessages("chat-booking", "", 1)
+	require.NoError(t, err)
+	require.Len(t, page.Messages, 1)
+	assert.Equal(t, second.ID, page.Messages[0].ID)
+	require.NotEmpty(t, page.NextBefore)
+	page, err = service.ListChatMessages("chat-booking", page.NextBefore, 1)
+	require.NoError(t, err)
+	require.Len(t, page.Messages, 1)
+	assert.Equal(t, first.ID, page.Messages[0].ID)
+	assert.Empty(t, page.NextBefore)
+
+	receipt, err := service.MarkChatRead("chat-booking", "chat-walker", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), receipt.Marked)
+	read, err := repository.FindChatMessageByID("chat-booking", first.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, read.ReadAt)
+	unread, err := repository.FindChatMessageByID("chat-booking", second.ID)
+	require.NoError(t, err)
+	assert.Nil(t, unread.ReadAt)
+
+	_, err = service.ReportChatMessage("chat-booking", second.ID, "chat-walker", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid report")
+	incident, err := service.ReportChatMessage("chat-booking", second.ID, "chat-owner", "Rude reply")
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentTypeAbusiveMessage, incident.Type)
+	assert.Equal(t, "chat-walker", incident.WalkerID)
+	assert.Equal(t, "chat-owner", incident.OwnerID)
+	_, err = service.ReportChatMessage("chat-booking", second.ID, "chat-owner", "Again")
+	assert.ErrorIs(t, err, service.ErrChatMessageReported)
+
+	_, err = service.CreateIncident(context.Background(), models.Incident{Type: models.IncidentTypeAbusiveMessage, BookingID: "chat-booking"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid incident data")
+
+	data, err := service.ExportSubjectData("chat-owner")
+	require.NoError(t, err)
+	require.Len(t, data.ChatMessages, 1)
+
+	// Past retention only the recently reported message is kept
+	deleted, err := repository.DeleteChatMessagesBefore(time.Now().Add(time.Minute), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, err = repository.FindChatMessageByID("chat-booking", second.ID)
+	assert.NoError(t, err)
+}
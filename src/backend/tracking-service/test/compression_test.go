@@ -0,0 +1,66 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestCompression checks that clients negotiating permessage-deflate and clients that do not
+// receive the same messages, large and small, on one topic
+func TestCompression(t *testing.T) {
+	hub := websocket.NewHub()
+	hub.Compress(websocket.Compression{Enabled: true, Level: 1, Threshold: 128})
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	upgrader := gorillaws.Upgrader{EnableCompression: true, CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := websocket.NewClient(hub, conn, "compressed-walk")
+		client.Deflate = strings.Contains(r.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+		client.Serve()
+	}))
+	t.Cleanup(server.Close)
+
+	connect := func(compression bool) *gorillaws.Conn {
+		dialer := gorillaws.Dialer{EnableCompression: compression}
+		conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	compressed, plain := connect(true), connect(false)
+	require.Eventually(t, func() bool { return hub.Subscribers("compressed-walk") == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	large := `{"route":"` + strings.Repeat("51.5007,-0.1246;", 200) + `"}`
+	small := `{"latitude":51.5}`
+	hub.Publish("compressed-walk", websocket.KindLocation, large)
+	hub.Publish("compressed-walk", websocket.KindLocation, small)
+
+	for _, conn := range []*gorillaws.Conn{compressed, plain} {
+		for _, want := range []string{large, small} {
+			conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			_, data, err := conn.ReadMessage()
+			require.NoError(t, err)
+			var f struct {
+				Payload json.RawMessage `json:"payload"`
+			}
+			require.NoError(t, json.Unmarshal(data, &f))
+			assert.JSONEq(t, want, string(f.Payload))
+		}
+	}
+}
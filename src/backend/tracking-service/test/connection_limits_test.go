@@ -0,0 +1,73 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestConnectionLimits checks that connections beyond a user's quota or the instance's limit
+// are closed on arrival with the code of the limit, and that closing a connection frees its slot
+func TestConnectionLimits(t *testing.T) {
+	hub := websocket.NewHub()
+	hub.LimitConnections(3, 2)
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := websocket.NewClient(hub, conn, "chat:limits-booking")
+		client.UserID = r.URL.Query().Get("user")
+		client.Serve()
+	}))
+	t.Cleanup(server.Close)
+
+	connect := func(user string) *gorillaws.Conn {
+		conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?user="+user, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	refusedWith := func(conn *gorillaws.Conn) int {
+		var closeErr *gorillaws.CloseError
+		for {
+			conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			if _, _, err := conn.ReadMessage(); err != nil {
+				require.True(t, errors.As(err, &closeErr), "expected a close frame, got %v", err)
+				return closeErr.Code
+			}
+		}
+	}
+	connected := func(n int) {
+		require.Eventually(t, func() bool { return hub.GetConnectedClients() == n }, 2*time.Second, 10*time.Millisecond)
+	}
+
+	first := connect("limits-owner")
+	connect("limits-owner")
+	connected(2)
+	assert.Equal(t, websocket.CloseUserQuota, refusedWith(connect("limits-owner")))
+
+	connect("")
+	connected(3)
+	assert.Equal(t, websocket.CloseInstanceFull, refusedWith(connect("limits-walker")))
+
+	// Closing a connection frees its place in the instance and in its user's quota
+	require.NoError(t, first.Close())
+	connected(2)
+	connect("limits-owner")
+	connected(3)
+}
@@ -0,0 +1,285 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/handlers"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// memoryBackplane is a Backplane shared by hubs in one process, standing in for Redis
+type memoryBackplane struct {
+	mu          sync.Mutex
+	subscribers []func(websocket.Message)
+	sequences   map[string]uint64
+	claims      map[string]bool
+	counts      map[string]int
+}
+
+func newMemoryBackplane() *memoryBackplane {
+	return &memoryBackplane{sequences: map[string]uint64{}, claims: map[string]bool{}, counts: map[string]int{}}
+}
+
+func (b *memoryBackplane) Publish(ctx context.Context, message websocket.Message) error {
+	b.mu.Lock()
+	subscribers := append([]func(websocket.Message){}, b.subscribers...)
+	b.mu.Unlock()
+	for _, handler := range subscribers {
+		handler(message)
+	}
+	return nil
+}
+
+func (b *memoryBackplane) NextSequence(ctx context.Context, topic string) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sequences[topic]++
+	return b.sequences[topic], nil
+}
+
+func (b *memoryBackplane) Subscribe(ctx context.Context, handler func(websocket.Message)) error {
+	b.mu.Lock()
+	b.subscribers = append(b.subscribers, handler)
+	b.mu.Unlock()
+	<-ctx.Done()
+	return nil
+}
+
+func (b *memoryBackplane) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.claims[key] {
+		return false, nil
+	}
+	b.claims[key] = true
+	return true, nil
+}
+
+func (b *memoryBackplane) ReportConnections(ctx context.Context, instanceID string, count int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counts[instanceID] = count
+	return nil
+}
+
+func (b *memoryBackplane) ConnectionCounts(ctx context.Context) (map[string]int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	counts := make(map[string]int, len(b.counts))
+	for id, count := range b.counts {
+		counts[id] = count
+	}
+	return counts, nil
+}
+
+func (b *memoryBackplane) Close() error { return nil }
+
+// TestConnectionTokens checks that connection tokens carry their subscription and are
+// rejected once tampered with, signed with another secret or expired
+func TestConnectionTokens(t *testing.T) {
+	secret := []byte("token-secret")
+
+	token, err := websocket.IssueToken(secret, "token-walk", time.Minute)
+	require.NoError(t, err)
+	claims, err := websocket.ParseToken(secret, token)
+	require.NoError(t, err)
+	assert.Equal(t, "token-walk", claims.Topic)
+	assert.Empty(t, claims.UserID)
+	assert.False(t, claims.Dispatch)
+
+	token, err = websocket.IssueParticipantToken(secret, "chat:token-booking", "token-owner", "owner", time.Minute)
+	require.NoError(t, err)
+	claims, err = websocket.ParseToken(secret, token)
+	require.NoError(t, err)
+	assert.Equal(t, "token-owner", claims.UserID)
+	assert.Equal(t, "owner", claims.Role)
+
+	dispatch, err := websocket.IssueDispatchToken(secret, time.Minute)
+	require.NoError(t, err)
+	claims, err = websocket.ParseToken(secret, dispatch)
+	require.NoError(t, err)
+	assert.True(t, claims.Dispatch)
+	assert.Empty(t, claims.Topic)
+
+	// Swapping in another payload breaks the signature
+	payload, signature, _ := strings.Cut(token, ".")
+	forged, _ := websocket.IssueToken(secret, "someone-elses-walk", time.Minute)
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+	_, err = websocket.ParseToken(secret, forgedPayload+"."+signature)
+	assert.ErrorIs(t, err, websocket.ErrInvalidToken)
+	_, err = websocket.ParseToken(secret, payload+"."+signature+"x")
+	assert.ErrorIs(t, err, websocket.ErrInvalidToken)
+
+	_, err = websocket.ParseToken([]byte("other-secret"), token)
+	assert.ErrorIs(t, err, websocket.ErrInvalidToken)
+	for _, malformed := range []string{"", "no-dot", "a.b.c", "!!!." + websocket.ErrInvalidToken.Error()} {
+		_, err = websocket.ParseToken(secret, malformed)
+		assert.ErrorIs(t, err, websocket.ErrInvalidToken, malformed)
+	}
+
+	expired, err := websocket.IssueToken(secret, "token-walk", -time.Minute)
+	require.NoError(t, err)
+	_, err = websocket.ParseToken(secret, expired)
+	assert.ErrorIs(t, err, websocket.ErrTokenExpired)
+}
+
+// TestHubBackplane checks that hubs sharing a backplane deliver each other's messages with
+// sequence numbers shared across instances, and that work is claimed by one instance only
+func TestHubBackplane(t *testing.T) {
+	backplane := newMemoryBackplane()
+	hubs := make([]*websocket.Hub, 2)
+	for i := range hubs {
+		hubs[i] = websocket.NewHub()
+		hubs[i].UseBackplane(backplane)
+		go hubs[i].Run()
+		hub := hubs[i]
+		t.Cleanup(func() {
+			hub.CloseAllConnections()
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			hub.Stop(ctx)
+		})
+	}
+	require.Eventually(t, func() bool {
+		backplane.mu.Lock()
+		defer backplane.mu.Unlock()
+		return len(backplane.subscribers) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	conn := rateLimitServer(t, hubs[1])("topic=backplane-walk")
+	require.Eventually(t, func() bool { return hubs[1].Subscribers("backplane-walk") == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	hubs[0].Publish("backplane-walk", websocket.KindLocation, `{"latitude":51.5}`)
+	hubs[1].Publish("backplane-walk", websocket.KindLocation, `{"latitude":51.6}`)
+
+	var seqs []uint64
+	for len(seqs) < 2 {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var f struct {
+			Seq uint64 `json:"seq"`
+		}
+		require.NoError(t, json.Unmarshal(data, &f))
+		if f.Seq > 0 {
+			seqs = append(seqs, f.Seq)
+		}
+	}
+	assert.Equal(t, []uint64{1, 2}, seqs, "both instances number the walk's messages from one counter")
+
+	ctx := context.Background()
+	claimed, err := hubs[0].Claim(ctx, "stale:backplane-walk", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = hubs[1].Claim(ctx, "stale:backplane-walk", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, claimed, "another instance already holds the claim")
+
+	require.NoError(t, backplane.ReportConnections(ctx, "instance-a", 3))
+	counts, err := hubs[0].InstanceConnections(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"instance-a": 3}, counts)
+}
+
+// TestSessionTokenRequiresParticipant checks that walk connection tokens are only issued to
+// the walk's walker and owners, or admins, and that browsers on other sites cannot connect
+func TestSessionTokenRequiresParticipant(t *testing.T) {
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{
+		TokenSecret:       "session-token-secret",
+		TokenTTL:          time.Minute,
+		StaleAfter:        time.Minute,
+		MaxAccuracyMeters: 100,
+		AllowedOrigins:    []string{"https://app.example.com"},
+	}, hub)
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	require.NoError(t, repository.InsertSession(*models.NewGroupSession("token-session", "token-walker", []models.SessionBooking{
+		{BookingID: "token-booking-a", OwnerID: "token-owner-a"},
+		{BookingID: "token-booking-b", OwnerID: "token-owner-b"},
+	})))
+
+	tokens := auth.Require(testJWTSecret, policy.ResourceLocations, policy.ActionRead)(handlers.IssueConnectionTokenHandler)
+	request := map[string]string{"session_id": "token-session"}
+
+	rec := callAs(tokens, "", http.MethodPost, "/api/v1/location/tokens", request)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = callAs(tokens, userToken(t, "token-stranger", policy.RoleOwner), http.MethodPost, "/api/v1/location/tokens", request)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "strangers cannot follow the walk")
+	rec = callAs(tokens, userToken(t, "token-owner-a", policy.RoleOwner), http.MethodPost, "/api/v1/location/tokens",
+		map[string]string{"session_id": "unknown-session"})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = callAs(tokens, userToken(t, "token-owner-a", policy.RoleOwner), http.MethodPost, "/api/v1/location/tokens", map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var token string
+	for _, user := range []struct{ id, role string }{
+		{"token-walker", policy.RoleWalker},
+		{"token-owner-a", policy.RoleOwner},
+		{"token-owner-b", policy.RoleOwner},
+		{"support", policy.RoleAdmin},
+	} {
+		rec = callAs(tokens, userToken(t, user.id, user.role), http.MethodPost, "/api/v1/location/tokens", request)
+		require.Equal(t, http.StatusOK, rec.Code, "%s: %s", user.id, rec.Body.String())
+		var issued struct {
+			Token string `json:"token"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &issued))
+		claims, err := service.AuthorizeConnection(issued.Token)
+		require.NoError(t, err)
+		assert.Equal(t, "token-session", claims.Topic)
+		token = issued.Token
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handlers.WebSocketHandler))
+	t.Cleanup(server.Close)
+	dial := func(origin string) (int, error) {
+		header := http.Header{}
+		if origin != "" {
+			header.Set("Origin", origin)
+		}
+		conn, resp, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?token="+token, header)
+		if err != nil {
+			if resp == nil {
+				return 0, err
+			}
+			return resp.StatusCode, nil
+		}
+		conn.Close()
+		return resp.StatusCode, nil
+	}
+
+	for origin, want := range map[string]int{
+		"":                        http.StatusSwitchingProtocols,
+		"https://app.example.com": http.StatusSwitchingProtocols,
+		server.URL:                http.StatusSwitchingProtocols,
+		"https://evil.example":    http.StatusForbidden,
+	} {
+		status, err := dial(origin)
+		require.NoError(t, err)
+		assert.Equal(t, want, status, "origin %q", origin)
+	}
+}
@@ -0,0 +1,94 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/shared/clients"
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/handlers"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+)
+
+// TestWalkRequiresConsent checks that a walk cannot start until both the walker and the owner
+// have consented, and that revoked consent no longer counts
+func TestWalkRequiresConsent(t *testing.T) {
+	repository.UseMemoryStore()
+
+	_, err := service.StartSession("consent-booking", "consent-walker", "consent-owner")
+	assert.ErrorIs(t, err, service.ErrConsentRequired)
+
+	_, err = service.RecordConsent("consent-booking", models.Consent{
+		SubjectID: "consent-walker", Role: models.ConsentRoleWalker, TermsVersion: "2024-01",
+	})
+	require.NoError(t, err)
+
+	_, err = service.StartSession("consent-booking", "consent-walker", "consent-owner")
+	assert.ErrorIs(t, err, service.ErrConsentRequired)
+
+	state, err := service.GetConsentState("consent-booking")
+	require.NoError(t, err)
+	assert.True(t, state.WalkerConsented)
+	assert.False(t, state.OwnerConsented)
+
+	require.NoError(t, service.RevokeConsent("consent-booking", "consent-walker"))
+	state, err = service.GetConsentState("consent-booking")
+	require.NoError(t, err)
+	assert.False(t, state.WalkerConsented)
+	assert.Len(t, state.Records, 1)
+
+	data, err := service.ExportSubjectData("consent-walker")
+	require.NoError(t, err)
+	require.Len(t, data.Consents, 1)
+	assert.NotNil(t, data.Consents[0].RevokedAt)
+}
+
+// TestConsentAsParticipant checks that consent is given and withdrawn by the booking's owner
+// and walker as themselves, whoever the body names, and on behalf of others only by admins
+func TestConsentAsParticipant(t *testing.T) {
+	startBookingDirectory(t, "consent-key", clients.Booking{ID: "consented-booking", OwnerID: "consented-owner", WalkerID: "consented-walker"})
+
+	consent := auth.RequireMethod(testJWTSecret, policy.ResourceConsents)(handlers.BookingConsentHandler)
+	path := "/api/v1/bookings/consented-booking/consent"
+	body := map[string]string{"subject_id": "consented-walker", "role": "walker", "terms_version": "2024-01"}
+
+	rec := callAs(consent, "", http.MethodPost, path, body)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = callAs(consent, userToken(t, "consented-stranger", policy.RoleOwner), http.MethodPost, path, body)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "only the booking's participants can consent")
+
+	// The owner consents as themselves, not as the walker the body names
+	rec = callAs(consent, userToken(t, "consented-owner", policy.RoleOwner), http.MethodPost, path, body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var recorded models.Consent
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &recorded))
+	assert.Equal(t, "consented-owner", recorded.SubjectID)
+	assert.Equal(t, models.ConsentRoleOwner, recorded.Role)
+
+	rec = callAs(consent, userToken(t, "support", policy.RoleAdmin), http.MethodPost, path, body)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	state, err := service.GetConsentState("consented-booking")
+	require.NoError(t, err)
+	assert.True(t, state.OwnerConsented)
+	assert.True(t, state.WalkerConsented)
+
+	rec = callAs(consent, userToken(t, "consented-owner", policy.RoleOwner), http.MethodDelete, path+"/consented-walker", nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "owners cannot withdraw the walker's consent")
+	rec = callAs(consent, userToken(t, "consented-walker", policy.RoleWalker), http.MethodDelete, path+"/consented-walker", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = callAs(consent, userToken(t, "consented-stranger", policy.RoleWalker), http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = callAs(consent, userToken(t, "consented-owner", policy.RoleOwner), http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
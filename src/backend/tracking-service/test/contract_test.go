@@ -0,0 +1,45 @@
+// Package test provides contract tests for the messages tracking-service consumes
+package test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/shared/contracts"
+	"src/backend/tracking-service/internal/models"
+)
+
+// acceptedBookingEvent is the part of booking.walker_accepted tracking-service reads
+type acceptedBookingEvent struct {
+	Type string `json:"type"`
+	Data struct {
+		ID          string    `json:"id"`
+		OwnerID     string    `json:"owner_id"`
+		WalkerID    string    `json:"walker_id"`
+		ScheduledAt time.Time `json:"scheduled_at"`
+		Status      string    `json:"status"`
+	} `json:"data"`
+}
+
+// TestWalkerAcceptedEventStartsSession verifies the fixture booking-service is held to carries
+// enough to start a valid walk session. Fields added to acceptedBookingEvent must first be
+// added to the fixture, which is what makes booking-service's contract test enforce them.
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func TestWalkerAcceptedEventStartsSession(t *testing.T) {
+	fixture, err := contracts.Fixture(contracts.BookingWalkerAccepted)
+	require.NoError(t, err)
+
+	var event acceptedBookingEvent
+	require.NoError(t, json.Unmarshal(fixture, &event))
+	assert.Equal(t, "booking.walker_accepted", event.Type)
+	assert.Equal(t, "confirmed", event.Data.Status)
+	assert.False(t, event.Data.ScheduledAt.IsZero(), "scheduled_at must be an RFC 3339 time")
+
+	session := models.NewSession("session-1", event.Data.ID, event.Data.WalkerID, event.Data.OwnerID)
+	assert.NoError(t, session.Validate())
+}
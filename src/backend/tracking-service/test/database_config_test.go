@@ -0,0 +1,62 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// TestDatabaseConcernsConfig verifies the read preference and write concerns default to reads
+// from the primary, majority writes and single-node acknowledgement of points, and can be
+// overridden per environment
+func TestDatabaseConcernsConfig(t *testing.T) {
+	t.Setenv("TRACKING_DB_URI", "mongodb://127.0.0.1:1")
+	t.Setenv("TRACKING_TOKEN_SECRET", "config-secret")
+
+	cfg := config.LoadConfig()
+	assert.Equal(t, "primary", cfg.ReadPreference)
+	assert.Equal(t, "majority", cfg.WriteConcern)
+	assert.Equal(t, "1", cfg.LocationWriteConcern)
+	assert.Equal(t, 5*time.Second, cfg.WriteConcernTimeout)
+
+	t.Setenv("TRACKING_DB_READ_PREFERENCE", "secondaryPreferred")
+	t.Setenv("TRACKING_DB_WRITE_CONCERN", "2")
+	t.Setenv("TRACKING_DB_LOCATION_WRITE_CONCERN", "majority")
+	t.Setenv("TRACKING_DB_WRITE_CONCERN_TIMEOUT", "750ms")
+
+	cfg = config.LoadConfig()
+	assert.Equal(t, "secondaryPreferred", cfg.ReadPreference)
+	assert.Equal(t, "2", cfg.WriteConcern)
+	assert.Equal(t, "majority", cfg.LocationWriteConcern)
+	assert.Equal(t, 750*time.Millisecond, cfg.WriteConcernTimeout)
+}
+
+// TestDatabaseConcernsRejected verifies the repository refuses to connect with a read
+// preference or write concern MongoDB would not understand, or one leaving writes unacknowledged
+func TestDatabaseConcernsRejected(t *testing.T) {
+	valid := config.Config{
+		DatabaseURI:          "mongodb://127.0.0.1:1",
+		ReadPreference:       "nearest",
+		WriteConcern:         "majority",
+		LocationWriteConcern: "1",
+		WriteConcernTimeout:  time.Second,
+	}
+
+	for name, invalid := range map[string]func(cfg *config.Config){
+		"read preference":         func(cfg *config.Config) { cfg.ReadPreference = "fastest" },
+		"write concern":           func(cfg *config.Config) { cfg.WriteConcern = "all" },
+		"location write concern":  func(cfg *config.Config) { cfg.LocationWriteConcern = "0" },
+		"negative location nodes": func(cfg *config.Config) { cfg.LocationWriteConcern = "-1" },
+	} {
+		cfg := valid
+		invalid(&cfg)
+		err := repository.Initialize(cfg)
+		require.Error(t, err, name)
+		assert.Contains(t, err.Error(), "invalid", name)
+	}
+}
@@ -0,0 +1,199 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// dispatchFrame is the part of a frame the dispatch tests look at
+type dispatchFrame struct {
+	Control   string          `json:"control"`
+	Action    string          `json:"action"`
+	BookingID string          `json:"booking_id"`
+	Channel   string          `json:"channel"`
+	Topic     string          `json:"topic"`
+	Error     string          `json:"error"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// TestDispatchSubscriptions checks that a dispatch connection follows several bookings' walks
+// at once, subscribing and unsubscribing as it goes, and that other connections cannot
+func TestDispatchSubscriptions(t *testing.T) {
+	hub := websocket.NewHub()
+	walks := map[string]string{"dispatch-booking-1": "dispatch-walk-1", "dispatch-booking-2": "dispatch-walk-2"}
+	hub.ResolveBookings(func(bookingID string) (string, error) {
+		if topic, ok := walks[bookingID]; ok {
+			return topic, nil
+		}
+		return "", errors.New("booking has no active walk")
+	})
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := websocket.NewClient(hub, conn, r.URL.Query().Get("topic"))
+		client.Dispatch = r.URL.Query().Get("dispatch") == "true"
+		client.Serve()
+	}))
+	t.Cleanup(server.Close)
+
+	connect := func(query string) *gorillaws.Conn {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "?" + query
+		conn, _, err := gorillaws.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	send := func(conn *gorillaws.Conn, message string) {
+		require.NoError(t, conn.WriteMessage(gorillaws.TextMessage, []byte(message)))
+	}
+	next := func(conn *gorillaws.Conn) dispatchFrame {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var f dispatchFrame
+		require.NoError(t, json.Unmarshal(data, &f))
+		return f
+	}
+
+	dispatcher := connect("dispatch=true")
+	send(dispatcher, `{"control":"subscribe","booking_id":"dispatch-booking-1"}`)
+	assert.Equal(t, dispatchFrame{Control: "ack", Action: "subscribe", BookingID: "dispatch-booking-1", Topic: "dispatch-walk-1"}, next(dispatcher))
+	send(dispatcher, `{"control":"subscribe","booking_id":"dispatch-booking-2"}`)
+	assert.Equal(t, "dispatch-walk-2", next(dispatcher).Topic)
+	send(dispatcher, `{"control":"subscribe","booking_id":"dispatch-booking-3"}`)
+	assert.Equal(t, "booking has no active walk", next(dispatcher).Error)
+
+	// Both walks arrive over the one connection, each named by its topic
+	hub.Publish("dispatch-walk-1", websocket.KindLocation, `{"latitude":51.5}`)
+	hub.Publish("dispatch-walk-2", websocket.KindLocation, `{"latitude":48.8}`)
+	first, second := next(dispatcher), next(dispatcher)
+	assert.Equal(t, "dispatch-walk-1", first.Topic)
+	assert.JSONEq(t, `{"latitude":51.5}`, string(first.Payload))
+	assert.Equal(t, "dispatch-walk-2", second.Topic)
+
+	send(dispatcher, `{"control":"unsubscribe","booking_id":"dispatch-booking-1"}`)
+	assert.Equal(t, dispatchFrame{Control: "ack", Action: "unsubscribe", BookingID: "dispatch-booking-1", Topic: "dispatch-walk-1"}, next(dispatcher))
+	hub.Publish("dispatch-walk-1", websocket.KindLocation, `{"latitude":51.6}`)
+	hub.Publish("dispatch-walk-2", websocket.KindLocation, `{"latitude":48.9}`)
+	assert.Equal(t, "dispatch-walk-2", next(dispatcher).Topic)
+
+	// Walk subscribers cannot add to their subscription
+	subscriber := connect("topic=dispatch-walk-2")
+	send(subscriber, `{"control":"subscribe","booking_id":"dispatch-booking-1"}`)
+	assert.Equal(t, "subscriptions are only available to dispatch connections", next(subscriber).Error)
+}
+
+// TestFleetDigest checks that dispatchers subscribed to the fleet channel receive a digest of
+// every active walk, with its last position and a stale flag once it stops reporting
+func TestFleetDigest(t *testing.T) {
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{
+		TokenSecret:         "fleet-secret",
+		TokenTTL:            time.Minute,
+		StaleAfter:          300 * time.Millisecond,
+		MaxAccuracyMeters:   100,
+		ChatRetention:       90 * 24 * time.Hour,
+		ChatReportRetention: 365 * 24 * time.Hour,
+	}, hub)
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go service.RunFleetDigest(ctx)
+
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := websocket.NewClient(hub, conn, "")
+		client.Dispatch = true
+		client.Serve()
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	next := func() dispatchFrame {
+		conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var f dispatchFrame
+		require.NoError(t, json.Unmarshal(data, &f))
+		return f
+	}
+
+	for _, id := range []string{"fleet-walk-1", "fleet-walk-2"} {
+		session, err := json.Marshal(models.NewSession(id, "booking-"+id, "walker-"+id, "owner-"+id))
+		require.NoError(t, err)
+		hub.Publish(id, websocket.KindSessionStarted, string(session))
+	}
+	hub.Publish("fleet-walk-1", websocket.KindLocation, `{"latitude":51.5,"longitude":-0.14,"timestamp":"2024-01-01T12:00:00Z"}`)
+
+	// The first walk keeps reporting; the second goes quiet
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				hub.Publish("fleet-walk-1", websocket.KindHeartbeat, "")
+			}
+		}
+	}()
+
+	require.NoError(t, conn.WriteMessage(gorillaws.TextMessage, []byte(`{"control":"subscribe","channel":"fleet"}`)))
+	ack := next()
+	require.Empty(t, ack.Error)
+	assert.Equal(t, "fleet", ack.Channel)
+
+	var digest service.FleetDigest
+	for digest.Stale == 0 {
+		f := next()
+		require.Equal(t, ack.Topic, f.Topic)
+		require.NoError(t, json.Unmarshal(f.Payload, &digest))
+	}
+
+	assert.Equal(t, 2, digest.Active)
+	assert.Equal(t, 1, digest.Stale)
+	require.Len(t, digest.Walks, 2)
+	walks := map[string]service.FleetWalk{}
+	for _, walk := range digest.Walks {
+		walks[walk.SessionID] = walk
+	}
+	assert.False(t, walks["fleet-walk-1"].Stale)
+	require.NotNil(t, walks["fleet-walk-1"].Position)
+	assert.Equal(t, 51.5, walks["fleet-walk-1"].Position.Latitude)
+	assert.Equal(t, "booking-fleet-walk-1", walks["fleet-walk-1"].BookingID)
+	assert.True(t, walks["fleet-walk-2"].Stale)
+	assert.Nil(t, walks["fleet-walk-2"].Position)
+}
@@ -0,0 +1,133 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/handlers"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// drainSecret signs the connection tokens of the drain tests
+const drainSecret = "drain-secret"
+
+// drainServer runs a hub behind the WebSocket handler, returning it with a function dialling
+// a walk, which returns the connection or, when it is refused, only the response status
+func drainServer(t *testing.T) (*websocket.Hub, func(topic string) (*gorillaws.Conn, int)) {
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{TokenSecret: drainSecret, TokenTTL: time.Minute, MaxAccuracyMeters: 100}, hub)
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	server := httptest.NewServer(http.HandlerFunc(handlers.WebSocketHandler))
+	t.Cleanup(server.Close)
+	return hub, func(topic string) (*gorillaws.Conn, int) {
+		token, err := websocket.IssueToken([]byte(drainSecret), topic, time.Minute)
+		require.NoError(t, err)
+		conn, resp, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?token="+token, nil)
+		if err != nil {
+			require.NotNil(t, resp, err)
+			return nil, resp.StatusCode
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn, resp.StatusCode
+	}
+}
+
+// awaitDraining reads frames from conn until the server announces it is draining, returning
+// the delay it asked the client to wait before reconnecting
+func awaitDraining(t *testing.T, conn *gorillaws.Conn) time.Duration {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err, "the client was not told the server is draining")
+		var f struct {
+			Control          string `json:"control"`
+			ReconnectAfterMs int64  `json:"reconnect_after_ms"`
+		}
+		require.NoError(t, json.Unmarshal(data, &f))
+		if f.Control == "server_draining" {
+			return time.Duration(f.ReconnectAfterMs) * time.Millisecond
+		}
+	}
+}
+
+// TestDrainAnnouncesShutdown checks that draining tells every client to reconnect within the
+// first half of the drain period, refuses new connections, and ends as soon as the clients go
+func TestDrainAnnouncesShutdown(t *testing.T) {
+	hub, connect := drainServer(t)
+	var clients []*gorillaws.Conn
+	for _, topic := range []string{"drain-walk", "drain-walk", "drain-other-walk"} {
+		conn, status := connect(topic)
+		require.Equal(t, http.StatusSwitchingProtocols, status)
+		clients = append(clients, conn)
+	}
+	require.Eventually(t, func() bool { return hub.GetConnectedClients() == 3 }, 2*time.Second, 10*time.Millisecond)
+
+	period := 4 * time.Second
+	drained := make(chan struct{})
+	started := time.Now()
+	go func() {
+		defer close(drained)
+		hub.Drain(context.Background(), period)
+	}()
+
+	for _, conn := range clients {
+		delay := awaitDraining(t, conn)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.Less(t, delay, period/2)
+	}
+	assert.True(t, hub.Draining())
+
+	_, status := connect("drain-walk")
+	assert.Equal(t, http.StatusServiceUnavailable, status, "a draining instance takes no new connections")
+
+	for _, conn := range clients {
+		conn.Close()
+	}
+	select {
+	case <-drained:
+		assert.Less(t, time.Since(started), period, "draining ends once every client has gone")
+	case <-time.After(period + time.Second):
+		t.Fatal("drain did not return")
+	}
+}
+
+// TestDrainPeriodElapses checks that draining gives up on clients that do not leave once the
+// drain period is over, and on its context being cancelled
+func TestDrainPeriodElapses(t *testing.T) {
+	hub, connect := drainServer(t)
+	conn, status := connect("drain-stuck-walk")
+	require.Equal(t, http.StatusSwitchingProtocols, status)
+	require.Eventually(t, func() bool { return hub.GetConnectedClients() == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	started := time.Now()
+	hub.Drain(context.Background(), 600*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(started), 600*time.Millisecond)
+	awaitDraining(t, conn)
+	assert.Equal(t, 1, hub.GetConnectedClients())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	started = time.Now()
+	hub.Drain(ctx, time.Minute)
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
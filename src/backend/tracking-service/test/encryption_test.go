@@ -0,0 +1,40 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/encryption"
+)
+
+// TestKeyringRotation checks that values sealed before a rotation still open afterwards and
+// that sealed values cannot be moved to another record
+func TestKeyringRotation(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	before, err := encryption.NewKeyring("k1", map[string][]byte{"k1": oldKey})
+	require.NoError(t, err)
+	keyID, sealed, err := before.Seal([]byte("51.5,-0.12"), []byte("walk-1|1700000000000"))
+	require.NoError(t, err)
+	assert.Equal(t, "k1", keyID)
+
+	after, err := encryption.NewKeyring("k2", map[string][]byte{"k1": oldKey, "k2": newKey})
+	require.NoError(t, err)
+	plaintext, err := after.Open(keyID, sealed, []byte("walk-1|1700000000000"))
+	require.NoError(t, err)
+	assert.Equal(t, "51.5,-0.12", string(plaintext))
+
+	_, err = after.Open(keyID, sealed, []byte("walk-2|1700000000000"))
+	assert.Error(t, err)
+
+	_, err = after.Open("k3", sealed, nil)
+	assert.ErrorIs(t, err, encryption.ErrUnknownKey)
+
+	_, err = encryption.ParseKeys("k1:not-base64!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestEnvelopeProtocol checks that clients asking for envelopes receive typed, versioned
+// messages and signals, while legacy clients on the same topic keep receiving bare frames
+func TestEnvelopeProtocol(t *testing.T) {
+	hub := websocket.NewHub()
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := websocket.NewClient(hub, conn, "chat:envelope-booking")
+		client.UserID = r.URL.Query().Get("user")
+		if protocol, err := strconv.Atoi(r.URL.Query().Get("protocol")); err == nil {
+			client.Protocol = protocol
+		}
+		client.Serve()
+	}))
+	t.Cleanup(server.Close)
+
+	connect := func(user string, protocol int) *gorillaws.Conn {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "?user=" + user + "&protocol=" + strconv.Itoa(protocol)
+		conn, _, err := gorillaws.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	next := func(conn *gorillaws.Conn, v interface{}) {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, v))
+	}
+
+	legacy := connect("envelope-owner", websocket.ProtocolLegacy)
+	var online presenceFrame
+	next(legacy, &online)
+	require.Equal(t, "presence", online.Control)
+
+	current := connect("envelope-walker", websocket.ProtocolEnvelope)
+	var envelope websocket.Envelope
+	next(current, &envelope)
+	assert.Equal(t, "presence", envelope.Type)
+	assert.Equal(t, 1, envelope.Version)
+	assert.JSONEq(t, `{"user_id":"envelope-owner","status":"online"}`, string(envelope.Payload))
+	next(legacy, &online)
+	assert.Equal(t, presenceFrame{Control: "presence", UserID: "envelope-walker", Status: "online"}, online)
+	next(current, &envelope)
+	assert.Equal(t, "presence", envelope.Type)
+
+	hub.Publish("chat:envelope-booking", websocket.KindChat, `{"body":"On my way"}`)
+	next(current, &envelope)
+	assert.Equal(t, websocket.KindChat, envelope.Type)
+	assert.Equal(t, 1, envelope.Version)
+	assert.Equal(t, uint64(1), envelope.Seq)
+	assert.JSONEq(t, `{"body":"On my way"}`, string(envelope.Payload))
+	assert.WithinDuration(t, time.Now(), envelope.TS, 5*time.Second)
+
+	var bare struct {
+		Seq     uint64          `json:"seq"`
+		Payload json.RawMessage `json:"payload"`
+		Type    string          `json:"type"`
+	}
+	next(legacy, &bare)
+	assert.Equal(t, uint64(1), bare.Seq)
+	assert.JSONEq(t, `{"body":"On my way"}`, string(bare.Payload))
+	assert.Empty(t, bare.Type)
+
+	// Envelope clients send typing signals as envelopes too
+	require.NoError(t, current.WriteMessage(gorillaws.TextMessage, []byte(`{"type":"typing","version":1,"payload":{"status":"typing"}}`)))
+	next(legacy, &online)
+	assert.Equal(t, presenceFrame{Control: "typing", UserID: "envelope-walker", Status: "typing", ExpiresInMs: 5000}, online)
+}
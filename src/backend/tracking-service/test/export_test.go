@@ -0,0 +1,88 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/export"
+	"src/backend/tracking-service/internal/models"
+)
+
+// exportPoints returns two points from one session followed by one from another
+func exportPoints() []models.Location {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	accuracy := 5.0
+	return []models.Location{
+		{SessionID: "walk-a", Latitude: 51.5, Longitude: -0.12, Timestamp: start, AccuracyMeters: &accuracy},
+		{SessionID: "walk-a", Latitude: 51.501, Longitude: -0.121, Timestamp: start.Add(5 * time.Second)},
+		{SessionID: "walk-b", Latitude: 40.7, Longitude: -74, Timestamp: start.Add(time.Minute)},
+	}
+}
+
+// encodeAll writes points in format and returns the output
+func encodeAll(t *testing.T, format models.ExportFormat, points []models.Location) []byte {
+	var buf bytes.Buffer
+	encoder, err := export.NewEncoder(format, &buf)
+	require.NoError(t, err)
+	for _, point := range points {
+		require.NoError(t, encoder.Write(point))
+	}
+	require.NoError(t, encoder.Close())
+	return buf.Bytes()
+}
+
+// TestExportEncoders checks that every export format produces a well-formed file
+func TestExportEncoders(t *testing.T) {
+	points := exportPoints()
+
+	t.Run("csv", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(string(encodeAll(t, models.ExportFormatCSV, points))), "\n")
+		assert.Len(t, lines, len(points)+1)
+		assert.True(t, strings.HasPrefix(lines[0], "session_id,timestamp,latitude,longitude"))
+		assert.Equal(t, "walk-a,2024-05-01T09:00:00Z,51.5,-0.12,5,,,,,false", lines[1])
+	})
+
+	t.Run("geojson", func(t *testing.T) {
+		var collection struct {
+			Type     string `json:"type"`
+			Features []struct {
+				Geometry struct {
+					Coordinates []float64 `json:"coordinates"`
+				} `json:"geometry"`
+			} `json:"features"`
+		}
+		require.NoError(t, json.Unmarshal(encodeAll(t, models.ExportFormatGeoJSON, points), &collection))
+		assert.Equal(t, "FeatureCollection", collection.Type)
+		require.Len(t, collection.Features, len(points))
+		assert.Equal(t, []float64{-0.12, 51.5}, collection.Features[0].Geometry.Coordinates)
+	})
+
+	t.Run("gpx", func(t *testing.T) {
+		var gpx struct {
+			Tracks []struct {
+				Name   string `xml:"name"`
+				Points []struct {
+					Lat float64 `xml:"lat,attr"`
+				} `xml:"trkseg>trkpt"`
+			} `xml:"trk"`
+		}
+		require.NoError(t, xml.Unmarshal(encodeAll(t, models.ExportFormatGPX, points), &gpx))
+		require.Len(t, gpx.Tracks, 2)
+		assert.Equal(t, "walk-a", gpx.Tracks[0].Name)
+		assert.Len(t, gpx.Tracks[0].Points, 2)
+		assert.Len(t, gpx.Tracks[1].Points, 1)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var collection map[string]interface{}
+		require.NoError(t, json.Unmarshal(encodeAll(t, models.ExportFormatGeoJSON, nil), &collection))
+	})
+}
@@ -0,0 +1,105 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/export"
+	"src/backend/tracking-service/internal/handlers"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+)
+
+// TestExportsRequireOwnership checks that users export only walks they take part in, that
+// only admins export a time range across every walker, and that a job and its file are only
+// served to whoever requested it, or an admin, through signed links that expire
+func TestExportsRequireOwnership(t *testing.T) {
+	repository.UseMemoryStore()
+	require.NoError(t, repository.InsertSession(*models.NewSession("exports-walk", "exports-booking", "exports-walker", "exports-owner")))
+	start := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	for i := 0; i < 2; i++ {
+		location := models.NewLocation(51.5+float64(i)*0.001, -0.12, start.Add(time.Duration(i)*time.Minute))
+		location.SessionID = "exports-walk"
+		require.NoError(t, repository.InsertLocation(*location))
+	}
+
+	files, err := export.NewFileStore(t.TempDir(), []byte("export-link-secret"))
+	require.NoError(t, err)
+	service.ConfigureExports(files, config.Config{ExportWorkers: 1, ExportMaxPending: 10, ExportURLTTL: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	workers := make(chan struct{})
+	go func() {
+		defer close(workers)
+		service.RunExportWorkers(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-workers
+	})
+
+	access := auth.Require(testJWTSecret, policy.ResourceLocations, policy.ActionRead)
+	create := access(handlers.CreateExportHandler)
+	jobs := access(handlers.ExportJobHandler)
+	download := access(handlers.ExportFileHandler(files))
+	owner := userToken(t, "exports-owner", policy.RoleOwner)
+	walker := userToken(t, "exports-walker", policy.RoleWalker)
+	admin := userToken(t, "support", policy.RoleAdmin)
+	end := start.Add(time.Hour)
+	walk := map[string]interface{}{"format": "csv", "session_id": "exports-walk"}
+	allWalkers := map[string]interface{}{"format": "csv", "start_time": start, "end_time": end}
+
+	assert.Equal(t, http.StatusUnauthorized, callAs(create, "", http.MethodPost, "/api/v1/exports/jobs", walk).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(create, userToken(t, "exports-stranger", policy.RoleOwner), http.MethodPost, "/api/v1/exports/jobs", walk).Code,
+		"users cannot export others' walks")
+	assert.Equal(t, http.StatusForbidden, callAs(create, owner, http.MethodPost, "/api/v1/exports/jobs", allWalkers).Code,
+		"only admins export every walker's points")
+	assert.Equal(t, http.StatusAccepted, callAs(create, admin, http.MethodPost, "/api/v1/exports/jobs", allWalkers).Code)
+
+	rec := callAs(create, owner, http.MethodPost, "/api/v1/exports/jobs", walk)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var job models.ExportJob
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+	assert.Equal(t, "exports-owner", job.RequestedBy)
+
+	path := "/api/v1/exports/jobs/" + job.ID
+	require.Eventually(t, func() bool {
+		rec = callAs(jobs, owner, http.MethodGet, path, nil)
+		return rec.Code == http.StatusOK && json.Unmarshal(rec.Body.Bytes(), &job) == nil && job.IsFinished()
+	}, 5*time.Second, 20*time.Millisecond)
+	require.Equal(t, models.ExportStatusCompleted, job.Status, job.Error)
+	assert.Equal(t, http.StatusForbidden, callAs(jobs, walker, http.MethodGet, path, nil).Code,
+		"taking part in the walk does not give access to another user's export")
+	assert.Equal(t, http.StatusOK, callAs(jobs, admin, http.MethodGet, path, nil).Code)
+
+	link := job.DownloadURL
+	require.True(t, strings.HasPrefix(link, export.FilesPathPrefix), link)
+	rec = callAs(download, owner, http.MethodGet, link, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "exports-walk")
+	assert.Equal(t, http.StatusUnauthorized, callAs(download, "", http.MethodGet, link, nil).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(download, walker, http.MethodGet, link, nil).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(download, owner, http.MethodGet, strings.Split(link, "?")[0], nil).Code,
+		"files are only served through signed links")
+	assert.Equal(t, http.StatusForbidden, callAs(download, owner, http.MethodGet, link[:len(link)-1]+"0", nil).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(download, owner, http.MethodGet,
+		strings.Replace(link, "expires=", "expires=9", 1), nil).Code, "the expiry is signed")
+
+	expired, err := files.URL(ctx, "exports/"+job.ID+".csv", -time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, callAs(download, owner, http.MethodGet, expired, nil).Code)
+}
@@ -0,0 +1,105 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestGroupWalk checks that one walk session can serve several bookings, each of which finds
+// the walk and gets its own evidence
+func TestGroupWalk(t *testing.T) {
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{
+		TokenSecret:         "group-secret",
+		TokenTTL:            time.Minute,
+		StaleAfter:          time.Minute,
+		MaxAccuracyMeters:   100,
+		ChatRetention:       90 * 24 * time.Hour,
+		ChatReportRetention: 365 * 24 * time.Hour,
+	}, hub)
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	bookings := []models.SessionBooking{
+		{BookingID: "group-booking-a", OwnerID: "group-owner-a"},
+		{BookingID: "group-booking-b", OwnerID: "group-owner-b"},
+	}
+	consent := func(bookingID, subjectID string, role models.ConsentRole) {
+		_, err := service.RecordConsent(bookingID, models.Consent{SubjectID: subjectID, Role: role, TermsVersion: "2024-01"})
+		require.NoError(t, err)
+	}
+	consent("group-booking-a", "group-walker", models.ConsentRoleWalker)
+	consent("group-booking-a", "group-owner-a", models.ConsentRoleOwner)
+	consent("group-booking-b", "group-walker", models.ConsentRoleWalker)
+
+	// Every booking's owner must have agreed to tracking
+	_, err := service.StartGroupSession("group-walker", bookings)
+	assert.ErrorIs(t, err, service.ErrConsentRequired)
+	assert.Contains(t, err.Error(), "group-booking-b")
+	consent("group-booking-b", "group-owner-b", models.ConsentRoleOwner)
+
+	_, err = service.StartGroupSession("group-walker", append(bookings, bookings[0]))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid session data")
+
+	session, err := service.StartGroupSession("group-walker", bookings)
+	require.NoError(t, err)
+	assert.True(t, session.IsGroup())
+	assert.Equal(t, "group-booking-a", session.BookingID)
+	assert.Equal(t, "group-owner-a", session.OwnerID)
+
+	for _, booking := range bookings {
+		found, err := repository.FindSessionsByBooking(booking.BookingID)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, session.ID, found[0].ID)
+	}
+
+	start := time.Now().Add(-10 * time.Minute).UTC()
+	require.NoError(t, service.TrackLocation(models.Location{SessionID: session.ID, Latitude: 51.5000, Longitude: -0.1400, Timestamp: start}))
+	require.NoError(t, service.TrackLocation(models.Location{SessionID: session.ID, Latitude: 51.5090, Longitude: -0.1400, Timestamp: start.Add(5 * time.Minute)}))
+
+	// Photos name the dogs they show, and only bookings of the walk
+	_, err = service.RecordWalkPhoto(session.ID, models.WalkPhoto{URL: "https://photos.example.com/group.jpg"})
+	require.NoError(t, err)
+	_, err = service.RecordWalkPhoto(session.ID, models.WalkPhoto{URL: "https://photos.example.com/a.jpg", BookingIDs: []string{"group-booking-a"}})
+	require.NoError(t, err)
+	_, err = service.RecordWalkPhoto(session.ID, models.WalkPhoto{URL: "https://photos.example.com/x.jpg", BookingIDs: []string{"other-booking"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid photo")
+
+	a, err := service.BookingWalkEvidence("group-booking-a")
+	require.NoError(t, err)
+	b, err := service.BookingWalkEvidence("group-booking-b")
+	require.NoError(t, err)
+
+	assert.True(t, a.Group)
+	assert.True(t, b.Group)
+	assert.Equal(t, 2, a.Photos)
+	assert.Equal(t, 1, b.Photos)
+	assert.InDelta(t, 1000, a.DistanceMeters, 10)
+	assert.InDelta(t, a.DistanceMeters, b.DistanceMeters, 0.001)
+
+	// A walk of one booking stays an ordinary walk
+	consent("solo-booking", "group-walker", models.ConsentRoleWalker)
+	consent("solo-booking", "solo-owner", models.ConsentRoleOwner)
+	solo, err := service.StartSession("solo-booking", "group-walker", "solo-owner")
+	require.NoError(t, err)
+	assert.False(t, solo.IsGroup())
+	assert.Empty(t, solo.Bookings)
+	assert.Len(t, solo.AllBookings(), 1)
+}
@@ -0,0 +1,111 @@
+// Package test provides benchmarks for the tracking-service WebSocket hub
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// benchTopic is the subscription every benchmark client joins
+const benchTopic = "bench-session"
+
+// benchPayload is a representative location message
+const benchPayload = `{"session_id":"bench-session","latitude":40.7128,"longitude":-74.006,"timestamp":"2024-01-01T12:00:00Z"}`
+
+// startBenchHub runs a hub behind a test server and connects subscribers to it. Each
+// message a subscriber reads is signalled on the returned channel.
+func startBenchHub(b *testing.B, subscribers int) (*websocket.Hub, <-chan struct{}) {
+	b.Helper()
+
+	hub := websocket.NewHub()
+	go hub.Run()
+
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		websocket.NewClient(hub, conn, benchTopic).Serve()
+	}))
+	b.Cleanup(server.Close)
+	b.Cleanup(hub.CloseAllConnections)
+
+	received := make(chan struct{}, subscribers*16)
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	for i := 0; i < subscribers; i++ {
+		conn, _, err := gorillaws.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			b.Fatalf("failed to connect subscriber %d: %v", i, err)
+		}
+		b.Cleanup(func() { conn.Close() })
+
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+				received <- struct{}{}
+			}
+		}()
+	}
+
+	// Wait until the hub has registered every subscriber
+	deadline := time.Now().Add(5 * time.Second)
+	for hub.GetConnectedClients() < subscribers {
+		if time.Now().After(deadline) {
+			b.Fatalf("only %d of %d subscribers registered", hub.GetConnectedClients(), subscribers)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	return hub, received
+}
+
+// BenchmarkHubBroadcast measures end-to-end delivery of one location message to every
+// subscriber of a walk, over real WebSocket connections
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func BenchmarkHubBroadcast(b *testing.B) {
+	for _, subscribers := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("subscribers=%d", subscribers), func(b *testing.B) {
+			hub, received := startBenchHub(b, subscribers)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				hub.Publish(benchTopic, websocket.KindLocation, benchPayload)
+				for j := 0; j < subscribers; j++ {
+					<-received
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkHubPublishParallel measures hub throughput with many walkers publishing at once
+// to topics that have no local subscribers, isolating the hub loop from socket writes
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func BenchmarkHubPublishParallel(b *testing.B) {
+	hub := websocket.NewHub()
+	go hub.Run()
+	b.Cleanup(hub.CloseAllConnections)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		topic := fmt.Sprintf("walker-%p", pb)
+		for pb.Next() {
+			hub.Publish(topic, websocket.KindLocation, benchPayload)
+		}
+	})
+}
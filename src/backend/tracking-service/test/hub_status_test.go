@@ -0,0 +1,66 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestHubStatus checks that the hub status reports rooms by size, the clients with messages
+// waiting in their buffers, and what was delivered and dropped
+func TestHubStatus(t *testing.T) {
+	hub := websocket.NewHub()
+	hub.SetInstanceID("status-instance")
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	// Clients without connections never drain their buffers, like a stuck stream
+	watchers := []*websocket.Client{
+		websocket.NewClient(hub, nil, "status-walk-1"),
+		websocket.NewClient(hub, nil, "status-walk-1"),
+		websocket.NewClient(hub, nil, "status-walk-2"),
+	}
+	for _, client := range watchers {
+		hub.Register <- client
+	}
+
+	for i := 0; i < 3; i++ {
+		hub.Publish("status-walk-1", websocket.KindLocation, `{"latitude":51.5}`)
+	}
+	hub.Publish("status-walk-2", websocket.KindLocation, `{"latitude":48.8}`)
+	hub.Publish("status-walk-2", "telemetry", `{}`)
+
+	var status websocket.HubStatus
+	require.Eventually(t, func() bool {
+		status = hub.Status()
+		return status.Broadcast.Messages == 4
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "status-instance", status.InstanceID)
+	assert.Equal(t, 3, status.Clients)
+	assert.Equal(t, 2, status.RoomCount)
+	assert.Equal(t, []websocket.RoomStatus{
+		{Topic: "status-walk-1", Subscribers: 2},
+		{Topic: "status-walk-2", Subscribers: 1},
+	}, status.Rooms)
+	assert.Equal(t, 7, status.QueuedMessages)
+	require.Len(t, status.Backlogged, 3)
+	assert.Equal(t, 3, status.Backlogged[0].Queued)
+	assert.Equal(t, "status-walk-2", status.Backlogged[2].Topic)
+	assert.Equal(t, uint64(1), status.Dropped["unregistered_kind"])
+	assert.Zero(t, status.Dropped["send_buffer_full"])
+
+	// The admin endpoint serves the status as JSON
+	encoded, err := json.Marshal(status)
+	require.NoError(t, err)
+	var report map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &report))
+	assert.Equal(t, float64(7), report["queued_messages"])
+	assert.Contains(t, report, "broadcast")
+}
@@ -0,0 +1,186 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/shared/clock"
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/handlers"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestIncidentWorkflow checks that incidents reported on a walk take its booking and
+// participants, and move through the status workflow only as it allows
+func TestIncidentWorkflow(t *testing.T) {
+	repository.UseMemoryStore()
+	service.Initialize(config.Config{MaxAccuracyMeters: 100}, websocket.NewHub())
+
+	session := models.NewSession("incident-walk", "incident-booking", "incident-walker", "incident-owner")
+	session.Region = "north"
+	require.NoError(t, repository.InsertSession(*session))
+
+	reportedAt := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	walkClock := clock.NewFake(reportedAt)
+	ctx := clock.WithContext(context.Background(), walkClock)
+
+	_, err := service.CreateIncident(ctx, models.Incident{Type: models.IncidentTypeSOS, SessionID: "incident-walk"})
+	assert.Error(t, err, "emergencies are raised through the SOS endpoint")
+	_, err = service.CreateIncident(ctx, models.Incident{Type: models.IncidentTypeInjury})
+	assert.Error(t, err, "an incident names its walk or booking")
+
+	incident, err := service.CreateIncident(ctx, models.Incident{
+		Type:        models.IncidentTypeInjury,
+		SessionID:   "incident-walk",
+		BookingID:   "incident-forged-booking",
+		ReportedBy:  "incident-walker",
+		Message:     "cut paw on glass",
+		Status:      models.IncidentStatusClosed,
+		Attachments: []models.Attachment{{MediaID: "media-1", URL: "https://media.example.com/1.jpg"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusOpen, incident.Status, "new incidents are always open")
+	assert.Equal(t, "incident-booking", incident.BookingID, "the walk's booking wins over the body's")
+	assert.Equal(t, "incident-owner", incident.OwnerID)
+	assert.Equal(t, "north", incident.Region)
+	require.Len(t, incident.Attachments, 1)
+	assert.Equal(t, "incident-walker", incident.Attachments[0].AddedBy)
+	assert.True(t, incident.CreatedAt.Equal(reportedAt), "incidents are timed by the request's clock")
+	assert.True(t, incident.Attachments[0].AddedAt.Equal(reportedAt))
+
+	status := func(s models.IncidentStatus) *models.IncidentStatus { return &s }
+	_, err = service.UpdateIncident(ctx, incident.ID, service.IncidentUpdate{Status: status(models.IncidentStatusClosed)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot move from open to closed")
+
+	walkClock.Advance(time.Hour)
+	updated, err := service.UpdateIncident(ctx, incident.ID, service.IncidentUpdate{Status: status(models.IncidentStatusInvestigating)})
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusInvestigating, updated.Status)
+	assert.True(t, updated.UpdatedAt.Equal(reportedAt.Add(time.Hour)))
+
+	_, err = service.UpdateIncident(ctx, incident.ID, service.IncidentUpdate{Status: status(models.IncidentStatusResolved)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a resolution is required")
+
+	resolution := "vet checked the paw"
+	updated, err = service.UpdateIncident(ctx, incident.ID, service.IncidentUpdate{Status: status(models.IncidentStatusResolved), Resolution: &resolution})
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusResolved, updated.Status)
+	assert.Equal(t, resolution, updated.Resolution)
+	assert.NotNil(t, updated.ResolvedAt)
+
+	// Resolved incidents can be reopened, but closed ones stay closed
+	updated, err = service.UpdateIncident(ctx, incident.ID, service.IncidentUpdate{Status: status(models.IncidentStatusInvestigating)})
+	require.NoError(t, err)
+	assert.Nil(t, updated.ResolvedAt)
+	_, err = service.UpdateIncident(ctx, incident.ID, service.IncidentUpdate{Status: status(models.IncidentStatusResolved), Resolution: &resolution})
+	require.NoError(t, err)
+	_, err = service.UpdateIncident(ctx, incident.ID, service.IncidentUpdate{Status: status(models.IncidentStatusClosed)})
+	require.NoError(t, err)
+	_, err = service.UpdateIncident(ctx, incident.ID, service.IncidentUpdate{Status: status(models.IncidentStatusInvestigating)})
+	assert.Error(t, err)
+
+	_, err = service.UpdateIncident(ctx, "incident-unknown", service.IncidentUpdate{Status: status(models.IncidentStatusInvestigating)})
+	assert.ErrorIs(t, err, service.ErrIncidentNotFound)
+}
+
+// TestIncidentQueue checks that the admin queue lists incidents still needing attention,
+// oldest first, filtered by status, type and region
+func TestIncidentQueue(t *testing.T) {
+	repository.UseMemoryStore()
+	service.Initialize(config.Config{MaxAccuracyMeters: 100}, websocket.NewHub())
+	ctx := context.Background()
+
+	report := func(bookingID string, incidentType models.IncidentType, region string) *models.Incident {
+		incident, err := service.CreateIncident(ctx, models.Incident{Type: incidentType, BookingID: bookingID, Region: region})
+		require.NoError(t, err)
+		return incident
+	}
+	lost := report("queue-lost", models.IncidentTypeLostDog, "north")
+	injury := report("queue-injury", models.IncidentTypeInjury, "south")
+	resolved := report("queue-resolved", models.IncidentTypeInjury, "north")
+	resolution := "dog found"
+	status := models.IncidentStatusResolved
+	_, err := service.UpdateIncident(ctx, resolved.ID, service.IncidentUpdate{Status: &status, Resolution: &resolution})
+	require.NoError(t, err)
+
+	ids := func(incidents []models.Incident) []string {
+		var ids []string
+		for _, incident := range incidents {
+			ids = append(ids, incident.BookingID)
+		}
+		return ids
+	}
+
+	queue, err := service.IncidentQueue("", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{lost.BookingID, injury.BookingID}, ids(queue))
+
+	queue, err = service.IncidentQueue("", models.IncidentTypeInjury, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{injury.BookingID}, ids(queue))
+
+	queue, err = service.IncidentQueue("", "", " North ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{lost.BookingID}, ids(queue))
+
+	queue, err = service.IncidentQueue(models.IncidentStatusResolved, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{resolved.BookingID}, ids(queue))
+}
+
+// TestIncidentAccess checks that incidents are reported as the user of the token, read only by
+// their reporter or an admin, and moved through the status workflow only by admins
+func TestIncidentAccess(t *testing.T) {
+	repository.UseMemoryStore()
+	service.Initialize(config.Config{MaxAccuracyMeters: 100}, websocket.NewHub())
+	require.NoError(t, repository.InsertSession(*models.NewSession("incident-access-walk", "incident-access-booking", "incident-access-walker", "incident-access-owner")))
+
+	incidents := auth.RequireMethod(testJWTSecret, policy.ResourceIncidents)
+	create := incidents(handlers.CreateIncidentHandler)
+	incident := incidents(handlers.IncidentHandler)
+	walker := userToken(t, "incident-access-walker", policy.RoleWalker)
+	owner := userToken(t, "incident-access-owner", policy.RoleOwner)
+	admin := userToken(t, "support", policy.RoleAdmin)
+	report := map[string]string{"type": string(models.IncidentTypeInjury), "session_id": "incident-access-walk", "reported_by": "incident-access-owner"}
+
+	assert.Equal(t, http.StatusUnauthorized, callAs(create, "", http.MethodPost, "/api/v1/incidents", report).Code)
+	rec := callAs(create, walker, http.MethodPost, "/api/v1/incidents", report)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var reported models.Incident
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reported))
+	assert.Equal(t, "incident-access-walker", reported.ReportedBy, "the reporter comes from the token, not the body")
+
+	path := "/api/v1/incidents/" + reported.ID
+	assert.Equal(t, http.StatusUnauthorized, callAs(incident, "", http.MethodGet, path, nil).Code)
+	assert.Equal(t, http.StatusOK, callAs(incident, walker, http.MethodGet, path, nil).Code)
+	assert.Equal(t, http.StatusOK, callAs(incident, admin, http.MethodGet, path, nil).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(incident, owner, http.MethodGet, path, nil).Code,
+		"only the reporter reads an incident")
+	assert.Equal(t, http.StatusForbidden, callAs(incident, owner, http.MethodPost, path+"/attachments",
+		models.Attachment{MediaID: "media-2", URL: "https://media.example.com/2.jpg"}).Code)
+
+	investigating := map[string]string{"status": string(models.IncidentStatusInvestigating)}
+	assert.Equal(t, http.StatusForbidden, callAs(incident, walker, http.MethodPatch, path, investigating).Code,
+		"reporters cannot move their incidents through the workflow")
+	rec = callAs(incident, admin, http.MethodPatch, path, investigating)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &reported))
+	assert.Equal(t, models.IncidentStatusInvestigating, reported.Status)
+}
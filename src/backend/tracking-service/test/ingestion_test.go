@@ -0,0 +1,198 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/geohash"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+)
+
+// TestLocationDeduplication checks that retried points are stored once and that points
+// differing only in device sequence number are both kept
+func TestLocationDeduplication(t *testing.T) {
+	repository.UseMemoryStore()
+
+	timestamp := time.Now().Add(-time.Minute).UTC()
+	seq := int64(7)
+	point := models.Location{SessionID: "dedupe-walk", Latitude: 51.5, Longitude: -0.12, Timestamp: timestamp, DeviceSeq: &seq}
+
+	// A retry reaching this instance is dropped by the recent point cache
+	require.NoError(t, service.TrackLocation(point))
+	require.NoError(t, service.TrackLocation(point))
+
+	// A retry written directly, as by another instance, is dropped by the store
+	require.NoError(t, repository.InsertLocation(point))
+
+	nextSeq := seq + 1
+	sibling := point
+	sibling.DeviceSeq = &nextSeq
+	require.NoError(t, service.TrackLocation(sibling))
+
+	stored, err := repository.FindLocationsBySession("dedupe-walk")
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+}
+
+// TestLateLocations checks that a point arriving after a newer one is stored and marked late,
+// and that the session route is still in recording order
+func TestLateLocations(t *testing.T) {
+	repository.UseMemoryStore()
+
+	start := time.Now().Add(-5 * time.Minute).UTC()
+	newer := models.Location{SessionID: "late-walk", Latitude: 51.501, Longitude: -0.121, Timestamp: start.Add(time.Minute)}
+	older := models.Location{SessionID: "late-walk", Latitude: 51.5, Longitude: -0.12, Timestamp: start}
+
+	require.NoError(t, service.TrackLocation(newer))
+	require.NoError(t, service.TrackLocation(older))
+
+	route, err := service.GetSessionRoute("late-walk", false)
+	require.NoError(t, err)
+	require.Len(t, route, 2)
+	assert.True(t, route[0].Timestamp.Equal(start))
+	assert.True(t, route[0].Late)
+	assert.False(t, route[1].Late)
+}
+
+// TestPrivacyZoneMasking checks that points inside a walker's privacy zone are stored but
+// left out of the route shown to owners
+func TestPrivacyZoneMasking(t *testing.T) {
+	repository.UseMemoryStore()
+
+	session := models.NewSession("private-walk", "booking-1", "walker-private", "owner-1")
+	require.NoError(t, repository.InsertSession(*session))
+
+	_, err := service.CreatePrivacyZone("walker-private", models.PrivacyZone{
+		Name: "Home", Latitude: 51.5, Longitude: -0.12, RadiusMeters: 100,
+	})
+	require.NoError(t, err)
+
+	start := time.Now().Add(-5 * time.Minute).UTC()
+	home := models.Location{SessionID: session.ID, Latitude: 51.5002, Longitude: -0.12, Timestamp: start}
+	park := models.Location{SessionID: session.ID, Latitude: 51.51, Longitude: -0.12, Timestamp: start.Add(time.Minute)}
+	require.NoError(t, service.TrackLocation(home))
+	require.NoError(t, service.TrackLocation(park))
+
+	stored, err := repository.FindLocationsBySession(session.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+
+	route, err := service.GetSessionRoute(session.ID, false)
+	require.NoError(t, err)
+	require.Len(t, route, 1)
+	assert.Equal(t, park.Latitude, route[0].Latitude)
+}
+
+// TestGeohash checks cells against the reference encoding and that neighbours surround a cell
+func TestGeohash(t *testing.T) {
+	assert.Equal(t, "u4pruydqqvj", geohash.Encode(57.64911, 10.40744, 11))
+
+	box, err := geohash.Decode("u4pruydqqvj")
+	require.NoError(t, err)
+	latitude, longitude := box.Center()
+	assert.InDelta(t, 57.64911, latitude, 0.0001)
+	assert.InDelta(t, 10.40744, longitude, 0.0001)
+
+	neighbors, err := geohash.Neighbors("gcpvj0")
+	require.NoError(t, err)
+	assert.Len(t, neighbors, 8)
+	assert.NotContains(t, neighbors, "gcpvj0")
+
+	_, err = geohash.Decode("gcpva")
+	assert.Error(t, err)
+}
+
+// TestProximityAndHeatmap checks that broadcast points keep the walker's position current for
+// proximity searches and are counted per cell in the heatmap
+func TestProximityAndHeatmap(t *testing.T) {
+	repository.UseMemoryStore()
+
+	require.NoError(t, repository.InsertSession(*models.NewSession("nearby-walk", "nearby-booking", "nearby-walker", "nearby-owner")))
+	require.NoError(t, repository.InsertSession(*models.NewSession("far-walk", "far-booking", "far-walker", "far-owner")))
+
+	now := time.Now().Add(-time.Minute).UTC()
+	require.NoError(t, service.TrackLocation(models.Location{SessionID: "nearby-walk", Latitude: 51.5010, Longitude: -0.1410, Timestamp: now.Add(-time.Second)}))
+	require.NoError(t, service.TrackLocation(models.Location{SessionID: "nearby-walk", Latitude: 51.5014, Longitude: -0.1419, Timestamp: now}))
+	require.NoError(t, service.TrackLocation(models.Location{SessionID: "far-walk", Latitude: 51.5310, Longitude: -0.1240, Timestamp: now}))
+
+	nearby, err := service.NearbyWalkers(51.5007, -0.1416, 500, 0)
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, "nearby-walker", nearby[0].WalkerID)
+	assert.Less(t, nearby[0].DistanceMeters, 500.0)
+
+	_, err = service.NearbyWalkers(51.5007, -0.1416, 50000, 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid proximity query")
+
+	last, err := service.LastWalkerPosition("nearby-walker")
+	require.NoError(t, err)
+	assert.Equal(t, "nearby-walk", last.SessionID)
+	assert.InDelta(t, 51.5014, last.Latitude, 0.001)
+	assert.InDelta(t, -0.1419, last.Longitude, 0.001)
+
+	_, err = service.LastWalkerPosition("unseen-walker")
+	assert.ErrorIs(t, err, service.ErrPositionNotFound)
+
+	cells, err := service.LocationHeatmap(now.Add(-time.Hour), now.Add(time.Second), 5)
+	require.NoError(t, err)
+	var total int64
+	for _, cell := range cells {
+		assert.Len(t, cell.Cell, 5)
+		total += cell.Count
+	}
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, int64(2), cells[0].Count)
+}
+
+// TestWalkEvidence checks that photos are only recorded during a walk and that a booking's
+// evidence sums the duration, distance and photos of its sessions
+func TestWalkEvidence(t *testing.T) {
+	repository.UseMemoryStore()
+
+	session := models.NewSession("evidence-walk", "evidence-booking", "evidence-walker", "evidence-owner")
+	session.StartedAt = time.Now().Add(-30 * time.Minute)
+	require.NoError(t, repository.InsertSession(*session))
+
+	start := time.Now().Add(-20 * time.Minute).UTC()
+	require.NoError(t, service.TrackLocation(models.Location{SessionID: "evidence-walk", Latitude: 51.5000, Longitude: -0.1400, Timestamp: start}))
+	require.NoError(t, service.TrackLocation(models.Location{SessionID: "evidence-walk", Latitude: 51.5090, Longitude: -0.1400, Timestamp: start.Add(10 * time.Minute)}))
+
+	_, err := service.RecordWalkPhoto("evidence-walk", models.WalkPhoto{URL: "not a url"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid photo")
+
+	photo, err := service.RecordWalkPhoto("evidence-walk", models.WalkPhoto{URL: "https://photos.example.com/dog.jpg"})
+	require.NoError(t, err)
+	assert.False(t, photo.TakenAt.IsZero())
+
+	evidence, err := service.BookingWalkEvidence("evidence-booking")
+	require.NoError(t, err)
+	assert.Equal(t, 1, evidence.Sessions)
+	assert.True(t, evidence.Active)
+	assert.Equal(t, 1, evidence.Photos)
+	assert.InDelta(t, 1000, evidence.DistanceMeters, 10)
+	assert.InDelta(t, 30*60, evidence.DurationSeconds, 5)
+
+	require.NoError(t, service.EndSession(context.Background(), "evidence-walk"))
+	_, err = service.RecordWalkPhoto("evidence-walk", models.WalkPhoto{URL: "https://photos.example.com/late.jpg"})
+	assert.ErrorIs(t, err, service.ErrSessionNotFound)
+
+	evidence, err = service.BookingWalkEvidence("evidence-booking")
+	require.NoError(t, err)
+	assert.False(t, evidence.Active)
+
+	none, err := service.BookingWalkEvidence("unwalked-booking")
+	require.NoError(t, err)
+	assert.Zero(t, none.Sessions)
+}
@@ -0,0 +1,223 @@
+//go:build integration
+
+// Integration tests run the real repository against a throwaway MongoDB container.
+// Run with: go test -tags integration ./test/...
+package test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"        // v3.10.0
+	"github.com/ory/dockertest/v3/docker" // v3.10.0
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+	"go.mongodb.org/mongo-driver/bson"
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// Human Tasks:
+// 1. Ensure Docker is available to the test runner (DOCKER_HOST is honoured)
+// 2. Run the integration suite in CI on every change to internal/repository
+
+// idCounter keeps document IDs unique across tests sharing the database
+var idCounter atomic.Int64
+
+// TestMain starts MongoDB, creates the indexes and tears the container down afterwards
+func TestMain(m *testing.M) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		log.Fatalf("Failed to connect to Docker: %v", err)
+	}
+	pool.MaxWait = 2 * time.Minute
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "mongo",
+		Tag:        "6.0",
+	}, func(hostConfig *docker.HostConfig) {
+		hostConfig.AutoRemove = true
+		hostConfig.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		log.Fatalf("Failed to start MongoDB: %v", err)
+	}
+	// Reap the container even if the test binary is killed
+	resource.Expire(600)
+
+	cfg := config.Config{
+		DatabaseURI:        fmt.Sprintf("mongodb://%s", resource.GetHostPort("27017/tcp")),
+		BatchSize:          10,
+		BatchFlushInterval: 50 * time.Millisecond,
+	}
+	if err := pool.Retry(func() error { return repository.Initialize(cfg) }); err != nil {
+		pool.Purge(resource)
+		log.Fatalf("MongoDB did not become ready: %v", err)
+	}
+
+	if err := repository.EnsureIndexes(context.Background()); err != nil {
+		pool.Purge(resource)
+		log.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	code := m.Run()
+
+	repository.Close(context.Background())
+	pool.Purge(resource)
+	os.Exit(code)
+}
+
+// newID returns an ID unique within the test run
+func newID(prefix string) string {
+	return fmt.Sprintf("%s-%d", prefix, idCounter.Add(1))
+}
+
+// TestEnsureIndexesIsIdempotent verifies indexes can be ensured on every start
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func TestEnsureIndexesIsIdempotent(t *testing.T) {
+	require.NoError(t, repository.EnsureIndexes(context.Background()))
+	require.NoError(t, repository.Ping(context.Background()))
+}
+
+// TestLocationPersistence verifies batched and direct inserts are queryable by time and session
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func TestLocationPersistence(t *testing.T) {
+	sessionID := newID("session")
+	start := time.Now().Add(-time.Hour).Truncate(time.Millisecond).UTC()
+	accuracy := 6.5
+
+	for i := 0; i < 5; i++ {
+		location := models.Location{
+			SessionID:      sessionID,
+			Latitude:       40.7128 + float64(i)*0.0001,
+			Longitude:      -74.0060,
+			Timestamp:      start.Add(time.Duration(i) * time.Minute),
+			AccuracyMeters: &accuracy,
+		}
+		require.NoError(t, repository.EnqueueLocation(location))
+	}
+
+	// Batched points are written within the flush interval
+	var route []models.Location
+	require.Eventually(t, func() bool {
+		var err error
+		route, err = repository.FindLocationsBySession(sessionID)
+		return err == nil && len(route) == 5
+	}, 5*time.Second, 50*time.Millisecond)
+
+	for i, point := range route {
+		assert.True(t, start.Add(time.Duration(i)*time.Minute).Equal(point.Timestamp), "route should be ordered by timestamp")
+		require.NotNil(t, point.AccuracyMeters)
+		assert.Equal(t, accuracy, *point.AccuracyMeters)
+	}
+
+	inRange, err := repository.FindLocationsByTimeRange(start.Add(90*time.Second), start.Add(200*time.Second))
+	require.NoError(t, err)
+	count := 0
+	for _, point := range inRange {
+		if point.SessionID == sessionID {
+			count++
+		}
+	}
+	assert.Equal(t, 2, count)
+
+	latest, err := repository.FindLatestLocation(sessionID)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.True(t, start.Add(4*time.Minute).Equal(latest.Timestamp))
+
+	none, err := repository.FindLatestLocation(newID("session"))
+	require.NoError(t, err)
+	assert.Nil(t, none)
+}
+
+// TestSessionLifecycle verifies sessions can be started, listed as active and ended once
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func TestSessionLifecycle(t *testing.T) {
+	session := models.NewSession(newID("session"), newID("booking"), newID("walker"), newID("owner"))
+	require.NoError(t, repository.InsertSession(*session))
+
+	stored, err := repository.FindSessionByID(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, session.BookingID, stored.BookingID)
+	assert.Equal(t, models.SessionStatusActive, stored.Status)
+
+	active, err := repository.FindActiveSessions()
+	require.NoError(t, err)
+	assert.True(t, containsSession(active, session.ID))
+
+	require.NoError(t, repository.EndSession(session.ID, time.Now()))
+	assert.ErrorIs(t, repository.EndSession(session.ID, time.Now()), repository.ErrSessionNotFound)
+
+	active, err = repository.FindActiveSessions()
+	require.NoError(t, err)
+	assert.False(t, containsSession(active, session.ID))
+}
+
+// TestIncidentConditionalUpdate verifies incident updates fail when the status changed underneath them
+// Addresses requirement: Real-time location tracking
+// Location: 1.2 System Overview/High-Level Description/Backend Services
+func TestIncidentConditionalUpdate(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond).UTC()
+	incident := models.Incident{
+		ID:         newID("incident"),
+		Type:       models.IncidentTypeInjury,
+		Status:     models.IncidentStatusOpen,
+		SessionID:  newID("session"),
+		ReportedBy: newID("walker"),
+		Message:    "Dog cut its paw",
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	require.NoError(t, repository.InsertIncident(incident))
+
+	require.NoError(t, repository.UpdateIncident(incident.ID, models.IncidentStatusOpen,
+		bson.M{"status": models.IncidentStatusInvestigating, "updated_at": now}))
+
+	// A second writer still expecting "open" loses
+	assert.ErrorIs(t, repository.UpdateIncident(incident.ID, models.IncidentStatusOpen,
+		bson.M{"status": models.IncidentStatusResolved}), repository.ErrIncidentChanged)
+
+	require.NoError(t, repository.AddIncidentAttachment(incident.ID, models.Attachment{
+		MediaID:     newID("media"),
+		ContentType: "image/jpeg",
+		AddedBy:     incident.ReportedBy,
+		AddedAt:     now,
+	}))
+
+	stored, err := repository.FindIncidentByID(incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentStatusInvestigating, stored.Status)
+	assert.Len(t, stored.Attachments, 1)
+
+	queue, err := repository.FindIncidentQueue([]models.IncidentStatus{models.IncidentStatusInvestigating}, models.IncidentTypeInjury, "", 100)
+	require.NoError(t, err)
+	found := false
+	for _, queued := range queue {
+		found = found || queued.ID == incident.ID
+	}
+	assert.True(t, found, "investigating incident should be queued")
+
+	_, err = repository.FindIncidentByID(newID("incident"))
+	assert.ErrorIs(t, err, repository.ErrIncidentNotFound)
+}
+
+// containsSession reports whether sessions includes id
+func containsSession(sessions []models.Session, id string) bool {
+	for _, session := range sessions {
+		if session.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+	"go.uber.org/goleak"                     // v1.2.1
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestLifecycleStopsGoroutines checks that stopping the hub, its clients, the location batch
+// writer and the session staleness monitors in shutdown order leaves none of their goroutines
+// running, and that a stopped hub drops what is published to it instead of blocking
+func TestLifecycleStopsGoroutines(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	// A walk in progress when the instance starts is watched for staleness
+	repository.UseMemoryStore()
+	session := models.NewSession("lifecycle-walk", "lifecycle-booking", "lifecycle-walker", "lifecycle-owner")
+	require.NoError(t, repository.InsertSession(*session))
+
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{
+		TokenSecret:         "lifecycle-secret",
+		TokenTTL:            time.Minute,
+		StaleAfter:          time.Minute,
+		MaxAccuracyMeters:   100,
+		ChatRetention:       90 * 24 * time.Hour,
+		ChatReportRetention: 365 * 24 * time.Hour,
+	}, hub)
+	go hub.Run()
+
+	// A client connected over a real WebSocket runs its read and write pumps
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		websocket.NewClient(hub, conn, "lifecycle-walk").Serve()
+	}))
+	conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return hub.GetConnectedClients() == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	writer := repository.NewBatchWriter(10, 10*time.Millisecond)
+	writer.Start()
+	require.NoError(t, writer.Enqueue(models.Location{
+		SessionID: "lifecycle-walk", Latitude: 51.5, Longitude: -0.14, Timestamp: time.Now(),
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, service.Stop(ctx))
+	hub.CloseAllConnections()
+	require.NoError(t, hub.Stop(ctx))
+	require.NoError(t, writer.Stop(ctx))
+	assert.ErrorIs(t, writer.Enqueue(models.Location{SessionID: "lifecycle-walk"}), repository.ErrWriterClosed)
+	conn.Close()
+	server.Close()
+
+	stored, err := repository.FindLocationsBySession("lifecycle-walk")
+	require.NoError(t, err)
+	assert.Len(t, stored, 1, "buffered points are flushed before the writer stops")
+
+	hub.Publish("lifecycle-walk", websocket.KindLocation, `{"latitude":51.5}`)
+	assert.Equal(t, uint64(1), hub.Status().Dropped["hub_stopped"])
+	assert.NoError(t, hub.Stop(ctx), "stopping twice is harmless")
+}
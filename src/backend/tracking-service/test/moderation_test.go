@@ -0,0 +1,81 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/shared/moderation"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestChatModeration checks that blocked chat messages are rejected, that flagged ones are
+// delivered and queued for review, and that removing flagged content takes the message down
+func TestChatModeration(t *testing.T) {
+	repository.UseMemoryStore()
+	service.Hub = websocket.NewHub()
+	go service.Hub.Run()
+
+	moderation.Init(moderation.Options{BlockedTerms: []string{"idiot"}, FlaggedTerms: []string{"cash only", "bit"}})
+	t.Cleanup(func() { moderation.Init(moderation.Options{}) })
+
+	_, err := service.SendChatMessage("moderated-booking", models.ChatMessage{
+		SenderID: "moderated-owner", SenderRole: models.ConsentRoleOwner, Body: "You IDIOT!",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid chat message")
+
+	// Terms match whole words only
+	_, err = service.SendChatMessage("moderated-booking", models.ChatMessage{
+		SenderID: "moderated-owner", SenderRole: models.ConsentRoleOwner, Body: "Just a bite of his biscuit",
+	})
+	require.NoError(t, err)
+	pending, err := service.ListFlaggedContent("", 0)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	first, err := service.SendChatMessage("moderated-booking", models.ChatMessage{
+		SenderID: "moderated-walker", SenderRole: models.ConsentRoleWalker, Body: "Cash only, please",
+	})
+	require.NoError(t, err)
+	second, err := service.SendChatMessage("moderated-booking", models.ChatMessage{
+		SenderID: "moderated-walker", SenderRole: models.ConsentRoleWalker, Body: "He bit me",
+	})
+	require.NoError(t, err)
+
+	pending, err = service.ListFlaggedContent(models.FlaggedStatusPending, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, first.ID, pending[0].SourceID)
+	assert.Equal(t, []string{"cash only"}, pending[0].Matches)
+
+	_, err = service.ReviewFlaggedContent(pending[0].ID, "moderator", "approved")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid review")
+
+	dismissed, err := service.ReviewFlaggedContent(pending[0].ID, "moderator", models.FlaggedStatusDismissed)
+	require.NoError(t, err)
+	assert.Equal(t, "moderator", dismissed.ReviewedBy)
+	_, err = service.ReviewFlaggedContent(pending[0].ID, "moderator", models.FlaggedStatusRemoved)
+	assert.ErrorIs(t, err, service.ErrFlaggedContentReviewed)
+	_, err = repository.FindChatMessageByID("moderated-booking", first.ID)
+	assert.NoError(t, err)
+
+	_, err = service.ReviewFlaggedContent(pending[1].ID, "moderator", models.FlaggedStatusRemoved)
+	require.NoError(t, err)
+	_, err = repository.FindChatMessageByID("moderated-booking", second.ID)
+	assert.ErrorIs(t, err, repository.ErrChatMessageNotFound)
+
+	removed, err := service.ListFlaggedContent(models.FlaggedStatusRemoved, 0)
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "He bit me", removed[0].Text)
+}
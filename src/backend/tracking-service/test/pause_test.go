@@ -0,0 +1,180 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/export"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestWalkPauses checks that a paused walk marks its points paused in storage and on the live
+// stream, announces the pause and resume, and leaves paused time and distance out of evidence
+func TestWalkPauses(t *testing.T) {
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{
+		TokenSecret:         "pause-secret",
+		TokenTTL:            time.Minute,
+		StaleAfter:          time.Minute,
+		MaxAccuracyMeters:   100,
+		ChatRetention:       90 * 24 * time.Hour,
+		ChatReportRetention: 365 * 24 * time.Hour,
+	}, hub)
+
+	var mu sync.Mutex
+	var events []string
+	var broadcast []models.Location
+	hub.Observe(func(message websocket.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch message.Kind {
+		case websocket.KindEvent:
+			var event struct {
+				Event string `json:"event"`
+			}
+			if json.Unmarshal([]byte(message.Data), &event) == nil {
+				events = append(events, event.Event)
+			}
+		case websocket.KindLocation:
+			var location models.Location
+			if json.Unmarshal([]byte(message.Data), &location) == nil {
+				broadcast = append(broadcast, location)
+			}
+		}
+	})
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	session := models.NewSession("paused-walk", "paused-booking", "paused-walker", "paused-owner")
+	session.StartedAt = time.Now().Add(-30 * time.Minute)
+	require.NoError(t, repository.InsertSession(*session))
+
+	track := func(latitude float64, timestamp time.Time) {
+		require.NoError(t, service.TrackLocation(models.Location{
+			SessionID: "paused-walk", Latitude: latitude, Longitude: -0.14, Timestamp: timestamp,
+		}))
+	}
+
+	track(51.5, time.Now())
+	time.Sleep(10 * time.Millisecond)
+
+	paused, err := service.PauseSession(context.Background(), "paused-walk")
+	require.NoError(t, err)
+	assert.True(t, paused.Paused())
+	_, err = service.PauseSession(context.Background(), "paused-walk")
+	assert.ErrorIs(t, err, service.ErrSessionPaused)
+
+	// Wandering round the store while paused
+	time.Sleep(10 * time.Millisecond)
+	track(51.509, time.Now())
+	time.Sleep(10 * time.Millisecond)
+
+	resumed, err := service.ResumeSession(context.Background(), "paused-walk")
+	require.NoError(t, err)
+	assert.False(t, resumed.Paused())
+	require.Len(t, resumed.Pauses, 1)
+	pause := resumed.Pauses[0]
+	_, err = service.ResumeSession(context.Background(), "paused-walk")
+	assert.ErrorIs(t, err, service.ErrSessionNotPaused)
+
+	// A point from inside the pause arriving late is still marked paused. It was recorded right
+	// after the pause began, so before the point tracked during the pause and late whatever the
+	// timing of the test
+	track(51.508, pause.PausedAt.Add(time.Millisecond))
+
+	time.Sleep(10 * time.Millisecond)
+	track(51.5, time.Now())
+	track(51.509, time.Now())
+
+	stored, err := repository.FindLocationsBySession("paused-walk")
+	require.NoError(t, err)
+	require.Len(t, stored, 5)
+	var flags []bool
+	for _, point := range stored {
+		flags = append(flags, point.Paused)
+	}
+	assert.Equal(t, []bool{false, true, true, false, false}, flags)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(broadcast) == 4 && len(events) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, []string{service.EventWalkPaused, service.EventWalkResumed}, events)
+	assert.True(t, broadcast[1].Paused)
+	assert.False(t, broadcast[2].Paused)
+	mu.Unlock()
+
+	// Only the kilometer walked after resuming counts
+	evidence, err := service.BookingWalkEvidence("paused-booking")
+	require.NoError(t, err)
+	assert.InDelta(t, 1000, evidence.DistanceMeters, 10)
+	assert.InDelta(t, pause.ResumedAt.Sub(pause.PausedAt).Seconds(), evidence.PausedSeconds, 0.001)
+	assert.InDelta(t, 30*60, evidence.DurationSeconds, 5)
+
+	require.NoError(t, service.EndSession(context.Background(), "paused-walk"))
+	_, err = service.PauseSession(context.Background(), "paused-walk")
+	assert.ErrorIs(t, err, service.ErrSessionNotFound)
+}
+
+// TestPausedTime checks that paused time is counted up to the end of the walk, including a
+// pause the walk ended in
+func TestPausedTime(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	pausedAt := start.Add(50 * time.Minute)
+	session := models.Session{
+		StartedAt: start,
+		EndedAt:   &end,
+		PausedAt:  &pausedAt,
+		Pauses:    []models.WalkPause{{PausedAt: start.Add(10 * time.Minute), ResumedAt: start.Add(20 * time.Minute)}},
+	}
+
+	assert.Equal(t, 20*time.Minute, session.PausedBetween(start, end, end.Add(time.Hour)))
+	assert.Equal(t, 5*time.Minute, session.PausedBetween(start.Add(15*time.Minute), start.Add(30*time.Minute), end))
+	assert.Len(t, session.AllPauses(end), 2)
+	assert.True(t, session.Paused())
+}
+
+// TestPausedExport checks that exports mark the points recorded while a walk was paused
+func TestPausedExport(t *testing.T) {
+	points := exportPoints()[:2]
+	points = append(points, points[1], points[0])
+	points[1].Paused, points[2].Paused = true, true
+
+	csv := strings.Split(strings.TrimSpace(string(encodeAll(t, models.ExportFormatCSV, points))), "\n")
+	assert.True(t, strings.HasSuffix(csv[0], ",paused"))
+	assert.True(t, strings.HasSuffix(csv[2], ",true"))
+	assert.True(t, strings.HasSuffix(csv[4], ",false"))
+
+	gpx := encodeAll(t, models.ExportFormatGPX, points)
+	assert.Equal(t, 3, bytes.Count(gpx, []byte("<trkseg>")))
+	assert.Equal(t, 2, bytes.Count(gpx, []byte("<type>paused</type>")))
+
+	var buf bytes.Buffer
+	encoder, err := export.NewEncoder(models.ExportFormatGeoJSON, &buf)
+	require.NoError(t, err)
+	for _, point := range points {
+		require.NoError(t, encoder.Write(point))
+	}
+	require.NoError(t, encoder.Close())
+	assert.Equal(t, 2, strings.Count(buf.String(), `"paused":true`))
+}
@@ -0,0 +1,81 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// presenceFrame is the part of a control frame the presence tests look at
+type presenceFrame struct {
+	Control     string `json:"control"`
+	UserID      string `json:"user_id"`
+	Status      string `json:"status"`
+	ExpiresInMs int64  `json:"expires_in_ms"`
+}
+
+// TestChatPresence checks that chat participants are told who else is connected, see each
+// other's typing and are told when the other participant's connection drops
+func TestChatPresence(t *testing.T) {
+	hub := websocket.NewHub()
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := websocket.NewClient(hub, conn, "chat:presence-booking")
+		client.UserID = r.URL.Query().Get("user")
+		client.Role = r.URL.Query().Get("role")
+		client.Serve()
+	}))
+	t.Cleanup(server.Close)
+
+	connect := func(user, role string) *gorillaws.Conn {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "?user=" + user + "&role=" + role
+		conn, _, err := gorillaws.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	next := func(conn *gorillaws.Conn) presenceFrame {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var f presenceFrame
+		require.NoError(t, json.Unmarshal(data, &f))
+		return f
+	}
+
+	owner := connect("presence-owner", "owner")
+	assert.Equal(t, presenceFrame{Control: "presence", UserID: "presence-owner", Status: "online"}, next(owner))
+
+	// A participant joining later is told who is already there, then announced
+	walker := connect("presence-walker", "walker")
+	assert.Equal(t, presenceFrame{Control: "presence", UserID: "presence-owner", Status: "online"}, next(walker))
+	assert.Equal(t, presenceFrame{Control: "presence", UserID: "presence-walker", Status: "online"}, next(owner))
+	assert.Equal(t, presenceFrame{Control: "presence", UserID: "presence-walker", Status: "online"}, next(walker))
+
+	// Repeats within the throttle interval are dropped; a change of status is not
+	require.NoError(t, walker.WriteMessage(gorillaws.TextMessage, []byte(`{"control":"typing","status":"typing"}`)))
+	require.NoError(t, walker.WriteMessage(gorillaws.TextMessage, []byte(`{"control":"typing","status":"typing"}`)))
+	require.NoError(t, walker.WriteMessage(gorillaws.TextMessage, []byte(`{"control":"typing","status":"idle"}`)))
+	assert.Equal(t, presenceFrame{Control: "typing", UserID: "presence-walker", Status: "typing", ExpiresInMs: 5000}, next(owner))
+	assert.Equal(t, presenceFrame{Control: "typing", UserID: "presence-walker", Status: "idle"}, next(owner))
+
+	walker.Close()
+	assert.Equal(t, presenceFrame{Control: "presence", UserID: "presence-walker", Status: "offline"}, next(owner))
+}
@@ -0,0 +1,85 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/shared/clients"
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/handlers"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+)
+
+// TestPrivacyZonesRequireWalker checks that a walker's privacy zones can only be listed,
+// created and deleted by the walker or an admin
+func TestPrivacyZonesRequireWalker(t *testing.T) {
+	repository.UseMemoryStore()
+
+	zones := auth.RequireMethod(testJWTSecret, policy.ResourcePrivacyZones)(handlers.WalkerPrivacyZonesHandler)
+	walker := userToken(t, "zoned-walker", policy.RoleWalker)
+	path := "/api/v1/walkers/zoned-walker/privacy-zones"
+	home := map[string]interface{}{"name": "Home", "latitude": 51.5, "longitude": -0.14, "radius_meters": 200}
+
+	rec := callAs(zones, "", http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = callAs(zones, userToken(t, "zoned-owner", policy.RoleOwner), http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "owners have no privacy zones")
+	rec = callAs(zones, userToken(t, "other-walker", policy.RoleWalker), http.MethodPost, path, home)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "walkers cannot define zones for each other")
+
+	rec = callAs(zones, walker, http.MethodPost, path, home)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created models.PrivacyZone
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "zoned-walker", created.WalkerID)
+
+	rec = callAs(zones, userToken(t, "other-walker", policy.RoleWalker), http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	rec = callAs(zones, userToken(t, "other-walker", policy.RoleWalker), http.MethodDelete, path+"/"+created.ID, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = callAs(zones, userToken(t, "support", policy.RoleAdmin), http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed struct {
+		PrivacyZones []models.PrivacyZone `json:"privacy_zones"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	assert.Len(t, listed.PrivacyZones, 1)
+
+	rec = callAs(zones, walker, http.MethodDelete, path+"/"+created.ID, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	rec = callAs(zones, walker, http.MethodPatch, path, home)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "walkers cannot update zones")
+}
+
+// TestWalkerPositionRequiresServiceKey checks that walkers' last positions are only answered to
+// the backend services holding the service API key
+func TestWalkerPositionRequiresServiceKey(t *testing.T) {
+	repository.UseMemoryStore()
+
+	position := func(configured, provided string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/walkers/positioned-walker/position", nil)
+		if provided != "" {
+			req.Header.Set(clients.APIKeyHeader, provided)
+		}
+		rec := httptest.NewRecorder()
+		auth.RequireAPIKey(configured)(handlers.WalkerPositionHandler)(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, position("service-key", ""))
+	assert.Equal(t, http.StatusUnauthorized, position("service-key", "guessed-key"))
+	assert.Equal(t, http.StatusUnauthorized, position("", ""), "no key configured admits nobody")
+	assert.Equal(t, http.StatusNotFound, position("service-key", "service-key"))
+}
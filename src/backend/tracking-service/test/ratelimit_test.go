@@ -0,0 +1,144 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// rateLimitServer serves hub clients subscribed to the topic in the query string, dispatch
+// connections when asked for
+func rateLimitServer(t *testing.T, hub *websocket.Hub) func(query string) *gorillaws.Conn {
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := websocket.NewClient(hub, conn, r.URL.Query().Get("topic"))
+		client.Dispatch = r.URL.Query().Get("dispatch") == "true"
+		client.Serve()
+	}))
+	t.Cleanup(server.Close)
+
+	return func(query string) *gorillaws.Conn {
+		conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?"+query, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+}
+
+// drainFrames reads frames from conn until none arrives for a while, returning their payloads
+func drainFrames(t *testing.T, conn *gorillaws.Conn) []string {
+	var payloads []string
+	for {
+		conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return payloads
+		}
+		var f struct {
+			Control string          `json:"control"`
+			Payload json.RawMessage `json:"payload"`
+		}
+		require.NoError(t, json.Unmarshal(data, &f))
+		if f.Control != "" {
+			payloads = append(payloads, f.Control)
+		} else {
+			payloads = append(payloads, string(f.Payload))
+		}
+	}
+}
+
+// TestSessionRateLimit checks that a burst published to one walk is cut off at the session
+// rate without holding back other walks or SOS alerts
+func TestSessionRateLimit(t *testing.T) {
+	hub := websocket.NewHub()
+	hub.LimitRates(websocket.RateLimits{SessionRate: 5})
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	connect := rateLimitServer(t, hub)
+	noisy := connect("topic=limited-walk-1")
+	quiet := connect("topic=limited-walk-2")
+	require.Eventually(t, func() bool { return hub.Subscribers("limited-walk-1") == 1 }, 2*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return hub.Subscribers("limited-walk-2") == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 30; i++ {
+		hub.Publish("limited-walk-1", websocket.KindLocation, `{"latitude":51.5}`)
+	}
+	hub.Publish("limited-walk-1", websocket.KindSOS, `{"alert":"sos"}`)
+	hub.Publish("limited-walk-2", websocket.KindLocation, `{"latitude":48.8}`)
+
+	received := drainFrames(t, noisy)
+	assert.GreaterOrEqual(t, len(received), 10)
+	assert.Less(t, len(received), 20)
+	assert.Contains(t, received, `{"alert":"sos"}`)
+	assert.Equal(t, []string{`{"latitude":48.8}`}, drainFrames(t, quiet))
+}
+
+// TestTenantFanoutLimit checks that the deliveries of a tenant's walks are limited together,
+// counting one per subscriber, while other tenants keep receiving theirs
+func TestTenantFanoutLimit(t *testing.T) {
+	hub := websocket.NewHub()
+	hub.LimitRates(websocket.RateLimits{TenantFanout: 5})
+	hub.TenantOf(func(topic string) string {
+		if strings.HasPrefix(topic, "fanout-busy") {
+			return "walker:busy"
+		}
+		return ""
+	})
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	connect := rateLimitServer(t, hub)
+	var viewers []*gorillaws.Conn
+	for _, topic := range []string{"fanout-busy-1", "fanout-busy-1", "fanout-busy-2"} {
+		viewers = append(viewers, connect("topic="+topic))
+	}
+	other := connect("topic=fanout-other")
+	require.Eventually(t, func() bool { return hub.Subscribers("fanout-busy-1") == 2 }, 2*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return hub.Subscribers("fanout-busy-2") == 1 }, 2*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return hub.Subscribers("fanout-other") == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	// Each message to the first walk is two deliveries, so its burst of 10 admits five
+	for i := 0; i < 10; i++ {
+		hub.Publish("fanout-busy-1", websocket.KindLocation, `{"latitude":51.5}`)
+	}
+	hub.Publish("fanout-busy-2", websocket.KindLocation, `{"latitude":51.6}`)
+	hub.Publish("fanout-other", websocket.KindLocation, `{"latitude":48.8}`)
+
+	assert.Len(t, drainFrames(t, viewers[0]), 5)
+	assert.Empty(t, drainFrames(t, viewers[2]))
+	assert.Equal(t, []string{`{"latitude":48.8}`}, drainFrames(t, other))
+}
+
+// TestConnectionRateLimit checks that messages a client sends beyond the connection rate are
+// ignored
+func TestConnectionRateLimit(t *testing.T) {
+	hub := websocket.NewHub()
+	hub.LimitRates(websocket.RateLimits{ConnectionRate: 2})
+	hub.Channel("limited", "dispatch:limited")
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	dispatcher := rateLimitServer(t, hub)("dispatch=true")
+	for i := 0; i < 20; i++ {
+		require.NoError(t, dispatcher.WriteMessage(gorillaws.TextMessage, []byte(`{"control":"subscribe","channel":"limited"}`)))
+	}
+
+	acks := drainFrames(t, dispatcher)
+	assert.GreaterOrEqual(t, len(acks), 4)
+	assert.Less(t, len(acks), 10)
+}
@@ -0,0 +1,331 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/fixtures"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// updateGolden rewrites the expected replay outcomes from the current pipeline; review the
+// diff before committing it
+var updateGolden = flag.Bool("update", false, "rewrite testdata/replay golden files")
+
+// replayDir holds captured walk fixtures and the outcome each must produce
+const replayDir = "testdata/replay"
+
+// replayLatitude and replayLongitude are where fixtures are replayed
+const (
+	replayLatitude  = 51.5072
+	replayLongitude = -0.1276
+)
+
+// replayOutcome is what ingesting a fixture produced: how each point was handled, and the
+// route kept for history and summaries
+type replayOutcome struct {
+	Points     int `json:"points"`
+	Stored     int `json:"stored"`
+	Broadcast  int `json:"broadcast"`
+	Late       int `json:"late"`
+	Withheld   int `json:"withheld"`
+	Duplicates int `json:"duplicates"`
+
+	// Anomalies lists every point not broadcast live as it arrived, in arrival order
+	Anomalies []replayAnomaly `json:"anomalies"`
+
+	// DurationSeconds and DistanceMeters summarize the stored route
+	DurationSeconds float64 `json:"duration_seconds"`
+	DistanceMeters  float64 `json:"distance_meters"`
+}
+
+// replayAnomaly is a point that was not broadcast live and why
+type replayAnomaly struct {
+	Arrival   int    `json:"arrival"`
+	DeviceSeq int64  `json:"device_seq"`
+	Outcome   string `json:"outcome"`
+}
+
+// replayHarness ingests fixtures through the service with a running hub, recording the points
+// the hub broadcast
+type replayHarness struct {
+	hub *websocket.Hub
+
+	mu        sync.Mutex
+	broadcast map[string]map[int64]int
+	done      map[string]chan struct{}
+}
+
+// newReplayHarness initializes the service against the in-memory store and a running hub
+func newReplayHarness(tb testing.TB) *replayHarness {
+	repository.UseMemoryStore()
+	harness := &replayHarness{
+		hub:       websocket.NewHub(),
+		broadcast: make(map[string]map[int64]int),
+		done:      make(map[string]chan struct{}),
+	}
+	service.Initialize(config.Config{
+		TokenSecret:         "replay-secret",
+		TokenTTL:            time.Minute,
+		StaleAfter:          time.Minute,
+		MaxAccuracyMeters:   100,
+		ChatRetention:       90 * 24 * time.Hour,
+		ChatReportRetention: 365 * 24 * time.Hour,
+	}, harness.hub)
+	harness.hub.Observe(harness.observe)
+	go harness.hub.Run()
+	tb.Cleanup(harness.hub.CloseAllConnections)
+	return harness
+}
+
+// observe records broadcast points, and the end marker published after a replay
+func (h *replayHarness) observe(message websocket.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch message.Kind {
+	case websocket.KindLocation:
+		var location models.Location
+		if json.Unmarshal([]byte(message.Data), &location) == nil && location.DeviceSeq != nil {
+			if h.broadcast[message.Topic] == nil {
+				h.broadcast[message.Topic] = make(map[int64]int)
+			}
+			h.broadcast[message.Topic][*location.DeviceSeq]++
+		}
+	case websocket.KindSessionEnded:
+		if done, ok := h.done[message.Topic]; ok {
+			close(done)
+			delete(h.done, message.Topic)
+		}
+	}
+}
+
+// replay ingests a fixture as a new walk and waits until the hub has handled every point.
+// The hub loop handles messages in the order they are published, so once the end marker
+// published after the last point is observed, every broadcast has been observed too.
+func (h *replayHarness) replay(tb testing.TB, sessionID string, points []models.Location) {
+	done := make(chan struct{})
+	h.mu.Lock()
+	h.done[sessionID] = done
+	h.mu.Unlock()
+
+	start := time.Now().Add(-24 * time.Hour).UTC()
+	for _, point := range fixtures.Rebase(points, sessionID, replayLatitude, replayLongitude, start) {
+		require.NoError(tb, service.TrackLocation(point))
+	}
+	h.hub.Publish(sessionID, websocket.KindSessionEnded, "")
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		tb.Fatalf("hub did not handle replay of %s", sessionID)
+	}
+}
+
+// outcome reports how a replayed walk was handled
+func (h *replayHarness) outcome(tb testing.TB, sessionID string, points []models.Location) replayOutcome {
+	route, err := service.GetSessionRoute(sessionID, false)
+	require.NoError(tb, err)
+
+	late := make(map[int64]bool)
+	for _, point := range route {
+		if point.Late && point.DeviceSeq != nil {
+			late[*point.DeviceSeq] = true
+		}
+	}
+
+	h.mu.Lock()
+	broadcast := h.broadcast[sessionID]
+	h.mu.Unlock()
+
+	result := replayOutcome{Points: len(points), Stored: len(route), Anomalies: []replayAnomaly{}}
+	arrived := make(map[string]bool)
+	for i, point := range points {
+		require.NotNil(tb, point.DeviceSeq, "fixture points must carry a device sequence number")
+		seq := *point.DeviceSeq
+
+		var outcome string
+		switch {
+		case arrived[point.DedupeKey()]:
+			outcome = "duplicate"
+			result.Duplicates++
+		case late[seq]:
+			outcome = "late"
+			result.Late++
+		case broadcast[seq] > 0:
+			result.Broadcast++
+		default:
+			outcome = "withheld"
+			result.Withheld++
+		}
+		arrived[point.DedupeKey()] = true
+
+		if outcome != "" {
+			result.Anomalies = append(result.Anomalies, replayAnomaly{Arrival: i + 1, DeviceSeq: seq, Outcome: outcome})
+		}
+	}
+	for seq, count := range broadcast {
+		assert.Equal(tb, 1, count, "point %d broadcast more than once", seq)
+	}
+
+	if len(route) > 0 {
+		result.DurationSeconds = route[len(route)-1].Timestamp.Sub(route[0].Timestamp).Seconds()
+	}
+	for i := 1; i < len(route); i++ {
+		result.DistanceMeters += distanceMeters(route[i-1], route[i])
+	}
+	result.DistanceMeters = math.Round(result.DistanceMeters)
+	return result
+}
+
+// distanceMeters is the great-circle distance between two points
+func distanceMeters(a, b models.Location) float64 {
+	const earthRadiusMeters = 6371000.0
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+// replayFixtures returns the paths of every captured fixture
+func replayFixtures(tb testing.TB) []string {
+	paths, err := filepath.Glob(filepath.Join(replayDir, "*"+fixtures.FileExtension))
+	require.NoError(tb, err)
+	require.NotEmpty(tb, paths, "no fixtures in %s", replayDir)
+	return paths
+}
+
+// TestReplayFixtures replays each captured walk through ingestion and checks that points are
+// stored, broadcast and flagged as the known-good outcome recorded beside it. Run with
+// -update to record outcomes for new fixtures.
+func TestReplayFixtures(t *testing.T) {
+	harness := newReplayHarness(t)
+
+	for _, path := range replayFixtures(t) {
+		name := strings.TrimSuffix(filepath.Base(path), fixtures.FileExtension)
+		t.Run(name, func(t *testing.T) {
+			points, err := fixtures.Load(path)
+			require.NoError(t, err)
+
+			sessionID := fmt.Sprintf("replay-%s-%d", name, time.Now().UnixNano())
+			harness.replay(t, sessionID, points)
+			got := harness.outcome(t, sessionID, points)
+
+			golden := strings.TrimSuffix(path, fixtures.FileExtension) + ".golden.json"
+			if *updateGolden {
+				encoded, err := json.MarshalIndent(got, "", "  ")
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(golden, append(encoded, '\n'), 0o644))
+				return
+			}
+
+			encoded, err := os.ReadFile(golden)
+			require.NoError(t, err, "missing golden file; run with -update to record it")
+			var want replayOutcome
+			require.NoError(t, json.Unmarshal(encoded, &want))
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+// TestFixtureCapture checks that captured walks are anonymized, and that a rebased fixture
+// keeps the shape and timing of the original walk
+func TestFixtureCapture(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := fixtures.NewRecorder(dir)
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Hour).UTC().Truncate(time.Millisecond)
+	altitude := 42.0
+	original := make([]models.Location, 3)
+	for i := range original {
+		seq := int64(i + 1)
+		original[i] = models.Location{
+			SessionID: "capture-walk",
+			Latitude:  40.7128 + float64(i)*0.0005,
+			Longitude: -74.006 + float64(i)*0.0007,
+			Timestamp: start.Add(time.Duration(i) * 5 * time.Second),
+			Altitude:  &altitude,
+			DeviceSeq: &seq,
+		}
+		recorder.Record(original[i])
+	}
+	// A retry is captured as it arrived
+	recorder.Record(original[2])
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+fixtures.FileExtension))
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	raw, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "capture-walk")
+	assert.NotContains(t, string(raw), "40.71")
+
+	captured, err := fixtures.Load(paths[0])
+	require.NoError(t, err)
+	require.Len(t, captured, 4)
+	assert.Zero(t, captured[0].Latitude)
+	assert.Zero(t, captured[0].Longitude)
+	assert.True(t, captured[0].Timestamp.Equal(fixtures.Epoch))
+	assert.Nil(t, captured[0].Altitude)
+	assert.Equal(t, captured[2].DedupeKey(), captured[3].DedupeKey())
+
+	rebased := fixtures.Rebase(captured, "rebased", original[0].Latitude, original[0].Longitude, start)
+	for i, point := range original {
+		assert.Equal(t, "rebased", rebased[i].SessionID)
+		assert.True(t, rebased[i].Timestamp.Equal(point.Timestamp))
+		assert.InDelta(t, 0, distanceMeters(point, rebased[i]), 0.05)
+	}
+}
+
+// BenchmarkReplayIngestion measures ingesting every captured walk, with the hub broadcasting
+// the points to no subscribers
+func BenchmarkReplayIngestion(b *testing.B) {
+	newReplayHarness(b)
+
+	var walks [][]models.Location
+	points := 0
+	for _, path := range replayFixtures(b) {
+		walk, err := fixtures.Load(path)
+		require.NoError(b, err)
+		walks = append(walks, walk)
+		points += len(walk)
+	}
+
+	start := time.Now().Add(-24 * time.Hour).UTC()
+	b.ReportAllocs()
+	b.ResetTimer()
+	began := time.Now()
+	for i := 0; i < b.N; i++ {
+		for j, walk := range walks {
+			sessionID := fmt.Sprintf("bench-replay-%d-%d", i, j)
+			for _, point := range fixtures.Rebase(walk, sessionID, replayLatitude, replayLongitude, start) {
+				if err := service.TrackLocation(point); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(points*b.N)/time.Since(began).Seconds(), "points/s")
+}
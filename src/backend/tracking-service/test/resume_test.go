@@ -0,0 +1,213 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/handlers"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// resumeSecret signs the connection tokens of the resumption tests
+const resumeSecret = "resume-secret"
+
+// resumeServer runs a hub keeping size messages per walk for ttl behind the WebSocket
+// handler, returning a function connecting to a walk with the given last_seq query. A
+// connection is returned as the function collecting the frames it received; a refused one
+// only as the response status.
+func resumeServer(t *testing.T, size int, ttl time.Duration) (*websocket.Hub, func(topic, lastSeq string) (func() []string, int)) {
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	hub.ConfigureReplay(size, ttl)
+	service.Initialize(config.Config{
+		TokenSecret:       resumeSecret,
+		TokenTTL:          time.Minute,
+		StaleAfter:        time.Minute,
+		MaxAccuracyMeters: 100,
+	}, hub)
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	server := httptest.NewServer(http.HandlerFunc(handlers.WebSocketHandler))
+	t.Cleanup(server.Close)
+	return hub, func(topic, lastSeq string) (func() []string, int) {
+		token, err := websocket.IssueToken([]byte(resumeSecret), topic, time.Minute)
+		require.NoError(t, err)
+		query := "?token=" + token
+		if lastSeq != "" {
+			query += "&last_seq=" + lastSeq
+		}
+		conn, resp, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+query, nil)
+		if err != nil {
+			require.NotNil(t, resp, err)
+			return nil, resp.StatusCode
+		}
+		t.Cleanup(func() { conn.Close() })
+		return resumedFrames(conn), resp.StatusCode
+	}
+}
+
+// resumedFrames reads the sequenced frames and resume signals a client receives in the
+// background, returning a function that collects them, as "resume_incomplete" or the frame's
+// sequence number, until none arrives for a while
+func resumedFrames(conn *gorillaws.Conn) func() []string {
+	frames := make(chan string, 100)
+	go func() {
+		defer close(frames)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var f struct {
+				Seq     uint64 `json:"seq"`
+				Control string `json:"control"`
+			}
+			if json.Unmarshal(data, &f) != nil {
+				continue
+			}
+			switch {
+			case f.Control == "resume_incomplete":
+				frames <- f.Control
+			case f.Seq > 0:
+				frames <- fmt.Sprint(f.Seq)
+			}
+		}
+	}()
+	return func() []string {
+		var received []string
+		for {
+			select {
+			case frame, ok := <-frames:
+				if !ok {
+					return received
+				}
+				received = append(received, frame)
+			case <-time.After(300 * time.Millisecond):
+				return received
+			}
+		}
+	}
+}
+
+// followWalk connects to a walk from the live stream and waits until the hub has registered it
+func followWalk(t *testing.T, hub *websocket.Hub, connect func(topic, lastSeq string) (func() []string, int), topic string) func() []string {
+	t.Helper()
+	follower, status := connect(topic, "")
+	require.Equal(t, http.StatusSwitchingProtocols, status)
+	require.Eventually(t, func() bool { return hub.Subscribers(topic) == 1 }, 2*time.Second, 10*time.Millisecond)
+	return follower
+}
+
+// publishPoints publishes n location messages to topic and waits until the client following
+// it has received them, so they are in the hub's replay buffer
+func publishPoints(t *testing.T, hub *websocket.Hub, follower func() []string, topic string, n int) []string {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		hub.Publish(topic, websocket.KindLocation, fmt.Sprintf(`{"latitude":51.5,"n":%d}`, i))
+	}
+	received := follower()
+	require.Len(t, received, n)
+	return received
+}
+
+// TestResumeReplaysMissedMessages checks that a client reconnecting with the last sequence
+// number it processed receives only the messages after it, once each and in order, before
+// the live stream carries on
+func TestResumeReplaysMissedMessages(t *testing.T) {
+	hub, connect := resumeServer(t, 10, time.Minute)
+	follower := followWalk(t, hub, connect, "resume-walk")
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, publishPoints(t, hub, follower, "resume-walk", 5))
+
+	resumed, status := connect("resume-walk", "3")
+	require.Equal(t, http.StatusSwitchingProtocols, status)
+	assert.Equal(t, []string{"4", "5"}, resumed(), "messages already processed are not sent again")
+
+	caughtUp, _ := connect("resume-walk", "5")
+	assert.Empty(t, caughtUp())
+	fresh, _ := connect("resume-walk", "")
+	assert.Empty(t, fresh(), "clients that do not resume start from the live stream")
+
+	hub.Publish("resume-walk", websocket.KindLocation, `{"latitude":51.6}`)
+	for _, received := range []func() []string{follower, resumed, caughtUp, fresh} {
+		assert.Equal(t, []string{"6"}, received())
+	}
+
+	_, status = connect("resume-walk", "not-a-number")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+// TestResumeAfterWraparound checks that once the buffer has wrapped past the messages a
+// client missed, it is told to backfill from history before the messages still held
+func TestResumeAfterWraparound(t *testing.T) {
+	hub, connect := resumeServer(t, 3, time.Minute)
+	publishPoints(t, hub, followWalk(t, hub, connect, "wrapped-walk"), "wrapped-walk", 5)
+
+	// Only 3 to 5 are still held, so the client resuming after 1 has lost 2
+	evicted, _ := connect("wrapped-walk", "1")
+	assert.Equal(t, []string{"resume_incomplete", "3", "4", "5"}, evicted())
+
+	// Resuming right before the oldest message held loses nothing
+	held, _ := connect("wrapped-walk", "2")
+	assert.Equal(t, []string{"3", "4", "5"}, held())
+
+	// Another walk's buffer is not shared
+	other, _ := connect("wrapped-other-walk", "1")
+	assert.Empty(t, other())
+}
+
+// TestResumeAfterExpiry checks that messages held past the replay TTL are no longer replayed,
+// and that the resuming client is told to backfill instead, even once they are pruned
+func TestResumeAfterExpiry(t *testing.T) {
+	hub, connect := resumeServer(t, 10, 100*time.Millisecond)
+	publishPoints(t, hub, followWalk(t, hub, connect, "expired-walk"), "expired-walk", 3)
+
+	time.Sleep(300 * time.Millisecond)
+	expired, _ := connect("expired-walk", "1")
+	assert.Equal(t, []string{"resume_incomplete"}, expired())
+
+	caughtUp, _ := connect("expired-walk", "3")
+	assert.Empty(t, caughtUp(), "a client that saw every message has missed nothing")
+}
+
+// TestResumeDuringLiveStream checks that a client resuming while messages are being published
+// receives every message after its last one exactly once, whether replayed or live
+func TestResumeDuringLiveStream(t *testing.T) {
+	hub, connect := resumeServer(t, 100, time.Minute)
+	follower := followWalk(t, hub, connect, "live-walk")
+	publishPoints(t, hub, follower, "live-walk", 5)
+
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 0; i < 30; i++ {
+			hub.Publish("live-walk", websocket.KindLocation, fmt.Sprintf(`{"latitude":51.5,"n":%d}`, i))
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	resumed, _ := connect("live-walk", "5")
+	<-published
+
+	var want []string
+	for seq := 6; seq <= 35; seq++ {
+		want = append(want, fmt.Sprint(seq))
+	}
+	assert.Equal(t, want, resumed())
+	assert.Equal(t, want, follower())
+}
@@ -0,0 +1,271 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/export"
+	"src/backend/tracking-service/internal/mapmatching"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// streetLongitude is where the fake OSRM server's only street runs, due north
+const streetLongitude = -0.12
+
+// fakeOSRM is an OSRM match service snapping every point onto the street at streetLongitude.
+// It answers with status and body instead while status is set.
+type fakeOSRM struct {
+	requests int64
+	status   int64
+	body     atomic.Value
+}
+
+// startOSRM runs a fake OSRM server and returns it with its base URL
+func startOSRM(t *testing.T) (*fakeOSRM, string) {
+	osrm := &fakeOSRM{}
+	server := httptest.NewServer(osrm)
+	t.Cleanup(server.Close)
+	return osrm, server.URL
+}
+
+// fail makes the server answer every request with status and body
+func (f *fakeOSRM) fail(status int, body string) {
+	f.body.Store(body)
+	atomic.StoreInt64(&f.status, int64(status))
+}
+
+func (f *fakeOSRM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt64(&f.requests, 1)
+	if status := atomic.LoadInt64(&f.status); status != 0 {
+		w.WriteHeader(int(status))
+		fmt.Fprint(w, f.body.Load())
+		return
+	}
+
+	type tracepoint struct {
+		Location [2]float64 `json:"location"`
+	}
+	var tracepoints []*tracepoint
+	for _, coordinate := range strings.Split(strings.TrimPrefix(r.URL.Path, "/match/v1/foot/"), ";") {
+		lat, _ := strconv.ParseFloat(strings.SplitN(coordinate, ",", 2)[1], 64)
+		tracepoints = append(tracepoints, &tracepoint{Location: [2]float64{streetLongitude, lat}})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"code": "Ok", "tracepoints": tracepoints})
+}
+
+// snappingService initializes the service against a fresh store, snapping routes with the
+// OSRM server at url unless url is empty
+func snappingService(url string) {
+	repository.UseMemoryStore()
+	service.Initialize(config.Config{MaxAccuracyMeters: 100, MapMatchingURL: url}, websocket.NewHub())
+}
+
+// walkAlongStreet records a session of n points a minute apart walking north along the street,
+// each one drifting some 35 metres off it to one side or the other
+func walkAlongStreet(t *testing.T, sessionID, bookingID string, n int) time.Time {
+	t.Helper()
+	session := models.NewSession(sessionID, bookingID, "route-walker", "route-owner")
+	session.StartedAt = time.Now().Add(-time.Duration(n+1) * time.Minute)
+	require.NoError(t, repository.InsertSession(*session))
+
+	start := session.StartedAt.Add(30 * time.Second).UTC().Truncate(time.Second)
+	for i := 0; i < n; i++ {
+		drift := 0.0005
+		if i%2 == 1 {
+			drift = -drift
+		}
+		require.NoError(t, repository.InsertLocation(models.Location{
+			SessionID: sessionID,
+			Latitude:  51.5 + float64(i)*0.0009,
+			Longitude: streetLongitude + drift,
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+	return start
+}
+
+// onStreet reports whether every point of route lies on the street
+func onStreet(route []models.Location) bool {
+	for _, point := range route {
+		if point.Longitude != streetLongitude {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSnappedRoute checks that routes are snapped only when asked for, and matched once per
+// session until the walk records another point
+func TestSnappedRoute(t *testing.T) {
+	osrm, url := startOSRM(t)
+	snappingService(url)
+	walkAlongStreet(t, "snap-walk", "snap-booking", 3)
+
+	raw, err := service.GetSessionRoute("snap-walk", false)
+	require.NoError(t, err)
+	require.Len(t, raw, 3)
+	assert.False(t, onStreet(raw))
+	assert.Zero(t, atomic.LoadInt64(&osrm.requests))
+
+	snapped, err := service.GetSessionRoute("snap-walk", true)
+	require.NoError(t, err)
+	require.Len(t, snapped, 3)
+	assert.True(t, onStreet(snapped))
+	for i := range snapped {
+		assert.Equal(t, raw[i].Latitude, snapped[i].Latitude)
+		assert.Equal(t, raw[i].Timestamp, snapped[i].Timestamp)
+	}
+
+	_, err = service.GetSessionRoute("snap-walk", true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&osrm.requests), "an unchanged route is served from the cache")
+
+	require.NoError(t, repository.InsertLocation(models.Location{SessionID: "snap-walk", Latitude: 51.51, Longitude: -0.1205, Timestamp: time.Now()}))
+	snapped, err = service.GetSessionRoute("snap-walk", true)
+	require.NoError(t, err)
+	assert.Len(t, snapped, 4)
+	assert.True(t, onStreet(snapped))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&osrm.requests), "a route with new points is matched again")
+
+	// Without a provider routes are never snapped
+	snappingService("")
+	walkAlongStreet(t, "unsnapped-walk", "unsnapped-booking", 3)
+	unsnapped, err := service.GetSessionRoute("unsnapped-walk", true)
+	require.NoError(t, err)
+	assert.False(t, onStreet(unsnapped))
+}
+
+// TestSnappedRouteFallsBack checks that a trace OSRM cannot match is kept as recorded, and
+// that a failed request is reported by the provider and leaves routes unsnapped rather than
+// failing them
+func TestSnappedRouteFallsBack(t *testing.T) {
+	osrm, url := startOSRM(t)
+	snappingService(url)
+	walkAlongStreet(t, "fallback-walk", "fallback-booking", 3)
+	raw, err := repository.FindLocationsBySession("fallback-walk")
+	require.NoError(t, err)
+	provider := mapmatching.NewOSRMProvider(url)
+
+	// OSRM answers a trace it cannot match with a 400
+	osrm.fail(http.StatusBadRequest, `{"code":"NoMatch","message":"Could not match the trace."}`)
+	matched, err := provider.Match(context.Background(), raw)
+	require.NoError(t, err)
+	assert.Equal(t, raw, matched)
+
+	osrm.fail(http.StatusBadRequest, `{"code":"TooBig","message":"Too many trace coordinates"}`)
+	_, err = provider.Match(context.Background(), raw)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 400: TooBig")
+
+	osrm.fail(http.StatusBadGateway, "<html>Bad Gateway</html>")
+	_, err = provider.Match(context.Background(), raw)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 502")
+
+	route, err := service.GetSessionRoute("fallback-walk", true)
+	require.NoError(t, err)
+	assert.Equal(t, raw, route, "routes stay raw while matching fails")
+}
+
+// TestLongRouteIsComplete checks that routes longer than any single match request are read
+// and snapped in full
+func TestLongRouteIsComplete(t *testing.T) {
+	osrm, url := startOSRM(t)
+	snappingService(url)
+	walkAlongStreet(t, "long-walk", "long-booking", 10050)
+
+	raw, err := repository.FindLocationsBySession("long-walk")
+	require.NoError(t, err)
+	assert.Len(t, raw, 10050)
+
+	snapped, err := service.GetSessionRoute("long-walk", true)
+	require.NoError(t, err)
+	assert.Len(t, snapped, 10050)
+	assert.True(t, onStreet(snapped))
+	assert.Equal(t, int64(101), atomic.LoadInt64(&osrm.requests), "OSRM is sent at most 100 points at a time")
+}
+
+// TestWalkEvidenceSnapped checks that walk distances are measured along the snapped route, so
+// GPS drift does not lengthen them
+func TestWalkEvidenceSnapped(t *testing.T) {
+	snappingService("")
+	walkAlongStreet(t, "evidence-raw-walk", "evidence-raw-booking", 11)
+	raw, err := service.BookingWalkEvidence("evidence-raw-booking")
+	require.NoError(t, err)
+
+	_, url := startOSRM(t)
+	snappingService(url)
+	walkAlongStreet(t, "evidence-snapped-walk", "evidence-snapped-booking", 11)
+	snapped, err := service.BookingWalkEvidence("evidence-snapped-booking")
+	require.NoError(t, err)
+
+	// Ten steps of 0.0009 degrees north are 1 km
+	assert.InDelta(t, 1000, snapped.DistanceMeters, 5)
+	assert.Greater(t, raw.DistanceMeters, snapped.DistanceMeters+100)
+}
+
+// TestSnappedExport checks that a session can be exported snapped, within its time range,
+// and that only sessions can be
+func TestSnappedExport(t *testing.T) {
+	_, url := startOSRM(t)
+	snappingService(url)
+	start := walkAlongStreet(t, "export-snapped-walk", "export-snapped-booking", 5)
+
+	dir := t.TempDir()
+	store, err := export.NewFileStore(dir, []byte("export-link-secret"))
+	require.NoError(t, err)
+	service.ConfigureExports(store, config.Config{ExportWorkers: 1, ExportMaxPending: 10, ExportURLTTL: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+	workers := make(chan struct{})
+	go func() {
+		defer close(workers)
+		service.RunExportWorkers(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-workers
+	})
+
+	_, err = service.CreateExport("route-walker", false, models.ExportFormatCSV, "", &start, &start, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only a session's route can be snapped")
+
+	end := start.Add(2 * time.Minute)
+	job, err := service.CreateExport("route-walker", false, models.ExportFormatCSV, "export-snapped-walk", &start, &end, true)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		job, err = service.GetExport(ctx, job.ID, "route-walker", false)
+		return err == nil && job.IsFinished()
+	}, 5*time.Second, 20*time.Millisecond)
+	require.Equal(t, models.ExportStatusCompleted, job.Status, job.Error)
+	assert.Equal(t, int64(3), job.PointCount)
+
+	file, err := os.ReadFile(filepath.Join(dir, "exports", job.ID+".csv"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(file)), "\n")
+	require.Len(t, lines, 4)
+	for _, line := range lines[1:] {
+		assert.Equal(t, "-0.12", strings.Split(line, ",")[3], line)
+	}
+}
@@ -0,0 +1,90 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/seed"
+)
+
+// seedWalks seeds a fresh store with opts and returns its report and the trace of each
+// seeded session, in order
+func seedWalks(t *testing.T, opts seed.Options) (*seed.Report, [][]models.Location) {
+	t.Helper()
+	repository.UseMemoryStore()
+	report, err := seed.Run(opts)
+	require.NoError(t, err)
+
+	traces := make([][]models.Location, opts.Walks+opts.Active)
+	for i := range traces {
+		traces[i], err = repository.FindLocationsBySession(fmt.Sprintf("seed%d-session-%04d", opts.Seed, i+1))
+		require.NoError(t, err)
+	}
+	return report, traces
+}
+
+// TestSeedReproducible verifies the same seed always seeds the same walks, each with the
+// consent it needs and a trace covering it, and leaves the active walks active
+func TestSeedReproducible(t *testing.T) {
+	start := time.Date(2030, 3, 4, 0, 0, 0, 0, time.UTC)
+	now := start.Add(10 * time.Hour)
+	opts := seed.Options{
+		Seed: 7, Walks: 4, Active: 2, Start: start, Now: now,
+		Latitude: 37.7749, Longitude: -122.4194, Interval: 30 * time.Second,
+	}
+
+	report, traces := seedWalks(t, opts)
+	assert.Equal(t, 4, report.Walks)
+	assert.Equal(t, 2, report.Active)
+
+	points := 0
+	for i, trace := range traces {
+		session, err := repository.FindSessionByID(fmt.Sprintf("seed7-session-%04d", i+1))
+		require.NoError(t, err)
+		require.NotNil(t, session)
+		require.NotEmpty(t, trace, session.ID)
+		points += len(trace)
+
+		consents, err := repository.FindConsents(session.BookingID, "")
+		require.NoError(t, err)
+		assert.Len(t, consents, 2, "the walker and the owner have consented to %s", session.ID)
+
+		assert.Equal(t, session.StartedAt, trace[0].Timestamp)
+		if i < opts.Walks {
+			assert.Equal(t, models.SessionStatusEnded, session.Status)
+			require.NotNil(t, session.EndedAt)
+			assert.True(t, session.StartedAt.Before(start), "completed walks are in the week before the start date")
+			assert.False(t, trace[len(trace)-1].Timestamp.After(*session.EndedAt))
+		} else {
+			assert.Equal(t, models.SessionStatusActive, session.Status)
+			assert.Nil(t, session.EndedAt)
+			assert.False(t, trace[len(trace)-1].Timestamp.After(now))
+		}
+		for _, location := range trace {
+			assert.InDelta(t, opts.Latitude, location.Latitude, 0.1)
+			assert.InDelta(t, opts.Longitude, location.Longitude, 0.1)
+		}
+	}
+	assert.Equal(t, report.Points, points)
+
+	active, err := repository.FindActiveSessions()
+	require.NoError(t, err)
+	assert.Len(t, active, 2)
+
+	again, retraced := seedWalks(t, opts)
+	assert.Equal(t, report, again)
+	assert.Equal(t, traces, retraced)
+
+	_, err = seed.Run(seed.Options{Seed: 1, Walks: 1, Interval: 0})
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestWalkSimulation checks that a simulated walk starts a session for synthetic participants,
+// streams points near its start through the normal ingestion path, and ends when stopped
+func TestWalkSimulation(t *testing.T) {
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{
+		TokenSecret:         "simulation-secret",
+		TokenTTL:            time.Minute,
+		StaleAfter:          time.Minute,
+		MaxAccuracyMeters:   100,
+		ChatRetention:       90 * 24 * time.Hour,
+		ChatReportRetention: 365 * 24 * time.Hour,
+	}, hub)
+
+	var mu sync.Mutex
+	var points []models.Location
+	hub.Observe(func(message websocket.Message) {
+		if message.Kind != websocket.KindLocation {
+			return
+		}
+		var location models.Location
+		if json.Unmarshal([]byte(message.Data), &location) == nil {
+			mu.Lock()
+			points = append(points, location)
+			mu.Unlock()
+		}
+	})
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	_, err := service.StartSimulation(service.SimulationSettings{Latitude: 51.5072, Longitude: -0.1276})
+	require.ErrorIs(t, err, service.ErrSimulationUnavailable)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.RunSimulations(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	require.Eventually(t, func() bool {
+		_, err := service.StartSimulation(service.SimulationSettings{Latitude: 91})
+		return err != service.ErrSimulationUnavailable
+	}, time.Second, 10*time.Millisecond)
+
+	simulation, err := service.StartSimulation(service.SimulationSettings{
+		Latitude:        51.5072,
+		Longitude:       -0.1276,
+		SpeedMps:        5,
+		JitterMeters:    3,
+		IntervalSeconds: 0.1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "sim-walker-"+simulation.ID, simulation.WalkerID)
+	assert.Greater(t, simulation.RouteMeters, 0.0)
+	require.Len(t, service.ListSimulations(), 1)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(points) >= 3
+	}, 5*time.Second, 20*time.Millisecond)
+
+	mu.Lock()
+	for _, point := range points {
+		assert.Equal(t, simulation.SessionID, point.SessionID)
+		assert.Less(t, math.Abs(point.Latitude-51.5072), 0.01)
+		assert.Less(t, math.Abs(point.Longitude+0.1276), 0.015)
+		require.NotNil(t, point.Speed)
+		require.NotNil(t, point.DeviceSeq)
+	}
+	mu.Unlock()
+
+	require.NoError(t, service.StopSimulation(simulation.ID))
+	require.Eventually(t, func() bool { return len(service.ListSimulations()) == 0 }, 2*time.Second, 10*time.Millisecond)
+	session, err := service.GetSession(simulation.SessionID)
+	require.NoError(t, err)
+	assert.NotNil(t, session.EndedAt)
+	assert.ErrorIs(t, service.StopSimulation(simulation.ID), service.ErrSimulationNotFound)
+}
@@ -0,0 +1,125 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// slowHub starts a hub applying policy with one client that never reads its buffer
+func slowHub(t *testing.T, policy websocket.SlowConsumerPolicy) *websocket.Hub {
+	hub := websocket.NewHub()
+	hub.HandleSlowConsumers(policy)
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	client := websocket.NewClient(hub, nil, "slow-walk")
+	client.App = "PawsApp/3.2 (iOS 17.1)"
+	hub.Register <- client
+	return hub
+}
+
+// TestSlowConsumerDropOldest checks that a client that falls behind keeps its connection and
+// its newest messages, and that one event names its app
+func TestSlowConsumerDropOldest(t *testing.T) {
+	hub := slowHub(t, websocket.SlowConsumerDropOldest)
+	for i := 0; i < 100; i++ {
+		hub.Publish("slow-walk", websocket.KindLocation, fmt.Sprintf(`{"n":%d}`, i))
+	}
+
+	var status websocket.HubStatus
+	require.Eventually(t, func() bool {
+		status = hub.Status()
+		return status.Broadcast.Messages == 100
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 1, status.Clients)
+	require.Len(t, status.Backlogged, 1)
+	assert.Equal(t, 64, status.Backlogged[0].Queued)
+	assert.Equal(t, uint64(36), status.Dropped["drop_oldest"])
+	require.Len(t, status.SlowConsumers, 1)
+	assert.Equal(t, "PawsApp/3.2 (iOS 17.1)", status.SlowConsumers[0].App)
+	assert.Equal(t, websocket.SlowConsumerDropOldest, status.SlowConsumers[0].Policy)
+	assert.Equal(t, 1, status.SlowConsumers[0].Episodes)
+}
+
+// TestSlowConsumerDigest checks that a client that falls behind is downgraded to the latest
+// message of each topic instead of every message
+func TestSlowConsumerDigest(t *testing.T) {
+	hub := slowHub(t, websocket.SlowConsumerDigest)
+	for i := 0; i < 74; i++ {
+		hub.Publish("slow-walk", websocket.KindLocation, fmt.Sprintf(`{"n":%d}`, i))
+	}
+
+	var status websocket.HubStatus
+	require.Eventually(t, func() bool {
+		status = hub.Status()
+		return status.Broadcast.Messages == 74
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 1, status.Clients)
+	require.Len(t, status.Backlogged, 1)
+	assert.True(t, status.Backlogged[0].Digest)
+	assert.Equal(t, 64, status.Backlogged[0].Queued)
+	assert.Equal(t, uint64(9), status.Dropped["coalesced"])
+	require.Len(t, status.SlowConsumers, 1)
+	assert.Equal(t, websocket.SlowConsumerDigest, status.SlowConsumers[0].Policy)
+}
+
+// TestSlowConsumerDisconnect checks that a client that falls behind is disconnected with the
+// slow consumer close code once it has read what was already buffered for it
+func TestSlowConsumerDisconnect(t *testing.T) {
+	hub := websocket.NewHub()
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := websocket.NewClient(hub, conn, "slow-walk")
+		client.App = r.UserAgent()
+		client.Serve()
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), http.Header{"User-Agent": {"PawsApp/3.2"}})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	require.Eventually(t, func() bool { return hub.Subscribers("slow-walk") == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	// Large messages the client does not read fill the socket, then the send buffer
+	payload := fmt.Sprintf(`{"padding":%q}`, strings.Repeat("x", 64*1024))
+	require.Eventually(t, func() bool {
+		hub.Publish("slow-walk", websocket.KindLocation, payload)
+		return hub.Subscribers("slow-walk") == 0
+	}, 10*time.Second, time.Millisecond)
+
+	var closeErr *gorillaws.CloseError
+	for {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, _, err = conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+	require.True(t, errors.As(err, &closeErr), "expected a close frame, got %v", err)
+	assert.Equal(t, websocket.CloseSlowConsumer, closeErr.Code)
+
+	status := hub.Status()
+	require.NotEmpty(t, status.SlowConsumers)
+	assert.Equal(t, "PawsApp/3.2", status.SlowConsumers[0].App)
+	assert.Equal(t, websocket.SlowConsumerDisconnect, status.SlowConsumers[0].Policy)
+}
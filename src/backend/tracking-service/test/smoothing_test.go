@@ -0,0 +1,144 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// smoothingHub initializes the service with smoothing on or off and returns a function
+// waiting for n points of a session to be broadcast
+func smoothingHub(t *testing.T, smoothing bool) func(sessionID string, n int) []models.Location {
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{
+		TokenSecret:         "smoothing-secret",
+		TokenTTL:            time.Minute,
+		StaleAfter:          time.Minute,
+		MaxAccuracyMeters:   100,
+		Smoothing:           smoothing,
+		SmoothingSpeed:      1.5,
+		ChatRetention:       90 * 24 * time.Hour,
+		ChatReportRetention: 365 * 24 * time.Hour,
+	}, hub)
+
+	var mu sync.Mutex
+	broadcast := make(map[string][]models.Location)
+	hub.Observe(func(message websocket.Message) {
+		var location models.Location
+		if message.Kind == websocket.KindLocation && json.Unmarshal([]byte(message.Data), &location) == nil {
+			mu.Lock()
+			broadcast[message.Topic] = append(broadcast[message.Topic], location)
+			mu.Unlock()
+		}
+	})
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	return func(sessionID string, n int) []models.Location {
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(broadcast[sessionID]) >= n
+		}, 2*time.Second, 10*time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]models.Location(nil), broadcast[sessionID]...)
+	}
+}
+
+// rmsMeters is the root mean square distance of points from truth
+func rmsMeters(points []models.Location, truth models.Location) float64 {
+	var sum float64
+	for _, point := range points {
+		d := distanceMeters(point, truth)
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(points)))
+}
+
+// TestLiveSmoothing checks that smoothing steadies the broadcast position of a walker standing
+// still while following a real move, and that stored points keep the reported fixes
+func TestLiveSmoothing(t *testing.T) {
+	repository.UseMemoryStore()
+	waitFor := smoothingHub(t, true)
+
+	sessionID := fmt.Sprintf("smoothed-walk-%d", time.Now().UnixNano())
+	truth := models.Location{Latitude: 51.5, Longitude: -0.12}
+	start := time.Now().Add(-time.Hour).UTC()
+	accuracy := 30.0
+
+	// A walker waiting at a crossing, with fixes scattered around them
+	var sent []models.Location
+	for i := 0; i < 30; i++ {
+		north := 25 * math.Cos(float64(i)*2.1)
+		east := 25 * math.Sin(float64(i)*1.7)
+		point := models.Location{
+			SessionID:      sessionID,
+			Latitude:       truth.Latitude + north/111320,
+			Longitude:      truth.Longitude + east/(111320*math.Cos(truth.Latitude*math.Pi/180)),
+			Timestamp:      start.Add(time.Duration(i) * 5 * time.Second),
+			AccuracyMeters: &accuracy,
+		}
+		require.NoError(t, service.TrackLocation(point))
+		sent = append(sent, point)
+	}
+
+	broadcast := waitFor(sessionID, len(sent))
+	for _, point := range broadcast {
+		assert.True(t, point.Smoothed)
+	}
+	raw := rmsMeters(sent[10:], truth)
+	smoothed := rmsMeters(broadcast[10:], truth)
+	assert.Less(t, smoothed, raw/2, "smoothed error %.1fm, raw error %.1fm", smoothed, raw)
+
+	stored, err := repository.FindLocationsBySession(sessionID)
+	require.NoError(t, err)
+	require.Len(t, stored, len(sent))
+	for i, point := range stored {
+		assert.Equal(t, sent[i].Latitude, point.Latitude)
+		assert.Equal(t, sent[i].Longitude, point.Longitude)
+		assert.False(t, point.Smoothed)
+	}
+
+	// A precise fix a minute later, 200m away, is followed closely
+	precise := 5.0
+	moved := models.Location{
+		SessionID:      sessionID,
+		Latitude:       truth.Latitude + 200.0/111320,
+		Longitude:      truth.Longitude,
+		Timestamp:      sent[len(sent)-1].Timestamp.Add(time.Minute),
+		AccuracyMeters: &precise,
+	}
+	require.NoError(t, service.TrackLocation(moved))
+	broadcast = waitFor(sessionID, len(sent)+1)
+	assert.Less(t, distanceMeters(broadcast[len(broadcast)-1], moved), 30.0)
+
+	// Without smoothing, positions are broadcast as reported
+	waitFor = smoothingHub(t, false)
+	sessionID = fmt.Sprintf("raw-walk-%d", time.Now().UnixNano())
+	for _, point := range sent[:3] {
+		point.SessionID = sessionID
+		require.NoError(t, service.TrackLocation(point))
+	}
+	for i, point := range waitFor(sessionID, 3) {
+		assert.False(t, point.Smoothed)
+		assert.Equal(t, sent[i].Latitude, point.Latitude)
+		assert.Equal(t, sent[i].Longitude, point.Longitude)
+	}
+}
@@ -0,0 +1,118 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// startRecorder runs a server answering every request with 200, returning it as the channel
+// receiving the body of each request
+func startRecorder(t *testing.T) (string, chan map[string]interface{}) {
+	bodies := make(chan map[string]interface{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies <- body
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, bodies
+}
+
+// TestRaiseSOS checks that an SOS is recorded as an open incident at the walk's last known
+// position, and that the owner and the on-call channel are alerted about it
+func TestRaiseSOS(t *testing.T) {
+	notifications, owners := startRecorder(t)
+	oncall, alerts := startRecorder(t)
+
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	service.Initialize(config.Config{MaxAccuracyMeters: 100, NotificationURL: notifications, OnCallWebhookURL: oncall}, hub)
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	require.NoError(t, repository.InsertSession(*models.NewSession("sos-walk", "sos-booking", "sos-walker", "sos-owner")))
+	require.NoError(t, repository.InsertLocation(models.Location{SessionID: "sos-walk", Latitude: 51.5, Longitude: -0.12, Timestamp: time.Now()}))
+
+	incident, err := service.RaiseSOS("sos-walk", "dog slipped its lead", nil)
+	require.NoError(t, err)
+	stored, err := service.GetIncident(incident.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentTypeSOS, stored.Type)
+	assert.Equal(t, models.IncidentStatusOpen, stored.Status)
+	assert.Equal(t, "sos-booking", stored.BookingID)
+	assert.Equal(t, "sos-walker", stored.ReportedBy)
+	require.NotNil(t, stored.Location, "the last known position is used when none is reported")
+	assert.Equal(t, 51.5, stored.Location.Latitude)
+
+	select {
+	case notification := <-owners:
+		assert.Equal(t, "sos-owner", notification["recipient"])
+		assert.Equal(t, "high", notification["priority"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("the owner was not notified")
+	}
+	select {
+	case alert := <-alerts:
+		assert.Contains(t, alert["text"], "dog slipped its lead")
+		assert.Equal(t, incident.ID, alert["fields"].(map[string]interface{})["incident_id"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("the on-call channel was not alerted")
+	}
+
+	_, err = service.RaiseSOS("sos-unknown-walk", "help", nil)
+	assert.Error(t, err)
+}
+
+// TestSOSSkipsQueuedTraffic checks that an SOS published behind a backlog of locations is
+// delivered ahead of them
+func TestSOSSkipsQueuedTraffic(t *testing.T) {
+	hub := websocket.NewHub()
+
+	// Hold the hub loop on the first location until the backlog and the SOS are queued
+	release := make(chan struct{})
+	held := false
+	hub.Observe(func(message websocket.Message) {
+		if message.Kind == websocket.KindLocation && !held {
+			held = true
+			<-release
+		}
+	})
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	conn := rateLimitServer(t, hub)("topic=sos-backlog-walk")
+	require.Eventually(t, func() bool { return hub.Subscribers("sos-backlog-walk") == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 20; i++ {
+		hub.Publish("sos-backlog-walk", websocket.KindLocation, fmt.Sprintf(`{"n":%d}`, i))
+	}
+	hub.Publish("sos-backlog-walk", websocket.KindSOS, `{"alert":"sos"}`)
+	close(release)
+
+	received := drainFrames(t, conn)
+	require.Len(t, received, 21)
+	position := -1
+	for i, payload := range received {
+		if payload == `{"alert":"sos"}` {
+			position = i
+		}
+	}
+	assert.Contains(t, []int{0, 1}, position, "only the location already being handled goes out before the SOS")
+}
@@ -0,0 +1,108 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/shared/clock"
+	"src/backend/tracking-service/internal/config"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// TestStalenessFakeClock checks that a walk is reported stale exactly when the walker has been
+// quiet for the threshold by the service's clock, counted in elapsed time even when the clocks
+// go forward for daylight saving in between, and resumed when the walker reports again
+func TestStalenessFakeClock(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	// Two minutes before the clocks go forward from 01:00 GMT to 02:00 BST
+	start := time.Date(2026, time.March, 29, 0, 58, 0, 0, london)
+	fake := clock.NewFake(start)
+
+	repository.UseMemoryStore()
+	hub := websocket.NewHub()
+	events := make(chan map[string]interface{}, 10)
+	hub.Observe(func(message websocket.Message) {
+		if message.Kind != websocket.KindEvent {
+			return
+		}
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(message.Data), &event); err == nil {
+			events <- event
+		}
+	})
+	service.Initialize(config.Config{
+		TokenSecret:         "staleness-secret",
+		TokenTTL:            time.Minute,
+		StaleAfter:          5 * time.Minute,
+		MaxAccuracyMeters:   100,
+		ChatRetention:       90 * 24 * time.Hour,
+		ChatReportRetention: 365 * 24 * time.Hour,
+		Clock:               fake,
+	}, hub)
+	go hub.Run()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		assert.NoError(t, service.Stop(ctx))
+		assert.NoError(t, hub.Stop(ctx))
+	})
+
+	next := func() map[string]interface{} {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(2 * time.Second):
+			require.FailNow(t, "no session event")
+			return nil
+		}
+	}
+	lastSeen := func(event map[string]interface{}) time.Time {
+		at, err := time.Parse(time.RFC3339Nano, event["last_seen_at"].(string))
+		require.NoError(t, err)
+		return at
+	}
+	none := func(msg string) {
+		select {
+		case event := <-events:
+			assert.Failf(t, msg, "unexpected event %v", event)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	session, err := json.Marshal(models.NewSession("stale-walk", "stale-booking", "stale-walker", "stale-owner"))
+	require.NoError(t, err)
+	hub.Publish("stale-walk", websocket.KindSessionStarted, string(session))
+	require.Eventually(t, func() bool { return fake.Timers() == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	// By the wall clock 02:02 BST is over an hour on, but only four minutes have passed
+	fake.Advance(4 * time.Minute)
+	assert.Equal(t, "02:02", fake.Now().In(london).Format("15:04"))
+	none("the walk is not stale before the threshold")
+
+	fake.Advance(time.Minute)
+	stale := next()
+	assert.Equal(t, service.EventTrackingStale, stale["event"])
+	assert.Equal(t, "stale-walk", stale["session_id"])
+	assert.True(t, start.Equal(lastSeen(stale)))
+	assert.Equal(t, 300.0, stale["stale_after_seconds"])
+
+	fake.Advance(3 * time.Minute)
+	hub.Publish("stale-walk", websocket.KindHeartbeat, "")
+	resumed := next()
+	assert.Equal(t, service.EventTrackingResumed, resumed["event"])
+	assert.True(t, start.Add(8*time.Minute).Equal(lastSeen(resumed)))
+}
@@ -0,0 +1,151 @@
+// Package test provides unit tests for the tracking-service components
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	gorillaws "github.com/gorilla/websocket" // v1.5.0
+	"github.com/stretchr/testify/assert"     // v1.8.0
+	"github.com/stretchr/testify/require"    // v1.8.0
+
+	"src/backend/tracking-service/internal/websocket"
+)
+
+// throttledFrame is the subset of a frame the throttling test reads
+type throttledFrame struct {
+	Control    string          `json:"control"`
+	Topic      string          `json:"topic"`
+	Error      string          `json:"error"`
+	IntervalMs int64           `json:"interval_ms"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// frameReader reads frames from conn in the background and returns a function collecting
+// those that arrive within a period. A connection cannot be read again once a read times
+// out, so reads are never given a deadline.
+func frameReader(conn *gorillaws.Conn) func(period time.Duration) []throttledFrame {
+	frames := make(chan throttledFrame, 64)
+	go func() {
+		defer close(frames)
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var f throttledFrame
+			if json.Unmarshal(data, &f) == nil {
+				frames <- f
+			}
+		}
+	}()
+
+	return func(period time.Duration) []throttledFrame {
+		var collected []throttledFrame
+		deadline := time.After(period)
+		for {
+			select {
+			case f, ok := <-frames:
+				if !ok {
+					return collected
+				}
+				collected = append(collected, f)
+			case <-deadline:
+				return collected
+			}
+		}
+	}
+}
+
+// TestSubscriptionThrottling checks that a subscriber asking for an update interval receives
+// at most the latest location per interval, ending on the newest, while events and other
+// subscribers of the walk are unaffected
+func TestSubscriptionThrottling(t *testing.T) {
+	hub := websocket.NewHub()
+	hub.Channel("fleet", "throttle-fleet")
+	go hub.Run()
+	t.Cleanup(hub.CloseAllConnections)
+
+	upgrader := gorillaws.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		client := websocket.NewClient(hub, conn, r.URL.Query().Get("topic"))
+		client.Dispatch = r.URL.Query().Get("dispatch") == "true"
+		intervalMs, _ := strconv.Atoi(r.URL.Query().Get("interval_ms"))
+		client.Interval = time.Duration(intervalMs) * time.Millisecond
+		client.Serve()
+	}))
+	t.Cleanup(server.Close)
+
+	connect := func(query string) *gorillaws.Conn {
+		conn, _, err := gorillaws.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?"+query, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+
+	owner := frameReader(connect("topic=throttled-walk&interval_ms=400"))
+	viewer := frameReader(connect("topic=throttled-walk"))
+	require.Eventually(t, func() bool { return hub.Subscribers("throttled-walk") == 2 }, time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 10; i++ {
+		hub.Publish("throttled-walk", websocket.KindLocation, fmt.Sprintf(`{"n":%d}`, i))
+		if i == 4 {
+			hub.Publish("throttled-walk", websocket.KindEvent, `{"event":"walk_photo_added"}`)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	locations := func(frames []throttledFrame) (payloads []string, events int) {
+		for _, f := range frames {
+			if strings.Contains(string(f.Payload), `"n"`) {
+				payloads = append(payloads, string(f.Payload))
+			} else {
+				events++
+			}
+		}
+		return payloads, events
+	}
+
+	all, events := locations(viewer(time.Second))
+	assert.Len(t, all, 10)
+	assert.Equal(t, 1, events)
+
+	throttled, events := locations(owner(time.Second))
+	assert.Equal(t, 1, events)
+	require.GreaterOrEqual(t, len(throttled), 2)
+	assert.LessOrEqual(t, len(throttled), 4)
+	assert.JSONEq(t, `{"n":0}`, throttled[0])
+	assert.JSONEq(t, `{"n":9}`, throttled[len(throttled)-1])
+	assert.Greater(t, hub.Status().Dropped["throttled"], uint64(0))
+
+	// Dispatchers set an interval per subscription, within bounds
+	conn := connect("dispatch=true")
+	dispatcher := frameReader(conn)
+	require.NoError(t, conn.WriteMessage(gorillaws.TextMessage, []byte(`{"control":"subscribe","channel":"fleet","interval_ms":3600000}`)))
+	frames := dispatcher(300 * time.Millisecond)
+	require.Len(t, frames, 1)
+	assert.Equal(t, "interval_ms must be between 0 and 60000", frames[0].Error)
+
+	require.NoError(t, conn.WriteMessage(gorillaws.TextMessage, []byte(`{"control":"subscribe","channel":"fleet","interval_ms":5000}`)))
+	frames = dispatcher(300 * time.Millisecond)
+	require.Len(t, frames, 1)
+	assert.Equal(t, "throttle-fleet", frames[0].Topic)
+	assert.Equal(t, int64(5000), frames[0].IntervalMs)
+
+	for i := 0; i < 3; i++ {
+		hub.Publish("throttle-fleet", websocket.KindFleet, fmt.Sprintf(`{"active":%d}`, i))
+	}
+	frames = dispatcher(500 * time.Millisecond)
+	require.Len(t, frames, 1)
+	assert.JSONEq(t, `{"active":0}`, string(frames[0].Payload))
+}
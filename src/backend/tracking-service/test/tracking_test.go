@@ -1,16 +1,18 @@
-// Package test provides unit tests for the tracking-service components
+//go:build !integration
+
+// Package test provides unit tests for the tracking-service components. The in-memory store
+// replaces MongoDB for the whole process, so these tests are excluded from the integration
+// build that runs against a real database.
 package test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
-	"encoding/json"
 
-	"github.com/stretchr/testify/assert" // v1.8.0
-	"github.com/stretchr/testify/mock"   // v1.8.0
-	"github.com/gorilla/websocket"       // v1.5.0
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
 
-	"src/backend/tracking-service/internal/config"
 	"src/backend/tracking-service/internal/models"
 	"src/backend/tracking-service/internal/repository"
 	"src/backend/tracking-service/internal/service"
@@ -18,181 +20,160 @@ import (
 )
 
 // Human Tasks:
-// 1. Ensure MongoDB test instance is available and configured
-// 2. Configure test environment variables:
-//    - TRACKING_DB_URI: MongoDB test instance connection string
-//    - TRACKING_WS_PORT: Test WebSocket port (e.g., 8081)
-// 3. Set up test data cleanup procedures
-// 4. Configure test coverage reporting
-
-// MockMongoCollection mocks MongoDB collection operations
-type MockMongoCollection struct {
-	mock.Mock
-}
-
-func (m *MockMongoCollection) InsertOne(location models.Location) error {
-	args := m.Called(location)
-	return args.Error(0)
-}
-
-func (m *MockMongoCollection) Find(startTime, endTime time.Time) ([]models.Location, error) {
-	args := m.Called(startTime, endTime)
-	return args.Get(0).([]models.Location), args.Error(1)
-}
+// 1. Configure test coverage reporting
+// 2. Repository tests against a real MongoDB live in integration_test.go (build tag: integration)
 
 // TestInsertLocation tests the InsertLocation function
 // Addresses requirement: Real-time location tracking
 // Location: 1.2 System Overview/High-Level Description/Backend Services
 func TestInsertLocation(t *testing.T) {
-	// Initialize mock collection
-	mockCollection := new(MockMongoCollection)
+	repository.UseMemoryStore()
 
 	// Create test location data
 	testLocation := models.Location{
+		SessionID: "insert-walk",
 		Latitude:  40.7128,
 		Longitude: -74.0060,
-		Timestamp: time.Now(),
+		Timestamp: time.Now().Add(-time.Minute).UTC(),
 	}
 
-	// Set up mock expectations
-	mockCollection.On("InsertOne", testLocation).Return(nil)
-
 	// Test location insertion
 	err := repository.InsertLocation(testLocation)
 
 	// Assert expectations
 	assert.NoError(t, err, "InsertLocation should not return an error")
-	mockCollection.AssertExpectations(t)
+	stored, err := repository.FindLocationsBySession("insert-walk")
+	require.NoError(t, err)
+	require.Len(t, stored, 1, "The inserted location should be stored")
+	assert.Equal(t, testLocation.Latitude, stored[0].Latitude)
 }
 
 // TestFindLocationsByTimeRange tests the FindLocationsByTimeRange function
 // Addresses requirement: Real-time location tracking
 // Location: 1.2 System Overview/High-Level Description/Backend Services
 func TestFindLocationsByTimeRange(t *testing.T) {
-	// Initialize mock collection
-	mockCollection := new(MockMongoCollection)
+	repository.UseMemoryStore()
 
 	// Create test time range
-	endTime := time.Now()
+	endTime := time.Now().UTC()
 	startTime := endTime.Add(-1 * time.Hour)
 
-	// Create expected location data
-	expectedLocations := []models.Location{
-		{
+	// Store locations inside and outside the range
+	for _, offset := range []time.Duration{-15 * time.Minute, 15 * time.Minute, 30 * time.Minute, 90 * time.Minute} {
+		require.NoError(t, repository.InsertLocation(models.Location{
+			SessionID: "range-walk",
 			Latitude:  40.7128,
 			Longitude: -74.0060,
-			Timestamp: startTime.Add(15 * time.Minute),
-		},
-		{
-			Latitude:  40.7129,
-			Longitude: -74.0061,
-			Timestamp: startTime.Add(30 * time.Minute),
-		},
+			Timestamp: startTime.Add(offset),
+		}))
 	}
 
-	// Set up mock expectations
-	mockCollection.On("Find", startTime, endTime).Return(expectedLocations, nil)
-
 	// Test location retrieval
 	locations, err := repository.FindLocationsByTimeRange(startTime, endTime)
 
 	// Assert expectations
 	assert.NoError(t, err, "FindLocationsByTimeRange should not return an error")
-	assert.Equal(t, expectedLocations, locations, "Retrieved locations should match expected data")
-	mockCollection.AssertExpectations(t)
-}
-
-// TestWebSocketBroadcast tests the WebSocket hub's broadcasting functionality
-// Addresses requirement: Scalable microservices architecture
-// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
-func TestWebSocketBroadcast(t *testing.T) {
-	// Initialize WebSocket hub
-	hub := websocket.NewHub()
-	go hub.Run()
-
-	// Create mock WebSocket connections
-	mockConn1 := &websocket.Conn{}
-	mockConn2 := &websocket.Conn{}
-
-	// Register mock connections
-	hub.Register <- mockConn1
-	hub.Register <- mockConn2
-
-	// Create test message
-	testLocation := models.Location{
-		Latitude:  40.7128,
-		Longitude: -74.0060,
-		Timestamp: time.Now(),
-	}
-	locationJSON, err := json.Marshal(testLocation)
-	assert.NoError(t, err, "Failed to marshal test location")
-
-	// Broadcast test message
-	hub.BroadcastMessage(string(locationJSON))
-
-	// Allow time for message processing
-	time.Sleep(100 * time.Millisecond)
-
-	// Assert hub state
-	assert.Equal(t, 2, hub.GetConnectedClients(), "Hub should have 2 connected clients")
+	require.Len(t, locations, 2, "Only locations within the range should be returned")
+	assert.True(t, locations[0].Timestamp.Equal(startTime.Add(15*time.Minute)))
+	assert.True(t, locations[1].Timestamp.Equal(startTime.Add(30*time.Minute)))
 }
 
 // TestTrackLocation tests the TrackLocation service function
 // Addresses requirement: Real-time location tracking
 // Location: 1.2 System Overview/High-Level Description/Backend Services
 func TestTrackLocation(t *testing.T) {
+	repository.UseMemoryStore()
+
 	// Create test location
 	testLocation := models.Location{
+		SessionID: "track-walk",
 		Latitude:  40.7128,
 		Longitude: -74.0060,
-		Timestamp: time.Now(),
+		Timestamp: time.Now().Add(-time.Second).UTC(),
 	}
 
-	// Initialize mock dependencies
-	mockCollection := new(MockMongoCollection)
-	mockCollection.On("InsertOne", testLocation).Return(nil)
-
 	// Test location tracking
 	err := service.TrackLocation(testLocation)
 
 	// Assert expectations
 	assert.NoError(t, err, "TrackLocation should not return an error")
-	mockCollection.AssertExpectations(t)
+	stored, err := repository.FindLocationsBySession("track-walk")
+	require.NoError(t, err)
+	assert.Len(t, stored, 1, "The tracked location should be stored")
+
+	// Invalid locations are rejected before they are stored
+	invalid := testLocation
+	invalid.Latitude = 91
+	assert.Error(t, service.TrackLocation(invalid), "TrackLocation should reject an out of range latitude")
+	stored, err = repository.FindLocationsBySession("track-walk")
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
 }
 
 // TestGetLocationHistory tests the GetLocationHistory service function
 // Addresses requirement: Real-time location tracking
 // Location: 1.2 System Overview/High-Level Description/Backend Services
 func TestGetLocationHistory(t *testing.T) {
+	repository.UseMemoryStore()
+
 	// Create test time range
-	endTime := time.Now()
+	endTime := time.Now().UTC()
 	startTime := endTime.Add(-1 * time.Hour)
 
-	// Create expected location data
-	expectedLocations := []models.Location{
-		{
+	for _, offset := range []time.Duration{15 * time.Minute, 30 * time.Minute} {
+		require.NoError(t, repository.InsertLocation(models.Location{
+			SessionID: "history-walk",
 			Latitude:  40.7128,
 			Longitude: -74.0060,
-			Timestamp: startTime.Add(15 * time.Minute),
-		},
-		{
-			Latitude:  40.7129,
-			Longitude: -74.0061,
-			Timestamp: startTime.Add(30 * time.Minute),
-		},
+			Timestamp: startTime.Add(offset),
+		}))
 	}
 
-	// Initialize mock dependencies
-	mockCollection := new(MockMongoCollection)
-	mockCollection.On("Find", startTime, endTime).Return(expectedLocations, nil)
-
 	// Test location history retrieval
 	locations, err := service.GetLocationHistory(startTime, endTime)
 
 	// Assert expectations
 	assert.NoError(t, err, "GetLocationHistory should not return an error")
-	assert.Equal(t, expectedLocations, locations, "Retrieved locations should match expected data")
-	mockCollection.AssertExpectations(t)
+	assert.Len(t, locations, 2, "Both locations in the range should be returned")
+
+	// Ranges longer than a day are refused
+	_, err = service.GetLocationHistory(endTime.Add(-25*time.Hour), endTime)
+	assert.Error(t, err, "GetLocationHistory should refuse ranges over 24 hours")
+}
+
+// TestWebSocketBroadcast tests the WebSocket hub's broadcasting functionality
+// Addresses requirement: Scalable microservices architecture
+// Location: 7.3 Technical Decisions/Architecture Patterns/Microservices
+func TestWebSocketBroadcast(t *testing.T) {
+	// Initialize WebSocket hub
+	hub := websocket.NewHub()
+	go hub.Run()
+
+	// Create clients without underlying connections; the hub only touches their send buffers
+	client1 := websocket.NewClient(hub, nil, "")
+	client2 := websocket.NewClient(hub, nil, "")
+
+	// Register clients
+	hub.Register <- client1
+	hub.Register <- client2
+
+	// Create test message
+	testLocation := models.Location{
+		Latitude:  40.7128,
+		Longitude: -74.0060,
+		Timestamp: time.Now(),
+	}
+	locationJSON, err := json.Marshal(testLocation)
+	assert.NoError(t, err, "Failed to marshal test location")
+
+	// Broadcast test message
+	hub.BroadcastMessage(string(locationJSON))
+
+	// Allow time for message processing
+	time.Sleep(100 * time.Millisecond)
+
+	// Assert hub state
+	assert.Equal(t, 2, hub.GetConnectedClients(), "Hub should have 2 connected clients")
 }
 
 // TestInvalidTimeRange tests error handling for invalid time ranges
@@ -205,4 +186,37 @@ func TestInvalidTimeRange(t *testing.T) {
 
 	assert.Error(t, err, "GetLocationHistory should return an error for invalid time range")
 	assert.Nil(t, locations, "No locations should be returned for invalid time range")
-}
\ No newline at end of file
+}
+
+// TestLocationMetadataValidation tests validation of optional device metadata
+// Addresses requirement: Technical Specification/7.2.1 Core Components/Tracking Service
+func TestLocationMetadataValidation(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+
+	base := models.Location{
+		Latitude:  40.7128,
+		Longitude: -74.0060,
+		Timestamp: time.Now().Add(-1 * time.Second),
+	}
+
+	// Test case: metadata omitted entirely
+	assert.NoError(t, base.Validate(), "Location without metadata should be valid")
+	assert.True(t, base.IsPrecise(10), "Location without reported accuracy should be treated as precise")
+
+	// Test case: valid metadata
+	withMetadata := base
+	withMetadata.AccuracyMeters = value(8)
+	withMetadata.Heading = value(270)
+	withMetadata.BatteryPercent = value(42)
+	assert.NoError(t, withMetadata.Validate(), "Location with valid metadata should be valid")
+	assert.False(t, withMetadata.IsPrecise(5), "Location should not be precise beyond the accuracy limit")
+
+	// Test case: out of range metadata
+	invalidHeading := base
+	invalidHeading.Heading = value(360)
+	assert.Error(t, invalidHeading.Validate(), "Heading of 360 should be rejected")
+
+	invalidBattery := base
+	invalidBattery.BatteryPercent = value(101)
+	assert.Error(t, invalidBattery.Validate(), "Battery above 100 percent should be rejected")
+}
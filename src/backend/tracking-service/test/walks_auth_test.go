@@ -0,0 +1,137 @@
+//go:build !integration
+
+// The in-memory store replaces MongoDB for the whole process, so these tests are
+// excluded from the integration build that runs against a real database.
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"  // v1.8.0
+	"github.com/stretchr/testify/require" // v1.8.0
+
+	"src/backend/shared/clients"
+	"src/backend/shared/policy"
+	"src/backend/tracking-service/internal/auth"
+	"src/backend/tracking-service/internal/handlers"
+	"src/backend/tracking-service/internal/models"
+	"src/backend/tracking-service/internal/repository"
+	"src/backend/tracking-service/internal/service"
+)
+
+// consentToWalk records the walker's and the owner's consent to tracking for a booking
+func consentToWalk(t *testing.T, bookingID, walkerID, ownerID string) {
+	t.Helper()
+	for subjectID, role := range map[string]models.ConsentRole{walkerID: models.ConsentRoleWalker, ownerID: models.ConsentRoleOwner} {
+		_, err := service.RecordConsent(bookingID, models.Consent{SubjectID: subjectID, Role: role, TermsVersion: "2024-01"})
+		require.NoError(t, err)
+	}
+}
+
+// TestStartWalkAsWalker checks that walkers start walks as the user of their token, only for
+// bookings assigned to them and with the owner the booking-service knows, while admins name
+// the walker and owner themselves
+func TestStartWalkAsWalker(t *testing.T) {
+	startBookingDirectory(t, "walks-key",
+		clients.Booking{ID: "walks-booking", OwnerID: "walks-owner", WalkerID: "walks-walker"},
+		clients.Booking{ID: "walks-other-booking", OwnerID: "walks-other-owner", WalkerID: "walks-other-walker"})
+	consentToWalk(t, "walks-booking", "walks-walker", "walks-owner")
+	consentToWalk(t, "walks-admin-booking", "walks-admin-walker", "walks-admin-owner")
+
+	start := auth.Require(testJWTSecret, policy.ResourceLocations, policy.ActionCreate)(handlers.StartWalkHandler)
+	walker := userToken(t, "walks-walker", policy.RoleWalker)
+	forged := map[string]string{"booking_id": "walks-booking", "owner_id": "walks-forged-owner"}
+
+	assert.Equal(t, http.StatusUnauthorized, callAs(start, "", http.MethodPost, "/api/v1/walks", forged).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(start, userToken(t, "walks-owner", policy.RoleOwner), http.MethodPost, "/api/v1/walks", forged).Code,
+		"owners do not start walks")
+	assert.Equal(t, http.StatusForbidden, callAs(start, walker, http.MethodPost, "/api/v1/walks",
+		map[string]string{"booking_id": "walks-booking", "walker_id": "walks-other-walker"}).Code, "the body cannot name another walker")
+	assert.Equal(t, http.StatusForbidden, callAs(start, walker, http.MethodPost, "/api/v1/walks",
+		map[string]string{"booking_id": "walks-other-booking"}).Code, "walkers cannot start others' bookings")
+	assert.Equal(t, http.StatusForbidden, callAs(start, walker, http.MethodPost, "/api/v1/walks",
+		map[string]string{"booking_id": "walks-unknown-booking"}).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(start, walker, http.MethodPost, "/api/v1/walks",
+		map[string]interface{}{"bookings": []models.SessionBooking{{BookingID: "walks-booking"}, {BookingID: "walks-other-booking"}}}).Code,
+		"every booking of a group walk must be the walker's")
+
+	rec := callAs(start, walker, http.MethodPost, "/api/v1/walks", forged)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var session models.Session
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &session))
+	assert.Equal(t, "walks-walker", session.WalkerID)
+	assert.Equal(t, "walks-owner", session.OwnerID, "the owner comes from the booking, not the body")
+
+	rec = callAs(start, userToken(t, "support", policy.RoleAdmin), http.MethodPost, "/api/v1/walks", map[string]string{
+		"booking_id": "walks-admin-booking", "walker_id": "walks-admin-walker", "owner_id": "walks-admin-owner",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &session))
+	assert.Equal(t, "walks-admin-walker", session.WalkerID)
+	assert.Equal(t, "walks-admin-owner", session.OwnerID)
+}
+
+// TestWalkActionsRequireWalker checks that a walk's participants follow it, and that only its
+// walker, or an admin, sends heartbeats for it or ends it
+func TestWalkActionsRequireWalker(t *testing.T) {
+	startBookingDirectory(t, "walks-key")
+	require.NoError(t, repository.InsertSession(*models.NewSession("walks-session", "walks-session-booking", "walks-session-walker", "walks-session-owner")))
+
+	walks := auth.RequireMethod(testJWTSecret, policy.ResourceLocations)(handlers.WalkHandler)
+	walker := userToken(t, "walks-session-walker", policy.RoleWalker)
+	owner := userToken(t, "walks-session-owner", policy.RoleOwner)
+	heartbeat := "/api/v1/walks/walks-session/heartbeat"
+
+	assert.Equal(t, http.StatusUnauthorized, callAs(walks, "", http.MethodPost, heartbeat, nil).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(walks, owner, http.MethodPost, heartbeat, nil).Code, "owners follow walks, they do not act on them")
+	assert.Equal(t, http.StatusForbidden, callAs(walks, userToken(t, "walks-stranger", policy.RoleWalker), http.MethodPost, heartbeat, nil).Code,
+		"walkers cannot send heartbeats for others' walks")
+	assert.Equal(t, http.StatusNotFound, callAs(walks, walker, http.MethodPost, "/api/v1/walks/walks-unknown/heartbeat", nil).Code,
+		"heartbeats are only published for walks that exist")
+	assert.Equal(t, http.StatusOK, callAs(walks, walker, http.MethodPost, heartbeat, nil).Code)
+	assert.Equal(t, http.StatusOK, callAs(walks, userToken(t, "support", policy.RoleAdmin), http.MethodPost, heartbeat, nil).Code)
+
+	assert.Equal(t, http.StatusOK, callAs(walks, owner, http.MethodGet, "/api/v1/walks/walks-session", nil).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(walks, userToken(t, "walks-other-owner", policy.RoleOwner), http.MethodGet, "/api/v1/walks/walks-session", nil).Code)
+
+	assert.Equal(t, http.StatusForbidden, callAs(walks, userToken(t, "walks-stranger", policy.RoleWalker), http.MethodPost, "/api/v1/walks/walks-session/end", nil).Code)
+	assert.Equal(t, http.StatusOK, callAs(walks, walker, http.MethodPost, "/api/v1/walks/walks-session/end", nil).Code)
+}
+
+// TestWalkEvidenceAccess checks that a booking's walk evidence is read by its owner and walker,
+// or an admin, with a user token, and by the other backend services with the API key
+func TestWalkEvidenceAccess(t *testing.T) {
+	startBookingDirectory(t, "walks-key",
+		clients.Booking{ID: "evidence-booking", OwnerID: "evidence-owner", WalkerID: "evidence-walker"})
+	require.NoError(t, repository.InsertSession(*models.NewSession("evidence-session", "evidence-booking", "evidence-walker", "evidence-owner")))
+
+	users := auth.Require(testJWTSecret, policy.ResourceLocations, policy.ActionRead)(handlers.BookingHandler)
+	path := "/api/v1/bookings/evidence-booking/walk-evidence"
+
+	assert.Equal(t, http.StatusUnauthorized, callAs(users, "", http.MethodGet, path, nil).Code)
+	assert.Equal(t, http.StatusForbidden, callAs(users, userToken(t, "evidence-stranger", policy.RoleOwner), http.MethodGet, path, nil).Code,
+		"only the booking's participants read its walk evidence")
+	for _, token := range []string{
+		userToken(t, "evidence-owner", policy.RoleOwner),
+		userToken(t, "evidence-walker", policy.RoleWalker),
+		userToken(t, "support", policy.RoleAdmin),
+	} {
+		rec := callAs(users, token, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var evidence models.WalkEvidence
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &evidence))
+		assert.Equal(t, 1, evidence.Sessions)
+	}
+
+	services := auth.RequireAPIKey("evidence-service-key")(handlers.BookingHandler)
+	for key, status := range map[string]int{"": http.StatusUnauthorized, "wrong-key": http.StatusUnauthorized, "evidence-service-key": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(clients.APIKeyHeader, key)
+		rec := httptest.NewRecorder()
+		services(rec, req)
+		assert.Equal(t, status, rec.Code, key)
+	}
+}